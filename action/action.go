@@ -13,6 +13,8 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/go-pkgs/crypto"
+
+	"github.com/iotexproject/iotex-core/signer"
 )
 
 var (
@@ -41,7 +43,7 @@ type hasDestination interface {
 }
 
 // Sign signs the action using sender's private key
-func Sign(act Envelope, sk crypto.PrivateKey) (SealedEnvelope, error) {
+func Sign(act Envelope, sk signer.HashSigner) (SealedEnvelope, error) {
 	sealed := SealedEnvelope{Envelope: act}
 
 	sealed.srcPubkey = sk.PublicKey()
@@ -56,6 +58,22 @@ func Sign(act Envelope, sk crypto.PrivateKey) (SealedEnvelope, error) {
 	return sealed, nil
 }
 
+// Sponsor has the payer sign the action sealed by the sender, to pay the gas fee of the action
+func Sponsor(sealed SealedEnvelope, sk crypto.PrivateKey) (SealedEnvelope, error) {
+	if sealed.encoding != IoTeXProtobufEncoding {
+		return sealed, errors.Wrap(ErrAction, "ethereum transaction cannot be sponsored")
+	}
+	hash := sealed.senderHash()
+	sig, err := sk.Sign(hash[:])
+	if err != nil {
+		return sealed, errors.Wrapf(ErrAction, "failed to sign sealed action hash = %x", hash)
+	}
+	sealed.payerPubkey = sk.PublicKey()
+	sealed.payerSignature = sig
+	sealed.payload.SetEnvelopeContext(sealed)
+	return sealed, nil
+}
+
 // FakeSeal creates a SealedActionEnvelope without signature.
 // This method should be only used in tests.
 func FakeSeal(act Envelope, pubk crypto.PublicKey) SealedEnvelope {
@@ -89,17 +107,40 @@ func Verify(sealed SealedEnvelope) error {
 	if intrinsicGas > sealed.GasLimit() || err != nil {
 		return errors.Wrap(ErrInsufficientBalanceForGas, "insufficient gas")
 	}
+	return VerifySignature(sealed)
+}
 
-	hash := sealed.Envelope.Hash()
-	if sealed.SrcPubkey().Verify(hash[:], sealed.Signature()) {
+// VerifySignature verifies the signature of the action using sender's public key, and the signature of the payer of a
+// sponsored action using the payer's public key
+func VerifySignature(sealed SealedEnvelope) error {
+	if sealed.SrcPubkey() == nil {
+		return errors.New("empty public key")
+	}
+	hash, err := sealed.envelopeHash()
+	if err != nil {
+		return errors.Wrap(err, "failed to get envelope hash")
+	}
+	if !sealed.SrcPubkey().Verify(hash[:], sealed.Signature()) {
+		return errors.Wrapf(
+			ErrAction,
+			"failed to verify action hash = %x and signature = %x",
+			hash,
+			sealed.Signature(),
+		)
+	}
+	if !sealed.IsSponsored() {
 		return nil
 	}
-	return errors.Wrapf(
-		ErrAction,
-		"failed to verify action hash = %x and signature = %x",
-		hash,
-		sealed.Signature(),
-	)
+	hash = sealed.senderHash()
+	if !sealed.PayerPubkey().Verify(hash[:], sealed.PayerSignature()) {
+		return errors.Wrapf(
+			ErrAction,
+			"failed to verify sealed action hash = %x and payer signature = %x",
+			hash,
+			sealed.PayerSignature(),
+		)
+	}
+	return nil
 }
 
 // ClassifyActions classfies actions
@@ -63,6 +63,8 @@ func TestActionProtoAndVerify(t *testing.T) {
 		require.NoError(err)
 
 		require.Equal(ErrInsufficientBalanceForGas, errors.Cause(Verify(selp)))
+		// the signature alone is valid
+		require.NoError(VerifySignature(selp))
 	})
 	t.Run("invalid signature", func(t *testing.T) {
 		bd := &EnvelopeBuilder{}
@@ -75,5 +77,6 @@ func TestActionProtoAndVerify(t *testing.T) {
 		selp.signature = []byte("invalid signature")
 
 		require.True(strings.Contains(Verify(selp).Error(), "failed to verify action hash"))
+		require.True(strings.Contains(VerifySignature(selp).Error(), "failed to verify action hash"))
 	})
 }
@@ -0,0 +1,170 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+)
+
+// Encoding is the encoding of the action which the sender signs
+type Encoding uint32
+
+const (
+	// IoTeXProtobufEncoding signs the hash of the protobuf-serialized action envelope
+	IoTeXProtobufEncoding Encoding = iota
+	// EthereumRLPEncoding signs the EIP-155 hash of the RLP-encoded ethereum transaction of a transfer or execution
+	EthereumRLPEncoding
+)
+
+// DecodeEthereumTx decodes a RLP-encoded ethereum transaction signed with the EIP-155 replay protection, and recovers
+// its sender. A transaction creating a contract or carrying data is mapped to an execution, and any other one to a
+// transfer.
+func DecodeEthereumTx(raw []byte) (SealedEnvelope, error) {
+	tx := &types.Transaction{}
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return SealedEnvelope{}, errors.Wrap(err, "failed to decode ethereum transaction")
+	}
+	if !tx.Protected() {
+		return SealedEnvelope{}, errors.Wrap(ErrAction, "ethereum transaction is not protected by EIP-155")
+	}
+	chainID := tx.ChainId()
+	if !chainID.IsUint64() || chainID.Uint64() == 0 || chainID.Uint64() > math.MaxUint32 {
+		return SealedEnvelope{}, errors.Wrapf(ErrAction, "invalid chain id %s", chainID)
+	}
+	v, r, s := tx.RawSignatureValues()
+	// the recovery id is encoded in v as chainID*2 + 35 + {0, 1}
+	recID := new(big.Int).Sub(v, new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big.NewInt(35)))
+	if !recID.IsUint64() || recID.Uint64() > 1 || !ethcrypto.ValidateSignatureValues(byte(recID.Uint64()), r, s, true) {
+		return SealedEnvelope{}, errors.Wrap(ErrAction, "invalid signature values of ethereum transaction")
+	}
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = byte(recID.Uint64())
+
+	var payload actionPayload
+	switch {
+	case tx.To() == nil || len(tx.Data()) > 0:
+		contract := EmptyAddress
+		if tx.To() != nil {
+			addr, err := address.FromBytes(tx.To().Bytes())
+			if err != nil {
+				return SealedEnvelope{}, err
+			}
+			contract = addr.String()
+		}
+		payload, _ = NewExecution(contract, tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data())
+	default:
+		addr, err := address.FromBytes(tx.To().Bytes())
+		if err != nil {
+			return SealedEnvelope{}, err
+		}
+		payload, _ = NewTransfer(tx.Nonce(), tx.Value(), addr.String(), nil, tx.Gas(), tx.GasPrice())
+	}
+	elp := (&EnvelopeBuilder{}).
+		SetNonce(tx.Nonce()).
+		SetGasLimit(tx.Gas()).
+		SetGasPrice(tx.GasPrice()).
+		SetAction(payload).
+		Build()
+	sealed := SealedEnvelope{
+		Envelope:     elp,
+		signature:    sig,
+		encoding:     EthereumRLPEncoding,
+		evmNetworkID: uint32(chainID.Uint64()),
+	}
+	h, err := sealed.envelopeHash()
+	if err != nil {
+		return SealedEnvelope{}, err
+	}
+	if sealed.srcPubkey, err = crypto.RecoverPubkey(h[:], sig); err != nil {
+		return SealedEnvelope{}, errors.Wrap(err, "failed to recover sender of ethereum transaction")
+	}
+	sealed.payload.SetEnvelopeContext(sealed)
+	return sealed, nil
+}
+
+// SignEthereumTx signs the envelope of a transfer or execution as a RLP-encoded ethereum transaction of the network
+func SignEthereumTx(act Envelope, evmNetworkID uint32, sk crypto.PrivateKey) (SealedEnvelope, error) {
+	sealed := SealedEnvelope{
+		Envelope:     act,
+		srcPubkey:    sk.PublicKey(),
+		encoding:     EthereumRLPEncoding,
+		evmNetworkID: evmNetworkID,
+	}
+	h, err := sealed.envelopeHash()
+	if err != nil {
+		return sealed, err
+	}
+	if sealed.signature, err = sk.Sign(h[:]); err != nil {
+		return sealed, errors.Wrapf(ErrAction, "failed to sign ethereum transaction hash = %x", h)
+	}
+	sealed.payload.SetEnvelopeContext(sealed)
+	return sealed, nil
+}
+
+// ethTx converts the envelope of a transfer or execution into the unsigned ethereum transaction
+func (elp *Envelope) ethTx() (*types.Transaction, error) {
+	switch act := elp.payload.(type) {
+	case *Transfer:
+		to, err := address.FromString(act.Recipient())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewTransaction(
+			elp.nonce, common.BytesToAddress(to.Bytes()), act.Amount(), elp.gasLimit, elp.GasPrice(), act.Payload(),
+		), nil
+	case *Execution:
+		if act.Contract() == EmptyAddress {
+			return types.NewContractCreation(elp.nonce, act.Amount(), elp.gasLimit, elp.GasPrice(), act.Data()), nil
+		}
+		to, err := address.FromString(act.Contract())
+		if err != nil {
+			return nil, err
+		}
+		return types.NewTransaction(
+			elp.nonce, common.BytesToAddress(to.Bytes()), act.Amount(), elp.gasLimit, elp.GasPrice(), act.Data(),
+		), nil
+	default:
+		return nil, errors.Wrapf(ErrAction, "action %T cannot be encoded as ethereum transaction", act)
+	}
+}
+
+// ethSigner returns the EIP-155 signer of the network of the sealed envelope
+func (sealed *SealedEnvelope) ethSigner() types.Signer {
+	return types.NewEIP155Signer(new(big.Int).SetUint64(uint64(sealed.evmNetworkID)))
+}
+
+// ethTxHash returns the hash of the signed ethereum transaction, which is its action hash
+func (sealed *SealedEnvelope) ethTxHash() (hash.Hash256, error) {
+	if len(sealed.signature) != 65 {
+		return hash.ZeroHash256, errors.Wrapf(ErrAction, "invalid signature length %d", len(sealed.signature))
+	}
+	tx, err := sealed.Envelope.ethTx()
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	sig := make([]byte, 65)
+	copy(sig, sealed.signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if tx, err = tx.WithSignature(sealed.ethSigner(), sig); err != nil {
+		return hash.ZeroHash256, err
+	}
+	return hash.BytesToHash256(tx.Hash().Bytes()), nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func signEthTx(t *testing.T, tx *types.Transaction, chainID int64) []byte {
+	sk := identityset.PrivateKey(27).EcdsaPrivateKey().(*ecdsa.PrivateKey)
+	tx, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(chainID)), sk)
+	require.NoError(t, err)
+	raw, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestDecodeEthereumTx(t *testing.T) {
+	require := require.New(t)
+	to := common.BytesToAddress(identityset.Address(28).Bytes())
+
+	t.Run("transfer", func(t *testing.T) {
+		tx := types.NewTransaction(3, to, big.NewInt(100), 21000, big.NewInt(1000), nil)
+		raw := signEthTx(t, tx, 4689)
+		selp, err := DecodeEthereumTx(raw)
+		require.NoError(err)
+		require.Equal(EthereumRLPEncoding, selp.Encoding())
+		require.EqualValues(4689, selp.EVMNetworkID())
+		require.Equal(identityset.PrivateKey(27).PublicKey().HexString(), selp.SrcPubkey().HexString())
+		tsf, ok := selp.Action().(*Transfer)
+		require.True(ok)
+		require.Equal(identityset.Address(28).String(), tsf.Recipient())
+		require.EqualValues(100, tsf.Amount().Int64())
+		require.EqualValues(3, selp.Nonce())
+		require.NoError(Verify(selp))
+
+		// the action hash is the hash of the ethereum transaction
+		signed := &types.Transaction{}
+		require.NoError(rlp.DecodeBytes(raw, signed))
+		require.Equal(hash.BytesToHash256(signed.Hash().Bytes()), selp.Hash())
+
+		// the encoding survives the serialization
+		b, err := proto.Marshal(selp.Proto())
+		require.NoError(err)
+		pbAct := &iotextypes.Action{}
+		require.NoError(proto.Unmarshal(b, pbAct))
+		var loaded SealedEnvelope
+		require.NoError(loaded.LoadProto(pbAct))
+		require.Equal(EthereumRLPEncoding, loaded.Encoding())
+		require.Equal(selp.Hash(), loaded.Hash())
+		require.NoError(Verify(loaded))
+
+		// the signature doesn't verify on another chain
+		loaded.evmNetworkID = 1
+		require.Error(Verify(loaded))
+	})
+
+	t.Run("execution", func(t *testing.T) {
+		raw := signEthTx(t, types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(1), []byte{0x60, 0x00}), 4690)
+		selp, err := DecodeEthereumTx(raw)
+		require.NoError(err)
+		exec, ok := selp.Action().(*Execution)
+		require.True(ok)
+		require.Equal(EmptyAddress, exec.Contract())
+		require.NoError(Verify(selp))
+
+		raw = signEthTx(t, types.NewTransaction(2, to, big.NewInt(0), 100000, big.NewInt(1), []byte{1, 2}), 4690)
+		selp, err = DecodeEthereumTx(raw)
+		require.NoError(err)
+		exec, ok = selp.Action().(*Execution)
+		require.True(ok)
+		require.Equal(identityset.Address(28).String(), exec.Contract())
+		require.Equal([]byte{1, 2}, exec.Data())
+		require.NoError(Verify(selp))
+	})
+
+	t.Run("sign", func(t *testing.T) {
+		tsf, err := NewTransfer(1, big.NewInt(10), identityset.Address(28).String(), nil, 21000, big.NewInt(1))
+		require.NoError(err)
+		elp := (&EnvelopeBuilder{}).SetNonce(1).SetGasLimit(21000).SetGasPrice(big.NewInt(1)).SetAction(tsf).Build()
+		selp, err := SignEthereumTx(elp, 4689, identityset.PrivateKey(27))
+		require.NoError(err)
+		require.NoError(Verify(selp))
+		selp2, err := DecodeEthereumTx(signEthTx(t, types.NewTransaction(1, to, big.NewInt(10), 21000, big.NewInt(1), nil), 4689))
+		require.NoError(err)
+		require.Equal(selp2.Hash(), selp.Hash())
+	})
+
+	t.Run("IoTeX encoding", func(t *testing.T) {
+		tsf, err := NewTransfer(1, big.NewInt(10), identityset.Address(28).String(), nil, 21000, big.NewInt(1))
+		require.NoError(err)
+		elp := (&EnvelopeBuilder{}).SetNonce(1).SetGasLimit(21000).SetGasPrice(big.NewInt(1)).SetAction(tsf).Build()
+		selp, err := Sign(elp, identityset.PrivateKey(27))
+		require.NoError(err)
+		// the actions signed before the ethereum transactions are activated carry no extension, and keep their hash
+		pbAct := selp.Proto()
+		require.Empty(pbAct.ProtoReflect().GetUnknown())
+		require.Equal(hash.Hash256b(byteutil.Must(proto.Marshal(&iotextypes.Action{
+			Core:         elp.Proto(),
+			SenderPubKey: identityset.PrivateKey(27).PublicKey().Bytes(),
+			Signature:    selp.Signature(),
+		}))), selp.Hash())
+		ethSelp, err := SignEthereumTx(elp, 4689, identityset.PrivateKey(27))
+		require.NoError(err)
+		require.NotEqual(selp.Hash(), ethSelp.Hash())
+		// nor is an ethereum transaction sponsored
+		_, err = Sponsor(ethSelp, identityset.PrivateKey(28))
+		require.Error(err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodeEthereumTx([]byte{0xf8, 0x6b})
+		require.Error(err)
+		// no replay protection
+		tx, err := types.SignTx(types.NewTransaction(1, to, big.NewInt(1), 21000, big.NewInt(1), nil),
+			types.HomesteadSigner{}, identityset.PrivateKey(27).EcdsaPrivateKey().(*ecdsa.PrivateKey))
+		require.NoError(err)
+		raw, err := rlp.EncodeToBytes(tx)
+		require.NoError(err)
+		_, err = DecodeEthereumTx(raw)
+		require.Error(err)
+
+		// the actions other than transfer and execution cannot be signed as ethereum transaction
+		elp := (&EnvelopeBuilder{}).SetNonce(1).SetGasLimit(10000).SetAction(NewPutPollResult(1, 1, nil)).Build()
+		_, err = SignEthereumTx(elp, 4689, identityset.PrivateKey(27))
+		require.Error(err)
+	})
+}
@@ -41,13 +41,18 @@ func (p *Protocol) handleTransfer(ctx context.Context, act action.Action, sm pro
 	}
 
 	gasFee := big.NewInt(0).Mul(tsf.GasPrice(), big.NewInt(0).SetUint64(actionCtx.IntrinsicGas))
-	if big.NewInt(0).Add(tsf.Amount(), gasFee).Cmp(sender.Balance) == 1 {
+	// the gas fee of a sponsored transfer is deposited from the balance of the payer
+	required := big.NewInt(0).Add(tsf.Amount(), gasFee)
+	if actionCtx.GasPayer != nil {
+		required = tsf.Amount()
+	}
+	if required.Cmp(sender.Balance) == 1 {
 		return nil, errors.Wrapf(
 			state.ErrNotEnoughBalance,
 			"sender %s balance %s, required amount %s",
 			actionCtx.Caller.String(),
 			sender.Balance,
-			big.NewInt(0).Add(tsf.Amount(), gasFee),
+			required,
 		)
 	}
 
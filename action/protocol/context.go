@@ -26,6 +26,10 @@ type (
 
 	registryContextKey struct{}
 
+	verifiedActionContextKey struct{}
+
+	verifiedActionsContextKey struct{}
+
 	// TipInfo contains the tip block information
 	TipInfo struct {
 		Height    uint64
@@ -39,6 +43,8 @@ type (
 		Genesis genesis.Genesis
 		// Tip is the information of tip block
 		Tip TipInfo
+		// EvmNetworkID is the chain id of the network in the ethereum transactions
+		EvmNetworkID uint32
 	}
 
 	// BlockCtx provides block auxiliary information.
@@ -65,9 +71,31 @@ type (
 		IntrinsicGas uint64
 		// Nonce is the nonce of the action
 		Nonce uint64
+		// GasPayer is the address of whom pays the gas fee of a sponsored action, or nil if the caller pays it
+		GasPayer address.Address
 	}
 )
 
+// WithVerifiedAction marks in context that the signature of the action of the given hash has been verified
+func WithVerifiedAction(ctx context.Context, h hash.Hash256) context.Context {
+	return context.WithValue(ctx, verifiedActionContextKey{}, h)
+}
+
+// WithVerifiedActions marks in context that the signatures of all actions have been verified, as the block containing
+// them has been prevalidated
+func WithVerifiedActions(ctx context.Context) context.Context {
+	return context.WithValue(ctx, verifiedActionsContextKey{}, true)
+}
+
+// IsVerifiedAction returns whether the signature of the action of the given hash has been verified
+func IsVerifiedAction(ctx context.Context, h hash.Hash256) bool {
+	if all, ok := ctx.Value(verifiedActionsContextKey{}).(bool); ok && all {
+		return true
+	}
+	verified, ok := ctx.Value(verifiedActionContextKey{}).(hash.Hash256)
+	return ok && verified == h
+}
+
 // WithRegistry adds registry to context
 func WithRegistry(ctx context.Context, reg *Registry) context.Context {
 	return context.WithValue(ctx, registryContextKey{}, reg)
@@ -32,6 +32,15 @@ func TestRegistryCtx(t *testing.T) {
 	require.Equal(reg, MustGetRegistry(ctx))
 }
 
+func TestVerifiedActionCtx(t *testing.T) {
+	require := require.New(t)
+	h := hash.Hash256b([]byte("action"))
+	require.False(IsVerifiedAction(context.Background(), h))
+	ctx := WithVerifiedAction(context.Background(), h)
+	require.True(IsVerifiedAction(ctx, h))
+	require.False(IsVerifiedAction(ctx, hash.ZeroHash256))
+}
+
 func TestWithBlockchainCtx(t *testing.T) {
 	require := require.New(t)
 	bcCtx := BlockchainCtx{
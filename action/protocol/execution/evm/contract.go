@@ -7,7 +7,9 @@
 package evm
 
 import (
+	"bytes"
 	"context"
+	"sort"
 
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/pkg/errors"
@@ -41,6 +43,21 @@ type (
 		LoadRoot() error
 		Iterator() (trie.Iterator, error)
 		Snapshot() Contract
+		// StorageChanges returns the slots of the storage changed since the last commit
+		StorageChanges() ([]StorageChange, error)
+	}
+
+	// StorageChange is the change of a slot of the contract storage, the value is nil if the slot doesn't exist
+	StorageChange struct {
+		Key      hash.Hash256
+		OldValue []byte
+		NewValue []byte
+	}
+
+	// StorageChangeRecorder records the storage changes of the contracts, it is implemented by the state manager
+	// which tracks the state diff of a block
+	StorageChangeRecorder interface {
+		RecordStorageChanges(hash.Hash160, []StorageChange)
 	}
 
 	contract struct {
@@ -51,6 +68,7 @@ type (
 		code       SerializableBytes // contract byte-code
 		root       hash.Hash256
 		committed  map[hash.Hash256][]byte
+		original   map[hash.Hash256][]byte // value of the slots before they're set since the last commit
 		sm         protocol.StateManager
 		trie       trie.Trie // storage trie of the contract
 	}
@@ -85,6 +103,9 @@ func (c *contract) SetState(key hash.Hash256, value []byte) error {
 	if _, ok := c.committed[key]; !ok {
 		c.GetState(key)
 	}
+	if _, ok := c.original[key]; !ok {
+		c.original[key] = c.committed[key]
+	}
 	c.dirtyState = true
 	if err := c.trie.Upsert(key[:], value); err != nil {
 		return err
@@ -139,6 +160,7 @@ func (c *contract) Commit() error {
 		// purge the committed value cache
 		c.committed = nil
 		c.committed = make(map[hash.Hash256][]byte)
+		c.original = make(map[hash.Hash256][]byte)
 	}
 	if c.dirtyCode {
 		if _, err := c.sm.PutState(c.code, protocol.NamespaceOption(CodeKVNameSpace), protocol.KeyOption(c.Account.CodeHash)); err != nil {
@@ -171,6 +193,7 @@ func (c *contract) Snapshot() Contract {
 		code:       c.code,
 		root:       c.Account.Root,
 		committed:  c.committed,
+		original:   c.original,
 		sm:         c.sm,
 		// note we simply save the trie (which is an interface/pointer)
 		// later Revert() call needs to reset the saved trie root
@@ -178,22 +201,51 @@ func (c *contract) Snapshot() Contract {
 	}
 }
 
+// StorageChanges returns the slots of the storage changed since the last commit, in the order of the keys
+func (c *contract) StorageChanges() ([]StorageChange, error) {
+	changes := make([]StorageChange, 0, len(c.original))
+	for key, oldValue := range c.original {
+		newValue, err := c.trie.Get(key[:])
+		switch errors.Cause(err) {
+		case nil:
+		case trie.ErrNotExist:
+			newValue = nil
+		default:
+			return nil, err
+		}
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, StorageChange{Key: key, OldValue: oldValue, NewValue: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].Key[:], changes[j].Key[:]) < 0
+	})
+	return changes, nil
+}
+
+// StorageTrieHashFunc returns the hash func of the nodes of the storage trie of a contract
+func StorageTrieHashFunc(addr hash.Hash160) mptrie.HashFunc {
+	return func(data []byte) []byte {
+		h := hash.Hash256b(append(addr[:], data...))
+		return h[:]
+	}
+}
+
 // newContract returns a Contract instance
 func newContract(addr hash.Hash160, account *state.Account, sm protocol.StateManager, enableAsync bool) (Contract, error) {
 	c := &contract{
 		Account:   account,
 		root:      account.Root,
 		committed: make(map[hash.Hash256][]byte),
+		original:  make(map[hash.Hash256][]byte),
 		sm:        sm,
 		async:     enableAsync,
 	}
 	options := []mptrie.Option{
 		mptrie.KVStoreOption(newKVStoreForTrieWithStateManager(ContractKVNameSpace, sm)),
 		mptrie.KeyLengthOption(len(hash.Hash256{})),
-		mptrie.HashFuncOption(func(data []byte) []byte {
-			h := hash.Hash256b(append(addr[:], data...))
-			return h[:]
-		}),
+		mptrie.HashFuncOption(StorageTrieHashFunc(addr)),
 	}
 	if account.Root != hash.ZeroHash256 {
 		options = append(options, mptrie.RootHashOption(account.Root[:]))
@@ -278,3 +278,30 @@ func TestSnapshot(t *testing.T) {
 		testfunc(true)
 	})
 }
+
+func TestStorageChanges(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	sm, err := initMockStateManager(ctrl)
+	require.NoError(err)
+	c, err := newContract(hash.BytesToHash160(identityset.Address(28).Bytes()), &state.Account{}, sm, false)
+	require.NoError(err)
+
+	require.NoError(c.SetState(k1b, v1b[:]))
+	require.NoError(c.SetState(k2b, v1b[:]))
+	require.NoError(c.SetState(k2b, v2b[:]))
+	require.NoError(c.Commit())
+	changes, err := c.StorageChanges()
+	require.NoError(err)
+	require.Empty(changes)
+
+	// k1 is set back to its value, k2 keeps the value before the first change
+	require.NoError(c.SetState(k1b, v3b[:]))
+	require.NoError(c.SetState(k1b, v1b[:]))
+	require.NoError(c.SetState(k2b, v3b[:]))
+	require.NoError(c.SetState(k2b, v4b[:]))
+	changes, err = c.StorageChanges()
+	require.NoError(err)
+	require.Equal([]StorageChange{{Key: k2b, OldValue: v2b[:], NewValue: v4b[:]}}, changes)
+}
@@ -7,7 +7,6 @@
 package evm
 
 import (
-	"bytes"
 	"context"
 	"math"
 	"math/big"
@@ -29,7 +28,6 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/log"
-	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 )
 
 var (
@@ -76,6 +74,8 @@ type (
 		contract           *common.Address
 		gas                uint64
 		data               []byte
+		// gasPayer is the address paying the gas fee, which is the payer of a sponsored execution, or the executor
+		gasPayer common.Address
 	}
 )
 
@@ -90,6 +90,10 @@ func newParams(
 	blkCtx := protocol.MustGetBlockCtx(ctx)
 	bcCtx := protocol.MustGetBlockchainCtx(ctx)
 	executorAddr := common.BytesToAddress(actionCtx.Caller.Bytes())
+	gasPayer := executorAddr
+	if actionCtx.GasPayer != nil {
+		gasPayer = common.BytesToAddress(actionCtx.GasPayer.Bytes())
+	}
 	var contractAddrPointer *common.Address
 	if execution.Contract() != action.EmptyAddress {
 		contract, err := address.FromString(execution.Contract())
@@ -147,6 +151,7 @@ func newParams(
 		contractAddrPointer,
 		gasLimit,
 		execution.Data(),
+		gasPayer,
 	}, nil
 }
 
@@ -161,10 +166,10 @@ func securityDeposit(ps *Params, stateDB vm.StateDB, gasLimit uint64) error {
 		return action.ErrHitGasLimit
 	}
 	maxGasValue := new(big.Int).Mul(new(big.Int).SetUint64(ps.gas), ps.context.GasPrice)
-	if stateDB.GetBalance(ps.context.Origin).Cmp(maxGasValue) < 0 {
+	if stateDB.GetBalance(ps.gasPayer).Cmp(maxGasValue) < 0 {
 		return action.ErrInsufficientBalanceForGas
 	}
-	stateDB.SubBalance(ps.context.Origin, maxGasValue)
+	stateDB.SubBalance(ps.gasPayer, maxGasValue)
 	return nil
 }
 
@@ -187,11 +192,20 @@ func ExecuteContract(
 		hu.IsPost(config.Greenland, blkCtx.BlockHeight),
 		execution.Hash(),
 	)
+	if overrides, ok := GetStateOverrides(ctx); ok {
+		if err := overrides.Apply(stateDB); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to apply state overrides")
+		}
+	}
 	ps, err := newParams(ctx, execution, stateDB, getBlockHash)
 	if err != nil {
 		return nil, nil, err
 	}
-	retval, depositGas, remainingGas, contractAddress, statusCode, err := executeInEVM(ps, stateDB, hu, blkCtx.GasLimit, blkCtx.BlockHeight)
+	var evmConfig vm.Config
+	if tracer, ok := GetTracer(ctx); ok {
+		evmConfig.Debug, evmConfig.Tracer = true, tracer
+	}
+	retval, depositGas, remainingGas, contractAddress, statusCode, err := executeInEVM(ps, stateDB, evmConfig, hu, blkCtx.GasLimit, blkCtx.BlockHeight)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -206,11 +220,11 @@ func ExecuteContract(
 	var burnLog *action.TransactionLog
 	if hu.IsPost(config.Pacific, blkCtx.BlockHeight) {
 		// Refund all deposit and, actual gas fee will be subtracted when depositing gas fee to the rewarding protocol
-		stateDB.AddBalance(ps.context.Origin, big.NewInt(0).Mul(big.NewInt(0).SetUint64(depositGas), ps.context.GasPrice))
+		stateDB.AddBalance(ps.gasPayer, big.NewInt(0).Mul(big.NewInt(0).SetUint64(depositGas), ps.context.GasPrice))
 	} else {
 		if remainingGas > 0 {
 			remainingValue := new(big.Int).Mul(new(big.Int).SetUint64(remainingGas), ps.context.GasPrice)
-			stateDB.AddBalance(ps.context.Origin, remainingValue)
+			stateDB.AddBalance(ps.gasPayer, remainingValue)
 		}
 		if depositGas-remainingGas > 0 {
 			burnLog = &action.TransactionLog{
@@ -240,12 +254,11 @@ func ExecuteContract(
 		receipt.AddTransactionLogs(stateDB.TransactionLogs()...)
 	}
 
-	if hu.IsPost(config.Hawaii, blkCtx.BlockHeight) && receipt.Status == uint64(iotextypes.ReceiptStatus_ErrExecutionReverted) && retval != nil && bytes.Equal(retval[:4], revertSelector) {
+	if hu.IsPost(config.Hawaii, blkCtx.BlockHeight) && receipt.Status == uint64(iotextypes.ReceiptStatus_ErrExecutionReverted) {
 		// in case of the execution revert error, parse the retVal and add to receipt
-		data := retval[4:]
-		msgLength := byteutil.BytesToUint64BigEndian(data[56:64])
-		revertMsg := string(data[64 : 64+msgLength])
-		receipt.SetExecutionRevertMsg(revertMsg)
+		if revertMsg, ok := revertMessage(retval); ok {
+			receipt.SetExecutionRevertMsg(revertMsg)
+		}
 	}
 	log.S().Debugf("Receipt: %+v, %v", receipt, err)
 	return retval, receipt, nil
@@ -262,16 +275,15 @@ func getChainConfig(hu config.HeightUpgrade) *params.ChainConfig {
 }
 
 //Error in executeInEVM is a consensus issue
-func executeInEVM(evmParams *Params, stateDB *StateDBAdapter, hu config.HeightUpgrade, gasLimit uint64, blockHeight uint64) ([]byte, uint64, uint64, string, uint64, error) {
+func executeInEVM(evmParams *Params, stateDB *StateDBAdapter, evmConfig vm.Config, hu config.HeightUpgrade, gasLimit uint64, blockHeight uint64) ([]byte, uint64, uint64, string, uint64, error) {
 	isBering := hu.IsPost(config.Bering, blockHeight)
 	remainingGas := evmParams.gas
 	if err := securityDeposit(evmParams, stateDB, gasLimit); err != nil {
 		log.L().Warn("unexpected error: not enough security deposit", zap.Error(err))
 		return nil, 0, 0, action.EmptyAddress, uint64(iotextypes.ReceiptStatus_Failure), err
 	}
-	var config vm.Config
 	chainConfig := getChainConfig(hu)
-	evm := vm.NewEVM(evmParams.context, stateDB, chainConfig, config)
+	evm := vm.NewEVM(evmParams.context, stateDB, chainConfig, evmConfig)
 	intriGas, err := intrinsicGas(evmParams.data)
 	if err != nil {
 		return nil, evmParams.gas, remainingGas, action.EmptyAddress, uint64(iotextypes.ReceiptStatus_Failure), err
@@ -390,8 +402,8 @@ func SimulateExecution(
 		ctx,
 		protocol.BlockCtx{
 			BlockHeight:    bcCtx.Tip.Height + 1,
-			BlockTimeStamp: bcCtx.Tip.Timestamp.Add(bcCtx.Genesis.BlockInterval),
-			GasLimit:       bcCtx.Genesis.BlockGasLimit,
+			BlockTimeStamp: bcCtx.Tip.Timestamp.Add(bcCtx.Genesis.BlockIntervalByHeight(bcCtx.Tip.Height + 1)),
+			GasLimit:       bcCtx.Genesis.BlockGasLimitByHeight(bcCtx.Tip.Height + 1),
 			Producer:       zeroAddr,
 		},
 	)
@@ -659,6 +659,16 @@ func (stateDB *StateDBAdapter) CommitContracts() error {
 			continue
 		}
 		contract := stateDB.cachedContract[addr]
+		if recorder, ok := stateDB.sm.(StorageChangeRecorder); ok {
+			changes, err := contract.StorageChanges()
+			if err != nil {
+				stateDB.logError(err)
+				return errors.Wrap(err, "failed to get storage changes of contract")
+			}
+			if len(changes) > 0 {
+				recorder.RecordStorageChanges(addr, changes)
+			}
+		}
 		if err := contract.Commit(); err != nil {
 			stateDB.logError(err)
 			return errors.Wrap(err, "failed to commit contract")
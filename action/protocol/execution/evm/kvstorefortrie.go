@@ -19,11 +19,17 @@ import (
 
 type kvStoreForTrie struct {
 	nsOpt protocol.StateOption
+	sr    protocol.StateReader
 	sm    protocol.StateManager
 }
 
 func newKVStoreForTrieWithStateManager(ns string, sm protocol.StateManager) trie.KVStore {
-	return &kvStoreForTrie{nsOpt: protocol.NamespaceOption(ns), sm: sm}
+	return &kvStoreForTrie{nsOpt: protocol.NamespaceOption(ns), sr: sm, sm: sm}
+}
+
+// newKVStoreForTrieWithStateReader returns a read only kv store of the trie
+func newKVStoreForTrieWithStateReader(ns string, sr protocol.StateReader) trie.KVStore {
+	return &kvStoreForTrie{nsOpt: protocol.NamespaceOption(ns), sr: sr}
 }
 
 func (kv *kvStoreForTrie) Start(context.Context) error {
@@ -35,6 +41,9 @@ func (kv *kvStoreForTrie) Stop(context.Context) error {
 }
 
 func (kv *kvStoreForTrie) Put(key []byte, value []byte) error {
+	if kv.sm == nil {
+		return errors.New("kv store of the trie is read only")
+	}
 	var sb SerializableBytes
 	if err := sb.Deserialize(value); err != nil {
 		return err
@@ -45,6 +54,9 @@ func (kv *kvStoreForTrie) Put(key []byte, value []byte) error {
 }
 
 func (kv *kvStoreForTrie) Delete(key []byte) error {
+	if kv.sm == nil {
+		return errors.New("kv store of the trie is read only")
+	}
 	_, err := kv.sm.DelState(protocol.KeyOption(key), kv.nsOpt)
 	if errors.Cause(err) == state.ErrStateNotExist {
 		return nil
@@ -54,7 +66,7 @@ func (kv *kvStoreForTrie) Delete(key []byte) error {
 
 func (kv *kvStoreForTrie) Get(key []byte) ([]byte, error) {
 	var value SerializableBytes
-	_, err := kv.sm.State(&value, protocol.KeyOption(key), kv.nsOpt)
+	_, err := kv.sr.State(&value, protocol.KeyOption(key), kv.nsOpt)
 	switch errors.Cause(err) {
 	case state.ErrStateNotExist:
 		return nil, errors.Wrapf(db.ErrNotExist, "failed to find key %x", key)
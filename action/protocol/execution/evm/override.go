@@ -0,0 +1,67 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type (
+	overridesCtxKey struct{}
+
+	// AccountOverride replaces parts of an account's state before a simulated execution, nil fields are left untouched
+	AccountOverride struct {
+		Balance   *big.Int
+		Nonce     *uint64
+		Code      []byte
+		StateDiff map[common.Hash]common.Hash
+	}
+
+	// StateOverrides are the account overrides of a simulated execution
+	StateOverrides map[common.Address]*AccountOverride
+)
+
+// WithStateOverrides attaches state overrides to the context, they are applied before a contract is executed with the
+// context. It must only be used for simulations, as the overridden state is committed along with the execution.
+func WithStateOverrides(ctx context.Context, overrides StateOverrides) context.Context {
+	return context.WithValue(ctx, overridesCtxKey{}, overrides)
+}
+
+// GetStateOverrides returns the state overrides attached to the context
+func GetStateOverrides(ctx context.Context) (StateOverrides, bool) {
+	overrides, ok := ctx.Value(overridesCtxKey{}).(StateOverrides)
+	return overrides, ok
+}
+
+// Apply writes the overrides into the state
+func (overrides StateOverrides) Apply(stateDB *StateDBAdapter) error {
+	for addr, override := range overrides {
+		if override == nil {
+			continue
+		}
+		if override.Nonce != nil {
+			stateDB.SetNonce(addr, *override.Nonce)
+		}
+		if override.Balance != nil {
+			stateDB.SubBalance(addr, stateDB.GetBalance(addr))
+			stateDB.AddBalance(addr, override.Balance)
+		}
+		if override.Code != nil {
+			stateDB.SetCode(addr, override.Code)
+		}
+		for k, v := range override.StateDiff {
+			stateDB.SetState(addr, k, v)
+		}
+		if err := stateDB.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
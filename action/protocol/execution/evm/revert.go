@@ -0,0 +1,81 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+// panicSelector is the function selector of the Panic(uint256) errors raised by solidity since 0.8
+var panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+// panicReasons are the descriptions of the panic codes of solidity
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// revertMessage returns the message of the Error(string) revert data, as stored in the receipt. Malformed data has
+// no message instead of failing the execution.
+func revertMessage(retval []byte) (string, bool) {
+	if len(retval) < 4 || !bytes.Equal(retval[:4], revertSelector) {
+		return "", false
+	}
+	data := retval[4:]
+	if len(data) < 64 {
+		return "", false
+	}
+	msgLength := byteutil.BytesToUint64BigEndian(data[56:64])
+	if msgLength > uint64(len(data)-64) {
+		return "", false
+	}
+	return string(data[64 : 64+msgLength]), true
+}
+
+// DecodeRevertReason returns a human readable reason of the revert data returned by a reverted execution. Besides
+// Error(string), it decodes the Panic(uint256) of solidity and reports the selector and arguments of custom errors.
+// An empty string is returned if there is no revert data.
+func DecodeRevertReason(retval []byte) string {
+	if len(retval) == 0 {
+		return ""
+	}
+	if msg, ok := revertMessage(retval); ok {
+		return msg
+	}
+	if len(retval) < 4 {
+		return "0x" + hex.EncodeToString(retval)
+	}
+	if bytes.Equal(retval[:4], panicSelector) && len(retval) == 36 {
+		code := new(big.Int).SetBytes(retval[4:])
+		if code.IsUint64() {
+			if reason, ok := panicReasons[code.Uint64()]; ok {
+				return fmt.Sprintf("panic 0x%x: %s", code, reason)
+			}
+		}
+		return fmt.Sprintf("panic 0x%x", code)
+	}
+	reason := "custom error 0x" + hex.EncodeToString(retval[:4])
+	if len(retval) > 4 {
+		reason += ": 0x" + hex.EncodeToString(retval[4:])
+	}
+	return reason
+}
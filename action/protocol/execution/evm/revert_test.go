@@ -0,0 +1,52 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRevertReason(t *testing.T) {
+	mustDecode := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+	// Error("not enough balance")
+	errorString := mustDecode("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000012" +
+		"6e6f7420656e6f7567682062616c616e63650000000000000000000000000000")
+	tests := []struct {
+		data   []byte
+		reason string
+	}{
+		{nil, ""},
+		{errorString, "not enough balance"},
+		// the message is longer than the data
+		{errorString[:4+64+8], "custom error 0x08c379a0: 0x" + hex.EncodeToString(errorString[4:4+64+8])},
+		{errorString[:4], "custom error 0x08c379a0"},
+		{mustDecode("4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011"), "panic 0x11: arithmetic underflow or overflow"},
+		{mustDecode("4e487b71" + "00000000000000000000000000000000000000000000000000000000000000ff"), "panic 0xff"},
+		{mustDecode("cf479181" + "0000000000000000000000000000000000000000000000000000000000000001"), "custom error 0xcf479181: 0x0000000000000000000000000000000000000000000000000000000000000001"},
+		{mustDecode("cf47"), "0xcf47"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.reason, DecodeRevertReason(test.data))
+	}
+
+	msg, ok := revertMessage(errorString)
+	require.True(t, ok)
+	require.Equal(t, "not enough balance", msg)
+	for _, data := range [][]byte{nil, errorString[:3], errorString[:40], errorString[:4+64+8]} {
+		_, ok = revertMessage(data)
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"context"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+)
+
+// StorageSlot is a slot of the storage of a contract
+type StorageSlot struct {
+	Key   hash.Hash256
+	Value []byte
+}
+
+// ReadContractStorageRange returns at most limit slots of the storage of the contract, in the order of the keys from
+// the first key not less than start, and the key of the slot following them, which is nil if there are no more slots
+func ReadContractStorageRange(
+	sr protocol.StateReader,
+	contract address.Address,
+	start hash.Hash256,
+	limit uint64,
+) ([]StorageSlot, *hash.Hash256, error) {
+	addr := hash.BytesToHash160(contract.Bytes())
+	account, err := accountutil.LoadAccount(sr, addr)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load account of contract %s", contract.String())
+	}
+	if account.Root == hash.ZeroHash256 {
+		return []StorageSlot{}, nil, nil
+	}
+	tr, err := mptrie.New(
+		mptrie.KVStoreOption(newKVStoreForTrieWithStateReader(ContractKVNameSpace, sr)),
+		mptrie.KeyLengthOption(len(hash.Hash256{})),
+		mptrie.HashFuncOption(StorageTrieHashFunc(addr)),
+		mptrie.RootHashOption(account.Root[:]),
+	)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to create storage trie of contract %s", contract.String())
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, nil, err
+	}
+	defer tr.Stop(context.Background())
+
+	iter, err := mptrie.NewOrderedLeafIterator(tr, start[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	slots := []StorageSlot{}
+	for {
+		key, value, err := iter.Next()
+		if err == trie.ErrEndOfIterator {
+			return slots, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		k := hash.BytesToHash256(key)
+		if uint64(len(slots)) == limit {
+			return slots, &k, nil
+		}
+		slots = append(slots, StorageSlot{Key: k, Value: value})
+	}
+}
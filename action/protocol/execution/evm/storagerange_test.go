@@ -0,0 +1,65 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestReadContractStorageRange(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	sm, err := initMockStateManager(ctrl)
+	require.NoError(err)
+
+	contract := identityset.Address(28)
+	addr := hash.BytesToHash160(contract.Bytes())
+	// the account is not a contract yet
+	slots, next, err := ReadContractStorageRange(sm, contract, hash.ZeroHash256, 10)
+	require.NoError(err)
+	require.Empty(slots)
+	require.Nil(next)
+
+	c, err := newContract(addr, &state.Account{}, sm, false)
+	require.NoError(err)
+	var keys []hash.Hash256
+	for i := byte(5); i > 0; i-- {
+		key := hash.BytesToHash256([]byte{2 * i})
+		keys = append([]hash.Hash256{key}, keys...)
+		require.NoError(c.SetState(key, []byte{i, i}))
+	}
+	require.NoError(c.Commit())
+	_, err = sm.PutState(c.SelfState(), protocol.LegacyKeyOption(addr))
+	require.NoError(err)
+
+	slots, next, err = ReadContractStorageRange(sm, contract, hash.ZeroHash256, 2)
+	require.NoError(err)
+	require.Equal([]StorageSlot{{Key: keys[0], Value: []byte{1, 1}}, {Key: keys[1], Value: []byte{2, 2}}}, slots)
+	require.Equal(keys[2], *next)
+
+	slots, next, err = ReadContractStorageRange(sm, contract, *next, 10)
+	require.NoError(err)
+	require.Len(slots, 3)
+	require.Equal(keys[2], slots[0].Key)
+	require.Equal(keys[4], slots[2].Key)
+	require.Nil(next)
+
+	// the start key doesn't need to exist
+	slots, _, err = ReadContractStorageRange(sm, contract, hash.BytesToHash256([]byte{5}), 10)
+	require.NoError(err)
+	require.Len(slots, 3)
+	require.Equal(keys[2], slots[0].Key)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+type (
+	tracerCtxKey struct{}
+
+	// CallFrame is a call made during an execution, as reported by the call tracer
+	CallFrame struct {
+		Type    string          `json:"type"`
+		From    common.Address  `json:"from"`
+		To      *common.Address `json:"to,omitempty"`
+		Value   *hexutil.Big    `json:"value,omitempty"`
+		Gas     hexutil.Uint64  `json:"gas"`
+		GasUsed hexutil.Uint64  `json:"gasUsed"`
+		Input   hexutil.Bytes   `json:"input"`
+		Output  hexutil.Bytes   `json:"output,omitempty"`
+		Error   string          `json:"error,omitempty"`
+		Calls   []*CallFrame    `json:"calls,omitempty"`
+
+		// bookkeeping of the parent when the call is made
+		gasIn, gasCost uint64
+		outOff, outLen int64
+		entered        bool
+	}
+
+	// CallTracer records the tree of calls made by an execution, in the format of the callTracer of ethereum clients
+	CallTracer struct {
+		root  *CallFrame
+		calls []*CallFrame
+	}
+)
+
+// WithTracer attaches an EVM tracer to the context, contracts executed with the context are traced by it
+func WithTracer(ctx context.Context, tracer vm.Tracer) context.Context {
+	return context.WithValue(ctx, tracerCtxKey{}, tracer)
+}
+
+// GetTracer returns the EVM tracer attached to the context
+func GetTracer(ctx context.Context) (vm.Tracer, bool) {
+	tracer, ok := ctx.Value(tracerCtxKey{}).(vm.Tracer)
+	return tracer, ok
+}
+
+// NewCallTracer creates a call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// Result returns the root call of the traced execution
+func (t *CallTracer) Result() *CallFrame {
+	return t.root
+}
+
+// CaptureStart records the top level call
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := "CALL"
+	if create {
+		typ = "CREATE"
+	}
+	t.root = &CallFrame{
+		Type:  typ,
+		From:  from,
+		To:    &to,
+		Value: (*hexutil.Big)(new(big.Int).Set(value)),
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	t.calls = []*CallFrame{t.root}
+	return nil
+}
+
+// CaptureState tracks the inner calls by the call opcodes and the change of depth
+func (t *CallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil {
+		return t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	if len(t.calls) == 0 {
+		return nil
+	}
+	// entered the pending call, its gas is now known
+	if top := t.calls[len(t.calls)-1]; depth == len(t.calls) && top != t.root && !top.entered {
+		top.Gas, top.entered = hexutil.Uint64(gas), true
+	}
+	// returned from inner calls
+	for len(t.calls) > depth {
+		t.exitCall(gas, memory, stack)
+	}
+
+	switch op {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		to := common.BigToAddress(stack.Back(1))
+		frame := &CallFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			To:      &to,
+			Gas:     hexutil.Uint64(stack.Back(0).Uint64()),
+			gasIn:   gas,
+			gasCost: cost,
+		}
+		args := 2
+		if op == vm.CALL || op == vm.CALLCODE {
+			frame.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(2)))
+			args = 3
+		} else if op == vm.DELEGATECALL {
+			frame.From, frame.Value = contract.Caller(), (*hexutil.Big)(new(big.Int).Set(contract.Value()))
+		}
+		frame.Input = memory.Get(stack.Back(args).Int64(), stack.Back(args+1).Int64())
+		frame.outOff, frame.outLen = stack.Back(args+2).Int64(), stack.Back(args+3).Int64()
+		t.enterCall(frame)
+	case vm.CREATE, vm.CREATE2:
+		t.enterCall(&CallFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			Value:   (*hexutil.Big)(new(big.Int).Set(stack.Back(0))),
+			Input:   memory.Get(stack.Back(1).Int64(), stack.Back(2).Int64()),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+	case vm.SELFDESTRUCT:
+		to := common.BigToAddress(stack.Back(0))
+		parent := t.calls[len(t.calls)-1]
+		parent.Calls = append(parent.Calls, &CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    &to,
+			Value: (*hexutil.Big)(env.StateDB.GetBalance(contract.Address())),
+		})
+	}
+	return nil
+}
+
+// CaptureFault records the error of the current call
+func (t *CallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if depth > 0 && depth <= len(t.calls) {
+		if frame := t.calls[depth-1]; frame.Error == "" {
+			frame.Error = err.Error()
+		}
+	}
+	return nil
+}
+
+// CaptureEnd records the result of the top level call
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	if t.root == nil {
+		return nil
+	}
+	t.root.GasUsed = hexutil.Uint64(gasUsed)
+	t.root.Output = common.CopyBytes(output)
+	if err != nil {
+		t.root.Error = err.Error()
+	}
+	t.calls = nil
+	return nil
+}
+
+func (t *CallTracer) enterCall(frame *CallFrame) {
+	parent := t.calls[len(t.calls)-1]
+	parent.Calls = append(parent.Calls, frame)
+	t.calls = append(t.calls, frame)
+}
+
+// exitCall closes the innermost call, the parent's stack now holds its result
+func (t *CallTracer) exitCall(gas uint64, memory *vm.Memory, stack *vm.Stack) {
+	frame := t.calls[len(t.calls)-1]
+	t.calls = t.calls[:len(t.calls)-1]
+	if used := int64(frame.gasIn) - int64(frame.gasCost) + int64(frame.Gas) - int64(gas); used > 0 {
+		frame.GasUsed = hexutil.Uint64(used)
+	}
+	ret := stack.Back(0)
+	switch frame.Type {
+	case vm.CREATE.String(), vm.CREATE2.String():
+		if ret.Sign() == 0 {
+			if frame.Error == "" {
+				frame.Error = "internal failure"
+			}
+			return
+		}
+		to := common.BigToAddress(ret)
+		frame.To = &to
+	default:
+		if ret.Sign() == 0 && frame.Error == "" {
+			frame.Error = "internal failure"
+		}
+		if frame.outLen > 0 {
+			frame.Output = memory.Get(frame.outOff, frame.outLen)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTracer(t *testing.T) {
+	require := require.New(t)
+
+	tracer := NewCallTracer()
+	ctx := WithTracer(context.Background(), tracer)
+	got, ok := GetTracer(ctx)
+	require.True(ok)
+	require.Equal(tracer, got)
+	_, ok = GetTracer(context.Background())
+	require.False(ok)
+
+	// call the identity precompile with 2 bytes of input and copy the output back, then stop
+	code := []byte{
+		0x61, 0xab, 0xcd, // PUSH2 0xabcd
+		0x60, 0x00, // PUSH1 0
+		0x52,       // MSTORE
+		0x60, 0x02, // PUSH1 2 (outLen)
+		0x60, 0x00, // PUSH1 0 (outOff)
+		0x60, 0x02, // PUSH1 2 (inLen)
+		0x60, 0x1e, // PUSH1 30 (inOff)
+		0x60, 0x00, // PUSH1 0 (value)
+		0x60, 0x04, // PUSH1 4 (identity)
+		0x61, 0xff, 0xff, // PUSH2 0xffff (gas)
+		byte(vm.CALL),
+		byte(vm.STOP),
+	}
+	_, _, err := runtime.Execute(code, []byte{0x01}, &runtime.Config{
+		EVMConfig: vm.Config{Debug: true, Tracer: tracer},
+	})
+	require.NoError(err)
+
+	root := tracer.Result()
+	require.Equal("CALL", root.Type)
+	require.Equal([]byte{0x01}, []byte(root.Input))
+	require.Empty(root.Error)
+	require.NotZero(root.GasUsed)
+	require.Len(root.Calls, 1)
+	call := root.Calls[0]
+	require.Equal("CALL", call.Type)
+	require.Equal(common.BytesToAddress([]byte{0x04}), *call.To)
+	require.Equal(*root.To, call.From)
+	require.EqualValues(0xffff, call.Gas)
+	require.Equal([]byte{0xab, 0xcd}, []byte(call.Input))
+	require.Equal([]byte{0xab, 0xcd}, []byte(call.Output))
+	require.Empty(call.Error)
+	require.NotZero(call.GasUsed)
+}
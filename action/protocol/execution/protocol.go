@@ -93,6 +93,17 @@ func (p *Protocol) ReadState(context.Context, protocol.StateReader, []byte, ...[
 	return nil, uint64(0), protocol.ErrUnimplemented
 }
 
+// ReadContractStorageRange returns at most limit slots of the storage of the contract from the start key, and the key
+// of the next slot, which is nil if there are no more slots
+func (p *Protocol) ReadContractStorageRange(
+	sr protocol.StateReader,
+	contract address.Address,
+	start hash.Hash256,
+	limit uint64,
+) ([]evm.StorageSlot, *hash.Hash256, error) {
+	return evm.ReadContractStorageRange(sr, contract, start, limit)
+}
+
 // Register registers the protocol with a unique ID
 func (p *Protocol) Register(r *protocol.Registry) error {
 	return r.Register(protocolID, p)
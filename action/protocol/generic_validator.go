@@ -37,13 +37,23 @@ func NewGenericValidator(sr StateReader, accountState AccountState) *GenericVali
 // Validate validates a generic action
 func (v *GenericValidator) Validate(ctx context.Context, selp action.SealedEnvelope) error {
 	// Verify action using action sender's public key
-	if err := action.Verify(selp); err != nil {
+	if err := v.verify(ctx, selp); err != nil {
 		return errors.Wrap(err, "failed to verify action signature")
 	}
+	if selp.Encoding() == action.EthereumRLPEncoding {
+		if err := validateEthereumTx(ctx, selp); err != nil {
+			return err
+		}
+	}
 	caller, err := address.FromBytes(selp.SrcPubkey().Hash())
 	if err != nil {
 		return err
 	}
+	if selp.IsSponsored() {
+		if err := validateSponsoredAction(ctx, caller, selp); err != nil {
+			return err
+		}
+	}
 	// Reject action if nonce is too low
 	confirmedState, err := v.accountState(v.sr, caller.String())
 	if err != nil {
@@ -56,3 +66,60 @@ func (v *GenericValidator) Validate(ctx context.Context, selp action.SealedEnvel
 	}
 	return selp.Action().SanityCheck()
 }
+
+// verify verifies the action, whose signature is skipped if it has been verified before
+func (v *GenericValidator) verify(ctx context.Context, selp action.SealedEnvelope) error {
+	if !IsVerifiedAction(ctx, selp.Hash()) {
+		return action.Verify(selp)
+	}
+	intrinsicGas, err := selp.IntrinsicGas()
+	if intrinsicGas > selp.GasLimit() || err != nil {
+		return errors.Wrap(action.ErrInsufficientBalanceForGas, "insufficient gas")
+	}
+	return nil
+}
+
+// validateSponsoredAction rejects the sponsored action before the activation height, or paid by its sender
+func validateSponsoredAction(ctx context.Context, caller address.Address, selp action.SealedEnvelope) error {
+	bcCtx, ok := GetBlockchainCtx(ctx)
+	if !ok {
+		return errors.New("missing blockchain context to validate sponsored action")
+	}
+	height := bcCtx.Tip.Height + 1
+	if blkCtx, ok := GetBlockCtx(ctx); ok {
+		height = blkCtx.BlockHeight
+	}
+	// the gas fee is charged to the sender before pacific height
+	g := bcCtx.Genesis
+	if height < g.SponsoredGasBlockHeight || height < g.PacificBlockHeight {
+		return errors.Wrapf(action.ErrAction, "sponsored action is not accepted until height %d", g.SponsoredGasBlockHeight)
+	}
+	payer, err := address.FromBytes(selp.PayerPubkey().Hash())
+	if err != nil {
+		return err
+	}
+	if address.Equal(payer, caller) {
+		return errors.Wrap(action.ErrAction, "sponsored action paid by its sender")
+	}
+	return nil
+}
+
+// validateEthereumTx rejects the ethereum transaction before the activation height, or signed for another chain
+func validateEthereumTx(ctx context.Context, selp action.SealedEnvelope) error {
+	bcCtx, ok := GetBlockchainCtx(ctx)
+	if !ok {
+		return errors.New("missing blockchain context to validate ethereum transaction")
+	}
+	height := bcCtx.Tip.Height + 1
+	if blkCtx, ok := GetBlockCtx(ctx); ok {
+		height = blkCtx.BlockHeight
+	}
+	g := bcCtx.Genesis
+	if height < g.EthereumTxBlockHeight {
+		return errors.Wrapf(action.ErrAction, "ethereum transaction is not accepted until height %d", g.EthereumTxBlockHeight)
+	}
+	if selp.EVMNetworkID() != bcCtx.EvmNetworkID {
+		return errors.Wrapf(action.ErrAction, "ethereum transaction of chain id %d", selp.EVMNetworkID())
+	}
+	return nil
+}
@@ -147,5 +147,67 @@ func TestActionProtoAndGenericValidator(t *testing.T) {
 			SetGasLimit(100000).Build()
 		selp := action.FakeSeal(elp, identityset.PrivateKey(27).PublicKey())
 		require.True(strings.Contains(valid.Validate(ctx, selp).Error(), "failed to verify action signature"))
+		// the signature is not verified again if it has been verified before
+		err = valid.Validate(WithVerifiedAction(ctx, selp.Hash()), selp)
+		require.False(err != nil && strings.Contains(err.Error(), "failed to verify action signature"))
+	})
+	t.Run("sponsored", func(t *testing.T) {
+		v, err := action.NewExecution("", 3, big.NewInt(10), uint64(100000), big.NewInt(10), data)
+		require.NoError(err)
+		bd := &action.EnvelopeBuilder{}
+		elp := bd.SetGasPrice(big.NewInt(10)).
+			SetNonce(3).
+			SetGasLimit(uint64(100000)).
+			SetAction(v).Build()
+		selp, err := action.Sign(elp, identityset.PrivateKey(28))
+		require.NoError(err)
+		sponsored, err := action.Sponsor(selp, identityset.PrivateKey(29))
+		require.NoError(err)
+		nselp := action.SealedEnvelope{}
+		require.NoError(nselp.LoadProto(sponsored.Proto()))
+
+		// rejected before the activation height
+		err = valid.Validate(ctx, nselp)
+		require.Error(err)
+		require.True(strings.Contains(err.Error(), "sponsored action is not accepted"))
+
+		bcCtx := MustGetBlockchainCtx(ctx)
+		bcCtx.Genesis.SponsoredGasBlockHeight = 1
+		sctx := WithBlockchainCtx(ctx, bcCtx)
+		require.NoError(valid.Validate(sctx, nselp))
+
+		// the sender cannot pay its own action as payer
+		selfPaid, err := action.Sponsor(selp, identityset.PrivateKey(28))
+		require.NoError(err)
+		err = valid.Validate(sctx, selfPaid)
+		require.Error(err)
+		require.True(strings.Contains(err.Error(), "paid by its sender"))
+	})
+	t.Run("ethereum transaction", func(t *testing.T) {
+		v, err := action.NewTransfer(3, big.NewInt(1), caller.String(), nil, uint64(21000), big.NewInt(10))
+		require.NoError(err)
+		elp := (&action.EnvelopeBuilder{}).SetNonce(3).SetGasLimit(21000).SetGasPrice(big.NewInt(10)).SetAction(v).Build()
+		selp, err := action.SignEthereumTx(elp, 4689, identityset.PrivateKey(28))
+		require.NoError(err)
+		plain, err := action.Sign(elp, identityset.PrivateKey(28))
+		require.NoError(err)
+
+		bcCtx := MustGetBlockchainCtx(ctx)
+		bcCtx.EvmNetworkID = 4689
+		// not accepted before the activation height
+		require.Error(valid.Validate(WithBlockchainCtx(ctx, bcCtx), selp))
+		bcCtx.Genesis.EthereumTxBlockHeight = 10
+		ethCtx := WithBlockchainCtx(ctx, bcCtx)
+		require.Error(valid.Validate(WithBlockCtx(ethCtx, BlockCtx{BlockHeight: 9}), selp))
+		require.NoError(valid.Validate(WithBlockCtx(ethCtx, BlockCtx{BlockHeight: 10}), selp))
+		// the actions of the IoTeX encoding are accepted on both sides of the activation height
+		for _, height := range []uint64{9, 10} {
+			require.NoError(valid.Validate(WithBlockCtx(ethCtx, BlockCtx{BlockHeight: height}), plain))
+		}
+		// nor signed for another chain
+		selp, err = action.SignEthereumTx(elp, 4690, identityset.PrivateKey(28))
+		require.NoError(err)
+		require.Error(valid.Validate(WithBlockCtx(ethCtx, BlockCtx{BlockHeight: 10}), selp))
+		require.Error(valid.Validate(context.Background(), selp))
 	})
 }
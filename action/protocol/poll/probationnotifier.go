@@ -0,0 +1,301 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// ProbationEventType is the type of a probation event
+type ProbationEventType string
+
+const (
+	// ProbationAtRisk is the event of a delegate whose productivity of the current epoch is below the threshold
+	ProbationAtRisk ProbationEventType = "atRisk"
+	// ProbationEntered is the event of a delegate put on probation from the next epoch
+	ProbationEntered ProbationEventType = "probation"
+	// ProbationResumed is the event of a delegate released from probation from the next epoch
+	ProbationResumed ProbationEventType = "resumed"
+)
+
+var probationEventsMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_probation_events",
+		Help: "Probation events of the delegates",
+	},
+	[]string{"type", "delegate"},
+)
+
+func init() {
+	prometheus.MustRegister(probationEventsMtc)
+}
+
+type (
+	// ProbationEvent is an event of the probation of a delegate
+	ProbationEvent struct {
+		Type     ProbationEventType `json:"type"`
+		Delegate string             `json:"delegate"`
+		// EpochNum is the current epoch for an at-risk event, and the next epoch for the others
+		EpochNum uint64 `json:"epochNum"`
+		Height   uint64 `json:"height"`
+		// Productivity is the percentage of the blocks produced by the delegate out of the expected in the current
+		// epoch till the height
+		Productivity uint64 `json:"productivity"`
+	}
+
+	// ProbationHook is notified of the probation events
+	ProbationHook interface {
+		Notify(ProbationEvent)
+	}
+
+	metricProbationHook struct{}
+
+	webhookProbationHook struct {
+		url    string
+		client *http.Client
+	}
+
+	// ProbationNotifier watches the productivity and the probation list of the delegates on the blocks, and notifies
+	// the hooks of the delegates at risk of, entering and resuming from probation
+	ProbationNotifier struct {
+		gen              genesis.Genesis
+		hu               config.HeightUpgrade
+		registry         *protocol.Registry
+		rp               *rolldpos.Protocol
+		pp               Protocol
+		sr               protocol.StateReader
+		getProbationList GetProbationList
+		productivity     Productivity
+		watched          map[string]bool
+		hooks            []ProbationHook
+		// atRisk is the epoch in which a delegate is last notified at risk
+		atRisk map[string]uint64
+	}
+)
+
+// NewMetricProbationHook returns a hook counting the probation events in metrics
+func NewMetricProbationHook() ProbationHook {
+	return &metricProbationHook{}
+}
+
+func (h *metricProbationHook) Notify(event ProbationEvent) {
+	probationEventsMtc.WithLabelValues(string(event.Type), event.Delegate).Inc()
+}
+
+// NewWebhookProbationHook returns a hook posting the probation events to the url in JSON
+func NewWebhookProbationHook(url string, timeout time.Duration) ProbationHook {
+	return &webhookProbationHook{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *webhookProbationHook) Notify(event ProbationEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.L().Error("Failed to marshal the probation event.", zap.Error(err))
+		return
+	}
+	go func() {
+		resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.L().Warn("Failed to post the probation event.", zap.String("type", string(event.Type)), zap.Error(err))
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			log.L().Warn("Probation webhook rejected the event.", zap.Int("status", resp.StatusCode))
+		}
+	}()
+}
+
+// NewProbationNotifier returns a probation notifier, the rolldpos and the poll protocols should be registered in the
+// registry. The delegates are the ones to notify about, all the delegates if it is empty
+func NewProbationNotifier(
+	gen genesis.Genesis,
+	registry *protocol.Registry,
+	sr protocol.StateReader,
+	getProbationList GetProbationList,
+	productivity Productivity,
+	delegates []string,
+	hooks ...ProbationHook,
+) (*ProbationNotifier, error) {
+	rp := rolldpos.FindProtocol(registry)
+	if rp == nil {
+		return nil, errors.New("rolldpos protocol is not registered")
+	}
+	pp := FindProtocol(registry)
+	if pp == nil {
+		return nil, errors.New("poll protocol is not registered")
+	}
+	watched := make(map[string]bool, len(delegates))
+	for _, delegate := range delegates {
+		watched[delegate] = true
+	}
+	return &ProbationNotifier{
+		gen:              gen,
+		hu:               config.NewHeightUpgrade(&gen),
+		registry:         registry,
+		rp:               rp,
+		pp:               pp,
+		sr:               sr,
+		getProbationList: getProbationList,
+		productivity:     productivity,
+		watched:          watched,
+		hooks:            hooks,
+		atRisk:           map[string]uint64{},
+	}, nil
+}
+
+// ReceiveBlock checks the productivity of the delegates on every block, and the probation list of the next epoch on
+// the last block of an epoch
+func (n *ProbationNotifier) ReceiveBlock(blk *block.Block) error {
+	height := blk.Height()
+	epochNum := n.rp.GetEpochNum(height)
+	if !n.hu.IsPost(config.Easter, n.rp.GetEpochHeight(epochNum+1)) {
+		return nil
+	}
+	stateHeight, err := n.sr.Height()
+	if err != nil {
+		return err
+	}
+	if n.rp.GetEpochNum(stateHeight) != epochNum {
+		// the delegates and the probation list in the state are of another epoch
+		log.L().Debug("Skip checking probation of a past epoch.", zap.Uint64("height", height))
+		return nil
+	}
+	for delegate, notified := range n.atRisk {
+		if notified < epochNum {
+			delete(n.atRisk, delegate)
+		}
+	}
+	productivities, err := n.productivities(height, epochNum)
+	if err != nil {
+		return err
+	}
+	for delegate, productivity := range productivities {
+		if n.atRisk[delegate] == epochNum || productivity >= n.gen.ProductivityThreshold {
+			continue
+		}
+		n.atRisk[delegate] = epochNum
+		n.notify(ProbationEvent{
+			Type:         ProbationAtRisk,
+			Delegate:     delegate,
+			EpochNum:     epochNum,
+			Height:       height,
+			Productivity: productivity,
+		})
+	}
+	if height != n.rp.GetEpochLastBlockHeight(epochNum) {
+		return nil
+	}
+	cur, err := n.probationList(false)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the probation list of the current epoch")
+	}
+	next, err := n.probationList(true)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the probation list of the next epoch")
+	}
+	for delegate := range next.ProbationInfo {
+		if _, ok := cur.ProbationInfo[delegate]; !ok {
+			n.notify(ProbationEvent{
+				Type:         ProbationEntered,
+				Delegate:     delegate,
+				EpochNum:     epochNum + 1,
+				Height:       height,
+				Productivity: productivities[delegate],
+			})
+		}
+	}
+	for delegate := range cur.ProbationInfo {
+		if _, ok := next.ProbationInfo[delegate]; !ok {
+			n.notify(ProbationEvent{
+				Type:         ProbationResumed,
+				Delegate:     delegate,
+				EpochNum:     epochNum + 1,
+				Height:       height,
+				Productivity: productivities[delegate],
+			})
+		}
+	}
+	return nil
+}
+
+// productivities returns the productivities of the delegates of the epoch till the height, which is empty before every
+// delegate gets its turn to produce
+func (n *ProbationNotifier) productivities(height, epochNum uint64) (map[string]uint64, error) {
+	ctx := protocol.WithBlockchainCtx(
+		protocol.WithRegistry(context.Background(), n.registry),
+		protocol.BlockchainCtx{
+			Genesis: n.gen,
+		},
+	)
+	delegates, err := n.pp.Delegates(ctx, n.sr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the delegates")
+	}
+	start := n.rp.GetEpochHeight(epochNum)
+	numBlks := height - start + 1
+	if len(delegates) == 0 || numBlks < uint64(len(delegates)) {
+		return map[string]uint64{}, nil
+	}
+	produce, err := n.productivity(start, height)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the productivity")
+	}
+	expected := numBlks / uint64(len(delegates))
+	productivities := make(map[string]uint64, len(delegates))
+	for _, delegate := range delegates {
+		productivities[delegate.Address] = produce[delegate.Address] * 100 / expected
+	}
+	return productivities, nil
+}
+
+func (n *ProbationNotifier) probationList(readFromNext bool) (*vote.ProbationList, error) {
+	list, _, err := n.getProbationList(n.sr, readFromNext)
+	switch errors.Cause(err) {
+	case nil:
+		return list, nil
+	case state.ErrStateNotExist:
+		// no delegate is on probation before the first probation list is calculated
+		return vote.NewProbationList(0), nil
+	default:
+		return nil, err
+	}
+}
+
+func (n *ProbationNotifier) notify(event ProbationEvent) {
+	if len(n.watched) > 0 && !n.watched[event.Delegate] {
+		return
+	}
+	log.L().Info("Probation event of delegate.",
+		zap.String("type", string(event.Type)),
+		zap.String("delegate", event.Delegate),
+		zap.Uint64("epoch", event.EpochNum),
+		zap.Uint64("productivity", event.Productivity))
+	for _, hook := range n.hooks {
+		hook.Notify(event)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+	"github.com/iotexproject/iotex-core/test/mock/mock_poll"
+)
+
+type probationEvents []ProbationEvent
+
+func (e *probationEvents) Notify(event ProbationEvent) {
+	*e = append(*e, event)
+}
+
+func TestProbationNotifier(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gen := config.Default.Genesis
+	gen.EasterBlockHeight = 1
+	gen.ProductivityThreshold = 85
+	registry := protocol.NewRegistry()
+	// an epoch of 8 blocks
+	require.NoError(registry.Register("rolldpos", rolldpos.NewProtocol(4, 4, 2)))
+	delegates := state.CandidateList{}
+	for i := 0; i < 4; i++ {
+		delegates = append(delegates, &state.Candidate{Address: identityset.Address(i).String()})
+	}
+	pp := mock_poll.NewMockProtocol(ctrl)
+	pp.EXPECT().Delegates(gomock.Any(), gomock.Any()).Return(delegates, nil).AnyTimes()
+	require.NoError(registry.Register(protocolID, pp))
+
+	var tipHeight uint64
+	sr := mock_chainmanager.NewMockStateReader(ctrl)
+	sr.EXPECT().Height().DoAndReturn(func() (uint64, error) { return tipHeight, nil }).AnyTimes()
+	// delegate 2 is on probation in the current epoch
+	cur := vote.NewProbationList(50)
+	cur.ProbationInfo[delegates[2].Address] = 1
+	next := vote.NewProbationList(50)
+	next.ProbationInfo[delegates[3].Address] = 1
+	getProbationList := func(_ protocol.StateReader, readFromNext bool) (*vote.ProbationList, uint64, error) {
+		if readFromNext {
+			return next, tipHeight, nil
+		}
+		return cur, tipHeight, nil
+	}
+	// delegate 3 produces no block
+	producers := []string{"", delegates[0].Address, delegates[1].Address, delegates[2].Address}
+	productivity := func(start, end uint64) (map[string]uint64, error) {
+		produce := map[string]uint64{}
+		for h := start; h <= end; h++ {
+			produce[producers[h%3+1]]++
+		}
+		return produce, nil
+	}
+
+	events := &probationEvents{}
+	n, err := NewProbationNotifier(gen, registry, sr, getProbationList, productivity, nil, events)
+	require.NoError(err)
+	receive := func(height uint64) {
+		tipHeight = height
+		blk, err := block.NewTestingBuilder().SetHeight(height).SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		require.NoError(n.ReceiveBlock(&blk))
+	}
+
+	// no delegate is at risk before every delegate gets its turn
+	receive(1)
+	receive(3)
+	require.Empty(*events)
+	receive(4)
+	require.Equal(probationEvents{
+		{Type: ProbationAtRisk, Delegate: delegates[3].Address, EpochNum: 1, Height: 4, Productivity: 0},
+	}, *events)
+	// a delegate is notified at risk once in an epoch
+	receive(5)
+	require.Len(*events, 1)
+
+	// the probation list of the next epoch is checked at the last block of the epoch
+	*events = nil
+	receive(8)
+	require.ElementsMatch(probationEvents{
+		{Type: ProbationEntered, Delegate: delegates[3].Address, EpochNum: 2, Height: 8, Productivity: 0},
+		{Type: ProbationResumed, Delegate: delegates[2].Address, EpochNum: 2, Height: 8, Productivity: 150},
+	}, *events)
+
+	// the block of a past epoch is skipped
+	*events = nil
+	tipHeight = 9
+	blk, err := block.NewTestingBuilder().SetHeight(8).SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+	require.NoError(n.ReceiveBlock(&blk))
+	require.Empty(*events)
+
+	// the delegate is notified at risk again in the next epoch
+	receive(12)
+	require.Equal(probationEvents{
+		{Type: ProbationAtRisk, Delegate: delegates[3].Address, EpochNum: 2, Height: 12, Productivity: 0},
+	}, *events)
+
+	// only the watched delegates are notified about
+	*events = nil
+	n, err = NewProbationNotifier(gen, registry, sr, getProbationList, productivity, []string{delegates[2].Address}, events)
+	require.NoError(err)
+	receive(16)
+	require.Equal(probationEvents{
+		{Type: ProbationResumed, Delegate: delegates[2].Address, EpochNum: 3, Height: 16, Productivity: 100},
+	}, *events)
+}
+
+func TestWebhookProbationHook(t *testing.T) {
+	require := require.New(t)
+	received := make(chan ProbationEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event ProbationEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			received <- event
+		}
+	}))
+	defer srv.Close()
+
+	event := ProbationEvent{
+		Type:         ProbationAtRisk,
+		Delegate:     identityset.Address(0).String(),
+		EpochNum:     3,
+		Height:       100,
+		Productivity: 50,
+	}
+	NewWebhookProbationHook(srv.URL, time.Second).Notify(event)
+	select {
+	case got := <-received:
+		require.Equal(event, got)
+	case <-time.After(time.Second):
+		require.Fail("webhook is not called")
+	}
+}
@@ -65,20 +65,30 @@ func (p *Protocol) Deposit(
 	amount *big.Int,
 	transactionLogType iotextypes.TransactionLogType,
 ) (*action.TransactionLog, error) {
-	actionCtx := protocol.MustGetActionCtx(ctx)
+	return p.deposit(ctx, sm, protocol.MustGetActionCtx(ctx).Caller, amount, transactionLogType)
+}
+
+// deposit deposits token of the depositor into the rewarding fund
+func (p *Protocol) deposit(
+	ctx context.Context,
+	sm protocol.StateManager,
+	depositor address.Address,
+	amount *big.Int,
+	transactionLogType iotextypes.TransactionLogType,
+) (*action.TransactionLog, error) {
 	if err := p.assertAmount(amount); err != nil {
 		return nil, err
 	}
-	if err := p.assertEnoughBalance(actionCtx, sm, amount); err != nil {
+	if err := p.assertEnoughBalance(depositor, sm, amount); err != nil {
 		return nil, err
 	}
-	// Subtract balance from caller
-	acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	// Subtract balance from depositor
+	acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(depositor.Bytes()))
 	if err != nil {
 		return nil, err
 	}
 	acc.Balance = big.NewInt(0).Sub(acc.Balance, amount)
-	if err := accountutil.StoreAccount(sm, actionCtx.Caller, acc); err != nil {
+	if err := accountutil.StoreAccount(sm, depositor, acc); err != nil {
 		return nil, err
 	}
 	// Add balance to fund
@@ -93,7 +103,7 @@ func (p *Protocol) Deposit(
 	}
 	return &action.TransactionLog{
 		Type:      transactionLogType,
-		Sender:    actionCtx.Caller.String(),
+		Sender:    depositor.String(),
 		Recipient: address.RewardingPoolAddr,
 		Amount:    amount,
 	}, nil
@@ -126,11 +136,11 @@ func (p *Protocol) AvailableBalance(
 }
 
 func (p *Protocol) assertEnoughBalance(
-	actionCtx protocol.ActionCtx,
+	addr address.Address,
 	sm protocol.StateReader,
 	amount *big.Int,
 ) error {
-	acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(addr.Bytes()))
 	if err != nil {
 		return err
 	}
@@ -140,7 +150,7 @@ func (p *Protocol) assertEnoughBalance(
 	return nil
 }
 
-// DepositGas deposits gas into the rewarding fund
+// DepositGas deposits gas into the rewarding fund, which is paid by the payer of a sponsored action, or by the caller
 func DepositGas(ctx context.Context, sm protocol.StateManager, amount *big.Int) (*action.TransactionLog, error) {
 	// If the gas fee is 0, return immediately
 	if amount.Cmp(big.NewInt(0)) == 0 {
@@ -160,5 +170,10 @@ func DepositGas(ctx context.Context, sm protocol.StateManager, amount *big.Int)
 	if rp == nil {
 		return nil, nil
 	}
-	return rp.Deposit(ctx, sm, amount, iotextypes.TransactionLogType_GAS_FEE)
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	payer := actionCtx.Caller
+	if actionCtx.GasPayer != nil {
+		payer = actionCtx.GasPayer
+	}
+	return rp.deposit(ctx, sm, payer, amount, iotextypes.TransactionLogType_GAS_FEE)
 }
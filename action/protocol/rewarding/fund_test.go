@@ -20,6 +20,7 @@ import (
 
 	"github.com/iotexproject/iotex-core/action/protocol"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/test/identityset"
 )
 
 func TestProtocol_Fund(t *testing.T) {
@@ -58,3 +59,27 @@ func TestDepositNegativeGasFee(t *testing.T) {
 		require.Error(t, err)
 	}, false)
 }
+
+func TestDepositSponsoredGasFee(t *testing.T) {
+	testProtocol(t, func(t *testing.T, ctx context.Context, sm protocol.StateManager, p *Protocol) {
+		// the gas fee of a sponsored action is deposited from the payer's balance
+		ctx = protocol.WithActionCtx(ctx, protocol.ActionCtx{
+			Caller:   identityset.Address(27),
+			GasPayer: identityset.Address(28),
+		})
+		rlog, err := DepositGas(ctx, sm, big.NewInt(5))
+		require.NoError(t, err)
+		require.Equal(t, identityset.Address(28).String(), rlog.Sender)
+		require.Equal(t, iotextypes.TransactionLogType_GAS_FEE, rlog.Type)
+		acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(identityset.Address(28).Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(995), acc.Balance)
+		totalBalance, _, err := p.TotalBalance(ctx, sm)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(5), totalBalance)
+
+		// the caller doesn't pay
+		_, err = p.Deposit(ctx, sm, big.NewInt(5), iotextypes.TransactionLogType_DEPOSIT_TO_REWARDING_FUND)
+		require.Error(t, err)
+	}, false)
+}
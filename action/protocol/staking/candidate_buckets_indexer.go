@@ -124,6 +124,20 @@ func (cbi *CandidatesBucketsIndexer) GetCandidates(height uint64, offset, limit
 	return d, height, err
 }
 
+// Candidates returns all candidates of the snapshot at the epoch start height, or db.ErrNotExist if the epoch isn't
+// indexed
+func (cbi *CandidatesBucketsIndexer) Candidates(height uint64) (*iotextypes.CandidateListV2, error) {
+	ret, err := cbi.kvStore.Get(StakingCandidatesNamespace, byteutil.Uint64ToBytesBigEndian(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get candidates at height %d", height)
+	}
+	candidateList := &iotextypes.CandidateListV2{}
+	if err := proto.Unmarshal(ret, candidateList); err != nil {
+		return nil, err
+	}
+	return candidateList, nil
+}
+
 // PutBuckets puts vote buckets into indexer
 func (cbi *CandidatesBucketsIndexer) PutBuckets(height uint64, buckets *iotextypes.VoteBucketList) error {
 	bucketsBytes, err := proto.Marshal(buckets)
@@ -171,3 +185,17 @@ func (cbi *CandidatesBucketsIndexer) GetBuckets(height uint64, offset, limit uin
 	d, err := proto.Marshal(buckets)
 	return d, height, err
 }
+
+// Buckets returns all vote buckets of the snapshot at the epoch start height, or db.ErrNotExist if the epoch isn't
+// indexed
+func (cbi *CandidatesBucketsIndexer) Buckets(height uint64) (*iotextypes.VoteBucketList, error) {
+	ret, err := cbi.kvStore.Get(StakingBucketsNamespace, byteutil.Uint64ToBytesBigEndian(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get buckets at height %d", height)
+	}
+	buckets := &iotextypes.VoteBucketList{}
+	if err := proto.Unmarshal(ret, buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/stretchr/testify/require"
@@ -79,6 +81,13 @@ func TestCandidatesBucketsIndexer_PutGetCandidates(t *testing.T) {
 			require.Equal(r.Candidates[0].SelfStakeBucketIdx, tests[1].candidates.Candidates[0].SelfStakeBucketIdx)
 		}
 	}
+
+	all, err := cbi.Candidates(2)
+	require.NoError(err)
+	require.Len(all.Candidates, 1)
+	require.Equal("abc", all.Candidates[0].Name)
+	_, err = cbi.Candidates(3)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
 	require.NoError(cbi.Stop(ctx))
 }
 
@@ -140,5 +149,12 @@ func TestCandidatesBucketsIndexer_PutGetBuckets(t *testing.T) {
 			require.Equal(r.Buckets[0].Owner, tests[1].buckets.Buckets[0].Owner)
 		}
 	}
+
+	all, err := cbi.Buckets(2)
+	require.NoError(err)
+	require.Len(all.Buckets, 1)
+	require.Equal(uint64(1234), all.Buckets[0].Index)
+	_, err = cbi.Buckets(3)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
 	require.NoError(cbi.Stop(ctx))
 }
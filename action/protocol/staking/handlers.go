@@ -689,8 +689,10 @@ func (p *Protocol) handleCandidateRegister(ctx context.Context, act *action.Cand
 		return log, nil, errors.Wrapf(err, "failed to store account %s", actCtx.Caller.String())
 	}
 
-	// put registrationFee to reward pool
-	if _, err = p.depositGas(ctx, csm, registrationFee); err != nil {
+	// put registrationFee to reward pool, which is paid by the caller even if the gas fee of the action is sponsored
+	callerCtx := actCtx
+	callerCtx.GasPayer = nil
+	if _, err = p.depositGas(protocol.WithActionCtx(ctx, callerCtx), csm, registrationFee); err != nil {
 		return log, nil, errors.Wrap(err, "failed to deposit gas")
 	}
 
@@ -830,9 +832,14 @@ func fetchCaller(ctx context.Context, sr protocol.StateReader, amount *big.Int)
 			failureStatus: iotextypes.ReceiptStatus_Failure,
 		}
 	}
-	gasFee := big.NewInt(0).Mul(actionCtx.GasPrice, big.NewInt(0).SetUint64(actionCtx.IntrinsicGas))
+	required := new(big.Int).Set(amount)
+	// the gas fee of a sponsored action is deposited from the balance of the payer
+	if actionCtx.GasPayer == nil {
+		gasFee := big.NewInt(0).Mul(actionCtx.GasPrice, big.NewInt(0).SetUint64(actionCtx.IntrinsicGas))
+		required.Add(required, gasFee)
+	}
 	// check caller's balance
-	if gasFee.Add(amount, gasFee).Cmp(caller.Balance) == 1 {
+	if required.Cmp(caller.Balance) == 1 {
 		return nil, &handleError{
 			err:           errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String()),
 			failureStatus: iotextypes.ReceiptStatus_ErrNotEnoughBalance,
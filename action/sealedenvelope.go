@@ -1,28 +1,70 @@
 package action
 
 import (
+	"math/big"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/iotexproject/go-pkgs/crypto"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
 
+	"github.com/iotexproject/iotex-core/action/actionpb"
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/pkg/version"
+)
+
+const (
+	// actionExtensionField is the field of iotextypes.Action carrying the serialized actionpb.ActionExtension
+	actionExtensionField protowire.Number = 1000
+	// actionExtensionVersion is the version of actionpb.ActionExtension
+	actionExtensionVersion = 1
 )
 
 // SealedEnvelope is a signed action envelope.
 type SealedEnvelope struct {
 	Envelope
 
-	srcPubkey crypto.PublicKey
-	signature []byte
+	srcPubkey    crypto.PublicKey
+	signature    []byte
+	encoding     Encoding
+	evmNetworkID uint32
+	// the payer of the gas fee of a sponsored action, and its signature
+	payerPubkey    crypto.PublicKey
+	payerSignature []byte
 }
 
-// Hash returns the hash value of SealedEnvelope.
+// Hash returns the hash value of SealedEnvelope. The hash of an action signed as ethereum transaction is the hash of
+// the transaction, so that ethereum clients can look it up.
 func (sealed *SealedEnvelope) Hash() hash.Hash256 {
+	if sealed.encoding == EthereumRLPEncoding {
+		if h, err := sealed.ethTxHash(); err == nil {
+			return h
+		}
+		// the action cannot be encoded as ethereum transaction, and fails the verification of its signature
+	}
 	return hash.Hash256b(byteutil.Must(proto.Marshal(sealed.Proto())))
 }
 
+// Encoding returns the encoding of the action which the sender signs
+func (sealed *SealedEnvelope) Encoding() Encoding { return sealed.encoding }
+
+// EVMNetworkID returns the network id of the ethereum transaction, or 0 for an action of the IoTeX encoding
+func (sealed *SealedEnvelope) EVMNetworkID() uint32 { return sealed.evmNetworkID }
+
+// envelopeHash returns the hash of the envelope which the sender signs
+func (sealed *SealedEnvelope) envelopeHash() (hash.Hash256, error) {
+	if sealed.encoding != EthereumRLPEncoding {
+		return sealed.Envelope.Hash(), nil
+	}
+	tx, err := sealed.Envelope.ethTx()
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	return hash.BytesToHash256(sealed.ethSigner().Hash(tx).Bytes()), nil
+}
+
 // SrcPubkey returns the source public key
 func (sealed *SealedEnvelope) SrcPubkey() crypto.PublicKey { return sealed.srcPubkey }
 
@@ -33,8 +75,55 @@ func (sealed *SealedEnvelope) Signature() []byte {
 	return sig
 }
 
-// Proto converts it to it's proto scheme.
-func (sealed *SealedEnvelope) Proto() *iotextypes.Action {
+// IsSponsored returns whether the gas fee of the action is paid by a payer other than the sender
+func (sealed *SealedEnvelope) IsSponsored() bool { return sealed.payerPubkey != nil }
+
+// PayerPubkey returns the public key of the payer of the gas fee, or nil if the sender pays it
+func (sealed *SealedEnvelope) PayerPubkey() crypto.PublicKey { return sealed.payerPubkey }
+
+// PayerSignature returns the signature of the payer of the gas fee
+func (sealed *SealedEnvelope) PayerSignature() []byte {
+	sig := make([]byte, len(sealed.payerSignature))
+	copy(sig, sealed.payerSignature)
+	return sig
+}
+
+// GasFee returns the max gas fee of the action, which is the part of its cost paid by the payer of a sponsored action
+func (sealed *SealedEnvelope) GasFee() (*big.Int, error) {
+	gas := sealed.GasLimit()
+	if _, ok := sealed.payload.(*Execution); !ok {
+		intrinsicGas, err := sealed.IntrinsicGas()
+		if err != nil {
+			return nil, err
+		}
+		gas = intrinsicGas
+	}
+	return new(big.Int).Mul(sealed.GasPrice(), new(big.Int).SetUint64(gas)), nil
+}
+
+// SenderCost returns the part of the cost of the action paid by the sender, which is the whole cost unless the action
+// is sponsored
+func (sealed *SealedEnvelope) SenderCost() (*big.Int, error) {
+	cost, err := sealed.Cost()
+	if err != nil || !sealed.IsSponsored() {
+		return cost, err
+	}
+	fee, err := sealed.GasFee()
+	if err != nil {
+		return nil, err
+	}
+	if cost.Cmp(fee) < 0 {
+		return big.NewInt(0), nil
+	}
+	return cost.Sub(cost, fee), nil
+}
+
+// senderHash returns the hash of the action signed by the sender, which the payer of a sponsored action signs
+func (sealed *SealedEnvelope) senderHash() hash.Hash256 {
+	return hash.Hash256b(byteutil.Must(proto.Marshal(sealed.senderProto())))
+}
+
+func (sealed *SealedEnvelope) senderProto() *iotextypes.Action {
 	return &iotextypes.Action{
 		Core:         sealed.Envelope.Proto(),
 		SenderPubKey: sealed.srcPubkey.Bytes(),
@@ -42,6 +131,26 @@ func (sealed *SealedEnvelope) Proto() *iotextypes.Action {
 	}
 }
 
+// Proto converts it to it's proto scheme.
+func (sealed *SealedEnvelope) Proto() *iotextypes.Action {
+	pbAct := sealed.senderProto()
+	if sealed.IsSponsored() || sealed.encoding != IoTeXProtobufEncoding {
+		pbExt := &actionpb.ActionExtension{
+			Version:      actionExtensionVersion,
+			Encoding:     uint32(sealed.encoding),
+			EvmNetworkID: sealed.evmNetworkID,
+		}
+		if sealed.IsSponsored() {
+			pbExt.PayerPubKey = sealed.payerPubkey.Bytes()
+			pbExt.PayerSignature = sealed.payerSignature
+		}
+		ext := byteutil.Must(proto.Marshal(pbExt))
+		b := protowire.AppendTag(nil, actionExtensionField, protowire.BytesType)
+		pbAct.ProtoReflect().SetUnknown(protowire.AppendBytes(b, ext))
+	}
+	return pbAct
+}
+
 // LoadProto loads from proto scheme.
 func (sealed *SealedEnvelope) LoadProto(pbAct *iotextypes.Action) error {
 	if pbAct == nil {
@@ -62,7 +171,80 @@ func (sealed *SealedEnvelope) LoadProto(pbAct *iotextypes.Action) error {
 	if err := sealed.Envelope.LoadProto(pbAct.GetCore()); err != nil {
 		return err
 	}
+	if err := sealed.loadExtension(pbAct.ProtoReflect().GetUnknown()); err != nil {
+		return err
+	}
+	if err := sealed.validateEncoding(); err != nil {
+		return err
+	}
 
 	sealed.payload.SetEnvelopeContext(*sealed)
 	return nil
 }
+
+// loadExtension loads the extension from the unknown fields of the action proto
+func (sealed *SealedEnvelope) loadExtension(b []byte) error {
+	var loaded bool
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num != actionExtensionField || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		ext := &actionpb.ActionExtension{}
+		if err := proto.Unmarshal(v, ext); err != nil {
+			return errors.Wrap(err, "failed to unmarshal action extension")
+		}
+		if ext.GetVersion() != actionExtensionVersion {
+			return errors.Errorf("unknown action extension version %d", ext.GetVersion())
+		}
+		// the action proto is encoded again for its hash, so it has at most one extension, which isn't empty
+		if loaded || (len(ext.GetPayerPubKey()) == 0 && ext.GetEncoding() == uint32(IoTeXProtobufEncoding)) {
+			return errors.New("invalid action extension")
+		}
+		loaded = true
+		sealed.encoding = Encoding(ext.GetEncoding())
+		sealed.evmNetworkID = ext.GetEvmNetworkID()
+		if len(ext.GetPayerPubKey()) == 0 {
+			continue
+		}
+		payerPub, err := crypto.BytesToPublicKey(ext.GetPayerPubKey())
+		if err != nil {
+			return errors.Wrap(err, "invalid payer public key")
+		}
+		sealed.payerPubkey = payerPub
+		sealed.payerSignature = make([]byte, len(ext.GetPayerSignature()))
+		copy(sealed.payerSignature, ext.GetPayerSignature())
+	}
+	return nil
+}
+
+// validateEncoding validates the encoding fields of the loaded action
+func (sealed *SealedEnvelope) validateEncoding() error {
+	switch sealed.encoding {
+	case IoTeXProtobufEncoding:
+		if sealed.evmNetworkID != 0 {
+			return errors.New("evm network id of action of IoTeX encoding")
+		}
+	case EthereumRLPEncoding:
+		// the ethereum transaction doesn't carry the version, which is fixed so that the action hash is unique
+		if sealed.evmNetworkID == 0 || sealed.Envelope.version != version.ProtocolVersion || sealed.IsSponsored() {
+			return errors.New("invalid ethereum transaction")
+		}
+	default:
+		return errors.Errorf("unknown action encoding %d", sealed.encoding)
+	}
+	return nil
+}
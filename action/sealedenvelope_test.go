@@ -1,13 +1,17 @@
 package action
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/iotexproject/go-pkgs/crypto"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 
+	"github.com/iotexproject/iotex-core/action/actionpb"
 	"github.com/iotexproject/iotex-core/pkg/unit"
 	"github.com/iotexproject/iotex-core/test/identityset"
 )
@@ -91,3 +95,71 @@ func createSealedEnvelope() (SealedEnvelope, error) {
 	se.signature = signByte
 	return se, err
 }
+
+func TestSealedEnvelope_Sponsor(t *testing.T) {
+	req := require.New(t)
+	tsf, err := NewTransfer(1, big.NewInt(100), identityset.Address(1).String(), nil, 100000, big.NewInt(10))
+	req.NoError(err)
+	elp := (&EnvelopeBuilder{}).SetNonce(1).SetGasLimit(100000).SetGasPrice(big.NewInt(10)).SetAction(tsf).Build()
+	selp, err := Sign(elp, identityset.PrivateKey(27))
+	req.NoError(err)
+	req.False(selp.IsSponsored())
+	req.Nil(selp.PayerPubkey())
+	// an action without extension has no unknown field, so its hash is unchanged
+	req.Empty(selp.Proto().ProtoReflect().GetUnknown())
+	cost, err := selp.Cost()
+	req.NoError(err)
+	senderCost, err := selp.SenderCost()
+	req.NoError(err)
+	req.Equal(cost, senderCost)
+
+	sponsored, err := Sponsor(selp, identityset.PrivateKey(28))
+	req.NoError(err)
+	req.True(sponsored.IsSponsored())
+	req.Equal(identityset.PrivateKey(28).PublicKey(), sponsored.PayerPubkey())
+	req.NotEqual(selp.Hash(), sponsored.Hash())
+	req.NoError(VerifySignature(sponsored))
+	fee, err := sponsored.GasFee()
+	req.NoError(err)
+	req.Equal(big.NewInt(100000), fee)
+	senderCost, err = sponsored.SenderCost()
+	req.NoError(err)
+	req.Equal(big.NewInt(100), senderCost)
+
+	// the extension survives the serialization
+	b, err := proto.Marshal(sponsored.Proto())
+	req.NoError(err)
+	pb := &iotextypes.Action{}
+	req.NoError(proto.Unmarshal(b, pb))
+	loaded := SealedEnvelope{}
+	req.NoError(loaded.LoadProto(pb))
+	req.Equal(sponsored.Hash(), loaded.Hash())
+	req.Equal(sponsored.PayerPubkey(), loaded.PayerPubkey())
+	req.Equal(sponsored.PayerSignature(), loaded.PayerSignature())
+	req.NoError(VerifySignature(loaded))
+
+	// the payer signs the action signed by the sender
+	other, err := Sign(elp, identityset.PrivateKey(29))
+	req.NoError(err)
+	forged := other
+	forged.payerPubkey = sponsored.payerPubkey
+	forged.payerSignature = sponsored.payerSignature
+	req.Error(VerifySignature(forged))
+
+	// the extension of an unknown version is rejected
+	ext, err := proto.Marshal(&actionpb.ActionExtension{
+		Version:        actionExtensionVersion + 1,
+		PayerPubKey:    sponsored.payerPubkey.Bytes(),
+		PayerSignature: sponsored.payerSignature,
+	})
+	req.NoError(err)
+	pb = selp.Proto()
+	pb.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, actionExtensionField, protowire.BytesType), ext))
+	req.Error(loaded.LoadProto(pb))
+}
+
+func TestActionExtensionField(t *testing.T) {
+	// the field carrying the extension must not be defined by iotextypes.Action
+	fields := (&iotextypes.Action{}).ProtoReflect().Descriptor().Fields()
+	require.Nil(t, fields.ByNumber(actionExtensionField))
+}
@@ -43,60 +43,97 @@ type ActionIterator interface {
 	PopAccount()
 }
 
+// Option sets an option of the action iterator
+type Option func(*actionIterator)
+
+// WithPriority returns an option to take the actions of the prioritized senders first, before the actions of the
+// other senders regardless of their gas prices
+func WithPriority(prioritized func(sender string) bool) Option {
+	return func(ai *actionIterator) {
+		ai.prioritized = prioritized
+	}
+}
+
 type actionIterator struct {
 	accountActs map[string][]action.SealedEnvelope
-	heads       actionByPrice
+	prioritized func(string) bool
+	// priorityHeads are the head actions of the prioritized senders, which are taken before the other heads
+	priorityHeads actionByPrice
+	heads         actionByPrice
+	// last is the heap of the last action taken
+	last *actionByPrice
 }
 
 // NewActionIterator return a new action iterator
-func NewActionIterator(accountActs map[string][]action.SealedEnvelope) ActionIterator {
-	heads := make(actionByPrice, 0, len(accountActs))
+func NewActionIterator(accountActs map[string][]action.SealedEnvelope, opts ...Option) ActionIterator {
+	ai := &actionIterator{
+		accountActs: accountActs,
+		heads:       make(actionByPrice, 0, len(accountActs)),
+	}
+	for _, opt := range opts {
+		opt(ai)
+	}
 	for sender, accActs := range accountActs {
 		if len(accActs) == 0 {
 			continue
 		}
 
-		heads = append(heads, accActs[0])
+		if ai.prioritized != nil && ai.prioritized(sender) {
+			ai.priorityHeads = append(ai.priorityHeads, accActs[0])
+		} else {
+			ai.heads = append(ai.heads, accActs[0])
+		}
 		if len(accActs) > 1 {
 			accountActs[sender] = accActs[1:]
 		} else {
 			accountActs[sender] = []action.SealedEnvelope{}
 		}
 	}
-	heap.Init(&heads)
-	return &actionIterator{
-		accountActs: accountActs,
-		heads:       heads,
+	heap.Init(&ai.priorityHeads)
+	heap.Init(&ai.heads)
+	return ai
+}
+
+// top returns the heap of the next action
+func (ai *actionIterator) top() *actionByPrice {
+	if len(ai.priorityHeads) != 0 {
+		return &ai.priorityHeads
 	}
+	if len(ai.heads) != 0 {
+		return &ai.heads
+	}
+	return nil
 }
 
 // LoadNext load next action of account of top action
 func (ai *actionIterator) loadNextActionForTopAccount() {
-	sender := ai.heads[0].SrcPubkey()
+	heads := ai.last
+	sender := (*heads)[0].SrcPubkey()
 	callerAddr, _ := address.FromBytes(sender.Hash())
 	callerAddrStr := callerAddr.String()
 	if actions, ok := ai.accountActs[callerAddrStr]; ok && len(actions) > 0 {
-		ai.heads[0], ai.accountActs[callerAddrStr] = actions[0], actions[1:]
-		heap.Fix(&ai.heads, 0)
+		(*heads)[0], ai.accountActs[callerAddrStr] = actions[0], actions[1:]
+		heap.Fix(heads, 0)
 	} else {
-		heap.Pop(&ai.heads)
+		heap.Pop(heads)
 	}
 }
 
 // Next load next action of account of top action
 func (ai *actionIterator) Next() (action.SealedEnvelope, bool) {
-	if len(ai.heads) == 0 {
+	ai.last = ai.top()
+	if ai.last == nil {
 		return action.SealedEnvelope{}, false
 	}
 
-	headAction := ai.heads[0]
+	headAction := (*ai.last)[0]
 	ai.loadNextActionForTopAccount()
 	return headAction, true
 }
 
 // PopAccount will remove all actions related to this account
 func (ai *actionIterator) PopAccount() {
-	if len(ai.heads) != 0 {
-		heap.Pop(&ai.heads)
+	if ai.last != nil && len(*ai.last) != 0 {
+		heap.Pop(ai.last)
 	}
 }
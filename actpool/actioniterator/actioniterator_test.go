@@ -101,6 +101,66 @@ func TestActionIterator(t *testing.T) {
 	require.Equal(appliedActionList, []action.SealedEnvelope{selp3, selp1, selp2, selp4, selp5, selp6})
 }
 
+func TestActionIteratorPriority(t *testing.T) {
+	require := require.New(t)
+	signedTransfer := func(index int, nonce uint64, gasPrice int64) action.SealedEnvelope {
+		tsf, err := action.NewTransfer(nonce, big.NewInt(100), identityset.Address(27).String(), nil, uint64(0), big.NewInt(gasPrice))
+		require.NoError(err)
+		bd := &action.EnvelopeBuilder{}
+		elp := bd.SetNonce(nonce).
+			SetGasPrice(big.NewInt(gasPrice)).
+			SetAction(tsf).Build()
+		selp, err := action.Sign(elp, identityset.PrivateKey(index))
+		require.NoError(err)
+		return selp
+	}
+	a, b, c := identityset.Address(28).String(), identityset.Address(29).String(), identityset.Address(30).String()
+	selpA := signedTransfer(28, 1, 30)
+	selpB1 := signedTransfer(29, 1, 5)
+	selpB2 := signedTransfer(29, 2, 40)
+	selpC1 := signedTransfer(30, 1, 1)
+	selpC2 := signedTransfer(30, 2, 1)
+	newAccMap := func() map[string][]action.SealedEnvelope {
+		return map[string][]action.SealedEnvelope{
+			a: {selpA},
+			b: {selpB1, selpB2},
+			c: {selpC1, selpC2},
+		}
+	}
+
+	// the prioritized senders go first regardless of gas price
+	ai := NewActionIterator(newAccMap(), WithPriority(func(sender string) bool {
+		return sender == b || sender == c
+	}))
+	appliedActionList := make([]action.SealedEnvelope, 0)
+	for {
+		bestAction, ok := ai.Next()
+		if !ok {
+			break
+		}
+		appliedActionList = append(appliedActionList, bestAction)
+	}
+	require.Equal([]action.SealedEnvelope{selpB1, selpB2, selpC1, selpC2, selpA}, appliedActionList)
+
+	// popping a prioritized account keeps the other accounts
+	ai = NewActionIterator(newAccMap(), WithPriority(func(sender string) bool {
+		return sender == c
+	}))
+	bestAction, ok := ai.Next()
+	require.True(ok)
+	require.Equal(selpC1, bestAction)
+	ai.PopAccount()
+	appliedActionList = appliedActionList[:0]
+	for {
+		bestAction, ok := ai.Next()
+		if !ok {
+			break
+		}
+		appliedActionList = append(appliedActionList, bestAction)
+	}
+	require.Equal([]action.SealedEnvelope{selpA, selpB1, selpB2}, appliedActionList)
+}
+
 func BenchmarkLooping(b *testing.B) {
 	accMap := make(map[string][]action.SealedEnvelope)
 	for i := 0; i < b.N; i++ {
@@ -8,22 +8,29 @@ package actpool
 
 import (
 	"context"
+	"math/big"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/go-pkgs/cache"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/actpool/actioniterator"
+	"github.com/iotexproject/iotex-core/actpool/denylist"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/prometheustimer"
 )
@@ -33,19 +40,54 @@ var (
 		Name: "iotex_actpool_rejection_metrics",
 		Help: "actpool metrics.",
 	}, []string{"type"})
+	actpoolDropMtc = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "iotex_actpool_dropped_actions",
+		Help: "Number of actions dropped from actpool, by reason.",
+	}, []string{"reason"})
+	actpoolMinGasPriceMtc = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iotex_actpool_min_gas_price",
+		Help: "Minimal gas price currently accepted by actpool.",
+	})
 )
 
 func init() {
 	prometheus.MustRegister(actpoolMtc)
+	prometheus.MustRegister(actpoolDropMtc)
+	prometheus.MustRegister(actpoolMinGasPriceMtc)
 }
 
+// Reasons an action is dropped from the pool before being mined
+const (
+	// DropExpired is the reason of the actions which stay in the pool longer than the action expiry
+	DropExpired = "expired"
+	// DropEvicted is the reason of the actions evicted by an action of a higher gas price when the pool is full
+	DropEvicted = "evicted"
+	// DropReplaced is the reason of the actions replaced by an action of the same nonce and a higher gas price
+	DropReplaced = "replaced"
+	// DropInvalidated is the reason of the actions which become invalid, because the balance of the sender is not
+	// sufficient any more or the sender is deleted from the pool
+	DropInvalidated = "invalidated"
+)
+
+// droppedActsCacheSize is the number of dropped actions whose reasons are kept
+const droppedActsCacheSize = 16384
+
 // ActPool is the interface of actpool
 type ActPool interface {
 	action.SealedEnvelopeValidator
+	lifecycle.StartStopper
 	// Reset resets actpool state
 	Reset()
 	// PendingActionMap returns an action map with all accepted actions
 	PendingActionMap() map[string][]action.SealedEnvelope
+	// PendingActionIterator returns an iterator of the pending actions, in descending order of gas price with the
+	// actions of a sender in nonce order, and the ID of the snapshot of the pool it iterates. The iterator is not
+	// affected by the later changes of the pool.
+	PendingActionIterator(...actioniterator.Option) (actioniterator.ActionIterator, uint64)
+	// PendingActionDiff returns the actions added to and removed from the pool since a snapshot
+	PendingActionDiff(snapshot uint64) (*ActionDiff, error)
+	// Content returns the actions in pool of each sender
+	Content() map[string]AccountContent
 	// Add adds an action into the pool after passing validation
 	Add(ctx context.Context, act action.SealedEnvelope) error
 	// GetPendingNonce returns pending nonce in pool given an account address
@@ -54,6 +96,12 @@ type ActPool interface {
 	GetUnconfirmedActs(addr string) []action.SealedEnvelope
 	// GetActionByHash returns the pending action in pool given action's hash
 	GetActionByHash(hash hash.Hash256) (action.SealedEnvelope, error)
+	// GetDropReason returns the reason a recently dropped action is no longer in pool
+	GetDropReason(hash hash.Hash256) (string, error)
+	// IsLocal returns whether an address is a local account, whose actions are prioritized
+	IsLocal(addr string) bool
+	// MinGasPrice returns the minimal gas price currently accepted, which is raised when the pool is under pressure
+	MinGasPrice() *big.Int
 	// GetSize returns the act pool size
 	GetSize() uint64
 	// GetCapacity returns the act pool capacity
@@ -68,6 +116,25 @@ type ActPool interface {
 	ReceiveBlock(*block.Block) error
 
 	AddActionEnvelopeValidators(...action.SealedEnvelopeValidator)
+	// AddSubscriber registers a subscriber to be notified of newly accepted actions
+	AddSubscriber(ActionSubscriber)
+	// RemoveSubscriber unregisters a subscriber
+	RemoveSubscriber(ActionSubscriber)
+}
+
+// ActionSubscriber is notified when an action is accepted into the pool. It is called with the pool locked, so it must
+// not block or call back into the pool except to remove itself.
+type ActionSubscriber interface {
+	ReceiveAction(action.SealedEnvelope)
+}
+
+// AccountContent is the actions in pool of an account. The pending actions can be executed in nonce order, the queued
+// actions wait for the actions of missing nonces.
+type AccountContent struct {
+	// NextNonce is the nonce following the pending actions
+	NextNonce uint64
+	Pending   []action.SealedEnvelope
+	Queued    []action.SealedEnvelope
 }
 
 // SortedActions is a slice of actions that implements sort.Interface to sort by Value.
@@ -101,6 +168,24 @@ type actPool struct {
 	timerFactory              *prometheustimer.TimerFactory
 	enableExperimentalActions bool
 	senderBlackList           map[string]bool
+	localAccounts             map[string]bool
+	subMutex                  sync.RWMutex
+	subscribers               []ActionSubscriber
+	journal                   *actJournal
+	journalRotated            time.Time
+	droppedActs               *cache.ThreadSafeLruCache
+	baseGasPrice              *big.Int
+	minGasPrice               *big.Int
+	actSources                map[hash.Hash256]string
+	sourceActs                map[string]uint64
+	senderAdmissions          *slidingWindow
+	sourceAdmissions          *slidingWindow
+	denylist                  *denylist.Denylist
+	blockGasLimit             func(uint64) uint64
+	snapshot                  uint64
+	changes                   []poolChange
+	orphans                   *orphanBuffer
+	sponsored                 map[string]*big.Int // the gas fees of the sponsored actions in pool by payer
 }
 
 // NewActPool constructs a new actpool
@@ -113,14 +198,31 @@ func NewActPool(sf protocol.StateReader, cfg config.ActPool, opts ...Option) (Ac
 	for _, bannedSender := range cfg.BlackList {
 		senderBlackList[bannedSender] = true
 	}
+	localAccounts := make(map[string]bool)
+	for _, local := range cfg.LocalAccounts {
+		localAccounts[local] = true
+	}
 
 	ap := &actPool{
 		cfg:             cfg,
 		sf:              sf,
 		senderBlackList: senderBlackList,
+		localAccounts:   localAccounts,
 		accountActs:     make(map[string]ActQueue),
 		accountDesActs:  make(map[string]map[hash.Hash256]action.SealedEnvelope),
 		allActions:      make(map[hash.Hash256]action.SealedEnvelope),
+		droppedActs:     cache.NewThreadSafeLruCache(droppedActsCacheSize),
+		baseGasPrice:    cfg.MinGasPrice(),
+		actSources:      make(map[hash.Hash256]string),
+		sourceActs:      make(map[string]uint64),
+		sponsored:       make(map[string]*big.Int),
+	}
+	ap.senderAdmissions = newSlidingWindow(cfg.AdmissionWindow, cfg.MaxAdmissionsPerSender)
+	ap.sourceAdmissions = newSlidingWindow(cfg.AdmissionWindow, cfg.MaxAdmissionsPerSource)
+	ap.minGasPrice = ap.baseGasPrice
+	ap.orphans = newOrphanBuffer(cfg.MaxNumOrphans, cfg.MaxNumOrphansPerAcct, cfg.ActionExpiry)
+	if cfg.JournalPath != "" {
+		ap.journal = newActJournal(cfg.JournalPath)
 	}
 	for _, opt := range opts {
 		if err := opt(ap); err != nil {
@@ -140,10 +242,82 @@ func NewActPool(sf protocol.StateReader, cfg config.ActPool, opts ...Option) (Ac
 	return ap, nil
 }
 
+// Start reloads the actions of the journal, which are validated again, and rewrites the journal with the actions
+// accepted
+// ValidateAction checks the action is decodable and of enough gas limit, regardless of the state of the sender. The
+// signature is left to the verify workers of the dispatcher, which verify the actions in parallel.
+func ValidateAction(pb *iotextypes.Action) error {
+	var selp action.SealedEnvelope
+	if err := selp.LoadProto(pb); err != nil {
+		return err
+	}
+	if selp.SrcPubkey() == nil {
+		return errors.New("empty public key")
+	}
+	intrinsicGas, err := selp.IntrinsicGas()
+	if err != nil {
+		return err
+	}
+	if intrinsicGas > selp.GasLimit() {
+		return errors.Wrap(action.ErrInsufficientBalanceForGas, "insufficient gas")
+	}
+	return nil
+}
+
+func (ap *actPool) Start(ctx context.Context) error {
+	if ap.journal == nil {
+		return nil
+	}
+	loaded, dropped, err := ap.journal.load(func(act action.SealedEnvelope) error {
+		return ap.Add(ctx, act)
+	})
+	if err != nil {
+		return err
+	}
+	log.L().Info("Loaded actpool journal.", zap.Int("loaded", loaded), zap.Int("dropped", dropped))
+
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	return ap.rotateJournal()
+}
+
+// Stop writes the actions in pool to the journal
+func (ap *actPool) Stop(ctx context.Context) error {
+	if ap.journal == nil {
+		return nil
+	}
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	if err := ap.rotateJournal(); err != nil {
+		return err
+	}
+	return ap.journal.close()
+}
+
 func (ap *actPool) AddActionEnvelopeValidators(fs ...action.SealedEnvelopeValidator) {
 	ap.actionEnvelopeValidators = append(ap.actionEnvelopeValidators, fs...)
 }
 
+// AddSubscriber registers a subscriber to be notified of newly accepted actions
+func (ap *actPool) AddSubscriber(s ActionSubscriber) {
+	ap.subMutex.Lock()
+	defer ap.subMutex.Unlock()
+	ap.subscribers = append(ap.subscribers, s)
+}
+
+// RemoveSubscriber unregisters a subscriber
+func (ap *actPool) RemoveSubscriber(s ActionSubscriber) {
+	ap.subMutex.Lock()
+	defer ap.subMutex.Unlock()
+	subscribers := make([]ActionSubscriber, 0, len(ap.subscribers))
+	for _, sub := range ap.subscribers {
+		if sub != s {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	ap.subscribers = subscribers
+}
+
 // Reset resets actpool state
 // Step I: remove all the actions in actpool that have already been committed to block
 // Step II: update pending balance of each account if it still exists in pool
@@ -164,10 +338,18 @@ func (ap *actPool) ReceiveBlock(*block.Block) error {
 	defer ap.mutex.Unlock()
 
 	ap.reset()
+	ap.promoteOrphans()
+	ap.adjustMinGasPrice(true)
+	// Drop the mined actions from the journal once in a while
+	if ap.journal != nil && time.Since(ap.journalRotated) >= ap.cfg.JournalRotateInterval {
+		if err := ap.rotateJournal(); err != nil {
+			log.L().Error("Failed to rotate actpool journal.", zap.Error(err))
+		}
+	}
 	return nil
 }
 
-// PendingActionIterator returns an action interator with all accepted actions
+// PendingActionMap returns an action map with all accepted actions
 func (ap *actPool) PendingActionMap() map[string][]action.SealedEnvelope {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
@@ -182,32 +364,62 @@ func (ap *actPool) PendingActionMap() map[string][]action.SealedEnvelope {
 	return actionMap
 }
 
-func (ap *actPool) Add(ctx context.Context, act action.SealedEnvelope) error {
+func (ap *actPool) Content() map[string]AccountContent {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
 
-	// Reject action if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		actpoolMtc.WithLabelValues("overMaxNumActsPerPool").Inc()
-		return errors.Wrap(action.ErrActPool, "insufficient space for action")
+	// Remove the actions that are already timeout
+	ap.reset()
+
+	content := make(map[string]AccountContent, len(ap.accountActs))
+	for from, queue := range ap.accountActs {
+		c := AccountContent{Pending: queue.PendingActs()}
+		if len(c.Pending) > 0 {
+			c.NextNonce = c.Pending[len(c.Pending)-1].Nonce() + 1
+		} else {
+			confirmedState, err := accountutil.AccountState(ap.sf, from)
+			if err != nil {
+				log.L().Error("Error when getting the nonce", zap.String("address", from), zap.Error(err))
+				continue
+			}
+			c.NextNonce = confirmedState.Nonce + 1
+		}
+		c.Queued = queue.QueuedActs()
+		content[from] = c
 	}
+	return content
+}
+
+func (ap *actPool) Add(ctx context.Context, act action.SealedEnvelope) error {
 	intrinsicGas, err := act.IntrinsicGas()
 	if err != nil {
 		actpoolMtc.WithLabelValues("failedGetIntrinsicGas").Inc()
 		return errors.Wrap(err, "failed to get action's intrinsic gas")
 	}
-	if ap.gasInPool+intrinsicGas > ap.cfg.MaxGasLimitPerPool {
-		actpoolMtc.WithLabelValues("overMaxGasLimitPerPool").Inc()
-		return errors.Wrap(action.ErrActPool, "insufficient gas space for action")
-	}
 	hash := act.Hash()
+	// Verify the signature before taking the lock, so that the actions of concurrent callers are verified in parallel
+	if !protocol.IsVerifiedAction(ctx, hash) {
+		if err := action.VerifySignature(act); err != nil {
+			actpoolMtc.WithLabelValues("invalidSignature").Inc()
+			return errors.Wrap(err, "failed to verify action signature")
+		}
+		ctx = protocol.WithVerifiedAction(ctx, hash)
+	}
+
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
 	// Reject action if it already exists in pool
 	if _, exist := ap.allActions[hash]; exist {
 		actpoolMtc.WithLabelValues("existedAction").Inc()
 		return errors.Errorf("reject existed action: %x", hash)
 	}
-	// Reject action if the gas price is lower than the threshold
-	if act.GasPrice().Cmp(ap.cfg.MinGasPrice()) < 0 {
+	caller, err := address.FromBytes(act.SrcPubkey().Hash())
+	if err != nil {
+		return err
+	}
+	// Reject action if the gas price is lower than the threshold, unless the sender is a local account
+	if !ap.localAccounts[caller.String()] && act.GasPrice().Cmp(ap.minGasPrice) < 0 {
 		actpoolMtc.WithLabelValues("gasPriceLower").Inc()
 		return errors.Wrapf(
 			action.ErrGasPrice,
@@ -216,15 +428,64 @@ func (ap *actPool) Add(ctx context.Context, act action.SealedEnvelope) error {
 			act.GasPrice(),
 		)
 	}
+	if ap.blockGasLimit != nil {
+		height, err := ap.sf.Height()
+		if err != nil {
+			return errors.Wrap(err, "failed to get height")
+		}
+		if limit := ap.blockGasLimit(height + 1); act.GasLimit() > limit {
+			actpoolMtc.WithLabelValues("gasLimitExceedsBlock").Inc()
+			return errors.Wrapf(
+				action.ErrHitGasLimit,
+				"reject the action %x whose gas limit %d exceeds the block gas limit %d",
+				hash,
+				act.GasLimit(),
+				limit,
+			)
+		}
+	}
+	// The denylist applies to the actions to admit only, the blocks are not validated against it
+	if ap.denylist != nil {
+		if err := ap.denylist.Check(act); err != nil {
+			actpoolMtc.WithLabelValues("denied").Inc()
+			return err
+		}
+	}
+	// Limit the actions received from peers and api clients, unless the sender is a local account
+	source, hasSource := GetActionSource(ctx)
+	if hasSource && !ap.localAccounts[caller.String()] {
+		if err := ap.admit(caller.String(), source); err != nil {
+			return err
+		}
+	}
 	if err := ap.validate(ctx, act); err != nil {
 		return err
 	}
+	// Keep the action whose nonce is too far ahead until the nonce of the sender catches up, instead of rejecting it
+	if ap.orphans != nil {
+		if orphaned, err := ap.addOrphan(caller.String(), act); orphaned || err != nil {
+			return err
+		}
+	}
 
-	caller, err := address.FromBytes(act.SrcPubkey().Hash())
-	if err != nil {
+	// Evict the cheaper actions if pool space is full
+	if err := ap.makeRoom(caller.String(), act, intrinsicGas); err != nil {
+		return err
+	}
+	if err := ap.enqueueAction(caller.String(), act, hash, act.Nonce()); err != nil {
 		return err
 	}
-	return ap.enqueueAction(caller.String(), act, hash, act.Nonce())
+	if hasSource {
+		ap.actSources[hash] = source
+		ap.sourceActs[source]++
+	}
+	ap.adjustMinGasPrice(false)
+	if ap.journal != nil {
+		if err := ap.journal.insert(act); err != nil {
+			log.L().Error("Failed to journal action.", log.Hex("hash", hash[:]), zap.Error(err))
+		}
+	}
+	return nil
 }
 
 // GetPendingNonce returns pending nonce in pool or confirmed nonce given an account address
@@ -275,6 +536,20 @@ func (ap *actPool) GetActionByHash(hash hash.Hash256) (action.SealedEnvelope, er
 	return act, nil
 }
 
+// IsLocal returns whether an address is a local account, whose actions are prioritized
+func (ap *actPool) IsLocal(addr string) bool {
+	return ap.localAccounts[addr]
+}
+
+// GetDropReason returns the reason a recently dropped action is no longer in pool
+func (ap *actPool) GetDropReason(hash hash.Hash256) (string, error) {
+	reason, ok := ap.droppedActs.Get(hash)
+	if !ok {
+		return "", errors.Wrapf(action.ErrNotFound, "action hash %x is not dropped recently", hash)
+	}
+	return reason.(string), nil
+}
+
 // GetSize returns the act pool size
 func (ap *actPool) GetSize() uint64 {
 	ap.mutex.RLock()
@@ -301,6 +576,13 @@ func (ap *actPool) GetGasCapacity() uint64 {
 	return ap.cfg.MaxGasLimitPerPool
 }
 
+// MinGasPrice returns the minimal gas price currently accepted
+func (ap *actPool) MinGasPrice() *big.Int {
+	ap.mutex.RLock()
+	defer ap.mutex.RUnlock()
+	return new(big.Int).Set(ap.minGasPrice)
+}
+
 func (ap *actPool) Validate(ctx context.Context, selp action.SealedEnvelope) error {
 	ap.mutex.RLock()
 	defer ap.mutex.RUnlock()
@@ -311,7 +593,7 @@ func (ap *actPool) DeleteAction(caller address.Address) {
 	ap.mutex.RLock()
 	defer ap.mutex.RUnlock()
 	pendingActs := ap.accountActs[caller.String()].AllActs()
-	ap.removeInvalidActs(pendingActs)
+	ap.dropActs(pendingActs, DropInvalidated)
 	delete(ap.accountActs, caller.String())
 }
 
@@ -362,12 +644,11 @@ func (ap *actPool) enqueueAction(sender string, act action.SealedEnvelope, actHa
 			actpoolMtc.WithLabelValues("failedToGetBalance").Inc()
 			return errors.Wrapf(err, "failed to get sender's balance for action %x", actHash)
 		}
-		queue.SetPendingBalance(state.Balance)
+		queue.SetPendingBalance(ap.unsponsoredBalance(sender, state.Balance))
 	}
-	if queue.Overlaps(act) {
-		// Nonce already exists
-		actpoolMtc.WithLabelValues("nonceUsed").Inc()
-		return errors.Wrapf(action.ErrNonce, "duplicate nonce for action %x", actHash)
+	if old, exist := queue.ActByNonce(actNonce); exist {
+		// Nonce already exists, the action may replace the existing one with a higher gas price
+		return ap.replaceAction(sender, queue, old, act, actHash)
 	}
 
 	if actNonce-confirmedNonce-1 >= ap.cfg.MaxNumActsPerAcct {
@@ -380,7 +661,7 @@ func (ap *actPool) enqueueAction(sender string, act action.SealedEnvelope, actHa
 		return errors.Wrapf(action.ErrNonce, "nonce too large ,actNonce : %x", actNonce)
 	}
 
-	cost, err := act.Cost()
+	cost, err := act.SenderCost()
 	if err != nil {
 		actpoolMtc.WithLabelValues("failedToGetCost").Inc()
 		return errors.Wrapf(err, "failed to get cost of action %x", actHash)
@@ -397,12 +678,144 @@ func (ap *actPool) enqueueAction(sender string, act action.SealedEnvelope, actHa
 			sender,
 		)
 	}
+	if err := ap.checkGasPayer(act, actHash, nil); err != nil {
+		return err
+	}
 
 	if err := queue.Put(act); err != nil {
 		actpoolMtc.WithLabelValues("failedPutActQueue").Inc()
 		return errors.Wrapf(err, "cannot put action %x into ActQueue", actHash)
 	}
+	ap.addToPool(sender, act, actHash)
+	// If the pending nonce equals this nonce, update queue, which promotes the queued actions following it
+	nonce := queue.PendingNonce()
+	if actNonce == nonce {
+		ap.updateAccount(sender)
+	}
+	ap.notifySubscribers(act)
+	return nil
+}
+
+// replaceAction replaces the action of the same nonce in the queue of the sender, if the gas price of the new action
+// exceeds the gas price of the replaced one by at least the configured percentage
+func (ap *actPool) replaceAction(sender string, queue ActQueue, old, act action.SealedEnvelope, actHash hash.Hash256) error {
+	minGasPrice := new(big.Int).Mul(old.GasPrice(), new(big.Int).SetUint64(100+ap.cfg.ReplaceGasPriceBump))
+	minGasPrice.Div(minGasPrice, big.NewInt(100))
+	if act.GasPrice().Cmp(old.GasPrice()) <= 0 || act.GasPrice().Cmp(minGasPrice) < 0 {
+		actpoolMtc.WithLabelValues("replacementUnderpriced").Inc()
+		return errors.Wrapf(
+			action.ErrNonce,
+			"duplicate nonce for action %x, a replacement needs a gas price of at least %s and above %s",
+			actHash,
+			minGasPrice,
+			old.GasPrice(),
+		)
+	}
+	cost, err := act.SenderCost()
+	if err != nil {
+		actpoolMtc.WithLabelValues("failedToGetCost").Inc()
+		return errors.Wrapf(err, "failed to get cost of action %x", actHash)
+	}
+	// The cost of a pending action is already deducted from the pending balance
+	balance := new(big.Int).Set(queue.PendingBalance())
+	if old.Nonce() < queue.PendingNonce() {
+		oldCost, err := old.SenderCost()
+		if err != nil {
+			actpoolMtc.WithLabelValues("failedToGetCost").Inc()
+			return errors.Wrapf(err, "failed to get cost of action %x", old.Hash())
+		}
+		balance.Add(balance, oldCost)
+	}
+	if balance.Cmp(cost) < 0 {
+		actpoolMtc.WithLabelValues("insufficientBalance").Inc()
+		return errors.Wrapf(
+			action.ErrBalance,
+			"insufficient balance for action %x, cost = %s, pending balance = %s, sender = %s",
+			actHash,
+			cost.String(),
+			balance.String(),
+			sender,
+		)
+	}
+	if err := ap.checkGasPayer(act, actHash, &old); err != nil {
+		return err
+	}
+	if _, err := queue.Replace(act); err != nil {
+		actpoolMtc.WithLabelValues("failedPutActQueue").Inc()
+		return errors.Wrapf(err, "cannot replace action %x in ActQueue", old.Hash())
+	}
+	ap.dropActs([]action.SealedEnvelope{old}, DropReplaced)
+	ap.addToPool(sender, act, actHash)
+	// The pending balance is recomputed with the cost of the new action
+	if err := ap.resetAccount(sender, queue); err != nil {
+		return err
+	}
+	actpoolMtc.WithLabelValues("replaced").Inc()
+	ap.notifySubscribers(act)
+	return nil
+}
+
+// makeRoom evicts the actions of the lowest gas prices until the pool has space for the action. Only the last action
+// of the other senders, whose gas price is lower than the gas price of the action, can be evicted. The actions of the
+// local accounts are never evicted.
+func (ap *actPool) makeRoom(sender string, act action.SealedEnvelope, intrinsicGas uint64) error {
+	if queue, ok := ap.accountActs[sender]; ok && queue.Overlaps(act) {
+		// A replacement takes the space of the action it replaces
+		return nil
+	}
+	for {
+		label, reason := "", ""
+		switch {
+		case uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool:
+			label, reason = "overMaxNumActsPerPool", "insufficient space for action"
+		case ap.gasInPool+intrinsicGas > ap.cfg.MaxGasLimitPerPool:
+			label, reason = "overMaxGasLimitPerPool", "insufficient gas space for action"
+		default:
+			return nil
+		}
+		from, ok := ap.cheapestLastAction(sender, act.GasPrice())
+		if !ok {
+			actpoolMtc.WithLabelValues(label).Inc()
+			return errors.Wrap(action.ErrActPool, reason)
+		}
+		queue := ap.accountActs[from]
+		evicted, _ := queue.PopLast()
+		ap.dropActs([]action.SealedEnvelope{evicted}, DropEvicted)
+		if queue.Empty() {
+			delete(ap.accountActs, from)
+		} else if evicted.Nonce() < queue.PendingNonce() {
+			if err := ap.resetAccount(from, queue); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// cheapestLastAction returns the sender whose last action has the lowest gas price, which is lower than gasPrice
+func (ap *actPool) cheapestLastAction(exclude string, gasPrice *big.Int) (string, bool) {
+	var (
+		cheapest string
+		lowest   = gasPrice
+	)
+	for from, queue := range ap.accountActs {
+		if from == exclude || ap.localAccounts[from] {
+			continue
+		}
+		acts := queue.AllActs()
+		if len(acts) == 0 {
+			continue
+		}
+		if price := acts[len(acts)-1].GasPrice(); price.Cmp(lowest) < 0 {
+			cheapest, lowest = from, price
+		}
+	}
+	return cheapest, cheapest != ""
+}
+
+// addToPool indexes an action put into the queue of its sender
+func (ap *actPool) addToPool(sender string, act action.SealedEnvelope, actHash hash.Hash256) {
 	ap.allActions[actHash] = act
+	ap.recordChange(actHash, act, true)
 
 	//add actions to destination map
 	desAddress, ok := act.Destination()
@@ -416,12 +829,16 @@ func (ap *actPool) enqueueAction(sender string, act action.SealedEnvelope, actHa
 
 	intrinsicGas, _ := act.IntrinsicGas()
 	ap.gasInPool += intrinsicGas
-	// If the pending nonce equals this nonce, update queue
-	nonce := queue.PendingNonce()
-	if actNonce == nonce {
-		ap.updateAccount(sender)
+	ap.updateSponsored(act, true)
+}
+
+func (ap *actPool) notifySubscribers(act action.SealedEnvelope) {
+	ap.subMutex.RLock()
+	subscribers := ap.subscribers
+	ap.subMutex.RUnlock()
+	for _, s := range subscribers {
+		s.ReceiveAction(act)
 	}
-	return nil
 }
 
 // removeConfirmedActs removes processed (committed to block) actions from pool
@@ -450,13 +867,110 @@ func (ap *actPool) removeInvalidActs(acts []action.SealedEnvelope) {
 		hash := act.Hash()
 		log.L().Debug("Removed invalidated action.", log.Hex("hash", hash[:]))
 		delete(ap.allActions, hash)
+		ap.recordChange(hash, act, false)
+		if source, ok := ap.actSources[hash]; ok {
+			delete(ap.actSources, hash)
+			if ap.sourceActs[source]--; ap.sourceActs[source] == 0 {
+				delete(ap.sourceActs, source)
+			}
+		}
 		intrinsicGas, _ := act.IntrinsicGas()
 		ap.subGasFromPool(intrinsicGas)
+		ap.updateSponsored(act, false)
 		//del actions in destination map
 		ap.deleteAccountDestinationActions(act)
 	}
 }
 
+// addOrphan keeps the action as an orphan if its nonce is beyond the range of the account queue, but within the
+// orphan nonce gap. It returns whether the action is an orphan.
+func (ap *actPool) addOrphan(sender string, act action.SealedEnvelope) (bool, error) {
+	confirmedState, err := accountutil.AccountState(ap.sf, sender)
+	if err != nil {
+		actpoolMtc.WithLabelValues("failedToGetNonce").Inc()
+		return false, errors.Wrapf(err, "failed to get sender's nonce for action %x", act.Hash())
+	}
+	maxNonce := confirmedState.Nonce + ap.cfg.MaxNumActsPerAcct
+	if act.Nonce() <= maxNonce || act.Nonce() > maxNonce+ap.cfg.MaxOrphanNonceGap {
+		return false, nil
+	}
+	if err := ap.orphans.put(sender, act, time.Now()); err != nil {
+		actpoolMtc.WithLabelValues("orphanRejected").Inc()
+		return true, err
+	}
+	actpoolMtc.WithLabelValues("orphaned").Inc()
+	return true, nil
+}
+
+// promoteOrphans puts the orphans into the pool once the nonces of their senders catch up, and drops the expired ones
+func (ap *actPool) promoteOrphans() {
+	if ap.orphans == nil {
+		return
+	}
+	now := time.Now()
+	for _, sender := range ap.orphans.senders() {
+		confirmedState, err := accountutil.AccountState(ap.sf, sender)
+		if err != nil {
+			log.L().Error("Error when getting the nonce of orphan actions.", zap.String("sender", sender), zap.Error(err))
+			continue
+		}
+		acts, expired := ap.orphans.take(sender, confirmedState.Nonce+ap.cfg.MaxNumActsPerAcct, now)
+		ap.dropOrphans(expired, DropExpired)
+		for _, act := range acts {
+			if err := ap.promoteOrphan(sender, act, confirmedState.Nonce); err != nil {
+				h := act.Hash()
+				log.L().Debug("Dropped orphan action.", log.Hex("hash", h[:]), zap.Error(err))
+				ap.dropOrphans([]action.SealedEnvelope{act}, DropInvalidated)
+				continue
+			}
+			actpoolMtc.WithLabelValues("orphanPromoted").Inc()
+		}
+	}
+}
+
+func (ap *actPool) promoteOrphan(sender string, act action.SealedEnvelope, confirmedNonce uint64) error {
+	if act.Nonce() <= confirmedNonce {
+		return errors.Wrap(action.ErrNonce, "nonce is too low")
+	}
+	intrinsicGas, err := act.IntrinsicGas()
+	if err != nil {
+		return err
+	}
+	hash := act.Hash()
+	if err := ap.makeRoom(sender, act, intrinsicGas); err != nil {
+		return err
+	}
+	if err := ap.enqueueAction(sender, act, hash, act.Nonce()); err != nil {
+		return err
+	}
+	if ap.journal != nil {
+		if err := ap.journal.insert(act); err != nil {
+			log.L().Error("Failed to journal action.", log.Hex("hash", hash[:]), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// dropOrphans records the reason the orphans are dropped
+func (ap *actPool) dropOrphans(acts []action.SealedEnvelope, reason string) {
+	for _, act := range acts {
+		ap.droppedActs.Add(act.Hash(), reason)
+	}
+	actpoolDropMtc.WithLabelValues(reason).Add(float64(len(acts)))
+}
+
+// dropActs removes the actions dropped before being mined from pool, and records the reason
+func (ap *actPool) dropActs(acts []action.SealedEnvelope, reason string) {
+	if len(acts) == 0 {
+		return
+	}
+	for _, act := range acts {
+		ap.droppedActs.Add(act.Hash(), reason)
+	}
+	actpoolDropMtc.WithLabelValues(reason).Add(float64(len(acts)))
+	ap.removeInvalidActs(acts)
+}
+
 // deleteAccountDestinationActions just for destination map
 func (ap *actPool) deleteAccountDestinationActions(acts ...action.SealedEnvelope) {
 	for _, act := range acts {
@@ -470,13 +984,11 @@ func (ap *actPool) deleteAccountDestinationActions(acts ...action.SealedEnvelope
 	}
 }
 
-// updateAccount updates queue's status and remove invalidated actions from pool if necessary
+// updateAccount updates queue's status and remove expired and invalidated actions from pool if necessary
 func (ap *actPool) updateAccount(sender string) {
 	queue := ap.accountActs[sender]
-	acts := queue.UpdateQueue(queue.PendingNonce())
-	if len(acts) > 0 {
-		ap.removeInvalidActs(acts)
-	}
+	ap.dropActs(queue.CleanTimeout(), DropExpired)
+	ap.dropActs(queue.UpdateQueue(queue.PendingNonce()), DropInvalidated)
 	// Delete the queue entry if it becomes empty
 	if queue.Empty() {
 		delete(ap.accountActs, sender)
@@ -490,20 +1002,211 @@ func (ap *actPool) reset() {
 	// Remove confirmed actions in actpool
 	ap.removeConfirmedActs()
 	for from, queue := range ap.accountActs {
-		// Reset pending balance for each account
-		state, err := accountutil.AccountState(ap.sf, from)
-		if err != nil {
+		if err := ap.resetAccount(from, queue); err != nil {
 			log.L().Error("Error when resetting actpool state.", zap.Error(err))
 			return
 		}
-		queue.SetPendingBalance(state.Balance)
+	}
+}
 
-		// Reset pending nonce and remove invalid actions for each account
-		confirmedNonce := state.Nonce
-		pendingNonce := confirmedNonce + 1
-		queue.SetPendingNonce(pendingNonce)
-		ap.updateAccount(from)
+// resetAccount resets the pending balance and nonce of an account from its confirmed state, and removes the actions
+// which become invalid
+func (ap *actPool) resetAccount(from string, queue ActQueue) error {
+	// Reset pending balance for each account
+	state, err := accountutil.AccountState(ap.sf, from)
+	if err != nil {
+		return err
 	}
+	queue.SetPendingBalance(ap.unsponsoredBalance(from, state.Balance))
+
+	// Reset pending nonce and remove invalid actions for each account
+	confirmedNonce := state.Nonce
+	pendingNonce := confirmedNonce + 1
+	queue.SetPendingNonce(pendingNonce)
+	ap.updateAccount(from)
+	return nil
+}
+
+// checkGasPayer checks the payer of a sponsored action can pay its gas fee, besides the gas fees of the other actions it
+// sponsors and the costs of its own pending actions. The gas fee of the replaced action is released if the same payer
+// sponsors it.
+func (ap *actPool) checkGasPayer(act action.SealedEnvelope, actHash hash.Hash256, replaced *action.SealedEnvelope) error {
+	if !act.IsSponsored() {
+		return nil
+	}
+	payer, fee, err := sponsorship(act)
+	if err != nil {
+		actpoolMtc.WithLabelValues("failedToGetCost").Inc()
+		return errors.Wrapf(err, "failed to get gas fee of action %x", actHash)
+	}
+	balance, err := ap.payerBalance(payer)
+	if err != nil {
+		actpoolMtc.WithLabelValues("failedToGetBalance").Inc()
+		return errors.Wrapf(err, "failed to get payer's balance for action %x", actHash)
+	}
+	if replaced != nil && replaced.IsSponsored() {
+		oldPayer, oldFee, err := sponsorship(*replaced)
+		if err != nil {
+			actpoolMtc.WithLabelValues("failedToGetCost").Inc()
+			return errors.Wrapf(err, "failed to get gas fee of action %x", replaced.Hash())
+		}
+		if oldPayer == payer {
+			balance = new(big.Int).Add(balance, oldFee)
+		}
+	}
+	if balance.Cmp(fee) < 0 {
+		actpoolMtc.WithLabelValues("insufficientPayerBalance").Inc()
+		return errors.Wrapf(
+			action.ErrInsufficientBalanceForGas,
+			"insufficient balance for gas fee of action %x, fee = %s, available balance = %s, payer = %s",
+			actHash,
+			fee.String(),
+			balance.String(),
+			payer,
+		)
+	}
+	return nil
+}
+
+// payerBalance returns the balance left to the gas fees the payer sponsors, which is the pending balance of its own
+// queue, or its confirmed balance less the gas fees of the actions in pool it sponsors
+func (ap *actPool) payerBalance(payer string) (*big.Int, error) {
+	if queue, ok := ap.accountActs[payer]; ok {
+		return queue.PendingBalance(), nil
+	}
+	state, err := accountutil.AccountState(ap.sf, payer)
+	if err != nil {
+		return nil, err
+	}
+	return ap.unsponsoredBalance(payer, state.Balance), nil
+}
+
+// unsponsoredBalance returns the balance of the account less the gas fees of the actions in pool it sponsors
+func (ap *actPool) unsponsoredBalance(addr string, balance *big.Int) *big.Int {
+	if fees, ok := ap.sponsored[addr]; ok {
+		return new(big.Int).Sub(balance, fees)
+	}
+	return balance
+}
+
+// updateSponsored adds, or removes, the gas fee of a sponsored action in pool to the gas fees its payer sponsors, which
+// are set aside from the pending balance of the payer's own queue
+func (ap *actPool) updateSponsored(act action.SealedEnvelope, added bool) {
+	if !act.IsSponsored() {
+		return
+	}
+	payer, fee, err := sponsorship(act)
+	if err != nil {
+		return
+	}
+	if !added {
+		fee.Neg(fee)
+	}
+	fees := new(big.Int).Set(fee)
+	if prev, ok := ap.sponsored[payer]; ok {
+		fees.Add(fees, prev)
+	}
+	if fees.Sign() > 0 {
+		ap.sponsored[payer] = fees
+	} else {
+		delete(ap.sponsored, payer)
+	}
+	if queue, ok := ap.accountActs[payer]; ok {
+		queue.SetPendingBalance(new(big.Int).Sub(queue.PendingBalance(), fee))
+	}
+}
+
+// sponsorship returns the payer and the gas fee of a sponsored action
+func sponsorship(act action.SealedEnvelope) (string, *big.Int, error) {
+	payer, err := address.FromBytes(act.PayerPubkey().Hash())
+	if err != nil {
+		return "", nil, err
+	}
+	fee, err := act.GasFee()
+	if err != nil {
+		return "", nil, err
+	}
+	return payer.String(), fee, nil
+}
+
+// admit checks the actions of the sender, and of the source, against their admission limits
+func (ap *actPool) admit(sender, source string) error {
+	if ap.cfg.MaxNumActsPerSource > 0 && ap.sourceActs[source] >= ap.cfg.MaxNumActsPerSource {
+		actpoolMtc.WithLabelValues("sourceFull").Inc()
+		return errors.Wrapf(action.ErrActPool, "too many actions in pool from %s", source)
+	}
+	now := time.Now()
+	if !ap.senderAdmissions.allow(sender, now) {
+		actpoolMtc.WithLabelValues("senderRateLimited").Inc()
+		return errors.Wrapf(action.ErrActPool, "admission rate limit of sender %s exceeded", sender)
+	}
+	if !ap.sourceAdmissions.allow(source, now) {
+		actpoolMtc.WithLabelValues("sourceRateLimited").Inc()
+		return errors.Wrapf(action.ErrActPool, "admission rate limit of %s exceeded", source)
+	}
+	return nil
+}
+
+// utilization returns the percentage of the pool capacity used, in actions or in gas, whichever is higher
+func (ap *actPool) utilization() uint64 {
+	var utilization uint64
+	if ap.cfg.MaxNumActsPerPool > 0 {
+		utilization = uint64(len(ap.allActions)) * 100 / ap.cfg.MaxNumActsPerPool
+	}
+	if ap.cfg.MaxGasLimitPerPool > 0 {
+		if gas := ap.gasInPool * 100 / ap.cfg.MaxGasLimitPerPool; gas > utilization {
+			utilization = gas
+		}
+	}
+	return utilization
+}
+
+// adjustMinGasPrice raises the minimal gas price at once to the floor of the highest gas price step the utilization
+// crosses. If decay is set, a minimal gas price above the floor falls back towards it by the configured percentage.
+func (ap *actPool) adjustMinGasPrice(decay bool) {
+	utilization := ap.utilization()
+	multiplier := uint64(100)
+	for _, step := range ap.cfg.GasPriceSteps {
+		if utilization < step.Utilization {
+			break
+		}
+		multiplier = step.Multiplier
+	}
+	floor := new(big.Int).Mul(ap.baseGasPrice, new(big.Int).SetUint64(multiplier))
+	floor.Div(floor, big.NewInt(100))
+	switch {
+	case ap.minGasPrice.Cmp(floor) <= 0:
+		ap.minGasPrice = floor
+	case decay:
+		fall := new(big.Int).Sub(ap.minGasPrice, floor)
+		fall.Mul(fall, new(big.Int).SetUint64(ap.cfg.GasPriceDecay)).Div(fall, big.NewInt(100))
+		if fall.Sign() == 0 {
+			// the remaining raise is too small to decay by percentage
+			ap.minGasPrice = floor
+		} else {
+			ap.minGasPrice = new(big.Int).Sub(ap.minGasPrice, fall)
+		}
+	}
+	price, _ := new(big.Float).SetInt(ap.minGasPrice).Float64()
+	actpoolMinGasPriceMtc.Set(price)
+}
+
+// rotateJournal rewrites the journal with the actions in pool
+func (ap *actPool) rotateJournal() error {
+	senders := make([]string, 0, len(ap.accountActs))
+	for sender := range ap.accountActs {
+		senders = append(senders, sender)
+	}
+	sort.Strings(senders)
+	acts := make([]action.SealedEnvelope, 0, len(ap.allActions))
+	for _, sender := range senders {
+		acts = append(acts, ap.accountActs[sender].AllActs()...)
+	}
+	if err := ap.journal.rotate(acts); err != nil {
+		return err
+	}
+	ap.journalRotated = time.Now()
+	return nil
 }
 
 func (ap *actPool) subGasFromPool(gas uint64) {
@@ -9,6 +9,7 @@ package actpool
 import (
 	"bytes"
 	"context"
+	"io/ioutil"
 	"math/big"
 	"strings"
 	"testing"
@@ -19,6 +20,8 @@ import (
 	"github.com/iotexproject/iotex-core/test/mock/mock_sealed_envelope_validator"
 
 	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
@@ -27,6 +30,7 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol/account"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
 	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/actpool/denylist"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/test/identityset"
@@ -901,6 +905,551 @@ func TestActPool_GetUnconfirmedActs(t *testing.T) {
 	require.Equal([]action.SealedEnvelope{tsf1, tsf3, tsf4, tsf5}, acts)
 }
 
+func TestActPool_Content(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	Ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+
+	tsf1, err := testutil.SignedTransfer(addr1, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr1, priKey1, uint64(3), big.NewInt(30), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr1, priKey1, uint64(4), big.NewInt(30), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf5, err := testutil.SignedTransfer(addr1, priKey2, uint64(2), big.NewInt(30), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100000000000000000)
+
+		return 0, nil
+	}).AnyTimes()
+	require.NoError(ap.Add(context.Background(), tsf1))
+	require.NoError(ap.Add(context.Background(), tsf3))
+	require.NoError(ap.Add(context.Background(), tsf4))
+	require.NoError(ap.Add(context.Background(), tsf5))
+
+	content := ap.Content()
+	require.Len(content, 2)
+	require.Equal(AccountContent{
+		NextNonce: 2,
+		Pending:   []action.SealedEnvelope{tsf1},
+		Queued:    []action.SealedEnvelope{tsf3, tsf4},
+	}, content[addr1])
+	// the account without pending actions waits for its first nonce
+	require.Equal(AccountContent{
+		NextNonce: 1,
+		Pending:   []action.SealedEnvelope{},
+		Queued:    []action.SealedEnvelope{tsf5},
+	}, content[addr2])
+}
+
+func TestActPool_QueueAndReplace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(10000000)
+		return 0, nil
+	}).AnyTimes()
+	Ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(100))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(100))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, priKey1, uint64(3), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(100))
+	require.NoError(err)
+
+	// the action after the nonce gap is queued, and promoted once the gap is filled
+	require.NoError(ap.Add(ctx, tsf1))
+	require.NoError(ap.Add(ctx, tsf3))
+	queue := ap.accountActs[addr1]
+	require.Equal(uint64(2), queue.PendingNonce())
+	require.Equal([]action.SealedEnvelope{tsf3}, queue.QueuedActs())
+	require.NoError(ap.Add(ctx, tsf2))
+	require.Equal(uint64(4), queue.PendingNonce())
+	require.Empty(queue.QueuedActs())
+	gas := ap.gasInPool
+
+	// the replacement needs a gas price higher by the configured percentage
+	underpriced, err := testutil.SignedTransfer(addr3, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(100))
+	require.NoError(err)
+	require.Equal(action.ErrNonce, errors.Cause(ap.Add(ctx, underpriced)))
+	underpriced, err = testutil.SignedTransfer(addr3, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(109))
+	require.NoError(err)
+	require.Equal(action.ErrNonce, errors.Cause(ap.Add(ctx, underpriced)))
+	overBalance, err := testutil.SignedTransfer(addr3, priKey1, uint64(2), big.NewInt(10000000), []byte{}, uint64(10000), big.NewInt(110))
+	require.NoError(err)
+	require.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, overBalance)))
+
+	replacement, err := testutil.SignedTransfer(addr3, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(110))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, replacement))
+	_, err = ap.GetActionByHash(tsf2.Hash())
+	require.Equal(action.ErrNotFound, errors.Cause(err))
+	_, err = ap.GetActionByHash(replacement.Hash())
+	require.NoError(err)
+	require.Equal(gas, ap.gasInPool)
+	require.Equal(uint64(3), ap.GetSize())
+	require.Empty(ap.accountDesActs[addr2][tsf2.Hash()])
+	require.Equal(replacement, ap.accountDesActs[addr3][replacement.Hash()])
+	require.Equal(uint64(4), queue.PendingNonce())
+	require.Equal([]action.SealedEnvelope{tsf1, replacement, tsf3}, ap.PendingActionMap()[addr1])
+	// the pending balance accounts for the cost of the replacement
+	balance := big.NewInt(10000000)
+	for _, act := range []action.SealedEnvelope{tsf1, replacement, tsf3} {
+		cost, err := act.Cost()
+		require.NoError(err)
+		balance.Sub(balance, cost)
+	}
+	require.Equal(balance, queue.PendingBalance())
+}
+
+func TestActPool_Sponsored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		cfg := &protocol.StateConfig{}
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		switch {
+		case bytes.Equal(cfg.Key, identityset.Address(28).Bytes()):
+			acct.Balance = big.NewInt(100)
+		case bytes.Equal(cfg.Key, identityset.Address(29).Bytes()):
+			acct.Balance = big.NewInt(25000)
+		default:
+			acct.Balance = big.NewInt(0)
+		}
+		return 0, nil
+	}).AnyTimes()
+	Ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	g := config.Default.Genesis
+	g.PacificBlockHeight = 0
+	g.SponsoredGasBlockHeight = 0
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+	sponsor := func(selp action.SealedEnvelope, err error) action.SealedEnvelope {
+		require.NoError(err)
+		sponsored, err := action.Sponsor(selp, priKey2)
+		require.NoError(err)
+		return sponsored
+	}
+
+	// the sender cannot pay the gas fee of 10000, but the payer can
+	tsf1, err := testutil.SignedTransfer(addr4, priKey1, 1, big.NewInt(10), nil, 10000, big.NewInt(1))
+	require.NoError(err)
+	require.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, tsf1)))
+	sponsored1 := sponsor(tsf1, nil)
+	require.NoError(ap.Add(ctx, sponsored1))
+	require.Equal(big.NewInt(90), ap.accountActs[addr1].PendingBalance())
+	sponsored2 := sponsor(testutil.SignedTransfer(addr4, priKey3, 1, big.NewInt(0), nil, 10000, big.NewInt(1)))
+	require.NoError(ap.Add(ctx, sponsored2))
+	require.Equal(big.NewInt(20000), ap.sponsored[addr2])
+
+	// the payer's balance left is 5000, which covers neither another gas fee nor a costlier action of its own
+	sponsored3 := sponsor(testutil.SignedTransfer(addr4, priKey3, 2, big.NewInt(0), nil, 10000, big.NewInt(1)))
+	require.Equal(action.ErrInsufficientBalanceForGas, errors.Cause(ap.Add(ctx, sponsored3)))
+	tsf2, err := testutil.SignedTransfer(addr4, priKey2, 1, big.NewInt(5001), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	require.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, tsf2)))
+	tsf2, err = testutil.SignedTransfer(addr4, priKey2, 1, big.NewInt(4000), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf2))
+	require.Equal(big.NewInt(1000), ap.accountActs[addr2].PendingBalance())
+
+	// the gas fee of a dropped sponsored action is released, and the pending balances are kept by a reset
+	ap.DeleteAction(identityset.Address(30))
+	require.Equal(big.NewInt(10000), ap.sponsored[addr2])
+	require.Equal(big.NewInt(11000), ap.accountActs[addr2].PendingBalance())
+	ap.Reset()
+	require.Equal(big.NewInt(11000), ap.accountActs[addr2].PendingBalance())
+	require.Equal(big.NewInt(90), ap.accountActs[addr1].PendingBalance())
+	require.Equal(uint64(2), ap.GetSize())
+}
+
+func TestActPool_Eviction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	cfg := getActPoolCfg()
+	cfg.MaxNumActsPerPool = 3
+	Ap, err := NewActPool(sf, cfg, EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(30))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(10))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr1, priKey2, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(20))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf1))
+	require.NoError(ap.Add(ctx, tsf2))
+	require.NoError(ap.Add(ctx, tsf3))
+
+	// an action not paying more than the cheapest action is rejected when the pool is full
+	tsf4, err := testutil.SignedTransfer(addr1, priKey3, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(10))
+	require.NoError(err)
+	require.Equal(action.ErrActPool, errors.Cause(ap.Add(ctx, tsf4)))
+	_, err = ap.GetDropReason(tsf4.Hash())
+	require.Equal(action.ErrNotFound, errors.Cause(err))
+
+	// the last action of the sender paying the lowest gas price is evicted
+	tsf5, err := testutil.SignedTransfer(addr1, priKey3, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(15))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf5))
+	require.Equal(uint64(3), ap.GetSize())
+	reason, err := ap.GetDropReason(tsf2.Hash())
+	require.NoError(err)
+	require.Equal(DropEvicted, reason)
+	pendingNonce, err := ap.GetPendingNonce(addr1)
+	require.NoError(err)
+	require.Equal(uint64(2), pendingNonce)
+	require.Equal([]action.SealedEnvelope{tsf1}, ap.PendingActionMap()[addr1])
+
+	// the actions of the sender itself are not evicted for its own action
+	tsf6, err := testutil.SignedTransfer(addr1, priKey3, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(25))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf6))
+	_, err = ap.GetDropReason(tsf5.Hash())
+	require.Equal(action.ErrNotFound, errors.Cause(err))
+	reason, err = ap.GetDropReason(tsf3.Hash())
+	require.NoError(err)
+	require.Equal(DropEvicted, reason)
+
+	// the replaced and expired actions are recorded
+	replacement, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(40))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, replacement))
+	reason, err = ap.GetDropReason(tsf1.Hash())
+	require.NoError(err)
+	require.Equal(DropReplaced, reason)
+	for _, queue := range ap.accountActs {
+		queue.(*actQueue).ttl = time.Nanosecond
+	}
+	ap.Reset()
+	require.Zero(ap.GetSize())
+	reason, err = ap.GetDropReason(replacement.Hash())
+	require.NoError(err)
+	require.Equal(DropExpired, reason)
+}
+
+func TestActPool_LocalAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	cfg := getActPoolCfg()
+	cfg.MaxNumActsPerPool = 2
+	cfg.MinGasPriceStr = "10"
+	cfg.LocalAccounts = []string{addr1}
+	Ap, err := NewActPool(sf, cfg, EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+	require.True(ap.IsLocal(addr1))
+	require.False(ap.IsLocal(addr2))
+
+	// the local account bypasses the minimal gas price
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf1))
+	tsf2, err := testutil.SignedTransfer(addr1, priKey2, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+	require.NoError(err)
+	require.Equal(action.ErrGasPrice, errors.Cause(ap.Add(ctx, tsf2)))
+
+	// the actions of the local account are not evicted when the pool is full
+	tsf3, err := testutil.SignedTransfer(addr2, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf3))
+	tsf4, err := testutil.SignedTransfer(addr1, priKey2, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(100))
+	require.NoError(err)
+	require.Equal(action.ErrActPool, errors.Cause(ap.Add(ctx, tsf4)))
+	require.Equal([]action.SealedEnvelope{tsf1, tsf3}, ap.PendingActionMap()[addr1])
+}
+
+func TestActPool_DynamicMinGasPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	confirmedNonce := uint64(0)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = confirmedNonce
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	cfg := getActPoolCfg()
+	cfg.MaxNumActsPerPool = 4
+	cfg.MinGasPriceStr = "10"
+	cfg.GasPriceSteps = []config.GasPriceStep{{Utilization: 50, Multiplier: 200}, {Utilization: 75, Multiplier: 500}}
+	cfg.GasPriceDecay = 50
+	Ap, err := NewActPool(sf, cfg, EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+	require.Equal(big.NewInt(10), ap.MinGasPrice())
+
+	// the minimal gas price is raised at once as the utilization crosses the thresholds
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(10))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf1))
+	require.Equal(big.NewInt(10), ap.MinGasPrice())
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(10))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf2))
+	require.Equal(big.NewInt(20), ap.MinGasPrice())
+	tsf3, err := testutil.SignedTransfer(addr2, priKey1, uint64(3), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(10))
+	require.NoError(err)
+	require.Equal(action.ErrGasPrice, errors.Cause(ap.Add(ctx, tsf3)))
+	tsf3, err = testutil.SignedTransfer(addr2, priKey1, uint64(3), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(20))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf3))
+	require.Equal(big.NewInt(50), ap.MinGasPrice())
+
+	// and decays every block once the actions are mined
+	confirmedNonce = 3
+	require.NoError(ap.ReceiveBlock(nil))
+	require.Zero(ap.GetSize())
+	require.Equal(big.NewInt(30), ap.MinGasPrice())
+	require.NoError(ap.ReceiveBlock(nil))
+	require.Equal(big.NewInt(20), ap.MinGasPrice())
+	for i := 0; i < 5; i++ {
+		require.NoError(ap.ReceiveBlock(nil))
+	}
+	require.Equal(big.NewInt(10), ap.MinGasPrice())
+}
+
+func TestActPool_AdmissionLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	confirmedNonce := uint64(0)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = confirmedNonce
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	cfg := getActPoolCfg()
+	cfg.MaxNumActsPerSource = 2
+	cfg.AdmissionWindow = time.Hour
+	cfg.MaxAdmissionsPerSender = 3
+	cfg.LocalAccounts = []string{addr3}
+	Ap, err := NewActPool(sf, cfg, EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	peer1 := WithActionSource(context.Background(), "peer:1")
+	peer2 := WithActionSource(context.Background(), "peer:2")
+	transfer := func(priKey crypto.PrivateKey, nonce uint64) action.SealedEnvelope {
+		tsf, err := testutil.SignedTransfer(addr4, priKey, nonce, big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+		require.NoError(err)
+		return tsf
+	}
+
+	// the actions in pool from a source are capped
+	require.NoError(ap.Add(peer1, transfer(priKey1, 1)))
+	require.NoError(ap.Add(peer1, transfer(priKey1, 2)))
+	require.Equal(action.ErrActPool, errors.Cause(ap.Add(peer1, transfer(priKey1, 3))))
+	require.NoError(ap.Add(peer2, transfer(priKey1, 3)))
+	// the actions of a local account, or without a source, are not limited
+	require.NoError(ap.Add(peer1, transfer(priKey3, 1)))
+	require.NoError(ap.Add(context.Background(), transfer(priKey1, 4)))
+	require.EqualValues(3, ap.sourceActs["peer:1"])
+
+	// the source is freed when its actions are mined
+	confirmedNonce = 2
+	require.NoError(ap.ReceiveBlock(nil))
+	_, ok = ap.sourceActs["peer:1"]
+	require.False(ok)
+	require.EqualValues(1, ap.sourceActs["peer:2"])
+
+	// the admission rate of a sender is limited no matter the source
+	require.Equal(action.ErrActPool, errors.Cause(ap.Add(peer2, transfer(priKey1, 5))))
+	require.NoError(ap.Add(peer1, transfer(priKey2, 3)))
+}
+
+func TestActPool_Denylist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	path, err := testutil.PathOfTempFile("denylist")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, path)
+	require.NoError(ioutil.WriteFile(path, []byte("addresses:\n  - "+addr2+"\n"), 0600))
+	d, err := denylist.New(path, 0)
+	require.NoError(err)
+	ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions(), WithDenylist(d))
+	require.NoError(err)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+	require.NoError(err)
+	require.Equal(action.ErrAddress, errors.Cause(ap.Add(ctx, tsf1)))
+	tsf2, err := testutil.SignedTransfer(addr3, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf2))
+	// the blocks are not validated against the denylist
+	require.NoError(ap.Validate(ctx, tsf1))
+}
+
+func TestActPool_BlockGasLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	height := uint64(9)
+	sf.EXPECT().Height().DoAndReturn(func() (uint64, error) { return height, nil }).AnyTimes()
+	limit := func(h uint64) uint64 {
+		if h >= 20 {
+			return 50000
+		}
+		return 20000
+	}
+	ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions(), WithBlockGasLimit(limit))
+	require.NoError(err)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(30000), big.NewInt(0))
+	require.NoError(err)
+	require.Equal(action.ErrHitGasLimit, errors.Cause(ap.Add(ctx, tsf1)))
+	// the action is admitted once the higher block gas limit is activated at the next height
+	height = 19
+	require.NoError(ap.Add(ctx, tsf1))
+}
+
+func TestActPool_Orphans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	confirmedNonce := uint64(0)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = confirmedNonce
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	cfg := getActPoolCfg()
+	cfg.MaxNumActsPerAcct = 2
+	cfg.ActionExpiry = time.Hour
+	cfg.MaxNumOrphans = 2
+	cfg.MaxNumOrphansPerAcct = 2
+	cfg.MaxOrphanNonceGap = 2
+	Ap, err := NewActPool(sf, cfg, EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := context.Background()
+	transfer := func(priKey crypto.PrivateKey, nonce uint64) action.SealedEnvelope {
+		tsf, err := testutil.SignedTransfer(addr4, priKey, nonce, big.NewInt(10), []byte{}, uint64(10000), big.NewInt(0))
+		require.NoError(err)
+		return tsf
+	}
+
+	require.NoError(ap.Add(ctx, transfer(priKey1, 1)))
+	require.NoError(ap.Add(ctx, transfer(priKey1, 2)))
+	// the actions beyond the range of the account queue are kept as orphans, up to the orphan nonce gap
+	orphan3, orphan4 := transfer(priKey1, 3), transfer(priKey1, 4)
+	require.NoError(ap.Add(ctx, orphan3))
+	require.NoError(ap.Add(ctx, orphan4))
+	require.Equal(action.ErrNonce, errors.Cause(ap.Add(ctx, transfer(priKey1, 5))))
+	require.Error(ap.Add(ctx, orphan4))
+	require.Equal(action.ErrActPool, errors.Cause(ap.Add(ctx, transfer(priKey2, 3))))
+	require.Equal(uint64(2), ap.GetSize())
+	require.Equal(uint64(2), ap.orphans.len())
+	_, err = ap.GetActionByHash(orphan3.Hash())
+	require.Error(err)
+
+	// the orphans are put into the pool once the nonce of the sender catches up
+	confirmedNonce = 2
+	require.NoError(ap.ReceiveBlock(nil))
+	require.Equal(uint64(2), ap.GetSize())
+	require.Zero(ap.orphans.len())
+	for _, act := range []action.SealedEnvelope{orphan3, orphan4} {
+		_, err = ap.GetActionByHash(act.Hash())
+		require.NoError(err)
+	}
+	pendingNonce, err := ap.GetPendingNonce(addr1)
+	require.NoError(err)
+	require.Equal(uint64(5), pendingNonce)
+}
+
 func TestActPool_GetActionByHash(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1024,6 +1573,47 @@ func TestActPool_AddActionNotEnoughGasPrice(t *testing.T) {
 	require.Error(t, ap.Add(ctx, tsf))
 }
 
+type testActionSubscriber struct {
+	received []action.SealedEnvelope
+}
+
+func (s *testActionSubscriber) ReceiveAction(selp action.SealedEnvelope) {
+	s.received = append(s.received, selp)
+}
+
+func TestActPool_Subscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100)
+		return 0, nil
+	}).AnyTimes()
+	ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions())
+	require.NoError(err)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{})
+
+	sub := &testActionSubscriber{}
+	ap.AddSubscriber(sub)
+	tsf1, err := testutil.SignedTransfer(addr1, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf1))
+	// rejected actions are not notified
+	require.Error(ap.Add(ctx, tsf1))
+	require.Equal([]action.SealedEnvelope{tsf1}, sub.received)
+
+	ap.RemoveSubscriber(sub)
+	tsf2, err := testutil.SignedTransfer(addr1, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, tsf2))
+	require.Len(sub.received, 1)
+}
+
 // Helper function to return the correct pending nonce just in case of empty queue
 func (ap *actPool) getPendingNonce(addr string) (uint64, error) {
 	if queue, ok := ap.accountActs[addr]; ok {
@@ -1048,11 +1638,12 @@ func (ap *actPool) getPendingBalance(addr string) (*big.Int, error) {
 
 func getActPoolCfg() config.ActPool {
 	return config.ActPool{
-		MaxNumActsPerPool:  maxNumActsPerPool,
-		MaxGasLimitPerPool: maxGasLimitPerPool,
-		MaxNumActsPerAcct:  maxNumActsPerAcct,
-		MinGasPriceStr:     "0",
-		BlackList:          []string{addr6},
+		MaxNumActsPerPool:   maxNumActsPerPool,
+		MaxGasLimitPerPool:  maxGasLimitPerPool,
+		MaxNumActsPerAcct:   maxNumActsPerAcct,
+		MinGasPriceStr:      "0",
+		ReplaceGasPriceBump: 10,
+		BlackList:           []string{addr6},
 	}
 }
 
@@ -1071,3 +1662,14 @@ func lenPendingActionMap(acts map[string][]action.SealedEnvelope) int {
 	}
 	return l
 }
+
+func TestValidateAction(t *testing.T) {
+	require := require.New(t)
+	tsf, err := testutil.SignedTransfer(addr2, priKey1, 1, big.NewInt(1), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ValidateAction(tsf.Proto()))
+	tsf, err = testutil.SignedTransfer(addr2, priKey1, 1, big.NewInt(1), nil, 100, big.NewInt(0))
+	require.NoError(err)
+	require.Equal(action.ErrInsufficientBalanceForGas, errors.Cause(ValidateAction(tsf.Proto())))
+	require.Error(ValidateAction(&iotextypes.Action{}))
+}
@@ -52,8 +52,12 @@ func (h *noncePriorityQueue) Pop() interface{} {
 type ActQueue interface {
 	Overlaps(action.SealedEnvelope) bool
 	Put(action.SealedEnvelope) error
+	ActByNonce(uint64) (action.SealedEnvelope, bool)
+	Replace(action.SealedEnvelope) (action.SealedEnvelope, error)
 	FilterNonce(uint64) []action.SealedEnvelope
 	UpdateQueue(uint64) []action.SealedEnvelope
+	CleanTimeout() []action.SealedEnvelope
+	PopLast() (action.SealedEnvelope, bool)
 	SetPendingNonce(uint64)
 	PendingNonce() uint64
 	SetPendingBalance(*big.Int)
@@ -61,6 +65,7 @@ type ActQueue interface {
 	Len() int
 	Empty() bool
 	PendingActs() []action.SealedEnvelope
+	QueuedActs() []action.SealedEnvelope
 	AllActs() []action.SealedEnvelope
 }
 
@@ -72,6 +77,9 @@ type actQueue struct {
 	items map[uint64]action.SealedEnvelope
 	// Priority Queue that stores all the nonces belonging to an account. Nonces are used as indices for action map
 	index noncePriorityQueue
+	// Set of the nonces of the actions waiting for the actions of lower nonces, they are promoted to pending once the
+	// nonce gap is filled
+	queued map[uint64]struct{}
 	// Current pending nonce tracking previous actions that can be committed to the next block for the account
 	pendingNonce uint64
 	// Current pending balance for the account
@@ -91,6 +99,7 @@ func NewActQueue(ap *actPool, address string, ops ...ActQueueOption) ActQueue {
 		address:        address,
 		items:          make(map[uint64]action.SealedEnvelope),
 		index:          noncePriorityQueue{},
+		queued:         make(map[uint64]struct{}),
 		pendingNonce:   uint64(1), // Taking coinbase Action into account, pendingNonce should start with 1
 		pendingBalance: big.NewInt(0),
 		ttl:            0,
@@ -115,9 +124,36 @@ func (q *actQueue) Put(act action.SealedEnvelope) error {
 	}
 	heap.Push(&q.index, nonceWithTTL{nonce: nonce, deadline: time.Now().Add(q.ttl)})
 	q.items[nonce] = act
+	if nonce >= q.pendingNonce {
+		q.queued[nonce] = struct{}{}
+	}
 	return nil
 }
 
+// ActByNonce returns the action of the given nonce in the queue
+func (q *actQueue) ActByNonce(nonce uint64) (action.SealedEnvelope, bool) {
+	act, exist := q.items[nonce]
+	return act, exist
+}
+
+// Replace replaces the action of the same nonce with the given action, and returns the replaced action. The ttl of
+// the nonce starts over.
+func (q *actQueue) Replace(act action.SealedEnvelope) (action.SealedEnvelope, error) {
+	nonce := act.Nonce()
+	old, exist := q.items[nonce]
+	if !exist {
+		return action.SealedEnvelope{}, errors.Wrapf(action.ErrNotFound, "no action of nonce %d to replace", nonce)
+	}
+	for i := range q.index {
+		if q.index[i].nonce == nonce {
+			q.index[i].deadline = time.Now().Add(q.ttl)
+			break
+		}
+	}
+	q.items[nonce] = act
+	return old, nil
+}
+
 // FilterNonce removes all actions from the map with a nonce lower than the given threshold
 func (q *actQueue) FilterNonce(threshold uint64) []action.SealedEnvelope {
 	var removed []action.SealedEnvelope
@@ -126,32 +162,50 @@ func (q *actQueue) FilterNonce(threshold uint64) []action.SealedEnvelope {
 		nonce := heap.Pop(&q.index).(nonceWithTTL).nonce
 		removed = append(removed, q.items[nonce])
 		delete(q.items, nonce)
+		delete(q.queued, nonce)
 	}
 	return removed
 }
 
+// CleanTimeout removes the actions which stay in the queue longer than the ttl
+func (q *actQueue) CleanTimeout() []action.SealedEnvelope {
+	if q.ttl == 0 {
+		return nil
+	}
+	return q.cleanTimeout()
+}
+
 func (q *actQueue) cleanTimeout() []action.SealedEnvelope {
 	removedFromQueue := make([]action.SealedEnvelope, 0)
-	for i := 0; i < len(q.index); i++ {
-		if time.Now().After(q.index[i].deadline) {
-			// remove
-			removedFromQueue = append(removedFromQueue, q.items[q.index[i].nonce])
-			delete(q.items, q.index[i].nonce)
-			q.index = append(q.index[:i], q.index[i+1:]...)
+	now := time.Now()
+	index := q.index[:0]
+	for _, n := range q.index {
+		if !now.After(n.deadline) {
+			index = append(index, n)
+			continue
 		}
+		removedFromQueue = append(removedFromQueue, q.items[n.nonce])
+		delete(q.items, n.nonce)
+		delete(q.queued, n.nonce)
 	}
+	q.index = index
+	heap.Init(&q.index)
 	return removedFromQueue
 }
 
+// PopLast removes the action of the highest nonce from the queue
+func (q *actQueue) PopLast() (action.SealedEnvelope, bool) {
+	if q.index.Len() == 0 {
+		return action.SealedEnvelope{}, false
+	}
+	sort.Sort(q.index)
+	return q.removeActs(q.index.Len() - 1)[0], true
+}
+
 // UpdateQueue updates the pending nonce and balance of the queue
 func (q *actQueue) UpdateQueue(nonce uint64) []action.SealedEnvelope {
 	removedFromQueue := make([]action.SealedEnvelope, 0)
-	// First remove all timed out actions
-	if q.ttl != 0 {
-		removedFromQueue = append(removedFromQueue, q.cleanTimeout()...)
-	}
-
-	// Now, starting from the current pending nonce, incrementally find the next pending nonce
+	// Starting from the current pending nonce, incrementally find the next pending nonce
 	// while updating pending balance if actions are payable
 	for ; ; nonce++ {
 		_, exist := q.items[nonce]
@@ -163,6 +217,7 @@ func (q *actQueue) UpdateQueue(nonce uint64) []action.SealedEnvelope {
 		}
 	}
 	q.pendingNonce = nonce
+	q.promote()
 
 	// Find the index of new pending nonce within the queue
 	sort.Sort(q.index)
@@ -194,6 +249,18 @@ func (q *actQueue) UpdateQueue(nonce uint64) []action.SealedEnvelope {
 	return removedFromQueue
 }
 
+// promote moves the actions below the pending nonce from the queued set to pending, and those at or above it back to
+// the queued set
+func (q *actQueue) promote() {
+	for nonce := range q.items {
+		if nonce < q.pendingNonce {
+			delete(q.queued, nonce)
+		} else {
+			q.queued[nonce] = struct{}{}
+		}
+	}
+}
+
 // SetPendingNonce sets pending nonce for the queue
 func (q *actQueue) SetPendingNonce(nonce uint64) {
 	q.pendingNonce = nonce
@@ -245,6 +312,20 @@ func (q *actQueue) PendingActs() []action.SealedEnvelope {
 	return acts
 }
 
+// QueuedActs returns the nonce-sorted actions which wait for the actions of lower nonces
+func (q *actQueue) QueuedActs() []action.SealedEnvelope {
+	nonces := make([]uint64, 0, len(q.queued))
+	for nonce := range q.queued {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	acts := make([]action.SealedEnvelope, 0, len(nonces))
+	for _, nonce := range nonces {
+		acts = append(acts, q.items[nonce])
+	}
+	return acts
+}
+
 // AllActs returns all the actions currently in queue
 func (q *actQueue) AllActs() []action.SealedEnvelope {
 	acts := make([]action.SealedEnvelope, 0, len(q.items))
@@ -264,15 +345,17 @@ func (q *actQueue) removeActs(idx int) []action.SealedEnvelope {
 	for i := idx; i < q.index.Len(); i++ {
 		removedFromQueue = append(removedFromQueue, q.items[q.index[i].nonce])
 		delete(q.items, q.index[i].nonce)
+		delete(q.queued, q.index[i].nonce)
 	}
 	q.index = q.index[:idx]
 	heap.Init(&q.index)
 	return removedFromQueue
 }
 
-// enoughBalance helps check whether queue's pending balance is sufficient for the given action
+// enoughBalance helps check whether queue's pending balance is sufficient for the given action, whose gas fee is set
+// aside from the balance of its payer instead if it's sponsored
 func (q *actQueue) enoughBalance(act action.SealedEnvelope, updateBalance bool) bool {
-	cost, _ := act.Cost()
+	cost, _ := act.SenderCost()
 	if q.pendingBalance.Cmp(cost) < 0 {
 		return false
 	}
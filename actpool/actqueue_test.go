@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -212,3 +213,39 @@ func TestActQueueTimeOutAction(t *testing.T) {
 	q.(*actQueue).cleanTimeout()
 	assert.Equal(t, 1, q.Len())
 }
+
+func TestActQueueQueuedAndReplace(t *testing.T) {
+	require := require.New(t)
+	q := NewActQueue(nil, "").(*actQueue)
+	q.pendingBalance = big.NewInt(10000)
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, 1, big.NewInt(1), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, priKey1, 3, big.NewInt(1), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, 2, big.NewInt(1), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(q.Put(tsf1))
+	require.NoError(q.Put(tsf3))
+	require.Empty(q.UpdateQueue(q.pendingNonce))
+	require.Equal(uint64(2), q.pendingNonce)
+	require.Equal([]action.SealedEnvelope{tsf3}, q.QueuedActs())
+	// filling the gap promotes the queued action
+	require.NoError(q.Put(tsf2))
+	require.Empty(q.UpdateQueue(q.pendingNonce))
+	require.Equal(uint64(4), q.pendingNonce)
+	require.Empty(q.QueuedActs())
+
+	replacement, err := testutil.SignedTransfer(addr2, priKey1, 2, big.NewInt(2), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	old, err := q.Replace(replacement)
+	require.NoError(err)
+	require.Equal(tsf2, old)
+	act, ok := q.ActByNonce(2)
+	require.True(ok)
+	require.Equal(replacement, act)
+	require.Equal(3, q.Len())
+	missing, err := testutil.SignedTransfer(addr2, priKey1, 5, big.NewInt(2), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	_, err = q.Replace(missing)
+	require.Equal(action.ErrNotFound, errors.Cause(err))
+}
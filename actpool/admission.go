@@ -0,0 +1,89 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"context"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/cache"
+)
+
+// admissionKeysCacheSize is the number of senders, or sources, whose admission rates are tracked
+const admissionKeysCacheSize = 16384
+
+type actionSourceCtxKey struct{}
+
+// WithActionSource attaches to the context the source an action is received from, such as a peer or an api client
+// ip, whose actions in pool are limited
+func WithActionSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, actionSourceCtxKey{}, source)
+}
+
+// GetActionSource returns the source an action is received from
+func GetActionSource(ctx context.Context) (string, bool) {
+	source, ok := ctx.Value(actionSourceCtxKey{}).(string)
+	return source, ok && source != ""
+}
+
+type (
+	// slidingWindow limits the events of each key in a sliding window. The count in the sliding window is approximated
+	// by weighting the count of the previous fixed window with its overlap with the sliding window.
+	slidingWindow struct {
+		window time.Duration
+		limit  uint64
+		counts *cache.ThreadSafeLruCache
+	}
+
+	windowCount struct {
+		start    time.Time
+		current  uint64
+		previous uint64
+	}
+)
+
+// newSlidingWindow creates a sliding window limiter, it returns nil if the limit is disabled
+func newSlidingWindow(window time.Duration, limit uint64) *slidingWindow {
+	if window <= 0 || limit == 0 {
+		return nil
+	}
+	return &slidingWindow{
+		window: window,
+		limit:  limit,
+		counts: cache.NewThreadSafeLruCache(admissionKeysCacheSize),
+	}
+}
+
+// allow counts an event of the key at the given time, unless the key has reached the limit. All events are allowed if
+// the limiter is nil.
+func (w *slidingWindow) allow(key string, now time.Time) bool {
+	if w == nil {
+		return true
+	}
+	var c *windowCount
+	if v, ok := w.counts.Get(key); ok {
+		c = v.(*windowCount)
+	} else {
+		c = &windowCount{start: now}
+		w.counts.Add(key, c)
+	}
+	if elapsed := now.Sub(c.start); elapsed >= w.window {
+		if elapsed >= 2*w.window {
+			c.previous = 0
+		} else {
+			c.previous = c.current
+		}
+		c.current = 0
+		c.start = c.start.Add(elapsed / w.window * w.window)
+	}
+	overlap := 1 - float64(now.Sub(c.start))/float64(w.window)
+	if float64(c.previous)*overlap+float64(c.current) >= float64(w.limit) {
+		return false
+	}
+	c.current++
+	return true
+}
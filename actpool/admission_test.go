@@ -0,0 +1,56 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionSourceCtx(t *testing.T) {
+	require := require.New(t)
+	_, ok := GetActionSource(context.Background())
+	require.False(ok)
+	_, ok = GetActionSource(WithActionSource(context.Background(), ""))
+	require.False(ok)
+	source, ok := GetActionSource(WithActionSource(context.Background(), "ip:127.0.0.1"))
+	require.True(ok)
+	require.Equal("ip:127.0.0.1", source)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	require := require.New(t)
+	var disabled *slidingWindow
+	require.True(disabled.allow("a", time.Now()))
+	require.Nil(newSlidingWindow(time.Minute, 0))
+	require.Nil(newSlidingWindow(0, 10))
+
+	w := newSlidingWindow(time.Minute, 4)
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		require.True(w.allow("a", now))
+	}
+	require.False(w.allow("a", now))
+	// the keys are limited separately
+	require.True(w.allow("b", now))
+
+	// half of the previous window overlaps the sliding window
+	now = now.Add(90 * time.Second)
+	require.True(w.allow("a", now))
+	require.True(w.allow("a", now))
+	require.False(w.allow("a", now))
+
+	// the previous window no longer overlaps after two windows
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 4; i++ {
+		require.True(w.allow("a", now))
+	}
+	require.False(w.allow("a", now))
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package denylist
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+var deniedMtc = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "iotex_denylist_denied_actions",
+	Help: "Number of actions rejected by the denylist.",
+}, []string{"rule"})
+
+func init() {
+	prometheus.MustRegister(deniedMtc)
+}
+
+type (
+	// Denylist rejects the actions sent from or to a denied address, and the executions of a denied contract. The list
+	// is loaded from a yaml file, which is reloaded on SIGHUP, and once it is modified.
+	Denylist struct {
+		path      string
+		interval  time.Duration
+		mutex     sync.RWMutex
+		addresses map[string]bool
+		contracts map[string]bool
+		modTime   time.Time
+		done      chan struct{}
+		wg        sync.WaitGroup
+	}
+
+	// listFile is the content of the denylist file, the addresses are in io or 0x format
+	listFile struct {
+		Addresses []string `yaml:"addresses"`
+		Contracts []string `yaml:"contracts"`
+	}
+)
+
+// New loads a denylist from the file, which is checked for modification at the given interval once started
+func New(path string, interval time.Duration) (*Denylist, error) {
+	d := &Denylist{
+		path:     path,
+		interval: interval,
+	}
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Start starts reloading the denylist on SIGHUP and on modification
+func (d *Denylist) Start(_ context.Context) error {
+	d.done = make(chan struct{})
+	d.wg.Add(1)
+	go d.watch()
+	return nil
+}
+
+// Stop stops reloading the denylist
+func (d *Denylist) Stop(_ context.Context) error {
+	if d.done != nil {
+		close(d.done)
+		d.wg.Wait()
+		d.done = nil
+	}
+	return nil
+}
+
+// Reload loads the denylist from the file, the list in use is kept if it fails
+func (d *Denylist) Reload() error {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read denylist")
+	}
+	data, err := ioutil.ReadFile(d.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read denylist")
+	}
+	var content listFile
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return errors.Wrap(err, "failed to parse denylist")
+	}
+	addresses, err := addressSet(content.Addresses)
+	if err != nil {
+		return err
+	}
+	contracts, err := addressSet(content.Contracts)
+	if err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.addresses = addresses
+	d.contracts = contracts
+	d.modTime = info.ModTime()
+	return nil
+}
+
+// Check returns an error if the action is denied
+func (d *Denylist) Check(selp action.SealedEnvelope) error {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	sender, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return err
+	}
+	if d.addresses[sender.String()] {
+		deniedMtc.WithLabelValues("sender").Inc()
+		return errors.Wrapf(action.ErrAddress, "sender %s is denied", sender.String())
+	}
+	dst, ok := selp.Destination()
+	if !ok {
+		return nil
+	}
+	if d.addresses[dst] {
+		deniedMtc.WithLabelValues("recipient").Inc()
+		return errors.Wrapf(action.ErrAddress, "recipient %s is denied", dst)
+	}
+	if _, ok := selp.Action().(*action.Execution); ok && d.contracts[dst] {
+		deniedMtc.WithLabelValues("contract").Inc()
+		return errors.Wrapf(action.ErrAddress, "contract %s is denied", dst)
+	}
+	return nil
+}
+
+// watch reloads the denylist on each SIGHUP, and when the file is modified, until the denylist is stopped
+func (d *Denylist) watch() {
+	defer d.wg.Done()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+	var tick <-chan time.Time
+	if d.interval > 0 {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-sig:
+			d.reload()
+		case <-tick:
+			if info, err := os.Stat(d.path); err == nil && d.modified(info.ModTime()) {
+				d.reload()
+			}
+		}
+	}
+}
+
+func (d *Denylist) reload() {
+	if err := d.Reload(); err != nil {
+		log.L().Error("Failed to reload denylist.", zap.Error(err))
+		return
+	}
+	log.L().Info("Reloaded denylist.", zap.String("path", d.path))
+}
+
+func (d *Denylist) modified(modTime time.Time) bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return !modTime.Equal(d.modTime)
+}
+
+func addressSet(addrs []string) (map[string]bool, error) {
+	set := make(map[string]bool, len(addrs))
+	for _, s := range addrs {
+		var (
+			addr address.Address
+			err  error
+		)
+		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+			addr, err = address.FromHex(s)
+		} else {
+			addr, err = address.FromString(s)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address %s in denylist", s)
+		}
+		set[addr.String()] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package denylist
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestDenylist(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "denylist")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "denylist.yaml")
+
+	_, err = New(path, time.Second)
+	require.Error(err)
+
+	sender := identityset.Address(1).String()
+	recipient := identityset.Address(2).String()
+	contract := identityset.Address(3).String()
+	hexRecipient := "0x" + hex.EncodeToString(identityset.Address(2).Bytes())
+	require.NoError(ioutil.WriteFile(path, []byte("addresses:\n  - "+sender+"\n  - "+hexRecipient+"\ncontracts:\n  - "+contract+"\n"), 0600))
+	d, err := New(path, 10*time.Millisecond)
+	require.NoError(err)
+
+	transfer := func(priKey int, to string) action.SealedEnvelope {
+		tsf, err := testutil.SignedTransfer(to, identityset.PrivateKey(priKey), 1, big.NewInt(1), nil, 100000, big.NewInt(0))
+		require.NoError(err)
+		return tsf
+	}
+	execution := func(priKey int, contract string) action.SealedEnvelope {
+		exec, err := testutil.SignedExecution(contract, identityset.PrivateKey(priKey), 1, big.NewInt(0), 100000, big.NewInt(0), nil)
+		require.NoError(err)
+		return exec
+	}
+	require.Equal(action.ErrAddress, errors.Cause(d.Check(transfer(1, contract))))
+	require.Equal(action.ErrAddress, errors.Cause(d.Check(transfer(4, recipient))))
+	require.Equal(action.ErrAddress, errors.Cause(d.Check(execution(4, contract))))
+	// a denied contract only rejects the executions
+	require.NoError(d.Check(transfer(4, contract)))
+	require.NoError(d.Check(execution(4, identityset.Address(5).String())))
+
+	// an invalid list is not loaded
+	require.NoError(ioutil.WriteFile(path, []byte("addresses:\n  - invalid\n"), 0600))
+	require.Error(d.Reload())
+	require.Error(d.Check(transfer(1, contract)))
+
+	// the list is reloaded once modified
+	ctx := context.Background()
+	require.NoError(d.Start(ctx))
+	defer func() { require.NoError(d.Stop(ctx)) }()
+	require.NoError(ioutil.WriteFile(path, []byte("contracts:\n  - "+contract+"\n"), 0600))
+	modTime := time.Now().Add(time.Second)
+	require.NoError(os.Chtimes(path, modTime, modTime))
+	require.Eventually(func() bool { return d.Check(transfer(1, contract)) == nil }, 5*time.Second, 10*time.Millisecond)
+	require.Error(d.Check(execution(4, contract)))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// maxJournalEntrySize bounds the size of a journal entry, a larger size means the journal is corrupted
+const maxJournalEntrySize = 1 << 24
+
+// actJournal is an append-only file of the actions accepted into the pool, which are reloaded after a restart. Each
+// entry is the size of the action, as an uvarint, followed by the action in protobuf.
+type actJournal struct {
+	path   string
+	writer *os.File
+}
+
+func newActJournal(path string) *actJournal {
+	return &actJournal{path: path}
+}
+
+// load reads the actions of the journal and adds them with add. It returns the numbers of actions added and dropped.
+// A truncated entry at the end of the journal, which is left by a crash, ends the load.
+func (j *actJournal) load(add func(action.SealedEnvelope) error) (int, int, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to open actpool journal")
+	}
+	defer f.Close()
+
+	var loaded, dropped int
+	r := bufio.NewReader(f)
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil || size > maxJournalEntrySize {
+			log.L().Warn("Actpool journal is truncated.", zap.String("path", j.path), zap.Error(err))
+			break
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			log.L().Warn("Actpool journal is truncated.", zap.String("path", j.path), zap.Error(err))
+			break
+		}
+		pb := &iotextypes.Action{}
+		if err := proto.Unmarshal(buf, pb); err != nil {
+			dropped++
+			continue
+		}
+		var selp action.SealedEnvelope
+		if err := selp.LoadProto(pb); err != nil {
+			dropped++
+			continue
+		}
+		if err := add(selp); err != nil {
+			log.L().Debug("Dropped journaled action.", zap.Error(err))
+			dropped++
+			continue
+		}
+		loaded++
+	}
+	return loaded, dropped, nil
+}
+
+// insert appends an action to the journal, it does nothing until the journal is opened by rotate
+func (j *actJournal) insert(act action.SealedEnvelope) error {
+	if j.writer == nil {
+		return nil
+	}
+	return writeJournalEntry(j.writer, act)
+}
+
+// rotate rewrites the journal with the given actions, and opens it for appending
+func (j *actJournal) rotate(acts []action.SealedEnvelope) error {
+	if err := j.close(); err != nil {
+		return err
+	}
+	tmp := j.path + ".new"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create actpool journal")
+	}
+	w := bufio.NewWriter(f)
+	for _, act := range acts {
+		if err := writeJournalEntry(w, act); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to write actpool journal")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to write actpool journal")
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return errors.Wrap(err, "failed to replace actpool journal")
+	}
+	if j.writer, err = os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		return errors.Wrap(err, "failed to open actpool journal")
+	}
+	return nil
+}
+
+// close closes the journal file
+func (j *actJournal) close() error {
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	return err
+}
+
+func writeJournalEntry(w io.Writer, act action.SealedEnvelope) error {
+	data, err := proto.Marshal(act.Proto())
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize action")
+	}
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(data)))
+	if _, err := w.Write(size[:n]); err != nil {
+		return errors.Wrap(err, "failed to write actpool journal")
+	}
+	if _, err := w.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write actpool journal")
+	}
+	return nil
+}
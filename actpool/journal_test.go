@@ -0,0 +1,105 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestActPool_Journal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "actpool")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, dir)
+	cfg := getActPoolCfg()
+	cfg.JournalPath = filepath.Join(dir, "actpool.journal")
+
+	var confirmedNonce uint64
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = confirmedNonce
+		acct.Balance = big.NewInt(1000)
+		return 0, nil
+	}).AnyTimes()
+	newPool := func() *actPool {
+		ap, err := NewActPool(sf, cfg, EnableExperimentalActions())
+		require.NoError(err)
+		ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+		return ap.(*actPool)
+	}
+	ctx := context.Background()
+
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr1, priKey2, uint64(4), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+
+	// the journal is created on start, and the accepted actions are appended to it
+	ap := newPool()
+	require.NoError(ap.Start(ctx))
+	require.NoError(ap.Add(ctx, tsf1))
+	require.NoError(ap.Add(ctx, tsf2))
+	require.NoError(ap.Add(ctx, tsf4))
+	require.Error(ap.Add(ctx, tsf1))
+
+	// a restart without a graceful stop reloads the actions, a truncated entry is ignored
+	f, err := os.OpenFile(cfg.JournalPath, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(err)
+	_, err = f.Write([]byte{0x10, 0x01})
+	require.NoError(err)
+	require.NoError(f.Close())
+	ap2 := newPool()
+	require.NoError(ap2.Start(ctx))
+	require.Equal(uint64(3), ap2.GetSize())
+	pending := ap2.PendingActionMap()
+	require.Len(pending[addr1], 2)
+	require.Equal(tsf1.Hash(), pending[addr1][0].Hash())
+	require.Equal(tsf2.Hash(), pending[addr1][1].Hash())
+	require.Empty(pending[addr2])
+	_, err = ap2.GetActionByHash(tsf4.Hash())
+	require.NoError(err)
+	require.NoError(ap2.Stop(ctx))
+
+	// the mined actions are dropped on reload
+	confirmedNonce = 1
+	ap3 := newPool()
+	require.NoError(ap3.Start(ctx))
+	require.Equal(uint64(2), ap3.GetSize())
+	_, err = ap3.GetActionByHash(tsf1.Hash())
+	require.Equal(action.ErrNotFound, errors.Cause(err))
+	require.NoError(ap3.Stop(ctx))
+
+	// the journal is rewritten with the actions in pool
+	ap4 := newPool()
+	loaded, dropped, err := ap4.journal.load(func(action.SealedEnvelope) error { return nil })
+	require.NoError(err)
+	require.Equal(2, loaded)
+	require.Zero(dropped)
+}
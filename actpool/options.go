@@ -8,6 +8,8 @@ package actpool
 
 import (
 	"time"
+
+	"github.com/iotexproject/iotex-core/actpool/denylist"
 )
 
 type ttlOption struct{ ttl time.Duration }
@@ -18,3 +20,20 @@ func WithTimeOut(ttl time.Duration) interface{ ActQueueOption } {
 }
 
 func (o *ttlOption) SetActQueueOption(aq *actQueue) { aq.ttl = o.ttl }
+
+// WithDenylist returns an option to reject the actions denied by the denylist
+func WithDenylist(d *denylist.Denylist) Option {
+	return func(pool *actPool) error {
+		pool.denylist = d
+		return nil
+	}
+}
+
+// WithBlockGasLimit returns an option to reject the actions whose gas limit exceeds the gas limit of the next block,
+// which never fit in a block
+func WithBlockGasLimit(limit func(height uint64) uint64) Option {
+	return func(pool *actPool) error {
+		pool.blockGasLimit = limit
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"sort"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+type (
+	// orphanBuffer keeps the actions whose nonces are too far ahead of their senders' to be put into the pool, until
+	// the nonces of the senders catch up
+	orphanBuffer struct {
+		maxNum        uint64
+		maxNumPerAcct uint64
+		expiry        time.Duration
+		size          uint64
+		acts          map[string]map[uint64]orphan
+		hashes        map[hash.Hash256]struct{}
+	}
+
+	orphan struct {
+		act      action.SealedEnvelope
+		deadline time.Time
+	}
+)
+
+// newOrphanBuffer returns nil if the number of orphans is not positive
+func newOrphanBuffer(maxNum, maxNumPerAcct uint64, expiry time.Duration) *orphanBuffer {
+	if maxNum == 0 || maxNumPerAcct == 0 {
+		return nil
+	}
+	return &orphanBuffer{
+		maxNum:        maxNum,
+		maxNumPerAcct: maxNumPerAcct,
+		expiry:        expiry,
+		acts:          make(map[string]map[uint64]orphan),
+		hashes:        make(map[hash.Hash256]struct{}),
+	}
+}
+
+// put keeps an action of the sender, which replaces the orphan of the same nonce if its gas price is higher
+func (b *orphanBuffer) put(sender string, act action.SealedEnvelope, now time.Time) error {
+	h := act.Hash()
+	if _, ok := b.hashes[h]; ok {
+		return errors.Errorf("reject existed orphan action: %x", h)
+	}
+	acts := b.acts[sender]
+	if old, ok := acts[act.Nonce()]; ok {
+		if act.GasPrice().Cmp(old.act.GasPrice()) <= 0 {
+			return errors.Wrapf(action.ErrNonce, "duplicate nonce for orphan action %x", h)
+		}
+		delete(b.hashes, old.act.Hash())
+		b.size--
+	} else if uint64(len(acts)) >= b.maxNumPerAcct || b.size >= b.maxNum {
+		return errors.Wrapf(action.ErrActPool, "no room for orphan action %x", h)
+	}
+	if acts == nil {
+		acts = make(map[uint64]orphan)
+		b.acts[sender] = acts
+	}
+	acts[act.Nonce()] = orphan{act: act, deadline: now.Add(b.expiry)}
+	b.hashes[h] = struct{}{}
+	b.size++
+	return nil
+}
+
+// senders returns the senders of the orphans
+func (b *orphanBuffer) senders() []string {
+	senders := make([]string, 0, len(b.acts))
+	for sender := range b.acts {
+		senders = append(senders, sender)
+	}
+	return senders
+}
+
+// take removes the orphans of the sender up to the nonce, in nonce order, and the expired orphans of the sender
+func (b *orphanBuffer) take(sender string, maxNonce uint64, now time.Time) ([]action.SealedEnvelope, []action.SealedEnvelope) {
+	var taken, expired []action.SealedEnvelope
+	acts := b.acts[sender]
+	for nonce, o := range acts {
+		switch {
+		case nonce <= maxNonce:
+			taken = append(taken, o.act)
+		case now.After(o.deadline):
+			expired = append(expired, o.act)
+		default:
+			continue
+		}
+		delete(acts, nonce)
+		delete(b.hashes, o.act.Hash())
+		b.size--
+	}
+	if len(acts) == 0 {
+		delete(b.acts, sender)
+	}
+	sort.Slice(taken, func(i, j int) bool { return taken[i].Nonce() < taken[j].Nonce() })
+	return taken, expired
+}
+
+// len returns the number of orphans
+func (b *orphanBuffer) len() uint64 {
+	return b.size
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestOrphanBuffer(t *testing.T) {
+	require := require.New(t)
+	require.Nil(newOrphanBuffer(0, 1, time.Minute))
+	require.Nil(newOrphanBuffer(1, 0, time.Minute))
+
+	transfer := func(nonce uint64, gasPrice int64) action.SealedEnvelope {
+		tsf, err := testutil.SignedTransfer(addr2, priKey1, nonce, big.NewInt(10), []byte{}, uint64(10000), big.NewInt(gasPrice))
+		require.NoError(err)
+		return tsf
+	}
+	b := newOrphanBuffer(3, 2, time.Minute)
+	now := time.Now()
+	tsf5, tsf7 := transfer(5, 1), transfer(7, 1)
+	require.NoError(b.put(addr1, tsf7, now))
+	require.NoError(b.put(addr1, tsf5, now.Add(time.Minute)))
+	require.Error(b.put(addr1, tsf5, now))
+	require.Equal(action.ErrActPool, errors.Cause(b.put(addr1, transfer(6, 1), now)))
+	// an orphan is replaced by the one of the same nonce and a higher gas price only
+	require.Equal(action.ErrNonce, errors.Cause(b.put(addr1, transfer(5, 0), now)))
+	tsf5 = transfer(5, 2)
+	require.NoError(b.put(addr1, tsf5, now))
+	require.Equal(uint64(2), b.len())
+	require.NoError(b.put(addr2, transfer(9, 1), now))
+	require.Equal(action.ErrActPool, errors.Cause(b.put(addr3, transfer(10, 1), now)))
+	require.ElementsMatch([]string{addr1, addr2}, b.senders())
+
+	taken, expired := b.take(addr1, 4, now)
+	require.Empty(taken)
+	require.Empty(expired)
+	taken, expired = b.take(addr1, 7, now)
+	require.Equal([]action.SealedEnvelope{tsf5, tsf7}, taken)
+	require.Empty(expired)
+	taken, expired = b.take(addr2, 8, now.Add(2*time.Minute))
+	require.Empty(taken)
+	require.Len(expired, 1)
+	require.Zero(b.len())
+	require.Empty(b.senders())
+}
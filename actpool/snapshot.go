@@ -0,0 +1,117 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"sort"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/actpool/actioniterator"
+)
+
+// maxPoolChanges is the number of the latest changes of the pool kept to compute the diffs since a snapshot
+const maxPoolChanges = 1 << 16
+
+var (
+	// ErrSnapshotExpired indicates the changes since a snapshot are no longer kept
+	ErrSnapshotExpired = errors.New("actpool snapshot expired")
+	// ErrUnknownSnapshot indicates a snapshot which is not taken yet
+	ErrUnknownSnapshot = errors.New("unknown actpool snapshot")
+)
+
+type (
+	// ActionDiff is the actions added to and removed from the pool between two snapshots
+	ActionDiff struct {
+		From    uint64
+		To      uint64
+		Added   []action.SealedEnvelope
+		Removed []hash.Hash256
+	}
+
+	// poolChange is an action added to or removed from the pool, the pool is at snapshot seq after the change
+	poolChange struct {
+		seq   uint64
+		hash  hash.Hash256
+		act   action.SealedEnvelope
+		added bool
+	}
+)
+
+// PendingActionIterator returns an iterator of the pending actions of the current snapshot, and the snapshot ID
+func (ap *actPool) PendingActionIterator(opts ...actioniterator.Option) (actioniterator.ActionIterator, uint64) {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	// Remove the actions that are already timeout
+	ap.reset()
+
+	actionMap := make(map[string][]action.SealedEnvelope, len(ap.accountActs))
+	for from, queue := range ap.accountActs {
+		if acts := queue.PendingActs(); len(acts) > 0 {
+			actionMap[from] = acts
+		}
+	}
+	return actioniterator.NewActionIterator(actionMap, opts...), ap.snapshot
+}
+
+// PendingActionDiff returns the actions added to and removed from the pool since a snapshot. An action added and
+// then removed, or the other way around, is in neither.
+func (ap *actPool) PendingActionDiff(snapshot uint64) (*ActionDiff, error) {
+	ap.mutex.RLock()
+	defer ap.mutex.RUnlock()
+
+	if snapshot > ap.snapshot {
+		return nil, errors.Wrapf(ErrUnknownSnapshot, "snapshot %d is after the current snapshot %d", snapshot, ap.snapshot)
+	}
+	if len(ap.changes) > 0 && snapshot+1 < ap.changes[0].seq {
+		return nil, errors.Wrapf(ErrSnapshotExpired, "the changes since snapshot %d are dropped", snapshot)
+	}
+	first := sort.Search(len(ap.changes), func(i int) bool { return ap.changes[i].seq > snapshot })
+	firstAdded := make(map[hash.Hash256]bool)
+	last := make(map[hash.Hash256]int)
+	for i := first; i < len(ap.changes); i++ {
+		c := ap.changes[i]
+		if _, ok := firstAdded[c.hash]; !ok {
+			firstAdded[c.hash] = c.added
+		}
+		last[c.hash] = i
+	}
+	indexes := make([]int, 0, len(last))
+	for h, i := range last {
+		// an action both added and removed is as before the snapshot
+		if firstAdded[h] == ap.changes[i].added {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+	diff := &ActionDiff{From: snapshot, To: ap.snapshot}
+	for _, i := range indexes {
+		if c := ap.changes[i]; c.added {
+			diff.Added = append(diff.Added, c.act)
+		} else {
+			diff.Removed = append(diff.Removed, c.hash)
+		}
+	}
+	return diff, nil
+}
+
+// recordChange records an action added to or removed from the pool, which advances the snapshot
+func (ap *actPool) recordChange(h hash.Hash256, act action.SealedEnvelope, added bool) {
+	ap.snapshot++
+	c := poolChange{seq: ap.snapshot, hash: h, added: added}
+	if added {
+		c.act = act
+	}
+	ap.changes = append(ap.changes, c)
+	if len(ap.changes) > 2*maxPoolChanges {
+		// drop the oldest changes in a batch, instead of on each change
+		ap.changes = append(ap.changes[:0:0], ap.changes[len(ap.changes)-maxPoolChanges:]...)
+	}
+}
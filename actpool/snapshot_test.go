@@ -0,0 +1,99 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestActPool_PendingActionSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	confirmedNonce := uint64(0)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = confirmedNonce
+		acct.Balance = big.NewInt(100000000)
+		return 0, nil
+	}).AnyTimes()
+	Ap, err := NewActPool(sf, getActPoolCfg(), EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+
+	tsf1, err := testutil.SignedTransfer(addr3, priKey1, 1, big.NewInt(10), []byte{}, uint64(10000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr3, priKey2, 1, big.NewInt(10), []byte{}, uint64(10000), big.NewInt(2))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr3, priKey1, 2, big.NewInt(10), []byte{}, uint64(10000), big.NewInt(3))
+	require.NoError(err)
+	require.NoError(ap.Add(context.Background(), tsf1))
+	require.NoError(ap.Add(context.Background(), tsf2))
+
+	iter, snapshot := ap.PendingActionIterator()
+	require.Equal(uint64(2), snapshot)
+	// the later changes of the pool do not affect the iterator
+	require.NoError(ap.Add(context.Background(), tsf3))
+	var acts []action.SealedEnvelope
+	for {
+		act, ok := iter.Next()
+		if !ok {
+			break
+		}
+		acts = append(acts, act)
+	}
+	require.Equal([]action.SealedEnvelope{tsf2, tsf1}, acts)
+
+	confirmedNonce = 1
+	ap.Reset()
+	h1 := tsf1.Hash()
+	h2 := tsf2.Hash()
+	diff, err := ap.PendingActionDiff(snapshot)
+	require.NoError(err)
+	require.Equal(snapshot, diff.From)
+	require.Equal(uint64(5), diff.To)
+	require.Equal([]action.SealedEnvelope{tsf3}, diff.Added)
+	require.ElementsMatch([]hash.Hash256{h1, h2}, diff.Removed)
+	// the actions added and then removed are not in the diff
+	diff, err = ap.PendingActionDiff(0)
+	require.NoError(err)
+	require.Equal([]action.SealedEnvelope{tsf3}, diff.Added)
+	require.Empty(diff.Removed)
+	diff, err = ap.PendingActionDiff(5)
+	require.NoError(err)
+	require.Empty(diff.Added)
+	require.Empty(diff.Removed)
+
+	_, err = ap.PendingActionDiff(6)
+	require.Equal(ErrUnknownSnapshot, errors.Cause(err))
+	for i := 0; i < 2*maxPoolChanges; i++ {
+		ap.recordChange(hash.ZeroHash256, action.SealedEnvelope{}, false)
+	}
+	require.True(len(ap.changes) <= 2*maxPoolChanges)
+	_, err = ap.PendingActionDiff(snapshot)
+	require.Equal(ErrSnapshotExpired, errors.Cause(err))
+	_, err = ap.PendingActionDiff(ap.snapshot - maxPoolChanges)
+	require.NoError(err)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actsync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/go-pkgs/hash"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+)
+
+// requestedCacheSize is the number of the latest requested actions to remember
+const requestedCacheSize = 65536
+
+type (
+	// AnnounceOutbound broadcasts the hashes of actions
+	AnnounceOutbound func(ctx context.Context, hashes []hash.Hash256) error
+	// RequestOutbound requests a peer for the actions of the hashes
+	RequestOutbound func(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error
+	// UnicastOutbound sends a unicast message to the given peer
+	UnicastOutbound func(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error
+)
+
+// ActionSync exchanges the pending actions with peers by their hashes. The node announces the hashes of its new
+// actions to its neighbors, which request the actions missing in their pools and announce them in turn, instead of
+// everyone receiving every action.
+type ActionSync struct {
+	cfg             config.ActionSync
+	ap              actpool.ActPool
+	announceHandler AnnounceOutbound
+	requestHandler  RequestOutbound
+	unicastHandler  UnicastOutbound
+	requested       *cache.ThreadSafeLruCache
+	mu              sync.Mutex
+	pending         []hash.Hash256
+	announceTask    *routine.RecurringTask
+}
+
+var _ lifecycle.StartStopper = (*ActionSync)(nil)
+
+// NewActionSync creates an action sync
+func NewActionSync(
+	cfg config.ActionSync,
+	ap actpool.ActPool,
+	announceHandler AnnounceOutbound,
+	requestHandler RequestOutbound,
+	unicastHandler UnicastOutbound,
+) *ActionSync {
+	as := &ActionSync{
+		cfg:             cfg,
+		ap:              ap,
+		announceHandler: announceHandler,
+		requestHandler:  requestHandler,
+		unicastHandler:  unicastHandler,
+		requested:       cache.NewThreadSafeLruCache(requestedCacheSize),
+	}
+	as.announceTask = routine.NewRecurringTask(as.flush, cfg.AnnounceInterval)
+	return as
+}
+
+// Start starts the action sync
+func (as *ActionSync) Start(ctx context.Context) error {
+	return as.announceTask.Start(ctx)
+}
+
+// Stop stops the action sync
+func (as *ActionSync) Stop(ctx context.Context) error {
+	return as.announceTask.Stop(ctx)
+}
+
+// Announce queues the hash of a new action to announce
+func (as *ActionSync) Announce(h hash.Hash256) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.pending = append(as.pending, h)
+}
+
+// ReceiveAction announces an action added to the pool, if it is requested from a peer
+func (as *ActionSync) ReceiveAction(h hash.Hash256) {
+	if _, ok := as.requested.Get(h); !ok {
+		return
+	}
+	as.requested.Remove(h)
+	as.Announce(h)
+}
+
+// HandleAnnouncement requests the announced actions which are neither in the pool nor requested lately
+func (as *ActionSync) HandleAnnouncement(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error {
+	var missing []hash.Hash256
+	now := time.Now()
+	for _, h := range hashes {
+		if _, err := as.ap.GetActionByHash(h); err == nil {
+			continue
+		}
+		if requested, ok := as.requested.Get(h); ok && now.Sub(requested.(time.Time)) < as.cfg.RequestTTL {
+			continue
+		}
+		as.requested.Add(h, now)
+		missing = append(missing, h)
+	}
+	for len(missing) > 0 {
+		n := len(missing)
+		if n > as.cfg.MaxHashesPerMsg {
+			n = as.cfg.MaxHashesPerMsg
+		}
+		if err := as.requestHandler(ctx, peer, missing[:n]); err != nil {
+			// the actions can be requested from another peer announcing them
+			for _, h := range missing {
+				as.requested.Remove(h)
+			}
+			return errors.Wrapf(err, "failed to request actions from %s", peer.ID.Pretty())
+		}
+		missing = missing[n:]
+	}
+	return nil
+}
+
+// HandleRequest sends the requested actions in the pool to the peer
+func (as *ActionSync) HandleRequest(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error {
+	if len(hashes) > as.cfg.MaxHashesPerMsg {
+		hashes = hashes[:as.cfg.MaxHashesPerMsg]
+	}
+	for _, h := range hashes {
+		selp, err := as.ap.GetActionByHash(h)
+		if err != nil {
+			continue
+		}
+		if err := as.unicastHandler(ctx, peer, selp.Proto()); err != nil {
+			return errors.Wrapf(err, "failed to send action to %s", peer.ID.Pretty())
+		}
+	}
+	return nil
+}
+
+// flush announces the queued hashes
+func (as *ActionSync) flush() {
+	as.mu.Lock()
+	pending := as.pending
+	as.pending = nil
+	as.mu.Unlock()
+
+	for len(pending) > 0 {
+		n := len(pending)
+		if n > as.cfg.MaxHashesPerMsg {
+			n = as.cfg.MaxHashesPerMsg
+		}
+		if err := as.announceHandler(context.Background(), pending[:n]); err != nil {
+			log.L().Warn("Failed to announce actions.", zap.Int("num", n), zap.Error(err))
+		}
+		pending = pending[n:]
+	}
+}
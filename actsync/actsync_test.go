@@ -0,0 +1,122 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actsync
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestActionSync(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	selp, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(2), 1, big.NewInt(1), nil, 100000, big.NewInt(0))
+	require.NoError(err)
+	inPool := selp.Hash()
+	missing1, missing2, missing3 := hash.Hash256b([]byte("1")), hash.Hash256b([]byte("2")), hash.Hash256b([]byte("3"))
+	ap := mock_actpool.NewMockActPool(ctrl)
+	ap.EXPECT().GetActionByHash(gomock.Any()).DoAndReturn(func(h hash.Hash256) (action.SealedEnvelope, error) {
+		if h == inPool {
+			return selp, nil
+		}
+		return action.SealedEnvelope{}, errors.New("not found")
+	}).AnyTimes()
+
+	var (
+		mu        sync.Mutex
+		announced [][]hash.Hash256
+		requested [][]hash.Hash256
+		sent      []proto.Message
+		failing   bool
+	)
+	cfg := config.Default.ActionSync
+	cfg.AnnounceInterval = 10 * time.Millisecond
+	cfg.MaxHashesPerMsg = 2
+	as := NewActionSync(
+		cfg,
+		ap,
+		func(_ context.Context, hashes []hash.Hash256) error {
+			mu.Lock()
+			defer mu.Unlock()
+			announced = append(announced, hashes)
+			return nil
+		},
+		func(_ context.Context, _ peerstore.PeerInfo, hashes []hash.Hash256) error {
+			if failing {
+				return errors.New("unreachable")
+			}
+			requested = append(requested, hashes)
+			return nil
+		},
+		func(_ context.Context, _ peerstore.PeerInfo, msg proto.Message) error {
+			sent = append(sent, msg)
+			return nil
+		},
+	)
+	ctx := context.Background()
+	require.NoError(as.Start(ctx))
+	defer func() { require.NoError(as.Stop(ctx)) }()
+
+	// the hashes are announced in batches
+	for _, h := range []hash.Hash256{missing1, missing2, missing3} {
+		as.Announce(h)
+	}
+	require.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(announced) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.Equal([][]hash.Hash256{{missing1, missing2}, {missing3}}, announced)
+	mu.Unlock()
+
+	// only the actions missing in the pool are requested, and not again before the request expires
+	require.NoError(as.HandleAnnouncement(ctx, peerstore.PeerInfo{}, []hash.Hash256{inPool, missing1}))
+	require.NoError(as.HandleAnnouncement(ctx, peerstore.PeerInfo{}, []hash.Hash256{missing1}))
+	require.Equal([][]hash.Hash256{{missing1}}, requested)
+	// a failed request can be retried
+	failing = true
+	require.Error(as.HandleAnnouncement(ctx, peerstore.PeerInfo{}, []hash.Hash256{missing2}))
+	failing = false
+	require.NoError(as.HandleAnnouncement(ctx, peerstore.PeerInfo{}, []hash.Hash256{missing2, missing3, inPool}))
+	require.Equal([][]hash.Hash256{{missing1}, {missing2, missing3}}, requested)
+
+	// the requested actions are announced in turn once received
+	as.ReceiveAction(missing1)
+	as.ReceiveAction(inPool)
+	require.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(announced) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.Equal([]hash.Hash256{missing1}, announced[2])
+	mu.Unlock()
+
+	// only the actions in the pool are sent back
+	require.NoError(as.HandleRequest(ctx, peerstore.PeerInfo{}, []hash.Hash256{missing1, inPool}))
+	require.Len(sent, 1)
+	require.True(proto.Equal(selp.Proto(), sent[0]))
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/api/apipb"
+)
+
+type (
+	// ActPoolAccount is the actions in pool of a sender
+	ActPoolAccount struct {
+		Address string
+		// NextNonce is the nonce following the pending actions
+		NextNonce uint64
+		// Pending are the actions which can be executed in nonce order
+		Pending []action.SealedEnvelope
+		// Queued are the actions held back by missing nonces
+		Queued []action.SealedEnvelope
+		// NonceGaps are the missing nonces before the queued actions
+		NonceGaps []NonceRange
+	}
+
+	// NonceRange is the nonces from From to To, both inclusive
+	NonceRange struct {
+		From uint64
+		To   uint64
+	}
+
+	// ActPoolStatus is the counts and the sizes of the actions in pool
+	ActPoolStatus struct {
+		Pending     uint64
+		Queued      uint64
+		Size        uint64
+		Gas         uint64
+		Capacity    uint64
+		GasCapacity uint64
+	}
+)
+
+// GetActPoolContent returns the actions in pool grouped by sender, with the nonce gaps of each sender
+func (api *Server) GetActPoolContent(ctx context.Context, in *apipb.GetActPoolContentRequest) (*apipb.GetActPoolContentResponse, error) {
+	accounts, err := api.actPoolContent(ctx, in.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	res := &apipb.GetActPoolContentResponse{Accounts: make([]*apipb.ActPoolAccount, 0, len(accounts))}
+	for _, account := range accounts {
+		pb := &apipb.ActPoolAccount{
+			Address:   account.Address,
+			NextNonce: account.NextNonce,
+		}
+		for _, selp := range account.Pending {
+			pb.Pending = append(pb.Pending, selp.Proto())
+		}
+		for _, selp := range account.Queued {
+			pb.Queued = append(pb.Queued, selp.Proto())
+		}
+		for _, gap := range account.NonceGaps {
+			pb.NonceGaps = append(pb.NonceGaps, &apipb.NonceRange{From: gap.From, To: gap.To})
+		}
+		res.Accounts = append(res.Accounts, pb)
+	}
+	return res, nil
+}
+
+// GetActPoolStatus returns the counts and the sizes of the actions in pool
+func (api *Server) GetActPoolStatus(ctx context.Context, in *apipb.GetActPoolStatusRequest) (*apipb.GetActPoolStatusResponse, error) {
+	status, err := api.actPoolStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &apipb.GetActPoolStatusResponse{
+		Pending:     status.Pending,
+		Queued:      status.Queued,
+		Size:        status.Size,
+		Gas:         status.Gas,
+		Capacity:    status.Capacity,
+		GasCapacity: status.GasCapacity,
+	}, nil
+}
+
+// actPoolContent returns the actions in pool grouped by sender, of the given sender if addr is not empty
+func (api *Server) actPoolContent(ctx context.Context, addr string) ([]*ActPoolAccount, error) {
+	if addr != "" {
+		addr = normalizeAddress(addr)
+		if _, err := address.FromString(addr); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	content := api.ap.Content()
+	accounts := make([]*ActPoolAccount, 0, len(content))
+	for from, c := range content {
+		if addr != "" && from != addr {
+			continue
+		}
+		account := &ActPoolAccount{
+			Address:   from,
+			NextNonce: c.NextNonce,
+			Pending:   c.Pending,
+			Queued:    c.Queued,
+		}
+		next := c.NextNonce
+		for _, selp := range c.Queued {
+			if selp.Nonce() > next {
+				account.NonceGaps = append(account.NonceGaps, NonceRange{From: next, To: selp.Nonce() - 1})
+			}
+			next = selp.Nonce() + 1
+		}
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Address < accounts[j].Address
+	})
+	return accounts, nil
+}
+
+// actPoolStatus returns the counts and the sizes of the actions in pool
+func (api *Server) actPoolStatus(ctx context.Context) (*ActPoolStatus, error) {
+	ret := &ActPoolStatus{
+		Gas:         api.ap.GetGasSize(),
+		Capacity:    api.ap.GetCapacity(),
+		GasCapacity: api.ap.GetGasCapacity(),
+	}
+	for _, c := range api.ap.Content() {
+		ret.Pending += uint64(len(c.Pending))
+		ret.Queued += uint64(len(c.Queued))
+		for _, acts := range [][]action.SealedEnvelope{c.Pending, c.Queued} {
+			for _, selp := range acts {
+				ret.Size += uint64(proto.Size(selp.Proto()))
+			}
+		}
+	}
+	return ret, nil
+}
+
+// GetActionDropReason returns the reason a recently dropped action left the actpool before being mined
+func (api *Server) GetActionDropReason(ctx context.Context, actHash string) (string, error) {
+	h, err := hash.HexStringToHash256(actHash)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	reason, err := api.ap.GetDropReason(h)
+	if err != nil {
+		return "", status.Error(codes.NotFound, err.Error())
+	}
+	return reason, nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetActPoolContent(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	sender := identityset.Address(27).String()
+	nonce, err := svr.ap.GetPendingNonce(sender)
+	require.NoError(err)
+	var acts []action.SealedEnvelope
+	// the action of nonce+1 is missing
+	for _, n := range []uint64{nonce, nonce + 2, nonce + 3} {
+		tsf, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), n,
+			big.NewInt(20), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+		require.NoError(err)
+		require.NoError(svr.ap.Add(ctx, tsf))
+		acts = append(acts, tsf)
+	}
+
+	accounts, err := svr.actPoolContent(ctx, sender)
+	require.NoError(err)
+	require.Len(accounts, 1)
+	require.Equal(sender, accounts[0].Address)
+	require.Equal(nonce+1, accounts[0].NextNonce)
+	require.Equal(acts[:1], accounts[0].Pending)
+	require.Equal(acts[1:], accounts[0].Queued)
+	require.Equal([]NonceRange{{From: nonce + 1, To: nonce + 1}}, accounts[0].NonceGaps)
+	_, err = svr.GetActPoolContent(ctx, &apipb.GetActPoolContentRequest{Address: "invalid"})
+	require.Equal(codes.InvalidArgument, grpcstatus.Code(err))
+	content, err := svr.GetActPoolContent(ctx, &apipb.GetActPoolContentRequest{Address: identityset.Address(29).String()})
+	require.NoError(err)
+	require.Empty(content.Accounts)
+	content, err = svr.GetActPoolContent(ctx, &apipb.GetActPoolContentRequest{})
+	require.NoError(err)
+	require.Len(content.Accounts, 1)
+	require.Equal(sender, content.Accounts[0].Address)
+	require.Len(content.Accounts[0].Pending, 1)
+	require.Len(content.Accounts[0].Queued, 2)
+	require.Equal(acts[0].Proto(), content.Accounts[0].Pending[0])
+	require.Equal([]*apipb.NonceRange{{From: nonce + 1, To: nonce + 1}}, content.Accounts[0].NonceGaps)
+
+	status, err := svr.GetActPoolStatus(ctx, &apipb.GetActPoolStatusRequest{})
+	require.NoError(err)
+	require.EqualValues(1, status.Pending)
+	require.EqualValues(2, status.Queued)
+	require.NotZero(status.Size)
+	require.Equal(svr.ap.GetGasSize(), status.Gas)
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		ethAddr := common.BytesToAddress(identityset.Address(27).Bytes()).Hex()
+		call := func(method string, params ...interface{}) map[string]interface{} {
+			res := web3Call(t, web3, method, params...)
+			require.Nil(res.Error)
+			var result map[string]interface{}
+			require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+			return result
+		}
+		require.Equal(map[string]interface{}{"pending": "0x1", "queued": "0x2"}, call("txpool_status"))
+
+		content := call("txpool_content")
+		require.Contains(content["pending"], ethAddr)
+		require.Contains(content["queued"].(map[string]interface{})[ethAddr], strconv.FormatUint(nonce+3, 10))
+
+		summary := call("txpool_inspect")["pending"].(map[string]interface{})[ethAddr].(map[string]interface{})
+		require.Contains(summary[strconv.FormatUint(nonce, 10)], "20 wei + ")
+
+		from := call("txpool_contentFrom", ethAddr)
+		require.Equal([]interface{}{map[string]interface{}{
+			"from": hexUint64(nonce + 1),
+			"to":   hexUint64(nonce + 1),
+		}}, from["nonceGaps"])
+		require.Equal(hexUint64(nonce+1), from["nextNonce"])
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		replacement, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), nonce+3,
+			big.NewInt(20), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64+1))
+		require.NoError(err)
+		require.NoError(svr.ap.Add(ctx, replacement))
+		dropped := acts[2].Hash()
+		reason, err := svr.GetActionDropReason(ctx, hex.EncodeToString(dropped[:]))
+		require.NoError(err)
+		require.Equal(actpool.DropReplaced, reason)
+		kept := replacement.Hash()
+		_, err = svr.GetActionDropReason(ctx, hex.EncodeToString(kept[:]))
+		require.Equal(codes.NotFound, grpcstatus.Code(err))
+
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "txpool_dropReason", "0x"+hex.EncodeToString(dropped[:]))
+		require.Nil(res.Error)
+		require.JSONEq(`"replaced"`, string(res.Result.(json.RawMessage)))
+		res = web3Call(t, web3, "txpool_dropReason", "0x"+hex.EncodeToString(kept[:]))
+		require.Nil(res.Error)
+		require.Equal("null", string(res.Result.(json.RawMessage)))
+	})
+
+	t.Run("min gas price", func(t *testing.T) {
+		require.Equal(svr.ap.MinGasPrice(), svr.gs.MinGasPrice())
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "iotex_minGasPrice")
+		require.Nil(res.Error)
+		require.JSONEq(`"`+hexutil.EncodeBig(svr.ap.MinGasPrice())+`"`, string(res.Result.(json.RawMessage)))
+	})
+}
+
+func hexUint64(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	// AddressFormatBoth is the address format of the rest responses which carry each io address along with its 0x
+	// hex encoding, in a sibling field suffixed with _hex
+	AddressFormatBoth = "both"
+
+	// hexAddressFieldSuffix is the suffix of the field carrying the 0x hex encoding of an address
+	hexAddressFieldSuffix = "_hex"
+
+	// AddressFormatMetadataKey is the grpc metadata key of the address format of the responses. With AddressFormatBoth,
+	// the 0x hex encoding of each io address of a unary response is returned in the header metadata.
+	AddressFormatMetadataKey = "x-address-format"
+	// HexAddressesMetadataKey is the grpc header metadata key carrying the io addresses of a response along with their
+	// 0x hex encoding, each as "<io address>=<hex address>"
+	HexAddressesMetadataKey = "x-hex-addresses"
+)
+
+// signedActionMessage is the message of a signed action, whose addresses are covered by the signature and never
+// rewritten
+const signedActionMessage protoreflect.FullName = "iotextypes.Action"
+
+type addressStream struct {
+	grpc.ServerStream
+}
+
+// isHexAddress tells whether s is a 0x-prefixed hex address
+func isHexAddress(s string) bool {
+	return (strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")) && common.IsHexAddress(s)
+}
+
+// parseAddress parses an io address, or a 0x-prefixed hex address
+func parseAddress(s string) (address.Address, error) {
+	if isHexAddress(s) {
+		return address.FromBytes(common.HexToAddress(s).Bytes())
+	}
+	return address.FromString(s)
+}
+
+// normalizeAddress converts a 0x-prefixed hex address into an io address, other strings are returned as is
+func normalizeAddress(s string) string {
+	if !isHexAddress(s) {
+		return s
+	}
+	addr, err := parseAddress(s)
+	if err != nil {
+		return s
+	}
+	return addr.String()
+}
+
+// isAddressField tells whether a field of a request carries an address, by its name
+func isAddressField(fd protoreflect.FieldDescriptor) bool {
+	if fd.Kind() != protoreflect.StringKind {
+		return false
+	}
+	name := strings.ToLower(string(fd.Name()))
+	return name == "contract" || name == "recipient" || strings.HasSuffix(name, "address")
+}
+
+// normalizeAddresses converts the 0x hex addresses of the address fields of a request into io addresses, so that
+// the methods taking io addresses accept both formats. The signed actions are left untouched.
+func normalizeAddresses(m proto.Message) {
+	if m == nil {
+		return
+	}
+	normalizeMessage(proto.MessageReflect(m))
+}
+
+func normalizeMessage(m protoreflect.Message) {
+	if m.Descriptor().FullName() == signedActionMessage {
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				normalizeMessage(list.Get(i).Message())
+			}
+		case fd.IsList() && isAddressField(fd):
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, protoreflect.ValueOfString(normalizeAddress(list.Get(i).String())))
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			normalizeMessage(v.Message())
+		case isAddressField(fd):
+			m.Set(fd, protoreflect.ValueOfString(normalizeAddress(v.String())))
+		}
+		return true
+	})
+}
+
+// addressUnaryInterceptor normalizes the addresses of the unary grpc requests, and returns the hex encoding of the
+// addresses of the response if the client asks for both formats
+func addressUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if msg, ok := req.(proto.Message); ok {
+		normalizeAddresses(msg)
+	}
+	resp, err := handler(ctx, req)
+	if err != nil || !wantsHexAddresses(ctx) {
+		return resp, err
+	}
+	if msg, ok := resp.(proto.Message); ok && msg != nil {
+		if pairs := hexAddressPairs(msg); len(pairs) > 0 {
+			if err := grpc.SetHeader(ctx, metadata.MD{HexAddressesMetadataKey: pairs}); err != nil {
+				log.L().Warn("failed to set the hex addresses of the response.", zap.Error(err))
+			}
+		}
+	}
+	return resp, nil
+}
+
+// wantsHexAddresses tells whether the grpc client asks for the addresses in both formats
+func wantsHexAddresses(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, format := range md.Get(AddressFormatMetadataKey) {
+		if format == AddressFormatBoth {
+			return true
+		}
+	}
+	return false
+}
+
+// hexAddressPairs returns each io address of a message, once, along with its hex encoding as "<io address>=<hex address>"
+func hexAddressPairs(m proto.Message) []string {
+	var (
+		pairs []string
+		seen  = make(map[string]bool)
+		add   = func(s string) {
+			if hex, ok := ioToHexAddress(s); ok && !seen[s] {
+				seen[s] = true
+				pairs = append(pairs, s+"="+hex)
+			}
+		}
+		walk func(protoreflect.Message)
+	)
+	walk = func(m protoreflect.Message) {
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			switch {
+			case fd.IsMap():
+			case fd.IsList():
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					switch fd.Kind() {
+					case protoreflect.MessageKind:
+						walk(list.Get(i).Message())
+					case protoreflect.StringKind:
+						add(list.Get(i).String())
+					}
+				}
+			case fd.Kind() == protoreflect.MessageKind:
+				walk(v.Message())
+			case fd.Kind() == protoreflect.StringKind:
+				add(v.String())
+			}
+			return true
+		})
+	}
+	walk(proto.MessageReflect(m))
+	return pairs
+}
+
+// addressStreamInterceptor normalizes the addresses of the messages received over grpc streams
+func addressStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &addressStream{ServerStream: ss})
+}
+
+// RecvMsg receives a message and normalizes its addresses
+func (s *addressStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		normalizeAddresses(msg)
+	}
+	return nil
+}
+
+// withHexAddresses adds the 0x hex encoding of each io address of a json response in a sibling field
+func withHexAddresses(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		hexFields := make(map[string]interface{})
+		for key, value := range v {
+			switch value := value.(type) {
+			case string:
+				if hex, ok := ioToHexAddress(value); ok {
+					hexFields[key+hexAddressFieldSuffix] = hex
+				}
+			case []interface{}:
+				if hexes, ok := ioToHexAddresses(value); ok {
+					hexFields[key+hexAddressFieldSuffix] = hexes
+				} else {
+					withHexAddresses(value)
+				}
+			default:
+				withHexAddresses(value)
+			}
+		}
+		for key, value := range hexFields {
+			if _, ok := v[key]; !ok {
+				v[key] = value
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			withHexAddresses(value)
+		}
+	}
+}
+
+func ioToHexAddress(s string) (string, bool) {
+	if !strings.HasPrefix(s, address.MainnetPrefix) && !strings.HasPrefix(s, address.TestnetPrefix) {
+		return "", false
+	}
+	addr, err := address.FromString(s)
+	if err != nil {
+		return "", false
+	}
+	return common.BytesToAddress(addr.Bytes()).Hex(), true
+}
+
+// ioToHexAddresses converts a list of io addresses, it fails if any of the values is not an io address
+func ioToHexAddresses(values []interface{}) ([]interface{}, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+	hexes := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		hex, ok := ioToHexAddress(s)
+		if !ok {
+			return nil, false
+		}
+		hexes = append(hexes, hex)
+	}
+	return hexes, true
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestAddressFormats(t *testing.T) {
+	require := require.New(t)
+	ioAddr := identityset.Address(27).String()
+	hexAddr := common.BytesToAddress(identityset.Address(27).Bytes()).Hex()
+
+	for _, s := range []string{ioAddr, hexAddr} {
+		addr, err := parseAddress(s)
+		require.NoError(err)
+		require.Equal(ioAddr, addr.String())
+		require.Equal(ioAddr, normalizeAddress(s))
+	}
+	_, err := parseAddress("0x1234")
+	require.Error(err)
+	// strings which are not hex addresses are kept
+	require.Equal("0x1234", normalizeAddress("0x1234"))
+	require.Equal(hexAddr[2:], normalizeAddress(hexAddr[2:]))
+
+	t.Run("requests", func(t *testing.T) {
+		read := &iotexapi.ReadContractRequest{
+			Execution:     &iotextypes.Execution{Contract: hexAddr, Amount: "0"},
+			CallerAddress: hexAddr,
+		}
+		res, err := addressUnaryInterceptor(context.Background(), read, &grpc.UnaryServerInfo{}, func(_ context.Context, req interface{}) (interface{}, error) {
+			return req, nil
+		})
+		require.NoError(err)
+		require.Equal(ioAddr, res.(*iotexapi.ReadContractRequest).CallerAddress)
+		require.Equal(ioAddr, res.(*iotexapi.ReadContractRequest).Execution.Contract)
+
+		logs := &iotexapi.GetLogsRequest{Filter: &iotexapi.LogsFilter{Address: []string{hexAddr, ioAddr}}}
+		normalizeAddresses(logs)
+		require.Equal([]string{ioAddr, ioAddr}, logs.Filter.Address)
+
+		// the addresses of a signed action are covered by its signature
+		send := &iotexapi.SendActionRequest{Action: &iotextypes.Action{
+			Core: &iotextypes.ActionCore{Action: &iotextypes.ActionCore_Transfer{
+				Transfer: &iotextypes.Transfer{Recipient: hexAddr},
+			}},
+		}}
+		normalizeAddresses(send)
+		require.Equal(hexAddr, send.Action.Core.GetTransfer().Recipient)
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		var v interface{}
+		require.NoError(json.Unmarshal([]byte(`{"address":"`+ioAddr+`","nested":[{"owner":"`+ioAddr+`","amount":"1"}],"list":["`+ioAddr+`"],"name":"io"}`), &v))
+		withHexAddresses(v)
+		m := v.(map[string]interface{})
+		require.Equal(hexAddr, m["address_hex"])
+		require.Equal([]interface{}{hexAddr}, m["list_hex"])
+		require.Equal(hexAddr, m["nested"].([]interface{})[0].(map[string]interface{})["owner_hex"])
+		require.NotContains(m, "name_hex")
+		require.NotContains(m["nested"].([]interface{})[0], "amount_hex")
+
+		logs := &iotexapi.GetLogsRequest{Filter: &iotexapi.LogsFilter{Address: []string{ioAddr, ioAddr}, Topics: []*iotexapi.Topics{}}}
+		require.Equal([]string{ioAddr + "=" + hexAddr}, hexAddressPairs(logs))
+		require.Empty(hexAddressPairs(&iotexapi.GetChainMetaResponse{}))
+	})
+
+	t.Run("servers", func(t *testing.T) {
+		cfg := newConfig(t)
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+
+		rest := startRESTServer(t, svr)
+		defer func() {
+			rest.Stop(context.Background())
+		}()
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accounts/"+hexAddr+"?addressFormat=both", nil))
+		require.Equal(http.StatusOK, rec.Code)
+		var account struct {
+			AccountMeta struct {
+				Address    string `json:"address"`
+				AddressHex string `json:"address_hex"`
+			} `json:"accountMeta"`
+		}
+		require.NoError(json.Unmarshal(rec.Body.Bytes(), &account))
+		require.Equal(ioAddr, account.AccountMeta.Address)
+		require.Equal(hexAddr, account.AccountMeta.AddressHex)
+
+		// the grpc response carries the hex addresses in its header if asked for
+		conn := dialGRPCServer(t, svr)
+		defer svr.grpcServer.Stop()
+		defer conn.Close()
+		client := iotexapi.NewAPIServiceClient(conn)
+		for _, format := range []string{"", AddressFormatBoth} {
+			var header metadata.MD
+			ctx := metadata.AppendToOutgoingContext(context.Background(), AddressFormatMetadataKey, format)
+			res, err := client.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: hexAddr}, grpc.Header(&header))
+			require.NoError(err)
+			require.Equal(ioAddr, res.AccountMeta.Address)
+			if format == AddressFormatBoth {
+				require.Equal([]string{ioAddr + "=" + hexAddr}, header.Get(HexAddressesMetadataKey))
+			} else {
+				require.Empty(header.Get(HexAddressesMetadataKey))
+			}
+		}
+
+		// web3 takes io addresses as well
+		web3 := NewWeb3Server(svr, 0)
+		var balances [2]hexutil.Big
+		for i, addr := range []string{hexAddr, ioAddr} {
+			res := web3Call(t, web3, "eth_getBalance", addr, "latest")
+			require.Nil(res.Error)
+			require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &balances[i]))
+		}
+		require.Equal(balances[0].ToInt(), balances[1].ToInt())
+	})
+}
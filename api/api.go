@@ -14,17 +14,20 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
@@ -37,9 +40,13 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
 	"github.com/iotexproject/iotex-core/action/protocol/poll"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/staking"
 	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/actpool/denylist"
+	"github.com/iotexproject/iotex-core/api/apipb"
 	logfilter "github.com/iotexproject/iotex-core/api/logfilter"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
@@ -48,6 +55,7 @@ import (
 	"github.com/iotexproject/iotex-core/blockindex"
 	"github.com/iotexproject/iotex-core/blocksync"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/gasstation"
 	"github.com/iotexproject/iotex-core/pkg/log"
@@ -63,15 +71,29 @@ var (
 	ErrReceipt = errors.New("invalid receipt")
 	// ErrAction indicates the error of action
 	ErrAction = errors.New("invalid action")
+	// ErrHeight indicates the error of a queried height
+	ErrHeight = errors.New("invalid height")
 )
 
 // BroadcastOutbound sends a broadcast message to the whole network
 type BroadcastOutbound func(ctx context.Context, chainID uint32, msg proto.Message) error
 
+// PrivateRelay sends a message to the current delegates only, instead of broadcasting it to the whole network
+type PrivateRelay func(ctx context.Context, chainID uint32, msg proto.Message) error
+
+// Neighbors returns the connected peers
+type Neighbors func(ctx context.Context) ([]peerstore.PeerInfo, error)
+
 // Config represents the config to setup api
 type Config struct {
-	broadcastHandler  BroadcastOutbound
-	electionCommittee committee.Committee
+	broadcastHandler   BroadcastOutbound
+	privateRelay       PrivateRelay
+	electionCommittee  committee.Committee
+	neighbors          Neighbors
+	denylist           *denylist.Denylist
+	candBucketsIndexer *staking.CandidatesBucketsIndexer
+	evidenceReader     scheme.EvidenceReader
+	roundHistoryReader scheme.RoundHistoryReader
 }
 
 // Option is the option to override the api config
@@ -85,6 +107,14 @@ func WithBroadcastOutbound(broadcastHandler BroadcastOutbound) Option {
 	}
 }
 
+// WithPrivateRelay is the option to relay the private actions to the current delegates
+func WithPrivateRelay(privateRelay PrivateRelay) Option {
+	return func(cfg *Config) error {
+		cfg.privateRelay = privateRelay
+		return nil
+	}
+}
+
 // WithNativeElection is the option to return native election data through API.
 func WithNativeElection(committee committee.Committee) Option {
 	return func(cfg *Config) error {
@@ -93,23 +123,77 @@ func WithNativeElection(committee committee.Committee) Option {
 	}
 }
 
+// WithNeighbors is the option to report the peer count in the sync status
+func WithNeighbors(neighbors Neighbors) Option {
+	return func(cfg *Config) error {
+		cfg.neighbors = neighbors
+		return nil
+	}
+}
+
+// WithDenylist is the option to reject the submitted actions denied by the denylist
+func WithDenylist(d *denylist.Denylist) Option {
+	return func(cfg *Config) error {
+		cfg.denylist = d
+		return nil
+	}
+}
+
+// WithCandidatesBucketsIndexer is the option to return the staking snapshots of the past epochs
+func WithCandidatesBucketsIndexer(cbi *staking.CandidatesBucketsIndexer) Option {
+	return func(cfg *Config) error {
+		cfg.candBucketsIndexer = cbi
+		return nil
+	}
+}
+
+// WithEvidenceReader is the option to return the evidences of the equivocation of the delegates
+func WithEvidenceReader(r scheme.EvidenceReader) Option {
+	return func(cfg *Config) error {
+		cfg.evidenceReader = r
+		return nil
+	}
+}
+
+// WithRoundHistoryReader is the option to return the histories of the recent consensus rounds
+func WithRoundHistoryReader(r scheme.RoundHistoryReader) Option {
+	return func(cfg *Config) error {
+		cfg.roundHistoryReader = r
+		return nil
+	}
+}
+
 // Server provides api for user to query blockchain data
 type Server struct {
-	bc                blockchain.Blockchain
-	bs                blocksync.BlockSync
-	sf                factory.Factory
-	dao               blockdao.BlockDAO
-	indexer           blockindex.Indexer
-	bfIndexer         blockindex.BloomFilterIndexer
-	ap                actpool.ActPool
-	gs                *gasstation.GasStation
-	broadcastHandler  BroadcastOutbound
-	cfg               config.Config
-	registry          *protocol.Registry
-	chainListener     Listener
-	grpcServer        *grpc.Server
-	hasActionIndex    bool
-	electionCommittee committee.Committee
+	bc                 blockchain.Blockchain
+	bs                 blocksync.BlockSync
+	sf                 factory.Factory
+	dao                blockdao.BlockDAO
+	indexer            blockindex.Indexer
+	bfIndexer          blockindex.BloomFilterIndexer
+	ap                 actpool.ActPool
+	gs                 *gasstation.GasStation
+	broadcastHandler   BroadcastOutbound
+	privateRelay       PrivateRelay
+	cfg                config.Config
+	registry           *protocol.Registry
+	chainListener      Listener
+	grpcServer         *grpc.Server
+	web3Server         *Web3Server
+	restServer         *RESTServer
+	graphQLServer      *GraphQLServer
+	limiter            *rateLimiter
+	auth               *authenticator
+	observer           *methodObserver
+	cache              *responseCache
+	hasActionIndex     bool
+	electionCommittee  committee.Committee
+	neighbors          Neighbors
+	denylist           *denylist.Denylist
+	candBucketsIndexer *staking.CandidatesBucketsIndexer
+	evidenceReader     scheme.EvidenceReader
+	roundHistoryReader scheme.RoundHistoryReader
+	startingHeight     uint64
 }
 
 // NewServer creates a new server
@@ -132,7 +216,7 @@ func NewServer(
 		}
 	}
 
-	if cfg.API == (config.API{}) {
+	if reflect.DeepEqual(cfg.API, config.API{}) {
 		log.L().Warn("API server is not configured.")
 		cfg.API = config.Default.API
 	}
@@ -142,34 +226,110 @@ func NewServer(
 	}
 
 	svr := &Server{
-		bc:                chain,
-		bs:                bs,
-		sf:                sf,
-		dao:               dao,
-		indexer:           indexer,
-		bfIndexer:         bfIndexer,
-		ap:                actPool,
-		broadcastHandler:  apiCfg.broadcastHandler,
-		cfg:               cfg,
-		registry:          registry,
-		chainListener:     NewChainListener(),
-		gs:                gasstation.NewGasStation(chain, sf.SimulateExecution, dao, cfg.API),
-		electionCommittee: apiCfg.electionCommittee,
-	}
+		bc:                 chain,
+		bs:                 bs,
+		sf:                 sf,
+		dao:                dao,
+		indexer:            indexer,
+		bfIndexer:          bfIndexer,
+		ap:                 actPool,
+		broadcastHandler:   apiCfg.broadcastHandler,
+		privateRelay:       apiCfg.privateRelay,
+		cfg:                cfg,
+		registry:           registry,
+		chainListener:      NewChainListener(),
+		electionCommittee:  apiCfg.electionCommittee,
+		neighbors:          apiCfg.neighbors,
+		denylist:           apiCfg.denylist,
+		candBucketsIndexer: apiCfg.candBucketsIndexer,
+		evidenceReader:     apiCfg.evidenceReader,
+		roundHistoryReader: apiCfg.roundHistoryReader,
+	}
+	var gsOpts []gasstation.Option
+	if actPool != nil {
+		gsOpts = append(gsOpts, gasstation.WithMinGasPrice(actPool.MinGasPrice))
+	}
+	svr.gs = gasstation.NewGasStation(chain, sf.SimulateExecution, dao, cfg.API, gsOpts...)
 	if _, ok := cfg.Plugins[config.GatewayPlugin]; ok {
 		svr.hasActionIndex = true
 	}
-	svr.grpcServer = grpc.NewServer(
-		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
-	)
+	cache, err := newResponseCache(cfg.API.ResponseCache, cfg.Chain.ID, func() uint64 {
+		return svr.bc.TipHeight()
+	})
+	if err != nil {
+		return nil, err
+	}
+	svr.cache = cache
+	svr.observer = newMethodObserver(cfg.API.SlowQueryThreshold)
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_prometheus.StreamServerInterceptor,
+		svr.observer.streamInterceptor,
+		addressStreamInterceptor,
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_prometheus.UnaryServerInterceptor,
+		svr.observer.unaryInterceptor,
+		addressUnaryInterceptor,
+	}
+	if svr.limiter = newRateLimiter(cfg.API.RateLimit); svr.limiter != nil {
+		streamInterceptors = append(streamInterceptors, svr.limiter.streamInterceptor)
+		unaryInterceptors = append(unaryInterceptors, svr.limiter.unaryInterceptor)
+	}
+	if svr.auth, err = newAuthenticator(cfg.API.Auth); err != nil {
+		return nil, err
+	}
+	if svr.auth != nil {
+		streamInterceptors = append(streamInterceptors, svr.auth.streamInterceptor)
+		unaryInterceptors = append(unaryInterceptors, svr.auth.unaryInterceptor)
+	}
+	svr.grpcServer = grpc.NewServer(append(
+		grpcServerOptions(cfg.API.GRPC),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+	)...)
 	iotexapi.RegisterAPIServiceServer(svr.grpcServer, svr)
+	apipb.RegisterExtensionServiceServer(svr.grpcServer, svr)
 	grpc_prometheus.Register(svr.grpcServer)
-	reflection.Register(svr.grpcServer)
+	if cfg.API.GRPC.EnableReflection {
+		reflection.Register(svr.grpcServer)
+	}
+	if cfg.API.Web3Port > 0 {
+		svr.web3Server = NewWeb3Server(svr, cfg.API.Web3Port)
+	}
+	if cfg.API.RESTPort > 0 {
+		if svr.restServer, err = NewRESTServer(svr, cfg.API.RESTPort); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.API.GraphQL.Port > 0 {
+		if svr.graphQLServer, err = NewGraphQLServer(svr, cfg.API.GraphQL.Port); err != nil {
+			return nil, err
+		}
+	}
 
 	return svr, nil
 }
 
+// grpcServerOptions returns the message size, stream and keepalive options of the grpc server
+func grpcServerOptions(cfg config.GRPC) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     cfg.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.MaxConnectionAgeGrace,
+			Time:                  cfg.KeepaliveTime,
+			Timeout:               cfg.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepaliveMinTime,
+			PermitWithoutStream: cfg.PermitWithoutStream,
+		}),
+	}
+}
+
 // GetAccount returns the metadata of an account
 func (api *Server) GetAccount(ctx context.Context, in *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error) {
 	if in.Address == address.RewardingPoolAddr || in.Address == address.StakingBucketPoolAddr {
@@ -345,13 +505,45 @@ func (api *Server) GetServerMeta(ctx context.Context,
 // SendAction is the API to send an action to blockchain.
 func (api *Server) SendAction(ctx context.Context, in *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error) {
 	log.L().Debug("receive send action request")
+	selp, err := api.addAction(ctx, in.Action)
+	if err != nil {
+		return nil, err
+	}
+	// If there is no error putting into local actpool,
+	// Broadcast it to the network
+	if err = api.broadcastHandler(context.Background(), api.bc.ChainID(), in.Action); err != nil {
+		log.L().Warn("Failed to broadcast SendAction request.", zap.Error(err))
+	}
+	hash := selp.Hash()
+	return &iotexapi.SendActionResponse{ActionHash: hex.EncodeToString(hash[:])}, nil
+}
+
+// addAction adds the action to the local actpool
+func (api *Server) addAction(ctx context.Context, actPb *iotextypes.Action) (action.SealedEnvelope, error) {
 	var selp action.SealedEnvelope
 	var err error
-	if err = selp.LoadProto(in.Action); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+	if err = selp.LoadProto(actPb); err != nil {
+		return selp, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if api.denylist != nil {
+		if err := api.denylist.Check(selp); err != nil {
+			return selp, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+	// Add to local actpool, which limits the actions of the client ip
+	bcCtx, err := api.bc.Context()
+	if err != nil {
+		return selp, status.Error(codes.Internal, err.Error())
 	}
-	// Add to local actpool
+	ctx = protocol.WithBlockchainCtx(ctx, protocol.MustGetBlockchainCtx(bcCtx))
 	ctx = protocol.WithRegistry(ctx, api.registry)
+	origin, ok := getRequestOrigin(ctx)
+	if !ok {
+		origin, _ = getRequestOrigin(grpcRequestOrigin(ctx))
+	}
+	if origin.ip != "" {
+		ctx = actpool.WithActionSource(ctx, "ip:"+origin.ip)
+	}
 	if err = api.ap.Add(ctx, selp); err != nil {
 		log.L().Debug(err.Error())
 		var desc string
@@ -382,15 +574,9 @@ func (api *Server) SendAction(ctx context.Context, in *iotexapi.SendActionReques
 		if err != nil {
 			log.S().Panicf("Unexpected error attaching metadata: %v", err)
 		}
-		return nil, st.Err()
-	}
-	// If there is no error putting into local actpool,
-	// Broadcast it to the network
-	if err = api.broadcastHandler(context.Background(), api.bc.ChainID(), in.Action); err != nil {
-		log.L().Warn("Failed to broadcast SendAction request.", zap.Error(err))
+		return selp, st.Err()
 	}
-	hash := selp.Hash()
-	return &iotexapi.SendActionResponse{ActionHash: hex.EncodeToString(hash[:])}, nil
+	return selp, nil
 }
 
 // GetReceiptByAction gets receipt with corresponding action hash
@@ -402,20 +588,27 @@ func (api *Server) GetReceiptByAction(ctx context.Context, in *iotexapi.GetRecei
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	key := "receipt:" + hex.EncodeToString(actHash[:])
+	res := &iotexapi.GetReceiptByActionResponse{}
+	if api.cache.get(key, res) {
+		return res, nil
+	}
 	receipt, err := api.GetReceiptByActionHash(actHash)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, blockError(err)
 	}
 	blkHash, err := api.getBlockHashByActionHash(actHash)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
-	return &iotexapi.GetReceiptByActionResponse{
+	res = &iotexapi.GetReceiptByActionResponse{
 		ReceiptInfo: &iotexapi.ReceiptInfo{
 			Receipt: receipt.ConvertToReceiptPb(),
 			BlkHash: hex.EncodeToString(blkHash[:]),
 		},
-	}, nil
+	}
+	api.cache.put(key, receipt.BlockHeight, res)
+	return res, nil
 }
 
 // ReadContract reads the state in a contract address specified by the slot
@@ -427,30 +620,11 @@ func (api *Server) ReadContract(ctx context.Context, in *iotexapi.ReadContractRe
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	state, err := accountutil.AccountState(api.sf, in.CallerAddress)
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
-	}
-
-	sc, _ = action.NewExecution(
-		sc.Contract(),
-		state.Nonce+1,
-		sc.Amount(),
-		api.cfg.Genesis.BlockGasLimit,
-		big.NewInt(0),
-		sc.Data(),
-	)
-
 	callerAddr, err := address.FromString(in.CallerAddress)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-
-	ctx, err = api.bc.Context()
-	if err != nil {
-		return nil, err
-	}
-	retval, receipt, err := api.sf.SimulateExecution(ctx, callerAddr, sc, api.dao.GetBlockHash)
+	retval, receipt, err := api.simulateExecution(ctx, callerAddr, sc.Contract(), sc.Amount(), 0, sc.Data())
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -632,38 +806,50 @@ func (api *Server) GetRawBlocks(
 		return nil, status.Error(codes.InvalidArgument, "start height should not exceed tip height")
 	}
 	var res []*iotexapi.BlockInfo
-	for height := int(in.StartHeight); height <= int(tipHeight); height++ {
-		if uint64(len(res)) >= in.Count {
-			break
+	for height := in.StartHeight; height <= tipHeight && uint64(len(res)) < in.Count; height++ {
+		info, err := api.rawBlock(height, in.WithReceipts, in.WithTransactionLogs)
+		if err != nil {
+			return nil, err
 		}
-		blk, err := api.dao.GetBlockByHeight(uint64(height))
+		res = append(res, info)
+	}
+
+	return &iotexapi.GetRawBlocksResponse{Blocks: res}, nil
+}
+
+// rawBlock returns the block at the height, along with its receipts and transaction logs if requested
+func (api *Server) rawBlock(height uint64, withReceipts, withTransactionLogs bool) (*iotexapi.BlockInfo, error) {
+	key := fmt.Sprintf("rawblock:%d:%t:%t", height, withReceipts, withTransactionLogs)
+	if info := (&iotexapi.BlockInfo{}); api.cache.get(key, info) {
+		return info, nil
+	}
+	blk, err := api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, blockError(err)
+	}
+	var receiptsPb []*iotextypes.Receipt
+	if withReceipts {
+		receipts, err := api.dao.GetReceipts(height)
 		if err != nil {
-			return nil, status.Error(codes.NotFound, err.Error())
+			return nil, blockError(err)
 		}
-		var receiptsPb []*iotextypes.Receipt
-		if in.WithReceipts {
-			receipts, err := api.dao.GetReceipts(uint64(height))
-			if err != nil {
-				return nil, status.Error(codes.NotFound, err.Error())
-			}
-			for _, receipt := range receipts {
-				receiptsPb = append(receiptsPb, receipt.ConvertToReceiptPb())
-			}
+		for _, receipt := range receipts {
+			receiptsPb = append(receiptsPb, receipt.ConvertToReceiptPb())
 		}
-		var transactionLogs *iotextypes.TransactionLogs
-		if in.WithTransactionLogs {
-			if transactionLogs, err = api.dao.TransactionLogs(uint64(height)); err != nil {
-				return nil, status.Error(codes.NotFound, err.Error())
-			}
+	}
+	var transactionLogs *iotextypes.TransactionLogs
+	if withTransactionLogs {
+		if transactionLogs, err = api.dao.TransactionLogs(height); err != nil {
+			return nil, blockError(err)
 		}
-		res = append(res, &iotexapi.BlockInfo{
-			Block:           blk.ConvertToBlockPb(),
-			Receipts:        receiptsPb,
-			TransactionLogs: transactionLogs,
-		})
 	}
-
-	return &iotexapi.GetRawBlocksResponse{Blocks: res}, nil
+	info := &iotexapi.BlockInfo{
+		Block:           blk.ConvertToBlockPb(),
+		Receipts:        receiptsPb,
+		TransactionLogs: transactionLogs,
+	}
+	api.cache.put(key, height, info)
+	return info, nil
 }
 
 // GetLogs get logs filtered by contract address and topics
@@ -676,55 +862,62 @@ func (api *Server) GetLogs(
 	}
 
 	var (
-		logs []*iotextypes.Log
-		err  error
+		logs     []*iotextypes.Log
+		err      error
+		endBlock uint64
 	)
+	key, err := logsCacheKey(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if res := (&iotexapi.GetLogsResponse{}); key != "" && api.cache.get(key, res) {
+		return res, nil
+	}
 	switch {
 	case in.GetByBlock() != nil:
 		req := in.GetByBlock()
-		startBlock, err := api.dao.GetBlockHeight(hash.BytesToHash256(req.BlockHash))
+		endBlock, err = api.dao.GetBlockHeight(hash.BytesToHash256(req.BlockHash))
 		if err != nil {
 			return nil, status.Error(codes.InvalidArgument, "invalid block hash")
 		}
-		logs, err = api.getLogsInBlock(logfilter.NewLogFilter(in.GetFilter(), nil, nil), startBlock)
+		logs, err = api.getLogsInBlock(logfilter.NewLogFilter(in.GetFilter(), nil, nil), endBlock)
 	case in.GetByRange() != nil:
 		req := in.GetByRange()
-		startBlock := req.GetFromBlock()
-		if startBlock > api.bc.TipHeight() {
-			return nil, status.Error(codes.InvalidArgument, "start block > tip height")
-		}
-		endBlock := req.GetToBlock()
-		if endBlock > api.bc.TipHeight() || endBlock == 0 {
-			endBlock = api.bc.TipHeight()
+		var (
+			startBlock uint64
+			followTip  bool
+		)
+		if startBlock, endBlock, followTip, err = api.logsRange(req); err != nil {
+			return nil, err
 		}
-		paginationSize := req.GetPaginationSize()
-		if paginationSize == 0 {
-			paginationSize = 1000
+		if followTip {
+			// the range follows the tip, so its logs are not cached
+			key = ""
 		}
-		logs, err = api.getLogsInRange(logfilter.NewLogFilter(in.GetFilter(), nil, nil), startBlock, endBlock, paginationSize)
+		logs, err = api.getLogsInRange(logfilter.NewLogFilter(in.GetFilter(), nil, nil), startBlock, endBlock, req.GetPaginationSize())
 	default:
 		return nil, status.Error(codes.InvalidArgument, "invalid GetLogsRequest type")
 	}
 
-	return &iotexapi.GetLogsResponse{Logs: logs}, err
+	res := &iotexapi.GetLogsResponse{Logs: logs}
+	if err == nil && key != "" {
+		api.cache.put(key, endBlock, res)
+	}
+	return res, err
 }
 
 // StreamBlocks streams blocks
 func (api *Server) StreamBlocks(in *iotexapi.StreamBlocksRequest, stream iotexapi.APIService_StreamBlocksServer) error {
-	errChan := make(chan error)
-	if err := api.chainListener.AddResponder(NewBlockListener(stream, errChan)); err != nil {
+	errChan := make(chan error, 2)
+	responder := newBufferedResponder(
+		NewBlockListener(stream, errChan),
+		api.cfg.API.StreamBufferSize,
+		api.cfg.API.StreamTimeout,
+	)
+	if err := api.chainListener.AddResponder(responder); err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
-
-	for {
-		select {
-		case err := <-errChan:
-			if err != nil {
-				err = status.Error(codes.Aborted, err.Error())
-			}
-			return err
-		}
-	}
+	return waitStream(stream.Context(), responder, errChan)
 }
 
 // StreamLogs streams logs that match the filter condition
@@ -732,20 +925,55 @@ func (api *Server) StreamLogs(in *iotexapi.StreamLogsRequest, stream iotexapi.AP
 	if in.GetFilter() == nil {
 		return status.Error(codes.InvalidArgument, "empty filter")
 	}
-	errChan := make(chan error)
+	errChan := make(chan error, 2)
 	// register the log filter so it will match logs in new blocks
-	if err := api.chainListener.AddResponder(logfilter.NewLogFilter(in.GetFilter(), stream, errChan)); err != nil {
+	responder := newBufferedResponder(
+		logfilter.NewLogFilter(in.GetFilter(), stream, errChan),
+		api.cfg.API.StreamBufferSize,
+		api.cfg.API.StreamTimeout,
+	)
+	if err := api.chainListener.AddResponder(responder); err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
+	return waitStream(stream.Context(), responder, errChan)
+}
 
-	for {
-		select {
-		case err := <-errChan:
-			if err != nil {
-				err = status.Error(codes.Aborted, err.Error())
-			}
-			return err
+// StreamReceipts streams the receipts of the new blocks
+func (api *Server) StreamReceipts(in *apipb.StreamReceiptsRequest, stream apipb.ExtensionService_StreamReceiptsServer) error {
+	errChan := make(chan error, 2)
+	responder := newBufferedResponder(
+		newReceiptListener(stream, errChan),
+		api.cfg.API.StreamBufferSize,
+		api.cfg.API.StreamTimeout,
+	)
+	if err := api.chainListener.AddResponder(responder); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return waitStream(stream.Context(), responder, errChan)
+}
+
+// waitStream blocks until the stream ends, which happens when the client goes away, the listener stops, or the client
+// fails to keep up with the chain
+func waitStream(ctx context.Context, responder *bufferedResponder, errChan chan error) error {
+	// the listener drops the responder on its next block
+	defer responder.close(errStreamClosed)
+	select {
+	case err := <-errChan:
+		if err != nil {
+			err = status.Error(codes.Aborted, err.Error())
+		}
+		return err
+	case <-responder.Done():
+		switch err := responder.Err(); errors.Cause(err) {
+		case nil:
+			return nil
+		case errStreamLagging:
+			return status.Error(codes.ResourceExhausted, err.Error())
+		default:
+			return status.Error(codes.Aborted, err.Error())
 		}
+	case <-ctx.Done():
+		return status.Error(codes.Canceled, ctx.Err().Error())
 	}
 }
 
@@ -838,8 +1066,11 @@ func (api *Server) GetTransactionLogByActionHash(
 
 	sysLog, err := api.dao.TransactionLogs(actIndex.BlockHeight())
 	if err != nil {
-		if errors.Cause(err) == db.ErrNotExist {
+		switch errors.Cause(err) {
+		case db.ErrNotExist:
 			return nil, status.Error(codes.NotFound, err.Error())
+		case filedao.ErrPruned:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -891,6 +1122,9 @@ func (api *Server) GetTransactionLogByBlockHeight(
 			res.TransactionLogs = &iotextypes.TransactionLogs{}
 			return res, nil
 		}
+		if errors.Cause(err) == filedao.ErrPruned {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -907,6 +1141,7 @@ func (api *Server) Start() error {
 		return errors.Wrap(err, "API server failed to listen")
 	}
 	log.L().Info("API server is listening.", zap.String("addr", lis.Addr().String()))
+	api.startingHeight = api.bc.TipHeight()
 
 	go func() {
 		if err := api.grpcServer.Serve(lis); err != nil {
@@ -919,11 +1154,41 @@ func (api *Server) Start() error {
 	if err := api.chainListener.Start(); err != nil {
 		return errors.Wrap(err, "failed to start blockchain listener")
 	}
+	if api.web3Server != nil {
+		if err := api.web3Server.Start(context.Background()); err != nil {
+			return errors.Wrap(err, "failed to start web3 server")
+		}
+	}
+	if api.restServer != nil {
+		if err := api.restServer.Start(context.Background()); err != nil {
+			return errors.Wrap(err, "failed to start rest server")
+		}
+	}
+	if api.graphQLServer != nil {
+		if err := api.graphQLServer.Start(context.Background()); err != nil {
+			return errors.Wrap(err, "failed to start graphql server")
+		}
+	}
 	return nil
 }
 
 // Stop stops the API server
 func (api *Server) Stop() error {
+	if api.web3Server != nil {
+		if err := api.web3Server.Stop(context.Background()); err != nil {
+			return errors.Wrap(err, "failed to stop web3 server")
+		}
+	}
+	if api.restServer != nil {
+		if err := api.restServer.Stop(context.Background()); err != nil {
+			return errors.Wrap(err, "failed to stop rest server")
+		}
+	}
+	if api.graphQLServer != nil {
+		if err := api.graphQLServer.Stop(context.Background()); err != nil {
+			return errors.Wrap(err, "failed to stop graphql server")
+		}
+	}
 	api.grpcServer.Stop()
 	if err := api.bc.RemoveSubscriber(api.chainListener); err != nil {
 		return errors.Wrap(err, "failed to unsubscribe blockchain listener")
@@ -1022,7 +1287,7 @@ func (api *Server) getActions(start uint64, count uint64) (*iotexapi.GetActionsR
 	for height := api.bc.TipHeight(); height >= 1 && count > 0; height-- {
 		blk, err := api.dao.GetBlockByHeight(height)
 		if err != nil {
-			return nil, status.Error(codes.NotFound, err.Error())
+			return nil, blockError(err)
 		}
 		if !hit && reverseStart >= uint64(len(blk.Actions)) {
 			reverseStart -= uint64(len(blk.Actions))
@@ -1161,7 +1426,7 @@ func (api *Server) getActionsByBlock(blkHash string, start uint64, count uint64)
 	}
 	blk, err := api.dao.GetBlock(hash)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, blockError(err)
 	}
 	if start >= uint64(len(blk.Actions)) {
 		return nil, status.Error(codes.InvalidArgument, "start exceeds the limit")
@@ -1219,6 +1484,18 @@ func (api *Server) getBlockMeta(blkHash string) (*iotexapi.GetBlockMetasResponse
 
 // getBlockMetaByHeight gets block meta by height
 func (api *Server) getBlockMetaByHeight(height uint64) (*iotextypes.BlockMeta, error) {
+	key := fmt.Sprintf("blockmeta:%d", height)
+	if blockMeta := (&iotextypes.BlockMeta{}); api.cache.get(key, blockMeta) {
+		return blockMeta, nil
+	}
+	blockMeta, err := api.readBlockMetaByHeight(height)
+	if err == nil {
+		api.cache.put(key, height, blockMeta)
+	}
+	return blockMeta, err
+}
+
+func (api *Server) readBlockMetaByHeight(height uint64) (*iotextypes.BlockMeta, error) {
 	if api.indexer != nil {
 		blockMeta, err := api.getBlockMetasByHeader(height)
 		if errors.Cause(err) != db.ErrNotExist {
@@ -1228,8 +1505,12 @@ func (api *Server) getBlockMetaByHeight(height uint64) (*iotextypes.BlockMeta, e
 	return api.getBlockMetasByBlock(height)
 }
 
-// getBlockMetaByHash gets block meta by hash
+// getBlockMetaByHash gets block meta by hash, the hash is resolved to the height through the header cache of the dao,
+// so the meta is cached once for both lookups
 func (api *Server) getBlockMetaByHash(h hash.Hash256) (*iotextypes.BlockMeta, error) {
+	if header, err := api.dao.Header(h); err == nil {
+		return api.getBlockMetaByHeight(header.Height())
+	}
 	if api.indexer != nil {
 		blockMeta, err := api.getBlockMetaByHeader(h)
 		if errors.Cause(err) != db.ErrNotExist {
@@ -1271,11 +1552,24 @@ func (api *Server) getBlockMetasByHeader(height uint64) (*iotextypes.BlockMeta,
 	return blockMeta, nil
 }
 
+// blockGasLimit returns the gas limit of the block following the tip, which caps the gas of a simulated execution
+func (api *Server) blockGasLimit() uint64 {
+	return api.cfg.Genesis.BlockGasLimitByHeight(api.bc.TipHeight() + 1)
+}
+
+// blockError converts the error of reading a block, or its receipts and transaction logs, to a grpc status error
+func blockError(err error) error {
+	if errors.Cause(err) == filedao.ErrPruned {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.NotFound, err.Error())
+}
+
 // getBlockMetasByBlock gets block by height
 func (api *Server) getBlockMetasByBlock(height uint64) (*iotextypes.BlockMeta, error) {
 	blk, err := api.dao.GetBlockByHeight(height)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, blockError(err)
 	}
 	blockMeta := api.getCommonBlockMeta(blk)
 	blockMeta = api.putBlockMetaUpgradeByBlock(blk, blockMeta)
@@ -1300,7 +1594,7 @@ func (api *Server) getBlockMetaByHeader(h hash.Hash256) (*iotextypes.BlockMeta,
 func (api *Server) getBlockMetaByBlock(h hash.Hash256) (*iotextypes.BlockMeta, error) {
 	blk, err := api.dao.GetBlock(h)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, blockError(err)
 	}
 	blockMeta := api.getCommonBlockMeta(blk)
 	blockMeta = api.putBlockMetaUpgradeByBlock(blk, blockMeta)
@@ -1398,9 +1692,17 @@ func (api *Server) pendingAction(selp action.SealedEnvelope) (*iotexapi.ActionIn
 }
 
 func (api *Server) getAction(actHash hash.Hash256, checkPending bool) (*iotexapi.ActionInfo, error) {
+	key := "action:" + hex.EncodeToString(actHash[:])
+	if info := (&iotexapi.ActionInfo{}); api.cache.get(key, info) {
+		return info, nil
+	}
 	selp, blkHash, blkHeight, err := api.getActionByActionHash(actHash)
 	if err == nil {
-		return api.committedAction(selp, blkHash, blkHeight)
+		info, err := api.committedAction(selp, blkHash, blkHeight)
+		if err == nil {
+			api.cache.put(key, blkHeight, info)
+		}
+		return info, err
 	}
 	// Try to fetch pending action from actpool
 	if checkPending {
@@ -1489,6 +1791,8 @@ func (api *Server) getLogsInBlock(filter *logfilter.LogFilter, blockNumber uint6
 	return filter.MatchLogs(receipts), nil
 }
 
+// getLogsInRange returns the logs in [start, end]. If the pagination size is set, at most as many logs are returned,
+// otherwise the query fails if it exceeds the limits of the api.
 // TODO: improve using goroutine
 func (api *Server) getLogsInRange(filter *logfilter.LogFilter, start, end, paginationSize uint64) ([]*iotextypes.Log, error) {
 	if start > end {
@@ -1497,6 +1801,11 @@ func (api *Server) getLogsInRange(filter *logfilter.LogFilter, start, end, pagin
 	if start == 0 {
 		start = 1
 	}
+	limits := api.cfg.API.LogQuery
+	maxLogs := limits.MaxLogs
+	if paginationSize > 0 && paginationSize < maxLogs {
+		maxLogs = paginationSize
+	}
 
 	logs := []*iotextypes.Log{}
 	// getLogs via range Blooom filter [start, end]
@@ -1504,17 +1813,35 @@ func (api *Server) getLogsInRange(filter *logfilter.LogFilter, start, end, pagin
 	if err != nil {
 		return nil, err
 	}
-	for _, i := range blockNumbers {
+	for n, i := range blockNumbers {
+		if uint64(n) == limits.MaxBlocksScanned {
+			return nil, &QueryTooBroadError{
+				Reason:           fmt.Sprintf("more than %d blocks to scan", limits.MaxBlocksScanned),
+				FromBlock:        start,
+				ToBlock:          end,
+				SuggestedToBlock: blockNumbers[n-1],
+			}
+		}
 		logsInBlock, err := api.getLogsInBlock(filter, i)
 		if err != nil {
 			return nil, err
 		}
-		for _, log := range logsInBlock {
-			logs = append(logs, log)
-			if len(logs) >= int(paginationSize) {
-				return logs, nil
+		if paginationSize == 0 && uint64(len(logs)+len(logsInBlock)) > maxLogs {
+			suggested := start
+			if i > start {
+				suggested = i - 1
+			}
+			return nil, &QueryTooBroadError{
+				Reason:           fmt.Sprintf("more than %d logs", maxLogs),
+				FromBlock:        start,
+				ToBlock:          end,
+				SuggestedToBlock: suggested,
 			}
 		}
+		logs = append(logs, logsInBlock...)
+		if paginationSize > 0 && uint64(len(logs)) >= maxLogs {
+			return logs[:maxLogs], nil
+		}
 	}
 
 	return logs, nil
@@ -1541,7 +1868,7 @@ func (api *Server) estimateActionGasConsumptionForExecution(exec *iotextypes.Exe
 		sc.Contract(),
 		nonce,
 		sc.Amount(),
-		api.cfg.Genesis.BlockGasLimit,
+		api.blockGasLimit(),
 		big.NewInt(0),
 		sc.Data(),
 	)
@@ -1550,16 +1877,13 @@ func (api *Server) estimateActionGasConsumptionForExecution(exec *iotextypes.Exe
 	if err != nil {
 		return nil, err
 	}
-	_, receipt, err := api.sf.SimulateExecution(ctx, callerAddr, sc, api.dao.GetBlockHash)
+	retval, receipt, err := api.sf.SimulateExecution(ctx, callerAddr, sc, api.dao.GetBlockHash)
 
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	if receipt.Status != uint64(iotextypes.ReceiptStatus_Success) {
-		if receipt.ExecutionRevertMsg() != "" {
-			return nil, status.Errorf(codes.Internal, fmt.Sprintf("execution simulation is reverted due to the reason: %s", receipt.ExecutionRevertMsg()))
-		}
-		return nil, status.Error(codes.Internal, "execution simulation is failed")
+		return nil, newExecutionRevertedError(receipt.Status, retval)
 	}
 	estimatedGas := receipt.GasConsumed
 	enough, err := api.isGasLimitEnough(callerAddr, sc, nonce, estimatedGas)
@@ -1567,7 +1891,7 @@ func (api *Server) estimateActionGasConsumptionForExecution(exec *iotextypes.Exe
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	if !enough {
-		low, high := estimatedGas, api.cfg.Genesis.BlockGasLimit
+		low, high := estimatedGas, api.blockGasLimit()
 		estimatedGas = high
 		for low <= high {
 			mid := (low + high) / 2
@@ -1588,6 +1912,81 @@ func (api *Server) estimateActionGasConsumptionForExecution(exec *iotextypes.Exe
 	}, nil
 }
 
+// simulateExecution runs a read-only execution from caller against the tip state, a zero gas limit means block gas limit.
+// The evm tracer and state overrides carried by ctx are applied to the execution.
+func (api *Server) simulateExecution(
+	ctx context.Context,
+	caller address.Address,
+	contract string,
+	amount *big.Int,
+	gasLimit uint64,
+	data []byte,
+) ([]byte, *action.Receipt, error) {
+	sc, err := api.simulatedExecution(api.sf, caller, contract, amount, gasLimit, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	bcCtx, err := api.bc.Context()
+	if err != nil {
+		return nil, nil, err
+	}
+	return api.sf.SimulateExecution(withSimulationOptions(ctx, bcCtx), caller, sc, api.dao.GetBlockHash)
+}
+
+// simulatePendingExecution simulates the execution on top of the pending state, after the actions in the actpool
+func (api *Server) simulatePendingExecution(
+	ctx context.Context,
+	caller address.Address,
+	contract string,
+	amount *big.Int,
+	gasLimit uint64,
+	data []byte,
+) ([]byte, *action.Receipt, error) {
+	bcCtx, err := api.bc.Context()
+	if err != nil {
+		return nil, nil, err
+	}
+	ps, err := api.pendingState(bcCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sc, err := api.simulatedExecution(ps, caller, contract, amount, gasLimit, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ps.SimulateExecution(withSimulationOptions(ctx, bcCtx), caller, sc, api.dao.GetBlockHash)
+}
+
+// simulatedExecution creates the execution of the caller with the next nonce of the state
+func (api *Server) simulatedExecution(
+	sr protocol.StateReader,
+	caller address.Address,
+	contract string,
+	amount *big.Int,
+	gasLimit uint64,
+	data []byte,
+) (*action.Execution, error) {
+	state, err := accountutil.AccountState(sr, caller.String())
+	if err != nil {
+		return nil, err
+	}
+	if blockGasLimit := api.blockGasLimit(); gasLimit == 0 || gasLimit > blockGasLimit {
+		gasLimit = blockGasLimit
+	}
+	return action.NewExecution(contract, state.Nonce+1, amount, gasLimit, big.NewInt(0), data)
+}
+
+// withSimulationOptions copies the tracer and state overrides of the request context into the blockchain context
+func withSimulationOptions(ctx context.Context, bcCtx context.Context) context.Context {
+	if tracer, ok := evm.GetTracer(ctx); ok {
+		bcCtx = evm.WithTracer(bcCtx, tracer)
+	}
+	if overrides, ok := evm.GetStateOverrides(ctx); ok {
+		bcCtx = evm.WithStateOverrides(bcCtx, overrides)
+	}
+	return bcCtx
+}
+
 func (api *Server) estimateActionGasConsumptionForTransfer(transfer *iotextypes.Transfer) (*iotexapi.EstimateActionGasConsumptionResponse, error) {
 	payloadSize := uint64(len(transfer.Payload))
 	return &iotexapi.EstimateActionGasConsumptionResponse{
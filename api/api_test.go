@@ -11,6 +11,7 @@ import (
 	"encoding/hex"
 	"math"
 	"math/big"
+	"net"
 	"strconv"
 	"testing"
 	"time"
@@ -19,8 +20,10 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
@@ -37,6 +40,7 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
 	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/api/apipb"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/blockchain/blockdao"
@@ -1135,6 +1139,7 @@ func TestServer_SendAction(t *testing.T) {
 	}}
 
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
+	chain.EXPECT().Context().Return(protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{}), nil).AnyTimes()
 	ap.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil).Times(2)
 
 	for i, test := range sendActionTests {
@@ -2257,8 +2262,12 @@ func setupChain(cfg config.Config) (blockchain.Blockchain, blockdao.BlockDAO, bl
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, "", errors.New("failed to create bloomfilter indexer")
 	}
-	// create BlockDAO
-	dao := blockdao.NewBlockDAOInMemForTest([]blockdao.BlockIndexer{sf, indexer, bfIndexer})
+	// create BlockDAO, the bloomfilter indexer is only run by the gateway
+	indexers := []blockdao.BlockIndexer{sf, indexer}
+	if _, ok := cfg.Plugins[config.GatewayPlugin]; ok {
+		indexers = append(indexers, bfIndexer)
+	}
+	dao := blockdao.NewBlockDAOInMemForTest(indexers)
 	if dao == nil {
 		return nil, nil, nil, nil, nil, nil, nil, "", errors.New("failed to create blockdao")
 	}
@@ -2384,10 +2393,48 @@ func createServer(cfg config.Config, needActPool bool) (*Server, string, error)
 		registry:       registry,
 		hasActionIndex: true,
 	}
+	// the rest gateway calls the api over grpc
+	svr.grpcServer = grpc.NewServer(
+		grpc.ChainStreamInterceptor(addressStreamInterceptor),
+		grpc.ChainUnaryInterceptor(addressUnaryInterceptor),
+	)
+	iotexapi.RegisterAPIServiceServer(svr.grpcServer, svr)
+	apipb.RegisterExtensionServiceServer(svr.grpcServer, svr)
 
 	return svr, bfIndexFile, nil
 }
 
+// dialGRPCServer serves the grpc server in memory and connects to it, the caller stops the server
+func dialGRPCServer(t *testing.T, svr *Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	go svr.grpcServer.Serve(lis)
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	require.NoError(t, err)
+	return conn
+}
+
+func TestNewServer_GRPCOptions(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	for _, enabled := range []bool{true, false} {
+		cfg.API.GRPC.EnableReflection = enabled
+		s, err := NewServer(cfg, svr.bc, nil, svr.sf, svr.dao, svr.indexer, svr.bfIndexer, svr.ap, svr.registry)
+		require.NoError(err)
+		info := s.grpcServer.GetServiceInfo()
+		require.Contains(info, "iotexapi.APIService")
+		_, ok := info["grpc.reflection.v1alpha.ServerReflection"]
+		require.Equal(enabled, ok)
+	}
+}
+
 func TestServer_GetActPoolActions(t *testing.T) {
 	require := require.New(t)
 	cfg := newConfig(t)
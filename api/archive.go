@@ -0,0 +1,166 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// GetAccountAtHeight returns the metadata of an account at the given height, which requires the node to run in
+// archive mode. The pending nonce is the next nonce as of the height, and the number of actions is not reported.
+func (api *Server) GetAccountAtHeight(ctx context.Context, in *apipb.GetAccountAtHeightRequest) (*iotexapi.GetAccountResponse, error) {
+	height := in.GetHeight()
+	if in.Address == address.RewardingPoolAddr || in.Address == address.StakingBucketPoolAddr {
+		return nil, status.Error(codes.InvalidArgument, "historical state of protocol accounts is not supported")
+	}
+	sr, err := api.historyStateReader(height)
+	if err != nil {
+		return nil, historyError(err)
+	}
+	state, err := accountutil.AccountState(sr, in.Address)
+	if err != nil {
+		return nil, historyError(err)
+	}
+	tip, err := api.tipInfoAtHeight(height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &iotexapi.GetAccountResponse{
+		AccountMeta: &iotextypes.AccountMeta{
+			Address:      in.Address,
+			Balance:      state.Balance.String(),
+			Nonce:        state.Nonce,
+			PendingNonce: state.Nonce + 1,
+			IsContract:   state.IsContract(),
+		},
+		BlockIdentifier: &iotextypes.BlockIdentifier{
+			Hash:   hex.EncodeToString(tip.Hash[:]),
+			Height: height,
+		},
+	}, nil
+}
+
+// ReadContractAtHeight reads the state of a contract at the given height, which requires the node to run in archive
+// mode
+func (api *Server) ReadContractAtHeight(ctx context.Context, in *apipb.ReadContractAtHeightRequest) (*iotexapi.ReadContractResponse, error) {
+	height := in.GetHeight()
+	sc := &action.Execution{}
+	if err := sc.LoadProto(in.Execution); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	callerAddr, err := address.FromString(in.CallerAddress)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	retval, receipt, err := api.simulateExecutionAtHeight(ctx, height, callerAddr, sc.Contract(), sc.Amount(), 0, sc.Data())
+	if err != nil {
+		return nil, historyError(err)
+	}
+	return &iotexapi.ReadContractResponse{
+		Data:    hex.EncodeToString(retval),
+		Receipt: receipt.ConvertToReceiptPb(),
+	}, nil
+}
+
+// historyStateReader returns a reader of the state at the given height
+func (api *Server) historyStateReader(height uint64) (protocol.StateReader, error) {
+	if tipHeight := api.bc.TipHeight(); height > tipHeight {
+		return nil, errors.Wrapf(ErrHeight, "query height %d is higher than tip height %d", height, tipHeight)
+	}
+	if ar, ok := api.sf.(factory.ArchiveReader); ok {
+		return ar.NewWorkingSetAt(context.Background(), height)
+	}
+	if !api.cfg.Chain.EnableArchiveMode {
+		return nil, factory.ErrNoArchiveData
+	}
+	return factory.NewHistoryStateReader(api.sf, height), nil
+}
+
+// tipInfoAtHeight returns the block at the given height as a chain tip
+func (api *Server) tipInfoAtHeight(height uint64) (*protocol.TipInfo, error) {
+	if height == 0 {
+		return &protocol.TipInfo{
+			Height:    0,
+			Hash:      api.cfg.Genesis.Hash(),
+			Timestamp: time.Unix(api.cfg.Genesis.Timestamp, 0),
+		}, nil
+	}
+	header, err := api.bc.BlockHeaderByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.TipInfo{
+		Height:    height,
+		Hash:      header.HashBlock(),
+		Timestamp: header.Timestamp(),
+	}, nil
+}
+
+// simulateExecutionAtHeight simulates an execution on top of the state at the given height, as if it were in the next
+// block
+func (api *Server) simulateExecutionAtHeight(
+	ctx context.Context,
+	height uint64,
+	caller address.Address,
+	contract string,
+	amount *big.Int,
+	gasLimit uint64,
+	data []byte,
+) ([]byte, *action.Receipt, error) {
+	sr, err := api.historyStateReader(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	state, err := accountutil.AccountState(sr, caller.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	if blockGasLimit := api.cfg.Genesis.BlockGasLimitByHeight(height); gasLimit == 0 || gasLimit > blockGasLimit {
+		gasLimit = blockGasLimit
+	}
+	sc, err := action.NewExecution(contract, state.Nonce+1, amount, gasLimit, big.NewInt(0), data)
+	if err != nil {
+		return nil, nil, err
+	}
+	tip, err := api.tipInfoAtHeight(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	bcCtx := withSimulationOptions(ctx, protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{
+		Genesis: api.cfg.Genesis,
+		Tip:     *tip,
+	}))
+	return api.sf.SimulateExecutionAtHeight(bcCtx, height, caller, sc, api.dao.GetBlockHash)
+}
+
+func historyError(err error) error {
+	switch errors.Cause(err) {
+	case factory.ErrNoArchiveData, factory.ErrNotSupported:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case ErrHeight:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_HistoricalState(t *testing.T) {
+	require := require.New(t)
+	addr := identityset.Address(27).String()
+	ethAddr, err := ioAddrToEthAddr(addr)
+	require.NoError(err)
+
+	t.Run("archive mode", func(t *testing.T) {
+		cfg := newConfig(t)
+		cfg.Chain.EnableArchiveMode = true
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+		ctx := context.Background()
+
+		latest, err := svr.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: addr})
+		require.NoError(err)
+		tipHeight := svr.bc.TipHeight()
+		res, err := svr.GetAccountAtHeight(ctx, &apipb.GetAccountAtHeightRequest{Address: addr, Height: tipHeight})
+		require.NoError(err)
+		require.Equal(latest.AccountMeta.Balance, res.AccountMeta.Balance)
+		require.Equal(latest.AccountMeta.Nonce, res.AccountMeta.Nonce)
+		require.Equal(latest.BlockIdentifier, res.BlockIdentifier)
+
+		// the genesis balance, before any transfer of the account
+		res, err = svr.GetAccountAtHeight(ctx, &apipb.GetAccountAtHeightRequest{Address: addr})
+		require.NoError(err)
+		require.Equal(cfg.Genesis.InitBalanceMap[addr], res.AccountMeta.Balance)
+		require.Zero(res.AccountMeta.Nonce)
+		require.NotEqual(latest.AccountMeta.Balance, res.AccountMeta.Balance)
+
+		_, err = svr.GetAccountAtHeight(ctx, &apipb.GetAccountAtHeightRequest{Address: addr, Height: tipHeight + 1})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		h, err := hash.HexStringToHash256(hex.EncodeToString(executionHash2[:]))
+		require.NoError(err)
+		ai, err := svr.indexer.GetActionIndex(h[:])
+		require.NoError(err)
+		exec, err := svr.dao.GetActionByActionHash(h, ai.BlockHeight())
+		require.NoError(err)
+		request := &iotexapi.ReadContractRequest{
+			Execution:     exec.Proto().GetCore().GetExecution(),
+			CallerAddress: identityset.Address(30).String(),
+		}
+		expected, err := svr.ReadContract(ctx, request)
+		require.NoError(err)
+		read, err := svr.ReadContractAtHeight(ctx, &apipb.ReadContractAtHeightRequest{
+			Execution:     request.Execution,
+			CallerAddress: request.CallerAddress,
+			Height:        tipHeight,
+		})
+		require.NoError(err)
+		require.Equal(expected.Data, read.Data)
+		require.Equal(expected.Receipt.Status, read.Receipt.Status)
+		_, err = svr.ReadContractAtHeight(ctx, &apipb.ReadContractAtHeightRequest{
+			Execution:     request.Execution,
+			CallerAddress: request.CallerAddress,
+			Height:        1,
+		})
+		require.NoError(err)
+
+		// the action is replayed after the actions before it in its block, on top of the state of the previous block
+		svr.cfg.API.EnableDebugAPI = true
+		receipt, err := svr.GetReceiptByAction(ctx, &iotexapi.GetReceiptByActionRequest{ActionHash: hex.EncodeToString(h[:])})
+		require.NoError(err)
+		trace, err := svr.TraceAction(ctx, &apipb.TraceActionRequest{ActionHash: hex.EncodeToString(h[:])})
+		require.NoError(err)
+		var structLogs StructLogResult
+		require.NoError(json.Unmarshal([]byte(trace.Result), &structLogs))
+		require.Equal(receipt.ReceiptInfo.Receipt.GasConsumed, structLogs.Gas)
+		require.Equal(receipt.ReceiptInfo.Receipt.Status == 1, !structLogs.Failed)
+		blkTrace, err := svr.TraceBlock(ctx, &apipb.TraceBlockRequest{
+			Height: ai.BlockHeight(),
+			Config: &apipb.TraceConfig{Tracer: CallTracerName},
+		})
+		require.NoError(err)
+		require.Equal(hex.EncodeToString(h[:]), blkTrace.Traces[len(blkTrace.Traces)-1].ActionHash)
+		_, err = svr.TraceAction(ctx, &apipb.TraceActionRequest{
+			ActionHash: hex.EncodeToString(h[:]),
+			Config:     &apipb.TraceConfig{Tracer: "prestateTracer"},
+		})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		web3 := NewWeb3Server(svr, 0)
+		var balance hexutil.Big
+		resp := web3Call(t, web3, "eth_getBalance", ethAddr, "0x0")
+		require.Nil(resp.Error)
+		require.NoError(json.Unmarshal(resp.Result.(json.RawMessage), &balance))
+		require.Equal(cfg.Genesis.InitBalanceMap[addr], (*big.Int)(&balance).String())
+		var nonce hexutil.Uint64
+		resp = web3Call(t, web3, "eth_getTransactionCount", ethAddr, "0x0")
+		require.Nil(resp.Error)
+		require.NoError(json.Unmarshal(resp.Result.(json.RawMessage), &nonce))
+		require.EqualValues(1, nonce)
+	})
+
+	t.Run("archive mode disabled", func(t *testing.T) {
+		cfg := newConfig(t)
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+
+		_, err = svr.GetAccountAtHeight(context.Background(), &apipb.GetAccountAtHeightRequest{Address: addr})
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+		_, err = svr.ReadContractAtHeight(context.Background(), &apipb.ReadContractAtHeightRequest{
+			Execution:     testExecution.Proto().GetCore().GetExecution(),
+			CallerAddress: addr,
+		})
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+		svr.cfg.API.EnableDebugAPI = true
+		_, err = svr.TraceAction(context.Background(), &apipb.TraceActionRequest{
+			ActionHash: hex.EncodeToString(executionHash2[:]),
+		})
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+
+		web3 := NewWeb3Server(svr, 0)
+		require.Equal(-32000, web3Call(t, web3, "eth_getBalance", ethAddr, "0x0").Error.Code)
+		require.Nil(web3Call(t, web3, "eth_getBalance", ethAddr, "latest").Error)
+	})
+}
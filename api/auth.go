@@ -0,0 +1,160 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// AuthorizationHeader is the header, or the grpc metadata key, carrying the bearer token of a request
+const AuthorizationHeader = "authorization"
+
+var (
+	// ErrUnauthenticated indicates the request of a protected method carries no valid api key or token
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// protectedMethods are the write methods, and the expensive debug methods, which need authentication
+	protectedMethods = map[string]bool{
+		"SendAction":                 true,
+		"SendPrivateAction":          true,
+		"eth_sendRawTransaction":     true,
+		"eth_sendPrivateTransaction": true,
+		"debug_traceTransaction":     true,
+		"debug_traceCall":            true,
+		"debug_traceBlockByNumber":   true,
+		"debug_storageRangeAt":       true,
+		"TraceAction":                true,
+		"TraceBlock":                 true,
+	}
+)
+
+// authenticator checks the api key or the json web token of the requests of protected methods
+type authenticator struct {
+	apiKeys map[string]struct{}
+	issuer  string
+	keyFunc jwt.Keyfunc
+}
+
+// newAuthenticator creates an authenticator, it returns nil if authentication is disabled
+func newAuthenticator(cfg config.Auth) (*authenticator, error) {
+	a := &authenticator{
+		apiKeys: make(map[string]struct{}, len(cfg.APIKeys)),
+		issuer:  cfg.JWTIssuer,
+	}
+	for _, key := range cfg.APIKeys {
+		a.apiKeys[key] = struct{}{}
+	}
+	switch {
+	case cfg.JWTSecret != "" && cfg.JWTPublicKeyFile != "":
+		return nil, errors.New("jwt secret and jwt public key cannot be both set")
+	case cfg.JWTSecret != "":
+		secret := []byte(cfg.JWTSecret)
+		a.keyFunc = func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.Errorf("unexpected signing method %s", token.Header["alg"])
+			}
+			return secret, nil
+		}
+	case cfg.JWTPublicKeyFile != "":
+		pem, err := ioutil.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read jwt public key")
+		}
+		if key, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+			a.keyFunc = func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+					return nil, errors.Errorf("unexpected signing method %s", token.Header["alg"])
+				}
+				return key, nil
+			}
+			break
+		}
+		key, err := jwt.ParseECPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, errors.New("jwt public key is neither a RSA nor an ECDSA key")
+		}
+		a.keyFunc = func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+				return nil, errors.Errorf("unexpected signing method %s", token.Header["alg"])
+			}
+			return key, nil
+		}
+	}
+	if len(a.apiKeys) == 0 && a.keyFunc == nil {
+		return nil, nil
+	}
+	return a, nil
+}
+
+// authenticate checks the credentials of a request of the method, all requests pass if the authenticator is nil
+func (a *authenticator) authenticate(ctx context.Context, method string) error {
+	if a == nil || !protectedMethods[method] {
+		return nil
+	}
+	origin, _ := getRequestOrigin(ctx)
+	if origin.apiKey != "" {
+		if _, ok := a.apiKeys[origin.apiKey]; ok {
+			return nil
+		}
+	}
+	if origin.token != "" && a.keyFunc != nil {
+		err := a.verifyToken(origin.token)
+		if err == nil {
+			return nil
+		}
+		return errors.Wrapf(ErrUnauthenticated, "invalid token: %v", err)
+	}
+	return errors.Wrapf(ErrUnauthenticated, "%s needs an api key or a token", method)
+}
+
+func (a *authenticator) verifyToken(raw string) error {
+	claims := &jwt.StandardClaims{}
+	// the expiration and the not-before time are checked by the parser
+	if _, err := jwt.ParseWithClaims(raw, claims, a.keyFunc); err != nil {
+		return err
+	}
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return errors.Errorf("unexpected issuer %s", claims.Issuer)
+	}
+	return nil
+}
+
+// unaryInterceptor rejects the unauthenticated unary grpc requests of protected methods
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authenticate(grpcRequestOrigin(ctx), path.Base(info.FullMethod)); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor rejects the unauthenticated grpc streams of protected methods
+func (a *authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authenticate(grpcRequestOrigin(ss.Context()), path.Base(info.FullMethod)); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return handler(srv, ss)
+}
+
+// bearerToken returns the token of an authorization value of the bearer scheme
+func bearerToken(authorization string) string {
+	const prefix = "bearer "
+	if len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return strings.TrimSpace(authorization[len(prefix):])
+	}
+	return ""
+}
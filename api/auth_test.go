@@ -0,0 +1,130 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestAuthenticator(t *testing.T) {
+	require := require.New(t)
+
+	var disabled *authenticator
+	a, err := newAuthenticator(config.Auth{})
+	require.NoError(err)
+	require.Nil(a)
+	require.NoError(disabled.authenticate(context.Background(), "SendAction"))
+	_, err = newAuthenticator(config.Auth{JWTSecret: "secret", JWTPublicKeyFile: "key.pem"})
+	require.Error(err)
+
+	a, err = newAuthenticator(config.Auth{APIKeys: []string{"key"}, JWTSecret: "secret", JWTIssuer: "iotex"})
+	require.NoError(err)
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.StandardClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(err)
+		return token
+	}
+	// unprotected methods need no credentials
+	require.NoError(a.authenticate(context.Background(), "GetAccount"))
+	require.Equal(ErrUnauthenticated, errors.Cause(a.authenticate(context.Background(), "SendAction")))
+	require.NoError(a.authenticate(withRequestOrigin(context.Background(), "1.2.3.4", "key"), "SendAction"))
+	require.Error(a.authenticate(withRequestOrigin(context.Background(), "1.2.3.4", "other"), "eth_sendRawTransaction"))
+
+	valid := sign(jwt.SigningMethodHS256, []byte("secret"), jwt.StandardClaims{
+		Issuer:    "iotex",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(a.authenticate(withRequestCredentials(context.Background(), "1.2.3.4", "", valid), "debug_traceCall"))
+	for _, token := range []string{
+		sign(jwt.SigningMethodHS256, []byte("wrong"), jwt.StandardClaims{Issuer: "iotex"}),
+		sign(jwt.SigningMethodHS256, []byte("secret"), jwt.StandardClaims{Issuer: "other"}),
+		sign(jwt.SigningMethodHS256, []byte("secret"), jwt.StandardClaims{Issuer: "iotex", ExpiresAt: time.Now().Add(-time.Hour).Unix()}),
+		"malformed",
+	} {
+		err := a.authenticate(withRequestCredentials(context.Background(), "1.2.3.4", "", token), "debug_traceCall")
+		require.Equal(ErrUnauthenticated, errors.Cause(err))
+	}
+
+	t.Run("public key", func(t *testing.T) {
+		sk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(err)
+		der, err := x509.MarshalPKIXPublicKey(&sk.PublicKey)
+		require.NoError(err)
+		file, err := testutil.PathOfTempFile("jwt.pem")
+		require.NoError(err)
+		defer testutil.CleanupPath(t, file)
+		require.NoError(ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+		a, err := newAuthenticator(config.Auth{JWTPublicKeyFile: file})
+		require.NoError(err)
+		token := sign(jwt.SigningMethodES256, sk, jwt.StandardClaims{Subject: "client"})
+		require.NoError(a.authenticate(withRequestCredentials(context.Background(), "1.2.3.4", "", token), "SendAction"))
+		// a token signed by hmac with the public key as the secret is rejected
+		token = sign(jwt.SigningMethodHS256, der, jwt.StandardClaims{})
+		require.Error(a.authenticate(withRequestCredentials(context.Background(), "1.2.3.4", "", token), "SendAction"))
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, "Bearer "+valid))
+		handler := func(context.Context, interface{}) (interface{}, error) {
+			return "ok", nil
+		}
+		res, err := a.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/iotexapi.APIService/SendAction"}, handler)
+		require.NoError(err)
+		require.Equal("ok", res)
+		_, err = a.unaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/iotexapi.APIService/SendAction"}, handler)
+		require.Equal(codes.Unauthenticated, status.Code(err))
+		// the traces replay the blocks as the debug methods of web3 do
+		for _, method := range []string{"TraceAction", "TraceBlock"} {
+			_, err = a.unaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/apipb.ExtensionService/" + method}, handler)
+			require.Equal(codes.Unauthenticated, status.Code(err))
+		}
+		require.Equal(methodCosts["debug_traceTransaction"], methodCosts["TraceAction"])
+		require.Equal(methodCosts["debug_traceBlockByNumber"], methodCosts["TraceBlock"])
+	})
+
+	t.Run("web3", func(t *testing.T) {
+		cfg := newConfig(t)
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+		svr.auth = a
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "eth_sendRawTransaction", "0x00")
+		require.Equal(-32001, res.Error.Code)
+		require.Nil(web3Call(t, web3, "eth_blockNumber").Error)
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(AuthorizationHeader, "Bearer "+valid)
+		origin, ok := getRequestOrigin(withHTTPRequestOrigin(context.Background(), req))
+		require.True(ok)
+		require.Equal(valid, origin.token)
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+)
+
+// BatchRead runs the read requests of the batch in order. A failed request doesn't fail the batch, its status is
+// returned in its response instead. Each request is charged to the rate limit of the client on its own.
+func (api *Server) BatchRead(ctx context.Context, in *apipb.BatchReadRequest) (*apipb.BatchReadResponse, error) {
+	requests := in.GetRequests()
+	if len(requests) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty batch")
+	}
+	if limit := api.cfg.API.BatchRequestLimit; limit > 0 && len(requests) > limit {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d requests exceeds the limit %d", len(requests), limit)
+	}
+	originCtx := grpcRequestOrigin(ctx)
+	res := &apipb.BatchReadResponse{Responses: make([]*apipb.ReadResponse, 0, len(requests))}
+	for _, req := range requests {
+		resp, err := api.read(ctx, originCtx, req)
+		if err != nil {
+			st := status.Convert(err)
+			resp = &apipb.ReadResponse{Code: uint32(st.Code()), Message: st.Message()}
+		}
+		res.Responses = append(res.Responses, resp)
+	}
+	return res, nil
+}
+
+func (api *Server) read(ctx, originCtx context.Context, req *apipb.ReadRequest) (*apipb.ReadResponse, error) {
+	var (
+		method string
+		run    func() (*apipb.ReadResponse, error)
+	)
+	switch r := req.GetRequest().(type) {
+	case *apipb.ReadRequest_GetAccount:
+		method = "GetAccount"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.GetAccount(ctx, r.GetAccount)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_GetAccount{GetAccount: res}}, err
+		}
+	case *apipb.ReadRequest_GetActions:
+		method = "GetActions"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.GetActions(ctx, r.GetActions)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_GetActions{GetActions: res}}, err
+		}
+	case *apipb.ReadRequest_GetBlockMetas:
+		method = "GetBlockMetas"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.GetBlockMetas(ctx, r.GetBlockMetas)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_GetBlockMetas{GetBlockMetas: res}}, err
+		}
+	case *apipb.ReadRequest_GetChainMeta:
+		method = "GetChainMeta"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.GetChainMeta(ctx, r.GetChainMeta)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_GetChainMeta{GetChainMeta: res}}, err
+		}
+	case *apipb.ReadRequest_GetReceiptByAction:
+		method = "GetReceiptByAction"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.GetReceiptByAction(ctx, r.GetReceiptByAction)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_GetReceiptByAction{GetReceiptByAction: res}}, err
+		}
+	case *apipb.ReadRequest_ReadContract:
+		method = "ReadContract"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.ReadContract(ctx, r.ReadContract)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_ReadContract{ReadContract: res}}, err
+		}
+	case *apipb.ReadRequest_ReadState:
+		method = "ReadState"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.ReadState(ctx, r.ReadState)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_ReadState{ReadState: res}}, err
+		}
+	case *apipb.ReadRequest_EstimateActionGasConsumption:
+		method = "EstimateActionGasConsumption"
+		run = func() (*apipb.ReadResponse, error) {
+			res, err := api.EstimateActionGasConsumption(ctx, r.EstimateActionGasConsumption)
+			return &apipb.ReadResponse{Response: &apipb.ReadResponse_EstimateActionGasConsumption{EstimateActionGasConsumption: res}}, err
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "unknown read request")
+	}
+	if err := api.limiter.allow(originCtx, method); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return run()
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_BatchRead(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	cfg.API.BatchRequestLimit = 3
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	ctx := context.Background()
+
+	addr := identityset.Address(27).String()
+	res, err := svr.BatchRead(ctx, &apipb.BatchReadRequest{Requests: []*apipb.ReadRequest{
+		{Request: &apipb.ReadRequest_GetAccount{GetAccount: &iotexapi.GetAccountRequest{Address: addr}}},
+		{Request: &apipb.ReadRequest_GetAccount{GetAccount: &iotexapi.GetAccountRequest{Address: "invalid"}}},
+		{Request: &apipb.ReadRequest_GetChainMeta{GetChainMeta: &iotexapi.GetChainMetaRequest{}}},
+	}})
+	require.NoError(err)
+	require.Len(res.Responses, 3)
+	account, err := svr.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: addr})
+	require.NoError(err)
+	require.EqualValues(codes.OK, res.Responses[0].Code)
+	require.Equal(account.AccountMeta.Balance, res.Responses[0].GetGetAccount().AccountMeta.Balance)
+	require.NotEqual(codes.OK, codes.Code(res.Responses[1].Code))
+	require.Nil(res.Responses[1].GetResponse())
+	require.EqualValues(svr.bc.TipHeight(), res.Responses[2].GetGetChainMeta().ChainMeta.Height)
+
+	_, err = svr.BatchRead(ctx, &apipb.BatchReadRequest{})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.BatchRead(ctx, &apipb.BatchReadRequest{Requests: make([]*apipb.ReadRequest, 4)})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	res, err = svr.BatchRead(ctx, &apipb.BatchReadRequest{Requests: []*apipb.ReadRequest{{}}})
+	require.NoError(err)
+	require.EqualValues(codes.InvalidArgument, res.Responses[0].Code)
+}
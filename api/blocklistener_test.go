@@ -60,3 +60,90 @@ func TestBlockListener(t *testing.T) {
 	require.Equal(t, errorSend, <-errChan)
 	require.NoError(t, <-errChan)
 }
+
+type blockingResponder struct {
+	received chan *block.Block
+	release  chan struct{}
+	exited   bool
+}
+
+func (r *blockingResponder) Respond(blk *block.Block) error {
+	<-r.release
+	r.received <- blk
+	return nil
+}
+
+func (r *blockingResponder) Exit() {
+	r.exited = true
+}
+
+func TestBufferedResponder(t *testing.T) {
+	require := require.New(t)
+	blk, err := block.NewTestingBuilder().
+		SetHeight(1).
+		SetTimeStamp(time.Now()).
+		SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+
+	inner := &blockingResponder{
+		received: make(chan *block.Block, 10),
+		release:  make(chan struct{}),
+	}
+	br := newBufferedResponder(inner, 2, 0)
+	// the first block is being delivered, two more fill up the queue
+	require.NoError(br.Respond(&blk))
+	require.Eventually(func() bool { return len(br.queue) == 0 }, time.Second, 10*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		require.NoError(br.Respond(&blk))
+	}
+	require.Equal(errStreamLagging, br.Respond(&blk))
+	<-br.Done()
+	require.Equal(errStreamLagging, br.Err())
+	require.Equal(errStreamClosed, br.Respond(&blk))
+	close(inner.release)
+
+	inner = &blockingResponder{
+		received: make(chan *block.Block, 10),
+		release:  make(chan struct{}),
+	}
+	close(inner.release)
+	br = newBufferedResponder(inner, 2, 0)
+	require.NoError(br.Respond(&blk))
+	require.Equal(&blk, <-inner.received)
+	br.Exit()
+	require.True(inner.exited)
+	require.NoError(br.Err())
+
+	// with a timeout, a full queue waits for the client to catch up
+	inner = &blockingResponder{
+		received: make(chan *block.Block, 10),
+		release:  make(chan struct{}),
+	}
+	br = newBufferedResponder(inner, 1, time.Second)
+	require.NoError(br.Respond(&blk))
+	require.Eventually(func() bool { return len(br.queue) == 0 }, time.Second, 10*time.Millisecond)
+	require.NoError(br.Respond(&blk))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(inner.release)
+	}()
+	require.NoError(br.Respond(&blk))
+	for i := 0; i < 3; i++ {
+		require.Equal(&blk, <-inner.received)
+	}
+	br.Exit()
+	require.NoError(br.Err())
+
+	// and drops it once the timeout expires
+	inner = &blockingResponder{
+		received: make(chan *block.Block, 10),
+		release:  make(chan struct{}),
+	}
+	br = newBufferedResponder(inner, 1, 50*time.Millisecond)
+	require.NoError(br.Respond(&blk))
+	require.Eventually(func() bool { return len(br.queue) == 0 }, time.Second, 10*time.Millisecond)
+	require.NoError(br.Respond(&blk))
+	require.Equal(errStreamLagging, br.Respond(&blk))
+	require.Equal(errStreamLagging, br.Err())
+	close(inner.release)
+}
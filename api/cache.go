@@ -0,0 +1,167 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// backends of the response cache
+const (
+	MemoryCacheBackend = "memory"
+	RedisCacheBackend  = "redis"
+)
+
+var responseCacheMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_api_response_cache",
+		Help: "Lookups of the api response cache",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(responseCacheMtc)
+}
+
+type (
+	// cacheBackend stores serialized responses
+	cacheBackend interface {
+		Get(key string) ([]byte, bool)
+		Set(key string, value []byte)
+	}
+
+	memCacheBackend struct {
+		lru *cache.ThreadSafeLruCache
+	}
+
+	redisCacheBackend struct {
+		client *redis.Client
+		ttl    time.Duration
+	}
+
+	// responseCache caches the responses of immutable queries, which are about blocks that can no longer change.
+	// Responses about the most recent blocks are not cached.
+	responseCache struct {
+		backend   cacheBackend
+		prefix    string
+		tipDepth  uint64
+		tipHeight func() uint64
+	}
+)
+
+// newResponseCache creates a response cache, it returns nil if the cache is disabled
+func newResponseCache(cfg config.ResponseCache, chainID uint32, tipHeight func() uint64) (*responseCache, error) {
+	var backend cacheBackend
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case MemoryCacheBackend:
+		if cfg.Size <= 0 {
+			return nil, errors.Errorf("invalid response cache size %d", cfg.Size)
+		}
+		backend = &memCacheBackend{lru: cache.NewThreadSafeLruCache(cfg.Size)}
+	case RedisCacheBackend:
+		backend = &redisCacheBackend{
+			client: redis.NewClient(&redis.Options{Addr: cfg.RedisAddr}),
+			ttl:    cfg.RedisTTL,
+		}
+	default:
+		return nil, errors.Errorf("unknown response cache backend %s", cfg.Backend)
+	}
+	return &responseCache{
+		backend:   backend,
+		prefix:    fmt.Sprintf("iotex-api:%d:", chainID),
+		tipDepth:  cfg.TipDepth,
+		tipHeight: tipHeight,
+	}, nil
+}
+
+// get reads the cached response of the key into msg, it always misses if the cache is nil
+func (c *responseCache) get(key string, msg proto.Message) bool {
+	if c == nil {
+		return false
+	}
+	data, ok := c.backend.Get(c.prefix + key)
+	if ok {
+		if err := proto.Unmarshal(data, msg); err == nil {
+			responseCacheMtc.WithLabelValues("hit").Inc()
+			return true
+		}
+	}
+	responseCacheMtc.WithLabelValues("miss").Inc()
+	return false
+}
+
+// put caches the response of the key, which is about the block at height
+func (c *responseCache) put(key string, height uint64, msg proto.Message) {
+	if c == nil || height+c.tipDepth > c.tipHeight() {
+		return
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		log.L().Debug("failed to serialize response.", zap.Error(err))
+		return
+	}
+	c.backend.Set(c.prefix+key, data)
+}
+
+func (b *memCacheBackend) Get(key string) ([]byte, bool) {
+	v, ok := b.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v.([]byte), true
+}
+
+func (b *memCacheBackend) Set(key string, value []byte) {
+	b.lru.Add(key, value)
+}
+
+func (b *redisCacheBackend) Get(key string) ([]byte, bool) {
+	data, err := b.client.Get(key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.L().Debug("failed to read response cache.", zap.Error(err))
+		}
+		return nil, false
+	}
+	return data, true
+}
+
+func (b *redisCacheBackend) Set(key string, value []byte) {
+	if err := b.client.Set(key, value, b.ttl).Err(); err != nil {
+		log.L().Debug("failed to write response cache.", zap.Error(err))
+	}
+}
+
+// logsCacheKey returns the cache key of a logs request, which is empty if the range of the request follows the tip
+func logsCacheKey(in *iotexapi.GetLogsRequest) (string, error) {
+	if in.GetByRange() != nil && in.GetByRange().GetToBlock() == 0 {
+		return "", nil
+	}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		return "", err
+	}
+	h := hash.Hash256b(data)
+	return "logs:" + hex.EncodeToString(h[:]), nil
+}
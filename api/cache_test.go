@@ -0,0 +1,186 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestResponseCache(t *testing.T) {
+	require := require.New(t)
+
+	c, err := newResponseCache(config.ResponseCache{}, 1, nil)
+	require.NoError(err)
+	require.Nil(c)
+	require.False(c.get("key", &iotextypes.BlockMeta{}))
+	c.put("key", 1, &iotextypes.BlockMeta{})
+	_, err = newResponseCache(config.ResponseCache{Backend: "unknown"}, 1, nil)
+	require.Error(err)
+	_, err = newResponseCache(config.ResponseCache{Backend: MemoryCacheBackend}, 1, nil)
+	require.Error(err)
+
+	tip := uint64(10)
+	c, err = newResponseCache(config.ResponseCache{Backend: MemoryCacheBackend, Size: 10, TipDepth: 2}, 1, func() uint64 {
+		return tip
+	})
+	require.NoError(err)
+	meta := &iotextypes.BlockMeta{Height: 8, Hash: "abcd"}
+	c.put("near tip", 9, meta)
+	require.False(c.get("near tip", &iotextypes.BlockMeta{}))
+	c.put("final", 8, meta)
+	cached := &iotextypes.BlockMeta{}
+	require.True(c.get("final", cached))
+	require.True(proto.Equal(meta, cached))
+
+	// keys are namespaced by chain
+	other, err := newResponseCache(config.ResponseCache{Backend: MemoryCacheBackend, Size: 10}, 2, func() uint64 {
+		return tip
+	})
+	require.NoError(err)
+	other.backend = c.backend
+	require.False(other.get("final", cached))
+
+	key, err := logsCacheKey(&iotexapi.GetLogsRequest{
+		Filter: &iotexapi.LogsFilter{},
+		Lookup: &iotexapi.GetLogsRequest_ByRange{ByRange: &iotexapi.GetLogsByRange{FromBlock: 1}},
+	})
+	require.NoError(err)
+	require.Empty(key)
+	key, err = logsCacheKey(&iotexapi.GetLogsRequest{
+		Filter: &iotexapi.LogsFilter{},
+		Lookup: &iotexapi.GetLogsRequest_ByRange{ByRange: &iotexapi.GetLogsByRange{FromBlock: 1, ToBlock: 2}},
+	})
+	require.NoError(err)
+	require.NotEmpty(key)
+
+	t.Run("server", func(t *testing.T) {
+		cfg := newConfig(t)
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+		svr.cache, err = newResponseCache(cfg.API.ResponseCache, cfg.Chain.ID, svr.bc.TipHeight)
+		require.NoError(err)
+
+		req := &iotexapi.GetRawBlocksRequest{StartHeight: 1, Count: 10, WithReceipts: true}
+		res, err := svr.GetRawBlocks(context.Background(), req)
+		require.NoError(err)
+		tipHeight := svr.bc.TipHeight()
+		for h := uint64(1); h <= tipHeight; h++ {
+			_, ok := svr.cache.backend.Get(svr.cache.prefix + fmt.Sprintf("rawblock:%d:true:false", h))
+			// the tip block is not cached
+			require.Equal(h < tipHeight, ok)
+		}
+		cachedRes, err := svr.GetRawBlocks(context.Background(), req)
+		require.NoError(err)
+		require.True(proto.Equal(res, cachedRes))
+
+		metas, err := svr.GetBlockMetas(context.Background(), &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: 1, Count: 10}},
+		})
+		require.NoError(err)
+		cachedMetas, err := svr.GetBlockMetas(context.Background(), &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: 1, Count: 10}},
+		})
+		require.NoError(err)
+		require.True(proto.Equal(metas, cachedMetas))
+	})
+
+	t.Run("reorg", func(t *testing.T) {
+		cfg := newConfig(t)
+		cfg.Plugins = make(map[int]interface{})
+		cfg.Chain.MaxReorgDepth = 2
+		cfg.API.ResponseCache.TipDepth = 2
+		require.NoError(config.ValidateReorg(cfg))
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+		svr.cache, err = newResponseCache(cfg.API.ResponseCache, cfg.Chain.ID, svr.bc.TipHeight)
+		require.NoError(err)
+
+		// a side chain forks at the max reorg depth below the tip
+		sideCfg := newConfig(t)
+		sideCfg.Plugins = make(map[int]interface{})
+		sideCfg.Chain.MaxReorgDepth = 2
+		bc, sideDAO, _, _, _, _, _, sideBfIndexFile, err := setupChain(sideCfg)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, sideBfIndexFile)
+		}()
+		require.NoError(bc.Start(context.Background()))
+		defer func() {
+			require.NoError(bc.Stop(context.Background()))
+		}()
+		tipHeight := svr.bc.TipHeight()
+		forkHeight := tipHeight - cfg.Chain.MaxReorgDepth
+		for h := uint64(1); h <= forkHeight; h++ {
+			blk, err := svr.dao.GetBlockByHeight(h)
+			require.NoError(err)
+			require.NoError(bc.ValidateBlock(blk))
+			require.NoError(bc.CommitBlock(blk))
+		}
+		tip, err := svr.dao.GetBlockByHeight(tipHeight)
+		require.NoError(err)
+		for h := forkHeight + 1; h <= tipHeight+1; h++ {
+			blk, err := bc.MintNewBlock(tip.Timestamp().Add(time.Duration(h) * time.Second))
+			require.NoError(err)
+			require.NoError(bc.CommitBlock(blk))
+		}
+
+		// the responses about every block are read before the reorg
+		req := &iotexapi.GetRawBlocksRequest{StartHeight: 1, Count: tipHeight, WithReceipts: true}
+		_, err = svr.GetRawBlocks(context.Background(), req)
+		require.NoError(err)
+		metasReq := &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: 1, Count: tipHeight}},
+		}
+		_, err = svr.GetBlockMetas(context.Background(), metasReq)
+		require.NoError(err)
+		for h := forkHeight + 1; h <= tipHeight+1; h++ {
+			blk, err := sideDAO.GetBlockByHeight(h)
+			require.NoError(err)
+			err = svr.bc.ValidateBlock(blk)
+			if h <= tipHeight {
+				require.Equal(blockchain.ErrForkBlock, errors.Cause(err))
+				continue
+			}
+			require.NoError(err)
+			require.NoError(svr.bc.CommitBlock(blk))
+		}
+		require.Equal(bc.TipHash(), svr.bc.TipHash())
+
+		// the responses about the reverted blocks are read from the side chain
+		res, err := svr.GetRawBlocks(context.Background(), req)
+		require.NoError(err)
+		metas, err := svr.GetBlockMetas(context.Background(), metasReq)
+		require.NoError(err)
+		for h := uint64(1); h <= tipHeight; h++ {
+			blk, err := sideDAO.GetBlockByHeight(h)
+			require.NoError(err)
+			blkHash := blk.HashBlock()
+			require.True(proto.Equal(blk.ConvertToBlockPb(), res.Blocks[h-1].Block))
+			require.Equal(hex.EncodeToString(blkHash[:]), metas.BlkMetas[h-1].Hash)
+		}
+	})
+}
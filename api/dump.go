@@ -0,0 +1,97 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type (
+	// web3AccountDump is the result of iotex_dumpAccounts
+	web3AccountDump struct {
+		Accounts []*web3DumpedAccount `json:"accounts"`
+		Next     *hexutil.Bytes       `json:"next"`
+	}
+
+	web3DumpedAccount struct {
+		Key         hexutil.Bytes   `json:"key"`
+		Address     *common.Address `json:"address"`
+		Balance     *hexutil.Big    `json:"balance"`
+		Nonce       hexutil.Uint64  `json:"nonce"`
+		CodeHash    hexutil.Bytes   `json:"codeHash"`
+		StorageRoot common.Hash     `json:"storageRoot"`
+	}
+)
+
+// DumpAccounts returns a page of the accounts of the state at height, and the cursor of the next page, which is nil if
+// there are no more accounts. The states below the tip height are only available when they are retained.
+func (api *Server) DumpAccounts(height uint64, cursor []byte, limit uint64) ([]*factory.AccountEntry, []byte, error) {
+	dumper, ok := api.sf.(factory.AccountDumper)
+	if !ok {
+		return nil, nil, status.Error(codes.Unimplemented, "account dump is not supported by the state factory")
+	}
+	if limit == 0 || limit > api.cfg.API.RangeQueryLimit {
+		return nil, nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	if tipHeight := api.bc.TipHeight(); height > tipHeight {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "query height %d is higher than tip height %d", height, tipHeight)
+	}
+	entries, next, err := dumper.DumpAccounts(height, cursor, limit)
+	if err != nil {
+		return nil, nil, historyError(err)
+	}
+	return entries, next, nil
+}
+
+func (svr *Web3Server) dumpAccounts(params []json.RawMessage) (interface{}, error) {
+	var (
+		blkNum string
+		cursor hexutil.Bytes
+		limit  hexutil.Uint64
+	)
+	if err := parseWeb3Params(params, 1, &blkNum, &cursor, &limit); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = hexutil.Uint64(svr.api.cfg.API.RangeQueryLimit)
+	}
+	entries, next, err := svr.api.DumpAccounts(height, cursor, uint64(limit))
+	if err != nil {
+		return nil, err
+	}
+	ret := &web3AccountDump{Accounts: make([]*web3DumpedAccount, 0, len(entries))}
+	for _, entry := range entries {
+		acct := &web3DumpedAccount{
+			Key:         entry.Key,
+			Balance:     (*hexutil.Big)(entry.Account.Balance),
+			Nonce:       hexutil.Uint64(entry.Account.Nonce),
+			CodeHash:    entry.Account.CodeHash,
+			StorageRoot: common.BytesToHash(entry.Account.Root[:]),
+		}
+		if entry.Address != nil {
+			addr := common.BytesToAddress(entry.Address[:])
+			acct.Address = &addr
+		}
+		ret.Accounts = append(ret.Accounts, acct)
+	}
+	if next != nil {
+		n := hexutil.Bytes(next)
+		ret.Next = &n
+	}
+	return ret, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_DumpAccounts(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tip := svr.bc.TipHeight()
+
+	balances := make(map[hash.Hash160]*big.Int)
+	var (
+		cursor []byte
+		pages  int
+	)
+	for {
+		entries, next, err := svr.DumpAccounts(tip, cursor, 5)
+		require.NoError(err)
+		pages++
+		for _, entry := range entries {
+			require.NotNil(entry.Address)
+			balances[*entry.Address] = entry.Account.Balance
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	require.True(pages > 1)
+	expected, err := accountutil.AccountState(svr.sf, identityset.Address(27).String())
+	require.NoError(err)
+	require.Equal(expected.Balance, balances[hash.BytesToHash160(identityset.Address(27).Bytes())])
+
+	_, _, err = svr.DumpAccounts(tip, nil, 0)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, _, err = svr.DumpAccounts(tip+1, nil, 5)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, _, err = svr.DumpAccounts(tip-1, nil, 5)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "iotex_dumpAccounts", "latest", "0x", "0x2")
+		require.Nil(res.Error)
+		var result web3AccountDump
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Len(result.Accounts, 2)
+		require.NotNil(result.Next)
+		res = web3Call(t, web3, "iotex_dumpAccounts", "latest", result.Next.String(), "0x2")
+		require.Nil(res.Error)
+	})
+}
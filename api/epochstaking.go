@@ -0,0 +1,117 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/db"
+)
+
+// GetEpochCandidates returns the staking candidates of the snapshot of an epoch, which is taken at the end of the
+// epoch and elects the delegates of the next epoch. The snapshots are kept by the staking indexer, so voting history
+// is read without replaying the chain.
+func (api *Server) GetEpochCandidates(ctx context.Context, epoch uint64) (*iotextypes.CandidateListV2, error) {
+	height, err := api.epochSnapshotHeight(epoch)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := api.candBucketsIndexer.Candidates(height)
+	if err != nil {
+		return nil, epochSnapshotError(epoch, err)
+	}
+	return candidates, nil
+}
+
+// GetEpochBuckets returns a page of the vote buckets of the snapshot of an epoch, the page is capped by the range
+// query limit
+func (api *Server) GetEpochBuckets(ctx context.Context, epoch uint64, offset, limit uint64) (*iotextypes.VoteBucketList, error) {
+	if limit == 0 || limit > api.cfg.API.RangeQueryLimit {
+		return nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	height, err := api.epochSnapshotHeight(epoch)
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := api.candBucketsIndexer.Buckets(height)
+	if err != nil {
+		return nil, epochSnapshotError(epoch, err)
+	}
+	length := uint64(len(buckets.Buckets))
+	if offset >= length {
+		return &iotextypes.VoteBucketList{}, nil
+	}
+	end := offset + limit
+	if end > length {
+		end = length
+	}
+	buckets.Buckets = buckets.Buckets[offset:end]
+	return buckets, nil
+}
+
+// epochSnapshotHeight returns the height the snapshot of the epoch is indexed at
+func (api *Server) epochSnapshotHeight(epoch uint64) (uint64, error) {
+	if api.candBucketsIndexer == nil {
+		return 0, status.Error(codes.Unavailable, "staking indexer is disabled")
+	}
+	if epoch == 0 {
+		return 0, status.Error(codes.InvalidArgument, "epoch should be positive")
+	}
+	rp := rolldpos.FindProtocol(api.registry)
+	if rp == nil {
+		return 0, status.Error(codes.Internal, "rolldpos is not registered")
+	}
+	return rp.GetEpochHeight(epoch), nil
+}
+
+func epochSnapshotError(epoch uint64, err error) error {
+	if errors.Cause(err) == db.ErrNotExist {
+		// the snapshot is taken when the next epoch starts
+		return status.Errorf(codes.NotFound, "snapshot of epoch %d is not indexed", epoch)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// getEpochCandidates returns the candidates of the snapshot of the epoch in the path
+func (svr *RESTServer) getEpochCandidates(ctx context.Context, _ *http.Request, path string) (proto.Message, error) {
+	epoch, err := strconv.ParseUint(path, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid epoch "+path)
+	}
+	return svr.api.GetEpochCandidates(ctx, epoch)
+}
+
+// getEpochBuckets returns a page of the vote buckets of the snapshot of the epoch in the path
+func (svr *RESTServer) getEpochBuckets(ctx context.Context, req *http.Request, path string) (proto.Message, error) {
+	epoch, err := strconv.ParseUint(path, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid epoch "+path)
+	}
+	query := req.URL.Query()
+	var offset uint64
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid offset %s", v)
+		}
+	}
+	limit := svr.api.cfg.API.RangeQueryLimit
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid limit %s", v)
+		}
+	}
+	return svr.api.GetEpochBuckets(ctx, epoch, offset, limit)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol/staking"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetEpochCandidates(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	_, err = svr.GetEpochCandidates(ctx, 1)
+	require.Equal(codes.Unavailable, status.Code(err))
+
+	cbi, err := staking.NewStakingCandidatesBucketsIndexer(db.NewMemKVStore())
+	require.NoError(err)
+	require.NoError(cbi.Start(ctx))
+	defer func() {
+		require.NoError(cbi.Stop(ctx))
+	}()
+	svr.candBucketsIndexer = cbi
+	// the snapshot of epoch 1 is indexed at its start height
+	require.NoError(cbi.PutCandidates(1, &iotextypes.CandidateListV2{
+		Candidates: []*iotextypes.CandidateV2{{Name: "alice"}, {Name: "bob"}},
+	}))
+	require.NoError(cbi.PutBuckets(1, &iotextypes.VoteBucketList{
+		Buckets: []*iotextypes.VoteBucket{{Index: 0}, {Index: 1}, {Index: 2}},
+	}))
+
+	candidates, err := svr.GetEpochCandidates(ctx, 1)
+	require.NoError(err)
+	require.Len(candidates.Candidates, 2)
+	require.Equal("bob", candidates.Candidates[1].Name)
+	_, err = svr.GetEpochCandidates(ctx, 0)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.GetEpochCandidates(ctx, 2)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	buckets, err := svr.GetEpochBuckets(ctx, 1, 1, 5)
+	require.NoError(err)
+	require.Len(buckets.Buckets, 2)
+	require.EqualValues(1, buckets.Buckets[0].Index)
+	buckets, err = svr.GetEpochBuckets(ctx, 1, 3, 5)
+	require.NoError(err)
+	require.Empty(buckets.Buckets)
+	_, err = svr.GetEpochBuckets(ctx, 1, 0, cfg.API.RangeQueryLimit+1)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	t.Run("rest", func(t *testing.T) {
+		rest, err := NewRESTServer(svr, 0)
+		require.NoError(err)
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/epochs/candidates/1", nil))
+		require.Equal(http.StatusOK, rec.Code)
+		res := &iotextypes.CandidateListV2{}
+		require.NoError(jsonpb.Unmarshal(rec.Body, res))
+		require.Len(res.Candidates, 2)
+
+		rec = httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/epochs/buckets/1?limit=2", nil))
+		require.Equal(http.StatusOK, rec.Code)
+		bucketsRes := &iotextypes.VoteBucketList{}
+		require.NoError(jsonpb.Unmarshal(rec.Body, bucketsRes))
+		require.Len(bucketsRes.Buckets, 2)
+
+		rec = httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/epochs/candidates/2", nil))
+		require.Equal(http.StatusNotFound, rec.Code)
+	})
+}
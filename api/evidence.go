@@ -0,0 +1,117 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+)
+
+// web3Evidence is an evidence in the result of iotex_getEquivocationEvidences, the messages are the serialized
+// consensus messages signed by the delegate
+type web3Evidence struct {
+	Height    hexutil.Uint64  `json:"height"`
+	Endorser  string          `json:"endorser"`
+	Timestamp hexutil.Uint64  `json:"timestamp"`
+	Messages  []hexutil.Bytes `json:"messages"`
+}
+
+// GetEquivocationEvidences returns the evidences of the equivocation of the delegates from a height on
+func (api *Server) GetEquivocationEvidences(
+	_ context.Context,
+	in *apipb.GetEquivocationEvidencesRequest,
+) (*apipb.GetEquivocationEvidencesResponse, error) {
+	limit := in.GetLimit()
+	if limit == 0 {
+		limit = api.cfg.API.RangeQueryLimit
+	}
+	evidences, err := api.evidences(in.GetHeight(), limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &apipb.GetEquivocationEvidencesResponse{}
+	for _, evidence := range evidences {
+		ts, err := ptypes.TimestampProto(evidence.Timestamp)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		res.Evidences = append(res.Evidences, &apipb.EquivocationEvidence{
+			Height:    evidence.Height,
+			Endorser:  evidence.Endorser,
+			Timestamp: ts,
+			Messages:  evidence.Messages[:],
+		})
+	}
+	return res, nil
+}
+
+// evidences returns at most limit evidences of the equivocation of the delegates from height on
+func (api *Server) evidences(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+	if api.evidenceReader == nil {
+		return nil, status.Error(codes.Unimplemented, "equivocation evidence is not available")
+	}
+	if limit == 0 || limit > api.cfg.API.RangeQueryLimit {
+		return nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	evidences, err := api.evidenceReader.Evidences(height, limit)
+	switch errors.Cause(err) {
+	case nil:
+		return evidences, nil
+	case scheme.ErrEvidenceNotSupported:
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	default:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (svr *Web3Server) getEquivocationEvidences(params []json.RawMessage) (interface{}, error) {
+	var (
+		blkNum string
+		limit  hexutil.Uint64
+	)
+	if err := parseWeb3Params(params, 1, &blkNum, &limit); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = hexutil.Uint64(svr.api.cfg.API.RangeQueryLimit)
+	}
+	evidences, err := svr.api.evidences(height, uint64(limit))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*web3Evidence, 0, len(evidences))
+	for _, evidence := range evidences {
+		e := &web3Evidence{
+			Height:    hexutil.Uint64(evidence.Height),
+			Endorser:  evidence.Endorser,
+			Timestamp: hexutil.Uint64(evidence.Timestamp.Unix()),
+		}
+		for _, msg := range evidence.Messages {
+			value, err := proto.Marshal(msg)
+			if err != nil {
+				return nil, err
+			}
+			e.Messages = append(e.Messages, value)
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type evidenceReaderFunc func(uint64, uint64) ([]*scheme.Evidence, error)
+
+func (f evidenceReaderFunc) Evidences(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+	return f(height, limit)
+}
+
+func TestServer_Evidences(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	_, err = svr.GetEquivocationEvidences(ctx, &apipb.GetEquivocationEvidencesRequest{Height: 1, Limit: 10})
+	require.Equal(codes.Unimplemented, status.Code(err))
+
+	evidence := &scheme.Evidence{
+		Height:    3,
+		Endorser:  identityset.Address(1).String(),
+		Timestamp: time.Unix(1600000000, 0),
+		Messages: [2]*iotextypes.ConsensusMessage{
+			{Height: 3},
+			{Height: 3},
+		},
+	}
+	svr.evidenceReader = evidenceReaderFunc(func(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+		if height > evidence.Height {
+			return nil, nil
+		}
+		return []*scheme.Evidence{evidence}, nil
+	})
+	for _, limit := range []uint64{10, 0} {
+		res, err := svr.GetEquivocationEvidences(ctx, &apipb.GetEquivocationEvidencesRequest{Height: 1, Limit: limit})
+		require.NoError(err)
+		require.Len(res.Evidences, 1)
+		require.Equal(evidence.Height, res.Evidences[0].Height)
+		require.Equal(evidence.Endorser, res.Evidences[0].Endorser)
+		require.Equal(evidence.Timestamp.Unix(), res.Evidences[0].Timestamp.Seconds)
+		require.Len(res.Evidences[0].Messages, 2)
+	}
+	res, err := svr.GetEquivocationEvidences(ctx, &apipb.GetEquivocationEvidencesRequest{Height: 4, Limit: 10})
+	require.NoError(err)
+	require.Empty(res.Evidences)
+	_, err = svr.GetEquivocationEvidences(ctx, &apipb.GetEquivocationEvidencesRequest{
+		Height: 1,
+		Limit:  cfg.API.RangeQueryLimit + 1,
+	})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	svr.evidenceReader = evidenceReaderFunc(func(uint64, uint64) ([]*scheme.Evidence, error) {
+		return nil, scheme.ErrEvidenceNotSupported
+	})
+	_, err = svr.GetEquivocationEvidences(ctx, &apipb.GetEquivocationEvidencesRequest{Height: 1, Limit: 10})
+	require.Equal(codes.Unimplemented, status.Code(err))
+
+	t.Run("web3", func(t *testing.T) {
+		svr.evidenceReader = evidenceReaderFunc(func(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+			return []*scheme.Evidence{evidence}, nil
+		})
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "iotex_getEquivocationEvidences", "0x1")
+		require.Nil(res.Error)
+		var result []*web3Evidence
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Len(result, 1)
+		require.Equal(uint64(3), uint64(result[0].Height))
+		require.Equal(evidence.Endorser, result[0].Endorser)
+		require.Equal(uint64(1600000000), uint64(result[0].Timestamp))
+		require.Len(result[0].Messages, 2)
+	})
+}
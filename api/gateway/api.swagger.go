@@ -0,0 +1,1970 @@
+// Code generated from the output of protoc-gen-swagger. DO NOT EDIT.
+
+package gateway
+
+// SwaggerJSON is the OpenAPI v2 spec of the gateway
+const SwaggerJSON = `{
+  "swagger": "2.0",
+  "info": {
+    "title": "proto/api/api.proto",
+    "version": "version not set"
+  },
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/accounts/{address}": {
+      "get": {
+        "summary": "get the address detail of an address",
+        "operationId": "APIService_GetAccount",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/iotexapiGetAccountResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "address",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "APIService"
+        ]
+      }
+    },
+    "/actions": {
+      "post": {
+        "summary": "sendAction",
+        "operationId": "APIService_SendAction",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/iotexapiSendActionResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/iotexapiSendActionRequest"
+            }
+          }
+        ],
+        "tags": [
+          "APIService"
+        ]
+      }
+    },
+    "/actions/{byHash.actionHash}": {
+      "get": {
+        "summary": "get action(s) by:\n1. start index and action count\n2. action hash\n3. address with start index and action count\n4. get unconfirmed actions by address with start index and action count\n5. block hash with start index and action count",
+        "operationId": "APIService_GetActions",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/iotexapiGetActionsResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "byHash.actionHash",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "byIndex.start",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "byIndex.count",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "byHash.checkPending",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          },
+          {
+            "name": "byAddr.address",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "byAddr.start",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "byAddr.count",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "unconfirmedByAddr.address",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "unconfirmedByAddr.start",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "unconfirmedByAddr.count",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "byBlk.blkHash",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "byBlk.start",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "byBlk.count",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          }
+        ],
+        "tags": [
+          "APIService"
+        ]
+      }
+    },
+    "/blocks/{startHeight}": {
+      "get": {
+        "summary": "get raw blocks data",
+        "operationId": "APIService_GetRawBlocks",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/iotexapiGetRawBlocksResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "startHeight",
+            "in": "path",
+            "required": true,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "count",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "uint64"
+          },
+          {
+            "name": "withReceipts",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          },
+          {
+            "name": "withTransactionLogs",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          }
+        ],
+        "tags": [
+          "APIService"
+        ]
+      }
+    },
+    "/chainmeta": {
+      "get": {
+        "summary": "get chain metadata",
+        "operationId": "APIService_GetChainMeta",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/iotexapiGetChainMetaResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "tags": [
+          "APIService"
+        ]
+      }
+    },
+    "/receipts/{actionHash}": {
+      "get": {
+        "summary": "get receipt by action Hash",
+        "operationId": "APIService_GetReceiptByAction",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/iotexapiGetReceiptByActionResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "actionHash",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "APIService"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "TransactionLogTransaction": {
+      "type": "object",
+      "properties": {
+        "topic": {
+          "type": "string",
+          "format": "byte"
+        },
+        "amount": {
+          "type": "string"
+        },
+        "sender": {
+          "type": "string"
+        },
+        "recipient": {
+          "type": "string"
+        },
+        "type": {
+          "$ref": "#/definitions/iotextypesTransactionLogType"
+        }
+      }
+    },
+    "iotexapiActionInfo": {
+      "type": "object",
+      "properties": {
+        "action": {
+          "$ref": "#/definitions/iotextypesAction"
+        },
+        "actHash": {
+          "type": "string"
+        },
+        "blkHash": {
+          "type": "string"
+        },
+        "blkHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "sender": {
+          "type": "string"
+        },
+        "gasFee": {
+          "type": "string"
+        },
+        "timestamp": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "iotexapiBlockInfo": {
+      "type": "object",
+      "properties": {
+        "block": {
+          "$ref": "#/definitions/iotextypesBlock"
+        },
+        "receipts": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesReceipt"
+          }
+        },
+        "transactionLogs": {
+          "$ref": "#/definitions/iotextypesTransactionLogs"
+        }
+      }
+    },
+    "iotexapiBlockProducerInfo": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "votes": {
+          "type": "string"
+        },
+        "active": {
+          "type": "boolean"
+        },
+        "production": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiEstimateActionGasConsumptionResponse": {
+      "type": "object",
+      "properties": {
+        "gas": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiEstimateGasForActionResponse": {
+      "type": "object",
+      "properties": {
+        "gas": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiGetAccountResponse": {
+      "type": "object",
+      "properties": {
+        "accountMeta": {
+          "$ref": "#/definitions/iotextypesAccountMeta"
+        },
+        "blockIdentifier": {
+          "$ref": "#/definitions/iotextypesBlockIdentifier"
+        }
+      }
+    },
+    "iotexapiGetActPoolActionsResponse": {
+      "type": "object",
+      "properties": {
+        "actions": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesAction"
+          }
+        }
+      }
+    },
+    "iotexapiGetActionByHashRequest": {
+      "type": "object",
+      "properties": {
+        "actionHash": {
+          "type": "string"
+        },
+        "checkPending": {
+          "type": "boolean"
+        }
+      }
+    },
+    "iotexapiGetActionsByAddressRequest": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "start": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "count": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiGetActionsByBlockRequest": {
+      "type": "object",
+      "properties": {
+        "blkHash": {
+          "type": "string"
+        },
+        "start": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "count": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiGetActionsByIndexRequest": {
+      "type": "object",
+      "properties": {
+        "start": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "count": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiGetActionsResponse": {
+      "type": "object",
+      "properties": {
+        "total": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "actionInfo": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotexapiActionInfo"
+          }
+        }
+      }
+    },
+    "iotexapiGetBlockMetaByHashRequest": {
+      "type": "object",
+      "properties": {
+        "blkHash": {
+          "type": "string"
+        }
+      }
+    },
+    "iotexapiGetBlockMetasByIndexRequest": {
+      "type": "object",
+      "properties": {
+        "start": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "count": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiGetBlockMetasResponse": {
+      "type": "object",
+      "properties": {
+        "total": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "blkMetas": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesBlockMeta"
+          }
+        }
+      }
+    },
+    "iotexapiGetChainMetaResponse": {
+      "type": "object",
+      "properties": {
+        "chainMeta": {
+          "$ref": "#/definitions/iotextypesChainMeta"
+        },
+        "syncStage": {
+          "type": "string"
+        }
+      }
+    },
+    "iotexapiGetElectionBucketsResponse": {
+      "type": "object",
+      "properties": {
+        "buckets": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesElectionBucket"
+          }
+        }
+      }
+    },
+    "iotexapiGetEpochMetaResponse": {
+      "type": "object",
+      "properties": {
+        "epochData": {
+          "$ref": "#/definitions/iotextypesEpochData"
+        },
+        "totalBlocks": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "blockProducersInfo": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotexapiBlockProducerInfo"
+          }
+        }
+      }
+    },
+    "iotexapiGetEvmTransfersByActionHashResponse": {
+      "type": "object",
+      "properties": {
+        "actionEvmTransfers": {
+          "$ref": "#/definitions/iotextypesActionEvmTransfer"
+        }
+      },
+      "title": "Deprecated"
+    },
+    "iotexapiGetEvmTransfersByBlockHeightResponse": {
+      "type": "object",
+      "properties": {
+        "blockEvmTransfers": {
+          "$ref": "#/definitions/iotextypesBlockEvmTransfer"
+        }
+      },
+      "title": "Deprecated"
+    },
+    "iotexapiGetLogsByBlock": {
+      "type": "object",
+      "properties": {
+        "blockHash": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotexapiGetLogsByRange": {
+      "type": "object",
+      "properties": {
+        "fromBlock": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "toBlock": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "paginationSize": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiGetLogsResponse": {
+      "type": "object",
+      "properties": {
+        "logs": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesLog"
+          }
+        }
+      }
+    },
+    "iotexapiGetRawBlocksResponse": {
+      "type": "object",
+      "properties": {
+        "blocks": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotexapiBlockInfo"
+          }
+        }
+      }
+    },
+    "iotexapiGetReceiptByActionResponse": {
+      "type": "object",
+      "properties": {
+        "receiptInfo": {
+          "$ref": "#/definitions/iotexapiReceiptInfo"
+        }
+      }
+    },
+    "iotexapiGetServerMetaResponse": {
+      "type": "object",
+      "properties": {
+        "serverMeta": {
+          "$ref": "#/definitions/iotextypesServerMeta"
+        }
+      }
+    },
+    "iotexapiGetTransactionLogByActionHashResponse": {
+      "type": "object",
+      "properties": {
+        "transactionLog": {
+          "$ref": "#/definitions/iotextypesTransactionLog"
+        }
+      }
+    },
+    "iotexapiGetTransactionLogByBlockHeightResponse": {
+      "type": "object",
+      "properties": {
+        "transactionLogs": {
+          "$ref": "#/definitions/iotextypesTransactionLogs"
+        },
+        "blockIdentifier": {
+          "$ref": "#/definitions/iotextypesBlockIdentifier"
+        }
+      }
+    },
+    "iotexapiGetUnconfirmedActionsByAddressRequest": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "start": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "count": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiLogsFilter": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "topics": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotexapiTopics"
+          }
+        }
+      }
+    },
+    "iotexapiReadContractResponse": {
+      "type": "object",
+      "properties": {
+        "data": {
+          "type": "string"
+        },
+        "receipt": {
+          "$ref": "#/definitions/iotextypesReceipt"
+        }
+      }
+    },
+    "iotexapiReadStateResponse": {
+      "type": "object",
+      "properties": {
+        "data": {
+          "type": "string",
+          "format": "byte"
+        },
+        "blockIdentifier": {
+          "$ref": "#/definitions/iotextypesBlockIdentifier"
+        }
+      }
+    },
+    "iotexapiReceiptInfo": {
+      "type": "object",
+      "properties": {
+        "receipt": {
+          "$ref": "#/definitions/iotextypesReceipt"
+        },
+        "blkHash": {
+          "type": "string"
+        }
+      }
+    },
+    "iotexapiSendActionRequest": {
+      "type": "object",
+      "properties": {
+        "action": {
+          "$ref": "#/definitions/iotextypesAction"
+        }
+      }
+    },
+    "iotexapiSendActionResponse": {
+      "type": "object",
+      "properties": {
+        "actionHash": {
+          "type": "string"
+        }
+      }
+    },
+    "iotexapiStreamBlocksResponse": {
+      "type": "object",
+      "properties": {
+        "block": {
+          "$ref": "#/definitions/iotexapiBlockInfo"
+        }
+      }
+    },
+    "iotexapiStreamLogsResponse": {
+      "type": "object",
+      "properties": {
+        "log": {
+          "$ref": "#/definitions/iotextypesLog"
+        }
+      }
+    },
+    "iotexapiSuggestGasPriceResponse": {
+      "type": "object",
+      "properties": {
+        "gasPrice": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotexapiTopics": {
+      "type": "object",
+      "properties": {
+        "topic": {
+          "type": "array",
+          "items": {
+            "type": "string",
+            "format": "byte"
+          }
+        }
+      }
+    },
+    "iotextypesAccountMeta": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "balance": {
+          "type": "string"
+        },
+        "nonce": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "pendingNonce": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "numActions": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "isContract": {
+          "type": "boolean"
+        }
+      },
+      "title": "Account Metadata"
+    },
+    "iotextypesAction": {
+      "type": "object",
+      "properties": {
+        "core": {
+          "$ref": "#/definitions/iotextypesActionCore"
+        },
+        "senderPubKey": {
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesActionCore": {
+      "type": "object",
+      "properties": {
+        "version": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "nonce": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "gasLimit": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "gasPrice": {
+          "type": "string"
+        },
+        "transfer": {
+          "$ref": "#/definitions/iotextypesTransfer"
+        },
+        "execution": {
+          "$ref": "#/definitions/iotextypesExecution"
+        },
+        "startSubChain": {
+          "$ref": "#/definitions/iotextypesStartSubChain",
+          "title": "FedChain"
+        },
+        "stopSubChain": {
+          "$ref": "#/definitions/iotextypesStopSubChain"
+        },
+        "putBlock": {
+          "$ref": "#/definitions/iotextypesPutBlock"
+        },
+        "createDeposit": {
+          "$ref": "#/definitions/iotextypesCreateDeposit"
+        },
+        "settleDeposit": {
+          "$ref": "#/definitions/iotextypesSettleDeposit"
+        },
+        "createPlumChain": {
+          "$ref": "#/definitions/iotextypesCreatePlumChain",
+          "title": "PlumChain"
+        },
+        "terminatePlumChain": {
+          "$ref": "#/definitions/iotextypesTerminatePlumChain"
+        },
+        "plumPutBlock": {
+          "$ref": "#/definitions/iotextypesPlumPutBlock"
+        },
+        "plumCreateDeposit": {
+          "$ref": "#/definitions/iotextypesPlumCreateDeposit"
+        },
+        "plumStartExit": {
+          "$ref": "#/definitions/iotextypesPlumStartExit"
+        },
+        "plumChallengeExit": {
+          "$ref": "#/definitions/iotextypesPlumChallengeExit"
+        },
+        "plumResponseChallengeExit": {
+          "$ref": "#/definitions/iotextypesPlumResponseChallengeExit"
+        },
+        "plumFinalizeExit": {
+          "$ref": "#/definitions/iotextypesPlumFinalizeExit"
+        },
+        "plumSettleDeposit": {
+          "$ref": "#/definitions/iotextypesPlumSettleDeposit"
+        },
+        "plumTransfer": {
+          "$ref": "#/definitions/iotextypesPlumTransfer"
+        },
+        "depositToRewardingFund": {
+          "$ref": "#/definitions/iotextypesDepositToRewardingFund",
+          "title": "Rewarding protocol actions"
+        },
+        "claimFromRewardingFund": {
+          "$ref": "#/definitions/iotextypesClaimFromRewardingFund"
+        },
+        "grantReward": {
+          "$ref": "#/definitions/iotextypesGrantReward"
+        },
+        "stakeCreate": {
+          "$ref": "#/definitions/iotextypesStakeCreate",
+          "title": "Native staking"
+        },
+        "stakeUnstake": {
+          "$ref": "#/definitions/iotextypesStakeReclaim"
+        },
+        "stakeWithdraw": {
+          "$ref": "#/definitions/iotextypesStakeReclaim"
+        },
+        "stakeAddDeposit": {
+          "$ref": "#/definitions/iotextypesStakeAddDeposit"
+        },
+        "stakeRestake": {
+          "$ref": "#/definitions/iotextypesStakeRestake"
+        },
+        "stakeChangeCandidate": {
+          "$ref": "#/definitions/iotextypesStakeChangeCandidate"
+        },
+        "stakeTransferOwnership": {
+          "$ref": "#/definitions/iotextypesStakeTransferOwnership"
+        },
+        "candidateRegister": {
+          "$ref": "#/definitions/iotextypesCandidateRegister"
+        },
+        "candidateUpdate": {
+          "$ref": "#/definitions/iotextypesCandidateBasicInfo"
+        },
+        "putPollResult": {
+          "$ref": "#/definitions/iotextypesPutPollResult"
+        }
+      }
+    },
+    "iotextypesActionEvmTransfer": {
+      "type": "object",
+      "properties": {
+        "actionHash": {
+          "type": "string",
+          "format": "byte"
+        },
+        "numEvmTransfers": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "evmTransfers": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesEvmTransfer"
+          }
+        }
+      },
+      "title": "Deprecated"
+    },
+    "iotextypesBlock": {
+      "type": "object",
+      "properties": {
+        "header": {
+          "$ref": "#/definitions/iotextypesBlockHeader"
+        },
+        "body": {
+          "$ref": "#/definitions/iotextypesBlockBody"
+        },
+        "footer": {
+          "$ref": "#/definitions/iotextypesBlockFooter"
+        }
+      },
+      "title": "block consists of header followed by transactions\nhash of current block can be computed from header hence not stored"
+    },
+    "iotextypesBlockBody": {
+      "type": "object",
+      "properties": {
+        "actions": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesAction"
+          }
+        }
+      },
+      "title": "body of a block"
+    },
+    "iotextypesBlockEvmTransfer": {
+      "type": "object",
+      "properties": {
+        "blockHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "numEvmTransfers": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "actionEvmTransfers": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesActionEvmTransfer"
+          }
+        }
+      },
+      "title": "Deprecated"
+    },
+    "iotextypesBlockFooter": {
+      "type": "object",
+      "properties": {
+        "endorsements": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesEndorsement"
+          }
+        },
+        "timestamp": {
+          "type": "string",
+          "format": "date-time"
+        }
+      },
+      "title": "footer of a block"
+    },
+    "iotextypesBlockHeader": {
+      "type": "object",
+      "properties": {
+        "core": {
+          "$ref": "#/definitions/iotextypesBlockHeaderCore"
+        },
+        "producerPubkey": {
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "type": "string",
+          "format": "byte"
+        }
+      },
+      "title": "header of a block"
+    },
+    "iotextypesBlockHeaderCore": {
+      "type": "object",
+      "properties": {
+        "version": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "timestamp": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "prevBlockHash": {
+          "type": "string",
+          "format": "byte"
+        },
+        "txRoot": {
+          "type": "string",
+          "format": "byte"
+        },
+        "deltaStateDigest": {
+          "type": "string",
+          "format": "byte"
+        },
+        "receiptRoot": {
+          "type": "string",
+          "format": "byte"
+        },
+        "logsBloom": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesBlockIdentifier": {
+      "type": "object",
+      "properties": {
+        "hash": {
+          "type": "string"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        }
+      },
+      "title": "BlockIdentifier Metadata"
+    },
+    "iotextypesBlockMeta": {
+      "type": "object",
+      "properties": {
+        "hash": {
+          "type": "string"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "timestamp": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "numActions": {
+          "type": "string",
+          "format": "int64"
+        },
+        "producerAddress": {
+          "type": "string"
+        },
+        "transferAmount": {
+          "type": "string"
+        },
+        "txRoot": {
+          "type": "string"
+        },
+        "receiptRoot": {
+          "type": "string"
+        },
+        "deltaStateDigest": {
+          "type": "string"
+        },
+        "logsBloom": {
+          "type": "string"
+        },
+        "previousBlockHash": {
+          "type": "string"
+        }
+      },
+      "title": "Block Metadata"
+    },
+    "iotextypesCandidate": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "votes": {
+          "type": "string",
+          "format": "byte"
+        },
+        "pubKey": {
+          "type": "string",
+          "format": "byte"
+        },
+        "rewardAddress": {
+          "type": "string"
+        }
+      },
+      "title": "Candidates and list of candidates"
+    },
+    "iotextypesCandidateBasicInfo": {
+      "type": "object",
+      "properties": {
+        "name": {
+          "type": "string"
+        },
+        "operatorAddress": {
+          "type": "string"
+        },
+        "rewardAddress": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesCandidateList": {
+      "type": "object",
+      "properties": {
+        "candidates": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesCandidate"
+          }
+        }
+      }
+    },
+    "iotextypesCandidateRegister": {
+      "type": "object",
+      "properties": {
+        "candidate": {
+          "$ref": "#/definitions/iotextypesCandidateBasicInfo"
+        },
+        "stakedAmount": {
+          "type": "string"
+        },
+        "stakedDuration": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "autoStake": {
+          "type": "boolean"
+        },
+        "ownerAddress": {
+          "type": "string"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesChainMeta": {
+      "type": "object",
+      "properties": {
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "numActions": {
+          "type": "string",
+          "format": "int64"
+        },
+        "tps": {
+          "type": "string",
+          "format": "int64"
+        },
+        "epoch": {
+          "$ref": "#/definitions/iotextypesEpochData"
+        },
+        "tpsFloat": {
+          "type": "number",
+          "format": "float"
+        }
+      },
+      "title": "Blockchain Metadata"
+    },
+    "iotextypesClaimFromRewardingFund": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string"
+        },
+        "data": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesCreateDeposit": {
+      "type": "object",
+      "properties": {
+        "chainID": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "amount": {
+          "type": "string"
+        },
+        "recipient": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesCreatePlumChain": {
+      "type": "object",
+      "title": "plum main chain APIs"
+    },
+    "iotextypesDepositToRewardingFund": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string"
+        },
+        "data": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesElectionBucket": {
+      "type": "object",
+      "properties": {
+        "voter": {
+          "type": "string",
+          "format": "byte"
+        },
+        "candidate": {
+          "type": "string",
+          "format": "byte"
+        },
+        "amount": {
+          "type": "string",
+          "format": "byte"
+        },
+        "startTime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "duration": {
+          "type": "string"
+        },
+        "decay": {
+          "type": "boolean"
+        }
+      }
+    },
+    "iotextypesEndorsement": {
+      "type": "object",
+      "properties": {
+        "timestamp": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "endorser": {
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesEpochData": {
+      "type": "object",
+      "properties": {
+        "num": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "gravityChainStartHeight": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesEvmTransfer": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "byte"
+        },
+        "from": {
+          "type": "string"
+        },
+        "to": {
+          "type": "string"
+        }
+      },
+      "title": "Deprecated"
+    },
+    "iotextypesExecution": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string"
+        },
+        "contract": {
+          "type": "string"
+        },
+        "data": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesGrantReward": {
+      "type": "object",
+      "properties": {
+        "type": {
+          "$ref": "#/definitions/iotextypesRewardType"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesLog": {
+      "type": "object",
+      "properties": {
+        "contractAddress": {
+          "type": "string"
+        },
+        "topics": {
+          "type": "array",
+          "items": {
+            "type": "string",
+            "format": "byte"
+          }
+        },
+        "data": {
+          "type": "string",
+          "format": "byte"
+        },
+        "blkHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "actHash": {
+          "type": "string",
+          "format": "byte"
+        },
+        "index": {
+          "type": "integer",
+          "format": "int64"
+        }
+      }
+    },
+    "iotextypesMerkleRoot": {
+      "type": "object",
+      "properties": {
+        "name": {
+          "type": "string"
+        },
+        "value": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesPlumChallengeExit": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "coinID": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "challengeTransfer": {
+          "type": "string",
+          "format": "byte"
+        },
+        "challengeTransferBlockProof": {
+          "type": "string",
+          "format": "byte"
+        },
+        "challengeTransferBlockHeight": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesPlumCreateDeposit": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "amount": {
+          "type": "string"
+        },
+        "recipient": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesPlumFinalizeExit": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "coinID": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesPlumPutBlock": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "roots": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string",
+            "format": "byte"
+          }
+        }
+      }
+    },
+    "iotextypesPlumResponseChallengeExit": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "coinID": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "challengeTransfer": {
+          "type": "string",
+          "format": "byte"
+        },
+        "responseTransfer": {
+          "type": "string",
+          "format": "byte"
+        },
+        "responseTransferBlockProof": {
+          "type": "string",
+          "format": "byte"
+        },
+        "previousTransferBlockHeight": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesPlumSettleDeposit": {
+      "type": "object",
+      "properties": {
+        "coinID": {
+          "type": "string",
+          "format": "uint64"
+        }
+      },
+      "title": "plum sub chain APIs"
+    },
+    "iotextypesPlumStartExit": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "previousTransfer": {
+          "type": "string",
+          "format": "byte"
+        },
+        "previousTransferBlockProof": {
+          "type": "string",
+          "format": "byte"
+        },
+        "previousTransferBlockHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "exitTransfer": {
+          "type": "string",
+          "format": "byte"
+        },
+        "exitTransferBlockProof": {
+          "type": "string",
+          "format": "byte"
+        },
+        "exitTransferBlockHeight": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesPlumTransfer": {
+      "type": "object",
+      "properties": {
+        "coinID": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "denomination": {
+          "type": "string",
+          "format": "byte"
+        },
+        "owner": {
+          "type": "string"
+        },
+        "recipient": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesPutBlock": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        },
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "roots": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesMerkleRoot"
+          }
+        }
+      }
+    },
+    "iotextypesPutPollResult": {
+      "type": "object",
+      "properties": {
+        "height": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "candidates": {
+          "$ref": "#/definitions/iotextypesCandidateList"
+        }
+      }
+    },
+    "iotextypesReceipt": {
+      "type": "object",
+      "properties": {
+        "status": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "blkHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "actHash": {
+          "type": "string",
+          "format": "byte"
+        },
+        "gasConsumed": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "contractAddress": {
+          "type": "string"
+        },
+        "logs": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesLog"
+          }
+        },
+        "executionRevertMsg": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesRewardType": {
+      "type": "string",
+      "enum": [
+        "BlockReward",
+        "EpochReward"
+      ],
+      "default": "BlockReward"
+    },
+    "iotextypesServerMeta": {
+      "type": "object",
+      "properties": {
+        "packageVersion": {
+          "type": "string"
+        },
+        "packageCommitID": {
+          "type": "string"
+        },
+        "gitStatus": {
+          "type": "string"
+        },
+        "goVersion": {
+          "type": "string"
+        },
+        "buildTime": {
+          "type": "string"
+        }
+      },
+      "title": "Server Metadata"
+    },
+    "iotextypesSettleDeposit": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string"
+        },
+        "recipient": {
+          "type": "string"
+        },
+        "index": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesStakeAddDeposit": {
+      "type": "object",
+      "properties": {
+        "bucketIndex": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "amount": {
+          "type": "string"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      },
+      "title": "add the amount of bucket"
+    },
+    "iotextypesStakeChangeCandidate": {
+      "type": "object",
+      "properties": {
+        "bucketIndex": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "candidateName": {
+          "type": "string"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      },
+      "title": "move the bucket to vote for another candidate or transfer the ownership of bucket to another voters"
+    },
+    "iotextypesStakeCreate": {
+      "type": "object",
+      "properties": {
+        "candidateName": {
+          "type": "string"
+        },
+        "stakedAmount": {
+          "type": "string"
+        },
+        "stakedDuration": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "autoStake": {
+          "type": "boolean"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      },
+      "title": "create stake"
+    },
+    "iotextypesStakeReclaim": {
+      "type": "object",
+      "properties": {
+        "bucketIndex": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      },
+      "title": "unstake or withdraw"
+    },
+    "iotextypesStakeRestake": {
+      "type": "object",
+      "properties": {
+        "bucketIndex": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "stakedDuration": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "autoStake": {
+          "type": "boolean"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      },
+      "title": "restake the duration and autoStake flag of bucket"
+    },
+    "iotextypesStakeTransferOwnership": {
+      "type": "object",
+      "properties": {
+        "bucketIndex": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "voterAddress": {
+          "type": "string"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "iotextypesStartSubChain": {
+      "type": "object",
+      "properties": {
+        "chainID": {
+          "type": "integer",
+          "format": "int64",
+          "title": "TODO: chainID chould be assigned by system and returned via a receipt"
+        },
+        "securityDeposit": {
+          "type": "string"
+        },
+        "operationDeposit": {
+          "type": "string"
+        },
+        "startHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "parentHeightOffset": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "iotextypesStopSubChain": {
+      "type": "object",
+      "properties": {
+        "chainID": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "stopHeight": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "subChainAddress": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesTerminatePlumChain": {
+      "type": "object",
+      "properties": {
+        "subChainAddress": {
+          "type": "string"
+        }
+      }
+    },
+    "iotextypesTransactionLog": {
+      "type": "object",
+      "properties": {
+        "actionHash": {
+          "type": "string",
+          "format": "byte"
+        },
+        "numTransactions": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "transactions": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TransactionLogTransaction"
+          }
+        }
+      }
+    },
+    "iotextypesTransactionLogType": {
+      "type": "string",
+      "enum": [
+        "IN_CONTRACT_TRANSFER",
+        "WITHDRAW_BUCKET",
+        "CREATE_BUCKET",
+        "DEPOSIT_TO_BUCKET",
+        "CANDIDATE_SELF_STAKE",
+        "CANDIDATE_REGISTRATION_FEE",
+        "GAS_FEE",
+        "NATIVE_TRANSFER",
+        "DEPOSIT_TO_REWARDING_FUND",
+        "CLAIM_FROM_REWARDING_FUND"
+      ],
+      "default": "IN_CONTRACT_TRANSFER"
+    },
+    "iotextypesTransactionLogs": {
+      "type": "object",
+      "properties": {
+        "logs": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/iotextypesTransactionLog"
+          }
+        }
+      }
+    },
+    "iotextypesTransfer": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "title": "used by state-based model"
+        },
+        "recipient": {
+          "type": "string"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "protobufAny": {
+      "type": "object",
+      "properties": {
+        "type_url": {
+          "type": "string"
+        },
+        "value": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "runtimeError": {
+      "type": "object",
+      "properties": {
+        "error": {
+          "type": "string"
+        },
+        "code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "message": {
+          "type": "string"
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          }
+        }
+      }
+    },
+    "runtimeStreamError": {
+      "type": "object",
+      "properties": {
+        "grpc_code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "http_code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "message": {
+          "type": "string"
+        },
+        "http_status": {
+          "type": "string"
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          }
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,37 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package gateway is the grpc-gateway of the iotexapi.APIService, generated from the mapping in gateway.yaml. The
+// generated code refers to the service and its messages as if it were in their package, which is iotexapi of
+// iotex-proto, so they are aliased here.
+package gateway
+
+import (
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+)
+
+type (
+	// APIServiceClient is the client of the api service
+	APIServiceClient = iotexapi.APIServiceClient
+	// APIServiceServer is the server of the api service
+	APIServiceServer = iotexapi.APIServiceServer
+
+	// GetAccountRequest is the request of GetAccount
+	GetAccountRequest = iotexapi.GetAccountRequest
+	// GetActionsRequest is the request of GetActions
+	GetActionsRequest = iotexapi.GetActionsRequest
+	// GetChainMetaRequest is the request of GetChainMeta
+	GetChainMetaRequest = iotexapi.GetChainMetaRequest
+	// GetRawBlocksRequest is the request of GetRawBlocks
+	GetRawBlocksRequest = iotexapi.GetRawBlocksRequest
+	// GetReceiptByActionRequest is the request of GetReceiptByAction
+	GetReceiptByActionRequest = iotexapi.GetReceiptByActionRequest
+	// SendActionRequest is the request of SendAction
+	SendActionRequest = iotexapi.SendActionRequest
+)
+
+// NewAPIServiceClient creates a client of the api service
+var NewAPIServiceClient = iotexapi.NewAPIServiceClient
@@ -0,0 +1,618 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// graphQLSchema is the schema of the chain data, hashes and byte strings are hex encoded
+const graphQLSchema = `
+scalar Long
+
+schema {
+	query: Query
+}
+
+type Query {
+	block(height: Long, hash: String): Block
+	blocks(from: Long!, count: Long!): [Block!]!
+	action(hash: String!): Action
+	receipt(hash: String!): Receipt
+	account(address: String!): Account
+	logs(fromBlock: Long!, toBlock: Long!, addresses: [String!], topics: [[String!]!]): [Log!]!
+}
+
+type Block {
+	height: Long!
+	hash: String!
+	prevHash: String!
+	timestamp: String!
+	producer: String!
+	txRoot: String!
+	receiptRoot: String!
+	numActions: Long!
+	actions: [Action!]!
+	receipts: [Receipt!]!
+}
+
+type Action {
+	hash: String!
+	type: String!
+	sender: String!
+	recipient: String
+	amount: String
+	nonce: Long!
+	gasLimit: Long!
+	gasPrice: String!
+	blockHeight: Long!
+	block: Block
+	receipt: Receipt
+}
+
+type Receipt {
+	actionHash: String!
+	status: Long!
+	blockHeight: Long!
+	gasConsumed: Long!
+	contractAddress: String!
+	logs: [Log!]!
+}
+
+type Log {
+	address: String!
+	topics: [String!]!
+	data: String!
+	blockHeight: Long!
+	actionHash: String!
+	index: Long!
+}
+
+type Account {
+	address: String!
+	balance: String!
+	nonce: Long!
+	pendingNonce: Long!
+	isContract: Boolean!
+}
+`
+
+// ErrQueryTooComplex indicates a graphql query resolves more objects than allowed
+var ErrQueryTooComplex = errors.New("query too complex")
+
+type (
+	// GraphQLServer serves graphql queries over the blocks, actions, receipts, logs and accounts
+	GraphQLServer struct {
+		api        *Server
+		schema     *graphql.Schema
+		httpServer *httpServer
+	}
+
+	graphQLRequest struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+
+	gqlComplexityCtxKey struct{}
+
+	// gqlComplexity is the number of objects a query may still resolve, a query without it is not limited
+	gqlComplexity struct {
+		remaining int64
+	}
+
+	// gqlLong is a 64-bit unsigned integer, which does not fit the 32-bit Int of graphql
+	gqlLong uint64
+
+	gqlQueryResolver struct {
+		api *Server
+	}
+
+	gqlBlockResolver struct {
+		api          *Server
+		blk          *block.Block
+		receiptsOnce sync.Once
+		receipts     []*action.Receipt
+		receiptsErr  error
+	}
+
+	gqlActionResolver struct {
+		api    *Server
+		selp   action.SealedEnvelope
+		height uint64
+		// blk is the block which the action was resolved from, if any
+		blk *gqlBlockResolver
+	}
+
+	gqlReceiptResolver struct {
+		receipt *action.Receipt
+	}
+
+	gqlLogResolver struct {
+		log *action.Log
+	}
+
+	gqlAccountResolver struct {
+		meta *iotextypes.AccountMeta
+	}
+)
+
+// NewGraphQLServer creates a graphql server serving on the given port
+func NewGraphQLServer(api *Server, port int) (*GraphQLServer, error) {
+	schema, err := graphql.ParseSchema(
+		graphQLSchema,
+		&gqlQueryResolver{api: api},
+		graphql.MaxDepth(api.cfg.API.GraphQL.MaxDepth),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse graphql schema")
+	}
+	svr := &GraphQLServer{
+		api:    api,
+		schema: schema,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", svr)
+	svr.httpServer = newHTTPServer("graphql", api.cfg.API.HTTP, port, mux)
+	return svr, nil
+}
+
+// Start starts the graphql server
+func (svr *GraphQLServer) Start(_ context.Context) error {
+	return svr.httpServer.start()
+}
+
+// Stop stops the graphql server
+func (svr *GraphQLServer) Stop(ctx context.Context) error {
+	return svr.httpServer.stop(ctx)
+}
+
+// ServeHTTP handles a graphql query, sent in the body of a post or in the query string of a get
+func (svr *GraphQLServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var gqlReq graphQLRequest
+	switch req.Method {
+	case http.MethodGet:
+		gqlReq.Query = req.URL.Query().Get("query")
+		gqlReq.OperationName = req.URL.Query().Get("operationName")
+		if vars := req.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &gqlReq.Variables); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, web3MaxRequestSize))
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := json.Unmarshal(body, &gqlReq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := withHTTPRequestOrigin(req.Context(), req)
+	if err := svr.api.limiter.allow(ctx, "graphql"); err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	resp := svr.exec(ctx, &gqlReq)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.L().Warn("failed to write graphql response.", zap.Error(err))
+	}
+}
+
+func (svr *GraphQLServer) exec(ctx context.Context, req *graphQLRequest) *graphql.Response {
+	if maxComplexity := svr.api.cfg.API.GraphQL.MaxComplexity; maxComplexity > 0 {
+		ctx = context.WithValue(ctx, gqlComplexityCtxKey{}, &gqlComplexity{remaining: int64(maxComplexity)})
+	}
+	return svr.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+// chargeComplexity charges n objects to the complexity budget of the query
+func chargeComplexity(ctx context.Context, n int) error {
+	c, ok := ctx.Value(gqlComplexityCtxKey{}).(*gqlComplexity)
+	if !ok || n == 0 {
+		return nil
+	}
+	if atomic.AddInt64(&c.remaining, -int64(n)) < 0 {
+		return ErrQueryTooComplex
+	}
+	return nil
+}
+
+// ImplementsGraphQLType implements the graphql Unmarshaler interface
+func (gqlLong) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+// UnmarshalGraphQL implements the graphql Unmarshaler interface
+func (l *gqlLong) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case int32:
+		if v < 0 {
+			return errors.Errorf("negative Long %d", v)
+		}
+		*l = gqlLong(v)
+	case float64:
+		if v < 0 || v != float64(uint64(v)) {
+			return errors.Errorf("invalid Long %v", v)
+		}
+		*l = gqlLong(v)
+	case string:
+		u, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid Long %s", v)
+		}
+		*l = gqlLong(u)
+	default:
+		return errors.Errorf("unexpected Long %v", input)
+	}
+	return nil
+}
+
+func (r *gqlQueryResolver) Block(ctx context.Context, args struct {
+	Height *gqlLong
+	Hash   *string
+}) (*gqlBlockResolver, error) {
+	var (
+		blk *block.Block
+		err error
+	)
+	switch {
+	case args.Height != nil:
+		blk, err = r.api.dao.GetBlockByHeight(uint64(*args.Height))
+	case args.Hash != nil:
+		var h hash.Hash256
+		if h, err = hash.HexStringToHash256(*args.Hash); err != nil {
+			return nil, err
+		}
+		blk, err = r.api.dao.GetBlock(h)
+	default:
+		blk, err = r.api.dao.GetBlockByHeight(r.api.bc.TipHeight())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, 1); err != nil {
+		return nil, err
+	}
+	return &gqlBlockResolver{api: r.api, blk: blk}, nil
+}
+
+func (r *gqlQueryResolver) Blocks(ctx context.Context, args struct {
+	From  gqlLong
+	Count gqlLong
+}) ([]*gqlBlockResolver, error) {
+	from, count := uint64(args.From), uint64(args.Count)
+	if from == 0 {
+		return nil, errors.New("start height should be greater than zero")
+	}
+	if count > r.api.cfg.API.RangeQueryLimit {
+		return nil, errors.New("range exceeds the limit")
+	}
+	tip := r.api.bc.TipHeight()
+	if from > tip {
+		return []*gqlBlockResolver{}, nil
+	}
+	if from+count > tip+1 {
+		count = tip + 1 - from
+	}
+	if err := chargeComplexity(ctx, int(count)); err != nil {
+		return nil, err
+	}
+	blks := make([]*gqlBlockResolver, 0, count)
+	for h := from; h < from+count; h++ {
+		blk, err := r.api.dao.GetBlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		blks = append(blks, &gqlBlockResolver{api: r.api, blk: blk})
+	}
+	return blks, nil
+}
+
+func (r *gqlQueryResolver) Action(ctx context.Context, args struct{ Hash string }) (*gqlActionResolver, error) {
+	h, err := hash.HexStringToHash256(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if !r.api.hasActionIndex || r.api.indexer == nil {
+		return nil, errors.New("action index not available")
+	}
+	selp, _, height, err := r.api.getActionByActionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, 1); err != nil {
+		return nil, err
+	}
+	return &gqlActionResolver{api: r.api, selp: selp, height: height}, nil
+}
+
+func (r *gqlQueryResolver) Receipt(ctx context.Context, args struct{ Hash string }) (*gqlReceiptResolver, error) {
+	h, err := hash.HexStringToHash256(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := r.api.GetReceiptByActionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, 1); err != nil {
+		return nil, err
+	}
+	return &gqlReceiptResolver{receipt: receipt}, nil
+}
+
+func (r *gqlQueryResolver) Account(ctx context.Context, args struct{ Address string }) (*gqlAccountResolver, error) {
+	res, err := r.api.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: args.Address})
+	if err != nil {
+		return nil, err
+	}
+	return &gqlAccountResolver{meta: res.AccountMeta}, nil
+}
+
+func (r *gqlQueryResolver) Logs(ctx context.Context, args struct {
+	FromBlock gqlLong
+	ToBlock   gqlLong
+	Addresses *[]string
+	Topics    *[][]string
+}) ([]*gqlLogResolver, error) {
+	filter := &iotexapi.LogsFilter{}
+	if args.Addresses != nil {
+		filter.Address = *args.Addresses
+	}
+	if args.Topics != nil {
+		for _, topics := range *args.Topics {
+			t := &iotexapi.Topics{}
+			for _, topic := range topics {
+				b, err := hex.DecodeString(topic)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid topic %s", topic)
+				}
+				t.Topic = append(t.Topic, b)
+			}
+			filter.Topics = append(filter.Topics, t)
+		}
+	}
+	res, err := r.api.GetLogs(ctx, &iotexapi.GetLogsRequest{
+		Filter: filter,
+		Lookup: &iotexapi.GetLogsRequest_ByRange{
+			ByRange: &iotexapi.GetLogsByRange{FromBlock: uint64(args.FromBlock), ToBlock: uint64(args.ToBlock)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, len(res.Logs)); err != nil {
+		return nil, err
+	}
+	logs := make([]*gqlLogResolver, len(res.Logs))
+	for i, pb := range res.Logs {
+		l := &action.Log{}
+		l.ConvertFromLogPb(pb)
+		logs[i] = &gqlLogResolver{log: l}
+	}
+	return logs, nil
+}
+
+func (r *gqlBlockResolver) Height() gqlLong { return gqlLong(r.blk.Height()) }
+
+func (r *gqlBlockResolver) Hash() string {
+	h := r.blk.HashBlock()
+	return hex.EncodeToString(h[:])
+}
+
+func (r *gqlBlockResolver) PrevHash() string {
+	h := r.blk.PrevHash()
+	return hex.EncodeToString(h[:])
+}
+
+func (r *gqlBlockResolver) Timestamp() string {
+	return r.blk.Timestamp().UTC().Format(time.RFC3339Nano)
+}
+
+func (r *gqlBlockResolver) Producer() string { return r.blk.ProducerAddress() }
+
+func (r *gqlBlockResolver) TxRoot() string {
+	h := r.blk.TxRoot()
+	return hex.EncodeToString(h[:])
+}
+
+func (r *gqlBlockResolver) ReceiptRoot() string {
+	h := r.blk.ReceiptRoot()
+	return hex.EncodeToString(h[:])
+}
+
+func (r *gqlBlockResolver) NumActions() gqlLong { return gqlLong(len(r.blk.Actions)) }
+
+func (r *gqlBlockResolver) Actions(ctx context.Context) ([]*gqlActionResolver, error) {
+	if err := chargeComplexity(ctx, len(r.blk.Actions)); err != nil {
+		return nil, err
+	}
+	acts := make([]*gqlActionResolver, len(r.blk.Actions))
+	for i, selp := range r.blk.Actions {
+		acts[i] = &gqlActionResolver{api: r.api, selp: selp, height: r.blk.Height(), blk: r}
+	}
+	return acts, nil
+}
+
+func (r *gqlBlockResolver) Receipts(ctx context.Context) ([]*gqlReceiptResolver, error) {
+	receipts, err := r.loadReceipts()
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, len(receipts)); err != nil {
+		return nil, err
+	}
+	res := make([]*gqlReceiptResolver, len(receipts))
+	for i, receipt := range receipts {
+		res[i] = &gqlReceiptResolver{receipt: receipt}
+	}
+	return res, nil
+}
+
+// loadReceipts reads the receipts of the block once, they are shared by the actions of the block
+func (r *gqlBlockResolver) loadReceipts() ([]*action.Receipt, error) {
+	r.receiptsOnce.Do(func() {
+		r.receipts, r.receiptsErr = r.api.dao.GetReceipts(r.blk.Height())
+	})
+	return r.receipts, r.receiptsErr
+}
+
+func (r *gqlActionResolver) Hash() string {
+	h := r.selp.Hash()
+	return hex.EncodeToString(h[:])
+}
+
+func (r *gqlActionResolver) Type() string {
+	t := reflect.TypeOf(r.selp.Action())
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+func (r *gqlActionResolver) Sender() string {
+	sender, _ := address.FromBytes(r.selp.SrcPubkey().Hash())
+	return sender.String()
+}
+
+func (r *gqlActionResolver) Recipient() *string {
+	dst, ok := r.selp.Destination()
+	if !ok {
+		return nil
+	}
+	return &dst
+}
+
+func (r *gqlActionResolver) Amount() *string {
+	act, ok := r.selp.Action().(interface{ Amount() *big.Int })
+	if !ok || act.Amount() == nil {
+		return nil
+	}
+	amount := act.Amount().String()
+	return &amount
+}
+
+func (r *gqlActionResolver) Nonce() gqlLong { return gqlLong(r.selp.Nonce()) }
+
+func (r *gqlActionResolver) GasLimit() gqlLong { return gqlLong(r.selp.GasLimit()) }
+
+func (r *gqlActionResolver) GasPrice() string { return r.selp.GasPrice().String() }
+
+func (r *gqlActionResolver) BlockHeight() gqlLong { return gqlLong(r.height) }
+
+func (r *gqlActionResolver) Block(ctx context.Context) (*gqlBlockResolver, error) {
+	if r.blk != nil {
+		return r.blk, nil
+	}
+	blk, err := r.api.dao.GetBlockByHeight(r.height)
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, 1); err != nil {
+		return nil, err
+	}
+	return &gqlBlockResolver{api: r.api, blk: blk}, nil
+}
+
+func (r *gqlActionResolver) Receipt(ctx context.Context) (*gqlReceiptResolver, error) {
+	h := r.selp.Hash()
+	if r.blk == nil {
+		receipt, err := r.api.dao.GetReceiptByActionHash(h, r.height)
+		if err != nil {
+			return nil, err
+		}
+		return &gqlReceiptResolver{receipt: receipt}, chargeComplexity(ctx, 1)
+	}
+	receipts, err := r.blk.loadReceipts()
+	if err != nil {
+		return nil, err
+	}
+	for _, receipt := range receipts {
+		if receipt.ActionHash == h {
+			return &gqlReceiptResolver{receipt: receipt}, chargeComplexity(ctx, 1)
+		}
+	}
+	return nil, nil
+}
+
+func (r *gqlReceiptResolver) ActionHash() string { return hex.EncodeToString(r.receipt.ActionHash[:]) }
+
+func (r *gqlReceiptResolver) Status() gqlLong { return gqlLong(r.receipt.Status) }
+
+func (r *gqlReceiptResolver) BlockHeight() gqlLong { return gqlLong(r.receipt.BlockHeight) }
+
+func (r *gqlReceiptResolver) GasConsumed() gqlLong { return gqlLong(r.receipt.GasConsumed) }
+
+func (r *gqlReceiptResolver) ContractAddress() string { return r.receipt.ContractAddress }
+
+func (r *gqlReceiptResolver) Logs(ctx context.Context) ([]*gqlLogResolver, error) {
+	logs := r.receipt.Logs()
+	if err := chargeComplexity(ctx, len(logs)); err != nil {
+		return nil, err
+	}
+	res := make([]*gqlLogResolver, len(logs))
+	for i, l := range logs {
+		res[i] = &gqlLogResolver{log: l}
+	}
+	return res, nil
+}
+
+func (r *gqlLogResolver) Address() string { return r.log.Address }
+
+func (r *gqlLogResolver) Topics() []string {
+	topics := make([]string, len(r.log.Topics))
+	for i, topic := range r.log.Topics {
+		topics[i] = hex.EncodeToString(topic[:])
+	}
+	return topics
+}
+
+func (r *gqlLogResolver) Data() string { return hex.EncodeToString(r.log.Data) }
+
+func (r *gqlLogResolver) BlockHeight() gqlLong { return gqlLong(r.log.BlockHeight) }
+
+func (r *gqlLogResolver) ActionHash() string { return hex.EncodeToString(r.log.ActionHash[:]) }
+
+func (r *gqlLogResolver) Index() gqlLong { return gqlLong(r.log.Index) }
+
+func (r *gqlAccountResolver) Address() string { return r.meta.Address }
+
+func (r *gqlAccountResolver) Balance() string { return r.meta.Balance }
+
+func (r *gqlAccountResolver) Nonce() gqlLong { return gqlLong(r.meta.Nonce) }
+
+func (r *gqlAccountResolver) PendingNonce() gqlLong { return gqlLong(r.meta.PendingNonce) }
+
+func (r *gqlAccountResolver) IsContract() bool { return r.meta.IsContract }
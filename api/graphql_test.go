@@ -0,0 +1,115 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestGraphQLServer(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, true)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	gql, err := NewGraphQLServer(svr, 0)
+	require.NoError(err)
+	query := func(q string, vars map[string]interface{}) map[string]interface{} {
+		res := gql.exec(context.Background(), &graphQLRequest{Query: q, Variables: vars})
+		require.Empty(res.Errors)
+		var data map[string]interface{}
+		require.NoError(json.Unmarshal(res.Data, &data))
+		return data
+	}
+
+	// nested resolution from a block down to the logs
+	data := query(`{
+		blocks(from: 1, count: 10) {
+			height
+			numActions
+			actions { hash sender nonce receipt { status logs { address } } }
+			receipts { actionHash }
+		}
+	}`, nil)
+	blks := data["blocks"].([]interface{})
+	require.Len(blks, 4)
+	blk := blks[0].(map[string]interface{})
+	require.EqualValues(1, blk["height"])
+	acts := blk["actions"].([]interface{})
+	require.EqualValues(len(acts), blk["numActions"])
+	require.Len(blk["receipts"], len(acts))
+	for _, act := range acts {
+		require.NotNil(act.(map[string]interface{})["receipt"])
+	}
+
+	data = query(`query($hash: String!) { action(hash: $hash) { hash type recipient amount blockHeight block { height } receipt { actionHash } } }`,
+		map[string]interface{}{"hash": hex.EncodeToString(transferHash1[:])})
+	act := data["action"].(map[string]interface{})
+	require.Equal(hex.EncodeToString(transferHash1[:]), act["hash"])
+	require.Equal("Transfer", act["type"])
+	require.NotNil(act["amount"])
+	require.Equal(act["blockHeight"], act["block"].(map[string]interface{})["height"])
+	require.Equal(act["hash"], act["receipt"].(map[string]interface{})["actionHash"])
+
+	data = query(`query($addr: String!) { account(address: $addr) { address balance nonce } }`,
+		map[string]interface{}{"addr": identityset.Address(27).String()})
+	require.Equal(identityset.Address(27).String(), data["account"].(map[string]interface{})["address"])
+
+	data = query(`{ block(height: "0x2") { height hash } latest: block { height } logs(fromBlock: 1, toBlock: 4) { blockHeight } }`, nil)
+	require.EqualValues(2, data["block"].(map[string]interface{})["height"])
+	require.EqualValues(4, data["latest"].(map[string]interface{})["height"])
+	require.NotNil(data["logs"])
+
+	t.Run("limits", func(t *testing.T) {
+		res := gql.exec(context.Background(), &graphQLRequest{Query: `{ block(height: -1) { height } }`})
+		require.NotEmpty(res.Errors)
+
+		svr.cfg.API.GraphQL.MaxComplexity = 3
+		defer func() {
+			svr.cfg.API.GraphQL.MaxComplexity = cfg.API.GraphQL.MaxComplexity
+		}()
+		res = gql.exec(context.Background(), &graphQLRequest{Query: `{ blocks(from: 1, count: 4) { height } }`})
+		require.Len(res.Errors, 1)
+		require.Contains(res.Errors[0].Message, ErrQueryTooComplex.Error())
+
+		shallow := newConfig(t)
+		shallow.API.GraphQL.MaxDepth = 2
+		gql, err := NewGraphQLServer(&Server{cfg: shallow}, 0)
+		require.NoError(err)
+		res = gql.exec(context.Background(), &graphQLRequest{Query: `{ block { actions { receipt { status } } } }`})
+		require.NotEmpty(res.Errors)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		body, err := json.Marshal(&graphQLRequest{Query: `{ block(height: 1) { height } }`})
+		require.NoError(err)
+		rec := httptest.NewRecorder()
+		gql.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+		require.Equal(http.StatusOK, rec.Code)
+		require.JSONEq(`{"data":{"block":{"height":1}}}`, rec.Body.String())
+		rec = httptest.NewRecorder()
+		gql.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql?query=%7Bblock(height:1)%7Bheight%7D%7D", nil))
+		require.JSONEq(`{"data":{"block":{"height":1}}}`, rec.Body.String())
+		rec = httptest.NewRecorder()
+		gql.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/graphql", nil))
+		require.Equal(http.StatusMethodNotAllowed, rec.Code)
+	})
+}
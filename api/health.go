@@ -0,0 +1,171 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+type (
+	// SyncStatus is the sync progress of the node, and whether it is ready to serve requests
+	SyncStatus struct {
+		TipHeight uint64 `json:"tipHeight"`
+		// TargetHeight is the best known height of the peers
+		TargetHeight uint64 `json:"targetHeight"`
+		// StartingHeight is the tip height when the api started
+		StartingHeight uint64 `json:"startingHeight"`
+		// BlocksPerSecond is the sync speed over the sliding windows of 1m, 5m and 15m
+		BlocksPerSecond map[string]float64 `json:"blocksPerSecond,omitempty"`
+		// ETASeconds is the estimated seconds to sync to the target height, 0 if synced and -1 if unknown
+		ETASeconds  int64         `json:"etaSeconds"`
+		Indexers    []IndexerSync `json:"indexers"`
+		ActPoolSize uint64        `json:"actPoolSize"`
+		PeerCount   int           `json:"peerCount"`
+		Ready       bool          `json:"ready"`
+		// Reasons are why the node is not ready
+		Reasons []string `json:"reasons,omitempty"`
+	}
+
+	// IndexerSync is the height of an indexer, and the number of blocks it falls behind the tip
+	IndexerSync struct {
+		Name   string `json:"name"`
+		Height uint64 `json:"height"`
+		Lag    uint64 `json:"lag"`
+	}
+
+	heightIndexer interface {
+		Height() (uint64, error)
+	}
+)
+
+// GetSyncStatus returns the sync progress of the chain, the indexers and the actpool, and the peer count
+func (api *Server) GetSyncStatus(ctx context.Context) (*SyncStatus, error) {
+	tip := api.bc.TipHeight()
+	ret := &SyncStatus{
+		TipHeight:      tip,
+		TargetHeight:   tip,
+		StartingHeight: api.startingHeight,
+		Indexers:       []IndexerSync{},
+		Ready:          true,
+	}
+	if api.bs != nil {
+		if target := api.bs.TargetHeight(); target > tip {
+			ret.TargetHeight = target
+		}
+		ret.BlocksPerSecond = api.bs.SyncSpeed()
+		if eta := api.bs.SyncETA(); eta < 0 {
+			ret.ETASeconds = -1
+		} else {
+			ret.ETASeconds = int64(math.Ceil(eta.Seconds()))
+		}
+	}
+	indexers := []struct {
+		name    string
+		indexer heightIndexer
+	}{
+		{"factory", api.sf},
+		{"blockindexer", api.indexer},
+		{"bloomfilter", api.bfIndexer},
+	}
+	for _, idx := range indexers {
+		if idx.indexer == nil {
+			continue
+		}
+		height, err := idx.indexer.Height()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get the height of %s: %v", idx.name, err)
+		}
+		sync := IndexerSync{Name: idx.name, Height: height}
+		if tip > height {
+			sync.Lag = tip - height
+		}
+		ret.Indexers = append(ret.Indexers, sync)
+	}
+	if api.ap != nil {
+		ret.ActPoolSize = api.ap.GetSize()
+	}
+	if api.neighbors != nil {
+		peers, err := api.neighbors(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		ret.PeerCount = len(peers)
+	}
+
+	cfg := api.cfg.API.Health
+	if lag := ret.TargetHeight - tip; lag > cfg.MaxSyncLag {
+		ret.Reasons = append(ret.Reasons, fmt.Sprintf("tip falls %d blocks behind the peers", lag))
+	}
+	for _, idx := range ret.Indexers {
+		if idx.Lag > cfg.MaxIndexerLag {
+			ret.Reasons = append(ret.Reasons, fmt.Sprintf("%s falls %d blocks behind the tip", idx.Name, idx.Lag))
+		}
+	}
+	if api.neighbors != nil && ret.PeerCount < cfg.MinPeers {
+		ret.Reasons = append(ret.Reasons, fmt.Sprintf("%d peers connected, at least %d needed", ret.PeerCount, cfg.MinPeers))
+	}
+	ret.Ready = len(ret.Reasons) == 0
+	return ret, nil
+}
+
+// healthRoutes are the liveness, readiness and sync status endpoints of the http servers of the api, they are neither
+// rate limited nor authenticated so that load balancers can always probe them
+func (api *Server) healthRoutes() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/health": func(w http.ResponseWriter, _ *http.Request) {
+			writeHealth(w, http.StatusOK, map[string]string{"status": "ok"})
+		},
+		"/ready": func(w http.ResponseWriter, req *http.Request) {
+			ss, err := api.GetSyncStatus(req.Context())
+			if err != nil {
+				writeHealth(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+				return
+			}
+			code := http.StatusOK
+			if !ss.Ready {
+				code = http.StatusServiceUnavailable
+			}
+			writeHealth(w, code, ss)
+		},
+		"/dbintegrity": func(w http.ResponseWriter, _ *http.Request) {
+			results := []filedao.FileIntegrity{}
+			if r, ok := api.dao.(filedao.IntegrityReporter); ok {
+				if v := r.Integrity(); v != nil {
+					results = v
+				}
+			}
+			writeHealth(w, http.StatusOK, results)
+		},
+		"/syncstatus": func(w http.ResponseWriter, req *http.Request) {
+			ss, err := api.GetSyncStatus(req.Context())
+			if err != nil {
+				writeHealth(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeHealth(w, http.StatusOK, ss)
+		},
+	}
+}
+
+func writeHealth(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.L().Warn("failed to write health response.", zap.Error(err))
+	}
+}
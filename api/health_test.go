@@ -0,0 +1,113 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/mock/mock_blocksync"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetSyncStatus(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, true)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	bs := mock_blocksync.NewMockBlockSync(ctrl)
+	targetHeight := uint64(4)
+	bs.EXPECT().TargetHeight().DoAndReturn(func() uint64 { return targetHeight }).AnyTimes()
+	eta := time.Duration(0)
+	bs.EXPECT().SyncSpeed().Return(map[string]float64{"1m": 0.5, "5m": 0.2, "15m": 0}).AnyTimes()
+	bs.EXPECT().SyncETA().DoAndReturn(func() time.Duration { return eta }).AnyTimes()
+	svr.bs = bs
+	svr.neighbors = func(context.Context) ([]peerstore.PeerInfo, error) {
+		return make([]peerstore.PeerInfo, 2), nil
+	}
+
+	ss, err := svr.GetSyncStatus(ctx)
+	require.NoError(err)
+	require.True(ss.Ready)
+	require.EqualValues(4, ss.TipHeight)
+	require.EqualValues(4, ss.TargetHeight)
+	require.Equal(2, ss.PeerCount)
+	require.Equal(0.5, ss.BlocksPerSecond["1m"])
+	require.Zero(ss.ETASeconds)
+	require.Equal(svr.ap.GetSize(), ss.ActPoolSize)
+	require.Len(ss.Indexers, 3)
+	for _, idx := range ss.Indexers {
+		require.Zero(idx.Lag, idx.Name)
+	}
+
+	eta = -1
+	ss, err = svr.GetSyncStatus(ctx)
+	require.NoError(err)
+	require.EqualValues(-1, ss.ETASeconds)
+
+	eta = 1500 * time.Millisecond
+	targetHeight = 4 + cfg.API.Health.MaxSyncLag + 1
+	svr.cfg.API.Health.MinPeers = 3
+	ss, err = svr.GetSyncStatus(ctx)
+	require.NoError(err)
+	require.False(ss.Ready)
+	require.Len(ss.Reasons, 2)
+	require.EqualValues(2, ss.ETASeconds)
+
+	t.Run("http", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		rest, err := NewRESTServer(svr, 0)
+		require.NoError(err)
+		for _, h := range []http.Handler{web3, rest} {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+			require.Equal(http.StatusOK, rec.Code)
+
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			require.Equal(http.StatusServiceUnavailable, rec.Code)
+			status := &SyncStatus{}
+			require.NoError(json.Unmarshal(rec.Body.Bytes(), status))
+			require.Equal(ss, status)
+
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/syncstatus", nil))
+			require.Equal(http.StatusOK, rec.Code)
+
+			// the in-memory chain db has no sealed file
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dbintegrity", nil))
+			require.Equal(http.StatusOK, rec.Code)
+			require.JSONEq("[]", rec.Body.String())
+		}
+
+		res := web3Call(t, web3, "eth_syncing")
+		require.Nil(res.Error)
+		var syncing map[string]string
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &syncing))
+		require.Equal(hexUint64(targetHeight), syncing["highestBlock"])
+		require.Equal("0x4", syncing["currentBlock"])
+		res = web3Call(t, web3, "net_peerCount")
+		require.Nil(res.Error)
+		require.Equal(`"0x2"`, string(res.Result.(json.RawMessage)))
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+type (
+	// httpServer is an http server of the api, which applies the cors policy, and serves https if a certificate is
+	// configured, or HTTP/2 over plain http if h2c is enabled
+	httpServer struct {
+		name   string
+		server *http.Server
+		cors   *corsPolicy
+		certs  *certReloader
+		done   chan struct{}
+	}
+
+	// corsPolicy answers the cross-origin requests of the allowed origins
+	corsPolicy struct {
+		anyOrigin bool
+		origins   map[string]bool
+		maxAge    string
+	}
+
+	// certReloader holds the tls certificate, which is reloaded from the files on SIGHUP
+	certReloader struct {
+		certFile, keyFile string
+		mutex             sync.RWMutex
+		cert              *tls.Certificate
+	}
+)
+
+var (
+	corsAllowedMethods = strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodOptions}, ", ")
+	corsAllowedHeaders = strings.Join([]string{"Content-Type", AuthorizationHeader, APIKeyHeader}, ", ")
+)
+
+func newHTTPServer(name string, cfg config.HTTP, port int, handler http.Handler) *httpServer {
+	s := &httpServer{
+		name: name,
+		cors: newCORSPolicy(cfg),
+		done: make(chan struct{}),
+	}
+	handler = s.cors.wrap(handler)
+	if cfg.TLSCertFile != "" {
+		s.certs = &certReloader{certFile: cfg.TLSCertFile, keyFile: cfg.TLSKeyFile}
+	} else if cfg.EnableH2C {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+	s.server = &http.Server{
+		Addr:    ":" + strconv.Itoa(port),
+		Handler: handler,
+	}
+	return s
+}
+
+// start listens on the port and serves in the background
+func (s *httpServer) start() error {
+	if s.certs != nil {
+		if err := s.certs.reload(); err != nil {
+			return err
+		}
+		s.server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: s.certs.getCertificate,
+		}
+		go s.certs.reloadOnSignal(s.done)
+	}
+	lis, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		log.L().Error(s.name+" server failed to listen.", zap.Error(err))
+		return errors.Wrap(err, s.name+" server failed to listen")
+	}
+	log.L().Info(s.name+" server is listening.", zap.String("addr", lis.Addr().String()), zap.Bool("tls", s.certs != nil))
+	go func() {
+		var err error
+		if s.certs != nil {
+			err = s.server.ServeTLS(lis, "", "")
+		} else {
+			err = s.server.Serve(lis)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.L().Fatal("Node failed to serve "+s.name+".", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// stop shuts the server down gracefully, within 5 seconds
+func (s *httpServer) stop(ctx context.Context) error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+func newCORSPolicy(cfg config.HTTP) *corsPolicy {
+	p := &corsPolicy{
+		origins: make(map[string]bool, len(cfg.AllowedOrigins)),
+		maxAge:  strconv.Itoa(int(cfg.CORSMaxAge.Seconds())),
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			p.anyOrigin = true
+		}
+		p.origins[strings.TrimSuffix(origin, "/")] = true
+	}
+	return p
+}
+
+// allowed tells whether the origin may send cross-origin requests
+func (p *corsPolicy) allowed(origin string) bool {
+	return p.anyOrigin || p.origins[origin]
+}
+
+// checkWebsocketOrigin accepts the websocket connections of the allowed origins, or of the same origin only if none
+// is configured
+func (p *corsPolicy) checkWebsocketOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if len(p.origins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, req.Host)
+	}
+	return p.allowed(origin)
+}
+
+// wrap adds the cors headers to the responses to the allowed origins, and answers their preflight requests
+func (p *corsPolicy) wrap(next http.Handler) http.Handler {
+	if len(p.origins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		origin := req.Header.Get("Origin")
+		if origin == "" || !p.allowed(origin) {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if p.anyOrigin {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			header.Set("Access-Control-Max-Age", p.maxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		header.Set("Access-Control-Expose-Headers", RESTNextCursorHeader)
+		next.ServeHTTP(w, req)
+	})
+}
+
+// reload loads the certificate from the files, the certificate in use is kept if it fails
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load tls certificate")
+	}
+	r.mutex.Lock()
+	r.cert = &cert
+	r.mutex.Unlock()
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.cert, nil
+}
+
+// reloadOnSignal reloads the certificate on each SIGHUP until done is closed
+func (r *certReloader) reloadOnSignal(done <-chan struct{}) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+	for {
+		select {
+		case <-done:
+			return
+		case <-sig:
+			if err := r.reload(); err != nil {
+				log.L().Error("failed to reload tls certificate.", zap.Error(err))
+				continue
+			}
+			log.L().Info("reloaded tls certificate.", zap.String("cert", r.certFile))
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestCORSPolicy(t *testing.T) {
+	require := require.New(t)
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	request := func(h http.Handler, method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// no origin is allowed by default
+	p := newCORSPolicy(config.Default.API.HTTP)
+	rec := request(p.wrap(next), http.MethodPost, "https://app.iotex.io", false)
+	require.Equal(http.StatusTeapot, rec.Code)
+	require.Empty(rec.Header().Get("Access-Control-Allow-Origin"))
+	ws := httptest.NewRequest(http.MethodGet, "http://localhost:15014/", nil)
+	require.True(p.checkWebsocketOrigin(ws))
+	// the websocket connections of the same origin only are accepted by default
+	ws.Header.Set("Origin", "http://localhost:15014")
+	require.True(p.checkWebsocketOrigin(ws))
+	ws.Header.Set("Origin", "https://evil.com")
+	require.False(p.checkWebsocketOrigin(ws))
+
+	cfg := config.Default.API.HTTP
+	cfg.AllowedOrigins = []string{"https://app.iotex.io/"}
+	p = newCORSPolicy(cfg)
+	h := p.wrap(next)
+	rec = request(h, http.MethodPost, "https://app.iotex.io", false)
+	require.Equal(http.StatusTeapot, rec.Code)
+	require.Equal("https://app.iotex.io", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(RESTNextCursorHeader, rec.Header().Get("Access-Control-Expose-Headers"))
+	rec = request(h, http.MethodOptions, "https://app.iotex.io", true)
+	require.Equal(http.StatusNoContent, rec.Code)
+	require.Equal(corsAllowedMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+	require.Equal(corsAllowedHeaders, rec.Header().Get("Access-Control-Allow-Headers"))
+	require.Equal("600", rec.Header().Get("Access-Control-Max-Age"))
+	rec = request(h, http.MethodOptions, "https://evil.com", true)
+	require.Equal(http.StatusTeapot, rec.Code)
+	require.Empty(rec.Header().Get("Access-Control-Allow-Origin"))
+
+	ws.Header.Set("Origin", "https://evil.com")
+	require.False(p.checkWebsocketOrigin(ws))
+	ws.Header.Set("Origin", "https://app.iotex.io")
+	require.True(p.checkWebsocketOrigin(ws))
+
+	cfg.AllowedOrigins = []string{"*"}
+	rec = request(newCORSPolicy(cfg).wrap(next), http.MethodGet, "https://evil.com", false)
+	require.Equal("*", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestHTTPServer(t *testing.T) {
+	require := require.New(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(req.Proto))
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "httpserver")
+		require.NoError(err)
+		defer testutil.CleanupPath(t, dir)
+		cfg := config.Default.API.HTTP
+		cfg.TLSCertFile = filepath.Join(dir, "cert.pem")
+		cfg.TLSKeyFile = filepath.Join(dir, "key.pem")
+		writeTestCert(t, cfg.TLSCertFile, cfg.TLSKeyFile, "first")
+
+		port := testutil.RandomPort()
+		s := newHTTPServer("test", cfg, port, handler)
+		require.NoError(s.start())
+		defer func() {
+			require.NoError(s.stop(context.Background()))
+		}()
+		commonName := func() string {
+			conn, err := tls.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), &tls.Config{InsecureSkipVerify: true})
+			require.NoError(err)
+			defer conn.Close()
+			return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		}
+		require.Equal("first", commonName())
+		// https offers HTTP/2
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+		}}
+		resp, err := client.Get("https://127.0.0.1:" + strconv.Itoa(port))
+		require.NoError(err)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(err)
+		require.NoError(resp.Body.Close())
+		require.Equal("HTTP/2.0", string(body))
+
+		// the certificate is replaced on reload, and kept if the files are broken
+		writeTestCert(t, cfg.TLSCertFile, cfg.TLSKeyFile, "second")
+		require.NoError(s.certs.reload())
+		require.Equal("second", commonName())
+		require.NoError(ioutil.WriteFile(cfg.TLSKeyFile, []byte("broken"), 0600))
+		require.Error(s.certs.reload())
+		require.Equal("second", commonName())
+	})
+
+	t.Run("h2c", func(t *testing.T) {
+		cfg := config.Default.API.HTTP
+		cfg.EnableH2C = true
+		port := testutil.RandomPort()
+		s := newHTTPServer("test", cfg, port, handler)
+		require.NoError(s.start())
+		defer func() {
+			require.NoError(s.stop(context.Background()))
+		}()
+		client := &http.Client{Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		}}
+		resp, err := client.Get("http://127.0.0.1:" + strconv.Itoa(port))
+		require.NoError(err)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(err)
+		require.NoError(resp.Body.Close())
+		require.Equal("HTTP/2.0", string(body))
+	})
+
+	t.Run("missing certificate", func(t *testing.T) {
+		cfg := config.Default.API.HTTP
+		cfg.TLSCertFile = "nonexistent.pem"
+		cfg.TLSKeyFile = "nonexistent.pem"
+		require.Error(newHTTPServer("test", cfg, testutil.RandomPort(), handler).start())
+	})
+}
+
+func writeTestCert(t *testing.T, certFile, keyFile, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockindex"
+	"github.com/iotexproject/iotex-core/lightclient"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// HeaderProof returns the header of the height with the commit endorsements, which prove the header is final
+func (api *Server) HeaderProof(height uint64) (*lightclient.HeaderProof, error) {
+	if height == 0 || height > api.bc.TipHeight() {
+		return nil, status.Errorf(codes.NotFound, "header %d not found", height)
+	}
+	blk, err := api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return lightclient.NewHeaderProof(blk), nil
+}
+
+// DelegatesProof returns the delegate set of the epoch, with the header proof of the first block of the epoch
+func (api *Server) DelegatesProof(ctx context.Context, epoch uint64) (*lightclient.DelegatesProof, error) {
+	if epoch < 1 {
+		return nil, status.Error(codes.InvalidArgument, "epoch number cannot be less than one")
+	}
+	rp := rolldpos.FindProtocol(api.registry)
+	if rp == nil {
+		return nil, status.Error(codes.Unimplemented, "rolldpos protocol is not registered")
+	}
+	pp := poll.FindProtocol(api.registry)
+	if pp == nil {
+		return nil, status.Error(codes.Internal, "poll protocol is not registered")
+	}
+	epochHeight := rp.GetEpochHeight(epoch)
+	proof, err := api.HeaderProof(epochHeight)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := api.readState(
+		ctx,
+		pp,
+		strconv.FormatUint(epochHeight, 10),
+		[]byte("ActiveBlockProducersByEpoch"),
+		[]byte(strconv.FormatUint(epoch, 10)),
+	)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	var delegates state.CandidateList
+	if err := delegates.Deserialize(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	ret := &lightclient.DelegatesProof{
+		Epoch: epoch,
+		Proof: proof,
+	}
+	for _, d := range delegates {
+		ret.Delegates = append(ret.Delegates, d.Address)
+	}
+	return ret, nil
+}
+
+// TxProof returns the merkle proof of the action to the tx root of its block
+func (api *Server) TxProof(actHash hash.Hash256) (*lightclient.TxProof, error) {
+	if !api.hasActionIndex || api.indexer == nil {
+		return nil, status.Error(codes.NotFound, blockindex.ErrActionIndexNA.Error())
+	}
+	actIndex, err := api.indexer.GetActionIndex(actHash[:])
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	blk, err := api.dao.GetBlockByHeight(actIndex.BlockHeight())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	proof, err := lightclient.NewTxProof(blk, actHash)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return proof, nil
+}
+
+func (svr *RESTServer) getHeaderProof(_ context.Context, w http.ResponseWriter, _ *http.Request, path string) {
+	height, err := strconv.ParseUint(path, 10, 64)
+	if err != nil {
+		svr.writeError(w, status.Error(codes.InvalidArgument, "invalid block height "+path))
+		return
+	}
+	proof, err := svr.api.HeaderProof(height)
+	if err != nil {
+		svr.writeError(w, err)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+func (svr *RESTServer) getDelegatesProof(ctx context.Context, w http.ResponseWriter, _ *http.Request, path string) {
+	epoch, err := strconv.ParseUint(path, 10, 64)
+	if err != nil {
+		svr.writeError(w, status.Error(codes.InvalidArgument, "invalid epoch "+path))
+		return
+	}
+	proof, err := svr.api.DelegatesProof(ctx, epoch)
+	if err != nil {
+		svr.writeError(w, err)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+func (svr *RESTServer) getTxProof(_ context.Context, w http.ResponseWriter, _ *http.Request, path string) {
+	actHash, err := hash.HexStringToHash256(path)
+	if err != nil {
+		svr.writeError(w, status.Error(codes.InvalidArgument, "invalid action hash "+path))
+		return
+	}
+	proof, err := svr.api.TxProof(actHash)
+	if err != nil {
+		svr.writeError(w, err)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L().Warn("failed to write rest response.", zap.Error(err))
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/lightclient"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestLightClientProofs(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, true)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	rest, err := NewRESTServer(svr, 0)
+	require.NoError(err)
+	do := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, bytes.NewReader(nil)))
+		return rec
+	}
+
+	rec := do("/light/txproofs/" + hex.EncodeToString(transferHash1[:]))
+	require.Equal(http.StatusOK, rec.Code)
+	txProof := &lightclient.TxProof{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), txProof))
+	require.Equal(transferHash1, txProof.ActionHash)
+
+	rec = do("/light/headers/" + "1")
+	require.Equal(http.StatusOK, rec.Code)
+	headerProof := &lightclient.HeaderProof{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), headerProof))
+	require.EqualValues(txProof.Height, headerProof.Header.Height())
+	require.NoError(txProof.Verify(headerProof.Header))
+
+	rec = do("/light/delegates/1")
+	require.Equal(http.StatusOK, rec.Code)
+	delegatesProof := &lightclient.DelegatesProof{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), delegatesProof))
+	require.EqualValues(1, delegatesProof.Epoch)
+	require.NotEmpty(delegatesProof.Delegates)
+	require.EqualValues(1, delegatesProof.Proof.Header.Height())
+
+	require.Equal(http.StatusNotFound, do("/light/headers/100").Code)
+	require.Equal(http.StatusBadRequest, do("/light/headers/one").Code)
+	require.Equal(http.StatusBadRequest, do("/light/delegates/0").Code)
+	require.Equal(http.StatusBadRequest, do("/light/txproofs/xyz").Code)
+	require.Equal(http.StatusNotFound, do("/light/txproofs/"+hex.EncodeToString(make([]byte, 32))).Code)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	logfilter "github.com/iotexproject/iotex-core/api/logfilter"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+type (
+	// QueryTooBroadError indicates a log query exceeds the limits of the api. The range [FromBlock, SuggestedToBlock]
+	// is within the limits.
+	QueryTooBroadError struct {
+		Reason           string
+		FromBlock        uint64
+		ToBlock          uint64
+		SuggestedToBlock uint64
+	}
+
+	// LogsQueryCost is the estimated cost of a log query, predicted by the bloom filters without scanning any receipt
+	LogsQueryCost struct {
+		FromBlock uint64 `json:"fromBlock"`
+		ToBlock   uint64 `json:"toBlock"`
+		// CandidateBlocks is the number of blocks matched by the bloom filters, whose receipts are to be scanned
+		CandidateBlocks  uint64 `json:"candidateBlocks"`
+		MaxBlocksScanned uint64 `json:"maxBlocksScanned"`
+		MaxLogs          uint64 `json:"maxLogs"`
+		// TooBroad tells the query is going to fail for scanning too many blocks. A query within the block limit may
+		// still fail for returning too many logs.
+		TooBroad bool `json:"tooBroad"`
+		// SuggestedToBlock is the end of the narrower range within the block limit, if the query is too broad
+		SuggestedToBlock uint64 `json:"suggestedToBlock,omitempty"`
+	}
+)
+
+// Error returns the error message
+func (e *QueryTooBroadError) Error() string {
+	return fmt.Sprintf("query too broad: %s in [%d, %d], try [%d, %d]", e.Reason, e.FromBlock, e.ToBlock, e.FromBlock, e.SuggestedToBlock)
+}
+
+// GRPCStatus returns the grpc status of the error, which carries the suggested range as a field violation
+func (e *QueryTooBroadError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	st, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       "to_block",
+				Description: fmt.Sprintf("%d", e.SuggestedToBlock),
+			},
+		},
+	})
+	if err != nil {
+		log.S().Panicf("Unexpected error attaching metadata: %v", err)
+	}
+	return st
+}
+
+// EstimateLogsQuery estimates the cost of a log query before executing it, with the range bloom filters
+func (api *Server) EstimateLogsQuery(ctx context.Context, in *iotexapi.GetLogsRequest) (*LogsQueryCost, error) {
+	if in.GetFilter() == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty filter")
+	}
+	filter := logfilter.NewLogFilter(in.GetFilter(), nil, nil)
+	limits := api.cfg.API.LogQuery
+	cost := &LogsQueryCost{
+		MaxBlocksScanned: limits.MaxBlocksScanned,
+		MaxLogs:          limits.MaxLogs,
+	}
+	switch {
+	case in.GetByBlock() != nil:
+		height, err := api.dao.GetBlockHeight(hash.BytesToHash256(in.GetByBlock().BlockHash))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid block hash")
+		}
+		bf, err := api.bfIndexer.BlockFilterByHeight(height)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		cost.FromBlock, cost.ToBlock = height, height
+		if filter.ExistInBloomFilterv2(bf) {
+			cost.CandidateBlocks = 1
+		}
+	case in.GetByRange() != nil:
+		start, end, _, err := api.logsRange(in.GetByRange())
+		if err != nil {
+			return nil, err
+		}
+		if start == 0 {
+			start = 1
+		}
+		if start > end {
+			return nil, status.Error(codes.InvalidArgument, "invalid start and end height")
+		}
+		cost.FromBlock, cost.ToBlock = start, end
+		blockNumbers, err := api.bfIndexer.FilterBlocksInRange(filter, start, end)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		cost.CandidateBlocks = uint64(len(blockNumbers))
+		if cost.CandidateBlocks > limits.MaxBlocksScanned {
+			cost.TooBroad = true
+			cost.SuggestedToBlock = blockNumbers[limits.MaxBlocksScanned-1]
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid GetLogsRequest type")
+	}
+	return cost, nil
+}
+
+// logsRange returns the range of a log query, whose end is the tip if not set or beyond the tip
+func (api *Server) logsRange(req *iotexapi.GetLogsByRange) (uint64, uint64, bool, error) {
+	tipHeight := api.bc.TipHeight()
+	start, end := req.GetFromBlock(), req.GetToBlock()
+	if start > tipHeight {
+		return 0, 0, false, status.Error(codes.InvalidArgument, "start block > tip height")
+	}
+	if end > tipHeight || end == 0 {
+		return start, tipHeight, true, nil
+	}
+	return start, end, false, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_LogQueryLimits(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tip := svr.bc.TipHeight()
+	byRange := func(from, to, paginationSize uint64) *iotexapi.GetLogsRequest {
+		return &iotexapi.GetLogsRequest{
+			Filter: &iotexapi.LogsFilter{},
+			Lookup: &iotexapi.GetLogsRequest_ByRange{
+				ByRange: &iotexapi.GetLogsByRange{FromBlock: from, ToBlock: to, PaginationSize: paginationSize},
+			},
+		}
+	}
+	res, err := svr.GetLogs(ctx, byRange(1, tip, 0))
+	require.NoError(err)
+	require.Len(res.Logs, 4)
+	cost, err := svr.EstimateLogsQuery(ctx, byRange(1, tip, 0))
+	require.NoError(err)
+	require.False(cost.TooBroad)
+	require.True(cost.CandidateBlocks > 1)
+	require.True(cost.CandidateBlocks <= tip)
+
+	// too many logs
+	svr.cfg.API.LogQuery.MaxLogs = 2
+	_, err = svr.GetLogs(ctx, byRange(1, tip, 0))
+	e, ok := errors.Cause(err).(*QueryTooBroadError)
+	require.True(ok)
+	require.EqualValues(1, e.FromBlock)
+	require.Equal(tip, e.ToBlock)
+	st := status.Convert(err)
+	require.Equal(codes.InvalidArgument, st.Code())
+	require.Len(st.Details(), 1)
+	require.IsType(&errdetails.BadRequest{}, st.Details()[0])
+	res, err = svr.GetLogs(ctx, byRange(1, e.SuggestedToBlock, 0))
+	require.NoError(err)
+	require.True(len(res.Logs) <= 2)
+	// a paginated query returns the first page instead
+	res, err = svr.GetLogs(ctx, byRange(1, tip, 3))
+	require.NoError(err)
+	require.Len(res.Logs, 2)
+	res, err = svr.GetLogs(ctx, byRange(1, tip, 1))
+	require.NoError(err)
+	require.Len(res.Logs, 1)
+
+	// too many blocks to scan, which is predicted by the estimation
+	svr.cfg.API.LogQuery.MaxLogs = 100
+	svr.cfg.API.LogQuery.MaxBlocksScanned = 1
+	cost, err = svr.EstimateLogsQuery(ctx, byRange(1, tip, 0))
+	require.NoError(err)
+	require.True(cost.TooBroad)
+	_, err = svr.GetLogs(ctx, byRange(1, tip, 0))
+	e, ok = errors.Cause(err).(*QueryTooBroadError)
+	require.True(ok)
+	require.Equal(cost.SuggestedToBlock, e.SuggestedToBlock)
+	_, err = svr.GetLogs(ctx, byRange(1, e.SuggestedToBlock, 0))
+	require.NoError(err)
+	_, err = svr.EstimateLogsQuery(ctx, &iotexapi.GetLogsRequest{})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		filter := map[string]string{"fromBlock": "0x1", "toBlock": "latest"}
+		res := web3Call(t, web3, "eth_getLogs", filter)
+		require.Equal(-32005, res.Error.Code)
+		data := res.Error.Data.(map[string]interface{})
+		require.Equal("0x1", data["from"])
+		require.Equal(hexUint64(e.SuggestedToBlock), data["to"])
+
+		res = web3Call(t, web3, "iotex_getLogsCost", filter)
+		require.Nil(res.Error)
+		var cost map[string]interface{}
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &cost))
+		require.Equal(true, cost["tooBroad"])
+		require.Equal(hexUint64(e.SuggestedToBlock), cost["suggestedToBlock"])
+	})
+}
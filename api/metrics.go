@@ -0,0 +1,167 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"path"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	grpcProtocol = "grpc"
+	web3Protocol = "web3"
+)
+
+var (
+	apiLatencyMtc = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "iotex_api_method_latency_seconds",
+			Help:    "Latency of the api methods",
+			Buckets: []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+		},
+		[]string{"protocol", "method"},
+	)
+	apiRequestMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_api_method_requests_total",
+			Help: "Number of api requests, by their outcome",
+		},
+		[]string{"protocol", "method", "status"},
+	)
+	apiPayloadSizeMtc = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "iotex_api_method_payload_bytes",
+			Help:    "Size of the requests and responses of the api methods",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 10),
+		},
+		[]string{"protocol", "method", "direction"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(apiLatencyMtc)
+	prometheus.MustRegister(apiRequestMtc)
+	prometheus.MustRegister(apiPayloadSizeMtc)
+}
+
+type (
+	// methodObserver records the latency, outcome and payload sizes of the api methods, and logs the requests slower
+	// than the threshold
+	methodObserver struct {
+		slowQueryThreshold time.Duration
+	}
+
+	// observedStream counts the bytes received and sent over a grpc stream, and keeps the first message received,
+	// which is the request of a server stream
+	observedStream struct {
+		grpc.ServerStream
+		first      interface{}
+		recv, sent int
+	}
+)
+
+func newMethodObserver(slowQueryThreshold time.Duration) *methodObserver {
+	return &methodObserver{slowQueryThreshold: slowQueryThreshold}
+}
+
+// observe records a request which started at start. The params are only serialized if the request is slow, to log
+// their digest. A nil observer records the metrics without logging.
+func (o *methodObserver) observe(
+	protocol, method string,
+	start time.Time,
+	reqSize, respSize int,
+	err error,
+	params func() []byte,
+) {
+	duration := time.Since(start)
+	apiLatencyMtc.WithLabelValues(protocol, method).Observe(duration.Seconds())
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	apiRequestMtc.WithLabelValues(protocol, method, outcome).Inc()
+	apiPayloadSizeMtc.WithLabelValues(protocol, method, "request").Observe(float64(reqSize))
+	apiPayloadSizeMtc.WithLabelValues(protocol, method, "response").Observe(float64(respSize))
+	if o == nil || o.slowQueryThreshold <= 0 || duration < o.slowQueryThreshold {
+		return
+	}
+	digest := hash.Hash160b(params())
+	log.L().Warn("slow api query.",
+		zap.String("protocol", protocol),
+		zap.String("method", method),
+		zap.String("params", hex.EncodeToString(digest[:])),
+		zap.Duration("duration", duration),
+		zap.Bool("failed", err != nil))
+}
+
+// unaryInterceptor observes the unary grpc requests
+func (o *methodObserver) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	o.observe(grpcProtocol, path.Base(info.FullMethod), start, messageSize(req), messageSize(resp), err, func() []byte {
+		return marshalMessage(req)
+	})
+	return resp, err
+}
+
+// streamInterceptor observes the grpc streams, whose latency is the lifetime of the stream
+func (o *methodObserver) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	stream := &observedStream{ServerStream: ss}
+	err := handler(srv, stream)
+	o.observe(grpcProtocol, path.Base(info.FullMethod), start, stream.recv, stream.sent, err, func() []byte {
+		return marshalMessage(stream.first)
+	})
+	return err
+}
+
+// SendMsg sends a message and counts its size
+func (s *observedStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent += messageSize(m)
+	}
+	return err
+}
+
+// RecvMsg receives a message and counts its size
+func (s *observedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		if s.first == nil {
+			s.first = m
+		}
+		s.recv += messageSize(m)
+	}
+	return err
+}
+
+func messageSize(m interface{}) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+func marshalMessage(m interface{}) []byte {
+	if msg, ok := m.(proto.Message); ok {
+		if data, err := proto.Marshal(msg); err == nil {
+			return data
+		}
+	}
+	return nil
+}
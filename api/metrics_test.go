@@ -0,0 +1,52 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestMethodObserver(t *testing.T) {
+	require := require.New(t)
+	o := newMethodObserver(time.Nanosecond)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/iotexapi.APIService/GetReceiptByAction"}
+	req := &iotexapi.GetReceiptByActionRequest{ActionHash: "abc"}
+	ok := testutil.ToFloat64(apiRequestMtc.WithLabelValues(grpcProtocol, "GetReceiptByAction", "ok"))
+	failed := testutil.ToFloat64(apiRequestMtc.WithLabelValues(grpcProtocol, "GetReceiptByAction", "error"))
+	resp, err := o.unaryInterceptor(context.Background(), req, info, func(context.Context, interface{}) (interface{}, error) {
+		return &iotexapi.GetReceiptByActionResponse{}, nil
+	})
+	require.NoError(err)
+	require.NotNil(resp)
+	require.Equal(ok+1, testutil.ToFloat64(apiRequestMtc.WithLabelValues(grpcProtocol, "GetReceiptByAction", "ok")))
+	_, err = o.unaryInterceptor(context.Background(), req, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errors.New("not found")
+	})
+	require.Error(err)
+	require.Equal(failed+1, testutil.ToFloat64(apiRequestMtc.WithLabelValues(grpcProtocol, "GetReceiptByAction", "error")))
+
+	require.Equal(len("abc")+2, messageSize(req))
+	require.Zero(messageSize("not a message"))
+	require.Nil(marshalMessage(nil))
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(&Server{observer: o}, 0)
+		unknown := testutil.ToFloat64(apiRequestMtc.WithLabelValues(web3Protocol, "unknown", "error"))
+		res := web3Call(t, web3, "eth_noSuchMethod")
+		require.NotNil(res.Error)
+		require.Equal(unknown+1, testutil.ToFloat64(apiRequestMtc.WithLabelValues(web3Protocol, "unknown", "error")))
+	})
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/blockindex"
+)
+
+const _cursorVersion = 1
+
+type (
+	// ActionsQuery is a query of a page of actions, of an address, of a block, or of the whole chain if neither is set
+	ActionsQuery struct {
+		Address   string
+		BlockHash string
+		// Cursor is the cursor returned with the previous page, empty for the first page
+		Cursor string
+		// Descending returns the latest actions first
+		Descending bool
+		// Limit is the number of actions in a page, 0 means the default page size, it is capped by the range query limit
+		Limit uint64
+	}
+
+	// ActionsPage is a page of actions
+	ActionsPage struct {
+		// Total is the number of actions matching the query
+		Total   uint64
+		Actions []*iotexapi.ActionInfo
+		// NextCursor is the cursor of the next page, empty if this is the last page
+		NextCursor string
+	}
+
+	// actionCursor is the position of the next page in the ascending index of the query. The scope binds it to the
+	// query and the order it was returned with.
+	actionCursor struct {
+		position uint64
+		scope    []byte
+	}
+
+	// actionRange returns the actions in [start, start+count) of an ascending index
+	actionRange func(start, count uint64) ([]*iotexapi.ActionInfo, error)
+)
+
+// GetActionsPage returns a page of actions. Unlike the offsets of GetActions, which shift as new actions are indexed,
+// a cursor keeps its position, so that paging through the actions of a busy address returns each of them exactly once.
+func (api *Server) GetActionsPage(ctx context.Context, in *apipb.GetActionsPageRequest) (*apipb.GetActionsPageResponse, error) {
+	page, err := api.actionsPage(ctx, &ActionsQuery{
+		Address:    in.GetAddress(),
+		BlockHash:  in.GetBlockHash(),
+		Cursor:     in.GetCursor(),
+		Descending: in.GetDescending(),
+		Limit:      in.GetLimit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &apipb.GetActionsPageResponse{
+		Total:      page.Total,
+		ActionInfo: page.Actions,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// actionsPage returns a page of the actions of the query
+func (api *Server) actionsPage(ctx context.Context, query *ActionsQuery) (*ActionsPage, error) {
+	if query.Address != "" && query.BlockHash != "" {
+		return nil, status.Error(codes.InvalidArgument, "address and block hash cannot be both set")
+	}
+	if addr := normalizeAddress(query.Address); addr != query.Address {
+		// the cursors are bound to the io address, whichever format the query takes
+		normalized := *query
+		normalized.Address = addr
+		query = &normalized
+	}
+	if query.BlockHash == "" && (!api.hasActionIndex || api.indexer == nil) {
+		return nil, status.Error(codes.NotFound, blockindex.ErrActionIndexNA.Error())
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = api.cfg.API.PageSize
+	}
+	if limit > api.cfg.API.RangeQueryLimit {
+		limit = api.cfg.API.RangeQueryLimit
+	}
+	total, fetch, err := api.actionIndex(query)
+	if err != nil {
+		return nil, err
+	}
+	scope := cursorScope(query)
+	ret := &ActionsPage{Total: total}
+	if total == 0 {
+		return ret, nil
+	}
+	// the position is the index of the first action of the page in ascending order, or of the last one in descending
+	position := uint64(0)
+	if query.Descending {
+		position = total - 1
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeActionCursor(query.Cursor)
+		if err != nil || !bytes.Equal(cursor.scope, scope) || cursor.position >= total {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		position = cursor.position
+	}
+	start, count := position, limit
+	if query.Descending {
+		if position+1 < limit {
+			count = position + 1
+		}
+		start = position + 1 - count
+	} else if start+count > total {
+		count = total - start
+	}
+	if ret.Actions, err = fetch(start, count); err != nil {
+		return nil, err
+	}
+	switch {
+	case query.Descending && start > 0:
+		ret.NextCursor = (&actionCursor{position: start - 1, scope: scope}).encode()
+	case !query.Descending && start+count < total:
+		ret.NextCursor = (&actionCursor{position: start + count, scope: scope}).encode()
+	}
+	if query.Descending {
+		for i, j := 0, len(ret.Actions)-1; i < j; i, j = i+1, j-1 {
+			ret.Actions[i], ret.Actions[j] = ret.Actions[j], ret.Actions[i]
+		}
+	}
+	return ret, nil
+}
+
+// actionIndex returns the number of actions matching the query, and the range function of its ascending index
+func (api *Server) actionIndex(query *ActionsQuery) (uint64, actionRange, error) {
+	switch {
+	case query.Address != "":
+		addr, err := address.FromString(query.Address)
+		if err != nil {
+			return 0, nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		addrHash := hash.BytesToHash160(addr.Bytes())
+		total, err := api.indexer.GetActionCountByAddress(addrHash)
+		if err != nil {
+			return 0, nil, status.Error(codes.Internal, err.Error())
+		}
+		return total, func(start, count uint64) ([]*iotexapi.ActionInfo, error) {
+			hashes, err := api.indexer.GetActionsByAddress(addrHash, start, count)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			return api.actionsByHashes(hashes)
+		}, nil
+	case query.BlockHash != "":
+		h, err := hash.HexStringToHash256(query.BlockHash)
+		if err != nil {
+			return 0, nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		blk, err := api.dao.GetBlock(h)
+		if err != nil {
+			return 0, nil, status.Error(codes.NotFound, err.Error())
+		}
+		return uint64(len(blk.Actions)), func(start, count uint64) ([]*iotexapi.ActionInfo, error) {
+			return api.actionsInBlock(blk, start, count), nil
+		}, nil
+	default:
+		total, err := api.indexer.GetTotalActions()
+		if err != nil {
+			return 0, nil, status.Error(codes.Internal, err.Error())
+		}
+		return total, func(start, count uint64) ([]*iotexapi.ActionInfo, error) {
+			hashes, err := api.indexer.GetActionHashFromIndex(start, count)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			return api.actionsByHashes(hashes)
+		}, nil
+	}
+}
+
+func (api *Server) actionsByHashes(hashes [][]byte) ([]*iotexapi.ActionInfo, error) {
+	ret := make([]*iotexapi.ActionInfo, 0, len(hashes))
+	for _, h := range hashes {
+		act, err := api.getAction(hash.BytesToHash256(h), false)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		ret = append(ret, act)
+	}
+	return ret, nil
+}
+
+// cursorScope binds a cursor to the filter and the order of the query
+func cursorScope(query *ActionsQuery) []byte {
+	order := "asc"
+	if query.Descending {
+		order = "desc"
+	}
+	h := hash.Hash160b([]byte(query.Address + "/" + query.BlockHash + "/" + order))
+	return h[:8]
+}
+
+func (c *actionCursor) encode() string {
+	b := make([]byte, 1+8+len(c.scope))
+	b[0] = _cursorVersion
+	binary.BigEndian.PutUint64(b[1:9], c.position)
+	copy(b[9:], c.scope)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeActionCursor(s string) (*actionCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 1+8+8 || b[0] != _cursorVersion {
+		return nil, errors.New("malformed cursor")
+	}
+	return &actionCursor{
+		position: binary.BigEndian.Uint64(b[1:9]),
+		scope:    b[9:],
+	}, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetActionsPage(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	// pages through all the actions, and checks they are returned in order exactly once
+	pageThrough := func(query *apipb.GetActionsPageRequest) []*iotexapi.ActionInfo {
+		var acts []*iotexapi.ActionInfo
+		for {
+			page, err := svr.GetActionsPage(ctx, query)
+			require.NoError(err)
+			require.True(uint64(len(page.ActionInfo)) <= query.Limit)
+			acts = append(acts, page.ActionInfo...)
+			if page.NextCursor == "" {
+				require.EqualValues(page.Total, len(acts))
+				return acts
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+	total, err := svr.indexer.GetTotalActions()
+	require.NoError(err)
+	expected, err := svr.getActions(0, total)
+	require.NoError(err)
+	asc := pageThrough(&apipb.GetActionsPageRequest{Limit: 3})
+	require.Equal(expected.ActionInfo, asc)
+	desc := pageThrough(&apipb.GetActionsPageRequest{Limit: 4, Descending: true})
+	require.Len(desc, len(asc))
+	for i := range desc {
+		require.Equal(asc[len(asc)-1-i], desc[i])
+	}
+
+	addr := identityset.Address(27).String()
+	byAddr := pageThrough(&apipb.GetActionsPageRequest{Address: addr, Limit: 1})
+	count, err := svr.indexer.GetActionCountByAddress(hash.BytesToHash160(identityset.Address(27).Bytes()))
+	require.NoError(err)
+	require.EqualValues(count, len(byAddr))
+	blk, err := svr.dao.GetBlockByHeight(1)
+	require.NoError(err)
+	h := blk.HashBlock()
+	byBlk := pageThrough(&apipb.GetActionsPageRequest{BlockHash: hex.EncodeToString(h[:]), Limit: 2, Descending: true})
+	require.Len(byBlk, len(blk.Actions))
+
+	// the page size is capped by the range query limit
+	svr.cfg.API.RangeQueryLimit = 2
+	page, err := svr.GetActionsPage(ctx, &apipb.GetActionsPageRequest{Limit: 100})
+	require.NoError(err)
+	require.Len(page.ActionInfo, 2)
+
+	// a cursor is bound to the query it is returned with
+	_, err = svr.GetActionsPage(ctx, &apipb.GetActionsPageRequest{Descending: true, Cursor: page.NextCursor})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.GetActionsPage(ctx, &apipb.GetActionsPageRequest{Cursor: "malformed"})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.GetActionsPage(ctx, &apipb.GetActionsPageRequest{Address: addr, BlockHash: hex.EncodeToString(h[:])})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	t.Run("rest", func(t *testing.T) {
+		rest, err := NewRESTServer(svr, 0)
+		require.NoError(err)
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/actions?order=desc&limit=1&address="+addr, nil))
+		require.Equal(http.StatusOK, rec.Code)
+		res := &iotexapi.GetActionsResponse{}
+		require.NoError(jsonpb.Unmarshal(rec.Body, res))
+		require.Equal(count, res.Total)
+		require.Equal(byAddr[len(byAddr)-1].ActHash, res.ActionInfo[0].ActHash)
+		cursor := rec.Header().Get(RESTNextCursorHeader)
+		require.NotEmpty(cursor)
+
+		rec = httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/actions?order=desc&limit=1&address="+addr+"&cursor="+cursor, nil))
+		require.Equal(http.StatusOK, rec.Code)
+		require.NoError(jsonpb.Unmarshal(rec.Body, res))
+		require.Equal(byAddr[len(byAddr)-2].ActHash, res.ActionInfo[0].ActHash)
+
+		rec = httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/actions?order=random", nil))
+		require.Equal(http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/api/apipb"
+)
+
+// pendingActionStream queues the actions accepted into the actpool for a streaming client. The actpool notifies its
+// subscribers with the pool locked, so the client is dropped instead of blocking the pool once it falls behind by more
+// than the queue size.
+type pendingActionStream struct {
+	queue chan action.SealedEnvelope
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newPendingActionStream(size int) *pendingActionStream {
+	if size <= 0 {
+		size = 1
+	}
+	return &pendingActionStream{
+		queue: make(chan action.SealedEnvelope, size),
+		done:  make(chan struct{}),
+	}
+}
+
+// ReceiveAction queues the action for the client
+func (s *pendingActionStream) ReceiveAction(selp action.SealedEnvelope) {
+	select {
+	case s.queue <- selp:
+	default:
+		s.once.Do(func() { close(s.done) })
+	}
+}
+
+// StreamPendingActions streams the actions newly accepted into the actpool, until the client goes away or lags behind
+func (api *Server) StreamPendingActions(
+	in *apipb.StreamPendingActionsRequest,
+	stream apipb.ExtensionService_StreamPendingActionsServer,
+) error {
+	s := newPendingActionStream(api.cfg.API.StreamBufferSize)
+	api.ap.AddSubscriber(s)
+	defer api.ap.RemoveSubscriber(s)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.done:
+			return status.Error(codes.ResourceExhausted, errStreamLagging.Error())
+		case selp := <-s.queue:
+			h := selp.Hash()
+			if err := stream.Send(&apipb.StreamPendingActionsResponse{
+				Action:     selp.Proto(),
+				ActionHash: hex.EncodeToString(h[:]),
+			}); err != nil {
+				return status.Error(codes.Aborted, err.Error())
+			}
+		}
+	}
+}
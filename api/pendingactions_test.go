@@ -0,0 +1,96 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type pendingActionsServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *apipb.StreamPendingActionsResponse
+}
+
+func (s *pendingActionsServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *pendingActionsServer) Send(res *apipb.StreamPendingActionsResponse) error {
+	s.sent <- res
+	return nil
+}
+
+func TestServer_StreamPendingActions(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ap := mock_actpool.NewMockActPool(ctrl)
+	cfg := config.Default
+	cfg.API.StreamBufferSize = 1
+	svr := Server{ap: ap, cfg: cfg}
+	subs := make(chan actpool.ActionSubscriber, 1)
+	ap.EXPECT().AddSubscriber(gomock.Any()).Do(func(s actpool.ActionSubscriber) { subs <- s }).Times(2)
+	ap.EXPECT().RemoveSubscriber(gomock.Any()).Times(2)
+
+	selp, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), 1,
+		big.NewInt(1), nil, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+	require.NoError(err)
+	h := selp.Hash()
+
+	t.Run("stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &pendingActionsServer{ctx: ctx, sent: make(chan *apipb.StreamPendingActionsResponse)}
+		errChan := make(chan error)
+		go func() {
+			errChan <- svr.StreamPendingActions(&apipb.StreamPendingActionsRequest{}, stream)
+		}()
+		sub := <-subs
+		sub.ReceiveAction(selp)
+		res := <-stream.sent
+		require.Equal(hex.EncodeToString(h[:]), res.ActionHash)
+		require.Equal(selp.Proto().GetCore().GetNonce(), res.Action.GetCore().GetNonce())
+		cancel()
+		require.NoError(<-errChan)
+	})
+
+	t.Run("lagging", func(t *testing.T) {
+		stream := &pendingActionsServer{ctx: context.Background(), sent: make(chan *apipb.StreamPendingActionsResponse)}
+		errChan := make(chan error)
+		go func() {
+			errChan <- svr.StreamPendingActions(&apipb.StreamPendingActionsRequest{}, stream)
+		}()
+		sub := <-subs
+		// the client doesn't receive, and the queue of one action overflows without blocking the actpool
+		for i := 0; i < 3; i++ {
+			sub.ReceiveAction(selp)
+		}
+		go func() {
+			for range stream.sent {
+			}
+		}()
+		require.Equal(codes.ResourceExhausted, status.Code(<-errChan))
+		close(stream.sent)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SendPrivateAction adds the action to the local actpool, and relays it to the current delegates only, so that the
+// action is not exposed to the whole network before it's included in a block
+func (api *Server) SendPrivateAction(ctx context.Context, in *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error) {
+	if api.privateRelay == nil {
+		return nil, status.Error(codes.Unimplemented, "private relay is not supported by this node")
+	}
+	selp, err := api.addAction(ctx, in.Action)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.privateRelay(context.Background(), api.bc.ChainID(), in.Action); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to relay the action to the delegates: %v", err)
+	}
+	hash := selp.Hash()
+	return &iotexapi.SendActionResponse{ActionHash: hex.EncodeToString(hash[:])}, nil
+}
+
+func (svr *RESTServer) sendPrivateAction(ctx context.Context, req *http.Request, _ string) (proto.Message, error) {
+	in := &iotexapi.SendActionRequest{}
+	if err := jsonpb.Unmarshal(req.Body, in); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return svr.api.SendPrivateAction(ctx, in)
+}
+
+func (svr *Web3Server) sendPrivateTransaction(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var raw hexutil.Bytes
+	if err := parseWeb3Params(params, 1, &raw); err != nil {
+		return nil, err
+	}
+	actPb := &iotextypes.Action{}
+	if err := proto.Unmarshal(raw, actPb); err != nil || actPb.GetCore() == nil {
+		return nil, errors.Wrap(errUnsupported, "only protobuf-encoded IoTeX actions are accepted")
+	}
+	res, err := svr.api.SendPrivateAction(ctx, &iotexapi.SendActionRequest{Action: actPb})
+	if err != nil {
+		return nil, err
+	}
+	return "0x" + res.ActionHash, nil
+}
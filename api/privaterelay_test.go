@@ -0,0 +1,70 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+)
+
+func TestServer_SendPrivateAction(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	ap := mock_actpool.NewMockActPool(ctrl)
+	var (
+		broadcasted int
+		relayed     []proto.Message
+		relayErr    error
+	)
+	svr := Server{bc: chain, ap: ap, broadcastHandler: func(_ context.Context, _ uint32, _ proto.Message) error {
+		broadcasted++
+		return nil
+	}}
+	test := sendActionTests[0]
+	request := &iotexapi.SendActionRequest{Action: test.actionPb}
+
+	// not supported without the relay
+	_, err := svr.SendPrivateAction(context.Background(), request)
+	require.Equal(codes.Unimplemented, status.Code(err))
+
+	svr.privateRelay = func(_ context.Context, chainID uint32, msg proto.Message) error {
+		require.EqualValues(1, chainID)
+		relayed = append(relayed, msg)
+		return relayErr
+	}
+	chain.EXPECT().ChainID().Return(uint32(1)).AnyTimes()
+	chain.EXPECT().Context().Return(protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{}), nil).AnyTimes()
+	ap.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	res, err := svr.SendPrivateAction(context.Background(), request)
+	require.NoError(err)
+	require.Equal(test.actionHash, res.ActionHash)
+	require.Len(relayed, 1)
+	require.True(proto.Equal(test.actionPb, relayed[0]))
+	// the private action is never gossiped
+	require.Zero(broadcasted)
+
+	relayErr = errors.New("no delegate")
+	_, err = svr.SendPrivateAction(context.Background(), request)
+	require.Equal(codes.Unavailable, status.Code(err))
+	require.Zero(broadcasted)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type (
+	// AccountProof is the merkle proof of an account, and of the storage slots of the contract, at a height
+	AccountProof struct {
+		Address string
+		Height  uint64
+		// Account is the account proven by the proof, which is empty if the account does not exist
+		Account *state.Account
+		// Proof proves the account, or its absence, against the state root
+		Proof         *factory.StateProof
+		StorageProofs []*StorageProof
+	}
+
+	// StorageProof is the merkle proof of a storage slot against the storage root of the contract
+	StorageProof struct {
+		Key hash.Hash256
+		// Value is nil if the slot is not set
+		Value []byte
+		Proof [][]byte
+	}
+)
+
+// GetProof returns the merkle proofs of an account and of its storage slots at a height, the tip height if it is 0
+func (api *Server) GetProof(ctx context.Context, in *apipb.GetProofRequest) (*apipb.GetProofResponse, error) {
+	height := in.GetHeight()
+	if height == 0 {
+		height = api.bc.TipHeight()
+	}
+	storageKeys := make([]hash.Hash256, 0, len(in.GetStorageKeys()))
+	for _, key := range in.GetStorageKeys() {
+		if len(key) != len(hash.ZeroHash256) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid storage key %x", key)
+		}
+		storageKeys = append(storageKeys, hash.BytesToHash256(key))
+	}
+	proof, err := api.accountProof(ctx, in.GetAddress(), storageKeys, height)
+	if err != nil {
+		return nil, err
+	}
+	res := &apipb.GetProofResponse{
+		Address:        proof.Address,
+		Height:         proof.Height,
+		Balance:        proof.Account.Balance.String(),
+		Nonce:          proof.Account.Nonce,
+		CodeHash:       proof.Account.CodeHash,
+		StateRoot:      proof.Proof.StateRoot,
+		NamespaceKey:   proof.Proof.NamespaceKey,
+		NamespaceProof: proof.Proof.NamespaceProof,
+		Key:            proof.Proof.Key,
+		KeyProof:       proof.Proof.KeyProof,
+	}
+	if proof.Account.Root != hash.ZeroHash256 {
+		res.StorageRoot = proof.Account.Root[:]
+	}
+	for _, sp := range proof.StorageProofs {
+		res.StorageProofs = append(res.StorageProofs, &apipb.StorageProof{
+			Key:   sp.Key[:],
+			Value: sp.Value,
+			Proof: sp.Proof,
+		})
+	}
+	return res, nil
+}
+
+// accountProof returns the merkle proofs of an account and of its storage slots at height. The states below the tip
+// height are only available in archive mode.
+func (api *Server) accountProof(ctx context.Context, addr string, storageKeys []hash.Hash256, height uint64) (*AccountProof, error) {
+	prover, ok := api.sf.(factory.Prover)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "merkle proofs are only available with the state trie, which statedb does not keep")
+	}
+	if height < api.bc.TipHeight() && !api.cfg.Chain.EnableArchiveMode {
+		return nil, status.Error(codes.FailedPrecondition, factory.ErrNoArchiveData.Error())
+	}
+	ioAddr, err := parseAddress(addr)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	addrHash := hash.BytesToHash160(ioAddr.Bytes())
+	proof, err := prover.ProofAtHeight(
+		height,
+		protocol.NamespaceOption(factory.AccountKVNamespace),
+		protocol.LegacyKeyOption(addrHash),
+	)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	account, err := provenAccount(proof)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	ret := &AccountProof{
+		Address: addr,
+		Height:  height,
+		Account: account,
+		Proof:   proof,
+	}
+	var root []byte
+	if account.Root != hash.ZeroHash256 {
+		root = account.Root[:]
+	}
+	hashFunc := evm.StorageTrieHashFunc(addrHash)
+	for _, key := range storageKeys {
+		sp := &StorageProof{Key: key}
+		sp.Proof, err = prover.TrieProofAtHeight(height, evm.ContractKVNameSpace, root, key[:], mptrie.HashFuncOption(hashFunc))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if root != nil {
+			sp.Value, err = mptrie.VerifyProof(root, key[:], sp.Proof, hashFunc)
+			if err != nil && errors.Cause(err) != trie.ErrNotExist {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		ret.StorageProofs = append(ret.StorageProofs, sp)
+	}
+	return ret, nil
+}
+
+// provenAccount verifies the proof of an account and decodes the account
+func provenAccount(proof *factory.StateProof) (*state.Account, error) {
+	account := state.EmptyAccount()
+	nsRoot, err := mptrie.VerifyProof(proof.StateRoot, proof.NamespaceKey, proof.NamespaceProof, nil)
+	switch errors.Cause(err) {
+	case nil:
+	case trie.ErrNotExist:
+		return &account, nil
+	default:
+		return nil, err
+	}
+	value, err := mptrie.VerifyProof(nsRoot, proof.Key, proof.KeyProof, nil)
+	switch errors.Cause(err) {
+	case nil:
+	case trie.ErrNotExist:
+		return &account, nil
+	default:
+		return nil, err
+	}
+	if err := state.Deserialize(&account, value); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetProof(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tip := svr.bc.TipHeight()
+	addr := identityset.Address(27).String()
+	expected, err := accountutil.AccountState(svr.sf, addr)
+	require.NoError(err)
+
+	slot := hash.Hash256b([]byte("slot"))
+	proof, err := svr.accountProof(ctx, addr, []hash.Hash256{slot}, tip)
+	require.NoError(err)
+	require.Equal(expected.Balance, proof.Account.Balance)
+	require.Equal(expected.Nonce, proof.Account.Nonce)
+	nsRoot, err := mptrie.VerifyProof(proof.Proof.StateRoot, proof.Proof.NamespaceKey, proof.Proof.NamespaceProof, nil)
+	require.NoError(err)
+	_, err = mptrie.VerifyProof(nsRoot, proof.Proof.Key, proof.Proof.KeyProof, nil)
+	require.NoError(err)
+	// the account is not a contract, so the slot is proven absent in the empty storage trie
+	require.Len(proof.StorageProofs, 1)
+	require.Nil(proof.StorageProofs[0].Value)
+	require.Len(proof.StorageProofs[0].Proof, 1)
+
+	// an account which does not exist is proven absent
+	absent := hash.Hash160b([]byte("absent"))
+	absentAddr, err := address.FromBytes(absent[:])
+	require.NoError(err)
+	proof, err = svr.accountProof(ctx, absentAddr.String(), nil, tip)
+	require.NoError(err)
+	require.Zero(proof.Account.Balance.Sign())
+
+	_, err = svr.accountProof(ctx, addr, nil, tip-1)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+	_, err = svr.accountProof(ctx, "invalid", nil, tip)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	t.Run("grpc", func(t *testing.T) {
+		res, err := svr.GetProof(ctx, &apipb.GetProofRequest{Address: addr, StorageKeys: [][]byte{slot[:]}})
+		require.NoError(err)
+		require.Equal(tip, res.Height)
+		require.Equal(expected.Balance.String(), res.Balance)
+		require.Equal(expected.Nonce, res.Nonce)
+		nsRoot, err := mptrie.VerifyProof(res.StateRoot, res.NamespaceKey, res.NamespaceProof, nil)
+		require.NoError(err)
+		_, err = mptrie.VerifyProof(nsRoot, res.Key, res.KeyProof, nil)
+		require.NoError(err)
+		require.Len(res.StorageProofs, 1)
+		require.Equal(slot[:], res.StorageProofs[0].Key)
+		require.Empty(res.StorageProofs[0].Value)
+
+		_, err = svr.GetProof(ctx, &apipb.GetProofRequest{Address: addr, StorageKeys: [][]byte{{1}}})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		ethAddr := common.BytesToAddress(identityset.Address(27).Bytes()).Hex()
+		res := web3Call(t, web3, "eth_getProof", ethAddr, []string{"0x1"}, "latest")
+		require.Nil(res.Error)
+		var result map[string]interface{}
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Equal(ethAddr, result["address"])
+		require.Equal(hexUint64(expected.Nonce), result["nonce"])
+		require.NotEmpty(result["accountProof"])
+		require.Len(result["storageProof"], 1)
+
+		res = web3Call(t, web3, "eth_getProof", ethAddr, []string{"0x" + common.Bytes2Hex(make([]byte, 33))}, "latest")
+		require.Equal(-32602, res.Error.Code)
+	})
+}
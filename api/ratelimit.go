@@ -0,0 +1,220 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// APIKeyHeader is the header, or the grpc metadata key, carrying the api key of a request
+const APIKeyHeader = "x-api-key"
+
+var (
+	// ErrRateLimited indicates the client has exceeded its request rate
+	ErrRateLimited = errors.New("request rate limit exceeded")
+
+	rateLimitedMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_api_rate_limited_total",
+			Help: "Number of api requests rejected by the rate limiter",
+		},
+		[]string{"method", "limit"},
+	)
+
+	// methodCosts are the tokens charged for expensive methods, other methods cost one token
+	methodCosts = map[string]int{
+		"GetLogs":                      10,
+		"ReadContract":                 5,
+		"ReadContractBatch":            50,
+		"EstimateGasForAction":         5,
+		"EstimateActionGasConsumption": 5,
+		"SimulateAction":               5,
+		"GetRawBlocks":                 5,
+		"GetBlockReceipts":             5,
+		"GetEpochBuckets":              5,
+		"StreamBlocks":                 10,
+		"StreamLogs":                   10,
+		"eth_getLogs":                  10,
+		"eth_getFilterLogs":            10,
+		"eth_call":                     5,
+		"iotex_callBatch":              50,
+		"eth_estimateGas":              5,
+		"eth_getBlockReceipts":         5,
+		"iotex_getStateDiff":           5,
+		"iotex_dumpAccounts":           10,
+		"debug_traceTransaction":       50,
+		"debug_traceCall":              50,
+		"debug_traceBlockByNumber":     100,
+		"debug_storageRangeAt":         10,
+		"TraceAction":                  50,
+		"TraceBlock":                   100,
+	}
+)
+
+func init() {
+	prometheus.MustRegister(rateLimitedMtc)
+}
+
+type (
+	requestOriginCtxKey struct{}
+
+	// requestOrigin is where a request comes from, and the credentials it carries
+	requestOrigin struct {
+		ip     string
+		apiKey string
+		token  string
+	}
+
+	// rateLimiter charges the requests of a client ip, and of an api key, against token buckets
+	rateLimiter struct {
+		cfg     config.RateLimit
+		ips     *cache.ThreadSafeLruCache
+		apiKeys *cache.ThreadSafeLruCache
+	}
+)
+
+// withRequestOrigin attaches the origin of a request to the context
+func withRequestOrigin(ctx context.Context, remoteAddr, apiKey string) context.Context {
+	return withRequestCredentials(ctx, remoteAddr, apiKey, "")
+}
+
+// withRequestCredentials attaches the origin of a request, with its bearer token, to the context
+func withRequestCredentials(ctx context.Context, remoteAddr, apiKey, token string) context.Context {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+	return context.WithValue(ctx, requestOriginCtxKey{}, requestOrigin{ip: ip, apiKey: apiKey, token: token})
+}
+
+// withHTTPRequestOrigin attaches the origin of an http request to the context
+func withHTTPRequestOrigin(ctx context.Context, req *http.Request) context.Context {
+	return withRequestCredentials(ctx, req.RemoteAddr, req.Header.Get(APIKeyHeader), bearerToken(req.Header.Get(AuthorizationHeader)))
+}
+
+func getRequestOrigin(ctx context.Context) (requestOrigin, bool) {
+	origin, ok := ctx.Value(requestOriginCtxKey{}).(requestOrigin)
+	return origin, ok
+}
+
+// newRateLimiter creates a rate limiter, it returns nil if rate limiting is disabled
+func newRateLimiter(cfg config.RateLimit) *rateLimiter {
+	if cfg.RequestsPerSecond <= 0 {
+		return nil
+	}
+	size := cfg.MaxClients
+	if size <= 0 {
+		size = config.Default.API.RateLimit.MaxClients
+	}
+	return &rateLimiter{
+		cfg:     cfg,
+		ips:     cache.NewThreadSafeLruCache(size),
+		apiKeys: cache.NewThreadSafeLruCache(size),
+	}
+}
+
+// allow charges the cost of the method to the origin of the request, all requests are allowed if the limiter is nil
+func (l *rateLimiter) allow(ctx context.Context, method string) error {
+	if l == nil {
+		return nil
+	}
+	origin, ok := getRequestOrigin(ctx)
+	if !ok {
+		return nil
+	}
+	cost, ok := methodCosts[method]
+	if !ok {
+		cost = 1
+	}
+	now := time.Now()
+	if !take(l.limiter(l.ips, origin.ip, l.cfg.RequestsPerSecond, l.cfg.Burst), now, cost) {
+		rateLimitedMtc.WithLabelValues(method, "ip").Inc()
+		return errors.Wrapf(ErrRateLimited, "client %s", origin.ip)
+	}
+	if origin.apiKey != "" && l.cfg.APIKeyRequestsPerSecond > 0 &&
+		!take(l.limiter(l.apiKeys, origin.apiKey, l.cfg.APIKeyRequestsPerSecond, l.cfg.APIKeyBurst), now, cost) {
+		rateLimitedMtc.WithLabelValues(method, "apikey").Inc()
+		return errors.Wrap(ErrRateLimited, "api key")
+	}
+	return nil
+}
+
+func (l *rateLimiter) limiter(buckets *cache.ThreadSafeLruCache, key string, rps float64, burst int) *rate.Limiter {
+	if v, ok := buckets.Get(key); ok {
+		return v.(*rate.Limiter)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	// concurrent first requests of a client may each create a bucket, only one of them is kept
+	limiter := rate.NewLimiter(rate.Limit(rps), burst)
+	buckets.Add(key, limiter)
+	return limiter
+}
+
+// take takes the tokens from the bucket, a cost above the burst takes the whole bucket
+func take(limiter *rate.Limiter, now time.Time, cost int) bool {
+	if cost > limiter.Burst() {
+		cost = limiter.Burst()
+	}
+	return limiter.AllowN(now, cost)
+}
+
+// unaryInterceptor rejects the unary grpc requests of clients which exceed their rate
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.allow(grpcRequestOrigin(ctx), path.Base(info.FullMethod)); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor charges the opening of a grpc stream to the client
+func (l *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.allow(grpcRequestOrigin(ss.Context()), path.Base(info.FullMethod)); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return handler(srv, ss)
+}
+
+func grpcRequestOrigin(ctx context.Context) context.Context {
+	var remoteAddr, apiKey, token string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(APIKeyHeader); len(keys) > 0 {
+			apiKey = keys[0]
+		}
+		if auths := md.Get(AuthorizationHeader); len(auths) > 0 {
+			token = bearerToken(auths[0])
+		}
+		// the rest gateway calls over an in-memory connection, and appends the address of its client to the
+		// forwarded addresses
+		if fwd := md.Get(restForwardedForKey); remoteAddr == restGatewayAddr && len(fwd) > 0 {
+			addrs := strings.Split(fwd[len(fwd)-1], ",")
+			remoteAddr = strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+	return withRequestCredentials(ctx, remoteAddr, apiKey, token)
+}
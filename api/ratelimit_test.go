@@ -0,0 +1,94 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestRateLimiter(t *testing.T) {
+	require := require.New(t)
+
+	var disabled *rateLimiter
+	require.Nil(newRateLimiter(config.Default.API.RateLimit))
+	require.NoError(disabled.allow(withRequestOrigin(context.Background(), "1.2.3.4:5", ""), "GetAccount"))
+
+	l := newRateLimiter(config.RateLimit{
+		RequestsPerSecond:       0.001,
+		Burst:                   3,
+		APIKeyRequestsPerSecond: 0.001,
+		APIKeyBurst:             4,
+	})
+	require.NotNil(l)
+	ctx := withRequestOrigin(context.Background(), "1.2.3.4:5", "")
+	for i := 0; i < 3; i++ {
+		require.NoError(l.allow(ctx, "GetAccount"))
+	}
+	require.Equal(ErrRateLimited, errors.Cause(l.allow(ctx, "GetAccount")))
+	// the same ip on another port shares the bucket
+	require.Equal(ErrRateLimited, errors.Cause(l.allow(withRequestOrigin(context.Background(), "1.2.3.4:6", ""), "GetAccount")))
+	// requests without an origin are not limited
+	require.NoError(l.allow(context.Background(), "GetAccount"))
+
+	// an expensive method takes the whole bucket
+	ctx = withRequestOrigin(context.Background(), "1.2.3.5:5", "")
+	require.NoError(l.allow(ctx, "eth_getLogs"))
+	require.Error(l.allow(ctx, "eth_blockNumber"))
+
+	// the api key bucket is shared by all ips
+	for i := 0; i < 4; i++ {
+		require.NoError(l.allow(withRequestOrigin(context.Background(), net.IPv4(10, 0, 0, byte(i)).String(), "key"), "GetAccount"))
+	}
+	require.Equal(ErrRateLimited, errors.Cause(l.allow(withRequestOrigin(context.Background(), "10.0.0.9", "key"), "GetAccount")))
+	require.NoError(l.allow(withRequestOrigin(context.Background(), "10.0.0.9", "other"), "GetAccount"))
+
+	t.Run("grpc", func(t *testing.T) {
+		l := newRateLimiter(config.RateLimit{RequestsPerSecond: 0.001, Burst: 1})
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(APIKeyHeader, "key"))
+		origin, ok := getRequestOrigin(grpcRequestOrigin(ctx))
+		require.True(ok)
+		require.Equal(requestOrigin{ip: "1.2.3.4", apiKey: "key"}, origin)
+		info := &grpc.UnaryServerInfo{FullMethod: "/iotexapi.APIService/GetAccount"}
+		handler := func(context.Context, interface{}) (interface{}, error) {
+			return "ok", nil
+		}
+		res, err := l.unaryInterceptor(ctx, nil, info, handler)
+		require.NoError(err)
+		require.Equal("ok", res)
+		_, err = l.unaryInterceptor(ctx, nil, info, handler)
+		require.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("web3", func(t *testing.T) {
+		cfg := newConfig(t)
+		svr, bfIndexFile, err := createServer(cfg, false)
+		require.NoError(err)
+		defer func() {
+			testutil.CleanupPath(t, bfIndexFile)
+		}()
+		svr.limiter = newRateLimiter(config.RateLimit{RequestsPerSecond: 0.001, Burst: 1})
+		web3 := NewWeb3Server(svr, 0)
+		ctx := withRequestOrigin(context.Background(), "1.2.3.4:5", "")
+		req := &web3Request{JSONRPC: web3Version, Method: "eth_blockNumber"}
+		require.Nil(web3.handleWeb3Req(ctx, req).Error)
+		require.Equal(-32005, web3.handleWeb3Req(ctx, req).Error.Code)
+	})
+}
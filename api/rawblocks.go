@@ -0,0 +1,244 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	// registers the gzip compressor of grpc, which the clients may request with grpc.UseCompressor
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/pkg/compress"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	// RawBlocksContentType is the content type of the raw block stream of the rest server, each block is a
+	// iotexapi.BlockInfo prefixed with its length in uvarint
+	RawBlocksContentType = "application/x-protobuf; delimited=true"
+
+	// ZstdCompressor is the name of the zstd compressor of grpc
+	ZstdCompressor = "zstd"
+)
+
+type (
+	// zstdCompressor is the zstd compressor of grpc. The messages are compressed as a whole with the shared encoder,
+	// which avoids creating an encoder per message.
+	zstdCompressor struct{}
+
+	zstdMessageWriter struct {
+		bytes.Buffer
+		w io.Writer
+	}
+
+	// rawBlockWriter writes the length delimited blocks of a stream, and flushes each of them to the client
+	rawBlockWriter struct {
+		w       io.Writer
+		flusher interface{ Flush() error }
+		http    http.Flusher
+	}
+)
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// StreamRawBlocks streams the blocks of the range one by one
+func (api *Server) StreamRawBlocks(in *iotexapi.GetRawBlocksRequest, stream apipb.ExtensionService_StreamRawBlocksServer) error {
+	return api.sendRawBlocks(stream.Context(), in, stream.Send)
+}
+
+// sendRawBlocks sends the blocks of the range one by one, so that a range larger than the message size limit can
+// be fetched in one request. The range is capped by the range query limit, as GetRawBlocks.
+func (api *Server) sendRawBlocks(
+	ctx context.Context,
+	in *iotexapi.GetRawBlocksRequest,
+	send func(*iotexapi.BlockInfo) error,
+) error {
+	if in.Count == 0 || in.Count > api.cfg.API.RangeQueryLimit {
+		return status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	tipHeight := api.bc.TipHeight()
+	if in.StartHeight > tipHeight {
+		return status.Error(codes.InvalidArgument, "start height should not exceed tip height")
+	}
+	for height := in.StartHeight; height <= tipHeight && height-in.StartHeight < in.Count; height++ {
+		if err := ctx.Err(); err != nil {
+			return status.Error(codes.Canceled, err.Error())
+		}
+		info, err := api.rawBlock(height, in.WithReceipts, in.WithTransactionLogs)
+		if err != nil {
+			return err
+		}
+		if err := send(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamRawBlocks streams the blocks of a range in chunks, compressed with zstd or gzip if the client accepts either
+func (svr *RESTServer) streamRawBlocks(ctx context.Context, w http.ResponseWriter, req *http.Request, _ string) {
+	in, err := rawBlocksRequest(req, svr.api.cfg.API.RangeQueryLimit)
+	if err != nil {
+		svr.writeError(w, err)
+		return
+	}
+	if in.StartHeight > svr.api.bc.TipHeight() {
+		svr.writeError(w, status.Error(codes.InvalidArgument, "start height should not exceed tip height"))
+		return
+	}
+	bw := &rawBlockWriter{w: w}
+	bw.http, _ = w.(http.Flusher)
+	w.Header().Set("Content-Type", RawBlocksContentType)
+	switch acceptedEncoding(req) {
+	case "zstd":
+		enc, err := zstd.NewWriter(w)
+		if err != nil {
+			svr.writeError(w, status.Error(codes.Internal, err.Error()))
+			return
+		}
+		defer enc.Close()
+		w.Header().Set("Content-Encoding", "zstd")
+		bw.w, bw.flusher = enc, enc
+	case "gzip":
+		enc := gzip.NewWriter(w)
+		defer enc.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		bw.w, bw.flusher = enc, enc
+	}
+	if err := svr.api.sendRawBlocks(ctx, in, bw.write); err != nil {
+		// the response status is already sent, so the stream is aborted to let the client see it truncated
+		log.L().Warn("failed to stream raw blocks.", zap.Error(err))
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func rawBlocksRequest(req *http.Request, limit uint64) (*iotexapi.GetRawBlocksRequest, error) {
+	query := req.URL.Query()
+	start, err := strconv.ParseUint(query.Get("start"), 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start %s", query.Get("start"))
+	}
+	count := limit
+	if v := query.Get("count"); v != "" {
+		if count, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid count %s", v)
+		}
+	}
+	if count == 0 || count > limit {
+		return nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	in := &iotexapi.GetRawBlocksRequest{
+		StartHeight: start,
+		Count:       count,
+	}
+	if in.WithReceipts, err = queryBool(req, "receipts"); err != nil {
+		return nil, err
+	}
+	if in.WithTransactionLogs, err = queryBool(req, "logs"); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// acceptedEncoding returns the preferred encoding of the Accept-Encoding header among zstd and gzip, or an empty
+// string if neither is accepted
+func acceptedEncoding(req *http.Request) string {
+	var gzipAccepted bool
+	for _, v := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		coding := strings.TrimSpace(v)
+		if i := strings.Index(coding, ";"); i >= 0 {
+			if strings.TrimSpace(coding[i+1:]) == "q=0" {
+				continue
+			}
+			coding = strings.TrimSpace(coding[:i])
+		}
+		switch coding {
+		case "zstd":
+			return "zstd"
+		case "gzip":
+			gzipAccepted = true
+		}
+	}
+	if gzipAccepted {
+		return "gzip"
+	}
+	return ""
+}
+
+func (bw *rawBlockWriter) write(info *iotexapi.BlockInfo) error {
+	ser, err := proto.Marshal(info)
+	if err != nil {
+		return err
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(ser)))
+	if _, err := bw.w.Write(prefix[:n]); err != nil {
+		return err
+	}
+	if _, err := bw.w.Write(ser); err != nil {
+		return err
+	}
+	if bw.flusher != nil {
+		if err := bw.flusher.Flush(); err != nil {
+			return err
+		}
+	}
+	if bw.http != nil {
+		bw.http.Flush()
+	}
+	return nil
+}
+
+// Compress returns a writer compressing the message on close
+func (*zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdMessageWriter{w: w}, nil
+}
+
+// Decompress returns a reader of the decompressed message
+func (*zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := compress.DecompZstd(data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(msg), nil
+}
+
+// Name returns the name of the compressor
+func (*zstdCompressor) Name() string {
+	return ZstdCompressor
+}
+
+func (mw *zstdMessageWriter) Close() error {
+	data, err := compress.CompZstd(mw.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = mw.w.Write(data)
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_StreamRawBlocks(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tip := svr.bc.TipHeight()
+	in := &iotexapi.GetRawBlocksRequest{StartHeight: 1, Count: 10, WithReceipts: true}
+	expected, err := svr.GetRawBlocks(ctx, in)
+	require.NoError(err)
+	require.Len(expected.Blocks, int(tip))
+	var blocks []*iotexapi.BlockInfo
+	require.NoError(svr.sendRawBlocks(ctx, in, func(info *iotexapi.BlockInfo) error {
+		blocks = append(blocks, info)
+		return nil
+	}))
+	require.Equal(expected.Blocks, blocks)
+
+	rest, err := NewRESTServer(svr, 0)
+	require.NoError(err)
+	for _, encoding := range []string{"", "gzip", "zstd"} {
+		req := httptest.NewRequest(http.MethodGet, "/rawblocks?start=1&count=10&receipts=true", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, req)
+		require.Equal(http.StatusOK, rec.Code)
+		require.Equal(RawBlocksContentType, rec.Header().Get("Content-Type"))
+		require.Equal(encoding, rec.Header().Get("Content-Encoding"))
+		require.True(rec.Flushed)
+		var body io.Reader = rec.Body
+		switch encoding {
+		case "gzip":
+			body, err = gzip.NewReader(body)
+			require.NoError(err)
+		case "zstd":
+			dec, err := zstd.NewReader(body)
+			require.NoError(err)
+			defer dec.Close()
+			body = dec
+		}
+		r := bufio.NewReader(body)
+		for i := 0; ; i++ {
+			size, err := binary.ReadUvarint(r)
+			if err == io.EOF {
+				require.Equal(len(expected.Blocks), i)
+				break
+			}
+			require.NoError(err)
+			ser := make([]byte, size)
+			_, err = io.ReadFull(r, ser)
+			require.NoError(err)
+			info := &iotexapi.BlockInfo{}
+			require.NoError(proto.Unmarshal(ser, info))
+			require.True(proto.Equal(expected.Blocks[i], info))
+		}
+	}
+	for _, query := range []string{"", "start=1&count=0", "start=1&count=1001", "start=5"} {
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rawblocks?"+query, nil))
+		require.Equal(http.StatusBadRequest, rec.Code)
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		conn := dialGRPCServer(t, svr)
+		defer svr.grpcServer.Stop()
+		defer conn.Close()
+		for _, compressor := range []string{"gzip", ZstdCompressor} {
+			stream, err := apipb.NewExtensionServiceClient(conn).StreamRawBlocks(ctx, in, grpc.UseCompressor(compressor))
+			require.NoError(err)
+			for i := 0; ; i++ {
+				info, err := stream.Recv()
+				if err == io.EOF {
+					require.Equal(len(expected.Blocks), i)
+					break
+				}
+				require.NoError(err)
+				require.True(proto.Equal(expected.Blocks[i], info))
+			}
+		}
+		stream, err := apipb.NewExtensionServiceClient(conn).StreamRawBlocks(ctx, &iotexapi.GetRawBlocksRequest{StartHeight: 1})
+		require.NoError(err)
+		_, err = stream.Recv()
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestZstdCompressor(t *testing.T) {
+	require := require.New(t)
+	c := encoding.GetCompressor(ZstdCompressor)
+	require.NotNil(c)
+	msg := bytes.Repeat([]byte("raw block "), 1000)
+	var b bytes.Buffer
+	w, err := c.Compress(&b)
+	require.NoError(err)
+	_, err = w.Write(msg)
+	require.NoError(err)
+	require.NoError(w.Close())
+	require.True(b.Len() < len(msg))
+	r, err := c.Decompress(&b)
+	require.NoError(err)
+	decompressed, err := ioutil.ReadAll(r)
+	require.NoError(err)
+	require.Equal(msg, decompressed)
+	require.NotNil(encoding.GetCompressor("gzip"))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type (
+	// ReadContractBatchResponse is the results of a batch of read-only contract calls
+	ReadContractBatchResponse struct {
+		// Height is the tip height of the state all the calls are executed against
+		Height  uint64
+		Results []*ReadContractResult
+	}
+
+	// ReadContractResult is the result of a call in a batch, Err is set if the call failed to run
+	ReadContractResult struct {
+		Response *iotexapi.ReadContractResponse
+		Err      error
+	}
+)
+
+// ReadContractBatch executes the read-only contract calls against the same state, and returns the status of each call
+// along with its result
+func (api *Server) ReadContractBatch(ctx context.Context, in *apipb.ReadContractBatchRequest) (*apipb.ReadContractBatchResponse, error) {
+	batch, err := api.readContractBatch(ctx, in.GetRequests())
+	if err != nil {
+		return nil, err
+	}
+	res := &apipb.ReadContractBatchResponse{
+		Height:  batch.Height,
+		Results: make([]*apipb.ReadContractResult, 0, len(batch.Results)),
+	}
+	for _, result := range batch.Results {
+		if result.Err != nil {
+			st := status.Convert(result.Err)
+			res.Results = append(res.Results, &apipb.ReadContractResult{Code: uint32(st.Code()), Message: st.Message()})
+			continue
+		}
+		res.Results = append(res.Results, &apipb.ReadContractResult{Response: result.Response})
+	}
+	return res, nil
+}
+
+// readContractBatch executes the read-only contract calls against the same state, so that the results are consistent
+// with each other even if a block is committed in the meantime. A call failing does not fail the others.
+func (api *Server) readContractBatch(ctx context.Context, in []*iotexapi.ReadContractRequest) (*ReadContractBatchResponse, error) {
+	if len(in) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty batch")
+	}
+	if len(in) > api.cfg.API.BatchRequestLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "batch size %d exceeds the limit %d", len(in), api.cfg.API.BatchRequestLimit)
+	}
+	var (
+		results     = make([]*ReadContractResult, len(in))
+		simulations []*factory.Simulation
+		// index of the call of each simulation
+		indexes []int
+	)
+	for i, req := range in {
+		sim, err := api.readContractSimulation(req)
+		if err != nil {
+			results[i] = &ReadContractResult{Err: err}
+			continue
+		}
+		simulations = append(simulations, sim)
+		indexes = append(indexes, i)
+	}
+	bcCtx, err := api.bc.Context()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	simulated, height, err := api.sf.SimulateExecutions(withSimulationOptions(ctx, bcCtx), simulations, api.dao.GetBlockHash)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for i, res := range simulated {
+		if res.Err != nil {
+			results[indexes[i]] = &ReadContractResult{Err: status.Error(codes.Internal, res.Err.Error())}
+			continue
+		}
+		results[indexes[i]] = &ReadContractResult{
+			Response: &iotexapi.ReadContractResponse{
+				Data:    hex.EncodeToString(res.Output),
+				Receipt: res.Receipt.ConvertToReceiptPb(),
+			},
+		}
+	}
+	return &ReadContractBatchResponse{Height: height, Results: results}, nil
+}
+
+func (api *Server) readContractSimulation(in *iotexapi.ReadContractRequest) (*factory.Simulation, error) {
+	sc := &action.Execution{}
+	if err := sc.LoadProto(in.Execution); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	caller, err := parseAddress(in.CallerAddress)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	state, err := accountutil.AccountState(api.sf, caller.String())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sc, err = action.NewExecution(normalizeAddress(sc.Contract()), state.Nonce+1, sc.Amount(), api.blockGasLimit(), big.NewInt(0), sc.Data())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &factory.Simulation{Caller: caller, Execution: sc}, nil
+}
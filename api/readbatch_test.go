@@ -0,0 +1,119 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_ReadContractBatch(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	test := readContractTests[0]
+	h, err := hash.HexStringToHash256(test.execHash)
+	require.NoError(err)
+	ai, err := svr.indexer.GetActionIndex(h[:])
+	require.NoError(err)
+	exec, err := svr.dao.GetActionByActionHash(h, ai.BlockHeight())
+	require.NoError(err)
+	read := &iotexapi.ReadContractRequest{
+		Execution:     exec.Proto().GetCore().GetExecution(),
+		CallerAddress: test.callerAddr,
+	}
+	expected, err := svr.ReadContract(ctx, read)
+	require.NoError(err)
+
+	// the caller transfers all its balance, which succeeds again in the next call, as the calls do not see the
+	// changes of each other
+	caller := identityset.Address(27).String()
+	state, err := accountutil.AccountState(svr.sf, caller)
+	require.NoError(err)
+	require.True(state.Balance.Sign() > 0)
+	transfer := &iotexapi.ReadContractRequest{
+		Execution: &iotextypes.Execution{
+			Amount:   state.Balance.String(),
+			Contract: identityset.Address(28).String(),
+		},
+		CallerAddress: caller,
+	}
+	res, err := svr.ReadContractBatch(ctx, &apipb.ReadContractBatchRequest{Requests: []*iotexapi.ReadContractRequest{
+		read,
+		{Execution: read.Execution, CallerAddress: "invalid"},
+		transfer,
+		transfer,
+	}})
+	require.NoError(err)
+	require.Equal(svr.bc.TipHeight(), res.Height)
+	require.Len(res.Results, 4)
+	require.EqualValues(codes.OK, res.Results[0].Code)
+	require.Equal(expected.Data, res.Results[0].Response.Data)
+	require.EqualValues(codes.InvalidArgument, res.Results[1].Code)
+	require.NotEmpty(res.Results[1].Message)
+	require.Nil(res.Results[1].Response)
+	for _, r := range res.Results[2:] {
+		require.EqualValues(codes.OK, r.Code)
+		require.Equal(uint64(iotextypes.ReceiptStatus_Success), r.Response.Receipt.Status)
+	}
+
+	_, err = svr.ReadContractBatch(ctx, &apipb.ReadContractBatchRequest{})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	svr.cfg.API.BatchRequestLimit = 1
+	_, err = svr.readContractBatch(ctx, []*iotexapi.ReadContractRequest{read, transfer})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	svr.cfg.API.BatchRequestLimit = 100
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		from := common.BytesToAddress(identityset.Address(27).Bytes()).Hex()
+		calls := []map[string]string{
+			{"from": from, "data": "0x600160005360016000f3"},
+			// reverts
+			{"from": from, "data": "0x60006000fd"},
+		}
+		res := web3Call(t, web3, "iotex_callBatch", calls, "latest")
+		require.Nil(res.Error)
+		var result struct {
+			BlockNumber string `json:"blockNumber"`
+			Results     []struct {
+				Result string     `json:"result"`
+				Error  *web3Error `json:"error"`
+			} `json:"results"`
+		}
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Equal(hexUint64(svr.bc.TipHeight()), result.BlockNumber)
+		require.Len(result.Results, 2)
+		require.Equal("0x", result.Results[0].Result)
+		require.Nil(result.Results[0].Error)
+		require.Equal(3, result.Results[1].Error.Code)
+
+		// only the latest state is supported
+		res = web3Call(t, web3, "iotex_callBatch", calls, "0x1")
+		require.NotNil(res.Error)
+	})
+}
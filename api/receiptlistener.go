@@ -0,0 +1,55 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// receiptListener streams the receipts of new blocks
+type receiptListener struct {
+	stream  apipb.ExtensionService_StreamReceiptsServer
+	errChan chan error
+}
+
+func newReceiptListener(stream apipb.ExtensionService_StreamReceiptsServer, errChan chan error) Responder {
+	return &receiptListener{
+		stream:  stream,
+		errChan: errChan,
+	}
+}
+
+// Respond sends the receipts of the block
+func (rl *receiptListener) Respond(blk *block.Block) error {
+	receipts := make([]*iotextypes.Receipt, 0, len(blk.Receipts))
+	for _, r := range blk.Receipts {
+		receipts = append(receipts, r.ConvertToReceiptPb())
+	}
+	h := blk.HashBlock()
+	if err := rl.stream.Send(&apipb.StreamReceiptsResponse{
+		Height:    blk.Height(),
+		BlockHash: hex.EncodeToString(h[:]),
+		Receipts:  receipts,
+	}); err != nil {
+		log.L().Info("Error when streaming the receipts", zap.Uint64("height", blk.Height()), zap.Error(err))
+		rl.errChan <- err
+		return err
+	}
+	return nil
+}
+
+// Exit sends to the error channel
+func (rl *receiptListener) Exit() {
+	rl.errChan <- nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+type receiptsServer struct {
+	grpc.ServerStream
+	err  error
+	sent []*apipb.StreamReceiptsResponse
+}
+
+func (s *receiptsServer) Context() context.Context {
+	return context.Background()
+}
+
+func (s *receiptsServer) Send(res *apipb.StreamReceiptsResponse) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, res)
+	return nil
+}
+
+func TestReceiptListener(t *testing.T) {
+	require := require.New(t)
+	errChan := make(chan error, 10)
+	server := &receiptsServer{}
+	responder := newReceiptListener(server, errChan)
+
+	blk, err := block.NewTestingBuilder().
+		SetHeight(3).
+		SetTimeStamp(time.Now()).
+		SetReceipts([]*action.Receipt{{BlockHeight: 3, Status: 1}, {BlockHeight: 3}}).
+		SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+
+	require.NoError(responder.Respond(&blk))
+	require.Len(server.sent, 1)
+	h := blk.HashBlock()
+	require.EqualValues(3, server.sent[0].Height)
+	require.Equal(hex.EncodeToString(h[:]), server.sent[0].BlockHash)
+	require.Len(server.sent[0].Receipts, 2)
+	require.EqualValues(1, server.sent[0].Receipts[0].Status)
+
+	server.err = errorSend
+	require.Equal(errorSend, responder.Respond(&blk))
+	responder.Exit()
+	require.Equal(errorSend, <-errChan)
+	require.NoError(<-errChan)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+)
+
+// GetBlockReceipts returns the receipts of each block of the range, in the order of the actions of the block
+func (api *Server) GetBlockReceipts(ctx context.Context, in *apipb.GetBlockReceiptsRequest) (*apipb.GetBlockReceiptsResponse, error) {
+	blocks, err := api.blockReceiptsRange(ctx, in.GetStartHeight(), in.GetCount())
+	if err != nil {
+		return nil, err
+	}
+	return &apipb.GetBlockReceiptsResponse{Blocks: blocks}, nil
+}
+
+// blockReceiptsRange returns the receipts of each block of the range, which saves an indexer a call per action. The
+// range is capped by the range query limit, as GetRawBlocks.
+func (api *Server) blockReceiptsRange(ctx context.Context, start, count uint64) ([]*iotextypes.Receipts, error) {
+	if count == 0 || count > api.cfg.API.RangeQueryLimit {
+		return nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	tipHeight := api.bc.TipHeight()
+	if start > tipHeight {
+		return nil, status.Error(codes.InvalidArgument, "start height should not exceed tip height")
+	}
+	var ret []*iotextypes.Receipts
+	for height := start; height <= tipHeight && height-start < count; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+		receipts, err := api.blockReceipts(height)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, receipts)
+	}
+	return ret, nil
+}
+
+func (api *Server) blockReceipts(height uint64) (*iotextypes.Receipts, error) {
+	key := fmt.Sprintf("receipts:%d", height)
+	if receipts := (&iotextypes.Receipts{}); api.cache.get(key, receipts) {
+		return receipts, nil
+	}
+	receipts, err := api.dao.GetReceipts(height)
+	if err != nil {
+		return nil, blockError(err)
+	}
+	receiptsPb := &iotextypes.Receipts{Receipts: make([]*iotextypes.Receipt, 0, len(receipts))}
+	for _, receipt := range receipts {
+		receiptsPb.Receipts = append(receiptsPb.Receipts, receipt.ConvertToReceiptPb())
+	}
+	api.cache.put(key, height, receiptsPb)
+	return receiptsPb, nil
+}
+
+// listReceipts returns the receipts of the blocks of a range, each block in a BlockInfo carrying its receipts only
+func (svr *RESTServer) listReceipts(ctx context.Context, req *http.Request, _ string) (proto.Message, error) {
+	query := req.URL.Query()
+	start, err := strconv.ParseUint(query.Get("start"), 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start %s", query.Get("start"))
+	}
+	count := svr.api.cfg.API.RangeQueryLimit
+	if v := query.Get("count"); v != "" {
+		if count, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid count %s", v)
+		}
+	}
+	blocks, err := svr.api.blockReceiptsRange(ctx, start, count)
+	if err != nil {
+		return nil, err
+	}
+	res := &iotexapi.GetRawBlocksResponse{Blocks: make([]*iotexapi.BlockInfo, 0, len(blocks))}
+	for _, receipts := range blocks {
+		res.Blocks = append(res.Blocks, &iotexapi.BlockInfo{Receipts: receipts.Receipts})
+	}
+	return res, nil
+}
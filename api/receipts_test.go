@@ -0,0 +1,109 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetBlockReceipts(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	res, err := svr.GetBlockReceipts(ctx, &apipb.GetBlockReceiptsRequest{StartHeight: 1, Count: 10})
+	require.NoError(err)
+	blocks := res.Blocks
+	require.Len(blocks, 4)
+	raw, err := svr.GetRawBlocks(ctx, &iotexapi.GetRawBlocksRequest{StartHeight: 1, Count: 4, WithReceipts: true})
+	require.NoError(err)
+	for i, receipts := range blocks {
+		require.Len(receipts.Receipts, len(raw.Blocks[i].Receipts))
+		for j := range receipts.Receipts {
+			require.True(proto.Equal(raw.Blocks[i].Receipts[j], receipts.Receipts[j]))
+		}
+	}
+
+	for _, test := range []struct {
+		start, count uint64
+	}{
+		{1, 0},
+		{1, cfg.API.RangeQueryLimit + 1},
+		{5, 1},
+	} {
+		_, err := svr.GetBlockReceipts(ctx, &apipb.GetBlockReceiptsRequest{StartHeight: test.start, Count: test.count})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	}
+
+	t.Run("rest", func(t *testing.T) {
+		rest, err := NewRESTServer(svr, 0)
+		require.NoError(err)
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/receipts?start=2&count=2", nil))
+		require.Equal(http.StatusOK, rec.Code)
+		res := &iotexapi.GetRawBlocksResponse{}
+		require.NoError(jsonpb.Unmarshal(rec.Body, res))
+		require.Len(res.Blocks, 2)
+		require.Nil(res.Blocks[0].Block)
+		require.Len(res.Blocks[0].Receipts, len(blocks[1].Receipts))
+		require.EqualValues(2, res.Blocks[0].Receipts[0].BlkHeight)
+
+		rec = httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/receipts?start=two", nil))
+		require.Equal(http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "eth_getBlockByNumber", "0x2", false)
+		require.Nil(res.Error)
+		var blk web3Block
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &blk))
+
+		for _, blkNumOrHash := range []string{"0x2", blk.Hash} {
+			res = web3Call(t, web3, "eth_getBlockReceipts", blkNumOrHash)
+			require.Nil(res.Error)
+			var receipts []*web3Receipt
+			require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &receipts))
+			require.Len(receipts, len(blk.Transactions))
+			// each receipt is the same as the one returned alone
+			for _, receipt := range receipts {
+				res = web3Call(t, web3, "eth_getTransactionReceipt", receipt.TransactionHash)
+				require.Nil(res.Error)
+				var expected web3Receipt
+				require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &expected))
+				require.Equal(expected, *receipt)
+			}
+		}
+
+		res = web3Call(t, web3, "eth_getBlockReceipts", "0x100")
+		require.Nil(res.Error)
+		require.Equal("null", string(res.Result.(json.RawMessage)))
+		res = web3Call(t, web3, "eth_getBlockReceipts", "0xzz")
+		require.Equal(-32602, res.Error.Code)
+	})
+}
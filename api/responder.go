@@ -7,11 +7,109 @@
 package api
 
 import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/blockchain/block"
 )
 
+var (
+	errStreamClosed  = errors.New("stream is closed")
+	errStreamLagging = errors.New("stream client is lagging behind")
+)
+
 // Responder responds to new block
 type Responder interface {
 	Respond(*block.Block) error
 	Exit()
 }
+
+// bufferedResponder decouples a streaming responder from block commits, so that a slow client blocks neither the chain
+// nor other clients for long. Blocks are queued and delivered in order. Once the queue is full, the listener waits up to
+// the timeout for the client to catch up, and drops it after that.
+type bufferedResponder struct {
+	Responder
+	queue   chan *block.Block
+	timeout time.Duration
+	done    chan struct{}
+	once    sync.Once
+	err     error
+}
+
+func newBufferedResponder(r Responder, size int, timeout time.Duration) *bufferedResponder {
+	if size <= 0 {
+		size = 1
+	}
+	br := &bufferedResponder{
+		Responder: r,
+		queue:     make(chan *block.Block, size),
+		timeout:   timeout,
+		done:      make(chan struct{}),
+	}
+	go br.run()
+	return br
+}
+
+// Respond queues the block for the client, waiting up to the timeout if the queue is full
+func (br *bufferedResponder) Respond(blk *block.Block) error {
+	select {
+	case <-br.done:
+		return errStreamClosed
+	case br.queue <- blk:
+		return nil
+	default:
+	}
+	if br.timeout > 0 {
+		timer := time.NewTimer(br.timeout)
+		defer timer.Stop()
+		select {
+		case <-br.done:
+			return errStreamClosed
+		case br.queue <- blk:
+			return nil
+		case <-timer.C:
+		}
+	}
+	br.close(errStreamLagging)
+	return errStreamLagging
+}
+
+// Exit closes the queue and notifies the client
+func (br *bufferedResponder) Exit() {
+	br.close(nil)
+	br.Responder.Exit()
+}
+
+// Done is closed once the responder stops delivering blocks, Err tells the reason
+func (br *bufferedResponder) Done() <-chan struct{} {
+	return br.done
+}
+
+// Err returns the reason why the responder stopped
+func (br *bufferedResponder) Err() error {
+	<-br.done
+	return br.err
+}
+
+func (br *bufferedResponder) close(err error) {
+	br.once.Do(func() {
+		br.err = err
+		close(br.done)
+	})
+}
+
+func (br *bufferedResponder) run() {
+	for {
+		select {
+		case <-br.done:
+			return
+		case blk := <-br.queue:
+			if err := br.Responder.Respond(blk); err != nil {
+				br.close(err)
+				return
+			}
+		}
+	}
+}
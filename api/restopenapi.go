@@ -0,0 +1,269 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+// restOpenAPISpec is the OpenAPI spec of the hand written routes of the rest server, the schemas are the JSON mapping
+// of the iotex-proto messages. The spec of the routes generated from the grpc methods is gateway.SwaggerJSON
+const restOpenAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "IoTeX REST API",
+    "description": "RESTful JSON mapping of the iotexapi.APIService grpc service. Messages use the proto3 JSON mapping with the original field names. The routes generated from the grpc methods are described in /gateway.swagger.json.",
+    "version": "v1"
+  },
+  "paths": {
+    "/health": {
+      "get": {
+        "summary": "Liveness of the node",
+        "operationId": "Health",
+        "responses": {
+          "200": {"description": "the node is alive"}
+        }
+      }
+    },
+    "/ready": {
+      "get": {
+        "summary": "Readiness of the node, it is not ready if the tip or an indexer lags behind",
+        "operationId": "Ready",
+        "responses": {
+          "200": {"description": "the node is ready, with its sync status", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SyncStatus"}}}},
+          "503": {"description": "the node is not ready, with its sync status", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SyncStatus"}}}}
+        }
+      }
+    },
+    "/syncstatus": {
+      "get": {
+        "summary": "Get the sync status of the chain, the indexers and the actpool, and the peer count",
+        "operationId": "GetSyncStatus",
+        "responses": {
+          "200": {"description": "the sync status", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SyncStatus"}}}}
+        }
+      }
+    },
+    "/rawblocks": {
+      "get": {
+        "summary": "Stream the blocks of a range, each as a iotexapi.BlockInfo prefixed with its length in uvarint, compressed with zstd or gzip if accepted by the client",
+        "operationId": "StreamRawBlocks",
+        "parameters": [
+          {"name": "start", "in": "query", "required": true, "schema": {"type": "integer"}},
+          {"name": "count", "in": "query", "required": false, "description": "number of blocks, defaults to and capped by the range query limit", "schema": {"type": "integer"}},
+          {"name": "receipts", "in": "query", "required": false, "schema": {"type": "boolean"}},
+          {"name": "logs", "in": "query", "required": false, "description": "include the transaction logs", "schema": {"type": "boolean"}},
+          {"name": "Accept-Encoding", "in": "header", "required": false, "schema": {"type": "string", "example": "zstd, gzip"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "length delimited iotexapi.BlockInfo",
+            "content": {"application/x-protobuf; delimited=true": {"schema": {"type": "string", "format": "binary"}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/actions": {
+      "get": {
+        "summary": "List a page of actions of an address, of a block, or of the chain, the cursor of the next page is returned in the X-Next-Cursor header",
+        "operationId": "ListActions",
+        "parameters": [
+          {"name": "address", "in": "query", "required": false, "schema": {"type": "string"}},
+          {"name": "block", "in": "query", "required": false, "description": "hash of the block", "schema": {"type": "string"}},
+          {"name": "cursor", "in": "query", "required": false, "description": "cursor of the page, returned with the previous page", "schema": {"type": "string"}},
+          {"name": "order", "in": "query", "required": false, "schema": {"type": "string", "enum": ["asc", "desc"]}},
+          {"name": "limit", "in": "query", "required": false, "description": "number of actions in a page, capped by the server", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "iotexapi.GetActionsResponse",
+            "headers": {"X-Next-Cursor": {"description": "cursor of the next page, absent on the last page", "schema": {"type": "string"}}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/actions/simulate": {
+      "post": {
+        "summary": "Simulate a signed or unsigned action on top of the pending state without broadcasting it, and return the receipt it would have",
+        "operationId": "SimulateAction",
+        "requestBody": {
+          "required": true,
+          "description": "iotexapi.SendActionRequest, the signature may be empty",
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}
+        },
+        "responses": {
+          "200": {"description": "iotextypes.Receipt", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/actions/private": {
+      "post": {
+        "summary": "Send a signed action to the current delegates only, instead of broadcasting it to the whole network",
+        "operationId": "SendPrivateAction",
+        "requestBody": {
+          "required": true,
+          "description": "iotexapi.SendActionRequest",
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}
+        },
+        "responses": {
+          "200": {"description": "iotexapi.SendActionResponse", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/receipts": {
+      "get": {
+        "summary": "Get the receipts of the blocks of a range, each block as a iotexapi.BlockInfo carrying its receipts only, in the order of its actions",
+        "operationId": "GetBlockReceipts",
+        "parameters": [
+          {"name": "start", "in": "query", "required": true, "schema": {"type": "integer"}},
+          {"name": "count", "in": "query", "required": false, "description": "number of blocks, defaults to and capped by the range query limit", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {"description": "iotexapi.GetRawBlocksResponse", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/epochs/candidates/{epoch}": {
+      "get": {
+        "summary": "Get the staking candidates of the snapshot taken at the end of an epoch",
+        "operationId": "GetEpochCandidates",
+        "parameters": [
+          {"name": "epoch", "in": "path", "required": true, "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {"description": "iotextypes.CandidateListV2", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/epochs/buckets/{epoch}": {
+      "get": {
+        "summary": "Get a page of the vote buckets of the snapshot taken at the end of an epoch",
+        "operationId": "GetEpochBuckets",
+        "parameters": [
+          {"name": "epoch", "in": "path", "required": true, "schema": {"type": "integer"}},
+          {"name": "offset", "in": "query", "required": false, "schema": {"type": "integer"}},
+          {"name": "limit", "in": "query", "required": false, "description": "number of buckets, defaults to and capped by the range query limit", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {"description": "iotextypes.VoteBucketList", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Message"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/light/headers/{height}": {
+      "get": {
+        "summary": "Get the header of a height with the commit endorsements proving it final, for light clients",
+        "operationId": "GetHeaderProof",
+        "parameters": [
+          {"name": "height", "in": "path", "required": true, "schema": {"type": "integer", "format": "uint64"}}
+        ],
+        "responses": {
+          "200": {"description": "the header proof", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HeaderProof"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/light/delegates/{epoch}": {
+      "get": {
+        "summary": "Get the delegate set of an epoch with the header proof of the first block of the epoch, for light clients",
+        "operationId": "GetDelegatesProof",
+        "parameters": [
+          {"name": "epoch", "in": "path", "required": true, "schema": {"type": "integer", "format": "uint64"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "the delegate set",
+            "content": {"application/json": {"schema": {
+              "type": "object",
+              "properties": {
+                "epoch": {"type": "integer", "format": "uint64"},
+                "delegates": {"type": "array", "items": {"type": "string"}},
+                "proof": {"$ref": "#/components/schemas/HeaderProof"}
+              }
+            }}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/light/txproofs/{hash}": {
+      "get": {
+        "summary": "Get the merkle path of an action to the tx root of its block, for light clients",
+        "operationId": "GetTxProof",
+        "parameters": [
+          {"name": "hash", "in": "path", "required": true, "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "the tx proof",
+            "content": {"application/json": {"schema": {
+              "type": "object",
+              "properties": {
+                "height": {"type": "integer", "format": "uint64"},
+                "actionHash": {"type": "string"},
+                "index": {"type": "integer", "description": "index of the action in the block"},
+                "path": {"type": "array", "items": {"type": "string"}, "description": "hex hashes of the siblings from the action up to the root"}
+              }
+            }}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Message": {
+        "type": "object",
+        "description": "proto3 JSON mapping of the iotex-proto message named in the response"
+      },
+      "Error": {
+        "type": "object",
+        "properties": {
+          "code": {"type": "integer", "description": "grpc status code"},
+          "message": {"type": "string"}
+        }
+      },
+      "HeaderProof": {
+        "type": "object",
+        "properties": {
+          "header": {"type": "string", "format": "byte", "description": "serialized iotextypes.BlockHeader"},
+          "footer": {"type": "string", "format": "byte", "description": "serialized iotextypes.BlockFooter carrying the commit endorsements"}
+        }
+      },
+      "SyncStatus": {
+        "type": "object",
+        "properties": {
+          "tipHeight": {"type": "integer", "format": "uint64"},
+          "targetHeight": {"type": "integer", "format": "uint64", "description": "best known height of the peers"},
+          "startingHeight": {"type": "integer", "format": "uint64"},
+          "blocksPerSecond": {"type": "object", "additionalProperties": {"type": "number"}, "description": "sync speed over the sliding windows of 1m, 5m and 15m"},
+          "etaSeconds": {"type": "integer", "format": "int64", "description": "estimated seconds to sync to the target height, 0 if synced and -1 if unknown"},
+          "indexers": {"type": "array", "items": {"type": "object", "properties": {
+            "name": {"type": "string"},
+            "height": {"type": "integer", "format": "uint64"},
+            "lag": {"type": "integer", "format": "uint64"}
+          }}},
+          "actPoolSize": {"type": "integer", "format": "uint64"},
+          "peerCount": {"type": "integer"},
+          "ready": {"type": "boolean"},
+          "reasons": {"type": "array", "items": {"type": "string"}}
+        }
+      }
+    },
+    "responses": {
+      "Error": {
+        "description": "the grpc status of the failed request, mapped to the http status",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,351 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/iotexproject/iotex-core/api/gateway"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	// RESTNextCursorHeader is the header carrying the cursor of the next page of a paginated rest response
+	RESTNextCursorHeader = "X-Next-Cursor"
+
+	// restMaxRequestSize is the maximal size of a rest request body
+	restMaxRequestSize = 1 << 20
+
+	// restGatewayAddr is the address of the in-memory connection of the gateway to the grpc server
+	restGatewayAddr = "bufconn"
+	// restGatewayBufferSize is the buffer size of the in-memory connection
+	restGatewayBufferSize = 1 << 20
+	// restForwardedForKey is the grpc metadata key of the addresses forwarded by the gateway
+	restForwardedForKey = "x-forwarded-for"
+)
+
+type (
+	// RESTServer serves a RESTful JSON mapping of the api, for clients which cannot speak grpc
+	RESTServer struct {
+		api        *Server
+		httpServer *httpServer
+		mux        *http.ServeMux
+		routes     map[string]map[string]http.HandlerFunc
+		marshaler  *jsonpb.Marshaler
+		// gateway serves the routes generated from the grpc methods, it calls the grpc server over conn
+		gateway    *runtime.ServeMux
+		hexGateway *runtime.ServeMux
+		listener   *bufconn.Listener
+		conn       *grpc.ClientConn
+	}
+
+	// hexAddressMarshaler marshals the gateway responses with the 0x hex encoding of each io address along with it
+	hexAddressMarshaler struct {
+		*runtime.JSONPb
+	}
+
+	restError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	// restPage is a page of a paginated rest response, the cursor of the next page is returned in a header
+	restPage struct {
+		proto.Message
+		nextCursor string
+	}
+
+	// restHandler handles a rest request, the path is the part after the route
+	restHandler func(ctx context.Context, req *http.Request, path string) (proto.Message, error)
+
+	// restStreamHandler handles a rest request whose response is not a single json message
+	restStreamHandler func(ctx context.Context, w http.ResponseWriter, req *http.Request, path string)
+)
+
+// NewRESTServer creates a rest server serving on the given port
+func NewRESTServer(api *Server, port int) (*RESTServer, error) {
+	svr := &RESTServer{
+		api:    api,
+		mux:    http.NewServeMux(),
+		routes: make(map[string]map[string]http.HandlerFunc),
+		// the field names of the messages are kept, as grpc-gateway does
+		marshaler: &jsonpb.Marshaler{OrigName: true},
+		listener:  bufconn.Listen(restGatewayBufferSize),
+	}
+	var err error
+	svr.conn, err = grpc.Dial(
+		restGatewayAddr,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return svr.listener.Dial()
+		}),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect the rest gateway to the grpc server")
+	}
+	client := iotexapi.NewAPIServiceClient(svr.conn)
+	svr.gateway = runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(restHeaderMatcher))
+	svr.hexGateway = runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(restHeaderMatcher),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &hexAddressMarshaler{JSONPb: &runtime.JSONPb{OrigName: true}}),
+	)
+	for _, mux := range []*runtime.ServeMux{svr.gateway, svr.hexGateway} {
+		if err := gateway.RegisterAPIServiceHandlerClient(context.Background(), mux, client); err != nil {
+			return nil, err
+		}
+	}
+	svr.forward("/chainmeta", http.MethodGet)
+	svr.forward("/blocks/", http.MethodGet)
+	svr.handle("/rawblocks", http.MethodGet, "GetRawBlocks", svr.streamRawBlocks)
+	svr.forward("/actions/", http.MethodGet)
+	svr.route("/actions", http.MethodGet, "GetActions", svr.listActions)
+	svr.forward("/actions", http.MethodPost)
+	svr.route("/actions/simulate", http.MethodPost, "SimulateAction", svr.simulateAction)
+	svr.route("/actions/private", http.MethodPost, "SendPrivateAction", svr.sendPrivateAction)
+	svr.forward("/receipts/", http.MethodGet)
+	svr.route("/receipts", http.MethodGet, "GetBlockReceipts", svr.listReceipts)
+	svr.forward("/accounts/", http.MethodGet)
+	svr.route("/epochs/candidates/", http.MethodGet, "GetEpochCandidates", svr.getEpochCandidates)
+	svr.route("/epochs/buckets/", http.MethodGet, "GetEpochBuckets", svr.getEpochBuckets)
+	svr.handle("/light/headers/", http.MethodGet, "GetRawBlocks", svr.getHeaderProof)
+	svr.handle("/light/delegates/", http.MethodGet, "GetEpochMeta", svr.getDelegatesProof)
+	svr.handle("/light/txproofs/", http.MethodGet, "GetReceiptByAction", svr.getTxProof)
+	for pattern, handler := range api.healthRoutes() {
+		svr.mux.HandleFunc(pattern, handler)
+	}
+	svr.mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(restOpenAPISpec)); err != nil {
+			log.L().Warn("failed to write openapi spec.", zap.Error(err))
+		}
+	})
+	svr.mux.HandleFunc("/gateway.swagger.json", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(gateway.SwaggerJSON)); err != nil {
+			log.L().Warn("failed to write gateway spec.", zap.Error(err))
+		}
+	})
+	svr.httpServer = newHTTPServer("rest", api.cfg.API.HTTP, port, svr.mux)
+	return svr, nil
+}
+
+// Start starts the rest server, and serves the gateway on the grpc server
+func (svr *RESTServer) Start(_ context.Context) error {
+	go func() {
+		if err := svr.api.grpcServer.Serve(svr.listener); err != nil {
+			log.L().Error("grpc server failed to serve the rest gateway.", zap.Error(err))
+		}
+	}()
+	return svr.httpServer.start()
+}
+
+// Stop stops the rest server
+func (svr *RESTServer) Stop(ctx context.Context) error {
+	if err := svr.httpServer.stop(ctx); err != nil {
+		return err
+	}
+	return svr.conn.Close()
+}
+
+// ServeHTTP handles a rest request
+func (svr *RESTServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	svr.mux.ServeHTTP(w, req)
+}
+
+// route registers the handler of a route and method, which is charged to the rate limiter as the grpc method
+func (svr *RESTServer) route(pattern, method, grpcMethod string, handler restHandler) {
+	svr.handle(pattern, method, grpcMethod, func(ctx context.Context, w http.ResponseWriter, req *http.Request, path string) {
+		res, err := handler(ctx, req, path)
+		if err != nil {
+			svr.writeError(w, err)
+			return
+		}
+		if page, ok := res.(*restPage); ok {
+			if page.nextCursor != "" {
+				w.Header().Set(RESTNextCursorHeader, page.nextCursor)
+			}
+			res = page.Message
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if req.URL.Query().Get("addressFormat") == AddressFormatBoth {
+			svr.writeWithHexAddresses(w, res)
+			return
+		}
+		if err := svr.marshaler.Marshal(w, res); err != nil {
+			log.L().Warn("failed to write rest response.", zap.Error(err))
+		}
+	})
+}
+
+// writeWithHexAddresses writes the response with the 0x hex encoding of each io address along with it
+func (svr *RESTServer) writeWithHexAddresses(w http.ResponseWriter, res proto.Message) {
+	data, err := svr.marshaler.MarshalToString(res)
+	if err != nil {
+		log.L().Warn("failed to write rest response.", zap.Error(err))
+		return
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		log.L().Warn("failed to write rest response.", zap.Error(err))
+		return
+	}
+	withHexAddresses(v)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L().Warn("failed to write rest response.", zap.Error(err))
+	}
+}
+
+// handle registers a handler writing the response itself, after the request is admitted by the rate limiter and the
+// authenticator
+func (svr *RESTServer) handle(pattern, method, grpcMethod string, handler restStreamHandler) {
+	svr.methods(pattern)[method] = func(w http.ResponseWriter, req *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, pattern)
+		if strings.HasSuffix(pattern, "/") && (path == "" || strings.Contains(path, "/")) {
+			svr.writeError(w, status.Error(codes.NotFound, "unknown path "+req.URL.Path))
+			return
+		}
+		req.Body = http.MaxBytesReader(w, req.Body, restMaxRequestSize)
+		ctx := withHTTPRequestOrigin(req.Context(), req)
+		if err := svr.api.limiter.allow(ctx, grpcMethod); err != nil {
+			svr.writeError(w, status.Error(codes.ResourceExhausted, err.Error()))
+			return
+		}
+		if err := svr.api.auth.authenticate(ctx, grpcMethod); err != nil {
+			svr.writeError(w, status.Error(codes.Unauthenticated, err.Error()))
+			return
+		}
+		handler(ctx, w, req, path)
+	}
+}
+
+// forward registers a route served by the gateway. The gateway calls the grpc server, whose interceptors admit the
+// request.
+func (svr *RESTServer) forward(pattern, method string) {
+	svr.methods(pattern)[method] = func(w http.ResponseWriter, req *http.Request) {
+		req.Body = http.MaxBytesReader(w, req.Body, restMaxRequestSize)
+		if req.URL.Query().Get("addressFormat") == AddressFormatBoth {
+			svr.hexGateway.ServeHTTP(w, req)
+			return
+		}
+		svr.gateway.ServeHTTP(w, req)
+	}
+}
+
+// methods returns the handlers of the methods of a route
+func (svr *RESTServer) methods(pattern string) map[string]http.HandlerFunc {
+	methods, ok := svr.routes[pattern]
+	if !ok {
+		methods = make(map[string]http.HandlerFunc)
+		svr.routes[pattern] = methods
+		svr.mux.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+			h, ok := methods[req.Method]
+			if !ok {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			h(w, req)
+		})
+	}
+	return methods
+}
+
+func (svr *RESTServer) listActions(ctx context.Context, req *http.Request, _ string) (proto.Message, error) {
+	params := req.URL.Query()
+	query := &ActionsQuery{
+		Address:   params.Get("address"),
+		BlockHash: params.Get("block"),
+		Cursor:    params.Get("cursor"),
+	}
+	switch order := params.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order %s", order)
+	}
+	if limit := params.Get("limit"); limit != "" {
+		var err error
+		if query.Limit, err = strconv.ParseUint(limit, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid limit %s", limit)
+		}
+	}
+	page, err := svr.api.actionsPage(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &restPage{
+		Message:    &iotexapi.GetActionsResponse{Total: page.Total, ActionInfo: page.Actions},
+		nextCursor: page.NextCursor,
+	}, nil
+}
+
+func (svr *RESTServer) simulateAction(ctx context.Context, req *http.Request, _ string) (proto.Message, error) {
+	in := &iotexapi.SendActionRequest{}
+	if err := jsonpb.Unmarshal(req.Body, in); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return svr.api.simulateAction(ctx, in.Action)
+}
+
+func (svr *RESTServer) writeError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	if err := json.NewEncoder(w).Encode(&restError{Code: int(st.Code()), Message: st.Message()}); err != nil {
+		log.L().Warn("failed to write rest response.", zap.Error(err))
+	}
+}
+
+func queryBool(req *http.Request, key string) (bool, error) {
+	v := req.URL.Query().Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid %s %s", key, v)
+	}
+	return b, nil
+}
+
+// Marshal marshals the response, adding the 0x hex encoding of each io address
+func (m *hexAddressMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	withHexAddresses(res)
+	return json.Marshal(res)
+}
+
+// restHeaderMatcher passes the api key header to the grpc server, along with the headers passed by default
+func restHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, APIKeyHeader) {
+		return APIKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
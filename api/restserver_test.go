@@ -0,0 +1,106 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestRESTServer(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, true)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	rest := startRESTServer(t, svr)
+	defer func() {
+		rest.Stop(context.Background())
+	}()
+	do := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(method, url, bytes.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/chainmeta", nil)
+	require.Equal(http.StatusOK, rec.Code)
+	chainMeta := &iotexapi.GetChainMetaResponse{}
+	require.NoError(jsonpb.Unmarshal(rec.Body, chainMeta))
+	require.EqualValues(4, chainMeta.ChainMeta.Height)
+
+	// the routes of the grpc methods are generated, so their requests and responses are the grpc messages
+	rec = do(http.MethodGet, "/blocks/1?count=1&withReceipts=true", nil)
+	require.Equal(http.StatusOK, rec.Code)
+	blks := &iotexapi.GetRawBlocksResponse{}
+	require.NoError(jsonpb.Unmarshal(rec.Body, blks))
+	require.Len(blks.Blocks, 1)
+	require.EqualValues(1, blks.Blocks[0].Block.Header.Core.Height)
+	require.Equal(len(blks.Blocks[0].Block.Body.Actions), len(blks.Blocks[0].Receipts))
+	require.Equal(http.StatusBadRequest, do(http.MethodGet, "/blocks/100?count=1", nil).Code)
+	require.Equal(http.StatusBadRequest, do(http.MethodGet, "/blocks/one?count=1", nil).Code)
+	require.Equal(http.StatusBadRequest, do(http.MethodGet, "/blocks/1?count=1&withReceipts=maybe", nil).Code)
+	require.Equal(http.StatusNotFound, do(http.MethodGet, "/blocks/1/actions", nil).Code)
+
+	rec = do(http.MethodGet, "/actions/"+hex.EncodeToString(transferHash1[:]), nil)
+	require.Equal(http.StatusOK, rec.Code)
+	acts := &iotexapi.GetActionsResponse{}
+	require.NoError(jsonpb.Unmarshal(rec.Body, acts))
+	require.Len(acts.ActionInfo, 1)
+	require.Equal(hex.EncodeToString(transferHash1[:]), acts.ActionInfo[0].ActHash)
+	rec = do(http.MethodGet, "/receipts/"+hex.EncodeToString(transferHash1[:]), nil)
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal(http.StatusNotFound, do(http.MethodGet, "/receipts/"+hex.EncodeToString(make([]byte, 32)), nil).Code)
+
+	rec = do(http.MethodGet, "/accounts/"+identityset.Address(27).String(), nil)
+	require.Equal(http.StatusOK, rec.Code)
+	account := &iotexapi.GetAccountResponse{}
+	require.NoError(jsonpb.Unmarshal(rec.Body, account))
+	require.Equal(identityset.Address(27).String(), account.AccountMeta.Address)
+
+	// sending an action needs a signed action in the body
+	require.Equal(http.StatusMethodNotAllowed, do(http.MethodPut, "/actions", nil).Code)
+	rec = do(http.MethodPost, "/actions", []byte("{"))
+	require.Equal(http.StatusBadRequest, rec.Code)
+	var restErr restError
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &restErr))
+	require.NotEmpty(restErr.Message)
+
+	rec = do(http.MethodGet, "/openapi.json", nil)
+	require.Equal(http.StatusOK, rec.Code)
+	var spec map[string]interface{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &spec))
+	require.Contains(spec["paths"], "/epochs/candidates/{epoch}")
+	rec = do(http.MethodGet, "/gateway.swagger.json", nil)
+	require.Equal(http.StatusOK, rec.Code)
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &spec))
+	require.Contains(spec["paths"], "/blocks/{startHeight}")
+	require.Contains(spec["paths"], "/actions/{byHash.actionHash}")
+}
+
+// startRESTServer starts a rest server on a random port, whose gateway is served by the grpc server
+func startRESTServer(t *testing.T, svr *Server) *RESTServer {
+	rest, err := NewRESTServer(svr, 0)
+	require.NoError(t, err)
+	require.NoError(t, rest.Start(context.Background()))
+	return rest
+}
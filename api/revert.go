@@ -0,0 +1,70 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// ExecutionRevertedError indicates a simulated execution did not succeed. Reason is decoded from the revert data,
+// which is kept in Data for the clients to decode the custom errors with the contract abi.
+type ExecutionRevertedError struct {
+	Status uint64
+	Reason string
+	Data   []byte
+}
+
+// newExecutionRevertedError creates the error of a failed execution from its return value
+func newExecutionRevertedError(receiptStatus uint64, retval []byte) *ExecutionRevertedError {
+	e := &ExecutionRevertedError{
+		Status: receiptStatus,
+		Data:   retval,
+	}
+	if receiptStatus == uint64(iotextypes.ReceiptStatus_ErrExecutionReverted) {
+		e.Reason = evm.DecodeRevertReason(retval)
+	}
+	return e
+}
+
+// Error returns the error message
+func (e *ExecutionRevertedError) Error() string {
+	if e.Status != uint64(iotextypes.ReceiptStatus_ErrExecutionReverted) {
+		return fmt.Sprintf("execution failed with status %s", iotextypes.ReceiptStatus_name[int32(e.Status)])
+	}
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// GRPCStatus returns the grpc status of the error, which carries the receipt status and the revert data
+func (e *ExecutionRevertedError) GRPCStatus() *status.Status {
+	st := status.New(codes.Internal, e.Error())
+	st, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "EXECUTION_REVERTED",
+		Domain: "iotex.io",
+		Metadata: map[string]string{
+			"status": strconv.FormatUint(e.Status, 10),
+			"reason": e.Reason,
+			"data":   hex.EncodeToString(e.Data),
+		},
+	})
+	if err != nil {
+		log.S().Panicf("Unexpected error attaching metadata: %v", err)
+	}
+	return st
+}
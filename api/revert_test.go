@@ -0,0 +1,52 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExecutionRevertedError(t *testing.T) {
+	require := require.New(t)
+
+	// Error("denied")
+	data, err := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000006" +
+		"64656e6965640000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+	e := newExecutionRevertedError(uint64(iotextypes.ReceiptStatus_ErrExecutionReverted), data)
+	require.Equal("denied", e.Reason)
+	require.Equal("execution reverted: denied", e.Error())
+
+	st := status.Convert(e)
+	require.Equal(codes.Internal, st.Code())
+	require.Len(st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(ok)
+	require.Equal("denied", info.Metadata["reason"])
+	require.Equal(hex.EncodeToString(data), info.Metadata["data"])
+
+	web3Err := toWeb3Error(errors.Wrap(e, "wrapped"))
+	require.Equal(3, web3Err.Code)
+	require.Equal("execution reverted: denied", web3Err.Message)
+	require.Equal(hexutil.Bytes(data), web3Err.Data)
+
+	require.Equal("execution reverted", newExecutionRevertedError(uint64(iotextypes.ReceiptStatus_ErrExecutionReverted), nil).Error())
+	e = newExecutionRevertedError(uint64(iotextypes.ReceiptStatus_ErrOutOfGas), nil)
+	require.Empty(e.Reason)
+	require.Equal("execution failed with status ErrOutOfGas", e.Error())
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+)
+
+// web3RoundHistory is a round in the result of iotex_getConsensusRounds, the proposal latency is how many milliseconds
+// after the start of the round the proposal is received, null if it is not received
+type web3RoundHistory struct {
+	Height          hexutil.Uint64      `json:"height"`
+	Round           hexutil.Uint64      `json:"round"`
+	Proposer        string              `json:"proposer"`
+	StartTime       hexutil.Uint64      `json:"startTime"`
+	ProposalLatency *hexutil.Uint64     `json:"proposalLatency"`
+	Endorsements    map[string][]string `json:"endorsements"`
+	Committed       bool                `json:"committed"`
+	Missed          bool                `json:"missed"`
+	ViewChange      bool                `json:"viewChange"`
+}
+
+// RoundHistories returns the histories of at most limit recent consensus rounds, the latest first
+func (api *Server) RoundHistories(limit uint64) ([]*scheme.RoundHistory, error) {
+	if api.roundHistoryReader == nil {
+		return nil, status.Error(codes.Unimplemented, "consensus round history is not available")
+	}
+	if limit == 0 || limit > api.cfg.API.RangeQueryLimit {
+		return nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	histories, err := api.roundHistoryReader.RoundHistories(limit)
+	switch errors.Cause(err) {
+	case nil:
+		return histories, nil
+	case scheme.ErrRoundHistoryNotSupported:
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	default:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (svr *Web3Server) getConsensusRounds(params []json.RawMessage) (interface{}, error) {
+	var limit hexutil.Uint64
+	if err := parseWeb3Params(params, 0, &limit); err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = hexutil.Uint64(svr.api.cfg.API.RangeQueryLimit)
+	}
+	histories, err := svr.api.RoundHistories(uint64(limit))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*web3RoundHistory, 0, len(histories))
+	for _, history := range histories {
+		r := &web3RoundHistory{
+			Height:       hexutil.Uint64(history.Height),
+			Round:        hexutil.Uint64(history.Round),
+			Proposer:     history.Proposer,
+			StartTime:    hexutil.Uint64(history.StartTime.Unix()),
+			Endorsements: history.Endorsements,
+			Committed:    history.Committed,
+			Missed:       history.Missed,
+			ViewChange:   history.ViewChange,
+		}
+		if !history.ProposalTime.IsZero() {
+			// the proposal may arrive before the round starts on the clock of the node
+			var latency hexutil.Uint64
+			if history.ProposalTime.After(history.StartTime) {
+				latency = hexutil.Uint64(history.ProposalTime.Sub(history.StartTime) / time.Millisecond)
+			}
+			r.ProposalLatency = &latency
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
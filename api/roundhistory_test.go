@@ -0,0 +1,108 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type roundHistoryReaderFunc func(uint64) ([]*scheme.RoundHistory, error)
+
+func (f roundHistoryReaderFunc) RoundHistories(limit uint64) ([]*scheme.RoundHistory, error) {
+	return f(limit)
+}
+
+func TestServer_RoundHistories(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	_, err = svr.RoundHistories(10)
+	require.Equal(codes.Unimplemented, status.Code(err))
+
+	start := time.Unix(1600000000, 0)
+	histories := []*scheme.RoundHistory{
+		{
+			Height:       5,
+			Round:        1,
+			Proposer:     identityset.Address(2).String(),
+			StartTime:    start.Add(10 * time.Second),
+			ProposalTime: start.Add(10*time.Second + 300*time.Millisecond),
+			Endorsements: map[string][]string{
+				identityset.Address(1).String(): {"proposal", "lock", "commit"},
+			},
+			Committed:  true,
+			ViewChange: true,
+		},
+		{
+			Height:       5,
+			Round:        0,
+			Proposer:     identityset.Address(1).String(),
+			StartTime:    start,
+			Endorsements: map[string][]string{},
+			Missed:       true,
+		},
+	}
+	svr.roundHistoryReader = roundHistoryReaderFunc(func(limit uint64) ([]*scheme.RoundHistory, error) {
+		if limit < uint64(len(histories)) {
+			return histories[:limit], nil
+		}
+		return histories, nil
+	})
+	res, err := svr.RoundHistories(10)
+	require.NoError(err)
+	require.Equal(histories, res)
+	_, err = svr.RoundHistories(0)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.RoundHistories(cfg.API.RangeQueryLimit + 1)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "iotex_getConsensusRounds")
+		require.Nil(res.Error)
+		var result []*web3RoundHistory
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Len(result, 2)
+		require.Equal(uint64(5), uint64(result[0].Height))
+		require.Equal(uint64(1), uint64(result[0].Round))
+		require.Equal(histories[0].Proposer, result[0].Proposer)
+		require.Equal(uint64(1600000010), uint64(result[0].StartTime))
+		require.NotNil(result[0].ProposalLatency)
+		require.Equal(uint64(300), uint64(*result[0].ProposalLatency))
+		require.Equal(histories[0].Endorsements, result[0].Endorsements)
+		require.True(result[0].Committed)
+		require.True(result[0].ViewChange)
+		require.Nil(result[1].ProposalLatency)
+		require.True(result[1].Missed)
+
+		res = web3Call(t, web3, "iotex_getConsensusRounds", "0x1")
+		require.Nil(res.Error)
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Len(result, 1)
+	})
+
+	svr.roundHistoryReader = roundHistoryReaderFunc(func(uint64) ([]*scheme.RoundHistory, error) {
+		return nil, scheme.ErrRoundHistoryNotSupported
+	})
+	_, err = svr.RoundHistories(10)
+	require.Equal(codes.Unimplemented, status.Code(err))
+}
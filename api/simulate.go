@@ -0,0 +1,145 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// SimulateAction returns the receipt the action would have on top of the pending state, without broadcasting it
+func (api *Server) SimulateAction(ctx context.Context, in *apipb.SimulateActionRequest) (*apipb.SimulateActionResponse, error) {
+	receipt, err := api.simulateAction(ctx, in.GetAction())
+	if err != nil {
+		return nil, err
+	}
+	return &apipb.SimulateActionResponse{Receipt: receipt}, nil
+}
+
+// simulateAction runs the action on top of the pending state, which is the latest state after the actions of the
+// sender waiting in the actpool, and returns the receipt it would have, without broadcasting it. The signature is
+// verified only if present, so that a wallet can check an action before asking the user to sign it.
+func (api *Server) simulateAction(ctx context.Context, in *iotextypes.Action) (*iotextypes.Receipt, error) {
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(in); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(selp.Signature()) > 0 {
+		if err := action.Verify(selp); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	sender, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var pending []action.SealedEnvelope
+	if api.ap != nil {
+		for _, act := range api.ap.GetUnconfirmedActs(sender.String()) {
+			// the action replaces the pending one of the same nonce
+			if act.Nonce() < selp.Nonce() {
+				pending = append(pending, act)
+			}
+		}
+		sort.Slice(pending, func(i, j int) bool {
+			return pending[i].Nonce() < pending[j].Nonce()
+		})
+	}
+	bcCtx, err := api.bc.Context()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	receipt, err := api.sf.SimulateAction(withSimulationOptions(ctx, bcCtx), pending, selp)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return receipt.ConvertToReceiptPb(), nil
+}
+
+// GetPendingAccount returns the metadata of an account in the pending state, so that the nonce and the balance
+// account for the actions just sent
+func (api *Server) GetPendingAccount(ctx context.Context, in *apipb.GetPendingAccountRequest) (*iotexapi.GetAccountResponse, error) {
+	addr, err := parseAddress(in.GetAddress())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	bcCtx, err := api.bc.Context()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	ps, err := api.pendingState(bcCtx)
+	if err != nil {
+		return nil, err
+	}
+	state, err := accountutil.AccountState(ps, addr.String())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &iotexapi.GetAccountResponse{
+		AccountMeta: &iotextypes.AccountMeta{
+			Address:      addr.String(),
+			Balance:      state.Balance.String(),
+			Nonce:        state.Nonce,
+			PendingNonce: state.Nonce + 1,
+			IsContract:   state.IsContract(),
+		},
+	}, nil
+}
+
+// ReadPendingContract reads the state of a contract on top of the pending state
+func (api *Server) ReadPendingContract(ctx context.Context, in *iotexapi.ReadContractRequest) (*iotexapi.ReadContractResponse, error) {
+	sc := &action.Execution{}
+	if err := sc.LoadProto(in.Execution); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	callerAddr, err := parseAddress(in.CallerAddress)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	retval, receipt, err := api.simulatePendingExecution(ctx, callerAddr, sc.Contract(), sc.Amount(), 0, sc.Data())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &iotexapi.ReadContractResponse{
+		Data:    hex.EncodeToString(retval),
+		Receipt: receipt.ConvertToReceiptPb(),
+	}, nil
+}
+
+// pendingState returns the state after the actions waiting in the actpool, on top of the latest state. The actions
+// of each sender run in the order of their nonces, and the senders in the order of their addresses.
+func (api *Server) pendingState(bcCtx context.Context) (*factory.PendingState, error) {
+	var pending []action.SealedEnvelope
+	if api.ap != nil {
+		actMap := api.ap.PendingActionMap()
+		senders := make([]string, 0, len(actMap))
+		for sender := range actMap {
+			senders = append(senders, sender)
+		}
+		sort.Strings(senders)
+		for _, sender := range senders {
+			pending = append(pending, actMap[sender]...)
+		}
+	}
+	ps, err := api.sf.PendingState(bcCtx, pending)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return ps, nil
+}
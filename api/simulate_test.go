@@ -0,0 +1,180 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_SimulateAction(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, true)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	sender := identityset.Address(27).String()
+	state, err := accountutil.AccountState(svr.sf, sender)
+	require.NoError(err)
+	pendingNonce, err := svr.ap.GetPendingNonce(sender)
+	require.NoError(err)
+	require.True(pendingNonce > state.Nonce+1)
+	transferAll := func(nonce uint64) *iotextypes.Action {
+		tsf, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), nonce, state.Balance, nil, testutil.TestGasLimit, big.NewInt(0))
+		require.NoError(err)
+		return tsf.Proto()
+	}
+
+	// replacing the first pending action, the whole balance can be transferred
+	receipt, err := svr.simulateAction(ctx, transferAll(state.Nonce+1))
+	require.NoError(err)
+	require.Equal(uint64(iotextypes.ReceiptStatus_Success), receipt.Status)
+	require.NotZero(receipt.GasConsumed)
+	res, err := svr.SimulateAction(ctx, &apipb.SimulateActionRequest{Action: transferAll(state.Nonce + 1)})
+	require.NoError(err)
+	require.True(proto.Equal(receipt, res.Receipt))
+	// but not after the pending actions, which spend some of it
+	_, err = svr.simulateAction(ctx, transferAll(pendingNonce))
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// an unsigned action is simulated, while an invalid signature is rejected
+	act := transferAll(state.Nonce + 1)
+	act.Signature = nil
+	_, err = svr.simulateAction(ctx, act)
+	require.NoError(err)
+	act.Signature = make([]byte, 65)
+	_, err = svr.simulateAction(ctx, act)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// the execution reverts
+	exec, err := testutil.SignedExecution("", identityset.PrivateKey(27), state.Nonce+1, big.NewInt(0), testutil.TestGasLimit, big.NewInt(0), []byte{0x60, 0x00, 0x60, 0x00, 0xfd})
+	require.NoError(err)
+	receipt, err = svr.simulateAction(ctx, exec.Proto())
+	require.NoError(err)
+	require.NotEqual(uint64(iotextypes.ReceiptStatus_Success), receipt.Status)
+	// nothing is committed nor broadcast
+	after, err := accountutil.AccountState(svr.sf, sender)
+	require.NoError(err)
+	require.Equal(state, after)
+
+	t.Run("rest", func(t *testing.T) {
+		rest, err := NewRESTServer(svr, 0)
+		require.NoError(err)
+		body, err := (&jsonpb.Marshaler{}).MarshalToString(&iotexapi.SendActionRequest{Action: transferAll(state.Nonce + 1)})
+		require.NoError(err)
+		rec := httptest.NewRecorder()
+		rest.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/actions/simulate", bytes.NewBufferString(body)))
+		require.Equal(http.StatusOK, rec.Code)
+		res := &iotextypes.Receipt{}
+		require.NoError(jsonpb.Unmarshal(rec.Body, res))
+		require.Equal(uint64(iotextypes.ReceiptStatus_Success), res.Status)
+	})
+}
+
+func TestWeb3Server_PendingState(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, true)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	web3 := NewWeb3Server(svr, 0)
+	sender := identityset.Address(27).String()
+	state, err := accountutil.AccountState(svr.sf, sender)
+	require.NoError(err)
+	pendingNonce, err := svr.ap.GetPendingNonce(sender)
+	require.NoError(err)
+	require.True(pendingNonce > state.Nonce+1)
+	ethAddr := mustIoAddrToEthAddr(sender)
+
+	var nonce, pending hexutil.Uint64
+	res := web3Call(t, web3, "eth_getTransactionCount", ethAddr, "latest")
+	require.Nil(res.Error)
+	require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &nonce))
+	require.Equal(state.Nonce+1, uint64(nonce))
+	res = web3Call(t, web3, "eth_getTransactionCount", ethAddr, "pending")
+	require.Nil(res.Error)
+	require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &pending))
+	require.Equal(pendingNonce, uint64(pending))
+
+	// the pending actions spend some of the balance
+	var balance, pendingBalance hexutil.Big
+	res = web3Call(t, web3, "eth_getBalance", ethAddr, "latest")
+	require.Nil(res.Error)
+	require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &balance))
+	require.Equal(state.Balance, balance.ToInt())
+	res = web3Call(t, web3, "eth_getBalance", ethAddr, "pending")
+	require.Nil(res.Error)
+	require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &pendingBalance))
+	require.Equal(-1, pendingBalance.ToInt().Cmp(balance.ToInt()))
+
+	// transferring the whole balance succeeds on the latest state only
+	call := map[string]string{
+		"from":  ethAddr,
+		"to":    mustIoAddrToEthAddr(identityset.Address(28).String()),
+		"value": (*hexutil.Big)(state.Balance).String(),
+	}
+	res = web3Call(t, web3, "eth_call", call, "latest")
+	require.Nil(res.Error)
+	res = web3Call(t, web3, "eth_call", call, "pending")
+	require.NotNil(res.Error)
+	require.Equal(3, res.Error.Code)
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := context.Background()
+		account, err := svr.GetPendingAccount(ctx, &apipb.GetPendingAccountRequest{Address: sender})
+		require.NoError(err)
+		require.Equal(pendingNonce, account.AccountMeta.PendingNonce)
+		require.Equal(pendingBalance.ToInt().String(), account.AccountMeta.Balance)
+		_, err = svr.GetPendingAccount(ctx, &apipb.GetPendingAccountRequest{Address: "invalid"})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		read := &iotexapi.ReadContractRequest{
+			Execution: &iotextypes.Execution{
+				Amount:   state.Balance.String(),
+				Contract: identityset.Address(28).String(),
+			},
+			CallerAddress: sender,
+		}
+		latest, err := svr.ReadContract(ctx, read)
+		require.NoError(err)
+		require.Equal(uint64(iotextypes.ReceiptStatus_Success), latest.Receipt.Status)
+		pending, err := svr.ReadPendingContract(ctx, read)
+		require.NoError(err)
+		require.NotEqual(uint64(iotextypes.ReceiptStatus_Success), pending.Receipt.Status)
+	})
+
+	// nothing is committed
+	after, err := accountutil.AccountState(svr.sf, sender)
+	require.NoError(err)
+	require.Equal(state, after)
+}
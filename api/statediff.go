@@ -0,0 +1,124 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type (
+	// web3StateDiff is the result of iotex_getStateDiff, in the format of the stateDiff of trace_replayBlockTransactions
+	// keyed by the address
+	web3StateDiff map[common.Address]*web3AccountDiff
+
+	web3AccountDiff struct {
+		Balance interface{}                 `json:"balance"`
+		Nonce   interface{}                 `json:"nonce"`
+		Storage map[common.Hash]interface{} `json:"storage"`
+	}
+
+	// web3Change is a changed value, which is "=" if unchanged, {"+": new} if created, {"-": old} if deleted, and
+	// {"*": {"from": old, "to": new}} otherwise
+	web3Change struct {
+		From interface{} `json:"from"`
+		To   interface{} `json:"to"`
+	}
+)
+
+// GetStateDiff returns the changes of the accounts and the contract storage made by the block at height, which are
+// kept for the latest blocks of the state diff retention
+func (api *Server) GetStateDiff(height uint64) (*factory.StateDiff, error) {
+	reader, ok := api.sf.(factory.StateDiffReader)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "state diff is not supported by the state factory")
+	}
+	if tipHeight := api.bc.TipHeight(); height > tipHeight {
+		return nil, status.Errorf(codes.InvalidArgument, "query height %d is higher than tip height %d", height, tipHeight)
+	}
+	diff, err := reader.StateDiff(height)
+	if err != nil {
+		return nil, historyError(err)
+	}
+	return diff, nil
+}
+
+func (svr *Web3Server) getStateDiff(params []json.RawMessage) (interface{}, error) {
+	var blkNum string
+	if err := parseWeb3Params(params, 1, &blkNum); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := svr.api.GetStateDiff(height)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(web3StateDiff)
+	accountOf := func(addr common.Address) *web3AccountDiff {
+		if acct, ok := ret[addr]; ok {
+			return acct
+		}
+		acct := &web3AccountDiff{Balance: "=", Nonce: "=", Storage: make(map[common.Hash]interface{})}
+		ret[addr] = acct
+		return acct
+	}
+	for _, acct := range diff.Accounts {
+		d := accountOf(common.BytesToAddress(acct.Address[:]))
+		d.Balance = web3ValueChange(web3Big(acct.OldBalance), web3Big(acct.NewBalance))
+		d.Nonce = web3ValueChange(web3Nonce(acct.OldNonce), web3Nonce(acct.NewNonce))
+	}
+	for _, slot := range diff.Storage {
+		d := accountOf(common.BytesToAddress(slot.Contract[:]))
+		d.Storage[common.BytesToHash(slot.Key[:])] = web3ValueChange(web3Slot(slot.OldValue), web3Slot(slot.NewValue))
+	}
+	return ret, nil
+}
+
+// web3ValueChange returns the change from old to new, the values are nil if they don't exist
+func web3ValueChange(old, new interface{}) interface{} {
+	switch {
+	case old == nil && new == nil:
+		return "="
+	case old == nil:
+		return map[string]interface{}{"+": new}
+	case new == nil:
+		return map[string]interface{}{"-": old}
+	default:
+		return map[string]interface{}{"*": &web3Change{From: old, To: new}}
+	}
+}
+
+func web3Big(v *big.Int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return (*hexutil.Big)(v)
+}
+
+func web3Nonce(v *uint64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return hexutil.Uint64(*v)
+}
+
+func web3Slot(v []byte) interface{} {
+	if v == nil {
+		return nil
+	}
+	return common.BytesToHash(v)
+}
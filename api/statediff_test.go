@@ -0,0 +1,55 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_GetStateDiff(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	cfg.Chain.StateDiffRetention = 2
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tip := svr.bc.TipHeight()
+	diff, err := svr.GetStateDiff(tip)
+	require.NoError(err)
+	require.NotEmpty(diff.Accounts)
+	for _, acct := range diff.Accounts {
+		require.False(acct.OldBalance != nil && acct.OldBalance.Cmp(acct.NewBalance) == 0 && *acct.OldNonce == *acct.NewNonce)
+	}
+
+	_, err = svr.GetStateDiff(tip + 1)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.GetStateDiff(tip - 2)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		res := web3Call(t, web3, "iotex_getStateDiff", "latest")
+		require.Nil(res.Error)
+		var result map[string]map[string]interface{}
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Len(result, len(diff.Accounts))
+		for _, acct := range result {
+			require.Contains(acct, "balance")
+			require.Contains(acct, "nonce")
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+)
+
+type (
+	// web3StorageRange is the result of debug_storageRangeAt. The keys of the storage trie are the slots themselves
+	// rather than their hashes, so the slot is keyed by itself.
+	web3StorageRange struct {
+		Storage map[common.Hash]web3StorageEntry `json:"storage"`
+		NextKey *common.Hash                     `json:"nextKey"`
+	}
+
+	web3StorageEntry struct {
+		Key   *common.Hash `json:"key"`
+		Value common.Hash  `json:"value"`
+	}
+)
+
+// ReadContractStorageRange returns at most limit slots of the storage of a contract at height, in the order of the keys
+// from the start key, and the key of the slot following them, which is nil if there are no more slots. The states
+// below the tip height are only available in archive mode.
+func (api *Server) ReadContractStorageRange(
+	ctx context.Context,
+	contract string,
+	start hash.Hash256,
+	limit uint64,
+	height uint64,
+) ([]evm.StorageSlot, *hash.Hash256, error) {
+	if limit == 0 || limit > api.cfg.API.RangeQueryLimit {
+		return nil, nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+	addr, err := parseAddress(contract)
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ep := execution.FindProtocol(api.registry)
+	if ep == nil {
+		return nil, nil, status.Error(codes.Unimplemented, "execution protocol is not registered")
+	}
+	var sr protocol.StateReader = api.sf
+	if height != api.bc.TipHeight() {
+		if sr, err = api.historyStateReader(height); err != nil {
+			return nil, nil, historyError(err)
+		}
+	}
+	slots, next, err := ep.ReadContractStorageRange(sr, addr, start, limit)
+	if err != nil {
+		return nil, nil, historyError(err)
+	}
+	return slots, next, nil
+}
+
+// storageRangeAt serves debug_storageRangeAt. The state is kept per block rather than per transaction, so the
+// transaction index is either 0 for the state before the block, or the number of actions in the block for the state
+// after it.
+func (svr *Web3Server) storageRangeAt(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		blkHash, addr, keyStart string
+		txIndex                 uint64
+		maxResult               uint64
+	)
+	if err := parseWeb3Params(params, 5, &blkHash, &txIndex, &addr, &keyStart, &maxResult); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlock(h)
+	if err != nil {
+		return nil, errors.Wrapf(errInvalidParams, "block %s is not found", blkHash)
+	}
+	height := blk.Height()
+	switch txIndex {
+	case 0:
+		height--
+	case uint64(len(blk.Actions)):
+	default:
+		return nil, errors.Wrap(errUnsupported, "the state within a block is not kept")
+	}
+	ioAddr, err := ethAddrToIoAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	start, err := hexutil.Decode(keyStart)
+	if err != nil || len(start) > len(hash.ZeroHash256) {
+		return nil, errors.Wrapf(errInvalidParams, "invalid key %s", keyStart)
+	}
+	slots, next, err := svr.api.ReadContractStorageRange(
+		ctx,
+		ioAddr.String(),
+		hash.BytesToHash256(common.LeftPadBytes(start, len(hash.ZeroHash256))),
+		maxResult,
+		height,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ret := &web3StorageRange{Storage: make(map[common.Hash]web3StorageEntry, len(slots))}
+	for _, slot := range slots {
+		key := common.BytesToHash(slot.Key[:])
+		ret.Storage[key] = web3StorageEntry{Key: &key, Value: common.BytesToHash(slot.Value)}
+	}
+	if next != nil {
+		key := common.BytesToHash(next[:])
+		ret.NextKey = &key
+	}
+	return ret, nil
+}
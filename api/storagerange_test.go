@@ -0,0 +1,74 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_ReadContractStorageRange(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tip := svr.bc.TipHeight()
+	addr := identityset.Address(27).String()
+
+	// the account is not a contract, so its storage is empty
+	slots, next, err := svr.ReadContractStorageRange(ctx, addr, hash.ZeroHash256, 10, tip)
+	require.NoError(err)
+	require.Empty(slots)
+	require.Nil(next)
+
+	_, _, err = svr.ReadContractStorageRange(ctx, addr, hash.ZeroHash256, 0, tip)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, _, err = svr.ReadContractStorageRange(ctx, addr, hash.ZeroHash256, cfg.API.RangeQueryLimit+1, tip)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, _, err = svr.ReadContractStorageRange(ctx, "invalid", hash.ZeroHash256, 10, tip)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, _, err = svr.ReadContractStorageRange(ctx, addr, hash.ZeroHash256, 10, tip-1)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+
+	t.Run("web3", func(t *testing.T) {
+		web3 := NewWeb3Server(svr, 0)
+		blk, err := svr.dao.GetBlockByHeight(tip)
+		require.NoError(err)
+		h := blk.HashBlock()
+		blkHash := "0x" + hex.EncodeToString(h[:])
+		ethAddr := common.BytesToAddress(identityset.Address(27).Bytes()).Hex()
+
+		res := web3Call(t, web3, "debug_storageRangeAt", blkHash, len(blk.Actions), ethAddr, "0x00", 10)
+		require.Nil(res.Error)
+		var result web3StorageRange
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &result))
+		require.Empty(result.Storage)
+		require.Nil(result.NextKey)
+
+		// the state in the middle of a block is not kept
+		res = web3Call(t, web3, "debug_storageRangeAt", blkHash, len(blk.Actions)+1, ethAddr, "0x00", 10)
+		require.NotNil(res.Error)
+		res = web3Call(t, web3, "debug_storageRangeAt", blkHash, 0, ethAddr, "0x00", 10)
+		require.NotNil(res.Error)
+	})
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/api/apipb"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// CallTracerName is the name of the tracer reporting the call tree of an execution
+const CallTracerName = "callTracer"
+
+var errDebugAPIDisabled = status.Error(codes.Unimplemented, "debug api is disabled")
+
+type (
+	// TraceConfig selects the tracer of a trace request, the struct logger is used if Tracer is empty
+	TraceConfig struct {
+		Tracer string
+		vm.LogConfig
+	}
+
+	// StructLogResult is the trace of the struct logger
+	StructLogResult struct {
+		Gas         uint64         `json:"gas"`
+		Failed      bool           `json:"failed"`
+		ReturnValue string         `json:"returnValue"`
+		StructLogs  []vm.StructLog `json:"structLogs"`
+	}
+
+	// ActionTrace is the trace of an action in a block
+	ActionTrace struct {
+		ActHash string      `json:"txHash"`
+		Result  interface{} `json:"result"`
+	}
+
+	// actionTracer traces an action with the tracer selected by the config
+	actionTracer struct {
+		tracer       vm.Tracer
+		structLogger *vm.StructLogger
+		callTracer   *evm.CallTracer
+	}
+)
+
+func newActionTracer(cfg *TraceConfig) (*actionTracer, error) {
+	if cfg == nil {
+		cfg = &TraceConfig{}
+	}
+	t := &actionTracer{}
+	switch cfg.Tracer {
+	case "":
+		t.structLogger = vm.NewStructLogger(&cfg.LogConfig)
+		t.tracer = t.structLogger
+	case CallTracerName:
+		t.callTracer = evm.NewCallTracer()
+		t.tracer = t.callTracer
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported tracer %s", cfg.Tracer)
+	}
+	return t, nil
+}
+
+// result returns the trace of the action, given its output and receipt
+func (t *actionTracer) result(retval []byte, receipt *action.Receipt) interface{} {
+	if t.callTracer != nil {
+		return t.callTracer.Result()
+	}
+	logs := t.structLogger.StructLogs()
+	if logs == nil {
+		logs = []vm.StructLog{}
+	}
+	return &StructLogResult{
+		Gas:         receipt.GasConsumed,
+		Failed:      receipt.Status != uint64(iotextypes.ReceiptStatus_Success),
+		ReturnValue: hex.EncodeToString(retval),
+		StructLogs:  logs,
+	}
+}
+
+// TraceCall traces a read-only execution against the latest state
+func (api *Server) TraceCall(
+	caller address.Address,
+	contract string,
+	amount *big.Int,
+	gasLimit uint64,
+	data []byte,
+	cfg *TraceConfig,
+) (interface{}, error) {
+	if !api.cfg.API.EnableDebugAPI {
+		return nil, errDebugAPIDisabled
+	}
+	t, err := newActionTracer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	retval, receipt, err := api.simulateExecution(evm.WithTracer(context.Background(), t.tracer), caller, contract, amount, gasLimit, data)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return t.result(retval, receipt), nil
+}
+
+// traceAction traces an execution action, by replaying the actions of its block up to it on top of the state of the
+// previous block, which requires the state of the height to be retained
+func (api *Server) traceAction(actHash string, cfg *TraceConfig) (interface{}, error) {
+	if !api.cfg.API.EnableDebugAPI {
+		return nil, errDebugAPIDisabled
+	}
+	h, err := hash.HexStringToHash256(actHash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	selp, _, height, err := api.getActionByActionHash(h)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if _, ok := selp.Action().(*action.Execution); !ok {
+		return nil, status.Error(codes.InvalidArgument, errors.Wrap(ErrAction, "not an execution").Error())
+	}
+	blk, err := api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, blockError(err)
+	}
+	traces, err := api.replayBlock(blk, cfg, func(selp action.SealedEnvelope) bool {
+		return selp.Hash() == h
+	})
+	if err != nil {
+		return nil, err
+	}
+	return traces[0].Result, nil
+}
+
+// traceBlock traces the execution actions in the block at the given height, by replaying all the actions of the block
+// on top of the state of the previous block, which requires the state of the height to be retained
+func (api *Server) traceBlock(height uint64, cfg *TraceConfig) ([]*ActionTrace, error) {
+	if !api.cfg.API.EnableDebugAPI {
+		return nil, errDebugAPIDisabled
+	}
+	blk, err := api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, blockError(err)
+	}
+	return api.replayBlock(blk, cfg, func(selp action.SealedEnvelope) bool {
+		_, ok := selp.Action().(*action.Execution)
+		return ok
+	})
+}
+
+// TraceAction traces an execution action, the trace is encoded in JSON
+func (api *Server) TraceAction(ctx context.Context, in *apipb.TraceActionRequest) (*apipb.TraceActionResponse, error) {
+	res, err := api.traceAction(in.GetActionHash(), newTraceConfig(in.GetConfig()))
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &apipb.TraceActionResponse{Result: string(data)}, nil
+}
+
+// TraceBlock traces the execution actions in a block, the traces are encoded in JSON
+func (api *Server) TraceBlock(ctx context.Context, in *apipb.TraceBlockRequest) (*apipb.TraceBlockResponse, error) {
+	traces, err := api.traceBlock(in.GetHeight(), newTraceConfig(in.GetConfig()))
+	if err != nil {
+		return nil, err
+	}
+	res := &apipb.TraceBlockResponse{Traces: make([]*apipb.ActionTrace, 0, len(traces))}
+	for _, trace := range traces {
+		data, err := json.Marshal(trace.Result)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		res.Traces = append(res.Traces, &apipb.ActionTrace{ActionHash: trace.ActHash, Result: string(data)})
+	}
+	return res, nil
+}
+
+func newTraceConfig(cfg *apipb.TraceConfig) *TraceConfig {
+	return &TraceConfig{
+		Tracer: cfg.GetTracer(),
+		LogConfig: vm.LogConfig{
+			DisableStack:   cfg.GetDisableStack(),
+			DisableMemory:  cfg.GetDisableMemory(),
+			DisableStorage: cfg.GetDisableStorage(),
+			Limit:          int(cfg.GetLimit()),
+		},
+	}
+}
+
+// replayBlock runs the actions of the block in order on top of the state of the previous height, and traces the
+// actions selected by traced. The replay stops after the last traced action.
+func (api *Server) replayBlock(
+	blk *block.Block,
+	cfg *TraceConfig,
+	traced func(action.SealedEnvelope) bool,
+) ([]*ActionTrace, error) {
+	if _, err := newActionTracer(cfg); err != nil {
+		return nil, err
+	}
+	last := -1
+	for i, selp := range blk.Actions {
+		if traced(selp) {
+			last = i
+		}
+	}
+	traces := []*ActionTrace{}
+	if last < 0 {
+		return traces, nil
+	}
+	height := blk.Height()
+	sr, err := api.historyStateReader(height - 1)
+	if err != nil {
+		return nil, historyError(err)
+	}
+	replayer, ok := sr.(factory.Replayer)
+	if !ok {
+		return nil, historyError(errors.Wrap(factory.ErrNotSupported, "the historical state cannot replay actions"))
+	}
+	ctx, err := api.replayContext(blk)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := replayer.CreatePreStates(ctx); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for _, selp := range blk.Actions[:last+1] {
+		var (
+			actCtx = ctx
+			t      *actionTracer
+		)
+		if traced(selp) {
+			t, _ = newActionTracer(cfg)
+			actCtx = evm.WithTracer(ctx, t.tracer)
+		}
+		receipt, err := replayer.RunAction(actCtx, selp)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if t == nil {
+			continue
+		}
+		if receipt == nil {
+			return nil, status.Errorf(codes.Internal, "action %x is not handled by any protocol", selp.Hash())
+		}
+		var retval []byte
+		if t.structLogger != nil {
+			retval = t.structLogger.Output()
+		}
+		actHash := selp.Hash()
+		traces = append(traces, &ActionTrace{ActHash: hex.EncodeToString(actHash[:]), Result: t.result(retval, receipt)})
+	}
+	return traces, nil
+}
+
+// replayContext returns the context of the block to replay, whose tip is the previous block
+func (api *Server) replayContext(blk *block.Block) (context.Context, error) {
+	height := blk.Height()
+	tip, err := api.tipInfoAtHeight(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := address.FromBytes(blk.PublicKey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	ctx := protocol.WithRegistry(context.Background(), api.registry)
+	ctx = protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{
+		Genesis: api.cfg.Genesis,
+		Tip:     *tip,
+	})
+	return protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+		BlockHeight:    height,
+		BlockTimeStamp: blk.Timestamp(),
+		GasLimit:       api.cfg.Genesis.BlockGasLimitByHeight(height),
+		Producer:       producer,
+	}), nil
+}
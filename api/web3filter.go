@@ -0,0 +1,359 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/action"
+	logfilter "github.com/iotexproject/iotex-core/api/logfilter"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+const (
+	// web3FilterTimeout is how long a filter lives without being polled
+	web3FilterTimeout = 5 * time.Minute
+	// web3MaxFiltersPerClient caps the installed filters of a single client
+	web3MaxFiltersPerClient = 100
+	// web3MaxFilterChanges caps the changes buffered by a filter between two polls, older changes are dropped
+	web3MaxFilterChanges = 10000
+)
+
+// filter types
+const (
+	filterLogs = iota
+	filterBlocks
+	filterPendingTransactions
+)
+
+var errFilterNotFound = errors.New("filter not found")
+
+type (
+	// web3Filter buffers the changes of a polling filter since the last poll
+	web3Filter struct {
+		id       string
+		kind     int
+		client   string
+		mgr      *web3FilterManager
+		obj      web3FilterObject
+		filter   *logfilter.LogFilter
+		mutex    sync.Mutex
+		changes  []interface{}
+		lastPoll time.Time
+		removed  bool
+	}
+
+	// web3FilterManager keeps the installed filters and expires those not polled in time
+	web3FilterManager struct {
+		svr     *Web3Server
+		mutex   sync.RWMutex
+		filters map[string]*web3Filter
+		clients map[string]int
+		timeout time.Duration
+		quota   int
+	}
+
+	web3ClientCtxKey struct{}
+)
+
+func newWeb3FilterManager(svr *Web3Server) *web3FilterManager {
+	return &web3FilterManager{
+		svr:     svr,
+		filters: make(map[string]*web3Filter),
+		clients: make(map[string]int),
+		timeout: web3FilterTimeout,
+		quota:   web3MaxFiltersPerClient,
+	}
+}
+
+// web3Client returns the identity of the client which filter quota is charged to
+func web3Client(ctx context.Context) string {
+	client, _ := ctx.Value(web3ClientCtxKey{}).(string)
+	return client
+}
+
+// withWeb3Client attaches the client identity to the context, http clients are identified by host
+func withWeb3Client(ctx context.Context, remoteAddr string, isWebsocket bool) context.Context {
+	if !isWebsocket {
+		if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+			remoteAddr = host
+		}
+	}
+	return context.WithValue(ctx, web3ClientCtxKey{}, remoteAddr)
+}
+
+func (m *web3FilterManager) install(f *web3Filter) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.clients[f.client] >= m.quota {
+		return "", errors.Errorf("too many filters, limit is %d", m.quota)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	f.id = "0x" + hex.EncodeToString(id)
+	f.mgr = m
+	f.lastPoll = time.Now()
+	m.filters[f.id] = f
+	m.clients[f.client]++
+	return f.id, nil
+}
+
+func (m *web3FilterManager) get(id string) (*web3Filter, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	f, ok := m.filters[id]
+	if !ok {
+		return nil, errors.Wrapf(errFilterNotFound, "filter %s", id)
+	}
+	return f, nil
+}
+
+func (m *web3FilterManager) uninstall(id string) bool {
+	m.mutex.Lock()
+	f, ok := m.filters[id]
+	if ok {
+		delete(m.filters, id)
+		if m.clients[f.client]--; m.clients[f.client] <= 0 {
+			delete(m.clients, f.client)
+		}
+	}
+	m.mutex.Unlock()
+	if !ok {
+		return false
+	}
+	f.mutex.Lock()
+	f.removed = true
+	f.changes = nil
+	f.mutex.Unlock()
+	if f.kind == filterPendingTransactions {
+		m.svr.api.ap.RemoveSubscriber(f)
+	}
+	return true
+}
+
+// uninstallClient removes all filters of a client, which is called when a websocket connection closes
+func (m *web3FilterManager) uninstallClient(client string) {
+	for _, id := range m.filterIDs(func(f *web3Filter) bool { return f.client == client }) {
+		m.uninstall(id)
+	}
+}
+
+// expire removes the filters not polled since the timeout
+func (m *web3FilterManager) expire(now time.Time) {
+	for _, id := range m.filterIDs(func(f *web3Filter) bool {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return now.Sub(f.lastPoll) > m.timeout
+	}) {
+		m.uninstall(id)
+	}
+}
+
+func (m *web3FilterManager) filterIDs(pred func(*web3Filter) bool) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	var ids []string
+	for id, f := range m.filters {
+		if pred(f) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// run expires filters periodically until the context is done
+func (m *web3FilterManager) run(ctx context.Context) {
+	ticker := time.NewTicker(m.timeout / 5)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.expire(now)
+		}
+	}
+}
+
+func (f *web3Filter) push(changes ...interface{}) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.removed {
+		return errFilterNotFound
+	}
+	f.changes = append(f.changes, changes...)
+	if over := len(f.changes) - web3MaxFilterChanges; over > 0 {
+		f.changes = f.changes[over:]
+	}
+	return nil
+}
+
+// inRange checks whether the height is within the block range of a log filter, a tag other than earliest is open-ended
+func (f *web3Filter) inRange(height uint64) bool {
+	bound := func(tag string) (uint64, bool) {
+		switch tag {
+		case "", "latest", "pending":
+			return 0, false
+		case "earliest":
+			return 1, true
+		}
+		n, err := hexutil.DecodeUint64(tag)
+		return n, err == nil
+	}
+	if from, ok := bound(f.obj.FromBlock); ok && height < from {
+		return false
+	}
+	if to, ok := bound(f.obj.ToBlock); ok && height > to {
+		return false
+	}
+	return true
+}
+
+// touch keeps the filter alive
+func (f *web3Filter) touch() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.lastPoll = time.Now()
+}
+
+// poll returns and clears the buffered changes
+func (f *web3Filter) poll() []interface{} {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	changes := f.changes
+	f.changes = nil
+	f.lastPoll = time.Now()
+	if changes == nil {
+		changes = []interface{}{}
+	}
+	return changes
+}
+
+// Respond buffers the block hash or the matching logs of a new block
+func (f *web3Filter) Respond(blk *block.Block) error {
+	switch f.kind {
+	case filterBlocks:
+		return f.push(hashToHex(blk.HashBlock()))
+	case filterLogs:
+		if !f.inRange(blk.Height()) {
+			return f.push()
+		}
+		if bloom := blk.LogsBloomfilter(); bloom != nil && !f.filter.ExistInBloomFilter(bloom) {
+			return f.push()
+		}
+		blkHash := blk.HashBlock()
+		var logs []interface{}
+		for _, l := range f.filter.MatchLogs(blk.Receipts) {
+			index, _ := actionIndexInBlock(blk, hash.BytesToHash256(l.ActHash))
+			logs = append(logs, newWeb3Log(l, blkHash, index))
+		}
+		return f.push(logs...)
+	}
+	return nil
+}
+
+// Exit is called when the chain listener stops
+func (f *web3Filter) Exit() {
+	f.mgr.uninstall(f.id)
+}
+
+// ReceiveAction buffers the hash of a newly accepted pending action
+func (f *web3Filter) ReceiveAction(selp action.SealedEnvelope) {
+	_ = f.push(hashToHex(selp.Hash()))
+}
+
+func (svr *Web3Server) newFilter(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	f := &web3Filter{kind: filterLogs, client: web3Client(ctx)}
+	if err := parseWeb3Params(params, 1, &f.obj); err != nil {
+		return nil, err
+	}
+	if f.obj.BlockHash != "" {
+		return nil, errors.Wrap(errInvalidParams, "blockHash is not supported by filters")
+	}
+	filter, err := logsFilterFromWeb3(&f.obj)
+	if err != nil {
+		return nil, err
+	}
+	f.filter = logfilter.NewLogFilter(filter, nil, nil)
+	return svr.installFilter(f)
+}
+
+func (svr *Web3Server) newBlockFilter(ctx context.Context) (interface{}, error) {
+	return svr.installFilter(&web3Filter{kind: filterBlocks, client: web3Client(ctx)})
+}
+
+func (svr *Web3Server) newPendingTransactionFilter(ctx context.Context) (interface{}, error) {
+	return svr.installFilter(&web3Filter{kind: filterPendingTransactions, client: web3Client(ctx)})
+}
+
+func (svr *Web3Server) installFilter(f *web3Filter) (interface{}, error) {
+	id, err := svr.filters.install(f)
+	if err != nil {
+		return nil, err
+	}
+	if f.kind == filterPendingTransactions {
+		svr.api.ap.AddSubscriber(f)
+	} else if err := svr.api.chainListener.AddResponder(f); err != nil {
+		svr.filters.uninstall(id)
+		return nil, err
+	}
+	return id, nil
+}
+
+func (svr *Web3Server) getFilterChanges(params []json.RawMessage) (interface{}, error) {
+	var id string
+	if err := parseWeb3Params(params, 1, &id); err != nil {
+		return nil, err
+	}
+	f, err := svr.filters.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return f.poll(), nil
+}
+
+// getFilterLogs returns all logs matching the filter in its block range
+func (svr *Web3Server) getFilterLogs(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var id string
+	if err := parseWeb3Params(params, 1, &id); err != nil {
+		return nil, err
+	}
+	f, err := svr.filters.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if f.kind != filterLogs {
+		return nil, errors.Wrapf(errInvalidParams, "filter %s is not a log filter", id)
+	}
+	f.touch()
+	obj, err := json.Marshal(f.obj)
+	if err != nil {
+		return nil, err
+	}
+	return svr.getLogs(ctx, []json.RawMessage{obj})
+}
+
+func (svr *Web3Server) uninstallFilter(params []json.RawMessage) (interface{}, error) {
+	var id string
+	if err := parseWeb3Params(params, 1, &id); err != nil {
+		return nil, err
+	}
+	return svr.filters.uninstall(id), nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestWeb3Filter(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	svr.chainListener = NewChainListener()
+	web3 := NewWeb3Server(svr, 0)
+
+	resultOf := func(res *web3Response, out interface{}) {
+		require.Nil(res.Error)
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), out))
+	}
+
+	t.Run("block filter", func(t *testing.T) {
+		var id string
+		resultOf(web3Call(t, web3, "eth_newBlockFilter"), &id)
+		var changes []string
+		resultOf(web3Call(t, web3, "eth_getFilterChanges", id), &changes)
+		require.Empty(changes)
+
+		for _, h := range []uint64{3, 4} {
+			blk, err := svr.dao.GetBlockByHeight(h)
+			require.NoError(err)
+			require.NoError(svr.chainListener.ReceiveBlock(blk))
+		}
+		resultOf(web3Call(t, web3, "eth_getFilterChanges", id), &changes)
+		require.Equal([]string{"0x" + blkHash[3], "0x" + blkHash[4]}, changes)
+		resultOf(web3Call(t, web3, "eth_getFilterChanges", id), &changes)
+		require.Empty(changes)
+
+		var ok bool
+		resultOf(web3Call(t, web3, "eth_uninstallFilter", id), &ok)
+		require.True(ok)
+		resultOf(web3Call(t, web3, "eth_uninstallFilter", id), &ok)
+		require.False(ok)
+		require.Equal(-32000, web3Call(t, web3, "eth_getFilterChanges", id).Error.Code)
+	})
+
+	t.Run("log filter", func(t *testing.T) {
+		var id string
+		resultOf(web3Call(t, web3, "eth_newFilter", map[string]interface{}{"fromBlock": "0x1"}), &id)
+		var logs []web3Log
+		resultOf(web3Call(t, web3, "eth_getFilterLogs", id), &logs)
+		expected := web3Call(t, web3, "eth_getLogs", map[string]interface{}{"fromBlock": "0x1"})
+		var expectedLogs []web3Log
+		resultOf(expected, &expectedLogs)
+		require.Equal(expectedLogs, logs)
+
+		require.Equal(-32602, web3Call(t, web3, "eth_newFilter", map[string]interface{}{"blockHash": "0x" + blkHash[1]}).Error.Code)
+		var blockID string
+		resultOf(web3Call(t, web3, "eth_newBlockFilter"), &blockID)
+		require.Equal(-32602, web3Call(t, web3, "eth_getFilterLogs", blockID).Error.Code)
+	})
+
+	t.Run("pending filter", func(t *testing.T) {
+		var id string
+		resultOf(web3Call(t, web3, "eth_newPendingTransactionFilter"), &id)
+		tsf, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), 2, big.NewInt(20), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+		require.NoError(err)
+		require.NoError(svr.ap.Add(protocol.WithRegistry(context.Background(), svr.registry), tsf))
+		var changes []string
+		resultOf(web3Call(t, web3, "eth_getFilterChanges", id), &changes)
+		require.Equal([]string{hashToHex(tsf.Hash())}, changes)
+	})
+
+	t.Run("expiration and quota", func(t *testing.T) {
+		web3.filters.expire(time.Now().Add(web3FilterTimeout + time.Second))
+		require.Empty(web3.filters.filters)
+		require.Empty(web3.filters.clients)
+
+		web3.filters.quota = 2
+		for i := 0; i < 2; i++ {
+			require.Nil(web3Call(t, web3, "eth_newBlockFilter").Error)
+		}
+		require.Equal(-32000, web3Call(t, web3, "eth_newBlockFilter").Error.Code)
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+)
+
+type (
+	// web3AccountProof is the result of eth_getProof. The nodes are of the iotex state trie rather than the ethereum
+	// one: accountProof is the path to the account namespace in the state trie of stateRoot, followed by the path to
+	// the account in the namespace trie.
+	web3AccountProof struct {
+		Address      string             `json:"address"`
+		AccountProof []hexutil.Bytes    `json:"accountProof"`
+		Balance      *hexutil.Big       `json:"balance"`
+		CodeHash     common.Hash        `json:"codeHash"`
+		Nonce        hexutil.Uint64     `json:"nonce"`
+		StorageHash  common.Hash        `json:"storageHash"`
+		StorageProof []web3StorageProof `json:"storageProof"`
+		StateRoot    hexutil.Bytes      `json:"stateRoot"`
+	}
+
+	web3StorageProof struct {
+		Key   common.Hash     `json:"key"`
+		Value *hexutil.Big    `json:"value"`
+		Proof []hexutil.Bytes `json:"proof"`
+	}
+)
+
+func (svr *Web3Server) getProof(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		addr, blkNum string
+		keys         []string
+	)
+	if err := parseWeb3Params(params, 2, &addr, &keys, &blkNum); err != nil {
+		return nil, err
+	}
+	ioAddr, err := ethAddrToIoAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	storageKeys := make([]hash.Hash256, 0, len(keys))
+	for _, key := range keys {
+		// storage keys are accepted as quantities too, such as 0x1
+		hexKey := strings.TrimPrefix(key, "0x")
+		if len(hexKey)%2 == 1 {
+			hexKey = "0" + hexKey
+		}
+		b, err := hex.DecodeString(hexKey)
+		if err != nil || len(b) > len(hash.ZeroHash256) {
+			return nil, errors.Wrapf(errInvalidParams, "invalid storage key %s", key)
+		}
+		storageKeys = append(storageKeys, hash.BytesToHash256(common.LeftPadBytes(b, len(hash.ZeroHash256))))
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := svr.api.accountProof(ctx, ioAddr.String(), storageKeys, height)
+	if err != nil {
+		return nil, err
+	}
+	ret := &web3AccountProof{
+		Address:      addr,
+		AccountProof: append(toHexutilBytes(proof.Proof.NamespaceProof), toHexutilBytes(proof.Proof.KeyProof)...),
+		Balance:      (*hexutil.Big)(proof.Account.Balance),
+		CodeHash:     common.BytesToHash(proof.Account.CodeHash),
+		Nonce:        hexutil.Uint64(proof.Account.Nonce),
+		StorageHash:  common.Hash(proof.Account.Root),
+		StorageProof: make([]web3StorageProof, 0, len(proof.StorageProofs)),
+		StateRoot:    proof.Proof.StateRoot,
+	}
+	for _, sp := range proof.StorageProofs {
+		ret.StorageProof = append(ret.StorageProof, web3StorageProof{
+			Key:   common.Hash(sp.Key),
+			Value: (*hexutil.Big)(new(big.Int).SetBytes(sp.Value)),
+			Proof: toHexutilBytes(sp.Proof),
+		})
+	}
+	return ret, nil
+}
+
+func toHexutilBytes(nodes [][]byte) []hexutil.Bytes {
+	ret := make([]hexutil.Bytes, 0, len(nodes))
+	for _, node := range nodes {
+		ret = append(ret, node)
+	}
+	return ret
+}
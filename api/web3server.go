@@ -0,0 +1,1274 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/gasstation"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/version"
+)
+
+const (
+	// web3MaxRequestSize is the maximal size of a web3 request body
+	web3MaxRequestSize = 1 << 20
+	// web3Version is the JSON-RPC version spoken by the web3 server
+	web3Version = "2.0"
+)
+
+// web3 errors
+var (
+	errParse          = errors.New("parse error")
+	errInvalidRequest = errors.New("invalid request")
+	errMethodNotFound = errors.New("method not found")
+	errInvalidParams  = errors.New("invalid params")
+	errUnsupported    = errors.New("unsupported by this node")
+)
+
+type (
+	// Web3Server serves ethereum compatible JSON-RPC requests on top of the api server
+	Web3Server struct {
+		api        *Server
+		httpServer *httpServer
+		filters    *web3FilterManager
+		cancel     context.CancelFunc
+	}
+
+	web3Request struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+
+	web3Response struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  interface{}     `json:"result"`
+		Error   *web3Error      `json:"error,omitempty"`
+	}
+
+	web3Error struct {
+		Code    int         `json:"code"`
+		Message string      `json:"message"`
+		Data    interface{} `json:"data,omitempty"`
+	}
+)
+
+// MarshalJSON writes the result of a successful response even if it is null, and omits it from an error response as
+// JSON-RPC 2.0 requires
+func (r *web3Response) MarshalJSON() ([]byte, error) {
+	if r.Error != nil {
+		return json.Marshal(&struct {
+			JSONRPC string          `json:"jsonrpc"`
+			ID      json.RawMessage `json:"id"`
+			Error   *web3Error      `json:"error"`
+		}{r.JSONRPC, r.ID, r.Error})
+	}
+	return json.Marshal(&struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  interface{}     `json:"result"`
+	}{r.JSONRPC, r.ID, r.Result})
+}
+
+// NewWeb3Server creates a web3 server serving on the given port
+func NewWeb3Server(api *Server, port int) *Web3Server {
+	svr := &Web3Server{api: api}
+	svr.filters = newWeb3FilterManager(svr)
+	mux := http.NewServeMux()
+	mux.Handle("/", svr)
+	svr.httpServer = newHTTPServer("web3", api.cfg.API.HTTP, port, mux)
+	return svr
+}
+
+// Start starts the web3 server
+func (svr *Web3Server) Start(_ context.Context) error {
+	if err := svr.httpServer.start(); err != nil {
+		return err
+	}
+	var ctx context.Context
+	ctx, svr.cancel = context.WithCancel(context.Background())
+	go svr.filters.run(ctx)
+	return nil
+}
+
+// Stop stops the web3 server
+func (svr *Web3Server) Stop(ctx context.Context) error {
+	if svr.cancel != nil {
+		svr.cancel()
+	}
+	return svr.httpServer.stop(ctx)
+}
+
+// ServeHTTP handles a JSON-RPC request over HTTP, or over websocket if the client asks to upgrade
+func (svr *Web3Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if websocket.IsWebSocketUpgrade(req) {
+		svr.serveWebsocket(w, req)
+		return
+	}
+	if handler, ok := svr.api.healthRoutes()[req.URL.Path]; ok && req.Method == http.MethodGet {
+		handler(w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, web3MaxRequestSize))
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	resp := svr.handleWeb3Message(withWeb3Client(withHTTPRequestOrigin(req.Context(), req), req.RemoteAddr, false), body)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.L().Warn("failed to write web3 response.", zap.Error(err))
+	}
+}
+
+// handleWeb3Message handles a request, or a batch of requests which are answered in an array
+func (svr *Web3Server) handleWeb3Message(ctx context.Context, msg []byte) interface{} {
+	msg = bytes.TrimLeft(msg, " \t\r\n")
+	if len(msg) == 0 || msg[0] != '[' {
+		var req web3Request
+		if err := json.Unmarshal(msg, &req); err != nil {
+			return newWeb3ErrorResponse(nil, errors.Wrap(errParse, err.Error()))
+		}
+		return svr.handleWeb3Req(ctx, &req)
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil {
+		return newWeb3ErrorResponse(nil, errors.Wrap(errParse, err.Error()))
+	}
+	if len(batch) == 0 {
+		return newWeb3ErrorResponse(nil, errors.Wrap(errInvalidRequest, "empty batch"))
+	}
+	if limit := svr.api.cfg.API.BatchRequestLimit; limit > 0 && len(batch) > limit {
+		return newWeb3ErrorResponse(nil, errors.Wrapf(errInvalidRequest, "batch of %d requests exceeds the limit %d", len(batch), limit))
+	}
+	resps := make([]*web3Response, len(batch))
+	for i := range batch {
+		var req web3Request
+		if err := json.Unmarshal(batch[i], &req); err != nil {
+			resps[i] = newWeb3ErrorResponse(nil, errors.Wrap(errInvalidRequest, err.Error()))
+			continue
+		}
+		resps[i] = svr.handleWeb3Req(ctx, &req)
+	}
+	return resps
+}
+
+func (svr *Web3Server) handleWeb3Req(ctx context.Context, req *web3Request) *web3Response {
+	if req.JSONRPC != web3Version || req.Method == "" {
+		return newWeb3ErrorResponse(req.ID, errInvalidRequest)
+	}
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newWeb3ErrorResponse(req.ID, errors.Wrap(errInvalidParams, err.Error()))
+		}
+	}
+	if err := svr.api.limiter.allow(ctx, req.Method); err != nil {
+		return newWeb3ErrorResponse(req.ID, err)
+	}
+	if err := svr.api.auth.authenticate(ctx, req.Method); err != nil {
+		return newWeb3ErrorResponse(req.ID, err)
+	}
+	start := time.Now()
+	res, err := svr.dispatch(ctx, req.Method, params)
+	var result json.RawMessage
+	if err == nil && res != nil {
+		// the result is serialized here to measure its size, and written as is
+		result, err = json.Marshal(res)
+	}
+	method := req.Method
+	if errors.Cause(err) == errMethodNotFound {
+		// the methods unknown are not labelled by name, which is chosen by the client
+		method = "unknown"
+	}
+	svr.api.observer.observe(web3Protocol, method, start, len(req.Params), len(result), err, func() []byte {
+		return req.Params
+	})
+	if err != nil {
+		log.L().Debug("web3 request failed.", zap.String("method", req.Method), zap.Error(err))
+		return newWeb3ErrorResponse(req.ID, err)
+	}
+	if result == nil {
+		return &web3Response{JSONRPC: web3Version, ID: req.ID}
+	}
+	return &web3Response{JSONRPC: web3Version, ID: req.ID, Result: result}
+}
+
+func (svr *Web3Server) dispatch(ctx context.Context, method string, params []json.RawMessage) (interface{}, error) {
+	switch method {
+	case "web3_clientVersion":
+		return "iotex-core/" + version.PackageVersion, nil
+	case "net_version":
+		return strconv.FormatUint(uint64(svr.api.cfg.Chain.EVMNetworkID), 10), nil
+	case "net_listening":
+		return true, nil
+	case "eth_chainId":
+		return hexutil.Uint64(svr.api.cfg.Chain.EVMNetworkID), nil
+	case "eth_syncing":
+		return svr.syncing(ctx)
+	case "net_peerCount":
+		return svr.peerCount(ctx)
+	case "eth_mining", "eth_hashrate":
+		return svr.getZeroValue(method), nil
+	case "eth_accounts":
+		return []string{}, nil
+	case "eth_blockNumber":
+		return hexutil.Uint64(svr.api.bc.TipHeight()), nil
+	case "eth_gasPrice", "eth_maxPriorityFeePerGas":
+		return svr.gasPrice()
+	case "iotex_minGasPrice":
+		return (*hexutil.Big)(svr.api.gs.MinGasPrice()), nil
+	case "eth_feeHistory":
+		return svr.feeHistory(params)
+	case "eth_getBalance":
+		return svr.getBalance(params)
+	case "eth_getTransactionCount":
+		return svr.getTransactionCount(params)
+	case "eth_getCode":
+		return svr.getCode(params)
+	case "eth_getProof":
+		return svr.getProof(ctx, params)
+	case "iotex_getStateDiff":
+		return svr.getStateDiff(params)
+	case "iotex_dumpAccounts":
+		return svr.dumpAccounts(params)
+	case "iotex_getEquivocationEvidences":
+		return svr.getEquivocationEvidences(params)
+	case "iotex_getConsensusRounds":
+		return svr.getConsensusRounds(params)
+	case "eth_call":
+		return svr.call(params)
+	case "eth_estimateGas":
+		return svr.estimateGas(params)
+	case "iotex_callBatch":
+		return svr.callBatch(ctx, params)
+	case "eth_sendRawTransaction":
+		return svr.sendRawTransaction(ctx, params)
+	case "eth_sendPrivateTransaction":
+		return svr.sendPrivateTransaction(ctx, params)
+	case "eth_getBlockByNumber":
+		return svr.getBlockByNumber(params)
+	case "eth_getBlockByHash":
+		return svr.getBlockByHash(params)
+	case "eth_getBlockTransactionCountByNumber":
+		return svr.getBlockTransactionCountByNumber(params)
+	case "eth_getBlockTransactionCountByHash":
+		return svr.getBlockTransactionCountByHash(params)
+	case "eth_getTransactionByHash":
+		return svr.getTransactionByHash(params)
+	case "eth_getTransactionByBlockNumberAndIndex":
+		return svr.getTransactionByBlockNumberAndIndex(params)
+	case "eth_getTransactionByBlockHashAndIndex":
+		return svr.getTransactionByBlockHashAndIndex(params)
+	case "eth_getTransactionReceipt":
+		return svr.getTransactionReceipt(params)
+	case "eth_getBlockReceipts":
+		return svr.getBlockReceipts(params)
+	case "eth_getLogs":
+		return svr.getLogs(ctx, params)
+	case "iotex_getLogsCost":
+		return svr.getLogsCost(ctx, params)
+	case "debug_traceTransaction":
+		return svr.traceTransaction(params)
+	case "debug_traceCall":
+		return svr.traceCall(params)
+	case "debug_traceBlockByNumber":
+		return svr.traceBlockByNumber(params)
+	case "debug_storageRangeAt":
+		return svr.storageRangeAt(ctx, params)
+	case "eth_subscribe":
+		return svr.subscribe(ctx, params)
+	case "eth_unsubscribe":
+		return svr.unsubscribe(ctx, params)
+	case "eth_newFilter":
+		return svr.newFilter(ctx, params)
+	case "eth_newBlockFilter":
+		return svr.newBlockFilter(ctx)
+	case "eth_newPendingTransactionFilter":
+		return svr.newPendingTransactionFilter(ctx)
+	case "eth_getFilterChanges":
+		return svr.getFilterChanges(params)
+	case "eth_getFilterLogs":
+		return svr.getFilterLogs(ctx, params)
+	case "eth_uninstallFilter":
+		return svr.uninstallFilter(params)
+	case "txpool_content":
+		return svr.txPoolContent(ctx)
+	case "txpool_contentFrom":
+		return svr.txPoolContentFrom(ctx, params)
+	case "txpool_inspect":
+		return svr.txPoolInspect(ctx)
+	case "txpool_status":
+		return svr.txPoolStatus(ctx)
+	case "txpool_dropReason":
+		return svr.txPoolDropReason(ctx, params)
+	default:
+		return nil, errors.Wrap(errMethodNotFound, method)
+	}
+}
+
+func (svr *Web3Server) getZeroValue(method string) interface{} {
+	if method == "eth_mining" {
+		return false
+	}
+	return hexutil.Uint64(0)
+}
+
+func (svr *Web3Server) syncing(ctx context.Context) (interface{}, error) {
+	ss, err := svr.api.GetSyncStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ss.TargetHeight <= ss.TipHeight {
+		return false, nil
+	}
+	return map[string]hexutil.Uint64{
+		"startingBlock": hexutil.Uint64(ss.StartingHeight),
+		"currentBlock":  hexutil.Uint64(ss.TipHeight),
+		"highestBlock":  hexutil.Uint64(ss.TargetHeight),
+	}, nil
+}
+
+func (svr *Web3Server) peerCount(ctx context.Context) (interface{}, error) {
+	ss, err := svr.api.GetSyncStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.Uint64(ss.PeerCount), nil
+}
+
+func (svr *Web3Server) gasPrice() (interface{}, error) {
+	price, err := svr.api.gs.SuggestGasPrice()
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(new(big.Int).SetUint64(price)), nil
+}
+
+func (svr *Web3Server) feeHistory(params []json.RawMessage) (interface{}, error) {
+	var (
+		blockCount        web3Quantity
+		blkNum            string
+		rewardPercentiles []float64
+	)
+	if err := parseWeb3Params(params, 2, &blockCount, &blkNum, &rewardPercentiles); err != nil {
+		return nil, err
+	}
+	lastBlock, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	history, err := svr.api.gs.FeeHistory(uint64(blockCount), lastBlock, rewardPercentiles)
+	if errors.Cause(err) == gasstation.ErrInvalidFeeHistory {
+		return nil, errors.Wrap(errInvalidParams, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newWeb3FeeHistory(history), nil
+}
+
+func (svr *Web3Server) getBalance(params []json.RawMessage) (interface{}, error) {
+	var addr, blkNum string
+	if err := parseWeb3Params(params, 1, &addr, &blkNum); err != nil {
+		return nil, err
+	}
+	ioAddr, err := ethAddrToIoAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	sr, err := svr.stateReader(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	state, err := accountutil.AccountState(sr, ioAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.Balance), nil
+}
+
+func (svr *Web3Server) getTransactionCount(params []json.RawMessage) (interface{}, error) {
+	var addr, blkNum string
+	if err := parseWeb3Params(params, 1, &addr, &blkNum); err != nil {
+		return nil, err
+	}
+	ioAddr, err := ethAddrToIoAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	sr, err := svr.stateReader(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	state, err := accountutil.AccountState(sr, ioAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	// the next nonce to use, which is what ethereum tooling expects
+	return hexutil.Uint64(state.Nonce + 1), nil
+}
+
+func (svr *Web3Server) getCode(params []json.RawMessage) (interface{}, error) {
+	var addr, blkNum string
+	if err := parseWeb3Params(params, 1, &addr, &blkNum); err != nil {
+		return nil, err
+	}
+	ioAddr, err := ethAddrToIoAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	sr, err := svr.stateReader(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	state, err := accountutil.AccountState(sr, ioAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	if !state.IsContract() {
+		return hexutil.Bytes{}, nil
+	}
+	var code evm.SerializableBytes
+	if _, err := sr.State(&code, protocol.NamespaceOption(evm.CodeKVNameSpace), protocol.KeyOption(state.CodeHash)); err != nil {
+		return nil, err
+	}
+	return hexutil.Bytes(code), nil
+}
+
+func (svr *Web3Server) call(params []json.RawMessage) (interface{}, error) {
+	var (
+		callObj     web3CallObject
+		blkNum      string
+		overrideSet map[string]*web3AccountOverride
+	)
+	if err := parseWeb3Params(params, 1, &callObj, &blkNum, &overrideSet); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if len(overrideSet) > 0 {
+		overrides, err := toStateOverrides(overrideSet)
+		if err != nil {
+			return nil, err
+		}
+		ctx = evm.WithStateOverrides(ctx, overrides)
+	}
+	caller, err := svr.callerAddress(callObj.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := callObj.contract()
+	if err != nil {
+		return nil, err
+	}
+	height, historical, err := svr.historicalHeight(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		retval  []byte
+		receipt *action.Receipt
+	)
+	switch {
+	case blkNum == "pending":
+		retval, receipt, err = svr.api.simulatePendingExecution(ctx, caller, to, callObj.value(), uint64(callObj.Gas), callObj.data())
+	case historical:
+		retval, receipt, err = svr.api.simulateExecutionAtHeight(ctx, height, caller, to, callObj.value(), uint64(callObj.Gas), callObj.data())
+	default:
+		retval, receipt, err = svr.api.simulateExecution(ctx, caller, to, callObj.value(), uint64(callObj.Gas), callObj.data())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != uint64(iotextypes.ReceiptStatus_Success) {
+		return nil, newExecutionRevertedError(receipt.Status, retval)
+	}
+	return hexutil.Bytes(retval), nil
+}
+
+func (svr *Web3Server) callBatch(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var (
+		calls  []*web3CallObject
+		blkNum string
+	)
+	if err := parseWeb3Params(params, 1, &calls, &blkNum); err != nil {
+		return nil, err
+	}
+	if _, historical, err := svr.historicalHeight(blkNum); err != nil {
+		return nil, err
+	} else if historical {
+		return nil, errors.Wrap(errInvalidParams, "a batch of calls is executed against the latest block only")
+	}
+	reqs := make([]*iotexapi.ReadContractRequest, 0, len(calls))
+	for _, callObj := range calls {
+		caller, err := svr.callerAddress(callObj.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := callObj.contract()
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, &iotexapi.ReadContractRequest{
+			Execution: &iotextypes.Execution{
+				Amount:   callObj.value().String(),
+				Contract: to,
+				Data:     callObj.data(),
+			},
+			CallerAddress: caller.String(),
+		})
+	}
+	res, err := svr.api.readContractBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	ret := &web3CallBatchResult{
+		BlockNumber: hexutil.Uint64(res.Height),
+		Results:     make([]*web3CallResult, 0, len(res.Results)),
+	}
+	for _, r := range res.Results {
+		if r.Err != nil {
+			ret.Results = append(ret.Results, &web3CallResult{Error: toWeb3Error(r.Err)})
+			continue
+		}
+		retval, err := hex.DecodeString(r.Response.Data)
+		if err != nil {
+			return nil, err
+		}
+		if receipt := r.Response.Receipt; receipt.Status != uint64(iotextypes.ReceiptStatus_Success) {
+			ret.Results = append(ret.Results, &web3CallResult{Error: toWeb3Error(newExecutionRevertedError(receipt.Status, retval))})
+			continue
+		}
+		result := hexutil.Bytes(retval)
+		ret.Results = append(ret.Results, &web3CallResult{Result: &result})
+	}
+	return ret, nil
+}
+
+func (svr *Web3Server) estimateGas(params []json.RawMessage) (interface{}, error) {
+	var callObj web3CallObject
+	if err := parseWeb3Params(params, 1, &callObj); err != nil {
+		return nil, err
+	}
+	caller, err := svr.callerAddress(callObj.From)
+	if err != nil {
+		return nil, err
+	}
+	isContract := callObj.To == ""
+	to := action.EmptyAddress
+	if callObj.To != "" {
+		ioAddr, err := ethAddrToIoAddr(callObj.To)
+		if err != nil {
+			return nil, err
+		}
+		to = ioAddr.String()
+		state, err := accountutil.AccountState(svr.api.sf, to)
+		if err != nil {
+			return nil, err
+		}
+		isContract = state.IsContract()
+	}
+	if !isContract {
+		return hexutil.Uint64(uint64(len(callObj.data()))*action.TransferPayloadGas + action.TransferBaseIntrinsicGas), nil
+	}
+	res, err := svr.api.estimateActionGasConsumptionForExecution(&iotextypes.Execution{
+		Amount:   callObj.value().String(),
+		Contract: to,
+		Data:     callObj.data(),
+	}, caller.String())
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.Uint64(res.Gas), nil
+}
+
+// sendRawTransaction accepts a hex-encoded signed transaction, either a RLP-encoded ethereum transaction of a transfer
+// or execution, or a protobuf-serialized IoTeX action
+func (svr *Web3Server) sendRawTransaction(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var raw hexutil.Bytes
+	if err := parseWeb3Params(params, 1, &raw); err != nil {
+		return nil, err
+	}
+	actPb := &iotextypes.Action{}
+	if len(raw) > 0 && raw[0] >= 0xc0 {
+		// a RLP list, which never starts a serialized action
+		selp, err := action.DecodeEthereumTx(raw)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidParams, err.Error())
+		}
+		if id := svr.api.cfg.Chain.EVMNetworkID; selp.EVMNetworkID() != id {
+			return nil, errors.Wrapf(errInvalidParams, "chain id %d of transaction, expecting %d", selp.EVMNetworkID(), id)
+		}
+		actPb = selp.Proto()
+	} else if err := proto.Unmarshal(raw, actPb); err != nil || actPb.GetCore() == nil {
+		return nil, errors.Wrap(errInvalidParams, "neither an ethereum transaction nor an IoTeX action")
+	}
+	res, err := svr.api.SendAction(ctx, &iotexapi.SendActionRequest{Action: actPb})
+	if err != nil {
+		return nil, err
+	}
+	return "0x" + res.ActionHash, nil
+}
+
+func (svr *Web3Server) getBlockByNumber(params []json.RawMessage) (interface{}, error) {
+	var (
+		blkNum string
+		fullTx bool
+	)
+	if err := parseWeb3Params(params, 1, &blkNum, &fullTx); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nullIfNotExist(err)
+	}
+	return svr.newWeb3Block(blk, fullTx)
+}
+
+func (svr *Web3Server) getBlockByHash(params []json.RawMessage) (interface{}, error) {
+	var (
+		blkHash string
+		fullTx  bool
+	)
+	if err := parseWeb3Params(params, 1, &blkHash, &fullTx); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlock(h)
+	if err != nil {
+		return nullIfNotExist(err)
+	}
+	return svr.newWeb3Block(blk, fullTx)
+}
+
+func (svr *Web3Server) getBlockTransactionCountByNumber(params []json.RawMessage) (interface{}, error) {
+	var blkNum string
+	if err := parseWeb3Params(params, 1, &blkNum); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nullIfNotExist(err)
+	}
+	return hexutil.Uint64(len(blk.Actions)), nil
+}
+
+func (svr *Web3Server) getBlockTransactionCountByHash(params []json.RawMessage) (interface{}, error) {
+	var blkHash string
+	if err := parseWeb3Params(params, 1, &blkHash); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlock(h)
+	if err != nil {
+		return nullIfNotExist(err)
+	}
+	return hexutil.Uint64(len(blk.Actions)), nil
+}
+
+func (svr *Web3Server) getTransactionByHash(params []json.RawMessage) (interface{}, error) {
+	var actHash string
+	if err := parseWeb3Params(params, 1, &actHash); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(actHash)
+	if err != nil {
+		return nil, err
+	}
+	if svr.api.hasActionIndex && svr.api.indexer != nil {
+		if selp, _, height, err := svr.api.getActionByActionHash(h); err == nil {
+			blk, err := svr.api.dao.GetBlockByHeight(height)
+			if err != nil {
+				return nil, err
+			}
+			index, _ := actionIndexInBlock(blk, h)
+			return newWeb3Transaction(selp, blk, index), nil
+		}
+	}
+	selp, err := svr.api.ap.GetActionByHash(h)
+	if err != nil {
+		if errors.Cause(err) == action.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return newWeb3Transaction(selp, nil, 0), nil
+}
+
+func (svr *Web3Server) getTransactionByBlockNumberAndIndex(params []json.RawMessage) (interface{}, error) {
+	var blkNum string
+	var index hexutil.Uint64
+	if err := parseWeb3Params(params, 2, &blkNum, &index); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nullIfNotExist(err)
+	}
+	if uint64(index) >= uint64(len(blk.Actions)) {
+		return nil, nil
+	}
+	return newWeb3Transaction(blk.Actions[index], blk, uint64(index)), nil
+}
+
+func (svr *Web3Server) getTransactionByBlockHashAndIndex(params []json.RawMessage) (interface{}, error) {
+	var blkHash string
+	var index hexutil.Uint64
+	if err := parseWeb3Params(params, 2, &blkHash, &index); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlock(h)
+	if err != nil {
+		return nullIfNotExist(err)
+	}
+	if uint64(index) >= uint64(len(blk.Actions)) {
+		return nil, nil
+	}
+	return newWeb3Transaction(blk.Actions[index], blk, uint64(index)), nil
+}
+
+func (svr *Web3Server) getTransactionReceipt(params []json.RawMessage) (interface{}, error) {
+	var actHash string
+	if err := parseWeb3Params(params, 1, &actHash); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(actHash)
+	if err != nil {
+		return nil, err
+	}
+	if !svr.api.hasActionIndex || svr.api.indexer == nil {
+		return nil, errors.Wrap(errUnsupported, "action index is not available")
+	}
+	selp, _, height, err := svr.api.getActionByActionHash(h)
+	if err != nil {
+		// unknown or still pending
+		return nullIfNotExist(err)
+	}
+	blk, err := svr.api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := svr.api.dao.GetReceipts(height)
+	if err != nil {
+		return nil, err
+	}
+	index, _ := actionIndexInBlock(blk, h)
+	return newWeb3Receipt(selp, blk, receipts, index), nil
+}
+
+// getBlockReceipts returns the receipts of all the transactions of a block, given its number or hash
+func (svr *Web3Server) getBlockReceipts(params []json.RawMessage) (interface{}, error) {
+	var blkNumOrHash string
+	if err := parseWeb3Params(params, 1, &blkNumOrHash); err != nil {
+		return nil, err
+	}
+	blk, err := svr.blockByNumberOrHash(blkNumOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, nil
+	}
+	receipts, err := svr.api.dao.GetReceipts(blk.Height())
+	if err != nil {
+		return nil, err
+	}
+	return newWeb3BlockReceipts(blk, receipts), nil
+}
+
+// blockByNumberOrHash returns the block of a block tag, hex-encoded height or hash, or nil if it is unknown
+func (svr *Web3Server) blockByNumberOrHash(blkNumOrHash string) (*block.Block, error) {
+	if len(strings.TrimPrefix(blkNumOrHash, "0x")) == 2*len(hash.ZeroHash256) {
+		h, err := hexToHash(blkNumOrHash)
+		if err != nil {
+			return nil, err
+		}
+		blk, err := svr.api.dao.GetBlock(h)
+		if errors.Cause(err) == db.ErrNotExist {
+			return nil, nil
+		}
+		return blk, err
+	}
+	height, err := svr.parseBlockNumber(blkNumOrHash)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := svr.api.dao.GetBlockByHeight(height)
+	if errors.Cause(err) == db.ErrNotExist {
+		return nil, nil
+	}
+	return blk, err
+}
+
+func (svr *Web3Server) getLogs(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	req, err := svr.logsRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := svr.api.GetLogs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return svr.newWeb3Logs(res.Logs)
+}
+
+func (svr *Web3Server) getLogsCost(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	req, err := svr.logsRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	cost, err := svr.api.EstimateLogsQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	ret := &web3LogsCost{
+		FromBlock:        hexutil.Uint64(cost.FromBlock),
+		ToBlock:          hexutil.Uint64(cost.ToBlock),
+		CandidateBlocks:  hexutil.Uint64(cost.CandidateBlocks),
+		MaxBlocksScanned: hexutil.Uint64(cost.MaxBlocksScanned),
+		MaxLogs:          hexutil.Uint64(cost.MaxLogs),
+		TooBroad:         cost.TooBroad,
+	}
+	if cost.TooBroad {
+		suggested := hexutil.Uint64(cost.SuggestedToBlock)
+		ret.SuggestedToBlock = &suggested
+	}
+	return ret, nil
+}
+
+// logsRequest parses the filter object of eth_getLogs into a log query
+func (svr *Web3Server) logsRequest(params []json.RawMessage) (*iotexapi.GetLogsRequest, error) {
+	var filterObj web3FilterObject
+	if err := parseWeb3Params(params, 1, &filterObj); err != nil {
+		return nil, err
+	}
+	filter, err := logsFilterFromWeb3(&filterObj)
+	if err != nil {
+		return nil, err
+	}
+	req := &iotexapi.GetLogsRequest{Filter: filter}
+	if filterObj.BlockHash != "" {
+		h, err := hexToHash(filterObj.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		req.Lookup = &iotexapi.GetLogsRequest_ByBlock{
+			ByBlock: &iotexapi.GetLogsByBlock{BlockHash: h[:]},
+		}
+	} else {
+		from, err := svr.parseBlockNumber(filterObj.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		to, err := svr.parseBlockNumber(filterObj.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		req.Lookup = &iotexapi.GetLogsRequest_ByRange{
+			ByRange: &iotexapi.GetLogsByRange{FromBlock: from, ToBlock: to},
+		}
+	}
+	return req, nil
+}
+
+func (svr *Web3Server) traceTransaction(params []json.RawMessage) (interface{}, error) {
+	var (
+		txHash   string
+		traceCfg web3TraceConfig
+	)
+	if err := parseWeb3Params(params, 1, &txHash, &traceCfg); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return svr.api.traceAction(hex.EncodeToString(h[:]), traceCfg.toTraceConfig())
+}
+
+func (svr *Web3Server) traceCall(params []json.RawMessage) (interface{}, error) {
+	var (
+		callObj  web3CallObject
+		blkNum   string
+		traceCfg web3TraceConfig
+	)
+	if err := parseWeb3Params(params, 1, &callObj, &blkNum, &traceCfg); err != nil {
+		return nil, err
+	}
+	caller, err := svr.callerAddress(callObj.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := callObj.contract()
+	if err != nil {
+		return nil, err
+	}
+	return svr.api.TraceCall(caller, to, callObj.value(), uint64(callObj.Gas), callObj.data(), traceCfg.toTraceConfig())
+}
+
+func (svr *Web3Server) traceBlockByNumber(params []json.RawMessage) (interface{}, error) {
+	var (
+		blkNum   string
+		traceCfg web3TraceConfig
+	)
+	if err := parseWeb3Params(params, 1, &blkNum, &traceCfg); err != nil {
+		return nil, err
+	}
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := svr.api.traceBlock(height, traceCfg.toTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	for _, trace := range traces {
+		trace.ActHash = "0x" + trace.ActHash
+	}
+	return traces, nil
+}
+
+// newWeb3Logs converts logs and fills in the block hash and transaction index of each log
+func (svr *Web3Server) newWeb3Logs(logs []*iotextypes.Log) ([]*web3Log, error) {
+	var (
+		ret     = make([]*web3Log, 0, len(logs))
+		blk     *block.Block
+		blkHash hash.Hash256
+	)
+	for _, l := range logs {
+		if blk == nil || blk.Height() != l.BlkHeight {
+			var err error
+			if blk, err = svr.api.dao.GetBlockByHeight(l.BlkHeight); err != nil {
+				return nil, err
+			}
+			blkHash = blk.HashBlock()
+		}
+		index, _ := actionIndexInBlock(blk, hash.BytesToHash256(l.ActHash))
+		ret = append(ret, newWeb3Log(l, blkHash, index))
+	}
+	return ret, nil
+}
+
+func (svr *Web3Server) newWeb3Block(blk *block.Block, fullTx bool) (*web3Block, error) {
+	var gasUsed uint64
+	receipts, err := svr.api.dao.GetReceipts(blk.Height())
+	if err == nil {
+		for _, r := range receipts {
+			gasUsed += r.GasConsumed
+		}
+	}
+	var (
+		h            = blk.HashBlock()
+		prevHash     = blk.PrevHash()
+		txRoot       = blk.TxRoot()
+		stateRoot    = blk.DeltaStateDigest()
+		receiptRoot  = blk.ReceiptRoot()
+		logsBloom    = emptyLogsBloom
+		transactions = make([]interface{}, 0, len(blk.Actions))
+	)
+	if bf := blk.LogsBloomfilter(); bf != nil {
+		logsBloom = "0x" + hex.EncodeToString(bf.Bytes())
+	}
+	for i, selp := range blk.Actions {
+		if fullTx {
+			transactions = append(transactions, newWeb3Transaction(selp, blk, uint64(i)))
+		} else {
+			actHash := selp.Hash()
+			transactions = append(transactions, hashToHex(actHash))
+		}
+	}
+	var size int
+	if blkPb := blk.ConvertToBlockPb(); blkPb != nil {
+		size = proto.Size(blkPb)
+	}
+	return &web3Block{
+		Number:           hexutil.Uint64(blk.Height()),
+		Hash:             hashToHex(h),
+		ParentHash:       hashToHex(prevHash),
+		Sha3Uncles:       emptyUncleHash,
+		LogsBloom:        logsBloom,
+		TransactionsRoot: hashToHex(txRoot),
+		StateRoot:        hashToHex(stateRoot),
+		ReceiptsRoot:     hashToHex(receiptRoot),
+		Miner:            mustIoAddrToEthAddr(blk.ProducerAddress()),
+		ExtraData:        "0x",
+		Size:             hexutil.Uint64(size),
+		GasLimit:         hexutil.Uint64(svr.api.cfg.Genesis.BlockGasLimitByHeight(blk.Height())),
+		GasUsed:          hexutil.Uint64(gasUsed),
+		Timestamp:        hexutil.Uint64(blk.Timestamp().Unix()),
+		Transactions:     transactions,
+		Uncles:           []string{},
+	}, nil
+}
+
+func newWeb3Receipt(selp action.SealedEnvelope, blk *block.Block, receipts []*action.Receipt, index uint64) *web3Receipt {
+	var (
+		h          = selp.Hash()
+		blkHash    = blk.HashBlock()
+		cumulative uint64
+		receipt    *action.Receipt
+	)
+	for _, r := range receipts {
+		cumulative += r.GasConsumed
+		if r.ActionHash == h {
+			receipt = r
+			break
+		}
+	}
+	if receipt == nil {
+		receipt = &action.Receipt{ActionHash: h, BlockHeight: blk.Height()}
+	}
+	return newWeb3ReceiptOf(selp, blk, blkHash, receipt, index, cumulative)
+}
+
+// newWeb3BlockReceipts converts the receipts of all the actions of the block, in the order of the actions
+func newWeb3BlockReceipts(blk *block.Block, receipts []*action.Receipt) []*web3Receipt {
+	var (
+		blkHash    = blk.HashBlock()
+		cumulative uint64
+		byHash     = make(map[hash.Hash256]*action.Receipt, len(receipts))
+		ret        = make([]*web3Receipt, 0, len(blk.Actions))
+	)
+	for _, r := range receipts {
+		byHash[r.ActionHash] = r
+	}
+	for i, selp := range blk.Actions {
+		h := selp.Hash()
+		receipt, ok := byHash[h]
+		if !ok {
+			receipt = &action.Receipt{ActionHash: h, BlockHeight: blk.Height()}
+		}
+		cumulative += receipt.GasConsumed
+		ret = append(ret, newWeb3ReceiptOf(selp, blk, blkHash, receipt, uint64(i), cumulative))
+	}
+	return ret
+}
+
+func newWeb3ReceiptOf(
+	selp action.SealedEnvelope,
+	blk *block.Block,
+	blkHash hash.Hash256,
+	receipt *action.Receipt,
+	index uint64,
+	cumulative uint64,
+) *web3Receipt {
+	h := receipt.ActionHash
+	sender, _ := address.FromBytes(selp.SrcPubkey().Hash())
+	to, _, _ := actionToAndValue(selp)
+	logs := make([]*web3Log, 0, len(receipt.Logs()))
+	for _, l := range receipt.Logs() {
+		logs = append(logs, newWeb3Log(l.ConvertToLogPb(), blkHash, index))
+	}
+	ret := &web3Receipt{
+		TransactionHash:   hashToHex(h),
+		TransactionIndex:  hexutil.Uint64(index),
+		BlockHash:         hashToHex(blkHash),
+		BlockNumber:       hexutil.Uint64(blk.Height()),
+		From:              mustIoAddrToEthAddr(sender.String()),
+		To:                to,
+		CumulativeGasUsed: hexutil.Uint64(cumulative),
+		GasUsed:           hexutil.Uint64(receipt.GasConsumed),
+		Logs:              logs,
+		LogsBloom:         emptyLogsBloom,
+		Status:            hexutil.Uint64(receipt.Status),
+	}
+	if receipt.ContractAddress != "" {
+		if _, ok := selp.Action().(*action.Execution); ok && to == nil {
+			contract := mustIoAddrToEthAddr(receipt.ContractAddress)
+			ret.ContractAddress = &contract
+		}
+	}
+	return ret
+}
+
+// parseBlockNumber converts a block tag or hex-encoded height into a height
+func (svr *Web3Server) parseBlockNumber(blkNum string) (uint64, error) {
+	switch blkNum {
+	case "", "latest", "pending":
+		return svr.api.bc.TipHeight(), nil
+	case "earliest":
+		return 1, nil
+	default:
+		height, err := hexutil.DecodeUint64(blkNum)
+		if err != nil {
+			return 0, errors.Wrapf(errInvalidParams, "invalid block number %s", blkNum)
+		}
+		return height, nil
+	}
+}
+
+// historicalHeight returns the height of a block number which is below the tip, the state of which is only available
+// in archive mode
+func (svr *Web3Server) historicalHeight(blkNum string) (uint64, bool, error) {
+	height, err := svr.parseBlockNumber(blkNum)
+	if err != nil {
+		return 0, false, err
+	}
+	if height >= svr.api.bc.TipHeight() {
+		return 0, false, nil
+	}
+	if !svr.api.cfg.Chain.EnableArchiveMode {
+		return 0, false, errors.Wrapf(errUnsupported, "state at block %d requires archive mode", height)
+	}
+	return height, true, nil
+}
+
+// stateReader returns the reader of the state at the block number, the "pending" block tag reads the state after the
+// actions waiting in the actpool
+func (svr *Web3Server) stateReader(blkNum string) (protocol.StateReader, error) {
+	if blkNum == "pending" {
+		bcCtx, err := svr.api.bc.Context()
+		if err != nil {
+			return nil, err
+		}
+		return svr.api.pendingState(bcCtx)
+	}
+	height, historical, err := svr.historicalHeight(blkNum)
+	if err != nil {
+		return nil, err
+	}
+	if !historical {
+		return svr.api.sf, nil
+	}
+	return svr.api.historyStateReader(height)
+}
+
+// callerAddress returns the caller of eth_call and eth_estimateGas, which defaults to the zero address
+func (svr *Web3Server) callerAddress(from string) (address.Address, error) {
+	if from == "" {
+		return address.FromString(address.ZeroAddress)
+	}
+	return ethAddrToIoAddr(from)
+}
+
+// parseWeb3Params decodes the positional params into out, the first required params are mandatory
+func parseWeb3Params(params []json.RawMessage, required int, out ...interface{}) error {
+	if len(params) < required {
+		return errors.Wrapf(errInvalidParams, "expect at least %d params, got %d", required, len(params))
+	}
+	for i := range out {
+		if i >= len(params) {
+			break
+		}
+		if err := json.Unmarshal(params[i], out[i]); err != nil {
+			return errors.Wrapf(errInvalidParams, "param %d: %s", i, err.Error())
+		}
+	}
+	return nil
+}
+
+// Error returns the error message
+func (e *web3Error) Error() string {
+	return e.Message
+}
+
+// nullIfNotExist returns the null result of a missing block or transaction, and the error of any other failure
+func nullIfNotExist(err error) (interface{}, error) {
+	if errors.Cause(err) == db.ErrNotExist {
+		return nil, nil
+	}
+	return nil, err
+}
+
+func newWeb3ErrorResponse(id json.RawMessage, err error) *web3Response {
+	return &web3Response{JSONRPC: web3Version, ID: id, Error: toWeb3Error(err)}
+}
+
+func toWeb3Error(err error) *web3Error {
+	switch e := errors.Cause(err).(type) {
+	case *web3Error:
+		return e
+	case *ExecutionRevertedError:
+		// the revert data is returned for the clients to decode the custom errors, as ethereum nodes do
+		return &web3Error{
+			Code:    3,
+			Message: e.Error(),
+			Data:    hexutil.Bytes(e.Data),
+		}
+	case *QueryTooBroadError:
+		// the suggested range is returned in the data, as other ethereum gateways do
+		return &web3Error{
+			Code:    -32005,
+			Message: e.Error(),
+			Data: map[string]hexutil.Uint64{
+				"from": hexutil.Uint64(e.FromBlock),
+				"to":   hexutil.Uint64(e.SuggestedToBlock),
+			},
+		}
+	}
+	code := -32000
+	switch errors.Cause(err) {
+	case errParse:
+		code = -32700
+	case errInvalidRequest:
+		code = -32600
+	case errMethodNotFound:
+		code = -32601
+	case errInvalidParams:
+		code = -32602
+	case ErrRateLimited:
+		code = -32005
+	case ErrUnauthenticated:
+		code = -32001
+	}
+	return &web3Error{Code: code, Message: strings.TrimSpace(err.Error())}
+}
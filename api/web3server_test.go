@@ -0,0 +1,308 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func web3Call(t *testing.T, svr *Web3Server, method string, params ...interface{}) *web3Response {
+	if params == nil {
+		params = []interface{}{}
+	}
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	svr.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res struct {
+		web3Response
+		Result json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	res.web3Response.Result = res.Result
+	return &res.web3Response
+}
+
+func TestWeb3Server(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	cfg.Genesis.EthereumTxBlockHeight = 1
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	web3 := NewWeb3Server(svr, 0)
+
+	t.Run("chain meta", func(t *testing.T) {
+		res := web3Call(t, web3, "eth_chainId")
+		require.Nil(res.Error)
+		require.Equal(`"0x1251"`, string(res.Result.(json.RawMessage)))
+		res = web3Call(t, web3, "eth_blockNumber")
+		require.Nil(res.Error)
+		require.Equal(`"0x4"`, string(res.Result.(json.RawMessage)))
+	})
+
+	t.Run("balance and nonce", func(t *testing.T) {
+		ethAddr := common.BytesToAddress(identityset.Address(30).Bytes()).Hex()
+		res := web3Call(t, web3, "eth_getBalance", ethAddr, "latest")
+		require.Nil(res.Error)
+		require.Equal(`"0x3"`, string(res.Result.(json.RawMessage)))
+		res = web3Call(t, web3, "eth_getTransactionCount", ethAddr, "latest")
+		require.Nil(res.Error)
+		require.Equal(`"0x9"`, string(res.Result.(json.RawMessage)))
+	})
+
+	t.Run("fee history", func(t *testing.T) {
+		res := web3Call(t, web3, "eth_feeHistory", "0x2", "latest", []float64{25, 75})
+		require.Nil(res.Error)
+		var history web3FeeHistory
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &history))
+		require.EqualValues(3, history.OldestBlock)
+		require.Len(history.GasUsedRatio, 2)
+		require.Len(history.BaseFeePerGas, 3)
+		require.Len(history.Reward, 2)
+		require.Len(history.Reward[0], 2)
+		res = web3Call(t, web3, "eth_feeHistory", 10, "0x2")
+		require.Nil(res.Error)
+		history = web3FeeHistory{}
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &history))
+		require.EqualValues(1, history.OldestBlock)
+		require.Len(history.GasUsedRatio, 2)
+		require.Nil(history.Reward)
+		require.Equal(-32602, web3Call(t, web3, "eth_feeHistory", "0x2", "latest", []float64{75, 25}).Error.Code)
+		require.Equal(-32602, web3Call(t, web3, "eth_feeHistory", "0x0", "latest").Error.Code)
+	})
+
+	t.Run("block and transaction", func(t *testing.T) {
+		res := web3Call(t, web3, "eth_getBlockByNumber", "0x2", false)
+		require.Nil(res.Error)
+		var blk web3Block
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &blk))
+		require.EqualValues(2, blk.Number)
+		require.Equal("0x"+blkHash[2], blk.Hash)
+		require.NotEmpty(blk.Transactions)
+
+		txHash := blk.Transactions[0].(string)
+		res = web3Call(t, web3, "eth_getTransactionByHash", txHash)
+		require.Nil(res.Error)
+		var tx web3Transaction
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &tx))
+		require.Equal(txHash, tx.Hash)
+		require.Equal(blk.Hash, *tx.BlockHash)
+
+		res = web3Call(t, web3, "eth_getTransactionReceipt", txHash)
+		require.Nil(res.Error)
+		var receipt web3Receipt
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &receipt))
+		require.Equal(txHash, receipt.TransactionHash)
+		require.EqualValues(1, receipt.Status)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		res := web3Call(t, web3, "eth_unknownMethod")
+		require.Equal(-32601, res.Error.Code)
+		res = web3Call(t, web3, "eth_getBalance")
+		require.Equal(-32602, res.Error.Code)
+		res = web3Call(t, web3, "eth_getBalance", "0x123", "latest")
+		require.Equal(-32602, res.Error.Code)
+		res = web3Call(t, web3, "eth_sendRawTransaction", "0xf86b")
+		require.Equal(-32602, res.Error.Code)
+		res = web3Call(t, web3, "eth_sendRawTransaction", "0x00")
+		require.Equal(-32602, res.Error.Code)
+	})
+
+	t.Run("null result", func(t *testing.T) {
+		for _, c := range []struct {
+			method string
+			params []interface{}
+		}{
+			{"eth_getBlockByNumber", []interface{}{"0x100", false}},
+			{"eth_getBlockByHash", []interface{}{"0x" + strings.Repeat("01", 32), false}},
+			{"eth_getBlockTransactionCountByNumber", []interface{}{"0x100"}},
+			{"eth_getBlockTransactionCountByHash", []interface{}{"0x" + strings.Repeat("01", 32)}},
+			{"eth_getTransactionByBlockNumberAndIndex", []interface{}{"0x100", "0x0"}},
+			{"eth_getTransactionByBlockNumberAndIndex", []interface{}{"0x1", "0x100"}},
+			{"eth_getTransactionByHash", []interface{}{"0x" + strings.Repeat("01", 32)}},
+			{"eth_getTransactionReceipt", []interface{}{"0x" + strings.Repeat("01", 32)}},
+		} {
+			res := web3Call(t, web3, c.method, c.params...)
+			require.Nil(res.Error, c.method)
+			// the result key is present even if null
+			require.Equal("null", string(res.Result.(json.RawMessage)), c.method)
+		}
+	})
+
+	t.Run("raw ethereum transaction", func(t *testing.T) {
+		svr.broadcastHandler = func(context.Context, uint32, proto.Message) error { return nil }
+		sk := identityset.PrivateKey(27)
+		res := web3Call(t, web3, "eth_getTransactionCount", common.BytesToAddress(identityset.Address(27).Bytes()).Hex(), "latest")
+		require.Nil(res.Error)
+		var nonce hexutil.Uint64
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &nonce))
+
+		to := common.BytesToAddress(identityset.Address(28).Bytes())
+		sign := func(chainID int64) string {
+			tx, err := types.SignTx(types.NewTransaction(uint64(nonce), to, big.NewInt(1), 21000, big.NewInt(0), nil),
+				types.NewEIP155Signer(big.NewInt(chainID)), sk.EcdsaPrivateKey().(*ecdsa.PrivateKey))
+			require.NoError(err)
+			raw, err := rlp.EncodeToBytes(tx)
+			require.NoError(err)
+			return hexutil.Encode(raw)
+		}
+		// signed for another chain
+		res = web3Call(t, web3, "eth_sendRawTransaction", sign(1))
+		require.Equal(-32602, res.Error.Code)
+
+		raw := sign(int64(cfg.Chain.EVMNetworkID))
+		res = web3Call(t, web3, "eth_sendRawTransaction", raw)
+		require.Nil(res.Error)
+		var txHash string
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &txHash))
+		tx := &types.Transaction{}
+		require.NoError(rlp.DecodeBytes(hexutil.MustDecode(raw), tx))
+		require.Equal(tx.Hash().Hex(), txHash)
+
+		res = web3Call(t, web3, "eth_getTransactionByHash", txHash)
+		require.Nil(res.Error)
+		var pending web3Transaction
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &pending))
+		require.Equal(txHash, pending.Hash)
+		require.Equal(common.BytesToAddress(identityset.Address(27).Bytes()).Hex(), pending.From)
+	})
+
+	t.Run("call with state overrides", func(t *testing.T) {
+		contract := "0x0000000000000000000000000000000000001234"
+		callObj := map[string]string{"to": contract}
+		slot := "0x0000000000000000000000000000000000000000000000000000000000000000"
+		value := "0x0000000000000000000000000000000000000000000000000000000000000007"
+		// SLOAD slot 0 and return it
+		res := web3Call(t, web3, "eth_call", callObj, "latest", map[string]interface{}{
+			contract: map[string]interface{}{
+				"code":      "0x60005460005260206000f3",
+				"stateDiff": map[string]string{slot: value},
+			},
+		})
+		require.Nil(res.Error)
+		require.Equal(`"`+value+`"`, string(res.Result.(json.RawMessage)))
+
+		// without overrides the address has no code
+		res = web3Call(t, web3, "eth_call", callObj, "latest")
+		require.Nil(res.Error)
+		require.Equal(`"0x"`, string(res.Result.(json.RawMessage)))
+
+		res = web3Call(t, web3, "eth_call", callObj, "latest", map[string]interface{}{
+			contract: map[string]interface{}{"state": map[string]string{slot: value}},
+		})
+		require.Equal(-32000, res.Error.Code)
+		res = web3Call(t, web3, "eth_call", callObj, "latest", map[string]interface{}{
+			"0x1234": map[string]interface{}{"nonce": "0x1"},
+		})
+		require.Equal(-32602, res.Error.Code)
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		callObj := map[string]string{
+			"from": common.BytesToAddress(identityset.Address(27).Bytes()).Hex(),
+			"to":   common.BytesToAddress(identityset.Address(31).Bytes()).Hex(),
+		}
+		res := web3Call(t, web3, "debug_traceCall", callObj, "latest", map[string]string{"tracer": "callTracer"})
+		require.Equal(-32000, res.Error.Code)
+
+		svr.cfg.API.EnableDebugAPI = true
+		defer func() {
+			svr.cfg.API.EnableDebugAPI = false
+		}()
+		res = web3Call(t, web3, "debug_traceCall", callObj, "latest", map[string]string{"tracer": "callTracer"})
+		require.Nil(res.Error)
+		var frame struct {
+			Type string `json:"type"`
+			To   string `json:"to"`
+		}
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &frame))
+		require.Equal("CALL", frame.Type)
+		require.Equal(strings.ToLower(callObj["to"]), frame.To)
+
+		res = web3Call(t, web3, "debug_traceCall", callObj)
+		require.Nil(res.Error)
+		var structLogs StructLogResult
+		require.NoError(json.Unmarshal(res.Result.(json.RawMessage), &structLogs))
+		require.False(structLogs.Failed)
+
+		res = web3Call(t, web3, "debug_traceCall", callObj, "latest", map[string]string{"tracer": "prestateTracer"})
+		require.Equal(-32000, res.Error.Code)
+		res = web3Call(t, web3, "debug_traceBlockByNumber", "0x1")
+		require.Nil(res.Error)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		web3.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(http.StatusMethodNotAllowed, rec.Code)
+		rec = httptest.NewRecorder()
+		web3.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{"))))
+		var res web3Response
+		require.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Equal(-32700, res.Error.Code)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		post := func(body string) []byte {
+			rec := httptest.NewRecorder()
+			web3.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body))))
+			require.Equal(http.StatusOK, rec.Code)
+			return rec.Body.Bytes()
+		}
+		var batch []web3Response
+		require.NoError(json.Unmarshal(post(` [{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},1,{"jsonrpc":"2.0","id":"b","method":"eth_chainId"}]`), &batch))
+		require.Len(batch, 3)
+		require.Equal("1", string(batch[0].ID))
+		require.Equal("0x4", batch[0].Result)
+		require.Equal(-32600, batch[1].Error.Code)
+		require.Equal(`"b"`, string(batch[2].ID))
+		require.Nil(batch[2].Error)
+
+		var res web3Response
+		require.NoError(json.Unmarshal(post("[]"), &res))
+		require.Equal(-32600, res.Error.Code)
+		reqs := make([]web3Request, cfg.API.BatchRequestLimit+1)
+		for i := range reqs {
+			reqs[i] = web3Request{JSONRPC: web3Version, ID: json.RawMessage("1"), Method: "eth_blockNumber"}
+		}
+		body, err := json.Marshal(reqs)
+		require.NoError(err)
+		require.NoError(json.Unmarshal(post(string(body)), &res))
+		require.Equal(-32600, res.Error.Code)
+	})
+	require.NoError(web3.Stop(context.Background()))
+}
@@ -0,0 +1,447 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/gasstation"
+)
+
+type (
+	web3Block struct {
+		Number           hexutil.Uint64 `json:"number"`
+		Hash             string         `json:"hash"`
+		ParentHash       string         `json:"parentHash"`
+		Sha3Uncles       string         `json:"sha3Uncles"`
+		LogsBloom        string         `json:"logsBloom"`
+		TransactionsRoot string         `json:"transactionsRoot"`
+		StateRoot        string         `json:"stateRoot"`
+		ReceiptsRoot     string         `json:"receiptsRoot"`
+		Miner            string         `json:"miner"`
+		Difficulty       hexutil.Uint64 `json:"difficulty"`
+		TotalDifficulty  hexutil.Uint64 `json:"totalDifficulty"`
+		ExtraData        string         `json:"extraData"`
+		Size             hexutil.Uint64 `json:"size"`
+		GasLimit         hexutil.Uint64 `json:"gasLimit"`
+		GasUsed          hexutil.Uint64 `json:"gasUsed"`
+		Timestamp        hexutil.Uint64 `json:"timestamp"`
+		Transactions     []interface{}  `json:"transactions"`
+		Uncles           []string       `json:"uncles"`
+	}
+
+	web3Transaction struct {
+		Hash             string          `json:"hash"`
+		Nonce            hexutil.Uint64  `json:"nonce"`
+		BlockHash        *string         `json:"blockHash"`
+		BlockNumber      *hexutil.Uint64 `json:"blockNumber"`
+		TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+		From             string          `json:"from"`
+		To               *string         `json:"to"`
+		Value            *hexutil.Big    `json:"value"`
+		GasPrice         *hexutil.Big    `json:"gasPrice"`
+		Gas              hexutil.Uint64  `json:"gas"`
+		Input            hexutil.Bytes   `json:"input"`
+		R                hexutil.Bytes   `json:"r"`
+		S                hexutil.Bytes   `json:"s"`
+		V                hexutil.Uint64  `json:"v"`
+	}
+
+	web3Receipt struct {
+		TransactionHash   string         `json:"transactionHash"`
+		TransactionIndex  hexutil.Uint64 `json:"transactionIndex"`
+		BlockHash         string         `json:"blockHash"`
+		BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+		From              string         `json:"from"`
+		To                *string        `json:"to"`
+		CumulativeGasUsed hexutil.Uint64 `json:"cumulativeGasUsed"`
+		GasUsed           hexutil.Uint64 `json:"gasUsed"`
+		ContractAddress   *string        `json:"contractAddress"`
+		Logs              []*web3Log     `json:"logs"`
+		LogsBloom         string         `json:"logsBloom"`
+		Status            hexutil.Uint64 `json:"status"`
+	}
+
+	web3Log struct {
+		Removed          bool           `json:"removed"`
+		LogIndex         hexutil.Uint64 `json:"logIndex"`
+		TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+		TransactionHash  string         `json:"transactionHash"`
+		BlockHash        string         `json:"blockHash"`
+		BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+		Address          string         `json:"address"`
+		Data             hexutil.Bytes  `json:"data"`
+		Topics           []string       `json:"topics"`
+	}
+
+	// web3CallObject is the transaction call object of eth_call and eth_estimateGas
+	web3CallObject struct {
+		From     string         `json:"from"`
+		To       string         `json:"to"`
+		Gas      hexutil.Uint64 `json:"gas"`
+		GasPrice *hexutil.Big   `json:"gasPrice"`
+		Value    *hexutil.Big   `json:"value"`
+		Data     hexutil.Bytes  `json:"data"`
+		Input    hexutil.Bytes  `json:"input"`
+	}
+
+	// web3FilterObject is the filter object of eth_getLogs
+	web3FilterObject struct {
+		FromBlock string           `json:"fromBlock"`
+		ToBlock   string           `json:"toBlock"`
+		Address   web3StringList   `json:"address"`
+		Topics    []web3StringList `json:"topics"`
+		BlockHash string           `json:"blockHash"`
+	}
+
+	// web3CallBatchResult is the results of iotex_callBatch, executed against the state of the block
+	web3CallBatchResult struct {
+		BlockNumber hexutil.Uint64    `json:"blockNumber"`
+		Results     []*web3CallResult `json:"results"`
+	}
+
+	// web3CallResult is the return value of a call of a batch, or its error
+	web3CallResult struct {
+		Result *hexutil.Bytes `json:"result,omitempty"`
+		Error  *web3Error     `json:"error,omitempty"`
+	}
+
+	// web3LogsCost is the estimated cost of an eth_getLogs query
+	web3LogsCost struct {
+		FromBlock        hexutil.Uint64  `json:"fromBlock"`
+		ToBlock          hexutil.Uint64  `json:"toBlock"`
+		CandidateBlocks  hexutil.Uint64  `json:"candidateBlocks"`
+		MaxBlocksScanned hexutil.Uint64  `json:"maxBlocksScanned"`
+		MaxLogs          hexutil.Uint64  `json:"maxLogs"`
+		TooBroad         bool            `json:"tooBroad"`
+		SuggestedToBlock *hexutil.Uint64 `json:"suggestedToBlock,omitempty"`
+	}
+
+	// web3AccountOverride is an entry of the state override set of eth_call
+	web3AccountOverride struct {
+		Balance   *hexutil.Big                `json:"balance"`
+		Nonce     *hexutil.Uint64             `json:"nonce"`
+		Code      *hexutil.Bytes              `json:"code"`
+		State     map[common.Hash]common.Hash `json:"state"`
+		StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+	}
+
+	// web3TraceConfig is the tracing options of the debug namespace
+	web3TraceConfig struct {
+		Tracer         string `json:"tracer"`
+		DisableStack   bool   `json:"disableStack"`
+		DisableMemory  bool   `json:"disableMemory"`
+		DisableStorage bool   `json:"disableStorage"`
+		Limit          int    `json:"limit"`
+	}
+
+	// web3FeeHistory is the result of eth_feeHistory
+	web3FeeHistory struct {
+		OldestBlock   hexutil.Uint64   `json:"oldestBlock"`
+		Reward        [][]*hexutil.Big `json:"reward,omitempty"`
+		BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio  []float64        `json:"gasUsedRatio"`
+	}
+
+	// web3StringList accepts either a single string or an array of strings
+	web3StringList []string
+
+	// web3Quantity accepts either a hex encoded or a decimal number
+	web3Quantity uint64
+)
+
+var (
+	// emptyUncleHash is the keccak hash of an empty RLP list, which is what ethereum clients expect for sha3Uncles
+	emptyUncleHash = "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"
+	// emptyLogsBloom is the 256-byte zero bloom returned when a block has no logs
+	emptyLogsBloom = "0x" + strings.Repeat("0", 512)
+)
+
+// UnmarshalJSON decodes a hex string or a number
+func (q *web3Quantity) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return (*hexutil.Uint64)(q).UnmarshalJSON(data)
+	}
+	return json.Unmarshal(data, (*uint64)(q))
+}
+
+// UnmarshalJSON decodes a string or an array of strings
+func (l *web3StringList) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*l = nil
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = web3StringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// data returns the call data, preferring the newer "input" field
+func (obj *web3CallObject) data() []byte {
+	if len(obj.Input) > 0 {
+		return obj.Input
+	}
+	return obj.Data
+}
+
+// value returns the amount transferred by the call
+func (obj *web3CallObject) value() *big.Int {
+	if obj.Value == nil {
+		return big.NewInt(0)
+	}
+	return obj.Value.ToInt()
+}
+
+// contract returns the io address of the callee, which is empty for contract creation
+func (obj *web3CallObject) contract() (string, error) {
+	if obj.To == "" {
+		return action.EmptyAddress, nil
+	}
+	ioAddr, err := ethAddrToIoAddr(obj.To)
+	if err != nil {
+		return "", err
+	}
+	return ioAddr.String(), nil
+}
+
+// toStateOverrides converts the override set of eth_call, replacing the whole storage of an account is not supported
+func toStateOverrides(set map[string]*web3AccountOverride) (evm.StateOverrides, error) {
+	overrides := make(evm.StateOverrides, len(set))
+	for addr, acct := range set {
+		if !common.IsHexAddress(addr) {
+			return nil, errors.Wrapf(errInvalidParams, "invalid address %s", addr)
+		}
+		if acct == nil {
+			continue
+		}
+		if acct.State != nil {
+			return nil, errors.Wrap(errUnsupported, "state override is not supported, use stateDiff instead")
+		}
+		override := &evm.AccountOverride{StateDiff: acct.StateDiff}
+		if acct.Balance != nil {
+			override.Balance = acct.Balance.ToInt()
+		}
+		if acct.Nonce != nil {
+			nonce := uint64(*acct.Nonce)
+			override.Nonce = &nonce
+		}
+		if acct.Code != nil {
+			override.Code = []byte(*acct.Code)
+		}
+		overrides[common.HexToAddress(addr)] = override
+	}
+	return overrides, nil
+}
+
+func (cfg *web3TraceConfig) toTraceConfig() *TraceConfig {
+	return &TraceConfig{
+		Tracer: cfg.Tracer,
+		LogConfig: vm.LogConfig{
+			DisableStack:   cfg.DisableStack,
+			DisableMemory:  cfg.DisableMemory,
+			DisableStorage: cfg.DisableStorage,
+			Limit:          cfg.Limit,
+		},
+	}
+}
+
+func newWeb3FeeHistory(history *gasstation.FeeHistory) *web3FeeHistory {
+	res := &web3FeeHistory{
+		OldestBlock:  hexutil.Uint64(history.OldestBlock),
+		GasUsedRatio: history.GasUsedRatio,
+	}
+	for _, fee := range history.BaseFee {
+		res.BaseFeePerGas = append(res.BaseFeePerGas, (*hexutil.Big)(fee))
+	}
+	for _, rewards := range history.Reward {
+		r := make([]*hexutil.Big, len(rewards))
+		for i := range rewards {
+			r[i] = (*hexutil.Big)(rewards[i])
+		}
+		res.Reward = append(res.Reward, r)
+	}
+	return res
+}
+
+// ethAddrToIoAddr converts a 0x-prefixed ethereum address into an io address, an io address is accepted as is
+func ethAddrToIoAddr(ethAddr string) (address.Address, error) {
+	if !common.IsHexAddress(ethAddr) {
+		if addr, err := address.FromString(ethAddr); err == nil {
+			return addr, nil
+		}
+		return nil, errors.Wrapf(errInvalidParams, "invalid address %s", ethAddr)
+	}
+	return address.FromBytes(common.HexToAddress(ethAddr).Bytes())
+}
+
+// ioAddrToEthAddr converts an io address into a 0x-prefixed ethereum address
+func ioAddrToEthAddr(ioAddr string) (string, error) {
+	if ioAddr == "" {
+		return "", nil
+	}
+	addr, err := address.FromString(ioAddr)
+	if err != nil {
+		return "", err
+	}
+	return common.BytesToAddress(addr.Bytes()).Hex(), nil
+}
+
+// mustIoAddrToEthAddr is ioAddrToEthAddr for addresses already validated by the chain
+func mustIoAddrToEthAddr(ioAddr string) string {
+	ethAddr, err := ioAddrToEthAddr(ioAddr)
+	if err != nil {
+		return ""
+	}
+	return ethAddr
+}
+
+func hashToHex(h hash.Hash256) string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+func hexToHash(s string) (hash.Hash256, error) {
+	h, err := hash.HexStringToHash256(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return hash.ZeroHash256, errors.Wrapf(errInvalidParams, "invalid hash %s", s)
+	}
+	return h, nil
+}
+
+// actionIndexInBlock returns the position of the action in the block
+func actionIndexInBlock(blk *block.Block, actHash hash.Hash256) (uint64, bool) {
+	for i, selp := range blk.Actions {
+		if selp.Hash() == actHash {
+			return uint64(i), true
+		}
+	}
+	return 0, false
+}
+
+// actionToAndValue returns the recipient, value and input data of an action in ethereum's terms
+func actionToAndValue(selp action.SealedEnvelope) (*string, *big.Int, []byte) {
+	var (
+		to    *string
+		value = big.NewInt(0)
+		input []byte
+	)
+	switch act := selp.Action().(type) {
+	case *action.Transfer:
+		ethAddr := mustIoAddrToEthAddr(act.Recipient())
+		to, value, input = &ethAddr, act.Amount(), act.Payload()
+	case *action.Execution:
+		if act.Contract() != action.EmptyAddress {
+			ethAddr := mustIoAddrToEthAddr(act.Contract())
+			to = &ethAddr
+		}
+		value, input = act.Amount(), act.Data()
+	default:
+		if dst, ok := selp.Destination(); ok && dst != "" {
+			ethAddr := mustIoAddrToEthAddr(dst)
+			to = &ethAddr
+		}
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return to, value, input
+}
+
+// newWeb3Transaction converts an action into the ethereum transaction object, blk is nil for pending actions
+func newWeb3Transaction(selp action.SealedEnvelope, blk *block.Block, index uint64) *web3Transaction {
+	actHash := selp.Hash()
+	sender, _ := address.FromBytes(selp.SrcPubkey().Hash())
+	to, value, input := actionToAndValue(selp)
+	tx := &web3Transaction{
+		Hash:     hashToHex(actHash),
+		Nonce:    hexutil.Uint64(selp.Nonce()),
+		From:     mustIoAddrToEthAddr(sender.String()),
+		To:       to,
+		Value:    (*hexutil.Big)(value),
+		GasPrice: (*hexutil.Big)(selp.GasPrice()),
+		Gas:      hexutil.Uint64(selp.GasLimit()),
+		Input:    input,
+	}
+	if sig := selp.Signature(); len(sig) == 65 {
+		tx.R, tx.S, tx.V = sig[:32], sig[32:64], hexutil.Uint64(sig[64])+27
+		if selp.Encoding() == action.EthereumRLPEncoding {
+			// EIP-155 encodes the chain id into v
+			tx.V = hexutil.Uint64(sig[64]%27) + 35 + 2*hexutil.Uint64(selp.EVMNetworkID())
+		}
+	}
+	if blk != nil {
+		blkHash := hashToHex(blk.HashBlock())
+		height, idx := hexutil.Uint64(blk.Height()), hexutil.Uint64(index)
+		tx.BlockHash, tx.BlockNumber, tx.TransactionIndex = &blkHash, &height, &idx
+	}
+	return tx
+}
+
+// newWeb3Log converts a log into the ethereum log object
+func newWeb3Log(l *iotextypes.Log, blkHash hash.Hash256, txIndex uint64) *web3Log {
+	topics := make([]string, 0, len(l.Topics))
+	for _, t := range l.Topics {
+		topics = append(topics, "0x"+hex.EncodeToString(t))
+	}
+	return &web3Log{
+		LogIndex:         hexutil.Uint64(l.Index),
+		TransactionIndex: hexutil.Uint64(txIndex),
+		TransactionHash:  "0x" + hex.EncodeToString(l.ActHash),
+		BlockHash:        hashToHex(blkHash),
+		BlockNumber:      hexutil.Uint64(l.BlkHeight),
+		Address:          mustIoAddrToEthAddr(l.ContractAddress),
+		Data:             l.Data,
+		Topics:           topics,
+	}
+}
+
+// logsFilterFromWeb3 converts ethereum log filter into the native logs filter
+func logsFilterFromWeb3(obj *web3FilterObject) (*iotexapi.LogsFilter, error) {
+	filter := &iotexapi.LogsFilter{}
+	for _, addr := range obj.Address {
+		ioAddr, err := ethAddrToIoAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+		filter.Address = append(filter.Address, ioAddr.String())
+	}
+	for _, topics := range obj.Topics {
+		var t iotexapi.Topics
+		for _, topic := range topics {
+			h, err := hexToHash(topic)
+			if err != nil {
+				return nil, err
+			}
+			t.Topic = append(t.Topic, h[:])
+		}
+		filter.Topics = append(filter.Topics, &t)
+	}
+	return filter, nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+type (
+	// web3TxPoolContent is the result of txpool_content, the transactions are keyed by sender and nonce
+	web3TxPoolContent struct {
+		Pending map[string]map[string]interface{} `json:"pending"`
+		Queued  map[string]map[string]interface{} `json:"queued"`
+	}
+
+	// web3TxPoolAccount is the result of txpool_contentFrom, with the missing nonces holding back the queued actions
+	web3TxPoolAccount struct {
+		Pending   map[string]*web3Transaction `json:"pending"`
+		Queued    map[string]*web3Transaction `json:"queued"`
+		NextNonce hexutil.Uint64              `json:"nextNonce"`
+		NonceGaps []web3NonceRange            `json:"nonceGaps"`
+	}
+
+	web3NonceRange struct {
+		From hexutil.Uint64 `json:"from"`
+		To   hexutil.Uint64 `json:"to"`
+	}
+)
+
+func (svr *Web3Server) txPoolContent(ctx context.Context) (interface{}, error) {
+	accounts, err := svr.api.actPoolContent(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	content := &web3TxPoolContent{
+		Pending: make(map[string]map[string]interface{}),
+		Queued:  make(map[string]map[string]interface{}),
+	}
+	for _, account := range accounts {
+		ethAddr := mustIoAddrToEthAddr(account.Address)
+		if txs := web3TxsByNonce(account.Pending, newWeb3PoolTransaction); len(txs) > 0 {
+			content.Pending[ethAddr] = txs
+		}
+		if txs := web3TxsByNonce(account.Queued, newWeb3PoolTransaction); len(txs) > 0 {
+			content.Queued[ethAddr] = txs
+		}
+	}
+	return content, nil
+}
+
+func (svr *Web3Server) txPoolInspect(ctx context.Context) (interface{}, error) {
+	accounts, err := svr.api.actPoolContent(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	content := &web3TxPoolContent{
+		Pending: make(map[string]map[string]interface{}),
+		Queued:  make(map[string]map[string]interface{}),
+	}
+	for _, account := range accounts {
+		ethAddr := mustIoAddrToEthAddr(account.Address)
+		if txs := web3TxsByNonce(account.Pending, inspectWeb3Transaction); len(txs) > 0 {
+			content.Pending[ethAddr] = txs
+		}
+		if txs := web3TxsByNonce(account.Queued, inspectWeb3Transaction); len(txs) > 0 {
+			content.Queued[ethAddr] = txs
+		}
+	}
+	return content, nil
+}
+
+func (svr *Web3Server) txPoolContentFrom(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var ethAddr string
+	if err := parseWeb3Params(params, 1, &ethAddr); err != nil {
+		return nil, err
+	}
+	ioAddr, err := ethAddrToIoAddr(ethAddr)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := svr.api.actPoolContent(ctx, ioAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	ret := &web3TxPoolAccount{
+		Pending:   map[string]*web3Transaction{},
+		Queued:    map[string]*web3Transaction{},
+		NonceGaps: []web3NonceRange{},
+	}
+	if len(accounts) == 0 {
+		return ret, nil
+	}
+	account := accounts[0]
+	for _, selp := range account.Pending {
+		ret.Pending[strconv.FormatUint(selp.Nonce(), 10)] = newWeb3Transaction(selp, nil, 0)
+	}
+	for _, selp := range account.Queued {
+		ret.Queued[strconv.FormatUint(selp.Nonce(), 10)] = newWeb3Transaction(selp, nil, 0)
+	}
+	ret.NextNonce = hexutil.Uint64(account.NextNonce)
+	for _, gap := range account.NonceGaps {
+		ret.NonceGaps = append(ret.NonceGaps, web3NonceRange{From: hexutil.Uint64(gap.From), To: hexutil.Uint64(gap.To)})
+	}
+	return ret, nil
+}
+
+func (svr *Web3Server) txPoolStatus(ctx context.Context) (interface{}, error) {
+	status, err := svr.api.actPoolStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]hexutil.Uint64{
+		"pending": hexutil.Uint64(status.Pending),
+		"queued":  hexutil.Uint64(status.Queued),
+	}, nil
+}
+
+// txPoolDropReason returns the reason a recently dropped transaction left the pool before being mined, or null if it
+// was not dropped recently
+func (svr *Web3Server) txPoolDropReason(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	var actHash string
+	if err := parseWeb3Params(params, 1, &actHash); err != nil {
+		return nil, err
+	}
+	h, err := hexToHash(actHash)
+	if err != nil {
+		return nil, err
+	}
+	reason, err := svr.api.GetActionDropReason(ctx, hex.EncodeToString(h[:]))
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reason, nil
+}
+
+func web3TxsByNonce(acts []action.SealedEnvelope, convert func(action.SealedEnvelope) interface{}) map[string]interface{} {
+	txs := make(map[string]interface{}, len(acts))
+	for _, selp := range acts {
+		txs[strconv.FormatUint(selp.Nonce(), 10)] = convert(selp)
+	}
+	return txs
+}
+
+func newWeb3PoolTransaction(selp action.SealedEnvelope) interface{} {
+	return newWeb3Transaction(selp, nil, 0)
+}
+
+// inspectWeb3Transaction summarizes a transaction the way geth does in txpool_inspect
+func inspectWeb3Transaction(selp action.SealedEnvelope) interface{} {
+	to, value, _ := actionToAndValue(selp)
+	recipient := "contract creation"
+	if to != nil {
+		recipient = *to
+	}
+	return fmt.Sprintf("%s: %s wei + %d gas × %s wei", recipient, value, selp.GasLimit(), selp.GasPrice())
+}
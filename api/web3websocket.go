@@ -0,0 +1,308 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/action"
+	logfilter "github.com/iotexproject/iotex-core/api/logfilter"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	// web3WriteTimeout is the deadline of writing a message to a websocket client
+	web3WriteTimeout = 10 * time.Second
+	// web3MaxSubscriptionsPerConn caps the live subscriptions of a single websocket connection
+	web3MaxSubscriptionsPerConn = 100
+	// web3NotificationBuffer is the number of notifications queued per connection before dropping the connection
+	web3NotificationBuffer = 1024
+)
+
+// subscription types
+const (
+	subNewHeads               = "newHeads"
+	subLogs                   = "logs"
+	subNewPendingTransactions = "newPendingTransactions"
+)
+
+var (
+	errNotificationsUnsupported = errors.New("notifications not supported")
+	errConnClosed               = errors.New("websocket connection closed")
+)
+
+type (
+	// web3Conn is a websocket connection which may hold subscriptions
+	web3Conn struct {
+		svr    *Web3Server
+		conn   *websocket.Conn
+		outbox chan interface{}
+		done   chan struct{}
+		once   sync.Once
+		mutex  sync.Mutex
+		subs   map[string]*web3Subscription
+	}
+
+	// web3Subscription pushes chain events of a given kind to its connection
+	web3Subscription struct {
+		id     string
+		kind   string
+		conn   *web3Conn
+		filter *logfilter.LogFilter
+	}
+
+	web3Notification struct {
+		JSONRPC string                 `json:"jsonrpc"`
+		Method  string                 `json:"method"`
+		Params  web3NotificationParams `json:"params"`
+	}
+
+	web3NotificationParams struct {
+		Subscription string      `json:"subscription"`
+		Result       interface{} `json:"result"`
+	}
+
+	web3ConnCtxKey struct{}
+)
+
+// serveWebsocket upgrades the request and serves JSON-RPC requests over the websocket until it closes
+func (svr *Web3Server) serveWebsocket(w http.ResponseWriter, req *http.Request) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     svr.httpServer.cors.checkWebsocketOrigin,
+	}
+	ws, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		log.L().Debug("failed to upgrade websocket.", zap.Error(err))
+		return
+	}
+	c := &web3Conn{
+		svr:    svr,
+		conn:   ws,
+		outbox: make(chan interface{}, web3NotificationBuffer),
+		done:   make(chan struct{}),
+		subs:   make(map[string]*web3Subscription),
+	}
+	go c.writeLoop()
+	defer c.close()
+
+	ctx := context.WithValue(withWeb3Client(withHTTPRequestOrigin(req.Context(), req), req.RemoteAddr, true), web3ConnCtxKey{}, c)
+	defer svr.filters.uninstallClient(web3Client(ctx))
+	ws.SetReadLimit(web3MaxRequestSize)
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if !c.send(svr.handleWeb3Message(ctx, msg)) {
+			return
+		}
+	}
+}
+
+func (svr *Web3Server) subscribe(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	c, ok := ctx.Value(web3ConnCtxKey{}).(*web3Conn)
+	if !ok {
+		return nil, errNotificationsUnsupported
+	}
+	var (
+		kind      string
+		filterObj web3FilterObject
+	)
+	if err := parseWeb3Params(params, 1, &kind, &filterObj); err != nil {
+		return nil, err
+	}
+	sub := &web3Subscription{kind: kind, conn: c}
+	switch kind {
+	case subNewHeads, subNewPendingTransactions:
+	case subLogs:
+		filter, err := logsFilterFromWeb3(&filterObj)
+		if err != nil {
+			return nil, err
+		}
+		sub.filter = logfilter.NewLogFilter(filter, nil, nil)
+	default:
+		return nil, errors.Wrapf(errInvalidParams, "unsupported subscription %s", kind)
+	}
+	if err := c.addSubscription(sub); err != nil {
+		return nil, err
+	}
+	if kind == subNewPendingTransactions {
+		svr.api.ap.AddSubscriber(sub)
+	} else if err := svr.api.chainListener.AddResponder(sub); err != nil {
+		c.removeSubscription(sub.id)
+		return nil, err
+	}
+	return sub.id, nil
+}
+
+func (svr *Web3Server) unsubscribe(ctx context.Context, params []json.RawMessage) (interface{}, error) {
+	c, ok := ctx.Value(web3ConnCtxKey{}).(*web3Conn)
+	if !ok {
+		return nil, errNotificationsUnsupported
+	}
+	var id string
+	if err := parseWeb3Params(params, 1, &id); err != nil {
+		return nil, err
+	}
+	return c.removeSubscription(id), nil
+}
+
+func (c *web3Conn) addSubscription(sub *web3Subscription) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.subs) >= web3MaxSubscriptionsPerConn {
+		return errors.Errorf("too many subscriptions, limit is %d", web3MaxSubscriptionsPerConn)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	sub.id = "0x" + hex.EncodeToString(id)
+	c.subs[sub.id] = sub
+	return nil
+}
+
+// removeSubscription drops the subscription, block responders are pruned by the listener on their next Respond
+func (c *web3Conn) removeSubscription(id string) bool {
+	c.mutex.Lock()
+	sub, ok := c.subs[id]
+	delete(c.subs, id)
+	c.mutex.Unlock()
+	if ok && sub.kind == subNewPendingTransactions {
+		c.svr.api.ap.RemoveSubscriber(sub)
+	}
+	return ok
+}
+
+func (c *web3Conn) hasSubscription(id string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, ok := c.subs[id]
+	return ok
+}
+
+// send queues a message for the client without blocking, a slow client is disconnected
+func (c *web3Conn) send(msg interface{}) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.outbox <- msg:
+		return true
+	default:
+		log.L().Debug("websocket client is too slow, disconnecting.")
+		c.close()
+		return false
+	}
+}
+
+func (c *web3Conn) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.outbox:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(web3WriteTimeout)); err != nil {
+				c.close()
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+func (c *web3Conn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.mutex.Lock()
+		ids := make([]string, 0, len(c.subs))
+		for id := range c.subs {
+			ids = append(ids, id)
+		}
+		c.mutex.Unlock()
+		for _, id := range ids {
+			c.removeSubscription(id)
+		}
+		if err := c.conn.Close(); err != nil {
+			log.L().Debug("failed to close websocket.", zap.Error(err))
+		}
+	})
+}
+
+func (sub *web3Subscription) notify(result interface{}) error {
+	if !sub.conn.hasSubscription(sub.id) {
+		return errConnClosed
+	}
+	if !sub.conn.send(&web3Notification{
+		JSONRPC: web3Version,
+		Method:  "eth_subscription",
+		Params:  web3NotificationParams{Subscription: sub.id, Result: result},
+	}) {
+		return errConnClosed
+	}
+	return nil
+}
+
+// Respond pushes the new block header or matching logs to the client
+func (sub *web3Subscription) Respond(blk *block.Block) error {
+	switch sub.kind {
+	case subNewHeads:
+		header, err := sub.conn.svr.newWeb3Block(blk, false)
+		if err != nil {
+			return err
+		}
+		header.Transactions = nil
+		return sub.notify(header)
+	case subLogs:
+		if !sub.conn.hasSubscription(sub.id) {
+			return errConnClosed
+		}
+		if bloom := blk.LogsBloomfilter(); bloom != nil && !sub.filter.ExistInBloomFilter(bloom) {
+			return nil
+		}
+		blkHash := blk.HashBlock()
+		for _, l := range sub.filter.MatchLogs(blk.Receipts) {
+			index, _ := actionIndexInBlock(blk, hash.BytesToHash256(l.ActHash))
+			if err := sub.notify(newWeb3Log(l, blkHash, index)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Exit is called when the chain listener stops
+func (sub *web3Subscription) Exit() {
+	sub.conn.close()
+}
+
+// ReceiveAction pushes the hash of a newly accepted pending action to the client
+func (sub *web3Subscription) ReceiveAction(selp action.SealedEnvelope) {
+	if err := sub.notify(hashToHex(selp.Hash())); err != nil {
+		log.L().Debug("failed to notify pending action.", zap.Error(err))
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type web3WsMessage struct {
+	ID     json.RawMessage        `json:"id"`
+	Result json.RawMessage        `json:"result"`
+	Error  *web3Error             `json:"error"`
+	Method string                 `json:"method"`
+	Params web3NotificationParams `json:"params"`
+}
+
+func web3WsCall(t *testing.T, conn *websocket.Conn, method string, params ...interface{}) *web3WsMessage {
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	}))
+	return web3WsRead(t, conn)
+}
+
+func web3WsRead(t *testing.T, conn *websocket.Conn) *web3WsMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg web3WsMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return &msg
+}
+
+func TestWeb3Websocket(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	svr.chainListener = NewChainListener()
+	require.NoError(svr.chainListener.Start())
+	defer func() {
+		require.NoError(svr.chainListener.Stop())
+	}()
+
+	ts := httptest.NewServer(NewWeb3Server(svr, 0))
+	defer ts.Close()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http")
+	// the connections of other sites are rejected
+	_, _, err = websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://example.com"}})
+	require.Equal(websocket.ErrBadHandshake, err)
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{ts.URL}})
+	require.NoError(err)
+	defer conn.Close()
+
+	// plain requests are served over websocket as well
+	res := web3WsCall(t, conn, "eth_blockNumber")
+	require.Nil(res.Error)
+	require.Equal(`"0x4"`, string(res.Result))
+
+	// subscriptions are not available over http
+	require.Equal(-32000, web3Call(t, NewWeb3Server(svr, 0), "eth_subscribe", "newHeads").Error.Code)
+	res = web3WsCall(t, conn, "eth_subscribe", "unknown")
+	require.Equal(-32602, res.Error.Code)
+
+	res = web3WsCall(t, conn, "eth_subscribe", "newHeads")
+	require.Nil(res.Error)
+	var headsID string
+	require.NoError(json.Unmarshal(res.Result, &headsID))
+
+	blk, err := svr.dao.GetBlockByHeight(2)
+	require.NoError(err)
+	require.NoError(svr.chainListener.ReceiveBlock(blk))
+	msg := web3WsRead(t, conn)
+	require.Equal("eth_subscription", msg.Method)
+	require.Equal(headsID, msg.Params.Subscription)
+	header, err := json.Marshal(msg.Params.Result)
+	require.NoError(err)
+	var head web3Block
+	require.NoError(json.Unmarshal(header, &head))
+	require.EqualValues(2, head.Number)
+	require.Equal("0x"+blkHash[2], head.Hash)
+
+	res = web3WsCall(t, conn, "eth_unsubscribe", headsID)
+	require.Equal("true", string(res.Result))
+	res = web3WsCall(t, conn, "eth_unsubscribe", headsID)
+	require.Equal("false", string(res.Result))
+
+	res = web3WsCall(t, conn, "eth_subscribe", "newPendingTransactions")
+	require.Nil(res.Error)
+	var pendingID string
+	require.NoError(json.Unmarshal(res.Result, &pendingID))
+	tsf, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), 2, big.NewInt(20), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+	require.NoError(err)
+	ctx := protocol.WithRegistry(context.Background(), svr.registry)
+	require.NoError(svr.ap.Add(ctx, tsf))
+	msg = web3WsRead(t, conn)
+	require.Equal(pendingID, msg.Params.Subscription)
+	require.Equal(hashToHex(tsf.Hash()), msg.Params.Result)
+}
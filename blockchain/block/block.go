@@ -35,6 +35,9 @@ type Block struct {
 
 	// TODO: move receipts out of block struct
 	Receipts []*action.Receipt
+
+	// prevalidated is set once the checks independent of the state pass
+	prevalidated bool
 }
 
 // ConvertToBlockHeaderPb converts BlockHeader to BlockHeader
@@ -47,6 +47,42 @@ func (v *validator) Validate(ctx context.Context, blk *Block) error {
 	return nil
 }
 
+// Prevalidate runs the validation stages independent of the state: the signature and the tx root of the block, and
+// the signature and the sanity of its actions, which run in parallel. It may run for the next blocks while the
+// current one executes, and a prevalidated block skips these stages when it is validated against the state.
+func Prevalidate(blk *Block) error {
+	if err := VerifyBlock(blk); err != nil {
+		return err
+	}
+	errChan := make(chan error, len(blk.Actions))
+	var wg sync.WaitGroup
+	for _, selp := range blk.Actions {
+		wg.Add(1)
+		go func(s action.SealedEnvelope) {
+			defer wg.Done()
+			if err := action.Verify(s); err != nil {
+				errChan <- err
+				return
+			}
+			if err := s.Action().SanityCheck(); err != nil {
+				errChan <- err
+			}
+		}(selp)
+	}
+	wg.Wait()
+	close(errChan)
+	for err := range errChan {
+		return errors.Wrap(err, "failed to validate action")
+	}
+	blk.prevalidated = true
+	return nil
+}
+
+// Prevalidated returns whether the block has passed Prevalidate
+func (b *Block) Prevalidated() bool {
+	return b.prevalidated
+}
+
 func (v *validator) validateActions(
 	ctx context.Context,
 	actions []action.SealedEnvelope,
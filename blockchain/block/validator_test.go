@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/identityset"
@@ -59,3 +60,34 @@ func TestValidator(t *testing.T) {
 	require.True(strings.Contains(v.Validate(ctx, &nblk).Error(), "MockChainManager nonce error"))
 
 }
+
+func TestPrevalidate(t *testing.T) {
+	require := require.New(t)
+
+	tsf1, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), 1, big.NewInt(20), []byte{}, 100000, big.NewInt(10))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(identityset.Address(29).String(), identityset.PrivateKey(27), 2, big.NewInt(30), []byte{}, 100000, big.NewInt(10))
+	require.NoError(err)
+	blk, err := NewTestingBuilder().
+		SetHeight(1).
+		SetPrevBlockHash(tsf1.Hash()).
+		SetTimeStamp(testutil.TimestampNow()).
+		AddActions(tsf1, tsf2).
+		SignAndBuild(identityset.PrivateKey(27))
+	require.NoError(err)
+	require.False(blk.Prevalidated())
+	require.NoError(Prevalidate(&blk))
+	require.True(blk.Prevalidated())
+
+	// an action with a wrong signature fails the block
+	forged := action.AssembleSealedEnvelope(tsf2.Envelope, tsf2.SrcPubkey(), tsf1.Signature())
+	blk, err = NewTestingBuilder().
+		SetHeight(1).
+		SetPrevBlockHash(tsf1.Hash()).
+		SetTimeStamp(testutil.TimestampNow()).
+		AddActions(tsf1, forged).
+		SignAndBuild(identityset.PrivateKey(27))
+	require.NoError(err)
+	require.Error(Prevalidate(&blk))
+	require.False(blk.Prevalidated())
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/prometheustimer"
+	"github.com/iotexproject/iotex-core/signer"
 )
 
 var (
@@ -50,8 +51,13 @@ var (
 	ErrInsufficientGas = errors.New("insufficient intrinsic gas value")
 	// ErrBalance indicates the error of balance
 	ErrBalance = errors.New("invalid balance")
+	// ErrForkBlock indicates the block is on a side chain which is not longer than the chain
+	ErrForkBlock = errors.New("block on a side chain")
 )
 
+// forkCacheSizeFactor is the number of side chain blocks kept per block of the max reorg depth
+const forkCacheSizeFactor = 4
+
 func init() {
 	prometheus.MustRegister(blockMtc)
 }
@@ -129,6 +135,8 @@ type blockchain struct {
 	lifecycle      lifecycle.Lifecycle
 	pubSubManager  PubSubManager
 	timerFactory   *prometheustimer.TimerFactory
+	forks          *forkCache
+	signer         signer.Signer
 
 	// used by account-based model
 	bbf BlockBuilderFactory
@@ -145,6 +153,14 @@ func BlockValidatorOption(blockValidator block.Validator) Option {
 	}
 }
 
+// SignerOption sets the signer of the blocks produced by the node, which signs with the producer private key by default
+func SignerOption(s signer.Signer) Option {
+	return func(bc *blockchain, cfg config.Config) error {
+		bc.signer = s
+		return nil
+	}
+}
+
 // BoltDBDaoOption sets blockchain's dao with BoltDB from config.Chain.ChainDBPath
 func BoltDBDaoOption(indexers ...blockdao.BlockIndexer) Option {
 	return func(bc *blockchain, cfg config.Config) error {
@@ -179,6 +195,9 @@ func NewBlockchain(cfg config.Config, dao blockdao.BlockDAO, bbf BlockBuilderFac
 		bbf:           bbf,
 		pubSubManager: NewPubSub(cfg.BlockSync.BufferSize),
 	}
+	if cfg.Chain.MaxReorgDepth > 0 {
+		chain.forks = newForkCache(int(cfg.Chain.MaxReorgDepth) * forkCacheSizeFactor)
+	}
 	for _, opt := range opts {
 		if err := opt(chain, cfg); err != nil {
 			log.S().Panicf("Failed to execute blockchain creation option %p: %v", opt, err)
@@ -262,7 +281,8 @@ func (bc *blockchain) TipHeight() uint64 {
 	return tipHeight
 }
 
-// ValidateBlock validates a new block before adding it to the blockchain
+// ValidateBlock validates a new block before adding it to the blockchain. If deep reorg is enabled, a block on a side
+// chain is kept in the fork cache, and it is valid once the side chain grows longer than the chain.
 func (bc *blockchain) ValidateBlock(blk *block.Block) error {
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
@@ -271,6 +291,19 @@ func (bc *blockchain) ValidateBlock(blk *block.Block) error {
 	if blk == nil {
 		return ErrInvalidBlock
 	}
+	if bc.forks != nil && blk.Height() != 0 {
+		tip, err := bc.tipInfo()
+		if err != nil {
+			return err
+		}
+		if blk.PrevHash() != tip.Hash {
+			return bc.validateForkBlock(blk, tip)
+		}
+	}
+	return bc.validateBlock(blk)
+}
+
+func (bc *blockchain) validateBlock(blk *block.Block) error {
 	tip, err := bc.tipInfo()
 	if err != nil {
 		return err
@@ -295,8 +328,10 @@ func (bc *blockchain) ValidateBlock(blk *block.Block) error {
 			tip.Hash,
 		)
 	}
-	if err := block.VerifyBlock(blk); err != nil {
-		return errors.Wrap(err, "failed to verify block's signature and merkle root")
+	if !blk.Prevalidated() {
+		if err := block.VerifyBlock(blk); err != nil {
+			return errors.Wrap(err, "failed to verify block's signature and merkle root")
+		}
 	}
 
 	producerAddr, err := address.FromBytes(blk.PublicKey().Hash())
@@ -311,10 +346,13 @@ func (bc *blockchain) ValidateBlock(blk *block.Block) error {
 		protocol.BlockCtx{
 			BlockHeight:    blk.Height(),
 			BlockTimeStamp: blk.Timestamp(),
-			GasLimit:       bc.config.Genesis.BlockGasLimit,
+			GasLimit:       bc.config.Genesis.BlockGasLimitByHeight(blk.Height()),
 			Producer:       producerAddr,
 		},
 	)
+	if blk.Prevalidated() {
+		ctx = protocol.WithVerifiedActions(ctx)
+	}
 	if bc.blockValidator == nil {
 		return nil
 	}
@@ -336,7 +374,7 @@ func (bc *blockchain) contextWithBlock(ctx context.Context, producer address.Add
 			BlockHeight:    height,
 			BlockTimeStamp: timestamp,
 			Producer:       producer,
-			GasLimit:       bc.config.Genesis.BlockGasLimit,
+			GasLimit:       bc.config.Genesis.BlockGasLimitByHeight(height),
 		})
 }
 
@@ -353,8 +391,9 @@ func (bc *blockchain) context(ctx context.Context, tipInfoFlag bool) (context.Co
 	return protocol.WithBlockchainCtx(
 		ctx,
 		protocol.BlockchainCtx{
-			Genesis: bc.config.Genesis,
-			Tip:     tip,
+			Genesis:      bc.config.Genesis,
+			Tip:          tip,
+			EvmNetworkID: bc.config.Chain.EVMNetworkID,
 		},
 	), nil
 }
@@ -375,17 +414,20 @@ func (bc *blockchain) MintNewBlock(timestamp time.Time) (*block.Block, error) {
 	}
 	ctx = bc.contextWithBlock(ctx, bc.config.ProducerAddress(), newblockHeight, timestamp)
 	// run execution and update state trie root hash
-	minterPrivateKey := bc.config.ProducerPrivateKey()
+	minter := bc.signer
+	if minter == nil {
+		minter = signer.NewLocalSigner(bc.config.ProducerPrivateKey())
+	}
 	blockBuilder, err := bc.bbf.NewBlockBuilder(
 		ctx,
 		func(elp action.Envelope) (action.SealedEnvelope, error) {
-			return action.Sign(elp, minterPrivateKey)
+			return action.Sign(elp, signer.ForMessage(minter, signer.ActionMessage))
 		},
 	)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create block builder at new block height %d", newblockHeight)
 	}
-	blk, err := blockBuilder.SignAndBuild(minterPrivateKey)
+	blk, err := blockBuilder.SignAndBuild(signer.ForMessage(minter, signer.BlockMessage))
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create block")
 	}
@@ -399,6 +441,15 @@ func (bc *blockchain) CommitBlock(blk *block.Block) error {
 	defer bc.mu.Unlock()
 	timer := bc.timerFactory.NewTimer("CommitBlock")
 	defer timer.End()
+	if bc.forks != nil && blk.Height() != 0 {
+		tip, err := bc.tipInfo()
+		if err != nil {
+			return err
+		}
+		if blk.PrevHash() != tip.Hash {
+			return bc.reorg(blk, tip)
+		}
+	}
 	return bc.commitBlock(blk)
 }
 
@@ -475,6 +526,9 @@ func (bc *blockchain) commitBlock(blk *block.Block) error {
 	}
 	blkHash := blk.HashBlock()
 	blk.HeaderLogger(log.L()).Info("Committed a block.", log.Hex("tipHash", blkHash[:]))
+	if bc.forks != nil && blk.Height() > bc.config.Chain.MaxReorgDepth {
+		bc.forks.Prune(blk.Height() - bc.config.Chain.MaxReorgDepth)
+	}
 	blockMtc.WithLabelValues("numActions").Set(float64(len(blk.Actions)))
 	// emit block to all block subscribers
 	bc.emitToSubscribers(blk)
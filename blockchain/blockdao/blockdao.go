@@ -8,12 +8,15 @@ package blockdao
 
 import (
 	"context"
+	"encoding/hex"
+	"strconv"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/iotexproject/go-pkgs/cache"
 	"github.com/iotexproject/go-pkgs/hash"
@@ -25,6 +28,7 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/blockchain/filedao"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/prometheustimer"
@@ -48,6 +52,20 @@ type (
 		GetActionByActionHash(hash.Hash256, uint64) (action.SealedEnvelope, error)
 		GetReceiptByActionHash(hash.Hash256, uint64) (*action.Receipt, error)
 		DeleteBlockToTarget(uint64) error
+		BlocksInRange(uint64, uint64) (BlockIterator, error)
+		ReceiptsInRange(uint64, uint64) (ReceiptIterator, error)
+	}
+
+	// BlockIterator iterates the blocks in ascending order of height
+	BlockIterator interface {
+		// Next returns the next block with its receipts, or io.EOF after the last one
+		Next() (*block.Block, error)
+	}
+
+	// ReceiptIterator iterates the receipts of the blocks in ascending order of height
+	ReceiptIterator interface {
+		// Next returns the receipts of the next block, or io.EOF after the last one
+		Next() ([]*action.Receipt, error)
 	}
 
 	// BlockIndexer defines an interface to accept block to build index
@@ -59,25 +77,79 @@ type (
 		DeleteTipBlock(blk *block.Block) error
 	}
 
+	blockIterator struct {
+		iter     filedao.BlockStoreIterator
+		receipts *receiptStore
+	}
+
+	receiptIterator struct {
+		iter     filedao.BlockStoreIterator
+		receipts *receiptStore
+	}
+
 	blockDAO struct {
 		blockStore   filedao.FileDAO
+		receiptStore *receiptStore
+		journal      *commitJournal
 		indexers     []BlockIndexer
 		timerFactory *prometheustimer.TimerFactory
 		lifecycle    lifecycle.Lifecycle
 		headerCache  *cache.ThreadSafeLruCache
 		bodyCache    *cache.ThreadSafeLruCache
 		footerCache  *cache.ThreadSafeLruCache
-		tipHeight    uint64
+		// loading deduplicates the concurrent reads of the same header or footer missing in the caches
+		loading   singleflight.Group
+		tipHeight uint64
 	}
 )
 
+func (it *blockIterator) Next() (*block.Block, error) {
+	store, err := it.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+	if it.receipts == nil {
+		store.Block.Receipts = store.Receipts
+		return store.Block, nil
+	}
+	if store.Block.Receipts, err = it.receipts.Get(store.Block.Height()); err != nil {
+		return nil, err
+	}
+	return store.Block, nil
+}
+
+func (it *receiptIterator) Next() ([]*action.Receipt, error) {
+	store, err := it.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+	if it.receipts == nil {
+		return store.Receipts, nil
+	}
+	return it.receipts.Get(store.Block.Height())
+}
+
 // NewBlockDAO instantiates a block DAO
 func NewBlockDAO(indexers []BlockIndexer, cfg config.DB) BlockDAO {
 	blkStore, err := filedao.NewFileDAO(cfg)
 	if err != nil {
 		return nil
 	}
-	return createBlockDAO(blkStore, indexers, cfg)
+	dao := createBlockDAO(blkStore, indexers, cfg)
+	if dao == nil {
+		return nil
+	}
+	if cfg.Receipt.DbPath != "" {
+		receiptCfg := cfg
+		receiptCfg.DbPath = cfg.Receipt.DbPath
+		dao.(*blockDAO).withReceiptStore(newReceiptStore(db.NewBoltDB(receiptCfg), cfg.Receipt.Retention))
+	}
+	if cfg.CommitJournalPath != "" {
+		journalCfg := cfg
+		journalCfg.DbPath = cfg.CommitJournalPath
+		dao.(*blockDAO).withCommitJournal(newCommitJournal(db.NewBoltDB(journalCfg)))
+	}
+	return dao
 }
 
 // NewBlockDAOInMemForTest creates a in-memory block DAO for testing
@@ -101,7 +173,45 @@ func (dao *blockDAO) Start(ctx context.Context) error {
 		return err
 	}
 	atomic.StoreUint64(&dao.tipHeight, tipHeight)
-	return dao.checkIndexers(ctx)
+	if err := dao.recoverPendingBlock(ctx); err != nil {
+		return err
+	}
+	if err := dao.checkIndexers(ctx); err != nil {
+		return err
+	}
+	if dao.journal != nil {
+		return dao.journal.End()
+	}
+	return nil
+}
+
+// recoverPendingBlock completes the commit of the journaled block into the block store, if the node crashed before
+// the block store committed it. The indexers are then caught up from the block store by checkIndexers
+func (dao *blockDAO) recoverPendingBlock(ctx context.Context) error {
+	if dao.journal == nil {
+		return nil
+	}
+	blk, err := dao.journal.Pending()
+	if err != nil || blk == nil {
+		return err
+	}
+	switch height := blk.Height(); {
+	case height <= dao.tipHeight:
+		return nil
+	case height == dao.tipHeight+1:
+		log.L().Info("Recovering the commit of pending block.", zap.Uint64("height", height))
+		ctx, err = dao.fillWithBlockInfoAsTip(ctx, dao.tipHeight)
+		if err != nil {
+			return err
+		}
+		if err := dao.putBlockStore(ctx, blk); err != nil {
+			return errors.Wrapf(err, "failed to recover pending block %d", height)
+		}
+		atomic.StoreUint64(&dao.tipHeight, height)
+		return nil
+	default:
+		return errors.Errorf("journaled block %d is above dao tip height %d", height, dao.tipHeight)
+	}
 }
 
 func (dao *blockDAO) fillWithBlockInfoAsTip(ctx context.Context, height uint64) (context.Context, error) {
@@ -143,17 +253,17 @@ func (dao *blockDAO) checkIndexers(ctx context.Context) error {
 			// TODO: delete block
 			return errors.New("indexer tip height cannot by higher than dao tip height")
 		}
+		var iter BlockIterator
+		if tipHeight < dao.tipHeight {
+			if iter, err = dao.BlocksInRange(tipHeight+1, dao.tipHeight); err != nil {
+				return err
+			}
+		}
 		for i := tipHeight + 1; i <= dao.tipHeight; i++ {
-			blk, err := dao.GetBlockByHeight(i)
+			blk, err := iter.Next()
 			if err != nil {
 				return err
 			}
-			if blk.Receipts == nil {
-				blk.Receipts, err = dao.GetReceipts(i)
-				if err != nil {
-					return err
-				}
-			}
 			producer, err := address.FromBytes(blk.PublicKey().Hash())
 			if err != nil {
 				return err
@@ -168,7 +278,7 @@ func (dao *blockDAO) checkIndexers(ctx context.Context) error {
 					BlockHeight:    i,
 					BlockTimeStamp: blk.Timestamp(),
 					Producer:       producer,
-					GasLimit:       bcCtx.Genesis.BlockGasLimit,
+					GasLimit:       bcCtx.Genesis.BlockGasLimitByHeight(i),
 				},
 			), blk); err != nil {
 				return err
@@ -223,13 +333,18 @@ func (dao *blockDAO) HeaderByHeight(height uint64) (*block.Header, error) {
 	}
 	cacheMtc.WithLabelValues("miss_header").Inc()
 
-	header, err := dao.blockStore.HeaderByHeight(height)
+	v, err := dao.load("header:"+strconv.FormatUint(height, 10), func() (interface{}, error) {
+		header, err := dao.blockStore.HeaderByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		dao.cacheHeader(header)
+		return header, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	lruCachePut(dao.headerCache, height, header)
-	return header, nil
+	return v.(*block.Header), nil
 }
 
 func (dao *blockDAO) FooterByHeight(height uint64) (*block.Footer, error) {
@@ -239,13 +354,18 @@ func (dao *blockDAO) FooterByHeight(height uint64) (*block.Footer, error) {
 	}
 	cacheMtc.WithLabelValues("miss_footer").Inc()
 
-	footer, err := dao.blockStore.FooterByHeight(height)
+	v, err := dao.load("footer:"+strconv.FormatUint(height, 10), func() (interface{}, error) {
+		footer, err := dao.blockStore.FooterByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		lruCachePut(dao.footerCache, height, footer)
+		return footer, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	lruCachePut(dao.footerCache, height, footer)
-	return footer, nil
+	return v.(*block.Footer), nil
 }
 
 func (dao *blockDAO) Height() (uint64, error) {
@@ -259,13 +379,33 @@ func (dao *blockDAO) Header(h hash.Hash256) (*block.Header, error) {
 	}
 	cacheMtc.WithLabelValues("miss_header").Inc()
 
-	header, err := dao.blockStore.Header(h)
+	v, err := dao.load("header:"+hex.EncodeToString(h[:]), func() (interface{}, error) {
+		header, err := dao.blockStore.Header(h)
+		if err != nil {
+			return nil, err
+		}
+		dao.cacheHeader(header)
+		return header, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return v.(*block.Header), nil
+}
+
+// load reads the missing entry once for the concurrent callers of the same key
+func (dao *blockDAO) load(key string, fn func() (interface{}, error)) (interface{}, error) {
+	v, err, shared := dao.loading.Do(key, fn)
+	if shared {
+		cacheMtc.WithLabelValues("shared").Inc()
+	}
+	return v, err
+}
 
-	lruCachePut(dao.headerCache, h, header)
-	return header, nil
+// cacheHeader caches the header by both height and hash, so a lookup by either key is served by the other
+func (dao *blockDAO) cacheHeader(header *block.Header) {
+	lruCachePut(dao.headerCache, header.Height(), header)
+	lruCachePut(dao.headerCache, header.HashHeader(), header)
 }
 
 func (dao *blockDAO) GetActionByActionHash(h hash.Hash256, height uint64) (action.SealedEnvelope, error) {
@@ -282,7 +422,7 @@ func (dao *blockDAO) GetActionByActionHash(h hash.Hash256, height uint64) (actio
 }
 
 func (dao *blockDAO) GetReceiptByActionHash(h hash.Hash256, height uint64) (*action.Receipt, error) {
-	receipts, err := dao.blockStore.GetReceipts(height)
+	receipts, err := dao.getReceipts(height)
 	if err != nil {
 		return nil, err
 	}
@@ -297,9 +437,39 @@ func (dao *blockDAO) GetReceiptByActionHash(h hash.Hash256, height uint64) (*act
 func (dao *blockDAO) GetReceipts(height uint64) ([]*action.Receipt, error) {
 	timer := dao.timerFactory.NewTimer("get_receipt")
 	defer timer.End()
+	return dao.getReceipts(height)
+}
+
+func (dao *blockDAO) getReceipts(height uint64) ([]*action.Receipt, error) {
+	if dao.receiptStore != nil {
+		return dao.receiptStore.Get(height)
+	}
 	return dao.blockStore.GetReceipts(height)
 }
 
+func (dao *blockDAO) BlockStoresInRange(start, end uint64) (filedao.BlockStoreIterator, error) {
+	return dao.blockStore.BlockStoresInRange(start, end)
+}
+
+// BlocksInRange returns the iterator of the blocks from start to end height, which reads the blocks sequentially from
+// the block store, bypassing the caches
+func (dao *blockDAO) BlocksInRange(start, end uint64) (BlockIterator, error) {
+	iter, err := dao.blockStore.BlockStoresInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &blockIterator{iter: iter, receipts: dao.receiptStore}, nil
+}
+
+// ReceiptsInRange returns the iterator of the receipts of the blocks from start to end height
+func (dao *blockDAO) ReceiptsInRange(start, end uint64) (ReceiptIterator, error) {
+	iter, err := dao.blockStore.BlockStoresInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &receiptIterator{iter: iter, receipts: dao.receiptStore}, nil
+}
+
 func (dao *blockDAO) ContainsTransactionLog() bool {
 	return dao.blockStore.ContainsTransactionLog()
 }
@@ -310,6 +480,38 @@ func (dao *blockDAO) TransactionLogs(height uint64) (*iotextypes.TransactionLogs
 	return dao.blockStore.TransactionLogs(height)
 }
 
+// Integrity returns the results of the latest verification of the sealed chain db files
+func (dao *blockDAO) Integrity() []filedao.FileIntegrity {
+	if r, ok := dao.blockStore.(filedao.IntegrityReporter); ok {
+		return r.Integrity()
+	}
+	return nil
+}
+
+// Backup writes a consistent copy of the block store, the receipt store and the commit journal into the directory. The
+// journal is backed up before the block store, which is backed up before the receipt store, so the pending commit in the
+// journal is at most one block above the block store, whose blocks all have their receipts.
+func (dao *blockDAO) Backup(ctx context.Context, dir string) error {
+	var stores []interface{}
+	if dao.journal != nil {
+		stores = append(stores, dao.journal.kvStore)
+	}
+	stores = append(stores, dao.blockStore)
+	if dao.receiptStore != nil {
+		stores = append(stores, dao.receiptStore.kvStore)
+	}
+	for _, store := range stores {
+		b, ok := store.(db.Backuper)
+		if !ok {
+			return errors.Wrapf(filedao.ErrNotSupported, "%T cannot be backed up", store)
+		}
+		if err := b.Backup(ctx, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (dao *blockDAO) PutBlock(ctx context.Context, blk *block.Block) error {
 	timer := dao.timerFactory.NewTimer("put_block")
 	defer timer.End()
@@ -319,13 +521,17 @@ func (dao *blockDAO) PutBlock(ctx context.Context, blk *block.Block) error {
 	if err != nil {
 		return err
 	}
-	if err := dao.blockStore.PutBlock(ctx, blk); err != nil {
+	if dao.journal != nil {
+		if err := dao.journal.Begin(blk); err != nil {
+			return err
+		}
+	}
+	if err := dao.putBlockStore(ctx, blk); err != nil {
 		return err
 	}
 	atomic.StoreUint64(&dao.tipHeight, blk.Height())
 	header := blk.Header
-	lruCachePut(dao.headerCache, blk.Height(), &header)
-	lruCachePut(dao.headerCache, header.HashHeader(), &header)
+	dao.cacheHeader(&header)
 
 	// index the block if there's indexer
 	for _, indexer := range dao.indexers {
@@ -333,13 +539,39 @@ func (dao *blockDAO) PutBlock(ctx context.Context, blk *block.Block) error {
 			return err
 		}
 	}
+	if dao.journal != nil {
+		return dao.journal.End()
+	}
 	return nil
 }
 
+func (dao *blockDAO) putBlockStore(ctx context.Context, blk *block.Block) error {
+	if dao.receiptStore == nil {
+		return dao.blockStore.PutBlock(ctx, blk)
+	}
+	// the receipts are stored in the receipt db only
+	stored := *blk
+	stored.Receipts = nil
+	if err := dao.blockStore.PutBlock(ctx, &stored); err != nil {
+		return err
+	}
+	return dao.receiptStore.Put(blk.Height(), blk.Receipts)
+}
+
 func (dao *blockDAO) DeleteTipBlock() error {
 	timer := dao.timerFactory.NewTimer("del_block")
 	defer timer.End()
-	return dao.blockStore.DeleteTipBlock()
+	if dao.receiptStore == nil {
+		return dao.blockStore.DeleteTipBlock()
+	}
+	tipHeight, err := dao.blockStore.Height()
+	if err != nil {
+		return err
+	}
+	if err := dao.blockStore.DeleteTipBlock(); err != nil {
+		return err
+	}
+	return dao.receiptStore.Delete(tipHeight)
 }
 
 func (dao *blockDAO) DeleteBlockToTarget(targetHeight uint64) error {
@@ -362,6 +594,11 @@ func (dao *blockDAO) DeleteBlockToTarget(targetHeight uint64) error {
 		if err := dao.blockStore.DeleteTipBlock(); err != nil {
 			return err
 		}
+		if dao.receiptStore != nil {
+			if err := dao.receiptStore.Delete(tipHeight); err != nil {
+				return err
+			}
+		}
 		// purge from cache
 		h := blk.HashBlock()
 		lruCacheDel(dao.headerCache, tipHeight)
@@ -409,6 +646,18 @@ func createBlockDAO(blkStore filedao.FileDAO, indexers []BlockIndexer, cfg confi
 	return blockDAO
 }
 
+// withReceiptStore stores the receipts in the receipt store instead of the block store
+func (dao *blockDAO) withReceiptStore(rs *receiptStore) {
+	dao.receiptStore = rs
+	dao.lifecycle.Add(rs)
+}
+
+// withCommitJournal journals the block commit, so the block store and the indexers are kept consistent after a crash
+func (dao *blockDAO) withCommitJournal(j *commitJournal) {
+	dao.journal = j
+	dao.lifecycle.Add(j)
+}
+
 func lruCacheGet(c *cache.ThreadSafeLruCache, key interface{}) (interface{}, bool) {
 	if c != nil {
 		return c.Get(key)
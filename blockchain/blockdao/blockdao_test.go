@@ -3,8 +3,13 @@ package blockdao
 import (
 	"context"
 	"hash/fnv"
+	"io"
+	"io/ioutil"
 	"math/big"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -240,6 +245,33 @@ func TestBlockDAO(t *testing.T) {
 		// commit an existing block
 		require.Equal(filedao.ErrAlreadyExist, dao.PutBlock(ctx, blks[2]))
 
+		// test range iterators
+		blkIter, err := dao.BlocksInRange(1, 3)
+		require.NoError(err)
+		receiptIter, err := dao.ReceiptsInRange(2, 3)
+		require.NoError(err)
+		for i := 0; i < 3; i++ {
+			blk, err := blkIter.Next()
+			require.NoError(err)
+			require.Equal(blks[i].HashBlock(), blk.HashBlock())
+			require.Equal(len(receipts[i]), len(blk.Receipts))
+			if i == 0 {
+				continue
+			}
+			r, err := receiptIter.Next()
+			require.NoError(err)
+			require.Equal(len(receipts[i]), len(r))
+			for j := range receipts[i] {
+				require.Equal(receipts[i][j].Hash(), r[j].Hash())
+			}
+		}
+		_, err = blkIter.Next()
+		require.Equal(io.EOF, err)
+		_, err = receiptIter.Next()
+		require.Equal(io.EOF, err)
+		_, err = dao.BlocksInRange(1, 5)
+		require.Equal(db.ErrNotExist, errors.Cause(err))
+
 		// check non-exist block
 		h, err := dao.GetBlockHash(5)
 		require.Equal(db.ErrNotExist, errors.Cause(err))
@@ -373,22 +405,32 @@ func TestBlockDAO(t *testing.T) {
 		testutil.CleanupPath(t, testPath)
 	}()
 
+	receiptPath, err := testutil.PathOfTempFile("test-receipt-store")
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, receiptPath)
+	}()
+
 	daoList := []struct {
 		inMemory, legacy bool
 		compressBlock    string
+		receiptDbPath    string
 	}{
-		{true, false, ""},
-		{false, true, ""},
-		{false, true, compress.Gzip},
-		{false, false, ""},
-		{false, false, compress.Gzip},
-		{false, false, compress.Snappy},
+		{true, false, "", ""},
+		{false, true, "", ""},
+		{false, true, compress.Gzip, ""},
+		{false, false, "", ""},
+		{false, false, compress.Gzip, ""},
+		{false, false, compress.Snappy, ""},
+		{false, false, "", receiptPath},
 	}
 
 	cfg := config.Default.DB
 	cfg.DbPath = testPath
 	for _, v := range daoList {
 		testutil.CleanupPath(t, testPath)
+		testutil.CleanupPath(t, receiptPath)
+		cfg.Receipt.DbPath = v.receiptDbPath
 		dao, err := createTestBlockDAO(v.inMemory, v.legacy, v.compressBlock, cfg)
 		require.NoError(err)
 		require.NotNil(dao)
@@ -399,6 +441,8 @@ func TestBlockDAO(t *testing.T) {
 
 	for _, v := range daoList {
 		testutil.CleanupPath(t, testPath)
+		testutil.CleanupPath(t, receiptPath)
+		cfg.Receipt.DbPath = v.receiptDbPath
 		dao, err := createTestBlockDAO(v.inMemory, v.legacy, v.compressBlock, cfg)
 		require.NoError(err)
 		require.NotNil(dao)
@@ -485,3 +529,118 @@ func BenchmarkBlockCache(b *testing.B) {
 		test(0, b)
 	})
 }
+
+type countingFileDAO struct {
+	filedao.FileDAO
+	headerReads int32
+}
+
+func (fd *countingFileDAO) HeaderByHeight(height uint64) (*block.Header, error) {
+	atomic.AddInt32(&fd.headerReads, 1)
+	return fd.FileDAO.HeaderByHeight(height)
+}
+
+func (fd *countingFileDAO) Header(h hash.Hash256) (*block.Header, error) {
+	atomic.AddInt32(&fd.headerReads, 1)
+	return fd.FileDAO.Header(h)
+}
+
+func TestBlockDAOHeaderCache(t *testing.T) {
+	require := require.New(t)
+	ctx := protocol.WithBlockchainCtx(
+		context.Background(),
+		protocol.BlockchainCtx{
+			Genesis: config.Default.Genesis,
+		},
+	)
+	fd, err := filedao.NewFileDAOInMemForTest()
+	require.NoError(err)
+	store := &countingFileDAO{FileDAO: fd}
+	dao := createBlockDAO(store, nil, config.DB{MaxCacheSize: 16})
+	require.NoError(dao.Start(ctx))
+	defer func() {
+		require.NoError(dao.Stop(ctx))
+	}()
+	blks := getTestBlocks(t)
+	for _, blk := range blks {
+		require.NoError(fd.PutBlock(ctx, blk))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			header, err := dao.HeaderByHeight(2)
+			require.NoError(err)
+			require.Equal(blks[1].HashBlock(), header.HashHeader())
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(1, atomic.LoadInt32(&store.headerReads))
+
+	// the header loaded by height is served by hash, and vice versa
+	header, err := dao.Header(blks[1].HashBlock())
+	require.NoError(err)
+	require.Equal(uint64(2), header.Height())
+	header, err = dao.Header(blks[2].HashBlock())
+	require.NoError(err)
+	require.Equal(uint64(3), header.Height())
+	header, err = dao.HeaderByHeight(3)
+	require.NoError(err)
+	require.Equal(blks[2].HashBlock(), header.HashHeader())
+	require.EqualValues(2, atomic.LoadInt32(&store.headerReads))
+}
+
+func TestBlockDAOBackup(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "test-blockdao-backup")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, dir)
+	backupDir := filepath.Join(dir, "backup")
+	require.NoError(os.Mkdir(backupDir, 0700))
+	ctx := protocol.WithBlockchainCtx(
+		context.Background(),
+		protocol.BlockchainCtx{
+			Genesis: config.Default.Genesis,
+		},
+	)
+
+	cfg := config.Default.DB
+	cfg.DbPath = filepath.Join(dir, "chain.db")
+	cfg.Receipt.DbPath = filepath.Join(dir, "receipt.db")
+	cfg.CommitJournalPath = filepath.Join(dir, "journal.db")
+	blks := getTestBlocks(t)
+	dao := NewBlockDAO(nil, cfg)
+	require.NoError(dao.Start(ctx))
+	for _, blk := range blks[:2] {
+		require.NoError(dao.PutBlock(ctx, blk))
+	}
+	require.NoError(dao.(*blockDAO).Backup(ctx, backupDir))
+	require.NoError(dao.PutBlock(ctx, blks[2]))
+	require.NoError(dao.Stop(ctx))
+
+	cfg.DbPath = filepath.Join(backupDir, "chain.db")
+	cfg.Receipt.DbPath = filepath.Join(backupDir, "receipt.db")
+	cfg.CommitJournalPath = filepath.Join(backupDir, "journal.db")
+	dao = NewBlockDAO(nil, cfg)
+	require.NoError(dao.Start(ctx))
+	defer func() {
+		require.NoError(dao.Stop(ctx))
+	}()
+	height, err := dao.Height()
+	require.NoError(err)
+	require.EqualValues(2, height)
+	for _, blk := range blks[:2] {
+		h, err := dao.GetBlockHash(blk.Height())
+		require.NoError(err)
+		require.Equal(blk.HashBlock(), h)
+		receipts, err := dao.GetReceipts(blk.Height())
+		require.NoError(err)
+		require.Equal(len(blk.Receipts), len(receipts))
+	}
+
+	// the in-memory block store cannot be backed up
+	inMem := NewBlockDAOInMemForTest(nil)
+	require.Equal(filedao.ErrNotSupported, errors.Cause(inMem.(*blockDAO).Backup(ctx, backupDir)))
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+)
+
+const journalNS = "jnl"
+
+var pendingBlockKey = []byte("pending")
+
+// commitJournal is the write-ahead journal of the block commit. The block and its receipts are written to the journal
+// before the chain db and the indexers, and cleared after all of them have committed the block, so a commit
+// interrupted by a crash is completed on the next start, instead of leaving the chain db and the indexers diverged
+type commitJournal struct {
+	kvStore db.KVStore
+}
+
+func newCommitJournal(kvStore db.KVStore) *commitJournal {
+	return &commitJournal{kvStore: kvStore}
+}
+
+func (j *commitJournal) Start(ctx context.Context) error {
+	return j.kvStore.Start(ctx)
+}
+
+func (j *commitJournal) Stop(ctx context.Context) error {
+	return j.kvStore.Stop(ctx)
+}
+
+// Begin records the block about to be committed
+func (j *commitJournal) Begin(blk *block.Block) error {
+	value, err := (&block.Store{Block: blk, Receipts: blk.Receipts}).Serialize()
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(
+		j.kvStore.Put(journalNS, pendingBlockKey, value),
+		"failed to journal block %d", blk.Height(),
+	)
+}
+
+// End clears the journal after the block is committed
+func (j *commitJournal) End() error {
+	return errors.Wrap(j.kvStore.Delete(journalNS, pendingBlockKey), "failed to clear commit journal")
+}
+
+// Pending returns the block whose commit was not completed, or nil if there's none
+func (j *commitJournal) Pending() (*block.Block, error) {
+	value, err := j.kvStore.Get(journalNS, pendingBlockKey)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return nil, nil
+	default:
+		return nil, errors.Wrap(err, "failed to read commit journal")
+	}
+	store := &block.Store{}
+	if err := store.Deserialize(value); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize journaled block")
+	}
+	store.Block.Receipts = store.Receipts
+	return store.Block, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type heightIndexer struct {
+	height uint64
+}
+
+func (idx *heightIndexer) Start(context.Context) error { return nil }
+
+func (idx *heightIndexer) Stop(context.Context) error { return nil }
+
+func (idx *heightIndexer) Height() (uint64, error) { return idx.height, nil }
+
+func (idx *heightIndexer) PutBlock(_ context.Context, blk *block.Block) error {
+	idx.height = blk.Height()
+	return nil
+}
+
+func (idx *heightIndexer) DeleteTipBlock(blk *block.Block) error {
+	idx.height = blk.Height() - 1
+	return nil
+}
+
+func TestCommitJournal(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	j := newCommitJournal(db.NewMemKVStore())
+	r.NoError(j.Start(ctx))
+	blk, err := j.Pending()
+	r.NoError(err)
+	r.Nil(blk)
+
+	blks := getTestBlocks(t)
+	r.NoError(j.Begin(blks[0]))
+	blk, err = j.Pending()
+	r.NoError(err)
+	r.Equal(blks[0].HashBlock(), blk.HashBlock())
+	r.NoError(j.End())
+	blk, err = j.Pending()
+	r.NoError(err)
+	r.Nil(blk)
+	r.NoError(j.Stop(ctx))
+}
+
+func TestBlockDAORecoverPendingBlock(t *testing.T) {
+	r := require.New(t)
+	testPath, err := testutil.PathOfTempFile("test-journal-chain")
+	r.NoError(err)
+	journalPath, err := testutil.PathOfTempFile("test-journal")
+	r.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, testPath)
+		testutil.CleanupPath(t, journalPath)
+	}()
+	testutil.CleanupPath(t, testPath)
+	testutil.CleanupPath(t, journalPath)
+
+	cfg := config.Default.DB
+	cfg.DbPath = testPath
+	cfg.CommitJournalPath = journalPath
+	ctx := protocol.WithBlockchainCtx(
+		context.Background(),
+		protocol.BlockchainCtx{
+			Genesis: config.Default.Genesis,
+		},
+	)
+	blks := getTestBlocks(t)
+	indexer := &heightIndexer{}
+	dao := NewBlockDAO([]BlockIndexer{indexer}, cfg)
+	r.NoError(dao.Start(ctx))
+	r.NoError(dao.PutBlock(ctx, blks[0]))
+	r.NoError(dao.Stop(ctx))
+
+	// crash after the journal and the indexer committed block 2, but before the block store did
+	journalCfg := cfg
+	journalCfg.DbPath = journalPath
+	j := newCommitJournal(db.NewBoltDB(journalCfg))
+	r.NoError(j.Start(ctx))
+	r.NoError(j.Begin(blks[1]))
+	r.NoError(j.Stop(ctx))
+	indexer.height = 2
+
+	dao = NewBlockDAO([]BlockIndexer{indexer}, cfg)
+	r.NoError(dao.Start(ctx))
+	height, err := dao.Height()
+	r.NoError(err)
+	r.Equal(uint64(2), height)
+	h, err := dao.GetBlockHash(2)
+	r.NoError(err)
+	r.Equal(blks[1].HashBlock(), h)
+	r.Equal(uint64(2), indexer.height)
+	blk, err := dao.(*blockDAO).journal.Pending()
+	r.NoError(err)
+	r.Nil(blk)
+
+	// crash after the journal committed block 3, before the block store and the indexer did
+	r.NoError(dao.(*blockDAO).journal.Begin(blks[2]))
+	r.NoError(dao.Stop(ctx))
+	dao = NewBlockDAO([]BlockIndexer{indexer}, cfg)
+	r.NoError(dao.Start(ctx))
+	height, err = dao.Height()
+	r.NoError(err)
+	r.Equal(uint64(3), height)
+	r.Equal(uint64(3), indexer.height)
+	r.NoError(dao.Stop(ctx))
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	receiptNS     = "rcp"
+	receiptMetaNS = "rcm"
+	// maxPruneReceipts bounds the number of heights pruned at a time, so enabling the receipt retention on a long chain
+	// catches up gradually instead of stalling the block commit
+	maxPruneReceipts = 1024
+)
+
+var receiptPrunedHeightKey = []byte("ph")
+
+// receiptStore keeps the receipts in their own db file, apart from the chain db, so they are pruned with a retention
+// independent of the blocks
+type receiptStore struct {
+	kvStore   db.KVStore
+	retention uint64
+	pruned    uint64
+}
+
+func newReceiptStore(kvStore db.KVStore, retention uint64) *receiptStore {
+	return &receiptStore{
+		kvStore:   kvStore,
+		retention: retention,
+	}
+}
+
+func (rs *receiptStore) Start(ctx context.Context) error {
+	if err := rs.kvStore.Start(ctx); err != nil {
+		return err
+	}
+	value, err := rs.kvStore.Get(receiptMetaNS, receiptPrunedHeightKey)
+	switch errors.Cause(err) {
+	case nil:
+		atomic.StoreUint64(&rs.pruned, byteutil.BytesToUint64BigEndian(value))
+	case db.ErrNotExist, db.ErrBucketNotExist:
+	default:
+		return errors.Wrap(err, "failed to get pruned height of receipts")
+	}
+	return nil
+}
+
+func (rs *receiptStore) Stop(ctx context.Context) error {
+	return rs.kvStore.Stop(ctx)
+}
+
+// Put writes the receipts of the block at height, and prunes the receipts out of the retention
+func (rs *receiptStore) Put(height uint64, receipts []*action.Receipt) error {
+	pb := &iotextypes.Receipts{}
+	for _, r := range receipts {
+		pb.Receipts = append(pb.Receipts, r.ConvertToReceiptPb())
+	}
+	value, err := proto.Marshal(pb)
+	if err != nil {
+		return err
+	}
+	if err := rs.kvStore.Put(receiptNS, byteutil.Uint64ToBytesBigEndian(height), value); err != nil {
+		return errors.Wrapf(err, "failed to put receipts at height %d", height)
+	}
+	if rs.retention > 0 && height > rs.retention {
+		if err := rs.prune(height - rs.retention); err != nil {
+			// the block is committed, failing to prune only delays the pruning to the next block
+			log.L().Warn("Failed to prune receipts.", zap.Uint64("height", height-rs.retention), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Get returns the receipts of the block at height
+func (rs *receiptStore) Get(height uint64) ([]*action.Receipt, error) {
+	if height <= atomic.LoadUint64(&rs.pruned) {
+		return nil, errors.Wrapf(filedao.ErrPruned, "failed to get receipts at height %d", height)
+	}
+	value, err := rs.kvStore.Get(receiptNS, byteutil.Uint64ToBytesBigEndian(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get receipts at height %d", height)
+	}
+	pb := &iotextypes.Receipts{}
+	if err := proto.Unmarshal(value, pb); err != nil {
+		return nil, err
+	}
+	receipts := make([]*action.Receipt, 0, len(pb.Receipts))
+	for _, receiptPb := range pb.Receipts {
+		r := &action.Receipt{}
+		r.ConvertFromReceiptPb(receiptPb)
+		receipts = append(receipts, r)
+	}
+	return receipts, nil
+}
+
+// Delete removes the receipts of the block at height
+func (rs *receiptStore) Delete(height uint64) error {
+	return rs.kvStore.Delete(receiptNS, byteutil.Uint64ToBytesBigEndian(height))
+}
+
+// prune removes the receipts at or below the height
+func (rs *receiptStore) prune(height uint64) error {
+	pruned := atomic.LoadUint64(&rs.pruned)
+	if height <= pruned {
+		return nil
+	}
+	if height-pruned > maxPruneReceipts {
+		height = pruned + maxPruneReceipts
+	}
+	b := batch.NewBatch()
+	for h := pruned + 1; h <= height; h++ {
+		b.Delete(receiptNS, byteutil.Uint64ToBytesBigEndian(h), "failed to delete receipts")
+	}
+	b.Put(receiptMetaNS, receiptPrunedHeightKey, byteutil.Uint64ToBytesBigEndian(height), "failed to put pruned height")
+	// reads of the pruned heights are rejected before the receipts are deleted
+	atomic.StoreUint64(&rs.pruned, height)
+	if err := rs.kvStore.WriteBatch(b); err != nil {
+		atomic.StoreUint64(&rs.pruned, pruned)
+		return errors.Wrapf(err, "failed to prune receipts up to height %d", height)
+	}
+	return nil
+}
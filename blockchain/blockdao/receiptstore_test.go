@@ -0,0 +1,56 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/db"
+)
+
+func TestReceiptStore(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	kv := db.NewMemKVStore()
+	rs := newReceiptStore(kv, 2)
+	r.NoError(rs.Start(ctx))
+
+	for h := uint64(1); h <= 4; h++ {
+		r.NoError(rs.Put(h, []*action.Receipt{
+			{Status: 1, BlockHeight: h, ActionHash: hash.Hash256b([]byte{byte(h)}), GasConsumed: h},
+		}))
+	}
+	for h := uint64(1); h <= 2; h++ {
+		_, err := rs.Get(h)
+		r.Equal(filedao.ErrPruned, errors.Cause(err))
+	}
+	for h := uint64(3); h <= 4; h++ {
+		receipts, err := rs.Get(h)
+		r.NoError(err)
+		r.Len(receipts, 1)
+		r.Equal(h, receipts[0].BlockHeight)
+		r.Equal(hash.Hash256b([]byte{byte(h)}), receipts[0].ActionHash)
+	}
+	r.NoError(rs.Delete(4))
+	_, err := rs.Get(4)
+	r.Equal(db.ErrNotExist, errors.Cause(err))
+
+	// the pruned height is restored on restart
+	r.NoError(rs.Stop(ctx))
+	rs = newReceiptStore(kv, 2)
+	r.NoError(rs.Start(ctx))
+	_, err = rs.Get(2)
+	r.Equal(filedao.ErrPruned, errors.Cause(err))
+}
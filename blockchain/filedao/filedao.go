@@ -42,6 +42,7 @@ var (
 	ErrAlreadyExist     = errors.New("block already exist")
 	ErrInvalidTipHeight = errors.New("invalid tip height")
 	ErrDataCorruption   = errors.New("data is corrupted")
+	ErrPruned           = errors.New("block data is pruned")
 )
 
 type (
@@ -67,6 +68,7 @@ type (
 		Header(hash.Hash256) (*block.Header, error)
 		HeaderByHeight(uint64) (*block.Header, error)
 		FooterByHeight(uint64) (*block.Footer, error)
+		BlockStoresInRange(uint64, uint64) (BlockStoreIterator, error)
 	}
 
 	// fileDAO implements FileDAO
@@ -93,21 +95,33 @@ func NewFileDAO(cfg config.DB) (FileDAO, error) {
 		if err := createNewV2File(1, cfg); err != nil {
 			return nil, err
 		}
-		header = &FileHeader{Version: FileV2}
+		header = &FileHeader{Version: FileV3}
 	}
 
 	switch header.Version {
 	case FileLegacyMaster:
 		// master file is legacy format
 		return CreateFileDAO(true, cfg)
-	case FileV2:
-		// master file is v2 format
+	case FileV2, FileV3:
+		// master file is v2 or v3 format
 		return CreateFileDAO(false, cfg)
 	default:
 		panic(fmt.Errorf("corrupted file version: %s", header.Version))
 	}
 }
 
+// NewFileDAOFromHeight creates the chain db file whose first block is at the given height, for a node bootstrapped
+// from a state snapshot instead of the genesis block
+func NewFileDAOFromHeight(height uint64, cfg config.DB) (FileDAO, error) {
+	if _, err := checkMasterChainDBFile(cfg.DbPath); err != ErrFileNotExist {
+		return nil, errors.Wrapf(ErrAlreadyExist, "chain db file %s", cfg.DbPath)
+	}
+	if err := createNewV2File(height, cfg); err != nil {
+		return nil, err
+	}
+	return CreateFileDAO(false, cfg)
+}
+
 // NewFileDAOInMemForTest creates an in-memory FileDAO for testing
 func NewFileDAOInMemForTest() (FileDAO, error) {
 	return newTestInMemFd()
@@ -184,12 +198,12 @@ func (fd *fileDAO) GetBlockHeight(hash hash.Hash256) (uint64, error) {
 
 func (fd *fileDAO) GetBlock(hash hash.Hash256) (*block.Block, error) {
 	var (
-		blk *block.Block
-		err error
+		height uint64
+		err    error
 	)
 	if fd.v2Fd != nil {
-		if blk, err = fd.v2Fd.GetBlock(hash); err == nil {
-			return blk, nil
+		if height, err = fd.v2Fd.GetBlockHeight(hash); err == nil {
+			return fd.GetBlockByHeight(height)
 		}
 	}
 
@@ -201,7 +215,11 @@ func (fd *fileDAO) GetBlock(hash hash.Hash256) (*block.Block, error) {
 
 func (fd *fileDAO) GetBlockByHeight(height uint64) (*block.Block, error) {
 	if fd.v2Fd != nil {
-		if v2 := fd.v2Fd.FileDAOByHeight(height); v2 != nil {
+		v2, err := fd.v2Fd.DataFileByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if v2 != nil {
 			return v2.GetBlockByHeight(height)
 		}
 	}
@@ -214,12 +232,12 @@ func (fd *fileDAO) GetBlockByHeight(height uint64) (*block.Block, error) {
 
 func (fd *fileDAO) Header(hash hash.Hash256) (*block.Header, error) {
 	var (
-		blk *block.Block
-		err error
+		height uint64
+		err    error
 	)
 	if fd.v2Fd != nil {
-		if blk, err = fd.v2Fd.GetBlock(hash); err == nil {
-			return &blk.Header, nil
+		if height, err = fd.v2Fd.GetBlockHeight(hash); err == nil {
+			return fd.HeaderByHeight(height)
 		}
 	}
 
@@ -231,12 +249,12 @@ func (fd *fileDAO) Header(hash hash.Hash256) (*block.Header, error) {
 
 func (fd *fileDAO) HeaderByHeight(height uint64) (*block.Header, error) {
 	if fd.v2Fd != nil {
-		if v2 := fd.v2Fd.FileDAOByHeight(height); v2 != nil {
-			blk, err := v2.GetBlockByHeight(height)
-			if err != nil {
-				return nil, err
-			}
-			return &blk.Header, nil
+		v2, err := fd.v2Fd.DataFileByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if v2 != nil {
+			return v2.HeaderByHeight(height)
 		}
 	}
 
@@ -248,12 +266,12 @@ func (fd *fileDAO) HeaderByHeight(height uint64) (*block.Header, error) {
 
 func (fd *fileDAO) FooterByHeight(height uint64) (*block.Footer, error) {
 	if fd.v2Fd != nil {
-		if v2 := fd.v2Fd.FileDAOByHeight(height); v2 != nil {
-			blk, err := v2.GetBlockByHeight(height)
-			if err != nil {
-				return nil, err
-			}
-			return &blk.Footer, nil
+		v2, err := fd.v2Fd.DataFileByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if v2 != nil {
+			return v2.FooterByHeight(height)
 		}
 	}
 
@@ -265,7 +283,11 @@ func (fd *fileDAO) FooterByHeight(height uint64) (*block.Footer, error) {
 
 func (fd *fileDAO) GetReceipts(height uint64) ([]*action.Receipt, error) {
 	if fd.v2Fd != nil {
-		if v2 := fd.v2Fd.FileDAOByHeight(height); v2 != nil {
+		v2, err := fd.v2Fd.DataFileByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if v2 != nil {
 			return v2.GetReceipts(height)
 		}
 	}
@@ -283,7 +305,11 @@ func (fd *fileDAO) ContainsTransactionLog() bool {
 
 func (fd *fileDAO) TransactionLogs(height uint64) (*iotextypes.TransactionLogs, error) {
 	if fd.v2Fd != nil {
-		if v2 := fd.v2Fd.FileDAOByHeight(height); v2 != nil {
+		v2, err := fd.v2Fd.DataFileByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if v2 != nil {
 			return v2.TransactionLogs(height)
 		}
 	}
@@ -308,7 +334,35 @@ func (fd *fileDAO) PutBlock(ctx context.Context, blk *block.Block) error {
 			return err
 		}
 	}
-	return fd.currFd.PutBlock(ctx, blk)
+	if err := fd.currFd.PutBlock(ctx, blk); err != nil {
+		return err
+	}
+
+	if fd.cfg.BlockRetention > 0 && blk.Height() > fd.cfg.BlockRetention {
+		fd.prune(blk.Height() - fd.cfg.BlockRetention)
+	}
+	if fd.v2Fd != nil && fd.v2Fd.cold != nil {
+		fd.v2Fd.moveFiles(blk.Height())
+	}
+	return nil
+}
+
+// prune removes the block bodies, receipts and transaction logs at or below the given height from the v2 files. The
+// headers and footers are kept, and the legacy file is never pruned.
+func (fd *fileDAO) prune(height uint64) {
+	if fd.v2Fd == nil {
+		return
+	}
+	for _, v := range fd.v2Fd.Indices {
+		if v.start > height {
+			break
+		}
+		if err := v.fd.prune(height); err != nil {
+			// the block is committed, failing to prune only delays the pruning to the next block
+			log.L().Warn("Failed to prune blocks.", zap.Uint64("height", height), zap.Error(err))
+			return
+		}
+	}
 }
 
 func (fd *fileDAO) prepNextDbFile(height uint64) error {
@@ -358,10 +412,26 @@ func (fd *fileDAO) addNewV2File(height uint64) error {
 
 	// create v2 manager
 	fd.v2Fd, _ = newFileV2Manager([]*fileDAOv2{v2})
+	if fd.cfg.ColdStorage.URL != "" {
+		if err = fd.v2Fd.enableColdStorage(fd.cfg); err != nil {
+			return err
+		}
+	}
+	if fd.cfg.Integrity.Interval > 0 {
+		fd.v2Fd.enableVerifier(fd.cfg)
+	}
 	err = fd.v2Fd.Start(ctx)
 	return err
 }
 
+// Integrity returns the results of the latest verification of the sealed v2 files
+func (fd *fileDAO) Integrity() []FileIntegrity {
+	if fd.v2Fd == nil {
+		return nil
+	}
+	return fd.v2Fd.Integrity()
+}
+
 func (fd *fileDAO) DeleteTipBlock() error {
 	return fd.currFd.DeleteTipBlock()
 }
@@ -402,6 +472,14 @@ func CreateFileDAO(legacy bool, cfg config.DB) (FileDAO, error) {
 	if err != nil {
 		return nil, err
 	}
+	if fd.cfg.ColdStorage.URL != "" {
+		if err := v2Fd.enableColdStorage(fd.cfg); err != nil {
+			return nil, err
+		}
+	}
+	if fd.cfg.Integrity.Interval > 0 {
+		v2Fd.enableVerifier(fd.cfg)
+	}
 	fd.v2Fd = v2Fd
 	return &fd, nil
 }
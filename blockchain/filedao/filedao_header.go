@@ -21,6 +21,7 @@ const (
 	FileLegacyMaster    = "V1-master"
 	FileLegacyAuxiliary = "V1-aux"
 	FileV2              = "V2"
+	FileV3              = "V3"
 	FileAll             = "All"
 )
 
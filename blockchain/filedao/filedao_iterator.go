@@ -0,0 +1,216 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+)
+
+type (
+	// BlockStoreIterator iterates the blocks with their receipts in ascending order of height
+	BlockStoreIterator interface {
+		// Next returns the next block with its receipts, or io.EOF after the last one
+		Next() (*block.Store, error)
+	}
+
+	// heightIterator reads the blocks one height at a time
+	heightIterator struct {
+		fd        BaseFileDAO
+		next, end uint64
+	}
+
+	// v2Iterator reads the blocks of a v2 file sequentially, one block store at a time
+	v2Iterator struct {
+		fd        *fileDAOv2
+		next, end uint64
+		storeKey  uint64
+		pbStores  *iotextypes.BlockStores
+	}
+
+	// fileIterator reads the blocks across the files, from the iterator of the file holding the next height
+	fileIterator struct {
+		fd        *fileDAO
+		next, end uint64
+		curr      BlockStoreIterator
+	}
+)
+
+func checkRange(start, end, tip uint64) error {
+	if start == 0 || start > end {
+		return errors.Wrapf(db.ErrInvalid, "invalid range [%d, %d]", start, end)
+	}
+	if end > tip {
+		return errors.Wrapf(db.ErrNotExist, "range [%d, %d] is above the tip height %d", start, end, tip)
+	}
+	return nil
+}
+
+func (it *heightIterator) Next() (*block.Store, error) {
+	if it.next > it.end {
+		return nil, io.EOF
+	}
+	blk, err := it.fd.GetBlockByHeight(it.next)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := it.fd.GetReceipts(it.next)
+	if err != nil {
+		return nil, err
+	}
+	it.next++
+	return &block.Store{Block: blk, Receipts: receipts}, nil
+}
+
+func (it *v2Iterator) Next() (*block.Store, error) {
+	if it.next > it.end {
+		return nil, io.EOF
+	}
+	fd := it.fd
+	if fd.isPruned(it.next) {
+		return nil, errors.Wrapf(ErrPruned, "failed to get block at height %d", it.next)
+	}
+
+	var (
+		storeKey = blockStoreKey(it.next, fd.header)
+		store    *block.Store
+		err      error
+	)
+	if storeKey >= fd.blkStore.Size() {
+		store, err = fd.blkBuffer.Get(stagingKey(it.next, fd.header))
+	} else {
+		if it.pbStores == nil || it.storeKey != storeKey {
+			if err = it.load(storeKey); err != nil {
+				return nil, errors.Wrapf(err, "failed to get block at height %d", it.next)
+			}
+		}
+		store, err = extractBlockStore(it.pbStores, stagingKey(it.next, fd.header))
+	}
+	if err != nil {
+		return nil, err
+	}
+	it.next++
+	return store, nil
+}
+
+// load reads the block store, without adding it to the read cache of the file
+func (it *v2Iterator) load(storeKey uint64) error {
+	fd := it.fd
+	if value, ok := fd.blkCache.Get(storeKey); ok {
+		it.storeKey, it.pbStores = storeKey, value.(*iotextypes.BlockStores)
+		return nil
+	}
+	value, err := fd.blkStore.Get(storeKey)
+	if err != nil {
+		return err
+	}
+	if value, err = fd.decompress(value); err != nil {
+		return err
+	}
+	pbStores, err := block.DeserializeBlockStoresPb(value)
+	if err != nil {
+		return err
+	}
+	if len(pbStores.BlockStores) != int(fd.header.BlockStoreSize) {
+		return ErrDataCorruption
+	}
+	it.storeKey, it.pbStores = storeKey, pbStores
+	return nil
+}
+
+func (it *fileIterator) Next() (*block.Store, error) {
+	for it.next <= it.end {
+		if it.curr == nil {
+			curr, err := it.fd.fileIterator(it.next, it.end)
+			if err != nil {
+				return nil, err
+			}
+			it.curr = curr
+		}
+		store, err := it.curr.Next()
+		if err == io.EOF {
+			// continue with the next file
+			it.curr = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		it.next = store.Block.Height() + 1
+		return store, nil
+	}
+	return nil, io.EOF
+}
+
+// fileIterator returns the iterator of the file holding the start height, up to the end height or the last height of
+// the file
+func (fd *fileDAO) fileIterator(start, end uint64) (BlockStoreIterator, error) {
+	if fd.v2Fd != nil {
+		v2, err := fd.v2Fd.DataFileByHeight(start)
+		if err != nil {
+			return nil, err
+		}
+		if v2 != nil {
+			if tip := v2.loadTip().Height; end > tip {
+				end = tip
+			}
+			return &v2Iterator{fd: v2, next: start, end: end}, nil
+		}
+	}
+
+	if fd.legacyFd != nil {
+		if fd.v2Fd != nil {
+			if bottom := fd.v2Fd.bottom(); end >= bottom {
+				end = bottom - 1
+			}
+		}
+		return &heightIterator{fd: fd.legacyFd, next: start, end: end}, nil
+	}
+	return nil, ErrNotSupported
+}
+
+// BlockStoresInRange returns the iterator of the blocks from start to end height, which reads the blocks sequentially
+// from the files holding them
+func (fd *fileDAO) BlockStoresInRange(start, end uint64) (BlockStoreIterator, error) {
+	tip, err := fd.Height()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRange(start, end, tip); err != nil {
+		return nil, err
+	}
+	return &fileIterator{fd: fd, next: start, end: end}, nil
+}
+
+// BlockStoresInRange returns the iterator of the blocks from start to end height
+func (fd *fileDAOv2) BlockStoresInRange(start, end uint64) (BlockStoreIterator, error) {
+	if err := checkRange(start, end, fd.loadTip().Height); err != nil {
+		return nil, err
+	}
+	if start < fd.header.Start {
+		return nil, errors.Wrapf(db.ErrNotExist, "range [%d, %d] is below the file start %d", start, end, fd.header.Start)
+	}
+	return &v2Iterator{fd: fd, next: start, end: end}, nil
+}
+
+// BlockStoresInRange returns the iterator of the blocks from start to end height
+func (fd *fileDAOLegacy) BlockStoresInRange(start, end uint64) (BlockStoreIterator, error) {
+	tip, err := fd.Height()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRange(start, end, tip); err != nil {
+		return nil, err
+	}
+	return &heightIterator{fd: fd, next: start, end: end}, nil
+}
@@ -9,9 +9,13 @@ package filedao
 import (
 	"context"
 	"encoding/hex"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/go-pkgs/crypto"
@@ -117,8 +121,8 @@ func TestReadFileHeader(t *testing.T) {
 	test2 := []testCheckFile{
 		{FileLegacyMaster, "", ErrFileInvalid},
 		{FileLegacyAuxiliary, "", ErrFileInvalid},
-		{FileV2, FileV2, nil},
-		{FileAll, FileV2, nil},
+		{FileV2, FileV3, nil},
+		{FileAll, FileV3, nil},
 	}
 	for _, v := range test2 {
 		h, err = readFileHeader(cfg.DbPath, v.checkType)
@@ -149,7 +153,7 @@ func TestNewFileDAOSplitV2(t *testing.T) {
 	r.NotNil(fd)
 	h, err = readFileHeader(cfg.DbPath, FileAll)
 	r.NoError(err)
-	r.Equal(FileV2, h.Version)
+	r.Equal(FileV3, h.Version)
 	ctx := context.Background()
 	r.NoError(fd.Start(ctx))
 	fm := fd.(*fileDAO)
@@ -174,6 +178,8 @@ func TestNewFileDAOSplitV2(t *testing.T) {
 	r.EqualValues(2, fm.topIndex)
 	r.EqualValues(21, fm.splitHeight)
 	testVerifyChainDB(t, fd, 1, 25)
+	testVerifyBlockStoresInRange(t, fd, 1, 25)
+	testVerifyBlockStoresInRange(t, fd, 9, 21)
 	r.NoError(fd.Stop(ctx))
 	top, files := checkAuxFiles(cfg.DbPath, FileV2)
 	r.EqualValues(2, top)
@@ -186,6 +192,238 @@ func TestNewFileDAOSplitV2(t *testing.T) {
 	os.RemoveAll(file2)
 }
 
+func TestNewFileDAOPruned(t *testing.T) {
+	r := require.New(t)
+
+	cfg := config.Default.DB
+	cfg.V2BlocksToSplitDB = 40
+	cfg.BlockRetention = 16
+	cfg.DbPath = "./filedao_pruned.db"
+	defer os.RemoveAll(cfg.DbPath)
+	file1 := kthAuxFileName(cfg.DbPath, 1)
+	defer os.RemoveAll(file1)
+
+	ctx := context.Background()
+	fd, err := NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	r.NoError(testCommitBlocks(t, fd, 1, 70, hash.ZeroHash256))
+
+	testPruned := func(fd FileDAO) {
+		// the first file holds blocks 1 to 40, and the block stores up to 32 are pruned
+		for _, height := range []uint64{1, 10, 32} {
+			_, err := fd.GetBlockByHeight(height)
+			r.Equal(ErrPruned, errors.Cause(err))
+			_, err = fd.GetReceipts(height)
+			r.Equal(ErrPruned, errors.Cause(err))
+			_, err = fd.TransactionLogs(height)
+			r.Equal(ErrPruned, errors.Cause(err))
+			h, err := fd.GetBlockHash(height)
+			r.NoError(err)
+			_, err = fd.GetBlock(h)
+			r.Equal(ErrPruned, errors.Cause(err))
+
+			// headers and footers are kept
+			header, err := fd.HeaderByHeight(height)
+			r.NoError(err)
+			r.Equal(h, header.HashBlock())
+			header, err = fd.Header(h)
+			r.NoError(err)
+			r.Equal(height, header.Height())
+			_, err = fd.FooterByHeight(height)
+			r.NoError(err)
+		}
+		testVerifyChainDB(t, fd, 33, 70)
+		testVerifyBlockStoresInRange(t, fd, 33, 70)
+		iter, err := fd.BlockStoresInRange(30, 40)
+		r.NoError(err)
+		_, err = iter.Next()
+		r.Equal(ErrPruned, errors.Cause(err))
+	}
+	testPruned(fd)
+	r.NoError(fd.Stop(ctx))
+
+	// the pruned height is kept after restart
+	fd, err = NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	testPruned(fd)
+	r.NoError(fd.Stop(ctx))
+}
+
+func TestNewFileDAOIntegrity(t *testing.T) {
+	r := require.New(t)
+
+	cfg := config.Default.DB
+	cfg.V2BlocksToSplitDB = 40
+	cfg.BlockRetention = 16
+	cfg.Integrity.Interval = time.Hour
+	cfg.Integrity.CompactRatio = 1
+	cfg.DbPath = "./filedao_integrity.db"
+	defer os.RemoveAll(cfg.DbPath)
+	file1 := kthAuxFileName(cfg.DbPath, 1)
+	defer os.RemoveAll(file1)
+	defer func(size int64) { minCompactSize = size }(minCompactSize)
+	minCompactSize = 0
+
+	ctx := context.Background()
+	fd, err := NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	r.NoError(testCommitBlocks(t, fd, 1, 70, hash.ZeroHash256))
+
+	// the sealed file is verified, and rewritten since its pruned blocks leave it fragmented
+	fm := fd.(*fileDAO).v2Fd
+	sealed := fm.Indices[0].fd
+	fm.verifier.verifyAll()
+	results := fd.(IntegrityReporter).Integrity()
+	r.Equal(1, len(results))
+	r.Equal(cfg.DbPath, results[0].File)
+	r.EqualValues(1, results[0].Start)
+	r.EqualValues(40, results[0].End)
+	r.False(results[0].Corrupted)
+	r.NotEqual(sealed, fm.Indices[0].fd)
+	r.True(sealed.retired)
+	testVerifyChainDB(t, fd, 33, 70)
+
+	// the rewrite keeps the checksum, which detects the corruption of the block hashes
+	fm.verifier.verifyAll()
+	r.False(fd.(IntegrityReporter).Integrity()[0].Corrupted)
+	r.NoError(fm.Indices[0].fd.kvStore.Put(headerDataNs, checksumKey, []byte("corrupted")))
+	fm.verifier.verifyAll()
+	results = fd.(IntegrityReporter).Integrity()
+	r.True(results[0].Corrupted)
+	r.Contains(results[0].Error, "block hashes do not match their checksum")
+	r.NoError(fd.Stop(ctx))
+}
+
+func TestNewFileDAOColdStorage(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "filedao_cold")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+	cfg := config.Default.DB
+	cfg.V2BlocksToSplitDB = 10
+	cfg.DbPath = filepath.Join(dir, "chain.db")
+	cfg.ColdStorage.URL = "file://" + filepath.Join(dir, "bucket")
+	cfg.ColdStorage.Depth = 10
+	cfg.ColdStorage.CacheDir = filepath.Join(dir, "cache")
+	cfg.ColdStorage.CacheSize = 1
+
+	ctx := context.Background()
+	fd, err := NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	r.NoError(testCommitBlocks(t, fd, 1, 45, hash.ZeroHash256))
+	// a move is skipped while another one is ongoing, finish the remaining moves
+	fm := fd.(*fileDAO).v2Fd
+	for i := 0; i < 3; i++ {
+		fm.moveFiles(45)
+		fm.cold.wg.Wait()
+	}
+
+	// the files ending at least 10 blocks below the tip are moved
+	r.Equal(5, len(fm.Indices))
+	for i, v := range fm.Indices {
+		r.Equal(i < 3, v.fd.coldKey != "")
+	}
+	for _, name := range []string{"chain.db", "chain-00000001.db", "chain-00000002.db"} {
+		_, err = os.Stat(filepath.Join(dir, "bucket", name))
+		r.NoError(err)
+	}
+	testVerifyChainDB(t, fd, 1, 45)
+	testVerifyBlockStoresInRange(t, fd, 1, 45)
+	h, err := fd.GetBlockHash(5)
+	r.NoError(err)
+	blk, err := fd.GetBlock(h)
+	r.NoError(err)
+	r.EqualValues(5, blk.Height())
+	r.NoError(fd.Stop(ctx))
+
+	// the stubs are kept after restart, and the blocks are fetched from cold storage
+	r.NoError(os.RemoveAll(cfg.ColdStorage.CacheDir))
+	fd, err = NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	testVerifyChainDB(t, fd, 1, 45)
+	r.NoError(fd.Stop(ctx))
+
+	// the blocks cannot be read without cold storage
+	cfg.ColdStorage.URL = ""
+	fd, err = NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	_, err = fd.GetBlockByHeight(5)
+	r.Equal(ErrNotSupported, errors.Cause(err))
+	_, err = fd.GetBlockHash(5)
+	r.NoError(err)
+	r.NoError(fd.Stop(ctx))
+}
+
+func TestNewFileDAOBackup(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "filedao_backup")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+	backupDir := filepath.Join(dir, "backup")
+	r.NoError(os.Mkdir(backupDir, 0700))
+	cfg := config.Default.DB
+	cfg.V2BlocksToSplitDB = 10
+	cfg.DbPath = filepath.Join(dir, "chain.db")
+
+	ctx := context.Background()
+	fd, err := NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	r.NoError(testCommitBlocks(t, fd, 1, 25, hash.ZeroHash256))
+	r.NoError(fd.(*fileDAO).Backup(ctx, backupDir))
+	// the blocks committed after the backup are not in the backup
+	r.NoError(testCommitBlocks(t, fd, 26, 30, hash.ZeroHash256))
+	r.Error(fd.(*fileDAO).Backup(ctx, backupDir))
+	r.NoError(fd.Stop(ctx))
+
+	// the sealed files are hard-linked, and the top file is copied
+	for k, linked := range []bool{true, true, false} {
+		name := cfg.DbPath
+		if k > 0 {
+			name = kthAuxFileName(cfg.DbPath, uint64(k))
+		}
+		info, err := os.Stat(name)
+		r.NoError(err)
+		backup, err := os.Stat(filepath.Join(backupDir, filepath.Base(name)))
+		r.NoError(err)
+		r.Equal(linked, os.SameFile(info, backup))
+	}
+
+	cfg.DbPath = filepath.Join(backupDir, "chain.db")
+	fd, err = NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	height, err := fd.Height()
+	r.NoError(err)
+	r.EqualValues(25, height)
+	testVerifyChainDB(t, fd, 1, 25)
+	r.NoError(fd.Stop(ctx))
+
+	// the sealed files still written by the pruning are copied
+	cfg.DbPath = filepath.Join(dir, "pruned.db")
+	cfg.V2BlocksToSplitDB = 40
+	cfg.BlockRetention = 16
+	fd, err = NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	r.NoError(testCommitBlocks(t, fd, 1, 45, hash.ZeroHash256))
+	r.NoError(fd.(*fileDAO).Backup(ctx, backupDir))
+	r.NoError(fd.Stop(ctx))
+	info, err := os.Stat(cfg.DbPath)
+	r.NoError(err)
+	backup, err := os.Stat(filepath.Join(backupDir, "pruned.db"))
+	r.NoError(err)
+	r.False(os.SameFile(info, backup))
+}
+
 func TestNewFileDAOSplitLegacy(t *testing.T) {
 	r := require.New(t)
 
@@ -223,6 +461,8 @@ func TestNewFileDAOSplitLegacy(t *testing.T) {
 	r.EqualValues(4, fm.topIndex)
 	r.EqualValues(46, fm.splitHeight)
 	testVerifyChainDB(t, fd, 1, 55)
+	testVerifyBlockStoresInRange(t, fd, 1, 55)
+	testVerifyBlockStoresInRange(t, fd, 3, 20)
 	r.NoError(fd.Stop(ctx))
 
 	// now we should have:
@@ -245,13 +485,13 @@ func TestNewFileDAOSplitLegacy(t *testing.T) {
 	r.Equal(FileLegacyAuxiliary, h.Version)
 	h, err = readFileHeader(file2, FileV2)
 	r.NoError(err)
-	r.Equal(FileV2, h.Version)
+	r.Equal(FileV3, h.Version)
 	h, err = readFileHeader(file3, FileV2)
 	r.NoError(err)
-	r.Equal(FileV2, h.Version)
+	r.Equal(FileV3, h.Version)
 	h, err = readFileHeader(file4, FileV2)
 	r.NoError(err)
-	r.Equal(FileV2, h.Version)
+	r.Equal(FileV3, h.Version)
 	top, files := checkAuxFiles(cfg.DbPath, FileLegacyAuxiliary)
 	r.EqualValues(1, top)
 	r.Equal(1, len(files))
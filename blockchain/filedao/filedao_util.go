@@ -31,7 +31,7 @@ func checkMasterChainDBFile(defaultName string) (*FileHeader, error) {
 	case FileLegacyAuxiliary:
 		// default chain db file is legacy format, but not master, the master file has been corrupted
 		return h, ErrFileInvalid
-	case FileLegacyMaster, FileV2:
+	case FileLegacyMaster, FileV2, FileV3:
 		return h, nil
 	default:
 		panic(fmt.Errorf("corrupted file version: %s", h.Version))
@@ -56,7 +56,7 @@ func readFileHeader(filename, fileType string) (*FileHeader, error) {
 	switch fileType {
 	case FileLegacyMaster, FileLegacyAuxiliary:
 		return ReadHeaderLegacy(file)
-	case FileV2:
+	case FileV2, FileV3:
 		if headerV2, err := ReadHeaderV2(file); err == nil {
 			return headerV2, nil
 		}
@@ -108,7 +108,7 @@ func checkAuxFiles(filename, fileType string) (uint64, []string) {
 		}
 		name := dir + "/" + v.Name()
 		header, err := readFileHeader(name, fileType)
-		if err == nil && header.Version == fileType {
+		if err == nil && sameFormat(header.Version, fileType) {
 			possible = append(possible, name)
 			if index > top {
 				top = index
@@ -118,6 +118,15 @@ func checkAuxFiles(filename, fileType string) (uint64, []string) {
 	return top, possible
 }
 
+// sameFormat returns true if a file of the version is managed as the file type, where V2 and V3 files are both managed
+// by the v2 file manager
+func sameFormat(version, fileType string) bool {
+	if fileType == FileV2 || fileType == FileV3 {
+		return version == FileV2 || version == FileV3
+	}
+	return version == fileType
+}
+
 // isAuxFile returns true if file is an auxiliary chain db filename, and its index
 func isAuxFile(file, base string) (uint64, bool) {
 	extB := path.Ext(base)
@@ -8,6 +8,7 @@ package filedao
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"unsafe"
 
@@ -37,31 +38,37 @@ var (
 )
 
 type (
-	// fileDAOv2 handles chain db file after file split activation at v1.1.2
+	// fileDAOv2 handles chain db file after file split activation at v1.1.2, in either V2 or V3 format
 	fileDAOv2 struct {
-		filename  string
-		header    *FileHeader
-		tip       *FileTip
-		blkBuffer *stagingBuffer
-		blkCache  *cache.ThreadSafeLruCache
-		kvStore   db.KVStore
-		batch     batch.KVStoreBatch
-		hashStore db.CountingIndex // store block hash
-		blkStore  db.CountingIndex // store raw blocks
-		sysStore  db.CountingIndex // store transaction log
+		filename   string
+		compressor string // compressor of new records in V3 format
+		header     *FileHeader
+		tip        *FileTip
+		blkBuffer  *stagingBuffer
+		blkCache   *cache.ThreadSafeLruCache
+		kvStore    db.KVStore
+		batch      batch.KVStoreBatch
+		hashStore  db.CountingIndex // store block hash
+		blkStore   db.CountingIndex // store raw blocks
+		sysStore   db.CountingIndex // store transaction log
+		pruned     uint64           // highest height whose block body, receipts and transaction log are pruned
+		coldKey    string           // key of the object in cold storage, if the file is a stub of a moved file
+		mutex      sync.Mutex       // serializes the pruning and the rewrite of the file
+		retired    bool             // the file is replaced by its rewrite
 	}
 )
 
-// newFileDAOv2 creates a new v2 file
+// newFileDAOv2 creates a new file in V3 format
 func newFileDAOv2(bottom uint64, cfg config.DB) (*fileDAOv2, error) {
 	if bottom == 0 {
 		return nil, ErrNotSupported
 	}
 
 	fd := fileDAOv2{
-		filename: cfg.DbPath,
+		filename:   cfg.DbPath,
+		compressor: cfg.Compressor,
 		header: &FileHeader{
-			Version:        FileV2,
+			Version:        FileV3,
 			Compressor:     cfg.Compressor,
 			BlockStoreSize: uint64(cfg.BlockStoreBatchSize),
 			Start:          bottom,
@@ -76,13 +83,14 @@ func newFileDAOv2(bottom uint64, cfg config.DB) (*fileDAOv2, error) {
 	return &fd, nil
 }
 
-// openFileDAOv2 opens an existing v2 file
+// openFileDAOv2 opens an existing V2 or V3 file
 func openFileDAOv2(cfg config.DB) *fileDAOv2 {
 	return &fileDAOv2{
-		filename: cfg.DbPath,
-		blkCache: cache.NewThreadSafeLruCache(16),
-		kvStore:  db.NewBoltDB(cfg),
-		batch:    batch.NewBatch(),
+		filename:   cfg.DbPath,
+		compressor: cfg.Compressor,
+		blkCache:   cache.NewThreadSafeLruCache(16),
+		kvStore:    db.NewBoltDB(cfg),
+		batch:      batch.NewBatch(),
 	}
 }
 
@@ -112,6 +120,13 @@ func (fd *fileDAOv2) Start(ctx context.Context) error {
 		}
 	}
 
+	if fd.pruned, err = fd.readPrunedHeight(); err != nil {
+		return err
+	}
+	if fd.coldKey, err = fd.readColdKey(); err != nil {
+		return err
+	}
+
 	// create counting index for hash, blk, and transaction log
 	if fd.hashStore, err = db.NewCountingIndexNX(fd.kvStore, []byte(hashDataNS)); err != nil {
 		return err
@@ -178,6 +193,9 @@ func (fd *fileDAOv2) GetBlock(h hash.Hash256) (*block.Block, error) {
 }
 
 func (fd *fileDAOv2) GetBlockByHeight(height uint64) (*block.Block, error) {
+	if fd.isPruned(height) {
+		return nil, errors.Wrapf(ErrPruned, "failed to get block at height %d", height)
+	}
 	blkInfo, err := fd.getBlockStore(height)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get block at height %d", height)
@@ -186,6 +204,9 @@ func (fd *fileDAOv2) GetBlockByHeight(height uint64) (*block.Block, error) {
 }
 
 func (fd *fileDAOv2) GetReceipts(height uint64) ([]*action.Receipt, error) {
+	if fd.isPruned(height) {
+		return nil, errors.Wrapf(ErrPruned, "failed to get receipts at height %d", height)
+	}
 	blkInfo, err := fd.getBlockStore(height)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get receipts at height %d", height)
@@ -193,6 +214,35 @@ func (fd *fileDAOv2) GetReceipts(height uint64) ([]*action.Receipt, error) {
 	return blkInfo.Receipts, nil
 }
 
+// HeaderByHeight returns the block header, which is kept after the block is pruned
+func (fd *fileDAOv2) HeaderByHeight(height uint64) (*block.Header, error) {
+	blk, err := fd.getHeaderAndFooter(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block header at height %d", height)
+	}
+	return &blk.Header, nil
+}
+
+// FooterByHeight returns the block footer, which is kept after the block is pruned
+func (fd *fileDAOv2) FooterByHeight(height uint64) (*block.Footer, error) {
+	blk, err := fd.getHeaderAndFooter(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block footer at height %d", height)
+	}
+	return &blk.Footer, nil
+}
+
+func (fd *fileDAOv2) getHeaderAndFooter(height uint64) (*block.Block, error) {
+	if fd.isPruned(height) {
+		return fd.getPrunedBlock(height)
+	}
+	blkInfo, err := fd.getBlockStore(height)
+	if err != nil {
+		return nil, err
+	}
+	return blkInfo.Block, nil
+}
+
 func (fd *fileDAOv2) ContainsTransactionLog() bool {
 	return true
 }
@@ -201,12 +251,18 @@ func (fd *fileDAOv2) TransactionLogs(height uint64) (*iotextypes.TransactionLogs
 	if !fd.ContainsHeight(height) {
 		return nil, ErrNotSupported
 	}
+	if fd.isPruned(height) {
+		return nil, errors.Wrapf(ErrPruned, "failed to get transaction log at height %d", height)
+	}
 
 	value, err := fd.sysStore.Get(height - fd.header.Start)
 	if err != nil {
+		if fd.isPruned(height) {
+			err = ErrPruned
+		}
 		return nil, errors.Wrapf(err, "failed to get transaction log at height %d", height)
 	}
-	value, err = decompBytes(value, fd.header.Compressor)
+	value, err = fd.decompress(value)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get transaction log at height %d", height)
 	}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// Backup writes a consistent copy of the chain db files into the directory, while the blocks keep being committed. The
+// backup ends at the tip of the top file when the backup starts, or later.
+func (fd *fileDAO) Backup(ctx context.Context, dir string) error {
+	if fd.legacyFd != nil {
+		return errors.Wrap(ErrNotSupported, "cannot back up the legacy chain db file")
+	}
+	if fd.v2Fd == nil {
+		return ErrNotSupported
+	}
+	return fd.v2Fd.backup(ctx, dir, fd.cfg.BlockRetention > 0)
+}
+
+// backup hard-links the sealed files which are written no more, and copies the others as of a bolt transaction. A file
+// replaced by its rewrite or its cold storage stub is renamed over, so the hard link keeps the content as of the backup.
+func (fm *FileV2Manager) backup(ctx context.Context, dir string, pruning bool) error {
+	fm.lock.RLock()
+	fds := make([]*fileDAOv2, len(fm.Indices))
+	for i, v := range fm.Indices {
+		fds[i] = v.fd
+	}
+	fm.lock.RUnlock()
+
+	for i, fd := range fds {
+		if i < len(fds)-1 && !fd.writable(pruning) {
+			target := filepath.Join(dir, filepath.Base(fd.filename))
+			err := os.Link(fd.filename, target)
+			if err == nil {
+				continue
+			}
+			if os.IsExist(err) {
+				return errors.Wrapf(db.ErrIO, "backup %s already exists", target)
+			}
+			// the directory is on another device
+			log.L().Debug("Failed to link chain db file, copying it.", zap.String("file", fd.filename), zap.Error(err))
+		}
+		kv, ok := fd.kvStore.(db.Backuper)
+		if !ok {
+			return errors.Wrapf(ErrNotSupported, "cannot back up chain db file %s", fd.filename)
+		}
+		if err := kv.Backup(ctx, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writable returns true if the sealed file may still be written, by the pruning of its block stores
+func (fd *fileDAOv2) writable(pruning bool) bool {
+	if !pruning || fd.coldKey != "" {
+		return false
+	}
+	return atomic.LoadUint64(&fd.pruned) < fd.highestBlockOfStoreTip()
+}
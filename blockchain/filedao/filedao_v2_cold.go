@@ -0,0 +1,299 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"container/list"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/objstore"
+)
+
+var (
+	// coldObjectKey is the key of the object of a file moved to cold storage, in the stub left in place of the file
+	coldObjectKey = []byte("co")
+)
+
+type (
+	// coldStorage moves the sealed v2 files to an object store. A moved file is replaced by a stub, which keeps the
+	// hashes and the hash-height mapping of the blocks, and its block data is read from a copy fetched back on demand.
+	// The fetched files are kept in an LRU cache.
+	coldStorage struct {
+		cfg    config.DB
+		store  objstore.Store
+		mutex  sync.Mutex
+		files  map[string]*list.Element
+		lru    *list.List
+		moving bool
+		wg     sync.WaitGroup
+	}
+
+	coldFile struct {
+		key  string
+		path string // path to delete once evicted, empty for a file moved in this run
+		fd   *fileDAOv2
+	}
+)
+
+func newColdStorage(cfg config.DB) (*coldStorage, error) {
+	store, err := objstore.New(cfg.ColdStorage.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.ColdStorage.CacheDir, 0700); err != nil {
+		return nil, err
+	}
+	return &coldStorage{
+		cfg:   cfg,
+		store: store,
+		files: make(map[string]*list.Element),
+		lru:   list.New(),
+	}, nil
+}
+
+// fetch returns the file of the object, which is fetched from the object store if not in cache. A file evicted from
+// the cache is closed, so a read in progress on it may fail.
+func (cs *coldStorage) fetch(key string) (*fileDAOv2, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if e, ok := cs.files[key]; ok {
+		cs.lru.MoveToFront(e)
+		return e.Value.(*coldFile).fd, nil
+	}
+
+	path := filepath.Join(cs.cfg.ColdStorage.CacheDir, key)
+	if _, err := os.Stat(path); err != nil {
+		// a file fetched in a previous run is complete, as it is renamed after fetched
+		if err := cs.download(key, path); err != nil {
+			return nil, err
+		}
+	}
+	cfg := cs.cfg
+	cfg.DbPath = path
+	fd := openFileDAOv2(cfg)
+	if err := fd.Start(context.Background()); err != nil {
+		return nil, errors.Wrapf(err, "failed to open cold file %s", key)
+	}
+	cs.add(&coldFile{key: key, path: path, fd: fd})
+	return fd, nil
+}
+
+func (cs *coldStorage) download(key, path string) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	err = cs.store.Get(context.Background(), key, f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrapf(err, "failed to fetch cold file %s", key)
+	}
+	return os.Rename(tmp, path)
+}
+
+// add puts the file in cache and evicts the least recently used files, must be called with the lock held
+func (cs *coldStorage) add(file *coldFile) {
+	cs.files[file.key] = cs.lru.PushFront(file)
+	for cs.lru.Len() > cs.cfg.ColdStorage.CacheSize {
+		cs.evict(cs.lru.Back())
+	}
+}
+
+func (cs *coldStorage) evict(e *list.Element) {
+	file := cs.lru.Remove(e).(*coldFile)
+	delete(cs.files, file.key)
+	if err := file.fd.Stop(context.Background()); err != nil {
+		log.L().Warn("Failed to close cold file.", zap.String("file", file.key), zap.Error(err))
+	}
+	if file.path != "" {
+		os.Remove(file.path)
+	}
+}
+
+// Stop waits for the ongoing move, and closes the cached files
+func (cs *coldStorage) Stop() {
+	cs.wg.Wait()
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	for cs.lru.Len() > 0 {
+		e := cs.lru.Back()
+		file := cs.lru.Remove(e).(*coldFile)
+		delete(cs.files, file.key)
+		if err := file.fd.Stop(context.Background()); err != nil {
+			log.L().Warn("Failed to close cold file.", zap.String("file", file.key), zap.Error(err))
+		}
+	}
+}
+
+// readColdKey reads the key of the object in cold storage, which is empty if the file is not a stub
+func (fd *fileDAOv2) readColdKey() (string, error) {
+	value, err := fd.kvStore.Get(headerDataNs, coldObjectKey)
+	if err != nil {
+		if errors.Cause(err) == db.ErrNotExist || errors.Cause(err) == db.ErrBucketNotExist {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "failed to get cold object key")
+	}
+	return string(value), nil
+}
+
+// enableColdStorage enables moving the sealed files to cold storage
+func (fm *FileV2Manager) enableColdStorage(cfg config.DB) error {
+	cs, err := newColdStorage(cfg)
+	if err != nil {
+		return err
+	}
+	fm.cold = cs
+	return nil
+}
+
+// DataFileByHeight returns the file holding the block data of the given height, which for a file moved to cold
+// storage is fetched back from it
+func (fm *FileV2Manager) DataFileByHeight(height uint64) (*fileDAOv2, error) {
+	fd := fm.FileDAOByHeight(height)
+	if fd == nil || fd.coldKey == "" {
+		return fd, nil
+	}
+	if fm.cold == nil {
+		return nil, errors.Wrapf(ErrNotSupported, "file %s is in cold storage, which is not configured", fd.filename)
+	}
+	return fm.cold.fetch(fd.coldKey)
+}
+
+// moveFiles moves in the background the oldest sealed file, which ends at least the depth below the height
+func (fm *FileV2Manager) moveFiles(height uint64) {
+	cs := fm.cold
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if cs.moving {
+		return
+	}
+
+	fm.lock.RLock()
+	var index *fileV2Index
+	// the top file is being written
+	for _, v := range fm.Indices[:len(fm.Indices)-1] {
+		if v.fd.coldKey == "" && v.end+cs.cfg.ColdStorage.Depth <= height {
+			index = v
+			break
+		}
+	}
+	fm.lock.RUnlock()
+	if index == nil {
+		return
+	}
+	cs.moving = true
+	cs.wg.Add(1)
+	go func() {
+		defer cs.wg.Done()
+		if err := fm.moveFile(index); err != nil {
+			log.L().Error("Failed to move file to cold storage.", zap.String("file", index.fd.filename), zap.Error(err))
+		}
+		cs.mutex.Lock()
+		cs.moving = false
+		cs.mutex.Unlock()
+	}()
+}
+
+// moveFile uploads the file to the object store, and replaces it with a stub. The file stays open in the cache, until
+// evicted.
+func (fm *FileV2Manager) moveFile(index *fileV2Index) error {
+	var (
+		cs   = fm.cold
+		fd   = index.fd
+		key  = filepath.Base(fd.filename)
+		stub = fd.filename + ".stub"
+		ctx  = context.Background()
+	)
+	if err := cs.upload(fd, key, stub); err != nil {
+		os.Remove(stub)
+		return err
+	}
+	// the open file keeps reading the replaced file
+	if err := os.Rename(stub, fd.filename); err != nil {
+		os.Remove(stub)
+		return err
+	}
+	cfg := cs.cfg
+	cfg.DbPath = fd.filename
+	stubFd := openFileDAOv2(cfg)
+	if err := stubFd.Start(ctx); err != nil {
+		return err
+	}
+
+	fm.lock.Lock()
+	index.fd = stubFd
+	fm.lock.Unlock()
+	cs.mutex.Lock()
+	cs.add(&coldFile{key: key, fd: fd})
+	cs.mutex.Unlock()
+	log.L().Info("Moved file to cold storage.", zap.String("file", fd.filename), zap.Uint64("end", index.end))
+	return nil
+}
+
+// upload puts the file in the object store, and writes its stub
+func (cs *coldStorage) upload(fd *fileDAOv2, key, stub string) error {
+	kv, ok := fd.kvStore.(*db.BoltDB)
+	if !ok {
+		return ErrNotSupported
+	}
+	cp, err := kv.Checkpoint()
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+
+	r, w := io.Pipe()
+	go func() {
+		_, err := cp.WriteTo(w)
+		w.CloseWithError(err)
+	}()
+	err = cs.store.Put(context.Background(), key, r, cp.Size())
+	r.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		return err
+	}
+	return writeStub(cp, stub, key)
+}
+
+// writeStub writes the stub of a file moved to cold storage, which is the file without its block data
+func writeStub(cp *db.Checkpoint, path, key string) error {
+	ctx := context.Background()
+	stub := db.NewBoltDB(config.DB{DbPath: path, NumRetries: 3})
+	if err := stub.Start(ctx); err != nil {
+		return err
+	}
+	defer stub.Stop(ctx)
+	err := copyRecords(cp, stub, []string{hashDataNS, headerDataNs, blockHashHeightMappingNS}, func(ns string, k, v []byte) ([]byte, error) {
+		if ns == headerDataNs && len(k) == 8 {
+			// skip the staging buffer
+			return nil, nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		return err
+	}
+	return stub.Put(headerDataNs, coldObjectKey, []byte(key))
+}
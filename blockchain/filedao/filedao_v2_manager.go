@@ -3,6 +3,7 @@ package filedao
 import (
 	"context"
 	"sort"
+	"sync"
 
 	"github.com/iotexproject/go-pkgs/hash"
 
@@ -18,7 +19,10 @@ type (
 
 	// FileV2Manager manages collection of v2 files
 	FileV2Manager struct {
-		Indices []*fileV2Index
+		Indices  []*fileV2Index
+		lock     sync.RWMutex
+		cold     *coldStorage  // nil if cold storage is not enabled
+		verifier *fileVerifier // nil if the background verification is not enabled
 	}
 )
 
@@ -59,11 +63,22 @@ func (fm *FileV2Manager) Start(ctx context.Context) error {
 		fm.Indices[i].end = end
 	}
 	sort.Slice(fm.Indices, func(i, j int) bool { return fm.Indices[i].start < fm.Indices[j].start })
+	if fm.verifier != nil {
+		fm.verifier.Start()
+	}
 	return nil
 }
 
 // Stop stops the FileV2Manager
 func (fm *FileV2Manager) Stop(ctx context.Context) error {
+	if fm.verifier != nil {
+		if err := fm.verifier.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if fm.cold != nil {
+		fm.cold.Stop()
+	}
 	for i := range fm.Indices {
 		if err := fm.Indices[i].fd.Stop(ctx); err != nil {
 			return err
@@ -73,7 +88,10 @@ func (fm *FileV2Manager) Stop(ctx context.Context) error {
 }
 
 // FileDAOByHeight returns FileDAO for the given height
-func (fm *FileV2Manager) FileDAOByHeight(height uint64) BaseFileDAO {
+func (fm *FileV2Manager) FileDAOByHeight(height uint64) *fileDAOv2 {
+	fm.lock.RLock()
+	defer fm.lock.RUnlock()
+
 	right := len(fm.Indices) - 1
 	if height >= fm.Indices[right].start {
 		return fm.Indices[right].fd
@@ -95,8 +113,19 @@ func (fm *FileV2Manager) FileDAOByHeight(height uint64) BaseFileDAO {
 	return nil
 }
 
+// bottom returns the start height of the bottom (with minimum height) v2 file
+func (fm *FileV2Manager) bottom() uint64 {
+	fm.lock.RLock()
+	defer fm.lock.RUnlock()
+
+	return fm.Indices[0].start
+}
+
 // GetBlockHeight returns height by hash
 func (fm *FileV2Manager) GetBlockHeight(hash hash.Hash256) (uint64, error) {
+	fm.lock.RLock()
+	defer fm.lock.RUnlock()
+
 	for _, file := range fm.Indices {
 		if height, err := file.fd.GetBlockHeight(hash); err == nil {
 			return height, nil
@@ -107,16 +136,25 @@ func (fm *FileV2Manager) GetBlockHeight(hash hash.Hash256) (uint64, error) {
 
 // GetBlock returns block by hash
 func (fm *FileV2Manager) GetBlock(hash hash.Hash256) (*block.Block, error) {
-	for _, file := range fm.Indices {
-		if blk, err := file.fd.GetBlock(hash); err == nil {
-			return blk, nil
-		}
+	height, err := fm.GetBlockHeight(hash)
+	if err != nil {
+		return nil, err
 	}
-	return nil, db.ErrNotExist
+	fd, err := fm.DataFileByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	if fd == nil {
+		return nil, db.ErrNotExist
+	}
+	return fd.GetBlockByHeight(height)
 }
 
 // AddFileDAO add a new v2 file
 func (fm *FileV2Manager) AddFileDAO(fd *fileDAOv2, start uint64) error {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
 	// update current top's end
 	top := fm.Indices[len(fm.Indices)-1]
 	end, err := top.fd.Height()
@@ -135,6 +173,9 @@ func (fm *FileV2Manager) AddFileDAO(fd *fileDAOv2, start uint64) error {
 
 // TopFd returns the top (with maximum height) v2 file
 func (fm *FileV2Manager) TopFd() (BaseFileDAO, uint64) {
+	fm.lock.RLock()
+	defer fm.lock.RUnlock()
+
 	top := fm.Indices[len(fm.Indices)-1]
 	return top.fd, top.start
 }
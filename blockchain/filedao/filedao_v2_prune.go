@@ -0,0 +1,146 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+// maxPruneStores bounds the number of block stores pruned at a time, so enabling pruning on a long chain catches up
+// gradually instead of stalling the block commit
+const maxPruneStores = 64
+
+// namespace for the headers and footers of pruned blocks
+const prunedHeaderNS = "phd"
+
+var (
+	prunedHeightKey = []byte("ph")
+)
+
+// readPrunedHeight reads the highest height whose block body, receipts and transaction log are pruned
+func (fd *fileDAOv2) readPrunedHeight() (uint64, error) {
+	value, err := getValueMustBe8Bytes(fd.kvStore, headerDataNs, prunedHeightKey)
+	if err != nil {
+		if errors.Cause(err) == db.ErrNotExist || errors.Cause(err) == db.ErrBucketNotExist {
+			return fd.header.Start - 1, nil
+		}
+		return 0, errors.Wrap(err, "failed to get pruned height")
+	}
+	return byteutil.BytesToUint64BigEndian(value), nil
+}
+
+func (fd *fileDAOv2) isPruned(height uint64) bool {
+	return fd.header.Start <= height && height <= atomic.LoadUint64(&fd.pruned)
+}
+
+// prune removes the block bodies, receipts and transaction logs at or below the given height, while keeping their
+// headers and footers. Only the block stores entirely below the height are pruned.
+func (fd *fileDAOv2) prune(height uint64) error {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+	if fd.retired {
+		// the rewrite of the file is pruned by the next block
+		return nil
+	}
+	if height < fd.header.Start {
+		return nil
+	}
+	if tip := fd.highestBlockOfStoreTip(); height > tip {
+		height = tip
+	}
+	var (
+		size  = fd.header.BlockStoreSize
+		first = (atomic.LoadUint64(&fd.pruned) + 1 - fd.header.Start) / size
+		last  = (height + 1 - fd.header.Start) / size
+	)
+	if last <= first {
+		return nil
+	}
+	if last-first > maxPruneStores {
+		last = first + maxPruneStores
+	}
+
+	for k := first; k < last; k++ {
+		value, err := fd.blkStore.Get(k)
+		if err != nil {
+			return err
+		}
+		value, err = fd.decompress(value)
+		if err != nil {
+			return err
+		}
+		pbStores, err := block.DeserializeBlockStoresPb(value)
+		if err != nil {
+			return err
+		}
+		if len(pbStores.BlockStores) != int(size) {
+			return ErrDataCorruption
+		}
+		for i, pbStore := range pbStores.BlockStores {
+			ser, err := proto.Marshal(&iotextypes.Block{
+				Header: pbStore.Block.Header,
+				Body:   &iotextypes.BlockBody{},
+				Footer: pbStore.Block.Footer,
+			})
+			if err != nil {
+				return err
+			}
+			h := fd.header.Start + k*size + uint64(i)
+			fd.batch.Put(prunedHeaderNS, byteutil.Uint64ToBytesBigEndian(h), ser, "failed to put pruned header")
+		}
+	}
+	pruned := fd.header.Start + last*size - 1
+	if err := fd.kvStore.WriteBatch(fd.batch); err != nil {
+		fd.batch.Clear()
+		return errors.Wrapf(err, "failed to put headers up to height %d", pruned)
+	}
+	fd.batch.Clear()
+
+	// with the headers in place, reads of the pruned heights are rejected before the data is deleted
+	prev := atomic.SwapUint64(&fd.pruned, pruned)
+	for k := first; k < last; k++ {
+		fd.blkCache.Remove(k)
+		fd.batch.Delete(blockDataNS, byteutil.Uint64ToBytesBigEndian(k), "failed to delete block store")
+	}
+	for h := fd.header.Start + first*size; h <= pruned; h++ {
+		fd.batch.Delete(systemLogNS, byteutil.Uint64ToBytesBigEndian(h-fd.header.Start), "failed to delete transaction log")
+	}
+	fd.batch.Put(headerDataNs, prunedHeightKey, byteutil.Uint64ToBytesBigEndian(pruned), "failed to put pruned height")
+	if err := fd.kvStore.WriteBatch(fd.batch); err != nil {
+		fd.batch.Clear()
+		atomic.StoreUint64(&fd.pruned, prev)
+		return errors.Wrapf(err, "failed to prune blocks up to height %d", pruned)
+	}
+	fd.batch.Clear()
+	return nil
+}
+
+// getPrunedBlock returns the header and footer of a pruned block, in a block with an empty body
+func (fd *fileDAOv2) getPrunedBlock(height uint64) (*block.Block, error) {
+	value, err := fd.kvStore.Get(prunedHeaderNS, byteutil.Uint64ToBytesBigEndian(height))
+	if err != nil {
+		return nil, err
+	}
+	pb := &iotextypes.Block{}
+	if err := proto.Unmarshal(value, pb); err != nil {
+		return nil, err
+	}
+	blk := &block.Block{}
+	if err := blk.ConvertFromBlockPb(pb); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
@@ -18,6 +18,16 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 )
 
+// flagCompressors are the compressors of V3 records, indexed by their flags
+var flagCompressors = []string{"", compress.Gzip, compress.Snappy, compress.Zstd}
+
+var compressorFlags = map[string]byte{
+	"":              0,
+	compress.Gzip:   1,
+	compress.Snappy: 2,
+	compress.Zstd:   3,
+}
+
 func (fd *fileDAOv2) populateStagingBuffer() (*stagingBuffer, error) {
 	buffer := newStagingBuffer(fd.header.BlockStoreSize)
 	blockStoreTip := fd.highestBlockOfStoreTip()
@@ -30,7 +40,7 @@ func (fd *fileDAOv2) populateStagingBuffer() (*stagingBuffer, error) {
 			return nil, err
 		}
 
-		v, err = decompBytes(v, fd.header.Compressor)
+		v, err = fd.decompress(v)
 		if err != nil {
 			return nil, err
 		}
@@ -79,7 +89,7 @@ func (fd *fileDAOv2) putBlock(blk *block.Block) error {
 	if err != nil {
 		return err
 	}
-	blkBytes, err := compBytes(ser, fd.header.Compressor)
+	blkBytes, err := fd.compress(ser)
 	if err != nil {
 		return err
 	}
@@ -99,7 +109,7 @@ func (fd *fileDAOv2) putBlock(blk *block.Block) error {
 	if ser, err = fd.blkBuffer.Serialize(); err != nil {
 		return err
 	}
-	if blkBytes, err = compBytes(ser, fd.header.Compressor); err != nil {
+	if blkBytes, err = fd.compress(ser); err != nil {
 		return err
 	}
 	return addOneEntryToBatch(fd.blkStore, blkBytes, fd.batch)
@@ -110,7 +120,7 @@ func (fd *fileDAOv2) putTransactionLog(blk *block.Block) error {
 	if sysLog == nil {
 		sysLog = &block.BlkTransactionLog{}
 	}
-	logBytes, err := compBytes(sysLog.Serialize(), fd.header.Compressor)
+	logBytes, err := fd.compress(sysLog.Serialize())
 	if err != nil {
 		return err
 	}
@@ -127,6 +137,44 @@ func addOneEntryToBatch(c db.CountingIndex, v []byte, b batch.KVStoreBatch) erro
 	return c.Finalize()
 }
 
+// compress encodes a record of blocks, receipts or transaction log. A V2 record uses the compressor of the file, and a
+// V3 record uses the configured compressor and is prefixed with its flag.
+func (fd *fileDAOv2) compress(v []byte) ([]byte, error) {
+	if fd.header.Version == FileV2 {
+		return compBytes(v, fd.header.Compressor)
+	}
+	return compRecord(v, fd.compressor)
+}
+
+// decompress decodes a record of blocks, receipts or transaction log
+func (fd *fileDAOv2) decompress(v []byte) ([]byte, error) {
+	if fd.header.Version == FileV2 {
+		return decompBytes(v, fd.header.Compressor)
+	}
+	return decompRecord(v)
+}
+
+// compRecord compresses a V3 record and prefixes it with the flag of the compressor
+func compRecord(v []byte, comp string) ([]byte, error) {
+	flag, ok := compressorFlags[comp]
+	if !ok {
+		return nil, errors.Errorf("unsupported compressor %s", comp)
+	}
+	v, err := compBytes(v, comp)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{flag}, v...), nil
+}
+
+// decompRecord decompresses a V3 record with the compressor of its flag
+func decompRecord(v []byte) ([]byte, error) {
+	if len(v) == 0 || int(v[0]) >= len(flagCompressors) {
+		return nil, ErrDataCorruption
+	}
+	return decompBytes(v[1:], flagCompressors[v[0]])
+}
+
 func compBytes(v []byte, comp string) ([]byte, error) {
 	if comp != "" {
 		return compress.Compress(v, comp)
@@ -190,9 +238,13 @@ func (fd *fileDAOv2) getBlockStore(height uint64) (*block.Store, error) {
 
 	value, err := fd.blkStore.Get(storeKey)
 	if err != nil {
+		if fd.isPruned(height) {
+			// pruned after the caller checked
+			return nil, ErrPruned
+		}
 		return nil, err
 	}
-	value, err = decompBytes(value, fd.header.Compressor)
+	value, err = fd.decompress(value)
 	if err != nil {
 		return nil, err
 	}
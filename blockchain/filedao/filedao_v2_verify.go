@@ -0,0 +1,386 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+var (
+	// minCompactSize is the least number of bytes a rewrite has to reclaim
+	minCompactSize int64 = 16 << 20
+
+	// checksumKey is the key of the checksum of the block hashes of a sealed file, which is written when the file is
+	// verified the first time
+	checksumKey = []byte("ck")
+
+	corruptionMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_chaindb_file_corrupted",
+			Help: "Whether the sealed chain db file failed the verification.",
+		},
+		[]string{"file"},
+	)
+	compactionMtc = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "iotex_chaindb_file_compaction",
+			Help: "Number of the sealed chain db files rewritten.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(corruptionMtc)
+	prometheus.MustRegister(compactionMtc)
+}
+
+type (
+	// FileIntegrity is the result of the latest verification of a sealed chain db file
+	FileIntegrity struct {
+		File       string    `json:"file"`
+		Start      uint64    `json:"start"`
+		End        uint64    `json:"end"`
+		Corrupted  bool      `json:"corrupted"`
+		Error      string    `json:"error,omitempty"`
+		VerifiedAt time.Time `json:"verifiedAt"`
+	}
+
+	// IntegrityReporter reports the integrity of the sealed chain db files
+	IntegrityReporter interface {
+		Integrity() []FileIntegrity
+	}
+
+	// fileVerifier verifies the sealed v2 files in the background. A file is verified against the hashes of its blocks,
+	// and the checksum of the hashes. A verified file is rewritten if its size is out of proportion to its records,
+	// which happens once its blocks are pruned.
+	fileVerifier struct {
+		cfg     config.DB
+		fm      *FileV2Manager
+		mutex   sync.Mutex
+		results map[string]*FileIntegrity
+		retired []*fileDAOv2 // the files replaced by their rewrites, which are closed on stop
+		quit    chan struct{}
+		wg      sync.WaitGroup
+	}
+)
+
+func (fm *FileV2Manager) enableVerifier(cfg config.DB) {
+	fm.verifier = &fileVerifier{
+		cfg:     cfg,
+		fm:      fm,
+		results: make(map[string]*FileIntegrity),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Integrity returns the results of the latest verification of the sealed files, in ascending order of height
+func (fm *FileV2Manager) Integrity() []FileIntegrity {
+	if fm.verifier == nil {
+		return nil
+	}
+	fv := fm.verifier
+	fv.mutex.Lock()
+	defer fv.mutex.Unlock()
+	ret := make([]FileIntegrity, 0, len(fv.results))
+	for _, r := range fv.results {
+		ret = append(ret, *r)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Start < ret[j].Start })
+	return ret
+}
+
+func (fv *fileVerifier) Start() {
+	fv.wg.Add(1)
+	go func() {
+		defer fv.wg.Done()
+		ticker := time.NewTicker(fv.cfg.Integrity.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-fv.quit:
+				return
+			case <-ticker.C:
+				fv.verifyAll()
+			}
+		}
+	}()
+}
+
+func (fv *fileVerifier) Stop(ctx context.Context) error {
+	close(fv.quit)
+	fv.wg.Wait()
+	for _, fd := range fv.retired {
+		if err := fd.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyAll verifies the sealed files which are kept locally, one at a time
+func (fv *fileVerifier) verifyAll() {
+	fm := fv.fm
+	fm.lock.RLock()
+	var indices []*fileV2Index
+	// the top file is being written
+	for _, v := range fm.Indices[:len(fm.Indices)-1] {
+		if v.fd.coldKey == "" {
+			indices = append(indices, v)
+		}
+	}
+	fm.lock.RUnlock()
+
+	for _, index := range indices {
+		select {
+		case <-fv.quit:
+			return
+		default:
+		}
+		fm.lock.RLock()
+		fd, start, end := index.fd, index.start, index.end
+		fm.lock.RUnlock()
+		err := fd.verify()
+		fv.report(fd.filename, start, end, err)
+		if err != nil || fv.cfg.Integrity.CompactRatio == 0 || fm.cold != nil {
+			continue
+		}
+		if err := fv.compact(index, fd); err != nil {
+			log.L().Error("Failed to rewrite chain db file.", zap.String("file", fd.filename), zap.Error(err))
+		}
+	}
+}
+
+func (fv *fileVerifier) report(file string, start, end uint64, err error) {
+	result := &FileIntegrity{
+		File:       file,
+		Start:      start,
+		End:        end,
+		VerifiedAt: time.Now(),
+	}
+	if err != nil {
+		result.Corrupted = true
+		result.Error = err.Error()
+		corruptionMtc.WithLabelValues(file).Set(1)
+		log.L().Error("Chain db file is corrupted.", zap.String("file", file), zap.Error(err))
+	} else {
+		corruptionMtc.WithLabelValues(file).Set(0)
+	}
+	fv.mutex.Lock()
+	fv.results[file] = result
+	fv.mutex.Unlock()
+}
+
+// compact rewrites the file if it is fragmented, and replaces it with the rewrite. The file stays open until stop, for
+// the reads in flight.
+func (fv *fileVerifier) compact(index *fileV2Index, fd *fileDAOv2) error {
+	kv, ok := fd.kvStore.(*db.BoltDB)
+	if !ok {
+		return nil
+	}
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+	if fd.retired {
+		return nil
+	}
+	info, err := os.Stat(fd.filename)
+	if err != nil {
+		return err
+	}
+	cp, err := kv.Checkpoint()
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+	var live int64
+	for _, ns := range v2Namespaces {
+		err := cp.ForEach(ns, func(k, v []byte) error {
+			live += int64(len(k) + len(v))
+			return nil
+		})
+		if err != nil && errors.Cause(err) != db.ErrNotExist {
+			return err
+		}
+	}
+	if info.Size()-live < minCompactSize || float64(info.Size()) < float64(live)*fv.cfg.Integrity.CompactRatio {
+		return nil
+	}
+
+	tmp := fd.filename + ".compact"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := writeCompacted(cp, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, fd.filename); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	cfg := fv.cfg
+	cfg.DbPath = fd.filename
+	newFd := openFileDAOv2(cfg)
+	if err := newFd.Start(context.Background()); err != nil {
+		return err
+	}
+
+	fv.fm.lock.Lock()
+	index.fd = newFd
+	fv.fm.lock.Unlock()
+	fd.retired = true
+	fv.mutex.Lock()
+	fv.retired = append(fv.retired, fd)
+	fv.mutex.Unlock()
+	compactionMtc.Inc()
+	log.L().Info("Rewrote chain db file.",
+		zap.String("file", fd.filename),
+		zap.Int64("size", info.Size()),
+		zap.Int64("records", live))
+	return nil
+}
+
+func writeCompacted(cp *db.Checkpoint, path string) error {
+	ctx := context.Background()
+	dst := db.NewBoltDB(config.DB{DbPath: path, NumRetries: 3})
+	if err := dst.Start(ctx); err != nil {
+		return err
+	}
+	defer dst.Stop(ctx)
+	return copyRecords(cp, dst, v2Namespaces, func(_ string, _, v []byte) ([]byte, error) {
+		return v, nil
+	})
+}
+
+// verify checks the blocks of the file against their hashes, and the hashes against their checksum
+func (fd *fileDAOv2) verify() error {
+	tip := fd.loadTip().Height
+	if tip < fd.header.Start {
+		return nil
+	}
+	hashes := make([]hash.Hash256, 0, tip-fd.header.Start+1)
+	checksum := sha256.New()
+	for i := uint64(0); i <= tip-fd.header.Start; i++ {
+		h, err := fd.hashStore.Get(i)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get hash of block %d", fd.header.Start+i)
+		}
+		checksum.Write(h)
+		hashes = append(hashes, hash.BytesToHash256(h))
+	}
+	if err := fd.verifyChecksum(checksum.Sum(nil)); err != nil {
+		return err
+	}
+
+	pruned := atomic.LoadUint64(&fd.pruned)
+	for height := fd.header.Start; height <= pruned && height <= tip; height++ {
+		blk, err := fd.getPrunedBlock(height)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get header of pruned block %d", height)
+		}
+		if err := verifyBlock(blk, hashes[height-fd.header.Start], height, false); err != nil {
+			return err
+		}
+	}
+	size := fd.header.BlockStoreSize
+	for k := (pruned + 1 - fd.header.Start) / size; k < fd.blkStore.Size(); k++ {
+		value, err := fd.blkStore.Get(k)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get block store %d", k)
+		}
+		value, err = fd.decompress(value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decompress block store %d", k)
+		}
+		pbStores, err := block.DeserializeBlockStoresPb(value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to deserialize block store %d", k)
+		}
+		if len(pbStores.BlockStores) != int(size) {
+			return errors.Wrapf(ErrDataCorruption, "block store %d has %d blocks", k, len(pbStores.BlockStores))
+		}
+		for i := range pbStores.BlockStores {
+			height := fd.header.Start + k*size + uint64(i)
+			store, err := extractBlockStore(pbStores, uint64(i))
+			if err != nil {
+				return errors.Wrapf(err, "failed to deserialize block %d", height)
+			}
+			if err := verifyBlock(store.Block, hashes[height-fd.header.Start], height, true); err != nil {
+				return err
+			}
+		}
+	}
+	for height := fd.highestBlockOfStoreTip() + 1; height <= tip; height++ {
+		store, err := fd.blkBuffer.Get(stagingKey(height, fd.header))
+		if err != nil {
+			return errors.Wrapf(err, "failed to get staged block %d", height)
+		}
+		if err := verifyBlock(store.Block, hashes[height-fd.header.Start], height, true); err != nil {
+			return err
+		}
+	}
+	for height := pruned + 1; height <= tip; height++ {
+		value, err := fd.sysStore.Get(height - fd.header.Start)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get transaction log of block %d", height)
+		}
+		if value, err = fd.decompress(value); err == nil {
+			_, err = block.DeserializeSystemLogPb(value)
+		}
+		if err != nil {
+			return errors.Wrapf(ErrDataCorruption, "transaction log of block %d: %v", height, err)
+		}
+	}
+	return nil
+}
+
+// verifyChecksum compares the checksum of the block hashes with the one written by the first verification
+func (fd *fileDAOv2) verifyChecksum(checksum []byte) error {
+	value, err := fd.kvStore.Get(headerDataNs, checksumKey)
+	switch errors.Cause(err) {
+	case nil:
+		if !bytes.Equal(value, checksum) {
+			return errors.Wrap(ErrDataCorruption, "block hashes do not match their checksum")
+		}
+		return nil
+	case db.ErrNotExist:
+		return fd.kvStore.Put(headerDataNs, checksumKey, checksum)
+	default:
+		return errors.Wrap(err, "failed to get checksum of block hashes")
+	}
+}
+
+// verifyBlock checks the block against its hash, and its actions against the tx root unless its body is pruned
+func verifyBlock(blk *block.Block, h hash.Hash256, height uint64, withBody bool) error {
+	if blk.Height() != height {
+		return errors.Wrapf(ErrDataCorruption, "block %d is stored at height %d", blk.Height(), height)
+	}
+	if blk.HashBlock() != h {
+		return errors.Wrapf(ErrDataCorruption, "hash of block %d does not match", height)
+	}
+	if withBody && blk.VerifyTxRoot(blk.CalculateTxRoot()) != nil {
+		return errors.Wrapf(ErrDataCorruption, "actions of block %d do not match its tx root", height)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+// migrateBatchSize is the number of records written to the V3 file at a time
+const migrateBatchSize = 4096
+
+// v2Namespaces are the namespaces of a V2 or V3 file
+var v2Namespaces = []string{
+	hashDataNS,
+	blockDataNS,
+	headerDataNs,
+	systemLogNS,
+	blockHashHeightMappingNS,
+	prunedHeaderNS,
+}
+
+// recordNamespaces are the namespaces holding records of blocks, receipts or transaction log, which are keyed by
+// 8-byte index
+var recordNamespaces = map[string]bool{
+	blockDataNS:  true,
+	headerDataNs: true,
+	systemLogNS:  true,
+}
+
+// MigrateToV3 converts the V2 files of the chain DB to V3 format, with their records compressed by the configured
+// compressor. The legacy file and the V3 files are left as is.
+func MigrateToV3(cfg config.DB) error {
+	if _, ok := compressorFlags[cfg.Compressor]; !ok {
+		return errors.Errorf("unsupported compressor %s", cfg.Compressor)
+	}
+	header, err := checkMasterChainDBFile(cfg.DbPath)
+	if err != nil {
+		return err
+	}
+	var files []string
+	if header.Version != FileLegacyMaster {
+		files = append(files, cfg.DbPath)
+	}
+	_, auxFiles := checkAuxFiles(cfg.DbPath, FileV2)
+	for _, file := range append(files, auxFiles...) {
+		if err := migrateFileToV3(file, cfg.Compressor); err != nil {
+			return errors.Wrapf(err, "failed to migrate %s", file)
+		}
+	}
+	return nil
+}
+
+// migrateFileToV3 converts a V2 file to V3 format. The V3 file is written next to the V2 file, and replaces it once
+// complete, so an interrupted migration leaves the V2 file intact.
+func migrateFileToV3(filename, compressor string) error {
+	header, err := readFileHeader(filename, FileV2)
+	if err != nil {
+		return err
+	}
+	if header.Version == FileV3 {
+		return nil
+	}
+
+	tmp := filename + ".v3"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := convertToV3(filename, tmp, header, compressor); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+func convertToV3(src, dst string, header *FileHeader, compressor string) error {
+	ctx := context.Background()
+	srcDB := db.NewBoltDB(config.DB{DbPath: src, NumRetries: 3})
+	if err := srcDB.Start(ctx); err != nil {
+		return err
+	}
+	defer srcDB.Stop(ctx)
+	dstDB := db.NewBoltDB(config.DB{DbPath: dst, NumRetries: 3})
+	if err := dstDB.Start(ctx); err != nil {
+		return err
+	}
+	defer dstDB.Stop(ctx)
+
+	cp, err := srcDB.Checkpoint()
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+	err = copyRecords(cp, dstDB, v2Namespaces, func(ns string, k, v []byte) ([]byte, error) {
+		if ns == headerDataNs && bytes.Equal(k, fileHeaderKey) {
+			return nil, nil
+		}
+		if !recordNamespaces[ns] || len(k) != 8 {
+			return v, nil
+		}
+		v, err := decompBytes(v, header.Compressor)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decompress record %x in %s", k, ns)
+		}
+		return compRecord(v, compressor)
+	})
+	if err != nil {
+		return err
+	}
+
+	// write the header last, a file without header is not a valid file
+	header.Version = FileV3
+	header.Compressor = compressor
+	return WriteHeaderV2(dstDB, header)
+}
+
+// copyRecords copies the records of the namespaces at the checkpoint to the DB. Each record is converted before it is
+// written, and skipped if converted to nil.
+func copyRecords(cp *db.Checkpoint, dst *db.BoltDB, namespaces []string, convert func(ns string, k, v []byte) ([]byte, error)) error {
+	b := batch.NewBatch()
+	for _, ns := range namespaces {
+		ns := ns
+		err := cp.ForEach(ns, func(k, v []byte) error {
+			value, err := convert(ns, k, v)
+			if err != nil || value == nil {
+				return err
+			}
+			// the key and value are only valid during the iteration
+			b.Put(ns, append([]byte{}, k...), append([]byte{}, value...), "failed to put record")
+			if b.Size() < migrateBatchSize {
+				return nil
+			}
+			if err := dst.WriteBatch(b); err != nil {
+				return err
+			}
+			b.Clear()
+			return nil
+		})
+		if err != nil && errors.Cause(err) != db.ErrNotExist {
+			return err
+		}
+	}
+	return dst.WriteBatch(b)
+}
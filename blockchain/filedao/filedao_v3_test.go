@@ -0,0 +1,89 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package filedao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/compress"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestRecordCompression(t *testing.T) {
+	r := require.New(t)
+
+	data := []byte("block, receipts and transaction log")
+	for _, comp := range []string{"", compress.Gzip, compress.Snappy, compress.Zstd} {
+		v, err := compRecord(data, comp)
+		r.NoError(err)
+		r.Equal(compressorFlags[comp], v[0])
+		v, err = decompRecord(v)
+		r.NoError(err)
+		r.Equal(data, v)
+	}
+	_, err := compRecord(data, "unknown")
+	r.Error(err)
+	_, err = decompRecord(nil)
+	r.Equal(ErrDataCorruption, err)
+	_, err = decompRecord([]byte{byte(len(flagCompressors))})
+	r.Equal(ErrDataCorruption, err)
+}
+
+func TestMigrateToV3(t *testing.T) {
+	r := require.New(t)
+
+	cfg := config.Default.DB
+	cfg.DbPath = "./filedao_v3.db"
+	cfg.Compressor = compress.Gzip
+	testutil.CleanupPath(t, cfg.DbPath)
+	defer testutil.CleanupPath(t, cfg.DbPath)
+
+	// create a V2 file with a block store and blocks in the staging buffer
+	v2, err := newFileDAOv2(1, cfg)
+	r.NoError(err)
+	v2.header.Version = FileV2
+	ctx := context.Background()
+	r.NoError(v2.Start(ctx))
+	r.NoError(testCommitBlocks(t, v2, 1, 20, hash.ZeroHash256))
+	tip, err := v2.GetBlockHash(20)
+	r.NoError(err)
+	r.NoError(v2.Stop(ctx))
+
+	cfg.Compressor = "unknown"
+	r.Error(MigrateToV3(cfg))
+	cfg.Compressor = compress.Zstd
+	r.NoError(MigrateToV3(cfg))
+	h, err := readFileHeader(cfg.DbPath, FileAll)
+	r.NoError(err)
+	r.Equal(FileV3, h.Version)
+	r.Equal(compress.Zstd, h.Compressor)
+	r.EqualValues(1, h.Start)
+	r.EqualValues(cfg.BlockStoreBatchSize, h.BlockStoreSize)
+	// a V3 file is left as is
+	r.NoError(MigrateToV3(cfg))
+
+	// the compressor of new records follows the config
+	cfg.Compressor = compress.Snappy
+	fd, err := NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	testVerifyChainDB(t, fd, 1, 20)
+	r.NoError(testCommitBlocks(t, fd, 21, 40, tip))
+	r.NoError(fd.Stop(ctx))
+
+	cfg.Compressor = ""
+	fd, err = NewFileDAO(cfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	defer fd.Stop(ctx)
+	testVerifyChainDB(t, fd, 1, 40)
+}
@@ -9,6 +9,7 @@ package filedao
 import (
 	"context"
 	"encoding/hex"
+	"io"
 	"math/big"
 	"testing"
 
@@ -56,7 +57,7 @@ func newFileDAOv2InMem(bottom uint64) (*fileDAOv2, error) {
 
 	fd := fileDAOv2{
 		header: &FileHeader{
-			Version:        FileV2,
+			Version:        FileV3,
 			Compressor:     "",
 			BlockStoreSize: 16,
 			Start:          bottom,
@@ -192,6 +193,35 @@ func testVerifyChainDB(t *testing.T, fd FileDAO, start, end uint64) {
 	}
 }
 
+func testVerifyBlockStoresInRange(t *testing.T, fd FileDAO, start, end uint64) {
+	r := require.New(t)
+
+	iter, err := fd.BlockStoresInRange(start, end)
+	r.NoError(err)
+	for i := start; i <= end; i++ {
+		store, err := iter.Next()
+		r.NoError(err)
+		r.Equal(i, store.Block.Height())
+		h, err := fd.GetBlockHash(i)
+		r.NoError(err)
+		r.Equal(h, store.Block.HashBlock())
+		r.Equal(i, store.Receipts[0].BlockHeight)
+		r.Equal(store.Block.Header.PrevHash(), store.Receipts[0].ActionHash)
+	}
+	_, err = iter.Next()
+	r.Equal(io.EOF, err)
+
+	// invalid range
+	_, err = fd.BlockStoresInRange(end, start-1)
+	r.Equal(db.ErrInvalid, errors.Cause(err))
+	_, err = fd.BlockStoresInRange(0, end)
+	r.Equal(db.ErrInvalid, errors.Cause(err))
+	tip, err := fd.Height()
+	r.NoError(err)
+	_, err = fd.BlockStoresInRange(start, tip+1)
+	r.Equal(db.ErrNotExist, errors.Cause(err))
+}
+
 func createTestingBlock(builder *block.TestingBuilder, height uint64, h hash.Hash256) *block.Block {
 	r := &action.Receipt{
 		Status:      1,
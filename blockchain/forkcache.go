@@ -0,0 +1,89 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+// forkCache keeps the recent blocks of the side chains, so the chain can switch to a side chain once it grows longer
+type forkCache struct {
+	mu     sync.Mutex
+	size   int
+	blocks map[hash.Hash256]*block.Block
+}
+
+func newForkCache(size int) *forkCache {
+	return &forkCache{
+		size:   size,
+		blocks: make(map[hash.Hash256]*block.Block),
+	}
+}
+
+// Add adds a block, and evicts the lowest block if the cache is full
+func (fc *forkCache) Add(blk *block.Block) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	h := blk.HashBlock()
+	if _, ok := fc.blocks[h]; ok {
+		return
+	}
+	if len(fc.blocks) >= fc.size {
+		var (
+			lowest hash.Hash256
+			height uint64
+		)
+		for k, b := range fc.blocks {
+			if height == 0 || b.Height() < height {
+				lowest, height = k, b.Height()
+			}
+		}
+		delete(fc.blocks, lowest)
+	}
+	fc.blocks[h] = blk
+}
+
+// Get returns the block of the hash
+func (fc *forkCache) Get(h hash.Hash256) (*block.Block, bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	blk, ok := fc.blocks[h]
+	return blk, ok
+}
+
+// Remove removes the block of the hash
+func (fc *forkCache) Remove(h hash.Hash256) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	delete(fc.blocks, h)
+}
+
+// Prune removes the blocks at or below the height, which are too deep to switch to
+func (fc *forkCache) Prune(height uint64) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	for k, b := range fc.blocks {
+		if b.Height() <= height {
+			delete(fc.blocks, k)
+		}
+	}
+}
+
+// Blocks returns the blocks in the cache
+func (fc *forkCache) Blocks() []*block.Block {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	blocks := make([]*block.Block, 0, len(fc.blocks))
+	for _, b := range fc.blocks {
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
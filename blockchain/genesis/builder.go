@@ -0,0 +1,308 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package genesis
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/crypto/bls"
+)
+
+// ErrInvalidGenesis indicates the genesis config is invalid
+var ErrInvalidGenesis = errors.New("invalid genesis")
+
+// Builder constructs a genesis config for a private network programmatically, starting from the default config
+// without any initial account or delegate. The first error is kept and returned by Build.
+type Builder struct {
+	g   Genesis
+	err error
+}
+
+// NewBuilder creates a genesis builder
+func NewBuilder() *Builder {
+	g := defaultConfig()
+	g.Poll.PollMode = "lifeLong"
+	g.EnableGravityChainVoting = false
+	g.GravityChainCeilingHeight = 0
+	return &Builder{g: g}
+}
+
+// SetTimestamp sets the timestamp of the genesis block
+func (b *Builder) SetTimestamp(ts time.Time) *Builder {
+	b.g.Timestamp = ts.Unix()
+	return b
+}
+
+// SetBlockInterval sets the interval between two blocks
+func (b *Builder) SetBlockInterval(interval time.Duration) *Builder {
+	b.g.BlockInterval = interval
+	return b
+}
+
+// SetEpoch sets the number of delegates producing blocks in an epoch, the number of candidates they're selected from,
+// and the number of sub epochs in an epoch
+func (b *Builder) SetEpoch(numDelegates, numCandidateDelegates, numSubEpochs uint64) *Builder {
+	b.g.NumDelegates = numDelegates
+	b.g.NumCandidateDelegates = numCandidateDelegates
+	b.g.NumSubEpochs = numSubEpochs
+	b.g.DardanellesNumSubEpochs = numSubEpochs
+	return b
+}
+
+// SetForkHeight activates all the hard forks at the height, a new network usually starts with all of them at 1
+func (b *Builder) SetForkHeight(height uint64) *Builder {
+	bc := &b.g.Blockchain
+	for _, h := range []*uint64{
+		&bc.PacificBlockHeight,
+		&bc.AleutianBlockHeight,
+		&bc.BeringBlockHeight,
+		&bc.CookBlockHeight,
+		&bc.DardanellesBlockHeight,
+		&bc.DaytonaBlockHeight,
+		&bc.EasterBlockHeight,
+		&bc.FbkMigrationBlockHeight,
+		&bc.FairbankBlockHeight,
+		&bc.GreenlandBlockHeight,
+		&bc.HawaiiBlockHeight,
+	} {
+		*h = height
+	}
+	return b
+}
+
+// ScheduleBlockGasLimit activates the block gas limit at the height, the activations are added in ascending order
+func (b *Builder) ScheduleBlockGasLimit(height, gasLimit uint64) *Builder {
+	b.g.BlockGasLimitSchedule = append(b.g.BlockGasLimitSchedule, GasLimitActivation{
+		Height:   height,
+		GasLimit: gasLimit,
+	})
+	return b
+}
+
+// ScheduleBlockInterval activates the block interval at the height, the activations are added in ascending order
+func (b *Builder) ScheduleBlockInterval(height uint64, interval time.Duration) *Builder {
+	b.g.BlockIntervalSchedule = append(b.g.BlockIntervalSchedule, BlockIntervalActivation{
+		Height:   height,
+		Interval: interval,
+	})
+	return b
+}
+
+// SetBLSAggregationHeight activates the aggregation of the commit endorsements in the block footer at the height
+func (b *Builder) SetBLSAggregationHeight(height uint64) *Builder {
+	b.g.BLSAggregationBlockHeight = height
+	return b
+}
+
+// AddBLSPublicKey registers the BLS public key in hex string of the delegate's operator address
+func (b *Builder) AddBLSPublicKey(operator, pk string) *Builder {
+	if _, ok := b.g.BLSPublicKeys[operator]; ok {
+		return b.fail(errors.Errorf("duplicate BLS public key of %s", operator))
+	}
+	b.g.BLSPublicKeys[operator] = pk
+	return b
+}
+
+// SetEthereumTxHeight starts accepting the RLP-encoded ethereum transactions at the height
+func (b *Builder) SetEthereumTxHeight(height uint64) *Builder {
+	b.g.EthereumTxBlockHeight = height
+	return b
+}
+
+// AddAccount adds an account with the initial balance
+func (b *Builder) AddAccount(addr string, balance *big.Int) *Builder {
+	if _, ok := b.g.InitBalanceMap[addr]; ok {
+		return b.fail(errors.Errorf("duplicate account %s", addr))
+	}
+	b.g.InitBalanceMap[addr] = balance.String()
+	return b
+}
+
+// AddDelegate adds a delegate of the poll protocol with its votes, the reward address could be empty
+func (b *Builder) AddDelegate(operator, reward string, votes *big.Int) *Builder {
+	b.g.Delegates = append(b.g.Delegates, Delegate{
+		OperatorAddrStr: operator,
+		RewardAddrStr:   reward,
+		VotesStr:        votes.String(),
+	})
+	return b
+}
+
+// AddBootstrapCandidate adds a candidate registered to the staking protocol at the genesis block
+func (b *Builder) AddBootstrapCandidate(c BootstrapCandidate) *Builder {
+	b.g.BootstrapCandidates = append(b.g.BootstrapCandidates, c)
+	return b
+}
+
+// SetRewards sets the block reward, the epoch reward and the initial balance of the rewarding fund
+func (b *Builder) SetRewards(blockReward, epochReward, initBalance *big.Int) *Builder {
+	b.g.BlockRewardStr = blockReward.String()
+	b.g.DardanellesBlockRewardStr = blockReward.String()
+	b.g.EpochRewardStr = epochReward.String()
+	b.g.AleutianEpochRewardStr = epochReward.String()
+	b.g.InitBalanceStr = initBalance.String()
+	return b
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = errors.Wrap(ErrInvalidGenesis, err.Error())
+	}
+	return b
+}
+
+// Build validates and returns the genesis config
+func (b *Builder) Build() (Genesis, error) {
+	if b.err != nil {
+		return Genesis{}, b.err
+	}
+	if err := b.g.Validate(); err != nil {
+		return Genesis{}, err
+	}
+	return b.g, nil
+}
+
+// Validate checks the genesis config is consistent, so a network isn't started with a config it can't run with
+func (g *Genesis) Validate() error {
+	if g.NumDelegates == 0 || g.NumSubEpochs == 0 {
+		return errors.Wrap(ErrInvalidGenesis, "numbers of delegates and sub epochs should be positive")
+	}
+	if g.NumCandidateDelegates < g.NumDelegates {
+		return errors.Wrap(ErrInvalidGenesis, "number of candidate delegates is less than the number of delegates")
+	}
+	if g.BlockInterval <= 0 {
+		return errors.Wrap(ErrInvalidGenesis, "block interval should be positive")
+	}
+	if g.ActionGasLimit > g.BlockGasLimit {
+		return errors.Wrap(ErrInvalidGenesis, "action gas limit exceeds block gas limit")
+	}
+	for i, a := range g.BlockGasLimitSchedule {
+		if i > 0 && a.Height <= g.BlockGasLimitSchedule[i-1].Height {
+			return errors.Wrap(ErrInvalidGenesis, "block gas limit schedule should be in ascending order of height")
+		}
+		if a.GasLimit < g.ActionGasLimit {
+			return errors.Wrapf(ErrInvalidGenesis, "block gas limit at height %d is less than action gas limit", a.Height)
+		}
+	}
+	for i, a := range g.BlockIntervalSchedule {
+		if i > 0 && a.Height <= g.BlockIntervalSchedule[i-1].Height {
+			return errors.Wrap(ErrInvalidGenesis, "block interval schedule should be in ascending order of height")
+		}
+		if a.Interval <= 0 {
+			return errors.Wrapf(ErrInvalidGenesis, "block interval at height %d should be positive", a.Height)
+		}
+	}
+	forks := []uint64{
+		g.PacificBlockHeight,
+		g.AleutianBlockHeight,
+		g.BeringBlockHeight,
+		g.CookBlockHeight,
+		g.DardanellesBlockHeight,
+		g.DaytonaBlockHeight,
+		g.EasterBlockHeight,
+		g.FbkMigrationBlockHeight,
+		g.FairbankBlockHeight,
+		g.GreenlandBlockHeight,
+		g.HawaiiBlockHeight,
+	}
+	for i := 1; i < len(forks); i++ {
+		if forks[i] < forks[i-1] {
+			return errors.Wrap(ErrInvalidGenesis, "hard fork heights should be in the order of the forks")
+		}
+	}
+	for operator, pk := range g.BLSPublicKeys {
+		if err := validateAddress(operator); err != nil {
+			return err
+		}
+		if _, err := bls.HexStringToPublicKey(pk); err != nil {
+			return errors.Wrapf(ErrInvalidGenesis, "invalid BLS public key of %s", operator)
+		}
+	}
+	for addr, balance := range g.InitBalanceMap {
+		if err := validateAddress(addr); err != nil {
+			return err
+		}
+		if err := validateAmount(balance); err != nil {
+			return errors.Wrapf(err, "balance of %s", addr)
+		}
+	}
+	if g.PollMode == "lifeLong" && uint64(len(g.Delegates)) < g.NumDelegates {
+		return errors.Wrapf(ErrInvalidGenesis, "%d delegates are less than the number of delegates %d", len(g.Delegates), g.NumDelegates)
+	}
+	operators := make(map[string]bool)
+	for _, d := range g.Delegates {
+		if err := validateAddress(d.OperatorAddrStr); err != nil {
+			return err
+		}
+		if operators[d.OperatorAddrStr] {
+			return errors.Wrapf(ErrInvalidGenesis, "duplicate delegate %s", d.OperatorAddrStr)
+		}
+		operators[d.OperatorAddrStr] = true
+		if d.RewardAddrStr != "" {
+			if err := validateAddress(d.RewardAddrStr); err != nil {
+				return err
+			}
+		}
+		if err := validateAmount(d.VotesStr); err != nil {
+			return errors.Wrapf(err, "votes of %s", d.OperatorAddrStr)
+		}
+	}
+	names := make(map[string]bool)
+	for _, c := range g.BootstrapCandidates {
+		for _, addr := range []string{c.OwnerAddress, c.OperatorAddress, c.RewardAddress} {
+			if err := validateAddress(addr); err != nil {
+				return err
+			}
+		}
+		if c.Name == "" || names[c.Name] {
+			return errors.Wrapf(ErrInvalidGenesis, "invalid or duplicate candidate name %q", c.Name)
+		}
+		names[c.Name] = true
+		if err := validateAmount(c.SelfStakingTokens); err != nil {
+			return errors.Wrapf(err, "self staking tokens of %s", c.Name)
+		}
+	}
+	for _, amount := range []string{
+		g.InitBalanceStr,
+		g.BlockRewardStr,
+		g.DardanellesBlockRewardStr,
+		g.EpochRewardStr,
+		g.AleutianEpochRewardStr,
+		g.FoundationBonusStr,
+	} {
+		if err := validateAmount(amount); err != nil {
+			return errors.Wrap(err, "rewarding")
+		}
+	}
+	return nil
+}
+
+// YAML encodes the genesis config in the yaml format loaded by the -genesis-path flag
+func (g *Genesis) YAML() ([]byte, error) {
+	return yaml.Marshal(g)
+}
+
+func validateAddress(addr string) error {
+	if _, err := address.FromString(addr); err != nil {
+		return errors.Wrapf(ErrInvalidGenesis, "invalid address %q", addr)
+	}
+	return nil
+}
+
+func validateAmount(amount string) error {
+	v, ok := new(big.Int).SetString(amount, 10)
+	if !ok || v.Sign() < 0 {
+		return errors.Wrapf(ErrInvalidGenesis, "invalid amount %q", amount)
+	}
+	return nil
+}
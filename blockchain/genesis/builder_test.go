@@ -0,0 +1,153 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package genesis
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/config"
+
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestBuilder(t *testing.T) {
+	r := require.New(t)
+	votes := unit.ConvertIotxToRau(1000000)
+	sk, err := bls.GenerateKey()
+	r.NoError(err)
+	b := NewBuilder().
+		SetTimestamp(time.Unix(1600000000, 0)).
+		SetBlockInterval(5*time.Second).
+		SetEpoch(2, 3, 1).
+		SetForkHeight(1).
+		ScheduleBlockGasLimit(100, 30000000).
+		ScheduleBlockGasLimit(200, 40000000).
+		ScheduleBlockInterval(300, 2*time.Second).
+		SetBLSAggregationHeight(10).
+		AddBLSPublicKey(identityset.Address(0).String(), sk.PublicKey().HexString()).
+		SetRewards(unit.ConvertIotxToRau(8), unit.ConvertIotxToRau(100), unit.ConvertIotxToRau(1000000))
+	for i := 0; i < 2; i++ {
+		addr := identityset.Address(i).String()
+		b.AddAccount(addr, votes).AddDelegate(addr, "", votes)
+	}
+	b.AddBootstrapCandidate(BootstrapCandidate{
+		OwnerAddress:      identityset.Address(2).String(),
+		OperatorAddress:   identityset.Address(2).String(),
+		RewardAddress:     identityset.Address(2).String(),
+		Name:              "alice",
+		SelfStakingTokens: votes.String(),
+	})
+	g, err := b.Build()
+	r.NoError(err)
+	r.Equal(uint64(1), g.HawaiiBlockHeight)
+	r.Len(g.Delegates, 2)
+	for _, test := range []struct {
+		height, gasLimit uint64
+	}{
+		{1, 20000000},
+		{99, 20000000},
+		{100, 30000000},
+		{199, 30000000},
+		{200, 40000000},
+	} {
+		r.Equal(test.gasLimit, g.BlockGasLimitByHeight(test.height))
+	}
+	r.Equal(uint64(20000000), g.MinBlockGasLimit())
+	_, ok := g.ScheduledBlockInterval(299)
+	r.False(ok)
+	r.Equal(5*time.Second, g.BlockIntervalByHeight(299))
+	interval, ok := g.ScheduledBlockInterval(300)
+	r.True(ok)
+	r.Equal(2*time.Second, interval)
+	r.Equal(2*time.Second, g.BlockIntervalByHeight(300))
+	r.Equal(uint64(10), g.BLSAggregationBlockHeight)
+	pk, err := g.BLSPublicKey(identityset.Address(0).String())
+	r.NoError(err)
+	r.Equal(sk.PublicKey().Bytes(), pk.Bytes())
+	_, err = g.BLSPublicKey(identityset.Address(1).String())
+	r.Error(err)
+
+	// the yaml is loaded back to the same genesis
+	out, err := g.YAML()
+	r.NoError(err)
+	yaml, err := config.NewYAML(config.Static(defaultConfig()), config.Source(bytes.NewReader(out)))
+	r.NoError(err)
+	var loaded Genesis
+	r.NoError(yaml.Get(config.Root).Populate(&loaded))
+	r.Equal(g.Hash(), loaded.Hash())
+	r.Equal(g.Blockchain, loaded.Blockchain)
+	r.Equal(g.BootstrapCandidates, loaded.BootstrapCandidates)
+
+	for _, test := range []struct {
+		b   *Builder
+		err string
+	}{
+		{
+			NewBuilder().SetEpoch(2, 1, 1),
+			"number of candidate delegates",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).ScheduleBlockGasLimit(100, 30000000).ScheduleBlockGasLimit(100, 40000000),
+			"ascending order of height",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).ScheduleBlockGasLimit(100, 1000),
+			"less than action gas limit",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).ScheduleBlockInterval(200, 2*time.Second).ScheduleBlockInterval(100, time.Second),
+			"ascending order of height",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).ScheduleBlockInterval(100, 0),
+			"should be positive",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).AddAccount("io1abc", big.NewInt(1)),
+			"invalid address",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).
+				AddAccount(identityset.Address(0).String(), big.NewInt(1)).
+				AddAccount(identityset.Address(0).String(), big.NewInt(1)),
+			"duplicate account",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).AddBLSPublicKey(identityset.Address(0).String(), "abcd"),
+			"invalid BLS public key",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).
+				AddBLSPublicKey(identityset.Address(0).String(), sk.PublicKey().HexString()).
+				AddBLSPublicKey(identityset.Address(0).String(), sk.PublicKey().HexString()),
+			"duplicate BLS public key",
+		},
+		{
+			NewBuilder().SetEpoch(2, 2, 1).AddDelegate(identityset.Address(0).String(), "", big.NewInt(1)),
+			"less than the number of delegates",
+		},
+		{
+			NewBuilder().SetEpoch(1, 1, 1).AddDelegate(identityset.Address(0).String(), "", big.NewInt(-1)),
+			"invalid amount",
+		},
+	} {
+		_, err := test.b.Build()
+		r.Equal(ErrInvalidGenesis, errors.Cause(err))
+		r.Contains(err.Error(), test.err)
+	}
+
+	// the mainnet genesis is valid
+	def := defaultConfig()
+	r.NoError(def.Validate())
+}
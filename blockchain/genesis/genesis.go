@@ -8,6 +8,7 @@ package genesis
 
 import (
 	"flag"
+	"math"
 	"math/big"
 	"sort"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 
+	"github.com/iotexproject/iotex-core/crypto/bls"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/unit"
 	"github.com/iotexproject/iotex-core/test/identityset"
@@ -41,6 +43,8 @@ func defaultConfig() Genesis {
 		Blockchain: Blockchain{
 			Timestamp:               1546329600,
 			BlockGasLimit:           20000000,
+			BlockGasLimitSchedule:   []GasLimitActivation{},
+			BlockIntervalSchedule:   []BlockIntervalActivation{},
 			ActionGasLimit:          5000000,
 			BlockInterval:           10 * time.Second,
 			NumSubEpochs:            2,
@@ -59,6 +63,12 @@ func defaultConfig() Genesis {
 			FairbankBlockHeight:     5165641,
 			GreenlandBlockHeight:    6544441,
 			HawaiiBlockHeight:       11073241,
+			// the sponsored actions are opted in by the networks
+			SponsoredGasBlockHeight: math.MaxUint64,
+			// the aggregation of the endorsements is opted in by the networks, and so are the ethereum transactions
+			BLSAggregationBlockHeight: math.MaxUint64,
+			BLSPublicKeys:             make(map[string]string),
+			EthereumTxBlockHeight:     math.MaxUint64,
 		},
 		Account: Account{
 			InitBalanceMap: make(map[string]string),
@@ -135,10 +145,16 @@ type (
 		Timestamp int64
 		// BlockGasLimit is the total gas limit could be consumed in a block
 		BlockGasLimit uint64 `yaml:"blockGasLimit"`
+		// BlockGasLimitSchedule is the list of block gas limits activated at the heights in ascending order, which
+		// override BlockGasLimit from their heights on
+		BlockGasLimitSchedule []GasLimitActivation `yaml:"blockGasLimitSchedule"`
 		// ActionGasLimit is the per action gas limit cap
 		ActionGasLimit uint64 `yaml:"actionGasLimit"`
 		// BlockInterval is the interval between two blocks
 		BlockInterval time.Duration `yaml:"blockInterval"`
+		// BlockIntervalSchedule is the list of block intervals activated at the heights in ascending order, which
+		// override BlockInterval and the interval of Dardanelles from their heights on
+		BlockIntervalSchedule []BlockIntervalActivation `yaml:"blockIntervalSchedule"`
 		// NumSubEpochs is the number of sub epochs in one epoch of block production
 		NumSubEpochs uint64 `yaml:"numSubEpochs"`
 		// DardanellesNumSubEpochs is the number of sub epochs starts from dardanelles height in one epoch of block production
@@ -172,6 +188,27 @@ type (
 		GreenlandBlockHeight uint64 `yaml:"greenlandHeight"`
 		// HawaiiBlockHeight is the start height to fix GetBlockHash in EVM
 		HawaiiBlockHeight uint64 `yaml:"hawaiiHeight"`
+		// SponsoredGasBlockHeight is the start height of accepting the sponsored actions, whose gas fee is paid by a payer
+		// signing the action besides the sender
+		SponsoredGasBlockHeight uint64 `yaml:"sponsoredGasHeight"`
+		// BLSAggregationBlockHeight is the start height of aggregating the commit endorsements in the block footer into
+		// one BLS signature
+		BLSAggregationBlockHeight uint64 `yaml:"blsAggregationHeight"`
+		// BLSPublicKeys is the delegates' operator address and BLS public key in hex string mapping, which verifies the
+		// aggregated endorsements
+		BLSPublicKeys map[string]string `yaml:"blsPublicKeys"`
+		// EthereumTxBlockHeight is the start height of accepting the RLP-encoded ethereum transactions
+		EthereumTxBlockHeight uint64 `yaml:"ethereumTxHeight"`
+	}
+	// GasLimitActivation is a block gas limit activated at a height
+	GasLimitActivation struct {
+		Height   uint64 `yaml:"height"`
+		GasLimit uint64 `yaml:"gasLimit"`
+	}
+	// BlockIntervalActivation is a block interval activated at a height
+	BlockIntervalActivation struct {
+		Height   uint64        `yaml:"height"`
+		Interval time.Duration `yaml:"interval"`
 	}
 	// Account contains the configs for account protocol
 	Account struct {
@@ -378,6 +415,63 @@ func (g *Genesis) Hash() hash.Hash256 {
 	return hash.Hash256b(b)
 }
 
+// BlockGasLimitByHeight returns the block gas limit in effect at the height
+func (bc *Blockchain) BlockGasLimitByHeight(height uint64) uint64 {
+	limit := bc.BlockGasLimit
+	for _, a := range bc.BlockGasLimitSchedule {
+		if a.Height > height {
+			break
+		}
+		limit = a.GasLimit
+	}
+	return limit
+}
+
+// MinBlockGasLimit returns the lowest block gas limit of the chain, among BlockGasLimit and the limits of the schedule
+func (bc *Blockchain) MinBlockGasLimit() uint64 {
+	limit := bc.BlockGasLimit
+	for _, a := range bc.BlockGasLimitSchedule {
+		if a.GasLimit < limit {
+			limit = a.GasLimit
+		}
+	}
+	return limit
+}
+
+// ScheduledBlockInterval returns the block interval of the schedule in effect at the height, and false if no interval
+// of the schedule is activated at the height
+func (bc *Blockchain) ScheduledBlockInterval(height uint64) (time.Duration, bool) {
+	var (
+		interval  time.Duration
+		activated bool
+	)
+	for _, a := range bc.BlockIntervalSchedule {
+		if a.Height > height {
+			break
+		}
+		interval, activated = a.Interval, true
+	}
+	return interval, activated
+}
+
+// BlockIntervalByHeight returns the block interval of the schedule in effect at the height, or BlockInterval if no
+// interval of the schedule is activated at the height
+func (bc *Blockchain) BlockIntervalByHeight(height uint64) time.Duration {
+	if interval, ok := bc.ScheduledBlockInterval(height); ok {
+		return interval
+	}
+	return bc.BlockInterval
+}
+
+// BLSPublicKey returns the BLS public key of the delegate's operator address
+func (bc *Blockchain) BLSPublicKey(operator string) (*bls.PublicKey, error) {
+	s, ok := bc.BLSPublicKeys[operator]
+	if !ok {
+		return nil, errors.Errorf("BLS public key of %s is not registered", operator)
+	}
+	return bls.HexStringToPublicKey(s)
+}
+
 // InitBalances returns the address that have initial balances and the corresponding amounts. The i-th amount is the
 // i-th address' balance.
 func (a *Account) InitBalances() ([]address.Address, []*big.Int) {
@@ -1878,4 +1878,175 @@ func makeTransfer(contract string, bc blockchain.Blockchain, ap actpool.ActPool,
 	return blk
 }
 
+func newReorgChain(t *testing.T, stateTX bool) (blockchain.Blockchain, factory.Factory, blockdao.BlockDAO, actpool.ActPool) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Chain.MaxReorgDepth = 3
+	cfg.Chain.EnableAsyncIndexWrite = false
+	cfg.Genesis.EnableGravityChainVoting = false
+	cfg.ActPool.MinGasPriceStr = "0"
+	cfg.Genesis.InitBalanceMap = map[string]string{
+		identityset.Address(27).String(): unit.ConvertIotxToRau(10000000000).String(),
+	}
+	registry := protocol.NewRegistry()
+	var (
+		sf  factory.Factory
+		err error
+	)
+	if stateTX {
+		sf, err = factory.NewStateDB(cfg, factory.InMemStateDBOption(), factory.RegistryStateDBOption(registry))
+	} else {
+		sf, err = factory.NewFactory(cfg, factory.InMemTrieOption(), factory.RegistryOption(registry))
+	}
+	require.NoError(err)
+	ap, err := actpool.NewActPool(sf, cfg.ActPool)
+	require.NoError(err)
+	require.NoError(account.NewProtocol(rewarding.DepositGas).Register(registry))
+	rp := rolldpos.NewProtocol(cfg.Genesis.NumCandidateDelegates, cfg.Genesis.NumDelegates, cfg.Genesis.NumSubEpochs)
+	require.NoError(rp.Register(registry))
+	indexer, err := blockindex.NewIndexer(db.NewMemKVStore(), cfg.Genesis.Hash())
+	require.NoError(err)
+	dao := blockdao.NewBlockDAOInMemForTest([]blockdao.BlockIndexer{sf, indexer})
+	bc := blockchain.NewBlockchain(
+		cfg,
+		dao,
+		factory.NewMinter(sf, ap),
+		blockchain.BlockValidatorOption(block.NewValidator(
+			sf,
+			protocol.NewGenericValidator(sf, accountutil.AccountState),
+		)),
+	)
+	require.NoError(bc.Start(context.Background()))
+	return bc, sf, dao, ap
+}
+
+func TestReorg(t *testing.T) {
+	t.Run("trie", func(t *testing.T) {
+		testReorg(t, false)
+	})
+	t.Run("statedb", func(t *testing.T) {
+		testReorg(t, true)
+	})
+}
+
+func testReorg(t *testing.T, stateTX bool) {
+	require := require.New(t)
+	ctx := context.Background()
+	bc1, sf1, dao1, ap1 := newReorgChain(t, stateTX)
+	defer func() {
+		require.NoError(bc1.Stop(ctx))
+	}()
+	bc2, sf2, _, ap2 := newReorgChain(t, stateTX)
+	defer func() {
+		require.NoError(bc2.Stop(ctx))
+	}()
+
+	var (
+		sender = identityset.PrivateKey(27)
+		now    = testutil.TimestampNow()
+	)
+	mint := func(bc blockchain.Blockchain, ap actpool.ActPool, recipient string, nonce uint64) *block.Block {
+		tsf, err := testutil.SignedTransfer(recipient, sender, nonce, big.NewInt(100), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+		require.NoError(err)
+		require.NoError(ap.Add(ctx, tsf))
+		blk, err := bc.MintNewBlock(now.Add(time.Duration(nonce) * time.Second))
+		require.NoError(err)
+		require.NoError(bc.CommitBlock(blk))
+		ap.Reset()
+		return blk
+	}
+	balance := func(sf factory.Factory, addr string) *big.Int {
+		acct, err := accountutil.AccountState(sf, addr)
+		require.NoError(err)
+		return acct.Balance
+	}
+
+	// both chains share block 1, then chain 1 grows to height 3 and chain 2 to height 4
+	blk := mint(bc1, ap1, identityset.Address(28).String(), 1)
+	require.NoError(bc2.ValidateBlock(blk))
+	require.NoError(bc2.CommitBlock(blk))
+	var main, side []*block.Block
+	for nonce := uint64(2); nonce <= 3; nonce++ {
+		main = append(main, mint(bc1, ap1, identityset.Address(29).String(), nonce))
+	}
+	for nonce := uint64(2); nonce <= 4; nonce++ {
+		side = append(side, mint(bc2, ap2, identityset.Address(30).String(), nonce))
+	}
+	require.Equal(big.NewInt(200), balance(sf1, identityset.Address(29).String()))
+
+	// the side chain is kept until it grows longer than the chain
+	for _, blk := range side[:2] {
+		require.Equal(blockchain.ErrForkBlock, errors.Cause(bc1.ValidateBlock(blk)))
+	}
+	require.Equal(blockchain.ErrForkBlock, errors.Cause(bc1.CommitBlock(side[1])))
+	require.Equal(main[1].HashBlock(), bc1.TipHash())
+	require.NoError(bc1.ValidateBlock(side[2]))
+	require.NoError(bc1.CommitBlock(side[2]))
+	require.Equal(bc2.TipHash(), bc1.TipHash())
+	require.Equal(big.NewInt(0), balance(sf1, identityset.Address(29).String()))
+	require.Equal(balance(sf2, identityset.Address(30).String()), balance(sf1, identityset.Address(30).String()))
+	for _, blk := range main {
+		_, err := dao1.GetActionByActionHash(blk.Actions[0].Hash(), blk.Height())
+		require.Error(err)
+	}
+	for _, blk := range side {
+		selp, err := dao1.GetActionByActionHash(blk.Actions[0].Hash(), blk.Height())
+		require.NoError(err)
+		require.Equal(blk.Actions[0].Hash(), selp.Hash())
+	}
+
+	// the chain switches back once the reverted blocks grow longer
+	bc3, _, _, ap3 := newReorgChain(t, stateTX)
+	defer func() {
+		require.NoError(bc3.Stop(ctx))
+	}()
+	for _, blk := range append([]*block.Block{blk}, main...) {
+		require.NoError(bc3.ValidateBlock(blk))
+		require.NoError(bc3.CommitBlock(blk))
+	}
+	var longer []*block.Block
+	for nonce := uint64(4); nonce <= 5; nonce++ {
+		longer = append(longer, mint(bc3, ap3, identityset.Address(31).String(), nonce))
+	}
+	require.Equal(blockchain.ErrForkBlock, errors.Cause(bc1.ValidateBlock(longer[0])))
+	require.NoError(bc1.ValidateBlock(longer[1]))
+	require.NoError(bc1.CommitBlock(longer[1]))
+	require.Equal(bc3.TipHash(), bc1.TipHash())
+	require.Equal(big.NewInt(200), balance(sf1, identityset.Address(29).String()))
+
+	// the chain switches back from an invalid side chain
+	invalid, err := block.NewTestingBuilder().
+		SetHeight(5).
+		SetPrevBlockHash(longer[0].HashBlock()).
+		SetTimeStamp(now.Add(10 * time.Second)).
+		SignAndBuild(sender)
+	require.NoError(err)
+	next, err := block.NewTestingBuilder().
+		SetHeight(6).
+		SetPrevBlockHash(invalid.HashBlock()).
+		SetTimeStamp(now.Add(11 * time.Second)).
+		SignAndBuild(sender)
+	require.NoError(err)
+	require.Equal(blockchain.ErrForkBlock, errors.Cause(bc1.ValidateBlock(&invalid)))
+	require.NoError(bc1.ValidateBlock(&next))
+	require.Error(bc1.CommitBlock(&next))
+	require.Equal(longer[1].HashBlock(), bc1.TipHash())
+	require.Equal(big.NewInt(200), balance(sf1, identityset.Address(29).String()))
+	require.Equal(blockchain.ErrInvalidBlock, errors.Cause(bc1.ValidateBlock(&next)))
+
+	// a side chain forking deeper than the max reorg depth is rejected
+	for i := 0; i < 3; i++ {
+		blk, err := bc1.MintNewBlock(now.Add(time.Duration(20+i) * time.Second))
+		require.NoError(err)
+		require.NoError(bc1.CommitBlock(blk))
+	}
+	deep, err := block.NewTestingBuilder().
+		SetHeight(3).
+		SetPrevBlockHash(side[0].HashBlock()).
+		SetTimeStamp(now.Add(30 * time.Second)).
+		SignAndBuild(sender)
+	require.NoError(err)
+	require.Equal(blockchain.ErrInvalidBlock, errors.Cause(bc1.ValidateBlock(&deep)))
+}
+
 // TODO: add func TestValidateBlock()
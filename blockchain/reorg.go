@@ -0,0 +1,177 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// validateForkBlock verifies a block on a side chain, which forks from the chain within the max reorg depth, and keeps
+// it in the fork cache. The state of the block is validated when the chain switches to the side chain.
+func (bc *blockchain) validateForkBlock(blk *block.Block, tip *protocol.TipInfo) error {
+	forkHeight, err := bc.forkHeight(blk, tip)
+	if err != nil {
+		return err
+	}
+	if !blk.Prevalidated() {
+		if err := block.VerifyBlock(blk); err != nil {
+			return errors.Wrap(err, "failed to verify block's signature and merkle root")
+		}
+	}
+	bc.forks.Add(blk)
+	if blk.Height() <= tip.Height {
+		return errors.Wrapf(
+			ErrForkBlock,
+			"side chain forked at height %d reaches height %d, not above the tip height %d",
+			forkHeight,
+			blk.Height(),
+			tip.Height,
+		)
+	}
+	return nil
+}
+
+// forkHeight returns the height where the side chain of the block forks from the chain, following the parents of the
+// block in the fork cache
+func (bc *blockchain) forkHeight(blk *block.Block, tip *protocol.TipInfo) (uint64, error) {
+	prevHash, height := blk.PrevHash(), blk.Height()-1
+	for {
+		if height+bc.config.Chain.MaxReorgDepth < tip.Height {
+			return 0, errors.Wrapf(
+				ErrInvalidBlock,
+				"block %d forks deeper than the max reorg depth %d",
+				blk.Height(),
+				bc.config.Chain.MaxReorgDepth,
+			)
+		}
+		onChain, err := bc.isOnChain(prevHash, height, tip)
+		if err != nil {
+			return 0, err
+		}
+		if onChain {
+			return height, nil
+		}
+		parent, ok := bc.forks.Get(prevHash)
+		if !ok || height == 0 {
+			return 0, errors.Wrapf(ErrInvalidBlock, "unknown parent %x of block %d", prevHash, height+1)
+		}
+		prevHash, height = parent.PrevHash(), height-1
+	}
+}
+
+func (bc *blockchain) isOnChain(h hash.Hash256, height uint64, tip *protocol.TipInfo) (bool, error) {
+	switch {
+	case height > tip.Height:
+		return false, nil
+	case height == tip.Height:
+		return h == tip.Hash, nil
+	case height == 0:
+		return h == bc.config.Genesis.Hash(), nil
+	}
+	blkHash, err := bc.dao.GetBlockHash(height)
+	if err != nil {
+		return false, err
+	}
+	return h == blkHash, nil
+}
+
+// reorg switches the chain to the side chain of the block. The blocks above the fork height are reverted from the
+// block DAO and its indexers, and the blocks of the side chain are validated and committed. If a block of the side
+// chain is invalid, the reverted blocks are committed back.
+func (bc *blockchain) reorg(blk *block.Block, tip *protocol.TipInfo) error {
+	if blk.Height() <= tip.Height {
+		return errors.Wrapf(ErrForkBlock, "block %d is not above the tip height %d", blk.Height(), tip.Height)
+	}
+	forkHeight, err := bc.forkHeight(blk, tip)
+	if err != nil {
+		return err
+	}
+	side := make([]*block.Block, blk.Height()-forkHeight)
+	side[len(side)-1] = blk
+	for i := len(side) - 2; i >= 0; i-- {
+		parent, ok := bc.forks.Get(side[i+1].PrevHash())
+		if !ok {
+			return errors.Wrapf(ErrInvalidBlock, "unknown parent of block %d", side[i+1].Height())
+		}
+		side[i] = parent
+	}
+	reverted := make([]*block.Block, 0, tip.Height-forkHeight)
+	for height := forkHeight + 1; height <= tip.Height; height++ {
+		b, err := bc.dao.GetBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		reverted = append(reverted, b)
+	}
+
+	log.L().Warn("Switching to a side chain.",
+		zap.Uint64("forkHeight", forkHeight),
+		zap.Uint64("tipHeight", tip.Height),
+		zap.Uint64("newTipHeight", blk.Height()))
+	if err := bc.dao.DeleteBlockToTarget(forkHeight); err != nil {
+		return errors.Wrapf(err, "failed to revert the chain to height %d", forkHeight)
+	}
+	// the reverted blocks become a side chain, which the chain could switch back to
+	for _, b := range reverted {
+		bc.forks.Add(b)
+	}
+	for _, b := range side {
+		err := bc.validateBlock(b)
+		if err == nil {
+			err = bc.commitBlock(b)
+		}
+		if err != nil {
+			bc.dropSideChain(b)
+			if e := bc.reapply(forkHeight, reverted); e != nil {
+				log.L().Panic("Failed to commit the reverted blocks back.", zap.Error(e))
+			}
+			return errors.Wrapf(err, "failed to switch to the side chain at block %d", b.Height())
+		}
+		bc.forks.Remove(b.HashBlock())
+	}
+	return nil
+}
+
+// reapply commits the reverted blocks back on top of the fork height
+func (bc *blockchain) reapply(forkHeight uint64, reverted []*block.Block) error {
+	if err := bc.dao.DeleteBlockToTarget(forkHeight); err != nil {
+		return err
+	}
+	for _, b := range reverted {
+		if err := bc.commitBlock(b); err != nil {
+			return err
+		}
+		bc.forks.Remove(b.HashBlock())
+	}
+	return nil
+}
+
+// dropSideChain removes the invalid block of a side chain from the fork cache, so are its descendants
+func (bc *blockchain) dropSideChain(blk *block.Block) {
+	invalid := map[hash.Hash256]bool{blk.HashBlock(): true}
+	bc.forks.Remove(blk.HashBlock())
+	for {
+		var removed bool
+		for _, b := range bc.forks.Blocks() {
+			if invalid[b.PrevHash()] && !invalid[b.HashBlock()] {
+				invalid[b.HashBlock()] = true
+				bc.forks.Remove(b.HashBlock())
+				removed = true
+			}
+		}
+		if !removed {
+			return
+		}
+	}
+}
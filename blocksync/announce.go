@@ -0,0 +1,72 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// requestedBlocksCacheSize is the number of the announced blocks remembered as requested
+const requestedBlocksCacheSize = 1024
+
+// AnnounceBlock announces the height and the hash of a block to the neighbors
+type AnnounceBlock func(ctx context.Context, height uint64, h hash.Hash256) error
+
+// WithAnnounceBlock is the option to set the callback announcing blocks
+func WithAnnounceBlock(announceHandler AnnounceBlock) Option {
+	return func(cfg *Config) error {
+		cfg.announceHandler = announceHandler
+		return nil
+	}
+}
+
+// ReceiveBlock announces a block committed at the tip to the neighbors, so the announcements of a new block spread
+// over the network. The blocks committed while catching up are not announced.
+func (bs *blockSyncer) ReceiveBlock(blk *block.Block) error {
+	if blk.Height() < bs.TargetHeight() {
+		return nil
+	}
+	return bs.announceHandler(context.Background(), blk.Height(), blk.HashBlock())
+}
+
+// ProcessBlockAnnouncement requests the announced block from the peer, unless it's committed, requested lately or too
+// far ahead of the tip, which is then synced by the sync worker
+func (bs *blockSyncer) ProcessBlockAnnouncement(ctx context.Context, peer peerstore.PeerInfo, height uint64, h hash.Hash256) error {
+	bs.worker.Observe(peer.ID.Pretty(), height)
+	tip := bs.bc.TipHeight()
+	if height <= tip {
+		return nil
+	}
+	bs.worker.SetTargetHeight(height)
+	if height > tip+bs.buf.bufSize() {
+		return nil
+	}
+	now := time.Now()
+	if requested, ok := bs.requestedBlocks.Get(h); ok && now.Sub(requested.(time.Time)) < bs.requestTTL {
+		return nil
+	}
+	bs.requestedBlocks.Add(h, now)
+	reqCtx, cancel := context.WithTimeout(ctx, bs.processSyncRequestTTL)
+	defer cancel()
+	if err := bs.unicastHandler(reqCtx, peer, &iotexrpc.BlockSync{Start: height, End: height}); err != nil {
+		// the block can be requested from another peer announcing it
+		bs.requestedBlocks.Remove(h)
+		return errors.Wrapf(err, "failed to request block %d from %s", height, peer.ID.Pretty())
+	}
+	log.L().Debug("Requested announced block.", zap.Uint64("height", height), zap.String("peer", peer.ID.Pretty()))
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockdao"
+	"github.com/iotexproject/iotex-core/test/mock/mock_consensus"
+)
+
+func TestBlockSyncerAnnouncement(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := newTestConfig()
+	require.NoError(err)
+	cfg.BlockSync.AnnounceBlocks = true
+	cfg.BlockSync.BufferSize = 16
+
+	tipHeight := uint64(10)
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().ChainID().Return(cfg.Chain.ID).AnyTimes()
+	chain.EXPECT().TipHeight().DoAndReturn(func() uint64 { return tipHeight }).AnyTimes()
+	chain.EXPECT().AddSubscriber(gomock.Any()).Return(nil).Times(1)
+
+	var (
+		requests  []*iotexrpc.BlockSync
+		sendErr   error
+		announced []uint64
+	)
+	bs, err := NewBlockSyncer(
+		cfg,
+		chain,
+		mock_blockdao.NewMockBlockDAO(ctrl),
+		mock_consensus.NewMockConsensus(ctrl),
+		WithUnicastOutBound(func(_ context.Context, _ peerstore.PeerInfo, msg proto.Message) error {
+			if sendErr != nil {
+				return sendErr
+			}
+			requests = append(requests, msg.(*iotexrpc.BlockSync))
+			return nil
+		}),
+		WithNeighbors(func(_ context.Context) ([]peerstore.PeerInfo, error) { return nil, nil }),
+		WithAnnounceBlock(func(_ context.Context, height uint64, _ hash.Hash256) error {
+			announced = append(announced, height)
+			return nil
+		}),
+	)
+	require.NoError(err)
+	ctx := context.Background()
+	peer := peerstore.PeerInfo{ID: "a"}
+
+	// the committed block is not requested
+	require.NoError(bs.ProcessBlockAnnouncement(ctx, peer, 10, hash.Hash256b([]byte("10"))))
+	require.Empty(requests)
+
+	// the new block is requested once
+	h := hash.Hash256b([]byte("11"))
+	require.NoError(bs.ProcessBlockAnnouncement(ctx, peer, 11, h))
+	require.NoError(bs.ProcessBlockAnnouncement(ctx, peer, 11, h))
+	require.Len(requests, 1)
+	require.Equal(uint64(11), requests[0].Start)
+	require.Equal(uint64(11), requests[0].End)
+	require.Equal(uint64(11), bs.TargetHeight())
+
+	// the block failed to request is requested from another peer announcing it
+	sendErr = errors.New("network error")
+	h = hash.Hash256b([]byte("12"))
+	require.Error(bs.ProcessBlockAnnouncement(ctx, peer, 12, h))
+	sendErr = nil
+	require.NoError(bs.ProcessBlockAnnouncement(ctx, peerstore.PeerInfo{ID: "b"}, 12, h))
+	require.Len(requests, 2)
+
+	// the block too far ahead is left to the sync worker
+	require.NoError(bs.ProcessBlockAnnouncement(ctx, peer, 100, hash.Hash256b([]byte("100"))))
+	require.Len(requests, 2)
+	require.Equal(uint64(100), bs.TargetHeight())
+
+	// only the blocks committed at the target height are announced
+	newBlock := func(height uint64) *block.Block {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(time.Now()).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		return &blk
+	}
+	receiver := bs.(*blockSyncer)
+	require.NoError(receiver.ReceiveBlock(newBlock(11)))
+	require.Empty(announced)
+	require.NoError(receiver.ReceiveBlock(newBlock(100)))
+	require.Equal([]uint64{100}, announced)
+}
+
+func TestBlockSyncerReportInvalidBlock(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := newTestConfig()
+	require.NoError(err)
+	cp := hash.Hash256b([]byte("checkpoint"))
+	cfg.BlockSync.Checkpoint.Height = 11
+	cfg.BlockSync.Checkpoint.Hash = hex.EncodeToString(cp[:])
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().ChainID().Return(cfg.Chain.ID).AnyTimes()
+	chain.EXPECT().TipHeight().Return(uint64(10)).AnyTimes()
+
+	var reported []string
+	bs, err := NewBlockSyncer(
+		cfg,
+		chain,
+		mock_blockdao.NewMockBlockDAO(ctrl),
+		mock_consensus.NewMockConsensus(ctrl),
+		WithNeighbors(func(_ context.Context) ([]peerstore.PeerInfo, error) { return nil, nil }),
+		WithReportInvalidBlock(func(peer string) {
+			reported = append(reported, peer)
+		}),
+	)
+	require.NoError(err)
+
+	// the block at the checkpoint height on another fork is reported
+	blk, err := block.NewTestingBuilder().
+		SetHeight(11).
+		SetTimeStamp(time.Now()).
+		SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+	require.NoError(bs.ProcessBlock(context.Background(), &blk))
+	require.Empty(reported)
+	require.NoError(bs.ProcessBlock(WithPeer(context.Background(), "a"), &blk))
+	require.Equal([]string{"a"}, reported)
+}
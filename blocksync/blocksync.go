@@ -8,14 +8,18 @@ package blocksync
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
-	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/go-pkgs/hash"
+
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/config"
@@ -23,7 +27,9 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/state/factory"
 	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 )
 
 type (
@@ -31,6 +37,12 @@ type (
 	UnicastOutbound func(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error
 	// Neighbors returns the neighbors' addresses
 	Neighbors func(ctx context.Context) ([]peerstore.PeerInfo, error)
+	// RequestHeaders requests the peer for the headers from start to end
+	RequestHeaders func(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error
+	// SendHeaders sends the headers to the peer
+	SendHeaders func(ctx context.Context, peer peerstore.PeerInfo, headers []*iotextypes.BlockHeader) error
+	// ReportInvalidBlock reports the peer which has sent an invalid block or header
+	ReportInvalidBlock func(peer string)
 )
 
 // BlockDAO represents the block data access object
@@ -40,8 +52,15 @@ type BlockDAO interface {
 
 // Config represents the config to setup blocksync
 type Config struct {
-	unicastHandler   UnicastOutbound
-	neighborsHandler Neighbors
+	unicastHandler        UnicastOutbound
+	neighborsHandler      Neighbors
+	requestHeadersHandler RequestHeaders
+	sendHeadersHandler    SendHeaders
+	announceHandler       AnnounceBlock
+	reportHandler         ReportInvalidBlock
+	stateRangeReader      factory.StateRangeReader
+	requestStateRange     RequestStateRange
+	sendStateRange        SendStateRange
 }
 
 // Option is the option to override the blocksync config
@@ -63,6 +82,54 @@ func WithNeighbors(neighborsHandler Neighbors) Option {
 	}
 }
 
+// WithRequestHeaders is the option to set the callback requesting headers
+func WithRequestHeaders(requestHeadersHandler RequestHeaders) Option {
+	return func(cfg *Config) error {
+		cfg.requestHeadersHandler = requestHeadersHandler
+		return nil
+	}
+}
+
+// WithSendHeaders is the option to set the callback sending headers
+func WithSendHeaders(sendHeadersHandler SendHeaders) Option {
+	return func(cfg *Config) error {
+		cfg.sendHeadersHandler = sendHeadersHandler
+		return nil
+	}
+}
+
+// WithReportInvalidBlock is the option to set the callback reporting the peers sending invalid blocks
+func WithReportInvalidBlock(reportHandler ReportInvalidBlock) Option {
+	return func(cfg *Config) error {
+		cfg.reportHandler = reportHandler
+		return nil
+	}
+}
+
+// WithStateRangeReader is the option to set the reader of the state trie ranges served to the peers
+func WithStateRangeReader(stateRangeReader factory.StateRangeReader) Option {
+	return func(cfg *Config) error {
+		cfg.stateRangeReader = stateRangeReader
+		return nil
+	}
+}
+
+// WithRequestStateRange is the option to set the callback requesting state ranges
+func WithRequestStateRange(requestStateRange RequestStateRange) Option {
+	return func(cfg *Config) error {
+		cfg.requestStateRange = requestStateRange
+		return nil
+	}
+}
+
+// WithSendStateRange is the option to set the callback sending state ranges
+func WithSendStateRange(sendStateRange SendStateRange) Option {
+	return func(cfg *Config) error {
+		cfg.sendStateRange = sendStateRange
+		return nil
+	}
+}
+
 // BlockSync defines the interface of blocksyncer
 type BlockSync interface {
 	lifecycle.StartStopper
@@ -71,7 +138,20 @@ type BlockSync interface {
 	ProcessSyncRequest(ctx context.Context, peer peerstore.PeerInfo, sync *iotexrpc.BlockSync) error
 	ProcessBlock(ctx context.Context, blk *block.Block) error
 	ProcessBlockSync(ctx context.Context, blk *block.Block) error
+	ProcessHeaderRequest(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error
+	ProcessHeaders(ctx context.Context, peer peerstore.PeerInfo, headers []*block.Header) error
+	ProcessBlockAnnouncement(ctx context.Context, peer peerstore.PeerInfo, height uint64, h hash.Hash256) error
+	// ProcessStateRangeRequest serves the request of id of the peer for a range of the state trie at height
+	ProcessStateRangeRequest(ctx context.Context, peer peerstore.PeerInfo, id, height uint64, nsKey, start []byte, limit uint64) error
+	// ProcessStateRange hands the serialized range of the state trie sent by the peer to the request of id
+	ProcessStateRange(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error
+	// FetchStateRange fetches a range of the state trie at height from the neighbors in turn
+	FetchStateRange(ctx context.Context, height uint64, nsKey []byte, start []byte, limit uint64) (*factory.StateRange, error)
 	SyncStatus() string
+	// SyncSpeed returns the blocks synced per second over the sliding windows of 1m, 5m and 15m
+	SyncSpeed() map[string]float64
+	// SyncETA returns the estimated time to sync to the target height, 0 if synced and negative if unknown
+	SyncETA() time.Duration
 }
 
 // blockSyncer implements BlockSync interface
@@ -84,9 +164,20 @@ type blockSyncer struct {
 	dao                   BlockDAO
 	unicastHandler        UnicastOutbound
 	neighborsHandler      Neighbors
+	sendHeadersHandler    SendHeaders
+	headerBatchSize       uint64
+	announceHandler       AnnounceBlock
+	reportHandler         ReportInvalidBlock
+	requestedBlocks       *cache.ThreadSafeLruCache
+	requestTTL            time.Duration
 	syncStageTask         *routine.RecurringTask
-	syncStageHeight       uint64
-	syncBlockIncrease     uint64
+	progress              *progressTracker
+	quota                 *servingQuota
+	stateRangeReader      factory.StateRangeReader
+	sendStateRange        SendStateRange
+	stateRangeLimit       uint64
+	stateRange            *stateRangeClient
+	stateRangeFetcher     *StateRangeFetcher
 }
 
 // NewBlockSyncer returns a new block syncer instance
@@ -104,26 +195,66 @@ func NewBlockSyncer(
 		bufferSize:   cfg.BlockSync.BufferSize,
 		intervalSize: cfg.BlockSync.IntervalSize,
 	}
+	if cp := cfg.BlockSync.Checkpoint; cp.Height > 0 {
+		h, err := hex.DecodeString(cp.Hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid checkpoint hash")
+		}
+		buf.checkpointHeight = cp.Height
+		buf.checkpointHash = hash.BytesToHash256(h)
+	}
 	bsCfg := Config{}
 	for _, opt := range opts {
 		if err := opt(&bsCfg); err != nil {
 			return nil, err
 		}
 	}
+	if cfg.BlockSync.HeaderFirst && bsCfg.requestHeadersHandler != nil {
+		buf.headers = newHeaderChain(chain, buf.checkpointHeight, buf.checkpointHash)
+	}
 	bs := &blockSyncer{
 		bc:                    chain,
 		dao:                   dao,
 		buf:                   buf,
 		unicastHandler:        bsCfg.unicastHandler,
 		neighborsHandler:      bsCfg.neighborsHandler,
-		worker:                newSyncWorker(chain.ChainID(), cfg, bsCfg.unicastHandler, bsCfg.neighborsHandler, buf),
+		sendHeadersHandler:    bsCfg.sendHeadersHandler,
+		headerBatchSize:       cfg.BlockSync.HeaderBatchSize,
+		announceHandler:       bsCfg.announceHandler,
+		reportHandler:         bsCfg.reportHandler,
+		requestedBlocks:       cache.NewThreadSafeLruCache(requestedBlocksCacheSize),
+		requestTTL:            cfg.BlockSync.SegmentTTL,
+		worker:                newSyncWorker(chain.ChainID(), cfg, bsCfg.unicastHandler, bsCfg.neighborsHandler, bsCfg.requestHeadersHandler, buf),
 		processSyncRequestTTL: cfg.BlockSync.ProcessSyncRequestTTL,
+		progress:              &progressTracker{},
+		quota:                 newServingQuota(cfg.BlockSync.ServeQuota),
+		stateRangeReader:      bsCfg.stateRangeReader,
+		sendStateRange:        bsCfg.sendStateRange,
+		stateRangeLimit:       cfg.BlockSync.StateRangeLimit,
+	}
+	if bsCfg.requestStateRange != nil {
+		bs.stateRange = newStateRangeClient(bsCfg.requestStateRange, cfg.BlockSync.SegmentTTL)
+		bs.stateRangeFetcher = NewStateRangeFetcher(bsCfg.neighborsHandler, bs.stateRange.Request)
 	}
 	bs.syncStageTask = routine.NewRecurringTask(bs.syncStageChecker, config.DardanellesBlockInterval)
-	atomic.StoreUint64(&bs.syncBlockIncrease, 0)
+	if cfg.BlockSync.AnnounceBlocks && bs.announceHandler != nil {
+		if err := chain.AddSubscriber(bs); err != nil {
+			return nil, errors.Wrap(err, "failed to subscribe to the committed blocks")
+		}
+	}
 	return bs, nil
 }
 
+// ValidateBlock checks the block is decodable, signed by its producer, and of the matching tx root, regardless of the
+// state of the chain
+func ValidateBlock(pb *iotextypes.Block) error {
+	blk := &block.Block{}
+	if err := blk.ConvertFromBlockPb(pb); err != nil {
+		return err
+	}
+	return block.VerifyBlock(blk)
+}
+
 // TargetHeight returns the target height to sync to
 func (bs *blockSyncer) TargetHeight() uint64 {
 	bs.worker.mu.RLock()
@@ -151,8 +282,12 @@ func (bs *blockSyncer) Stop(ctx context.Context) error {
 }
 
 // ProcessBlock processes an incoming latest committed block
-func (bs *blockSyncer) ProcessBlock(_ context.Context, blk *block.Block) error {
+func (bs *blockSyncer) ProcessBlock(ctx context.Context, blk *block.Block) error {
 	var needSync bool
+	peer, fromPeer := getPeer(ctx)
+	if fromPeer && blk != nil {
+		bs.worker.Deliver(peer, blk.Height())
+	}
 	moved, re := bs.buf.Flush(blk)
 	switch re {
 	case bCheckinLower:
@@ -165,6 +300,12 @@ func (bs *blockSyncer) ProcessBlock(_ context.Context, blk *block.Block) error {
 		needSync = !moved
 	case bCheckinSkipNil:
 		needSync = false
+	case bCheckinMismatch:
+		log.L().Warn("Drop block not matching the checkpoint or the validated header.", zap.Uint64("height", blk.Height()))
+		if fromPeer {
+			bs.worker.Demote(peer)
+			bs.reportInvalidBlock(peer)
+		}
 	}
 
 	if needSync {
@@ -173,7 +314,10 @@ func (bs *blockSyncer) ProcessBlock(_ context.Context, blk *block.Block) error {
 	return nil
 }
 
-func (bs *blockSyncer) ProcessBlockSync(_ context.Context, blk *block.Block) error {
+func (bs *blockSyncer) ProcessBlockSync(ctx context.Context, blk *block.Block) error {
+	if peer, ok := getPeer(ctx); ok && blk != nil {
+		bs.worker.Deliver(peer, blk.Height())
+	}
 	bs.buf.Flush(blk)
 	if bs.bc.TipHeight() == bs.TargetHeight() {
 		bs.worker.SetTargetHeight(bs.TargetHeight() + bs.buf.bufSize())
@@ -181,7 +325,8 @@ func (bs *blockSyncer) ProcessBlockSync(_ context.Context, blk *block.Block) err
 	return nil
 }
 
-// ProcessSyncRequest processes a block sync request
+// ProcessSyncRequest processes a block sync request. The range is capped to the max blocks per request, and the
+// blocks beyond the quota of the peer are not served.
 func (bs *blockSyncer) ProcessSyncRequest(ctx context.Context, peer peerstore.PeerInfo, sync *iotexrpc.BlockSync) error {
 	end := bs.bc.TipHeight()
 	switch {
@@ -196,32 +341,114 @@ func (bs *blockSyncer) ProcessSyncRequest(ctx context.Context, peer peerstore.Pe
 			zap.Uint64("tipHeight", end),
 		)
 	}
+	end = bs.quota.Cap(sync.Start, end)
+	id := peer.ID.Pretty()
 	for i := sync.Start; i <= end; i++ {
+		if err := bs.quota.TakeBlock(id, time.Now()); err != nil {
+			return err
+		}
 		blk, err := bs.dao.GetBlockByHeight(i)
 		if err != nil {
 			return err
 		}
+		blkPb := blk.ConvertToBlockPb()
+		if err := bs.quota.TakeBytes(id, proto.Size(blkPb), time.Now()); err != nil {
+			return err
+		}
 		// TODO: send back multiple blocks in one shot
 		syncCtx, cancel := context.WithTimeout(ctx, bs.processSyncRequestTTL)
 		defer cancel()
-		if err := bs.unicastHandler(syncCtx, peer, blk.ConvertToBlockPb()); err != nil {
+		if err := bs.unicastHandler(syncCtx, peer, blkPb); err != nil {
 			log.L().Debug("Failed to response to ProcessSyncRequest.", zap.Error(err))
 		}
 	}
 	return nil
 }
 
+// ProcessHeaderRequest processes a request for the headers from start to end, up to the header batch size and the
+// quota of the peer
+func (bs *blockSyncer) ProcessHeaderRequest(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error {
+	if bs.sendHeadersHandler == nil {
+		return errors.New("serving headers is not enabled")
+	}
+	if start == 0 || start > end {
+		return errors.Errorf("invalid header range [%d, %d]", start, end)
+	}
+	// the headers above the tip are not sent, an empty response tells the peer it's synced to the tip
+	if tip := bs.bc.TipHeight(); end > tip {
+		end = tip
+	}
+	if end >= start && end-start >= bs.headerBatchSize {
+		end = start + bs.headerBatchSize - 1
+	}
+	var (
+		headers []*iotextypes.BlockHeader
+		size    int
+	)
+	for i := start; i <= end; i++ {
+		header, err := bs.bc.BlockHeaderByHeight(i)
+		if err != nil {
+			return err
+		}
+		pb := header.BlockHeaderProto()
+		headers = append(headers, pb)
+		size += proto.Size(pb)
+	}
+	if err := bs.quota.TakeBytes(peer.ID.Pretty(), size, time.Now()); err != nil {
+		return err
+	}
+	syncCtx, cancel := context.WithTimeout(ctx, bs.processSyncRequestTTL)
+	defer cancel()
+	return bs.sendHeadersHandler(syncCtx, peer, headers)
+}
+
+// ProcessHeaders processes the headers sent by a peer, which are ignored unless the headers are synced first
+func (bs *blockSyncer) ProcessHeaders(ctx context.Context, peer peerstore.PeerInfo, headers []*block.Header) error {
+	err := bs.worker.ReceiveHeaders(ctx, peer, headers)
+	if err != nil {
+		bs.reportInvalidBlock(peer.ID.Pretty())
+	}
+	return err
+}
+
+func (bs *blockSyncer) reportInvalidBlock(peer string) {
+	if bs.reportHandler != nil {
+		bs.reportHandler(peer)
+	}
+}
+
 func (bs *blockSyncer) syncStageChecker() {
-	tipHeight := bs.bc.TipHeight()
-	atomic.StoreUint64(&bs.syncBlockIncrease, tipHeight-bs.syncStageHeight)
-	bs.syncStageHeight = tipHeight
+	bs.progress.Add(bs.bc.TipHeight(), time.Now())
+	reportProgress(bs.syncProgress())
+}
+
+func (bs *blockSyncer) syncProgress() syncProgress {
+	return bs.progress.Progress(bs.bc.TipHeight(), bs.TargetHeight())
+}
+
+// SyncSpeed returns the blocks synced per second over the sliding windows of 1m, 5m and 15m
+func (bs *blockSyncer) SyncSpeed() map[string]float64 {
+	return bs.syncProgress().BlocksPerSecond
+}
+
+// SyncETA returns the estimated time to sync to the target height, 0 if synced and negative if unknown
+func (bs *blockSyncer) SyncETA() time.Duration {
+	return bs.syncProgress().ETA
 }
 
 // SyncStatus report block sync status
 func (bs *blockSyncer) SyncStatus() string {
-	syncBlockIncrease := atomic.LoadUint64(&bs.syncBlockIncrease)
-	if syncBlockIncrease == 1 {
+	p := bs.syncProgress()
+	if p.Synced() {
 		return "synced to blockchain tip"
 	}
-	return fmt.Sprintf("sync in progress at %.1f blocks/sec", float64(syncBlockIncrease)/config.DardanellesBlockInterval.Seconds())
+	status := fmt.Sprintf(
+		"sync in progress at %.1f blocks/sec, %d blocks to go",
+		p.BlocksPerSecond[syncRateWindows[0].name],
+		p.TargetHeight-p.TipHeight,
+	)
+	if p.ETA >= 0 {
+		status += fmt.Sprintf(", eta %s", p.ETA.Round(time.Second))
+	}
+	return status
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -469,3 +470,19 @@ func newTestConfig() (config.Config, error) {
 	cfg.Genesis.EnableGravityChainVoting = false
 	return cfg, nil
 }
+
+func TestValidateBlock(t *testing.T) {
+	require := require.New(t)
+	blk, err := block.NewTestingBuilder().
+		SetHeight(1).
+		SetPrevBlockHash(hash.ZeroHash256).
+		SetTimeStamp(testutil.TimestampNow()).
+		SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+	pb := blk.ConvertToBlockPb()
+	require.NoError(ValidateBlock(pb))
+	// the block is not signed by its producer
+	pb.Header.Core.Height = 2
+	require.Contains(ValidateBlock(pb).Error(), "failed to verify block's signature")
+	require.Error(ValidateBlock(&iotextypes.Block{}))
+}
@@ -7,8 +7,10 @@
 package blocksync
 
 import (
+	"runtime"
 	"sync"
 
+	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-election/db"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -28,6 +30,7 @@ const (
 	bCheckinExisting
 	bCheckinHigher
 	bCheckinSkipNil
+	bCheckinMismatch
 )
 
 // blockBuffer is used to keep in-coming block in order.
@@ -39,6 +42,11 @@ type blockBuffer struct {
 	bufferSize   uint64
 	intervalSize uint64
 	commitHeight uint64 // last commit block height
+	// the trusted checkpoint, a block at its height on another fork is dropped
+	checkpointHeight uint64
+	checkpointHash   hash.Hash256
+	// the headers validated ahead of the blocks, nil if the headers are not synced first
+	headers *headerChain
 }
 
 // CommitHeight return the last commit block height
@@ -62,6 +70,14 @@ func (b *blockBuffer) Flush(blk *block.Block) (bool, bCheckinResult) {
 	if _, ok := b.blocks[blkHeight]; ok {
 		return false, bCheckinExisting
 	}
+	if b.checkpointHeight > 0 && blkHeight == b.checkpointHeight && blk.HashBlock() != b.checkpointHash {
+		return false, bCheckinMismatch
+	}
+	if b.headers != nil {
+		if h, ok := b.headers.Hash(blkHeight); ok && h != blk.HashBlock() {
+			return false, bCheckinMismatch
+		}
+	}
 	if blkHeight > confirmedHeight+b.bufferSize {
 		return false, bCheckinHigher
 	}
@@ -70,14 +86,23 @@ func (b *blockBuffer) Flush(blk *block.Block) (bool, bCheckinResult) {
 		zap.Uint64("recvHeight", blkHeight),
 		zap.Uint64("confirmedHeight", confirmedHeight),
 		zap.String("source", "blockBuffer"))
-	var heightToSync uint64
-	for heightToSync = confirmedHeight + 1; heightToSync <= confirmedHeight+b.bufferSize; heightToSync++ {
-		blk, ok := b.blocks[heightToSync]
+	var blks []*block.Block
+	for h := confirmedHeight + 1; h <= confirmedHeight+b.bufferSize; h++ {
+		blk, ok := b.blocks[h]
 		if !ok {
 			break
 		}
+		blks = append(blks, blk)
+	}
+	// the next blocks are prevalidated while the current one executes
+	done := make(chan struct{})
+	defer close(done)
+	results := prevalidate(blks, done)
+	var heightToSync uint64
+	for heightToSync = confirmedHeight + 1; heightToSync <= confirmedHeight+uint64(len(blks)); heightToSync++ {
+		i := heightToSync - confirmedHeight - 1
 		delete(b.blocks, heightToSync)
-		if err := commitBlock(b.bc, b.cs, blk); err != nil && errors.Cause(err) != blockchain.ErrInvalidTipHeight {
+		if err := commitBlock(b.bc, b.cs, blks[i], results[i]); err != nil && errors.Cause(err) != blockchain.ErrInvalidTipHeight {
 			if errors.Cause(err) == poll.ErrProposedDelegatesLength || errors.Cause(err) == poll.ErrDelegatesNotAsExpected || errors.Cause(err) == db.ErrNotExist {
 				l.Debug("Failed to commit the block.", zap.Error(err), zap.Uint64("syncHeight", heightToSync))
 			} else {
@@ -102,6 +127,30 @@ func (b *blockBuffer) Flush(blk *block.Block) (bool, bCheckinResult) {
 	return heightToSync > blkHeight, bCheckinValid
 }
 
+// prevalidate runs block.Prevalidate on the blocks ahead of their commit, on up to one block per CPU at a time. The
+// result of each block is sent to its channel, and the blocks not started yet are dropped once done is closed.
+func prevalidate(blks []*block.Block, done <-chan struct{}) []chan error {
+	results := make([]chan error, len(blks))
+	for i := range results {
+		results[i] = make(chan error, 1)
+	}
+	go func() {
+		sem := make(chan struct{}, runtime.NumCPU())
+		for i, blk := range blks {
+			select {
+			case sem <- struct{}{}:
+			case <-done:
+				return
+			}
+			go func(blk *block.Block, result chan<- error) {
+				result <- block.Prevalidate(blk)
+				<-sem
+			}(blk, results[i])
+		}
+	}()
+	return results
+}
+
 // GetBlocksIntervalsToSync returns groups of syncBlocksInterval are missing upto targetHeight.
 func (b *blockBuffer) GetBlocksIntervalsToSync(targetHeight uint64) []syncBlocksInterval {
 	var (
@@ -152,6 +201,13 @@ func (b *blockBuffer) GetBlocksIntervalsToSync(targetHeight uint64) []syncBlocks
 	return bi
 }
 
+// Clear drops the blocks in the buffer
+func (b *blockBuffer) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blocks = make(map[uint64]*block.Block)
+}
+
 // bufSize return the bufferSize of buffer
 func (b *blockBuffer) bufSize() uint64 {
 	return b.bufferSize
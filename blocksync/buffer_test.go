@@ -8,6 +8,7 @@ package blocksync
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/account"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
@@ -128,6 +130,21 @@ func TestBlockBufferFlush(t *testing.T) {
 	moved, re = b.Flush(blk)
 	assert.Equal(false, moved)
 	assert.Equal(bCheckinHigher, re)
+
+	// a block at the checkpoint height on another fork is dropped
+	b.checkpointHeight = 6
+	b.checkpointHash = hash.Hash256b([]byte("checkpoint"))
+	blk = block.NewBlockDeprecated(
+		uint32(123),
+		uint64(6),
+		hash.Hash256{},
+		testutil.TimestampNow(),
+		identityset.PrivateKey(27).PublicKey(),
+		nil,
+	)
+	moved, re = b.Flush(blk)
+	assert.Equal(false, moved)
+	assert.Equal(bCheckinMismatch, re)
 }
 
 func TestBlockBufferGetBlocksIntervalsToSync(t *testing.T) {
@@ -281,3 +298,46 @@ func TestBlockBufferGetBlocksIntervalsToSync(t *testing.T) {
 	// There should always have at least 1 interval range to sync
 	assert.Len(b.GetBlocksIntervalsToSync(0), 1)
 }
+
+func TestPrevalidate(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		blks []*block.Block
+		prev = hash.ZeroHash256
+	)
+	tsf1, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), 1, big.NewInt(20), []byte{}, 100000, big.NewInt(10))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(identityset.Address(29).String(), identityset.PrivateKey(27), 2, big.NewInt(30), []byte{}, 100000, big.NewInt(10))
+	require.NoError(err)
+	for i := uint64(1); i <= 20; i++ {
+		selp := tsf2
+		if i == 11 {
+			// the action of the block has a wrong signature
+			selp = action.AssembleSealedEnvelope(tsf2.Envelope, tsf2.SrcPubkey(), tsf1.Signature())
+		}
+		blk, err := block.NewTestingBuilder().
+			SetHeight(i).
+			SetPrevBlockHash(prev).
+			SetTimeStamp(testutil.TimestampNow()).
+			AddActions(selp).
+			SignAndBuild(identityset.PrivateKey(27))
+		require.NoError(err)
+		blks = append(blks, &blk)
+		prev = blk.HashBlock()
+	}
+
+	done := make(chan struct{})
+	results := prevalidate(blks, done)
+	for i, blk := range blks {
+		err := <-results[i]
+		if i == 10 {
+			require.Error(err)
+			require.False(blk.Prevalidated())
+			break
+		}
+		require.NoError(err)
+		require.True(blk.Prevalidated())
+	}
+	close(done)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"sync"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+// maxHeadersAhead is the max number of validated headers kept ahead of the chain tip
+const maxHeadersAhead = 100000
+
+// headerChain is the chain of the headers validated ahead of the blocks. Each header has to link to the one below it,
+// the lowest one to the chain tip, and to be signed by its producer. The blocks synced later have to match the
+// headers, so their bodies can be fetched from any peer in parallel.
+type headerChain struct {
+	mu      sync.RWMutex
+	bc      blockchain.Blockchain
+	headers map[uint64]*block.Header
+	tip     uint64
+	// the trusted checkpoint, a header at its height on another fork is rejected
+	checkpointHeight uint64
+	checkpointHash   hash.Hash256
+}
+
+func newHeaderChain(bc blockchain.Blockchain, checkpointHeight uint64, checkpointHash hash.Hash256) *headerChain {
+	return &headerChain{
+		bc:               bc,
+		headers:          make(map[uint64]*block.Header),
+		checkpointHeight: checkpointHeight,
+		checkpointHash:   checkpointHash,
+	}
+}
+
+// Tip returns the height of the highest validated header, or the chain tip if it's higher
+func (c *headerChain) Tip() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.prune()
+}
+
+// Full returns true if no more headers are accepted until the blocks catch up
+func (c *headerChain) Full() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.prune() >= c.bc.TipHeight()+maxHeadersAhead
+}
+
+// Hash returns the hash of the validated header at the height
+func (c *headerChain) Hash(height uint64) (hash.Hash256, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	header, ok := c.headers[height]
+	if !ok {
+		return hash.ZeroHash256, false
+	}
+	return header.HashHeader(), true
+}
+
+// Append validates the headers in ascending order and appends them to the chain. The headers at or below the tip are
+// skipped, and those following an invalid one are dropped. The number of the headers appended is returned.
+func (c *headerChain) Append(headers []*block.Header) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tip := c.prune()
+	var appended int
+	for _, header := range headers {
+		height := header.Height()
+		if height <= tip {
+			continue
+		}
+		if height > c.bc.TipHeight()+maxHeadersAhead {
+			break
+		}
+		if height != tip+1 {
+			return appended, errors.Errorf("header %d is not contiguous to %d", height, tip)
+		}
+		if prev, ok := c.headers[tip]; ok {
+			if header.PrevHash() != prev.HashHeader() {
+				return appended, errors.Errorf("header %d doesn't link to the previous header", height)
+			}
+			if !header.Timestamp().After(prev.Timestamp()) {
+				return appended, errors.Errorf("header %d is not later than the previous header", height)
+			}
+		} else if header.PrevHash() != c.bc.TipHash() {
+			return appended, errors.Errorf("header %d doesn't link to the chain tip", height)
+		}
+		if !header.VerifySignature() {
+			return appended, errors.Errorf("failed to verify the signature of header %d", height)
+		}
+		if c.checkpointHeight > 0 && height == c.checkpointHeight && header.HashHeader() != c.checkpointHash {
+			return appended, errors.Errorf("header %d doesn't match the checkpoint", height)
+		}
+		c.headers[height] = header
+		c.tip = height
+		tip = height
+		appended++
+	}
+	return appended, nil
+}
+
+// Reset drops all the validated headers, which are synced again from the chain tip
+func (c *headerChain) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers = make(map[uint64]*block.Header)
+	c.tip = c.bc.TipHeight()
+}
+
+// prune drops the headers at or below the chain tip, and all of them if they no longer link to the chain tip, which
+// has been committed on another fork. The tip of the headers is returned.
+func (c *headerChain) prune() uint64 {
+	confirmedHeight := c.bc.TipHeight()
+	if next, ok := c.headers[confirmedHeight+1]; c.tip <= confirmedHeight || ok && next.PrevHash() != c.bc.TipHash() {
+		c.headers = make(map[uint64]*block.Header)
+		c.tip = confirmedHeight
+		return c.tip
+	}
+	for height := range c.headers {
+		if height <= confirmedHeight {
+			delete(c.headers, height)
+		}
+	}
+	return c.tip
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+)
+
+func TestHeaderChain(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	genesisHash := hash.Hash256b([]byte("genesis"))
+	now := time.Now()
+	var blks []*block.Block
+	for i, prev := 0, genesisHash; i < 5; i++ {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(uint64(i + 1)).
+			SetPrevBlockHash(prev).
+			SetTimeStamp(now.Add(time.Duration(i) * time.Second)).
+			SignAndBuild(identityset.PrivateKey(i))
+		require.NoError(err)
+		blks = append(blks, &blk)
+		prev = blk.HashBlock()
+	}
+	headers := func(start, end int) []*block.Header {
+		var hs []*block.Header
+		for _, blk := range blks[start-1 : end] {
+			h := blk.Header
+			hs = append(hs, &h)
+		}
+		return hs
+	}
+
+	tipHeight, tipHash := uint64(0), genesisHash
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().TipHeight().DoAndReturn(func() uint64 { return tipHeight }).AnyTimes()
+	chain.EXPECT().TipHash().DoAndReturn(func() hash.Hash256 { return tipHash }).AnyTimes()
+
+	c := newHeaderChain(chain, 4, hash.ZeroHash256)
+	require.Equal(uint64(0), c.Tip())
+
+	// the headers link to the chain tip and to each other
+	appended, err := c.Append(headers(1, 2))
+	require.NoError(err)
+	require.Equal(2, appended)
+	require.Equal(uint64(2), c.Tip())
+	h, ok := c.Hash(2)
+	require.True(ok)
+	require.Equal(blks[1].HashBlock(), h)
+	_, ok = c.Hash(3)
+	require.False(ok)
+
+	// the headers at or below the tip are skipped
+	appended, err = c.Append(headers(1, 3))
+	require.NoError(err)
+	require.Equal(1, appended)
+	require.Equal(uint64(3), c.Tip())
+
+	// the header not matching the checkpoint is rejected
+	appended, err = c.Append(headers(4, 5))
+	require.Error(err)
+	require.Equal(0, appended)
+	require.Equal(uint64(3), c.Tip())
+
+	c = newHeaderChain(chain, 4, blks[3].HashBlock())
+	appended, err = c.Append(headers(1, 5))
+	require.NoError(err)
+	require.Equal(5, appended)
+
+	// the headers are pruned as the blocks are committed
+	tipHeight, tipHash = 2, blks[1].HashBlock()
+	require.Equal(uint64(5), c.Tip())
+	_, ok = c.Hash(2)
+	require.False(ok)
+	_, ok = c.Hash(3)
+	require.True(ok)
+
+	// all the headers are dropped once the chain tip is committed on another fork
+	tipHash = hash.Hash256b([]byte("fork"))
+	require.Equal(uint64(2), c.Tip())
+	_, ok = c.Hash(3)
+	require.False(ok)
+
+	// the headers not linking to the tip, not contiguous or not signed are rejected
+	tipHeight, tipHash = 0, genesisHash
+	c = newHeaderChain(chain, 0, hash.ZeroHash256)
+	_, err = c.Append(headers(2, 3))
+	require.Error(err)
+	tipHeight, tipHash = 1, hash.Hash256b([]byte("fork"))
+	_, err = c.Append(headers(2, 3))
+	require.Error(err)
+	tipHash = blks[0].HashBlock()
+	_, err = c.Append(append(headers(2, 2), headers(4, 4)...))
+	require.Error(err)
+	require.Equal(uint64(2), c.Tip())
+	pb := blks[2].Header.BlockHeaderProto()
+	pb.Signature = blks[3].Header.BlockHeaderProto().Signature
+	unsigned := &block.Header{}
+	require.NoError(unsigned.LoadFromBlockHeaderProto(pb))
+	_, err = c.Append([]*block.Header{unsigned})
+	require.Error(err)
+	require.Contains(err.Error(), "signature")
+	require.Equal(uint64(2), c.Tip())
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"time"
+)
+
+// peerStatsDecay is the weight of the latest response in the moving averages of the latency and the failure rate
+const peerStatsDecay = 0.2
+
+type peerCtxKey struct{}
+
+// WithPeer attaches to the context the ID of the peer a block is received from, whose responses are scored
+func WithPeer(ctx context.Context, peer string) context.Context {
+	return context.WithValue(ctx, peerCtxKey{}, peer)
+}
+
+// getPeer returns the ID of the peer a block is received from
+func getPeer(ctx context.Context) (string, bool) {
+	peer, ok := ctx.Value(peerCtxKey{}).(string)
+	return peer, ok && peer != ""
+}
+
+type (
+	// peerStats is the record of the responses of a peer to the sync requests
+	peerStats struct {
+		// latency is the moving average of the time from a request to the first block of the response
+		latency time.Duration
+		// failureRate is the moving average of the requests not responded in time
+		failureRate float64
+		// height is the highest block the peer has sent, which it's known to have
+		height       uint64
+		demotedUntil time.Time
+	}
+
+	// peerScorer scores the peers by their responses to the sync requests, the sync requests are sent to the peers of
+	// higher scores. The peers failing too many requests or sending blocks of another chain are demoted for a while,
+	// during which they're requested only if there is no other peer.
+	peerScorer struct {
+		demotion       time.Duration
+		maxFailureRate float64
+		peers          map[string]*peerStats
+	}
+)
+
+func newPeerScorer(demotion time.Duration, maxFailureRate float64) *peerScorer {
+	return &peerScorer{
+		demotion:       demotion,
+		maxFailureRate: maxFailureRate,
+		peers:          make(map[string]*peerStats),
+	}
+}
+
+func (s *peerScorer) stats(peer string) *peerStats {
+	stats, ok := s.peers[peer]
+	if !ok {
+		stats = &peerStats{}
+		s.peers[peer] = stats
+	}
+	return stats
+}
+
+// Observe records that the peer has the block of the height
+func (s *peerScorer) Observe(peer string, height uint64) {
+	if stats := s.stats(peer); height > stats.height {
+		stats.height = height
+	}
+}
+
+// Succeed records a request responded by the peer with the latency
+func (s *peerScorer) Succeed(peer string, latency time.Duration) {
+	stats := s.stats(peer)
+	if stats.latency == 0 {
+		stats.latency = latency
+	} else {
+		stats.latency = time.Duration(float64(stats.latency)*(1-peerStatsDecay) + float64(latency)*peerStatsDecay)
+	}
+	stats.failureRate *= 1 - peerStatsDecay
+}
+
+// Fail records a request not responded by the peer in time, the peer is demoted if it fails too many requests
+func (s *peerScorer) Fail(peer string, now time.Time) {
+	stats := s.stats(peer)
+	stats.failureRate = stats.failureRate*(1-peerStatsDecay) + peerStatsDecay
+	if stats.failureRate > s.maxFailureRate {
+		s.Demote(peer, now)
+	}
+}
+
+// Demote demotes the misbehaving peer. The failure rate restarts from the max, so the peer is demoted again by the
+// next failure unless it has responded to the requests after the demotion
+func (s *peerScorer) Demote(peer string, now time.Time) {
+	stats := s.stats(peer)
+	stats.demotedUntil = now.Add(s.demotion)
+	stats.failureRate = s.maxFailureRate
+}
+
+// Demoted returns true if the peer is demoted
+func (s *peerScorer) Demoted(peer string, now time.Time) bool {
+	stats, ok := s.peers[peer]
+	return ok && now.Before(stats.demotedUntil)
+}
+
+// Height returns the highest block the peer is known to have, 0 if unknown
+func (s *peerScorer) Height(peer string) uint64 {
+	if stats, ok := s.peers[peer]; ok {
+		return stats.height
+	}
+	return 0
+}
+
+// Score returns the score of the peer, which is higher for a lower failure rate and latency. A peer without any
+// response yet scores the highest so it gets requested.
+func (s *peerScorer) Score(peer string) float64 {
+	stats, ok := s.peers[peer]
+	if !ok {
+		return 1
+	}
+	return (1 - stats.failureRate) / (1 + stats.latency.Seconds())
+}
+
+// Retain drops the stats of the peers no longer connected, except those still demoted
+func (s *peerScorer) Retain(connected map[string]bool, now time.Time) {
+	for peer, stats := range s.peers {
+		if !connected[peer] && !now.Before(stats.demotedUntil) {
+			delete(s.peers, peer)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScorer(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	s := newPeerScorer(time.Minute, 0.5)
+	require.Equal(float64(1), s.Score("a"))
+	require.Zero(s.Height("a"))
+
+	s.Observe("a", 10)
+	s.Observe("a", 5)
+	require.Equal(uint64(10), s.Height("a"))
+	s.Succeed("a", time.Second)
+	require.Equal(0.5, s.Score("a"))
+	s.Succeed("b", 0)
+	require.True(s.Score("b") > s.Score("a"))
+
+	// the peer failing too many requests is demoted
+	for i := 0; i < 3; i++ {
+		s.Fail("b", now)
+		require.False(s.Demoted("b", now))
+	}
+	s.Fail("b", now)
+	require.True(s.Demoted("b", now))
+	require.False(s.Demoted("b", now.Add(time.Minute)))
+	// the demoted peer is demoted again by the next failure unless it has responded
+	s.Fail("b", now.Add(time.Minute))
+	require.True(s.Demoted("b", now.Add(time.Minute)))
+	s.Succeed("b", 0)
+	s.Succeed("b", 0)
+	s.Fail("b", now.Add(2*time.Minute))
+	require.False(s.Demoted("b", now.Add(2*time.Minute)))
+
+	// the stats of the disconnected peers are dropped unless they're demoted
+	s.Demote("c", now)
+	s.Retain(map[string]bool{"b": true}, now)
+	require.Zero(s.Height("a"))
+	require.True(s.Demoted("c", now))
+	s.Retain(map[string]bool{"b": true}, now.Add(time.Minute))
+	require.Len(s.peers, 1)
+}
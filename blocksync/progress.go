@@ -0,0 +1,151 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// syncRateWindows are the sliding windows over which the sync speed is measured, in ascending order
+var syncRateWindows = []struct {
+	name   string
+	length time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+}
+
+var (
+	syncHeightMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_blocksync_height",
+			Help: "Tip and target heights of block sync.",
+		},
+		[]string{"type"},
+	)
+	syncRateMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_blocksync_rate",
+			Help: "Blocks synced per second over the sliding windows.",
+		},
+		[]string{"window"},
+	)
+	syncETAMtc = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "iotex_blocksync_eta_seconds",
+			Help: "Estimated seconds to sync to the target height, -1 if unknown.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(syncHeightMtc)
+	prometheus.MustRegister(syncRateMtc)
+	prometheus.MustRegister(syncETAMtc)
+}
+
+type (
+	// syncProgress is the progress of the sync towards the target height
+	syncProgress struct {
+		TipHeight    uint64
+		TargetHeight uint64
+		// BlocksPerSecond is the sync speed over the sliding windows of 1m, 5m and 15m
+		BlocksPerSecond map[string]float64
+		// ETA is the estimated time to reach the target height at the speed over the longest window, 0 if synced and
+		// negative if unknown
+		ETA time.Duration
+	}
+
+	heightSample struct {
+		at     time.Time
+		height uint64
+	}
+
+	// progressTracker samples the tip height periodically to measure the sync speed over the sliding windows
+	progressTracker struct {
+		mu      sync.RWMutex
+		samples []heightSample
+	}
+)
+
+// Synced returns true if the tip has reached the target height
+func (p syncProgress) Synced() bool {
+	return p.TipHeight >= p.TargetHeight
+}
+
+// Add records the tip height at the time, the samples beyond the longest window are dropped
+func (t *progressTracker) Add(height uint64, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cutoff := now.Add(-syncRateWindows[len(syncRateWindows)-1].length)
+	var i int
+	for i < len(t.samples) && t.samples[i].at.Before(cutoff) {
+		i++
+	}
+	t.samples = append(t.samples[i:], heightSample{at: now, height: height})
+}
+
+// Progress returns the sync progress from the tip towards the target height
+func (t *progressTracker) Progress(tip, target uint64) syncProgress {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if target < tip {
+		target = tip
+	}
+	p := syncProgress{
+		TipHeight:       tip,
+		TargetHeight:    target,
+		BlocksPerSecond: make(map[string]float64, len(syncRateWindows)),
+	}
+	for _, window := range syncRateWindows {
+		p.BlocksPerSecond[window.name] = t.rate(window.length)
+	}
+	switch rate := p.BlocksPerSecond[syncRateWindows[len(syncRateWindows)-1].name]; {
+	case p.Synced():
+	case rate <= 0:
+		p.ETA = -1
+	default:
+		p.ETA = time.Duration(float64(target-tip) / rate * float64(time.Second))
+	}
+	return p
+}
+
+// rate returns the blocks per second from the earliest sample within the window to the latest one
+func (t *progressTracker) rate(window time.Duration) float64 {
+	if len(t.samples) < 2 {
+		return 0
+	}
+	last := t.samples[len(t.samples)-1]
+	for _, s := range t.samples {
+		if last.at.Sub(s.at) > window {
+			continue
+		}
+		if s.height >= last.height || !last.at.After(s.at) {
+			return 0
+		}
+		return float64(last.height-s.height) / last.at.Sub(s.at).Seconds()
+	}
+	return 0
+}
+
+// reportProgress exports the sync progress as metrics
+func reportProgress(p syncProgress) {
+	syncHeightMtc.WithLabelValues("tip").Set(float64(p.TipHeight))
+	syncHeightMtc.WithLabelValues("target").Set(float64(p.TargetHeight))
+	for window, rate := range p.BlocksPerSecond {
+		syncRateMtc.WithLabelValues(window).Set(rate)
+	}
+	if p.ETA < 0 {
+		syncETAMtc.Set(-1)
+	} else {
+		syncETAMtc.Set(p.ETA.Seconds())
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressTracker(t *testing.T) {
+	require := require.New(t)
+	tracker := &progressTracker{}
+
+	// the speed and the eta are unknown before the second sample
+	now := time.Now()
+	tracker.Add(100, now)
+	p := tracker.Progress(100, 200)
+	require.False(p.Synced())
+	require.Zero(p.BlocksPerSecond["1m"])
+	require.Equal(time.Duration(-1), p.ETA)
+
+	// 10 blocks/sec in the first 10 minutes, then 2 blocks/sec
+	height := uint64(100)
+	for i := 0; i < 120; i++ {
+		now = now.Add(5 * time.Second)
+		height += 50
+		tracker.Add(height, now)
+	}
+	for i := 0; i < 60; i++ {
+		now = now.Add(5 * time.Second)
+		height += 10
+		tracker.Add(height, now)
+	}
+	p = tracker.Progress(height, height+1000)
+	require.InDelta(2, p.BlocksPerSecond["1m"], 1e-9)
+	require.InDelta(2, p.BlocksPerSecond["5m"], 1e-9)
+	// the 15m window spans 5m at 2 blocks/sec and 10m at 10 blocks/sec
+	require.InDelta(float64(6000+600)/900, p.BlocksPerSecond["15m"], 1e-9)
+	require.Equal(time.Duration(1000/p.BlocksPerSecond["15m"]*float64(time.Second)), p.ETA)
+	require.Len(tracker.samples, 181)
+
+	// the target below the tip means synced
+	p = tracker.Progress(height, height-1)
+	require.True(p.Synced())
+	require.Equal(height, p.TargetHeight)
+	require.Zero(p.ETA)
+
+	// no progress makes the eta unknown
+	for i := 0; i < 180; i++ {
+		now = now.Add(5 * time.Second)
+		tracker.Add(height, now)
+	}
+	p = tracker.Progress(height, height+1)
+	require.Zero(p.BlocksPerSecond["15m"])
+	require.Equal(time.Duration(-1), p.ETA)
+}
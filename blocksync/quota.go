@@ -0,0 +1,117 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"time"
+
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+var (
+	// ErrQuotaExceeded indicates the peer has exceeded its quota of the blocks served
+	ErrQuotaExceeded = errors.New("block sync quota exceeded")
+
+	quotaExceededMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_blocksync_quota_exceeded_total",
+			Help: "Number of block sync responses cut short by the serve quota of the peer",
+		},
+		[]string{"limit"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(quotaExceededMtc)
+}
+
+type (
+	// peerQuota is the token buckets of a peer, a nil bucket is unlimited
+	peerQuota struct {
+		blocks *rate.Limiter
+		bytes  *rate.Limiter
+	}
+
+	// servingQuota charges the blocks and the bytes served to each peer against its token buckets, so that a peer
+	// requesting huge ranges repeatedly can't exhaust the disk and the upload bandwidth of the node
+	servingQuota struct {
+		cfg   config.ServeQuota
+		peers *cache.ThreadSafeLruCache
+	}
+)
+
+func newServingQuota(cfg config.ServeQuota) *servingQuota {
+	q := &servingQuota{cfg: cfg}
+	if cfg.BlocksPerSecond > 0 || cfg.BytesPerSecond > 0 {
+		q.peers = cache.NewThreadSafeLruCache(cfg.MaxPeers)
+	}
+	return q
+}
+
+// Cap caps the range requested to the max number of blocks per request
+func (q *servingQuota) Cap(start, end uint64) uint64 {
+	if end >= start && end-start >= q.cfg.MaxBlocksPerRequest {
+		return start + q.cfg.MaxBlocksPerRequest - 1
+	}
+	return end
+}
+
+// TakeBlock charges a block to the peer, before it's read
+func (q *servingQuota) TakeBlock(peer string, now time.Time) error {
+	if q.peers == nil {
+		return nil
+	}
+	if !take(q.quota(peer).blocks, now, 1) {
+		quotaExceededMtc.WithLabelValues("blocks").Inc()
+		return errors.Wrapf(ErrQuotaExceeded, "blocks of peer %s", peer)
+	}
+	return nil
+}
+
+// TakeBytes charges the size of a response to the peer, before it's sent
+func (q *servingQuota) TakeBytes(peer string, size int, now time.Time) error {
+	if q.peers == nil {
+		return nil
+	}
+	if !take(q.quota(peer).bytes, now, size) {
+		quotaExceededMtc.WithLabelValues("bytes").Inc()
+		return errors.Wrapf(ErrQuotaExceeded, "bytes of peer %s", peer)
+	}
+	return nil
+}
+
+func (q *servingQuota) quota(peer string) *peerQuota {
+	if v, ok := q.peers.Get(peer); ok {
+		return v.(*peerQuota)
+	}
+	// concurrent first requests of a peer may each create the buckets, only one of them is kept
+	quota := &peerQuota{}
+	if q.cfg.BlocksPerSecond > 0 {
+		quota.blocks = rate.NewLimiter(rate.Limit(q.cfg.BlocksPerSecond), q.cfg.BlockBurst)
+	}
+	if q.cfg.BytesPerSecond > 0 {
+		quota.bytes = rate.NewLimiter(rate.Limit(q.cfg.BytesPerSecond), q.cfg.ByteBurst)
+	}
+	q.peers.Add(peer, quota)
+	return quota
+}
+
+// take takes the tokens from the bucket, a cost above the burst takes the whole bucket
+func take(limiter *rate.Limiter, now time.Time, cost int) bool {
+	if limiter == nil {
+		return true
+	}
+	if cost > limiter.Burst() {
+		cost = limiter.Burst()
+	}
+	return limiter.AllowN(now, cost)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockdao"
+	"github.com/iotexproject/iotex-core/test/mock/mock_consensus"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServingQuota(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	q := newServingQuota(config.ServeQuota{
+		MaxBlocksPerRequest: 10,
+		BlocksPerSecond:     1,
+		BlockBurst:          2,
+		BytesPerSecond:      100,
+		ByteBurst:           1000,
+		MaxPeers:            10,
+	})
+	require.Equal(uint64(10), q.Cap(1, 100))
+	require.Equal(uint64(5), q.Cap(1, 5))
+	require.Equal(uint64(0), q.Cap(1, 0))
+
+	// the buckets are per peer
+	require.NoError(q.TakeBlock("a", now))
+	require.NoError(q.TakeBlock("a", now))
+	require.Equal(ErrQuotaExceeded, errors.Cause(q.TakeBlock("a", now)))
+	require.NoError(q.TakeBlock("b", now))
+	require.NoError(q.TakeBlock("a", now.Add(time.Second)))
+
+	// a response larger than the burst takes the whole bucket
+	require.NoError(q.TakeBytes("a", 5000, now))
+	require.Equal(ErrQuotaExceeded, errors.Cause(q.TakeBytes("a", 1, now)))
+	require.NoError(q.TakeBytes("a", 100, now.Add(time.Second)))
+
+	// the quota is disabled by 0 rates
+	q = newServingQuota(config.ServeQuota{MaxBlocksPerRequest: 10})
+	for i := 0; i < 100; i++ {
+		require.NoError(q.TakeBlock("a", now))
+		require.NoError(q.TakeBytes("a", 1<<30, now))
+	}
+}
+
+func TestBlockSyncerServingQuota(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := newTestConfig()
+	require.NoError(err)
+	cfg.BlockSync.ServeQuota = config.ServeQuota{
+		MaxBlocksPerRequest: 8,
+		BlocksPerSecond:     0.001,
+		BlockBurst:          5,
+		MaxPeers:            10,
+	}
+	blk, err := block.NewTestingBuilder().
+		SetHeight(1).
+		SetPrevBlockHash(hash.ZeroHash256).
+		SetTimeStamp(testutil.TimestampNow()).
+		SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().ChainID().Return(cfg.Chain.ID).AnyTimes()
+	chain.EXPECT().TipHeight().Return(uint64(100)).AnyTimes()
+	dao := mock_blockdao.NewMockBlockDAO(ctrl)
+	dao.EXPECT().GetBlockByHeight(gomock.Any()).Return(&blk, nil).AnyTimes()
+	served := make(map[string]int)
+	bs, err := NewBlockSyncer(
+		cfg,
+		chain,
+		dao,
+		mock_consensus.NewMockConsensus(ctrl),
+		WithUnicastOutBound(func(_ context.Context, peer peerstore.PeerInfo, _ proto.Message) error {
+			served[peer.ID.Pretty()]++
+			return nil
+		}),
+		WithNeighbors(func(_ context.Context) ([]peerstore.PeerInfo, error) { return nil, nil }),
+	)
+	require.NoError(err)
+
+	// the range is capped, and the blocks beyond the quota are not served
+	ctx := context.Background()
+	a, b := peerstore.PeerInfo{ID: "a"}, peerstore.PeerInfo{ID: "b"}
+	require.NoError(bs.ProcessSyncRequest(ctx, a, &iotexrpc.BlockSync{Start: 1, End: 3}))
+	require.Equal(3, served[a.ID.Pretty()])
+	err = bs.ProcessSyncRequest(ctx, a, &iotexrpc.BlockSync{Start: 4, End: 100})
+	require.Equal(ErrQuotaExceeded, errors.Cause(err))
+	require.Equal(5, served[a.ID.Pretty()])
+	require.NoError(bs.ProcessSyncRequest(ctx, b, &iotexrpc.BlockSync{Start: 1, End: 4}))
+	require.Equal(4, served[b.ID.Pretty()])
+
+	cfg.BlockSync.ServeQuota.BlockBurst = 100
+	bs, err = NewBlockSyncer(
+		cfg,
+		chain,
+		dao,
+		mock_consensus.NewMockConsensus(ctrl),
+		WithUnicastOutBound(func(_ context.Context, peer peerstore.PeerInfo, _ proto.Message) error {
+			served[peer.ID.Pretty()]++
+			return nil
+		}),
+	)
+	require.NoError(err)
+	require.NoError(bs.ProcessSyncRequest(ctx, b, &iotexrpc.BlockSync{Start: 1, End: 100}))
+	require.Equal(4+8, served[b.ID.Pretty()])
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"time"
+
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+)
+
+type (
+	// segment is a range of missing blocks requested from a peer
+	segment struct {
+		syncBlocksInterval
+		peer        string
+		requestedAt time.Time
+		responded   bool
+		// failed is the set of peers which have failed to deliver the blocks of the segment
+		failed map[string]bool
+	}
+
+	// segmentRequest is a segment to request from the peer
+	segmentRequest struct {
+		syncBlocksInterval
+		peer peerstore.PeerInfo
+	}
+
+	// segmentScheduler splits the missing blocks into segments requested from different peers concurrently, preferring
+	// the peers of higher scores. A segment not delivered in time is re-requested from another peer.
+	segmentScheduler struct {
+		ttl        time.Duration
+		maxPerPeer int
+		segments   map[uint64]*segment
+		scorer     *peerScorer
+	}
+)
+
+func newSegmentScheduler(ttl time.Duration, maxPerPeer int, scorer *peerScorer) *segmentScheduler {
+	if maxPerPeer <= 0 {
+		maxPerPeer = 1
+	}
+	return &segmentScheduler{
+		ttl:        ttl,
+		maxPerPeer: maxPerPeer,
+		segments:   make(map[uint64]*segment),
+		scorer:     scorer,
+	}
+}
+
+// Schedule matches the missing intervals in ascending order against the segments in flight, and returns the requests
+// of the intervals not requested yet or whose segments have expired. The lower intervals are assigned first, so that
+// the blocks are assembled in order as early as possible. The segments no longer missing are completed.
+func (s *segmentScheduler) Schedule(intervals []syncBlocksInterval, peers []peerstore.PeerInfo, now time.Time) []segmentRequest {
+	available := make(map[string]bool, len(peers))
+	for _, p := range peers {
+		available[p.ID.Pretty()] = true
+	}
+	s.scorer.Retain(available, now)
+	segments := make(map[uint64]*segment, len(intervals))
+	load := make(map[string]int)
+	var pending []*segment
+	for _, interval := range intervals {
+		old := s.covering(interval.Start)
+		if old != nil && now.Sub(old.requestedAt) < s.ttl && available[old.peer] {
+			// the blocks are on the way
+			segments[interval.Start] = &segment{
+				syncBlocksInterval: interval,
+				peer:               old.peer,
+				requestedAt:        old.requestedAt,
+				responded:          old.responded,
+				failed:             old.failed,
+			}
+			load[old.peer]++
+			continue
+		}
+		failed := make(map[string]bool)
+		if old != nil {
+			if !old.failed[old.peer] && !old.requestedAt.IsZero() {
+				// the segment is failed once, however many intervals are left of it
+				s.scorer.Fail(old.peer, now)
+				old.failed[old.peer] = true
+			}
+			for p := range old.failed {
+				failed[p] = true
+			}
+		}
+		pending = append(pending, &segment{
+			syncBlocksInterval: interval,
+			failed:             failed,
+		})
+	}
+
+	var requests []segmentRequest
+	for _, seg := range pending {
+		peer, ok := s.pick(peers, load, seg, now)
+		if !ok {
+			// all the peers are busy, the rest is requested once some segments are delivered
+			break
+		}
+		id := peer.ID.Pretty()
+		seg.peer = id
+		seg.requestedAt = now
+		segments[seg.Start] = seg
+		load[id]++
+		requests = append(requests, segmentRequest{
+			syncBlocksInterval: seg.syncBlocksInterval,
+			peer:               peer,
+		})
+	}
+	s.segments = segments
+	return requests
+}
+
+// Fail marks the segment as failed to request, so it's re-requested from another peer in the next schedule
+func (s *segmentScheduler) Fail(start uint64, now time.Time) {
+	if seg, ok := s.segments[start]; ok {
+		s.scorer.Fail(seg.peer, now)
+		seg.failed[seg.peer] = true
+		seg.requestedAt = time.Time{}
+	}
+}
+
+// Deliver records the block of the height received from the peer, the latency of the peer is the time from the
+// request of the segment to its first block received
+func (s *segmentScheduler) Deliver(peer string, height uint64, now time.Time) {
+	s.scorer.Observe(peer, height)
+	seg := s.covering(height)
+	if seg == nil || seg.peer != peer || seg.responded {
+		return
+	}
+	seg.responded = true
+	s.scorer.Succeed(peer, now.Sub(seg.requestedAt))
+}
+
+// Demote demotes the peer which has sent an invalid block
+func (s *segmentScheduler) Demote(peer string, now time.Time) {
+	s.scorer.Demote(peer, now)
+}
+
+// Rotate demotes the peers of the segments in flight and drops the segments, so that the blocks are requested from
+// other peers. The peers demoted are returned.
+func (s *segmentScheduler) Rotate(now time.Time) []string {
+	demoted := make(map[string]bool)
+	var peers []string
+	for _, seg := range s.segments {
+		if seg.peer == "" || demoted[seg.peer] {
+			continue
+		}
+		demoted[seg.peer] = true
+		s.scorer.Demote(seg.peer, now)
+		peers = append(peers, seg.peer)
+	}
+	s.segments = make(map[uint64]*segment)
+	return peers
+}
+
+// covering returns the segment in flight covering the height
+func (s *segmentScheduler) covering(height uint64) *segment {
+	for _, seg := range s.segments {
+		if seg.Start <= height && height <= seg.End {
+			return seg
+		}
+	}
+	return nil
+}
+
+// pick returns the peer of the highest score weighted by its load among the peers neither having failed the segment
+// nor demoted, the peers known to have the segment ranking first. If there is no such peer, the least loaded peer is
+// returned.
+func (s *segmentScheduler) pick(peers []peerstore.PeerInfo, load map[string]int, seg *segment, now time.Time) (peerstore.PeerInfo, bool) {
+	var (
+		best, fallback     peerstore.PeerInfo
+		bestOK, fallbackOK bool
+		bestScore          float64
+	)
+	for _, p := range peers {
+		id := p.ID.Pretty()
+		if load[id] >= s.maxPerPeer {
+			continue
+		}
+		if !fallbackOK || load[id] < load[fallback.ID.Pretty()] {
+			fallback, fallbackOK = p, true
+		}
+		if seg.failed[id] || s.scorer.Demoted(id, now) {
+			continue
+		}
+		// the weighted score is in [0, 1]
+		score := s.scorer.Score(id) / float64(1+load[id])
+		if s.scorer.Height(id) >= seg.Start {
+			score++
+		}
+		if !bestOK || score > bestScore {
+			best, bestOK, bestScore = p, true, score
+		}
+	}
+	if bestOK {
+		return best, true
+	}
+	return fallback, fallbackOK
+}
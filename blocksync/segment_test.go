@@ -0,0 +1,115 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"testing"
+	"time"
+
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentScheduler(t *testing.T) {
+	require := require.New(t)
+	peers := []peerstore.PeerInfo{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	intervals := []syncBlocksInterval{{1, 20}, {21, 40}, {41, 60}, {61, 70}}
+	assigned := func(reqs []segmentRequest) map[uint64]string {
+		m := make(map[uint64]string)
+		for _, req := range reqs {
+			m[req.Start] = req.peer.ID.Pretty()
+		}
+		return m
+	}
+	now := time.Now()
+	s := newSegmentScheduler(10*time.Second, 1, newPeerScorer(time.Minute, 0.5))
+
+	// the lower segments are requested from different peers, the rest waits for a peer
+	reqs := s.Schedule(intervals, peers, now)
+	require.Len(reqs, 3)
+	first := assigned(reqs)
+	require.Len(first, 3)
+	require.NotEqual(first[1], first[21])
+	require.NotEqual(first[21], first[41])
+	require.NotEqual(first[1], first[41])
+	require.Empty(s.Schedule(intervals, peers, now.Add(time.Second)))
+
+	// the peer delivering the first segment takes the next one
+	reqs = s.Schedule(intervals[1:], peers, now.Add(2*time.Second))
+	require.Len(reqs, 1)
+	require.Equal(uint64(61), reqs[0].Start)
+	require.Equal(first[1], reqs[0].peer.ID.Pretty())
+
+	// a partially delivered segment stays with its peer
+	require.Empty(s.Schedule([]syncBlocksInterval{{25, 28}, {32, 40}, {41, 60}, {61, 70}}, peers, now.Add(3*time.Second)))
+
+	// the failed segment is re-requested from another peer
+	s.Fail(41, now.Add(4*time.Second))
+	reqs = s.Schedule([]syncBlocksInterval{{25, 28}, {32, 40}, {41, 60}}, peers, now.Add(4*time.Second))
+	require.Len(reqs, 1)
+	require.Equal(uint64(41), reqs[0].Start)
+	require.NotEqual(first[41], reqs[0].peer.ID.Pretty())
+	retried := reqs[0].peer.ID.Pretty()
+
+	// the expired segments are re-requested from the peers which haven't failed them
+	reqs = s.Schedule([]syncBlocksInterval{{25, 28}, {41, 60}}, peers, now.Add(12*time.Second))
+	require.Len(reqs, 1)
+	require.Equal(uint64(25), reqs[0].Start)
+	require.NotEqual(first[21], reqs[0].peer.ID.Pretty())
+	require.NotEqual(retried, reqs[0].peer.ID.Pretty())
+
+	// the segment of a disconnected peer is re-requested
+	var rest []peerstore.PeerInfo
+	for _, p := range peers {
+		if p.ID.Pretty() != retried {
+			rest = append(rest, p)
+		}
+	}
+	reqs = s.Schedule([]syncBlocksInterval{{41, 60}}, rest, now.Add(13*time.Second))
+	require.Len(reqs, 1)
+	require.NotEqual(retried, reqs[0].peer.ID.Pretty())
+}
+
+func TestSegmentSchedulerPeerScore(t *testing.T) {
+	require := require.New(t)
+	peers := []peerstore.PeerInfo{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	id := func(i int) string { return peers[i].ID.Pretty() }
+	now := time.Now()
+	s := newSegmentScheduler(10*time.Second, 2, newPeerScorer(time.Minute, 0.5))
+
+	// the faster peer is preferred
+	reqs := s.Schedule([]syncBlocksInterval{{1, 10}, {11, 20}, {21, 30}}, peers, now)
+	require.Len(reqs, 3)
+	for _, req := range reqs {
+		latency := time.Second
+		if req.peer.ID.Pretty() == id(1) {
+			latency = 100 * time.Millisecond
+		}
+		s.Deliver(req.peer.ID.Pretty(), req.Start, now.Add(latency))
+	}
+	reqs = s.Schedule([]syncBlocksInterval{{31, 40}}, peers, now.Add(2*time.Second))
+	require.Len(reqs, 1)
+	require.Equal(id(1), reqs[0].peer.ID.Pretty())
+
+	// the peer known to have the blocks is preferred
+	s.Deliver(id(2), 100, now.Add(2*time.Second))
+	reqs = s.Schedule([]syncBlocksInterval{{31, 40}, {91, 100}}, peers, now.Add(3*time.Second))
+	require.Len(reqs, 1)
+	require.Equal(uint64(91), reqs[0].Start)
+	require.Equal(id(2), reqs[0].peer.ID.Pretty())
+
+	// the demoted peer is requested only if there is no other peer
+	s.Demote(id(1), now.Add(3*time.Second))
+	reqs = s.Schedule([]syncBlocksInterval{{101, 110}, {111, 120}}, peers, now.Add(4*time.Second))
+	require.Len(reqs, 2)
+	for _, req := range reqs {
+		require.NotEqual(id(1), req.peer.ID.Pretty())
+	}
+	reqs = s.Schedule([]syncBlocksInterval{{121, 130}}, peers[1:2], now.Add(5*time.Second))
+	require.Len(reqs, 1)
+	require.Equal(id(1), reqs[0].peer.ID.Pretty())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var syncStalledMtc = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "iotex_blocksync_stalled_total",
+		Help: "Number of times block sync has stalled and been recovered.",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(syncStalledMtc)
+}
+
+// stallDetector detects a sync making no progress, i.e., the tip not moving for a number of sync intervals while it's
+// behind the target height. It's typically a bad peer feeding blocks or headers of another fork, which would otherwise
+// require a restart of the node.
+type stallDetector struct {
+	// maxIntervals is the number of intervals without progress after which the sync is stalled, 0 disables detection
+	maxIntervals int
+	tip          uint64
+	intervals    int
+}
+
+func newStallDetector(maxIntervals int) *stallDetector {
+	return &stallDetector{maxIntervals: maxIntervals}
+}
+
+// Check is called once every sync interval, and returns true if the sync has stalled. The count restarts after a stall
+// is reported, so the recovery is retried every maxIntervals if the sync keeps stalling.
+func (d *stallDetector) Check(tip, target uint64) bool {
+	if d.maxIntervals <= 0 {
+		return false
+	}
+	if tip >= target || tip != d.tip {
+		d.tip = tip
+		d.intervals = 0
+		return false
+	}
+	d.intervals++
+	if d.intervals < d.maxIntervals {
+		return false
+	}
+	d.intervals = 0
+	return true
+}
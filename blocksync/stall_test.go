@@ -0,0 +1,100 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+)
+
+func TestStallDetector(t *testing.T) {
+	require := require.New(t)
+	d := newStallDetector(3)
+	require.False(d.Check(10, 100))
+	require.False(d.Check(10, 100))
+	require.False(d.Check(10, 100))
+	require.True(d.Check(10, 100))
+	// the count restarts after a stall
+	require.False(d.Check(10, 100))
+
+	// the progress restarts the count
+	require.False(d.Check(11, 100))
+	require.False(d.Check(11, 100))
+	require.False(d.Check(12, 100))
+	require.False(d.Check(12, 100))
+	require.False(d.Check(12, 100))
+	require.True(d.Check(12, 100))
+
+	// the synced tip never stalls
+	for i := 0; i < 10; i++ {
+		require.False(d.Check(100, 100))
+	}
+
+	// the detection is disabled by 0
+	d = newStallDetector(0)
+	for i := 0; i < 10; i++ {
+		require.False(d.Check(10, 100))
+	}
+}
+
+func TestSyncWorkerStall(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := newTestConfig()
+	require.NoError(err)
+	cfg.BlockSync.Interval = 0
+	cfg.BlockSync.StallIntervals = 2
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().TipHeight().Return(uint64(10)).AnyTimes()
+	buf := &blockBuffer{
+		blocks:       map[uint64]*block.Block{15: {}},
+		bc:           chain,
+		bufferSize:   cfg.BlockSync.BufferSize,
+		intervalSize: cfg.BlockSync.IntervalSize,
+	}
+	peers := []peerstore.PeerInfo{{ID: "a"}, {ID: "b"}}
+	var requested int
+	w := newSyncWorker(
+		cfg.Chain.ID,
+		cfg,
+		func(_ context.Context, _ peerstore.PeerInfo, _ proto.Message) error {
+			requested++
+			return nil
+		},
+		func(_ context.Context) ([]peerstore.PeerInfo, error) { return peers, nil },
+		nil,
+		buf,
+	)
+	w.SetTargetHeight(100)
+
+	w.Sync()
+	require.NotZero(requested)
+	w.Sync()
+	require.Len(buf.blocks, 1)
+	now := time.Now()
+	for _, p := range peers {
+		require.False(w.scorer.Demoted(p.ID.Pretty(), now))
+	}
+
+	// the peers feeding the stalled range are demoted, and the staged blocks are dropped
+	w.Sync()
+	require.Empty(buf.blocks)
+	for _, p := range peers {
+		require.True(w.scorer.Demoted(p.ID.Pretty(), now))
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type (
+	// StateRangeRequest requests a range of the state trie at height from the peer
+	StateRangeRequest func(
+		ctx context.Context,
+		peer peerstore.PeerInfo,
+		height uint64,
+		nsKey []byte,
+		start []byte,
+		limit uint64,
+	) (*factory.StateRange, error)
+
+	// RequestStateRange sends the request of id to the peer for a range of the state trie at height
+	RequestStateRange func(
+		ctx context.Context,
+		peer peerstore.PeerInfo,
+		id uint64,
+		height uint64,
+		nsKey []byte,
+		start []byte,
+		limit uint64,
+	) error
+
+	// SendStateRange sends the serialized range of the state trie for the request of id to the peer, empty data tells
+	// the peer the request can't be served
+	SendStateRange func(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error
+
+	// stateRangeClient requests the ranges of the state trie from the peers and matches the ranges sent back with the
+	// requests by their ids
+	stateRangeClient struct {
+		request RequestStateRange
+		ttl     time.Duration
+		mu      sync.Mutex
+		nextID  uint64
+		pending map[uint64]*pendingStateRange
+	}
+
+	pendingStateRange struct {
+		peer peer.ID
+		resp chan []byte
+	}
+
+	// StateRangeFetcher fetches the ranges of the state trie from the neighbors in turn, so that a range failing the
+	// check is fetched again from another neighbor
+	StateRangeFetcher struct {
+		neighborsHandler Neighbors
+		request          StateRangeRequest
+		next             uint32
+	}
+)
+
+func newStateRangeClient(request RequestStateRange, ttl time.Duration) *stateRangeClient {
+	return &stateRangeClient{
+		request: request,
+		ttl:     ttl,
+		pending: make(map[uint64]*pendingStateRange),
+	}
+}
+
+// Request requests a range of the state trie from the peer, and waits for the peer to send it back until the ttl
+// expires
+func (c *stateRangeClient) Request(
+	ctx context.Context,
+	peer peerstore.PeerInfo,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) (*factory.StateRange, error) {
+	pending := &pendingStateRange{peer: peer.ID, resp: make(chan []byte, 1)}
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = pending
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.ttl)
+	defer cancel()
+	if err := c.request(ctx, peer, id, height, nsKey, start, limit); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "no state range from peer %s", peer.ID.Pretty())
+	case data := <-pending.resp:
+		if len(data) == 0 {
+			return nil, errors.Errorf("peer %s can't serve the state range at height %d", peer.ID.Pretty(), height)
+		}
+		sr := &factory.StateRange{}
+		if err := sr.Deserialize(data); err != nil {
+			return nil, err
+		}
+		return sr, nil
+	}
+}
+
+// Receive hands the range sent back by the peer to the request of id, the range is dropped if it isn't requested from
+// the peer or the request has expired
+func (c *stateRangeClient) Receive(peer peerstore.PeerInfo, id uint64, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending, ok := c.pending[id]
+	if !ok || pending.peer != peer.ID {
+		return errors.Errorf("unsolicited state range %d from peer %s", id, peer.ID.Pretty())
+	}
+	delete(c.pending, id)
+	pending.resp <- data
+	return nil
+}
+
+// NewStateRangeFetcher returns a new state range fetcher
+func NewStateRangeFetcher(neighborsHandler Neighbors, request StateRangeRequest) *StateRangeFetcher {
+	return &StateRangeFetcher{
+		neighborsHandler: neighborsHandler,
+		request:          request,
+	}
+}
+
+// FetchStateRange fetches a range of the state trie at height from the next neighbor
+func (f *StateRangeFetcher) FetchStateRange(
+	ctx context.Context,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) (*factory.StateRange, error) {
+	peers, err := f.neighborsHandler(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(peers) == 0 {
+		return nil, errors.New("no neighbor to fetch state range from")
+	}
+	peer := peers[int(atomic.AddUint32(&f.next, 1)-1)%len(peers)]
+	return f.request(ctx, peer, height, nsKey, start, limit)
+}
+
+// ProcessStateRangeRequest serves a range of the state trie at height to a peer, the limit is capped by maxLimit
+func ProcessStateRangeRequest(
+	sr factory.StateRangeReader,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+	maxLimit uint64,
+) (*factory.StateRange, error) {
+	if limit == 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+	return sr.StateRange(height, nsKey, start, limit)
+}
+
+// ProcessStateRangeRequest serves the request of id of the peer for a range of the state trie at height. The peer is
+// sent an empty range if the request can't be served, so it needn't wait for the range to expire.
+func (bs *blockSyncer) ProcessStateRangeRequest(
+	ctx context.Context,
+	peer peerstore.PeerInfo,
+	id uint64,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) error {
+	if bs.stateRangeReader == nil || bs.sendStateRange == nil {
+		return errors.New("serving state ranges is not enabled")
+	}
+	sr, err := ProcessStateRangeRequest(bs.stateRangeReader, height, nsKey, start, limit, bs.stateRangeLimit)
+	var data []byte
+	if err == nil {
+		data = sr.Serialize()
+		if err = bs.quota.TakeBytes(peer.ID.Pretty(), len(data), time.Now()); err != nil {
+			data = nil
+		}
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, bs.processSyncRequestTTL)
+	defer cancel()
+	if sendErr := bs.sendStateRange(sendCtx, peer, id, data); sendErr != nil {
+		return sendErr
+	}
+	return err
+}
+
+// ProcessStateRange hands the serialized range of the state trie sent by the peer to the request of id
+func (bs *blockSyncer) ProcessStateRange(_ context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error {
+	if bs.stateRange == nil {
+		return errors.New("requesting state ranges is not enabled")
+	}
+	return bs.stateRange.Receive(peer, id, data)
+}
+
+// FetchStateRange fetches a range of the state trie at height from the neighbors in turn, which is checked by the
+// caller against the state root
+func (bs *blockSyncer) FetchStateRange(
+	ctx context.Context,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) (*factory.StateRange, error) {
+	if bs.stateRangeFetcher == nil {
+		return nil, errors.New("requesting state ranges is not enabled")
+	}
+	return bs.stateRangeFetcher.FetchStateRange(ctx, height, nsKey, start, limit)
+}
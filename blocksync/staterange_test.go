@@ -0,0 +1,134 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type testStateRangeReader struct {
+	limit uint64
+}
+
+func (sr *testStateRangeReader) StateRange(height uint64, nsKey []byte, start []byte, limit uint64) (*factory.StateRange, error) {
+	sr.limit = limit
+	return &factory.StateRange{NamespaceKey: nsKey}, nil
+}
+
+func TestStateRangeFetcher(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	peers := []peerstore.PeerInfo{{ID: "a"}, {ID: "b"}}
+	var requested []peerstore.PeerInfo
+	f := NewStateRangeFetcher(
+		func(context.Context) ([]peerstore.PeerInfo, error) {
+			return peers, nil
+		},
+		func(_ context.Context, peer peerstore.PeerInfo, height uint64, nsKey, start []byte, limit uint64) (*factory.StateRange, error) {
+			requested = append(requested, peer)
+			return &factory.StateRange{NamespaceKey: nsKey}, nil
+		},
+	)
+	for i := 0; i < 3; i++ {
+		_, err := f.FetchStateRange(ctx, 1, []byte{1}, nil, 10)
+		r.NoError(err)
+	}
+	// the neighbors are requested in turn
+	r.Equal([]peerstore.PeerInfo{peers[0], peers[1], peers[0]}, requested)
+
+	peers = nil
+	_, err := f.FetchStateRange(ctx, 1, []byte{1}, nil, 10)
+	r.Error(err)
+
+	// the limit is capped
+	sr := &testStateRangeReader{}
+	_, err = ProcessStateRangeRequest(sr, 1, nil, nil, 1000, 100)
+	r.NoError(err)
+	r.EqualValues(100, sr.limit)
+	_, err = ProcessStateRangeRequest(sr, 1, nil, nil, 10, 100)
+	r.NoError(err)
+	r.EqualValues(10, sr.limit)
+}
+
+func TestStateRangeClient(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	a, b := peerstore.PeerInfo{ID: "a"}, peerstore.PeerInfo{ID: "b"}
+	var reply func(peer peerstore.PeerInfo, id uint64, nsKey []byte)
+	c := newStateRangeClient(func(_ context.Context, peer peerstore.PeerInfo, id, _ uint64, nsKey, _ []byte, _ uint64) error {
+		go reply(peer, id, nsKey)
+		return nil
+	}, 100*time.Millisecond)
+
+	// the range is matched with the request by its id
+	reply = func(peer peerstore.PeerInfo, id uint64, nsKey []byte) {
+		r.Error(c.Receive(peer, id+1, nil))
+		r.Error(c.Receive(b, id, nil))
+		r.NoError(c.Receive(peer, id, (&factory.StateRange{NamespaceKey: nsKey}).Serialize()))
+		r.Error(c.Receive(peer, id, nil))
+	}
+	sr, err := c.Request(ctx, a, 1, []byte("ns"), nil, 10)
+	r.NoError(err)
+	r.Equal([]byte("ns"), sr.NamespaceKey)
+
+	// an empty range tells the peer can't serve the request
+	reply = func(peer peerstore.PeerInfo, id uint64, _ []byte) {
+		r.NoError(c.Receive(peer, id, nil))
+	}
+	_, err = c.Request(ctx, a, 1, []byte("ns"), nil, 10)
+	r.Error(err)
+
+	// the request expires without the range
+	reply = func(peerstore.PeerInfo, uint64, []byte) {}
+	_, err = c.Request(ctx, a, 1, []byte("ns"), nil, 10)
+	r.Equal(context.DeadlineExceeded, errors.Cause(err))
+	r.Empty(c.pending)
+}
+
+func TestProcessStateRangeRequest(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	var sent [][]byte
+	bs := &blockSyncer{
+		processSyncRequestTTL: time.Second,
+		quota:                 newServingQuota(config.Default.BlockSync.ServeQuota),
+		stateRangeLimit:       100,
+		sendStateRange: func(_ context.Context, _ peerstore.PeerInfo, id uint64, data []byte) error {
+			r.EqualValues(7, id)
+			sent = append(sent, data)
+			return nil
+		},
+	}
+	peer := peerstore.PeerInfo{ID: "a"}
+	r.Error(bs.ProcessStateRangeRequest(ctx, peer, 7, 1, []byte("ns"), nil, 10))
+	r.Empty(sent)
+
+	reader := &testStateRangeReader{}
+	bs.stateRangeReader = reader
+	r.NoError(bs.ProcessStateRangeRequest(ctx, peer, 7, 1, []byte("ns"), nil, 1000))
+	r.EqualValues(100, reader.limit)
+	r.Len(sent, 1)
+	sr := &factory.StateRange{}
+	r.NoError(sr.Deserialize(sent[0]))
+	r.Equal([]byte("ns"), sr.NamespaceKey)
+
+	// the peer is sent an empty range if the request can't be served
+	bs.quota = newServingQuota(config.ServeQuota{BytesPerSecond: 1, ByteBurst: 1, MaxPeers: 1})
+	r.NoError(bs.ProcessStateRangeRequest(ctx, peer, 7, 1, []byte("ns"), nil, 10))
+	r.Error(bs.ProcessStateRangeRequest(ctx, peer, 7, 1, []byte("ns"), nil, 10))
+	r.Len(sent, 3)
+	r.Empty(sent[2])
+}
@@ -12,10 +12,14 @@ import (
 	"github.com/iotexproject/iotex-core/consensus"
 )
 
-func commitBlock(bc blockchain.Blockchain, cs consensus.Consensus, blk *block.Block) error {
+// commitBlock commits the block once its prevalidation result is received
+func commitBlock(bc blockchain.Blockchain, cs consensus.Consensus, blk *block.Block, prevalidated <-chan error) error {
 	if err := cs.ValidateBlockFooter(blk); err != nil {
 		return err
 	}
+	if err := <-prevalidated; err != nil {
+		return err
+	}
 	if err := bc.ValidateBlock(blk); err != nil {
 		return err
 	}
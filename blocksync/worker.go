@@ -8,11 +8,13 @@ package blocksync
 
 import (
 	"context"
-	"math/rand"
 	"sync"
+	"time"
 
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/routine"
@@ -32,8 +34,21 @@ type syncWorker struct {
 	neighborsHandler Neighbors
 	buf              *blockBuffer
 	task             *routine.RecurringTask
-	maxRepeat        int
-	repeatDecayStep  int
+	scorer           *peerScorer
+	scheduler        *segmentScheduler
+	// the headers are synced ahead of the blocks if headers is not nil
+	headers               *headerChain
+	requestHeadersHandler RequestHeaders
+	headerBatchSize       uint64
+	headerTTL             time.Duration
+	headerRequest         *headerRequest
+	stall                 *stallDetector
+}
+
+// headerRequest is the request for headers in flight
+type headerRequest struct {
+	peer        string
+	requestedAt time.Time
 }
 
 func newSyncWorker(
@@ -41,16 +56,23 @@ func newSyncWorker(
 	cfg config.Config,
 	unicastHandler UnicastOutbound,
 	neighborsHandler Neighbors,
+	requestHeadersHandler RequestHeaders,
 	buf *blockBuffer,
 ) *syncWorker {
+	scorer := newPeerScorer(cfg.BlockSync.PeerDemotion, cfg.BlockSync.MaxPeerFailureRate)
 	w := &syncWorker{
-		chainID:          chainID,
-		unicastHandler:   unicastHandler,
-		neighborsHandler: neighborsHandler,
-		buf:              buf,
-		targetHeight:     0,
-		maxRepeat:        cfg.BlockSync.MaxRepeat,
-		repeatDecayStep:  cfg.BlockSync.RepeatDecayStep,
+		chainID:               chainID,
+		unicastHandler:        unicastHandler,
+		neighborsHandler:      neighborsHandler,
+		buf:                   buf,
+		targetHeight:          0,
+		scorer:                scorer,
+		scheduler:             newSegmentScheduler(cfg.BlockSync.SegmentTTL, cfg.BlockSync.MaxSegmentsPerPeer, scorer),
+		headers:               buf.headers,
+		requestHeadersHandler: requestHeadersHandler,
+		headerBatchSize:       cfg.BlockSync.HeaderBatchSize,
+		headerTTL:             cfg.BlockSync.SegmentTTL,
+		stall:                 newStallDetector(cfg.BlockSync.StallIntervals),
 	}
 	if cfg.BlockSync.Interval != 0 {
 		w.task = routine.NewRecurringTask(w.Sync, cfg.BlockSync.Interval)
@@ -80,6 +102,28 @@ func (w *syncWorker) SetTargetHeight(h uint64) {
 	}
 }
 
+// Deliver records the block received from the peer
+func (w *syncWorker) Deliver(peer string, height uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.scheduler.Deliver(peer, height, time.Now())
+}
+
+// Observe records that the peer has the block of the height
+func (w *syncWorker) Observe(peer string, height uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.scorer.Observe(peer, height)
+}
+
+// Demote demotes the peer which has sent an invalid block
+func (w *syncWorker) Demote(peer string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	log.L().Warn("Demote the peer sending invalid blocks.", zap.String("peer", peer))
+	w.scheduler.Demote(peer, time.Now())
+}
+
 // Sync checks the sliding window and send more sync request if needed
 func (w *syncWorker) Sync() {
 	w.mu.Lock()
@@ -95,6 +139,12 @@ func (w *syncWorker) Sync() {
 		log.L().Warn("Error when get neighbor peers.", zap.Error(err))
 		return
 	}
+	if tip := w.buf.bc.TipHeight(); w.stall.Check(tip, w.targetHeight) {
+		w.recoverStall(tip, time.Now())
+	}
+	if w.headers != nil {
+		w.syncHeaders(ctx, peers, time.Now())
+	}
 	intervals := w.buf.GetBlocksIntervalsToSync(w.targetHeight)
 	if intervals != nil {
 		log.L().Info("block sync intervals.",
@@ -102,19 +152,114 @@ func (w *syncWorker) Sync() {
 			zap.Uint64("targetHeight", w.targetHeight))
 	}
 
-	for i, interval := range intervals {
-		repeat := w.maxRepeat - i/w.repeatDecayStep
-		if repeat <= 0 {
-			repeat = 1
-		}
-		for j := 0; j < repeat; j++ {
-			rrIdx := rand.Intn(len(peers))
-			p := peers[rrIdx]
-			if err := w.unicastHandler(ctx, p, &iotexrpc.BlockSync{
-				Start: interval.Start, End: interval.End,
+	requests := w.scheduler.Schedule(intervals, peers, time.Now())
+	// the segments are requested from the peers concurrently, and those failed to send are re-requested from other
+	// peers in the next round
+	failed := make([]bool, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req segmentRequest) {
+			defer wg.Done()
+			if err := w.unicastHandler(ctx, req.peer, &iotexrpc.BlockSync{
+				Start: req.Start, End: req.End,
 			}); err != nil {
-				log.L().Debug("Failed to sync block.", zap.Error(err))
+				log.L().Debug("Failed to sync block.", zap.Error(err), zap.String("peer", req.peer.ID.Pretty()))
+				failed[i] = true
 			}
+		}(i, req)
+	}
+	wg.Wait()
+	for i, req := range requests {
+		if failed[i] {
+			w.scheduler.Fail(req.Start, time.Now())
+		}
+	}
+}
+
+// recoverStall recovers the stalled sync. The peers feeding the stalled range are demoted so the blocks are requested
+// from the other peers, and the blocks and the headers staged ahead of the tip are dropped, in case they're of another
+// fork.
+func (w *syncWorker) recoverStall(tip uint64, now time.Time) {
+	demoted := w.scheduler.Rotate(now)
+	if req := w.headerRequest; req != nil {
+		w.scorer.Demote(req.peer, now)
+		demoted = append(demoted, req.peer)
+		w.headerRequest = nil
+	}
+	w.buf.Clear()
+	if w.headers != nil {
+		w.headers.Reset()
+	}
+	syncStalledMtc.Inc()
+	log.L().Warn("Block sync stalled, rotated the peers and cleared the buffer.",
+		zap.Uint64("tipHeight", tip),
+		zap.Uint64("targetHeight", w.targetHeight),
+		zap.Strings("demotedPeers", demoted))
+}
+
+// ReceiveHeaders appends the headers received from the peer to the validated headers, and requests the next batch from
+// the peer if it has sent a full batch. The peer sending an invalid header is demoted.
+func (w *syncWorker) ReceiveHeaders(ctx context.Context, peer peerstore.PeerInfo, headers []*block.Header) error {
+	if w.headers == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id, now := peer.ID.Pretty(), time.Now()
+	if req := w.headerRequest; req != nil && req.peer == id {
+		w.headerRequest = nil
+		w.scorer.Succeed(id, now.Sub(req.requestedAt))
+	}
+	appended, err := w.headers.Append(headers)
+	if err != nil {
+		log.L().Warn("Demote the peer sending invalid headers.", zap.String("peer", id), zap.Error(err))
+		w.scorer.Demote(id, now)
+		return err
+	}
+	tip := w.headers.Tip()
+	w.scorer.Observe(id, tip)
+	if tip > w.targetHeight {
+		w.targetHeight = tip
+	}
+	if appended > 0 && uint64(len(headers)) >= w.headerBatchSize && w.headerRequest == nil && !w.headers.Full() {
+		w.requestHeaders(ctx, peer, tip+1, now)
+	}
+	return nil
+}
+
+// syncHeaders requests the next batch of headers unless a request is in flight. A request not responded in time
+// counts as a failure of the peer.
+func (w *syncWorker) syncHeaders(ctx context.Context, peers []peerstore.PeerInfo, now time.Time) {
+	if req := w.headerRequest; req != nil {
+		if now.Sub(req.requestedAt) < w.headerTTL {
+			return
 		}
+		w.scorer.Fail(req.peer, now)
+		w.headerRequest = nil
+	}
+	if w.headers.Full() {
+		return
+	}
+	start := w.headers.Tip() + 1
+	peer, ok := w.scheduler.pick(peers, nil, &segment{
+		syncBlocksInterval: syncBlocksInterval{Start: start, End: start + w.headerBatchSize - 1},
+	}, now)
+	if !ok {
+		return
+	}
+	w.requestHeaders(ctx, peer, start, now)
+}
+
+func (w *syncWorker) requestHeaders(ctx context.Context, peer peerstore.PeerInfo, start uint64, now time.Time) {
+	id := peer.ID.Pretty()
+	if err := w.requestHeadersHandler(ctx, peer, start, start+w.headerBatchSize-1); err != nil {
+		log.L().Debug("Failed to request headers.", zap.Error(err), zap.String("peer", id))
+		w.scorer.Fail(id, now)
+		return
+	}
+	w.headerRequest = &headerRequest{
+		peer:        id,
+		requestedAt: now,
 	}
 }
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package chainarchive reads and writes portable archives of a range of blocks, which copy the chain offline and
+// bootstrap nodes without network access. An archive is a header with the version and the range of heights, the
+// blocks with their receipts in ascending order of height, and the sha256 of all the preceding bytes.
+package chainarchive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+// Version is the version of the archive format written
+const Version uint16 = 1
+
+const (
+	// headerSize is the size of the magic, the version, the start and the end height
+	headerSize = 8 + 2 + 8 + 8
+	// maxRecordSize bounds the size of a block in the archive, a larger size means the archive is corrupted
+	maxRecordSize = 1 << 26
+)
+
+var (
+	magic = []byte("IOTXCHAR")
+
+	// ErrInvalidArchive indicates the archive is corrupted or truncated
+	ErrInvalidArchive = errors.New("invalid chain archive")
+)
+
+type (
+	// Writer writes the blocks of a range of heights to an archive
+	Writer struct {
+		w          io.Writer
+		sum        hash.Hash
+		next, end  uint64
+		sizeBuffer [binary.MaxVarintLen64]byte
+	}
+
+	// Reader reads the blocks of an archive
+	Reader struct {
+		r          *hashReader
+		start, end uint64
+		next       uint64
+		verified   bool
+	}
+
+	// hashReader hashes the bytes read
+	hashReader struct {
+		r   *bufio.Reader
+		sum hash.Hash
+	}
+)
+
+// NewWriter writes the header of an archive of the blocks from start to end height
+func NewWriter(w io.Writer, start, end uint64) (*Writer, error) {
+	if start == 0 || start > end {
+		return nil, errors.Errorf("invalid range [%d, %d]", start, end)
+	}
+	aw := &Writer{w: w, sum: sha256.New(), next: start, end: end}
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.BigEndian.PutUint16(header[8:], Version)
+	binary.BigEndian.PutUint64(header[10:], start)
+	binary.BigEndian.PutUint64(header[18:], end)
+	if err := aw.write(header); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write writes the block with its receipts, which is at the next height of the range
+func (aw *Writer) Write(store *iotextypes.BlockStore) error {
+	height := store.GetBlock().GetHeader().GetCore().GetHeight()
+	if height != aw.next || height > aw.end {
+		return errors.Errorf("block %d is not at the next height %d of the archive", height, aw.next)
+	}
+	ser, err := proto.Marshal(store)
+	if err != nil {
+		return err
+	}
+	n := binary.PutUvarint(aw.sizeBuffer[:], uint64(len(ser)))
+	if err := aw.write(aw.sizeBuffer[:n]); err != nil {
+		return err
+	}
+	if err := aw.write(ser); err != nil {
+		return err
+	}
+	aw.next++
+	return nil
+}
+
+// Close writes the checksum of the archive, after all the blocks of the range are written. The underlying writer is
+// not closed.
+func (aw *Writer) Close() error {
+	if aw.next != aw.end+1 {
+		return errors.Errorf("archive ends at height %d, expecting %d", aw.next-1, aw.end)
+	}
+	_, err := aw.w.Write(aw.sum.Sum(nil))
+	return err
+}
+
+func (aw *Writer) write(b []byte) error {
+	aw.sum.Write(b)
+	_, err := aw.w.Write(b)
+	return err
+}
+
+// NewReader reads the header of an archive
+func NewReader(r io.Reader) (*Reader, error) {
+	ar := &Reader{r: &hashReader{r: bufio.NewReader(r), sum: sha256.New()}}
+	header := make([]byte, headerSize)
+	if err := ar.read(header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:8], magic) {
+		return nil, errors.Wrap(ErrInvalidArchive, "not a chain archive")
+	}
+	if version := binary.BigEndian.Uint16(header[8:10]); version != Version {
+		return nil, errors.Wrapf(ErrInvalidArchive, "unsupported version %d", version)
+	}
+	ar.start = binary.BigEndian.Uint64(header[10:18])
+	ar.end = binary.BigEndian.Uint64(header[18:])
+	if ar.start == 0 || ar.start > ar.end {
+		return nil, errors.Wrapf(ErrInvalidArchive, "invalid range [%d, %d]", ar.start, ar.end)
+	}
+	ar.next = ar.start
+	return ar, nil
+}
+
+// Start returns the start height of the archive
+func (ar *Reader) Start() uint64 { return ar.start }
+
+// End returns the end height of the archive
+func (ar *Reader) End() uint64 { return ar.end }
+
+// Next returns the next block with its receipts. After the last block, it verifies the checksum of the archive and
+// returns io.EOF.
+func (ar *Reader) Next() (*block.Store, error) {
+	if ar.next > ar.end {
+		return nil, ar.verify()
+	}
+	ser, err := ar.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	store := &block.Store{}
+	if err := store.Deserialize(ser); err != nil {
+		return nil, errors.Wrapf(ErrInvalidArchive, "failed to deserialize block %d: %v", ar.next, err)
+	}
+	if height := store.Block.Height(); height != ar.next {
+		return nil, errors.Wrapf(ErrInvalidArchive, "block %d is out of order, expecting %d", height, ar.next)
+	}
+	ar.next++
+	return store, nil
+}
+
+// Verify reads the rest of the archive without decoding the blocks, and verifies its checksum
+func (ar *Reader) Verify() error {
+	for ; ar.next <= ar.end; ar.next++ {
+		if _, err := ar.readRecord(); err != nil {
+			return err
+		}
+	}
+	if err := ar.verify(); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func (ar *Reader) readRecord() ([]byte, error) {
+	size, err := binary.ReadUvarint(ar.r)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidArchive, "failed to read block %d: %v", ar.next, err)
+	}
+	if size > maxRecordSize {
+		return nil, errors.Wrapf(ErrInvalidArchive, "block %d has an invalid size %d", ar.next, size)
+	}
+	ser := make([]byte, size)
+	if err := ar.read(ser); err != nil {
+		return nil, err
+	}
+	return ser, nil
+}
+
+// verify checks the checksum after the last block, and that nothing follows it
+func (ar *Reader) verify() error {
+	if ar.verified {
+		return io.EOF
+	}
+	expected := ar.r.sum.Sum(nil)
+	sum := make([]byte, len(expected))
+	if _, err := io.ReadFull(ar.r.r, sum); err != nil {
+		return errors.Wrapf(ErrInvalidArchive, "failed to read checksum: %v", err)
+	}
+	if !bytes.Equal(sum, expected) {
+		return errors.Wrap(ErrInvalidArchive, "checksum mismatch")
+	}
+	if _, err := ar.r.r.ReadByte(); err != io.EOF {
+		return errors.Wrap(ErrInvalidArchive, "unexpected data after checksum")
+	}
+	ar.verified = true
+	return io.EOF
+}
+
+func (ar *Reader) read(b []byte) error {
+	if _, err := io.ReadFull(ar.r, b); err != nil {
+		return errors.Wrapf(ErrInvalidArchive, "failed to read archive: %v", err)
+	}
+	return nil
+}
+
+func (hr *hashReader) Read(b []byte) (int, error) {
+	n, err := hr.r.Read(b)
+	hr.sum.Write(b[:n])
+	return n, err
+}
+
+func (hr *hashReader) ReadByte() (byte, error) {
+	b, err := hr.r.ReadByte()
+	if err == nil {
+		hr.sum.Write([]byte{b})
+	}
+	return b, err
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package chainarchive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func newTestChain(r *require.Assertions) blockchain.Blockchain {
+	cfg := config.Default
+	cfg.Consensus.Scheme = config.NOOPScheme
+	cfg.Genesis.EnableGravityChainVoting = false
+	registry := protocol.NewRegistry()
+	r.NoError(account.NewProtocol(rewarding.DepositGas).Register(registry))
+	rp := rolldpos.NewProtocol(cfg.Genesis.NumCandidateDelegates, cfg.Genesis.NumDelegates, cfg.Genesis.NumSubEpochs)
+	r.NoError(rp.Register(registry))
+	sf, err := factory.NewFactory(cfg, factory.InMemTrieOption(), factory.RegistryOption(registry))
+	r.NoError(err)
+	ap, err := actpool.NewActPool(sf, cfg.ActPool, actpool.EnableExperimentalActions())
+	r.NoError(err)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+	chain := blockchain.NewBlockchain(
+		cfg,
+		nil,
+		factory.NewMinter(sf, ap),
+		blockchain.InMemDaoOption(sf),
+		blockchain.BlockValidatorOption(block.NewValidator(sf, ap)),
+	)
+	r.NoError(chain.Start(context.Background()))
+	return chain
+}
+
+func writeTestArchive(r *require.Assertions, blocks []*block.Block) []byte {
+	var buf bytes.Buffer
+	aw, err := NewWriter(&buf, blocks[0].Height(), blocks[len(blocks)-1].Height())
+	r.NoError(err)
+	for _, blk := range blocks {
+		r.NoError(aw.Write((&block.Store{Block: blk, Receipts: blk.Receipts}).ToProto()))
+	}
+	r.NoError(aw.Close())
+	return buf.Bytes()
+}
+
+func noFooterValidation(*block.Block) error { return nil }
+
+func TestArchive(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	src := newTestChain(r)
+	defer src.Stop(ctx)
+	var blocks []*block.Block
+	now := testutil.TimestampNow()
+	for i := 0; i < 5; i++ {
+		blk, err := src.MintNewBlock(now.Add(time.Duration(i) * time.Second))
+		r.NoError(err)
+		r.NoError(src.CommitBlock(blk))
+		blocks = append(blocks, blk)
+	}
+	data := writeTestArchive(r, blocks[1:4])
+
+	// read the archive
+	ar, err := NewReader(bytes.NewReader(data))
+	r.NoError(err)
+	r.EqualValues(2, ar.Start())
+	r.EqualValues(4, ar.End())
+	for _, blk := range blocks[1:4] {
+		store, err := ar.Next()
+		r.NoError(err)
+		r.Equal(blk.HashBlock(), store.Block.HashBlock())
+	}
+	_, err = ar.Next()
+	r.Equal(io.EOF, err)
+	_, err = ar.Next()
+	r.Equal(io.EOF, err)
+
+	// the blocks are written in order, and all of them
+	aw, err := NewWriter(ioutil.Discard, 1, 2)
+	r.NoError(err)
+	r.Error(aw.Write((&block.Store{Block: blocks[1]}).ToProto()))
+	r.NoError(aw.Write((&block.Store{Block: blocks[0]}).ToProto()))
+	r.Error(aw.Close())
+	_, err = NewWriter(ioutil.Discard, 2, 1)
+	r.Error(err)
+
+	// corrupted and truncated archives
+	for _, corrupt := range []func([]byte) []byte{
+		func(b []byte) []byte { b[0] = 'X'; return b },
+		func(b []byte) []byte { b[len(b)-1]++; return b },
+		func(b []byte) []byte { b[len(b)/2]++; return b },
+		func(b []byte) []byte { return b[:len(b)-1] },
+		func(b []byte) []byte { return b[:len(b)/2] },
+		func(b []byte) []byte { return append(b, 0) },
+	} {
+		b := corrupt(append([]byte{}, data...))
+		ar, err := NewReader(bytes.NewReader(b))
+		if err == nil {
+			err = ar.Verify()
+		}
+		r.Equal(ErrInvalidArchive, errors.Cause(err))
+	}
+
+	// import into a new chain
+	dir, err := ioutil.TempDir("", "chainarchive")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "chain.arc")
+	dst := newTestChain(r)
+	defer dst.Stop(ctx)
+	r.NoError(ioutil.WriteFile(path, data, 0600))
+	_, err = ImportFile(dst, noFooterValidation, path)
+	r.Error(err)
+	r.NoError(ioutil.WriteFile(path, writeTestArchive(r, blocks[:3]), 0600))
+	imported, err := ImportFile(dst, noFooterValidation, path)
+	r.NoError(err)
+	r.EqualValues(3, imported)
+	// the blocks in the chain are skipped
+	r.NoError(ioutil.WriteFile(path, data, 0600))
+	imported, err = ImportFile(dst, noFooterValidation, path)
+	r.NoError(err)
+	r.EqualValues(1, imported)
+	r.Equal(blocks[3].HashBlock(), dst.TipHash())
+
+	// the footer is validated
+	r.NoError(ioutil.WriteFile(path, writeTestArchive(r, blocks[4:]), 0600))
+	_, err = ImportFile(dst, func(*block.Block) error { return errors.New("not endorsed") }, path)
+	r.Error(err)
+	r.EqualValues(4, dst.TipHeight())
+
+	// a corrupted archive is not imported
+	b := writeTestArchive(r, blocks[4:])
+	b[len(b)-1]++
+	r.NoError(ioutil.WriteFile(path, b, 0600))
+	_, err = ImportFile(dst, noFooterValidation, path)
+	r.Equal(ErrInvalidArchive, errors.Cause(err))
+	r.EqualValues(4, dst.TipHeight())
+}
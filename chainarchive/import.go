@@ -0,0 +1,100 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package chainarchive
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+type (
+	// Chain is the chain an archive is imported into
+	Chain interface {
+		TipHeight() uint64
+		BlockHeaderByHeight(uint64) (*block.Header, error)
+		ValidateBlock(*block.Block) error
+		CommitBlock(*block.Block) error
+	}
+
+	// FooterValidator validates the endorsements of a block
+	FooterValidator func(*block.Block) error
+)
+
+// ImportFile imports an archive into the chain. The checksum of the archive is verified before any block is committed.
+func ImportFile(bc Chain, validateFooter FooterValidator, path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to open chain archive")
+	}
+	defer f.Close()
+
+	ar, err := NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	if err := ar.Verify(); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return Import(bc, validateFooter, f)
+}
+
+// Import commits the blocks of an archive to the chain, and returns the number of blocks committed. Every block is
+// validated as a synced block before committed, and the blocks at or below the tip are skipped after checking they
+// match the chain.
+func Import(bc Chain, validateFooter FooterValidator, r io.Reader) (uint64, error) {
+	ar, err := NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	if tip := bc.TipHeight(); ar.Start() > tip+1 {
+		return 0, errors.Errorf("archive starts at height %d, above the next height %d of the chain", ar.Start(), tip+1)
+	}
+
+	var imported uint64
+	for {
+		store, err := ar.Next()
+		if err == io.EOF {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+		blk := store.Block
+		if height := blk.Height(); height <= bc.TipHeight() {
+			header, err := bc.BlockHeaderByHeight(height)
+			if err != nil {
+				return imported, err
+			}
+			if header.HashBlock() != blk.HashBlock() {
+				return imported, errors.Errorf("block %d of the archive does not match the chain", height)
+			}
+			continue
+		}
+		if err := validateFooter(blk); err != nil {
+			return imported, errors.Wrapf(err, "failed to validate footer of block %d", blk.Height())
+		}
+		if err := bc.ValidateBlock(blk); err != nil {
+			return imported, errors.Wrapf(err, "failed to validate block %d", blk.Height())
+		}
+		if err := bc.CommitBlock(blk); err != nil {
+			return imported, errors.Wrapf(err, "failed to commit block %d", blk.Height())
+		}
+		imported++
+		if blk.Height()%10000 == 0 {
+			log.L().Info("Importing chain archive.", zap.Uint64("height", blk.Height()), zap.Uint64("end", ar.End()))
+		}
+	}
+}
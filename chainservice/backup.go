@@ -0,0 +1,88 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package chainservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// errBackupInProgress indicates a backup is requested while the previous one is running
+var errBackupInProgress = errors.New("backup is in progress")
+
+// BackupStatus is the result of a backup
+type BackupStatus struct {
+	Dir     string `json:"dir"`
+	Elapsed string `json:"elapsed"`
+}
+
+func appendBackuper(backupers []db.Backuper, store interface{}) []db.Backuper {
+	if b, ok := store.(db.Backuper); ok {
+		return append(backupers, b)
+	}
+	return backupers
+}
+
+// Backup writes a consistent copy of the state, the indexes and the blocks into the directory, while the node keeps
+// committing blocks. The files of a failed backup are left in the directory, which should be removed before retrying.
+func (cs *ChainService) Backup(ctx context.Context, dir string) error {
+	if !atomic.CompareAndSwapInt32(&cs.backingUp, 0, 1) {
+		return errBackupInProgress
+	}
+	defer atomic.StoreInt32(&cs.backingUp, 0)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create backup directory %s", dir)
+	}
+	for _, b := range cs.backupers {
+		if err := b.Backup(ctx, dir); err != nil {
+			return errors.Wrapf(err, "failed to back up %T", b)
+		}
+	}
+	return nil
+}
+
+// HandleBackup handles the admin request of the backup. POST /backup?dir={dir} backs up the databases into the
+// directory of the node, which must be an absolute path.
+func (cs *ChainService) HandleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	dir := r.URL.Query().Get("dir")
+	if !filepath.IsAbs(dir) {
+		http.Error(w, "backup directory must be an absolute path", http.StatusBadRequest)
+		return
+	}
+	start := time.Now()
+	if err := cs.Backup(r.Context(), dir); err != nil {
+		log.L().Error("Failed to back up the databases.", zap.String("dir", dir), zap.Error(err))
+		code := http.StatusInternalServerError
+		if err == errBackupInProgress {
+			code = http.StatusConflict
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	elapsed := time.Since(start)
+	log.L().Info("Backed up the databases.", zap.String("dir", dir), zap.Duration("elapsed", elapsed))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&BackupStatus{Dir: dir, Elapsed: elapsed.String()}); err != nil {
+		log.L().Warn("Failed to write the backup response.", zap.Error(err))
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-election/committee"
 	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
@@ -32,21 +33,45 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol/staking"
 	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
 	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/actpool/denylist"
+	"github.com/iotexproject/iotex-core/actsync"
 	"github.com/iotexproject/iotex-core/api"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/blockchain/blockdao"
 	"github.com/iotexproject/iotex-core/blockindex"
 	"github.com/iotexproject/iotex-core/blocksync"
+	"github.com/iotexproject/iotex-core/chainarchive"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus"
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+	crolldpos "github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/dispatcher"
 	"github.com/iotexproject/iotex-core/p2p"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/signer"
+	"github.com/iotexproject/iotex-core/snapshot"
 	"github.com/iotexproject/iotex-core/state/factory"
 )
 
+// P2PAgent is the network the chain service sends the messages through, which is the p2p agent of the node, or a
+// simulated network in the tests
+type P2PAgent interface {
+	BroadcastOutbound(context.Context, proto.Message) error
+	UnicastOutbound(context.Context, peerstore.PeerInfo, proto.Message) error
+	Neighbors(context.Context) ([]peerstore.PeerInfo, error)
+	RequestHeaders(context.Context, peerstore.PeerInfo, uint64, uint64) error
+	SendHeaders(context.Context, peerstore.PeerInfo, []*iotextypes.BlockHeader) error
+	AnnounceBlock(context.Context, uint64, hash.Hash256) error
+	AnnounceActions(context.Context, []hash.Hash256) error
+	RequestActions(context.Context, peerstore.PeerInfo, []hash.Hash256) error
+	RequestStateRange(context.Context, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64) error
+	SendStateRange(context.Context, peerstore.PeerInfo, uint64, []byte) error
+	ReportViolation(string, p2p.Violation)
+	RegisterValidator(uint32, iotexrpc.MessageType, p2p.Validator)
+}
+
 // ChainService is a blockchain service with all blockchain components.
 type ChainService struct {
 	actpool           actpool.ActPool
@@ -63,6 +88,10 @@ type ChainService struct {
 	candidateIndexer   *poll.CandidateIndexer
 	candBucketsIndexer *staking.CandidatesBucketsIndexer
 	registry           *protocol.Registry
+	denylist           *denylist.Denylist
+	actsync            *actsync.ActionSync
+	backupers          []db.Backuper
+	backingUp          int32
 }
 
 type optionParams struct {
@@ -92,7 +121,7 @@ func WithSubChain() Option {
 // New creates a ChainService from config and network.Overlay and dispatcher.Dispatcher.
 func New(
 	cfg config.Config,
-	p2pAgent *p2p.Agent,
+	p2pAgent P2PAgent,
 	dispatcher dispatcher.Dispatcher,
 	opts ...Option,
 ) (*ChainService, error) {
@@ -111,6 +140,14 @@ func New(
 			return nil, err
 		}
 	}
+	if cp := cfg.BlockSync.Checkpoint; cp.Height > 0 && !ops.isTesting {
+		// a new node starts from the state snapshot of the trusted checkpoint
+		dbCfg := cfg.DB
+		dbCfg.DbPath = cfg.Chain.ChainDBPath
+		if _, err := snapshot.Bootstrap(cp, cfg.Chain.ID, cfg.Chain.TrieDBPath, dbCfg); err != nil {
+			return nil, errors.Wrap(err, "failed to bootstrap from the checkpoint")
+		}
+	}
 	registry := protocol.NewRegistry()
 	// create state factory
 	var sf factory.Factory
@@ -134,6 +171,9 @@ func New(
 		}
 	}
 	indexers = append(indexers, sf)
+	// the state and the indexes are backed up before the blocks, so that a node restored from the backup catches them
+	// up with the blocks
+	backupers := appendBackuper(nil, sf)
 	var chainOpts []blockchain.Option
 	var electionCommittee committee.Committee
 	if cfg.Genesis.EnableGravityChainVoting {
@@ -166,7 +206,12 @@ func New(
 	_, gateway := cfg.Plugins[config.GatewayPlugin]
 	if gateway {
 		cfg.DB.DbPath = cfg.Chain.IndexDBPath
-		indexer, err = blockindex.NewIndexer(db.NewBoltDB(cfg.DB), cfg.Genesis.Hash())
+		kv, err := db.NewKVStore(cfg.DB)
+		if err != nil {
+			return nil, err
+		}
+		backupers = appendBackuper(backupers, kv)
+		indexer, err = blockindex.NewIndexer(kv, cfg.Genesis.Hash())
 		if err != nil {
 			return nil, err
 		}
@@ -176,7 +221,11 @@ func New(
 
 		// create bloomfilter indexer
 		cfg.DB.DbPath = cfg.Chain.BloomfilterIndexDBPath
-		bfIndexer, err = blockindex.NewBloomfilterIndexer(db.NewBoltDB(cfg.DB), cfg.Indexer)
+		if kv, err = db.NewKVStore(cfg.DB); err != nil {
+			return nil, err
+		}
+		backupers = appendBackuper(backupers, kv)
+		bfIndexer, err = blockindex.NewBloomfilterIndexer(kv, cfg.Indexer)
 		if err != nil {
 			return nil, err
 		}
@@ -184,19 +233,45 @@ func New(
 
 		// create candidate indexer
 		cfg.DB.DbPath = cfg.Chain.CandidateIndexDBPath
-		candidateIndexer, err = poll.NewCandidateIndexer(db.NewBoltDB(cfg.DB))
+		if kv, err = db.NewKVStore(cfg.DB); err != nil {
+			return nil, err
+		}
+		backupers = appendBackuper(backupers, kv)
+		candidateIndexer, err = poll.NewCandidateIndexer(kv)
 		if err != nil {
 			return nil, err
 		}
 		if cfg.Chain.EnableStakingIndexer {
 			cfg.DB.DbPath = cfg.Chain.StakingIndexDBPath
-			candBucketsIndexer, err = staking.NewStakingCandidatesBucketsIndexer(db.NewBoltDB(cfg.DB))
+			if kv, err = db.NewKVStore(cfg.DB); err != nil {
+				return nil, err
+			}
+			backupers = appendBackuper(backupers, kv)
+			candBucketsIndexer, err = staking.NewStakingCandidatesBucketsIndexer(kv)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	var rDPoSProtocol *rolldpos.Protocol
+	if cfg.Consensus.Scheme == config.RollDPoSScheme {
+		rDPoSProtocol = rolldpos.NewProtocol(
+			cfg.Genesis.NumCandidateDelegates,
+			cfg.Genesis.NumDelegates,
+			cfg.Genesis.NumSubEpochs,
+			rolldpos.EnableDardanellesSubEpoch(cfg.Genesis.DardanellesBlockHeight, cfg.Genesis.DardanellesNumSubEpochs),
+		)
+	}
+	// the snapshot exporter is the last indexer, which receives a block after the state has committed it
+	if cfg.Snapshot.Dir != "" && !ops.isTesting {
+		checkpointer, ok := sf.(snapshot.StateCheckpointer)
+		if !ok {
+			return nil, errors.New("state factory does not support snapshot")
+		}
+		indexers = append(indexers, snapshot.NewExporter(cfg.Snapshot, cfg.Chain.ID, rDPoSProtocol, checkpointer))
+	}
+
 	// create BlockDAO
 	var dao blockdao.BlockDAO
 	if ops.isTesting {
@@ -206,9 +281,19 @@ func New(
 		cfg.DB.CompressLegacy = cfg.Chain.CompressBlock
 		dao = blockdao.NewBlockDAO(indexers, cfg.DB)
 	}
+	backupers = appendBackuper(backupers, dao)
 
 	// Create ActPool
 	actOpts := make([]actpool.Option, 0)
+	var denied *denylist.Denylist
+	if cfg.ActPool.DenylistPath != "" {
+		denied, err = denylist.New(cfg.ActPool.DenylistPath, cfg.ActPool.DenylistReloadInterval)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load denylist")
+		}
+		actOpts = append(actOpts, actpool.WithDenylist(denied))
+	}
+	actOpts = append(actOpts, actpool.WithBlockGasLimit(cfg.Genesis.BlockGasLimitByHeight))
 	actPool, err := actpool.NewActPool(sf, cfg.ActPool, actOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create actpool")
@@ -224,6 +309,12 @@ func New(
 		chainOpts = append(chainOpts, blockchain.BlockValidatorOption(sf))
 	}
 
+	producerSigner, err := signer.NewProducerSigner(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create producer signer")
+	}
+	chainOpts = append(chainOpts, blockchain.SignerOption(producerSigner))
+
 	// create Blockchain
 	chain := blockchain.NewBlockchain(cfg, dao, factory.NewMinter(sf, actPool), chainOpts...)
 	if chain == nil {
@@ -244,11 +335,16 @@ func New(
 	}
 	copts := []consensus.Option{
 		consensus.WithBroadcast(func(msg proto.Message) error {
+			// the committed blocks are announced by the block syncer instead, the peers request those they lack
+			if _, ok := msg.(*iotextypes.Block); ok && cfg.BlockSync.AnnounceBlocks {
+				return nil
+			}
 			return p2pAgent.BroadcastOutbound(p2p.WitContext(context.Background(), p2p.Context{ChainID: chain.ChainID()}), msg)
 		}),
+		consensus.WithSigner(producerSigner),
+		consensus.WithActPool(actPool),
 	}
 	var (
-		rDPoSProtocol   *rolldpos.Protocol
 		pollProtocol    poll.Protocol
 		stakingProtocol *staking.Protocol
 	)
@@ -263,12 +359,6 @@ func New(
 		}
 	}
 	if cfg.Consensus.Scheme == config.RollDPoSScheme {
-		rDPoSProtocol = rolldpos.NewProtocol(
-			cfg.Genesis.NumCandidateDelegates,
-			cfg.Genesis.NumDelegates,
-			cfg.Genesis.NumSubEpochs,
-			rolldpos.EnableDardanellesSubEpoch(cfg.Genesis.DardanellesBlockHeight, cfg.Genesis.DardanellesNumSubEpochs),
-		)
 		copts = append(copts, consensus.WithRollDPoSProtocol(rDPoSProtocol))
 		pollProtocol, err = poll.NewProtocol(
 			cfg,
@@ -329,21 +419,139 @@ func New(
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create consensus")
 	}
-	bs, err := blocksync.NewBlockSyncer(
-		cfg,
-		chain,
-		dao,
-		consensus,
+	bsOpts := []blocksync.Option{
 		blocksync.WithUnicastOutBound(func(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error {
 			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
 			return p2pAgent.UnicastOutbound(ctx, peer, msg)
 		}),
 		blocksync.WithNeighbors(p2pAgent.Neighbors),
-	)
+		blocksync.WithRequestHeaders(func(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.RequestHeaders(ctx, peer, start, end)
+		}),
+		blocksync.WithSendHeaders(func(ctx context.Context, peer peerstore.PeerInfo, headers []*iotextypes.BlockHeader) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.SendHeaders(ctx, peer, headers)
+		}),
+		blocksync.WithAnnounceBlock(func(ctx context.Context, height uint64, h hash.Hash256) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.AnnounceBlock(ctx, height, h)
+		}),
+		blocksync.WithReportInvalidBlock(func(peer string) {
+			p2pAgent.ReportViolation(peer, p2p.ViolationInvalidBlock)
+		}),
+		blocksync.WithRequestStateRange(func(ctx context.Context, peer peerstore.PeerInfo, id, height uint64, nsKey, start []byte, limit uint64) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.RequestStateRange(ctx, peer, id, height, nsKey, start, limit)
+		}),
+		blocksync.WithSendStateRange(func(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.SendStateRange(ctx, peer, id, data)
+		}),
+	}
+	// only the factory of the state trie serves the ranges of the trie
+	if sr, ok := sf.(factory.StateRangeReader); ok {
+		bsOpts = append(bsOpts, blocksync.WithStateRangeReader(sr))
+	}
+	bs, err := blocksync.NewBlockSyncer(cfg, chain, dao, consensus, bsOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create blockSyncer")
 	}
 
+	var relay *delegateRelay
+	if pollProtocol != nil {
+		relay = newDelegateRelay(
+			func() ([]string, error) {
+				ctx := protocol.WithBlockchainCtx(
+					protocol.WithRegistry(context.Background(), registry),
+					protocol.BlockchainCtx{
+						Genesis: cfg.Genesis,
+					},
+				)
+				candidates, err := pollProtocol.Delegates(ctx, sf)
+				if err != nil {
+					return nil, err
+				}
+				addrs := []string{}
+				for _, candidate := range candidates {
+					addrs = append(addrs, candidate.Address)
+				}
+				return addrs, nil
+			},
+			p2pAgent.Neighbors,
+			func(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error {
+				ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+				return p2pAgent.UnicastOutbound(ctx, peer, msg)
+			},
+		)
+	}
+	p2pAgent.RegisterValidator(chain.ChainID(), iotexrpc.MessageType_CONSENSUS, func(ctx context.Context, msg proto.Message) error {
+		consensusMsg := msg.(*iotextypes.ConsensusMessage)
+		if err := crolldpos.ValidateConsensusMsg(consensusMsg); err != nil {
+			return err
+		}
+		if peer, ok := p2p.GetBroadcastPeer(ctx); ok && relay != nil {
+			relay.Observe(peer, consensusMsg)
+		}
+		return nil
+	})
+	p2pAgent.RegisterValidator(chain.ChainID(), iotexrpc.MessageType_ACTION, func(_ context.Context, msg proto.Message) error {
+		return actpool.ValidateAction(msg.(*iotextypes.Action))
+	})
+	p2pAgent.RegisterValidator(chain.ChainID(), iotexrpc.MessageType_BLOCK, func(_ context.Context, msg proto.Message) error {
+		return blocksync.ValidateBlock(msg.(*iotextypes.Block))
+	})
+
+	as := actsync.NewActionSync(
+		cfg.ActionSync,
+		actPool,
+		func(ctx context.Context, hashes []hash.Hash256) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.AnnounceActions(ctx, hashes)
+		},
+		func(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.RequestActions(ctx, peer, hashes)
+		},
+		func(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error {
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chain.ChainID()})
+			return p2pAgent.UnicastOutbound(ctx, peer, msg)
+		},
+	)
+
+	var evidenceReader scheme.EvidenceReader
+	if r, ok := consensus.(scheme.EvidenceReader); ok {
+		evidenceReader = r
+	}
+	var roundHistoryReader scheme.RoundHistoryReader
+	if r, ok := consensus.(scheme.RoundHistoryReader); ok {
+		roundHistoryReader = r
+	}
+	apiOpts := []api.Option{
+		api.WithBroadcastOutbound(func(ctx context.Context, chainID uint32, msg proto.Message) error {
+			if actPb, ok := msg.(*iotextypes.Action); ok && cfg.ActionSync.AnnounceActions {
+				var selp action.SealedEnvelope
+				if err := selp.LoadProto(actPb); err != nil {
+					return err
+				}
+				as.Announce(selp.Hash())
+				return nil
+			}
+			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chainID})
+			return p2pAgent.BroadcastOutbound(ctx, msg)
+		}),
+		api.WithNativeElection(electionCommittee),
+		api.WithNeighbors(p2pAgent.Neighbors),
+		api.WithDenylist(denied),
+		api.WithCandidatesBucketsIndexer(candBucketsIndexer),
+		api.WithEvidenceReader(evidenceReader),
+		api.WithRoundHistoryReader(roundHistoryReader),
+	}
+	if relay != nil {
+		apiOpts = append(apiOpts, api.WithPrivateRelay(func(ctx context.Context, _ uint32, msg proto.Message) error {
+			return relay.Relay(ctx, msg)
+		}))
+	}
 	var apiSvr *api.Server
 	apiSvr, err = api.NewServer(
 		cfg,
@@ -355,11 +563,7 @@ func New(
 		bfIndexer,
 		actPool,
 		registry,
-		api.WithBroadcastOutbound(func(ctx context.Context, chainID uint32, msg proto.Message) error {
-			ctx = p2p.WitContext(ctx, p2p.Context{ChainID: chainID})
-			return p2pAgent.BroadcastOutbound(ctx, msg)
-		}),
-		api.WithNativeElection(electionCommittee),
+		apiOpts...,
 	)
 	if err != nil {
 		return nil, err
@@ -391,6 +595,29 @@ func New(
 			return nil, err
 		}
 	}
+	if pn := cfg.Chain.ProbationNotifier; pn.Enabled && pollProtocol != nil {
+		hooks := []poll.ProbationHook{poll.NewMetricProbationHook()}
+		if pn.WebhookURL != "" {
+			hooks = append(hooks, poll.NewWebhookProbationHook(pn.WebhookURL, pn.WebhookTimeout))
+		}
+		notifier, err := poll.NewProbationNotifier(
+			cfg.Genesis,
+			registry,
+			sf,
+			candidatesutil.ProbationListFromDB,
+			func(start, end uint64) (map[string]uint64, error) {
+				return blockchain.Productivity(chain, start, end)
+			},
+			pn.Delegates,
+			hooks...,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create probation notifier")
+		}
+		if err := chain.AddSubscriber(notifier); err != nil {
+			return nil, errors.Wrap(err, "failed to add subscriber: probation notifier")
+		}
+	}
 
 	return &ChainService{
 		actpool:            actPool,
@@ -406,28 +633,31 @@ func New(
 		candBucketsIndexer: candBucketsIndexer,
 		api:                apiSvr,
 		registry:           registry,
+		denylist:           denied,
+		actsync:            as,
+		backupers:          backupers,
 	}, nil
 }
 
 // Start starts the server
 func (cs *ChainService) Start(ctx context.Context) error {
-	if cs.electionCommittee != nil {
-		if err := cs.electionCommittee.Start(ctx); err != nil {
-			return errors.Wrap(err, "error when starting election committee")
-		}
+	if err := cs.startChain(ctx); err != nil {
+		return err
 	}
-	if cs.candidateIndexer != nil {
-		if err := cs.candidateIndexer.Start(ctx); err != nil {
-			return errors.Wrap(err, "error when starting candidate indexer")
+	if cs.denylist != nil {
+		if err := cs.denylist.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting denylist")
 		}
 	}
-	if cs.candBucketsIndexer != nil {
-		if err := cs.candBucketsIndexer.Start(ctx); err != nil {
-			return errors.Wrap(err, "error when starting staking candidates indexer")
-		}
+	apCtx, err := cs.actpoolContext(ctx)
+	if err != nil {
+		return err
 	}
-	if err := cs.chain.Start(ctx); err != nil {
-		return errors.Wrap(err, "error when starting blockchain")
+	if err := cs.actpool.Start(apCtx); err != nil {
+		return errors.Wrap(err, "error when starting actpool")
+	}
+	if err := cs.actsync.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting action sync")
 	}
 	if err := cs.consensus.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting consensus")
@@ -472,6 +702,45 @@ func (cs *ChainService) Stop(ctx context.Context) error {
 	if err := cs.blocksync.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blocksync")
 	}
+	if err := cs.actsync.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping action sync")
+	}
+	if err := cs.actpool.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping actpool")
+	}
+	if cs.denylist != nil {
+		if err := cs.denylist.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping denylist")
+		}
+	}
+	return cs.stopChain(ctx)
+}
+
+// startChain starts the blockchain and the indexers it depends on
+func (cs *ChainService) startChain(ctx context.Context) error {
+	if cs.electionCommittee != nil {
+		if err := cs.electionCommittee.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting election committee")
+		}
+	}
+	if cs.candidateIndexer != nil {
+		if err := cs.candidateIndexer.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting candidate indexer")
+		}
+	}
+	if cs.candBucketsIndexer != nil {
+		if err := cs.candBucketsIndexer.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting staking candidates indexer")
+		}
+	}
+	if err := cs.chain.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting blockchain")
+	}
+	return nil
+}
+
+// stopChain stops the blockchain and the indexers it depends on
+func (cs *ChainService) stopChain(ctx context.Context) error {
 	if err := cs.chain.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blockchain")
 	}
@@ -491,18 +760,49 @@ func (cs *ChainService) Stop(ctx context.Context) error {
 	return nil
 }
 
+// ImportChain imports a chain archive, with the blockchain started alone, and returns the number of blocks committed
+func (cs *ChainService) ImportChain(ctx context.Context, archive string) (uint64, error) {
+	if err := cs.startChain(ctx); err != nil {
+		return 0, err
+	}
+	imported, err := chainarchive.ImportFile(cs.chain, cs.consensus.ValidateBlockFooter, archive)
+	if stopErr := cs.stopChain(ctx); err == nil {
+		err = stopErr
+	}
+	return imported, err
+}
+
 // HandleAction handles incoming action request.
 func (cs *ChainService) HandleAction(ctx context.Context, actPb *iotextypes.Action) error {
 	var act action.SealedEnvelope
 	if err := act.LoadProto(actPb); err != nil {
 		return err
 	}
-	ctx = protocol.WithRegistry(ctx, cs.registry)
-	err := cs.actpool.Add(ctx, act)
+	ctx, err := cs.actpoolContext(ctx)
 	if err != nil {
+		return err
+	}
+	// The actions in pool broadcast by a peer are limited
+	if peer, ok := p2p.GetBroadcastPeer(ctx); ok {
+		ctx = actpool.WithActionSource(ctx, "peer:"+peer)
+	}
+	if err := cs.actpool.Add(ctx, act); err != nil {
 		log.L().Debug(err.Error())
+		return err
+	}
+	cs.actsync.ReceiveAction(act.Hash())
+	return nil
+}
+
+// actpoolContext returns the context of adding actions to the actpool, whose validators need the registry and the
+// blockchain context of the tip
+func (cs *ChainService) actpoolContext(ctx context.Context) (context.Context, error) {
+	bcCtx, err := cs.chain.Context()
+	if err != nil {
+		return nil, err
 	}
-	return err
+	ctx = protocol.WithBlockchainCtx(ctx, protocol.MustGetBlockchainCtx(bcCtx))
+	return protocol.WithRegistry(ctx, cs.registry), nil
 }
 
 // HandleBlock handles incoming block request.
@@ -511,7 +811,7 @@ func (cs *ChainService) HandleBlock(ctx context.Context, pbBlock *iotextypes.Blo
 	if err := blk.ConvertFromBlockPb(pbBlock); err != nil {
 		return err
 	}
-	return cs.blocksync.ProcessBlock(ctx, blk)
+	return cs.blocksync.ProcessBlock(withBlockPeer(ctx), blk)
 }
 
 // HandleBlockSync handles incoming block sync request.
@@ -520,7 +820,18 @@ func (cs *ChainService) HandleBlockSync(ctx context.Context, pbBlock *iotextypes
 	if err := blk.ConvertFromBlockPb(pbBlock); err != nil {
 		return err
 	}
-	return cs.blocksync.ProcessBlockSync(ctx, blk)
+	return cs.blocksync.ProcessBlockSync(withBlockPeer(ctx), blk)
+}
+
+// withBlockPeer attaches the peer a block is received from, whose responses to the sync requests are scored
+func withBlockPeer(ctx context.Context) context.Context {
+	if peer, ok := p2p.GetUnicastPeer(ctx); ok {
+		return blocksync.WithPeer(ctx, peer)
+	}
+	if peer, ok := p2p.GetBroadcastPeer(ctx); ok {
+		return blocksync.WithPeer(ctx, peer)
+	}
+	return ctx
 }
 
 // HandleSyncRequest handles incoming sync request.
@@ -528,6 +839,57 @@ func (cs *ChainService) HandleSyncRequest(ctx context.Context, peer peerstore.Pe
 	return cs.blocksync.ProcessSyncRequest(ctx, peer, sync)
 }
 
+// HandleHeaderRequest handles incoming header request.
+func (cs *ChainService) HandleHeaderRequest(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error {
+	return cs.blocksync.ProcessHeaderRequest(ctx, peer, start, end)
+}
+
+// HandleHeaders handles incoming headers.
+func (cs *ChainService) HandleHeaders(ctx context.Context, peer peerstore.PeerInfo, pbHeaders []*iotextypes.BlockHeader) error {
+	headers := make([]*block.Header, 0, len(pbHeaders))
+	for _, pb := range pbHeaders {
+		header := &block.Header{}
+		if err := header.LoadFromBlockHeaderProto(pb); err != nil {
+			return err
+		}
+		headers = append(headers, header)
+	}
+	return cs.blocksync.ProcessHeaders(ctx, peer, headers)
+}
+
+// HandleBlockAnnouncement handles incoming block announcement.
+func (cs *ChainService) HandleBlockAnnouncement(ctx context.Context, peer peerstore.PeerInfo, height uint64, h hash.Hash256) error {
+	return cs.blocksync.ProcessBlockAnnouncement(ctx, peer, height, h)
+}
+
+// HandleStateRangeRequest handles incoming state range request.
+func (cs *ChainService) HandleStateRangeRequest(
+	ctx context.Context,
+	peer peerstore.PeerInfo,
+	id uint64,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) error {
+	return cs.blocksync.ProcessStateRangeRequest(ctx, peer, id, height, nsKey, start, limit)
+}
+
+// HandleStateRange handles incoming state range.
+func (cs *ChainService) HandleStateRange(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error {
+	return cs.blocksync.ProcessStateRange(ctx, peer, id, data)
+}
+
+// HandleActionAnnouncement handles incoming action announcement.
+func (cs *ChainService) HandleActionAnnouncement(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error {
+	return cs.actsync.HandleAnnouncement(ctx, peer, hashes)
+}
+
+// HandleActionRequest handles incoming action request.
+func (cs *ChainService) HandleActionRequest(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error {
+	return cs.actsync.HandleRequest(ctx, peer, hashes)
+}
+
 // HandleConsensusMsg handles incoming consensus message.
 func (cs *ChainService) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 	return cs.consensus.HandleConsensusMsg(msg)
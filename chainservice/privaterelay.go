@@ -0,0 +1,105 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package chainservice
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// maxDelegatePeers is the max number of the signers of the consensus messages whose peers are kept
+const maxDelegatePeers = 1000
+
+// errNoDelegatePeer indicates none of the current delegates is reachable
+var errNoDelegatePeer = errors.New("no peer of the current delegates is known")
+
+type (
+	// delegateRelay relays the private actions to the peers of the current delegates only. The peer of a delegate is
+	// learnt from the consensus messages, which are endorsed by the delegate and broadcast from its peer, both verified.
+	delegateRelay struct {
+		peers     *cache.ThreadSafeLruCache
+		delegates func() ([]string, error)
+		neighbors func(context.Context) ([]peerstore.PeerInfo, error)
+		unicast   func(context.Context, peerstore.PeerInfo, proto.Message) error
+	}
+)
+
+func newDelegateRelay(
+	delegates func() ([]string, error),
+	neighbors func(context.Context) ([]peerstore.PeerInfo, error),
+	unicast func(context.Context, peerstore.PeerInfo, proto.Message) error,
+) *delegateRelay {
+	return &delegateRelay{
+		peers:     cache.NewThreadSafeLruCache(maxDelegatePeers),
+		delegates: delegates,
+		neighbors: neighbors,
+		unicast:   unicast,
+	}
+}
+
+// Observe records the peer broadcasting the consensus message as the peer of its endorser, the message should have
+// been validated
+func (r *delegateRelay) Observe(peer string, msg *iotextypes.ConsensusMessage) {
+	en := &endorsement.Endorsement{}
+	if err := en.LoadProto(msg.GetEndorsement()); err != nil {
+		return
+	}
+	addr, err := address.FromBytes(en.Endorser().Hash())
+	if err != nil {
+		return
+	}
+	r.peers.Add(addr.String(), peer)
+}
+
+// Relay sends the message to the reachable peers of the current delegates, and returns an error if none of them
+// receives it
+func (r *delegateRelay) Relay(ctx context.Context, msg proto.Message) error {
+	delegates, err := r.delegates()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the current delegates")
+	}
+	nbs, err := r.neighbors(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the neighbors")
+	}
+	infos := make(map[string]peerstore.PeerInfo, len(nbs))
+	for _, nb := range nbs {
+		infos[nb.ID.Pretty()] = nb
+	}
+	var sent int
+	relayed := make(map[string]bool)
+	for _, delegate := range delegates {
+		v, ok := r.peers.Get(delegate)
+		if !ok {
+			continue
+		}
+		info, ok := infos[v.(string)]
+		if !ok || relayed[v.(string)] {
+			continue
+		}
+		relayed[v.(string)] = true
+		if err := r.unicast(ctx, info, msg); err != nil {
+			log.L().Debug("Failed to relay the private action.", zap.String("delegate", delegate), zap.Error(err))
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return errNoDelegatePeer
+	}
+	return nil
+}
@@ -8,7 +8,9 @@ package config
 
 import (
 	"crypto/ecdsa"
+	"encoding/hex"
 	"flag"
+	"math"
 	"math/big"
 	"os"
 	"strings"
@@ -17,12 +19,16 @@ import (
 	"github.com/iotexproject/go-p2p"
 	"github.com/iotexproject/go-pkgs/crypto"
 	"github.com/iotexproject/iotex-election/committee"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
 	uconfig "go.uber.org/config"
 	"go.uber.org/zap"
 
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/pkg/compress"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/unit"
 )
@@ -53,6 +59,9 @@ const (
 	StandaloneScheme = "STANDALONE"
 	// NOOPScheme means that the node does not create only block
 	NOOPScheme = "NOOP"
+	// DevScheme means that the node seals a block as soon as an action arrives, or at a fixed interval, to run a local
+	// development chain
+	DevScheme = "DEV"
 )
 
 const (
@@ -84,6 +93,11 @@ const (
 
 	SigP256k1  = "secp256k1"
 	SigP256sm2 = "p256sm2"
+
+	// BoltBackend, BadgerBackend and PebbleBackend are the backends of the index dbs
+	BoltBackend   = "bolt"
+	BadgerBackend = "badger"
+	PebbleBackend = "pebble"
 )
 
 var (
@@ -92,15 +106,24 @@ var (
 		Plugins: make(map[int]interface{}),
 		SubLogs: make(map[string]log.GlobalConfig),
 		Network: Network{
-			Host:              "0.0.0.0",
-			Port:              4689,
-			ExternalHost:      "",
-			ExternalPort:      4689,
-			BootstrapNodes:    []string{},
-			MasterKey:         "",
-			RateLimit:         p2p.DefaultRatelimitConfig,
-			EnableRateLimit:   true,
-			PrivateNetworkPSK: "",
+			Host:                 "0.0.0.0",
+			Port:                 4689,
+			ExternalHost:         "",
+			ExternalPort:         4689,
+			BootstrapNodes:       []string{},
+			MasterKey:            "",
+			RateLimit:            p2p.DefaultRatelimitConfig,
+			EnableRateLimit:      true,
+			PrivateNetworkPSK:    "",
+			StaticPeers:          []string{},
+			DNSSeeds:             []string{},
+			PeerBookPath:         "",
+			PeerMaintainInterval: time.Minute,
+			BanThreshold:         100,
+			BanDuration:          10 * time.Minute,
+			MaxBanDuration:       24 * time.Hour,
+			PeerMessageRate:      500,
+			Compression:          "",
 		},
 		Chain: Chain{
 			ChainDBPath:            "/var/data/chain.db",
@@ -110,17 +133,20 @@ var (
 			CandidateIndexDBPath:   "/var/data/candidate.index.db",
 			StakingIndexDBPath:     "/var/data/staking.index.db",
 			ID:                     1,
+			EVMNetworkID:           4689,
 			Address:                "",
 			ProducerPrivKey:        generateRandomKey(SigP256k1),
 			SignatureScheme:        []string{SigP256k1},
 			EmptyGenesis:           false,
-			GravityChainDB:         DB{DbPath: "/var/data/poll.db", NumRetries: 10},
+			GravityChainDB:         DB{DbPath: "/var/data/poll.db", NumRetries: 10, EncryptedNamespaces: []string{}},
 			Committee: committee.Config{
 				GravityChainAPIs: []string{},
 			},
 			EnableTrielessStateDB:         true,
 			EnableStateDBCaching:          false,
 			EnableArchiveMode:             false,
+			StateRetention:                0,
+			StateDiffRetention:            0,
 			EnableAsyncIndexWrite:         true,
 			EnableSystemLogIndexer:        false,
 			EnableStakingProtocol:         true,
@@ -131,14 +157,48 @@ var (
 			PollInitialCandidatesInterval: 10 * time.Second,
 			StateDBCacheSize:              1000,
 			WorkingSetCacheSize:           20,
+			MaxReorgDepth:                 0,
+			BlockComposition: BlockComposition{
+				MaxExecutions:       0,
+				MaxCalldataBytes:    0,
+				TransferReservedGas: 0,
+			},
+			RemoteSigner: RemoteSigner{
+				Endpoint:            "",
+				AllowedMessageTypes: []string{"block", "endorsement", "action"},
+				Timeout:             2 * time.Second,
+			},
+			ProbationNotifier: ProbationNotifier{
+				Enabled:        false,
+				WebhookURL:     "",
+				WebhookTimeout: 5 * time.Second,
+				Delegates:      []string{},
+			},
 		},
 		ActPool: ActPool{
-			MaxNumActsPerPool:  32000,
-			MaxGasLimitPerPool: 320000000,
-			MaxNumActsPerAcct:  2000,
-			ActionExpiry:       10 * time.Minute,
-			MinGasPriceStr:     big.NewInt(unit.Qev).String(),
-			BlackList:          []string{},
+			MaxNumActsPerPool:     32000,
+			MaxGasLimitPerPool:    320000000,
+			MaxNumActsPerAcct:     2000,
+			ActionExpiry:          10 * time.Minute,
+			MinGasPriceStr:        big.NewInt(unit.Qev).String(),
+			ReplaceGasPriceBump:   10,
+			BlackList:             []string{},
+			JournalRotateInterval: time.Hour,
+			LocalAccounts:         []string{},
+			GasPriceSteps: []GasPriceStep{
+				{Utilization: 70, Multiplier: 200},
+				{Utilization: 85, Multiplier: 500},
+				{Utilization: 95, Multiplier: 1000},
+			},
+			GasPriceDecay:          20,
+			MaxNumActsPerSource:    8000,
+			AdmissionWindow:        time.Minute,
+			MaxAdmissionsPerSender: 600,
+			MaxAdmissionsPerSource: 6000,
+			DenylistReloadInterval: 10 * time.Second,
+			MaxNumOrphans:          1024,
+			MaxNumOrphansPerAcct:   64,
+			MaxOrphanNonceGap:      1000,
 		},
 		Consensus: Consensus{
 			Scheme: StandaloneScheme,
@@ -151,11 +211,21 @@ var (
 					AcceptLockEndorsementTTL:     2 * time.Second,
 					CommitTTL:                    2 * time.Second,
 					EventChanSize:                10000,
+					Adaptive: AdaptiveTiming{
+						Enabled:  false,
+						Window:   20,
+						Margin:   1.5,
+						MinRatio: 0.5,
+						MaxRatio: 1.5,
+					},
 				},
 				ToleratedOvertime: 2 * time.Second,
 				Delay:             5 * time.Second,
 				ConsensusDBPath:   "/var/data/consensus.db",
 			},
+			Dev: Dev{
+				Interval: 0,
+			},
 		},
 		BlockSync: BlockSync{
 			Interval:              10 * time.Second,
@@ -164,20 +234,91 @@ var (
 			IntervalSize:          20,
 			MaxRepeat:             3,
 			RepeatDecayStep:       1,
+			MaxSegmentsPerPeer:    2,
+			SegmentTTL:            10 * time.Second,
+			PeerDemotion:          time.Minute,
+			MaxPeerFailureRate:    0.5,
+			HeaderFirst:           false,
+			HeaderBatchSize:       500,
+			AnnounceBlocks:        false,
+			StallIntervals:        6,
+			ServeQuota: ServeQuota{
+				MaxBlocksPerRequest: 200,
+				BlocksPerSecond:     100,
+				BlockBurst:          400,
+				BytesPerSecond:      8 << 20,
+				ByteBurst:           32 << 20,
+				MaxPeers:            1000,
+			},
+			StateRangeLimit: 1024,
+		},
+		ActionSync: ActionSync{
+			AnnounceActions:  false,
+			AnnounceInterval: 100 * time.Millisecond,
+			MaxHashesPerMsg:  1024,
+			RequestTTL:       10 * time.Second,
 		},
 		Dispatcher: Dispatcher{
-			EventChanSize: 10000,
+			EventChanSize:         10000,
+			ActionVerifyWorkers:   4,
+			ActionVerifyBatchSize: 64,
 		},
 		API: API{
 			UseRDS:    false,
 			Port:      14014,
+			Web3Port:  0,
+			RESTPort:  0,
 			TpsWindow: 10,
 			GasStation: GasStation{
 				SuggestBlockWindow: 20,
 				DefaultGas:         uint64(unit.Qev),
 				Percentile:         60,
+				FeeHistoryWindow:   1024,
+			},
+			RangeQueryLimit:   1000,
+			PageSize:          100,
+			StreamBufferSize:  128,
+			StreamTimeout:     2 * time.Second,
+			BatchRequestLimit: 100,
+			RateLimit: RateLimit{
+				MaxClients: 10000,
+			},
+			ResponseCache: ResponseCache{
+				Backend:  "memory",
+				Size:     10000,
+				RedisTTL: 24 * time.Hour,
+				TipDepth: 1,
+			},
+			GraphQL: GraphQL{
+				Port:          0,
+				MaxDepth:      10,
+				MaxComplexity: 1000,
+			},
+			Auth: Auth{
+				APIKeys: []string{},
+			},
+			Health: Health{
+				MaxSyncLag:    10,
+				MaxIndexerLag: 2,
+			},
+			LogQuery: LogQuery{
+				MaxBlocksScanned: 10000,
+				MaxLogs:          10000,
+			},
+			SlowQueryThreshold: time.Second,
+			HTTP: HTTP{
+				AllowedOrigins: []string{},
+				CORSMaxAge:     10 * time.Minute,
+			},
+			GRPC: GRPC{
+				EnableReflection:    true,
+				MaxRecvMsgSize:      4 << 20,
+				MaxSendMsgSize:      math.MaxInt32,
+				KeepaliveTime:       2 * time.Hour,
+				KeepaliveTimeout:    20 * time.Second,
+				KeepaliveMinTime:    5 * time.Minute,
+				PermitWithoutStream: false,
 			},
-			RangeQueryLimit: 1000,
 		},
 		System: System{
 			Active:                true,
@@ -186,8 +327,14 @@ var (
 			HTTPAdminPort:         9009,
 			StartSubChainInterval: 10 * time.Second,
 			SystemLogDBPath:       "/var/data/systemlog.db",
+			Standby: Standby{
+				PrimaryEndpoint:   "",
+				HeartbeatInterval: 5 * time.Second,
+				FailureWindow:     30 * time.Second,
+			},
 		},
 		DB: DB{
+			Backend:               BoltBackend,
 			NumRetries:            3,
 			MaxCacheSize:          64,
 			BlockStoreBatchSize:   16,
@@ -197,12 +344,43 @@ var (
 			SplitDBSizeMB:         0,
 			SplitDBHeight:         900000,
 			HistoryStateRetention: 2000,
+			ColdStorage: ColdStorage{
+				URL:       "",
+				Depth:     1000000,
+				CacheDir:  "/var/data/cold-cache",
+				CacheSize: 2,
+			},
+			Integrity: Integrity{
+				Interval:     0,
+				CompactRatio: 2,
+			},
+			SlowOpThreshold:     time.Second,
+			EncryptedNamespaces: []string{},
+			EncryptionKeyEnv:    "IOTEX_DB_ENCRYPTION_KEY",
+			Flush: FlushBatch{
+				MinBatchBytes: 0,
+				MaxBatchBytes: 64 * 1024 * 1024,
+				MaxDelay:      time.Second,
+			},
 		},
 		Indexer: Indexer{
 			RangeBloomFilterNumElements: 100000,
 			RangeBloomFilterSize:        1200000,
 			RangeBloomFilterNumHash:     8,
 		},
+		Snapshot: Snapshot{
+			Dir:      "",
+			Interval: 24,
+			Keep:     2,
+		},
+		LightClient: LightClient{
+			Endpoint:         "",
+			TrustedDelegates: []string{},
+			MaxHeaders:       10000,
+			SyncInterval:     10 * time.Second,
+			RequestTimeout:   10 * time.Second,
+			Port:             14016,
+		},
 		Genesis: genesis.Default,
 	}
 
@@ -213,10 +391,28 @@ var (
 	Validates = []Validate{
 		ValidateRollDPoS,
 		ValidateArchiveMode,
+		ValidateBlockRetention,
+		ValidateColdStorage,
+		ValidateReceiptRetention,
+		ValidateIntegrity,
+		ValidateDB,
+		ValidateNetwork,
+		ValidateReorg,
+		ValidateSnapshot,
+		ValidateCheckpoint,
 		ValidateDispatcher,
+		ValidateBlockSync,
+		ValidateActionSync,
 		ValidateAPI,
 		ValidateActPool,
+		ValidateBlockComposition,
 		ValidateForkHeights,
+		ValidateRemoteSigner,
+		ValidateStandby,
+		ValidateProbationNotifier,
+		ValidateDev,
+		ValidateBLS,
+		ValidateLightClient,
 	}
 )
 
@@ -235,6 +431,35 @@ type (
 		RateLimit         p2p.RateLimitConfig `yaml:"rateLimit"`
 		EnableRateLimit   bool                `yaml:"enableRateLimit"`
 		PrivateNetworkPSK string              `yaml:"privateNetworkPSK"`
+		// StaticPeers are the multiaddrs of the peers which are always dialed and never blocked
+		StaticPeers []string `yaml:"staticPeers"`
+		// DNSSeeds are the domains of the TXT records listing the multiaddrs of the peers to bootstrap from, or the
+		// trees of the records signed by the seed operators, given as tree://{public key in hex}@{domain}
+		DNSSeeds []string `yaml:"dnsSeeds"`
+		// PeerBookPath is the file persisting the known-good peers across restarts. Empty means disabled
+		PeerBookPath string `yaml:"peerBookPath"`
+		// PeerMaintainInterval is the interval to redial the static peers and persist the known-good peers
+		PeerMaintainInterval time.Duration `yaml:"peerMaintainInterval"`
+		// BanThreshold is the score of the protocol violations of a peer to ban it, 0 means the peers are never banned
+		// automatically
+		BanThreshold int `yaml:"banThreshold"`
+		// BanDuration is the time of the first ban of a peer, which doubles with each ban of the peer up to
+		// MaxBanDuration
+		BanDuration    time.Duration `yaml:"banDuration"`
+		MaxBanDuration time.Duration `yaml:"maxBanDuration"`
+		// PeerMessageRate is the max number of the messages received from a peer per second, beyond which the peer is
+		// spamming. 0 means unlimited
+		PeerMessageRate int `yaml:"peerMessageRate"`
+		// Compression is the compression of the broadcast and unicast messages sent, either Snappy or Zstd. Empty means
+		// no compression. The messages of all the compressions are received regardless. The unicast messages fall back
+		// to plain for the peers not supporting the compression, while the compressed broadcast messages are dropped
+		// by them, so it should be enabled once the network has upgraded
+		Compression string `yaml:"compression"`
+		// EnableNATPortMap maps the port on the UPnP or NAT-PMP gateway of the network and advertises the mapped
+		// address, unless ExternalHost is configured
+		EnableNATPortMap bool `yaml:"enableNATPortMap"`
+		// EnableAddressDiscovery keeps track of the public addresses of the node observed by the peers
+		EnableAddressDiscovery bool `yaml:"enableAddressDiscovery"`
 	}
 
 	// Chain is the config struct for blockchain package
@@ -246,6 +471,7 @@ type (
 		CandidateIndexDBPath   string           `yaml:"candidateIndexDBPath"`
 		StakingIndexDBPath     string           `yaml:"stakingIndexDBPath"`
 		ID                     uint32           `yaml:"id"`
+		EVMNetworkID           uint32           `yaml:"evmNetworkID"`
 		Address                string           `yaml:"address"`
 		ProducerPrivKey        string           `yaml:"producerPrivKey"`
 		SignatureScheme        []string         `yaml:"signatureScheme"`
@@ -258,6 +484,13 @@ type (
 		EnableStateDBCaching bool `yaml:"enableStateDBCaching"`
 		// EnableArchiveMode is only meaningful when EnableTrielessStateDB is false
 		EnableArchiveMode bool `yaml:"enableArchiveMode"`
+		// StateRetention is the number of recent heights whose state trie is kept when archive mode is disabled, the
+		// trie nodes replaced before are pruned. 0 means they are deleted right away. It is only meaningful when
+		// EnableTrielessStateDB is false
+		StateRetention uint64 `yaml:"stateRetention"`
+		// StateDiffRetention is the number of recent blocks whose changes of the accounts and the contract storage are
+		// recorded. 0 means disabled
+		StateDiffRetention uint64 `yaml:"stateDiffRetention"`
 		// EnableAsyncIndexWrite enables writing the block actions' and receipts' index asynchronously
 		EnableAsyncIndexWrite bool `yaml:"enableAsyncIndexWrite"`
 		// deprecated
@@ -278,6 +511,61 @@ type (
 		StateDBCacheSize int `yaml:"stateDBCacheSize"`
 		// WorkingSetCacheSize is the max size of workingset cache in state factory
 		WorkingSetCacheSize uint64 `yaml:"workingSetCacheSize"`
+		// MaxReorgDepth is the max number of blocks reverted to switch to a longer side chain, the state keeps the undo
+		// logs of as many recent blocks. 0 means disabled
+		MaxReorgDepth uint64 `yaml:"maxReorgDepth"`
+		// BlockComposition shapes the composition of the blocks produced by the node
+		BlockComposition BlockComposition `yaml:"blockComposition"`
+		// RemoteSigner signs the blocks and the endorsements with the producer key kept by a remote signing service
+		RemoteSigner RemoteSigner `yaml:"remoteSigner"`
+		// ProbationNotifier notifies the delegates at risk of, entering and resuming from probation
+		ProbationNotifier ProbationNotifier `yaml:"probationNotifier"`
+		// BLSPrivKey is the BLS private key signing the commit endorsements to be aggregated in the block footer after
+		// the BLS aggregation height of the genesis
+		BLSPrivKey string `yaml:"blsPrivKey"`
+	}
+
+	// BlockComposition is the config struct of the limits applied when the node picks actions into its block
+	BlockComposition struct {
+		// MaxExecutions is the max number of contract executions in a block. 0 means unlimited
+		MaxExecutions int `yaml:"maxExecutions"`
+		// MaxCalldataBytes is the max total size of the execution data and the transfer payloads in a block. 0 means
+		// unlimited
+		MaxCalldataBytes uint64 `yaml:"maxCalldataBytes"`
+		// TransferReservedGas is the amount of block gas only transfers could use, so that transfers are still packed
+		// when the pool is flooded with other actions
+		TransferReservedGas uint64 `yaml:"transferReservedGas"`
+	}
+
+	// RemoteSigner is the config struct of the remote signing service of the block producer key
+	RemoteSigner struct {
+		// Endpoint is the https URL of the signing service. Empty means the blocks are signed with ProducerPrivKey
+		Endpoint string `yaml:"endpoint"`
+		// PublicKey is the public key of the producer key kept by the service, against which the signatures are checked
+		PublicKey string `yaml:"publicKey"`
+		// CACertPath is the certificate of the CA the certificate of the service is checked with, the system roots are
+		// used if it is empty
+		CACertPath string `yaml:"caCertPath"`
+		// ClientCertPath and ClientKeyPath are the certificate and the key the node authenticates itself with
+		ClientCertPath string `yaml:"clientCertPath"`
+		ClientKeyPath  string `yaml:"clientKeyPath"`
+		// AllowedMessageTypes are the types of the messages sent to the service to sign, which are block, endorsement
+		// and action
+		AllowedMessageTypes []string `yaml:"allowedMessageTypes"`
+		// Timeout is the timeout of a signing request
+		Timeout time.Duration `yaml:"timeout"`
+	}
+
+	// ProbationNotifier is the config struct of the notifications of the probation of the delegates
+	ProbationNotifier struct {
+		// Enabled enables the notifications, which are exported as metrics
+		Enabled bool `yaml:"enabled"`
+		// WebhookURL is the URL the events are posted to in JSON. Empty means no webhook
+		WebhookURL string `yaml:"webhookURL"`
+		// WebhookTimeout is the timeout of posting an event to the webhook
+		WebhookTimeout time.Duration `yaml:"webhookTimeout"`
+		// Delegates are the addresses of the delegates to notify about. Empty means all the delegates
+		Delegates []string `yaml:"delegates"`
 	}
 
 	// Consensus is the config struct for consensus package
@@ -285,6 +573,14 @@ type (
 		// There are three schemes that are supported
 		Scheme   string   `yaml:"scheme"`
 		RollDPoS RollDPoS `yaml:"rollDPoS"`
+		Dev      Dev      `yaml:"dev"`
+	}
+
+	// Dev is the config struct for the development consensus scheme
+	Dev struct {
+		// Interval is the interval at which a block is sealed whether there are actions or not. 0 means a block is
+		// sealed as soon as an action arrives
+		Interval time.Duration `yaml:"interval"`
 	}
 
 	// BlockSync is the config struct for the BlockSync
@@ -293,10 +589,77 @@ type (
 		ProcessSyncRequestTTL time.Duration `yaml:"processSyncRequestTTL"`
 		BufferSize            uint64        `yaml:"bufferSize"`
 		IntervalSize          uint64        `yaml:"intervalSize"`
-		// MaxRepeat is the maximal number of repeat of a block sync request
+		// deprecated by the segments requested from different peers
 		MaxRepeat int `yaml:"maxRepeat"`
-		// RepeatDecayStep is the step for repeat number decreasing by 1
+		// deprecated by the segments requested from different peers
 		RepeatDecayStep int `yaml:"repeatDecayStep"`
+		// MaxSegmentsPerPeer is the max number of segments of missing blocks requested from a peer at once
+		MaxSegmentsPerPeer int `yaml:"maxSegmentsPerPeer"`
+		// SegmentTTL is the time to wait for the blocks of a segment before requesting them from another peer
+		SegmentTTL time.Duration `yaml:"segmentTTL"`
+		// PeerDemotion is the period a peer failing too many requests or sending invalid blocks is demoted for, during
+		// which the peer is requested only if there is no other peer
+		PeerDemotion time.Duration `yaml:"peerDemotion"`
+		// MaxPeerFailureRate is the moving average of the rate of the requests failed by a peer above which it's demoted
+		MaxPeerFailureRate float64 `yaml:"maxPeerFailureRate"`
+		// HeaderFirst syncs and validates the headers ahead of the blocks, whose bodies are then fetched in parallel
+		// and have to match the validated headers
+		HeaderFirst bool `yaml:"headerFirst"`
+		// HeaderBatchSize is the max number of headers requested from or served to a peer at once
+		HeaderBatchSize uint64 `yaml:"headerBatchSize"`
+		// AnnounceBlocks announces the height and the hash of a new block to the peers instead of broadcasting the full
+		// block, the peers lacking the block request it from the announcer. It should be enabled on all the nodes,
+		// otherwise those not enabling it receive the new blocks only by the periodic sync.
+		AnnounceBlocks bool `yaml:"announceBlocks"`
+		// StallIntervals is the number of sync intervals without any block committed while behind the target height,
+		// after which the sync is deemed stalled. The peers feeding the stalled range are then rotated and the blocks
+		// staged are dropped. 0 disables the detection.
+		StallIntervals int `yaml:"stallIntervals"`
+		// ServeQuota limits the blocks served to each peer
+		ServeQuota ServeQuota `yaml:"serveQuota"`
+		// Checkpoint is the trusted block, from whose state snapshot a new node starts to sync
+		Checkpoint Checkpoint `yaml:"checkpoint"`
+		// StateRangeLimit is the max number of leaves of the state trie served to a peer for a state range request
+		StateRangeLimit uint64 `yaml:"stateRangeLimit"`
+	}
+
+	// ServeQuota is the quota of the blocks served to each peer, the blocks and the bytes of the responses are charged
+	// against token buckets refilled at the given rates. A request beyond the quota is served up to the quota, and a 0
+	// rate disables the bucket
+	ServeQuota struct {
+		// MaxBlocksPerRequest is the max number of blocks served for a request, the rest of the range is dropped
+		MaxBlocksPerRequest uint64  `yaml:"maxBlocksPerRequest"`
+		BlocksPerSecond     float64 `yaml:"blocksPerSecond"`
+		BlockBurst          int     `yaml:"blockBurst"`
+		BytesPerSecond      float64 `yaml:"bytesPerSecond"`
+		ByteBurst           int     `yaml:"byteBurst"`
+		// MaxPeers is the number of peers whose buckets are kept, the least recently served are dropped
+		MaxPeers int `yaml:"maxPeers"`
+	}
+
+	// Checkpoint is a trusted block and its state root. A new node imports the state snapshot at the checkpoint, which
+	// is accepted only if it matches the checkpoint, and fully validates the blocks after it. 0 height means disabled
+	Checkpoint struct {
+		Height uint64 `yaml:"height"`
+		// Hash is the hex encoded hash of the block at Height
+		Hash string `yaml:"hash"`
+		// StateRoot is the hex encoded root of the account trie at Height, empty for a trieless state DB
+		StateRoot string `yaml:"stateRoot"`
+		// Snapshot is the url of the snapshot archive, file:///dir/file or s3://bucket/prefix/file
+		Snapshot string `yaml:"snapshot"`
+	}
+
+	// ActionSync is the config struct for the exchange of pending actions between peers
+	ActionSync struct {
+		// AnnounceActions sends the hashes of the actions received by the API to the neighbors, instead of broadcasting
+		// the actions. The neighbors request the actions they miss from the node.
+		AnnounceActions bool `yaml:"announceActions"`
+		// AnnounceInterval is the interval to batch the hashes to announce
+		AnnounceInterval time.Duration `yaml:"announceInterval"`
+		// MaxHashesPerMsg is the maximal number of hashes in an announcement or a request
+		MaxHashesPerMsg int `yaml:"maxHashesPerMsg"`
+		// RequestTTL is the duration an action requested from a peer is not requested again
+		RequestTTL time.Duration `yaml:"requestTTL"`
 	}
 
 	// RollDPoS is the config struct for RollDPoS consensus package
@@ -309,28 +672,171 @@ type (
 
 	// ConsensusTiming defines a set of time durations used in fsm and event queue size
 	ConsensusTiming struct {
-		EventChanSize                uint          `yaml:"eventChanSize"`
-		UnmatchedEventTTL            time.Duration `yaml:"unmatchedEventTTL"`
-		UnmatchedEventInterval       time.Duration `yaml:"unmatchedEventInterval"`
-		AcceptBlockTTL               time.Duration `yaml:"acceptBlockTTL"`
-		AcceptProposalEndorsementTTL time.Duration `yaml:"acceptProposalEndorsementTTL"`
-		AcceptLockEndorsementTTL     time.Duration `yaml:"acceptLockEndorsementTTL"`
-		CommitTTL                    time.Duration `yaml:"commitTTL"`
+		EventChanSize                uint           `yaml:"eventChanSize"`
+		UnmatchedEventTTL            time.Duration  `yaml:"unmatchedEventTTL"`
+		UnmatchedEventInterval       time.Duration  `yaml:"unmatchedEventInterval"`
+		AcceptBlockTTL               time.Duration  `yaml:"acceptBlockTTL"`
+		AcceptProposalEndorsementTTL time.Duration  `yaml:"acceptProposalEndorsementTTL"`
+		AcceptLockEndorsementTTL     time.Duration  `yaml:"acceptLockEndorsementTTL"`
+		CommitTTL                    time.Duration  `yaml:"commitTTL"`
+		Adaptive                     AdaptiveTiming `yaml:"adaptive"`
+	}
+
+	// AdaptiveTiming is the config to adapt the ttls of the consensus phases to how long the phases of the recent
+	// rounds take, within [MinRatio, MaxRatio] of the configured ttls and the block interval
+	AdaptiveTiming struct {
+		Enabled bool `yaml:"enabled"`
+		// Window is the number of the recent rounds the ttls are adapted to
+		Window uint `yaml:"window"`
+		// Margin is the ratio of the ttl of a phase to the longest duration of the phase in the window
+		Margin   float64 `yaml:"margin"`
+		MinRatio float64 `yaml:"minRatio"`
+		MaxRatio float64 `yaml:"maxRatio"`
 	}
 
 	// Dispatcher is the dispatcher config
 	Dispatcher struct {
 		EventChanSize uint `yaml:"eventChanSize"`
+		// ActionVerifyWorkers is the number of workers verifying the signatures of incoming actions
+		ActionVerifyWorkers uint `yaml:"actionVerifyWorkers"`
+		// ActionVerifyBatchSize is the max number of incoming actions verified in a batch
+		ActionVerifyBatchSize uint `yaml:"actionVerifyBatchSize"`
 		// TODO: explorer dependency deleted at #1085, need to revive by migrating to api
 	}
 
 	// API is the api service config
 	API struct {
-		UseRDS          bool       `yaml:"useRDS"`
-		Port            int        `yaml:"port"`
+		UseRDS bool `yaml:"useRDS"`
+		Port   int  `yaml:"port"`
+		// Web3Port is the port of the ethereum compatible JSON-RPC service, 0 means disabled
+		Web3Port int `yaml:"web3Port"`
+		// RESTPort is the port of the RESTful JSON gateway of the api, 0 means disabled
+		RESTPort        int        `yaml:"restPort"`
 		TpsWindow       int        `yaml:"tpsWindow"`
 		GasStation      GasStation `yaml:"gasStation"`
 		RangeQueryLimit uint64     `yaml:"rangeQueryLimit"`
+		// PageSize is the default number of items in a page of a cursor paginated query, capped by RangeQueryLimit
+		PageSize uint64 `yaml:"pageSize"`
+		// StreamBufferSize is the number of blocks queued for a streaming client
+		StreamBufferSize int `yaml:"streamBufferSize"`
+		// StreamTimeout is how long a new block waits for a streaming client whose queue is full, before the client is
+		// dropped. The wait holds back the blocks of the other streaming clients, and 0 drops the client at once.
+		StreamTimeout time.Duration `yaml:"streamTimeout"`
+		// EnableDebugAPI enables the evm tracing of executions, which is expensive and should not be exposed publicly
+		EnableDebugAPI bool `yaml:"enableDebugAPI"`
+		// RateLimit is the rate limit of the grpc and web3 requests
+		RateLimit RateLimit `yaml:"rateLimit"`
+		// BatchRequestLimit is the maximum number of requests in a web3 batch or a grpc BatchRead
+		BatchRequestLimit int `yaml:"batchRequestLimit"`
+		// ResponseCache is the cache of the responses about blocks, actions, receipts and logs
+		ResponseCache ResponseCache `yaml:"responseCache"`
+		// GraphQL is the graphql endpoint over the chain data
+		GraphQL GraphQL `yaml:"graphQL"`
+		// Auth is the authentication of the write and debug methods
+		Auth Auth `yaml:"auth"`
+		// Health is the readiness thresholds of the gateway
+		Health Health `yaml:"health"`
+		// GRPC is the options of the grpc server
+		GRPC GRPC `yaml:"grpc"`
+		// LogQuery is the limits of a log query over a range of blocks
+		LogQuery LogQuery `yaml:"logQuery"`
+		// SlowQueryThreshold is the duration above which a grpc or web3 request is logged, 0 means disabled
+		SlowQueryThreshold time.Duration `yaml:"slowQueryThreshold"`
+		// HTTP is the options of the web3, rest and graphql servers
+		HTTP HTTP `yaml:"http"`
+	}
+
+	// HTTP is the options of the http servers of the api, which let a gateway serve browsers over https without a
+	// reverse proxy
+	HTTP struct {
+		// AllowedOrigins are the origins allowed to send cross-origin requests, "*" allows any. If empty, no
+		// cross-origin http request is allowed, while the websocket connections are accepted from any origin.
+		AllowedOrigins []string `yaml:"allowedOrigins"`
+		// CORSMaxAge is how long the browsers cache the response to a preflight request
+		CORSMaxAge time.Duration `yaml:"corsMaxAge"`
+		// TLSCertFile and TLSKeyFile are the certificate and key to serve https, which are reloaded on SIGHUP. Plain
+		// http is served if they are empty.
+		TLSCertFile string `yaml:"tlsCertFile"`
+		TLSKeyFile  string `yaml:"tlsKeyFile"`
+		// EnableH2C serves HTTP/2 over plain http to the clients with prior knowledge, https always offers HTTP/2
+		EnableH2C bool `yaml:"enableH2C"`
+	}
+
+	// LogQuery is the limits of a log query over a range of blocks, a query exceeding them fails with a suggested
+	// narrower range
+	LogQuery struct {
+		// MaxBlocksScanned is the maximal number of blocks matched by the bloom filters, whose receipts are scanned
+		MaxBlocksScanned uint64 `yaml:"maxBlocksScanned"`
+		// MaxLogs is the maximal number of logs returned
+		MaxLogs uint64 `yaml:"maxLogs"`
+	}
+
+	// GRPC is the options of the grpc server. The defaults are those of grpc-go, except that the reflection service
+	// is enabled.
+	GRPC struct {
+		// EnableReflection registers the server reflection service, which lets tools like grpcurl list the methods
+		EnableReflection bool `yaml:"enableReflection"`
+		// MaxRecvMsgSize is the maximal size in bytes of a request
+		MaxRecvMsgSize int `yaml:"maxRecvMsgSize"`
+		// MaxSendMsgSize is the maximal size in bytes of a response, raise MaxRecvMsgSize of the clients along with it
+		MaxSendMsgSize int `yaml:"maxSendMsgSize"`
+		// MaxConcurrentStreams is the maximal number of concurrent streams of a connection, 0 means unlimited
+		MaxConcurrentStreams uint32 `yaml:"maxConcurrentStreams"`
+		// MaxConnectionIdle closes a connection idle for longer, 0 means never
+		MaxConnectionIdle time.Duration `yaml:"maxConnectionIdle"`
+		// MaxConnectionAge closes a connection older than it, after a grace period of MaxConnectionAgeGrace, 0 means never
+		MaxConnectionAge      time.Duration `yaml:"maxConnectionAge"`
+		MaxConnectionAgeGrace time.Duration `yaml:"maxConnectionAgeGrace"`
+		// KeepaliveTime is the idle time after which the server pings the client
+		KeepaliveTime time.Duration `yaml:"keepaliveTime"`
+		// KeepaliveTimeout is the time to wait for the ping ack before closing the connection
+		KeepaliveTimeout time.Duration `yaml:"keepaliveTimeout"`
+		// KeepaliveMinTime is the minimal interval of the pings of a client, a client pinging more often is disconnected
+		KeepaliveMinTime time.Duration `yaml:"keepaliveMinTime"`
+		// PermitWithoutStream allows the client pings when there is no active stream
+		PermitWithoutStream bool `yaml:"permitWithoutStream"`
+	}
+
+	// Health is the thresholds beyond which the gateway reports not ready, so that load balancers stop routing to it
+	Health struct {
+		// MaxSyncLag is the maximal number of blocks the tip may fall behind the best known peer height
+		MaxSyncLag uint64 `yaml:"maxSyncLag"`
+		// MaxIndexerLag is the maximal number of blocks an indexer may fall behind the tip
+		MaxIndexerLag uint64 `yaml:"maxIndexerLag"`
+		// MinPeers is the minimal number of connected peers, 0 means the peer count is not checked
+		MinPeers int `yaml:"minPeers"`
+	}
+
+	// Auth restricts the write methods, and the expensive debug methods, of the api to the clients carrying one of the
+	// api keys, or a json web token signed by the configured key. Authentication is disabled if neither is configured.
+	Auth struct {
+		APIKeys []string `yaml:"apiKeys"`
+		// JWTIssuer is the issuer of the accepted tokens, empty means any issuer
+		JWTIssuer string `yaml:"jwtIssuer"`
+		// JWTSecret is the HMAC key of the accepted tokens
+		JWTSecret string `yaml:"jwtSecret"`
+		// JWTPublicKeyFile is the PEM file of the RSA or ECDSA public key of the accepted tokens
+		JWTPublicKeyFile string `yaml:"jwtPublicKeyFile"`
+	}
+
+	// GraphQL is the config of the graphql endpoint. Each block, action, receipt and log resolved by a query adds one
+	// to its complexity.
+	GraphQL struct {
+		// Port is the port of the graphql endpoint, 0 means disabled
+		Port          int `yaml:"port"`
+		MaxDepth      int `yaml:"maxDepth"`
+		MaxComplexity int `yaml:"maxComplexity"`
+	}
+
+	// RateLimit is the token bucket rate limit of the api. Each request is charged to the bucket of its client ip, and
+	// to the bucket of its api key if it carries one. Expensive methods cost more tokens. A zero rate disables the limit.
+	RateLimit struct {
+		RequestsPerSecond       float64 `yaml:"requestsPerSecond"`
+		Burst                   int     `yaml:"burst"`
+		APIKeyRequestsPerSecond float64 `yaml:"apiKeyRequestsPerSecond"`
+		APIKeyBurst             int     `yaml:"apiKeyBurst"`
+		// MaxClients is the number of client ips, and of api keys, whose buckets are kept
+		MaxClients int `yaml:"maxClients"`
 	}
 
 	// GasStation is the gas station config
@@ -338,6 +844,22 @@ type (
 		SuggestBlockWindow int    `yaml:"suggestBlockWindow"`
 		DefaultGas         uint64 `yaml:"defaultGas"`
 		Percentile         int    `yaml:"Percentile"`
+		// FeeHistoryWindow is the maximum number of blocks in a fee history, and the number of blocks whose fee stats
+		// are cached
+		FeeHistoryWindow int `yaml:"feeHistoryWindow"`
+	}
+
+	// ResponseCache is the cache of the api responses which no longer change
+	ResponseCache struct {
+		// Backend is either memory or redis, the cache is disabled if it is empty
+		Backend string `yaml:"backend"`
+		// Size is the number of responses kept by the memory backend
+		Size      int           `yaml:"size"`
+		RedisAddr string        `yaml:"redisAddr"`
+		RedisTTL  time.Duration `yaml:"redisTTL"`
+		// TipDepth is the number of the most recent blocks, responses about which are not cached, and no less than the
+		// max reorg depth of the chain
+		TipDepth uint64 `yaml:"tipDepth"`
 	}
 
 	// System is the system config
@@ -351,6 +873,16 @@ type (
 		HTTPStatsPort         int           `yaml:"httpStatsPort"`
 		StartSubChainInterval time.Duration `yaml:"startSubChainInterval"`
 		SystemLogDBPath       string        `yaml:"systemLogDBPath"`
+		Standby               Standby       `yaml:"standby"`
+	}
+
+	// Standby is the config of a standby node, which shares the producer identity with the primary node, and takes
+	// over the block production once the primary fails the heartbeats for the failure window
+	Standby struct {
+		// PrimaryEndpoint is the URL of the /ha admin endpoint of the primary node, empty to disable the failover
+		PrimaryEndpoint   string        `yaml:"primaryEndpoint"`
+		HeartbeatInterval time.Duration `yaml:"heartbeatInterval"`
+		FailureWindow     time.Duration `yaml:"failureWindow"`
 	}
 
 	// ActPool is the actpool config
@@ -365,13 +897,64 @@ type (
 		ActionExpiry time.Duration `yaml:"actionExpiry"`
 		// MinGasPriceStr defines the minimal gas price the delegate will accept for an action
 		MinGasPriceStr string `yaml:"minGasPrice"`
+		// ReplaceGasPriceBump is the percentage by which the gas price of an action must exceed the gas price of the
+		// pending action of the same nonce to replace it
+		ReplaceGasPriceBump uint64 `yaml:"replaceGasPriceBump"`
 		// BlackList lists the account address that are banned from initiating actions
 		BlackList []string `yaml:"blackList"`
+		// JournalPath is the file the actions in pool are persisted to, to be reloaded after a restart. Empty
+		// disables the journal.
+		JournalPath string `yaml:"journalPath"`
+		// JournalRotateInterval is the interval the journal is rewritten at, to drop the actions no longer in pool
+		JournalRotateInterval time.Duration `yaml:"journalRotateInterval"`
+		// LocalAccounts lists the account addresses whose actions bypass the minimal gas price and the eviction when
+		// the pool is full, and are taken first into the proposed blocks
+		LocalAccounts []string `yaml:"localAccounts"`
+		// GasPriceSteps raise the minimal gas price as the utilization of the pool crosses their thresholds, in
+		// ascending order of utilization
+		GasPriceSteps []GasPriceStep `yaml:"gasPriceSteps"`
+		// GasPriceDecay is the percentage by which a raised minimal gas price falls back every block, once the
+		// utilization of the pool drops. 0 lets it fall back at once.
+		GasPriceDecay uint64 `yaml:"gasPriceDecay"`
+		// MaxNumActsPerSource is the maximum number of actions in pool received from a peer, or from an api client ip.
+		// 0 means unlimited.
+		MaxNumActsPerSource uint64 `yaml:"maxNumActsPerSource"`
+		// AdmissionWindow is the sliding window the admission rates of the senders and the sources are limited in
+		AdmissionWindow time.Duration `yaml:"admissionWindow"`
+		// MaxAdmissionsPerSender is the maximum number of actions of a sender admitted in the admission window. 0 means
+		// unlimited.
+		MaxAdmissionsPerSender uint64 `yaml:"maxAdmissionsPerSender"`
+		// MaxAdmissionsPerSource is the maximum number of actions of a source admitted in the admission window. 0 means
+		// unlimited.
+		MaxAdmissionsPerSource uint64 `yaml:"maxAdmissionsPerSource"`
+		// DenylistPath is the yaml file of the addresses, and the contracts, whose actions are rejected by the actpool
+		// and the api. It is reloaded on SIGHUP and once modified. Empty disables the denylist.
+		DenylistPath string `yaml:"denylistPath"`
+		// DenylistReloadInterval is the interval the denylist file is checked for modification at
+		DenylistReloadInterval time.Duration `yaml:"denylistReloadInterval"`
+		// MaxNumOrphans is the maximum number of the actions kept aside because their nonces are too far ahead of
+		// their senders', until the nonces of the senders catch up. 0 disables the orphans.
+		MaxNumOrphans uint64 `yaml:"maxNumOrphans"`
+		// MaxNumOrphansPerAcct is the maximum number of orphans of a sender
+		MaxNumOrphansPerAcct uint64 `yaml:"maxNumOrphansPerAcct"`
+		// MaxOrphanNonceGap is how far the nonce of an orphan can be beyond the nonces an account queue holds
+		MaxOrphanNonceGap uint64 `yaml:"maxOrphanNonceGap"`
+	}
+
+	// GasPriceStep is a threshold of the actpool utilization, above which the minimal gas price is raised
+	GasPriceStep struct {
+		// Utilization is the percentage of the pool capacity, in actions or in gas, used by the actions in pool
+		Utilization uint64 `yaml:"utilization"`
+		// Multiplier is the percentage of the configured minimal gas price the actions must pay above the threshold
+		Multiplier uint64 `yaml:"multiplier"`
 	}
 
 	// DB is the config for database
 	DB struct {
 		DbPath string `yaml:"dbPath"`
+		// Backend is the backend of the index dbs of the gateway, either bolt, badger or pebble. Bolt serializes the
+		// writes, while badger and pebble take the concurrent writes of the indexers
+		Backend string `yaml:"backend"`
 		// NumRetries is the number of retries
 		NumRetries uint8 `yaml:"numRetries"`
 		// MaxCacheSize is the max number of blocks that will be put into an LRU cache. 0 means disabled
@@ -380,7 +963,7 @@ type (
 		BlockStoreBatchSize int `yaml:"blockStoreBatchSize"`
 		// V2BlocksToSplitDB is the accumulated number of blocks to split a new file after v1.1.2
 		V2BlocksToSplitDB uint64 `yaml:"v2BlocksToSplitDB"`
-		// Compressor is the compression used on block data, used by new DB file after v1.1.2
+		// Compressor is the compression used on block data, used by new DB file after v1.1.2, and by new records of V3 DB file
 		Compressor string `yaml:"compressor"`
 		// CompressLegacy enables gzip compression on block data, used by legacy DB file before v1.1.2
 		CompressLegacy bool `yaml:"compressLegacy"`
@@ -390,6 +973,102 @@ type (
 		SplitDBHeight uint64 `yaml:"splitDBHeight"`
 		// HistoryStateRetention is the number of blocks account/contract state will be retained
 		HistoryStateRetention uint64 `yaml:"historyStateRetention"`
+		// BlockRetention is the number of latest blocks whose bodies, receipts and transaction logs will be retained,
+		// the headers and footers of older blocks are always kept. 0 means all blocks are retained
+		BlockRetention uint64 `yaml:"blockRetention"`
+		// ColdStorage moves the old chain db files to an object storage
+		ColdStorage ColdStorage `yaml:"coldStorage"`
+		// Receipt is the config for storing the receipts apart from the chain db
+		Receipt Receipt `yaml:"receipt"`
+		// Integrity is the config for verifying and compacting the sealed chain db files in the background
+		Integrity Integrity `yaml:"integrity"`
+		// CommitJournalPath is the path of the write-ahead journal of the block commit, which completes the commit of
+		// the chain db and the indexers after a crash. Empty means disabled
+		CommitJournalPath string `yaml:"commitJournalPath"`
+		// SlowOpThreshold is the duration above which a Get, Put, Delete or WriteBatch of a db is logged, 0 means
+		// disabled
+		SlowOpThreshold time.Duration `yaml:"slowOpThreshold"`
+		// EncryptedNamespaces are the namespaces of the index dbs, whose values are encrypted at rest by AES-GCM
+		EncryptedNamespaces []string `yaml:"encryptedNamespaces"`
+		// EncryptionKeyEnv is the environment variable of the hex encoded 16, 24 or 32 bytes AES key of the encrypted
+		// namespaces
+		EncryptionKeyEnv string `yaml:"encryptionKeyEnv"`
+		// Flush is the config of the write batches of the state db
+		Flush FlushBatch `yaml:"flush"`
+	}
+
+	// FlushBatch is the config of the write batches, which coalesces the small commits of the blocks under load, and
+	// splits the oversized ones
+	FlushBatch struct {
+		// MinBatchBytes is the size below which the commits arriving within MaxDelay of the previous one are coalesced
+		// into a larger write batch, 0 means disabled
+		MinBatchBytes uint64 `yaml:"minBatchBytes"`
+		// MaxBatchBytes is the size above which the coalesced commits are written, and the writes of the state sync are
+		// split, 0 means unlimited
+		MaxBatchBytes uint64 `yaml:"maxBatchBytes"`
+		// MaxDelay is the longest time the coalesced commits wait to be written
+		MaxDelay time.Duration `yaml:"maxDelay"`
+	}
+
+	// Integrity is the config for the background task, which verifies the sealed chain db files, and rewrites the
+	// fragmented ones
+	Integrity struct {
+		// Interval is the interval between two rounds of verification, 0 means disabled
+		Interval time.Duration `yaml:"interval"`
+		// CompactRatio is the ratio of the file size to the size of its records, above which a verified file is
+		// rewritten. 0 means the files are not rewritten
+		CompactRatio float64 `yaml:"compactRatio"`
+	}
+
+	// Receipt is the config for storing the receipts in their own db file, with a retention independent of the blocks.
+	// A gateway may keep all receipts while pruning the block bodies, and a delegate may keep all blocks while pruning
+	// the receipts.
+	Receipt struct {
+		// DbPath is the path of the receipt db file, empty means the receipts are stored in the chain db
+		DbPath string `yaml:"dbPath"`
+		// Retention is the number of latest blocks whose receipts will be retained. 0 means all receipts are retained
+		Retention uint64 `yaml:"retention"`
+	}
+
+	// ColdStorage is the config for moving the sealed chain db files, which are no longer written, to an object storage
+	// and fetching them back on demand
+	ColdStorage struct {
+		// URL is the object storage, file:///dir or s3://bucket/prefix?endpoint=host&region=region, empty means the
+		// files are kept locally
+		URL string `yaml:"url"`
+		// Depth is the number of blocks a file is sealed below the tip, before it is moved
+		Depth uint64 `yaml:"depth"`
+		// CacheDir is the directory of the files fetched back
+		CacheDir string `yaml:"cacheDir"`
+		// CacheSize is the number of files fetched back kept in the cache directory
+		CacheSize int `yaml:"cacheSize"`
+	}
+
+	// LightClient is the config struct of the light client mode, which keeps only the headers and the delegate sets
+	// verified against the proofs served by a full node
+	LightClient struct {
+		// Endpoint is the rest endpoint of the full node serving the proofs, the node runs as a light client if it's set
+		Endpoint string `yaml:"endpoint"`
+		// TrustedEpoch and TrustedDelegates are the delegate set trusted by the light client, from which the delegate
+		// sets of the following epochs are verified
+		TrustedEpoch     uint64   `yaml:"trustedEpoch"`
+		TrustedDelegates []string `yaml:"trustedDelegates"`
+		// MaxHeaders is the max number of verified headers kept, the lowest ones are dropped beyond it
+		MaxHeaders     int           `yaml:"maxHeaders"`
+		SyncInterval   time.Duration `yaml:"syncInterval"`
+		RequestTimeout time.Duration `yaml:"requestTimeout"`
+		// Port is the http port answering the queries with the verified proofs
+		Port int `yaml:"port"`
+	}
+
+	// Snapshot is the config for exporting state snapshots, which bootstrap new nodes
+	Snapshot struct {
+		// Dir is the directory of the exported snapshots, empty means the snapshots are not exported
+		Dir string `yaml:"dir"`
+		// Interval is the number of epochs between two snapshots
+		Interval uint64 `yaml:"interval"`
+		// Keep is the number of latest snapshots kept in the directory
+		Keep int `yaml:"keep"`
 	}
 
 	// Indexer is the config for indexer
@@ -404,20 +1083,23 @@ type (
 
 	// Config is the root config struct, each package's config should be put as its sub struct
 	Config struct {
-		Plugins    map[int]interface{}         `ymal:"plugins"`
-		Network    Network                     `yaml:"network"`
-		Chain      Chain                       `yaml:"chain"`
-		ActPool    ActPool                     `yaml:"actPool"`
-		Consensus  Consensus                   `yaml:"consensus"`
-		BlockSync  BlockSync                   `yaml:"blockSync"`
-		Dispatcher Dispatcher                  `yaml:"dispatcher"`
-		API        API                         `yaml:"api"`
-		System     System                      `yaml:"system"`
-		DB         DB                          `yaml:"db"`
-		Indexer    Indexer                     `yaml:"indexer"`
-		Log        log.GlobalConfig            `yaml:"log"`
-		SubLogs    map[string]log.GlobalConfig `yaml:"subLogs"`
-		Genesis    genesis.Genesis             `yaml:"genesis"`
+		Plugins     map[int]interface{}         `ymal:"plugins"`
+		Network     Network                     `yaml:"network"`
+		Chain       Chain                       `yaml:"chain"`
+		ActPool     ActPool                     `yaml:"actPool"`
+		Consensus   Consensus                   `yaml:"consensus"`
+		BlockSync   BlockSync                   `yaml:"blockSync"`
+		ActionSync  ActionSync                  `yaml:"actionSync"`
+		Dispatcher  Dispatcher                  `yaml:"dispatcher"`
+		API         API                         `yaml:"api"`
+		System      System                      `yaml:"system"`
+		DB          DB                          `yaml:"db"`
+		Indexer     Indexer                     `yaml:"indexer"`
+		Snapshot    Snapshot                    `yaml:"snapshot"`
+		LightClient LightClient                 `yaml:"lightClient"`
+		Log         log.GlobalConfig            `yaml:"log"`
+		SubLogs     map[string]log.GlobalConfig `yaml:"subLogs"`
+		Genesis     genesis.Genesis             `yaml:"genesis"`
 	}
 
 	// Validate is the interface of validating the config
@@ -515,8 +1197,7 @@ func NewSub(validates ...Validate) (Config, error) {
 
 // ProducerAddress returns the configured producer address derived from key
 func (cfg Config) ProducerAddress() address.Address {
-	sk := cfg.ProducerPrivateKey()
-	addr, err := address.FromBytes(sk.PublicKey().Hash())
+	addr, err := address.FromBytes(cfg.ProducerPublicKey().Hash())
 	if err != nil {
 		log.L().Panic(
 			"Error when constructing producer address",
@@ -526,6 +1207,21 @@ func (cfg Config) ProducerAddress() address.Address {
 	return addr
 }
 
+// ProducerPublicKey returns the public key of the producer key, which is kept by the remote signer if it is configured
+func (cfg Config) ProducerPublicKey() crypto.PublicKey {
+	if cfg.Chain.RemoteSigner.Endpoint == "" {
+		return cfg.ProducerPrivateKey().PublicKey()
+	}
+	pk, err := crypto.HexStringToPublicKey(cfg.Chain.RemoteSigner.PublicKey)
+	if err != nil {
+		log.L().Panic(
+			"Error when decoding public key of remote signer",
+			zap.Error(err),
+		)
+	}
+	return pk
+}
+
 // ProducerPrivateKey returns the configured private key
 func (cfg Config) ProducerPrivateKey() crypto.PrivateKey {
 	sk, err := crypto.HexStringToPrivateKey(cfg.Chain.ProducerPrivKey)
@@ -542,6 +1238,21 @@ func (cfg Config) ProducerPrivateKey() crypto.PrivateKey {
 	return sk
 }
 
+// BLSPrivateKey returns the configured BLS private key, or nil if it is not configured
+func (cfg Config) BLSPrivateKey() *bls.PrivateKey {
+	if cfg.Chain.BLSPrivKey == "" {
+		return nil
+	}
+	sk, err := bls.HexStringToPrivateKey(cfg.Chain.BLSPrivKey)
+	if err != nil {
+		log.L().Panic(
+			"Error when decoding BLS private key",
+			zap.Error(err),
+		)
+	}
+	return sk
+}
+
 func (cfg Config) whitelistSignatureScheme(sk crypto.PrivateKey) bool {
 	var sigScheme string
 
@@ -552,6 +1263,22 @@ func (cfg Config) whitelistSignatureScheme(sk crypto.PrivateKey) bool {
 		sigScheme = SigP256sm2
 	}
 
+	return cfg.whitelisted(sigScheme)
+}
+
+func (cfg Config) whitelistPublicKeyScheme(pk crypto.PublicKey) bool {
+	var sigScheme string
+
+	switch pk.EcdsaPublicKey().(type) {
+	case *ecdsa.PublicKey:
+		sigScheme = SigP256k1
+	case *crypto.P256sm2PubKey:
+		sigScheme = SigP256sm2
+	}
+	return cfg.whitelisted(sigScheme)
+}
+
+func (cfg Config) whitelisted(sigScheme string) bool {
 	if sigScheme == "" {
 		return false
 	}
@@ -594,6 +1321,65 @@ func ValidateDispatcher(cfg Config) error {
 	return nil
 }
 
+// ValidateBlockSync validates the block sync configs
+func ValidateBlockSync(cfg Config) error {
+	if cfg.BlockSync.MaxSegmentsPerPeer <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "max segments per peer should be greater than 0")
+	}
+	if cfg.BlockSync.SegmentTTL <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "block sync segment ttl should be greater than 0")
+	}
+	if cfg.BlockSync.PeerDemotion <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "block sync peer demotion should be greater than 0")
+	}
+	if rate := cfg.BlockSync.MaxPeerFailureRate; rate <= 0 || rate >= 1 {
+		return errors.Wrap(ErrInvalidCfg, "max peer failure rate should be in (0, 1)")
+	}
+	if cfg.BlockSync.HeaderBatchSize == 0 {
+		return errors.Wrap(ErrInvalidCfg, "header batch size should be greater than 0")
+	}
+	if cfg.BlockSync.StallIntervals < 0 {
+		return errors.Wrap(ErrInvalidCfg, "block sync stall intervals should not be negative")
+	}
+	quota := cfg.BlockSync.ServeQuota
+	if quota.MaxBlocksPerRequest == 0 {
+		return errors.Wrap(ErrInvalidCfg, "max blocks per request should be greater than 0")
+	}
+	if quota.BlocksPerSecond > 0 && quota.BlockBurst <= 0 || quota.BytesPerSecond > 0 && quota.ByteBurst <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "serve quota burst should be greater than 0")
+	}
+	if (quota.BlocksPerSecond > 0 || quota.BytesPerSecond > 0) && quota.MaxPeers <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "serve quota max peers should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateLightClient validates the light client configs
+func ValidateLightClient(cfg Config) error {
+	lc := cfg.LightClient
+	if lc.Endpoint == "" {
+		return nil
+	}
+	if lc.TrustedEpoch == 0 || len(lc.TrustedDelegates) == 0 {
+		return errors.Wrap(ErrInvalidCfg, "light client needs the delegate set of a trusted epoch")
+	}
+	if lc.SyncInterval <= 0 || lc.RequestTimeout <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "light client sync interval and request timeout should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateActionSync validates the action sync configs
+func ValidateActionSync(cfg Config) error {
+	if cfg.ActionSync.AnnounceInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "action announce interval should be greater than 0")
+	}
+	if cfg.ActionSync.MaxHashesPerMsg <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "max hashes per action sync message should be greater than 0")
+	}
+	return nil
+}
+
 // ValidateRollDPoS validates the roll-DPoS configs
 func ValidateRollDPoS(cfg Config) error {
 	if cfg.Consensus.Scheme != RollDPoSScheme {
@@ -604,11 +1390,25 @@ func ValidateRollDPoS(cfg Config) error {
 	if fsm.EventChanSize <= 0 {
 		return errors.Wrap(ErrInvalidCfg, "roll-DPoS event chan size should be greater than 0")
 	}
+	if adaptive := fsm.Adaptive; adaptive.Enabled {
+		if adaptive.Window == 0 {
+			return errors.Wrap(ErrInvalidCfg, "roll-DPoS adaptive timing window should be greater than 0")
+		}
+		if adaptive.Margin < 1 {
+			return errors.Wrap(ErrInvalidCfg, "roll-DPoS adaptive timing margin should be no less than 1")
+		}
+		if adaptive.MinRatio <= 0 || adaptive.MinRatio > 1 || adaptive.MaxRatio < 1 {
+			return errors.Wrap(ErrInvalidCfg, "roll-DPoS adaptive timing ratios should satisfy 0 < minRatio <= 1 <= maxRatio")
+		}
+	}
 	return nil
 }
 
 // ValidateArchiveMode validates the state factory setting
 func ValidateArchiveMode(cfg Config) error {
+	if cfg.Chain.EnableArchiveMode && cfg.Chain.StateRetention > 0 {
+		return errors.Wrap(ErrInvalidCfg, "state retention is incompatible with archive mode")
+	}
 	if !cfg.Chain.EnableArchiveMode || !cfg.Chain.EnableTrielessStateDB {
 		return nil
 	}
@@ -616,11 +1416,198 @@ func ValidateArchiveMode(cfg Config) error {
 	return errors.Wrap(ErrInvalidCfg, "Archive mode is incompatible with trieless state DB")
 }
 
+// ValidateBlockRetention validates the block pruning setting
+func ValidateBlockRetention(cfg Config) error {
+	if cfg.DB.BlockRetention == 0 {
+		return nil
+	}
+	if cfg.Chain.EnableArchiveMode {
+		return errors.Wrap(ErrInvalidCfg, "archive mode is incompatible with block pruning")
+	}
+	if cfg.DB.BlockRetention < uint64(cfg.DB.BlockStoreBatchSize) {
+		return errors.Wrap(ErrInvalidCfg, "block retention should not be less than block store batch size")
+	}
+	return nil
+}
+
+// ValidateColdStorage validates the cold storage setting of chain db
+func ValidateColdStorage(cfg Config) error {
+	c := cfg.DB.ColdStorage
+	if c.URL == "" {
+		return nil
+	}
+	if cfg.DB.V2BlocksToSplitDB == 0 {
+		return errors.Wrap(ErrInvalidCfg, "cold storage needs the chain db split into files")
+	}
+	if cfg.DB.BlockRetention > 0 {
+		return errors.Wrap(ErrInvalidCfg, "cold storage is incompatible with block pruning")
+	}
+	if c.CacheDir == "" || c.CacheSize <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "cold storage needs a cache directory and a positive cache size")
+	}
+	return nil
+}
+
+// ValidateReceiptRetention validates the receipt pruning setting
+func ValidateReceiptRetention(cfg Config) error {
+	if cfg.DB.Receipt.Retention == 0 {
+		return nil
+	}
+	if cfg.DB.Receipt.DbPath == "" {
+		return errors.Wrap(ErrInvalidCfg, "receipt pruning needs a receipt db apart from the chain db")
+	}
+	if cfg.Chain.EnableArchiveMode {
+		return errors.Wrap(ErrInvalidCfg, "archive mode is incompatible with receipt pruning")
+	}
+	return nil
+}
+
+// ValidateIntegrity validates the verification setting of chain db
+func ValidateIntegrity(cfg Config) error {
+	c := cfg.DB.Integrity
+	if c.Interval < 0 {
+		return errors.Wrap(ErrInvalidCfg, "integrity check interval should not be negative")
+	}
+	if c.CompactRatio != 0 && c.CompactRatio < 1 {
+		return errors.Wrap(ErrInvalidCfg, "compact ratio should not be less than 1")
+	}
+	return nil
+}
+
+// ValidateDB validates the db configs
+func ValidateDB(cfg Config) error {
+	switch cfg.DB.Backend {
+	case "", BoltBackend, BadgerBackend, PebbleBackend:
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unsupported db backend %s", cfg.DB.Backend)
+	}
+	if len(cfg.DB.EncryptedNamespaces) > 0 && cfg.DB.EncryptionKeyEnv == "" {
+		return errors.Wrap(ErrInvalidCfg, "encryption key env of the encrypted namespaces is not set")
+	}
+	if c := cfg.DB.Flush; c.MinBatchBytes > 0 {
+		if c.MaxDelay <= 0 {
+			return errors.Wrap(ErrInvalidCfg, "max delay of the coalesced commits should be positive")
+		}
+		if c.MaxBatchBytes > 0 && c.MinBatchBytes > c.MaxBatchBytes {
+			return errors.Wrap(ErrInvalidCfg, "min batch bytes should not be greater than max batch bytes")
+		}
+	}
+	return nil
+}
+
+// ValidateNetwork validates the static peers, the DNS seeds, the peer maintenance, the compression and the ban setting
+func ValidateNetwork(cfg Config) error {
+	for _, s := range cfg.Network.StaticPeers {
+		addr, err := multiaddr.NewMultiaddr(s)
+		if err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid static peer %s", s)
+		}
+		if _, err := peerstore.InfoFromP2pAddr(addr); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "static peer %s has no peer id", s)
+		}
+	}
+	for _, s := range cfg.Network.DNSSeeds {
+		if !strings.HasPrefix(s, "tree://") {
+			if s == "" || strings.ContainsAny(s, "/@ ") {
+				return errors.Wrapf(ErrInvalidCfg, "invalid dns seed %s", s)
+			}
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(s, "tree://"), "@", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return errors.Wrapf(ErrInvalidCfg, "invalid dns seed %s", s)
+		}
+		if _, err := crypto.HexStringToPublicKey(parts[0]); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid public key of dns seed %s", s)
+		}
+	}
+	if cfg.Network.PeerMaintainInterval < 0 {
+		return errors.Wrap(ErrInvalidCfg, "peer maintain interval should not be negative")
+	}
+	switch cfg.Network.Compression {
+	case "", compress.Snappy, compress.Zstd:
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unsupported p2p compression %s", cfg.Network.Compression)
+	}
+	if cfg.Network.BanThreshold < 0 || cfg.Network.PeerMessageRate < 0 {
+		return errors.Wrap(ErrInvalidCfg, "ban threshold and peer message rate should not be negative")
+	}
+	if cfg.Network.BanThreshold > 0 && (cfg.Network.BanDuration <= 0 || cfg.Network.MaxBanDuration < cfg.Network.BanDuration) {
+		return errors.Wrap(ErrInvalidCfg, "ban duration should be greater than 0 and not greater than max ban duration")
+	}
+	return nil
+}
+
+// ValidateReorg validates the deep reorg setting
+func ValidateReorg(cfg Config) error {
+	if cfg.Chain.MaxReorgDepth == 0 {
+		return nil
+	}
+	if cfg.Chain.EnableAsyncIndexWrite {
+		return errors.Wrap(ErrInvalidCfg, "deep reorg needs the index written along with the blocks")
+	}
+	if cfg.DB.BlockRetention > 0 && cfg.Chain.MaxReorgDepth >= cfg.DB.BlockRetention {
+		return errors.Wrap(ErrInvalidCfg, "max reorg depth should be less than block retention")
+	}
+	if _, ok := cfg.Plugins[GatewayPlugin]; ok {
+		return errors.Wrap(ErrInvalidCfg, "deep reorg does not support the gateway indexers")
+	}
+	// the responses about the blocks which a reorg could revert must not be cached
+	if cfg.API.ResponseCache.Backend != "" && cfg.API.ResponseCache.TipDepth < cfg.Chain.MaxReorgDepth {
+		return errors.Wrap(ErrInvalidCfg, "response cache tip depth should be no less than max reorg depth")
+	}
+	return nil
+}
+
+// ValidateCheckpoint validates the trusted checkpoint setting
+func ValidateCheckpoint(cfg Config) error {
+	c := cfg.BlockSync.Checkpoint
+	if c.Height == 0 {
+		return nil
+	}
+	if h, err := hex.DecodeString(c.Hash); err != nil || len(h) != 32 {
+		return errors.Wrap(ErrInvalidCfg, "checkpoint hash should be a hex encoded 32-byte hash")
+	}
+	if c.Snapshot == "" {
+		return errors.Wrap(ErrInvalidCfg, "checkpoint needs the url of its state snapshot")
+	}
+	if _, ok := cfg.Plugins[GatewayPlugin]; ok {
+		return errors.Wrap(ErrInvalidCfg, "checkpoint sync does not support the gateway indexers")
+	}
+	return nil
+}
+
+// ValidateSnapshot validates the snapshot export setting
+func ValidateSnapshot(cfg Config) error {
+	if cfg.Snapshot.Dir == "" {
+		return nil
+	}
+	if cfg.Snapshot.Interval == 0 || cfg.Snapshot.Keep <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "snapshot interval and the number of snapshots kept should be positive")
+	}
+	if cfg.Consensus.Scheme != RollDPoSScheme {
+		return errors.Wrap(ErrInvalidCfg, "snapshots are exported at the epoch boundaries of roll-DPoS")
+	}
+	if cfg.Chain.EnableTrielessStateDB && cfg.Chain.EnableStateDBCaching {
+		return errors.Wrap(ErrInvalidCfg, "snapshot export is incompatible with state DB caching")
+	}
+	return nil
+}
+
 // ValidateAPI validates the api configs
 func ValidateAPI(cfg Config) error {
 	if cfg.API.TpsWindow <= 0 {
 		return errors.Wrap(ErrInvalidCfg, "tps window is not a positive integer when the api is enabled")
 	}
+	if cfg.API.GRPC.MaxRecvMsgSize <= 0 || cfg.API.GRPC.MaxSendMsgSize <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "grpc max message sizes should be positive")
+	}
+	if cfg.API.LogQuery.MaxBlocksScanned == 0 || cfg.API.LogQuery.MaxLogs == 0 {
+		return errors.Wrap(ErrInvalidCfg, "log query limits should be positive")
+	}
+	if (cfg.API.HTTP.TLSCertFile == "") != (cfg.API.HTTP.TLSKeyFile == "") {
+		return errors.Wrap(ErrInvalidCfg, "tls certificate and key should be set together")
+	}
 	return nil
 }
 
@@ -640,6 +1627,41 @@ func ValidateActPool(cfg Config) error {
 			"maximum number of actions per pool cannot be less than maximum number of actions per account",
 		)
 	}
+	for _, local := range cfg.ActPool.LocalAccounts {
+		if _, err := address.FromString(local); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid local account %s", local)
+		}
+	}
+	for i, step := range cfg.ActPool.GasPriceSteps {
+		if step.Utilization == 0 || step.Utilization > 100 || step.Multiplier < 100 {
+			return errors.Wrapf(ErrInvalidCfg, "invalid gas price step %+v", step)
+		}
+		if i > 0 && step.Utilization <= cfg.ActPool.GasPriceSteps[i-1].Utilization {
+			return errors.Wrap(ErrInvalidCfg, "gas price steps should be in ascending order of utilization")
+		}
+	}
+	if cfg.ActPool.GasPriceDecay > 100 {
+		return errors.Wrap(ErrInvalidCfg, "gas price decay cannot be more than 100 percent")
+	}
+	if (cfg.ActPool.MaxAdmissionsPerSender > 0 || cfg.ActPool.MaxAdmissionsPerSource > 0) && cfg.ActPool.AdmissionWindow <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "admission window should be positive to limit admissions")
+	}
+	if cfg.ActPool.MaxNumOrphans > 0 && cfg.ActPool.MaxNumOrphansPerAcct == 0 {
+		return errors.Wrap(ErrInvalidCfg, "maximum number of orphans per account cannot be zero")
+	}
+	return nil
+}
+
+// ValidateBlockComposition validates the block composition limits
+func ValidateBlockComposition(cfg Config) error {
+	composition := cfg.Chain.BlockComposition
+	if composition.MaxExecutions < 0 {
+		return errors.Wrap(ErrInvalidCfg, "maximum number of executions per block cannot be negative")
+	}
+	// the reserved gas has to fit in every block, including those under the lowest limit of the schedule
+	if composition.TransferReservedGas >= cfg.Genesis.MinBlockGasLimit() {
+		return errors.Wrap(ErrInvalidCfg, "transfer reserved gas should be less than block gas limit")
+	}
 	return nil
 }
 
@@ -669,5 +1691,111 @@ func ValidateForkHeights(cfg Config) error {
 	return nil
 }
 
+// ValidateRemoteSigner validates the remote signer config
+func ValidateRemoteSigner(cfg Config) error {
+	rs := cfg.Chain.RemoteSigner
+	if rs.Endpoint == "" {
+		return nil
+	}
+	if !strings.HasPrefix(rs.Endpoint, "https://") {
+		return errors.Wrap(ErrInvalidCfg, "remote signer endpoint should be an https URL")
+	}
+	pk, err := crypto.HexStringToPublicKey(rs.PublicKey)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidCfg, "invalid public key of remote signer: %v", err)
+	}
+	if !cfg.whitelistPublicKeyScheme(pk) {
+		return errors.Wrap(ErrInvalidCfg, "the signature scheme of the remote signer is not whitelisted")
+	}
+	if (rs.ClientCertPath == "") != (rs.ClientKeyPath == "") {
+		return errors.Wrap(ErrInvalidCfg, "client certificate and key of remote signer should be set together")
+	}
+	for _, t := range rs.AllowedMessageTypes {
+		switch t {
+		case "block", "endorsement", "action":
+		default:
+			return errors.Wrapf(ErrInvalidCfg, "unknown message type %s of remote signer", t)
+		}
+	}
+	if rs.Timeout <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "remote signer timeout should be positive")
+	}
+	return nil
+}
+
 // DoNotValidate validates the given config
 func DoNotValidate(cfg Config) error { return nil }
+
+// ValidateStandby validates the standby config
+func ValidateStandby(cfg Config) error {
+	standby := cfg.System.Standby
+	if standby.PrimaryEndpoint == "" {
+		return nil
+	}
+	if cfg.System.Active {
+		return errors.Wrap(ErrInvalidCfg, "standby node should start in stand-by mode")
+	}
+	if cfg.Consensus.Scheme != RollDPoSScheme || cfg.Consensus.RollDPoS.ConsensusDBPath == "" {
+		return errors.Wrap(ErrInvalidCfg, "standby node requires the roll-DPoS consensus DB to log the signed messages")
+	}
+	if standby.HeartbeatInterval <= 0 || standby.FailureWindow < standby.HeartbeatInterval {
+		return errors.Wrap(ErrInvalidCfg, "standby failure window should be no less than the heartbeat interval")
+	}
+	return nil
+}
+
+// ValidateProbationNotifier validates the probation notifier config
+func ValidateProbationNotifier(cfg Config) error {
+	pn := cfg.Chain.ProbationNotifier
+	if !pn.Enabled {
+		return nil
+	}
+	if cfg.Consensus.Scheme != RollDPoSScheme {
+		return errors.Wrap(ErrInvalidCfg, "probation notifier requires roll-DPoS consensus scheme")
+	}
+	if pn.WebhookURL != "" {
+		if !strings.HasPrefix(pn.WebhookURL, "http://") && !strings.HasPrefix(pn.WebhookURL, "https://") {
+			return errors.Wrap(ErrInvalidCfg, "probation webhook should be an http or https URL")
+		}
+		if pn.WebhookTimeout <= 0 {
+			return errors.Wrap(ErrInvalidCfg, "probation webhook timeout should be positive")
+		}
+	}
+	for _, delegate := range pn.Delegates {
+		if _, err := address.FromString(delegate); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid delegate address %s to notify about: %v", delegate, err)
+		}
+	}
+	return nil
+}
+
+// ValidateDev validates the development consensus config
+func ValidateDev(cfg Config) error {
+	if cfg.Consensus.Scheme != DevScheme {
+		return nil
+	}
+	if cfg.Consensus.Dev.Interval < 0 {
+		return errors.Wrap(ErrInvalidCfg, "dev sealing interval should not be negative")
+	}
+	return nil
+}
+
+// ValidateBLS validates the BLS private key, which should be the one of the producer registered in the genesis
+func ValidateBLS(cfg Config) error {
+	if cfg.Chain.BLSPrivKey == "" {
+		return nil
+	}
+	sk, err := bls.HexStringToPrivateKey(cfg.Chain.BLSPrivKey)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidCfg, "invalid BLS private key: %v", err)
+	}
+	// the BLS signature is appended to the secp256k1 signature of the commit endorsement
+	if _, ok := cfg.ProducerPublicKey().EcdsaPublicKey().(*ecdsa.PublicKey); !ok {
+		return errors.Wrap(ErrInvalidCfg, "BLS signing requires a secp256k1 producer key")
+	}
+	producer := cfg.ProducerAddress().String()
+	if pk, ok := cfg.Genesis.BLSPublicKeys[producer]; ok && pk != sk.PublicKey().HexString() {
+		return errors.Wrapf(ErrInvalidCfg, "BLS private key does not match the public key of %s in genesis", producer)
+	}
+	return nil
+}
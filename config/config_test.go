@@ -14,11 +14,15 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/go-pkgs/crypto"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/crypto/bls"
 )
 
 const (
@@ -222,6 +226,99 @@ func TestValidateDispatcher(t *testing.T) {
 	)
 }
 
+func TestValidateBlockSync(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateBlockSync(cfg))
+	cfg.BlockSync.MaxSegmentsPerPeer = 0
+	err := ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "max segments per peer should be greater than 0"))
+
+	cfg = Default
+	cfg.BlockSync.SegmentTTL = 0
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "block sync segment ttl should be greater than 0"))
+
+	cfg = Default
+	cfg.BlockSync.PeerDemotion = 0
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "block sync peer demotion should be greater than 0"))
+
+	cfg = Default
+	cfg.BlockSync.MaxPeerFailureRate = 1
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "max peer failure rate should be in (0, 1)"))
+
+	cfg = Default
+	cfg.BlockSync.HeaderBatchSize = 0
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "header batch size should be greater than 0"))
+
+	cfg = Default
+	cfg.BlockSync.StallIntervals = -1
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "block sync stall intervals should not be negative"))
+
+	cfg = Default
+	cfg.BlockSync.ServeQuota.MaxBlocksPerRequest = 0
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "max blocks per request should be greater than 0"))
+
+	cfg = Default
+	cfg.BlockSync.ServeQuota.ByteBurst = 0
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "serve quota burst should be greater than 0"))
+
+	cfg = Default
+	cfg.BlockSync.ServeQuota.MaxPeers = 0
+	err = ValidateBlockSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "serve quota max peers should be greater than 0"))
+
+	cfg.BlockSync.ServeQuota.BlocksPerSecond = 0
+	cfg.BlockSync.ServeQuota.BytesPerSecond = 0
+	require.NoError(t, ValidateBlockSync(cfg))
+}
+
+func TestValidateActionSync(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateActionSync(cfg))
+	cfg.ActionSync.AnnounceInterval = 0
+	err := ValidateActionSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "action announce interval should be greater than 0"))
+
+	cfg = Default
+	cfg.ActionSync.MaxHashesPerMsg = 0
+	err = ValidateActionSync(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "max hashes per action sync message should be greater than 0"))
+}
+
+func TestValidateLightClient(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateLightClient(cfg))
+	cfg.LightClient.Endpoint = "http://127.0.0.1:14015"
+	err := ValidateLightClient(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "light client needs the delegate set of a trusted epoch"))
+
+	cfg.LightClient.TrustedEpoch = 1
+	cfg.LightClient.TrustedDelegates = []string{"io1"}
+	require.NoError(t, ValidateLightClient(cfg))
+	cfg.LightClient.SyncInterval = 0
+	err = ValidateLightClient(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "light client sync interval and request timeout should be greater than 0"))
+}
+
 func TestValidateRollDPoS(t *testing.T) {
 	cfg := Default
 	cfg.Consensus.Scheme = RollDPoSScheme
@@ -234,6 +331,25 @@ func TestValidateRollDPoS(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "roll-DPoS event chan size should be greater than 0"),
 	)
+
+	cfg = Default
+	cfg.Consensus.Scheme = RollDPoSScheme
+	cfg.Consensus.RollDPoS.FSM.Adaptive.Enabled = true
+	require.NoError(t, ValidateRollDPoS(cfg))
+	cfg.Consensus.RollDPoS.FSM.Adaptive.MinRatio = 1.2
+	err = ValidateRollDPoS(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "ratios")
+	cfg.Consensus.RollDPoS.FSM.Adaptive.MinRatio = 0.5
+	cfg.Consensus.RollDPoS.FSM.Adaptive.Margin = 0.8
+	err = ValidateRollDPoS(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "margin")
+	cfg.Consensus.RollDPoS.FSM.Adaptive.Margin = 1.5
+	cfg.Consensus.RollDPoS.FSM.Adaptive.Window = 0
+	err = ValidateRollDPoS(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "window")
 }
 
 func TestValidateArchiveMode(t *testing.T) {
@@ -251,6 +367,237 @@ func TestValidateArchiveMode(t *testing.T) {
 	cfg.Chain.EnableArchiveMode = false
 	cfg.Chain.EnableTrielessStateDB = false
 	require.NoError(t, errors.Cause(ValidateArchiveMode(cfg)))
+	cfg.Chain.StateRetention = 100
+	require.NoError(t, errors.Cause(ValidateArchiveMode(cfg)))
+	cfg.Chain.EnableArchiveMode = true
+	require.EqualError(t, ValidateArchiveMode(cfg), "state retention is incompatible with archive mode: invalid config value")
+}
+
+func TestValidateBlockRetention(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateBlockRetention(cfg))
+	cfg.DB.BlockRetention = 8
+	err := ValidateBlockRetention(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "block retention should not be less than block store batch size"))
+	cfg.DB.BlockRetention = 1000
+	require.NoError(t, ValidateBlockRetention(cfg))
+	cfg.Chain.EnableArchiveMode = true
+	err = ValidateBlockRetention(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "archive mode is incompatible with block pruning"))
+}
+
+func TestValidateColdStorage(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateColdStorage(cfg))
+	cfg.DB.ColdStorage.URL = "s3://bucket/chain"
+	cfg.DB.V2BlocksToSplitDB = 0
+	err := ValidateColdStorage(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "cold storage needs the chain db split into files"))
+	cfg.DB.V2BlocksToSplitDB = 1000000
+	require.NoError(t, ValidateColdStorage(cfg))
+	cfg.DB.BlockRetention = 1000
+	err = ValidateColdStorage(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "cold storage is incompatible with block pruning"))
+	cfg.DB.BlockRetention = 0
+	cfg.DB.ColdStorage.CacheSize = 0
+	err = ValidateColdStorage(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "cold storage needs a cache directory and a positive cache size"))
+}
+
+func TestValidateReceiptRetention(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateReceiptRetention(cfg))
+	cfg.DB.Receipt.Retention = 100
+	err := ValidateReceiptRetention(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "receipt pruning needs a receipt db apart from the chain db"))
+	cfg.DB.Receipt.DbPath = "/var/data/receipt.db"
+	require.NoError(t, ValidateReceiptRetention(cfg))
+	cfg.Chain.EnableArchiveMode = true
+	err = ValidateReceiptRetention(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "archive mode is incompatible with receipt pruning"))
+}
+
+func TestValidateIntegrity(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateIntegrity(cfg))
+	cfg.DB.Integrity.CompactRatio = 0.5
+	err := ValidateIntegrity(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "compact ratio should not be less than 1"))
+	cfg.DB.Integrity.CompactRatio = 0
+	require.NoError(t, ValidateIntegrity(cfg))
+}
+
+func TestValidateDB(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateDB(cfg))
+	cfg.DB.Backend = "leveldb"
+	err := ValidateDB(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "unsupported db backend leveldb"))
+	cfg.DB.Backend = PebbleBackend
+	require.NoError(t, ValidateDB(cfg))
+	cfg.DB.EncryptedNamespaces = []string{"Token"}
+	require.NoError(t, ValidateDB(cfg))
+	cfg.DB.EncryptionKeyEnv = ""
+	err = ValidateDB(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "encryption key env"))
+	cfg = Default
+	cfg.DB.Flush.MinBatchBytes = 1024 * 1024
+	require.NoError(t, ValidateDB(cfg))
+	cfg.DB.Flush.MaxDelay = 0
+	err = ValidateDB(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "max delay"))
+	cfg.DB.Flush.MaxDelay = time.Second
+	cfg.DB.Flush.MaxBatchBytes = 1024
+	err = ValidateDB(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "min batch bytes"))
+}
+
+func TestValidateNetwork(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateNetwork(cfg))
+	cfg.Network.StaticPeers = []string{"/ip4/127.0.0.1/tcp/4689"}
+	err := ValidateNetwork(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "has no peer id"))
+	cfg.Network.StaticPeers = []string{"127.0.0.1:4689"}
+	err = ValidateNetwork(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "invalid static peer"))
+	cfg.Network.StaticPeers = []string{"/ip4/127.0.0.1/tcp/4689/ipfs/QmSrEo6NEhD2yrhLXwPMfTY4EGQGHqmMHzpc1oRh8Cy9U9"}
+	require.NoError(t, ValidateNetwork(cfg))
+	for _, seed := range []string{"", "seed.iotex.io/peers", "tree://seed.iotex.io", "tree://abcd@seed.iotex.io"} {
+		cfg.Network.DNSSeeds = []string{seed}
+		err = ValidateNetwork(cfg)
+		require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+		require.True(t, strings.Contains(err.Error(), "dns seed"))
+	}
+	sk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cfg.Network.DNSSeeds = []string{"seed.iotex.io", "tree://" + sk.PublicKey().HexString() + "@seed.iotex.io"}
+	require.NoError(t, ValidateNetwork(cfg))
+	cfg.Network.PeerMaintainInterval = -1
+	err = ValidateNetwork(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "peer maintain interval should not be negative"))
+	cfg.Network.PeerMaintainInterval = 0
+	cfg.Network.PeerMessageRate = -1
+	err = ValidateNetwork(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "ban threshold and peer message rate should not be negative"))
+	cfg.Network.PeerMessageRate = 0
+	cfg.Network.MaxBanDuration = time.Minute
+	err = ValidateNetwork(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "ban duration should be greater than 0 and not greater than max ban duration"))
+	cfg.Network.BanThreshold = 0
+	require.NoError(t, ValidateNetwork(cfg))
+	cfg.Network.Compression = "Gzip"
+	err = ValidateNetwork(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "unsupported p2p compression Gzip"))
+	cfg.Network.Compression = "Zstd"
+	require.NoError(t, ValidateNetwork(cfg))
+}
+
+func TestValidateReorg(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateReorg(cfg))
+	cfg.Chain.MaxReorgDepth = 10
+	err := ValidateReorg(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "deep reorg needs the index written along with the blocks"))
+	cfg.Chain.EnableAsyncIndexWrite = false
+	err = ValidateReorg(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "response cache tip depth should be no less than max reorg depth"))
+	cfg.API.ResponseCache.TipDepth = 10
+	require.NoError(t, ValidateReorg(cfg))
+	cfg.API.ResponseCache = ResponseCache{}
+	require.NoError(t, ValidateReorg(cfg))
+	cfg.Plugins = map[int]interface{}{GatewayPlugin: nil}
+	err = ValidateReorg(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "deep reorg does not support the gateway indexers"))
+	cfg.DB.BlockRetention = 10
+	err = ValidateReorg(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "max reorg depth should be less than block retention"))
+}
+
+func TestValidateCheckpoint(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateCheckpoint(cfg))
+	cfg.BlockSync.Checkpoint.Height = 100
+	cfg.BlockSync.Checkpoint.Hash = "abcd"
+	err := ValidateCheckpoint(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "checkpoint hash should be a hex encoded 32-byte hash"))
+	cfg.BlockSync.Checkpoint.Hash = strings.Repeat("ab", 32)
+	err = ValidateCheckpoint(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "checkpoint needs the url of its state snapshot"))
+	cfg.BlockSync.Checkpoint.Snapshot = "file:///var/data/snapshot-100.tar.gz"
+	require.NoError(t, ValidateCheckpoint(cfg))
+	cfg.Plugins = map[int]interface{}{GatewayPlugin: nil}
+	err = ValidateCheckpoint(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "checkpoint sync does not support the gateway indexers"))
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateSnapshot(cfg))
+	cfg.Snapshot.Dir = "/var/data/snapshots"
+	cfg.Consensus.Scheme = RollDPoSScheme
+	require.NoError(t, ValidateSnapshot(cfg))
+	cfg.Snapshot.Keep = 0
+	err := ValidateSnapshot(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "snapshot interval and the number of snapshots kept should be positive"))
+	cfg.Snapshot.Keep = 2
+	cfg.Consensus.Scheme = StandaloneScheme
+	err = ValidateSnapshot(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "snapshots are exported at the epoch boundaries of roll-DPoS"))
+	cfg.Consensus.Scheme = RollDPoSScheme
+	cfg.Chain.EnableTrielessStateDB = true
+	cfg.Chain.EnableStateDBCaching = true
+	err = ValidateSnapshot(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "snapshot export is incompatible with state DB caching"))
+}
+
+func TestValidateAPI(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateAPI(cfg))
+	cfg.API.GRPC.MaxSendMsgSize = 0
+	err := ValidateAPI(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "grpc max message sizes should be positive"))
+
+	cfg = Default
+	cfg.API.LogQuery.MaxLogs = 0
+	err = ValidateAPI(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "log query limits should be positive"))
+
+	cfg = Default
+	cfg.API.HTTP.TLSCertFile = "cert.pem"
+	err = ValidateAPI(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "tls certificate and key should be set together"))
 }
 
 func TestValidateActPool(t *testing.T) {
@@ -291,6 +638,69 @@ func TestValidateActPool(t *testing.T) {
 			"maximum number of actions per pool cannot be less than maximum number of actions per account",
 		),
 	)
+
+	cfg.ActPool.MaxNumActsPerPool = 100
+	cfg.ActPool.LocalAccounts = []string{"io1zf69lmyzkkzlywwqzzggst45qupvx2cyye62fp"}
+	require.NoError(t, ValidateActPool(cfg))
+	cfg.ActPool.LocalAccounts = append(cfg.ActPool.LocalAccounts, "0x1234")
+	err = ValidateActPool(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "invalid local account 0x1234")
+
+	cfg.ActPool.LocalAccounts = nil
+	cfg.ActPool.GasPriceSteps = []GasPriceStep{{Utilization: 80, Multiplier: 200}, {Utilization: 80, Multiplier: 400}}
+	err = ValidateActPool(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "gas price steps should be in ascending order of utilization")
+	cfg.ActPool.GasPriceSteps = []GasPriceStep{{Utilization: 80, Multiplier: 50}}
+	err = ValidateActPool(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "invalid gas price step")
+	cfg.ActPool.GasPriceSteps = Default.ActPool.GasPriceSteps
+	cfg.ActPool.GasPriceDecay = 101
+	err = ValidateActPool(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "gas price decay cannot be more than 100 percent")
+
+	cfg.ActPool.GasPriceDecay = Default.ActPool.GasPriceDecay
+	cfg.ActPool.AdmissionWindow = 0
+	err = ValidateActPool(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "admission window should be positive to limit admissions")
+
+	cfg.ActPool.AdmissionWindow = Default.ActPool.AdmissionWindow
+	cfg.ActPool.MaxNumOrphansPerAcct = 0
+	err = ValidateActPool(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "maximum number of orphans per account cannot be zero")
+}
+
+func TestValidateBlockComposition(t *testing.T) {
+	cfg := Default
+	require.NoError(t, ValidateBlockComposition(cfg))
+	cfg.Chain.BlockComposition.MaxExecutions = -1
+	err := ValidateBlockComposition(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "maximum number of executions per block cannot be negative")
+
+	cfg.Chain.BlockComposition.MaxExecutions = 10
+	cfg.Chain.BlockComposition.TransferReservedGas = cfg.Genesis.BlockGasLimit
+	err = ValidateBlockComposition(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "transfer reserved gas should be less than block gas limit")
+	cfg.Chain.BlockComposition.TransferReservedGas = cfg.Genesis.BlockGasLimit / 10
+	require.NoError(t, ValidateBlockComposition(cfg))
+
+	// the reserved gas is checked against the lowest limit of the schedule
+	cfg.Genesis.BlockGasLimitSchedule = []genesis.GasLimitActivation{
+		{Height: 100, GasLimit: cfg.Genesis.BlockGasLimit * 2},
+		{Height: 200, GasLimit: cfg.Genesis.BlockGasLimit / 20},
+	}
+	err = ValidateBlockComposition(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "transfer reserved gas should be less than block gas limit")
+	cfg.Genesis.BlockGasLimitSchedule[1].GasLimit = cfg.Genesis.BlockGasLimit / 5
+	require.NoError(t, ValidateBlockComposition(cfg))
 }
 
 func TestValidateMinGasPrice(t *testing.T) {
@@ -312,6 +722,81 @@ func TestValidateProducerAddress(t *testing.T) {
 	require.NotNil(t, addr)
 }
 
+func TestValidateRemoteSigner(t *testing.T) {
+	require := require.New(t)
+	sk, err := crypto.GenerateKey()
+	require.NoError(err)
+	cfg := Default
+	require.NoError(ValidateRemoteSigner(cfg))
+	cfg.Chain.RemoteSigner.Endpoint = "http://127.0.0.1:8080"
+	cfg.Chain.RemoteSigner.PublicKey = sk.PublicKey().HexString()
+	err = ValidateRemoteSigner(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "https")
+
+	cfg.Chain.RemoteSigner.Endpoint = "https://127.0.0.1:8080"
+	require.NoError(ValidateRemoteSigner(cfg))
+	require.Equal(sk.PublicKey().HexString(), cfg.ProducerPublicKey().HexString())
+	require.Equal(sk.PublicKey().Hash(), cfg.ProducerAddress().Bytes())
+
+	cfg.Chain.RemoteSigner.ClientCertPath = "client.crt"
+	err = ValidateRemoteSigner(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	cfg.Chain.RemoteSigner.ClientKeyPath = "client.key"
+	require.NoError(ValidateRemoteSigner(cfg))
+
+	cfg.Chain.RemoteSigner.AllowedMessageTypes = []string{"block", "transfer"}
+	err = ValidateRemoteSigner(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "transfer")
+}
+
+func TestValidateStandby(t *testing.T) {
+	require := require.New(t)
+	cfg := Default
+	require.NoError(ValidateStandby(cfg))
+	cfg.System.Standby.PrimaryEndpoint = "http://127.0.0.1:9009/ha"
+	err := ValidateStandby(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "stand-by mode")
+
+	cfg.System.Active = false
+	err = ValidateStandby(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "consensus DB")
+	cfg.Consensus.Scheme = RollDPoSScheme
+	require.NoError(ValidateStandby(cfg))
+
+	cfg.System.Standby.FailureWindow = time.Second
+	err = ValidateStandby(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "failure window")
+}
+
+func TestValidateProbationNotifier(t *testing.T) {
+	require := require.New(t)
+	cfg := Default
+	require.NoError(ValidateProbationNotifier(cfg))
+	cfg.Chain.ProbationNotifier.Enabled = true
+	err := ValidateProbationNotifier(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "roll-DPoS")
+
+	cfg.Consensus.Scheme = RollDPoSScheme
+	require.NoError(ValidateProbationNotifier(cfg))
+	cfg.Chain.ProbationNotifier.WebhookURL = "127.0.0.1:8080/probation"
+	err = ValidateProbationNotifier(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "http or https URL")
+	cfg.Chain.ProbationNotifier.WebhookURL = "http://127.0.0.1:8080/probation"
+	require.NoError(ValidateProbationNotifier(cfg))
+
+	cfg.Chain.ProbationNotifier.Delegates = []string{"io1abc"}
+	err = ValidateProbationNotifier(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "invalid delegate address")
+}
+
 func TestValidateForkHeights(t *testing.T) {
 	r := require.New(t)
 
@@ -482,3 +967,43 @@ func TestWhitelist(t *testing.T) {
 	cfg.Chain.SignatureScheme = append(cfg.Chain.SignatureScheme, SigP256sm2)
 	require.Equal(sk, cfg.ProducerPrivateKey())
 }
+
+func TestValidateDev(t *testing.T) {
+	require := require.New(t)
+	cfg := Default
+	cfg.Consensus.Dev.Interval = -time.Second
+	require.NoError(ValidateDev(cfg))
+	cfg.Consensus.Scheme = DevScheme
+	err := ValidateDev(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "dev sealing interval")
+	cfg.Consensus.Dev.Interval = time.Second
+	require.NoError(ValidateDev(cfg))
+}
+
+func TestValidateBLS(t *testing.T) {
+	require := require.New(t)
+	cfg := Default
+	require.NoError(ValidateBLS(cfg))
+	require.Nil(cfg.BLSPrivateKey())
+	cfg.Chain.BLSPrivKey = "not a key"
+	err := ValidateBLS(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "invalid BLS private key")
+
+	sk, err := bls.GenerateKey()
+	require.NoError(err)
+	cfg.Chain.BLSPrivKey = sk.HexString()
+	require.NoError(ValidateBLS(cfg))
+	require.Equal(sk.PublicKey().Bytes(), cfg.BLSPrivateKey().PublicKey().Bytes())
+	other, err := bls.GenerateKey()
+	require.NoError(err)
+	cfg.Genesis.BLSPublicKeys = map[string]string{
+		cfg.ProducerAddress().String(): other.PublicKey().HexString(),
+	}
+	err = ValidateBLS(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "does not match")
+	cfg.Genesis.BLSPublicKeys[cfg.ProducerAddress().String()] = sk.PublicKey().HexString()
+	require.NoError(ValidateBLS(cfg))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/poll"
 	rp "github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/config"
@@ -23,6 +24,7 @@ import (
 	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/signer"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/state/factory"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
@@ -50,6 +52,8 @@ type optionParams struct {
 	broadcastHandler scheme.Broadcast
 	pp               poll.Protocol
 	rp               *rp.Protocol
+	signer           signer.Signer
+	ap               actpool.ActPool
 }
 
 // Option sets Consensus construction parameter.
@@ -71,6 +75,22 @@ func WithRollDPoSProtocol(rp *rp.Protocol) Option {
 	}
 }
 
+// WithSigner is an option to sign the proposals and endorsements with the signer rather than the producer private key
+func WithSigner(s signer.Signer) Option {
+	return func(ops *optionParams) error {
+		ops.signer = s
+		return nil
+	}
+}
+
+// WithActPool is an option to seal a block on the arrival of an action in the dev scheme
+func WithActPool(ap actpool.ActPool) Option {
+	return func(ops *optionParams) error {
+		ops.ap = ap
+		return nil
+	}
+}
+
 // WithPollProtocol is an option to register poll protocol
 func WithPollProtocol(pp poll.Protocol) Option {
 	return func(ops *optionParams) error {
@@ -100,6 +120,7 @@ func NewConsensus(
 		bd := rolldpos.NewRollDPoSBuilder().
 			SetAddr(cfg.ProducerAddress().String()).
 			SetPriKey(cfg.ProducerPrivateKey()).
+			SetSigner(ops.signer).
 			SetConfig(cfg).
 			SetChainManager(bc).
 			SetBroadcast(ops.broadcastHandler).
@@ -143,7 +164,7 @@ func NewConsensus(
 		}
 	case config.NOOPScheme:
 		cs.scheme = scheme.NewNoop()
-	case config.StandaloneScheme:
+	case config.StandaloneScheme, config.DevScheme:
 		mintBlockCB := func() (*block.Block, error) {
 			blk, err := bc.MintNewBlock(time.Now())
 			if err != nil {
@@ -168,13 +189,24 @@ func NewConsensus(
 			}
 			return nil
 		}
-		cs.scheme = scheme.NewStandalone(
-			mintBlockCB,
-			commitBlockCB,
-			broadcastBlockCB,
-			bc,
-			cfg.Genesis.BlockInterval,
-		)
+		if cfg.Consensus.Scheme == config.StandaloneScheme {
+			cs.scheme = scheme.NewStandalone(
+				mintBlockCB,
+				commitBlockCB,
+				broadcastBlockCB,
+				bc,
+				cfg.Genesis.BlockInterval,
+			)
+			break
+		}
+		dev := scheme.NewDev(mintBlockCB, commitBlockCB, broadcastBlockCB, bc, cfg.Consensus.Dev.Interval)
+		if cfg.Consensus.Dev.Interval == 0 {
+			if ops.ap == nil {
+				return nil, errors.New("dev scheme requires the action pool to seal blocks on the arrival of actions")
+			}
+			ops.ap.AddSubscriber(dev)
+		}
+		cs.scheme = dev
 	default:
 		return nil, errors.Errorf("unexpected IotxConsensus scheme %s", cfg.Consensus.Scheme)
 	}
@@ -209,6 +241,24 @@ func (c *IotxConsensus) Metrics() (scheme.ConsensusMetrics, error) {
 	return c.scheme.Metrics()
 }
 
+// Evidences returns at most limit evidences of the equivocation of the delegates from height on
+func (c *IotxConsensus) Evidences(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+	reader, ok := c.scheme.(scheme.EvidenceReader)
+	if !ok {
+		return nil, scheme.ErrEvidenceNotSupported
+	}
+	return reader.Evidences(height, limit)
+}
+
+// RoundHistories returns the histories of at most limit recent consensus rounds, the latest first
+func (c *IotxConsensus) RoundHistories(limit uint64) ([]*scheme.RoundHistory, error) {
+	reader, ok := c.scheme.(scheme.RoundHistoryReader)
+	if !ok {
+		return nil, scheme.ErrRoundHistoryNotSupported
+	}
+	return reader.RoundHistories(limit)
+}
+
 // HandleConsensusMsg handles consensus messages
 func (c *IotxConsensus) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 	return c.scheme.HandleConsensusMsg(msg)
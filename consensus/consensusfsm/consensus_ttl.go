@@ -9,6 +9,7 @@ package consensusfsm
 import (
 	"time"
 
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/config"
 )
 
@@ -24,12 +25,14 @@ type (
 		CommitTTL(uint64) time.Duration
 		BlockInterval(uint64) time.Duration
 		Delay(uint64) time.Duration
+		AdaptiveTiming() config.AdaptiveTiming
 	}
 
 	// config implements ConsensusConfig
 	consensusCfg struct {
 		cfg           config.ConsensusTiming
 		hu            config.HeightUpgrade
+		chain         genesis.Blockchain
 		blockInterval time.Duration
 		delay         time.Duration
 	}
@@ -40,6 +43,7 @@ func NewConsensusConfig(cfg config.Config) ConsensusConfig {
 	return &consensusCfg{
 		cfg.Consensus.RollDPoS.FSM,
 		config.NewHeightUpgrade(&cfg.Genesis),
+		cfg.Genesis.Blockchain,
 		cfg.Genesis.Blockchain.BlockInterval,
 		cfg.Consensus.RollDPoS.Delay,
 	}
@@ -51,9 +55,9 @@ func (c *consensusCfg) EventChanSize() uint {
 
 func (c *consensusCfg) UnmatchedEventTTL(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
-		return config.DardanellesUnmatchedEventTTL
+		return c.scale(height, config.DardanellesUnmatchedEventTTL)
 	}
-	return c.cfg.UnmatchedEventTTL
+	return c.scale(height, c.cfg.UnmatchedEventTTL)
 }
 
 func (c *consensusCfg) UnmatchedEventInterval(height uint64) time.Duration {
@@ -65,42 +69,64 @@ func (c *consensusCfg) UnmatchedEventInterval(height uint64) time.Duration {
 
 func (c *consensusCfg) AcceptBlockTTL(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
-		return config.DardanellesAcceptBlockTTL
+		return c.scale(height, config.DardanellesAcceptBlockTTL)
 	}
-	return c.cfg.AcceptBlockTTL
+	return c.scale(height, c.cfg.AcceptBlockTTL)
 }
 
 func (c *consensusCfg) AcceptProposalEndorsementTTL(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
-		return config.DardanellesAcceptProposalEndorsementTTL
+		return c.scale(height, config.DardanellesAcceptProposalEndorsementTTL)
 	}
-	return c.cfg.AcceptProposalEndorsementTTL
+	return c.scale(height, c.cfg.AcceptProposalEndorsementTTL)
 }
 
 func (c *consensusCfg) AcceptLockEndorsementTTL(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
-		return config.DardanellesAcceptLockEndorsementTTL
+		return c.scale(height, config.DardanellesAcceptLockEndorsementTTL)
 	}
-	return c.cfg.AcceptLockEndorsementTTL
+	return c.scale(height, c.cfg.AcceptLockEndorsementTTL)
 }
 
 func (c *consensusCfg) CommitTTL(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
-		return config.DardanellesCommitTTL
+		return c.scale(height, config.DardanellesCommitTTL)
 	}
-	return c.cfg.CommitTTL
+	return c.scale(height, c.cfg.CommitTTL)
 }
 
 func (c *consensusCfg) BlockInterval(height uint64) time.Duration {
+	if interval, ok := c.chain.ScheduledBlockInterval(height); ok {
+		return interval
+	}
+	return c.baseBlockInterval(height)
+}
+
+// baseBlockInterval returns the block interval at the height regardless of the schedule
+func (c *consensusCfg) baseBlockInterval(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
 		return config.DardanellesBlockInterval
 	}
 	return c.blockInterval
 }
 
+// scale scales the ttl in proportion to the block interval of the schedule in effect at the height, so the phases of
+// a round fit in the interval as they do in the base interval
+func (c *consensusCfg) scale(height uint64, ttl time.Duration) time.Duration {
+	interval, ok := c.chain.ScheduledBlockInterval(height)
+	if !ok {
+		return ttl
+	}
+	return time.Duration(float64(ttl) * float64(interval) / float64(c.baseBlockInterval(height)))
+}
+
 func (c *consensusCfg) Delay(height uint64) time.Duration {
 	if c.hu.IsPost(config.Dardanelles, height) {
 		return config.DardanellesDelay
 	}
 	return c.delay
 }
+
+func (c *consensusCfg) AdaptiveTiming() config.AdaptiveTiming {
+	return c.cfg.Adaptive
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package consensusfsm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+func TestConsensusConfigBlockIntervalSchedule(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Genesis.BlockInterval = 10 * time.Second
+	cfg.Genesis.DardanellesBlockHeight = 100
+	cfg.Genesis.BlockIntervalSchedule = []genesis.BlockIntervalActivation{
+		{Height: 50, Interval: 8 * time.Second},
+		{Height: 200, Interval: 2 * time.Second},
+	}
+	cc := NewConsensusConfig(cfg)
+
+	for _, test := range []struct {
+		height   uint64
+		interval time.Duration
+		ttls     [numOfPhases]time.Duration
+	}{
+		// the intervals before dardanelles
+		{1, 10 * time.Second, [numOfPhases]time.Duration{
+			4 * time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second,
+		}},
+		{50, 8 * time.Second, [numOfPhases]time.Duration{
+			3200 * time.Millisecond, 1600 * time.Millisecond, 1600 * time.Millisecond, 1600 * time.Millisecond,
+		}},
+		// the schedule overrides the interval of dardanelles
+		{100, 8 * time.Second, [numOfPhases]time.Duration{
+			3200 * time.Millisecond, 1600 * time.Millisecond, 1600 * time.Millisecond, 1600 * time.Millisecond,
+		}},
+		{200, 2 * time.Second, [numOfPhases]time.Duration{
+			800 * time.Millisecond, 400 * time.Millisecond, 400 * time.Millisecond, 400 * time.Millisecond,
+		}},
+	} {
+		require.Equal(test.interval, cc.BlockInterval(test.height))
+		require.Equal(test.ttls, [numOfPhases]time.Duration{
+			cc.AcceptBlockTTL(test.height),
+			cc.AcceptProposalEndorsementTTL(test.height),
+			cc.AcceptLockEndorsementTTL(test.height),
+			cc.CommitTTL(test.height),
+		})
+	}
+	require.Equal(800*time.Millisecond, cc.UnmatchedEventTTL(200))
+	require.Equal(config.DardanellesUnmatchedEventInterval, cc.UnmatchedEventInterval(200))
+
+	// the dardanelles interval applies without the schedule
+	cfg.Genesis.BlockIntervalSchedule = nil
+	cc = NewConsensusConfig(cfg)
+	require.Equal(config.DardanellesBlockInterval, cc.BlockInterval(200))
+	require.Equal(config.DardanellesAcceptBlockTTL, cc.AcceptBlockTTL(200))
+}
@@ -91,6 +91,7 @@ type ConsensusFSM struct {
 	evtq  chan *ConsensusEvent
 	close chan interface{}
 	ctx   Context
+	timer *roundTimer
 	wg    sync.WaitGroup
 }
 
@@ -100,6 +101,7 @@ func NewConsensusFSM(ctx Context) (*ConsensusFSM, error) {
 		evtq:  make(chan *ConsensusEvent, ctx.EventChanSize()),
 		close: make(chan interface{}),
 		ctx:   ctx,
+		timer: newRoundTimer(ctx.AdaptiveTiming()),
 	}
 	b := fsm.NewBuilder().
 		AddInitialState(sPrepare).
@@ -386,6 +388,7 @@ func (m *ConsensusFSM) calibrate(evt fsm.Event) (fsm.State, error) {
 }
 
 func (m *ConsensusFSM) prepare(evt fsm.Event) (fsm.State, error) {
+	m.timer.Stop()
 	if err := m.ctx.Prepare(); err != nil {
 		m.ctx.Logger().Error("Error during prepare", zap.Error(err))
 		return m.BackToPrepare(0)
@@ -412,25 +415,27 @@ func (m *ConsensusFSM) prepare(evt fsm.Event) (fsm.State, error) {
 		m.ctx.Logger().Panic("failed to convert ConsensusEvent in prepare")
 	}
 	h = cEvt.Height()
-	ttl := m.ctx.AcceptBlockTTL(h) - overtime
+	ttls := m.timer.TTLs(h, m.ctx)
+	ttl := ttls[blockPhase] - overtime
 	// Setup timeouts
 	if preCommitEndorsement := m.ctx.PreCommitEndorsement(); preCommitEndorsement != nil {
 		cEvt := m.ctx.NewConsensusEvent(eBroadcastPreCommitEndorsement, preCommitEndorsement)
 		m.produce(cEvt, ttl)
-		ttl += m.ctx.AcceptProposalEndorsementTTL(cEvt.Height())
+		ttl += ttls[proposalEndorsementPhase]
 		m.produce(cEvt, ttl)
-		ttl += m.ctx.AcceptLockEndorsementTTL(cEvt.Height())
+		ttl += ttls[lockEndorsementPhase]
 		m.produce(cEvt, ttl)
-		ttl += m.ctx.CommitTTL(cEvt.Height())
+		ttl += ttls[commitPhase]
 		m.produceConsensusEvent(eStopReceivingPreCommitEndorsement, ttl)
 		return sAcceptPreCommitEndorsement, nil
 	}
+	m.timer.Start(time.Now().Add(-overtime), ttls)
 	m.produceConsensusEvent(eFailedToReceiveBlock, ttl)
-	ttl += m.ctx.AcceptProposalEndorsementTTL(h)
+	ttl += ttls[proposalEndorsementPhase]
 	m.produceConsensusEvent(eStopReceivingProposalEndorsement, ttl)
-	ttl += m.ctx.AcceptLockEndorsementTTL(h)
+	ttl += ttls[lockEndorsementPhase]
 	m.produceConsensusEvent(eStopReceivingLockEndorsement, ttl)
-	ttl += m.ctx.CommitTTL(h)
+	ttl += ttls[commitPhase]
 	m.produceConsensusEvent(eStopReceivingPreCommitEndorsement, ttl)
 	return sAcceptBlockProposal, nil
 }
@@ -446,6 +451,7 @@ func (m *ConsensusFSM) onReceiveBlock(evt fsm.Event) (fsm.State, error) {
 		m.ctx.Logger().Debug("Failed to generate proposal endorsement", zap.Error(err))
 		return sAcceptBlockProposal, nil
 	}
+	m.timer.Finish(blockPhase, time.Now())
 
 	return sAcceptProposalEndorsement, nil
 }
@@ -462,6 +468,7 @@ func (m *ConsensusFSM) processBlock(block interface{}) error {
 
 func (m *ConsensusFSM) onFailedToReceiveBlock(evt fsm.Event) (fsm.State, error) {
 	m.ctx.Logger().Warn("didn't receive the proposed block before timeout")
+	m.timer.Finish(blockPhase, time.Now())
 	if err := m.processBlock(nil); err != nil {
 		m.ctx.Logger().Debug("Failed to generate proposal endorsement", zap.Error(err))
 	}
@@ -482,6 +489,7 @@ func (m *ConsensusFSM) onReceiveProposalEndorsement(evt fsm.Event) (fsm.State, e
 	if lockEndorsement == nil {
 		return sAcceptProposalEndorsement, nil
 	}
+	m.timer.Finish(proposalEndorsementPhase, time.Now())
 	m.ProduceReceiveLockEndorsementEvent(lockEndorsement)
 	m.ctx.Broadcast(lockEndorsement)
 
@@ -490,6 +498,7 @@ func (m *ConsensusFSM) onReceiveProposalEndorsement(evt fsm.Event) (fsm.State, e
 
 func (m *ConsensusFSM) onStopReceivingProposalEndorsement(evt fsm.Event) (fsm.State, error) {
 	m.ctx.Logger().Warn("Not enough proposal endorsements")
+	m.timer.Finish(proposalEndorsementPhase, time.Now())
 
 	return sAcceptLockEndorsement, nil
 }
@@ -506,6 +515,7 @@ func (m *ConsensusFSM) onReceiveLockEndorsement(evt fsm.Event) (fsm.State, error
 	if preCommitEndorsement == nil {
 		return sAcceptLockEndorsement, nil
 	}
+	m.timer.Finish(lockEndorsementPhase, time.Now())
 	m.ProduceReceivePreCommitEndorsementEvent(preCommitEndorsement)
 	m.ctx.Broadcast(preCommitEndorsement)
 
@@ -525,6 +535,7 @@ func (m *ConsensusFSM) onBroadcastPreCommitEndorsement(evt fsm.Event) (fsm.State
 
 func (m *ConsensusFSM) onStopReceivingLockEndorsement(evt fsm.Event) (fsm.State, error) {
 	m.ctx.Logger().Warn("Not enough lock endorsements")
+	m.timer.Finish(lockEndorsementPhase, time.Now())
 
 	return m.BackToPrepare(0)
 }
@@ -538,11 +549,13 @@ func (m *ConsensusFSM) onReceivePreCommitEndorsement(evt fsm.Event) (fsm.State,
 	if err != nil || !committed {
 		return sAcceptPreCommitEndorsement, err
 	}
+	m.timer.Finish(commitPhase, time.Now())
 	return m.BackToPrepare(0)
 }
 
 func (m *ConsensusFSM) onStopReceivingPreCommitEndorsement(evt fsm.Event) (fsm.State, error) {
 	m.ctx.Logger().Warn("Not enough pre-commit endorsements")
+	m.timer.Finish(commitPhase, time.Now())
 
 	return m.BackToPrepare(0)
 }
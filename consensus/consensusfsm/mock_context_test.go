@@ -7,6 +7,7 @@ package consensusfsm
 import (
 	gomock "github.com/golang/mock/gomock"
 	fsm "github.com/iotexproject/go-fsm"
+	config "github.com/iotexproject/iotex-core/config"
 	zap "go.uber.org/zap"
 	reflect "reflect"
 	time "time"
@@ -400,6 +401,20 @@ func (mr *MockContextMockRecorder) CommitTTL(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitTTL", reflect.TypeOf((*MockContext)(nil).CommitTTL), arg0)
 }
 
+// AdaptiveTiming mocks base method
+func (m *MockContext) AdaptiveTiming() config.AdaptiveTiming {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdaptiveTiming")
+	ret0, _ := ret[0].(config.AdaptiveTiming)
+	return ret0
+}
+
+// AdaptiveTiming indicates an expected call of AdaptiveTiming
+func (mr *MockContextMockRecorder) AdaptiveTiming() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdaptiveTiming", reflect.TypeOf((*MockContext)(nil).AdaptiveTiming))
+}
+
 // BlockInterval mocks base method
 func (m *MockContext) BlockInterval(arg0 uint64) time.Duration {
 	m.ctrl.T.Helper()
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package consensusfsm
+
+import (
+	"time"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// the phases of a consensus round
+const (
+	blockPhase = iota
+	proposalEndorsementPhase
+	lockEndorsementPhase
+	commitPhase
+	numOfPhases
+)
+
+// roundTimer tracks how long the phases of the recent rounds take, which include the latency of the messages of the
+// phases, and adapts the ttls of the phases of the coming round to them. A phase taking no longer than the recent ones
+// gets a shorter ttl than the configured one, a phase timing out gets a longer ttl next round, and the ttls are bound
+// to [MinRatio, MaxRatio] of the configured ttls and the block interval.
+//
+// The ttls only decide how long the fsm waits in each phase, the timestamps of the endorsements are still derived
+// from the configured ttls, which all the delegates agree on.
+type roundTimer struct {
+	cfg       config.AdaptiveTiming
+	durations [numOfPhases][]time.Duration
+	ttls      [numOfPhases]time.Duration
+	started   bool
+	last      time.Time
+	finished  int
+}
+
+func newRoundTimer(cfg config.AdaptiveTiming) *roundTimer {
+	return &roundTimer{cfg: cfg}
+}
+
+// TTLs returns the ttls of the phases of a round at height
+func (t *roundTimer) TTLs(height uint64, cc ConsensusConfig) [numOfPhases]time.Duration {
+	ttls := [numOfPhases]time.Duration{
+		cc.AcceptBlockTTL(height),
+		cc.AcceptProposalEndorsementTTL(height),
+		cc.AcceptLockEndorsementTTL(height),
+		cc.CommitTTL(height),
+	}
+	if !t.cfg.Enabled {
+		return ttls
+	}
+	var sum time.Duration
+	for i, ttl := range ttls {
+		if longest := t.longest(i); longest > 0 {
+			ttls[i] = bound(
+				time.Duration(float64(longest)*t.cfg.Margin),
+				time.Duration(float64(ttl)*t.cfg.MinRatio),
+				time.Duration(float64(ttl)*t.cfg.MaxRatio),
+			)
+		}
+		sum += ttls[i]
+	}
+	if interval := cc.BlockInterval(height); sum > interval {
+		// the phases share the block interval in proportion to their ttls
+		for i := range ttls {
+			ttls[i] = time.Duration(float64(ttls[i]) * float64(interval) / float64(sum))
+		}
+	}
+	return ttls
+}
+
+// Start starts timing a round, which starts at start with the ttls
+func (t *roundTimer) Start(start time.Time, ttls [numOfPhases]time.Duration) {
+	t.started = true
+	t.last = start
+	t.ttls = ttls
+	t.finished = -1
+}
+
+// Stop stops timing the round, the phases not finished yet are not recorded
+func (t *roundTimer) Stop() {
+	t.started = false
+}
+
+// Finish records the duration of a phase of the round, which ends or times out at now
+func (t *roundTimer) Finish(phase int, now time.Time) {
+	if !t.started || phase <= t.finished {
+		return
+	}
+	// a phase timing out takes longer than it is recorded, which makes its ttl grow by the margin next round
+	durations := append(t.durations[phase], now.Sub(t.last))
+	if uint(len(durations)) > t.cfg.Window {
+		durations = durations[uint(len(durations))-t.cfg.Window:]
+	}
+	t.durations[phase] = durations
+	t.last = now
+	t.finished = phase
+}
+
+func (t *roundTimer) longest(phase int) time.Duration {
+	var longest time.Duration
+	for _, d := range t.durations[phase] {
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+func bound(d, lower, upper time.Duration) time.Duration {
+	if d < lower {
+		return lower
+	}
+	if d > upper {
+		return upper
+	}
+	return d
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package consensusfsm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+func TestRoundTimer(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Genesis.BlockInterval = 10 * time.Second
+	cfg.Genesis.DardanellesBlockHeight = 100
+	cc := NewConsensusConfig(cfg)
+	base := [numOfPhases]time.Duration{4 * time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second}
+
+	// the configured ttls are used if it is disabled
+	timer := newRoundTimer(cfg.Consensus.RollDPoS.FSM.Adaptive)
+	now := time.Now()
+	timer.Start(now, timer.TTLs(1, cc))
+	timer.Finish(blockPhase, now.Add(time.Second))
+	require.Equal(base, timer.TTLs(1, cc))
+
+	adaptive := cfg.Consensus.RollDPoS.FSM.Adaptive
+	adaptive.Enabled = true
+	adaptive.Window = 2
+	timer = newRoundTimer(adaptive)
+	// the configured ttls are used before any round is timed
+	require.Equal(base, timer.TTLs(1, cc))
+
+	// a fast round shortens the ttls down to the min ratio
+	timer.Start(now, base)
+	timer.Finish(blockPhase, now.Add(2*time.Second))
+	timer.Finish(proposalEndorsementPhase, now.Add(2200*time.Millisecond))
+	// a phase is recorded once
+	timer.Finish(blockPhase, now.Add(2300*time.Millisecond))
+	timer.Finish(lockEndorsementPhase, now.Add(3*time.Second))
+	require.Equal([numOfPhases]time.Duration{
+		3 * time.Second,
+		time.Second,
+		1200 * time.Millisecond,
+		2 * time.Second,
+	}, timer.TTLs(1, cc))
+
+	// a phase timing out gets a longer ttl up to the max ratio
+	now = now.Add(10 * time.Second)
+	timer.Start(now, timer.TTLs(1, cc))
+	timer.Finish(blockPhase, now.Add(3*time.Second))
+	timer.Finish(proposalEndorsementPhase, now.Add(4*time.Second))
+	timer.Finish(lockEndorsementPhase, now.Add(5200*time.Millisecond))
+	timer.Finish(commitPhase, now.Add(6*time.Second))
+	require.Equal([numOfPhases]time.Duration{
+		4500 * time.Millisecond,
+		1500 * time.Millisecond,
+		1800 * time.Millisecond,
+		1200 * time.Millisecond,
+	}, timer.TTLs(1, cc))
+
+	// the phases of a stopped round are not recorded
+	timer.Stop()
+	timer.Finish(blockPhase, now.Add(time.Hour))
+	require.Equal(4500*time.Millisecond, timer.TTLs(1, cc)[blockPhase])
+
+	// the phases share the block interval if the ttls exceed it
+	now = now.Add(10 * time.Second)
+	timer.Start(now, timer.TTLs(1, cc))
+	timer.Finish(blockPhase, now.Add(5*time.Second))
+	timer.Finish(proposalEndorsementPhase, now.Add(8*time.Second))
+	ttls := timer.TTLs(1, cc)
+	require.Equal([numOfPhases]time.Duration{
+		5 * time.Second,
+		2500 * time.Millisecond,
+		1500 * time.Millisecond,
+		time.Second,
+	}, ttls)
+
+	// the older rounds out of the window are dropped
+	now = now.Add(10 * time.Second)
+	timer.Start(now, ttls)
+	timer.Finish(blockPhase, now.Add(time.Second))
+	now = now.Add(10 * time.Second)
+	timer.Start(now, ttls)
+	timer.Finish(blockPhase, now.Add(time.Second))
+	require.Equal(2*time.Second, timer.TTLs(1, cc)[blockPhase])
+}
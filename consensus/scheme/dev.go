@@ -0,0 +1,122 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// Dev is the consensus scheme of a single-node development chain. It seals a block as soon as an action arrives, or
+// at a fixed interval whether there are actions or not if the interval is set.
+type Dev struct {
+	handler  *standaloneHandler
+	interval time.Duration
+	trigger  chan struct{}
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDev creates a Dev struct, which seals a block on the arrival of an action if interval is 0
+func NewDev(create CreateBlockCB, commit ConsensusDoneCB, pub BroadcastCB, bc blockchain.Blockchain, interval time.Duration) *Dev {
+	return &Dev{
+		handler: &standaloneHandler{
+			bc:       bc,
+			createCb: create,
+			commitCb: commit,
+			pubCb:    pub,
+		},
+		interval: interval,
+		trigger:  make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start starts sealing blocks
+func (d *Dev) Start(ctx context.Context) error {
+	d.wg.Add(1)
+	go d.run()
+	return nil
+}
+
+// Stop stops sealing blocks
+func (d *Dev) Stop(ctx context.Context) error {
+	close(d.quit)
+	d.wg.Wait()
+	return nil
+}
+
+// ReceiveAction triggers sealing a block in the instant mode. The actions arriving while a block is being sealed are
+// sealed in the next block
+func (d *Dev) ReceiveAction(action.SealedEnvelope) {
+	if d.interval > 0 {
+		return
+	}
+	select {
+	case d.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dev) run() {
+	defer d.wg.Done()
+	var tick <-chan time.Time
+	if d.interval > 0 {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-d.quit:
+			return
+		case <-d.trigger:
+			d.handler.Run()
+		case <-tick:
+			d.handler.Run()
+		}
+	}
+}
+
+// HandleConsensusMsg handles incoming consensus message
+func (d *Dev) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
+	log.L().Warn("Dev scheme does not handle incoming consensus messages.")
+	return nil
+}
+
+// Calibrate triggers an event to calibrate consensus context
+func (d *Dev) Calibrate(uint64) {}
+
+// ValidateBlockFooter validates signatures in block footer
+func (d *Dev) ValidateBlockFooter(*block.Block) error {
+	return nil
+}
+
+// Metrics is not implemented for dev scheme
+func (d *Dev) Metrics() (ConsensusMetrics, error) {
+	return ConsensusMetrics{}, errors.Wrapf(
+		ErrNotImplemented,
+		"dev scheme does not supported metrics yet",
+	)
+}
+
+// Activate is not implemented for dev scheme
+func (d *Dev) Activate(_ bool) {
+	log.S().Warn("Dev scheme could not support activate")
+}
+
+// Active is always true for dev scheme
+func (d *Dev) Active() bool { return true }
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+func TestDev(t *testing.T) {
+	require := require.New(t)
+	var created, committed, published int32
+	create := func() (*block.Block, error) {
+		atomic.AddInt32(&created, 1)
+		return &block.Block{}, nil
+	}
+	commit := func(*block.Block) error {
+		atomic.AddInt32(&committed, 1)
+		return nil
+	}
+	pub := func(*block.Block) error {
+		atomic.AddInt32(&published, 1)
+		return nil
+	}
+	sealed := func(n int32) func() bool {
+		return func() bool {
+			return atomic.LoadInt32(&created) == n &&
+				atomic.LoadInt32(&committed) == n &&
+				atomic.LoadInt32(&published) == n
+		}
+	}
+
+	t.Run("instant", func(t *testing.T) {
+		atomic.StoreInt32(&created, 0)
+		atomic.StoreInt32(&committed, 0)
+		atomic.StoreInt32(&published, 0)
+		dev := NewDev(create, commit, pub, nil, 0)
+		require.NoError(dev.Start(context.Background()))
+		// no block is sealed without actions
+		time.Sleep(50 * time.Millisecond)
+		require.True(sealed(0)())
+		dev.ReceiveAction(action.SealedEnvelope{})
+		require.Eventually(sealed(1), time.Second, 10*time.Millisecond)
+		dev.ReceiveAction(action.SealedEnvelope{})
+		require.Eventually(sealed(2), time.Second, 10*time.Millisecond)
+		require.NoError(dev.Stop(context.Background()))
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		atomic.StoreInt32(&created, 0)
+		atomic.StoreInt32(&committed, 0)
+		atomic.StoreInt32(&published, 0)
+		dev := NewDev(create, commit, pub, nil, 20*time.Millisecond)
+		// the actions do not trigger sealing in the interval mode
+		dev.ReceiveAction(action.SealedEnvelope{})
+		require.Len(dev.trigger, 0)
+		require.NoError(dev.Start(context.Background()))
+		require.Eventually(func() bool {
+			return atomic.LoadInt32(&published) >= 2
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(dev.Stop(context.Background()))
+	})
+}
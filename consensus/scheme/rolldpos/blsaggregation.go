@@ -0,0 +1,97 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"time"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/signer"
+)
+
+// blsAggregation signs the commit endorsements with BLS from the activation height on, so that the commit
+// endorsements in the block footer are verified with one aggregated signature instead of one signature per delegate
+type blsAggregation struct {
+	height uint64
+	key    *bls.PrivateKey
+	pks    map[string]*bls.PublicKey
+}
+
+func newBLSAggregation(g genesis.Blockchain, key *bls.PrivateKey) (*blsAggregation, error) {
+	pks := make(map[string]*bls.PublicKey, len(g.BLSPublicKeys))
+	for operator := range g.BLSPublicKeys {
+		pk, err := g.BLSPublicKey(operator)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load BLS public key of %s", operator)
+		}
+		pks[operator] = pk
+	}
+	return &blsAggregation{
+		height: g.BLSAggregationBlockHeight,
+		key:    key,
+		pks:    pks,
+	}, nil
+}
+
+// activated returns true if the commit endorsements of the height are aggregated
+func (a *blsAggregation) activated(height uint64) bool {
+	return a != nil && height >= a.height
+}
+
+func (a *blsAggregation) publicKey(endorser crypto.PublicKey) (*bls.PublicKey, error) {
+	addr, err := address.FromBytes(endorser.Hash())
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := a.pks[addr.String()]
+	if !ok {
+		return nil, errors.Errorf("BLS public key of %s is not registered", addr.String())
+	}
+	return pk, nil
+}
+
+// endorse endorses the commit vote with both the endorser and the BLS key
+func (a *blsAggregation) endorse(endorser signer.HashSigner, vote *ConsensusVote, ts time.Time) (*endorsement.Endorsement, error) {
+	if a.key == nil {
+		return nil, errors.New("BLS private key is not configured to endorse the commit vote")
+	}
+	return endorsement.EndorseWithBLS(endorser, a.key, vote, ts)
+}
+
+// verifyEndorsement checks the BLS signature of the commit endorsement, which is to be aggregated
+func (a *blsAggregation) verifyEndorsement(vote *ConsensusVote, en *endorsement.Endorsement) error {
+	pk, err := a.publicKey(en.Endorser())
+	if err != nil {
+		return err
+	}
+	if !endorsement.VerifyBLSEndorsement(vote, en, pk) {
+		return errors.New("invalid BLS endorsement for the vote")
+	}
+	return nil
+}
+
+// verifyAggregated checks the aggregated commit endorsements of a block footer
+func (a *blsAggregation) verifyAggregated(vote *ConsensusVote, ens []*endorsement.Endorsement) error {
+	pks := make([]*bls.PublicKey, 0, len(ens))
+	for _, en := range ens {
+		pk, err := a.publicKey(en.Endorser())
+		if err != nil {
+			return err
+		}
+		pks = append(pks, pk)
+	}
+	if !endorsement.VerifyAggregatedEndorsements(vote, ens, pks) {
+		return errors.New("invalid aggregated endorsements for the vote")
+	}
+	return nil
+}
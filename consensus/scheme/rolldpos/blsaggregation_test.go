@@ -0,0 +1,112 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestBLSAggregation(t *testing.T) {
+	require := require.New(t)
+	g := genesis.Default.Blockchain
+	g.BLSAggregationBlockHeight = 10
+	g.BLSPublicKeys = make(map[string]string)
+	keys := make([]*bls.PrivateKey, 4)
+	for i := range keys {
+		sk, err := bls.GenerateKey()
+		require.NoError(err)
+		keys[i] = sk
+		// the last delegate is not registered
+		if i < 3 {
+			g.BLSPublicKeys[identityset.Address(i).String()] = sk.PublicKey().HexString()
+		}
+	}
+	aggregations := make([]*blsAggregation, 4)
+	for i := range aggregations {
+		a, err := newBLSAggregation(g, keys[i])
+		require.NoError(err)
+		aggregations[i] = a
+	}
+	var nilAggregation *blsAggregation
+	require.False(nilAggregation.activated(10))
+	require.False(aggregations[0].activated(9))
+	require.True(aggregations[0].activated(10))
+
+	now := time.Now()
+	vote := NewConsensusVote([]byte("block"), COMMIT)
+	var ens []*endorsement.Endorsement
+	for i := 0; i < 4; i++ {
+		en, err := aggregations[i].endorse(identityset.PrivateKey(i), vote, now.Add(time.Duration(i)*time.Millisecond))
+		require.NoError(err)
+		require.True(endorsement.VerifyEndorsement(vote, en))
+		// the BLS signature survives the protobuf message in a field of its own
+		pb, err := en.Proto()
+		require.NoError(err)
+		require.Equal(en.Signature(), pb.Signature)
+		b, err := proto.Marshal(pb)
+		require.NoError(err)
+		pb = &iotextypes.Endorsement{}
+		require.NoError(proto.Unmarshal(b, pb))
+		loaded := &endorsement.Endorsement{}
+		require.NoError(loaded.LoadProto(pb))
+		require.Equal(en.Signature(), loaded.Signature())
+		require.Equal(en.BLSSignature(), loaded.BLSSignature())
+		ens = append(ens, loaded)
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(aggregations[0].verifyEndorsement(vote, ens[i]))
+		require.Error(aggregations[0].verifyEndorsement(NewConsensusVote([]byte("other"), COMMIT), ens[i]))
+	}
+	require.Error(aggregations[0].verifyEndorsement(vote, ens[3]))
+	// the endorsement without BLS signature is not aggregated
+	plain, err := endorsement.Endorse(identityset.PrivateKey(0), vote, now)
+	require.NoError(err)
+	pb, err := plain.Proto()
+	require.NoError(err)
+	require.Empty(proto.MessageReflect(pb).GetUnknown())
+	require.Error(aggregations[0].verifyEndorsement(vote, plain))
+	_, err = endorsement.AggregateEndorsements([]*endorsement.Endorsement{plain})
+	require.Error(err)
+	_, err = (&blsAggregation{}).endorse(identityset.PrivateKey(0), vote, now)
+	require.Error(err)
+
+	aggregated, err := endorsement.AggregateEndorsements(ens[:3])
+	require.NoError(err)
+	require.Len(aggregated, 3)
+	for i, en := range aggregated {
+		require.Empty(en.Signature())
+		require.Equal(ens[i].Endorser(), en.Endorser())
+		require.True(ens[i].Timestamp().Equal(en.Timestamp()))
+		// the aggregated endorsements survive the protobuf message
+		pb, err := en.Proto()
+		require.NoError(err)
+		loaded := &endorsement.Endorsement{}
+		require.NoError(loaded.LoadProto(pb))
+		require.Equal(en.BLSSignature(), loaded.BLSSignature())
+		aggregated[i] = loaded
+	}
+	require.Len(aggregated[0].BLSSignature(), bls.SignatureLength)
+	require.Empty(aggregated[1].BLSSignature())
+	require.NoError(aggregations[1].verifyAggregated(vote, aggregated))
+	require.Error(aggregations[1].verifyAggregated(NewConsensusVote([]byte("other"), COMMIT), aggregated))
+	require.Error(aggregations[1].verifyAggregated(vote, aggregated[1:]))
+	require.Error(aggregations[1].verifyAggregated(vote, nil))
+	// an endorser not registered is rejected
+	aggregated, err = endorsement.AggregateEndorsements(ens)
+	require.NoError(err)
+	require.Error(aggregations[1].verifyAggregated(vote, aggregated))
+}
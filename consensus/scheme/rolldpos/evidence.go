@@ -0,0 +1,234 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	evidenceNS = "evidence"
+	// evidenceHeightWindow is the number of the recent heights of which the messages are kept for detection, the
+	// messages below the consensus height are dropped before they are checked
+	evidenceHeightWindow = 3
+)
+
+var (
+	equivocationMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_consensus_equivocation",
+			Help: "Conflicting consensus messages signed by the same delegate",
+		},
+		[]string{"type"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(equivocationMtc)
+}
+
+type (
+	seenMessage struct {
+		msg      *EndorsedConsensusMessage
+		reported bool
+	}
+
+	// evidencePool detects the conflicting proposals or endorsements a delegate signs in the same round, and keeps the
+	// evidences in the consensus DB, or in memory if there is none
+	evidencePool struct {
+		kv        db.KVStore
+		seen      map[uint64]map[string]*seenMessage
+		evidences []*scheme.Evidence
+		mutex     sync.Mutex
+	}
+)
+
+func newEvidencePool(kv db.KVStore) *evidencePool {
+	return &evidencePool{
+		kv:   kv,
+		seen: map[uint64]map[string]*seenMessage{},
+	}
+}
+
+// Check checks the message against the message of the same kind the delegate signed in the same round, and returns
+// the evidence if they conflict
+func (p *evidencePool) Check(msg *EndorsedConsensusMessage) (*scheme.Evidence, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	height := msg.Height()
+	msgs, exists := p.seen[height]
+	if !exists {
+		msgs = map[string]*seenMessage{}
+		p.seen[height] = msgs
+		for h := range p.seen {
+			if h+evidenceHeightWindow <= height {
+				delete(p.seen, h)
+			}
+		}
+	}
+	en := msg.Endorsement()
+	key := fmt.Sprintf("%s-%s-%d", en.Endorser().HexString(), signedKey(msg.Document()), en.Timestamp().UnixNano())
+	seen, exists := msgs[key]
+	if !exists {
+		msgs[key] = &seenMessage{msg: msg}
+		return nil, nil
+	}
+	if seen.reported || bytes.Equal(documentHash(seen.msg.Document()), documentHash(msg.Document())) {
+		return nil, nil
+	}
+	evidence, err := newEvidence(seen.msg, msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.put(evidence, key); err != nil {
+		return nil, err
+	}
+	seen.reported = true
+	if _, ok := msg.Document().(*blockProposal); ok {
+		equivocationMtc.WithLabelValues("proposal").Inc()
+	} else {
+		equivocationMtc.WithLabelValues("endorsement").Inc()
+	}
+	return evidence, nil
+}
+
+// Evidences returns at most limit evidences from height on
+func (p *evidencePool) Evidences(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	evidences := []*scheme.Evidence{}
+	if p.kv == nil {
+		for _, evidence := range p.evidences {
+			if uint64(len(evidences)) == limit {
+				break
+			}
+			if evidence.Height >= height {
+				evidences = append(evidences, evidence)
+			}
+		}
+		return evidences, nil
+	}
+	_, values, err := p.kv.Filter(evidenceNS, func(k, v []byte) bool { return true }, byteutil.Uint64ToBytesBigEndian(height), nil)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return evidences, nil
+	default:
+		return nil, err
+	}
+	for _, value := range values {
+		if uint64(len(evidences)) == limit {
+			break
+		}
+		evidence, err := decodeEvidence(value)
+		if err != nil {
+			return nil, err
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences, nil
+}
+
+func (p *evidencePool) put(evidence *scheme.Evidence, key string) error {
+	if p.kv == nil {
+		p.evidences = append(p.evidences, evidence)
+		return nil
+	}
+	value, err := encodeEvidence(evidence)
+	if err != nil {
+		return err
+	}
+	keyHash := hash.Hash256b([]byte(key))
+	return p.kv.Put(evidenceNS, append(byteutil.Uint64ToBytesBigEndian(evidence.Height), keyHash[:]...), value)
+}
+
+func newEvidence(first, second *EndorsedConsensusMessage) (*scheme.Evidence, error) {
+	en := first.Endorsement()
+	endorser, err := address.FromBytes(en.Endorser().Hash())
+	if err != nil {
+		return nil, err
+	}
+	evidence := &scheme.Evidence{
+		Height:    first.Height(),
+		Endorser:  endorser.String(),
+		Timestamp: en.Timestamp(),
+	}
+	for i, msg := range []*EndorsedConsensusMessage{first, second} {
+		if evidence.Messages[i], err = msg.Proto(); err != nil {
+			return nil, err
+		}
+	}
+	return evidence, nil
+}
+
+// encodeEvidence encodes the two messages of the evidence, each prefixed with its length
+func encodeEvidence(evidence *scheme.Evidence) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, msg := range evidence.Messages {
+		value, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(&buf, binary.BigEndian, uint32(len(value))); err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEvidence(value []byte) (*scheme.Evidence, error) {
+	var msgs [2]*EndorsedConsensusMessage
+	for i := range msgs {
+		if len(value) < 4 {
+			return nil, errors.New("invalid evidence")
+		}
+		size := binary.BigEndian.Uint32(value)
+		value = value[4:]
+		if uint64(len(value)) < uint64(size) {
+			return nil, errors.New("invalid evidence")
+		}
+		msgPb := &iotextypes.ConsensusMessage{}
+		if err := proto.Unmarshal(value[:size], msgPb); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal evidence")
+		}
+		value = value[size:]
+		msgs[i] = &EndorsedConsensusMessage{}
+		if err := msgs[i].LoadProto(msgPb); err != nil {
+			return nil, errors.Wrap(err, "failed to load evidence")
+		}
+	}
+	return newEvidence(msgs[0], msgs[1])
+}
+
+// documentHash returns the hash of the block a consensus message is about
+func documentHash(doc endorsement.Document) []byte {
+	switch d := doc.(type) {
+	case *ConsensusVote:
+		return d.BlockHash()
+	case *blockProposal:
+		h := d.block.HashBlock()
+		return h[:]
+	default:
+		return nil
+	}
+}
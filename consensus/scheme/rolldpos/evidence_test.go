@@ -0,0 +1,105 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestEvidencePool(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	endorse := func(i int, height uint64, hash string, topic ConsensusVoteTopic, ts time.Time) *EndorsedConsensusMessage {
+		vote := NewConsensusVote([]byte(hash), topic)
+		en, err := endorsement.Endorse(identityset.PrivateKey(i), vote, ts)
+		require.NoError(err)
+		return NewEndorsedConsensusMessage(height, vote, en)
+	}
+	check := func(p *evidencePool) {
+		for _, msg := range []*EndorsedConsensusMessage{
+			endorse(1, 5, "a", PROPOSAL, now),
+			// the same message again
+			endorse(1, 5, "a", PROPOSAL, now),
+			// another topic, round, height or delegate
+			endorse(1, 5, "b", LOCK, now),
+			endorse(1, 5, "b", PROPOSAL, now.Add(time.Second)),
+			endorse(1, 6, "b", PROPOSAL, now),
+			endorse(2, 5, "b", PROPOSAL, now),
+		} {
+			evidence, err := p.Check(msg)
+			require.NoError(err)
+			require.Nil(evidence)
+		}
+		evidence, err := p.Check(endorse(1, 5, "b", PROPOSAL, now))
+		require.NoError(err)
+		require.NotNil(evidence)
+		require.Equal(uint64(5), evidence.Height)
+		require.Equal(identityset.Address(1).String(), evidence.Endorser)
+		require.True(now.Equal(evidence.Timestamp))
+		// the equivocation is reported once
+		evidence, err = p.Check(endorse(1, 5, "c", PROPOSAL, now))
+		require.NoError(err)
+		require.Nil(evidence)
+		evidence, err = p.Check(endorse(2, 6, "c", COMMIT, now))
+		require.NoError(err)
+		require.Nil(evidence)
+		evidence, err = p.Check(endorse(2, 6, "d", COMMIT, now))
+		require.NoError(err)
+		require.NotNil(evidence)
+
+		evidences, err := p.Evidences(0, 10)
+		require.NoError(err)
+		require.Equal(2, len(evidences))
+		for i, hash := range []string{"a", "b"} {
+			msg := &EndorsedConsensusMessage{}
+			require.NoError(msg.LoadProto(evidences[0].Messages[i]))
+			require.Equal([]byte(hash), msg.Document().(*ConsensusVote).BlockHash())
+		}
+		evidences, err = p.Evidences(6, 10)
+		require.NoError(err)
+		require.Equal(1, len(evidences))
+		require.Equal(identityset.Address(2).String(), evidences[0].Endorser)
+		evidences, err = p.Evidences(0, 1)
+		require.NoError(err)
+		require.Equal(1, len(evidences))
+
+		// the messages of the old heights are dropped
+		_, err = p.Check(endorse(3, 5+evidenceHeightWindow, "a", PROPOSAL, now))
+		require.NoError(err)
+		_, exists := p.seen[5]
+		require.False(exists)
+	}
+
+	t.Run("memory", func(t *testing.T) {
+		check(newEvidencePool(nil))
+	})
+	t.Run("db", func(t *testing.T) {
+		testPath, err := testutil.PathOfTempFile("evidence")
+		require.NoError(err)
+		defer testutil.CleanupPath(t, testPath)
+		cfg := config.Default.DB
+		cfg.DbPath = testPath
+		kv := db.NewBoltDB(cfg)
+		require.NoError(kv.Start(context.Background()))
+		defer kv.Stop(context.Background())
+		p := newEvidencePool(kv)
+		evidences, err := p.Evidences(0, 10)
+		require.NoError(err)
+		require.Empty(evidences)
+		check(p)
+	})
+}
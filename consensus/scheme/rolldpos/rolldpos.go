@@ -24,6 +24,7 @@ import (
 	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/endorsement"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/signer"
 )
 
 var (
@@ -60,6 +61,7 @@ type ChainManager interface {
 type RollDPoS struct {
 	cfsm       *consensusfsm.ConsensusFSM
 	ctx        *rollDPoSCtx
+	evidences  *evidencePool
 	startDelay time.Duration
 	ready      chan interface{}
 }
@@ -87,10 +89,26 @@ func (r *RollDPoS) Stop(ctx context.Context) error {
 	return errors.Wrap(r.ctx.Stop(ctx), "error when stopping the roll dpos context")
 }
 
+// ValidateConsensusMsg checks the consensus message is decodable and endorsed by its signer, regardless of the state of
+// the consensus
+func ValidateConsensusMsg(msg *iotextypes.ConsensusMessage) error {
+	endorsedMessage := &EndorsedConsensusMessage{}
+	if err := endorsedMessage.LoadProto(msg); err != nil {
+		return errors.Wrapf(err, "failed to decode endorsed consensus message")
+	}
+	if !endorsement.VerifyEndorsedDocument(endorsedMessage) {
+		return errors.New("failed to verify signature in endorsement")
+	}
+	return nil
+}
+
 // HandleConsensusMsg handles incoming consensus message
 func (r *RollDPoS) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
-	// Do not handle consensus message if the node is not active in consensus
+	// Do not handle consensus message if the node is not active in consensus, but log the messages signed by the
+	// active node sharing the producer key, so that the node does not sign conflicting ones once it takes over
 	if !r.ctx.Active() {
+		<-r.ready
+		r.ctx.LogSignedByPeer(msg)
 		return nil
 	}
 	<-r.ready
@@ -127,12 +145,14 @@ func (r *RollDPoS) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 		if err := r.ctx.CheckBlockProposer(endorsedMessage.Height(), consensusMessage, en); err != nil {
 			return errors.Wrap(err, "failed to verify block proposal")
 		}
+		r.checkEquivocation(endorsedMessage)
 		r.cfsm.ProduceReceiveBlockEvent(endorsedMessage)
 		return nil
 	case *ConsensusVote:
 		if err := r.ctx.CheckVoteEndorser(endorsedMessage.Height(), consensusMessage, en); err != nil {
 			return errors.Wrapf(err, "failed to verify vote")
 		}
+		r.checkEquivocation(endorsedMessage)
 		switch consensusMessage.Topic() {
 		case PROPOSAL:
 			r.cfsm.ProduceReceiveProposalEndorsementEvent(endorsedMessage)
@@ -148,6 +168,32 @@ func (r *RollDPoS) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 	}
 }
 
+// RoundHistories returns the histories of at most limit recent rounds, the latest first
+func (r *RollDPoS) RoundHistories(limit uint64) ([]*scheme.RoundHistory, error) {
+	return r.ctx.history.Histories(limit), nil
+}
+
+// Evidences returns at most limit evidences of the equivocation of the delegates from height on
+func (r *RollDPoS) Evidences(height uint64, limit uint64) ([]*scheme.Evidence, error) {
+	return r.evidences.Evidences(height, limit)
+}
+
+func (r *RollDPoS) checkEquivocation(msg *EndorsedConsensusMessage) {
+	evidence, err := r.evidences.Check(msg)
+	if err != nil {
+		log.Logger("consensus").Error("failed to check equivocation", zap.Error(err))
+		return
+	}
+	if evidence != nil {
+		log.Logger("consensus").Warn(
+			"delegate signed conflicting consensus messages",
+			zap.String("endorser", evidence.Endorser),
+			zap.Uint64("height", evidence.Height),
+			zap.Time("timestamp", evidence.Timestamp),
+		)
+	}
+}
+
 // Calibrate called on receive a new block not via consensus
 func (r *RollDPoS) Calibrate(height uint64) {
 	r.cfsm.Calibrate(height)
@@ -170,13 +216,26 @@ func (r *RollDPoS) ValidateBlockFooter(blk *block.Block) error {
 		return err
 	}
 	blkHash := blk.HashBlock()
-	for _, en := range blk.Endorsements() {
-		if err := round.AddVoteEndorsement(
-			NewConsensusVote(blkHash[:], COMMIT),
-			en,
-		); err != nil {
+	vote := NewConsensusVote(blkHash[:], COMMIT)
+	if r.ctx.bls.activated(height) {
+		if err := r.ctx.bls.verifyAggregated(vote, blk.Endorsements()); err != nil {
 			return err
 		}
+		for _, en := range blk.Endorsements() {
+			if err := round.AddVerifiedVoteEndorsement(vote, en); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, en := range blk.Endorsements() {
+			// the BLS signature is only carried from the activation height on
+			if len(en.BLSSignature()) != 0 {
+				return errors.New("BLS signature of the endorsement before the aggregation height")
+			}
+			if err := round.AddVoteEndorsement(vote, en); err != nil {
+				return err
+			}
+		}
 	}
 	if !round.EndorsedByMajority(blkHash[:], []ConsensusVoteTopic{COMMIT}) {
 		return ErrInsufficientEndorsements
@@ -189,7 +248,7 @@ func (r *RollDPoS) ValidateBlockFooter(blk *block.Block) error {
 func (r *RollDPoS) Metrics() (scheme.ConsensusMetrics, error) {
 	var metrics scheme.ConsensusMetrics
 	height := r.ctx.chain.TipHeight()
-	round, err := r.ctx.roundCalc.NewRound(height+1, r.ctx.BlockInterval(height), time.Now(), nil)
+	round, err := r.ctx.roundCalc.NewRound(height+1, r.ctx.BlockInterval(height+1), time.Now(), nil)
 	if err != nil {
 		return metrics, errors.Wrap(err, "error when calculating round")
 	}
@@ -233,6 +292,7 @@ type Builder struct {
 	// TODO: we should use keystore in the future
 	encodedAddr      string
 	priKey           crypto.PrivateKey
+	signer           signer.Signer
 	chain            ChainManager
 	broadcastHandler scheme.Broadcast
 	// TODO: explorer dependency deleted at #1085, need to add api params
@@ -257,6 +317,12 @@ func (b *Builder) SetAddr(encodedAddr string) *Builder {
 	return b
 }
 
+// SetSigner sets the signer of the producer key, which overrides the private key
+func (b *Builder) SetSigner(s signer.Signer) *Builder {
+	b.signer = s
+	return b
+}
+
 // SetPriKey sets the private key
 func (b *Builder) SetPriKey(priKey crypto.PrivateKey) *Builder {
 	b.priKey = priKey
@@ -298,6 +364,10 @@ func (b *Builder) Build() (*RollDPoS, error) {
 		return nil, errors.Wrap(ErrNewRollDPoS, "broadcast callback is nil")
 	}
 	b.cfg.DB.DbPath = b.cfg.Consensus.RollDPoS.ConsensusDBPath
+	var endorser signer.HashSigner = b.priKey
+	if b.signer != nil {
+		endorser = signer.ForMessage(b.signer, signer.EndorsementMessage)
+	}
 	ctx, err := newRollDPoSCtx(
 		consensusfsm.NewConsensusConfig(b.cfg),
 		b.cfg.DB,
@@ -309,12 +379,15 @@ func (b *Builder) Build() (*RollDPoS, error) {
 		b.broadcastHandler,
 		b.delegatesByEpochFunc,
 		b.encodedAddr,
-		b.priKey,
+		endorser,
 		b.cfg.Genesis.BeringBlockHeight,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "error when constructing consensus context")
 	}
+	if ctx.bls, err = newBLSAggregation(b.cfg.Genesis.Blockchain, b.cfg.BLSPrivateKey()); err != nil {
+		return nil, errors.Wrap(err, "error when constructing BLS aggregation")
+	}
 	cfsm, err := consensusfsm.NewConsensusFSM(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "error when constructing the consensus FSM")
@@ -322,6 +395,7 @@ func (b *Builder) Build() (*RollDPoS, error) {
 	return &RollDPoS{
 		cfsm:       cfsm,
 		ctx:        ctx,
+		evidences:  newEvidencePool(ctx.eManagerDB),
 		startDelay: b.cfg.Consensus.RollDPoS.Delay,
 		ready:      make(chan interface{}),
 	}, nil
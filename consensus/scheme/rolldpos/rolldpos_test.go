@@ -35,6 +35,7 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/config"
 	cp "github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/crypto/bls"
 	"github.com/iotexproject/iotex-core/endorsement"
 	"github.com/iotexproject/iotex-core/p2p/node"
 	"github.com/iotexproject/iotex-core/state/factory"
@@ -168,7 +169,7 @@ func TestValidateBlockFooter(t *testing.T) {
 	blockHeight := uint64(8)
 	footer := &block.Footer{}
 	blockchain := mock_blockchain.NewMockBlockchain(ctrl)
-	blockchain.EXPECT().BlockFooterByHeight(blockHeight).Return(footer, nil).Times(5)
+	blockchain.EXPECT().BlockFooterByHeight(blockHeight).Return(footer, nil).Times(6)
 
 	sk1 := identityset.PrivateKey(1)
 	cfg := config.Default
@@ -176,7 +177,7 @@ func TestValidateBlockFooter(t *testing.T) {
 	cfg.Genesis.NumSubEpochs = 1
 	cfg.Genesis.BlockInterval = 10 * time.Second
 	cfg.Genesis.Timestamp = int64(1500000000)
-	blockchain.EXPECT().Genesis().Return(cfg.Genesis).Times(5)
+	blockchain.EXPECT().Genesis().Return(cfg.Genesis).Times(6)
 	rp := rolldpos.NewProtocol(
 		cfg.Genesis.NumCandidateDelegates,
 		cfg.Genesis.NumDelegates,
@@ -227,6 +228,22 @@ func TestValidateBlockFooter(t *testing.T) {
 	blk = makeBlock(t, 1, 4, true, 9)
 	err = r.ValidateBlockFooter(blk)
 	require.Error(t, err)
+
+	// Some endorsement carries BLS signature before the aggregation height
+	blk = makeBlock(t, 1, 4, false, 9)
+	blsKey, err := bls.GenerateKey()
+	require.NoError(t, err)
+	en := blk.Endorsements()[0]
+	hs := blk.HashBlock()
+	en, err = endorsement.EndorseWithBLS(identityset.PrivateKey(0), blsKey, NewConsensusVote(hs[:], COMMIT), en.Timestamp())
+	require.NoError(t, err)
+	footerPb, err := blk.Footer.ConvertToBlockFooterPb()
+	require.NoError(t, err)
+	footerPb.Endorsements[0], err = en.Proto()
+	require.NoError(t, err)
+	require.NoError(t, blk.Footer.ConvertFromBlockFooterPb(footerPb))
+	err = r.ValidateBlockFooter(blk)
+	require.Error(t, err)
 }
 
 func TestRollDPoS_Metrics(t *testing.T) {
@@ -666,3 +683,17 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateConsensusMsg(t *testing.T) {
+	require := require.New(t)
+	vote := NewConsensusVote(hash.ZeroHash256[:], COMMIT)
+	en, err := endorsement.Endorse(identityset.PrivateKey(0), vote, time.Now())
+	require.NoError(err)
+	msg, err := NewEndorsedConsensusMessage(1, vote, en).Proto()
+	require.NoError(err)
+	require.NoError(ValidateConsensusMsg(msg))
+	// the endorsement is of another vote
+	msg.GetVote().Topic = iotextypes.ConsensusVote_LOCK
+	require.Contains(ValidateConsensusMsg(msg).Error(), "failed to verify signature")
+	require.Error(ValidateConsensusMsg(&iotextypes.ConsensusMessage{}))
+}
@@ -7,13 +7,14 @@
 package rolldpos
 
 import (
+	"bytes"
 	"context"
 	"sync"
 	"time"
 
 	fsm "github.com/iotexproject/go-fsm"
-	"github.com/iotexproject/go-pkgs/crypto"
 	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -26,6 +27,7 @@ import (
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/endorsement"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/signer"
 )
 
 var (
@@ -79,10 +81,14 @@ type rollDPoSCtx struct {
 	broadcastHandler  scheme.Broadcast
 	roundCalc         *roundCalculator
 	eManagerDB        db.KVStore
+	wal               *consensusWAL
+	walMsgs           []*EndorsedConsensusMessage
+	history           *roundHistory
 	toleratedOvertime time.Duration
 
 	encodedAddr string
-	priKey      crypto.PrivateKey
+	endorser    signer.HashSigner
+	bls         *blsAggregation
 	round       *roundCtx
 	active      bool
 	mutex       sync.RWMutex
@@ -99,7 +105,7 @@ func newRollDPoSCtx(
 	broadcastHandler scheme.Broadcast,
 	delegatesByEpochFunc DelegatesByEpochFunc,
 	encodedAddr string,
-	priKey crypto.PrivateKey,
+	endorser signer.HashSigner,
 	beringHeight uint64,
 ) (*rollDPoSCtx, error) {
 	if chain == nil {
@@ -122,8 +128,10 @@ func newRollDPoSCtx(
 		)
 	}
 	var eManagerDB db.KVStore
+	var wal *consensusWAL
 	if len(consensusDBConfig.DbPath) > 0 {
 		eManagerDB = db.NewBoltDB(consensusDBConfig)
+		wal = newConsensusWAL(eManagerDB)
 	}
 	roundCalc := &roundCalculator{
 		delegatesByEpochFunc: delegatesByEpochFunc,
@@ -136,12 +144,14 @@ func newRollDPoSCtx(
 		ConsensusConfig:   cfg,
 		active:            active,
 		encodedAddr:       encodedAddr,
-		priKey:            priKey,
+		endorser:          endorser,
 		chain:             chain,
 		broadcastHandler:  broadcastHandler,
 		roundCalc:         roundCalc,
 		eManagerDB:        eManagerDB,
+		wal:               wal,
 		toleratedOvertime: toleratedOvertime,
+		history:           newRoundHistory(roundHistorySize),
 	}, nil
 }
 
@@ -151,7 +161,12 @@ func (ctx *rollDPoSCtx) Start(c context.Context) (err error) {
 		if err := ctx.eManagerDB.Start(c); err != nil {
 			return errors.Wrap(err, "Error when starting the collectionDB")
 		}
-		eManager, err = newEndorsementManager(ctx.eManagerDB)
+		if eManager, err = newEndorsementManager(ctx.eManagerDB); err != nil {
+			return err
+		}
+		if ctx.walMsgs, err = ctx.wal.Load(); err != nil {
+			return errors.Wrap(err, "failed to load consensus wal")
+		}
 	}
 	ctx.round, err = ctx.roundCalc.NewRoundWithToleration(0, ctx.BlockInterval(0), time.Now(), eManager, ctx.toleratedOvertime)
 
@@ -299,6 +314,10 @@ func (ctx *rollDPoSCtx) Prepare() error {
 		zap.String("roundStartTime", newRound.roundStartTime.String()),
 	)
 	ctx.round = newRound
+	ctx.history.Start(newRound.height, newRound.roundNum, newRound.Proposer(), newRound.StartTime())
+	if err := ctx.restoreOrResetWAL(); err != nil {
+		return err
+	}
 	consensusHeightMtc.WithLabelValues().Set(float64(ctx.round.height))
 	timeSlotMtc.WithLabelValues().Set(float64(ctx.round.roundNum))
 	return nil
@@ -375,6 +394,8 @@ func (ctx *rollDPoSCtx) NewProposalEndorsement(msg interface{}) (interface{}, er
 		if err := ctx.round.AddBlock(proposal.block); err != nil {
 			return nil, err
 		}
+		ctx.history.ProposalReceived(ctx.round.Height(), ctx.round.Number(), time.Now())
+		ctx.appendToWAL(ecm)
 		ctx.loggerWithStats().Debug("accept block proposal", log.Hex("block", blockHash))
 	} else if ctx.round.IsLocked() {
 		blockHash = ctx.round.HashOfBlockInLock()
@@ -459,8 +480,14 @@ func (ctx *rollDPoSCtx) Commit(msg interface{}) (bool, error) {
 		return false, nil
 	}
 	ctx.logger().Info("consensus reached", zap.Uint64("blockHeight", ctx.round.Height()))
+	endorsements := ctx.round.Endorsements(blkHash, []ConsensusVoteTopic{COMMIT})
+	if ctx.bls.activated(ctx.round.Height()) {
+		if endorsements, err = endorsement.AggregateEndorsements(endorsements); err != nil {
+			return false, errors.Wrap(err, "failed to aggregate endorsements")
+		}
+	}
 	if err := pendingBlock.Finalize(
-		ctx.round.Endorsements(blkHash, []ConsensusVoteTopic{COMMIT}),
+		endorsements,
 		ctx.round.StartTime().Add(
 			ctx.AcceptBlockTTL(ctx.round.height)+ctx.AcceptProposalEndorsementTTL(ctx.round.height)+ctx.AcceptLockEndorsementTTL(ctx.round.height),
 		),
@@ -477,6 +504,7 @@ func (ctx *rollDPoSCtx) Commit(msg interface{}) (bool, error) {
 	default:
 		return false, errors.Wrap(err, "error when committing a block")
 	}
+	ctx.history.Committed(ctx.round.Height(), ctx.round.Number())
 	// Broadcast the committed block to the network
 	if blkProto := pendingBlock.ConvertToBlockPb(); blkProto != nil {
 		if err := ctx.broadcastHandler(blkProto); err != nil {
@@ -557,6 +585,38 @@ func (ctx *rollDPoSCtx) Height() uint64 {
 	return ctx.round.Height()
 }
 
+// LogSignedByPeer logs a message of the next height signed with the producer key by another node, which is active
+// while the node is in stand-by mode, as signed by the node
+func (ctx *rollDPoSCtx) LogSignedByPeer(msgPb *iotextypes.ConsensusMessage) {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+	if ctx.wal == nil || ctx.endorser == nil {
+		return
+	}
+	height := ctx.chain.TipHeight() + 1
+	if msgPb.GetHeight() != height {
+		return
+	}
+	msg := &EndorsedConsensusMessage{}
+	if err := msg.LoadProto(msgPb); err != nil {
+		return
+	}
+	if !ctx.isSignedByDelegate(msg.Endorsement()) || !endorsement.VerifyEndorsedDocument(msg) {
+		return
+	}
+	if ctx.wal.Height() != height {
+		if err := ctx.wal.Reset(height); err != nil {
+			ctx.logger().Error("failed to reset consensus wal", zap.Error(err))
+			return
+		}
+		// the messages loaded on start are of a lower height
+		ctx.walMsgs = nil
+	}
+	if err := ctx.wal.AppendSigned(msg); err != nil {
+		ctx.logger().Error("failed to log consensus message signed by peer", zap.Error(err))
+	}
+}
+
 func (ctx *rollDPoSCtx) Activate(active bool) {
 	ctx.mutex.Lock()
 	defer ctx.mutex.Unlock()
@@ -605,11 +665,15 @@ func (ctx *rollDPoSCtx) isDelegate() bool {
 }
 
 func (ctx *rollDPoSCtx) endorseBlockProposal(proposal *blockProposal) (*EndorsedConsensusMessage, error) {
-	en, err := endorsement.Endorse(ctx.priKey, proposal, ctx.round.StartTime())
+	if signed := ctx.signedMessage(proposal, ctx.round.StartTime()); signed != nil {
+		// the delegate has proposed in this round before it restarts
+		return signed, nil
+	}
+	en, err := endorsement.Endorse(ctx.endorser, proposal, ctx.round.StartTime())
 	if err != nil {
 		return nil, err
 	}
-	return NewEndorsedConsensusMessage(proposal.block.Height(), proposal, en), nil
+	return ctx.appendSignedToWAL(NewEndorsedConsensusMessage(proposal.block.Height(), proposal, en))
 }
 
 func (ctx *rollDPoSCtx) logger() *zap.Logger {
@@ -671,9 +735,20 @@ func (ctx *rollDPoSCtx) verifyVote(
 	}
 	blkHash := vote.BlockHash()
 	endorsement := consensusMsg.Endorsement()
+	if vote.Topic() == COMMIT && ctx.bls.activated(ctx.round.Height()) {
+		if err := ctx.bls.verifyEndorsement(vote, endorsement); err != nil {
+			return blkHash, err
+		}
+	} else if len(endorsement.BLSSignature()) != 0 {
+		return blkHash, errors.New("BLS signature of the endorsement is not expected")
+	}
 	if err := ctx.round.AddVoteEndorsement(vote, endorsement); err != nil {
 		return blkHash, err
 	}
+	if endorser, err := address.FromBytes(endorsement.Endorser().Hash()); err == nil {
+		ctx.history.Endorsed(ctx.round.Height(), ctx.round.Number(), endorser.String(), vote.Topic())
+	}
+	ctx.appendToWAL(consensusMsg)
 	ctx.loggerWithStats().Debug(
 		"verified consensus vote",
 		log.Hex("block", blkHash),
@@ -695,10 +770,98 @@ func (ctx *rollDPoSCtx) newEndorsement(
 		blkHash,
 		topic,
 	)
-	en, err := endorsement.Endorse(ctx.priKey, vote, timestamp)
+	if signed := ctx.signedMessage(vote, timestamp); signed != nil {
+		if signedVote, ok := signed.Document().(*ConsensusVote); ok && !bytes.Equal(signedVote.BlockHash(), blkHash) {
+			ctx.loggerWithStats().Warn(
+				"refuse to endorse another block in the same round",
+				log.Hex("block", blkHash),
+				log.Hex("endorsedBlock", signedVote.BlockHash()),
+				zap.Uint8("topic", uint8(topic)),
+			)
+		}
+		return signed, nil
+	}
+	var (
+		en  *endorsement.Endorsement
+		err error
+	)
+	if topic == COMMIT && ctx.bls.activated(ctx.round.Height()) {
+		en, err = ctx.bls.endorse(ctx.endorser, vote, timestamp)
+	} else {
+		en, err = endorsement.Endorse(ctx.endorser, vote, timestamp)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return NewEndorsedConsensusMessage(ctx.round.Height(), vote, en), nil
+	return ctx.appendSignedToWAL(NewEndorsedConsensusMessage(ctx.round.Height(), vote, en))
+}
+
+// restoreOrResetWAL replays the consensus wal of the height of the round once the delegate restarts, and resets the
+// wal when the round moves to a new height
+func (ctx *rollDPoSCtx) restoreOrResetWAL() error {
+	if ctx.wal == nil {
+		return nil
+	}
+	msgs := ctx.walMsgs
+	ctx.walMsgs = nil
+	if ctx.round.Height() != ctx.wal.Height() {
+		return ctx.wal.Reset(ctx.round.Height())
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	for _, msg := range msgs {
+		en := msg.Endorsement()
+		isSigned := ctx.isSignedByDelegate(en)
+		if isSigned {
+			ctx.wal.RestoreSigned(msg)
+		}
+		var err error
+		switch doc := msg.Document().(type) {
+		case *blockProposal:
+			if err = ctx.round.AddBlock(doc.block); err == nil && isSigned {
+				err = ctx.round.SetMintedBlock(doc.block)
+			}
+		case *ConsensusVote:
+			err = ctx.round.AddVoteEndorsement(doc, en)
+		}
+		if err != nil {
+			ctx.logger().Debug("failed to replay consensus message", zap.Error(err))
+		}
+	}
+	ctx.logger().Info("replayed consensus wal", zap.Int("messages", len(msgs)))
+	// drop the endorsements of the previous rounds as the round update does
+	return ctx.round.eManager.Cleanup(ctx.round.StartTime())
+}
+
+func (ctx *rollDPoSCtx) signedMessage(doc endorsement.Document, timestamp time.Time) *EndorsedConsensusMessage {
+	if ctx.wal == nil {
+		return nil
+	}
+	return ctx.wal.Signed(doc, timestamp)
+}
+
+func (ctx *rollDPoSCtx) appendSignedToWAL(msg *EndorsedConsensusMessage) (*EndorsedConsensusMessage, error) {
+	if ctx.wal == nil {
+		return msg, nil
+	}
+	if err := ctx.wal.AppendSigned(msg); err != nil {
+		return nil, errors.Wrap(err, "failed to log signed consensus message")
+	}
+	return msg, nil
+}
+
+func (ctx *rollDPoSCtx) appendToWAL(msg *EndorsedConsensusMessage) {
+	// the messages signed by the delegate are logged before they are broadcast
+	if ctx.wal == nil || ctx.isSignedByDelegate(msg.Endorsement()) {
+		return
+	}
+	if err := ctx.wal.Append(msg); err != nil {
+		ctx.logger().Error("failed to log consensus message", zap.Error(err))
+	}
+}
+
+func (ctx *rollDPoSCtx) isSignedByDelegate(en *endorsement.Endorsement) bool {
+	return ctx.endorser != nil && en.Endorser().HexString() == ctx.endorser.PublicKey().HexString()
 }
@@ -197,6 +197,15 @@ func (ctx *roundCtx) AddVoteEndorsement(
 	if !endorsement.VerifyEndorsement(vote, en) {
 		return errors.New("invalid endorsement for the vote")
 	}
+	return ctx.AddVerifiedVoteEndorsement(vote, en)
+}
+
+// AddVerifiedVoteEndorsement adds the endorsement whose signature has been verified, e.g., as a part of the aggregated
+// endorsements of a block footer
+func (ctx *roundCtx) AddVerifiedVoteEndorsement(
+	vote *ConsensusVote,
+	en *endorsement.Endorsement,
+) error {
 	blockHash := vote.BlockHash()
 	// TODO: (zhi) request for block
 	if len(blockHash) != 0 && ctx.block(blockHash) == nil {
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+)
+
+// roundHistorySize is the number of the recent rounds of which the histories are kept
+const roundHistorySize = 256
+
+var (
+	proposalLatencyMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_consensus_proposal_latency",
+			Help: "Time from the start of the round to the receipt of the proposal",
+		},
+		[]string{},
+	)
+
+	endorsementsMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_consensus_endorsements",
+			Help: "Consensus endorsements received by delegate and topic",
+		},
+		[]string{"delegate", "topic"},
+	)
+
+	missedRoundsMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_consensus_missed_rounds",
+			Help: "Consensus rounds ending without the block committed by proposer",
+		},
+		[]string{"proposer"},
+	)
+
+	viewChangesMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_consensus_view_changes",
+			Help: "Consensus rounds following a missed round of the same height",
+		},
+		[]string{},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(proposalLatencyMtc)
+	prometheus.MustRegister(endorsementsMtc)
+	prometheus.MustRegister(missedRoundsMtc)
+	prometheus.MustRegister(viewChangesMtc)
+}
+
+// roundHistory keeps the histories of the recent rounds and exports the metrics of them
+type roundHistory struct {
+	size   int
+	rounds []*scheme.RoundHistory
+	mutex  sync.RWMutex
+}
+
+func newRoundHistory(size int) *roundHistory {
+	return &roundHistory{size: size}
+}
+
+// Start starts the history of a round, and closes the history of the last round, which is missed if the round is of
+// the same height
+func (h *roundHistory) Start(height uint64, round uint32, proposer string, startTime time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	last := h.last()
+	if last != nil && last.Height == height && last.Round == round {
+		return
+	}
+	history := &scheme.RoundHistory{
+		Height:       height,
+		Round:        round,
+		Proposer:     proposer,
+		StartTime:    startTime,
+		Endorsements: map[string][]string{},
+	}
+	if last != nil && !last.Committed {
+		switch {
+		case last.Height == height:
+			last.Missed = true
+			history.ViewChange = true
+			missedRoundsMtc.WithLabelValues(last.Proposer).Inc()
+			viewChangesMtc.WithLabelValues().Inc()
+		case last.Height < height:
+			// the block is committed by sync
+			last.Committed = true
+		}
+	}
+	h.rounds = append(h.rounds, history)
+	if len(h.rounds) > h.size {
+		h.rounds = h.rounds[len(h.rounds)-h.size:]
+	}
+}
+
+// ProposalReceived records the receipt of the proposal of a round
+func (h *roundHistory) ProposalReceived(height uint64, round uint32, now time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	history := h.round(height, round)
+	if history == nil || !history.ProposalTime.IsZero() {
+		return
+	}
+	history.ProposalTime = now
+	proposalLatencyMtc.WithLabelValues().Set(float64(now.Sub(history.StartTime)))
+}
+
+// Endorsed records an endorsement of a delegate received in a round
+func (h *roundHistory) Endorsed(height uint64, round uint32, delegate string, topic ConsensusVoteTopic) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	history := h.round(height, round)
+	if history == nil {
+		return
+	}
+	name := topicName(topic)
+	for _, t := range history.Endorsements[delegate] {
+		if t == name {
+			return
+		}
+	}
+	history.Endorsements[delegate] = append(history.Endorsements[delegate], name)
+	endorsementsMtc.WithLabelValues(delegate, name).Inc()
+}
+
+// Committed records the commit of the block of a round
+func (h *roundHistory) Committed(height uint64, round uint32) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if history := h.round(height, round); history != nil {
+		history.Committed = true
+	}
+}
+
+// Histories returns the copies of the histories of at most limit recent rounds, the latest first
+func (h *roundHistory) Histories(limit uint64) []*scheme.RoundHistory {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	histories := []*scheme.RoundHistory{}
+	for i := len(h.rounds) - 1; i >= 0 && uint64(len(histories)) < limit; i-- {
+		history := *h.rounds[i]
+		history.Endorsements = make(map[string][]string, len(h.rounds[i].Endorsements))
+		for delegate, topics := range h.rounds[i].Endorsements {
+			history.Endorsements[delegate] = append([]string{}, topics...)
+		}
+		histories = append(histories, &history)
+	}
+	return histories
+}
+
+func (h *roundHistory) last() *scheme.RoundHistory {
+	if len(h.rounds) == 0 {
+		return nil
+	}
+	return h.rounds[len(h.rounds)-1]
+}
+
+func (h *roundHistory) round(height uint64, round uint32) *scheme.RoundHistory {
+	last := h.last()
+	if last == nil || last.Height != height || last.Round != round {
+		return nil
+	}
+	return last
+}
+
+func topicName(topic ConsensusVoteTopic) string {
+	switch topic {
+	case PROPOSAL:
+		return "proposal"
+	case LOCK:
+		return "lock"
+	case COMMIT:
+		return "commit"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestRoundHistory(t *testing.T) {
+	require := require.New(t)
+	h := newRoundHistory(2)
+	require.Empty(h.Histories(10))
+
+	start := time.Now()
+	proposer := identityset.Address(1).String()
+	delegate := identityset.Address(2).String()
+	h.Start(5, 0, proposer, start)
+	// the same round starts once
+	h.Start(5, 0, proposer, start)
+	h.ProposalReceived(5, 0, start.Add(time.Second))
+	h.ProposalReceived(5, 0, start.Add(2*time.Second))
+	h.Endorsed(5, 0, delegate, PROPOSAL)
+	h.Endorsed(5, 0, delegate, PROPOSAL)
+	h.Endorsed(5, 0, delegate, LOCK)
+	// the messages of other rounds are ignored
+	h.Endorsed(5, 1, proposer, PROPOSAL)
+	h.ProposalReceived(6, 0, start)
+	histories := h.Histories(10)
+	require.Len(histories, 1)
+	require.Equal(uint64(5), histories[0].Height)
+	require.Equal(proposer, histories[0].Proposer)
+	require.Equal(start.Add(time.Second), histories[0].ProposalTime)
+	require.Equal(map[string][]string{delegate: {"proposal", "lock"}}, histories[0].Endorsements)
+	require.False(histories[0].Committed)
+	require.False(histories[0].Missed)
+	// the histories returned are copies
+	histories[0].Endorsements[delegate] = nil
+	require.Len(h.Histories(1)[0].Endorsements[delegate], 2)
+
+	// the round of the same height follows a missed round
+	h.Start(5, 1, delegate, start.Add(10*time.Second))
+	h.Committed(5, 1)
+	histories = h.Histories(10)
+	require.Len(histories, 2)
+	require.Equal(uint32(1), histories[0].Round)
+	require.True(histories[0].ViewChange)
+	require.True(histories[0].Committed)
+	require.True(histories[1].Missed)
+	require.False(histories[1].Committed)
+
+	// the round not committed by the delegate is committed if the next round is of a higher height
+	h.Start(6, 0, proposer, start.Add(20*time.Second))
+	h.Start(7, 0, proposer, start.Add(30*time.Second))
+	histories = h.Histories(10)
+	require.Len(histories, 2)
+	require.Equal(uint64(7), histories[0].Height)
+	require.True(histories[1].Committed)
+	require.False(histories[1].Missed)
+	require.False(histories[0].ViewChange)
+	require.Len(h.Histories(1), 1)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	walNS = "wal"
+)
+
+var (
+	walHeightKey = []byte("walHeight")
+)
+
+// consensusWAL is the write-ahead log of the consensus messages at the height of the current round. The proposals and
+// endorsements the delegate signs are logged before they are broadcast, and the ones it receives once they are
+// accepted, so that a restarted delegate replays them to rejoin the round without losing the endorsements or signing
+// a message conflicting with the one it has signed.
+type consensusWAL struct {
+	kv     db.KVStore
+	height uint64
+	size   uint64
+	signed map[string]*EndorsedConsensusMessage
+	mutex  sync.Mutex
+}
+
+func newConsensusWAL(kv db.KVStore) *consensusWAL {
+	return &consensusWAL{
+		kv:     kv,
+		signed: map[string]*EndorsedConsensusMessage{},
+	}
+}
+
+// Load reads the height and the messages of the log
+func (w *consensusWAL) Load() ([]*EndorsedConsensusMessage, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	value, err := w.kv.Get(eManagerNS, walHeightKey)
+	switch errors.Cause(err) {
+	case nil:
+		w.height = byteutil.BytesToUint64BigEndian(value)
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return nil, nil
+	default:
+		return nil, err
+	}
+	keys, values, err := w.kv.Filter(walNS, func(k, v []byte) bool { return true }, nil, nil)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return nil, nil
+	default:
+		return nil, err
+	}
+	msgs := make([]*EndorsedConsensusMessage, 0, len(values))
+	for i, value := range values {
+		msgPb := &iotextypes.ConsensusMessage{}
+		if err := proto.Unmarshal(value, msgPb); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal message %x of consensus wal", keys[i])
+		}
+		msg := &EndorsedConsensusMessage{}
+		if err := msg.LoadProto(msgPb); err != nil {
+			return nil, errors.Wrapf(err, "failed to load message %x of consensus wal", keys[i])
+		}
+		msgs = append(msgs, msg)
+	}
+	if n := len(keys); n > 0 {
+		w.size = byteutil.BytesToUint64BigEndian(keys[n-1]) + 1
+	}
+	return msgs, nil
+}
+
+// Height returns the height of the messages in the log
+func (w *consensusWAL) Height() uint64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.height
+}
+
+// Reset truncates the log and starts the log of a new height
+func (w *consensusWAL) Reset(height uint64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	b := batch.NewBatch()
+	for i := uint64(0); i < w.size; i++ {
+		b.Delete(walNS, byteutil.Uint64ToBytesBigEndian(i), "failed to delete consensus wal message")
+	}
+	b.Put(eManagerNS, walHeightKey, byteutil.Uint64ToBytesBigEndian(height), "failed to put consensus wal height")
+	if err := w.kv.WriteBatch(b); err != nil {
+		return err
+	}
+	w.height = height
+	w.size = 0
+	w.signed = map[string]*EndorsedConsensusMessage{}
+	return nil
+}
+
+// Append logs a message received at the height of the log, the messages of other heights are ignored
+func (w *consensusWAL) Append(msg *EndorsedConsensusMessage) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.append(msg)
+}
+
+// AppendSigned logs a message signed by the delegate, which is returned by Signed thereafter
+func (w *consensusWAL) AppendSigned(msg *EndorsedConsensusMessage) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.append(msg); err != nil {
+		return err
+	}
+	w.signed[signedKey(msg.Document())] = msg
+	return nil
+}
+
+// RestoreSigned restores a replayed message signed by the delegate
+func (w *consensusWAL) RestoreSigned(msg *EndorsedConsensusMessage) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.signed[signedKey(msg.Document())] = msg
+}
+
+// Signed returns the message of the same kind as the document the delegate has signed at the timestamp
+func (w *consensusWAL) Signed(doc endorsement.Document, timestamp time.Time) *EndorsedConsensusMessage {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	msg, exists := w.signed[signedKey(doc)]
+	if !exists || !msg.Endorsement().Timestamp().Equal(timestamp) {
+		return nil
+	}
+	return msg
+}
+
+func (w *consensusWAL) append(msg *EndorsedConsensusMessage) error {
+	if msg.Height() != w.height {
+		return nil
+	}
+	msgPb, err := msg.Proto()
+	if err != nil {
+		return err
+	}
+	value, err := proto.Marshal(msgPb)
+	if err != nil {
+		return err
+	}
+	if err := w.kv.Put(walNS, byteutil.Uint64ToBytesBigEndian(w.size), value); err != nil {
+		return errors.Wrap(err, "failed to put consensus wal message")
+	}
+	w.size++
+	return nil
+}
+
+// signedKey returns the key of a signed document, a delegate signs one proposal and one vote of each topic per round
+func signedKey(doc endorsement.Document) string {
+	if vote, ok := doc.(*ConsensusVote); ok {
+		return fmt.Sprintf("vote-%d", vote.Topic())
+	}
+	return "proposal"
+}
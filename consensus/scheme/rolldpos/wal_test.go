@@ -0,0 +1,275 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/consensus/consensusfsm"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestConsensusWAL(t *testing.T) {
+	require := require.New(t)
+	testPath, err := testutil.PathOfTempFile("wal")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testPath)
+	cfg := config.Default.DB
+	cfg.DbPath = testPath
+	ctx := context.Background()
+
+	kv := db.NewBoltDB(cfg)
+	require.NoError(kv.Start(ctx))
+	wal := newConsensusWAL(kv)
+	msgs, err := wal.Load()
+	require.NoError(err)
+	require.Empty(msgs)
+	require.NoError(wal.Reset(5))
+
+	now := time.Now()
+	priKey := identityset.PrivateKey(1)
+	endorse := func(height uint64, hash string, topic ConsensusVoteTopic) *EndorsedConsensusMessage {
+		vote := NewConsensusVote([]byte(hash), topic)
+		en, err := endorsement.Endorse(priKey, vote, now)
+		require.NoError(err)
+		return NewEndorsedConsensusMessage(height, vote, en)
+	}
+	require.NoError(wal.Append(endorse(5, "a", PROPOSAL)))
+	// the message of another height is not logged
+	require.NoError(wal.Append(endorse(6, "b", PROPOSAL)))
+	signed := endorse(5, "a", LOCK)
+	require.NoError(wal.AppendSigned(signed))
+	require.Equal(signed, wal.Signed(NewConsensusVote([]byte("c"), LOCK), now))
+	require.Nil(wal.Signed(NewConsensusVote([]byte("a"), LOCK), now.Add(time.Second)))
+	require.Nil(wal.Signed(NewConsensusVote([]byte("a"), COMMIT), now))
+	require.NoError(kv.Stop(ctx))
+
+	// the log survives the restart
+	kv = db.NewBoltDB(cfg)
+	require.NoError(kv.Start(ctx))
+	defer kv.Stop(ctx)
+	wal = newConsensusWAL(kv)
+	msgs, err = wal.Load()
+	require.NoError(err)
+	require.Equal(uint64(5), wal.Height())
+	require.Equal(2, len(msgs))
+	for i, topic := range []ConsensusVoteTopic{PROPOSAL, LOCK} {
+		vote, ok := msgs[i].Document().(*ConsensusVote)
+		require.True(ok)
+		require.Equal(topic, vote.Topic())
+		require.Equal([]byte("a"), vote.BlockHash())
+		require.Equal(uint64(5), msgs[i].Height())
+	}
+	require.NoError(wal.Append(endorse(5, "a", COMMIT)))
+	msgs, err = newConsensusWAL(kv).Load()
+	require.NoError(err)
+	require.Equal(3, len(msgs))
+
+	// reset truncates the log
+	require.NoError(wal.Reset(6))
+	require.Nil(wal.Signed(NewConsensusVote([]byte("a"), LOCK), now))
+	wal = newConsensusWAL(kv)
+	msgs, err = wal.Load()
+	require.NoError(err)
+	require.Empty(msgs)
+	require.Equal(uint64(6), wal.Height())
+}
+
+func TestRollDPoSCtxReplayWAL(t *testing.T) {
+	require := require.New(t)
+	testPath, err := testutil.PathOfTempFile("consensus")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testPath)
+	dbConfig := config.Default.DB
+	dbConfig.DbPath = testPath
+	cfg := config.Default
+	cfg.Genesis.BlockInterval = time.Second * 20
+	b, sf, _, rp, pp := makeChain(t)
+	delegatesByEpoch := func(epochnum uint64) ([]string, error) {
+		re := protocol.NewRegistry()
+		if err := rp.Register(re); err != nil {
+			return nil, err
+		}
+		tipHeight := b.TipHeight()
+		ctx := protocol.WithBlockchainCtx(
+			protocol.WithRegistry(context.Background(), re),
+			protocol.BlockchainCtx{
+				Genesis: config.Default.Genesis,
+				Tip: protocol.TipInfo{
+					Height: tipHeight,
+				},
+			},
+		)
+		tipEpochNum := rp.GetEpochNum(tipHeight)
+		var candidatesList state.CandidateList
+		var err error
+		switch epochnum {
+		case tipEpochNum:
+			candidatesList, err = pp.Delegates(ctx, sf)
+		case tipEpochNum + 1:
+			candidatesList, err = pp.NextDelegates(ctx, sf)
+		default:
+			err = errors.Errorf("invalid epoch number %d compared to tip epoch number %d", epochnum, tipEpochNum)
+		}
+		if err != nil {
+			return nil, err
+		}
+		var addrs []string
+		for _, cand := range candidatesList {
+			addrs = append(addrs, cand.Address)
+		}
+		return addrs, nil
+	}
+	newCtx := func(encodedAddr string, i int) *rollDPoSCtx {
+		rctx, err := newRollDPoSCtx(
+			consensusfsm.NewConsensusConfig(cfg),
+			dbConfig,
+			true,
+			time.Second,
+			true,
+			b,
+			rp,
+			nil,
+			delegatesByEpoch,
+			encodedAddr,
+			identityset.PrivateKey(i),
+			config.Default.Genesis.BeringBlockHeight,
+		)
+		require.NoError(err)
+		require.NoError(rctx.Start(context.Background()))
+		require.NoError(rctx.Prepare())
+		return rctx
+	}
+
+	// start once to find out the proposer of the round
+	rctx := newCtx("", 0)
+	proposer := rctx.round.Proposer()
+	require.NoError(rctx.Stop(context.Background()))
+	proposerIndex := -1
+	for i := 0; i < identityset.Size(); i++ {
+		if identityset.Address(i).String() == proposer {
+			proposerIndex = i
+			break
+		}
+	}
+	require.NotEqual(-1, proposerIndex)
+
+	rctx = newCtx(proposer, proposerIndex)
+	res, err := rctx.Proposal()
+	require.NoError(err)
+	proposal, ok := res.(*EndorsedConsensusMessage)
+	require.True(ok)
+	blkHash, err := proposal.Document().Hash()
+	require.NoError(err)
+	height := rctx.round.Height()
+	startTime := rctx.round.StartTime()
+	blk := proposal.Document().(*blockProposal).block
+	// the proposal is accepted once it is received
+	_, err = rctx.NewProposalEndorsement(proposal)
+	require.NoError(err)
+	minted := blk.HashBlock()
+	lock, err := rctx.newEndorsement(minted[:], LOCK, startTime.Add(time.Second))
+	require.NoError(err)
+	require.NoError(rctx.Stop(context.Background()))
+
+	// the restarted delegate replays the wal
+	rctx = newCtx(proposer, proposerIndex)
+	defer rctx.Stop(context.Background())
+	require.Equal(height, rctx.round.Height())
+	require.Equal(startTime, rctx.round.StartTime())
+	require.NotNil(rctx.round.Block(minted[:]))
+	require.NotNil(rctx.round.CachedMintedBlock())
+	require.Equal(minted, rctx.round.CachedMintedBlock().HashBlock())
+	res, err = rctx.Proposal()
+	require.NoError(err)
+	replayed, ok := res.(*EndorsedConsensusMessage)
+	require.True(ok)
+	replayedHash, err := replayed.Document().Hash()
+	require.NoError(err)
+	require.Equal(blkHash, replayedHash)
+	require.Equal(proposal.Endorsement().Signature(), replayed.Endorsement().Signature())
+
+	// no conflicting endorsement is signed in the same round
+	en, err := rctx.newEndorsement([]byte("another block"), LOCK, startTime.Add(time.Second))
+	require.NoError(err)
+	vote, ok := en.Document().(*ConsensusVote)
+	require.True(ok)
+	require.Equal(minted[:], vote.BlockHash())
+	require.Equal(lock.Endorsement().Signature(), en.Endorsement().Signature())
+}
+
+func TestRollDPoSCtxLogSignedByPeer(t *testing.T) {
+	require := require.New(t)
+	testPath, err := testutil.PathOfTempFile("consensus")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testPath)
+	dbConfig := config.Default.DB
+	dbConfig.DbPath = testPath
+	cfg := config.Default
+	cfg.Genesis.BlockInterval = time.Second * 20
+	b, _, _, rp, _ := makeChain(t)
+	rctx, err := newRollDPoSCtx(
+		consensusfsm.NewConsensusConfig(cfg),
+		dbConfig,
+		false,
+		time.Second,
+		true,
+		b,
+		rp,
+		nil,
+		func(uint64) ([]string, error) { return nil, nil },
+		identityset.Address(1).String(),
+		identityset.PrivateKey(1),
+		config.Default.Genesis.BeringBlockHeight,
+	)
+	require.NoError(err)
+	require.NoError(rctx.Start(context.Background()))
+	defer rctx.Stop(context.Background())
+
+	now := time.Now()
+	height := b.TipHeight() + 1
+	endorse := func(i int, height uint64, hash string) *EndorsedConsensusMessage {
+		vote := NewConsensusVote([]byte(hash), LOCK)
+		en, err := endorsement.Endorse(identityset.PrivateKey(i), vote, now)
+		require.NoError(err)
+		return NewEndorsedConsensusMessage(height, vote, en)
+	}
+	log := func(msg *EndorsedConsensusMessage) {
+		msgPb, err := msg.Proto()
+		require.NoError(err)
+		rctx.LogSignedByPeer(msgPb)
+	}
+	// the messages of other delegates or other heights are not logged
+	log(endorse(2, height, "a"))
+	log(endorse(1, height+1, "a"))
+	require.Nil(rctx.signedMessage(NewConsensusVote([]byte("b"), LOCK), now))
+
+	// the message signed by the peer sharing the key is logged as signed
+	signed := endorse(1, height, "a")
+	log(signed)
+	require.Equal(height, rctx.wal.Height())
+	res := rctx.signedMessage(NewConsensusVote([]byte("b"), LOCK), now)
+	require.NotNil(res)
+	require.Equal(signed.Endorsement().Signature(), res.Endorsement().Signature())
+
+	// the log survives the restart
+	require.NoError(rctx.Stop(context.Background()))
+	require.NoError(rctx.Start(context.Background()))
+	require.Equal(1, len(rctx.walMsgs))
+	require.Equal(signed.Endorsement().Signature(), rctx.walMsgs[0].Endorsement().Signature())
+}
@@ -7,13 +7,23 @@
 package scheme
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 )
 
+var (
+	// ErrEvidenceNotSupported indicates the consensus scheme does not detect equivocation
+	ErrEvidenceNotSupported = errors.New("equivocation detection is not supported by the consensus scheme")
+	// ErrRoundHistoryNotSupported indicates the consensus scheme does not keep the histories of the rounds
+	ErrRoundHistoryNotSupported = errors.New("round history is not supported by the consensus scheme")
+)
+
 // CreateBlockCB defines the callback to create a new block
 type CreateBlockCB func() (*block.Block, error)
 
@@ -48,3 +58,40 @@ type ConsensusMetrics struct {
 	LatestDelegates     []string
 	LatestBlockProducer string
 }
+
+// Evidence is the proof that a delegate signed two conflicting consensus messages of the same kind in the same round
+type Evidence struct {
+	Height    uint64
+	Endorser  string
+	Timestamp time.Time
+	Messages  [2]*iotextypes.ConsensusMessage
+}
+
+// EvidenceReader reads the evidences of equivocation detected by the consensus scheme
+type EvidenceReader interface {
+	// Evidences returns at most limit evidences from height on
+	Evidences(height uint64, limit uint64) ([]*Evidence, error)
+}
+
+// RoundHistory is what the node observes in a consensus round
+type RoundHistory struct {
+	Height    uint64
+	Round     uint32
+	Proposer  string
+	StartTime time.Time
+	// ProposalTime is when the proposal is received, zero if it is not received
+	ProposalTime time.Time
+	// Endorsements are the topics of the endorsements received in the round by the delegates
+	Endorsements map[string][]string
+	Committed    bool
+	// Missed indicates the round ended without the block committed
+	Missed bool
+	// ViewChange indicates the round follows a missed round of the same height
+	ViewChange bool
+}
+
+// RoundHistoryReader reads the histories of the recent consensus rounds
+type RoundHistoryReader interface {
+	// RoundHistories returns the histories of at most limit recent rounds, the latest first
+	RoundHistories(limit uint64) ([]*RoundHistory, error)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package bls implements the BLS signatures over the BLS12-381 curve with blst, of which the signatures of different
+// signers on different hashes can be aggregated into one. The signatures are in G1 and the public keys are in G2, both
+// compressed, and the hashes are mapped to G1 by the standard hash-to-curve of the ciphersuite.
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+const (
+	// SignatureLength is the length of a signature or an aggregated signature
+	SignatureLength = 48
+	// PublicKeyLength is the length of a public key
+	PublicKeyLength = 96
+)
+
+var (
+	// ErrInvalidKey is the error that the key is invalid
+	ErrInvalidKey = errors.New("invalid BLS key")
+	// ErrInvalidSignature is the error that the signature is invalid
+	ErrInvalidSignature = errors.New("invalid BLS signature")
+
+	// dst is the domain separation tag of the proof of possession ciphersuite, so that the signatures of the same hash
+	// could be aggregated. The public keys are registered in the genesis, which stands for their proofs of possession.
+	dst = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_")
+)
+
+type (
+	// PrivateKey is a BLS private key
+	PrivateKey struct {
+		k *blst.SecretKey
+	}
+
+	// PublicKey is a BLS public key
+	PublicKey struct {
+		p *blst.P2Affine
+	}
+)
+
+// GenerateKey generates a random private key
+func GenerateKey() (*PrivateKey, error) {
+	ikm := make([]byte, 32)
+	if _, err := rand.Read(ikm); err != nil {
+		return nil, err
+	}
+	return &PrivateKey{k: blst.KeyGen(ikm)}, nil
+}
+
+// HexStringToPrivateKey decodes a private key in hex string
+func HexStringToPrivateKey(s string) (*PrivateKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidKey, err.Error())
+	}
+	k := new(blst.SecretKey).Deserialize(b)
+	if k == nil || !k.Valid() {
+		return nil, errors.Wrap(ErrInvalidKey, "private key out of range")
+	}
+	return &PrivateKey{k: k}, nil
+}
+
+// HexString returns the private key in hex string
+func (sk *PrivateKey) HexString() string {
+	return hex.EncodeToString(sk.k.Serialize())
+}
+
+// PublicKey returns the public key of the private key
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{p: new(blst.P2Affine).From(sk.k)}
+}
+
+// Sign signs the hash
+func (sk *PrivateKey) Sign(h []byte) []byte {
+	return new(blst.P1Affine).Sign(sk.k, h, dst).Compress()
+}
+
+// BytesToPublicKey decodes a public key
+func BytesToPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeyLength {
+		return nil, errors.Wrapf(ErrInvalidKey, "invalid length %d", len(b))
+	}
+	p := new(blst.P2Affine).Uncompress(b)
+	if p == nil || !p.KeyValidate() {
+		return nil, errors.Wrap(ErrInvalidKey, "not a point of G2 or infinity")
+	}
+	return &PublicKey{p: p}, nil
+}
+
+// HexStringToPublicKey decodes a public key in hex string
+func HexStringToPublicKey(s string) (*PublicKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidKey, err.Error())
+	}
+	return BytesToPublicKey(b)
+}
+
+// Bytes returns the public key in bytes
+func (pk *PublicKey) Bytes() []byte {
+	return pk.p.Compress()
+}
+
+// HexString returns the public key in hex string
+func (pk *PublicKey) HexString() string {
+	return hex.EncodeToString(pk.Bytes())
+}
+
+// Verify checks the signature of the hash
+func (pk *PublicKey) Verify(h []byte, sig []byte) bool {
+	return VerifyAggregate([]*PublicKey{pk}, [][]byte{h}, sig)
+}
+
+// Aggregate aggregates the signatures into one
+func Aggregate(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errors.Wrap(ErrInvalidSignature, "no signature to aggregate")
+	}
+	agg := new(blst.P1Aggregate)
+	for _, sig := range sigs {
+		p, err := unmarshalSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+		agg.Add(p, false)
+	}
+	return agg.ToAffine().Compress(), nil
+}
+
+// VerifyAggregate checks the aggregated signature of the hashes, the i-th of which is signed by the i-th public key.
+// The public keys should be known to be of their owners, which is not checked against the rogue key attack
+func VerifyAggregate(pks []*PublicKey, hashes [][]byte, sig []byte) bool {
+	if len(pks) == 0 || len(pks) != len(hashes) {
+		return false
+	}
+	p, err := unmarshalSignature(sig)
+	if err != nil {
+		return false
+	}
+	points := make([]*blst.P2Affine, 0, len(pks))
+	msgs := make([]blst.Message, 0, len(hashes))
+	for i, pk := range pks {
+		points = append(points, pk.p)
+		msgs = append(msgs, hashes[i])
+	}
+	// the public keys are validated once decoded
+	return p.AggregateVerify(false, points, false, msgs, dst)
+}
+
+// unmarshalSignature decodes a signature, which has to be a point of G1
+func unmarshalSignature(sig []byte) (*blst.P1Affine, error) {
+	if len(sig) != SignatureLength {
+		return nil, errors.Wrapf(ErrInvalidSignature, "invalid length %d", len(sig))
+	}
+	p := new(blst.P1Affine).Uncompress(sig)
+	if p == nil || !p.SigValidate(false) {
+		return nil, errors.Wrap(ErrInvalidSignature, "not a point of G1")
+	}
+	return p, nil
+}
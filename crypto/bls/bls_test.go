@@ -0,0 +1,73 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package bls
+
+import (
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	require := require.New(t)
+	sk, err := GenerateKey()
+	require.NoError(err)
+	loaded, err := HexStringToPrivateKey(sk.HexString())
+	require.NoError(err)
+	require.Equal(sk.PublicKey().Bytes(), loaded.PublicKey().Bytes())
+	pk, err := HexStringToPublicKey(sk.PublicKey().HexString())
+	require.NoError(err)
+
+	h := hash.Hash256b([]byte("block"))
+	sig := sk.Sign(h[:])
+	require.Len(sig, SignatureLength)
+	require.True(pk.Verify(h[:], sig))
+	other := hash.Hash256b([]byte("other block"))
+	require.False(pk.Verify(other[:], sig))
+	require.False(pk.Verify(h[:], sig[1:]))
+
+	_, err = HexStringToPrivateKey("00")
+	require.Equal(ErrInvalidKey, errors.Cause(err))
+	_, err = BytesToPublicKey(make([]byte, PublicKeyLength))
+	require.Equal(ErrInvalidKey, errors.Cause(err))
+}
+
+func TestAggregate(t *testing.T) {
+	require := require.New(t)
+	var (
+		pks    []*PublicKey
+		hashes [][]byte
+		sigs   [][]byte
+	)
+	for i := 0; i < 4; i++ {
+		sk, err := GenerateKey()
+		require.NoError(err)
+		h := hash.Hash256b([]byte{byte(i)})
+		pks = append(pks, sk.PublicKey())
+		hashes = append(hashes, h[:])
+		sigs = append(sigs, sk.Sign(h[:]))
+	}
+	agg, err := Aggregate(sigs)
+	require.NoError(err)
+	require.Len(agg, SignatureLength)
+	require.True(VerifyAggregate(pks, hashes, agg))
+	// the signers and the hashes should match in order
+	require.False(VerifyAggregate([]*PublicKey{pks[1], pks[0], pks[2], pks[3]}, hashes, agg))
+	// a signature missing from the aggregate fails the verification
+	partial, err := Aggregate(sigs[:3])
+	require.NoError(err)
+	require.False(VerifyAggregate(pks, hashes, partial))
+	require.True(VerifyAggregate(pks[:3], hashes[:3], partial))
+
+	_, err = Aggregate(nil)
+	require.Error(err)
+	_, err = Aggregate([][]byte{sigs[0], []byte("invalid")})
+	require.Error(err)
+	require.False(VerifyAggregate(nil, nil, agg))
+}
@@ -8,6 +8,7 @@ package crypto
 
 import (
 	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
 )
 
 // Merkle tree struct
@@ -79,3 +80,48 @@ func (mk *Merkle) HashTree() hash.Hash256 {
 	mk.root = merkle[0]
 	return mk.root
 }
+
+// Proof returns the merkle path of the leaf at the index, i.e., the siblings from the leaf up to the children of the
+// root. The path of a single leaf is empty, since the leaf is the root.
+func (mk *Merkle) Proof(index int) ([]hash.Hash256, error) {
+	if index < 0 || index >= mk.size {
+		return nil, errors.Errorf("leaf index %d out of range [0, %d)", index, mk.size)
+	}
+	if mk.size == 1 {
+		return nil, nil
+	}
+	level := make([]hash.Hash256, len(mk.leaf))
+	copy(level, mk.leaf)
+	var path []hash.Hash256
+	for len(level) > 1 {
+		// the last hash is copied if the level has an odd number of hashes, as HashTree does
+		if len(level)&1 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		path = append(path, level[index^1])
+		next := make([]hash.Hash256, len(level)>>1)
+		for i := range next {
+			next[i] = hash.Hash256b(append(level[i<<1][:], level[i<<1+1][:]...))
+		}
+		level = next
+		index >>= 1
+	}
+	return path, nil
+}
+
+// VerifyMerkleProof checks the merkle path of the leaf at the index against the root
+func VerifyMerkleProof(root, leaf hash.Hash256, index int, path []hash.Hash256) bool {
+	if index < 0 {
+		return false
+	}
+	h := leaf
+	for _, sibling := range path {
+		if index&1 == 0 {
+			h = hash.Hash256b(append(h[:], sibling[:]...))
+		} else {
+			h = hash.Hash256b(append(sibling[:], h[:]...))
+		}
+		index >>= 1
+	}
+	return index == 0 && h == root
+}
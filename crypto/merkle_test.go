@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/go-pkgs/hash"
 )
@@ -41,3 +42,29 @@ func TestMerkleTree(t *testing.T) {
 	rootHashHex := hex.EncodeToString(rootHash[:])
 	assert.Equal(t, "4de26a6d1d6618f7bfeb3d168e37ef645db94c2d558bf8c3546d1311877ddffa", rootHashHex)
 }
+
+func TestMerkleProof(t *testing.T) {
+	require := require.New(t)
+	for size := 1; size <= 9; size++ {
+		leaves := make([]hash.Hash256, size)
+		for i := range leaves {
+			leaves[i] = hash.Hash256b([]byte{byte(i)})
+		}
+		m := NewMerkleTree(leaves)
+		root := m.HashTree()
+		for i, leaf := range leaves {
+			path, err := m.Proof(i)
+			require.NoError(err)
+			require.True(VerifyMerkleProof(root, leaf, i, path))
+			if i^1 < size {
+				require.False(VerifyMerkleProof(root, leaf, i^1, path))
+				require.False(VerifyMerkleProof(root, hash.ZeroHash256, i, path))
+				require.False(VerifyMerkleProof(root, leaf, i, path[1:]))
+			}
+		}
+		_, err := m.Proof(size + 1)
+		require.Error(err)
+		_, err = m.Proof(-1)
+		require.Error(err)
+	}
+}
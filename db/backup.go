@@ -0,0 +1,32 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Backuper takes a consistent backup of a store while it keeps serving the reads and writes
+type Backuper interface {
+	// Backup writes a consistent copy of the store into the directory, under the base name of the store
+	Backup(context.Context, string) error
+}
+
+// backupPath returns the path of the backup of the store at path in the directory, which must not exist yet
+func backupPath(dir, path string) (string, error) {
+	target := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		return "", errors.Wrapf(ErrIO, "backup %s already exists", target)
+	} else if !os.IsNotExist(err) {
+		return "", errors.Wrap(ErrIO, err.Error())
+	}
+	return target, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestBackup(t *testing.T) {
+	for _, backend := range []string{config.BoltBackend, config.BadgerBackend, config.PebbleBackend} {
+		t.Run(backend, func(t *testing.T) {
+			require := require.New(t)
+			ctx := context.Background()
+			root, err := ioutil.TempDir(os.TempDir(), "test-backup")
+			require.NoError(err)
+			defer testutil.CleanupPath(t, root)
+			backupDir := filepath.Join(root, "backup")
+			require.NoError(os.Mkdir(backupDir, 0700))
+
+			cfg := config.Default.DB
+			cfg.DbPath = filepath.Join(root, "test.db")
+			cfg.Backend = backend
+			kv, err := NewKVStore(cfg)
+			require.NoError(err)
+			require.NoError(kv.Start(ctx))
+			require.NoError(kv.Put(bucket1, testK1[0], testV1[0]))
+			require.NoError(kv.(Backuper).Backup(ctx, backupDir))
+			// the writes after the backup are not in the backup
+			require.NoError(kv.Put(bucket1, testK1[1], testV1[1]))
+			// the existing backup is not overwritten
+			require.Error(kv.(Backuper).Backup(ctx, backupDir))
+			require.NoError(kv.Stop(ctx))
+
+			cfg.DbPath = filepath.Join(backupDir, "test.db")
+			kv, err = NewKVStore(cfg)
+			require.NoError(err)
+			require.NoError(kv.Start(ctx))
+			defer func() {
+				require.NoError(kv.Stop(ctx))
+			}()
+			v, err := kv.Get(bucket1, testK1[0])
+			require.NoError(err)
+			require.Equal(testV1[0], v)
+			_, err = kv.Get(bucket1, testK1[1])
+			require.Equal(ErrNotExist, errors.Cause(err))
+		})
+	}
+}
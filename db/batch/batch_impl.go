@@ -57,7 +57,7 @@ func (b *baseKVStoreBatch) Unlock() {
 // ClearAndUnlock clears the write queue and unlocks the batch
 func (b *baseKVStoreBatch) ClearAndUnlock() {
 	defer b.mutex.Unlock()
-	b.writeQueue = nil
+	b.clearQueue()
 
 	b.fillLock.Lock()
 	defer b.fillLock.Unlock()
@@ -116,7 +116,7 @@ func (b *baseKVStoreBatch) SerializeQueue(serialize WriteInfoSerialize, filter W
 func (b *baseKVStoreBatch) Clear() {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
-	b.writeQueue = nil
+	b.clearQueue()
 
 	b.fillLock.Lock()
 	defer b.fillLock.Unlock()
@@ -176,6 +176,14 @@ func (b *baseKVStoreBatch) batch(op WriteType, namespace string, key, value []by
 		})
 }
 
+// clearQueue empties the write queue and keeps its capacity for reuse
+func (b *baseKVStoreBatch) clearQueue() {
+	for i := range b.writeQueue {
+		b.writeQueue[i] = nil
+	}
+	b.writeQueue = b.writeQueue[:0]
+}
+
 // truncate the write queue
 func (b *baseKVStoreBatch) truncate(size int) {
 	b.writeQueue = b.writeQueue[:size]
@@ -226,12 +234,7 @@ func (cb *cachedBatch) ClearAndUnlock() {
 	defer cb.lock.Unlock()
 	cb.KVStoreCache.Clear()
 	cb.kvStoreBatch.Clear()
-	// clear all saved snapshots
-	cb.tag = 0
-	cb.batchShots = nil
-	cb.cacheShots = nil
-	cb.batchShots = make([]int, 0)
-	cb.cacheShots = make([]KVStoreCache, 0)
+	cb.clearSnapshots()
 }
 
 // Put inserts a <key, value> record
@@ -258,12 +261,7 @@ func (cb *cachedBatch) Clear() {
 	defer cb.lock.Unlock()
 	cb.KVStoreCache.Clear()
 	cb.kvStoreBatch.Clear()
-	// clear all saved snapshots
-	cb.tag = 0
-	cb.batchShots = nil
-	cb.cacheShots = nil
-	cb.batchShots = make([]int, 0)
-	cb.cacheShots = make([]KVStoreCache, 0)
+	cb.clearSnapshots()
 }
 
 // Get retrieves a record
@@ -302,6 +300,16 @@ func (cb *cachedBatch) Revert(snapshot int) error {
 	return nil
 }
 
+// clearSnapshots clears all saved snapshots and keeps the capacity for reuse
+func (cb *cachedBatch) clearSnapshots() {
+	cb.tag = 0
+	for i := range cb.cacheShots {
+		cb.cacheShots[i] = nil
+	}
+	cb.batchShots = cb.batchShots[:0]
+	cb.cacheShots = cb.cacheShots[:0]
+}
+
 func (cb *cachedBatch) CheckFillPercent(ns string) (float64, bool) {
 	return cb.kvStoreBatch.CheckFillPercent(ns)
 }
@@ -78,10 +78,13 @@ func (c *kvCache) Evict(k hash.Hash160) {
 
 // Clear clear the cache
 func (c *kvCache) Clear() {
-	c.cache = nil
-	c.deleted = nil
-	c.cache = make(map[hash.Hash160][]byte)
-	c.deleted = make(map[hash.Hash160]struct{})
+	// the maps are cleared in place, so that a reused cache keeps their buckets
+	for k := range c.cache {
+		delete(c.cache, k)
+	}
+	for k := range c.deleted {
+		delete(c.deleted, k)
+	}
 }
 
 // Clone clones the cache
@@ -0,0 +1,355 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+// maxPendingLoads is the number of the pending writes when a backup is loaded into a new DB
+const maxPendingLoads = 256
+
+// BadgerDB is KVStore implementation based on badger DB, which takes the concurrent writes
+type BadgerDB struct {
+	db     *badger.DB
+	path   string
+	config config.DB
+	obs    *opObserver
+}
+
+// NewBadgerDB instantiates a BadgerDB which implements KVStore, the path of the config is the directory of the db
+func NewBadgerDB(cfg config.DB) *BadgerDB {
+	return &BadgerDB{
+		path:   cfg.DbPath,
+		config: cfg,
+		obs:    newOpObserver(cfg),
+	}
+}
+
+// Start opens the BadgerDB (creates the directory if not existing yet)
+func (b *BadgerDB) Start(_ context.Context) error {
+	db, err := badger.Open(badger.DefaultOptions(b.path).WithSyncWrites(true).WithLogger(nil))
+	if err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	b.db = db
+	return nil
+}
+
+// Backup streams the DB, as of a read timestamp, into a new DB in the directory, which doesn't block the writes
+func (b *BadgerDB) Backup(_ context.Context, dir string) error {
+	target, err := backupPath(dir, b.path)
+	if err != nil {
+		return err
+	}
+	dst, err := badger.Open(badger.DefaultOptions(target).WithSyncWrites(true).WithLogger(nil))
+	if err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	r, w := io.Pipe()
+	go func() {
+		_, err := b.db.Backup(w, 0)
+		w.CloseWithError(err)
+	}()
+	err = dst.Load(r, maxPendingLoads)
+	r.CloseWithError(io.ErrClosedPipe)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.RemoveAll(target)
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
+// Stop closes the BadgerDB
+func (b *BadgerDB) Stop(_ context.Context) error {
+	if b.db != nil {
+		if err := b.db.Close(); err != nil {
+			return errors.Wrap(ErrIO, err.Error())
+		}
+	}
+	return nil
+}
+
+// Put inserts a <key, value> record
+func (b *BadgerDB) Put(namespace string, key, value []byte) (err error) {
+	defer b.obs.observe(opPut, namespace, time.Now())
+	for c := uint8(0); c < b.config.NumRetries; c++ {
+		if err = b.db.Update(func(txn *badger.Txn) error {
+			if err := txn.Set(bucketKey(namespace), nil); err != nil {
+				return err
+			}
+			return txn.Set(recordKey(namespace, key), value)
+		}); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		err = errors.Wrap(ErrIO, err.Error())
+	}
+	return err
+}
+
+// Get retrieves a record
+func (b *BadgerDB) Get(namespace string, key []byte) ([]byte, error) {
+	defer b.obs.observe(opGet, namespace, time.Now())
+	var value []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(recordKey(namespace, key))
+		if err == badger.ErrKeyNotFound {
+			if _, err := txn.Get(bucketKey(namespace)); err == badger.ErrKeyNotFound {
+				return errors.Wrapf(ErrNotExist, "bucket = %x doesn't exist", []byte(namespace))
+			}
+			return errors.Wrapf(ErrNotExist, "key = %x doesn't exist", key)
+		}
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if err == nil {
+		return value, nil
+	}
+	if errors.Cause(err) == ErrNotExist {
+		return nil, err
+	}
+	return nil, errors.Wrap(ErrIO, err.Error())
+}
+
+// Filter returns <k, v> pair in a bucket that meet the condition
+func (b *BadgerDB) Filter(namespace string, cond Condition, minKey, maxKey []byte) ([][]byte, [][]byte, error) {
+	var fk, fv [][]byte
+	if err := b.db.View(func(txn *badger.Txn) error {
+		if _, err := txn.Get(bucketKey(namespace)); err == badger.ErrKeyNotFound {
+			return errors.Wrapf(ErrBucketNotExist, "bucket = %x doesn't exist", []byte(namespace))
+		}
+		prefix := recordPrefix(namespace)
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		checkMax := len(maxKey) > 0
+		for it.Seek(recordKey(namespace, minKey)); it.Valid(); it.Next() {
+			item := it.Item()
+			k := item.KeyCopy(nil)[len(prefix):]
+			if checkMax && bytes.Compare(k, maxKey) == 1 {
+				return nil
+			}
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if cond(k, v) {
+				fk = append(fk, k)
+				fv = append(fv, v)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if len(fk) == 0 {
+		return nil, nil, errors.Wrap(ErrNotExist, "filter returns no match")
+	}
+	return fk, fv, nil
+}
+
+// Range retrieves values for a range of keys
+func (b *BadgerDB) Range(namespace string, key []byte, count uint64) ([][]byte, error) {
+	value := make([][]byte, count)
+	err := b.db.View(func(txn *badger.Txn) error {
+		if _, err := txn.Get(bucketKey(namespace)); err == badger.ErrKeyNotFound {
+			return errors.Wrapf(ErrNotExist, "bucket = %s doesn't exist", namespace)
+		}
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = recordPrefix(namespace)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		it.Seek(recordKey(namespace, key))
+		// retrieve 'count' items
+		for i := uint64(0); i < count; i++ {
+			if !it.Valid() {
+				return errors.Wrapf(ErrNotExist, "entry for key 0x%x doesn't exist", key)
+			}
+			v, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			value[i] = v
+			it.Next()
+		}
+		return nil
+	})
+	if err == nil {
+		return value, nil
+	}
+	if errors.Cause(err) == ErrNotExist {
+		return nil, err
+	}
+	return nil, errors.Wrap(ErrIO, err.Error())
+}
+
+// Iterate returns an iterator of the records in a bucket, whose keys are in [start, end) where a nil end is unbounded.
+// The iterator holds a read transaction until it is closed.
+func (b *BadgerDB) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	txn := b.db.NewTransaction(false)
+	if _, err := txn.Get(bucketKey(namespace)); err != nil {
+		txn.Discard()
+		if err == badger.ErrKeyNotFound {
+			return emptyIterator{}, nil
+		}
+		return nil, errors.Wrap(ErrIO, err.Error())
+	}
+	prefix := recordPrefix(namespace)
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	it := &badgerIterator{
+		txn:       txn,
+		iter:      txn.NewIterator(opts),
+		prefixLen: len(prefix),
+		start:     recordKey(namespace, start),
+	}
+	if end != nil {
+		it.end = recordKey(namespace, end)
+	}
+	return it, nil
+}
+
+// badgerIterator iterates the records of a bucket within a read transaction
+type badgerIterator struct {
+	txn        *badger.Txn
+	iter       *badger.Iterator
+	prefixLen  int
+	start, end []byte
+	started    bool
+	key, value []byte
+	err        error
+}
+
+func (it *badgerIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		it.iter.Seek(it.start)
+	} else {
+		it.iter.Next()
+	}
+	if !it.iter.Valid() {
+		it.key, it.value = nil, nil
+		return false
+	}
+	item := it.iter.Item()
+	k := item.Key()
+	if it.end != nil && bytes.Compare(k, it.end) >= 0 {
+		it.key, it.value = nil, nil
+		return false
+	}
+	value, err := item.ValueCopy(it.value[:0])
+	if err != nil {
+		it.key, it.value, it.err = nil, nil, errors.Wrap(ErrIO, err.Error())
+		return false
+	}
+	it.key, it.value = k[it.prefixLen:], value
+	return true
+}
+
+func (it *badgerIterator) Key() []byte { return it.key }
+
+func (it *badgerIterator) Value() []byte { return it.value }
+
+func (it *badgerIterator) Error() error { return it.err }
+
+func (it *badgerIterator) Close() error {
+	it.iter.Close()
+	it.txn.Discard()
+	return nil
+}
+
+// Delete deletes a record, or the bucket of the namespace if the key is nil
+func (b *BadgerDB) Delete(namespace string, key []byte) (err error) {
+	defer b.obs.observe(opDelete, namespace, time.Now())
+	for c := uint8(0); c < b.config.NumRetries; c++ {
+		if key == nil {
+			if err = b.db.Update(func(txn *badger.Txn) error {
+				return txn.Delete(bucketKey(namespace))
+			}); err == nil {
+				err = b.db.DropPrefix(recordPrefix(namespace))
+			}
+		} else {
+			err = b.db.Update(func(txn *badger.Txn) error {
+				return txn.Delete(recordKey(namespace, key))
+			})
+		}
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		err = errors.Wrap(ErrIO, err.Error())
+	}
+	return err
+}
+
+// WriteBatch commits a batch in a transaction
+func (b *BadgerDB) WriteBatch(kvsb batch.KVStoreBatch) (err error) {
+	kvsb.Lock()
+	defer kvsb.Unlock()
+	defer b.obs.observe(opWriteBatch, batchNamespace(kvsb), time.Now())
+
+	for c := uint8(0); c < b.config.NumRetries; c++ {
+		if err = b.db.Update(func(txn *badger.Txn) error {
+			for i := 0; i < kvsb.Size(); i++ {
+				write, e := kvsb.Entry(i)
+				if e != nil {
+					return e
+				}
+				ns := write.Namespace()
+				errFmt := write.ErrorFormat()
+				errArgs := write.ErrorArgs()
+				switch write.WriteType() {
+				case batch.Put:
+					if e := txn.Set(bucketKey(ns), nil); e != nil {
+						return errors.Wrapf(e, errFmt, errArgs)
+					}
+					if e := txn.Set(recordKey(ns, write.Key()), write.Value()); e != nil {
+						return errors.Wrapf(e, errFmt, errArgs)
+					}
+				case batch.Delete:
+					if e := txn.Delete(recordKey(ns, write.Key())); e != nil {
+						return errors.Wrapf(e, errFmt, errArgs)
+					}
+				}
+			}
+			return nil
+		}); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		err = errors.Wrap(ErrIO, err.Error())
+	}
+	return err
+}
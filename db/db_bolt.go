@@ -9,6 +9,9 @@ package db
 import (
 	"bytes"
 	"context"
+	"io"
+	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
@@ -25,6 +28,7 @@ type BoltDB struct {
 	db     *bolt.DB
 	path   string
 	config config.DB
+	obs    *opObserver
 }
 
 // NewBoltDB instantiates an BoltDB with implements KVStore
@@ -33,6 +37,7 @@ func NewBoltDB(cfg config.DB) *BoltDB {
 		db:     nil,
 		path:   cfg.DbPath,
 		config: cfg,
+		obs:    newOpObserver(cfg),
 	}
 }
 
@@ -58,6 +63,7 @@ func (b *BoltDB) Stop(_ context.Context) error {
 
 // Put inserts a <key, value> record
 func (b *BoltDB) Put(namespace string, key, value []byte) (err error) {
+	defer b.obs.observe(opPut, namespace, time.Now())
 	for c := uint8(0); c < b.config.NumRetries; c++ {
 		if err = b.db.Update(func(tx *bolt.Tx) error {
 			bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
@@ -77,6 +83,7 @@ func (b *BoltDB) Put(namespace string, key, value []byte) (err error) {
 
 // Get retrieves a record
 func (b *BoltDB) Get(namespace string, key []byte) ([]byte, error) {
+	defer b.obs.observe(opGet, namespace, time.Now())
 	var value []byte
 	err := b.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(namespace))
@@ -181,6 +188,67 @@ func (b *BoltDB) Range(namespace string, key []byte, count uint64) ([][]byte, er
 	return nil, errors.Wrap(ErrIO, err.Error())
 }
 
+// Iterate returns an iterator of the records in a bucket, whose keys are in [start, end) where a nil end is unbounded.
+// The iterator holds a read transaction until it is closed, and a write growing the db file waits for it, so the
+// iterator must be closed before writing in the same goroutine.
+func (b *BoltDB) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, errors.Wrap(ErrIO, err.Error())
+	}
+	bucket := tx.Bucket([]byte(namespace))
+	if bucket == nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, errors.Wrap(ErrIO, err.Error())
+		}
+		return emptyIterator{}, nil
+	}
+	return &boltIterator{
+		tx:     tx,
+		cursor: bucket.Cursor(),
+		start:  start,
+		end:    end,
+	}, nil
+}
+
+// boltIterator iterates the records of a bucket within a read transaction
+type boltIterator struct {
+	tx         *bolt.Tx
+	cursor     *bolt.Cursor
+	start, end []byte
+	started    bool
+	key, value []byte
+}
+
+func (it *boltIterator) Next() bool {
+	var k, v []byte
+	if !it.started {
+		it.started = true
+		k, v = it.cursor.Seek(it.start)
+	} else if it.key != nil {
+		k, v = it.cursor.Next()
+	}
+	if k == nil || (it.end != nil && bytes.Compare(k, it.end) >= 0) {
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.key, it.value = k, v
+	return true
+}
+
+func (it *boltIterator) Key() []byte { return it.key }
+
+func (it *boltIterator) Value() []byte { return it.value }
+
+func (it *boltIterator) Error() error { return nil }
+
+func (it *boltIterator) Close() error {
+	if err := it.tx.Rollback(); err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
 // GetBucketByPrefix retrieves all bucket those with const namespace prefix
 func (b *BoltDB) GetBucketByPrefix(namespace []byte) ([][]byte, error) {
 	allKey := make([][]byte, 0)
@@ -221,6 +289,7 @@ func (b *BoltDB) GetKeyByPrefix(namespace, prefix []byte) ([][]byte, error) {
 
 // Delete deletes a record,if key is nil,this will delete the whole bucket
 func (b *BoltDB) Delete(namespace string, key []byte) (err error) {
+	defer b.obs.observe(opDelete, namespace, time.Now())
 	numRetries := b.config.NumRetries
 	for c := uint8(0); c < numRetries; c++ {
 		if key == nil {
@@ -253,6 +322,7 @@ func (b *BoltDB) Delete(namespace string, key []byte) (err error) {
 func (b *BoltDB) WriteBatch(kvsb batch.KVStoreBatch) (err error) {
 	kvsb.Lock()
 	defer kvsb.Unlock()
+	defer b.obs.observe(opWriteBatch, batchNamespace(kvsb), time.Now())
 
 	for c := uint8(0); c < b.config.NumRetries; c++ {
 		if err = b.db.Update(func(tx *bolt.Tx) error {
@@ -311,6 +381,98 @@ func (b *BoltDB) BucketExists(namespace string) bool {
 	return exist
 }
 
+// Checkpoint returns a consistent read-only view of the DB, which is not affected by the writes after it is taken.
+// The DB file cannot reuse the pages freed by later writes until the checkpoint is closed.
+func (b *BoltDB) Checkpoint() (*Checkpoint, error) {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, errors.Wrap(ErrIO, err.Error())
+	}
+	return &Checkpoint{tx: tx}, nil
+}
+
+// Checkpoint is a consistent read-only view of a BoltDB
+type Checkpoint struct {
+	tx *bolt.Tx
+}
+
+// Get retrieves a record
+func (c *Checkpoint) Get(namespace string, key []byte) ([]byte, error) {
+	bucket := c.tx.Bucket([]byte(namespace))
+	if bucket == nil {
+		return nil, errors.Wrapf(ErrNotExist, "bucket = %x doesn't exist", []byte(namespace))
+	}
+	v := bucket.Get(key)
+	if v == nil {
+		return nil, errors.Wrapf(ErrNotExist, "key = %x doesn't exist", key)
+	}
+	value := make([]byte, len(v))
+	copy(value, v)
+	return value, nil
+}
+
+// ForEach calls fn for each record in the namespace, the key and value are only valid during the call
+func (c *Checkpoint) ForEach(namespace string, fn func(k, v []byte) error) error {
+	bucket := c.tx.Bucket([]byte(namespace))
+	if bucket == nil {
+		return errors.Wrapf(ErrNotExist, "bucket = %x doesn't exist", []byte(namespace))
+	}
+	return bucket.ForEach(fn)
+}
+
+// Size returns the size of the DB file written by WriteTo
+func (c *Checkpoint) Size() int64 {
+	return c.tx.Size()
+}
+
+// WriteTo writes the whole DB file, as of the checkpoint, to w
+func (c *Checkpoint) WriteTo(w io.Writer) (int64, error) {
+	return c.tx.WriteTo(w)
+}
+
+// WriteFile writes the whole DB file, as of the checkpoint, to the path, where the file only appears once complete
+func (c *Checkpoint) WriteFile(path string) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+	if err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	defer os.Remove(tmp)
+	_, err = c.WriteTo(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
+// Close releases the checkpoint
+func (c *Checkpoint) Close() error {
+	return c.tx.Rollback()
+}
+
+// Backup copies the DB file into the directory as of a read transaction, which doesn't block the writes
+func (b *BoltDB) Backup(_ context.Context, dir string) error {
+	target, err := backupPath(dir, b.path)
+	if err != nil {
+		return err
+	}
+	cp, err := b.Checkpoint()
+	if err != nil {
+		return err
+	}
+	defer cp.Close()
+	return cp.WriteFile(target)
+}
+
 // ======================================
 // below functions used by RangeIndex
 // ======================================
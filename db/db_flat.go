@@ -0,0 +1,73 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// The flat KV stores, i.e. badger and pebble, have no buckets, so the namespace is encoded into the keys. The bucket of
+// a namespace is marked by bucketKey(namespace), which is put with the first record like the bucket of bolt, and the
+// records are keyed by the length of the namespace, the namespace and the key, so that the records of a namespace are
+// in the order of their keys
+const (
+	bucketKeyPrefix byte = iota
+	recordKeyPrefix
+)
+
+// NewKVStore returns the KVStore of the backend of the config, which is bolt by default, and encrypts the values of
+// the encrypted namespaces of the config
+func NewKVStore(cfg config.DB) (KVStore, error) {
+	var kv KVStore
+	switch cfg.Backend {
+	case "", config.BoltBackend:
+		kv = NewBoltDB(cfg)
+	case config.BadgerBackend:
+		kv = NewBadgerDB(cfg)
+	case config.PebbleBackend:
+		kv = NewPebbleDB(cfg)
+	default:
+		return nil, errors.Errorf("unsupported db backend %s", cfg.Backend)
+	}
+	if len(cfg.EncryptedNamespaces) > 0 {
+		kv = NewKVStoreWithEncryption(kv, EnvKeyProvider(cfg.EncryptionKeyEnv), cfg.EncryptedNamespaces)
+	}
+	return kv, nil
+}
+
+func bucketKey(namespace string) []byte {
+	return append([]byte{bucketKeyPrefix}, namespace...)
+}
+
+// recordPrefix returns the common prefix of the keys of the records in the namespace
+func recordPrefix(namespace string) []byte {
+	prefix := make([]byte, 1+binary.MaxVarintLen64, 1+binary.MaxVarintLen64+len(namespace))
+	prefix[0] = recordKeyPrefix
+	n := binary.PutUvarint(prefix[1:], uint64(len(namespace)))
+	return append(prefix[:1+n], namespace...)
+}
+
+func recordKey(namespace string, key []byte) []byte {
+	prefix := recordPrefix(namespace)
+	return append(prefix[:len(prefix):len(prefix)], key...)
+}
+
+// prefixEnd returns the smallest key greater than all the keys of the prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// testFlatKVStores runs the test on the badger and pebble stores in the temp directories
+func testFlatKVStores(t *testing.T, name string, testFunc func(KVStore, *testing.T)) {
+	for _, backend := range []string{config.BadgerBackend, config.PebbleBackend} {
+		path, err := ioutil.TempDir(os.TempDir(), name)
+		require.NoError(t, err)
+		cfg := config.Default.DB
+		cfg.DbPath = path
+		cfg.Backend = backend
+		kv, err := NewKVStore(cfg)
+		require.NoError(t, err)
+		t.Run(name+" on "+backend, func(t *testing.T) {
+			testFunc(kv, t)
+		})
+		testutil.CleanupPath(t, path)
+	}
+}
+
+func TestNewKVStore(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default.DB
+	kv, err := NewKVStore(cfg)
+	require.NoError(err)
+	require.IsType(&BoltDB{}, kv)
+	cfg.Backend = config.BadgerBackend
+	kv, err = NewKVStore(cfg)
+	require.NoError(err)
+	require.IsType(&BadgerDB{}, kv)
+	cfg.Backend = config.PebbleBackend
+	kv, err = NewKVStore(cfg)
+	require.NoError(err)
+	require.IsType(&PebbleDB{}, kv)
+	cfg.Backend = "leveldb"
+	_, err = NewKVStore(cfg)
+	require.Error(err)
+}
+
+func TestFlatKVStore(t *testing.T) {
+	testFlatKVStores(t, "test-flat", func(kv KVStore, t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+		require.NoError(kv.Start(ctx))
+
+		// the namespaces sharing a prefix don't see the records of each other
+		require.NoError(kv.Put("ns", []byte("1"), []byte("a")))
+		require.NoError(kv.Put("ns1", []byte("1"), []byte("b")))
+		require.NoError(kv.Put("n", []byte("s1"), []byte("c")))
+		for ns, expected := range map[string]string{"ns": "a", "ns1": "b"} {
+			v, err := kv.Get(ns, []byte("1"))
+			require.NoError(err)
+			require.Equal(expected, string(v))
+		}
+		_, err := kv.Get("n", []byte("s"))
+		require.Equal(ErrNotExist, errors.Cause(err))
+		fk, _, err := kv.Filter("ns", func(k, v []byte) bool { return true }, nil, nil)
+		require.NoError(err)
+		require.Equal([][]byte{[]byte("1")}, fk)
+
+		// a bucket deleted is not found any more
+		require.NoError(kv.Delete("ns", nil))
+		_, _, err = kv.Filter("ns", func(k, v []byte) bool { return true }, nil, nil)
+		require.Equal(ErrBucketNotExist, errors.Cause(err))
+		v, err := kv.Get("ns1", []byte("1"))
+		require.NoError(err)
+		require.Equal("b", string(v))
+
+		// range of the records
+		kvr, ok := kv.(KVStoreWithRange)
+		require.True(ok)
+		for i := byte(0); i < 5; i++ {
+			require.NoError(kv.Put("range", []byte{i}, []byte{i + 10}))
+		}
+		values, err := kvr.Range("range", []byte{1}, 3)
+		require.NoError(err)
+		require.Equal([][]byte{{11}, {12}, {13}}, values)
+		_, err = kvr.Range("range", []byte{3}, 3)
+		require.Equal(ErrNotExist, errors.Cause(err))
+		_, err = kvr.Range("ns", []byte{0}, 1)
+		require.Equal(ErrNotExist, errors.Cause(err))
+
+		// the records persist across the restart
+		require.NoError(kv.Stop(ctx))
+		require.NoError(kv.Start(ctx))
+		defer func() {
+			require.NoError(kv.Stop(ctx))
+		}()
+		v, err = kv.Get("ns1", []byte("1"))
+		require.NoError(err)
+		require.Equal("b", string(v))
+	})
+}
+
+func TestPrefixEnd(t *testing.T) {
+	require := require.New(t)
+	require.Equal([]byte{1, 3}, prefixEnd([]byte{1, 2}))
+	require.Equal([]byte{2}, prefixEnd([]byte{1, 0xff}))
+	require.Nil(prefixEnd([]byte{0xff, 0xff}))
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+// PebbleDB is KVStore implementation based on pebble DB, which takes the concurrent writes
+type PebbleDB struct {
+	db     *pebble.DB
+	path   string
+	config config.DB
+	obs    *opObserver
+}
+
+// NewPebbleDB instantiates a PebbleDB which implements KVStore, the path of the config is the directory of the db
+func NewPebbleDB(cfg config.DB) *PebbleDB {
+	return &PebbleDB{
+		path:   cfg.DbPath,
+		config: cfg,
+		obs:    newOpObserver(cfg),
+	}
+}
+
+// Start opens the PebbleDB (creates the directory if not existing yet)
+func (p *PebbleDB) Start(_ context.Context) error {
+	db, err := pebble.Open(p.path, &pebble.Options{})
+	if err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	p.db = db
+	return nil
+}
+
+// Backup takes a checkpoint of the DB in the directory, which hard-links the immutable table files when possible
+func (p *PebbleDB) Backup(_ context.Context, dir string) error {
+	target, err := backupPath(dir, p.path)
+	if err != nil {
+		return err
+	}
+	if err := p.db.Checkpoint(target); err != nil {
+		os.RemoveAll(target)
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
+// Stop closes the PebbleDB
+func (p *PebbleDB) Stop(_ context.Context) error {
+	if p.db != nil {
+		if err := p.db.Close(); err != nil {
+			return errors.Wrap(ErrIO, err.Error())
+		}
+	}
+	return nil
+}
+
+// Put inserts a <key, value> record
+func (p *PebbleDB) Put(namespace string, key, value []byte) (err error) {
+	defer p.obs.observe(opPut, namespace, time.Now())
+	for c := uint8(0); c < p.config.NumRetries; c++ {
+		b := p.db.NewBatch()
+		if err = b.Set(bucketKey(namespace), nil, nil); err == nil {
+			if err = b.Set(recordKey(namespace, key), value, nil); err == nil {
+				err = b.Commit(pebble.Sync)
+			}
+		}
+		b.Close()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		err = errors.Wrap(ErrIO, err.Error())
+	}
+	return err
+}
+
+// Get retrieves a record
+func (p *PebbleDB) Get(namespace string, key []byte) ([]byte, error) {
+	defer p.obs.observe(opGet, namespace, time.Now())
+	v, closer, err := p.db.Get(recordKey(namespace, key))
+	if err == pebble.ErrNotFound {
+		if !p.bucketExists(namespace) {
+			return nil, errors.Wrapf(ErrNotExist, "bucket = %x doesn't exist", []byte(namespace))
+		}
+		return nil, errors.Wrapf(ErrNotExist, "key = %x doesn't exist", key)
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrIO, err.Error())
+	}
+	defer closer.Close()
+	value := make([]byte, len(v))
+	copy(value, v)
+	return value, nil
+}
+
+// Filter returns <k, v> pair in a bucket that meet the condition
+func (p *PebbleDB) Filter(namespace string, cond Condition, minKey, maxKey []byte) ([][]byte, [][]byte, error) {
+	if !p.bucketExists(namespace) {
+		return nil, nil, errors.Wrapf(ErrBucketNotExist, "bucket = %x doesn't exist", []byte(namespace))
+	}
+	prefix := recordPrefix(namespace)
+	it := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: recordKey(namespace, minKey),
+		UpperBound: prefixEnd(prefix),
+	})
+	defer it.Close()
+
+	var fk, fv [][]byte
+	checkMax := len(maxKey) > 0
+	for it.First(); it.Valid(); it.Next() {
+		k := it.Key()[len(prefix):]
+		if checkMax && bytes.Compare(k, maxKey) == 1 {
+			break
+		}
+		if cond(k, it.Value()) {
+			fk = append(fk, append([]byte{}, k...))
+			fv = append(fv, append([]byte{}, it.Value()...))
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, nil, errors.Wrap(ErrIO, err.Error())
+	}
+
+	if len(fk) == 0 {
+		return nil, nil, errors.Wrap(ErrNotExist, "filter returns no match")
+	}
+	return fk, fv, nil
+}
+
+// Range retrieves values for a range of keys
+func (p *PebbleDB) Range(namespace string, key []byte, count uint64) ([][]byte, error) {
+	if !p.bucketExists(namespace) {
+		return nil, errors.Wrapf(ErrNotExist, "bucket = %s doesn't exist", namespace)
+	}
+	it := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: recordKey(namespace, key),
+		UpperBound: prefixEnd(recordPrefix(namespace)),
+	})
+	defer it.Close()
+
+	value := make([][]byte, count)
+	it.First()
+	// retrieve 'count' items
+	for i := uint64(0); i < count; i++ {
+		if !it.Valid() {
+			if err := it.Error(); err != nil {
+				return nil, errors.Wrap(ErrIO, err.Error())
+			}
+			return nil, errors.Wrapf(ErrNotExist, "entry for key 0x%x doesn't exist", key)
+		}
+		value[i] = append([]byte{}, it.Value()...)
+		it.Next()
+	}
+	return value, nil
+}
+
+// Iterate returns an iterator of the records in a bucket, whose keys are in [start, end) where a nil end is unbounded.
+// The iterator reads the DB as of its creation until it is closed.
+func (p *PebbleDB) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	if !p.bucketExists(namespace) {
+		return emptyIterator{}, nil
+	}
+	prefix := recordPrefix(namespace)
+	upper := prefixEnd(prefix)
+	if end != nil {
+		upper = recordKey(namespace, end)
+	}
+	return &pebbleIterator{
+		iter: p.db.NewIter(&pebble.IterOptions{
+			LowerBound: recordKey(namespace, start),
+			UpperBound: upper,
+		}),
+		prefixLen: len(prefix),
+	}, nil
+}
+
+// pebbleIterator iterates the records of a bucket within the bounds of a pebble iterator
+type pebbleIterator struct {
+	iter      *pebble.Iterator
+	prefixLen int
+	started   bool
+}
+
+func (it *pebbleIterator) Next() bool {
+	if !it.started {
+		it.started = true
+		return it.iter.First()
+	}
+	return it.iter.Next()
+}
+
+func (it *pebbleIterator) Key() []byte {
+	if !it.iter.Valid() {
+		return nil
+	}
+	return it.iter.Key()[it.prefixLen:]
+}
+
+func (it *pebbleIterator) Value() []byte {
+	if !it.iter.Valid() {
+		return nil
+	}
+	return it.iter.Value()
+}
+
+func (it *pebbleIterator) Error() error {
+	if err := it.iter.Error(); err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
+func (it *pebbleIterator) Close() error {
+	if err := it.iter.Close(); err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
+// Delete deletes a record, or the bucket of the namespace if the key is nil
+func (p *PebbleDB) Delete(namespace string, key []byte) (err error) {
+	defer p.obs.observe(opDelete, namespace, time.Now())
+	for c := uint8(0); c < p.config.NumRetries; c++ {
+		b := p.db.NewBatch()
+		if key == nil {
+			prefix := recordPrefix(namespace)
+			if err = b.Delete(bucketKey(namespace), nil); err == nil {
+				err = b.DeleteRange(prefix, prefixEnd(prefix), nil)
+			}
+		} else {
+			err = b.Delete(recordKey(namespace, key), nil)
+		}
+		if err == nil {
+			err = b.Commit(pebble.Sync)
+		}
+		b.Close()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		err = errors.Wrap(ErrIO, err.Error())
+	}
+	return err
+}
+
+// WriteBatch commits a batch atomically
+func (p *PebbleDB) WriteBatch(kvsb batch.KVStoreBatch) (err error) {
+	kvsb.Lock()
+	defer kvsb.Unlock()
+	defer p.obs.observe(opWriteBatch, batchNamespace(kvsb), time.Now())
+
+	for c := uint8(0); c < p.config.NumRetries; c++ {
+		b := p.db.NewBatch()
+		if err = p.fillBatch(b, kvsb); err == nil {
+			err = b.Commit(pebble.Sync)
+		}
+		b.Close()
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		err = errors.Wrap(ErrIO, err.Error())
+	}
+	return err
+}
+
+func (p *PebbleDB) fillBatch(b *pebble.Batch, kvsb batch.KVStoreBatch) error {
+	for i := 0; i < kvsb.Size(); i++ {
+		write, e := kvsb.Entry(i)
+		if e != nil {
+			return e
+		}
+		ns := write.Namespace()
+		errFmt := write.ErrorFormat()
+		errArgs := write.ErrorArgs()
+		switch write.WriteType() {
+		case batch.Put:
+			if e := b.Set(bucketKey(ns), nil, nil); e != nil {
+				return errors.Wrapf(e, errFmt, errArgs)
+			}
+			if e := b.Set(recordKey(ns, write.Key()), write.Value(), nil); e != nil {
+				return errors.Wrapf(e, errFmt, errArgs)
+			}
+		case batch.Delete:
+			if e := b.Delete(recordKey(ns, write.Key()), nil); e != nil {
+				return errors.Wrapf(e, errFmt, errArgs)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *PebbleDB) bucketExists(namespace string) bool {
+	_, closer, err := p.db.Get(bucketKey(namespace))
+	if err != nil {
+		return false
+	}
+	closer.Close()
+	return true
+}
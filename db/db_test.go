@@ -71,6 +71,7 @@ func TestKVStorePutGet(t *testing.T) {
 			testKVStorePutGet(v, t)
 		})
 	}
+	testFlatKVStores(t, "test-kv-store", testKVStorePutGet)
 
 }
 
@@ -253,6 +254,7 @@ func TestDBBatch(t *testing.T) {
 			testBatchRollback(v, t)
 		})
 	}
+	testFlatKVStores(t, "test-batch-commit", testBatchRollback)
 }
 
 func TestCacheKV(t *testing.T) {
@@ -347,6 +349,7 @@ func TestDeleteBucket(t *testing.T) {
 	t.Run("test delete bucket", func(t *testing.T) {
 		testFunc(NewBoltDB(cfg), t)
 	})
+	testFlatKVStores(t, "test-delete", testFunc)
 }
 
 func TestFilter(t *testing.T) {
@@ -449,4 +452,5 @@ func TestFilter(t *testing.T) {
 	t.Run("test filter", func(t *testing.T) {
 		testFunc(NewBoltDB(cfg), t)
 	})
+	testFlatKVStores(t, "test-filter", testFunc)
 }
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+// Iterator iterates the records of a bucket in the ascending order of the keys. It reads the store as of its creation,
+// and must be closed to release the read view.
+type Iterator interface {
+	// Next moves to the next record, it returns false after the last record or on an error
+	Next() bool
+	// Key returns the key of the record, which is valid until the next call of Next
+	Key() []byte
+	// Value returns the value of the record, which is valid until the next call of Next
+	Value() []byte
+	// Error returns the error met by the iteration
+	Error() error
+	// Close releases the read view of the store
+	Close() error
+}
+
+// IteratePrefix returns an iterator of the records in the bucket whose keys have the prefix
+func IteratePrefix(kv KVStore, namespace string, prefix []byte) (Iterator, error) {
+	return kv.Iterate(namespace, prefix, prefixEnd(prefix))
+}
+
+// overlay returns an iterator of the records of the iterator, overlaid by the last writes of the batch in the range
+func overlay(it Iterator, b batch.KVStoreBatch, ns string, start, end []byte) (Iterator, error) {
+	// the last write of each key in the range overrides the record of the iterator
+	latest := make(map[string]*batch.WriteInfo)
+	for i := 0; i < b.Size(); i++ {
+		entry, err := b.Entry(i)
+		if err != nil {
+			it.Close()
+			return nil, err
+		}
+		if entry.Namespace() != ns || !inRange(entry.Key(), start, end) {
+			continue
+		}
+		latest[string(entry.Key())] = entry
+	}
+	if len(latest) == 0 {
+		return it, nil
+	}
+	writes := make([]*batch.WriteInfo, 0, len(latest))
+	for _, entry := range latest {
+		writes = append(writes, entry)
+	}
+	sort.Slice(writes, func(i, j int) bool {
+		return bytes.Compare(writes[i].Key(), writes[j].Key()) < 0
+	})
+	return newMergeIterator(it, writes), nil
+}
+
+// inRange returns true if the key is in [start, end), where a nil end is unbounded
+func inRange(key, start, end []byte) bool {
+	return bytes.Compare(key, start) >= 0 && (end == nil || bytes.Compare(key, end) < 0)
+}
+
+// emptyIterator iterates a bucket not existing
+type emptyIterator struct{}
+
+func (emptyIterator) Next() bool    { return false }
+func (emptyIterator) Key() []byte   { return nil }
+func (emptyIterator) Value() []byte { return nil }
+func (emptyIterator) Error() error  { return nil }
+func (emptyIterator) Close() error  { return nil }
+
+// mergeIterator overlays the records of an iterator with the sorted writes to the same keys
+type mergeIterator struct {
+	base       Iterator
+	baseValid  bool // base is at a record not returned yet
+	baseUsed   bool // base record is returned or overridden, and base should move on
+	writes     []*batch.WriteInfo
+	index      int
+	key, value []byte
+}
+
+func newMergeIterator(base Iterator, writes []*batch.WriteInfo) *mergeIterator {
+	return &mergeIterator{
+		base:     base,
+		baseUsed: true,
+		writes:   writes,
+	}
+}
+
+func (it *mergeIterator) Next() bool {
+	for {
+		if it.baseUsed {
+			it.baseValid = it.base.Next()
+			it.baseUsed = false
+		}
+		var write *batch.WriteInfo
+		if it.index < len(it.writes) {
+			write = it.writes[it.index]
+		}
+		switch {
+		case write == nil && !it.baseValid:
+			it.key, it.value = nil, nil
+			return false
+		case write == nil || (it.baseValid && bytes.Compare(it.base.Key(), write.Key()) < 0):
+			it.key, it.value = it.base.Key(), it.base.Value()
+			it.baseUsed = true
+			return true
+		default:
+			if it.baseValid && bytes.Equal(it.base.Key(), write.Key()) {
+				it.baseUsed = true
+			}
+			it.index++
+			if write.WriteType() == batch.Delete {
+				continue
+			}
+			it.key, it.value = write.Key(), write.Value()
+			return true
+		}
+	}
+}
+
+func (it *mergeIterator) Key() []byte { return it.key }
+
+func (it *mergeIterator) Value() []byte { return it.value }
+
+func (it *mergeIterator) Error() error { return it.base.Error() }
+
+func (it *mergeIterator) Close() error { return it.base.Close() }
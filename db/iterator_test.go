@@ -0,0 +1,138 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// iterateAll returns a func which reads the keys and values of the iterator, and closes it
+func iterateAll(t *testing.T) func(Iterator, error) ([]string, []string) {
+	return func(it Iterator, err error) ([]string, []string) {
+		require.NoError(t, err)
+		var keys, values []string
+		for it.Next() {
+			keys = append(keys, string(it.Key()))
+			values = append(values, string(it.Value()))
+		}
+		require.NoError(t, it.Error())
+		require.NoError(t, it.Close())
+		return keys, values
+	}
+}
+
+func TestIterate(t *testing.T) {
+	for _, backend := range []string{config.BoltBackend, config.BadgerBackend, config.PebbleBackend} {
+		t.Run(backend, func(t *testing.T) {
+			require := require.New(t)
+			path, err := testutil.PathOfTempFile("test-iterate-" + backend)
+			require.NoError(err)
+			testutil.CleanupPath(t, path)
+			defer testutil.CleanupPath(t, path)
+
+			cfg := config.Default.DB
+			cfg.DbPath = path
+			cfg.Backend = backend
+			kv, err := NewKVStore(cfg)
+			require.NoError(err)
+			ctx := context.Background()
+			require.NoError(kv.Start(ctx))
+			defer func() {
+				require.NoError(kv.Stop(ctx))
+			}()
+
+			readAll := iterateAll(t)
+			b := batch.NewBatch()
+			for _, k := range []string{"a1", "a2", "a3", "b1", "b2", "c"} {
+				b.Put("ns", []byte(k), []byte("v"+k), "")
+			}
+			b.Put("ns1", []byte("a0"), []byte("x"), "")
+			require.NoError(kv.WriteBatch(b))
+
+			keys, values := readAll(kv.Iterate("ns", nil, nil))
+			require.Equal([]string{"a1", "a2", "a3", "b1", "b2", "c"}, keys)
+			require.Equal([]string{"va1", "va2", "va3", "vb1", "vb2", "vc"}, values)
+			keys, _ = readAll(kv.Iterate("ns", []byte("a2"), []byte("b2")))
+			require.Equal([]string{"a2", "a3", "b1"}, keys)
+			keys, _ = readAll(kv.Iterate("ns", []byte("b"), nil))
+			require.Equal([]string{"b1", "b2", "c"}, keys)
+			keys, _ = readAll(kv.Iterate("ns", []byte("d"), nil))
+			require.Empty(keys)
+			keys, _ = readAll(IteratePrefix(kv, "ns", []byte("a")))
+			require.Equal([]string{"a1", "a2", "a3"}, keys)
+			keys, _ = readAll(IteratePrefix(kv, "ns1", nil))
+			require.Equal([]string{"a0"}, keys)
+			keys, _ = readAll(kv.Iterate("ns2", nil, nil))
+			require.Empty(keys)
+
+			if backend == config.BoltBackend {
+				// the write would wait for the read transaction of the iterator
+				return
+			}
+			// the iterator doesn't see the writes after its creation
+			it, err := IteratePrefix(kv, "ns", []byte("b"))
+			require.NoError(err)
+			require.NoError(kv.Put("ns", []byte("b0"), []byte("vb0")))
+			keys, _ = readAll(it, nil)
+			require.Equal([]string{"b1", "b2"}, keys)
+			keys, _ = readAll(IteratePrefix(kv, "ns", []byte("b")))
+			require.Equal([]string{"b0", "b1", "b2"}, keys)
+		})
+	}
+}
+
+func TestIterateWithBuffer(t *testing.T) {
+	require := require.New(t)
+	path, err := testutil.PathOfTempFile("test-iterate-buffer")
+	require.NoError(err)
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	store := NewBoltDB(cfg)
+	ctx := context.Background()
+	require.NoError(store.Start(ctx))
+	defer func() {
+		require.NoError(store.Stop(ctx))
+	}()
+	for _, k := range []string{"a", "c", "e"} {
+		require.NoError(store.Put("ns", []byte(k), []byte("v"+k)))
+	}
+
+	flusher, err := NewKVStoreFlusher(store, batch.NewCachedBatch())
+	require.NoError(err)
+	kvb := flusher.KVStoreWithBuffer()
+	require.NoError(kvb.Put("ns", []byte("b"), []byte("vb")))
+	require.NoError(kvb.Put("ns", []byte("c"), []byte("x")))
+	require.NoError(kvb.Put("ns", []byte("c"), []byte("vc2")))
+	require.NoError(kvb.Delete("ns", []byte("e")))
+	require.NoError(kvb.Put("ns", []byte("f"), []byte("vf")))
+	require.NoError(kvb.Put("ns1", []byte("d"), []byte("vd")))
+
+	readAll := iterateAll(t)
+	keys, values := readAll(kvb.Iterate("ns", nil, nil))
+	require.Equal([]string{"a", "b", "c", "f"}, keys)
+	require.Equal([]string{"va", "vb", "vc2", "vf"}, values)
+	keys, _ = readAll(kvb.Iterate("ns", []byte("b"), []byte("f")))
+	require.Equal([]string{"b", "c"}, keys)
+
+	// the records in the store are unchanged until flushed
+	keys, _ = readAll(store.Iterate("ns", nil, nil))
+	require.Equal([]string{"a", "c", "e"}, keys)
+	require.NoError(flusher.Flush())
+	keys, values = readAll(store.Iterate("ns", nil, nil))
+	require.Equal([]string{"a", "b", "c", "f"}, keys)
+	require.Equal([]string{"va", "vb", "vc2", "vf"}, values)
+}
@@ -35,6 +35,8 @@ type (
 		WriteBatch(batch.KVStoreBatch) error
 		// Filter returns <k, v> pair in a bucket that meet the condition
 		Filter(string, Condition, []byte, []byte) ([][]byte, [][]byte, error)
+		// Iterate returns an iterator of the records in a bucket, whose keys are in [start, end)
+		Iterate(string, []byte, []byte) (Iterator, error)
 	}
 
 	// KVStoreWithRange is KVStore with Range() API
@@ -99,6 +99,11 @@ func (m *memKVStore) Filter(namespace string, c Condition, minKey, maxKey []byte
 	return nil, nil, errors.New("in-memory KVStore does not support Filter()")
 }
 
+// Iterate returns an iterator of the records in a bucket, whose keys are in [start, end)
+func (m *memKVStore) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	return nil, errors.New("in-memory KVStore does not support Iterate()")
+}
+
 // WriteBatch commits a batch
 func (m *memKVStore) WriteBatch(b batch.KVStoreBatch) (e error) {
 	succeed := false
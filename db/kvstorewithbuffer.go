@@ -3,11 +3,14 @@ package db
 import (
 	"bytes"
 	"context"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/db/batch"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
 )
 
 type (
@@ -31,6 +34,12 @@ type (
 	kvStoreWithBuffer struct {
 		store  KVStore
 		buffer batch.CachedBatch
+		// ttls is the time to live of the records in the expiring namespaces
+		ttls          map[string]time.Duration
+		sweepInterval time.Duration
+		sweeper       *routine.RecurringTask
+		// ttlLock keeps the sweeper from deleting a record being written again by the flush
+		ttlLock sync.Mutex
 	}
 
 	// KVStoreFlusher is a wrapper of KVStoreWithBuffer, which has flush api
@@ -45,6 +54,7 @@ type (
 		serializeFilter batch.WriteInfoFilter
 		serialize       batch.WriteInfoSerialize
 		flushTranslate  batch.WriteInfoTranslate
+		maxBatchBytes   uint64
 	}
 
 	// KVStoreFlusherOption sets option for KVStoreFlusher
@@ -87,6 +97,16 @@ func FlushTranslateOption(wit batch.WriteInfoTranslate) KVStoreFlusherOption {
 	}
 }
 
+// FlushBatchSizeOption splits the flush into the write batches of at most the size, 0 means unlimited. The flush is
+// no longer atomic, which only suits the writes to be redone from scratch after a failure, e.g., a state sync.
+func FlushBatchSizeOption(size uint64) KVStoreFlusherOption {
+	return func(f *flusher) error {
+		f.maxBatchBytes = size
+
+		return nil
+	}
+}
+
 // NewKVStoreFlusher returns kv store flusher
 func NewKVStoreFlusher(store KVStore, buffer batch.CachedBatch, opts ...KVStoreFlusherOption) (KVStoreFlusher, error) {
 	if store == nil {
@@ -111,7 +131,15 @@ func NewKVStoreFlusher(store KVStore, buffer batch.CachedBatch, opts ...KVStoreF
 }
 
 func (f *flusher) Flush() error {
-	if err := f.kvb.store.WriteBatch(f.kvb.buffer.Translate(f.flushTranslate)); err != nil {
+	b := f.kvb.buffer.Translate(f.flushTranslate)
+	if len(f.kvb.ttls) > 0 {
+		f.kvb.ttlLock.Lock()
+		defer f.kvb.ttlLock.Unlock()
+		if err := f.kvb.addExpiries(b, time.Now()); err != nil {
+			return err
+		}
+	}
+	if err := f.write(b); err != nil {
 		return err
 	}
 
@@ -121,6 +149,37 @@ func (f *flusher) Flush() error {
 	return nil
 }
 
+// write writes the batch, split by the max batch size
+func (f *flusher) write(b batch.KVStoreBatch) error {
+	if f.maxBatchBytes == 0 || batchSize(b) <= f.maxBatchBytes {
+		return f.kvb.store.WriteBatch(b)
+	}
+	var (
+		chunk = batch.NewBatch()
+		size  uint64
+	)
+	for i := 0; i < b.Size(); i++ {
+		write, err := b.Entry(i)
+		if err != nil {
+			return err
+		}
+		if s := writeSize(write); size > 0 && size+s > f.maxBatchBytes {
+			if err := f.kvb.store.WriteBatch(chunk); err != nil {
+				return err
+			}
+			chunk, size = batch.NewBatch(), 0
+		}
+		switch write.WriteType() {
+		case batch.Put:
+			chunk.Put(write.Namespace(), write.Key(), write.Value(), write.ErrorFormat(), write.ErrorArgs())
+		case batch.Delete:
+			chunk.Delete(write.Namespace(), write.Key(), write.ErrorFormat(), write.ErrorArgs())
+		}
+		size += writeSize(write)
+	}
+	return f.kvb.store.WriteBatch(chunk)
+}
+
 func (f *flusher) SerializeQueue() []byte {
 	return f.kvb.SerializeQueue(f.serialize, f.serializeFilter)
 }
@@ -130,10 +189,21 @@ func (f *flusher) KVStoreWithBuffer() KVStoreWithBuffer {
 }
 
 func (kvb *kvStoreWithBuffer) Start(ctx context.Context) error {
-	return kvb.store.Start(ctx)
+	if err := kvb.store.Start(ctx); err != nil {
+		return err
+	}
+	if len(kvb.ttls) == 0 {
+		return nil
+	}
+	return kvb.startSweeper(ctx)
 }
 
 func (kvb *kvStoreWithBuffer) Stop(ctx context.Context) error {
+	if kvb.sweeper != nil {
+		if err := kvb.sweeper.Stop(ctx); err != nil {
+			return err
+		}
+	}
 	return kvb.store.Stop(ctx)
 }
 
@@ -232,6 +302,15 @@ func (kvb *kvStoreWithBuffer) Filter(ns string, cond Condition, minKey, maxKey [
 	return fk, fv, nil
 }
 
+// Iterate returns an iterator of the records in the store, overlaid by the writes in the buffer
+func (kvb *kvStoreWithBuffer) Iterate(ns string, start, end []byte) (Iterator, error) {
+	it, err := kvb.store.Iterate(ns, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return overlay(it, kvb.buffer, ns, start, end)
+}
+
 func (kvb *kvStoreWithBuffer) WriteBatch(b batch.KVStoreBatch) (err error) {
 	b.Lock()
 	defer func() {
@@ -6,6 +6,8 @@ import (
 	"sync"
 
 	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/db/batch"
 	"github.com/iotexproject/iotex-core/pkg/log"
 )
@@ -51,6 +53,15 @@ func (kvc *kvStoreWithCache) Put(namespace string, key, value []byte) (err error
 	return nil
 }
 
+// Backup backs up the kvstore, which has all the records since the stateCaches are written through
+func (kvc *kvStoreWithCache) Backup(ctx context.Context, dir string) error {
+	b, ok := kvc.store.(Backuper)
+	if !ok {
+		return errors.Errorf("%T cannot be backed up", kvc.store)
+	}
+	return b.Backup(ctx, dir)
+}
+
 // Get retrieves a <key, value> record from stateCaches, and if not exists, retrieves from kvstore
 func (kvc *kvStoreWithCache) Get(namespace string, key []byte) ([]byte, error) {
 	if cachedData, isExist := kvc.getStateCaches(namespace, key); isExist {
@@ -70,6 +81,11 @@ func (kvc *kvStoreWithCache) Filter(namespace string, cond Condition, minKey, ma
 	return kvc.store.Filter(namespace, cond, minKey, maxKey)
 }
 
+// Iterate returns an iterator of the records in the kvstore, the cache being written through
+func (kvc *kvStoreWithCache) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	return kvc.store.Iterate(namespace, start, end)
+}
+
 // Delete deletes a record from statecaches if exists, and from kvstore
 func (kvc *kvStoreWithCache) Delete(namespace string, key []byte) (err error) {
 	if err := kvc.store.Delete(namespace, key); err != nil {
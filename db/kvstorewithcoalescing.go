@@ -0,0 +1,263 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+)
+
+// kvStoreWithCoalescing is an implementation of KVStore, which coalesces the write batches arriving within the max
+// delay of the previous one, until they add up to the min batch size. Each write batch is kept whole in a commit, so
+// the store is always at the end of a write batch, and the coalesced ones lost by a crash are written again by the
+// catch-up of the indexers. A failed write of the coalesced batches is sticky: the pending batches are kept for the
+// reads, and the later writes are refused, so no batch is written on top of the lost ones.
+type kvStoreWithCoalescing struct {
+	store     KVStore
+	cfg       config.FlushBatch
+	lock      sync.RWMutex
+	pending   batch.CachedBatch
+	size      uint64
+	since     time.Time // when the oldest pending write batch arrived
+	lastWrite time.Time
+	ticker    *routine.RecurringTask
+	err       error // the failure of the write of the pending batches
+}
+
+// NewKVStoreWithCoalescing wraps the store to coalesce the small write batches, it returns the store itself if the
+// coalescing is disabled by the config
+func NewKVStoreWithCoalescing(store KVStore, cfg config.FlushBatch) KVStore {
+	if cfg.MinBatchBytes == 0 {
+		return store
+	}
+	kvc := &kvStoreWithCoalescing{
+		store:   store,
+		cfg:     cfg,
+		pending: batch.NewCachedBatch(),
+	}
+	kvc.ticker = routine.NewRecurringTask(func() {
+		kvc.lock.Lock()
+		defer kvc.lock.Unlock()
+		if kvc.err != nil || kvc.size == 0 || time.Since(kvc.since) < kvc.cfg.MaxDelay {
+			return
+		}
+		if err := kvc.commit(); err != nil {
+			log.L().Error("Failed to write the coalesced batches.", zap.Error(err))
+		}
+	}, cfg.MaxDelay)
+	return kvc
+}
+
+// batchSize returns the bytes of the writes of the batch
+func batchSize(b batch.KVStoreBatch) uint64 {
+	var size uint64
+	for i := 0; i < b.Size(); i++ {
+		write, err := b.Entry(i)
+		if err != nil {
+			continue
+		}
+		size += writeSize(write)
+	}
+	return size
+}
+
+func writeSize(write *batch.WriteInfo) uint64 {
+	return uint64(len(write.Namespace()) + len(write.Key()) + len(write.Value()))
+}
+
+// Start starts the store and the ticker writing the pending batches
+func (kvc *kvStoreWithCoalescing) Start(ctx context.Context) error {
+	if err := kvc.store.Start(ctx); err != nil {
+		return err
+	}
+	return kvc.ticker.Start(ctx)
+}
+
+// Stop writes the pending batches and stops the store. The store is stopped even if the pending batches fail to be
+// written, and the error is returned.
+func (kvc *kvStoreWithCoalescing) Stop(ctx context.Context) error {
+	if err := kvc.ticker.Stop(ctx); err != nil {
+		return err
+	}
+	if err := kvc.flush(); err != nil {
+		if stopErr := kvc.store.Stop(ctx); stopErr != nil {
+			log.L().Error("Failed to stop the store.", zap.Error(stopErr))
+		}
+		return err
+	}
+	return kvc.store.Stop(ctx)
+}
+
+// Get returns the value of the pending writes, or of the store
+func (kvc *kvStoreWithCoalescing) Get(namespace string, key []byte) ([]byte, error) {
+	kvc.lock.RLock()
+	defer kvc.lock.RUnlock()
+	value, err := kvc.pending.Get(namespace, key)
+	switch errors.Cause(err) {
+	case batch.ErrNotExist:
+		return kvc.store.Get(namespace, key)
+	case batch.ErrAlreadyDeleted:
+		return nil, errors.Wrapf(ErrNotExist, "failed to get key %x in %s, deleted in pending batch", key, namespace)
+	}
+	return value, err
+}
+
+// Put writes the pending batches and then the record
+func (kvc *kvStoreWithCoalescing) Put(namespace string, key, value []byte) error {
+	kvc.lock.Lock()
+	defer kvc.lock.Unlock()
+	if err := kvc.commit(); err != nil {
+		return err
+	}
+	return kvc.store.Put(namespace, key, value)
+}
+
+// Delete writes the pending batches and then deletes the record
+func (kvc *kvStoreWithCoalescing) Delete(namespace string, key []byte) error {
+	kvc.lock.Lock()
+	defer kvc.lock.Unlock()
+	if err := kvc.commit(); err != nil {
+		return err
+	}
+	return kvc.store.Delete(namespace, key)
+}
+
+// WriteBatch adds the batch to the pending batches, which are written if they reach the min batch size, or if the
+// previous write batch arrived long ago, i.e., the store is not under load
+func (kvc *kvStoreWithCoalescing) WriteBatch(kvsb batch.KVStoreBatch) error {
+	kvsb.Lock()
+	defer kvsb.Unlock()
+	kvc.lock.Lock()
+	defer kvc.lock.Unlock()
+	if kvc.err != nil {
+		return kvc.err
+	}
+
+	size := batchSize(kvsb)
+	if kvc.cfg.MaxBatchBytes > 0 && kvc.size+size > kvc.cfg.MaxBatchBytes {
+		if err := kvc.commit(); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	if kvc.size == 0 {
+		kvc.since = now
+	}
+	for i := 0; i < kvsb.Size(); i++ {
+		write, err := kvsb.Entry(i)
+		if err != nil {
+			return err
+		}
+		ns := write.Namespace()
+		switch write.WriteType() {
+		case batch.Put:
+			if p, ok := kvsb.CheckFillPercent(ns); ok {
+				kvc.pending.AddFillPercent(ns, p)
+			}
+			kvc.pending.Put(ns, write.Key(), write.Value(), write.ErrorFormat(), write.ErrorArgs())
+		case batch.Delete:
+			kvc.pending.Delete(ns, write.Key(), write.ErrorFormat(), write.ErrorArgs())
+		}
+	}
+	kvc.size += size
+	underLoad := now.Sub(kvc.lastWrite) < kvc.cfg.MaxDelay
+	kvc.lastWrite = now
+	if kvc.size < kvc.cfg.MinBatchBytes && underLoad {
+		return nil
+	}
+	return kvc.commit()
+}
+
+// Filter writes the pending batches, and returns <k, v> pair in a bucket that meet the condition
+func (kvc *kvStoreWithCoalescing) Filter(namespace string, cond Condition, minKey, maxKey []byte) ([][]byte, [][]byte, error) {
+	if err := kvc.flush(); err != nil {
+		return nil, nil, err
+	}
+	return kvc.store.Filter(namespace, cond, minKey, maxKey)
+}
+
+// Iterate returns an iterator of the records in the store, overlaid by the pending writes
+func (kvc *kvStoreWithCoalescing) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	kvc.lock.RLock()
+	defer kvc.lock.RUnlock()
+	it, err := kvc.store.Iterate(namespace, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return overlay(it, kvc.pending, namespace, start, end)
+}
+
+// GetBucketByPrefix writes the pending batches, and retrieves the buckets of the store with the prefix
+func (kvc *kvStoreWithCoalescing) GetBucketByPrefix(prefix []byte) ([][]byte, error) {
+	lister, ok := kvc.store.(interface {
+		GetBucketByPrefix([]byte) ([][]byte, error)
+	})
+	if !ok {
+		return nil, errors.Errorf("%T cannot list the buckets", kvc.store)
+	}
+	if err := kvc.flush(); err != nil {
+		return nil, err
+	}
+	return lister.GetBucketByPrefix(prefix)
+}
+
+// Backup writes the pending batches, and backs up the store
+func (kvc *kvStoreWithCoalescing) Backup(ctx context.Context, dir string) error {
+	b, ok := kvc.store.(Backuper)
+	if !ok {
+		return errors.Errorf("%T cannot be backed up", kvc.store)
+	}
+	if err := kvc.flush(); err != nil {
+		return err
+	}
+	return b.Backup(ctx, dir)
+}
+
+// Checkpoint writes the pending batches, and returns the checkpoint of the bolt store
+func (kvc *kvStoreWithCoalescing) Checkpoint() (*Checkpoint, error) {
+	boltDB, ok := kvc.store.(*BoltDB)
+	if !ok {
+		return nil, errors.Errorf("%T has no checkpoint", kvc.store)
+	}
+	if err := kvc.flush(); err != nil {
+		return nil, err
+	}
+	return boltDB.Checkpoint()
+}
+
+func (kvc *kvStoreWithCoalescing) flush() error {
+	kvc.lock.Lock()
+	defer kvc.lock.Unlock()
+	return kvc.commit()
+}
+
+// commit writes the pending batches. If the write fails, the pending batches are kept and the error is returned by
+// all the later writes, as writing the next batches would leave a gap in the store.
+func (kvc *kvStoreWithCoalescing) commit() error {
+	if kvc.err != nil {
+		return kvc.err
+	}
+	if kvc.size == 0 {
+		return nil
+	}
+	if err := kvc.store.WriteBatch(kvc.pending); err != nil {
+		kvc.err = errors.Wrap(err, "failed to write the coalesced batches")
+		return kvc.err
+	}
+	kvc.pending.Clear()
+	kvc.size = 0
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// countingKVStore counts the write batches written to the store
+type countingKVStore struct {
+	KVStore
+	batches []int
+}
+
+func (s *countingKVStore) WriteBatch(b batch.KVStoreBatch) error {
+	s.batches = append(s.batches, b.Size())
+	return s.KVStore.WriteBatch(b)
+}
+
+// failingKVStore fails the write batches once failing is set
+type failingKVStore struct {
+	countingKVStore
+	failing int32
+}
+
+func (s *failingKVStore) WriteBatch(b batch.KVStoreBatch) error {
+	if atomic.LoadInt32(&s.failing) == 1 {
+		return errors.New("failed to write batch")
+	}
+	return s.countingKVStore.WriteBatch(b)
+}
+
+func TestKVStoreWithCoalescing(t *testing.T) {
+	require := require.New(t)
+	path, err := testutil.PathOfTempFile("test-coalescing")
+	require.NoError(err)
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	store := &countingKVStore{KVStore: NewBoltDB(cfg)}
+	require.Equal(store, NewKVStoreWithCoalescing(store, config.FlushBatch{}))
+
+	// each put is 12 bytes, and each delete is 4 bytes
+	kv := NewKVStoreWithCoalescing(store, config.FlushBatch{
+		MinBatchBytes: 28,
+		MaxBatchBytes: 48,
+		MaxDelay:      time.Hour,
+	})
+	ctx := context.Background()
+	require.NoError(kv.Start(ctx))
+	writeBatch := func(keys ...string) {
+		b := batch.NewBatch()
+		for _, k := range keys {
+			b.Put("ns", []byte(k), []byte("value_"+k), "")
+		}
+		require.NoError(kv.WriteBatch(b))
+	}
+	get := func(key string) error {
+		v, err := kv.Get("ns", []byte(key))
+		if err == nil {
+			require.Equal("value_"+key, string(v))
+		}
+		return err
+	}
+
+	// the first batch is written as the store is not under load
+	writeBatch("k1")
+	require.Equal([]int{1}, store.batches)
+	// the next ones are coalesced until they reach the min size
+	writeBatch("k2")
+	writeBatch("k3")
+	require.Equal([]int{1}, store.batches)
+	require.NoError(get("k2"))
+	b := batch.NewBatch()
+	b.Delete("ns", []byte("k1"), "")
+	require.NoError(kv.WriteBatch(b))
+	require.Equal([]int{1, 3}, store.batches)
+	require.Equal(ErrNotExist, errors.Cause(get("k1")))
+
+	// the pending batches are written before exceeding the max size
+	writeBatch("k4", "k5")
+	writeBatch("k6", "k7", "k8")
+	require.Equal([]int{1, 3, 2, 3}, store.batches)
+	writeBatch("k9")
+	it, err := kv.Iterate("ns", nil, nil)
+	require.NoError(err)
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	require.NoError(it.Close())
+	require.Equal([]string{"k2", "k3", "k4", "k5", "k6", "k7", "k8", "k9"}, keys)
+
+	// the pending batches are written before the other writes and on stop
+	require.NoError(kv.Put("ns", []byte("k10"), []byte("value_k10")))
+	require.Equal([]int{1, 3, 2, 3, 1}, store.batches)
+	writeBatch("k11")
+	require.NoError(kv.Stop(ctx))
+	require.Equal([]int{1, 3, 2, 3, 1, 1}, store.batches)
+	require.NoError(store.Start(ctx))
+	defer func() {
+		require.NoError(store.Stop(ctx))
+	}()
+	_, err = store.Get("ns", []byte("k11"))
+	require.NoError(err)
+}
+
+func TestKVStoreWithCoalescingDelay(t *testing.T) {
+	require := require.New(t)
+	store := &countingKVStore{KVStore: NewMemKVStore()}
+	kv := NewKVStoreWithCoalescing(store, config.FlushBatch{
+		MinBatchBytes: 1024,
+		MaxDelay:      200 * time.Millisecond,
+	})
+	ctx := context.Background()
+	require.NoError(kv.Start(ctx))
+	defer func() {
+		require.NoError(kv.Stop(ctx))
+	}()
+
+	for i := 0; i < 2; i++ {
+		b := batch.NewBatch()
+		b.Put("ns", []byte{byte(i)}, []byte("value"), "")
+		require.NoError(kv.WriteBatch(b))
+	}
+	require.Len(store.batches, 1)
+	// the coalesced batch is written after the max delay
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		_, err := store.Get("ns", []byte{1})
+		return err == nil, nil
+	}))
+}
+
+func TestKVStoreWithCoalescingWriteFailure(t *testing.T) {
+	require := require.New(t)
+	store := &failingKVStore{countingKVStore: countingKVStore{KVStore: NewMemKVStore()}}
+	kv := NewKVStoreWithCoalescing(store, config.FlushBatch{
+		MinBatchBytes: 1024,
+		MaxDelay:      100 * time.Millisecond,
+	})
+	ctx := context.Background()
+	require.NoError(kv.Start(ctx))
+	writeBatch := func(key byte) error {
+		b := batch.NewBatch()
+		b.Put("ns", []byte{key}, []byte("value"), "")
+		return kv.WriteBatch(b)
+	}
+
+	require.NoError(writeBatch(0))
+	require.NoError(writeBatch(1))
+	require.Equal([]int{1}, store.batches)
+	// the failed write by the ticker keeps the pending batch
+	atomic.StoreInt32(&store.failing, 1)
+	time.Sleep(300 * time.Millisecond)
+	v, err := kv.Get("ns", []byte{1})
+	require.NoError(err)
+	require.Equal("value", string(v))
+
+	// the later writes are refused even after the store recovers
+	atomic.StoreInt32(&store.failing, 0)
+	require.Error(writeBatch(2))
+	require.Error(kv.Put("ns", []byte{3}, []byte("value")))
+	require.Error(kv.Delete("ns", []byte{0}))
+	require.Error(kv.Stop(ctx))
+	require.Equal([]int{1}, store.batches)
+	_, err = kv.Get("ns", []byte{2})
+	require.Equal(ErrNotExist, errors.Cause(err))
+}
+
+func TestFlushBatchSizeOption(t *testing.T) {
+	require := require.New(t)
+	store := &countingKVStore{KVStore: NewMemKVStore()}
+	// each write is 12 bytes
+	f, err := NewKVStoreFlusher(store, batch.NewCachedBatch(), FlushBatchSizeOption(30))
+	require.NoError(err)
+	kvb := f.KVStoreWithBuffer()
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		require.NoError(kvb.Put("ns", []byte(k), []byte("value_"+k)))
+	}
+	require.NoError(f.Flush())
+	require.Equal([]int{2, 2, 1}, store.batches)
+	require.Zero(kvb.Size())
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		v, err := store.Get("ns", []byte(k))
+		require.NoError(err)
+		require.Equal("value_"+k, string(v))
+	}
+}
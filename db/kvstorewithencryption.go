@@ -0,0 +1,246 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+// ErrDecrypt indicates a value fails to be decrypted, either by a wrong key or a tampered value
+var ErrDecrypt = errors.New("failed to decrypt value")
+
+type (
+	// KeyProvider provides the AES key of the encrypted namespaces, e.g. from an environment variable or a KMS
+	KeyProvider interface {
+		Key(context.Context) ([]byte, error)
+	}
+
+	// EnvKeyProvider reads the hex encoded key from the environment variable of its name
+	EnvKeyProvider string
+
+	// kvStoreWithEncryption is an implementation of KVStore, which encrypts the values of the namespaces by AES-GCM.
+	// The keys are kept in plain text, so the iteration and the range queries work as well.
+	kvStoreWithEncryption struct {
+		store      KVStore
+		provider   KeyProvider
+		namespaces map[string]struct{}
+		aead       cipher.AEAD
+	}
+)
+
+// Key returns the key in the environment variable
+func (p EnvKeyProvider) Key(_ context.Context) ([]byte, error) {
+	v, ok := os.LookupEnv(string(p))
+	if !ok {
+		return nil, errors.Errorf("environment variable %s of the db encryption key is not set", string(p))
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(v))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the db encryption key in %s", string(p))
+	}
+	return key, nil
+}
+
+// NewKVStoreWithEncryption wraps the store to encrypt the values of the namespaces, by the 16, 24 or 32 bytes key of
+// the provider, which is read when the store is started
+func NewKVStoreWithEncryption(store KVStore, provider KeyProvider, namespaces []string) KVStore {
+	kve := &kvStoreWithEncryption{
+		store:      store,
+		provider:   provider,
+		namespaces: make(map[string]struct{}, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		kve.namespaces[ns] = struct{}{}
+	}
+	return kve
+}
+
+// Start reads the key and starts the store
+func (kve *kvStoreWithEncryption) Start(ctx context.Context) error {
+	key, err := kve.provider.Key(ctx)
+	if err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return errors.Wrap(err, "invalid db encryption key")
+	}
+	if kve.aead, err = cipher.NewGCM(block); err != nil {
+		return err
+	}
+	return kve.store.Start(ctx)
+}
+
+// Stop stops the store
+func (kve *kvStoreWithEncryption) Stop(ctx context.Context) error {
+	return kve.store.Stop(ctx)
+}
+
+// Put encrypts the value if the namespace is encrypted, and puts the record
+func (kve *kvStoreWithEncryption) Put(namespace string, key, value []byte) error {
+	if !kve.encrypted(namespace) {
+		return kve.store.Put(namespace, key, value)
+	}
+	sealed, err := kve.seal(namespace, key, value)
+	if err != nil {
+		return err
+	}
+	return kve.store.Put(namespace, key, sealed)
+}
+
+// Get gets the record and decrypts the value if the namespace is encrypted
+func (kve *kvStoreWithEncryption) Get(namespace string, key []byte) ([]byte, error) {
+	value, err := kve.store.Get(namespace, key)
+	if err != nil || !kve.encrypted(namespace) {
+		return value, err
+	}
+	return kve.open(namespace, key, value)
+}
+
+// Delete deletes the record
+func (kve *kvStoreWithEncryption) Delete(namespace string, key []byte) error {
+	return kve.store.Delete(namespace, key)
+}
+
+// WriteBatch encrypts the values of the encrypted namespaces in a copy of the batch, and commits the copy
+func (kve *kvStoreWithEncryption) WriteBatch(kvsb batch.KVStoreBatch) error {
+	var err error
+	b := kvsb.Translate(func(wi *batch.WriteInfo) *batch.WriteInfo {
+		if err != nil || wi.WriteType() != batch.Put || !kve.encrypted(wi.Namespace()) {
+			return wi
+		}
+		sealed, e := kve.seal(wi.Namespace(), wi.Key(), wi.Value())
+		if e != nil {
+			err = e
+			return wi
+		}
+		return batch.NewWriteInfo(batch.Put, wi.Namespace(), wi.Key(), sealed, wi.ErrorFormat(), wi.ErrorArgs())
+	})
+	if err != nil {
+		return err
+	}
+	// the copy doesn't keep the fill percents of the buckets
+	for i := 0; i < b.Size(); i++ {
+		write, err := b.Entry(i)
+		if err != nil {
+			return err
+		}
+		if p, ok := kvsb.CheckFillPercent(write.Namespace()); ok {
+			b.AddFillPercent(write.Namespace(), p)
+		}
+	}
+	return kve.store.WriteBatch(b)
+}
+
+// Filter returns <k, v> pair in a bucket that meet the condition, on the decrypted values
+func (kve *kvStoreWithEncryption) Filter(namespace string, cond Condition, minKey, maxKey []byte) ([][]byte, [][]byte, error) {
+	if !kve.encrypted(namespace) {
+		return kve.store.Filter(namespace, cond, minKey, maxKey)
+	}
+	fk, fv, err := kve.store.Filter(namespace, func(k, v []byte) bool {
+		value, err := kve.open(namespace, k, v)
+		return err == nil && cond(k, value)
+	}, minKey, maxKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range fv {
+		if fv[i], err = kve.open(namespace, fk[i], fv[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fk, fv, nil
+}
+
+// Iterate returns an iterator of the records in a bucket, which decrypts the values
+func (kve *kvStoreWithEncryption) Iterate(namespace string, start, end []byte) (Iterator, error) {
+	it, err := kve.store.Iterate(namespace, start, end)
+	if err != nil || !kve.encrypted(namespace) {
+		return it, err
+	}
+	return &decryptIterator{Iterator: it, kve: kve, namespace: namespace}, nil
+}
+
+// Backup backs up the store, whose values are kept encrypted in the backup
+func (kve *kvStoreWithEncryption) Backup(ctx context.Context, dir string) error {
+	b, ok := kve.store.(Backuper)
+	if !ok {
+		return errors.Errorf("%T cannot be backed up", kve.store)
+	}
+	return b.Backup(ctx, dir)
+}
+
+func (kve *kvStoreWithEncryption) encrypted(namespace string) bool {
+	_, ok := kve.namespaces[namespace]
+	return ok
+}
+
+// seal returns the nonce followed by the ciphertext of the value, which is bound to the namespace and the key
+func (kve *kvStoreWithEncryption) seal(namespace string, key, value []byte) ([]byte, error) {
+	if kve.aead == nil {
+		return nil, errors.New("encrypted KVStore is not started")
+	}
+	nonceSize := kve.aead.NonceSize()
+	sealed := make([]byte, nonceSize, nonceSize+len(value)+kve.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, sealed); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	return kve.aead.Seal(sealed, sealed, value, recordKey(namespace, key)), nil
+}
+
+func (kve *kvStoreWithEncryption) open(namespace string, key, sealed []byte) ([]byte, error) {
+	if kve.aead == nil {
+		return nil, errors.New("encrypted KVStore is not started")
+	}
+	nonceSize := kve.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.Wrapf(ErrDecrypt, "value of key %x in %s is too short", key, namespace)
+	}
+	value, err := kve.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], recordKey(namespace, key))
+	if err != nil {
+		return nil, errors.Wrapf(ErrDecrypt, "value of key %x in %s: %v", key, namespace, err)
+	}
+	return value, nil
+}
+
+// decryptIterator decrypts the values of the iterator
+type decryptIterator struct {
+	Iterator
+	kve       *kvStoreWithEncryption
+	namespace string
+	value     []byte
+	err       error
+}
+
+func (it *decryptIterator) Next() bool {
+	if it.err != nil || !it.Iterator.Next() {
+		it.value = nil
+		return false
+	}
+	it.value, it.err = it.kve.open(it.namespace, it.Iterator.Key(), it.Iterator.Value())
+	return it.err == nil
+}
+
+func (it *decryptIterator) Value() []byte { return it.value }
+
+func (it *decryptIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+const testEncryptionKeyEnv = "IOTEX_TEST_DB_ENCRYPTION_KEY"
+
+func TestEnvKeyProvider(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	p := EnvKeyProvider(testEncryptionKeyEnv)
+	require.NoError(os.Unsetenv(testEncryptionKeyEnv))
+	_, err := p.Key(ctx)
+	require.Error(err)
+	require.NoError(os.Setenv(testEncryptionKeyEnv, "xyz"))
+	defer os.Unsetenv(testEncryptionKeyEnv)
+	_, err = p.Key(ctx)
+	require.Error(err)
+	require.NoError(os.Setenv(testEncryptionKeyEnv, " 000102030405060708090a0b0c0d0e0f\n"))
+	key, err := p.Key(ctx)
+	require.NoError(err)
+	require.Equal([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, key)
+}
+
+func TestKVStoreWithEncryption(t *testing.T) {
+	require.NoError(t, os.Setenv(testEncryptionKeyEnv, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"))
+	defer os.Unsetenv(testEncryptionKeyEnv)
+
+	for _, backend := range []string{config.BoltBackend, config.BadgerBackend, config.PebbleBackend} {
+		t.Run(backend, func(t *testing.T) {
+			require := require.New(t)
+			path, err := testutil.PathOfTempFile("test-encryption-" + backend)
+			require.NoError(err)
+			testutil.CleanupPath(t, path)
+			defer testutil.CleanupPath(t, path)
+
+			cfg := config.Default.DB
+			cfg.DbPath = path
+			cfg.Backend = backend
+			cfg.EncryptedNamespaces = []string{bucket1}
+			cfg.EncryptionKeyEnv = testEncryptionKeyEnv
+			kv, err := NewKVStore(cfg)
+			require.NoError(err)
+			kve, ok := kv.(*kvStoreWithEncryption)
+			require.True(ok)
+			ctx := context.Background()
+			require.NoError(kv.Start(ctx))
+			defer func() {
+				require.NoError(kv.Stop(ctx))
+			}()
+
+			require.NoError(kv.Put(bucket1, testK1[0], testV1[0]))
+			require.NoError(kv.Put(bucket2, testK2[0], testV2[0]))
+			b := batch.NewBatch()
+			b.Put(bucket1, testK1[1], testV1[1], "")
+			b.Put(bucket2, testK2[1], testV2[1], "")
+			require.NoError(kv.WriteBatch(b))
+
+			// the values of the encrypted namespace are sealed in the store
+			for i := 0; i < 2; i++ {
+				v, err := kv.Get(bucket1, testK1[i])
+				require.NoError(err)
+				require.Equal(testV1[i], v)
+				v, err = kve.store.Get(bucket1, testK1[i])
+				require.NoError(err)
+				require.False(bytes.Contains(v, testV1[i]))
+				v, err = kve.store.Get(bucket2, testK2[i])
+				require.NoError(err)
+				require.Equal(testV2[i], v)
+			}
+
+			it, err := kv.Iterate(bucket1, nil, nil)
+			require.NoError(err)
+			var values [][]byte
+			for it.Next() {
+				values = append(values, append([]byte{}, it.Value()...))
+			}
+			require.NoError(it.Error())
+			require.NoError(it.Close())
+			require.ElementsMatch(testV1[:2], values)
+
+			fk, fv, err := kv.Filter(bucket1, func(k, v []byte) bool {
+				return bytes.Equal(v, testV1[1])
+			}, nil, nil)
+			require.NoError(err)
+			require.Equal([][]byte{testK1[1]}, fk)
+			require.Equal([][]byte{testV1[1]}, fv)
+
+			// a value moved to another key fails to be decrypted
+			sealed, err := kve.store.Get(bucket1, testK1[0])
+			require.NoError(err)
+			require.NoError(kve.store.Put(bucket1, testK1[2], sealed))
+			_, err = kv.Get(bucket1, testK1[2])
+			require.Equal(ErrDecrypt, errors.Cause(err))
+			it, err = kv.Iterate(bucket1, testK1[2], nil)
+			require.NoError(err)
+			require.False(it.Next())
+			require.Equal(ErrDecrypt, errors.Cause(it.Error()))
+			require.NoError(it.Close())
+
+			require.NoError(kv.Delete(bucket1, testK1[0]))
+			_, err = kv.Get(bucket1, testK1[0])
+			require.Equal(ErrNotExist, errors.Cause(err))
+		})
+	}
+}
+
+func TestKVStoreWithEncryptionKey(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	require.NoError(os.Setenv(testEncryptionKeyEnv, "0001020304"))
+	defer os.Unsetenv(testEncryptionKeyEnv)
+	kv := NewKVStoreWithEncryption(NewMemKVStore(), EnvKeyProvider(testEncryptionKeyEnv), []string{bucket1})
+	require.Error(kv.Start(ctx))
+	require.Error(kv.Put(bucket1, testK1[0], testV1[0]))
+
+	path, err := testutil.PathOfTempFile("test-encryption-key")
+	require.NoError(err)
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	require.NoError(os.Setenv(testEncryptionKeyEnv, "000102030405060708090a0b0c0d0e0f"))
+	kv = NewKVStoreWithEncryption(NewBoltDB(cfg), EnvKeyProvider(testEncryptionKeyEnv), []string{bucket1})
+	require.NoError(kv.Start(ctx))
+	require.NoError(kv.Put(bucket1, testK1[0], testV1[0]))
+	require.NoError(kv.Stop(ctx))
+
+	// the value is not decrypted by another key
+	require.NoError(os.Setenv(testEncryptionKeyEnv, "0f0e0d0c0b0a09080706050403020100"))
+	kv = NewKVStoreWithEncryption(NewBoltDB(cfg), EnvKeyProvider(testEncryptionKeyEnv), []string{bucket1})
+	require.NoError(kv.Start(ctx))
+	_, err = kv.Get(bucket1, testK1[0])
+	require.Equal(ErrDecrypt, errors.Cause(err))
+	require.NoError(kv.Stop(ctx))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	opGet        = "get"
+	opPut        = "put"
+	opDelete     = "delete"
+	opWriteBatch = "writeBatch"
+
+	// mixedNamespace labels a batch writing to more than one namespace
+	mixedNamespace = "mixed"
+	// otherNamespace labels the namespaces named by binary keys, e.g. the address buckets of the indexer, which would
+	// make a label value per address
+	otherNamespace = "other"
+	// maxNamespaceLabel is the length of the longest namespace labeled by its name
+	maxNamespaceLabel = 16
+)
+
+var dbLatencyMtc = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "iotex_db_operation_latency_seconds",
+		Help:    "Latency of the db operations",
+		Buckets: []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5},
+	},
+	[]string{"db", "op", "namespace"},
+)
+
+func init() {
+	prometheus.MustRegister(dbLatencyMtc)
+}
+
+// opObserver records the latency of the operations of a db, and logs the operations slower than the threshold
+type opObserver struct {
+	name            string
+	slowOpThreshold time.Duration
+}
+
+func newOpObserver(cfg config.DB) *opObserver {
+	return &opObserver{
+		name:            filepath.Base(cfg.DbPath),
+		slowOpThreshold: cfg.SlowOpThreshold,
+	}
+}
+
+// observe records an operation on the namespace which started at start. A nil observer records nothing.
+func (o *opObserver) observe(op, namespace string, start time.Time) {
+	if o == nil {
+		return
+	}
+	duration := time.Since(start)
+	namespace = namespaceLabel(namespace)
+	dbLatencyMtc.WithLabelValues(o.name, op, namespace).Observe(duration.Seconds())
+	if o.slowOpThreshold <= 0 || duration < o.slowOpThreshold {
+		return
+	}
+	log.L().Warn("Slow db operation.",
+		zap.String("db", o.name),
+		zap.String("op", op),
+		zap.String("namespace", namespace),
+		zap.Duration("duration", duration))
+}
+
+// batchNamespace returns the namespace written by the batch, or mixedNamespace if it writes to more than one
+func batchNamespace(kvsb batch.KVStoreBatch) string {
+	var (
+		namespace string
+		first     = true
+	)
+	for i := 0; i < kvsb.Size(); i++ {
+		write, err := kvsb.Entry(i)
+		if err != nil {
+			continue
+		}
+		if ns := write.Namespace(); first {
+			namespace, first = ns, false
+		} else if ns != namespace {
+			return mixedNamespace
+		}
+	}
+	return namespace
+}
+
+func namespaceLabel(namespace string) string {
+	if len(namespace) > maxNamespaceLabel {
+		return otherNamespace
+	}
+	for _, c := range []byte(namespace) {
+		if c < 0x20 || c > 0x7e {
+			return otherNamespace
+		}
+	}
+	return namespace
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// latencySamples returns the number of the latency samples of the operation
+func latencySamples(t *testing.T, name, op, namespace string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "iotex_db_operation_latency_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["db"] == name && labels["op"] == op && labels["namespace"] == namespace {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestOpObserver(t *testing.T) {
+	for _, backend := range []string{config.BoltBackend, config.BadgerBackend, config.PebbleBackend} {
+		t.Run(backend, func(t *testing.T) {
+			require := require.New(t)
+			path, err := testutil.PathOfTempFile("test-metrics-" + backend)
+			require.NoError(err)
+			testutil.CleanupPath(t, path)
+			defer testutil.CleanupPath(t, path)
+
+			cfg := config.Default.DB
+			cfg.DbPath = path
+			cfg.Backend = backend
+			// every operation is logged as slow
+			cfg.SlowOpThreshold = time.Nanosecond
+			kv, err := NewKVStore(cfg)
+			require.NoError(err)
+			ctx := context.Background()
+			require.NoError(kv.Start(ctx))
+			defer func() {
+				require.NoError(kv.Stop(ctx))
+			}()
+
+			name := filepath.Base(path)
+			require.NoError(kv.Put(bucket1, testK1[0], testV1[0]))
+			_, err = kv.Get(bucket1, testK1[0])
+			require.NoError(err)
+			require.NoError(kv.Delete(bucket1, testK1[0]))
+			b := batch.NewBatch()
+			b.Put(bucket1, testK1[1], testV1[1], "")
+			b.Put(bucket2, testK2[1], testV2[1], "")
+			require.NoError(kv.WriteBatch(b))
+			require.EqualValues(1, latencySamples(t, name, opPut, bucket1))
+			require.EqualValues(1, latencySamples(t, name, opGet, bucket1))
+			require.EqualValues(1, latencySamples(t, name, opDelete, bucket1))
+			require.EqualValues(1, latencySamples(t, name, opWriteBatch, mixedNamespace))
+		})
+	}
+}
+
+func TestBatchNamespace(t *testing.T) {
+	require := require.New(t)
+	b := batch.NewBatch()
+	require.Equal("", batchNamespace(b))
+	b.Put(bucket1, testK1[0], testV1[0], "")
+	b.Delete(bucket1, testK1[1], "")
+	require.Equal(bucket1, batchNamespace(b))
+	b.Put(bucket2, testK2[0], testV2[0], "")
+	require.Equal(mixedNamespace, batchNamespace(b))
+}
+
+func TestNamespaceLabel(t *testing.T) {
+	require := require.New(t)
+	require.Equal("Account", namespaceLabel("Account"))
+	require.Equal("", namespaceLabel(""))
+	require.Equal(otherNamespace, namespaceLabel(string([]byte{0x01, 'a'})))
+	require.Equal(otherNamespace, namespaceLabel("a_namespace_longer_than_sixteen"))
+}
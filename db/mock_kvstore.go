@@ -229,6 +229,21 @@ func (mr *MockKVStoreMockRecorder) Filter(arg0, arg1, arg2, arg3 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filter", reflect.TypeOf((*MockKVStore)(nil).Filter), arg0, arg1, arg2, arg3)
 }
 
+// Iterate mocks base method
+func (m *MockKVStore) Iterate(arg0 string, arg1, arg2 []byte) (Iterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Iterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockKVStoreMockRecorder) Iterate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockKVStore)(nil).Iterate), arg0, arg1, arg2)
+}
+
 // MockKVStoreWithRange is a mock of KVStoreWithRange interface
 type MockKVStoreWithRange struct {
 	ctrl     *gomock.Controller
@@ -353,6 +368,21 @@ func (mr *MockKVStoreWithRangeMockRecorder) Filter(arg0, arg1, arg2, arg3 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filter", reflect.TypeOf((*MockKVStoreWithRange)(nil).Filter), arg0, arg1, arg2, arg3)
 }
 
+// Iterate mocks base method
+func (m *MockKVStoreWithRange) Iterate(arg0 string, arg1, arg2 []byte) (Iterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Iterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockKVStoreWithRangeMockRecorder) Iterate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockKVStoreWithRange)(nil).Iterate), arg0, arg1, arg2)
+}
+
 // Range mocks base method
 func (m *MockKVStoreWithRange) Range(arg0 string, arg1 []byte, arg2 uint64) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -492,6 +522,21 @@ func (mr *MockKVStoreForRangeIndexMockRecorder) Filter(arg0, arg1, arg2, arg3 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filter", reflect.TypeOf((*MockKVStoreForRangeIndex)(nil).Filter), arg0, arg1, arg2, arg3)
 }
 
+// Iterate mocks base method
+func (m *MockKVStoreForRangeIndex) Iterate(arg0 string, arg1, arg2 []byte) (Iterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Iterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockKVStoreForRangeIndexMockRecorder) Iterate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockKVStoreForRangeIndex)(nil).Iterate), arg0, arg1, arg2)
+}
+
 // Insert mocks base method
 func (m *MockKVStoreForRangeIndex) Insert(arg0 []byte, arg1 uint64, arg2 []byte) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package mptrie
+
+import (
+	"golang.org/x/sync/errgroup"
+)
+
+// dirtySubtries returns the children of the root which are not hashed yet, the subtries under them are disjoint, so
+// they can be hashed concurrently
+func (mpt *merklePatriciaTrie) dirtySubtries() []node {
+	root, ok := mpt.root.(*branchNode)
+	if !ok {
+		return nil
+	}
+	var subtries []node
+	for index := 0; index < radix; index++ {
+		c, ok := root.children[byte(index)]
+		if !ok {
+			continue
+		}
+		switch n := c.(type) {
+		case *branchNode:
+			if n.hashVal == nil {
+				subtries = append(subtries, n)
+			}
+		case *extensionNode:
+			if n.hashVal == nil {
+				subtries = append(subtries, n)
+			}
+		case *leafNode:
+			if n.hashVal == nil {
+				subtries = append(subtries, n)
+			}
+		}
+	}
+	return subtries
+}
+
+// hashConcurrently hashes the subtries with at most workers goroutines. The hashes and the serialized nodes are cached
+// in the nodes, so flushing the trie afterwards only writes the nodes into the kv store, in the same order as before.
+func hashConcurrently(subtries []node, workers int) error {
+	if workers <= 1 || len(subtries) < 2 {
+		return nil
+	}
+	var (
+		g   errgroup.Group
+		sem = make(chan struct{}, workers)
+	)
+	for _, n := range subtries {
+		n := n
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			_, err := n.Hash()
+			return err
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package mptrie
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/db/trie"
+)
+
+// recordingKVStore records the keys put into the store in order
+type recordingKVStore struct {
+	trie.KVStore
+	puts [][]byte
+}
+
+func (kv *recordingKVStore) Put(key []byte, value []byte) error {
+	kv.puts = append(kv.puts, key)
+	return kv.KVStore.Put(key, value)
+}
+
+func TestHashConcurrently(t *testing.T) {
+	require := require.New(t)
+
+	commit := func(workers int) ([]byte, [][]byte) {
+		kv := &recordingKVStore{KVStore: trie.NewMemKVStore()}
+		tr, err := New(KVStoreOption(kv), KeyLengthOption(20), AsyncOption(), HashWorkersOption(workers))
+		require.NoError(err)
+		require.NoError(tr.Start(context.Background()))
+		for round := 0; round < 2; round++ {
+			for i := 0; i < 500; i++ {
+				key := hash.Hash160b([]byte{byte(round), byte(i), byte(i >> 8)})
+				require.NoError(tr.Upsert(key[:], []byte{byte(i)}))
+			}
+			if round == 1 {
+				for i := 0; i < 100; i++ {
+					key := hash.Hash160b([]byte{0, byte(i), byte(i >> 8)})
+					require.NoError(tr.Delete(key[:]))
+				}
+			}
+			_, err := tr.RootHash()
+			require.NoError(err)
+		}
+		root, err := tr.RootHash()
+		require.NoError(err)
+		return root, kv.puts
+	}
+	root, puts := commit(1)
+	for _, workers := range []int{2, 8} {
+		r, p := commit(workers)
+		require.Equal(root, r)
+		require.Equal(puts, p)
+	}
+
+	_, err := New(HashWorkersOption(0))
+	require.Error(err)
+}
+
+func TestTwoLayerTrieHashConcurrently(t *testing.T) {
+	require := require.New(t)
+
+	commit := func() []byte {
+		tlt := NewTwoLayerTrie(trie.NewMemKVStore(), "root")
+		require.NoError(tlt.Start(context.Background()))
+		for ns := 0; ns < 4; ns++ {
+			layerOneKey := hash.Hash160b([]byte{byte(ns)})
+			for i := 0; i < 200; i++ {
+				key := hash.Hash160b([]byte{byte(ns), byte(i)})
+				require.NoError(tlt.Upsert(layerOneKey[:], key[:], []byte{byte(i)}))
+			}
+		}
+		root, err := tlt.RootHash()
+		require.NoError(err)
+		return root
+	}
+	root := commit()
+	for i := 0; i < 3; i++ {
+		require.Equal(root, commit())
+	}
+}
@@ -7,6 +7,8 @@
 package mptrie
 
 import (
+	"bytes"
+
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/db/trie"
@@ -18,6 +20,18 @@ type LeafIterator struct {
 	stack []node
 }
 
+// OrderedLeafIterator goes through the leaves in the order of their keys, starting from a given key
+type OrderedLeafIterator struct {
+	start []byte
+	stack []prefixedNode
+}
+
+// prefixedNode is a node along with the key prefix of the path to it
+type prefixedNode struct {
+	node   node
+	prefix []byte
+}
+
 // NewLeafIterator returns a new leaf iterator
 func NewLeafIterator(tr trie.Trie) (trie.Iterator, error) {
 	mpt, ok := tr.(*merklePatriciaTrie)
@@ -63,3 +77,66 @@ func (li *LeafIterator) Next() ([]byte, []byte, error) {
 
 	return nil, nil, trie.ErrEndOfIterator
 }
+
+// NewOrderedLeafIterator returns an iterator going through the leaves in the order of their keys, from the first key
+// not less than start. The subtries whose keys are all less than start are skipped without being loaded.
+func NewOrderedLeafIterator(tr trie.Trie, start []byte) (trie.Iterator, error) {
+	mpt, ok := tr.(*merklePatriciaTrie)
+	if !ok {
+		return nil, errors.New("trie is not supported type")
+	}
+	return &OrderedLeafIterator{
+		start: start,
+		stack: []prefixedNode{{node: mpt.root}},
+	}, nil
+}
+
+// Next moves iterator to next node
+func (li *OrderedLeafIterator) Next() ([]byte, []byte, error) {
+	for len(li.stack) > 0 {
+		size := len(li.stack)
+		top := li.stack[size-1]
+		li.stack = li.stack[:size-1]
+		switch n := top.node.(type) {
+		case *hashNode:
+			loaded, err := n.LoadNode()
+			if err != nil {
+				return nil, nil, err
+			}
+			li.stack = append(li.stack, prefixedNode{node: loaded, prefix: top.prefix})
+		case *leafNode:
+			key, value := n.Key(), n.Value()
+			if bytes.Compare(key, li.start) < 0 {
+				continue
+			}
+			return append(key[:0:0], key...), append(value[:0:0], value...), nil
+		case *branchNode:
+			// push the children in the reverse order, so that they are popped in the order of the keys
+			for index := radix - 1; index >= 0; index-- {
+				c, ok := n.children[byte(index)]
+				if !ok {
+					continue
+				}
+				li.push(c, append(top.prefix[:len(top.prefix):len(top.prefix)], byte(index)))
+			}
+		case *extensionNode:
+			li.push(n.child, append(top.prefix[:len(top.prefix):len(top.prefix)], n.path...))
+		default:
+			return nil, nil, errors.New("unexpected node type")
+		}
+	}
+
+	return nil, nil, trie.ErrEndOfIterator
+}
+
+// push pushes the node unless all the keys under the prefix are less than start
+func (li *OrderedLeafIterator) push(n node, prefix []byte) {
+	l := len(prefix)
+	if l > len(li.start) {
+		l = len(li.start)
+	}
+	if bytes.Compare(prefix[:l], li.start[:l]) < 0 {
+		return
+	}
+	li.stack = append(li.stack, prefixedNode{node: n, prefix: prefix})
+}
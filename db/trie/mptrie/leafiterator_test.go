@@ -55,3 +55,47 @@ func TestIterator(t *testing.T) {
 		require.Equal(item.v, found[item.k], "key: %s", item.k)
 	}
 }
+
+func TestOrderedIterator(t *testing.T) {
+	require := require.New(t)
+	keys := []string{"block", "chain", "chair", "iotex", "night", "puppy"}
+
+	for _, async := range []bool{true, false} {
+		opts := []Option{KVStoreOption(trie.NewMemKVStore()), KeyLengthOption(5)}
+		if async {
+			opts = append(opts, AsyncOption())
+		}
+		mpt, err := New(opts...)
+		require.NoError(err)
+		require.NoError(mpt.Start(context.Background()))
+		// insert in a different order than the keys
+		for i := len(keys) - 1; i >= 0; i-- {
+			require.NoError(mpt.Upsert([]byte(keys[i]), []byte{byte(i)}))
+		}
+
+		for _, c := range []struct {
+			start    string
+			expected []string
+		}{
+			{"\x00\x00\x00\x00\x00", keys},
+			{"chain", keys[1:]},
+			{"chaio", keys[2:]},
+			{"d0000", keys[3:]},
+			{"zzzzz", nil},
+		} {
+			iter, err := NewOrderedLeafIterator(mpt, []byte(c.start))
+			require.NoError(err)
+			var found []string
+			for {
+				k, v, err := iter.Next()
+				if err != nil {
+					require.Equal(trie.ErrEndOfIterator, err)
+					break
+				}
+				require.Equal(keys[v[0]], string(k))
+				found = append(found, string(k))
+			}
+			require.Equal(c.expected, found, "start: %s", c.start)
+		}
+	}
+}
@@ -9,6 +9,7 @@ package mptrie
 import (
 	"bytes"
 	"context"
+	"runtime"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -47,6 +48,7 @@ type (
 		kvStore       trie.KVStore
 		hashFunc      HashFunc
 		async         bool
+		hashWorkers   int
 		emptyRootHash []byte
 	}
 )
@@ -104,12 +106,25 @@ func AsyncOption() Option {
 	}
 }
 
+// HashWorkersOption sets the number of goroutines hashing the subtries of the root concurrently when an async trie
+// is committed, 1 means the trie is hashed sequentially
+func HashWorkersOption(workers int) Option {
+	return func(mpt *merklePatriciaTrie) error {
+		if workers <= 0 {
+			return errors.New("invalid number of hash workers")
+		}
+		mpt.hashWorkers = workers
+		return nil
+	}
+}
+
 // New creates a trie with DB filename
 func New(options ...Option) (trie.Trie, error) {
 	t := &merklePatriciaTrie{
-		keyLength: 20,
-		hashFunc:  DefaultHashFunc,
-		kvStore:   trie.NewMemKVStore(),
+		keyLength:   20,
+		hashFunc:    DefaultHashFunc,
+		kvStore:     trie.NewMemKVStore(),
+		hashWorkers: runtime.NumCPU(),
 	}
 	for _, opt := range options {
 		if err := opt(t); err != nil {
@@ -142,6 +157,9 @@ func (mpt *merklePatriciaTrie) Stop(_ context.Context) error {
 
 func (mpt *merklePatriciaTrie) RootHash() ([]byte, error) {
 	if mpt.async {
+		if err := hashConcurrently(mpt.dirtySubtries(), mpt.hashWorkers); err != nil {
+			return nil, err
+		}
 		if err := mpt.root.Flush(); err != nil {
 			return nil, err
 		}
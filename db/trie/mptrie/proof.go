@@ -0,0 +1,137 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package mptrie
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/triepb"
+)
+
+// ErrInvalidProof indicates a proof does not match the root hash or the key
+var ErrInvalidProof = errors.New("invalid merkle proof")
+
+// Proof returns the serialized nodes on the path from the root to the key, root first. If the key does not exist,
+// the path ends where the key diverges from the trie, which proves the absence of the key.
+func (mpt *merklePatriciaTrie) Proof(key []byte) ([][]byte, error) {
+	mpt.mutex.Lock()
+	defer mpt.mutex.Unlock()
+
+	trieMtc.WithLabelValues("root", "Proof").Inc()
+	kt, err := mpt.checkKeyType(key)
+	if err != nil {
+		return nil, err
+	}
+	// the nodes of an async trie are written to the kv store on calculating the root hash
+	rootHash, err := mpt.RootHash()
+	if err != nil {
+		return nil, err
+	}
+	if mpt.isEmptyRootHash(rootHash) {
+		pb, err := newEmptyRootBranchNode(mpt).proto(false)
+		if err != nil {
+			return nil, err
+		}
+		ser, err := proto.Marshal(pb)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{ser}, nil
+	}
+	var (
+		proof  [][]byte
+		h      = rootHash
+		offset = 0
+	)
+	for {
+		ser, err := mpt.kvStore.Get(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get node %x", h)
+		}
+		proof = append(proof, ser)
+		next, consumed, err := nextProofNode(ser, kt, offset)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return proof, nil
+		}
+		h = next
+		offset += consumed
+	}
+}
+
+// VerifyProof checks the proof of the key against the root hash, and returns the value of the key. It returns
+// trie.ErrNotExist if the proof proves the absence of the key.
+func VerifyProof(rootHash []byte, key []byte, proof [][]byte, hashFunc HashFunc) ([]byte, error) {
+	if hashFunc == nil {
+		hashFunc = DefaultHashFunc
+	}
+	h := rootHash
+	offset := 0
+	for i, ser := range proof {
+		if !bytes.Equal(hashFunc(ser), h) {
+			return nil, errors.Wrapf(ErrInvalidProof, "hash of node %d does not match", i)
+		}
+		next, consumed, err := nextProofNode(ser, key, offset)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			h = next
+			offset += consumed
+			continue
+		}
+		if i != len(proof)-1 {
+			return nil, errors.Wrap(ErrInvalidProof, "redundant nodes after the end of the path")
+		}
+		pb := triepb.NodePb{}
+		if err := proto.Unmarshal(ser, &pb); err != nil {
+			return nil, errors.Wrap(ErrInvalidProof, err.Error())
+		}
+		if leaf := pb.GetLeaf(); leaf != nil && bytes.Equal(leaf.Path, key) {
+			return leaf.Value, nil
+		}
+		return nil, errors.Wrapf(trie.ErrNotExist, "key %x does not exist", key)
+	}
+	return nil, errors.Wrap(ErrInvalidProof, "path does not end")
+}
+
+// nextProofNode returns the hash of the next node on the path to the key, and the number of key bytes consumed by
+// the node. The hash is nil if the path ends at the node.
+func nextProofNode(ser []byte, key []byte, offset int) ([]byte, int, error) {
+	pb := triepb.NodePb{}
+	if err := proto.Unmarshal(ser, &pb); err != nil {
+		return nil, 0, errors.Wrap(ErrInvalidProof, err.Error())
+	}
+	if pb.GetLeaf() == nil && offset >= len(key) {
+		return nil, 0, errors.Wrap(ErrInvalidProof, "path is longer than the key")
+	}
+	switch {
+	case pb.GetBranch() != nil:
+		for _, child := range pb.GetBranch().Branches {
+			if child.Index == uint32(key[offset]) {
+				return child.Path, 1, nil
+			}
+		}
+		return nil, 0, nil
+	case pb.GetExtend() != nil:
+		ext := pb.GetExtend()
+		if !bytes.HasPrefix(key[offset:], ext.Path) {
+			return nil, 0, nil
+		}
+		return ext.Value, len(ext.Path), nil
+	case pb.GetLeaf() != nil:
+		return nil, 0, nil
+	default:
+		return nil, 0, errors.Wrap(ErrInvalidProof, "invalid node type")
+	}
+}
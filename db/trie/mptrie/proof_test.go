@@ -0,0 +1,71 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package mptrie
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/db/trie"
+)
+
+func TestProof(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		require := require.New(t)
+		opts := []Option{KeyLengthOption(8)}
+		if async {
+			opts = append(opts, AsyncOption())
+		}
+		tr, err := New(opts...)
+		require.NoError(err)
+		require.NoError(tr.Start(context.Background()))
+
+		// proof of absence in an empty trie
+		root, err := tr.RootHash()
+		require.NoError(err)
+		proof, err := tr.Proof(cat)
+		require.NoError(err)
+		_, err = VerifyProof(root, cat, proof, nil)
+		require.Equal(trie.ErrNotExist, errors.Cause(err))
+
+		keys := [][]byte{ham, car, cat, egg, dog, fox, cow, ant}
+		for i, k := range keys {
+			require.NoError(tr.Upsert(k, testV[i]))
+		}
+		root, err = tr.RootHash()
+		require.NoError(err)
+		for i, k := range keys {
+			proof, err := tr.Proof(k)
+			require.NoError(err)
+			value, err := VerifyProof(root, k, proof, nil)
+			require.NoError(err)
+			require.Equal(testV[i], value)
+		}
+		for _, k := range [][]byte{rat, br1, cl2} {
+			proof, err := tr.Proof(k)
+			require.NoError(err)
+			_, err = VerifyProof(root, k, proof, nil)
+			require.Equal(trie.ErrNotExist, errors.Cause(err))
+		}
+
+		proof, err = tr.Proof(cat)
+		require.NoError(err)
+		// a proof of another key, against another root, or with a tampered node is invalid
+		_, err = VerifyProof(root, rat, proof, nil)
+		require.Error(err)
+		_, err = VerifyProof(emptyTrieRootHash, cat, proof, nil)
+		require.Equal(ErrInvalidProof, errors.Cause(err))
+		proof[len(proof)-1] = append([]byte{}, proof[len(proof)-1]...)
+		proof[len(proof)-1][len(proof[len(proof)-1])-1]++
+		_, err = VerifyProof(root, cat, proof, nil)
+		require.Equal(ErrInvalidProof, errors.Cause(err))
+		require.NoError(tr.Stop(context.Background()))
+	}
+}
@@ -125,6 +125,22 @@ func (tlt *twoLayerTrie) stop(ctx context.Context, hkey string, lt *layerTwo) (e
 }
 
 func (tlt *twoLayerTrie) flush(ctx context.Context) error {
+	// hash the dirty layer two tries concurrently before writing their nodes one trie after another
+	var (
+		subtries []node
+		workers  = 1
+	)
+	for _, lt := range tlt.layerTwoMap {
+		if mpt, ok := lt.tr.(*merklePatriciaTrie); ok && lt.dirty {
+			subtries = append(subtries, mpt.dirtySubtries()...)
+			if mpt.hashWorkers > workers {
+				workers = mpt.hashWorkers
+			}
+		}
+	}
+	if err := hashConcurrently(subtries, workers); err != nil {
+		return err
+	}
 	for hkey, lt := range tlt.layerTwoMap {
 		if err := tlt.stop(ctx, hkey, lt); err != nil {
 			return err
@@ -189,3 +205,24 @@ func (tlt *twoLayerTrie) Delete(layerOneKey []byte, layerTwoKey []byte) error {
 
 	return nil
 }
+
+func (tlt *twoLayerTrie) Proof(layerOneKey []byte, layerTwoKey []byte) ([][]byte, [][]byte, error) {
+	// flush the layer two tries, so that the layer one trie holds their latest roots
+	if err := tlt.flush(context.Background()); err != nil {
+		return nil, nil, err
+	}
+	layerOneProof, err := tlt.layerOne.Proof(layerOneKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	lt, err := tlt.layerTwoTrie(layerOneKey, len(layerTwoKey))
+	if err != nil {
+		return nil, nil, err
+	}
+	layerTwoProof, err := lt.tr.Proof(layerTwoKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return layerOneProof, layerTwoProof, nil
+}
@@ -47,6 +47,8 @@ type (
 		SetRootHash([]byte) error
 		// IsEmpty returns true is this is an empty trie
 		IsEmpty() bool
+		// Proof returns the merkle proof of an entry, or of its absence
+		Proof([]byte) ([][]byte, error)
 	}
 	// TwoLayerTrie is a trie data structure with two layers
 	TwoLayerTrie interface {
@@ -64,5 +66,7 @@ type (
 		Upsert([]byte, []byte, []byte) error
 		// Delete deletes an item in layer two
 		Delete([]byte, []byte) error
+		// Proof returns the merkle proofs of an item in layer one and in layer two
+		Proof([]byte, []byte) ([][]byte, [][]byte, error)
 	}
 )
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	// ttlIndexNS is the namespace of the expiring records ordered by the expiry, keyed by expiry + namespace + key
+	ttlIndexNS = "TTLIndex"
+	// ttlExpiryNS is the namespace of the current expiry of the expiring records, keyed by namespace + key
+	ttlExpiryNS = "TTLExpiry"
+	// defaultSweepInterval is the interval of the sweeper unless set by SweepIntervalOption
+	defaultSweepInterval = time.Minute
+	// maxSweepSize is the max number of the expired records deleted by a write batch
+	maxSweepSize = 1000
+)
+
+// TTLOption sets the time to live of the records in the namespace. A record expires after the ttl since it is flushed,
+// and is deleted by the sweeper afterwards, which runs after the KVStoreWithBuffer is started.
+func TTLOption(namespace string, ttl time.Duration) KVStoreFlusherOption {
+	return func(f *flusher) error {
+		if ttl <= 0 {
+			return errors.New("ttl must be positive")
+		}
+		if namespace == ttlIndexNS || namespace == ttlExpiryNS {
+			return errors.Errorf("namespace %s is reserved", namespace)
+		}
+		if f.kvb.ttls == nil {
+			f.kvb.ttls = make(map[string]time.Duration)
+		}
+		f.kvb.ttls[namespace] = ttl
+
+		return nil
+	}
+}
+
+// SweepIntervalOption sets the interval of the sweeper of the expired records
+func SweepIntervalOption(interval time.Duration) KVStoreFlusherOption {
+	return func(f *flusher) error {
+		if interval <= 0 {
+			return errors.New("sweep interval must be positive")
+		}
+		f.kvb.sweepInterval = interval
+
+		return nil
+	}
+}
+
+// expiryKey returns the key of the record in ttlExpiryNS
+func expiryKey(namespace string, key []byte) []byte {
+	k := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(namespace)+len(key))
+	n := binary.PutUvarint(k, uint64(len(namespace)))
+	return append(append(k[:n], namespace...), key...)
+}
+
+// indexKey returns the key of the record in ttlIndexNS
+func indexKey(expiry uint64, namespace string, key []byte) []byte {
+	return append(byteutil.Uint64ToBytesBigEndian(expiry), expiryKey(namespace, key)...)
+}
+
+// parseIndexKey returns the expiry, the namespace and the key of the record in ttlIndexNS
+func parseIndexKey(k []byte) (uint64, string, []byte, error) {
+	if len(k) < 8 {
+		return 0, "", nil, errors.Errorf("invalid ttl index key %x", k)
+	}
+	size, n := binary.Uvarint(k[8:])
+	if n <= 0 || uint64(len(k)-8-n) < size {
+		return 0, "", nil, errors.Errorf("invalid ttl index key %x", k)
+	}
+	start := 8 + n
+	end := start + int(size)
+	return byteutil.BytesToUint64BigEndian(k[:8]), string(k[start:end]), k[end:], nil
+}
+
+// addExpiries adds the expiries of the records written by the batch into the expiring namespaces
+func (kvb *kvStoreWithBuffer) addExpiries(b batch.KVStoreBatch, now time.Time) error {
+	size := b.Size()
+	for i := 0; i < size; i++ {
+		write, err := b.Entry(i)
+		if err != nil {
+			return err
+		}
+		ttl, ok := kvb.ttls[write.Namespace()]
+		if !ok {
+			continue
+		}
+		ek := expiryKey(write.Namespace(), write.Key())
+		switch write.WriteType() {
+		case batch.Put:
+			expiry := uint64(now.Add(ttl).UnixNano())
+			b.Put(ttlExpiryNS, ek, byteutil.Uint64ToBytesBigEndian(expiry), "failed to put expiry of %x", write.Key())
+			b.Put(ttlIndexNS, indexKey(expiry, write.Namespace(), write.Key()), []byte{}, "failed to index expiry of %x", write.Key())
+		case batch.Delete:
+			// the index of the expiry is dropped by the sweeper
+			b.Delete(ttlExpiryNS, ek, "failed to delete expiry of %x", write.Key())
+		}
+	}
+	return nil
+}
+
+func (kvb *kvStoreWithBuffer) startSweeper(ctx context.Context) error {
+	interval := kvb.sweepInterval
+	if interval == 0 {
+		interval = defaultSweepInterval
+	}
+	kvb.sweeper = routine.NewRecurringTask(func() {
+		for {
+			n, err := kvb.sweep(time.Now())
+			if err != nil {
+				log.L().Error("Failed to sweep the expired records.", zap.Error(err))
+				return
+			}
+			if n < maxSweepSize {
+				return
+			}
+		}
+	}, interval)
+	return kvb.sweeper.Start(ctx)
+}
+
+// sweep deletes the records expired at now, it returns the number of the expiries swept, which is at most maxSweepSize
+func (kvb *kvStoreWithBuffer) sweep(now time.Time) (int, error) {
+	kvb.ttlLock.Lock()
+	defer kvb.ttlLock.Unlock()
+
+	it, err := kvb.store.Iterate(ttlIndexNS, nil, byteutil.Uint64ToBytesBigEndian(uint64(now.UnixNano())+1))
+	if err != nil {
+		return 0, err
+	}
+	var indexes [][]byte
+	for len(indexes) < maxSweepSize && it.Next() {
+		indexes = append(indexes, append([]byte{}, it.Key()...))
+	}
+	err = it.Error()
+	// the iterator is closed before the writes, which may wait for the read view of it
+	if cerr := it.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || len(indexes) == 0 {
+		return 0, err
+	}
+
+	b := batch.NewBatch()
+	for _, k := range indexes {
+		b.Delete(ttlIndexNS, k, "failed to delete ttl index %x", k)
+		expiry, ns, key, err := parseIndexKey(k)
+		if err != nil {
+			return 0, err
+		}
+		ek := expiryKey(ns, key)
+		v, err := kvb.store.Get(ttlExpiryNS, ek)
+		switch errors.Cause(err) {
+		case nil:
+		case ErrNotExist, ErrBucketNotExist:
+			// the record has been deleted
+			continue
+		default:
+			return 0, err
+		}
+		if !bytes.Equal(v, byteutil.Uint64ToBytesBigEndian(expiry)) {
+			// the record has been written again after this expiry
+			continue
+		}
+		b.Delete(ns, key, "failed to delete expired %x in %s", key, ns)
+		b.Delete(ttlExpiryNS, ek, "failed to delete expiry of %x", key)
+	}
+	if err := kvb.store.WriteBatch(b); err != nil {
+		return 0, err
+	}
+	return len(indexes), nil
+}
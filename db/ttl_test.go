@@ -0,0 +1,151 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestTTLOption(t *testing.T) {
+	require := require.New(t)
+	store := NewMemKVStore()
+	_, err := NewKVStoreFlusher(store, batch.NewCachedBatch(), TTLOption("ns", 0))
+	require.Error(err)
+	_, err = NewKVStoreFlusher(store, batch.NewCachedBatch(), TTLOption(ttlIndexNS, time.Hour))
+	require.Error(err)
+	_, err = NewKVStoreFlusher(store, batch.NewCachedBatch(), SweepIntervalOption(0))
+	require.Error(err)
+	f, err := NewKVStoreFlusher(store, batch.NewCachedBatch(), TTLOption("ns", time.Hour), SweepIntervalOption(time.Second))
+	require.NoError(err)
+	kvb := f.KVStoreWithBuffer().(*kvStoreWithBuffer)
+	require.Equal(map[string]time.Duration{"ns": time.Hour}, kvb.ttls)
+	require.Equal(time.Second, kvb.sweepInterval)
+}
+
+func TestParseIndexKey(t *testing.T) {
+	require := require.New(t)
+	expiry, ns, key, err := parseIndexKey(indexKey(12345, "ns", []byte("key")))
+	require.NoError(err)
+	require.EqualValues(12345, expiry)
+	require.Equal("ns", ns)
+	require.Equal([]byte("key"), key)
+	_, _, _, err = parseIndexKey([]byte{1, 2, 3})
+	require.Error(err)
+	_, _, _, err = parseIndexKey(append(indexKey(1, "ns", nil)[:9], 'n'))
+	require.Error(err)
+}
+
+func TestSweep(t *testing.T) {
+	require := require.New(t)
+	path, err := testutil.PathOfTempFile("test-ttl")
+	require.NoError(err)
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	store := NewBoltDB(cfg)
+	f, err := NewKVStoreFlusher(store, batch.NewCachedBatch(), TTLOption("ns", time.Hour))
+	require.NoError(err)
+	kvb := f.KVStoreWithBuffer().(*kvStoreWithBuffer)
+	ctx := context.Background()
+	require.NoError(store.Start(ctx))
+	defer func() {
+		require.NoError(store.Stop(ctx))
+	}()
+
+	// expiries returns the expiries in the index
+	expiries := func() []uint64 {
+		it, err := store.Iterate(ttlIndexNS, nil, nil)
+		require.NoError(err)
+		var expiries []uint64
+		for it.Next() {
+			expiry, _, _, err := parseIndexKey(it.Key())
+			require.NoError(err)
+			expiries = append(expiries, expiry)
+		}
+		require.NoError(it.Close())
+		return expiries
+	}
+	exists := func(ns, key string) bool {
+		_, err := store.Get(ns, []byte(key))
+		if errors.Cause(err) == ErrNotExist {
+			return false
+		}
+		require.NoError(err)
+		return true
+	}
+
+	require.NoError(kvb.Put("ns", []byte("a"), []byte("1")))
+	require.NoError(kvb.Put("ns", []byte("b"), []byte("2")))
+	require.NoError(kvb.Put("ns1", []byte("a"), []byte("3")))
+	require.NoError(f.Flush())
+	first := expiries()
+	require.Len(first, 2)
+	// a is written again, and b is deleted
+	require.NoError(kvb.Put("ns", []byte("a"), []byte("4")))
+	require.NoError(kvb.Delete("ns", []byte("b")))
+	require.NoError(f.Flush())
+	second := expiries()
+	require.Len(second, 3)
+	require.True(second[2] > first[1])
+
+	n, err := kvb.sweep(time.Unix(0, int64(first[0])-1))
+	require.NoError(err)
+	require.Zero(n)
+	// the first expiries are swept, but a lives until its second expiry
+	n, err = kvb.sweep(time.Unix(0, int64(first[1])))
+	require.NoError(err)
+	require.Equal(2, n)
+	require.Equal(second[2:], expiries())
+	require.True(exists("ns", "a"))
+	require.False(exists("ns", "b"))
+	n, err = kvb.sweep(time.Unix(0, int64(second[2])))
+	require.NoError(err)
+	require.Equal(1, n)
+	require.Empty(expiries())
+	require.False(exists("ns", "a"))
+	require.False(exists(ttlExpiryNS, string(expiryKey("ns", []byte("a")))))
+	// the records of the other namespaces never expire
+	require.True(exists("ns1", "a"))
+}
+
+func TestSweeper(t *testing.T) {
+	require := require.New(t)
+	path, err := testutil.PathOfTempFile("test-sweeper")
+	require.NoError(err)
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	f, err := NewKVStoreFlusher(NewBoltDB(cfg), batch.NewCachedBatch(),
+		TTLOption("ns", time.Millisecond), SweepIntervalOption(10*time.Millisecond))
+	require.NoError(err)
+	kvb := f.KVStoreWithBuffer()
+	ctx := context.Background()
+	require.NoError(kvb.Start(ctx))
+	defer func() {
+		require.NoError(kvb.Stop(ctx))
+	}()
+
+	require.NoError(kvb.Put("ns", []byte("a"), []byte("1")))
+	require.NoError(f.Flush())
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		_, err := kvb.Get("ns", []byte("a"))
+		return errors.Cause(err) == ErrNotExist, nil
+	}))
+}
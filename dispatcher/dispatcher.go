@@ -9,15 +9,19 @@ package dispatcher
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
@@ -33,6 +37,13 @@ type Subscriber interface {
 	HandleBlockSync(context.Context, *iotextypes.Block) error
 	HandleSyncRequest(context.Context, peerstore.PeerInfo, *iotexrpc.BlockSync) error
 	HandleConsensusMsg(*iotextypes.ConsensusMessage) error
+	HandleActionAnnouncement(context.Context, peerstore.PeerInfo, []hash.Hash256) error
+	HandleActionRequest(context.Context, peerstore.PeerInfo, []hash.Hash256) error
+	HandleHeaderRequest(context.Context, peerstore.PeerInfo, uint64, uint64) error
+	HandleHeaders(context.Context, peerstore.PeerInfo, []*iotextypes.BlockHeader) error
+	HandleBlockAnnouncement(context.Context, peerstore.PeerInfo, uint64, hash.Hash256) error
+	HandleStateRangeRequest(context.Context, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64) error
+	HandleStateRange(context.Context, peerstore.PeerInfo, uint64, []byte) error
 }
 
 // Dispatcher is used by peers, handles incoming block and header notifications and relays announcements of new blocks.
@@ -47,6 +58,20 @@ type Dispatcher interface {
 	// HandleTell handles the incoming tell message. The transportation layer semantics is exact once. The sender is
 	// given for the sake of replying the message
 	HandleTell(context.Context, uint32, peerstore.PeerInfo, proto.Message)
+	// HandleActionAnnouncement handles the incoming hashes of the actions announced by a peer
+	HandleActionAnnouncement(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256)
+	// HandleActionRequest handles the incoming request of a peer for the actions of the hashes
+	HandleActionRequest(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256)
+	// HandleHeaderRequest handles the incoming request of a peer for the headers from start to end
+	HandleHeaderRequest(context.Context, uint32, peerstore.PeerInfo, uint64, uint64)
+	// HandleHeaders handles the incoming headers sent by a peer
+	HandleHeaders(context.Context, uint32, peerstore.PeerInfo, []*iotextypes.BlockHeader)
+	// HandleBlockAnnouncement handles the incoming height and hash of a block announced by a peer
+	HandleBlockAnnouncement(context.Context, uint32, peerstore.PeerInfo, uint64, hash.Hash256)
+	// HandleStateRangeRequest handles the incoming request of a peer for a range of the state trie
+	HandleStateRangeRequest(context.Context, uint32, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64)
+	// HandleStateRange handles the incoming range of the state trie sent by a peer
+	HandleStateRange(context.Context, uint32, peerstore.PeerInfo, uint64, []byte)
 }
 
 var requestMtc = prometheus.NewCounterVec(
@@ -101,6 +126,9 @@ type IotxDispatcher struct {
 	shutdown       int32
 	eventChan      chan interface{}
 	syncChan       chan *blockSyncMsg
+	actionChan     chan *actionMsg
+	verifyWorkers  int
+	verifyBatch    int
 	eventAudit     map[iotexrpc.MessageType]int
 	eventAuditLock sync.RWMutex
 	wg             sync.WaitGroup
@@ -114,10 +142,19 @@ func NewDispatcher(cfg config.Config) (Dispatcher, error) {
 	d := &IotxDispatcher{
 		eventChan:   make(chan interface{}, cfg.Dispatcher.EventChanSize),
 		syncChan:    make(chan *blockSyncMsg, cfg.Dispatcher.EventChanSize),
+		actionChan:  make(chan *actionMsg, cfg.Dispatcher.EventChanSize),
 		eventAudit:  make(map[iotexrpc.MessageType]int),
 		quit:        make(chan struct{}),
 		subscribers: make(map[uint32]Subscriber),
 	}
+	d.verifyWorkers = int(cfg.Dispatcher.ActionVerifyWorkers)
+	if d.verifyWorkers == 0 {
+		d.verifyWorkers = runtime.NumCPU()
+	}
+	d.verifyBatch = int(cfg.Dispatcher.ActionVerifyBatchSize)
+	if d.verifyBatch == 0 {
+		d.verifyBatch = 1
+	}
 	return d, nil
 }
 
@@ -137,9 +174,10 @@ func (d *IotxDispatcher) Start(ctx context.Context) error {
 		return errors.New("Dispatcher already started")
 	}
 	log.L().Info("Starting dispatcher.")
-	d.wg.Add(2)
+	d.wg.Add(3)
 	go d.newsHandler()
 	go d.syncHandler()
+	go d.actionHandler()
 
 	return nil
 }
@@ -160,7 +198,7 @@ func (d *IotxDispatcher) Stop(ctx context.Context) error {
 func (d *IotxDispatcher) EventQueueSize() int {
 	d.eventAuditLock.RLock()
 	defer d.eventAuditLock.RUnlock()
-	return len(d.eventChan) + len(d.syncChan)
+	return len(d.eventChan) + len(d.syncChan) + len(d.actionChan)
 }
 
 // EventAudit returns the event audit map
@@ -181,8 +219,6 @@ loop:
 		select {
 		case m := <-d.eventChan:
 			switch msg := m.(type) {
-			case *actionMsg:
-				d.handleActionMsg(msg)
 			case *blockMsg:
 				d.handleBlockMsg(msg)
 			default:
@@ -213,6 +249,75 @@ loop:
 	log.L().Info("block sync handler done.")
 }
 
+// actionHandler handles incoming actions in batches, whose signatures are verified in parallel
+func (d *IotxDispatcher) actionHandler() {
+loop:
+	for {
+		select {
+		case m := <-d.actionChan:
+			batch := []*actionMsg{m}
+		drain:
+			for len(batch) < d.verifyBatch {
+				select {
+				case m := <-d.actionChan:
+					batch = append(batch, m)
+				default:
+					break drain
+				}
+			}
+			d.verifyActionMsgs(batch)
+			for _, m := range batch {
+				if m != nil {
+					d.handleActionMsg(m)
+				}
+			}
+		case <-d.quit:
+			break loop
+		}
+	}
+
+	d.wg.Done()
+	log.L().Info("action handler done.")
+}
+
+// verifyActionMsgs verifies the signatures of a batch of actions with the verify workers. The context of a valid
+// action is marked as verified, so that the signature is not verified again by the subscriber, and an invalid action
+// is set to nil in the batch.
+func (d *IotxDispatcher) verifyActionMsgs(batch []*actionMsg) {
+	var (
+		wg   sync.WaitGroup
+		next int32 = -1
+	)
+	workers := d.verifyWorkers
+	if workers > len(batch) {
+		workers = len(batch)
+	}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := int(atomic.AddInt32(&next, 1)); i < len(batch); i = int(atomic.AddInt32(&next, 1)) {
+				m := batch[i]
+				var selp action.SealedEnvelope
+				if err := selp.LoadProto(m.action); err != nil {
+					requestMtc.WithLabelValues("AddAction", "false").Inc()
+					log.L().Debug("Failed to load action.", zap.Error(err))
+					batch[i] = nil
+					continue
+				}
+				if err := action.Verify(selp); err != nil {
+					requestMtc.WithLabelValues("AddAction", "false").Inc()
+					log.L().Debug("Failed to verify action signature.", zap.Error(err))
+					batch[i] = nil
+					continue
+				}
+				m.ctx = protocol.WithVerifiedAction(m.ctx, selp.Hash())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 // handleActionMsg handles actionMsg from all peers.
 func (d *IotxDispatcher) handleActionMsg(m *actionMsg) {
 	log.L().Debug("receive actionMsg.")
@@ -274,11 +379,15 @@ func (d *IotxDispatcher) dispatchAction(ctx context.Context, chainID uint32, msg
 	if atomic.LoadInt32(&d.shutdown) != 0 {
 		return
 	}
-	d.enqueueEvent(&actionMsg{
+	if len(d.actionChan) == cap(d.actionChan) {
+		log.L().Warn("dispatcher action chan is full, drop an event.")
+		return
+	}
+	d.actionChan <- &actionMsg{
 		ctx:     ctx,
 		chainID: chainID,
 		action:  (msg).(*iotextypes.Action),
-	})
+	}
 }
 
 // dispatchBlockCommit adds the passed block message to the news handling queue.
@@ -350,11 +459,132 @@ func (d *IotxDispatcher) HandleTell(ctx context.Context, chainID uint32, peer pe
 		d.dispatchBlockSyncReq(ctx, chainID, peer, message)
 	case iotexrpc.MessageType_BLOCK:
 		d.dispatchBlockCommit(ctx, chainID, message)
+	case iotexrpc.MessageType_ACTION:
+		// the action requested from the peer
+		d.dispatchAction(ctx, chainID, message)
 	default:
 		log.L().Warn("Unexpected msgType handled by HandleTell.", zap.Any("msgType", msgType))
 	}
 }
 
+// HandleActionAnnouncement handles incoming action announcement
+func (d *IotxDispatcher) HandleActionAnnouncement(ctx context.Context, chainID uint32, peer peerstore.PeerInfo, hashes []hash.Hash256) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleActionAnnouncement(ctx, peer, hashes); err != nil {
+		log.L().Debug("Failed to handle action announcement.", zap.Error(err))
+		requestMtc.WithLabelValues("ActionAnnouncement", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("ActionAnnouncement", "true").Inc()
+}
+
+// HandleActionRequest handles incoming action request
+func (d *IotxDispatcher) HandleActionRequest(ctx context.Context, chainID uint32, peer peerstore.PeerInfo, hashes []hash.Hash256) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleActionRequest(ctx, peer, hashes); err != nil {
+		log.L().Debug("Failed to handle action request.", zap.Error(err))
+		requestMtc.WithLabelValues("ActionRequest", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("ActionRequest", "true").Inc()
+}
+
+// HandleHeaderRequest handles incoming header request
+func (d *IotxDispatcher) HandleHeaderRequest(ctx context.Context, chainID uint32, peer peerstore.PeerInfo, start, end uint64) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleHeaderRequest(ctx, peer, start, end); err != nil {
+		log.L().Debug("Failed to handle header request.", zap.Error(err))
+		requestMtc.WithLabelValues("HeaderRequest", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("HeaderRequest", "true").Inc()
+}
+
+// HandleHeaders handles incoming headers
+func (d *IotxDispatcher) HandleHeaders(ctx context.Context, chainID uint32, peer peerstore.PeerInfo, headers []*iotextypes.BlockHeader) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleHeaders(ctx, peer, headers); err != nil {
+		log.L().Debug("Failed to handle headers.", zap.Error(err))
+		requestMtc.WithLabelValues("Headers", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("Headers", "true").Inc()
+}
+
+// HandleBlockAnnouncement handles incoming block announcement
+func (d *IotxDispatcher) HandleBlockAnnouncement(ctx context.Context, chainID uint32, peer peerstore.PeerInfo, height uint64, h hash.Hash256) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleBlockAnnouncement(ctx, peer, height, h); err != nil {
+		log.L().Debug("Failed to handle block announcement.", zap.Error(err))
+		requestMtc.WithLabelValues("BlockAnnouncement", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("BlockAnnouncement", "true").Inc()
+}
+
+// HandleStateRangeRequest handles incoming state range request
+func (d *IotxDispatcher) HandleStateRangeRequest(
+	ctx context.Context,
+	chainID uint32,
+	peer peerstore.PeerInfo,
+	id uint64,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleStateRangeRequest(ctx, peer, id, height, nsKey, start, limit); err != nil {
+		log.L().Debug("Failed to handle state range request.", zap.Error(err))
+		requestMtc.WithLabelValues("StateRangeRequest", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("StateRangeRequest", "true").Inc()
+}
+
+// HandleStateRange handles incoming state range
+func (d *IotxDispatcher) HandleStateRange(ctx context.Context, chainID uint32, peer peerstore.PeerInfo, id uint64, data []byte) {
+	subscriber := d.subscriber(chainID)
+	if subscriber == nil {
+		return
+	}
+	if err := subscriber.HandleStateRange(ctx, peer, id, data); err != nil {
+		log.L().Debug("Failed to handle state range.", zap.Error(err))
+		requestMtc.WithLabelValues("StateRange", "false").Inc()
+		return
+	}
+	requestMtc.WithLabelValues("StateRange", "true").Inc()
+}
+
+func (d *IotxDispatcher) subscriber(chainID uint32) Subscriber {
+	d.subscribersMU.RLock()
+	defer d.subscribersMU.RUnlock()
+	subscriber, ok := d.subscribers[chainID]
+	if !ok {
+		log.L().Warn("chainID has not been registered in dispatcher.", zap.Uint32("chainID", chainID))
+		return nil
+	}
+	return subscriber
+}
+
 func (d *IotxDispatcher) enqueueEvent(event interface{}) {
 	if len(d.eventChan) == cap(d.eventChan) {
 		log.L().Warn("dispatcher event chan is full, drop an event.")
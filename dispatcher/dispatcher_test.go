@@ -8,14 +8,23 @@ package dispatcher
 
 import (
 	"context"
+	"math/big"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
 	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/iotexproject/iotex-proto/golang/testingpb"
@@ -86,6 +95,126 @@ func TestHandleTell(t *testing.T) {
 	}
 }
 
+func TestHandleActionInBatch(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Config{
+		Dispatcher: config.Dispatcher{EventChanSize: 1024, ActionVerifyWorkers: 4, ActionVerifyBatchSize: 8},
+	}
+	dp, err := NewDispatcher(cfg)
+	require.NoError(err)
+	sub := &actionSubscriber{}
+	dp.AddSubscriber(config.Default.Chain.ID, sub)
+
+	var (
+		ctx    = context.Background()
+		hashes = make(map[string]bool)
+		msgs   []proto.Message
+	)
+	for i := uint64(1); i <= 20; i++ {
+		selp, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(2), i, big.NewInt(1), nil, 100000, big.NewInt(0))
+		require.NoError(err)
+		h := selp.Hash()
+		hashes[string(h[:])] = true
+		msgs = append(msgs, selp.Proto())
+	}
+	// an action with an invalid signature is dropped before reaching the subscriber
+	tsf, err := action.NewTransfer(1, big.NewInt(1), identityset.Address(1).String(), nil, 100000, big.NewInt(0))
+	require.NoError(err)
+	elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(100000).SetAction(tsf).Build()
+	fake := action.FakeSeal(elp, identityset.PrivateKey(2).PublicKey())
+	msgs = append(msgs, fake.Proto())
+
+	require.NoError(dp.Start(ctx))
+	defer func() { require.NoError(dp.Stop(ctx)) }()
+	for _, msg := range msgs {
+		dp.HandleBroadcast(ctx, config.Default.Chain.ID, msg)
+	}
+	require.Eventually(func() bool { return sub.count() == 20 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(20, sub.count())
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	for i, pb := range sub.acts {
+		var selp action.SealedEnvelope
+		require.NoError(selp.LoadProto(pb))
+		h := selp.Hash()
+		require.True(hashes[string(h[:])])
+		require.True(protocol.IsVerifiedAction(sub.ctxs[i], h))
+		// the actions are handled in the order of arrival
+		require.Equal(uint64(i+1), selp.Nonce())
+	}
+}
+
+func TestHandleActionHashes(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Config{
+		Dispatcher: config.Dispatcher{EventChanSize: 1024, ActionVerifyWorkers: 1, ActionVerifyBatchSize: 1},
+	}
+	dp, err := NewDispatcher(cfg)
+	require.NoError(err)
+	sub := &actionSubscriber{}
+	dp.AddSubscriber(config.Default.Chain.ID, sub)
+	ctx := context.Background()
+	require.NoError(dp.Start(ctx))
+	defer func() { require.NoError(dp.Stop(ctx)) }()
+
+	hashes := []hash.Hash256{hash.Hash256b([]byte("1")), hash.Hash256b([]byte("2"))}
+	dp.HandleActionAnnouncement(ctx, config.Default.Chain.ID, peerstore.PeerInfo{}, hashes)
+	dp.HandleActionRequest(ctx, config.Default.Chain.ID, peerstore.PeerInfo{}, hashes[:1])
+	// the hashes of an unknown chain are dropped
+	dp.HandleActionAnnouncement(ctx, config.Default.Chain.ID+1, peerstore.PeerInfo{}, hashes)
+	sub.mu.Lock()
+	require.Equal(hashes, sub.announced)
+	require.Equal(hashes[:1], sub.requested)
+	sub.mu.Unlock()
+
+	// the action requested from a peer is sent back in a unicast message
+	selp, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(2), 1, big.NewInt(1), nil, 100000, big.NewInt(0))
+	require.NoError(err)
+	dp.HandleTell(ctx, config.Default.Chain.ID, peerstore.PeerInfo{}, selp.Proto())
+	require.Eventually(func() bool { return sub.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+type actionSubscriber struct {
+	DummySubscriber
+	mu        sync.Mutex
+	ctxs      []context.Context
+	acts      []*iotextypes.Action
+	announced []hash.Hash256
+	requested []hash.Hash256
+}
+
+func (s *actionSubscriber) HandleActionAnnouncement(_ context.Context, _ peerstore.PeerInfo, hashes []hash.Hash256) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.announced = append(s.announced, hashes...)
+	return nil
+}
+
+func (s *actionSubscriber) HandleActionRequest(_ context.Context, _ peerstore.PeerInfo, hashes []hash.Hash256) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requested = append(s.requested, hashes...)
+	return nil
+}
+
+func (s *actionSubscriber) HandleAction(ctx context.Context, act *iotextypes.Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctxs = append(s.ctxs, ctx)
+	s.acts = append(s.acts, act)
+	return nil
+}
+
+func (s *actionSubscriber) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.acts)
+}
+
 type DummySubscriber struct{}
 
 func (s *DummySubscriber) HandleBlock(context.Context, *iotextypes.Block) error { return nil }
@@ -99,3 +228,31 @@ func (s *DummySubscriber) HandleSyncRequest(context.Context, peerstore.PeerInfo,
 func (s *DummySubscriber) HandleAction(context.Context, *iotextypes.Action) error { return nil }
 
 func (s *DummySubscriber) HandleConsensusMsg(*iotextypes.ConsensusMessage) error { return nil }
+
+func (s *DummySubscriber) HandleActionAnnouncement(context.Context, peerstore.PeerInfo, []hash.Hash256) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleActionRequest(context.Context, peerstore.PeerInfo, []hash.Hash256) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleHeaderRequest(context.Context, peerstore.PeerInfo, uint64, uint64) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleHeaders(context.Context, peerstore.PeerInfo, []*iotextypes.BlockHeader) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleBlockAnnouncement(context.Context, peerstore.PeerInfo, uint64, hash.Hash256) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleStateRangeRequest(context.Context, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleStateRange(context.Context, peerstore.PeerInfo, uint64, []byte) error {
+	return nil
+}
@@ -9,12 +9,26 @@ package endorsement
 import (
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/iotexproject/go-pkgs/crypto"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
 
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/endorsement/endorsementpb"
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/signer"
+)
+
+const (
+	// endorsementExtensionField is the field of iotextypes.Endorsement carrying the serialized
+	// endorsementpb.EndorsementExtension
+	endorsementExtensionField protowire.Number = 1000
+	// endorsementExtensionVersion is the version of endorsementpb.EndorsementExtension
+	endorsementExtensionVersion = 1
 )
 
 type (
@@ -28,6 +42,9 @@ type (
 		ts        time.Time
 		endorser  crypto.PublicKey
 		signature []byte
+		// blsSignature is the BLS signature of the commit endorsement, or the aggregated signature of all the commit
+		// endorsements of a block
+		blsSignature []byte
 	}
 
 	// EndorsedDocument is an signed document
@@ -65,7 +82,7 @@ func NewEndorsement(
 
 // Endorse endorses a document
 func Endorse(
-	signer crypto.PrivateKey,
+	endorser signer.HashSigner,
 	doc Document,
 	ts time.Time,
 ) (*Endorsement, error) {
@@ -73,11 +90,53 @@ func Endorse(
 	if err != nil {
 		return nil, err
 	}
-	sig, err := signer.Sign(hash)
+	sig, err := endorser.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	return NewEndorsement(ts, endorser.PublicKey(), sig), nil
+}
+
+// EndorseWithBLS endorses a document, and signs it with the BLS key as well so the endorsement could be aggregated
+func EndorseWithBLS(
+	endorser signer.HashSigner,
+	blsKey *bls.PrivateKey,
+	doc Document,
+	ts time.Time,
+) (*Endorsement, error) {
+	en, err := Endorse(endorser, doc, ts)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashDocWithTime(doc, ts)
+	if err != nil {
+		return nil, err
+	}
+	en.blsSignature = blsKey.Sign(hash)
+	return en, nil
+}
+
+// AggregateEndorsements aggregates the BLS signatures of the endorsements of the same document. The returned
+// endorsements have no signature other than the aggregated one carried by the first endorsement.
+func AggregateEndorsements(ens []*Endorsement) ([]*Endorsement, error) {
+	if len(ens) == 0 {
+		return nil, errors.New("no endorsement to aggregate")
+	}
+	sigs := make([][]byte, 0, len(ens))
+	aggregated := make([]*Endorsement, 0, len(ens))
+	for _, en := range ens {
+		if len(en.blsSignature) == 0 {
+			return nil, errors.Errorf("endorsement of %s is not signed with BLS", en.endorser.HexString())
+		}
+		sigs = append(sigs, en.blsSignature)
+		aggregated = append(aggregated, NewEndorsement(en.ts, en.endorser, nil))
+	}
+	sig, err := bls.Aggregate(sigs)
 	if err != nil {
 		return nil, err
 	}
-	return NewEndorsement(ts, signer.PublicKey(), sig), nil
+	aggregated[0].blsSignature = sig
+	return aggregated, nil
 }
 
 // VerifyEndorsedDocument checks an endorsed document
@@ -95,6 +154,35 @@ func VerifyEndorsement(doc Document, en *Endorsement) bool {
 	return en.Endorser().Verify(hash, en.Signature())
 }
 
+// VerifyBLSEndorsement checks the BLS signature in an endorsement against a document
+func VerifyBLSEndorsement(doc Document, en *Endorsement, pk *bls.PublicKey) bool {
+	hash, err := hashDocWithTime(doc, en.Timestamp())
+	if err != nil {
+		return false
+	}
+	return pk.Verify(hash, en.blsSignature)
+}
+
+// VerifyAggregatedEndorsements checks the endorsements returned by AggregateEndorsements against a document, the
+// i-th public key being the BLS public key of the i-th endorser
+func VerifyAggregatedEndorsements(doc Document, ens []*Endorsement, pks []*bls.PublicKey) bool {
+	if len(ens) == 0 || len(ens) != len(pks) {
+		return false
+	}
+	hashes := make([][]byte, 0, len(ens))
+	for i, en := range ens {
+		if len(en.signature) != 0 || (i > 0 && len(en.blsSignature) != 0) {
+			return false
+		}
+		hash, err := hashDocWithTime(doc, en.Timestamp())
+		if err != nil {
+			return false
+		}
+		hashes = append(hashes, hash)
+	}
+	return bls.VerifyAggregate(pks, hashes, ens[0].blsSignature)
+}
+
 // Timestamp returns the signature time
 func (en *Endorsement) Timestamp() time.Time {
 	return en.ts
@@ -113,17 +201,37 @@ func (en *Endorsement) Signature() []byte {
 	return signature
 }
 
-// Proto converts an endorsement to protobuf message
+// BLSSignature returns the BLS signature of this endorsement
+func (en *Endorsement) BLSSignature() []byte {
+	signature := make([]byte, len(en.blsSignature))
+	copy(signature, en.blsSignature)
+
+	return signature
+}
+
+// Proto converts an endorsement to protobuf message, the BLS signature is carried in the extension of the message
 func (en *Endorsement) Proto() (*iotextypes.Endorsement, error) {
 	ts, err := ptypes.TimestampProto(en.ts)
 	if err != nil {
 		return nil, err
 	}
-	return &iotextypes.Endorsement{
+	ePb := &iotextypes.Endorsement{
 		Timestamp: ts,
 		Endorser:  en.endorser.Bytes(),
 		Signature: en.Signature(),
-	}, nil
+	}
+	if len(en.blsSignature) != 0 {
+		ext, err := proto.Marshal(&endorsementpb.EndorsementExtension{
+			Version:      endorsementExtensionVersion,
+			BlsSignature: en.BLSSignature(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		b := protowire.AppendTag(nil, endorsementExtensionField, protowire.BytesType)
+		proto.MessageReflect(ePb).SetUnknown(protowire.AppendBytes(b, ext))
+	}
+	return ePb, nil
 }
 
 // LoadProto converts a protobuf message to endorsement
@@ -138,6 +246,44 @@ func (en *Endorsement) LoadProto(ePb *iotextypes.Endorsement) (err error) {
 	}
 	en.signature = make([]byte, len(ePb.Signature))
 	copy(en.signature, ePb.Signature)
+	en.blsSignature = nil
 
+	return en.loadExtension(proto.MessageReflect(ePb).GetUnknown())
+}
+
+// loadExtension loads the extension from the unknown fields of the endorsement proto
+func (en *Endorsement) loadExtension(b []byte) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num != endorsementExtensionField || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		ext := &endorsementpb.EndorsementExtension{}
+		if err := proto.Unmarshal(v, ext); err != nil {
+			return errors.Wrap(err, "failed to unmarshal the extension of endorsement")
+		}
+		if ext.GetVersion() != endorsementExtensionVersion {
+			return errors.Errorf("unknown endorsement extension version %d", ext.GetVersion())
+		}
+		// the endorsement has at most one extension, which isn't empty
+		if en.blsSignature != nil || len(ext.GetBlsSignature()) == 0 {
+			return errors.New("invalid endorsement extension")
+		}
+		en.blsSignature = make([]byte, len(ext.GetBlsSignature()))
+		copy(en.blsSignature, ext.GetBlsSignature())
+	}
 	return nil
 }
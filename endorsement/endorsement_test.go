@@ -0,0 +1,101 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package endorsement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/endorsement/endorsementpb"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+type testDoc []byte
+
+func (d testDoc) Hash() ([]byte, error) { return d, nil }
+
+func loadEndorsement(t *testing.T, ePb *iotextypes.Endorsement) (*Endorsement, error) {
+	b, err := proto.Marshal(ePb)
+	require.NoError(t, err)
+	loaded := &iotextypes.Endorsement{}
+	require.NoError(t, proto.Unmarshal(b, loaded))
+	en := &Endorsement{}
+	return en, en.LoadProto(loaded)
+}
+
+func TestEndorsement_Proto(t *testing.T) {
+	require := require.New(t)
+	doc := testDoc("document")
+	ts := time.Unix(1600000000, 0).UTC()
+
+	t.Run("without BLS", func(t *testing.T) {
+		en, err := Endorse(identityset.PrivateKey(0), doc, ts)
+		require.NoError(err)
+		ePb, err := en.Proto()
+		require.NoError(err)
+		require.Empty(proto.MessageReflect(ePb).GetUnknown())
+		loaded, err := loadEndorsement(t, ePb)
+		require.NoError(err)
+		require.Empty(loaded.BLSSignature())
+		require.True(VerifyEndorsement(doc, loaded))
+
+		// the endorsements serialized before the BLS signatures still load
+		pbTs, err := ptypes.TimestampProto(ts)
+		require.NoError(err)
+		loaded, err = loadEndorsement(t, &iotextypes.Endorsement{
+			Timestamp: pbTs,
+			Endorser:  identityset.PrivateKey(0).PublicKey().Bytes(),
+			Signature: en.Signature(),
+		})
+		require.NoError(err)
+		require.Empty(loaded.BLSSignature())
+		require.True(VerifyEndorsement(doc, loaded))
+	})
+
+	t.Run("with BLS", func(t *testing.T) {
+		blsKey, err := bls.GenerateKey()
+		require.NoError(err)
+		en, err := EndorseWithBLS(identityset.PrivateKey(0), blsKey, doc, ts)
+		require.NoError(err)
+		ePb, err := en.Proto()
+		require.NoError(err)
+		loaded, err := loadEndorsement(t, ePb)
+		require.NoError(err)
+		require.Equal(en.BLSSignature(), loaded.BLSSignature())
+		require.True(VerifyEndorsement(doc, loaded))
+		require.True(VerifyBLSEndorsement(doc, loaded, blsKey.PublicKey()))
+
+		// the other unknown fields are skipped
+		b := protowire.AppendTag(nil, 1001, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+		proto.MessageReflect(ePb).SetUnknown(append(b, proto.MessageReflect(ePb).GetUnknown()...))
+		loaded, err = loadEndorsement(t, ePb)
+		require.NoError(err)
+		require.Equal(en.BLSSignature(), loaded.BLSSignature())
+
+		// the extension of an unknown version is rejected
+		ext, err := proto.Marshal(&endorsementpb.EndorsementExtension{Version: 2, BlsSignature: en.BLSSignature()})
+		require.NoError(err)
+		b = protowire.AppendTag(nil, endorsementExtensionField, protowire.BytesType)
+		proto.MessageReflect(ePb).SetUnknown(protowire.AppendBytes(b, ext))
+		_, err = loadEndorsement(t, ePb)
+		require.Error(err)
+	})
+}
+
+func TestEndorsementExtensionField(t *testing.T) {
+	// the field carrying the extension must not be defined by iotextypes.Endorsement
+	fields := (&iotextypes.Endorsement{}).ProtoReflect().Descriptor().Fields()
+	require.Nil(t, fields.ByNumber(endorsementExtensionField))
+}
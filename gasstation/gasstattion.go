@@ -11,6 +11,9 @@ import (
 	"math/big"
 	"sort"
 
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/cache"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
 
@@ -22,34 +25,85 @@ import (
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 )
 
+// ErrInvalidFeeHistory indicates an invalid fee history request
+var ErrInvalidFeeHistory = errors.New("invalid fee history request")
+
 // BlockDAO represents the block data access object
 type BlockDAO interface {
 	GetBlockHash(uint64) (hash.Hash256, error)
 	GetBlockByHeight(uint64) (*block.Block, error)
+	GetReceipts(uint64) ([]*action.Receipt, error)
 }
 
 // SimulateFunc is function that simulate execution
 type SimulateFunc func(context.Context, address.Address, *action.Execution, evm.GetBlockHash) ([]byte, *action.Receipt, error)
 
-// GasStation provide gas related api
-type GasStation struct {
-	bc        blockchain.Blockchain
-	simulator SimulateFunc
-	dao       BlockDAO
-	cfg       config.API
-}
+type (
+	// GasStation provide gas related api
+	GasStation struct {
+		bc        blockchain.Blockchain
+		simulator SimulateFunc
+		dao       BlockDAO
+		cfg       config.API
+		fees      *cache.ThreadSafeLruCache // lru cache of the fee stats of recent blocks
+		floor     func() *big.Int           // the minimal gas price currently accepted by the actpool
+	}
+
+	// Option sets gas station construction parameter
+	Option func(*GasStation)
+
+	// FeeHistory is the gas usage and gas prices of a range of blocks. There is no base fee on the chain, so the base
+	// fees are always zero, and the rewards are the gas prices paid by the user actions.
+	FeeHistory struct {
+		OldestBlock  uint64
+		Reward       [][]*big.Int
+		BaseFee      []*big.Int
+		GasUsedRatio []float64
+	}
+
+	// blockFee is the fee stats of the user actions in a block, ordered by gas price
+	blockFee struct {
+		gasUsedRatio float64
+		gasUsed      uint64
+		prices       []*big.Int
+		gas          []uint64
+	}
+)
 
 // NewGasStation creates a new gas station
-func NewGasStation(bc blockchain.Blockchain, simulator SimulateFunc, dao BlockDAO, cfg config.API) *GasStation {
-	return &GasStation{
+func NewGasStation(bc blockchain.Blockchain, simulator SimulateFunc, dao BlockDAO, cfg config.API, opts ...Option) *GasStation {
+	gs := &GasStation{
 		bc:        bc,
 		simulator: simulator,
 		dao:       dao,
 		cfg:       cfg,
 	}
+	if cfg.GasStation.FeeHistoryWindow > 0 {
+		gs.fees = cache.NewThreadSafeLruCache(cfg.GasStation.FeeHistoryWindow)
+	}
+	for _, opt := range opts {
+		opt(gs)
+	}
+	return gs
+}
+
+// WithMinGasPrice returns an option to take into account the minimal gas price currently accepted by the actpool,
+// which is raised when the actpool is under pressure
+func WithMinGasPrice(minGasPrice func() *big.Int) Option {
+	return func(gs *GasStation) {
+		gs.floor = minGasPrice
+	}
 }
 
-//IsSystemAction determine whether input action belongs to system action
+// MinGasPrice returns the minimal gas price currently accepted by the actpool
+func (gs *GasStation) MinGasPrice() *big.Int {
+	if gs.floor == nil {
+		return big.NewInt(0)
+	}
+	return gs.floor()
+}
+
+// IsSystemAction determine whether input action belongs to system action
 func (gs *GasStation) IsSystemAction(act action.SealedEnvelope) bool {
 	switch act.Action().(type) {
 	case *action.GrantReward:
@@ -61,8 +115,20 @@ func (gs *GasStation) IsSystemAction(act action.SealedEnvelope) bool {
 	}
 }
 
-// SuggestGasPrice suggest gas price
+// SuggestGasPrice suggest gas price, which is the configured percentile of the lowest gas prices of the recent blocks
+// with user actions, and no less than the minimal gas price currently accepted by the actpool
 func (gs *GasStation) SuggestGasPrice() (uint64, error) {
+	price, err := gs.suggestGasPrice()
+	if err != nil {
+		return price, err
+	}
+	if floor := gs.MinGasPrice(); floor.IsUint64() && floor.Uint64() > price {
+		price = floor.Uint64()
+	}
+	return price, nil
+}
+
+func (gs *GasStation) suggestGasPrice() (uint64, error) {
 	var smallestPrices []*big.Int
 	tip := gs.bc.TipHeight()
 
@@ -72,26 +138,14 @@ func (gs *GasStation) SuggestGasPrice() (uint64, error) {
 	}
 
 	for height := tip; height > endBlockHeight; height-- {
-		blk, err := gs.dao.GetBlockByHeight(height)
+		fee, err := gs.blockFee(height)
 		if err != nil {
 			return gs.cfg.GasStation.DefaultGas, err
 		}
-		if len(blk.Actions) == 0 {
-			continue
-		}
-		if len(blk.Actions) == 1 && gs.IsSystemAction(blk.Actions[0]) {
+		if len(fee.prices) == 0 {
 			continue
 		}
-		smallestPrice := blk.Actions[0].GasPrice()
-		for _, act := range blk.Actions {
-			if gs.IsSystemAction(act) {
-				continue
-			}
-			if smallestPrice.Cmp(act.GasPrice()) == 1 {
-				smallestPrice = act.GasPrice()
-			}
-		}
-		smallestPrices = append(smallestPrices, smallestPrice)
+		smallestPrices = append(smallestPrices, fee.prices[0])
 	}
 
 	if len(smallestPrices) == 0 {
@@ -106,6 +160,128 @@ func (gs *GasStation) SuggestGasPrice() (uint64, error) {
 	return gasPrice, nil
 }
 
+// FeeHistory returns the fee history of at most blockCount blocks ending at lastBlock. The rewards of a block are the
+// gas prices at the given percentiles of its gas used, in ascending order of price.
+func (gs *GasStation) FeeHistory(blockCount, lastBlock uint64, rewardPercentiles []float64) (*FeeHistory, error) {
+	if blockCount == 0 {
+		return nil, errors.Wrap(ErrInvalidFeeHistory, "block count must be greater than zero")
+	}
+	if window := uint64(gs.cfg.GasStation.FeeHistoryWindow); window > 0 && blockCount > window {
+		blockCount = window
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, errors.Wrapf(ErrInvalidFeeHistory, "invalid reward percentile %f", p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, errors.Wrapf(ErrInvalidFeeHistory, "reward percentiles are not ascending, %f > %f", rewardPercentiles[i-1], p)
+		}
+	}
+	if tip := gs.bc.TipHeight(); lastBlock > tip {
+		return nil, errors.Wrapf(ErrInvalidFeeHistory, "last block %d is higher than tip height %d", lastBlock, tip)
+	}
+	if lastBlock < blockCount {
+		// there is no block at genesis
+		blockCount = lastBlock
+	}
+	history := &FeeHistory{
+		OldestBlock:  lastBlock - blockCount + 1,
+		BaseFee:      make([]*big.Int, blockCount+1),
+		GasUsedRatio: make([]float64, blockCount),
+	}
+	if len(rewardPercentiles) > 0 {
+		history.Reward = make([][]*big.Int, blockCount)
+	}
+	for i := range history.BaseFee {
+		history.BaseFee[i] = big.NewInt(0)
+	}
+	for i := uint64(0); i < blockCount; i++ {
+		fee, err := gs.blockFee(history.OldestBlock + i)
+		if err != nil {
+			return nil, err
+		}
+		history.GasUsedRatio[i] = fee.gasUsedRatio
+		if history.Reward != nil {
+			history.Reward[i] = fee.rewards(rewardPercentiles)
+		}
+	}
+	return history, nil
+}
+
+func (gs *GasStation) blockFee(height uint64) (*blockFee, error) {
+	if gs.fees != nil {
+		if v, ok := gs.fees.Get(height); ok {
+			return v.(*blockFee), nil
+		}
+	}
+	blk, err := gs.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	gasConsumed := make(map[hash.Hash256]uint64)
+	if len(blk.Actions) > 0 {
+		receipts, err := gs.dao.GetReceipts(height)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range receipts {
+			gasConsumed[r.ActionHash] = r.GasConsumed
+		}
+	}
+	fee := &blockFee{}
+	var totalGas uint64
+	type userAction struct {
+		price *big.Int
+		gas   uint64
+	}
+	var acts []userAction
+	for _, selp := range blk.Actions {
+		gas := gasConsumed[selp.Hash()]
+		totalGas += gas
+		if gs.IsSystemAction(selp) {
+			continue
+		}
+		acts = append(acts, userAction{price: selp.GasPrice(), gas: gas})
+		fee.gasUsed += gas
+	}
+	sort.SliceStable(acts, func(i, j int) bool {
+		return acts[i].price.Cmp(acts[j].price) < 0
+	})
+	for _, act := range acts {
+		fee.prices = append(fee.prices, act.price)
+		fee.gas = append(fee.gas, act.gas)
+	}
+	g := gs.bc.Genesis()
+	if gasLimit := g.BlockGasLimitByHeight(height); gasLimit > 0 {
+		fee.gasUsedRatio = float64(totalGas) / float64(gasLimit)
+	}
+	if gs.fees != nil {
+		gs.fees.Add(height, fee)
+	}
+	return fee, nil
+}
+
+// rewards returns the gas prices at the percentiles of the gas used by the user actions
+func (fee *blockFee) rewards(percentiles []float64) []*big.Int {
+	rewards := make([]*big.Int, len(percentiles))
+	if len(fee.prices) == 0 {
+		for i := range rewards {
+			rewards[i] = big.NewInt(0)
+		}
+		return rewards
+	}
+	idx, sumGas := 0, fee.gas[0]
+	for i, p := range percentiles {
+		threshold := uint64(float64(fee.gasUsed) * p / 100)
+		for sumGas < threshold && idx < len(fee.prices)-1 {
+			idx++
+			sumGas += fee.gas[idx]
+		}
+		rewards[i] = new(big.Int).Set(fee.prices[idx])
+	}
+	return rewards
+}
+
 // EstimateGasForAction estimate gas for action
 func (gs *GasStation) EstimateGasForAction(actPb *iotextypes.Action) (uint64, error) {
 	var selp action.SealedEnvelope
@@ -15,6 +15,8 @@ import (
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/pkg/unit"
 
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -31,6 +33,7 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/version"
 	"github.com/iotexproject/iotex-core/state/factory"
 	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
 	"github.com/iotexproject/iotex-core/testutil"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 )
@@ -39,6 +42,29 @@ func TestNewGasStation(t *testing.T) {
 	require := require.New(t)
 	require.NotNil(NewGasStation(nil, nil, nil, config.Default.API))
 }
+
+func TestMinGasPrice(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	bc.EXPECT().TipHeight().Return(uint64(0)).AnyTimes()
+	cfg := config.Default.API
+
+	gs := NewGasStation(bc, nil, nil, cfg)
+	require.Zero(gs.MinGasPrice().Sign())
+	price, err := gs.SuggestGasPrice()
+	require.NoError(err)
+	require.Equal(cfg.GasStation.DefaultGas, price)
+
+	// the suggested gas price is no less than the minimal gas price accepted by the actpool
+	floor := new(big.Int).SetUint64(cfg.GasStation.DefaultGas * 3)
+	gs = NewGasStation(bc, nil, nil, cfg, WithMinGasPrice(func() *big.Int { return floor }))
+	require.Equal(floor, gs.MinGasPrice())
+	price, err = gs.SuggestGasPrice()
+	require.NoError(err)
+	require.Equal(floor.Uint64(), price)
+}
 func TestSuggestGasPriceForUserAction(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Default
@@ -113,6 +139,50 @@ func TestSuggestGasPriceForUserAction(t *testing.T) {
 	require.NoError(t, err)
 	// i from 10 to 29,gasprice for 20 to 39,60%*20+20=31
 	require.Equal(t, big.NewInt(1).Mul(big.NewInt(int64(31)), big.NewInt(unit.Qev)).Uint64(), gp)
+
+	history, err := gs.FeeHistory(4, height, []float64{0, 50, 100})
+	require.NoError(t, err)
+	require.Equal(t, height-3, history.OldestBlock)
+	require.Len(t, history.BaseFee, 5)
+	require.Len(t, history.GasUsedRatio, 4)
+	for i, rewards := range history.Reward {
+		// the block at height h has one transfer at the price of h+9
+		price := big.NewInt(1).Mul(big.NewInt(int64(history.OldestBlock)+int64(i)+9), big.NewInt(unit.Qev))
+		require.Equal(t, []*big.Int{price, price, price}, rewards)
+		require.True(t, history.GasUsedRatio[i] > 0)
+	}
+	history, err = gs.FeeHistory(100, 3, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), history.OldestBlock)
+	require.Len(t, history.GasUsedRatio, 3)
+	require.Nil(t, history.Reward)
+	for _, test := range []struct {
+		blockCount  uint64
+		lastBlock   uint64
+		percentiles []float64
+	}{
+		{0, height, nil},
+		{1, height + 1, nil},
+		{1, height, []float64{101}},
+		{1, height, []float64{50, 10}},
+	} {
+		_, err = gs.FeeHistory(test.blockCount, test.lastBlock, test.percentiles)
+		require.Equal(t, ErrInvalidFeeHistory, errors.Cause(err))
+	}
+}
+
+func TestBlockFeeRewards(t *testing.T) {
+	require := require.New(t)
+	fee := &blockFee{
+		gasUsed: 100,
+		prices:  []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)},
+		gas:     []uint64{10, 60, 30},
+	}
+	require.Equal(
+		[]*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(2), big.NewInt(2), big.NewInt(3)},
+		fee.rewards([]float64{0, 10, 11, 70, 71}),
+	)
+	require.Equal([]*big.Int{big.NewInt(0)}, (&blockFee{}).rewards([]float64{50}))
 }
 
 func TestSuggestGasPriceForSystemAction(t *testing.T) {
@@ -0,0 +1,35 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package genesis
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/ioctl/config"
+)
+
+// Multi-language support
+var (
+	genesisCmdShorts = map[config.Language]string{
+		config.English: "Manage the genesis config of a private network",
+		config.Chinese: "管理私有网络的创世配置",
+	}
+	genesisCmdUses = map[config.Language]string{
+		config.English: "genesis",
+		config.Chinese: "genesis",
+	}
+)
+
+// GenesisCmd represents the genesis command
+var GenesisCmd = &cobra.Command{
+	Use:   config.TranslateInLang(genesisCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(genesisCmdShorts, config.UILanguage),
+}
+
+func init() {
+	GenesisCmd.AddCommand(genesisGenerateCmd)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package genesis
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+	"github.com/iotexproject/iotex-core/ioctl/util"
+)
+
+// Multi-language support
+var (
+	generateCmdUses = map[config.Language]string{
+		config.English: "generate [--balance ADDRESS=IOTX]... [--delegate OPERATOR[,REWARD]=VOTES]... [-f FILE]",
+		config.Chinese: "generate [--balance 地址=IOTX]... [--delegate 操作地址[,收益地址]=票数]... [-f 文件]",
+	}
+	generateCmdShorts = map[config.Language]string{
+		config.English: "Generate a validated genesis yaml, which a node loads with the -genesis-path flag",
+		config.Chinese: "生成经过验证的创世yaml，节点可用 -genesis-path 参数加载",
+	}
+	flagTimestampUsages = map[config.Language]string{
+		config.English: "unix timestamp of the genesis block, now by default",
+		config.Chinese: "创世区块的unix时间戳，默认为当前时间",
+	}
+	flagBlockIntervalUsages = map[config.Language]string{
+		config.English: "interval between two blocks",
+		config.Chinese: "两个区块之间的间隔",
+	}
+	flagNumDelegatesUsages = map[config.Language]string{
+		config.English: "number of delegates producing blocks in an epoch",
+		config.Chinese: "每个纪元中出块的代表数量",
+	}
+	flagNumSubEpochsUsages = map[config.Language]string{
+		config.English: "number of sub epochs in an epoch",
+		config.Chinese: "每个纪元中子纪元的数量",
+	}
+	flagForkHeightUsages = map[config.Language]string{
+		config.English: "height all hard forks are activated at",
+		config.Chinese: "所有硬分叉的激活高度",
+	}
+	flagBalanceUsages = map[config.Language]string{
+		config.English: "initial balance of an account in IOTX, as ADDRESS=IOTX",
+		config.Chinese: "账户的初始余额（IOTX），格式为 地址=IOTX",
+	}
+	flagDelegateUsages = map[config.Language]string{
+		config.English: "delegate with its votes in IOTX, as OPERATOR[,REWARD]=VOTES",
+		config.Chinese: "代表及其票数（IOTX），格式为 操作地址[,收益地址]=票数",
+	}
+	flagGenesisFileUsages = map[config.Language]string{
+		config.English: "file to write the genesis yaml, stdout by default",
+		config.Chinese: "写入创世yaml的文件，默认为标准输出",
+	}
+)
+
+var (
+	timestamp     int64
+	blockInterval time.Duration
+	numDelegates  uint64
+	numSubEpochs  uint64
+	forkHeight    uint64
+	balances      []string
+	delegates     []string
+	genesisFile   string
+)
+
+// genesisGenerateCmd represents the genesis generate command
+var genesisGenerateCmd = &cobra.Command{
+	Use:   config.TranslateInLang(generateCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(generateCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := generate()
+		return output.PrintError(err)
+	},
+}
+
+func init() {
+	genesisGenerateCmd.Flags().Int64Var(&timestamp, "timestamp", 0,
+		config.TranslateInLang(flagTimestampUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().DurationVar(&blockInterval, "block-interval", 5*time.Second,
+		config.TranslateInLang(flagBlockIntervalUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().Uint64Var(&numDelegates, "num-delegates", 1,
+		config.TranslateInLang(flagNumDelegatesUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().Uint64Var(&numSubEpochs, "num-sub-epochs", 1,
+		config.TranslateInLang(flagNumSubEpochsUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().Uint64Var(&forkHeight, "fork-height", 1,
+		config.TranslateInLang(flagForkHeightUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().StringArrayVar(&balances, "balance", nil,
+		config.TranslateInLang(flagBalanceUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().StringArrayVar(&delegates, "delegate", nil,
+		config.TranslateInLang(flagDelegateUsages, config.UILanguage))
+	genesisGenerateCmd.Flags().StringVarP(&genesisFile, "file", "f", "",
+		config.TranslateInLang(flagGenesisFileUsages, config.UILanguage))
+}
+
+func generate() error {
+	ts := time.Now()
+	if timestamp != 0 {
+		ts = time.Unix(timestamp, 0)
+	}
+	b := genesis.NewBuilder().
+		SetTimestamp(ts).
+		SetBlockInterval(blockInterval).
+		SetEpoch(numDelegates, numDelegates, numSubEpochs).
+		SetForkHeight(forkHeight)
+	for _, v := range balances {
+		addrs, amount, err := parseAssignment(v)
+		if err != nil {
+			return err
+		}
+		if len(addrs) != 1 {
+			return output.NewError(output.FlagError, fmt.Sprintf("invalid balance %s", v), nil)
+		}
+		b.AddAccount(addrs[0], amount)
+	}
+	for _, v := range delegates {
+		addrs, votes, err := parseAssignment(v)
+		if err != nil {
+			return err
+		}
+		switch len(addrs) {
+		case 1:
+			b.AddDelegate(addrs[0], "", votes)
+		case 2:
+			b.AddDelegate(addrs[0], addrs[1], votes)
+		default:
+			return output.NewError(output.FlagError, fmt.Sprintf("invalid delegate %s", v), nil)
+		}
+	}
+	g, err := b.Build()
+	if err != nil {
+		return output.NewError(output.ValidationError, "invalid genesis", err)
+	}
+	out, err := g.YAML()
+	if err != nil {
+		return output.NewError(output.SerializationError, "failed to encode genesis", err)
+	}
+	if genesisFile == "" {
+		fmt.Print(string(out))
+		return nil
+	}
+	if err := ioutil.WriteFile(genesisFile, out, 0644); err != nil {
+		return output.NewError(output.WriteFileError, "failed to write genesis", err)
+	}
+	output.PrintResult(fmt.Sprintf("Genesis %x is written to %s", g.Hash(), genesisFile))
+	return nil
+}
+
+// parseAssignment parses ADDRESS[,ADDRESS]=IOTX, the addresses could be aliases
+func parseAssignment(v string) ([]string, *big.Int, error) {
+	parts := strings.SplitN(v, "=", 2)
+	if len(parts) != 2 {
+		return nil, nil, output.NewError(output.FlagError, fmt.Sprintf("invalid assignment %s", v), nil)
+	}
+	amount, err := util.StringToRau(parts[1], util.IotxDecimalNum)
+	if err != nil {
+		return nil, nil, output.NewError(output.ConvertError, fmt.Sprintf("invalid amount %s", parts[1]), err)
+	}
+	var addrs []string
+	for _, in := range strings.Split(parts[0], ",") {
+		addr, err := util.Address(in)
+		if err != nil {
+			return nil, nil, output.NewError(output.AddressError, "", err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, amount, nil
+}
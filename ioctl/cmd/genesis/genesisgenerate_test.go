@@ -0,0 +1,51 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package genesis
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestGenerate(t *testing.T) {
+	r := require.New(t)
+	addr := identityset.Address(0).String()
+	reward := identityset.Address(1).String()
+
+	genesisFile = filepath.Join(t.TempDir(), "genesis.yaml")
+	timestamp = 1600000000
+	balances = []string{addr + "=100"}
+	delegates = []string{addr + "," + reward + "=10.5"}
+	r.NoError(generate())
+
+	out, err := ioutil.ReadFile(genesisFile)
+	r.NoError(err)
+	var g genesis.Genesis
+	r.NoError(yaml.Unmarshal(out, &g))
+	r.Equal(int64(1600000000), g.Timestamp)
+	r.Equal("100000000000000000000", g.InitBalanceMap[addr])
+	r.Len(g.Delegates, 1)
+	r.Equal(reward, g.Delegates[0].RewardAddrStr)
+	r.Equal("10500000000000000000", g.Delegates[0].VotesStr)
+
+	// a network of 2 delegates is not generated with 1 delegate
+	numDelegates = 2
+	r.Error(generate())
+	numDelegates = 1
+
+	for _, v := range []string{addr, addr + "=abc", "io1abc=1"} {
+		_, _, err := parseAssignment(v)
+		r.Error(err)
+	}
+}
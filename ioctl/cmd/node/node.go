@@ -44,6 +44,8 @@ func init() {
 	NodeCmd.AddCommand(nodeDelegateCmd)
 	NodeCmd.AddCommand(nodeRewardCmd)
 	NodeCmd.AddCommand(nodeProbationlistCmd)
+	NodeCmd.AddCommand(nodeExportCmd)
+	NodeCmd.AddCommand(nodeBackupCmd)
 	NodeCmd.PersistentFlags().StringVar(&config.ReadConfig.Endpoint, "endpoint",
 		config.ReadConfig.Endpoint, config.TranslateInLang(flagEndpointUsages, config.UILanguage))
 	NodeCmd.PersistentFlags().BoolVar(&config.Insecure, "insecure", config.Insecure,
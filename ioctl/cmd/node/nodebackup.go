@@ -0,0 +1,95 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+)
+
+// Multi-language support
+var (
+	backupCmdUses = map[config.Language]string{
+		config.English: "backup DIR [--admin-endpoint HOST:PORT]",
+		config.Chinese: "backup 目录 [--admin-endpoint 主机:端口]",
+	}
+	backupCmdShorts = map[config.Language]string{
+		config.English: "Back up the databases of a running node into the absolute directory DIR of the node",
+		config.Chinese: "将运行中节点的数据库备份到节点上的绝对路径目录",
+	}
+	flagAdminEndpointUsages = map[config.Language]string{
+		config.English: "admin endpoint of the node",
+		config.Chinese: "节点的管理端点",
+	}
+)
+
+var adminEndpoint string
+
+// nodeBackupCmd represents the node backup command
+var nodeBackupCmd = &cobra.Command{
+	Use:   config.TranslateInLang(backupCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(backupCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := backup(args[0])
+		return output.PrintError(err)
+	},
+}
+
+type backupMessage struct {
+	Dir     string `json:"dir"`
+	Elapsed string `json:"elapsed"`
+}
+
+func (m *backupMessage) String() string {
+	if output.Format == "" {
+		return fmt.Sprintf("Backed up the databases to %s in %s", m.Dir, m.Elapsed)
+	}
+	return output.FormatString(output.Result, m)
+}
+
+func init() {
+	nodeBackupCmd.Flags().StringVar(&adminEndpoint, "admin-endpoint", "localhost:9009",
+		config.TranslateInLang(flagAdminEndpointUsages, config.UILanguage))
+}
+
+func backup(dir string) error {
+	u := url.URL{
+		Scheme:   "http",
+		Host:     adminEndpoint,
+		Path:     "/backup",
+		RawQuery: url.Values{"dir": []string{dir}}.Encode(),
+	}
+	resp, err := http.Post(u.String(), "", nil)
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to connect to admin endpoint", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to read response", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return output.NewError(output.APIError, strings.TrimSpace(string(body)), nil)
+	}
+	var message backupMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return output.NewError(output.SerializationError, "failed to deserialize response", err)
+	}
+	fmt.Println(message.String())
+	return nil
+}
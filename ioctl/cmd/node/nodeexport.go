@@ -0,0 +1,172 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package node
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/chainarchive"
+	"github.com/iotexproject/iotex-core/ioctl/cmd/bc"
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+	"github.com/iotexproject/iotex-core/ioctl/util"
+)
+
+// exportBatchSize is the number of blocks requested at a time
+const exportBatchSize = 100
+
+// Multi-language support
+var (
+	exportCmdUses = map[config.Language]string{
+		config.English: "export FILE [--from START_HEIGHT] [--to END_HEIGHT]",
+		config.Chinese: "export 文件 [--from 起始高度] [--to 结束高度]",
+	}
+	exportCmdShorts = map[config.Language]string{
+		config.English: "Export blocks to a chain archive, which a node imports with the -import-chain flag",
+		config.Chinese: "将区块导出到链存档，节点可用 -import-chain 参数导入",
+	}
+	flagExportFromUsages = map[config.Language]string{
+		config.English: "start height of the blocks",
+		config.Chinese: "区块的起始高度",
+	}
+	flagExportToUsages = map[config.Language]string{
+		config.English: "end height of the blocks, the tip height by default",
+		config.Chinese: "区块的结束高度，默认为最新高度",
+	}
+)
+
+var (
+	exportFrom uint64
+	exportTo   uint64
+)
+
+// nodeExportCmd represents the node export command
+var nodeExportCmd = &cobra.Command{
+	Use:   config.TranslateInLang(exportCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(exportCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := export(args[0])
+		return output.PrintError(err)
+	},
+}
+
+type exportMessage struct {
+	File string `json:"file"`
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+func (m *exportMessage) String() string {
+	if output.Format == "" {
+		return fmt.Sprintf("Exported blocks %d to %d to %s", m.From, m.To, m.File)
+	}
+	return output.FormatString(output.Result, m)
+}
+
+func init() {
+	nodeExportCmd.Flags().Uint64Var(&exportFrom, "from", 1,
+		config.TranslateInLang(flagExportFromUsages, config.UILanguage))
+	nodeExportCmd.Flags().Uint64Var(&exportTo, "to", 0,
+		config.TranslateInLang(flagExportToUsages, config.UILanguage))
+}
+
+func export(file string) error {
+	if exportTo == 0 {
+		chainMeta, err := bc.GetChainMeta()
+		if err != nil {
+			return output.NewError(0, "failed to get chain meta", err)
+		}
+		exportTo = chainMeta.Height
+	}
+	if exportFrom == 0 || exportFrom > exportTo {
+		return output.NewError(output.InputError, fmt.Sprintf("invalid range [%d, %d]", exportFrom, exportTo), nil)
+	}
+
+	// write to a temporary file, so an interrupted export leaves no partial archive
+	tmp := file + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return output.NewError(output.WriteFileError, "failed to create file", err)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if err := exportBlocks(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return output.NewError(output.WriteFileError, "failed to write file", err)
+	}
+	if err := f.Close(); err != nil {
+		return output.NewError(output.WriteFileError, "failed to write file", err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return output.NewError(output.WriteFileError, "failed to rename file", err)
+	}
+	message := exportMessage{File: file, From: exportFrom, To: exportTo}
+	fmt.Println(message.String())
+	return nil
+}
+
+func exportBlocks(w *bufio.Writer) error {
+	aw, err := chainarchive.NewWriter(w, exportFrom, exportTo)
+	if err != nil {
+		return output.NewError(output.WriteFileError, "failed to write archive", err)
+	}
+	conn, err := util.ConnectToEndpoint(config.ReadConfig.SecureConnect && !config.Insecure)
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to connect to endpoint", err)
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	ctx := context.Background()
+	jwtMD, err := util.JwtAuth()
+	if err == nil {
+		ctx = metautils.NiceMD(jwtMD).ToOutgoing(ctx)
+	}
+
+	for height := exportFrom; height <= exportTo; {
+		count := exportTo - height + 1
+		if count > exportBatchSize {
+			count = exportBatchSize
+		}
+		request := iotexapi.GetRawBlocksRequest{StartHeight: height, Count: count, WithReceipts: true}
+		response, err := cli.GetRawBlocks(ctx, &request)
+		if err != nil {
+			sta, ok := status.FromError(err)
+			if ok {
+				return output.NewError(output.APIError, sta.Message(), nil)
+			}
+			return output.NewError(output.NetworkError, "failed to invoke GetRawBlocks api", err)
+		}
+		if len(response.Blocks) == 0 {
+			return output.NewError(output.APIError, fmt.Sprintf("block %d is not returned", height), nil)
+		}
+		for _, blk := range response.Blocks {
+			if err := aw.Write(&iotextypes.BlockStore{Block: blk.Block, Receipts: blk.Receipts}); err != nil {
+				return output.NewError(output.WriteFileError, "failed to write archive", err)
+			}
+		}
+		height += uint64(len(response.Blocks))
+	}
+	if err := aw.Close(); err != nil {
+		return output.NewError(output.WriteFileError, "failed to write archive", err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/iotexproject/iotex-core/ioctl/cmd/bc"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/contract"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/did"
+	"github.com/iotexproject/iotex-core/ioctl/cmd/genesis"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/hdwallet"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/jwt"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/node"
@@ -78,6 +79,7 @@ func NewIoctl() *cobra.Command {
 	rootCmd.AddCommand(did.DIDCmd)
 	rootCmd.AddCommand(hdwallet.HdwalletCmd)
 	rootCmd.AddCommand(jwt.JwtCmd)
+	rootCmd.AddCommand(genesis.GenesisCmd)
 	rootCmd.PersistentFlags().StringVarP(&output.Format, "output-format", "o", "",
 		config.TranslateInLang(flagOutputFormatUsages, config.UILanguage))
 
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package lightclient
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/pkg/errors"
+)
+
+// maxResponseSize is the max size of a response of the backend
+const maxResponseSize = 4 << 20
+
+type (
+	// Backend is the full node serving the proofs to the light client, whose responses are not trusted but verified
+	Backend interface {
+		TipHeight(ctx context.Context) (uint64, error)
+		HeaderProof(ctx context.Context, height uint64) (*HeaderProof, error)
+		DelegatesProof(ctx context.Context, epoch uint64) (*DelegatesProof, error)
+		TxProof(ctx context.Context, actHash hash.Hash256) (*TxProof, error)
+	}
+
+	// restBackend requests the proofs from the rest server of a full node
+	restBackend struct {
+		endpoint string
+		client   *http.Client
+	}
+)
+
+// NewRESTBackend returns the backend requesting the rest server of a full node at the endpoint
+func NewRESTBackend(endpoint string, timeout time.Duration) Backend {
+	return &restBackend{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (b *restBackend) TipHeight(ctx context.Context) (uint64, error) {
+	res := &iotexapi.GetChainMetaResponse{}
+	if err := b.get(ctx, "/chainmeta", func(r io.Reader) error {
+		return jsonpb.Unmarshal(r, res)
+	}); err != nil {
+		return 0, err
+	}
+	if res.ChainMeta == nil {
+		return 0, errors.New("chain meta is missing in the response")
+	}
+	return res.ChainMeta.Height, nil
+}
+
+func (b *restBackend) HeaderProof(ctx context.Context, height uint64) (*HeaderProof, error) {
+	proof := &HeaderProof{}
+	if err := b.getJSON(ctx, "/light/headers/"+strconv.FormatUint(height, 10), proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+func (b *restBackend) DelegatesProof(ctx context.Context, epoch uint64) (*DelegatesProof, error) {
+	proof := &DelegatesProof{}
+	if err := b.getJSON(ctx, "/light/delegates/"+strconv.FormatUint(epoch, 10), proof); err != nil {
+		return nil, err
+	}
+	if proof.Proof == nil {
+		return nil, errors.Errorf("header proof of the delegates of epoch %d is missing in the response", epoch)
+	}
+	return proof, nil
+}
+
+func (b *restBackend) TxProof(ctx context.Context, actHash hash.Hash256) (*TxProof, error) {
+	proof := &TxProof{}
+	if err := b.getJSON(ctx, "/light/txproofs/"+hex.EncodeToString(actHash[:]), proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+func (b *restBackend) getJSON(ctx context.Context, path string, v interface{}) error {
+	return b.get(ctx, path, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(v)
+	})
+}
+
+func (b *restBackend) get(ctx context.Context, path string, decode func(io.Reader) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	res, err := b.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to request %s", path)
+	}
+	defer res.Body.Close()
+	body := io.LimitReader(res.Body, maxResponseSize)
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(body)
+		return errors.Errorf("failed to request %s: %s %s", path, res.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := decode(body); err != nil {
+		return errors.Wrapf(err, "failed to decode the response of %s", path)
+	}
+	return nil
+}
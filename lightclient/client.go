@@ -0,0 +1,301 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package lightclient
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	rp "github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
+	"github.com/iotexproject/iotex-core/crypto/bls"
+	"github.com/iotexproject/iotex-core/endorsement"
+)
+
+var (
+	// ErrUnknownDelegates indicates the delegate set of the epoch is not known to the client yet
+	ErrUnknownDelegates = errors.New("unknown delegates of the epoch")
+	// ErrUnknownHeader indicates the header is not verified by the client yet
+	ErrUnknownHeader = errors.New("unknown header")
+)
+
+// Client is a light client keeping only the headers and the delegate sets of the epochs, starting from a trusted
+// delegate set. A header is accepted once it's produced and endorsed by more than 2/3 of the delegates of its epoch,
+// i.e., it's final. Since the headers don't commit to the delegate sets, the delegate set of the next epoch is accepted
+// once a header of the epoch is endorsed by more than 2/3 of the new set and more than 1/3 of the current set, so at
+// least one honest delegate of the trusted set vouches for the handoff.
+type Client struct {
+	mu         sync.RWMutex
+	rp         *rp.Protocol
+	blsHeight  uint64
+	blsKeys    map[string]*bls.PublicKey
+	maxHeaders int
+	delegates  map[uint64]map[string]bool
+	headers    map[uint64]*block.Header
+	tip        uint64
+}
+
+// NewClient creates a light client trusting the delegate set of the epoch, and keeping up to maxHeaders headers
+func NewClient(g genesis.Genesis, epoch uint64, delegates []string, maxHeaders int) (*Client, error) {
+	if len(delegates) == 0 {
+		return nil, errors.New("trusted delegate set is empty")
+	}
+	c := &Client{
+		rp: rp.NewProtocol(
+			g.NumCandidateDelegates,
+			g.NumDelegates,
+			g.NumSubEpochs,
+			rp.EnableDardanellesSubEpoch(g.DardanellesBlockHeight, g.DardanellesNumSubEpochs),
+		),
+		blsHeight:  g.BLSAggregationBlockHeight,
+		blsKeys:    make(map[string]*bls.PublicKey, len(g.BLSPublicKeys)),
+		maxHeaders: maxHeaders,
+		delegates:  map[uint64]map[string]bool{epoch: toSet(delegates)},
+		headers:    make(map[uint64]*block.Header),
+	}
+	for operator := range g.BLSPublicKeys {
+		pk, err := g.BLSPublicKey(operator)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load BLS public key of %s", operator)
+		}
+		c.blsKeys[operator] = pk
+	}
+	return c, nil
+}
+
+// EpochNum returns the epoch of the height
+func (c *Client) EpochNum(height uint64) uint64 {
+	return c.rp.GetEpochNum(height)
+}
+
+// EpochHeight returns the first height of the epoch
+func (c *Client) EpochHeight(epoch uint64) uint64 {
+	return c.rp.GetEpochHeight(epoch)
+}
+
+// LastEpoch returns the highest epoch whose delegate set is known
+func (c *Client) LastEpoch() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var last uint64
+	for epoch := range c.delegates {
+		if epoch > last {
+			last = epoch
+		}
+	}
+	return last
+}
+
+// Delegates returns the delegate set of the epoch
+func (c *Client) Delegates(epoch uint64) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	set, ok := c.delegates[epoch]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownDelegates, "epoch %d", epoch)
+	}
+	delegates := make([]string, 0, len(set))
+	for d := range set {
+		delegates = append(delegates, d)
+	}
+	sort.Strings(delegates)
+	return delegates, nil
+}
+
+// TipHeight returns the height of the highest header verified
+func (c *Client) TipHeight() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tip
+}
+
+// Header returns the verified header of the height
+func (c *Client) Header(height uint64) (*block.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	header, ok := c.headers[height]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownHeader, "height %d", height)
+	}
+	return header, nil
+}
+
+// AddHeader verifies the header proof against the delegate set of its epoch, and keeps the header
+func (c *Client) AddHeader(proof *HeaderProof) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	height := proof.Header.Height()
+	set, ok := c.delegates[c.rp.GetEpochNum(height)]
+	if !ok {
+		return errors.Wrapf(ErrUnknownDelegates, "epoch of height %d", height)
+	}
+	endorsers, err := c.verify(proof)
+	if err != nil {
+		return err
+	}
+	if !set[proof.Header.ProducerAddress()] {
+		return errors.Errorf("producer %s of header %d is not a delegate", proof.Header.ProducerAddress(), height)
+	}
+	if !isMajority(endorsers, set) {
+		return errors.Errorf("header %d is not endorsed by more than 2/3 of the delegates", height)
+	}
+	return c.add(proof.Header)
+}
+
+// AddDelegates accepts the delegate set of the epoch following a known one, if the header proof of the epoch is
+// endorsed by more than 2/3 of the new set and more than 1/3 of the previous set
+func (c *Client) AddDelegates(proof *DelegatesProof) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.delegates[proof.Epoch]; ok {
+		return nil
+	}
+	prev, ok := c.delegates[proof.Epoch-1]
+	if !ok {
+		return errors.Wrapf(ErrUnknownDelegates, "epoch %d", proof.Epoch-1)
+	}
+	if len(proof.Delegates) == 0 {
+		return errors.Errorf("delegate set of epoch %d is empty", proof.Epoch)
+	}
+	if proof.Proof == nil {
+		return errors.Errorf("delegate set of epoch %d has no header proof", proof.Epoch)
+	}
+	height := proof.Proof.Header.Height()
+	if epoch := c.rp.GetEpochNum(height); epoch != proof.Epoch {
+		return errors.Errorf("header %d of epoch %d proves the delegates of epoch %d", height, epoch, proof.Epoch)
+	}
+	endorsers, err := c.verify(proof.Proof)
+	if err != nil {
+		return err
+	}
+	set := toSet(proof.Delegates)
+	if !set[proof.Proof.Header.ProducerAddress()] {
+		return errors.Errorf("producer %s of header %d is not a delegate", proof.Proof.Header.ProducerAddress(), height)
+	}
+	if !isMajority(endorsers, set) {
+		return errors.Errorf("header %d is not endorsed by more than 2/3 of the new delegates", height)
+	}
+	var vouched int
+	for endorser := range endorsers {
+		if prev[endorser] {
+			vouched++
+		}
+	}
+	if 3*vouched <= len(prev) {
+		return errors.Errorf("header %d is not endorsed by more than 1/3 of the delegates of epoch %d", height, proof.Epoch-1)
+	}
+	c.delegates[proof.Epoch] = set
+	return c.add(proof.Proof.Header)
+}
+
+// VerifyTx checks the tx proof against the verified header of its height
+func (c *Client) VerifyTx(proof *TxProof) error {
+	header, err := c.Header(proof.Height)
+	if err != nil {
+		return err
+	}
+	return proof.Verify(header)
+}
+
+// verify checks the signature of the header and the commit endorsements of the footer, and returns the endorsers
+func (c *Client) verify(proof *HeaderProof) (map[string]bool, error) {
+	header := proof.Header
+	if !header.VerifySignature() {
+		return nil, errors.Errorf("failed to verify the signature of header %d", header.Height())
+	}
+	blkHash := header.HashBlock()
+	vote := rolldpos.NewConsensusVote(blkHash[:], rolldpos.COMMIT)
+	ens := proof.Footer.Endorsements()
+	if header.Height() >= c.blsHeight {
+		pks := make([]*bls.PublicKey, 0, len(ens))
+		for _, en := range ens {
+			addr, err := address.FromBytes(en.Endorser().Hash())
+			if err != nil {
+				return nil, err
+			}
+			pk, ok := c.blsKeys[addr.String()]
+			if !ok {
+				return nil, errors.Errorf("BLS public key of %s is not registered", addr.String())
+			}
+			pks = append(pks, pk)
+		}
+		if !endorsement.VerifyAggregatedEndorsements(vote, ens, pks) {
+			return nil, errors.Errorf("invalid aggregated endorsements of header %d", header.Height())
+		}
+	} else {
+		for _, en := range ens {
+			if !endorsement.VerifyEndorsement(vote, en) {
+				return nil, errors.Errorf("invalid endorsement of header %d", header.Height())
+			}
+		}
+	}
+	endorsers := make(map[string]bool, len(ens))
+	for _, en := range ens {
+		addr, err := address.FromBytes(en.Endorser().Hash())
+		if err != nil {
+			return nil, err
+		}
+		endorsers[addr.String()] = true
+	}
+	return endorsers, nil
+}
+
+// add keeps the verified header, which has to link to the neighboring headers kept. The lowest headers are dropped
+// beyond the max number of headers.
+func (c *Client) add(header *block.Header) error {
+	height := header.Height()
+	h := header.HashBlock()
+	if old, ok := c.headers[height]; ok {
+		if old.HashBlock() != h {
+			return errors.Errorf("conflicting final headers at height %d", height)
+		}
+		return nil
+	}
+	if prev, ok := c.headers[height-1]; ok && prev.HashBlock() != header.PrevHash() {
+		return errors.Errorf("header %d doesn't link to the previous header", height)
+	}
+	if next, ok := c.headers[height+1]; ok && next.PrevHash() != h {
+		return errors.Errorf("header %d doesn't link to the next header", height)
+	}
+	c.headers[height] = header
+	if height > c.tip {
+		c.tip = height
+	}
+	for c.maxHeaders > 0 && len(c.headers) > c.maxHeaders {
+		lowest := height
+		for h := range c.headers {
+			if h < lowest {
+				lowest = h
+			}
+		}
+		delete(c.headers, lowest)
+	}
+	return nil
+}
+
+func isMajority(endorsers map[string]bool, delegates map[string]bool) bool {
+	var count int
+	for endorser := range endorsers {
+		if delegates[endorser] {
+			count++
+		}
+	}
+	return 3*count > 2*len(delegates)
+}
+
+func toSet(delegates []string) map[string]bool {
+	set := make(map[string]bool, len(delegates))
+	for _, d := range delegates {
+		set[d] = true
+	}
+	return set
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package lightclient
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// testGenesis has epochs of 2 blocks
+func testGenesis() genesis.Genesis {
+	g := genesis.Default
+	g.NumDelegates = 2
+	g.NumSubEpochs = 1
+	return g
+}
+
+func delegates(ids ...int) []string {
+	var ret []string
+	for _, id := range ids {
+		ret = append(ret, identityset.Address(id).String())
+	}
+	return ret
+}
+
+// newFinalBlock returns the block produced by the producer and endorsed by the endorsers
+func newFinalBlock(t *testing.T, height uint64, prev hash.Hash256, producer int, endorsers []int, acts ...action.SealedEnvelope) *block.Block {
+	ts := time.Unix(1600000000+int64(height), 0)
+	blk, err := block.NewTestingBuilder().
+		SetHeight(height).
+		SetPrevBlockHash(prev).
+		SetTimeStamp(ts).
+		AddActions(acts...).
+		SignAndBuild(identityset.PrivateKey(producer))
+	require.NoError(t, err)
+	h := blk.HashBlock()
+	vote := rolldpos.NewConsensusVote(h[:], rolldpos.COMMIT)
+	var ens []*endorsement.Endorsement
+	for _, i := range endorsers {
+		en, err := endorsement.Endorse(identityset.PrivateKey(i), vote, ts)
+		require.NoError(t, err)
+		ens = append(ens, en)
+	}
+	require.NoError(t, blk.Finalize(ens, ts))
+	return &blk
+}
+
+func TestClientHeaders(t *testing.T) {
+	require := require.New(t)
+	c, err := NewClient(testGenesis(), 1, delegates(0, 1, 2), 3)
+	require.NoError(err)
+
+	blk1 := newFinalBlock(t, 1, hash.ZeroHash256, 0, []int{0, 1, 2})
+	require.NoError(c.AddHeader(NewHeaderProof(blk1)))
+	header, err := c.Header(1)
+	require.NoError(err)
+	require.Equal(blk1.HashBlock(), header.HashBlock())
+	require.EqualValues(1, c.TipHeight())
+	_, err = c.Header(2)
+	require.Equal(ErrUnknownHeader, errors.Cause(err))
+
+	// the header has to be final
+	blk2 := newFinalBlock(t, 2, blk1.HashBlock(), 1, []int{0, 1})
+	require.Contains(c.AddHeader(NewHeaderProof(blk2)).Error(), "not endorsed by more than 2/3")
+	blk2 = newFinalBlock(t, 2, blk1.HashBlock(), 1, []int{0, 1, 5})
+	require.Contains(c.AddHeader(NewHeaderProof(blk2)).Error(), "not endorsed by more than 2/3")
+	blk2 = newFinalBlock(t, 2, blk1.HashBlock(), 5, []int{0, 1, 2})
+	require.Contains(c.AddHeader(NewHeaderProof(blk2)).Error(), "is not a delegate")
+	// the endorsements of another block don't count
+	other := newFinalBlock(t, 2, hash.ZeroHash256, 1, []int{0, 1, 2})
+	blk2 = newFinalBlock(t, 2, blk1.HashBlock(), 1, nil)
+	require.Contains(c.AddHeader(&HeaderProof{Header: &blk2.Header, Footer: &other.Footer}).Error(), "invalid endorsement")
+	// the header has to link to the neighbors
+	require.Contains(c.AddHeader(NewHeaderProof(other)).Error(), "doesn't link to the previous header")
+	blk2 = newFinalBlock(t, 2, blk1.HashBlock(), 1, []int{0, 1, 2})
+	require.NoError(c.AddHeader(NewHeaderProof(blk2)))
+	require.EqualValues(2, c.TipHeight())
+
+	// the delegates of the next epoch are unknown
+	blk3 := newFinalBlock(t, 3, blk2.HashBlock(), 0, []int{0, 1, 2})
+	err = c.AddHeader(NewHeaderProof(blk3))
+	require.Equal(ErrUnknownDelegates, errors.Cause(err))
+}
+
+func TestClientDelegates(t *testing.T) {
+	require := require.New(t)
+	c, err := NewClient(testGenesis(), 1, delegates(0, 1, 2), 2)
+	require.NoError(err)
+	require.EqualValues(2, c.EpochNum(3))
+	require.EqualValues(3, c.EpochHeight(2))
+
+	// the new delegates have to be vouched by more than 1/3 of the current ones
+	blk3 := newFinalBlock(t, 3, hash.ZeroHash256, 3, []int{3, 4, 5})
+	err = c.AddDelegates(&DelegatesProof{Epoch: 2, Delegates: delegates(3, 4, 5), Proof: NewHeaderProof(blk3)})
+	require.Contains(err.Error(), "not endorsed by more than 1/3 of the delegates of epoch 1")
+	blk3 = newFinalBlock(t, 3, hash.ZeroHash256, 3, []int{2, 3, 4})
+	err = c.AddDelegates(&DelegatesProof{Epoch: 2, Delegates: delegates(2, 3, 4), Proof: NewHeaderProof(blk3)})
+	require.Contains(err.Error(), "not endorsed by more than 1/3 of the delegates of epoch 1")
+	// the header has to be of the epoch
+	blk1 := newFinalBlock(t, 1, hash.ZeroHash256, 1, []int{1, 2, 3})
+	err = c.AddDelegates(&DelegatesProof{Epoch: 2, Delegates: delegates(1, 2, 3), Proof: NewHeaderProof(blk1)})
+	require.Contains(err.Error(), "proves the delegates of epoch 2")
+	_, err = c.Delegates(2)
+	require.Equal(ErrUnknownDelegates, errors.Cause(err))
+
+	blk2 := newFinalBlock(t, 2, hash.ZeroHash256, 1, []int{0, 1, 2})
+	blk3 = newFinalBlock(t, 3, blk2.HashBlock(), 3, []int{1, 2, 3})
+	require.NoError(c.AddDelegates(&DelegatesProof{Epoch: 2, Delegates: delegates(3, 2, 1), Proof: NewHeaderProof(blk3)}))
+	d, err := c.Delegates(2)
+	require.NoError(err)
+	require.ElementsMatch(delegates(1, 2, 3), d)
+	require.EqualValues(2, c.LastEpoch())
+	require.EqualValues(3, c.TipHeight())
+
+	// the epoch after an unknown one is rejected
+	blk5 := newFinalBlock(t, 5, hash.ZeroHash256, 1, []int{1, 2, 3})
+	err = c.AddDelegates(&DelegatesProof{Epoch: 4, Delegates: delegates(1, 2, 3), Proof: NewHeaderProof(blk5)})
+	require.Equal(ErrUnknownDelegates, errors.Cause(err))
+
+	// the lowest headers are dropped beyond the max
+	blk4 := newFinalBlock(t, 4, blk3.HashBlock(), 1, []int{1, 2, 3})
+	require.NoError(c.AddHeader(NewHeaderProof(blk4)))
+	require.NoError(c.AddHeader(NewHeaderProof(blk2)))
+	_, err = c.Header(2)
+	require.Equal(ErrUnknownHeader, errors.Cause(err))
+	_, err = c.Header(4)
+	require.NoError(err)
+}
+
+func TestClientTxProof(t *testing.T) {
+	require := require.New(t)
+	c, err := NewClient(testGenesis(), 1, delegates(0, 1, 2), 0)
+	require.NoError(err)
+
+	var acts []action.SealedEnvelope
+	for i := 0; i < 5; i++ {
+		act, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(0), uint64(i+1), big.NewInt(1), nil, 10000, big.NewInt(0))
+		require.NoError(err)
+		acts = append(acts, act)
+	}
+	blk := newFinalBlock(t, 1, hash.ZeroHash256, 0, []int{0, 1, 2}, acts...)
+	proof, err := NewTxProof(blk, acts[3].Hash())
+	require.NoError(err)
+	require.Equal(ErrUnknownHeader, errors.Cause(c.VerifyTx(proof)))
+	require.NoError(c.AddHeader(NewHeaderProof(blk)))
+	require.NoError(c.VerifyTx(proof))
+
+	// the json encoding
+	data, err := json.Marshal(proof)
+	require.NoError(err)
+	decoded := &TxProof{}
+	require.NoError(json.Unmarshal(data, decoded))
+	require.Equal(proof, decoded)
+
+	proof.Index = 2
+	require.Error(c.VerifyTx(proof))
+	proof.Index = 3
+	proof.ActionHash = acts[2].Hash()
+	require.Error(c.VerifyTx(proof))
+	_, err = NewTxProof(blk, hash.ZeroHash256)
+	require.Error(err)
+}
+
+func TestHeaderProofJSON(t *testing.T) {
+	require := require.New(t)
+	blk := newFinalBlock(t, 1, hash.ZeroHash256, 0, []int{0, 1, 2})
+	data, err := json.Marshal(NewHeaderProof(blk))
+	require.NoError(err)
+	proof := &HeaderProof{}
+	require.NoError(json.Unmarshal(data, proof))
+	require.Equal(blk.HashBlock(), proof.Header.HashBlock())
+	require.Len(proof.Footer.Endorsements(), 3)
+
+	c, err := NewClient(testGenesis(), 1, delegates(0, 1, 2), 0)
+	require.NoError(err)
+	require.NoError(c.AddHeader(proof))
+	require.Error(json.Unmarshal([]byte(`{"header":"AAAA"}`), proof))
+}
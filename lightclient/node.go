@@ -0,0 +1,213 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package lightclient
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+)
+
+type (
+	// Node is a light node, which syncs the delegate sets and the tip header from a full node backend, and answers the
+	// queries with the proofs verified against them. It keeps neither the blocks nor the states.
+	Node struct {
+		cfg     config.LightClient
+		client  *Client
+		backend Backend
+		task    *routine.RecurringTask
+	}
+
+	// headerResponse is the verified header answered by the node
+	headerResponse struct {
+		Height    uint64 `json:"height"`
+		Hash      string `json:"hash"`
+		PrevHash  string `json:"prevHash"`
+		Timestamp string `json:"timestamp"`
+		TxRoot    string `json:"txRoot"`
+		Producer  string `json:"producer"`
+	}
+
+	// actionResponse is the verified inclusion of an action answered by the node
+	actionResponse struct {
+		Proof  *TxProof        `json:"proof"`
+		Header *headerResponse `json:"header"`
+	}
+)
+
+// NewNode creates a light node
+func NewNode(cfg config.LightClient, g genesis.Genesis, backend Backend) (*Node, error) {
+	client, err := NewClient(g, cfg.TrustedEpoch, cfg.TrustedDelegates, cfg.MaxHeaders)
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{
+		cfg:     cfg,
+		client:  client,
+		backend: backend,
+	}
+	n.task = routine.NewRecurringTask(n.Sync, cfg.SyncInterval)
+	return n, nil
+}
+
+// Start starts syncing from the backend
+func (n *Node) Start(ctx context.Context) error {
+	return n.task.Start(ctx)
+}
+
+// Stop stops syncing from the backend
+func (n *Node) Stop(ctx context.Context) error {
+	return n.task.Stop(ctx)
+}
+
+// Client returns the light client of the node
+func (n *Node) Client() *Client {
+	return n.client
+}
+
+// Sync verifies the delegate sets of the epochs up to the tip of the backend, and the tip header
+func (n *Node) Sync() {
+	ctx, cancel := context.WithTimeout(context.Background(), n.cfg.RequestTimeout)
+	defer cancel()
+	if err := n.sync(ctx); err != nil {
+		log.L().Warn("Failed to sync the light client.", zap.Error(err))
+	}
+}
+
+func (n *Node) sync(ctx context.Context) error {
+	tip, err := n.backend.TipHeight(ctx)
+	if err != nil {
+		return err
+	}
+	tipEpoch := n.client.EpochNum(tip)
+	for epoch := n.client.LastEpoch() + 1; epoch <= tipEpoch; epoch++ {
+		proof, err := n.backend.DelegatesProof(ctx, epoch)
+		if err != nil {
+			return err
+		}
+		if err := n.client.AddDelegates(proof); err != nil {
+			return errors.Wrapf(err, "failed to verify the delegates of epoch %d", epoch)
+		}
+		log.L().Info("Verified the delegates of the epoch.", zap.Uint64("epoch", epoch))
+	}
+	if tip <= n.client.TipHeight() {
+		return nil
+	}
+	_, err = n.Header(ctx, tip)
+	return err
+}
+
+// Header returns the header of the height, which is requested from the backend and verified if it's not kept
+func (n *Node) Header(ctx context.Context, height uint64) (*block.Header, error) {
+	header, err := n.client.Header(height)
+	if err == nil {
+		return header, nil
+	}
+	proof, err := n.backend.HeaderProof(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if proof.Header.Height() != height {
+		return nil, errors.Errorf("backend returned header %d for height %d", proof.Header.Height(), height)
+	}
+	if err := n.client.AddHeader(proof); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify header %d", height)
+	}
+	return proof.Header, nil
+}
+
+// VerifyAction returns the proof of the action included in a final block, and the header of the block
+func (n *Node) VerifyAction(ctx context.Context, actHash hash.Hash256) (*TxProof, *block.Header, error) {
+	proof, err := n.backend.TxProof(ctx, actHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if proof.ActionHash != actHash {
+		return nil, nil, errors.Errorf("backend returned the proof of action %x for %x", proof.ActionHash, actHash)
+	}
+	header, err := n.Header(ctx, proof.Height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := proof.Verify(header); err != nil {
+		return nil, nil, err
+	}
+	return proof, header, nil
+}
+
+// ServeHTTP answers the queries of the verified headers at /headers/{height} and of the verified actions at
+// /actions/{hash}
+func (n *Node) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), n.cfg.RequestTimeout)
+	defer cancel()
+	switch path := req.URL.Path; {
+	case strings.HasPrefix(path, "/headers/"):
+		height, err := strconv.ParseUint(strings.TrimPrefix(path, "/headers/"), 10, 64)
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, map[string]string{"error": "invalid height"})
+			return
+		}
+		header, err := n.Header(ctx, height)
+		if err != nil {
+			writeResponse(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+		writeResponse(w, http.StatusOK, toHeaderResponse(header))
+	case strings.HasPrefix(path, "/actions/"):
+		actHash, err := hash.HexStringToHash256(strings.TrimPrefix(path, "/actions/"))
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, map[string]string{"error": "invalid action hash"})
+			return
+		}
+		proof, header, err := n.VerifyAction(ctx, actHash)
+		if err != nil {
+			writeResponse(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+		writeResponse(w, http.StatusOK, &actionResponse{Proof: proof, Header: toHeaderResponse(header)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func toHeaderResponse(header *block.Header) *headerResponse {
+	h, prev, root := header.HashBlock(), header.PrevHash(), header.TxRoot()
+	return &headerResponse{
+		Height:    header.Height(),
+		Hash:      hex.EncodeToString(h[:]),
+		PrevHash:  hex.EncodeToString(prev[:]),
+		Timestamp: header.Timestamp().UTC().Format(time.RFC3339Nano),
+		TxRoot:    hex.EncodeToString(root[:]),
+		Producer:  header.ProducerAddress(),
+	}
+}
+
+func writeResponse(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.L().Warn("failed to write light client response.", zap.Error(err))
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package lightclient
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type fakeBackend struct {
+	blocks    map[uint64]*block.Block
+	delegates map[uint64][]string
+	epochs    func(uint64) uint64
+}
+
+func (b *fakeBackend) TipHeight(context.Context) (uint64, error) {
+	return uint64(len(b.blocks)), nil
+}
+
+func (b *fakeBackend) HeaderProof(_ context.Context, height uint64) (*HeaderProof, error) {
+	blk, ok := b.blocks[height]
+	if !ok {
+		return nil, errors.Errorf("block %d not found", height)
+	}
+	return NewHeaderProof(blk), nil
+}
+
+func (b *fakeBackend) DelegatesProof(_ context.Context, epoch uint64) (*DelegatesProof, error) {
+	blk, ok := b.blocks[b.epochs(epoch)]
+	if !ok {
+		return nil, errors.Errorf("epoch %d not found", epoch)
+	}
+	return &DelegatesProof{Epoch: epoch, Delegates: b.delegates[epoch], Proof: NewHeaderProof(blk)}, nil
+}
+
+func (b *fakeBackend) TxProof(_ context.Context, actHash hash.Hash256) (*TxProof, error) {
+	for _, blk := range b.blocks {
+		if proof, err := NewTxProof(blk, actHash); err == nil {
+			return proof, nil
+		}
+	}
+	return nil, errors.New("action not found")
+}
+
+func TestNode(t *testing.T) {
+	require := require.New(t)
+	act, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(0), 1, big.NewInt(1), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	// epoch 1 is produced by 0, 1, 2, and epoch 2 by 1, 2, 3
+	blk1 := newFinalBlock(t, 1, hash.ZeroHash256, 0, []int{0, 1, 2})
+	blk2 := newFinalBlock(t, 2, blk1.HashBlock(), 1, []int{0, 1, 2}, act)
+	blk3 := newFinalBlock(t, 3, blk2.HashBlock(), 3, []int{1, 2, 3})
+	blk4 := newFinalBlock(t, 4, blk3.HashBlock(), 2, []int{1, 2, 3})
+
+	cfg := config.Default.LightClient
+	cfg.TrustedEpoch = 1
+	cfg.TrustedDelegates = delegates(0, 1, 2)
+	cfg.SyncInterval = time.Hour
+	g := testGenesis()
+	backend := &fakeBackend{
+		blocks:    map[uint64]*block.Block{1: blk1, 2: blk2, 3: blk3, 4: blk4},
+		delegates: map[uint64][]string{2: delegates(1, 2, 3)},
+	}
+	node, err := NewNode(cfg, g, backend)
+	require.NoError(err)
+	backend.epochs = node.Client().EpochHeight
+
+	node.Sync()
+	require.EqualValues(2, node.Client().LastEpoch())
+	require.EqualValues(4, node.Client().TipHeight())
+
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/headers/2")
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.StatusCode)
+	header := &headerResponse{}
+	require.NoError(json.NewDecoder(res.Body).Decode(header))
+	require.NoError(res.Body.Close())
+	h := blk2.HashBlock()
+	require.Equal(hex.EncodeToString(h[:]), header.Hash)
+	require.Equal(identityset.Address(1).String(), header.Producer)
+
+	actHash := act.Hash()
+	res, err = http.Get(srv.URL + "/actions/" + hex.EncodeToString(actHash[:]))
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.StatusCode)
+	action := &actionResponse{}
+	require.NoError(json.NewDecoder(res.Body).Decode(action))
+	require.NoError(res.Body.Close())
+	require.Equal(actHash, action.Proof.ActionHash)
+	require.EqualValues(2, action.Header.Height)
+
+	for path, code := range map[string]int{
+		"/headers/x": http.StatusBadRequest,
+		"/headers/5": http.StatusBadGateway,
+		"/actions/" + hex.EncodeToString(hash.ZeroHash256[:]): http.StatusBadGateway,
+		"/blocks/1": http.StatusNotFound,
+	} {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(err)
+		require.NoError(res.Body.Close())
+		require.Equal(code, res.StatusCode, path)
+	}
+
+	// the header forged by the backend is rejected
+	forged := newFinalBlock(t, 2, blk1.HashBlock(), 1, []int{3, 4, 5})
+	backend.blocks[2] = forged
+	node, err = NewNode(cfg, g, backend)
+	require.NoError(err)
+	_, err = node.Header(context.Background(), 2)
+	require.Contains(err.Error(), "not endorsed by more than 2/3")
+}
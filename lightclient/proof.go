@@ -0,0 +1,167 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package lightclient
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/crypto"
+)
+
+type (
+	// HeaderProof is a header with the commit endorsements of its footer, which prove the header is final
+	HeaderProof struct {
+		Header *block.Header
+		Footer *block.Footer
+	}
+
+	// DelegatesProof is the delegate set of an epoch, with the proof of the first header of the epoch endorsed by the
+	// delegates
+	DelegatesProof struct {
+		Epoch     uint64       `json:"epoch"`
+		Delegates []string     `json:"delegates"`
+		Proof     *HeaderProof `json:"proof"`
+	}
+
+	// TxProof is the merkle path of an action to the tx root of the header at the height
+	TxProof struct {
+		Height     uint64
+		ActionHash hash.Hash256
+		Index      int
+		Path       []hash.Hash256
+	}
+
+	// headerProofJSON is the json encoding of the serialized header and footer
+	headerProofJSON struct {
+		Header []byte `json:"header"`
+		Footer []byte `json:"footer"`
+	}
+
+	// txProofJSON is the json encoding of a tx proof with the hashes in hex
+	txProofJSON struct {
+		Height     uint64   `json:"height"`
+		ActionHash string   `json:"actionHash"`
+		Index      int      `json:"index"`
+		Path       []string `json:"path"`
+	}
+)
+
+// NewHeaderProof returns the header proof of the block
+func NewHeaderProof(blk *block.Block) *HeaderProof {
+	return &HeaderProof{
+		Header: &blk.Header,
+		Footer: &blk.Footer,
+	}
+}
+
+// MarshalJSON encodes the header proof into json
+func (p *HeaderProof) MarshalJSON() ([]byte, error) {
+	header, err := p.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	footer, err := p.Footer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&headerProofJSON{Header: header, Footer: footer})
+}
+
+// UnmarshalJSON decodes the header proof from json
+func (p *HeaderProof) UnmarshalJSON(data []byte) error {
+	var v headerProofJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	header, footer := &block.Header{}, &block.Footer{}
+	if err := header.Deserialize(v.Header); err != nil {
+		return errors.Wrap(err, "failed to deserialize header")
+	}
+	if err := footer.Deserialize(v.Footer); err != nil {
+		return errors.Wrap(err, "failed to deserialize footer")
+	}
+	p.Header, p.Footer = header, footer
+	return nil
+}
+
+// NewTxProof returns the proof of the action in the block
+func NewTxProof(blk *block.Block, actHash hash.Hash256) (*TxProof, error) {
+	leaves := make([]hash.Hash256, 0, len(blk.Actions))
+	index := -1
+	for i, act := range blk.Actions {
+		h := act.Hash()
+		if h == actHash {
+			index = i
+		}
+		leaves = append(leaves, h)
+	}
+	if index < 0 {
+		return nil, errors.Errorf("action %x is not in block %d", actHash, blk.Height())
+	}
+	path, err := crypto.NewMerkleTree(leaves).Proof(index)
+	if err != nil {
+		return nil, err
+	}
+	return &TxProof{
+		Height:     blk.Height(),
+		ActionHash: actHash,
+		Index:      index,
+		Path:       path,
+	}, nil
+}
+
+// Verify checks the proof against the tx root of the header
+func (p *TxProof) Verify(header *block.Header) error {
+	if header.Height() != p.Height {
+		return errors.Errorf("proof of height %d is checked against header %d", p.Height, header.Height())
+	}
+	if !crypto.VerifyMerkleProof(header.TxRoot(), p.ActionHash, p.Index, p.Path) {
+		return errors.Errorf("action %x is not included in block %d", p.ActionHash, p.Height)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the tx proof into json
+func (p *TxProof) MarshalJSON() ([]byte, error) {
+	v := txProofJSON{
+		Height:     p.Height,
+		ActionHash: hex.EncodeToString(p.ActionHash[:]),
+		Index:      p.Index,
+		Path:       make([]string, 0, len(p.Path)),
+	}
+	for _, h := range p.Path {
+		v.Path = append(v.Path, hex.EncodeToString(h[:]))
+	}
+	return json.Marshal(&v)
+}
+
+// UnmarshalJSON decodes the tx proof from json
+func (p *TxProof) UnmarshalJSON(data []byte) error {
+	var v txProofJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	actHash, err := hash.HexStringToHash256(v.ActionHash)
+	if err != nil {
+		return errors.Wrap(err, "invalid action hash")
+	}
+	path := make([]hash.Hash256, 0, len(v.Path))
+	for _, s := range v.Path {
+		h, err := hash.HexStringToHash256(s)
+		if err != nil {
+			return errors.Wrap(err, "invalid merkle path")
+		}
+		path = append(path, h)
+	}
+	p.Height, p.ActionHash, p.Index, p.Path = v.Height, actHash, v.Index, path
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+
+	p2p "github.com/iotexproject/go-p2p"
+	"github.com/iotexproject/go-pkgs/hash"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const (
+	actionAnnounceTopic = "actionannounce"
+	actionRequestTopic  = "actionrequest"
+)
+
+type (
+	// HandleActionHashesInbound handles the hashes of actions announced or requested by a peer
+	HandleActionHashesInbound func(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256)
+
+	// Option is the option to create an agent
+	Option func(*Agent)
+)
+
+// WithActionHashHandlers enables the agent to exchange the hashes of actions with peers. The announced hashes are
+// handled by announce, and the requested hashes by request.
+func WithActionHashHandlers(announce, request HandleActionHashesInbound) Option {
+	return func(p *Agent) {
+		p.actionAnnounceHandler = announce
+		p.actionRequestHandler = request
+	}
+}
+
+// AnnounceActions sends the hashes of actions to the neighbors
+func (p *Agent) AnnounceActions(ctx context.Context, hashes []hash.Hash256) error {
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		return errors.New("P2P context doesn't exist")
+	}
+	neighbors, err := p.Neighbors(ctx)
+	if err != nil {
+		return err
+	}
+	data := encodeActionHashes(p2pCtx.ChainID, hashes)
+	var lastErr error
+	for _, peer := range neighbors {
+		err := p.send(ctx, peer, actionAnnounceTopic, data)
+		p2pMsgCounter.WithLabelValues("unicast", actionAnnounceTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+		if err != nil {
+			lastErr = errors.Wrapf(err, "error when announcing actions to %s", peer.ID.Pretty())
+		}
+	}
+	return lastErr
+}
+
+// RequestActions requests a peer for the actions of the hashes, which are sent back as unicast actions
+func (p *Agent) RequestActions(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) (err error) {
+	defer func() {
+		p2pMsgCounter.WithLabelValues("unicast", actionRequestTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+	}()
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		err = errors.New("P2P context doesn't exist")
+		return
+	}
+	if err = p.send(ctx, peer, actionRequestTopic, encodeActionHashes(p2pCtx.ChainID, hashes)); err != nil {
+		err = errors.Wrap(err, "error when requesting actions")
+	}
+	return
+}
+
+// addActionHashPubSubs subscribes the topics of action hashes, the handlers block until ready is closed
+func (p *Agent) addActionHashPubSubs(host *p2p.Host, ready <-chan interface{}) error {
+	if p.actionAnnounceHandler == nil || p.actionRequestHandler == nil {
+		return nil
+	}
+	for topic, handler := range map[string]HandleActionHashesInbound{
+		actionAnnounceTopic: p.actionAnnounceHandler,
+		actionRequestTopic:  p.actionRequestHandler,
+	} {
+		topic, handler := topic, handler
+		if err := host.AddUnicastPubSub(topic+p.topicSuffix, func(ctx context.Context, _ io.Writer, data []byte) (err error) {
+			<-ready
+			stream, ok := p2p.GetUnicastStream(ctx)
+			if !ok {
+				return errors.New("error when asserting unicast stream context")
+			}
+			defer func() {
+				p2pMsgCounter.WithLabelValues("unicast", topic, "in", stream.Conn().RemotePeer().Pretty(), status(err)).Inc()
+			}()
+			peerID := stream.Conn().RemotePeer().Pretty()
+			if err = p.admit(peerID); err != nil {
+				return err
+			}
+			accountBandwidth(topic, "in", len(data), len(data))
+			chainID, hashes, err := decodeActionHashes(data)
+			if err != nil {
+				p.ReportViolation(peerID, ViolationMalformed)
+				return err
+			}
+			handler(ctx, chainID, peerstore.PeerInfo{
+				ID:    stream.Conn().RemotePeer(),
+				Addrs: []multiaddr.Multiaddr{stream.Conn().RemoteMultiaddr()},
+			}, hashes)
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "error when adding %s pubsub", topic)
+		}
+	}
+	return nil
+}
+
+// encodeActionHashes encodes the chain ID in 4 bytes followed by the hashes
+func encodeActionHashes(chainID uint32, hashes []hash.Hash256) []byte {
+	data := make([]byte, 4, 4+len(hashes)*len(hash.ZeroHash256))
+	binary.BigEndian.PutUint32(data, chainID)
+	for _, h := range hashes {
+		data = append(data, h[:]...)
+	}
+	return data
+}
+
+func decodeActionHashes(data []byte) (uint32, []hash.Hash256, error) {
+	if len(data) < 4 || (len(data)-4)%len(hash.ZeroHash256) != 0 {
+		return 0, nil, errors.Errorf("invalid size %d of action hashes", len(data))
+	}
+	chainID := binary.BigEndian.Uint32(data)
+	hashes := make([]hash.Hash256, (len(data)-4)/len(hash.ZeroHash256))
+	for i := range hashes {
+		copy(hashes[i][:], data[4+i*len(hash.ZeroHash256):])
+	}
+	return chainID, hashes, nil
+}
+
+func status(err error) string {
+	if err != nil {
+		return failureStr
+	}
+	return successStr
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestActionHashesEncoding(t *testing.T) {
+	require := require.New(t)
+	hashes := []hash.Hash256{hash.Hash256b([]byte("1")), hash.Hash256b([]byte("2"))}
+	data := encodeActionHashes(4689, hashes)
+	require.Len(data, 4+2*32)
+	chainID, decoded, err := decodeActionHashes(data)
+	require.NoError(err)
+	require.Equal(uint32(4689), chainID)
+	require.Equal(hashes, decoded)
+
+	chainID, decoded, err = decodeActionHashes(encodeActionHashes(1, nil))
+	require.NoError(err)
+	require.Equal(uint32(1), chainID)
+	require.Empty(decoded)
+
+	_, _, err = decodeActionHashes(data[:3])
+	require.Error(err)
+	_, _, err = decodeActionHashes(data[:len(data)-1])
+	require.Error(err)
+}
+
+func TestActionHashExchange(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	var (
+		mutex     sync.Mutex
+		announced []hash.Hash256
+		requested []hash.Hash256
+		announcer peerstore.PeerInfo
+	)
+	b := func(_ context.Context, _ uint32, _ proto.Message) {}
+	u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, _ proto.Message) {}
+	announce := func(_ context.Context, chainID uint32, peer peerstore.PeerInfo, hashes []hash.Hash256) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		require.Equal(uint32(1), chainID)
+		announcer = peer
+		announced = append(announced, hashes...)
+	}
+	request := func(_ context.Context, chainID uint32, _ peerstore.PeerInfo, hashes []hash.Hash256) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		require.Equal(uint32(1), chainID)
+		requested = append(requested, hashes...)
+	}
+	port := testutil.RandomPort()
+	node1 := NewAgent(config.Config{
+		Network: config.Network{Host: "127.0.0.1", Port: port},
+	}, b, u, WithActionHashHandlers(announce, request))
+	require.NoError(node1.Start(ctx))
+	defer func() { require.NoError(node1.Stop(ctx)) }()
+	node2 := NewAgent(config.Config{
+		Network: config.Network{
+			Host:           "127.0.0.1",
+			Port:           testutil.RandomPort(),
+			BootstrapNodes: []string{node1.Self()[0].String()},
+		},
+	}, b, u, WithActionHashHandlers(announce, request))
+	require.NoError(node2.Start(ctx))
+	defer func() { require.NoError(node2.Stop(ctx)) }()
+
+	p2pCtx := WitContext(ctx, Context{ChainID: 1})
+	hashes := []hash.Hash256{hash.Hash256b([]byte("1")), hash.Hash256b([]byte("2"))}
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		if err := node2.AnnounceActions(p2pCtx, hashes); err != nil {
+			return false, nil
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(announced) > 0, nil
+	}))
+	mutex.Lock()
+	require.Equal(hashes, announced[:2])
+	require.Equal(node2.Info().ID, announcer.ID)
+	mutex.Unlock()
+
+	require.NoError(node1.RequestActions(p2pCtx, node2.Info(), hashes[1:]))
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(requested) == 1, nil
+	}))
+	mutex.Lock()
+	require.Equal(hashes[1:], requested)
+	mutex.Unlock()
+}
@@ -14,11 +14,14 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	p2p "github.com/iotexproject/go-p2p"
+	"github.com/iotexproject/go-pkgs/cache"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	multiaddr "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
@@ -27,6 +30,7 @@ import (
 
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
 	goproto "github.com/iotexproject/iotex-proto/golang"
 	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
 )
@@ -83,19 +87,65 @@ type Agent struct {
 	unicastInboundAsyncHandler HandleUnicastInboundAsync
 	host                       *p2p.Host
 	unicastBlocklist           *BlockList
+	actionAnnounceHandler      HandleActionHashesInbound
+	actionRequestHandler       HandleActionHashesInbound
+	headerRequestHandler       HandleHeaderRequestInbound
+	headerResponseHandler      HandleHeadersInbound
+	blockAnnounceHandler       HandleBlockAnnouncementInbound
+	stateRangeRequestHandler   HandleStateRangeRequestInbound
+	stateRangeResponseHandler  HandleStateRangeInbound
+	staticPeers                []multiaddr.Multiaddr
+	staticIDs                  map[string]bool
+	book                       *peerBook
+	peerTask                   *routine.RecurringTask
+	rep                        *reputation
+	plainPeers                 *cache.ThreadSafeLruCache
+	mapping                    portMapping
+	addrTask                   *routine.RecurringTask
+	mutex                      sync.RWMutex
+	externalAddrs              []string
+	validators                 map[validatorKey]Validator
 }
 
 // NewAgent instantiates a local P2P agent instance
-func NewAgent(cfg config.Config, broadcastHandler HandleBroadcastInbound, unicastHandler HandleUnicastInboundAsync) *Agent {
+func NewAgent(cfg config.Config, broadcastHandler HandleBroadcastInbound, unicastHandler HandleUnicastInboundAsync, opts ...Option) *Agent {
 	gh := cfg.Genesis.Hash()
-	return &Agent{
+	agent := &Agent{
 		cfg: cfg.Network,
 		// Make sure the honest node only care the messages related the chain from the same genesis
 		topicSuffix:                hex.EncodeToString(gh[22:]), // last 10 bytes of genesis hash
 		broadcastInboundHandler:    broadcastHandler,
 		unicastInboundAsyncHandler: unicastHandler,
 		unicastBlocklist:           NewBlockList(blockListLen),
+		staticIDs:                  make(map[string]bool),
+		validators:                 make(map[validatorKey]Validator),
+		plainPeers:                 cache.NewThreadSafeLruCache(blockListLen),
+		book:                       newPeerBook(cfg.Network.PeerBookPath, maxBookPeers),
+		rep: newReputation(
+			float64(cfg.Network.BanThreshold),
+			cfg.Network.BanDuration,
+			cfg.Network.MaxBanDuration,
+			cfg.Network.PeerMessageRate,
+		),
 	}
+	for _, s := range cfg.Network.StaticPeers {
+		addr, err := multiaddr.NewMultiaddr(s)
+		if err != nil {
+			log.L().Error("Invalid static peer.", zap.String("address", s), zap.Error(err))
+			continue
+		}
+		info, err := peerstore.InfoFromP2pAddr(addr)
+		if err != nil {
+			log.L().Error("Invalid static peer.", zap.String("address", s), zap.Error(err))
+			continue
+		}
+		agent.staticPeers = append(agent.staticPeers, addr)
+		agent.staticIDs[info.ID.Pretty()] = true
+	}
+	for _, opt := range opts {
+		opt(agent)
+	}
+	return agent
 }
 
 // Start connects into P2P network
@@ -113,19 +163,89 @@ func (p *Agent) Start(ctx context.Context) error {
 	if p.cfg.EnableRateLimit {
 		opts = append(opts, p2p.WithRateLimit(p.cfg.RateLimit))
 	}
-	if p.cfg.ExternalHost != "" {
-		opts = append(opts, p2p.ExternalHostName(p.cfg.ExternalHost))
-		opts = append(opts, p2p.ExternalPort(p.cfg.ExternalPort))
+	externalHost, externalPort := p.cfg.ExternalHost, p.cfg.ExternalPort
+	if p.cfg.EnableNATPortMap {
+		// the configured external address takes precedence over the mapped one, e.g. for a static port forwarding
+		host, port, err := p.mapExternalAddr(ctx)
+		if err != nil {
+			log.L().Warn("Failed to map the port on the NAT gateway.", zap.Error(err))
+		} else {
+			log.L().Info("Mapped the port on the NAT gateway.", zap.String("host", host), zap.Int("port", port))
+			if externalHost == "" {
+				externalHost, externalPort = host, port
+			}
+		}
+	}
+	if externalHost != "" {
+		opts = append(opts, p2p.ExternalHostName(externalHost))
+		opts = append(opts, p2p.ExternalPort(externalPort))
 	}
 	if p.cfg.RelayType != "" {
 		opts = append(opts, p2p.WithRelay(p.cfg.RelayType))
 	}
 	host, err := p2p.NewHost(ctx, opts...)
 	if err != nil {
+		p.closeMapping()
 		return errors.Wrap(err, "error when instantiating Agent host")
 	}
 
-	if err := host.AddBroadcastPubSub(broadcastTopic+p.topicSuffix, func(ctx context.Context, data []byte) (err error) {
+	if err := host.AddBroadcastPubSub(p.topic(broadcastTopic, ""), p.broadcastHandler(ready)); err != nil {
+		return errors.Wrap(err, "error when adding broadcast pubsub")
+	}
+	for _, compression := range append([]string{""}, compressions...) {
+		if err := host.AddUnicastPubSub(p.topic(unicastTopic, compression), p.unicastHandler(compression, ready)); err != nil {
+			return errors.Wrap(err, "error when adding unicast pubsub")
+		}
+	}
+	if err := p.addActionHashPubSubs(host, ready); err != nil {
+		return err
+	}
+	if err := p.addHeaderPubSubs(host, ready); err != nil {
+		return err
+	}
+	if err := p.addBlockAnnouncePubSub(host, ready); err != nil {
+		return err
+	}
+	if err := p.addStateRangePubSubs(host, ready); err != nil {
+		return err
+	}
+
+	// the node doesn't wait on the bootstrap nodes if any static, persisted or DNS seed peer is connected
+	persisted, err := p.book.Load()
+	if err != nil {
+		log.L().Warn("Failed to load the persisted peers.", zap.Error(err))
+	}
+	peers := append(persisted, p.staticPeers...)
+	peers = append(peers, resolveSeeds(ctx, p.cfg.DNSSeeds)...)
+	if p.connectPeers(ctx, host, peers) > 0 {
+		go func() {
+			if err := p.connectBootNodes(ctx, host); err != nil {
+				log.L().Warn("Failed to connect the bootstrap nodes.", zap.Error(err))
+			}
+		}()
+	} else if err := p.connectBootNodes(ctx, host); err != nil {
+		return err
+	}
+	host.JoinOverlay(ctx)
+	p.host = host
+	close(ready)
+	if p.cfg.EnableAddressDiscovery {
+		p.discoverAddresses()
+		p.addrTask = routine.NewRecurringTask(p.discoverAddresses, addressDiscoveryInterval)
+		if err := p.addrTask.Start(ctx); err != nil {
+			return err
+		}
+	}
+	if p.cfg.PeerMaintainInterval > 0 && (len(p.staticPeers) > 0 || p.cfg.PeerBookPath != "") {
+		p.peerTask = routine.NewRecurringTask(func() { p.maintainPeers(ctx) }, p.cfg.PeerMaintainInterval)
+		return p.peerTask.Start(ctx)
+	}
+	return nil
+}
+
+// broadcastHandler returns the handler of the broadcast messages, which blocks until ready is closed
+func (p *Agent) broadcastHandler(ready <-chan interface{}) p2p.HandleBroadcast {
+	return func(ctx context.Context, data []byte) (err error) {
 		// Blocking handling the broadcast message until the agent is started
 		<-ready
 		var (
@@ -146,10 +266,6 @@ func (p *Agent) Start(ctx context.Context) error {
 			p2pMsgCounter.WithLabelValues("broadcast", strconv.Itoa(int(broadcast.MsgType)), "in", peerID, status).Inc()
 			p2pMsgLatency.WithLabelValues("broadcast", strconv.Itoa(int(broadcast.MsgType)), status).Observe(float64(latency))
 		}()
-		if err = proto.Unmarshal(data, &broadcast); err != nil {
-			err = errors.Wrap(err, "error when marshaling broadcast message")
-			return
-		}
 		// Skip the broadcast message if it's from the node itself
 		rawmsg, ok := p2p.GetBroadcastMsg(ctx)
 		if !ok {
@@ -161,6 +277,22 @@ func (p *Agent) Start(ctx context.Context) error {
 			skip = true
 			return
 		}
+		if p.rep.Banned(peerID, time.Now()) {
+			err = errors.Errorf("peer %s is banned", peerID)
+			return
+		}
+		wireSize := len(data)
+		if data, err = decodeBroadcast(data); err != nil {
+			err = errors.Wrap(err, "error when decompressing broadcast message")
+			p.ReportViolation(peerID, ViolationMalformed)
+			return
+		}
+		accountBandwidth(broadcastTopic, "in", wireSize, len(data))
+		if err = proto.Unmarshal(data, &broadcast); err != nil {
+			err = errors.Wrap(err, "error when marshaling broadcast message")
+			p.ReportViolation(peerID, ViolationMalformed)
+			return
+		}
 
 		t, _ := ptypes.Timestamp(broadcast.GetTimestamp())
 		latency = time.Since(t).Nanoseconds() / time.Millisecond.Nanoseconds()
@@ -168,15 +300,22 @@ func (p *Agent) Start(ctx context.Context) error {
 		msg, err := goproto.TypifyRPCMsg(broadcast.MsgType, broadcast.MsgBody)
 		if err != nil {
 			err = errors.Wrap(err, "error when typifying broadcast message")
+			p.ReportViolation(peerID, ViolationMalformed)
+			return
+		}
+		if err = p.validate(ctx, broadcast.ChainId, broadcast.MsgType, msg); err != nil {
+			err = errors.Wrap(err, "error when validating broadcast message")
+			p.ReportViolation(peerID, ViolationInvalidMessage)
 			return
 		}
 		p.broadcastInboundHandler(ctx, broadcast.ChainId, msg)
 		return
-	}); err != nil {
-		return errors.Wrap(err, "error when adding broadcast pubsub")
 	}
+}
 
-	if err := host.AddUnicastPubSub(unicastTopic+p.topicSuffix, func(ctx context.Context, _ io.Writer, data []byte) (err error) {
+// unicastHandler returns the handler of the unicast messages in the compression, which blocks until ready is closed
+func (p *Agent) unicastHandler(compression string, ready <-chan interface{}) p2p.HandleUnicast {
+	return func(ctx context.Context, _ io.Writer, data []byte) (err error) {
 		// Blocking handling the unicast message until the agent is started
 		<-ready
 		var (
@@ -192,96 +331,155 @@ func (p *Agent) Start(ctx context.Context) error {
 			p2pMsgCounter.WithLabelValues("unicast", strconv.Itoa(int(unicast.MsgType)), "in", peerID, status).Inc()
 			p2pMsgLatency.WithLabelValues("unicast", strconv.Itoa(int(unicast.MsgType)), status).Observe(float64(latency))
 		}()
+		stream, ok := p2p.GetUnicastStream(ctx)
+		if !ok {
+			err = errors.New("error when asserting unicast stream context")
+			return
+		}
+		peerID = stream.Conn().RemotePeer().Pretty()
+		if err = p.admit(peerID); err != nil {
+			return
+		}
+		wireSize := len(data)
+		if data, err = decompressPayload(data, compression); err != nil {
+			err = errors.Wrap(err, "error when decompressing unicast message")
+			p.ReportViolation(peerID, ViolationMalformed)
+			return
+		}
+		accountBandwidth(unicastTopic, "in", wireSize, len(data))
 		if err = proto.Unmarshal(data, &unicast); err != nil {
 			err = errors.Wrap(err, "error when marshaling unicast message")
+			p.ReportViolation(peerID, ViolationMalformed)
 			return
 		}
 		msg, err := goproto.TypifyRPCMsg(unicast.MsgType, unicast.MsgBody)
 		if err != nil {
 			err = errors.Wrap(err, "error when typifying unicast message")
+			p.ReportViolation(peerID, ViolationMalformed)
 			return
 		}
 
 		t, _ := ptypes.Timestamp(unicast.GetTimestamp())
 		latency = time.Since(t).Nanoseconds() / time.Millisecond.Nanoseconds()
 
-		stream, ok := p2p.GetUnicastStream(ctx)
-		if !ok {
-			err = errors.Wrap(err, "error when typifying unicast message")
-			return
-		}
-		peerID = stream.Conn().RemotePeer().Pretty()
 		peerInfo := peerstore.PeerInfo{
 			ID:    stream.Conn().RemotePeer(),
 			Addrs: []multiaddr.Multiaddr{stream.Conn().RemoteMultiaddr()},
 		}
 		p.unicastInboundAsyncHandler(ctx, unicast.ChainId, peerInfo, msg)
 		return
-	}); err != nil {
-		return errors.Wrap(err, "error when adding unicast pubsub")
 	}
+}
 
-	if len(p.cfg.BootstrapNodes) > 0 {
-		var tryNum, errNum, connNum, desiredConnNum int
-
-		conn := make(chan interface{}, len(p.cfg.BootstrapNodes))
-		connErrChan := make(chan error, len(p.cfg.BootstrapNodes))
-		desiredConnNum = int(math.RoundToEven(float64(len(p.cfg.BootstrapNodes)) / 2))
-		if float64(desiredConnNum) <= float64(len(p.cfg.BootstrapNodes))/2 {
-			desiredConnNum++
-		}
+func (p *Agent) connectBootNodes(ctx context.Context, host *p2p.Host) error {
+	if len(p.cfg.BootstrapNodes) == 0 {
+		return nil
+	}
+	var tryNum, errNum, connNum, desiredConnNum int
 
-		// try to connect to all bootstrap node beside itself.
-		for _, bootstrapNode := range p.cfg.BootstrapNodes {
-			bootAddr := multiaddr.StringCast(bootstrapNode)
-			if strings.Contains(bootAddr.String(), host.HostIdentity()) {
-				continue
-			}
+	conn := make(chan interface{}, len(p.cfg.BootstrapNodes))
+	connErrChan := make(chan error, len(p.cfg.BootstrapNodes))
+	desiredConnNum = int(math.RoundToEven(float64(len(p.cfg.BootstrapNodes)) / 2))
+	if float64(desiredConnNum) <= float64(len(p.cfg.BootstrapNodes))/2 {
+		desiredConnNum++
+	}
 
-			tryNum++
-			go func() {
-				if err := exponentialRetry(
-					func() error { return host.ConnectWithMultiaddr(ctx, bootAddr) },
-					dialRetryInterval,
-					numDialRetries,
-				); err != nil {
-					err := errors.Wrap(err, fmt.Sprintf("error when connecting bootstrap node %s", bootAddr.String()))
-					connErrChan <- err
-					return
-				}
-				conn <- true
-				log.L().Info("Connected bootstrap node.", zap.String("address", bootAddr.String()))
-			}()
+	// try to connect to all bootstrap node beside itself.
+	for _, bootstrapNode := range p.cfg.BootstrapNodes {
+		bootAddr := multiaddr.StringCast(bootstrapNode)
+		if strings.Contains(bootAddr.String(), host.HostIdentity()) {
+			continue
 		}
-		// wait on bootnodes connection
-		for {
-			select {
-			case err := <-connErrChan:
-				log.L().Info("Connection failed.", zap.Error(err))
-				errNum++
-				if errNum == tryNum {
-					return errors.New("failed to connect to any bootstrap node")
-				}
-			case <-conn:
-				connNum++
+
+		tryNum++
+		go func() {
+			if err := exponentialRetry(
+				func() error { return host.ConnectWithMultiaddr(ctx, bootAddr) },
+				dialRetryInterval,
+				numDialRetries,
+			); err != nil {
+				err := errors.Wrap(err, fmt.Sprintf("error when connecting bootstrap node %s", bootAddr.String()))
+				connErrChan <- err
+				return
 			}
-			// can add more condition later
-			if connNum >= desiredConnNum {
-				break
+			conn <- true
+			log.L().Info("Connected bootstrap node.", zap.String("address", bootAddr.String()))
+		}()
+	}
+	// wait on bootnodes connection
+	for {
+		select {
+		case err := <-connErrChan:
+			log.L().Info("Connection failed.", zap.Error(err))
+			errNum++
+			if errNum == tryNum {
+				return errors.New("failed to connect to any bootstrap node")
 			}
+		case <-conn:
+			connNum++
+		}
+		// can add more condition later
+		if connNum >= desiredConnNum {
+			break
 		}
 	}
-	host.JoinOverlay(ctx)
-	p.host = host
-	close(ready)
 	return nil
 }
 
+// connectPeers dials the peers once, and returns the number of the peers connected
+func (p *Agent) connectPeers(ctx context.Context, host *p2p.Host, addrs []multiaddr.Multiaddr) int {
+	var (
+		wg        sync.WaitGroup
+		connected int32
+	)
+	for _, addr := range addrs {
+		if strings.Contains(addr.String(), host.HostIdentity()) {
+			continue
+		}
+		wg.Add(1)
+		go func(addr multiaddr.Multiaddr) {
+			defer wg.Done()
+			dialCtx, cancel := context.WithTimeout(ctx, dialRetryInterval)
+			defer cancel()
+			if err := host.ConnectWithMultiaddr(dialCtx, addr); err != nil {
+				log.L().Debug("Failed to connect the peer.", zap.String("address", addr.String()), zap.Error(err))
+				return
+			}
+			atomic.AddInt32(&connected, 1)
+		}(addr)
+	}
+	wg.Wait()
+	return int(connected)
+}
+
+// maintainPeers redials the static peers, which the connection manager may have pruned, and persists the known-good
+// peers
+func (p *Agent) maintainPeers(ctx context.Context) {
+	if n := p.connectPeers(ctx, p.host, p.staticPeers); n < len(p.staticPeers) {
+		log.L().Warn("Some static peers are not connected.", zap.Int("connected", n), zap.Int("total", len(p.staticPeers)))
+	}
+	if err := p.book.Save(); err != nil {
+		log.L().Warn("Failed to persist the peers.", zap.Error(err))
+	}
+}
+
 // Stop disconnects from P2P network
 func (p *Agent) Stop(ctx context.Context) error {
 	if p.host == nil {
 		return nil
 	}
+	for _, task := range []*routine.RecurringTask{p.peerTask, p.addrTask} {
+		if task == nil {
+			continue
+		}
+		if err := task.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	p.closeMapping()
+	if err := p.book.Save(); err != nil {
+		log.L().Warn("Failed to persist the peers.", zap.Error(err))
+	}
 	if err := p.host.Close(); err != nil {
 		return errors.Wrap(err, "error when closing Agent host")
 	}
@@ -326,7 +524,7 @@ func (p *Agent) BroadcastOutbound(ctx context.Context, msg proto.Message) (err e
 		err = errors.Wrap(err, "error when marshaling broadcast message")
 		return err
 	}
-	if err = p.host.Broadcast(broadcastTopic+p.topicSuffix, data); err != nil {
+	if err = p.broadcast(broadcastTopic, data); err != nil {
 		err = errors.Wrap(err, "error when sending broadcast message")
 		return err
 	}
@@ -348,10 +546,15 @@ func (p *Agent) UnicastOutbound(ctx context.Context, peer peerstore.PeerInfo, ms
 		p2pMsgCounter.WithLabelValues("unicast", strconv.Itoa(int(msgType)), "out", peer.ID.Pretty(), status).Inc()
 	}()
 
-	if p.unicastBlocklist.Blocked(peerName, time.Now()) {
+	static := p.staticIDs[peerName]
+	if !static && p.unicastBlocklist.Blocked(peerName, time.Now()) {
 		err = errors.New("peer is in blocklist at this moment")
 		return
 	}
+	if p.rep.Banned(peerName, time.Now()) {
+		err = errors.New("peer is banned at this moment")
+		return
+	}
 
 	msgType, msgBody, err = convertAppMsg(msg)
 	if err != nil {
@@ -375,14 +578,18 @@ func (p *Agent) UnicastOutbound(ctx context.Context, peer peerstore.PeerInfo, ms
 		return
 	}
 
-	if err = p.host.Unicast(ctx, peer, unicastTopic+p.topicSuffix, data); err != nil {
+	if err = p.unicast(ctx, peer, unicastTopic, data); err != nil {
 		err = errors.Wrap(err, "error when sending unicast message")
-		p.unicastBlocklist.Add(peerName, time.Now())
+		// static peers are never blocked
+		if !static {
+			p.unicastBlocklist.Add(peerName, time.Now())
+		}
 		return
 	}
 
 	// remove peer from blocklist upon success
 	p.unicastBlocklist.Remove(peerName)
+	p.book.Seen(peer, time.Now())
 	return
 }
 
@@ -401,7 +608,7 @@ func (p *Agent) Neighbors(ctx context.Context) ([]peerstore.PeerInfo, error) {
 	}
 
 	for i, nb := range nbs {
-		if p.unicastBlocklist.Blocked(nb.ID.Pretty(), time.Now()) {
+		if id := nb.ID.Pretty(); p.rep.Banned(id, time.Now()) || (!p.staticIDs[id] && p.unicastBlocklist.Blocked(id, time.Now())) {
 			continue
 		}
 		res = append(res, nbs[i])
@@ -8,7 +8,13 @@ package p2p
 
 import (
 	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -162,3 +168,117 @@ func TestUnicast(t *testing.T) {
 		}))
 	}
 }
+
+func TestStaticAndPersistedPeers(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "agent")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	b := func(_ context.Context, _ uint32, _ proto.Message) {}
+	u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, _ proto.Message) {}
+
+	static := NewAgent(config.Config{
+		Network: config.Network{Host: "127.0.0.1", Port: testutil.RandomPort()},
+	}, b, u)
+	require.NoError(static.Start(ctx))
+	defer func() { require.NoError(static.Stop(ctx)) }()
+
+	// the static peer is connected without any bootstrap node, and never blocked
+	cfg := config.Config{
+		Network: config.Network{
+			Host:                 "127.0.0.1",
+			Port:                 testutil.RandomPort(),
+			StaticPeers:          []string{static.Self()[0].String()},
+			PeerBookPath:         filepath.Join(dir, "peers.json"),
+			PeerMaintainInterval: time.Hour,
+		},
+	}
+	agent := NewAgent(cfg, b, u)
+	require.NoError(agent.Start(ctx))
+	neighbors, err := agent.Neighbors(ctx)
+	require.NoError(err)
+	require.Len(neighbors, 1)
+	require.Equal(static.Info().ID, neighbors[0].ID)
+	for i := 0; i < blockThreshold; i++ {
+		agent.unicastBlocklist.Add(neighbors[0].ID.Pretty(), time.Now())
+	}
+	require.NoError(agent.UnicastOutbound(WitContext(ctx, Context{ChainID: 1}), neighbors[0], &testingpb.TestPayload{}))
+	require.NoError(agent.Stop(ctx))
+
+	// the known-good peer is dialed after the restart
+	cfg.Network.StaticPeers = nil
+	cfg.Network.Port = testutil.RandomPort()
+	agent = NewAgent(cfg, b, u)
+	require.NoError(agent.Start(ctx))
+	defer func() { require.NoError(agent.Stop(ctx)) }()
+	neighbors, err = agent.Neighbors(ctx)
+	require.NoError(err)
+	require.Len(neighbors, 1)
+	require.Equal(static.Info().ID, neighbors[0].ID)
+}
+
+func TestBanPeers(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	b := func(_ context.Context, _ uint32, _ proto.Message) {}
+	u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, _ proto.Message) {}
+	bootnode := NewAgent(config.Config{
+		Network: config.Network{Host: "127.0.0.1", Port: testutil.RandomPort()},
+	}, b, u)
+	require.NoError(bootnode.Start(ctx))
+	defer func() { require.NoError(bootnode.Stop(ctx)) }()
+	agent := NewAgent(config.Config{
+		Network: config.Network{
+			Host:           "127.0.0.1",
+			Port:           testutil.RandomPort(),
+			BootstrapNodes: []string{bootnode.Self()[0].String()},
+			BanDuration:    time.Hour,
+			MaxBanDuration: time.Hour,
+		},
+	}, b, u)
+	require.NoError(agent.Start(ctx))
+	defer func() { require.NoError(agent.Stop(ctx)) }()
+	id := bootnode.Info().ID.Pretty()
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		agent.HandlePeers(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+	listPeers := func() []PeerStatus {
+		w := serve(http.MethodGet, "/peers")
+		require.Equal(http.StatusOK, w.Code)
+		var peers []PeerStatus
+		require.NoError(json.NewDecoder(w.Body).Decode(&peers))
+		return peers
+	}
+	peers := listPeers()
+	require.Len(peers, 1)
+	require.Equal(id, peers[0].ID)
+	require.Nil(peers[0].BannedUntil)
+
+	require.Equal(http.StatusBadRequest, serve(http.MethodPost, "/peers/ban?id=abc").Code)
+	require.Equal(http.StatusBadRequest, serve(http.MethodPost, "/peers/ban?id="+id+"&duration=x").Code)
+	require.Equal(http.StatusBadRequest, serve(http.MethodPost, "/peers/unban").Code)
+	require.Equal(http.StatusMethodNotAllowed, serve(http.MethodGet, "/peers/ban?id="+id).Code)
+	require.Equal(http.StatusNotFound, serve(http.MethodGet, "/peers/x").Code)
+
+	// the banned peer is neither a neighbor nor sent to
+	require.Equal(http.StatusOK, serve(http.MethodPost, "/peers/ban?id="+id).Code)
+	peers = listPeers()
+	require.Len(peers, 1)
+	require.NotNil(peers[0].BannedUntil)
+	require.Equal(1, peers[0].Bans)
+	neighbors, err := agent.Neighbors(ctx)
+	require.NoError(err)
+	require.Empty(neighbors)
+	err = agent.UnicastOutbound(WitContext(ctx, Context{ChainID: 1}), bootnode.Info(), &testingpb.TestPayload{})
+	require.Contains(err.Error(), "peer is banned")
+
+	require.Equal(http.StatusOK, serve(http.MethodPost, "/peers/unban?id="+id).Code)
+	neighbors, err = agent.Neighbors(ctx)
+	require.NoError(err)
+	require.Len(neighbors, 1)
+	require.Nil(listPeers()[0].BannedUntil)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+
+	p2p "github.com/iotexproject/go-p2p"
+	"github.com/iotexproject/go-pkgs/hash"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const blockAnnounceTopic = "blockannounce"
+
+// blockAnnouncementSize is the size of the chain ID, the height and the hash of an announced block
+const blockAnnouncementSize = 4 + 8 + 32
+
+// HandleBlockAnnouncementInbound handles the height and the hash of a block announced by a peer
+type HandleBlockAnnouncementInbound func(context.Context, uint32, peerstore.PeerInfo, uint64, hash.Hash256)
+
+// WithBlockAnnouncementHandler enables the agent to exchange the announcements of new blocks with peers, which are
+// handled by announce
+func WithBlockAnnouncementHandler(announce HandleBlockAnnouncementInbound) Option {
+	return func(p *Agent) {
+		p.blockAnnounceHandler = announce
+	}
+}
+
+// AnnounceBlock sends the height and the hash of a new block to the neighbors, which request the block if they lack it
+func (p *Agent) AnnounceBlock(ctx context.Context, height uint64, h hash.Hash256) error {
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		return errors.New("P2P context doesn't exist")
+	}
+	neighbors, err := p.Neighbors(ctx)
+	if err != nil {
+		return err
+	}
+	data := encodeBlockAnnouncement(p2pCtx.ChainID, height, h)
+	var lastErr error
+	for _, peer := range neighbors {
+		err := p.send(ctx, peer, blockAnnounceTopic, data)
+		p2pMsgCounter.WithLabelValues("unicast", blockAnnounceTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+		if err != nil {
+			lastErr = errors.Wrapf(err, "error when announcing block to %s", peer.ID.Pretty())
+		}
+	}
+	return lastErr
+}
+
+// addBlockAnnouncePubSub subscribes the topic of block announcements, the handler blocks until ready is closed
+func (p *Agent) addBlockAnnouncePubSub(host *p2p.Host, ready <-chan interface{}) error {
+	if p.blockAnnounceHandler == nil {
+		return nil
+	}
+	if err := host.AddUnicastPubSub(blockAnnounceTopic+p.topicSuffix, func(ctx context.Context, _ io.Writer, data []byte) (err error) {
+		<-ready
+		stream, ok := p2p.GetUnicastStream(ctx)
+		if !ok {
+			return errors.New("error when asserting unicast stream context")
+		}
+		defer func() {
+			p2pMsgCounter.WithLabelValues("unicast", blockAnnounceTopic, "in", stream.Conn().RemotePeer().Pretty(), status(err)).Inc()
+		}()
+		peerID := stream.Conn().RemotePeer().Pretty()
+		if err = p.admit(peerID); err != nil {
+			return err
+		}
+		accountBandwidth(blockAnnounceTopic, "in", len(data), len(data))
+		chainID, height, h, err := decodeBlockAnnouncement(data)
+		if err != nil {
+			p.ReportViolation(peerID, ViolationMalformed)
+			return err
+		}
+		p.blockAnnounceHandler(ctx, chainID, peerstore.PeerInfo{
+			ID:    stream.Conn().RemotePeer(),
+			Addrs: []multiaddr.Multiaddr{stream.Conn().RemoteMultiaddr()},
+		}, height, h)
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "error when adding %s pubsub", blockAnnounceTopic)
+	}
+	return nil
+}
+
+// encodeBlockAnnouncement encodes the chain ID in 4 bytes followed by the height in 8 bytes and the hash of the block
+func encodeBlockAnnouncement(chainID uint32, height uint64, h hash.Hash256) []byte {
+	data := make([]byte, blockAnnouncementSize)
+	binary.BigEndian.PutUint32(data, chainID)
+	binary.BigEndian.PutUint64(data[4:], height)
+	copy(data[12:], h[:])
+	return data
+}
+
+func decodeBlockAnnouncement(data []byte) (uint32, uint64, hash.Hash256, error) {
+	if len(data) != blockAnnouncementSize {
+		return 0, 0, hash.ZeroHash256, errors.Errorf("invalid size %d of block announcement", len(data))
+	}
+	return binary.BigEndian.Uint32(data), binary.BigEndian.Uint64(data[4:]), hash.BytesToHash256(data[12:]), nil
+}
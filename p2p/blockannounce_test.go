@@ -0,0 +1,28 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockAnnouncementEncoding(t *testing.T) {
+	require := require.New(t)
+	h := hash.Hash256b([]byte("block"))
+	data := encodeBlockAnnouncement(4689, 100, h)
+	chainID, height, decoded, err := decodeBlockAnnouncement(data)
+	require.NoError(err)
+	require.Equal(uint32(4689), chainID)
+	require.Equal(uint64(100), height)
+	require.Equal(h, decoded)
+
+	_, _, _, err = decodeBlockAnnouncement(data[:len(data)-1])
+	require.Error(err)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/pkg/compress"
+)
+
+const (
+	// maxPayloadSize is the max size of a decompressed message
+	maxPayloadSize = 32 << 20
+	// plainPeerTTL is the time after which the compression is tried again with a peer not supporting it
+	plainPeerTTL = 10 * time.Minute
+	// errProtocolNotSupported is the error of the protocol negotiation when the peer doesn't handle the topic
+	errProtocolNotSupported = "protocol not supported"
+	// compressedMarker leads the compressed broadcast messages, which is never the first byte of a plain message as
+	// the field number 0 is invalid in protobuf
+	compressedMarker = 0x00
+)
+
+var (
+	// compressions are the compressions of the messages supported. Each of them has its own topic of the unicast
+	// messages, which is negotiated per connection, so a peer not supporting the compression is sent the plain
+	// messages. The broadcast messages share one topic, in which the compressed ones are led by the marker and the ID
+	// of the compression.
+	compressions   = []string{compress.Snappy, compress.Zstd}
+	compressionIDs = map[string]byte{
+		compress.Snappy: 1,
+		compress.Zstd:   2,
+	}
+
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxPayloadSize))
+
+	p2pWireBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_p2p_wire_bytes",
+			Help: "Bytes of the p2p messages on the wire",
+		},
+		[]string{"topic", "direction"},
+	)
+	p2pPayloadBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_p2p_payload_bytes",
+			Help: "Bytes of the p2p messages before the compression",
+		},
+		[]string{"topic", "direction"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(p2pWireBytes)
+	prometheus.MustRegister(p2pPayloadBytes)
+}
+
+// topic returns the topic of the messages in the compression
+func (p *Agent) topic(topic, compression string) string {
+	if compression == "" {
+		return topic + p.topicSuffix
+	}
+	return topic + "/" + strings.ToLower(compression) + p.topicSuffix
+}
+
+// unicast sends the data to the peer in the compression of the agent, or plain if the peer doesn't support it
+func (p *Agent) unicast(ctx context.Context, peer peerstore.PeerInfo, topic string, data []byte) error {
+	id := peer.ID.Pretty()
+	if p.cfg.Compression != "" && !p.plainPeer(id) {
+		compressed, err := compress.Compress(data, p.cfg.Compression)
+		if err != nil {
+			return err
+		}
+		err = p.host.Unicast(ctx, peer, p.topic(topic, p.cfg.Compression), compressed)
+		if err == nil {
+			accountBandwidth(topic, "out", len(compressed), len(data))
+			return nil
+		}
+		if !strings.Contains(err.Error(), errProtocolNotSupported) {
+			return err
+		}
+		p.plainPeers.Add(id, time.Now())
+	}
+	return p.send(ctx, peer, topic, data)
+}
+
+// send sends the plain data to the peer
+func (p *Agent) send(ctx context.Context, peer peerstore.PeerInfo, topic string, data []byte) error {
+	if err := p.host.Unicast(ctx, peer, p.topic(topic, ""), data); err != nil {
+		return err
+	}
+	accountBandwidth(topic, "out", len(data), len(data))
+	return nil
+}
+
+// broadcast publishes the data in the compression of the agent
+func (p *Agent) broadcast(topic string, data []byte) error {
+	wire := data
+	if p.cfg.Compression != "" {
+		compressed, err := compress.Compress(data, p.cfg.Compression)
+		if err != nil {
+			return err
+		}
+		wire = append([]byte{compressedMarker, compressionIDs[p.cfg.Compression]}, compressed...)
+	}
+	if err := p.host.Broadcast(p.topic(topic, ""), wire); err != nil {
+		return err
+	}
+	accountBandwidth(topic, "out", len(wire), len(data))
+	return nil
+}
+
+// plainPeer returns true if the peer has recently failed to negotiate the compressed topic
+func (p *Agent) plainPeer(id string) bool {
+	v, ok := p.plainPeers.Get(id)
+	if !ok {
+		return false
+	}
+	if time.Since(v.(time.Time)) < plainPeerTTL {
+		return true
+	}
+	p.plainPeers.Remove(id)
+	return false
+}
+
+// decodeBroadcast returns the plain broadcast message
+func decodeBroadcast(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != compressedMarker {
+		return data, nil
+	}
+	if len(data) < 2 {
+		return nil, errors.New("compression of broadcast message is missing")
+	}
+	for compression, id := range compressionIDs {
+		if id == data[1] {
+			return decompressPayload(data[2:], compression)
+		}
+	}
+	return nil, errors.Errorf("unsupported compression %d of broadcast message", data[1])
+}
+
+func decompressPayload(data []byte, compression string) ([]byte, error) {
+	switch compression {
+	case "":
+		return data, nil
+	case compress.Snappy:
+		size, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if size > maxPayloadSize {
+			return nil, errors.Errorf("decompressed size %d exceeds the limit", size)
+		}
+		return snappy.Decode(nil, data)
+	case compress.Zstd:
+		return zstdDecoder.DecodeAll(data, nil)
+	default:
+		return nil, errors.Errorf("unsupported compression %s", compression)
+	}
+}
+
+func accountBandwidth(topic, direction string, wire, payload int) {
+	p2pWireBytes.WithLabelValues(topic, direction).Add(float64(wire))
+	p2pPayloadBytes.WithLabelValues(topic, direction).Add(float64(payload))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/compress"
+	"github.com/iotexproject/iotex-core/testutil"
+	"github.com/iotexproject/iotex-proto/golang/testingpb"
+)
+
+func TestDecompressPayload(t *testing.T) {
+	require := require.New(t)
+	data := bytes.Repeat([]byte("iotex"), 1000)
+	for _, c := range compressions {
+		compressed, err := compress.Compress(data, c)
+		require.NoError(err)
+		require.True(len(compressed) < len(data))
+		decompressed, err := decompressPayload(compressed, c)
+		require.NoError(err)
+		require.Equal(data, decompressed)
+		_, err = decompressPayload([]byte("not compressed"), c)
+		require.Error(err)
+	}
+	decompressed, err := decompressPayload(data, "")
+	require.NoError(err)
+	require.Equal(data, decompressed)
+	_, err = decompressPayload(data, compress.Gzip)
+	require.Error(err)
+
+	// the compressed broadcast messages are led by the marker and the compression
+	decompressed, err = decodeBroadcast(data)
+	require.NoError(err)
+	require.Equal(data, decompressed)
+	compressed, err := compress.Compress(data, compress.Zstd)
+	require.NoError(err)
+	decompressed, err = decodeBroadcast(append([]byte{compressedMarker, compressionIDs[compress.Zstd]}, compressed...))
+	require.NoError(err)
+	require.Equal(data, decompressed)
+	_, err = decodeBroadcast(append([]byte{compressedMarker, compressionIDs[compress.Snappy]}, compressed...))
+	require.Error(err)
+	_, err = decodeBroadcast([]byte{compressedMarker})
+	require.Error(err)
+	_, err = decodeBroadcast([]byte{compressedMarker, 9})
+	require.Error(err)
+
+	// the size beyond the limit is rejected before decoding
+	_, err = decompressPayload(snappy.Encode(nil, make([]byte, maxPayloadSize+1)), compress.Snappy)
+	require.Contains(err.Error(), "exceeds the limit")
+}
+
+func TestCompressedMessages(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	var (
+		mutex     sync.Mutex
+		unicasts  = make(map[string][]byte)
+		broadcast = make(map[string][]byte)
+	)
+	newAgent := func(name, compression string, bootnodes ...string) *Agent {
+		b := func(_ context.Context, _ uint32, msg proto.Message) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			broadcast[name] = msg.(*testingpb.TestPayload).MsgBody
+		}
+		u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, msg proto.Message) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			unicasts[name] = msg.(*testingpb.TestPayload).MsgBody
+		}
+		agent := NewAgent(config.Config{
+			Network: config.Network{
+				Host:           "127.0.0.1",
+				Port:           testutil.RandomPort(),
+				BootstrapNodes: bootnodes,
+				Compression:    compression,
+			},
+		}, b, u)
+		require.NoError(agent.Start(ctx))
+		return agent
+	}
+	received := func(msgs map[string][]byte, name string, body []byte) func() (bool, error) {
+		return func() (bool, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return bytes.Equal(msgs[name], body), nil
+		}
+	}
+
+	// the node not supporting the compressions only handles the plain topics
+	supported := compressions
+	compressions = nil
+	plain := newAgent("plain", "")
+	compressions = supported
+	defer func() { require.NoError(plain.Stop(ctx)) }()
+	zstd := newAgent("zstd", compress.Zstd, plain.Self()[0].String())
+	defer func() { require.NoError(zstd.Stop(ctx)) }()
+	snappy := newAgent("snappy", compress.Snappy, zstd.Self()[0].String())
+	defer func() { require.NoError(snappy.Stop(ctx)) }()
+	p2pCtx := WitContext(ctx, Context{ChainID: 1})
+
+	// the peer not supporting the compression is sent the plain messages
+	body := bytes.Repeat([]byte{1}, 1000)
+	require.NoError(zstd.UnicastOutbound(p2pCtx, plain.Info(), &testingpb.TestPayload{MsgBody: body}))
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 10*time.Second, received(unicasts, "plain", body)))
+	require.True(zstd.plainPeer(plain.Info().ID.Pretty()))
+
+	// the messages in any compression are received
+	body = bytes.Repeat([]byte{2}, 1000)
+	require.NoError(zstd.UnicastOutbound(p2pCtx, snappy.Info(), &testingpb.TestPayload{MsgBody: body}))
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 10*time.Second, received(unicasts, "snappy", body)))
+	require.NoError(snappy.UnicastOutbound(p2pCtx, zstd.Info(), &testingpb.TestPayload{MsgBody: body}))
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 10*time.Second, received(unicasts, "zstd", body)))
+	require.False(zstd.plainPeer(snappy.Info().ID.Pretty()))
+
+	body = bytes.Repeat([]byte{3}, 1000)
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 20*time.Second, func() (bool, error) {
+		if err := snappy.BroadcastOutbound(p2pCtx, &testingpb.TestPayload{MsgBody: body}); err != nil {
+			return false, err
+		}
+		return received(broadcast, "zstd", body)()
+	}))
+}
@@ -6,9 +6,19 @@
 
 package p2p
 
-import "context"
+import (
+	"context"
 
-type p2pCtxKey struct{}
+	p2p "github.com/iotexproject/go-p2p"
+)
+
+type (
+	p2pCtxKey struct{}
+
+	broadcastPeerCtxKey struct{}
+
+	unicastPeerCtxKey struct{}
+)
 
 // Context provides the auxiliary information Agent network operations
 type Context struct {
@@ -25,3 +35,39 @@ func GetContext(ctx context.Context) (Context, bool) {
 	p2pCtx, ok := ctx.Value(p2pCtxKey{}).(Context)
 	return p2pCtx, ok
 }
+
+// WithBroadcastPeer sets the ID of the peer a broadcast message is received from, for the messages not received by the
+// p2p host, e.g. on a simulated network
+func WithBroadcastPeer(ctx context.Context, peer string) context.Context {
+	return context.WithValue(ctx, broadcastPeerCtxKey{}, peer)
+}
+
+// WithUnicastPeer sets the ID of the peer a unicast message is received from, for the messages not received by the
+// p2p host, e.g. on a simulated network
+func WithUnicastPeer(ctx context.Context, peer string) context.Context {
+	return context.WithValue(ctx, unicastPeerCtxKey{}, peer)
+}
+
+// GetBroadcastPeer gets the ID of the peer a broadcast message is received from
+func GetBroadcastPeer(ctx context.Context) (string, bool) {
+	if peer, ok := ctx.Value(broadcastPeerCtxKey{}).(string); ok {
+		return peer, true
+	}
+	msg, ok := p2p.GetBroadcastMsg(ctx)
+	if !ok || msg == nil {
+		return "", false
+	}
+	return msg.GetFrom().Pretty(), true
+}
+
+// GetUnicastPeer gets the ID of the peer a unicast message is received from
+func GetUnicastPeer(ctx context.Context) (string, bool) {
+	if peer, ok := ctx.Value(unicastPeerCtxKey{}).(string); ok {
+		return peer, true
+	}
+	stream, ok := p2p.GetUnicastStream(ctx)
+	if !ok || stream == nil {
+		return "", false
+	}
+	return stream.Conn().RemotePeer().Pretty(), true
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// A DNS seed is either a domain of TXT records listing the multiaddrs of the peers, or, in the style of EIP-1459, a
+// tree of TXT records signed by the seed operator, given as tree://{public key in hex}@{domain}. The root of the tree
+// is the TXT record of the domain
+//
+//	iotree-root:v1 e={hash} seq={seq} sig={signature}
+//
+// and each node is the TXT record of the subdomain named after its hash, either a branch or a peer
+//
+//	iotree-branch:{hash},{hash},...
+//	iotree-peer:{multiaddr}
+//
+// The hash of a record is the base32 encoded first 16 bytes of its hash, and the signature is over the hash of the
+// root record without the signature.
+const (
+	seedTreeScheme   = "tree://"
+	seedRootPrefix   = "iotree-root:v1"
+	seedBranchPrefix = "iotree-branch:"
+	seedPeerPrefix   = "iotree-peer:"
+	// maxSeedBranch is the max number of the children of a branch, which keeps the record within a TXT string
+	maxSeedBranch = 13
+	// maxSeedPeers is the max number of the peers resolved from a seed
+	maxSeedPeers = 64
+	// maxSeedRecords is the max number of the records resolved from a seed tree
+	maxSeedRecords = 256
+	// seedResolveTimeout is the time to resolve all the seeds
+	seedResolveTimeout = 10 * time.Second
+)
+
+var (
+	seedHashEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	// lookupTXT resolves the TXT records of the domain, it's replaced in tests
+	lookupTXT = net.DefaultResolver.LookupTXT
+)
+
+// BuildSeedTree returns the TXT records of the seed tree of the peers signed by the key, mapping the subdomains to the
+// records, where the empty subdomain is the root
+func BuildSeedTree(peers []string, seq uint64, sk crypto.PrivateKey) (map[string]string, error) {
+	records := make(map[string]string)
+	var hashes []string
+	for _, peer := range peers {
+		if _, err := multiaddr.NewMultiaddr(peer); err != nil {
+			return nil, errors.Wrapf(err, "invalid peer %s", peer)
+		}
+		hashes = append(hashes, addSeedRecord(records, seedPeerPrefix+peer))
+	}
+	for len(hashes) > 1 {
+		var branches []string
+		for i := 0; i < len(hashes); i += maxSeedBranch {
+			end := i + maxSeedBranch
+			if end > len(hashes) {
+				end = len(hashes)
+			}
+			branches = append(branches, addSeedRecord(records, seedBranchPrefix+strings.Join(hashes[i:end], ",")))
+		}
+		hashes = branches
+	}
+	if len(hashes) == 0 {
+		hashes = append(hashes, addSeedRecord(records, seedBranchPrefix))
+	}
+	root := fmt.Sprintf("%s e=%s seq=%d", seedRootPrefix, hashes[0], seq)
+	h := hash.Hash256b([]byte(root))
+	sig, err := sk.Sign(h[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign the seed tree")
+	}
+	records[""] = root + " sig=" + base64.RawURLEncoding.EncodeToString(sig)
+	return records, nil
+}
+
+func addSeedRecord(records map[string]string, record string) string {
+	h := seedRecordHash(record)
+	records[h] = record
+	return h
+}
+
+func seedRecordHash(record string) string {
+	h := hash.Hash256b([]byte(record))
+	return seedHashEncoding.EncodeToString(h[:16])
+}
+
+// resolveSeeds returns the peers of the DNS seeds, the seeds failing to resolve are skipped
+func resolveSeeds(ctx context.Context, seeds []string) []multiaddr.Multiaddr {
+	if len(seeds) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, seedResolveTimeout)
+	defer cancel()
+	var addrs []multiaddr.Multiaddr
+	for _, seed := range seeds {
+		peers, err := resolveSeed(ctx, seed)
+		if err != nil {
+			log.L().Warn("Failed to resolve the DNS seed.", zap.String("seed", seed), zap.Error(err))
+			continue
+		}
+		log.L().Debug("Resolved the DNS seed.", zap.String("seed", seed), zap.Int("peers", len(peers)))
+		addrs = append(addrs, peers...)
+	}
+	return addrs
+}
+
+// resolveSeed returns up to maxSeedPeers random peers of the seed
+func resolveSeed(ctx context.Context, seed string) ([]multiaddr.Multiaddr, error) {
+	var (
+		peers []string
+		err   error
+	)
+	if strings.HasPrefix(seed, seedTreeScheme) {
+		peers, err = resolveSeedTree(ctx, seed)
+	} else {
+		peers, err = lookupTXT(ctx, seed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	var addrs []multiaddr.Multiaddr
+	for _, peer := range peers {
+		addr, err := multiaddr.NewMultiaddr(strings.TrimSpace(peer))
+		if err != nil {
+			log.L().Debug("Invalid peer of the DNS seed.", zap.String("seed", seed), zap.String("peer", peer))
+			continue
+		}
+		if addrs = append(addrs, addr); len(addrs) == maxSeedPeers {
+			break
+		}
+	}
+	return addrs, nil
+}
+
+// parseSeedTree returns the public key and the domain of the seed tree
+func parseSeedTree(seed string) (crypto.PublicKey, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(seed, seedTreeScheme), "@", 2)
+	if !strings.HasPrefix(seed, seedTreeScheme) || len(parts) != 2 || parts[1] == "" {
+		return nil, "", errors.Errorf("invalid seed tree %s", seed)
+	}
+	pk, err := crypto.HexStringToPublicKey(parts[0])
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "invalid public key of seed tree %s", seed)
+	}
+	return pk, parts[1], nil
+}
+
+// resolveSeedTree returns the peers of the seed tree, whose records are verified against the signed root
+func resolveSeedTree(ctx context.Context, seed string) ([]string, error) {
+	pk, domain, err := parseSeedTree(seed)
+	if err != nil {
+		return nil, err
+	}
+	root, err := lookupSeedRecord(ctx, domain, seedRootPrefix)
+	if err != nil {
+		return nil, err
+	}
+	e, err := verifySeedRoot(root, pk)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		peers   []string
+		pending = []string{e}
+		visited = make(map[string]bool)
+	)
+	for len(pending) > 0 && len(peers) < maxSeedPeers {
+		h := pending[0]
+		pending = pending[1:]
+		if visited[h] {
+			continue
+		}
+		if visited[h] = true; len(visited) > maxSeedRecords {
+			break
+		}
+		record, err := lookupSeedRecord(ctx, h+"."+domain, "")
+		if err != nil {
+			return nil, err
+		}
+		if seedRecordHash(record) != h {
+			return nil, errors.Errorf("record %s of seed tree %s doesn't match its hash", h, domain)
+		}
+		switch {
+		case strings.HasPrefix(record, seedBranchPrefix):
+			children := strings.TrimPrefix(record, seedBranchPrefix)
+			if children == "" {
+				break
+			}
+			hashes := strings.Split(children, ",")
+			rand.Shuffle(len(hashes), func(i, j int) { hashes[i], hashes[j] = hashes[j], hashes[i] })
+			pending = append(pending, hashes...)
+		case strings.HasPrefix(record, seedPeerPrefix):
+			peers = append(peers, strings.TrimPrefix(record, seedPeerPrefix))
+		default:
+			return nil, errors.Errorf("unknown record %s of seed tree %s", h, domain)
+		}
+	}
+	return peers, nil
+}
+
+// lookupSeedRecord returns the TXT record of the name with the prefix
+func lookupSeedRecord(ctx context.Context, name, prefix string) (string, error) {
+	records, err := lookupTXT(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	for _, record := range records {
+		if strings.HasPrefix(record, prefix) {
+			return record, nil
+		}
+	}
+	return "", errors.Errorf("no record of %s", name)
+}
+
+// verifySeedRoot verifies the signature of the root record, and returns the hash of the tree
+func verifySeedRoot(root string, pk crypto.PublicKey) (string, error) {
+	idx := strings.LastIndex(root, " sig=")
+	if idx < 0 {
+		return "", errors.Errorf("seed root %s is not signed", root)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(root[idx+len(" sig="):])
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid signature of seed root %s", root)
+	}
+	h := hash.Hash256b([]byte(root[:idx]))
+	if !pk.Verify(h[:], sig) {
+		return "", errors.Errorf("signature of seed root %s is not valid", root)
+	}
+	var e string
+	for _, field := range strings.Fields(root[len(seedRootPrefix):idx]) {
+		switch kv := strings.SplitN(field, "=", 2); {
+		case len(kv) != 2:
+		case kv[0] == "e":
+			e = kv[1]
+		case kv[0] == "seq":
+			if _, err := strconv.ParseUint(kv[1], 10, 64); err != nil {
+				return "", errors.Wrapf(err, "invalid sequence of seed root %s", root)
+			}
+		}
+	}
+	if e == "" {
+		return "", errors.Errorf("seed root %s has no tree", root)
+	}
+	return e, nil
+}
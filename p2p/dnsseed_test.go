@@ -0,0 +1,128 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/crypto"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// fakeLookupTXT serves the TXT records of the domains
+func fakeLookupTXT(zones map[string]map[string]string) func(context.Context, string) ([]string, error) {
+	return func(_ context.Context, name string) ([]string, error) {
+		for domain, records := range zones {
+			if !strings.HasSuffix(name, domain) {
+				continue
+			}
+			if record, ok := records[strings.TrimSuffix(strings.TrimSuffix(name, domain), ".")]; ok {
+				return []string{record}, nil
+			}
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+}
+
+func TestSeedTree(t *testing.T) {
+	require := require.New(t)
+	defer func() { lookupTXT = net.DefaultResolver.LookupTXT }()
+	sk, err := crypto.GenerateKey()
+	require.NoError(err)
+	var peers []string
+	for i := 0; i < 30; i++ {
+		peers = append(peers, fmt.Sprintf("/ip4/1.2.3.%d/tcp/4689", i))
+	}
+	records, err := BuildSeedTree(peers, 1, sk)
+	require.NoError(err)
+	// 30 peers, 3 branches of them and the branch of the branches, plus the root
+	require.Len(records, 35)
+	for _, record := range records {
+		require.True(len(record) < 400)
+	}
+	seed := "tree://" + sk.PublicKey().HexString() + "@seed.iotex.io"
+	lookupTXT = fakeLookupTXT(map[string]map[string]string{"seed.iotex.io": records})
+	addrs, err := resolveSeed(context.Background(), seed)
+	require.NoError(err)
+	var resolved []string
+	for _, addr := range addrs {
+		resolved = append(resolved, addr.String())
+	}
+	require.ElementsMatch(peers, resolved)
+
+	// the tree signed by another key is rejected
+	other, err := crypto.GenerateKey()
+	require.NoError(err)
+	_, err = resolveSeed(context.Background(), "tree://"+other.PublicKey().HexString()+"@seed.iotex.io")
+	require.Contains(err.Error(), "signature of seed root")
+	// the tampered records are rejected
+	for sub, record := range records {
+		if strings.HasPrefix(record, seedPeerPrefix) {
+			records[sub] = seedPeerPrefix + "/ip4/6.6.6.6/tcp/4689"
+			break
+		}
+	}
+	_, err = resolveSeed(context.Background(), seed)
+	require.Contains(err.Error(), "doesn't match its hash")
+	_, err = resolveSeed(context.Background(), "tree://"+sk.PublicKey().HexString()+"@unknown.iotex.io")
+	require.Error(err)
+	_, _, err = parseSeedTree("tree://seed.iotex.io")
+	require.Error(err)
+
+	// an empty tree
+	records, err = BuildSeedTree(nil, 2, sk)
+	require.NoError(err)
+	lookupTXT = fakeLookupTXT(map[string]map[string]string{"seed.iotex.io": records})
+	addrs, err = resolveSeed(context.Background(), seed)
+	require.NoError(err)
+	require.Empty(addrs)
+	_, err = BuildSeedTree([]string{"1.2.3.4:4689"}, 1, sk)
+	require.Error(err)
+}
+
+func TestDNSSeeds(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	defer func() { lookupTXT = net.DefaultResolver.LookupTXT }()
+	b := func(_ context.Context, _ uint32, _ proto.Message) {}
+	u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, _ proto.Message) {}
+	seednode := NewAgent(config.Config{
+		Network: config.Network{Host: "127.0.0.1", Port: testutil.RandomPort()},
+	}, b, u)
+	require.NoError(seednode.Start(ctx))
+	defer func() { require.NoError(seednode.Stop(ctx)) }()
+
+	lookupTXT = func(_ context.Context, name string) ([]string, error) {
+		if name != "seed.iotex.io" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"invalid", seednode.Self()[0].String()}, nil
+	}
+	agent := NewAgent(config.Config{
+		Network: config.Network{
+			Host:     "127.0.0.1",
+			Port:     testutil.RandomPort(),
+			DNSSeeds: []string{"unknown.iotex.io", "seed.iotex.io"},
+		},
+	}, b, u)
+	require.NoError(agent.Start(ctx))
+	defer func() { require.NoError(agent.Stop(ctx)) }()
+	nbs, err := agent.Neighbors(ctx)
+	require.NoError(err)
+	require.Len(nbs, 1)
+	require.Equal(seednode.Info().ID, nbs[0].ID)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	p2p "github.com/iotexproject/go-p2p"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const (
+	headerRequestTopic  = "headerrequest"
+	headerResponseTopic = "headerresponse"
+)
+
+type (
+	// HandleHeaderRequestInbound handles the request of a peer for the headers from start to end
+	HandleHeaderRequestInbound func(context.Context, uint32, peerstore.PeerInfo, uint64, uint64)
+
+	// HandleHeadersInbound handles the headers sent by a peer
+	HandleHeadersInbound func(context.Context, uint32, peerstore.PeerInfo, []*iotextypes.BlockHeader)
+)
+
+// WithHeaderHandlers enables the agent to exchange the block headers with peers. The requests for headers are handled
+// by request, and the headers sent back by response.
+func WithHeaderHandlers(request HandleHeaderRequestInbound, response HandleHeadersInbound) Option {
+	return func(p *Agent) {
+		p.headerRequestHandler = request
+		p.headerResponseHandler = response
+	}
+}
+
+// RequestHeaders requests a peer for the headers from start to end, which are sent back by SendHeaders
+func (p *Agent) RequestHeaders(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) (err error) {
+	defer func() {
+		p2pMsgCounter.WithLabelValues("unicast", headerRequestTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+	}()
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		err = errors.New("P2P context doesn't exist")
+		return
+	}
+	if err = p.send(ctx, peer, headerRequestTopic, encodeHeaderRequest(p2pCtx.ChainID, start, end)); err != nil {
+		err = errors.Wrap(err, "error when requesting headers")
+	}
+	return
+}
+
+// SendHeaders sends the headers to the peer requesting them
+func (p *Agent) SendHeaders(ctx context.Context, peer peerstore.PeerInfo, headers []*iotextypes.BlockHeader) (err error) {
+	defer func() {
+		p2pMsgCounter.WithLabelValues("unicast", headerResponseTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+	}()
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		err = errors.New("P2P context doesn't exist")
+		return
+	}
+	data, err := encodeHeaders(p2pCtx.ChainID, headers)
+	if err != nil {
+		return
+	}
+	if err = p.send(ctx, peer, headerResponseTopic, data); err != nil {
+		err = errors.Wrap(err, "error when sending headers")
+	}
+	return
+}
+
+// addHeaderPubSubs subscribes the topics of headers, the handlers block until ready is closed
+func (p *Agent) addHeaderPubSubs(host *p2p.Host, ready <-chan interface{}) error {
+	if p.headerRequestHandler == nil || p.headerResponseHandler == nil {
+		return nil
+	}
+	for topic, handler := range map[string]func(context.Context, peerstore.PeerInfo, []byte) error{
+		headerRequestTopic: func(ctx context.Context, peer peerstore.PeerInfo, data []byte) error {
+			chainID, start, end, err := decodeHeaderRequest(data)
+			if err != nil {
+				return err
+			}
+			p.headerRequestHandler(ctx, chainID, peer, start, end)
+			return nil
+		},
+		headerResponseTopic: func(ctx context.Context, peer peerstore.PeerInfo, data []byte) error {
+			chainID, headers, err := decodeHeaders(data)
+			if err != nil {
+				return err
+			}
+			p.headerResponseHandler(ctx, chainID, peer, headers)
+			return nil
+		},
+	} {
+		topic, handler := topic, handler
+		if err := host.AddUnicastPubSub(topic+p.topicSuffix, func(ctx context.Context, _ io.Writer, data []byte) (err error) {
+			<-ready
+			stream, ok := p2p.GetUnicastStream(ctx)
+			if !ok {
+				return errors.New("error when asserting unicast stream context")
+			}
+			defer func() {
+				p2pMsgCounter.WithLabelValues("unicast", topic, "in", stream.Conn().RemotePeer().Pretty(), status(err)).Inc()
+			}()
+			peerID := stream.Conn().RemotePeer().Pretty()
+			if err = p.admit(peerID); err != nil {
+				return err
+			}
+			accountBandwidth(topic, "in", len(data), len(data))
+			// the handlers fail only if the data can't be decoded
+			if err = handler(ctx, peerstore.PeerInfo{
+				ID:    stream.Conn().RemotePeer(),
+				Addrs: []multiaddr.Multiaddr{stream.Conn().RemoteMultiaddr()},
+			}, data); err != nil {
+				p.ReportViolation(peerID, ViolationMalformed)
+			}
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "error when adding %s pubsub", topic)
+		}
+	}
+	return nil
+}
+
+// encodeHeaderRequest encodes the chain ID in 4 bytes followed by the start and the end heights in 8 bytes each
+func encodeHeaderRequest(chainID uint32, start, end uint64) []byte {
+	data := make([]byte, 20)
+	binary.BigEndian.PutUint32(data, chainID)
+	binary.BigEndian.PutUint64(data[4:], start)
+	binary.BigEndian.PutUint64(data[12:], end)
+	return data
+}
+
+func decodeHeaderRequest(data []byte) (uint32, uint64, uint64, error) {
+	if len(data) != 20 {
+		return 0, 0, 0, errors.Errorf("invalid size %d of header request", len(data))
+	}
+	return binary.BigEndian.Uint32(data), binary.BigEndian.Uint64(data[4:]), binary.BigEndian.Uint64(data[12:]), nil
+}
+
+// encodeHeaders encodes the chain ID in 4 bytes followed by the headers, each of which is prefixed by its size in 4
+// bytes
+func encodeHeaders(chainID uint32, headers []*iotextypes.BlockHeader) ([]byte, error) {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, chainID)
+	for _, header := range headers {
+		b, err := proto.Marshal(header)
+		if err != nil {
+			return nil, errors.Wrap(err, "error when marshaling header")
+		}
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(len(b)))
+		data = append(append(data, size...), b...)
+	}
+	return data, nil
+}
+
+func decodeHeaders(data []byte) (uint32, []*iotextypes.BlockHeader, error) {
+	if len(data) < 4 {
+		return 0, nil, errors.Errorf("invalid size %d of headers", len(data))
+	}
+	chainID := binary.BigEndian.Uint32(data)
+	var headers []*iotextypes.BlockHeader
+	for data = data[4:]; len(data) > 0; {
+		if len(data) < 4 {
+			return 0, nil, errors.New("invalid size prefix of header")
+		}
+		size := binary.BigEndian.Uint32(data)
+		if uint64(len(data)-4) < uint64(size) {
+			return 0, nil, errors.Errorf("invalid size %d of header", size)
+		}
+		header := &iotextypes.BlockHeader{}
+		if err := proto.Unmarshal(data[4:4+size], header); err != nil {
+			return 0, nil, errors.Wrap(err, "error when unmarshaling header")
+		}
+		headers = append(headers, header)
+		data = data[4+size:]
+	}
+	return chainID, headers, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderRequestEncoding(t *testing.T) {
+	require := require.New(t)
+	data := encodeHeaderRequest(4689, 10, 509)
+	chainID, start, end, err := decodeHeaderRequest(data)
+	require.NoError(err)
+	require.Equal(uint32(4689), chainID)
+	require.Equal(uint64(10), start)
+	require.Equal(uint64(509), end)
+
+	_, _, _, err = decodeHeaderRequest(data[:19])
+	require.Error(err)
+}
+
+func TestHeadersEncoding(t *testing.T) {
+	require := require.New(t)
+	headers := []*iotextypes.BlockHeader{
+		{Core: &iotextypes.BlockHeaderCore{Version: 1, Height: 1}, ProducerPubkey: []byte("a")},
+		{Core: &iotextypes.BlockHeaderCore{Version: 1, Height: 2}, ProducerPubkey: []byte("b")},
+	}
+	data, err := encodeHeaders(4689, headers)
+	require.NoError(err)
+	chainID, decoded, err := decodeHeaders(data)
+	require.NoError(err)
+	require.Equal(uint32(4689), chainID)
+	require.Len(decoded, 2)
+	for i := range headers {
+		require.True(proto.Equal(headers[i], decoded[i]))
+	}
+
+	data, err = encodeHeaders(1, nil)
+	require.NoError(err)
+	chainID, decoded, err = decodeHeaders(data)
+	require.NoError(err)
+	require.Equal(uint32(1), chainID)
+	require.Empty(decoded)
+
+	_, _, err = decodeHeaders(data[:3])
+	require.Error(err)
+	data, err = encodeHeaders(1, headers)
+	require.NoError(err)
+	_, _, err = decodeHeaders(data[:len(data)-1])
+	require.Error(err)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+
+	nat "github.com/libp2p/go-libp2p-nat"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	// natDiscoveryTimeout is the time to wait on the UPnP or NAT-PMP gateway of the network
+	natDiscoveryTimeout = 10 * time.Second
+	// addressDiscoveryInterval is the interval to check the addresses of the node observed by the peers
+	addressDiscoveryInterval = time.Minute
+)
+
+type (
+	// portMapping is a port mapped on the NAT device, which is renewed until closed
+	portMapping interface {
+		ExternalAddr() (net.Addr, error)
+		Close() error
+	}
+
+	natMapping struct {
+		nat.Mapping
+		nat *nat.NAT
+	}
+)
+
+// mapPort maps the tcp port on the NAT gateway, it's replaced in tests
+var mapPort = defaultMapPort
+
+// defaultMapPort maps the tcp port on the UPnP or NAT-PMP gateway of the network
+func defaultMapPort(ctx context.Context, port int) (portMapping, error) {
+	ctx, cancel := context.WithTimeout(ctx, natDiscoveryTimeout)
+	defer cancel()
+	n, err := nat.DiscoverNAT(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to discover the NAT gateway")
+	}
+	m, err := n.NewMapping("tcp", port)
+	if err != nil {
+		n.Close()
+		return nil, errors.Wrapf(err, "failed to map port %d", port)
+	}
+	return &natMapping{Mapping: m, nat: n}, nil
+}
+
+func (m *natMapping) Close() error {
+	if err := m.Mapping.Close(); err != nil {
+		return err
+	}
+	return m.nat.Close()
+}
+
+// mapExternalAddr maps the listening port on the NAT gateway, and returns the public address to advertise
+func (p *Agent) mapExternalAddr(ctx context.Context) (string, int, error) {
+	m, err := mapPort(ctx, p.cfg.Port)
+	if err != nil {
+		return "", 0, err
+	}
+	addr, err := m.ExternalAddr()
+	if err != nil {
+		m.Close()
+		return "", 0, errors.Wrap(err, "failed to get the external address of the port mapping")
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || tcpAddr.IP.To4() == nil || !isPublicIP(tcpAddr.IP) {
+		m.Close()
+		return "", 0, errors.Errorf("external address %s of the port mapping is not a public ipv4 address", addr)
+	}
+	p.mapping = m
+	return tcpAddr.IP.String(), tcpAddr.Port, nil
+}
+
+// ExternalAddresses returns the public addresses of the node, either mapped on the NAT gateway, configured, or
+// observed by the peers
+func (p *Agent) ExternalAddresses() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return append([]string{}, p.externalAddrs...)
+}
+
+// discoverAddresses records the public addresses of the node advertised by the host, which include the addresses
+// observed by enough peers
+func (p *Agent) discoverAddresses() {
+	var addrs []string
+	for _, addr := range publicAddrs(p.host.Info().Addrs) {
+		addrs = append(addrs, addr.String())
+	}
+	sort.Strings(addrs)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	known := make(map[string]bool, len(p.externalAddrs))
+	for _, addr := range p.externalAddrs {
+		known[addr] = true
+	}
+	for _, addr := range addrs {
+		if !known[addr] {
+			log.L().Info("Discovered external address.", zap.String("address", addr))
+		}
+	}
+	p.externalAddrs = addrs
+}
+
+// publicAddrs returns the addresses of public ipv4 or ipv6 hosts
+func publicAddrs(addrs []multiaddr.Multiaddr) []multiaddr.Multiaddr {
+	var ret []multiaddr.Multiaddr
+	for _, addr := range addrs {
+		s, err := addr.ValueForProtocol(multiaddr.P_IP4)
+		if err != nil {
+			if s, err = addr.ValueForProtocol(multiaddr.P_IP6); err != nil {
+				continue
+			}
+		}
+		if ip := net.ParseIP(s); ip != nil && isPublicIP(ip) {
+			ret = append(ret, addr)
+		}
+	}
+	return ret
+}
+
+var privateNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"fc00::/7",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// isPublicIP returns true if the ip is a global unicast address outside of the private and shared address spaces
+func isPublicIP(ip net.IP) bool {
+	if !ip.IsGlobalUnicast() {
+		return false
+	}
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Agent) closeMapping() {
+	if p.mapping == nil {
+		return
+	}
+	if err := p.mapping.Close(); err != nil {
+		log.L().Warn("Failed to remove the port mapping.", zap.Error(err))
+	}
+	p.mapping = nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type fakeMapping struct {
+	addr   net.Addr
+	closed bool
+}
+
+func (m *fakeMapping) ExternalAddr() (net.Addr, error) { return m.addr, nil }
+
+func (m *fakeMapping) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestPublicAddrs(t *testing.T) {
+	require := require.New(t)
+	for ip, public := range map[string]bool{
+		"1.2.3.4":     true,
+		"127.0.0.1":   false,
+		"10.1.2.3":    false,
+		"172.20.0.1":  false,
+		"192.168.1.1": false,
+		"100.64.0.1":  false,
+		"0.0.0.0":     false,
+		"2001:db8::1": true,
+		"fd00::1":     false,
+		"::1":         false,
+	} {
+		require.Equal(public, isPublicIP(net.ParseIP(ip)), ip)
+	}
+	addrs := []multiaddr.Multiaddr{
+		multiaddr.StringCast("/ip4/127.0.0.1/tcp/4689"),
+		multiaddr.StringCast("/ip4/1.2.3.4/tcp/4689"),
+		multiaddr.StringCast("/ip6/2001:db8::1/tcp/4689"),
+		multiaddr.StringCast("/dns4/example.com/tcp/4689"),
+	}
+	require.Equal(addrs[1:3], publicAddrs(addrs))
+}
+
+func TestNATPortMap(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	b := func(_ context.Context, _ uint32, _ proto.Message) {}
+	u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, _ proto.Message) {}
+	port := testutil.RandomPort()
+	mapping := &fakeMapping{addr: &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 14689}}
+	mapPort = func(_ context.Context, p int) (portMapping, error) {
+		require.Equal(port, p)
+		return mapping, nil
+	}
+	defer func() { mapPort = defaultMapPort }()
+
+	agent := NewAgent(config.Config{
+		Network: config.Network{
+			Host:                   "127.0.0.1",
+			Port:                   port,
+			EnableNATPortMap:       true,
+			EnableAddressDiscovery: true,
+		},
+	}, b, u)
+	require.NoError(agent.Start(ctx))
+	require.Equal([]string{"/ip4/1.2.3.4/tcp/14689"}, agent.ExternalAddresses())
+
+	w := httptest.NewRecorder()
+	agent.HandlePeers(w, httptest.NewRequest(http.MethodGet, "/peers/self", nil))
+	require.Equal(http.StatusOK, w.Code)
+	var self SelfStatus
+	require.NoError(json.NewDecoder(w.Body).Decode(&self))
+	require.Equal(agent.Info().ID.Pretty(), self.ID)
+	require.Len(self.Addrs, 2)
+	require.Equal([]string{"/ip4/1.2.3.4/tcp/14689"}, self.External)
+	require.NoError(agent.Stop(ctx))
+	require.True(mapping.closed)
+
+	// the node starts without the mapping if the gateway isn't found, and the private mapped address is not advertised
+	for _, mapPortErr := range []error{errors.New("no gateway"), nil} {
+		mapping = &fakeMapping{addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.1"), Port: 14689}}
+		mapPort = func(_ context.Context, _ int) (portMapping, error) {
+			if mapPortErr != nil {
+				return nil, mapPortErr
+			}
+			return mapping, nil
+		}
+		agent = NewAgent(config.Config{
+			Network: config.Network{
+				Host:                   "127.0.0.1",
+				Port:                   testutil.RandomPort(),
+				EnableNATPortMap:       true,
+				EnableAddressDiscovery: true,
+			},
+		}, b, u)
+		require.NoError(agent.Start(ctx))
+		require.Empty(agent.ExternalAddresses())
+		require.Len(agent.Info().Addrs, 1)
+		require.NoError(agent.Stop(ctx))
+		require.Equal(mapPortErr == nil, mapping.closed)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// maxBookPeers is the max number of peers kept in the peer book
+const maxBookPeers = 64
+
+type (
+	// peerBook keeps the known-good peers, which the node has sent messages to, and persists them into a file so that
+	// they are dialed again after restarts
+	peerBook struct {
+		mutex sync.Mutex
+		path  string
+		size  int
+		peers map[string]*bookPeer
+	}
+
+	bookPeer struct {
+		Addrs []string `json:"addrs"`
+		Seen  int64    `json:"seen"`
+	}
+)
+
+func newPeerBook(path string, size int) *peerBook {
+	return &peerBook{
+		path:  path,
+		size:  size,
+		peers: make(map[string]*bookPeer),
+	}
+}
+
+// Load reads the peers persisted in the file, and returns their addresses
+func (b *peerBook) Load() ([]multiaddr.Multiaddr, error) {
+	if b.path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read peer book %s", b.path)
+	}
+	var peers []*bookPeer
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode peer book %s", b.path)
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var addrs []multiaddr.Multiaddr
+	for _, p := range peers {
+		var id string
+		for _, s := range p.Addrs {
+			addr, err := multiaddr.NewMultiaddr(s)
+			if err != nil {
+				continue
+			}
+			info, err := peerstore.InfoFromP2pAddr(addr)
+			if err != nil {
+				continue
+			}
+			id = info.ID.Pretty()
+			addrs = append(addrs, addr)
+		}
+		if id == "" {
+			continue
+		}
+		if _, ok := b.peers[id]; !ok {
+			b.peers[id] = p
+		}
+	}
+	return addrs, nil
+}
+
+// Seen records the peer as good at the time
+func (b *peerBook) Seen(info peerstore.PeerInfo, t time.Time) {
+	addrs, err := peerstore.InfoToP2pAddrs(&info)
+	if err != nil || len(addrs) == 0 {
+		return
+	}
+	p := &bookPeer{Seen: t.Unix()}
+	for _, addr := range addrs {
+		p.Addrs = append(p.Addrs, addr.String())
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.peers[info.ID.Pretty()] = p
+}
+
+// Save persists the most recently seen peers into the file
+func (b *peerBook) Save() error {
+	if b.path == "" {
+		return nil
+	}
+	b.mutex.Lock()
+	peers := make([]*bookPeer, 0, len(b.peers))
+	for _, p := range b.peers {
+		peers = append(peers, p)
+	}
+	b.mutex.Unlock()
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Seen > peers[j].Seen
+	})
+	if len(peers) > b.size {
+		peers = peers[:b.size]
+	}
+	data, err := json.Marshal(peers)
+	if err != nil {
+		return err
+	}
+	tmp := b.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write peer book %s", b.path)
+	}
+	return os.Rename(tmp, b.path)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerBook(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "peerbook")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "peers.json")
+
+	ids := []string{
+		"QmSrEo6NEhD2yrhLXwPMfTY4EGQGHqmMHzpc1oRh8Cy9U9",
+		"QmYJ8mXyAhcQzRBeSkA5BZmHWiAYsgmmyQYwkP1oxMVZZ6",
+		"QmaKXNGE9q7VWx8XGFGLMM3UoT4D2oifMhEAqSUi3K6BsB",
+	}
+	infos := make([]peerstore.PeerInfo, len(ids))
+	for i, s := range ids {
+		info, err := peerstore.InfoFromP2pAddr(multiaddr.StringCast("/ip4/127.0.0.1/tcp/468" + strconv.Itoa(i) + "/ipfs/" + s))
+		require.NoError(err)
+		infos[i] = *info
+	}
+
+	// nothing is persisted without the path or the file
+	book := newPeerBook("", 2)
+	book.Seen(infos[0], time.Now())
+	require.NoError(book.Save())
+	book = newPeerBook(path, 2)
+	addrs, err := book.Load()
+	require.NoError(err)
+	require.Empty(addrs)
+
+	now := time.Now()
+	book.Seen(infos[0], now.Add(-time.Minute))
+	book.Seen(infos[1], now)
+	book.Seen(infos[2], now.Add(-time.Hour))
+	book.Seen(peerstore.PeerInfo{ID: infos[2].ID}, now)
+	require.NoError(book.Save())
+
+	// the most recently seen peers are persisted
+	addrs, err = newPeerBook(path, 2).Load()
+	require.NoError(err)
+	require.Len(addrs, 2)
+	require.Equal("/ip4/127.0.0.1/tcp/4681/p2p/"+ids[1], addrs[0].String())
+	require.Equal("/ip4/127.0.0.1/tcp/4680/p2p/"+ids[0], addrs[1].String())
+
+	require.NoError(ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = newPeerBook(path, 2).Load()
+	require.Error(err)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// ReportViolation records a protocol violation of the peer, which is banned once its violations score high enough.
+// The static peers are never banned automatically.
+func (p *Agent) ReportViolation(peerID string, v Violation) {
+	if until := p.rep.Report(peerID, v, p.staticIDs[peerID], time.Now()); !until.IsZero() {
+		log.L().Warn("Peer is banned.", zap.String("peer", peerID), zap.Stringer("violation", v), zap.Time("until", until))
+	}
+}
+
+// BanPeer bans the peer for the duration, or for the time escalating with its bans if the duration is 0, and returns
+// the time until which the peer is banned
+func (p *Agent) BanPeer(peerID string, d time.Duration) (time.Time, error) {
+	if _, err := multiaddr.NewMultiaddr("/ipfs/" + peerID); err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid peer id %s", peerID)
+	}
+	until := p.rep.Ban(peerID, d, time.Now())
+	log.L().Info("Peer is banned by the operator.", zap.String("peer", peerID), zap.Time("until", until))
+	return until, nil
+}
+
+// UnbanPeer lifts the ban of the peer
+func (p *Agent) UnbanPeer(peerID string) {
+	p.rep.Unban(peerID)
+	log.L().Info("Peer is unbanned by the operator.", zap.String("peer", peerID))
+}
+
+// ListPeers returns the reputation of the known peers, including the banned ones
+func (p *Agent) ListPeers(ctx context.Context) ([]PeerStatus, error) {
+	nbs, err := p.host.Neighbors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	peers := make(map[string]PeerStatus, len(nbs))
+	for _, nb := range nbs {
+		status := p.rep.Status(nb.ID.Pretty(), now)
+		for _, addr := range nb.Addrs {
+			status.Addrs = append(status.Addrs, addr.String())
+		}
+		peers[status.ID] = status
+	}
+	for _, id := range p.rep.BannedPeers(now) {
+		if _, ok := peers[id]; !ok {
+			peers[id] = p.rep.Status(id, now)
+		}
+	}
+	ret := make([]PeerStatus, 0, len(peers))
+	for id, status := range peers {
+		status.Static = p.staticIDs[id]
+		ret = append(ret, status)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+// SelfStatus is the addresses of the node itself
+type SelfStatus struct {
+	ID       string   `json:"id"`
+	Addrs    []string `json:"addrs"`
+	External []string `json:"external,omitempty"`
+}
+
+// HandlePeers handles the admin requests of the peers. GET /peers lists the peers, GET /peers/self shows the addresses
+// of the node, POST /peers/ban?id={id}&duration=
+// {duration} bans a peer, where the duration is optional, and POST /peers/unban?id={id} lifts the ban of a peer.
+func (p *Agent) HandlePeers(w http.ResponseWriter, r *http.Request) {
+	switch path := strings.TrimSuffix(r.URL.Path, "/"); {
+	case path == "/peers" && r.Method == http.MethodGet:
+		peers, err := p.ListPeers(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writePeersResponse(w, peers)
+	case path == "/peers/self" && r.Method == http.MethodGet:
+		self := SelfStatus{ID: p.host.HostIdentity(), External: p.ExternalAddresses()}
+		for _, addr := range p.host.Addresses() {
+			self.Addrs = append(self.Addrs, addr.String())
+		}
+		writePeersResponse(w, self)
+	case path == "/peers/ban" && r.Method == http.MethodPost:
+		var d time.Duration
+		if s := r.URL.Query().Get("duration"); s != "" {
+			var err error
+			if d, err = time.ParseDuration(s); err != nil || d < 0 {
+				http.Error(w, "invalid duration "+s, http.StatusBadRequest)
+				return
+			}
+		}
+		id := r.URL.Query().Get("id")
+		if _, err := p.BanPeer(id, d); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writePeersResponse(w, p.rep.Status(id, time.Now()))
+	case path == "/peers/unban" && r.Method == http.MethodPost:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(w, "peer id is missing", http.StatusBadRequest)
+			return
+		}
+		p.UnbanPeer(id)
+		writePeersResponse(w, p.rep.Status(id, time.Now()))
+	case path == "/peers" || path == "/peers/self" || path == "/peers/ban" || path == "/peers/unban":
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// admit checks the peer is neither banned nor sending messages beyond the limit
+func (p *Agent) admit(peerID string) error {
+	now := time.Now()
+	if p.rep.Banned(peerID, now) {
+		return errors.Errorf("peer %s is banned", peerID)
+	}
+	if !p.rep.Received(peerID, now) {
+		p.ReportViolation(peerID, ViolationSpam)
+		return errors.Errorf("peer %s sends messages beyond the limit", peerID)
+	}
+	return nil
+}
+
+func writePeersResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L().Warn("Failed to write the peers response.", zap.Error(err))
+	}
+}
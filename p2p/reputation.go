@@ -0,0 +1,277 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Violation is a kind of the protocol violations of the peers
+type Violation int
+
+const (
+	// ViolationMalformed is a message failing to be decoded
+	ViolationMalformed Violation = iota
+	// ViolationSpam is the messages beyond the rate limit of a peer
+	ViolationSpam
+	// ViolationInvalidBlock is a block failing the validation
+	ViolationInvalidBlock
+	// ViolationInvalidMessage is a broadcast message failing the pre-validation
+	ViolationInvalidMessage
+)
+
+const (
+	// scoreHalfLife is the time for the score of the violations to decay by half
+	scoreHalfLife = 10 * time.Minute
+	// maxPeerRecords is the number of the peer records beyond which the records of the peers not banned and of little
+	// score are dropped
+	maxPeerRecords = 1000
+)
+
+var (
+	violationPenalties = map[Violation]float64{
+		ViolationMalformed:      20,
+		ViolationSpam:           10,
+		ViolationInvalidBlock:   50,
+		ViolationInvalidMessage: 20,
+	}
+
+	peerViolationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_p2p_peer_violations",
+			Help: "Protocol violations of the peers",
+		},
+		[]string{"violation"},
+	)
+	peerBanCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "iotex_p2p_peer_bans",
+			Help: "Bans of the peers",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(peerViolationCounter)
+	prometheus.MustRegister(peerBanCounter)
+}
+
+func (v Violation) String() string {
+	switch v {
+	case ViolationMalformed:
+		return "malformed"
+	case ViolationSpam:
+		return "spam"
+	case ViolationInvalidBlock:
+		return "invalidBlock"
+	case ViolationInvalidMessage:
+		return "invalidMessage"
+	default:
+		return "unknown"
+	}
+}
+
+type (
+	// PeerStatus is the reputation of a peer
+	PeerStatus struct {
+		ID          string         `json:"id"`
+		Addrs       []string       `json:"addrs,omitempty"`
+		Static      bool           `json:"static"`
+		Score       float64        `json:"score"`
+		Violations  map[string]int `json:"violations,omitempty"`
+		Bans        int            `json:"bans"`
+		BannedUntil *time.Time     `json:"bannedUntil,omitempty"`
+	}
+
+	peerRecord struct {
+		score       float64
+		updated     time.Time
+		violations  map[Violation]int
+		bans        int
+		bannedUntil time.Time
+		window      time.Time
+		msgs        int
+	}
+
+	// reputation tracks the violations of the peers. A peer is banned once the score of its violations, which decays
+	// over time, reaches the threshold, and the time of the ban doubles with each ban of the peer.
+	reputation struct {
+		mutex     sync.Mutex
+		threshold float64
+		ban       time.Duration
+		maxBan    time.Duration
+		msgLimit  int
+		peers     map[string]*peerRecord
+	}
+)
+
+func newReputation(threshold float64, ban, maxBan time.Duration, msgLimit int) *reputation {
+	return &reputation{
+		threshold: threshold,
+		ban:       ban,
+		maxBan:    maxBan,
+		msgLimit:  msgLimit,
+		peers:     make(map[string]*peerRecord),
+	}
+}
+
+// Report records a violation of the peer, and returns the time until which the peer is banned, or zero if the peer
+// is not banned. The violations of an exempt peer are only counted.
+func (r *reputation) Report(peer string, v Violation, exempt bool, now time.Time) time.Time {
+	peerViolationCounter.WithLabelValues(v.String()).Inc()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rec := r.record(peer, now)
+	rec.violations[v]++
+	if now.Before(rec.bannedUntil) {
+		return rec.bannedUntil
+	}
+	if exempt {
+		return time.Time{}
+	}
+	rec.score += violationPenalties[v]
+	if r.threshold > 0 && rec.score >= r.threshold {
+		return r.banLocked(rec, 0, now)
+	}
+	return time.Time{}
+}
+
+// Received counts a message received from the peer, and returns false if the peer sends more messages than the limit
+// per second
+func (r *reputation) Received(peer string, now time.Time) bool {
+	if r.msgLimit <= 0 {
+		return true
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rec := r.record(peer, now)
+	if now.Sub(rec.window) >= time.Second {
+		rec.window = now
+		rec.msgs = 0
+	}
+	rec.msgs++
+	return rec.msgs <= r.msgLimit
+}
+
+// Ban bans the peer for the duration, or for the escalating time of the peer if the duration is 0, and returns the
+// time until which the peer is banned
+func (r *reputation) Ban(peer string, d time.Duration, now time.Time) time.Time {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.banLocked(r.record(peer, now), d, now)
+}
+
+// Unban lifts the ban of the peer and clears its score, the number of the bans is kept
+func (r *reputation) Unban(peer string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if rec, ok := r.peers[peer]; ok {
+		rec.bannedUntil = time.Time{}
+		rec.score = 0
+	}
+}
+
+// Banned returns true if the peer is banned at the time
+func (r *reputation) Banned(peer string, now time.Time) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rec, ok := r.peers[peer]
+	return ok && now.Before(rec.bannedUntil)
+}
+
+// Status returns the reputation of the peer
+func (r *reputation) Status(peer string, now time.Time) PeerStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	status := PeerStatus{ID: peer}
+	rec, ok := r.peers[peer]
+	if !ok {
+		return status
+	}
+	r.decay(rec, now)
+	status.Score = rec.score
+	status.Bans = rec.bans
+	if len(rec.violations) > 0 {
+		status.Violations = make(map[string]int, len(rec.violations))
+		for v, n := range rec.violations {
+			status.Violations[v.String()] = n
+		}
+	}
+	if now.Before(rec.bannedUntil) {
+		until := rec.bannedUntil
+		status.BannedUntil = &until
+	}
+	return status
+}
+
+// BannedPeers returns the peers banned at the time
+func (r *reputation) BannedPeers(now time.Time) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var peers []string
+	for peer, rec := range r.peers {
+		if now.Before(rec.bannedUntil) {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+func (r *reputation) banLocked(rec *peerRecord, d time.Duration, now time.Time) time.Time {
+	if d <= 0 {
+		d = r.ban
+		for i := 0; i < rec.bans && d < r.maxBan; i++ {
+			d *= 2
+		}
+		if d > r.maxBan {
+			d = r.maxBan
+		}
+	}
+	rec.bans++
+	rec.score = 0
+	rec.bannedUntil = now.Add(d)
+	peerBanCounter.Inc()
+	return rec.bannedUntil
+}
+
+// record returns the record of the peer with the score decayed to the time
+func (r *reputation) record(peer string, now time.Time) *peerRecord {
+	rec, ok := r.peers[peer]
+	if !ok {
+		if len(r.peers) >= maxPeerRecords {
+			r.prune(now)
+		}
+		rec = &peerRecord{
+			updated:    now,
+			violations: make(map[Violation]int),
+		}
+		r.peers[peer] = rec
+	}
+	r.decay(rec, now)
+	return rec
+}
+
+func (r *reputation) decay(rec *peerRecord, now time.Time) {
+	if elapsed := now.Sub(rec.updated); elapsed > 0 {
+		rec.score *= math.Pow(0.5, float64(elapsed)/float64(scoreHalfLife))
+		rec.updated = now
+	}
+}
+
+// prune drops the records of the peers which are neither banned nor of a score beyond 1
+func (r *reputation) prune(now time.Time) {
+	for peer, rec := range r.peers {
+		r.decay(rec, now)
+		if !now.Before(rec.bannedUntil) && rec.score < 1 {
+			delete(r.peers, peer)
+		}
+	}
+}
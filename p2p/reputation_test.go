@@ -0,0 +1,100 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReputation(t *testing.T) {
+	require := require.New(t)
+	r := newReputation(100, time.Minute, 3*time.Minute, 0)
+	now := time.Now()
+
+	// the score reaching the threshold bans the peer
+	require.True(r.Report("a", ViolationInvalidBlock, false, now).IsZero())
+	require.False(r.Banned("a", now))
+	until := r.Report("a", ViolationInvalidBlock, false, now)
+	require.Equal(now.Add(time.Minute), until)
+	require.True(r.Banned("a", now))
+	require.False(r.Banned("a", until))
+	status := r.Status("a", now)
+	require.Equal(1, status.Bans)
+	require.Equal(until, *status.BannedUntil)
+	require.Equal(map[string]int{"invalidBlock": 2}, status.Violations)
+	require.Zero(status.Score)
+	require.Equal([]string{"a"}, r.BannedPeers(now))
+
+	// the ban escalates up to the max
+	now = until
+	require.Equal(now.Add(2*time.Minute), r.Ban("a", 0, now))
+	now = now.Add(2 * time.Minute)
+	require.Equal(now.Add(3*time.Minute), r.Ban("a", 0, now))
+	require.Equal(now.Add(time.Hour), r.Ban("a", time.Hour, now))
+	r.Unban("a")
+	require.False(r.Banned("a", now))
+	require.Equal(4, r.Status("a", now).Bans)
+	require.Empty(r.BannedPeers(now))
+
+	// the score decays by half over the half life
+	r.Report("b", ViolationInvalidBlock, false, now)
+	require.InDelta(25, r.Status("b", now.Add(scoreHalfLife)).Score, 0.001)
+	require.True(r.Report("b", ViolationInvalidBlock, false, now.Add(scoreHalfLife)).IsZero())
+
+	// the exempt peer is never banned automatically
+	for i := 0; i < 10; i++ {
+		require.True(r.Report("c", ViolationMalformed, true, now).IsZero())
+	}
+	require.False(r.Banned("c", now))
+	require.Equal(10, r.Status("c", now).Violations["malformed"])
+
+	// the peer of no record
+	require.Equal(PeerStatus{ID: "d"}, r.Status("d", now))
+	r.Unban("d")
+	require.False(r.Banned("d", now))
+
+	// no peer is banned automatically without the threshold
+	r = newReputation(0, time.Minute, time.Minute, 0)
+	for i := 0; i < 10; i++ {
+		require.True(r.Report("a", ViolationInvalidBlock, false, now).IsZero())
+	}
+}
+
+func TestReputationReceived(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	r := newReputation(100, time.Minute, time.Minute, 0)
+	for i := 0; i < 100; i++ {
+		require.True(r.Received("a", now))
+	}
+
+	r = newReputation(100, time.Minute, time.Minute, 2)
+	require.True(r.Received("a", now))
+	require.True(r.Received("a", now.Add(time.Millisecond)))
+	require.False(r.Received("a", now.Add(2*time.Millisecond)))
+	require.True(r.Received("b", now))
+	require.True(r.Received("a", now.Add(time.Second)))
+}
+
+func TestReputationPrune(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	r := newReputation(100, time.Minute, time.Minute, 0)
+	r.Ban("banned", 0, now)
+	r.Report("bad", ViolationInvalidBlock, false, now)
+	for i := 0; len(r.peers) < maxPeerRecords; i++ {
+		r.Report(strconv.Itoa(i), ViolationSpam, true, now)
+	}
+	r.Report("new", ViolationSpam, true, now)
+	require.Len(r.peers, 3)
+	require.True(r.Banned("banned", now))
+	require.Equal(50.0, r.Status("bad", now).Score)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+
+	p2p "github.com/iotexproject/go-p2p"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const (
+	stateRangeRequestTopic  = "staterangerequest"
+	stateRangeResponseTopic = "staterangeresponse"
+)
+
+type (
+	// HandleStateRangeRequestInbound handles the request of id of a peer for at most limit leaves of the state trie at
+	// height in the namespace of nsKey, from the first key not less than start
+	HandleStateRangeRequestInbound func(context.Context, uint32, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64)
+
+	// HandleStateRangeInbound handles the serialized state range sent by a peer for the request of id, the data is
+	// empty if the peer can't serve the request
+	HandleStateRangeInbound func(context.Context, uint32, peerstore.PeerInfo, uint64, []byte)
+)
+
+// WithStateRangeHandlers enables the agent to exchange the ranges of the state trie with peers. The requests for
+// ranges are handled by request, and the ranges sent back by response.
+func WithStateRangeHandlers(request HandleStateRangeRequestInbound, response HandleStateRangeInbound) Option {
+	return func(p *Agent) {
+		p.stateRangeRequestHandler = request
+		p.stateRangeResponseHandler = response
+	}
+}
+
+// RequestStateRange requests a peer for at most limit leaves of the state trie at height in the namespace of nsKey, from
+// the first key not less than start. The range is sent back by SendStateRange with the same id.
+func (p *Agent) RequestStateRange(
+	ctx context.Context,
+	peer peerstore.PeerInfo,
+	id uint64,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) (err error) {
+	defer func() {
+		p2pMsgCounter.WithLabelValues("unicast", stateRangeRequestTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+	}()
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		err = errors.New("P2P context doesn't exist")
+		return
+	}
+	if err = p.send(ctx, peer, stateRangeRequestTopic, encodeStateRangeRequest(p2pCtx.ChainID, id, height, nsKey, start, limit)); err != nil {
+		err = errors.Wrap(err, "error when requesting state range")
+	}
+	return
+}
+
+// SendStateRange sends the serialized state range to the peer requesting it with id, empty data tells the peer the
+// request can't be served
+func (p *Agent) SendStateRange(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) (err error) {
+	defer func() {
+		p2pMsgCounter.WithLabelValues("unicast", stateRangeResponseTopic, "out", peer.ID.Pretty(), status(err)).Inc()
+	}()
+	p2pCtx, ok := GetContext(ctx)
+	if !ok {
+		err = errors.New("P2P context doesn't exist")
+		return
+	}
+	if err = p.send(ctx, peer, stateRangeResponseTopic, encodeStateRange(p2pCtx.ChainID, id, data)); err != nil {
+		err = errors.Wrap(err, "error when sending state range")
+	}
+	return
+}
+
+// addStateRangePubSubs subscribes the topics of state ranges, the handlers block until ready is closed
+func (p *Agent) addStateRangePubSubs(host *p2p.Host, ready <-chan interface{}) error {
+	if p.stateRangeRequestHandler == nil || p.stateRangeResponseHandler == nil {
+		return nil
+	}
+	for topic, handler := range map[string]func(context.Context, peerstore.PeerInfo, []byte) error{
+		stateRangeRequestTopic: func(ctx context.Context, peer peerstore.PeerInfo, data []byte) error {
+			chainID, id, height, nsKey, start, limit, err := decodeStateRangeRequest(data)
+			if err != nil {
+				return err
+			}
+			p.stateRangeRequestHandler(ctx, chainID, peer, id, height, nsKey, start, limit)
+			return nil
+		},
+		stateRangeResponseTopic: func(ctx context.Context, peer peerstore.PeerInfo, data []byte) error {
+			chainID, id, sr, err := decodeStateRange(data)
+			if err != nil {
+				return err
+			}
+			p.stateRangeResponseHandler(ctx, chainID, peer, id, sr)
+			return nil
+		},
+	} {
+		topic, handler := topic, handler
+		if err := host.AddUnicastPubSub(topic+p.topicSuffix, func(ctx context.Context, _ io.Writer, data []byte) (err error) {
+			<-ready
+			stream, ok := p2p.GetUnicastStream(ctx)
+			if !ok {
+				return errors.New("error when asserting unicast stream context")
+			}
+			defer func() {
+				p2pMsgCounter.WithLabelValues("unicast", topic, "in", stream.Conn().RemotePeer().Pretty(), status(err)).Inc()
+			}()
+			peerID := stream.Conn().RemotePeer().Pretty()
+			if err = p.admit(peerID); err != nil {
+				return err
+			}
+			accountBandwidth(topic, "in", len(data), len(data))
+			// the handlers fail only if the data can't be decoded
+			if err = handler(ctx, peerstore.PeerInfo{
+				ID:    stream.Conn().RemotePeer(),
+				Addrs: []multiaddr.Multiaddr{stream.Conn().RemoteMultiaddr()},
+			}, data); err != nil {
+				p.ReportViolation(peerID, ViolationMalformed)
+			}
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "error when adding %s pubsub", topic)
+		}
+	}
+	return nil
+}
+
+// encodeStateRangeRequest encodes the chain ID in 4 bytes, the id, the height and the limit in 8 bytes each, and the
+// namespace key prefixed by its size in 4 bytes, followed by the start key
+func encodeStateRangeRequest(chainID uint32, id, height uint64, nsKey, start []byte, limit uint64) []byte {
+	data := make([]byte, 32, 32+len(nsKey)+len(start))
+	binary.BigEndian.PutUint32(data, chainID)
+	binary.BigEndian.PutUint64(data[4:], id)
+	binary.BigEndian.PutUint64(data[12:], height)
+	binary.BigEndian.PutUint64(data[20:], limit)
+	binary.BigEndian.PutUint32(data[28:], uint32(len(nsKey)))
+	return append(append(data, nsKey...), start...)
+}
+
+func decodeStateRangeRequest(data []byte) (chainID uint32, id, height uint64, nsKey, start []byte, limit uint64, err error) {
+	if len(data) < 32 {
+		err = errors.Errorf("invalid size %d of state range request", len(data))
+		return
+	}
+	size := binary.BigEndian.Uint32(data[28:])
+	if uint64(len(data)-32) < uint64(size) {
+		err = errors.Errorf("invalid size %d of namespace key", size)
+		return
+	}
+	chainID = binary.BigEndian.Uint32(data)
+	id = binary.BigEndian.Uint64(data[4:])
+	height = binary.BigEndian.Uint64(data[12:])
+	limit = binary.BigEndian.Uint64(data[20:])
+	if size > 0 {
+		nsKey = append([]byte{}, data[32:32+size]...)
+	}
+	if rest := data[32+size:]; len(rest) > 0 {
+		start = append([]byte{}, rest...)
+	}
+	return
+}
+
+// encodeStateRange encodes the chain ID in 4 bytes and the id of the request in 8 bytes, followed by the serialized
+// state range
+func encodeStateRange(chainID uint32, id uint64, sr []byte) []byte {
+	data := make([]byte, 12, 12+len(sr))
+	binary.BigEndian.PutUint32(data, chainID)
+	binary.BigEndian.PutUint64(data[4:], id)
+	return append(data, sr...)
+}
+
+func decodeStateRange(data []byte) (uint32, uint64, []byte, error) {
+	if len(data) < 12 {
+		return 0, 0, nil, errors.Errorf("invalid size %d of state range", len(data))
+	}
+	return binary.BigEndian.Uint32(data), binary.BigEndian.Uint64(data[4:]), data[12:], nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateRangeRequestEncoding(t *testing.T) {
+	require := require.New(t)
+	for _, c := range []struct {
+		nsKey, start []byte
+	}{
+		{[]byte("ns"), []byte("start")},
+		{nil, nil},
+		{[]byte("ns"), nil},
+		{nil, []byte("start")},
+	} {
+		data := encodeStateRangeRequest(4689, 7, 100, c.nsKey, c.start, 1024)
+		chainID, id, height, nsKey, start, limit, err := decodeStateRangeRequest(data)
+		require.NoError(err)
+		require.Equal(uint32(4689), chainID)
+		require.Equal(uint64(7), id)
+		require.Equal(uint64(100), height)
+		require.Equal(c.nsKey, nsKey)
+		require.Equal(c.start, start)
+		require.Equal(uint64(1024), limit)
+	}
+
+	data := encodeStateRangeRequest(1, 1, 1, []byte("ns"), nil, 1)
+	_, _, _, _, _, _, err := decodeStateRangeRequest(data[:31])
+	require.Error(err)
+	_, _, _, _, _, _, err = decodeStateRangeRequest(data[:33])
+	require.Error(err)
+}
+
+func TestStateRangeEncoding(t *testing.T) {
+	require := require.New(t)
+	data := encodeStateRange(4689, 7, []byte("range"))
+	chainID, id, sr, err := decodeStateRange(data)
+	require.NoError(err)
+	require.Equal(uint32(4689), chainID)
+	require.Equal(uint64(7), id)
+	require.Equal([]byte("range"), sr)
+
+	_, id, sr, err = decodeStateRange(encodeStateRange(1, 8, nil))
+	require.NoError(err)
+	require.Equal(uint64(8), id)
+	require.Empty(sr)
+
+	_, _, _, err = decodeStateRange(data[:11])
+	require.Error(err)
+}
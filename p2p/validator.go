@@ -0,0 +1,65 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	// Validator pre-validates a broadcast message before it's handled. It's called on the receiving loop of the
+	// broadcast messages, so it should only run the cheap stateless checks, e.g. the signatures
+	Validator func(context.Context, proto.Message) error
+
+	validatorKey struct {
+		chainID uint32
+		msgType iotexrpc.MessageType
+	}
+)
+
+var validationCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_p2p_validation",
+		Help: "Pre-validation of the broadcast messages",
+	},
+	[]string{"message", "status"},
+)
+
+func init() {
+	prometheus.MustRegister(validationCounter)
+}
+
+// RegisterValidator registers the validator of the broadcast messages of the type on the chain. The message failing the
+// validation is dropped before reaching the protocols, and its sender is penalized, so that the senders of the invalid
+// messages are banned and their messages dropped at the p2p layer.
+func (p *Agent) RegisterValidator(chainID uint32, msgType iotexrpc.MessageType, v Validator) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.validators[validatorKey{chainID, msgType}] = v
+}
+
+// validate runs the validator of the message if any
+func (p *Agent) validate(ctx context.Context, chainID uint32, msgType iotexrpc.MessageType, msg proto.Message) error {
+	p.mutex.RLock()
+	v, ok := p.validators[validatorKey{chainID, msgType}]
+	p.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	err := v(ctx, msg)
+	status := successStr
+	if err != nil {
+		status = failureStr
+	}
+	validationCounter.WithLabelValues(strconv.Itoa(int(msgType)), status).Inc()
+	return err
+}
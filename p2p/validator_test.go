@@ -0,0 +1,87 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	"github.com/iotexproject/iotex-proto/golang/testingpb"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestValidator(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	var (
+		mutex    sync.Mutex
+		received = make(map[uint32][]byte)
+	)
+	b := func(_ context.Context, chainID uint32, msg proto.Message) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		received[chainID] = append(received[chainID], msg.(*testingpb.TestPayload).MsgBody[0])
+	}
+	u := func(_ context.Context, _ uint32, _ peerstore.PeerInfo, _ proto.Message) {}
+	bootnode := NewAgent(config.Config{
+		Network: config.Network{Host: "127.0.0.1", Port: testutil.RandomPort()},
+	}, b, u)
+	// the payloads of 0 are invalid on chain 1
+	bootnode.RegisterValidator(1, iotexrpc.MessageType_TEST, func(_ context.Context, msg proto.Message) error {
+		if msg.(*testingpb.TestPayload).MsgBody[0] == 0 {
+			return errors.New("invalid payload")
+		}
+		return nil
+	})
+	require.NoError(bootnode.Start(ctx))
+	defer func() { require.NoError(bootnode.Stop(ctx)) }()
+	agent := NewAgent(config.Config{
+		Network: config.Network{
+			Host:           "127.0.0.1",
+			Port:           testutil.RandomPort(),
+			BootstrapNodes: []string{bootnode.Self()[0].String()},
+		},
+	}, b, u)
+	require.NoError(agent.Start(ctx))
+	defer func() { require.NoError(agent.Stop(ctx)) }()
+
+	// the messages are broadcast until received, as the gossip of the new node takes a while to be ready
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 20*time.Second, func() (bool, error) {
+		for _, msg := range []struct {
+			chainID uint32
+			body    byte
+		}{{1, 0}, {2, 0}, {1, 1}} {
+			if err := agent.BroadcastOutbound(WitContext(ctx, Context{ChainID: msg.chainID}), &testingpb.TestPayload{
+				MsgBody: []byte{msg.body},
+			}); err != nil {
+				return false, err
+			}
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(received[1]) > 0 && len(received[2]) > 0, nil
+	}))
+	mutex.Lock()
+	for _, body := range received[1] {
+		require.EqualValues(1, body)
+	}
+	for _, body := range received[2] {
+		require.EqualValues(0, body)
+	}
+	mutex.Unlock()
+	status := bootnode.rep.Status(agent.Info().ID.Pretty(), time.Now())
+	require.True(status.Violations[ViolationInvalidMessage.String()] > 0)
+}
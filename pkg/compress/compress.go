@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
 )
 
@@ -19,6 +20,7 @@ import (
 const (
 	Gzip   = "Gzip"
 	Snappy = "Snappy"
+	Zstd   = "Zstd"
 )
 
 // error definition
@@ -26,6 +28,12 @@ var (
 	ErrInputEmpty = errors.New("input cannot be empty")
 )
 
+var (
+	// the zstd encoder and decoder are safe for concurrent use of EncodeAll and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
 // Compress compresses input according to compressor
 func Compress(value []byte, compressor string) ([]byte, error) {
 	if value == nil {
@@ -37,6 +45,8 @@ func Compress(value []byte, compressor string) ([]byte, error) {
 		return CompGzip(value)
 	case Snappy:
 		return CompSnappy(value)
+	case Zstd:
+		return CompZstd(value)
 	default:
 		panic("unsupported compressor")
 	}
@@ -49,6 +59,8 @@ func Decompress(value []byte, compressor string) ([]byte, error) {
 		return DecompGzip(value)
 	case Snappy:
 		return DecompSnappy(value)
+	case Zstd:
+		return DecompZstd(value)
 	default:
 		panic("unsupported compressor")
 	}
@@ -94,3 +106,17 @@ func DecompSnappy(data []byte) ([]byte, error) {
 	}
 	return v, err
 }
+
+// CompZstd uses zstd to compress the input bytes
+func CompZstd(data []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+// DecompZstd uses zstd to decompress the input bytes
+func DecompZstd(data []byte) ([]byte, error) {
+	v, err := zstdDecoder.DecodeAll(data, nil)
+	if len(v) == 0 {
+		v = []byte{}
+	}
+	return v, err
+}
@@ -23,6 +23,8 @@ func TestCompressDataBytes(t *testing.T) {
 	r.Error(err)
 	_, err = Decompress([]byte{}, Snappy)
 	r.Error(err)
+	_, err = Decompress([]byte("not zstd frames"), Zstd)
+	r.Error(err)
 	r.Panics(func() { Compress([]byte{}, "invalid") })
 	r.Panics(func() { Decompress([]byte{}, "invalid") })
 
@@ -36,7 +38,7 @@ func TestCompressDataBytes(t *testing.T) {
 		[]byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ`1234567890-=~!@#$%^&*()_+å∫ç∂´´©˙ˆˆ˚¬µ˜˜πœ®ß†¨¨∑≈¥Ω[]',./{}|:<>?"),
 	}
 	for _, ser := range compressTests {
-		for _, compress := range []string{Gzip, Snappy} {
+		for _, compress := range []string{Gzip, Snappy, Zstd} {
 			v, err := Compress(ser, compress)
 			r.NoError(err)
 
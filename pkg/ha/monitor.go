@@ -0,0 +1,104 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/consensus"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+)
+
+var _ lifecycle.StartStopper = (*Monitor)(nil)
+
+// Monitor runs on a standby node sharing the producer identity with the primary node. It sends heartbeats to the
+// admin endpoint of the primary, and activates the standby node once the primary is unreachable or in stand-by mode
+// for the failure window. The standby node does not step back by itself once it takes over.
+type Monitor struct {
+	c         consensus.Consensus
+	cfg       config.Standby
+	client    *http.Client
+	task      *routine.RecurringTask
+	lastAlive time.Time
+	mutex     sync.Mutex
+}
+
+// NewMonitor constructs a monitor of the primary node
+func NewMonitor(c consensus.Consensus, cfg config.Standby) *Monitor {
+	m := &Monitor{
+		c:      c,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.HeartbeatInterval},
+	}
+	m.task = routine.NewRecurringTask(m.check, cfg.HeartbeatInterval)
+	return m
+}
+
+// Start starts sending heartbeats to the primary
+func (m *Monitor) Start(ctx context.Context) error {
+	m.mutex.Lock()
+	m.lastAlive = time.Now()
+	m.mutex.Unlock()
+	return m.task.Start(ctx)
+}
+
+// Stop stops sending heartbeats to the primary
+func (m *Monitor) Stop(ctx context.Context) error {
+	return m.task.Stop(ctx)
+}
+
+func (m *Monitor) check() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.c.Active() {
+		return
+	}
+	err := m.heartbeat()
+	if err == nil {
+		m.lastAlive = time.Now()
+		return
+	}
+	silence := time.Since(m.lastAlive)
+	log.L().Warn("Primary node failed the heartbeat.", zap.Duration("silence", silence), zap.Error(err))
+	if silence < m.cfg.FailureWindow {
+		return
+	}
+	log.L().Warn("Primary node failed for the failure window, set the node to active mode.")
+	m.c.Activate(true)
+}
+
+// heartbeat returns nil if the primary is active
+func (m *Monitor) heartbeat() error {
+	resp, err := m.client.Get(m.cfg.PrimaryEndpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var status struct {
+		Active bool `json:"active"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return errors.Wrap(err, "failed to decode the status")
+	}
+	if !status.Active {
+		return errors.New("primary node is in stand-by mode")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package ha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/mock/mock_consensus"
+)
+
+func TestMonitor(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primaryActive := true
+	primary := mock_consensus.NewMockConsensus(ctrl)
+	primary.EXPECT().Active().DoAndReturn(func() bool { return primaryActive }).AnyTimes()
+	srv := httptest.NewServer(http.HandlerFunc(New(primary).Handle))
+	defer srv.Close()
+
+	standbyActive := false
+	standby := mock_consensus.NewMockConsensus(ctrl)
+	standby.EXPECT().Active().DoAndReturn(func() bool { return standbyActive }).AnyTimes()
+	standby.EXPECT().Activate(true).Do(func(bool) { standbyActive = true }).Times(1)
+
+	cfg := config.Default.System.Standby
+	cfg.PrimaryEndpoint = srv.URL
+	cfg.HeartbeatInterval = time.Hour
+	cfg.FailureWindow = time.Hour
+	m := NewMonitor(standby, cfg)
+	require.NoError(m.Start(context.Background()))
+	defer func() {
+		require.NoError(m.Stop(context.Background()))
+	}()
+
+	// the primary is alive
+	m.check()
+	require.False(standbyActive)
+	// the primary in stand-by mode fails the heartbeat, but the standby waits for the failure window
+	primaryActive = false
+	m.check()
+	require.False(standbyActive)
+
+	// the standby takes over after the failure window
+	m.lastAlive = time.Now().Add(-2 * time.Hour)
+	m.check()
+	require.True(standbyActive)
+	// the active node does not send heartbeats
+	srv.Close()
+	m.check()
+}
+
+func TestMonitorUnreachable(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	standbyActive := false
+	standby := mock_consensus.NewMockConsensus(ctrl)
+	standby.EXPECT().Active().DoAndReturn(func() bool { return standbyActive }).AnyTimes()
+	standby.EXPECT().Activate(true).Do(func(bool) { standbyActive = true }).Times(1)
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	cfg := config.Standby{
+		PrimaryEndpoint:   srv.URL,
+		HeartbeatInterval: 10 * time.Millisecond,
+		FailureWindow:     50 * time.Millisecond,
+	}
+	srv.Close()
+	m := NewMonitor(standby, cfg)
+	require.NoError(m.Start(context.Background()))
+	defer func() {
+		require.NoError(m.Stop(context.Background()))
+	}()
+	require.Eventually(func() bool {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		return standbyActive
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package objstore stores objects by key in a local directory, or in an S3 compatible object storage, such as AWS S3
+// and Google Cloud Storage with HMAC keys.
+package objstore
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotExist indicates the object does not exist
+	ErrNotExist = errors.New("object does not exist")
+)
+
+// Store is an object store
+type Store interface {
+	// Put stores the object of the given size read from r
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get writes the object to w
+	Get(ctx context.Context, key string, w io.Writer) error
+	// Delete deletes the object
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates an object store from its URL, which is either file:///dir, or
+// s3://bucket/prefix?endpoint=host&region=region. The credentials of s3 are read from the environment variables
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func New(rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid object store url %s", rawURL)
+	}
+	switch u.Scheme {
+	case "file":
+		return newDirStore(u.Path)
+	case "s3":
+		return newS3Store(u)
+	default:
+		return nil, errors.Errorf("unsupported object store %s", u.Scheme)
+	}
+}
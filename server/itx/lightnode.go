@@ -0,0 +1,60 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package itx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/lightclient"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/probe"
+	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+)
+
+// StartLightNode runs the node as a light client of the full node at the configured endpoint, which keeps only the
+// headers and the delegate sets, and answers the queries with the verified proofs on the light client port
+func StartLightNode(ctx context.Context, probeSvr *probe.Server, cfg config.Config) {
+	node, err := lightclient.NewNode(
+		cfg.LightClient,
+		cfg.Genesis,
+		lightclient.NewRESTBackend(cfg.LightClient.Endpoint, cfg.LightClient.RequestTimeout),
+	)
+	if err != nil {
+		log.L().Fatal("Failed to create light node.", zap.Error(err))
+		return
+	}
+	if err := node.Start(ctx); err != nil {
+		log.L().Fatal("Failed to start light node.", zap.Error(err))
+		return
+	}
+	defer func() {
+		if err := node.Stop(ctx); err != nil {
+			log.L().Panic("Failed to stop light node.", zap.Error(err))
+		}
+	}()
+
+	svr := httputil.Server(fmt.Sprintf(":%d", cfg.LightClient.Port), node)
+	go func() {
+		if err := svr.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.L().Error("Error when serving light client queries.", zap.Error(err))
+		}
+	}()
+	defer func() {
+		if err := svr.Shutdown(context.Background()); err != nil {
+			log.L().Error("Error when shutting down light client server.", zap.Error(err))
+		}
+	}()
+	probeSvr.Ready()
+
+	<-ctx.Done()
+	probeSvr.NotReady()
+}
@@ -57,7 +57,15 @@ func newServer(cfg config.Config, testing bool) (*Server, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to create dispatcher")
 	}
-	p2pAgent := p2p.NewAgent(cfg, dispatcher.HandleBroadcast, dispatcher.HandleTell)
+	p2pAgent := p2p.NewAgent(
+		cfg,
+		dispatcher.HandleBroadcast,
+		dispatcher.HandleTell,
+		p2p.WithActionHashHandlers(dispatcher.HandleActionAnnouncement, dispatcher.HandleActionRequest),
+		p2p.WithHeaderHandlers(dispatcher.HandleHeaderRequest, dispatcher.HandleHeaders),
+		p2p.WithBlockAnnouncementHandler(dispatcher.HandleBlockAnnouncement),
+		p2p.WithStateRangeHandlers(dispatcher.HandleStateRangeRequest, dispatcher.HandleStateRange),
+	)
 	chains := make(map[uint32]*chainservice.ChainService)
 	var cs *chainservice.ChainService
 	var opts []chainservice.Option
@@ -199,6 +207,9 @@ func StartServer(ctx context.Context, svr *Server, probeSvr *probe.Server, cfg c
 		log.RegisterLevelConfigMux(mux)
 		haCtl := ha.New(svr.rootChainService.Consensus())
 		mux.Handle("/ha", http.HandlerFunc(haCtl.Handle))
+		mux.Handle("/peers", http.HandlerFunc(svr.p2pAgent.HandlePeers))
+		mux.Handle("/peers/", http.HandlerFunc(svr.p2pAgent.HandlePeers))
+		mux.Handle("/backup", http.HandlerFunc(svr.rootChainService.HandleBackup))
 		mux.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
 		mux.Handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
 		mux.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
@@ -226,6 +237,18 @@ func StartServer(ctx context.Context, svr *Server, probeSvr *probe.Server, cfg c
 		}()
 	}
 
+	if cfg.System.Standby.PrimaryEndpoint != "" {
+		monitor := ha.NewMonitor(svr.rootChainService.Consensus(), cfg.System.Standby)
+		if err := monitor.Start(ctx); err != nil {
+			log.L().Panic("Failed to start primary monitor.", zap.Error(err))
+		}
+		defer func() {
+			if err := monitor.Stop(ctx); err != nil {
+				log.L().Panic("Failed to stop primary monitor.", zap.Error(err))
+			}
+		}()
+	}
+
 	<-ctx.Done()
 	probeSvr.NotReady()
 }
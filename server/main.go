@@ -30,7 +30,11 @@ import (
 	"github.com/iotexproject/iotex-core/server/itx"
 )
 
+// _importChainPath is the chain archive to import, the server exits after importing it
+var _importChainPath string
+
 func init() {
+	flag.StringVar(&_importChainPath, "import-chain", "", "Import a chain archive and exit")
 	flag.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"usage: server -config-path=[string]\n")
@@ -64,6 +68,11 @@ func main() {
 	cfgToLog.Chain.ProducerPrivKey = ""
 	log.S().Infof("Config in use: %+v", cfgToLog)
 
+	if _importChainPath != "" {
+		importChain(cfg)
+		return
+	}
+
 	// liveness start
 	probeSvr := probe.New(cfg.System.HTTPStatsPort)
 	if err := probeSvr.Start(ctx); err != nil {
@@ -82,6 +91,13 @@ func main() {
 		livenessCancel()
 	}()
 
+	if cfg.LightClient.Endpoint != "" {
+		itx.StartLightNode(ctx, probeSvr, cfg)
+		close(stopped)
+		<-livenessCtx.Done()
+		return
+	}
+
 	// create and start the node
 	svr, err := itx.NewServer(cfg)
 	if err != nil {
@@ -103,6 +119,18 @@ func main() {
 	<-livenessCtx.Done()
 }
 
+func importChain(cfg config.Config) {
+	svr, err := itx.NewServer(cfg)
+	if err != nil {
+		log.L().Fatal("Failed to create server.", zap.Error(err))
+	}
+	imported, err := svr.ChainService(cfg.Chain.ID).ImportChain(context.Background(), _importChainPath)
+	if err != nil {
+		log.L().Fatal("Failed to import chain archive.", zap.Uint64("imported", imported), zap.Error(err))
+	}
+	log.L().Info("Imported chain archive.", zap.String("file", _importChainPath), zap.Uint64("imported", imported))
+}
+
 func initLogger(cfg config.Config) {
 	addr := cfg.ProducerAddress()
 	if err := log.InitLoggers(cfg.Log, cfg.SubLogs, zap.Fields(
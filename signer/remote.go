@@ -0,0 +1,186 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// maxRequestSize is the max size of the body of a signing request
+const maxRequestSize = 1 << 12
+
+type (
+	// signRequest is the request to sign the hash of a message with the key of the public key
+	signRequest struct {
+		Type      MessageType `json:"type"`
+		PublicKey string      `json:"publicKey"`
+		Hash      string      `json:"hash"`
+	}
+
+	signResponse struct {
+		Signature string `json:"signature,omitempty"`
+		Error     string `json:"error,omitempty"`
+	}
+
+	// remoteSigner sends the hashes of the allowed types of messages to a signing service over TLS, and checks the
+	// signatures against the public key of the producer
+	remoteSigner struct {
+		endpoint string
+		pk       crypto.PublicKey
+		allowed  map[MessageType]bool
+		client   *http.Client
+	}
+
+	handler struct {
+		signer  Signer
+		allowed map[MessageType]bool
+	}
+)
+
+// NewRemoteSigner returns a signer sending the hashes to the remote signing service
+func NewRemoteSigner(cfg config.RemoteSigner) (Signer, error) {
+	pk, err := crypto.HexStringToPublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key of remote signer")
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CACertPath != "" {
+		pem, err := ioutil.ReadFile(cfg.CACertPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CA certificate of remote signer")
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificate in %s", cfg.CACertPath)
+		}
+	}
+	if cfg.ClientCertPath != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate of remote signer")
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return &remoteSigner{
+		endpoint: cfg.Endpoint,
+		pk:       pk,
+		allowed:  allowedTypes(cfg.AllowedMessageTypes),
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		},
+	}, nil
+}
+
+func (s *remoteSigner) PublicKey() crypto.PublicKey {
+	return s.pk
+}
+
+func (s *remoteSigner) SignHash(msgType MessageType, hash []byte) ([]byte, error) {
+	if !s.allowed[msgType] {
+		return nil, errors.Wrapf(ErrMessageTypeNotAllowed, "message type %s", msgType)
+	}
+	body, err := json.Marshal(&signRequest{
+		Type:      msgType,
+		PublicKey: s.pk.HexString(),
+		Hash:      hex.EncodeToString(hash),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request remote signer")
+	}
+	defer resp.Body.Close()
+	var res signResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode response of remote signer, status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("remote signer failed to sign %s, status %d: %s", msgType, resp.StatusCode, res.Error)
+	}
+	sig, err := hex.DecodeString(res.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature from remote signer")
+	}
+	if !s.pk.Verify(hash, sig) {
+		return nil, errors.New("signature from remote signer does not match the public key")
+	}
+	return sig, nil
+}
+
+// NewHandler returns the http handler of a signing service, which signs the hashes of the allowed types of messages
+// with the signer. The service is expected to be served over TLS and to authenticate the clients.
+func NewHandler(s Signer, allowed []MessageType) http.Handler {
+	types := make(map[MessageType]bool, len(allowed))
+	for _, t := range allowed {
+		types[t] = true
+	}
+	return &handler{signer: s, allowed: types}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.respond(w, http.StatusMethodNotAllowed, &signResponse{Error: "only POST is allowed"})
+		return
+	}
+	var req signRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		h.respond(w, http.StatusBadRequest, &signResponse{Error: "invalid request"})
+		return
+	}
+	if !h.allowed[req.Type] {
+		h.respond(w, http.StatusForbidden, &signResponse{Error: ErrMessageTypeNotAllowed.Error()})
+		return
+	}
+	if req.PublicKey != h.signer.PublicKey().HexString() {
+		h.respond(w, http.StatusForbidden, &signResponse{Error: "unknown public key"})
+		return
+	}
+	hash, err := hex.DecodeString(req.Hash)
+	if err != nil || len(hash) != 32 {
+		h.respond(w, http.StatusBadRequest, &signResponse{Error: "invalid hash"})
+		return
+	}
+	sig, err := h.signer.SignHash(req.Type, hash)
+	if err != nil {
+		log.L().Error("Failed to sign hash.", zap.String("type", string(req.Type)), zap.Error(err))
+		h.respond(w, http.StatusInternalServerError, &signResponse{Error: "failed to sign"})
+		return
+	}
+	h.respond(w, http.StatusOK, &signResponse{Signature: hex.EncodeToString(sig)})
+}
+
+func (h *handler) respond(w http.ResponseWriter, status int, res *signResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.L().Warn("Failed to write response of signing request.", zap.Error(err))
+	}
+}
+
+func allowedTypes(types []string) map[MessageType]bool {
+	allowed := make(map[MessageType]bool, len(types))
+	for _, t := range types {
+		allowed[MessageType(t)] = true
+	}
+	return allowed
+}
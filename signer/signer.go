@@ -0,0 +1,87 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package signer
+
+import (
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// MessageType is the type of the messages signed with the producer key
+type MessageType string
+
+const (
+	// BlockMessage is the header of a block produced by the node
+	BlockMessage MessageType = "block"
+	// EndorsementMessage is a proposal or an endorsement in consensus
+	EndorsementMessage MessageType = "endorsement"
+	// ActionMessage is an action the producer puts into its block, such as the grant of the block reward
+	ActionMessage MessageType = "action"
+)
+
+// ErrMessageTypeNotAllowed indicates the type of the message is not allowed to be signed
+var ErrMessageTypeNotAllowed = errors.New("message type is not allowed to be signed")
+
+type (
+	// Signer signs the hashes of the messages with the producer key, which is not necessarily kept by the node
+	Signer interface {
+		PublicKey() crypto.PublicKey
+		SignHash(MessageType, []byte) ([]byte, error)
+	}
+
+	// HashSigner signs the hashes of one type of messages, a private key is a hash signer of all types
+	HashSigner interface {
+		PublicKey() crypto.PublicKey
+		Sign([]byte) ([]byte, error)
+	}
+
+	localSigner struct {
+		sk crypto.PrivateKey
+	}
+
+	messageSigner struct {
+		signer  Signer
+		msgType MessageType
+	}
+)
+
+// NewLocalSigner returns a signer signing with the private key
+func NewLocalSigner(sk crypto.PrivateKey) Signer {
+	return &localSigner{sk: sk}
+}
+
+// NewProducerSigner returns the signer of the producer key, which is the remote signer if it is configured, otherwise
+// the producer private key
+func NewProducerSigner(cfg config.Config) (Signer, error) {
+	if cfg.Chain.RemoteSigner.Endpoint == "" {
+		return NewLocalSigner(cfg.ProducerPrivateKey()), nil
+	}
+	return NewRemoteSigner(cfg.Chain.RemoteSigner)
+}
+
+// ForMessage returns the hash signer of a type of messages
+func ForMessage(s Signer, msgType MessageType) HashSigner {
+	return &messageSigner{signer: s, msgType: msgType}
+}
+
+func (s *localSigner) PublicKey() crypto.PublicKey {
+	return s.sk.PublicKey()
+}
+
+func (s *localSigner) SignHash(_ MessageType, hash []byte) ([]byte, error) {
+	return s.sk.Sign(hash)
+}
+
+func (s *messageSigner) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+func (s *messageSigner) Sign(hash []byte) ([]byte, error) {
+	return s.signer.SignHash(s.msgType, hash)
+}
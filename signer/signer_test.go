@@ -0,0 +1,103 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package signer
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestLocalSigner(t *testing.T) {
+	require := require.New(t)
+	sk := identityset.PrivateKey(1)
+	s := NewLocalSigner(sk)
+	require.Equal(sk.PublicKey().HexString(), s.PublicKey().HexString())
+	h := hash.Hash256b([]byte("block"))
+	sig, err := ForMessage(s, BlockMessage).Sign(h[:])
+	require.NoError(err)
+	expected, err := sk.Sign(h[:])
+	require.NoError(err)
+	require.Equal(expected, sig)
+
+	cfg := config.Default
+	cfg.Chain.ProducerPrivKey = sk.HexString()
+	s, err = NewProducerSigner(cfg)
+	require.NoError(err)
+	require.Equal(sk.PublicKey().HexString(), s.PublicKey().HexString())
+}
+
+func TestRemoteSigner(t *testing.T) {
+	require := require.New(t)
+	sk := identityset.PrivateKey(2)
+	srv := httptest.NewTLSServer(NewHandler(NewLocalSigner(sk), []MessageType{BlockMessage, EndorsementMessage}))
+	defer srv.Close()
+
+	caFile, err := ioutil.TempFile(os.TempDir(), "ca")
+	require.NoError(err)
+	caPath := caFile.Name()
+	defer os.Remove(caPath)
+	require.NoError(pem.Encode(caFile, &pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}))
+	require.NoError(caFile.Close())
+
+	cfg := config.Default
+	cfg.Chain.RemoteSigner = config.RemoteSigner{
+		Endpoint:            srv.URL,
+		PublicKey:           sk.PublicKey().HexString(),
+		CACertPath:          caPath,
+		AllowedMessageTypes: []string{"block", "action"},
+		Timeout:             time.Second,
+	}
+	s, err := NewProducerSigner(cfg)
+	require.NoError(err)
+	require.Equal(sk.PublicKey().HexString(), s.PublicKey().HexString())
+	require.Equal(sk.PublicKey().HexString(), cfg.ProducerPublicKey().HexString())
+
+	h := hash.Hash256b([]byte("block"))
+	sig, err := ForMessage(s, BlockMessage).Sign(h[:])
+	require.NoError(err)
+	require.True(sk.PublicKey().Verify(h[:], sig))
+
+	// the type not allowed by the node is refused before it is sent
+	_, err = s.SignHash(EndorsementMessage, h[:])
+	require.Equal(ErrMessageTypeNotAllowed, errors.Cause(err))
+	// the type not allowed by the service is refused by the service
+	_, err = s.SignHash(ActionMessage, h[:])
+	require.Error(err)
+	// the service does not sign with a key other than its own
+	cfg.Chain.RemoteSigner.PublicKey = identityset.PrivateKey(3).PublicKey().HexString()
+	s, err = NewRemoteSigner(cfg.Chain.RemoteSigner)
+	require.NoError(err)
+	_, err = s.SignHash(BlockMessage, h[:])
+	require.Error(err)
+	// the hash of a message is 32 bytes
+	cfg.Chain.RemoteSigner.PublicKey = sk.PublicKey().HexString()
+	s, err = NewRemoteSigner(cfg.Chain.RemoteSigner)
+	require.NoError(err)
+	_, err = s.SignHash(BlockMessage, []byte("short"))
+	require.Error(err)
+
+	// the certificate of the service is verified
+	cfg.Chain.RemoteSigner.CACertPath = ""
+	s, err = NewRemoteSigner(cfg.Chain.RemoteSigner)
+	require.NoError(err)
+	_, err = s.SignHash(BlockMessage, h[:])
+	require.Error(err)
+}
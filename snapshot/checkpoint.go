@@ -0,0 +1,78 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/objstore"
+)
+
+// Bootstrap imports the snapshot of the trusted checkpoint into a new node, whose chain DB does not exist yet. The
+// snapshot is downloaded from its url, and only accepted if it matches the checkpoint, so the snapshot itself need not
+// be trusted. It returns nil manifest if the node is already bootstrapped.
+func Bootstrap(cp config.Checkpoint, chainID uint32, trieDBPath string, cfg config.DB) (*Manifest, error) {
+	if _, err := os.Stat(cfg.DbPath); !os.IsNotExist(err) {
+		return nil, nil
+	}
+	archive := filepath.Join(filepath.Dir(trieDBPath), fileName(cp.Height)+".download")
+	defer os.Remove(archive)
+	if err := download(cp.Snapshot, archive); err != nil {
+		return nil, errors.Wrapf(err, "failed to download snapshot %s", cp.Snapshot)
+	}
+	m, err := importArchive(archive, chainID, trieDBPath, cfg, func(m *Manifest) error {
+		return matchCheckpoint(m, cp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.L().Info("Bootstrapped from the checkpoint.", zap.Uint64("height", m.Height), zap.String("hash", m.Hash))
+	return m, nil
+}
+
+// matchCheckpoint checks the snapshot is taken at the checkpoint
+func matchCheckpoint(m *Manifest, cp config.Checkpoint) error {
+	if m.Height != cp.Height || m.Hash != cp.Hash {
+		return errors.Wrapf(ErrInvalidSnapshot, "snapshot at block %d %s does not match checkpoint %d %s", m.Height, m.Hash, cp.Height, cp.Hash)
+	}
+	if m.StateRoot != cp.StateRoot {
+		return errors.Wrapf(ErrInvalidSnapshot, "state root %s does not match checkpoint %s", m.StateRoot, cp.StateRoot)
+	}
+	return nil
+}
+
+// download writes the object at the url to the file
+func download(rawURL, file string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	key := path.Base(u.Path)
+	u.Path = path.Dir(u.Path)
+	store, err := objstore.New(u.String())
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := store.Get(context.Background(), key, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// numHashBlocks is the number of latest blocks the EVM reads the hashes of
+const numHashBlocks = 256
+
+type (
+	// StateCheckpointer takes consistent views of the state DB
+	StateCheckpointer interface {
+		Height() (uint64, error)
+		Checkpoint() (*db.Checkpoint, error)
+	}
+
+	// Exporter exports a snapshot at the end of every few epochs. It is the last indexer of the block DAO, so the state
+	// has committed the block when the exporter receives it. The state DB is checkpointed before the next block and
+	// written to the archive in the background.
+	Exporter struct {
+		cfg       config.Snapshot
+		chainID   uint32
+		rp        *rolldpos.Protocol
+		sf        StateCheckpointer
+		mutex     sync.Mutex
+		recent    []*block.Block
+		exporting bool
+		wg        sync.WaitGroup
+	}
+)
+
+// NewExporter creates a snapshot exporter
+func NewExporter(cfg config.Snapshot, chainID uint32, rp *rolldpos.Protocol, sf StateCheckpointer) *Exporter {
+	return &Exporter{
+		cfg:     cfg,
+		chainID: chainID,
+		rp:      rp,
+		sf:      sf,
+	}
+}
+
+// Start starts the exporter
+func (e *Exporter) Start(context.Context) error {
+	return os.MkdirAll(e.cfg.Dir, 0700)
+}
+
+// Stop waits for the ongoing export
+func (e *Exporter) Stop(context.Context) error {
+	e.wg.Wait()
+	return nil
+}
+
+// Height returns the height of the state, as the exporter does not index the past blocks
+func (e *Exporter) Height() (uint64, error) {
+	return e.sf.Height()
+}
+
+// PutBlock keeps the blocks of the last epoch, and exports a snapshot at the last block of an epoch. A failed export
+// does not fail the block.
+func (e *Exporter) PutBlock(_ context.Context, blk *block.Block) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	height := blk.Height()
+	if len(e.recent) > 0 && e.recent[len(e.recent)-1].Height()+1 != height {
+		e.recent = nil
+	}
+	e.recent = append(e.recent, blk)
+	lowest := e.lowestBlock(height)
+	for len(e.recent) > 0 && e.recent[0].Height() < lowest {
+		e.recent = e.recent[1:]
+	}
+
+	epoch := e.rp.GetEpochNum(height)
+	if height != e.rp.GetEpochLastBlockHeight(epoch) || epoch%e.cfg.Interval != 0 {
+		return nil
+	}
+	if e.exporting {
+		log.L().Warn("Skipped snapshot as the previous one is being exported.", zap.Uint64("height", height))
+		return nil
+	}
+	if e.recent[0].Height() != lowest {
+		log.L().Info("Skipped snapshot as the blocks of the epoch are not received.", zap.Uint64("height", height))
+		return nil
+	}
+	state, err := e.sf.Checkpoint()
+	if err != nil {
+		log.L().Error("Failed to checkpoint state.", zap.Uint64("height", height), zap.Error(err))
+		return nil
+	}
+	blocks := make([]*block.Block, len(e.recent))
+	copy(blocks, e.recent)
+	e.exporting = true
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		if err := e.export(state, blocks); err != nil {
+			log.L().Error("Failed to export snapshot.", zap.Uint64("height", height), zap.Error(err))
+		}
+		e.mutex.Lock()
+		e.exporting = false
+		e.mutex.Unlock()
+	}()
+	return nil
+}
+
+// DeleteTipBlock drops the tip block from the kept blocks
+func (e *Exporter) DeleteTipBlock(blk *block.Block) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if n := len(e.recent); n > 0 && e.recent[n-1].Height() == blk.Height() {
+		e.recent = e.recent[:n-1]
+	}
+	return nil
+}
+
+// lowestBlock is the lowest block to keep at the height, which covers the epoch for its productivity, and the blocks
+// whose hashes the EVM reads
+func (e *Exporter) lowestBlock(height uint64) uint64 {
+	if height <= numHashBlocks {
+		return 1
+	}
+	lowest := e.rp.GetEpochHeight(e.rp.GetEpochNum(height))
+	if h := height - numHashBlocks + 1; h < lowest {
+		lowest = h
+	}
+	return lowest
+}
+
+func (e *Exporter) export(state *db.Checkpoint, blocks []*block.Block) error {
+	defer state.Close()
+
+	tip := blocks[len(blocks)-1]
+	height, err := stateHeight(state)
+	if err != nil {
+		return err
+	}
+	if height != tip.Height() {
+		return errors.Errorf("state height %d does not match block height %d", height, tip.Height())
+	}
+	root, err := stateRoot(state)
+	if err != nil {
+		return err
+	}
+	h := tip.HashBlock()
+	m := &Manifest{
+		ChainID:        e.chainID,
+		Height:         height,
+		Hash:           hex.EncodeToString(h[:]),
+		StateRoot:      root,
+		IndexerHeights: map[string]uint64{"factory": height},
+	}
+
+	name := filepath.Join(e.cfg.Dir, fileName(height))
+	tmp := name + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create snapshot")
+	}
+	gw := gzip.NewWriter(f)
+	if err := writeArchive(gw, m, state, blocks); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+	log.L().Info("Exported snapshot.", zap.String("file", name), zap.Uint64("height", height))
+	e.removeOldSnapshots()
+	return nil
+}
+
+// removeOldSnapshots keeps the configured number of latest snapshots
+func (e *Exporter) removeOldSnapshots() {
+	files, err := filepath.Glob(filepath.Join(e.cfg.Dir, "snapshot-*.tar.gz"))
+	if err != nil {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return snapshotHeight(files[i]) < snapshotHeight(files[j])
+	})
+	for len(files) > e.cfg.Keep {
+		if err := os.Remove(files[0]); err != nil {
+			log.L().Warn("Failed to remove snapshot.", zap.String("file", files[0]), zap.Error(err))
+		}
+		files = files[1:]
+	}
+}
+
+func snapshotHeight(file string) uint64 {
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "snapshot-"), ".tar.gz")
+	height, _ := strconv.ParseUint(name, 10, 64)
+	return height
+}
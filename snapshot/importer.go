@@ -0,0 +1,138 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// Import restores a snapshot into the state DB and the chain DB of a new node, which then syncs the blocks after the
+// snapshot from its peers. The chain DB only holds the blocks in the snapshot, so the node cannot serve the history
+// and its API indexers are not supported.
+func Import(archive string, chainID uint32, trieDBPath string, cfg config.DB) (*Manifest, error) {
+	return importArchive(archive, chainID, trieDBPath, cfg, nil)
+}
+
+// importArchive imports the snapshot, after its manifest passes the check, if any
+func importArchive(archive string, chainID uint32, trieDBPath string, cfg config.DB, check func(*Manifest) error) (*Manifest, error) {
+	for _, path := range []string{trieDBPath, cfg.DbPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			return nil, errors.Errorf("%s already exists", path)
+		}
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open snapshot")
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSnapshot, err.Error())
+	}
+
+	tmp := trieDBPath + ".tmp"
+	defer os.Remove(tmp)
+	m, blocks, err := readArchive(gr, tmp)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID != chainID {
+		return nil, errors.Wrapf(ErrInvalidSnapshot, "snapshot of chain %d cannot bootstrap chain %d", m.ChainID, chainID)
+	}
+	if check != nil {
+		if err := check(m); err != nil {
+			return nil, err
+		}
+	}
+	if err := verifyBlocks(m, blocks); err != nil {
+		return nil, err
+	}
+	stateCfg := cfg
+	stateCfg.DbPath = tmp
+	if err := verifyStateDB(m, stateCfg); err != nil {
+		return nil, err
+	}
+
+	fd, err := filedao.NewFileDAOFromHeight(blocks[0].Height(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if err := fd.Start(ctx); err != nil {
+		return nil, err
+	}
+	for _, blk := range blocks {
+		if err := fd.PutBlock(ctx, blk); err != nil {
+			fd.Stop(ctx)
+			return nil, errors.Wrapf(err, "failed to put block %d", blk.Height())
+		}
+	}
+	if err := fd.Stop(ctx); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, trieDBPath); err != nil {
+		return nil, errors.Wrap(err, "failed to move state DB")
+	}
+	return m, nil
+}
+
+func verifyStateDB(m *Manifest, cfg config.DB) error {
+	ctx := context.Background()
+	kv := db.NewBoltDB(cfg)
+	if err := kv.Start(ctx); err != nil {
+		return err
+	}
+	defer kv.Stop(ctx)
+	state, err := kv.Checkpoint()
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+	return verifyState(m, state)
+}
+
+// verifyState checks the height and the root of the state match the manifest, and every node of the account trie is
+// stored under its hash
+func verifyState(m *Manifest, state *db.Checkpoint) error {
+	height, err := stateHeight(state)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSnapshot, err.Error())
+	}
+	if height != m.Height {
+		return errors.Wrapf(ErrInvalidSnapshot, "state height %d does not match %d", height, m.Height)
+	}
+	root, err := stateRoot(state)
+	if err != nil {
+		return err
+	}
+	if root != m.StateRoot {
+		return errors.Wrapf(ErrInvalidSnapshot, "state root %s does not match %s", root, m.StateRoot)
+	}
+	if root == "" {
+		return nil
+	}
+	return state.ForEach(factory.ArchiveTrieNamespace, func(k, v []byte) error {
+		if len(k) != len(hash.Hash160{}) || bytes.HasPrefix(k, []byte(factory.ArchiveTrieRootKey)) {
+			return nil
+		}
+		if h := hash.Hash160b(v); !bytes.Equal(h[:], k) {
+			return errors.Wrapf(ErrInvalidSnapshot, "trie node %x is corrupted", k)
+		}
+		return nil
+	})
+}
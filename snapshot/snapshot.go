@@ -0,0 +1,253 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package snapshot exports the state at the epoch boundaries to portable archives, which bootstrap new nodes without
+// replaying the whole chain. An archive is a gzipped tar of the state DB, the blocks of the last epoch, and a manifest
+// with the checksums of the other entries.
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// entries of the archive, in the order they are written
+const (
+	stateEntry    = "state.db"
+	blocksEntry   = "blocks"
+	manifestEntry = "manifest.json"
+)
+
+// maxBlockSize bounds the size of a block in the archive, a larger size means the archive is corrupted
+const maxBlockSize = 1 << 26
+
+var (
+	// ErrInvalidSnapshot indicates the archive is corrupted or does not match its manifest
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+)
+
+type (
+	// Manifest describes a snapshot
+	Manifest struct {
+		ChainID uint32 `json:"chainID"`
+		// Height is the height of the state, which is the last block of an epoch
+		Height uint64 `json:"height"`
+		// Hash is the hash of the block at Height
+		Hash string `json:"hash"`
+		// StateRoot is the root of the account trie, it is empty for a trieless state DB
+		StateRoot string `json:"stateRoot,omitempty"`
+		// IndexerHeights are the heights of the indexers in the snapshot
+		IndexerHeights map[string]uint64 `json:"indexerHeights"`
+		// Checksums are the sha256 of the other entries of the archive
+		Checksums map[string]string `json:"checksums"`
+	}
+)
+
+// writeArchive writes the state DB at the checkpoint and the blocks to an archive
+func writeArchive(w io.Writer, m *Manifest, state *db.Checkpoint, blocks []*block.Block) error {
+	tw := tar.NewWriter(w)
+	m.Checksums = make(map[string]string)
+
+	h := sha256.New()
+	if err := tw.WriteHeader(&tar.Header{Name: stateEntry, Mode: 0600, Size: state.Size()}); err != nil {
+		return errors.Wrap(err, "failed to write state header")
+	}
+	if _, err := state.WriteTo(io.MultiWriter(tw, h)); err != nil {
+		return errors.Wrap(err, "failed to write state")
+	}
+	m.Checksums[stateEntry] = hex.EncodeToString(h.Sum(nil))
+
+	var buf bytes.Buffer
+	for _, blk := range blocks {
+		ser, err := (&block.Store{Block: blk, Receipts: blk.Receipts}).Serialize()
+		if err != nil {
+			return err
+		}
+		var size [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(size[:], uint64(len(ser)))
+		buf.Write(size[:n])
+		buf.Write(ser)
+	}
+	if err := writeEntry(tw, blocksEntry, buf.Bytes()); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	m.Checksums[blocksEntry] = hex.EncodeToString(sum[:])
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, manifestEntry, data); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}); err != nil {
+		return errors.Wrapf(err, "failed to write %s header", name)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	return nil
+}
+
+// readArchive extracts the state DB of an archive to the state file, and returns the manifest and the blocks. The
+// checksums of the entries are verified against the manifest.
+func readArchive(r io.Reader, stateFile string) (*Manifest, []*block.Block, error) {
+	var (
+		tr        = tar.NewReader(r)
+		checksums = make(map[string]string)
+		blocks    []*block.Block
+		m         *Manifest
+	)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(ErrInvalidSnapshot, err.Error())
+		}
+		h := sha256.New()
+		switch hdr.Name {
+		case stateEntry:
+			if err := extractState(io.TeeReader(tr, h), stateFile); err != nil {
+				return nil, nil, err
+			}
+		case blocksEntry:
+			if blocks, err = readBlocks(io.TeeReader(tr, h)); err != nil {
+				return nil, nil, err
+			}
+		case manifestEntry:
+			m = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, nil, errors.Wrap(ErrInvalidSnapshot, err.Error())
+			}
+			continue
+		default:
+			return nil, nil, errors.Wrapf(ErrInvalidSnapshot, "unknown entry %s", hdr.Name)
+		}
+		checksums[hdr.Name] = checksum(h)
+	}
+	if m == nil {
+		return nil, nil, errors.Wrap(ErrInvalidSnapshot, "manifest is missing")
+	}
+	for _, name := range []string{stateEntry, blocksEntry} {
+		if checksums[name] == "" || checksums[name] != m.Checksums[name] {
+			return nil, nil, errors.Wrapf(ErrInvalidSnapshot, "checksum of %s does not match", name)
+		}
+	}
+	return m, blocks, nil
+}
+
+func extractState(r io.Reader, stateFile string) error {
+	f, err := os.OpenFile(stateFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create state file")
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to extract state")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to extract state")
+	}
+	return f.Close()
+}
+
+func readBlocks(r io.Reader) ([]*block.Block, error) {
+	var (
+		br     = bufio.NewReader(r)
+		blocks []*block.Block
+	)
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return blocks, nil
+		}
+		if err != nil || size > maxBlockSize {
+			return nil, errors.Wrap(ErrInvalidSnapshot, "block is truncated")
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, errors.Wrap(ErrInvalidSnapshot, "block is truncated")
+		}
+		store := &block.Store{}
+		if err := store.Deserialize(buf); err != nil {
+			return nil, errors.Wrap(ErrInvalidSnapshot, err.Error())
+		}
+		store.Block.Receipts = store.Receipts
+		blocks = append(blocks, store.Block)
+	}
+}
+
+func checksum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// stateHeight returns the height of the state at the checkpoint
+func stateHeight(state *db.Checkpoint) (uint64, error) {
+	value, err := state.Get(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get state height")
+	}
+	return byteutil.BytesToUint64(value), nil
+}
+
+// stateRoot returns the root of the account trie at the checkpoint, or empty for a trieless state DB
+func stateRoot(state *db.Checkpoint) (string, error) {
+	value, err := state.Get(factory.ArchiveTrieNamespace, []byte(factory.ArchiveTrieRootKey))
+	switch errors.Cause(err) {
+	case nil:
+		return hex.EncodeToString(value), nil
+	case db.ErrNotExist:
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+// verifyBlocks checks the blocks are consecutive and linked by hash, ending at the block of the manifest
+func verifyBlocks(m *Manifest, blocks []*block.Block) error {
+	if len(blocks) == 0 {
+		return errors.Wrap(ErrInvalidSnapshot, "no block")
+	}
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].Height() != blocks[i-1].Height()+1 || blocks[i].PrevHash() != blocks[i-1].HashBlock() {
+			return errors.Wrapf(ErrInvalidSnapshot, "block %d is not linked to its parent", blocks[i].Height())
+		}
+	}
+	tip := blocks[len(blocks)-1]
+	h := tip.HashBlock()
+	if tip.Height() != m.Height || hex.EncodeToString(h[:]) != m.Hash {
+		return errors.Wrap(ErrInvalidSnapshot, "last block does not match the manifest")
+	}
+	return nil
+}
+
+func fileName(height uint64) string {
+	return fmt.Sprintf("snapshot-%d.tar.gz", height)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type testState struct {
+	*db.BoltDB
+}
+
+func (s testState) Height() (uint64, error) {
+	value, err := s.Get(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey))
+	if err != nil {
+		return 0, err
+	}
+	return byteutil.BytesToUint64(value), nil
+}
+
+// commit updates the account trie and the height of the state
+func (s testState) commit(r *require.Assertions, height uint64) {
+	kv, err := trie.NewKVStore(factory.ArchiveTrieNamespace, s.BoltDB)
+	r.NoError(err)
+	tr, err := mptrie.New(mptrie.KVStoreOption(kv), mptrie.KeyLengthOption(20))
+	r.NoError(err)
+	r.NoError(tr.Start(context.Background()))
+	if root, err := s.Get(factory.ArchiveTrieNamespace, []byte(factory.ArchiveTrieRootKey)); err == nil {
+		r.NoError(tr.SetRootHash(root))
+	}
+	key := hash.Hash160b(byteutil.Uint64ToBytes(height))
+	r.NoError(tr.Upsert(key[:], byteutil.Uint64ToBytes(height)))
+	root, err := tr.RootHash()
+	r.NoError(err)
+	r.NoError(s.Put(factory.ArchiveTrieNamespace, []byte(factory.ArchiveTrieRootKey), root))
+	r.NoError(s.Put(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey), byteutil.Uint64ToBytes(height)))
+}
+
+func testBlock(r *require.Assertions, height uint64, prev hash.Hash256) *block.Block {
+	receipt := &action.Receipt{Status: 1, BlockHeight: height, ActionHash: prev}
+	blk, err := block.NewTestingBuilder().
+		SetHeight(height).
+		SetPrevBlockHash(prev).
+		SetReceipts([]*action.Receipt{receipt}).
+		SetTimeStamp(testutil.TimestampNow().UTC()).
+		SignAndBuild(identityset.PrivateKey(27))
+	r.NoError(err)
+	return &blk
+}
+
+func TestExportImport(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "snapshot")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.Default.DB
+	cfg.DbPath = filepath.Join(dir, "trie.db")
+	state := testState{db.NewBoltDB(cfg)}
+	r.NoError(state.Start(ctx))
+	defer state.Stop(ctx)
+
+	// an epoch has 2 blocks
+	rp := rolldpos.NewProtocol(2, 2, 1)
+	e := NewExporter(config.Snapshot{Dir: filepath.Join(dir, "snapshots"), Interval: 1, Keep: 1}, 1, rp, state)
+	r.NoError(e.Start(ctx))
+
+	var (
+		blocks []*block.Block
+		prev   = hash.ZeroHash256
+	)
+	putBlocks := func(start, end uint64) {
+		for i := start; i <= end; i++ {
+			blk := testBlock(r, i, prev)
+			state.commit(r, i)
+			r.NoError(e.PutBlock(ctx, blk))
+			blocks = append(blocks, blk)
+			prev = blk.HashBlock()
+		}
+		r.NoError(e.Stop(ctx))
+	}
+	putBlocks(1, 3)
+	_, err = os.Stat(filepath.Join(dir, "snapshots", fileName(2)))
+	r.NoError(err)
+	putBlocks(4, 4)
+	_, err = os.Stat(filepath.Join(dir, "snapshots", fileName(2)))
+	r.True(os.IsNotExist(err))
+	archive := filepath.Join(dir, "snapshots", fileName(4))
+	_, err = os.Stat(archive)
+	r.NoError(err)
+
+	chainCfg := config.Default.DB
+	chainCfg.DbPath = filepath.Join(dir, "new-chain.db")
+	newTrie := filepath.Join(dir, "new-trie.db")
+	_, err = Import(archive, 2, newTrie, chainCfg)
+	r.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	m, err := Import(archive, 1, newTrie, chainCfg)
+	r.NoError(err)
+	r.EqualValues(4, m.Height)
+	r.Equal(hex.EncodeToString(prev[:]), m.Hash)
+	_, err = Import(archive, 1, newTrie, chainCfg)
+	r.Error(err)
+
+	// the snapshot bootstraps a node only if it matches the checkpoint
+	cp := config.Checkpoint{Height: 4, Hash: m.Hash, StateRoot: "", Snapshot: "file://" + archive}
+	cpCfg := config.Default.DB
+	cpCfg.DbPath = filepath.Join(dir, "cp-chain.db")
+	cpTrie := filepath.Join(dir, "cp-trie.db")
+	_, err = Bootstrap(cp, 1, cpTrie, cpCfg)
+	r.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	r.Contains(err.Error(), "does not match checkpoint")
+	cp.StateRoot = m.StateRoot
+	cpManifest, err := Bootstrap(cp, 1, cpTrie, cpCfg)
+	r.NoError(err)
+	r.Equal(m, cpManifest)
+	cpManifest, err = Bootstrap(cp, 1, cpTrie, cpCfg)
+	r.NoError(err)
+	r.Nil(cpManifest)
+
+	// the chain db holds the blocks of the snapshot
+	fd, err := filedao.NewFileDAO(chainCfg)
+	r.NoError(err)
+	r.NoError(fd.Start(ctx))
+	height, err := fd.Height()
+	r.NoError(err)
+	r.EqualValues(4, height)
+	for _, blk := range blocks {
+		h, err := fd.GetBlockHash(blk.Height())
+		r.NoError(err)
+		r.Equal(blk.HashBlock(), h)
+		receipts, err := fd.GetReceipts(blk.Height())
+		r.NoError(err)
+		r.Equal(1, len(receipts))
+	}
+	r.NoError(fd.Stop(ctx))
+
+	// the state db is at the snapshot height
+	cfg.DbPath = newTrie
+	newState := testState{db.NewBoltDB(cfg)}
+	r.NoError(newState.Start(ctx))
+	defer newState.Stop(ctx)
+	height, err = newState.Height()
+	r.NoError(err)
+	r.EqualValues(4, height)
+	root, err := newState.Get(factory.ArchiveTrieNamespace, []byte(factory.ArchiveTrieRootKey))
+	r.NoError(err)
+	r.Equal(m.StateRoot, hex.EncodeToString(root))
+}
+
+func TestVerifyState(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "snapshot")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.Default.DB
+	cfg.DbPath = filepath.Join(dir, "trie.db")
+	state := testState{db.NewBoltDB(cfg)}
+	r.NoError(state.Start(ctx))
+	defer state.Stop(ctx)
+	state.commit(r, 1)
+	state.commit(r, 2)
+
+	cp, err := state.Checkpoint()
+	r.NoError(err)
+	root, err := stateRoot(cp)
+	r.NoError(err)
+	m := &Manifest{Height: 2, StateRoot: root}
+	r.NoError(verifyState(m, cp))
+	m.Height = 3
+	r.Equal(ErrInvalidSnapshot, errors.Cause(verifyState(m, cp)))
+	r.NoError(cp.Close())
+
+	// corrupt a trie node
+	cp, err = state.Checkpoint()
+	r.NoError(err)
+	var node []byte
+	r.NoError(cp.ForEach(factory.ArchiveTrieNamespace, func(k, v []byte) error {
+		if len(k) == len(hash.Hash160{}) {
+			node = append([]byte{}, k...)
+		}
+		return nil
+	}))
+	r.NoError(cp.Close())
+	r.NotNil(node)
+	r.NoError(state.Put(factory.ArchiveTrieNamespace, node, []byte("corrupted")))
+	cp, err = state.Checkpoint()
+	r.NoError(err)
+	defer cp.Close()
+	m.Height = 2
+	r.Equal(ErrInvalidSnapshot, errors.Cause(verifyState(m, cp)))
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// maxRangeAttempts is the maximal number of times a state range is fetched before the sync gives up
+const maxRangeAttempts = 5
+
+type (
+	// RangeFetcher fetches the ranges of the state trie at a height from the peers
+	RangeFetcher interface {
+		FetchStateRange(ctx context.Context, height uint64, nsKey []byte, start []byte, limit uint64) (*factory.StateRange, error)
+	}
+
+	// stateSyncer rebuilds the state trie from the fetched ranges
+	stateSyncer struct {
+		fetcher RangeFetcher
+		height  uint64
+		root    []byte
+		limit   uint64
+		flusher db.KVStoreFlusher
+		nodes   trie.KVStore
+	}
+)
+
+// SyncState rebuilds the state trie at the trusted checkpoint and the flat copies of its states into a new state DB,
+// from the ranges of the trie fetched from the peers instead of a snapshot archive. Each range is checked against the
+// state root of the checkpoint, and fetched again if it fails the check, and each trie is checked against its root
+// once it is rebuilt.
+func SyncState(
+	ctx context.Context,
+	cp config.Checkpoint,
+	fetcher RangeFetcher,
+	limit uint64,
+	trieDBPath string,
+	cfg config.DB,
+) error {
+	if _, err := os.Stat(trieDBPath); !os.IsNotExist(err) {
+		return errors.Errorf("%s already exists", trieDBPath)
+	}
+	if limit == 0 {
+		return errors.New("limit of state range should be positive")
+	}
+	root, err := hex.DecodeString(cp.StateRoot)
+	if err != nil || len(root) == 0 {
+		return errors.Errorf("invalid state root %s of checkpoint", cp.StateRoot)
+	}
+	tmp := trieDBPath + ".tmp"
+	defer os.Remove(tmp)
+	stateCfg := cfg
+	stateCfg.DbPath = tmp
+	kv := db.NewBoltDB(stateCfg)
+	if err := kv.Start(ctx); err != nil {
+		return err
+	}
+	// the state is synced from scratch after a failure, so the flush needn't be atomic
+	flusher, err := db.NewKVStoreFlusher(kv, batch.NewCachedBatch(), db.FlushBatchSizeOption(cfg.Flush.MaxBatchBytes))
+	if err != nil {
+		kv.Stop(ctx)
+		return err
+	}
+	nodes, err := trie.NewKVStore(factory.ArchiveTrieNamespace, flusher.KVStoreWithBuffer())
+	if err != nil {
+		kv.Stop(ctx)
+		return err
+	}
+	s := &stateSyncer{
+		fetcher: fetcher,
+		height:  cp.Height,
+		root:    root,
+		limit:   limit,
+		flusher: flusher,
+		nodes:   nodes,
+	}
+	if err := s.sync(ctx); err != nil {
+		kv.Stop(ctx)
+		return err
+	}
+	if err := kv.Stop(ctx); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, trieDBPath); err != nil {
+		return errors.Wrap(err, "failed to move state DB")
+	}
+	log.L().Info("Synced the state of the checkpoint.", zap.Uint64("height", cp.Height), zap.String("stateRoot", cp.StateRoot))
+	return nil
+}
+
+// sync rebuilds the tries of the namespaces and then the first layer
+func (s *stateSyncer) sync(ctx context.Context) error {
+	var nsKeys, nsRoots [][]byte
+	if err := s.fetchTrie(ctx, nil, s.root, func(r *factory.StateRange) error {
+		nsKeys = append(nsKeys, r.Keys...)
+		nsRoots = append(nsRoots, r.Values...)
+		factory.WriteStateRange(s.flusher.KVStoreWithBuffer(), r)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for i, nsKey := range nsKeys {
+		if err := s.rebuild(ctx, nsKey, nsRoots[i]); err != nil {
+			return err
+		}
+	}
+	if err := s.rebuildLayerOne(nsKeys, nsRoots); err != nil {
+		return err
+	}
+	kvb := s.flusher.KVStoreWithBuffer()
+	kvb.MustPut(factory.ArchiveTrieNamespace, []byte(factory.ArchiveTrieRootKey), s.root)
+	kvb.MustPut(factory.ArchiveTrieNamespace, []byte(fmt.Sprintf("%s-%d", factory.ArchiveTrieRootKey, s.height)), s.root)
+	kvb.MustPut(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey), byteutil.Uint64ToBytes(s.height))
+	return s.flusher.Flush()
+}
+
+// rebuild rebuilds the trie of a namespace, the nodes and the states are flushed to the DB after each range
+func (s *stateSyncer) rebuild(ctx context.Context, nsKey []byte, nsRoot []byte) error {
+	tr, err := mptrie.New(mptrie.KVStoreOption(s.nodes), mptrie.KeyLengthOption(len(hash.ZeroHash160)), mptrie.AsyncOption())
+	if err != nil {
+		return err
+	}
+	if err := tr.Start(ctx); err != nil {
+		return err
+	}
+	defer tr.Stop(ctx)
+
+	if err := s.fetchTrie(ctx, nsKey, nsRoot, func(r *factory.StateRange) error {
+		for i, key := range r.Keys {
+			if err := tr.Upsert(key, r.Values[i]); err != nil {
+				return err
+			}
+		}
+		factory.WriteStateRange(s.flusher.KVStoreWithBuffer(), r)
+		if _, err := tr.RootHash(); err != nil {
+			return err
+		}
+		return s.flusher.Flush()
+	}); err != nil {
+		return err
+	}
+	root, err := tr.RootHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, nsRoot) {
+		return errors.Wrapf(ErrInvalidSnapshot, "root of namespace %x is %x rather than %x", nsKey, root, nsRoot)
+	}
+	return nil
+}
+
+// rebuildLayerOne rebuilds the first layer from the roots of the namespaces
+func (s *stateSyncer) rebuildLayerOne(nsKeys, nsRoots [][]byte) error {
+	tr, err := mptrie.New(mptrie.KVStoreOption(s.nodes), mptrie.AsyncOption())
+	if err != nil {
+		return err
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return err
+	}
+	defer tr.Stop(context.Background())
+
+	for i, nsKey := range nsKeys {
+		if err := tr.Upsert(nsKey, nsRoots[i]); err != nil {
+			return err
+		}
+	}
+	root, err := tr.RootHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, s.root) {
+		return errors.Wrapf(ErrInvalidSnapshot, "state root is %x rather than %x", root, s.root)
+	}
+	return nil
+}
+
+// fetchTrie fetches all the leaves of a trie range by range, and passes each verified range to the handler
+func (s *stateSyncer) fetchTrie(ctx context.Context, nsKey []byte, root []byte, handle func(*factory.StateRange) error) error {
+	var start []byte
+	for {
+		r, err := s.fetchRange(ctx, nsKey, root, start)
+		if err != nil {
+			return err
+		}
+		if err := handle(r); err != nil {
+			return err
+		}
+		if !r.More {
+			return nil
+		}
+		if len(r.Keys) == 0 {
+			return errors.Wrapf(ErrInvalidSnapshot, "empty range of namespace %x has more leaves", nsKey)
+		}
+		if start = nextKey(r.Keys[len(r.Keys)-1]); start == nil {
+			return errors.Wrapf(ErrInvalidSnapshot, "range of namespace %x has more leaves after the last key", nsKey)
+		}
+	}
+}
+
+// fetchRange fetches a range until it passes the check
+func (s *stateSyncer) fetchRange(ctx context.Context, nsKey []byte, root []byte, start []byte) (*factory.StateRange, error) {
+	var lastErr error
+	for i := 0; i < maxRangeAttempts; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r, err := s.fetcher.FetchStateRange(ctx, s.height, nsKey, start, s.limit)
+		if err == nil {
+			var rangeRoot []byte
+			if rangeRoot, err = factory.VerifyStateRange(s.root, nsKey, start, r); err == nil && !bytes.Equal(rangeRoot, root) {
+				err = errors.Wrapf(factory.ErrInvalidStateRange, "root of namespace %x does not match", nsKey)
+			}
+			if err == nil {
+				return r, nil
+			}
+		}
+		lastErr = err
+		log.L().Warn("Failed to fetch state range.", zap.Binary("namespace", nsKey), zap.Binary("start", start), zap.Error(err))
+	}
+	return nil, errors.Wrapf(lastErr, "failed to fetch state range of namespace %x from %x", nsKey, start)
+}
+
+// nextKey returns the smallest key of the same length greater than the key, which is nil if there is none
+func nextKey(key []byte) []byte {
+	next := append(key[:0:0], key...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next
+		}
+	}
+	return nil
+}
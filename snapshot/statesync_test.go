@@ -0,0 +1,120 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+// testFetcher serves the ranges from a state factory, and forges the nth range if forge is set
+type testFetcher struct {
+	sr      factory.StateRangeReader
+	fetched int
+	forge   func(n int, r *factory.StateRange)
+}
+
+func (f *testFetcher) FetchStateRange(ctx context.Context, height uint64, nsKey []byte, start []byte, limit uint64) (*factory.StateRange, error) {
+	r, err := f.sr.StateRange(height, nsKey, start, limit)
+	if err != nil {
+		return nil, err
+	}
+	if f.forge != nil {
+		f.forge(f.fetched, r)
+	}
+	f.fetched++
+	return r, nil
+}
+
+func TestSyncState(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "statesync")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ge := config.Default.Genesis
+	ge.InitBalanceMap = make(map[string]string)
+	for i := 0; i < 20; i++ {
+		ge.InitBalanceMap[identityset.Address(i).String()] = big.NewInt(int64(100 + i)).String()
+	}
+	cfg := config.Default
+	cfg.Genesis = ge
+	newFactory := func(opts ...factory.Option) factory.Factory {
+		sf, err := factory.NewFactory(cfg, opts...)
+		r.NoError(err)
+		r.NoError(sf.Register(account.NewProtocol(rewarding.DepositGas)))
+		return sf
+	}
+	sctx := protocol.WithBlockCtx(
+		protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{Genesis: ge}),
+		protocol.BlockCtx{},
+	)
+	sf := newFactory(factory.InMemTrieOption())
+	r.NoError(sf.Start(sctx))
+	defer sf.Stop(sctx)
+	root, err := sf.(factory.StateRangeReader).StateRange(0, nil, nil, 1)
+	r.NoError(err)
+	cp := config.Checkpoint{StateRoot: hex.EncodeToString(root.StateRoot)}
+
+	dbCfg := config.Default.DB
+	// a forged value at the edge of a range is fetched again
+	fetcher := &testFetcher{sr: sf.(factory.StateRangeReader), forge: func(n int, r *factory.StateRange) {
+		if n == 2 && len(r.Values) > 0 {
+			r.Values[0] = []byte{1}
+		}
+	}}
+	trieDBPath := filepath.Join(dir, "trie.db")
+	r.NoError(SyncState(ctx, cp, fetcher, 3, trieDBPath, dbCfg))
+	r.Error(SyncState(ctx, cp, fetcher, 3, trieDBPath, dbCfg))
+
+	// the synced state serves the accounts
+	cfg.Chain.TrieDBPath = trieDBPath
+	synced := newFactory(factory.DefaultTrieOption())
+	r.NoError(synced.Start(sctx))
+	defer synced.Stop(sctx)
+	for i := 0; i < 20; i++ {
+		acct, err := accountutil.LoadAccount(synced, hash.BytesToHash160(identityset.Address(i).Bytes()))
+		r.NoError(err)
+		r.Equal(big.NewInt(int64(100+i)), acct.Balance)
+	}
+
+	// a forged value in the middle of a range is caught by the root of the trie
+	fetcher = &testFetcher{sr: sf.(factory.StateRangeReader), forge: func(n int, r *factory.StateRange) {
+		if r.NamespaceKey != nil && len(r.Values) == 3 {
+			r.Values[1] = []byte{1}
+		}
+	}}
+	err = SyncState(ctx, cp, fetcher, 3, filepath.Join(dir, "forged.db"), dbCfg)
+	r.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	_, err = os.Stat(filepath.Join(dir, "forged.db"))
+	r.True(os.IsNotExist(err))
+
+	// a range keeps failing the check
+	fetcher = &testFetcher{sr: sf.(factory.StateRangeReader), forge: func(n int, r *factory.StateRange) {
+		r.StateRoot = []byte{1}
+	}}
+	err = SyncState(ctx, cp, fetcher, 3, filepath.Join(dir, "failed.db"), dbCfg)
+	r.Equal(factory.ErrInvalidStateRange, errors.Cause(err))
+	r.Equal(maxRangeAttempts, fetcher.fetched)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+type (
+	// ArchiveReader reads the state at the heights whose state root is retained, which are all the heights in archive
+	// mode, or the latest heights within the state retention
+	ArchiveReader interface {
+		// StateRootAt returns the root of the state trie at height
+		StateRootAt(uint64) ([]byte, error)
+		// NewWorkingSetAt returns a read only view of the state at height, which is reconstructed from the state root
+		// of the height
+		NewWorkingSetAt(context.Context, uint64) (protocol.StateReader, error)
+	}
+
+	// Replayer replays the actions of the block following the height of a historical state, in the order of the
+	// block. The changes are kept in memory and never committed.
+	Replayer interface {
+		// CreatePreStates runs the handlers of the protocols before the first action of the block
+		CreatePreStates(context.Context) error
+		// RunAction runs the action in the block of the context
+		RunAction(context.Context, action.SealedEnvelope) (*action.Receipt, error)
+	}
+
+	// readOnlyWorkingSet is a working set on top of the state at a height, which only serves reads
+	readOnlyWorkingSet struct {
+		ws     *workingSet
+		height uint64
+	}
+)
+
+// StateRootAt returns the root of the state trie at height
+func (sf *factory) StateRootAt(height uint64) ([]byte, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	if err := sf.checkArchivedHeight(height); err != nil {
+		return nil, err
+	}
+	root, err := sf.dao.Get(ArchiveTrieNamespace, []byte(fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height)))
+	if errors.Cause(err) == db.ErrNotExist {
+		return nil, errors.Wrapf(ErrNoArchiveData, "state root of height %d is not retained", height)
+	}
+	return root, err
+}
+
+// NewWorkingSetAt returns a read only view of the state at height, the reads share the trie of the state root of the
+// height instead of loading it for each read
+func (sf *factory) NewWorkingSetAt(ctx context.Context, height uint64) (protocol.StateReader, error) {
+	if _, ok := protocol.GetBlockchainCtx(ctx); !ok {
+		ctx = protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{Genesis: sf.cfg.Genesis})
+	}
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	if err := sf.checkArchivedHeight(height); err != nil {
+		return nil, err
+	}
+	ws, err := sf.newWorkingSetAtHeight(ctx, height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to obtain working set at height %d from state factory", height)
+	}
+	return &readOnlyWorkingSet{ws: ws, height: height}, nil
+}
+
+// checkArchivedHeight checks the state of the height is retained, the caller should hold the lock
+func (sf *factory) checkArchivedHeight(height uint64) error {
+	if height > sf.currentChainHeight {
+		return errors.Errorf("query height %d is higher than tip height %d", height, sf.currentChainHeight)
+	}
+	if height < sf.currentChainHeight && !sf.historyAvailable(height) {
+		return ErrNoArchiveData
+	}
+	return nil
+}
+
+// Height returns the height of the state
+func (ro *readOnlyWorkingSet) Height() (uint64, error) {
+	return ro.height, nil
+}
+
+// State reads a state at the height
+func (ro *readOnlyWorkingSet) State(s interface{}, opts ...protocol.StateOption) (uint64, error) {
+	_, err := ro.ws.State(s, opts...)
+	return ro.height, err
+}
+
+// States is not supported, the keys of the states are hashed in the state trie
+func (ro *readOnlyWorkingSet) States(...protocol.StateOption) (uint64, state.Iterator, error) {
+	return ro.height, nil, errors.Wrap(ErrNotSupported, "Read historical states has not been implemented yet")
+}
+
+// ReadView reads the view of the latest state, same as the history state reader
+func (ro *readOnlyWorkingSet) ReadView(name string) (interface{}, error) {
+	return ro.ws.ReadView(name)
+}
+
+// CreatePreStates runs the handlers of the protocols before the first action of the block
+func (ro *readOnlyWorkingSet) CreatePreStates(ctx context.Context) error {
+	if err := ro.ws.validate(ctx); err != nil {
+		return err
+	}
+	for _, p := range protocol.MustGetRegistry(ctx).All() {
+		if pp, ok := p.(protocol.PreStatesCreator); ok {
+			if err := pp.CreatePreStates(ctx, ro.ws); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RunAction runs the action in the block of the context on top of the state, and the actions run before it
+func (ro *readOnlyWorkingSet) RunAction(ctx context.Context, selp action.SealedEnvelope) (*action.Receipt, error) {
+	if err := ro.ws.validate(ctx); err != nil {
+		return nil, err
+	}
+	ctx, err := withActionCtx(ctx, selp)
+	if err != nil {
+		return nil, err
+	}
+	return ro.ws.runAction(ctx, selp)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestArchiveReader(t *testing.T) {
+	r := require.New(t)
+	b := identityset.Address(31).String()
+
+	t.Run("archive mode", func(t *testing.T) {
+		cfg := config.Default
+		cfg.Chain.EnableArchiveMode = true
+		sf, ctx := startTestFactory(t, cfg, 3)
+		defer func() {
+			r.NoError(sf.Stop(ctx))
+		}()
+		ar := sf.(ArchiveReader)
+		roots := make(map[string]bool)
+		for h := uint64(0); h <= 3; h++ {
+			sr, err := ar.NewWorkingSetAt(context.Background(), h)
+			r.NoError(err)
+			height, err := sr.Height()
+			r.NoError(err)
+			r.Equal(h, height)
+			acct, err := accountutil.AccountState(sr, b)
+			r.NoError(err)
+			r.Equal(big.NewInt(int64(10*h)), acct.Balance)
+			_, _, err = sr.States()
+			r.Equal(ErrNotSupported, errors.Cause(err))
+
+			root, err := ar.StateRootAt(h)
+			r.NoError(err)
+			r.False(roots[string(root)])
+			roots[string(root)] = true
+		}
+		_, err := ar.NewWorkingSetAt(context.Background(), 4)
+		r.Error(err)
+		_, err = ar.StateRootAt(4)
+		r.Error(err)
+	})
+	t.Run("archive mode disabled", func(t *testing.T) {
+		sf, ctx := startTestFactory(t, config.Default, 2)
+		defer func() {
+			r.NoError(sf.Stop(ctx))
+		}()
+		ar := sf.(ArchiveReader)
+		sr, err := ar.NewWorkingSetAt(context.Background(), 2)
+		r.NoError(err)
+		acct, err := accountutil.AccountState(sr, b)
+		r.NoError(err)
+		r.Equal(big.NewInt(20), acct.Balance)
+		_, err = ar.NewWorkingSetAt(context.Background(), 1)
+		r.Equal(ErrNoArchiveData, errors.Cause(err))
+		_, err = ar.StateRootAt(1)
+		r.Equal(ErrNoArchiveData, errors.Cause(err))
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// blockComposer tracks the actions picked into the block being produced, and checks the next one against the
+// composition limits
+type blockComposer struct {
+	cfg           config.BlockComposition
+	executions    int
+	calldataBytes uint64
+}
+
+func newBlockComposer(cfg config.BlockComposition) *blockComposer {
+	return &blockComposer{cfg: cfg}
+}
+
+// Fits returns whether the action could be picked with the block gas left
+func (c *blockComposer) Fits(selp action.SealedEnvelope, gasLeft uint64) bool {
+	isTransfer, isExecution, size := actionCalldata(selp)
+	if !isTransfer {
+		if gasLeft < c.cfg.TransferReservedGas {
+			return false
+		}
+		gasLeft -= c.cfg.TransferReservedGas
+	}
+	if selp.GasLimit() > gasLeft {
+		return false
+	}
+	if isExecution && c.cfg.MaxExecutions > 0 && c.executions >= c.cfg.MaxExecutions {
+		return false
+	}
+	if c.cfg.MaxCalldataBytes > 0 && c.calldataBytes+size > c.cfg.MaxCalldataBytes {
+		return false
+	}
+	return true
+}
+
+// Add counts the action picked into the block
+func (c *blockComposer) Add(selp action.SealedEnvelope) {
+	_, isExecution, size := actionCalldata(selp)
+	if isExecution {
+		c.executions++
+	}
+	c.calldataBytes += size
+}
+
+func actionCalldata(selp action.SealedEnvelope) (isTransfer bool, isExecution bool, size uint64) {
+	switch act := selp.Action().(type) {
+	case *action.Transfer:
+		return true, false, uint64(len(act.Payload()))
+	case *action.Execution:
+		return false, true, uint64(len(act.Data()))
+	default:
+		return false, false, 0
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestBlockComposer(t *testing.T) {
+	r := require.New(t)
+	sk := identityset.PrivateKey(28)
+	to := identityset.Address(29).String()
+	tsf, err := testutil.SignedTransfer(to, sk, 1, big.NewInt(1), make([]byte, 10), 10000, big.NewInt(0))
+	r.NoError(err)
+	ex, err := testutil.SignedExecution(to, sk, 2, big.NewInt(0), 20000, big.NewInt(0), make([]byte, 30))
+	r.NoError(err)
+
+	t.Run("unlimited", func(t *testing.T) {
+		c := newBlockComposer(config.BlockComposition{})
+		for i := 0; i < 10; i++ {
+			r.True(c.Fits(ex, 20000))
+			c.Add(ex)
+		}
+		r.False(c.Fits(ex, 19999))
+		r.True(c.Fits(tsf, 10000))
+	})
+	t.Run("max executions", func(t *testing.T) {
+		c := newBlockComposer(config.BlockComposition{MaxExecutions: 2})
+		for i := 0; i < 2; i++ {
+			r.True(c.Fits(ex, 100000))
+			c.Add(ex)
+		}
+		r.False(c.Fits(ex, 100000))
+		r.True(c.Fits(tsf, 100000))
+	})
+	t.Run("max calldata bytes", func(t *testing.T) {
+		c := newBlockComposer(config.BlockComposition{MaxCalldataBytes: 45})
+		r.True(c.Fits(ex, 100000))
+		c.Add(ex)
+		r.True(c.Fits(tsf, 100000))
+		c.Add(tsf)
+		r.False(c.Fits(tsf, 100000))
+		r.False(c.Fits(ex, 100000))
+	})
+	t.Run("transfer reserved gas", func(t *testing.T) {
+		c := newBlockComposer(config.BlockComposition{TransferReservedGas: 15000})
+		r.True(c.Fits(ex, 35000))
+		r.False(c.Fits(ex, 34999))
+		r.True(c.Fits(tsf, 10000))
+		r.False(c.Fits(ex, 10000))
+	})
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// AccountPreimageNamespace is the bucket of the addresses of the accounts keyed by their keys in the state trie, which
+// are the hashes of the addresses
+const AccountPreimageNamespace = "AccountPreimage"
+
+// accountPreimageBackfilledKey marks the preimages of the accounts created before the bucket was introduced are added
+var accountPreimageBackfilledKey = []byte("backfilled")
+
+type (
+	// AccountDumper enumerates the accounts of the state at a height
+	AccountDumper interface {
+		// DumpAccounts returns at most limit accounts from the cursor, and the cursor of the next page, which is nil if
+		// there are no more accounts. The cursor is opaque, nil means the first page.
+		DumpAccounts(height uint64, cursor []byte, limit uint64) ([]*AccountEntry, []byte, error)
+	}
+
+	// AccountEntry is an account in the dump of the state
+	AccountEntry struct {
+		// Key is the key of the account in the state
+		Key []byte
+		// Address is the hash of the address of the account, which is nil if it is unknown
+		Address *hash.Hash160
+		Account *state.Account
+	}
+)
+
+// DumpAccounts returns a page of the accounts in the account trie at height, in the order of their keys in the trie
+func (sf *factory) DumpAccounts(height uint64, cursor []byte, limit uint64) ([]*AccountEntry, []byte, error) {
+	if len(cursor) != 0 && len(cursor) != len(hash.ZeroHash160) {
+		return nil, nil, errors.Errorf("invalid cursor %x", cursor)
+	}
+	if len(cursor) == 0 {
+		cursor = hash.ZeroHash160[:]
+	}
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	if err := sf.checkArchivedHeight(height); err != nil {
+		return nil, nil, err
+	}
+	accounts, err := sf.accountTrieAtHeight(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer accounts.Stop(context.Background())
+
+	iter, err := mptrie.NewOrderedLeafIterator(accounts, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries := []*AccountEntry{}
+	for {
+		key, value, err := iter.Next()
+		if err == trie.ErrEndOfIterator {
+			return entries, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if uint64(len(entries)) == limit {
+			return entries, key, nil
+		}
+		acct := &state.Account{}
+		if err := state.Deserialize(acct, value); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to deserialize account of key %x", key)
+		}
+		entry := &AccountEntry{Key: key, Account: acct}
+		addr, err := sf.dao.Get(AccountPreimageNamespace, key)
+		switch errors.Cause(err) {
+		case nil:
+			h := hash.BytesToHash160(addr)
+			entry.Address = &h
+		case db.ErrNotExist, db.ErrBucketNotExist:
+		default:
+			return nil, nil, errors.Wrapf(err, "failed to read the address of account key %x", key)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// accountTrieAtHeight returns the trie of the account namespace at height, which is read only. The caller should hold
+// the lock.
+func (sf *factory) accountTrieAtHeight(height uint64) (trie.Trie, error) {
+	layerOne, dbForTrie, _, err := sf.layerOneTrieAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	defer layerOne.Stop(context.Background())
+
+	accountRoot, err := layerOne.Get(namespaceKey(AccountKVNamespace))
+	switch errors.Cause(err) {
+	case nil:
+	case trie.ErrNotExist:
+		// no account yet
+		accountRoot = nil
+	default:
+		return nil, err
+	}
+	return namespaceTrie(dbForTrie, accountRoot)
+}
+
+// layerOneTrieAtHeight returns the first layer of the state trie at height, the store of the trie nodes, and the root
+// of the state trie, which are read only. The caller should hold the lock.
+func (sf *factory) layerOneTrieAtHeight(height uint64) (trie.Trie, trie.KVStore, []byte, error) {
+	rootKey := ArchiveTrieRootKey
+	if height < sf.currentChainHeight {
+		rootKey = fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height)
+	}
+	dbForTrie, err := trie.NewKVStore(ArchiveTrieNamespace, sf.dao)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to create db for trie")
+	}
+	root, err := dbForTrie.Get([]byte(rootKey))
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "failed to get the root of the state trie at height %d", height)
+	}
+	layerOne, err := mptrie.New(mptrie.KVStoreOption(dbForTrie), mptrie.RootHashOption(root))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := layerOne.Start(context.Background()); err != nil {
+		return nil, nil, nil, err
+	}
+	return layerOne, dbForTrie, root, nil
+}
+
+// namespaceTrie returns the trie of a namespace of the root in the second layer of the state trie, an empty root
+// means the namespace has no state
+func namespaceTrie(dbForTrie trie.KVStore, root []byte) (trie.Trie, error) {
+	opts := []mptrie.Option{mptrie.KVStoreOption(dbForTrie), mptrie.KeyLengthOption(len(hash.ZeroHash160))}
+	if len(root) != 0 {
+		opts = append(opts, mptrie.RootHashOption(root))
+	}
+	tr, err := mptrie.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// putAccountPreimage records the address of the account key if it is not recorded yet
+func putAccountPreimage(kv db.KVStoreWithBuffer, key []byte) {
+	legacyKey := toLegacyKey(key)
+	if _, err := kv.Get(AccountPreimageNamespace, legacyKey); err == nil {
+		return
+	}
+	kv.MustPut(AccountPreimageNamespace, legacyKey, key)
+}
+
+// backfillAccountPreimages records the addresses of the accounts created before the preimages are recorded
+func (sf *factory) backfillAccountPreimages() error {
+	if _, err := sf.dao.Get(AccountPreimageNamespace, accountPreimageBackfilledKey); err == nil {
+		return nil
+	}
+	keys, _, err := sf.dao.Filter(AccountKVNamespace, func(k, v []byte) bool {
+		return len(k) == len(hash.ZeroHash160)
+	}, nil, nil)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+	default:
+		log.L().Warn("Failed to backfill the account preimages.", zap.Error(err))
+		return nil
+	}
+	b := batch.NewBatch()
+	for _, key := range keys {
+		b.Put(AccountPreimageNamespace, toLegacyKey(key), key, "failed to put account preimage")
+	}
+	b.Put(AccountPreimageNamespace, accountPreimageBackfilledKey, []byte{1}, "failed to mark account preimages backfilled")
+	return sf.dao.WriteBatch(b)
+}
+
+// DumpAccounts returns a page of the accounts at height, in the order of their addresses. Only the latest state is
+// kept by statedb.
+func (sdb *stateDB) DumpAccounts(height uint64, cursor []byte, limit uint64) ([]*AccountEntry, []byte, error) {
+	if len(cursor) != 0 && len(cursor) != len(hash.ZeroHash160) {
+		return nil, nil, errors.Errorf("invalid cursor %x", cursor)
+	}
+	sdb.mutex.RLock()
+	defer sdb.mutex.RUnlock()
+	if height > sdb.currentChainHeight {
+		return nil, nil, errors.Errorf("query height %d is higher than tip height %d", height, sdb.currentChainHeight)
+	}
+	if height < sdb.currentChainHeight {
+		return nil, nil, errors.Wrap(ErrNoArchiveData, "statedb only keeps the latest state")
+	}
+	var n uint64
+	keys, values, err := sdb.dao.Filter(AccountKVNamespace, func(k, v []byte) bool {
+		if len(k) != len(hash.ZeroHash160) || bytes.Compare(k, cursor) < 0 || n > limit {
+			return false
+		}
+		n++
+		return true
+	}, cursor, nil)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return []*AccountEntry{}, nil, nil
+	default:
+		return nil, nil, err
+	}
+	var next []byte
+	if uint64(len(keys)) > limit {
+		keys, values, next = keys[:limit], values[:limit], keys[limit]
+	}
+	entries := make([]*AccountEntry, 0, len(keys))
+	for i, key := range keys {
+		acct := &state.Account{}
+		if err := state.Deserialize(acct, values[i]); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to deserialize account %x", key)
+		}
+		addr := hash.BytesToHash160(key)
+		entries = append(entries, &AccountEntry{Key: key, Address: &addr, Account: acct})
+	}
+	return entries, next, nil
+}
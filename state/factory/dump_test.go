@@ -0,0 +1,124 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestFactoryDumpAccounts(t *testing.T) {
+	r := require.New(t)
+	cfg := config.Default
+	cfg.Chain.StateRetention = 2
+	sf, ctx := startTestFactory(t, cfg, 3)
+	defer func() {
+		r.NoError(sf.Stop(ctx))
+	}()
+	dumper := sf.(AccountDumper)
+
+	dump := func(height uint64, limit uint64) map[hash.Hash160]*big.Int {
+		balances := make(map[hash.Hash160]*big.Int)
+		var cursor []byte
+		for {
+			entries, next, err := dumper.DumpAccounts(height, cursor, limit)
+			r.NoError(err)
+			r.True(uint64(len(entries)) <= limit)
+			for _, entry := range entries {
+				r.NotNil(entry.Address)
+				r.Equal(toLegacyKey(entry.Address[:]), entry.Key)
+				balances[*entry.Address] = entry.Account.Balance
+			}
+			if next == nil {
+				return balances
+			}
+			cursor = next
+		}
+	}
+	a := hash.BytesToHash160(identityset.Address(28).Bytes())
+	b := hash.BytesToHash160(identityset.Address(31).Bytes())
+	for _, limit := range []uint64{1, 10} {
+		r.Equal(map[hash.Hash160]*big.Int{a: big.NewInt(70), b: big.NewInt(30)}, dump(3, limit))
+		r.Equal(map[hash.Hash160]*big.Int{a: big.NewInt(80), b: big.NewInt(20)}, dump(2, limit))
+	}
+
+	_, _, err := dumper.DumpAccounts(0, nil, 10)
+	r.Equal(ErrNoArchiveData, errors.Cause(err))
+	_, _, err = dumper.DumpAccounts(4, nil, 10)
+	r.Error(err)
+	_, _, err = dumper.DumpAccounts(3, []byte{1}, 10)
+	r.Error(err)
+}
+
+func TestStateDBDumpAccounts(t *testing.T) {
+	r := require.New(t)
+	path, err := testutil.PathOfTempFile("dump")
+	r.NoError(err)
+	defer testutil.CleanupPath(t, path)
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	kv := db.NewBoltDB(cfg)
+	r.NoError(kv.Start(context.Background()))
+	defer kv.Stop(context.Background())
+
+	var addrs []hash.Hash160
+	for i := 0; i < 5; i++ {
+		addr := hash.BytesToHash160(identityset.Address(i).Bytes())
+		ss, err := state.Serialize(&state.Account{Balance: big.NewInt(int64(i))})
+		r.NoError(err)
+		r.NoError(kv.Put(AccountKVNamespace, addr[:], ss))
+		addrs = append(addrs, addr)
+	}
+	r.NoError(kv.Put(AccountKVNamespace, []byte(CurrentHeightKey), []byte{0, 0, 0, 0, 0, 0, 0, 3}))
+
+	sdb := &stateDB{dao: kv, currentChainHeight: 3}
+	found := make(map[hash.Hash160]bool)
+	var (
+		cursor []byte
+		pages  int
+	)
+	for {
+		entries, next, err := sdb.DumpAccounts(3, cursor, 2)
+		r.NoError(err)
+		pages++
+		for _, entry := range entries {
+			found[*entry.Address] = true
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	r.Equal(3, pages)
+	r.Len(found, len(addrs))
+	_, _, err = sdb.DumpAccounts(2, nil, 2)
+	r.Equal(ErrNoArchiveData, errors.Cause(err))
+
+	// the preimages of the existing accounts are backfilled once
+	sf := &factory{dao: kv}
+	r.NoError(sf.backfillAccountPreimages())
+	for _, addr := range addrs {
+		v, err := kv.Get(AccountPreimageNamespace, toLegacyKey(addr[:]))
+		r.NoError(err)
+		r.Equal(addr[:], v)
+	}
+	_, err = kv.Get(AccountPreimageNamespace, toLegacyKey([]byte(CurrentHeightKey)))
+	r.Equal(db.ErrNotExist, errors.Cause(err))
+	_, err = kv.Get(AccountPreimageNamespace, accountPreimageBackfilledKey)
+	r.NoError(err)
+}
@@ -81,6 +81,13 @@ type (
 		// NewBlockBuilder creates block builder
 		NewBlockBuilder(context.Context, actpool.ActPool, func(action.Envelope) (action.SealedEnvelope, error)) (*block.Builder, error)
 		SimulateExecution(context.Context, address.Address, *action.Execution, evm.GetBlockHash) ([]byte, *action.Receipt, error)
+		SimulateExecutionAtHeight(context.Context, uint64, address.Address, *action.Execution, evm.GetBlockHash) ([]byte, *action.Receipt, error)
+		// SimulateExecutions simulates the executions on top of the same state, and returns the tip height of the state
+		SimulateExecutions(context.Context, []*Simulation, evm.GetBlockHash) ([]*SimulationResult, uint64, error)
+		// SimulateAction runs the pending actions and then the action on top of the latest state, without committing
+		SimulateAction(context.Context, []action.SealedEnvelope, action.SealedEnvelope) (*action.Receipt, error)
+		// PendingState returns the state after the pending actions on top of the latest state, without committing
+		PendingState(context.Context, []action.SealedEnvelope) (*PendingState, error)
 		PutBlock(context.Context, *block.Block) error
 		DeleteTipBlock(*block.Block) error
 		StateAtHeight(uint64, interface{}, ...protocol.StateOption) error
@@ -126,7 +133,7 @@ func DefaultTrieOption() Option {
 			return errors.New("Invalid empty trie db path")
 		}
 		cfg.DB.DbPath = dbPath // TODO: remove this after moving TrieDBPath from cfg.Chain to cfg.DB
-		sf.dao = db.NewBoltDB(cfg.DB)
+		sf.dao = db.NewKVStoreWithCoalescing(db.NewBoltDB(cfg.DB), cfg.DB.Flush)
 		return nil
 	}
 }
@@ -222,6 +229,12 @@ func (sf *factory) Start(ctx context.Context) error {
 	switch errors.Cause(err) {
 	case nil:
 		sf.currentChainHeight = byteutil.BytesToUint64(h)
+		if err := sf.backfillAccountPreimages(); err != nil {
+			return errors.Wrap(err, "failed to backfill account preimages")
+		}
+		if err := sf.backfillStatePreimages(); err != nil {
+			return errors.Wrap(err, "failed to backfill state preimages")
+		}
 		// start all protocols
 		if sf.protocolView, err = sf.registry.StartAll(ctx, sf); err != nil {
 			return err
@@ -230,6 +243,12 @@ func (sf *factory) Start(ctx context.Context) error {
 		if err = sf.dao.Put(AccountKVNamespace, []byte(CurrentHeightKey), byteutil.Uint64ToBytes(0)); err != nil {
 			return errors.Wrap(err, "failed to init factory's height")
 		}
+		if err = sf.dao.Put(AccountPreimageNamespace, accountPreimageBackfilledKey, []byte{1}); err != nil {
+			return errors.Wrap(err, "failed to init account preimages")
+		}
+		if err = sf.dao.Put(StatePreimageNamespace, statePreimageBackfilledKey, []byte{1}); err != nil {
+			return errors.Wrap(err, "failed to init state preimages")
+		}
 		// start all protocols
 		if sf.protocolView, err = sf.registry.StartAll(ctx, sf); err != nil {
 			return err
@@ -273,25 +292,68 @@ func (sf *factory) Height() (uint64, error) {
 	return byteutil.BytesToUint64(height), nil
 }
 
+// Checkpoint returns a consistent view of the underlying DB, which is used to export the state
+func (sf *factory) Checkpoint() (*db.Checkpoint, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	return checkpoint(sf.dao)
+}
+
+// Backup writes a consistent copy of the underlying DB into the directory, which doesn't block the commits
+func (sf *factory) Backup(ctx context.Context, dir string) error {
+	return backup(ctx, sf.dao, dir)
+}
+
+// StateDiff returns the changes of the accounts and the contract storage made by the block at height
+func (sf *factory) StateDiff(height uint64) (*StateDiff, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	return readStateDiff(sf.dao, height, sf.cfg.Chain.StateDiffRetention)
+}
+
 func (sf *factory) newWorkingSet(ctx context.Context, height uint64) (*workingSet, error) {
-	flusher, err := db.NewKVStoreFlusher(sf.dao, batch.NewCachedBatch(), sf.flusherOptions(ctx, height)...)
+	return sf.newWorkingSetWithRoot(ctx, height, ArchiveTrieRootKey, true)
+}
+
+// newWorkingSetAtHeight creates a working set on top of the archived state at height, it must not be committed
+func (sf *factory) newWorkingSetAtHeight(ctx context.Context, height uint64) (*workingSet, error) {
+	return sf.newWorkingSetWithRoot(ctx, height+1, fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height), false)
+}
+
+func (sf *factory) newWorkingSetWithRoot(ctx context.Context, height uint64, rootKey string, create bool) (*workingSet, error) {
+	buffers := getWorkingSetBuffers()
+	flusher, err := db.NewKVStoreFlusher(
+		newDiffStore(
+			newPruneStore(newUndoStore(sf.dao, height, sf.cfg.Chain.MaxReorgDepth), height, sf.cfg.Chain.StateRetention),
+			height,
+			sf.cfg.Chain.StateDiffRetention,
+			buffers.storageDiffs,
+		),
+		buffers.batch,
+		sf.flusherOptions(ctx, height)...,
+	)
 	if err != nil {
+		putWorkingSetBuffers(buffers)
 		return nil, err
 	}
-	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, flusher.KVStoreWithBuffer(), ArchiveTrieRootKey, true)
+	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, flusher.KVStoreWithBuffer(), rootKey, create)
 	if err != nil {
+		putWorkingSetBuffers(buffers)
 		return nil, err
 	}
 	if err := tlt.Start(ctx); err != nil {
+		putWorkingSetBuffers(buffers)
 		return nil, err
 	}
 	finalized := false
 	trieRoots := make(map[int][]byte)
 
 	return &workingSet{
-		height:    height,
-		finalized: false,
-		dock:      protocol.NewDock(),
+		height:       height,
+		finalized:    false,
+		dock:         buffers.dock,
+		storageDiffs: buffers.storageDiffs,
+		buffers:      buffers,
 		getStateFunc: func(ns string, key []byte, s interface{}) error {
 			return readState(tlt, ns, key, s)
 		},
@@ -301,6 +363,10 @@ func (sf *factory) newWorkingSet(ctx context.Context, height uint64) (*workingSe
 				return errors.Wrapf(err, "failed to convert account %v to bytes", s)
 			}
 			flusher.KVStoreWithBuffer().MustPut(ns, key, ss)
+			if ns == AccountKVNamespace {
+				putAccountPreimage(flusher.KVStoreWithBuffer(), key)
+			}
+			putStatePreimage(flusher.KVStoreWithBuffer(), ns, key)
 			nsHash := hash.Hash160b([]byte(ns))
 
 			return tlt.Upsert(nsHash[:], toLegacyKey(key), ss)
@@ -393,7 +459,8 @@ func (sf *factory) flusherOptions(ctx context.Context, height uint64) []db.KVSto
 	preEaster := hu.IsPre(config.Easter, height)
 	opts := []db.KVStoreFlusherOption{
 		db.SerializeFilterOption(func(wi *batch.WriteInfo) bool {
-			if wi.Namespace() == ArchiveTrieNamespace {
+			switch wi.Namespace() {
+			case ArchiveTrieNamespace, AccountPreimageNamespace, StatePreimageNamespace:
 				return true
 			}
 			if wi.Namespace() != evm.CodeKVNameSpace {
@@ -483,7 +550,7 @@ func (sf *factory) NewBlockBuilder(
 			}
 		}
 	}
-	blkBuilder, err := ws.CreateBuilder(ctx, ap, postSystemActions, sf.cfg.Chain.AllowedBlockGasResidue)
+	blkBuilder, err := ws.CreateBuilder(ctx, ap, postSystemActions, sf.cfg.Chain.AllowedBlockGasResidue, sf.cfg.Chain.BlockComposition)
 	if err != nil {
 		return nil, err
 	}
@@ -508,6 +575,80 @@ func (sf *factory) SimulateExecution(
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to obtain working set from state factory")
 	}
+	defer ws.release()
+
+	return evm.SimulateExecution(ctx, ws, caller, ex, getBlockHash)
+}
+
+// SimulateExecutions simulates the executions one by one on top of the same state, each of them reverted before the
+// next one, so that none of them sees the changes of another, nor the blocks committed in the meantime
+func (sf *factory) SimulateExecutions(
+	ctx context.Context,
+	simulations []*Simulation,
+	getBlockHash evm.GetBlockHash,
+) ([]*SimulationResult, uint64, error) {
+	sf.mutex.Lock()
+	height := sf.currentChainHeight
+	ws, err := sf.newWorkingSet(ctx, height+1)
+	sf.mutex.Unlock()
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to obtain working set from state factory")
+	}
+	defer ws.release()
+	results, err := simulateExecutions(ctx, ws, simulations, getBlockHash)
+	return results, height, err
+}
+
+// SimulateAction runs the pending actions, and then the action, on top of the latest state. The pending actions failing
+// to run are skipped, and none of the changes is committed.
+func (sf *factory) SimulateAction(
+	ctx context.Context,
+	pending []action.SealedEnvelope,
+	selp action.SealedEnvelope,
+) (*action.Receipt, error) {
+	ps, err := sf.PendingState(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	return ps.simulateAction(selp)
+}
+
+// PendingState returns the state after running the pending actions on top of the latest state
+func (sf *factory) PendingState(ctx context.Context, pending []action.SealedEnvelope) (*PendingState, error) {
+	sf.mutex.Lock()
+	ctx = protocol.WithRegistry(ctx, sf.registry)
+	ws, err := sf.newWorkingSet(ctx, sf.currentChainHeight+1)
+	sf.mutex.Unlock()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain working set from state factory")
+	}
+	return newPendingState(ctx, ws, pending)
+}
+
+// SimulateExecutionAtHeight simulates a running of smart contract operation on top of the state at height -- archive
+// mode
+func (sf *factory) SimulateExecutionAtHeight(
+	ctx context.Context,
+	height uint64,
+	caller address.Address,
+	ex *action.Execution,
+	getBlockHash evm.GetBlockHash,
+) ([]byte, *action.Receipt, error) {
+	sf.mutex.Lock()
+	if height > sf.currentChainHeight {
+		sf.mutex.Unlock()
+		return nil, nil, errors.Errorf("query height %d is higher than tip height %d", height, sf.currentChainHeight)
+	}
+	if !sf.historyAvailable(height) {
+		sf.mutex.Unlock()
+		return nil, nil, ErrNoArchiveData
+	}
+	ws, err := sf.newWorkingSetAtHeight(ctx, height)
+	sf.mutex.Unlock()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to obtain working set at height %d from state factory", height)
+	}
+	defer ws.release()
 
 	return evm.SimulateExecution(ctx, ws, caller, ex, getBlockHash)
 }
@@ -528,7 +669,7 @@ func (sf *factory) PutBlock(ctx context.Context, blk *block.Block) error {
 		protocol.BlockCtx{
 			BlockHeight:    blk.Height(),
 			BlockTimeStamp: blk.Timestamp(),
-			GasLimit:       bcCtx.Genesis.BlockGasLimit,
+			GasLimit:       bcCtx.Genesis.BlockGasLimitByHeight(blk.Height()),
 			Producer:       producer,
 		},
 	)
@@ -564,12 +705,52 @@ func (sf *factory) PutBlock(ctx context.Context, blk *block.Block) error {
 			sf.currentChainHeight, h,
 		)
 	}
+	if err := ws.Commit(ctx); err != nil {
+		return err
+	}
+	// the working set of the committed block is never used again, its buffers are reused by the next blocks
+	sf.workingsets.Remove(key)
+	ws.release()
+	return nil
+}
 
-	return ws.Commit(ctx)
+// DeleteTipBlock reverts the state to the height before the tip block with its undo log
+func (sf *factory) DeleteTipBlock(blk *block.Block) error {
+	if sf.cfg.Chain.MaxReorgDepth == 0 {
+		return errors.Wrap(ErrNotSupported, "cannot delete tip block from factory")
+	}
+	if err := sf.revertTipBlock(blk.Height()); err != nil {
+		return err
+	}
+	view, err := restartProtocols(sf.cfg, sf.registry, sf)
+	if err != nil {
+		return err
+	}
+	sf.mutex.Lock()
+	sf.protocolView = view
+	sf.mutex.Unlock()
+	return nil
 }
 
-func (sf *factory) DeleteTipBlock(_ *block.Block) error {
-	return errors.Wrap(ErrNotSupported, "cannot delete tip block from factory")
+func (sf *factory) revertTipBlock(height uint64) error {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+	if height != sf.currentChainHeight {
+		return errors.Errorf("block %d is not the tip %d of factory", height, sf.currentChainHeight)
+	}
+	if err := revertTipBlock(sf.dao, height); err != nil {
+		return err
+	}
+	rootHash, err := sf.dao.Get(ArchiveTrieNamespace, []byte(ArchiveTrieRootKey))
+	if err != nil {
+		return err
+	}
+	if err := sf.twoLayerTrie.SetRootHash(rootHash); err != nil {
+		return err
+	}
+	sf.currentChainHeight = height - 1
+	sf.workingsets.Clear()
+	return nil
 }
 
 // StateAtHeight returns a confirmed state at height -- archive mode
@@ -678,8 +859,18 @@ func toLegacyKey(input []byte) []byte {
 	return key[:]
 }
 
+// historyAvailable returns whether the state trie at the height is kept, which is either in archive mode or within the
+// state retention
+func (sf *factory) historyAvailable(height uint64) bool {
+	if sf.saveHistory {
+		return true
+	}
+	retention := sf.cfg.Chain.StateRetention
+	return retention > 0 && height+retention >= sf.currentChainHeight
+}
+
 func (sf *factory) stateAtHeight(height uint64, ns string, key []byte, s interface{}) error {
-	if !sf.saveHistory {
+	if !sf.historyAvailable(height) {
 		return ErrNoArchiveData
 	}
 	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, sf.dao, fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height), false)
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"io/ioutil"
 	"math/big"
 	"math/rand"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-election/test/mock/mock_committee"
 	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
@@ -35,6 +37,7 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
 	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
+	"github.com/iotexproject/iotex-core/actpool/actioniterator"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/config"
@@ -824,6 +827,68 @@ func TestSDBLoadStoreHeightInMem(t *testing.T) {
 	testLoadStoreHeight(db, t)
 }
 
+func TestBackup(t *testing.T) {
+	require := require.New(t)
+	ctx := protocol.WithBlockchainCtx(
+		context.Background(),
+		protocol.BlockchainCtx{
+			Genesis: config.Default.Genesis,
+		},
+	)
+	putBlock := func(sf Factory, height uint64) {
+		ctx := protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+			BlockHeight: height,
+			Producer:    identityset.Address(27),
+			GasLimit:    testutil.TestGasLimit,
+		})
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(testutil.TimestampNow()).
+			SignAndBuild(identityset.PrivateKey(27))
+		require.NoError(err)
+		require.NoError(sf.PutBlock(ctx, &blk))
+	}
+	for name, create := range map[string]func(config.Config) (Factory, error){
+		"factory": func(cfg config.Config) (Factory, error) {
+			return NewFactory(cfg, DefaultTrieOption(), SkipBlockValidationOption())
+		},
+		"statedb": func(cfg config.Config) (Factory, error) {
+			return NewStateDB(cfg, CachedStateDBOption(), SkipBlockValidationStateDBOption())
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "test-backup")
+			require.NoError(err)
+			defer testutil.CleanupPath(t, dir)
+			backupDir := filepath.Join(dir, "backup")
+			require.NoError(os.Mkdir(backupDir, 0700))
+
+			cfg := config.Default
+			cfg.Chain.TrieDBPath = filepath.Join(dir, "trie.db")
+			sf, err := create(cfg)
+			require.NoError(err)
+			require.NoError(sf.Start(ctx))
+			putBlock(sf, 1)
+			require.NoError(sf.(db.Backuper).Backup(ctx, backupDir))
+			putBlock(sf, 2)
+			require.NoError(sf.Stop(ctx))
+
+			cfg.Chain.TrieDBPath = filepath.Join(backupDir, "trie.db")
+			sf, err = create(cfg)
+			require.NoError(err)
+			require.NoError(sf.Start(ctx))
+			height, err := sf.Height()
+			require.NoError(err)
+			require.EqualValues(1, height)
+			require.NoError(sf.Stop(ctx))
+		})
+	}
+
+	sf, err := NewStateDB(config.Default, InMemStateDBOption())
+	require.NoError(err)
+	require.Equal(ErrNotSupported, errors.Cause(sf.(db.Backuper).Backup(ctx, os.TempDir())))
+}
+
 func testLoadStoreHeight(sf Factory, t *testing.T) {
 	require := require.New(t)
 	ctx := protocol.WithBlockchainCtx(
@@ -1063,7 +1128,11 @@ func testNewBlockBuilder(factory Factory, t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	ap := mock_actpool.NewMockActPool(ctrl)
-	ap.EXPECT().PendingActionMap().Return(accMap).Times(1)
+	ap.EXPECT().IsLocal(gomock.Any()).Return(false).AnyTimes()
+	ap.EXPECT().PendingActionIterator(gomock.Any()).DoAndReturn(
+		func(opts ...actioniterator.Option) (actioniterator.ActionIterator, uint64) {
+			return actioniterator.NewActionIterator(accMap, opts...), 1
+		}).Times(1)
 	gasLimit := uint64(1000000)
 	ctx := protocol.WithBlockCtx(context.Background(),
 		protocol.BlockCtx{
@@ -1111,6 +1180,54 @@ func TestSimulateExecution(t *testing.T) {
 		require.NoError(sf.Stop(ctx))
 	}()
 	testSimulateExecution(ctx, sf, t)
+	_, _, err = sf.SimulateExecutionAtHeight(ctx, 0, identityset.Address(28), &action.Execution{}, nil)
+	require.Equal(ErrNoArchiveData, errors.Cause(err))
+}
+
+func TestSimulateExecutionAtHeight(t *testing.T) {
+	require := require.New(t)
+	testTriePath, err := testutil.PathOfTempFile(triePath)
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testTriePath)
+
+	cfg := config.Default
+	cfg.DB.DbPath = testTriePath
+	cfg.Chain.EnableArchiveMode = true
+	cfg.Genesis.InitBalanceMap[identityset.Address(28).String()] = "100"
+	registry := protocol.NewRegistry()
+	sf, err := NewFactory(cfg, PrecreatedTrieDBOption(db.NewBoltDB(cfg.DB)), RegistryOption(registry))
+	require.NoError(err)
+
+	acc := account.NewProtocol(rewarding.DepositGas)
+	require.NoError(acc.Register(registry))
+	ctx := protocol.WithBlockCtx(
+		protocol.WithBlockchainCtx(
+			context.Background(),
+			protocol.BlockchainCtx{Genesis: cfg.Genesis},
+		),
+		protocol.BlockCtx{},
+	)
+	require.NoError(sf.Start(ctx))
+	defer func() {
+		require.NoError(sf.Stop(ctx))
+	}()
+
+	data, _ := hex.DecodeString("608060405234801561001057600080fd5b5060df8061001f6000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a7230582002faabbefbbda99b20217cf33cb8ab8100caf1542bf1f48117d72e2c59139aea0029")
+	ex, err := action.NewExecution(action.EmptyAddress, 1, big.NewInt(0), uint64(100000), big.NewInt(0), data)
+	require.NoError(err)
+	getBlockHash := func(uint64) (hash.Hash256, error) {
+		return hash.ZeroHash256, nil
+	}
+	_, receipt, err := sf.SimulateExecutionAtHeight(ctx, 0, identityset.Address(28), ex, getBlockHash)
+	require.NoError(err)
+	require.Equal(uint64(iotextypes.ReceiptStatus_Success), receipt.Status)
+	_, _, err = sf.SimulateExecutionAtHeight(ctx, 1, identityset.Address(28), ex, getBlockHash)
+	require.Error(err)
+
+	// the simulation is not committed
+	a, err := accountutil.AccountState(sf, identityset.Address(28).String())
+	require.NoError(err)
+	require.Zero(a.Nonce)
 }
 
 func TestSTXSimulateExecution(t *testing.T) {
@@ -1140,6 +1257,8 @@ func TestSTXSimulateExecution(t *testing.T) {
 		require.NoError(sdb.Stop(ctx))
 	}()
 	testSimulateExecution(ctx, sdb, t)
+	_, _, err = sdb.SimulateExecutionAtHeight(ctx, 0, identityset.Address(28), &action.Execution{}, nil)
+	require.Equal(ErrNotSupported, errors.Cause(err))
 }
 
 func testSimulateExecution(ctx context.Context, sf Factory, t *testing.T) {
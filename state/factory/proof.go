@@ -0,0 +1,146 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+)
+
+type (
+	// Prover generates the merkle proofs of the states against the state root. The state trie is a two layer trie,
+	// the first layer maps the hash of each namespace to the root of the trie of the namespace, which maps the hash of
+	// each key to the serialized state.
+	Prover interface {
+		// ProofAtHeight returns the proof of the state of the namespace and key at height
+		ProofAtHeight(uint64, ...protocol.StateOption) (*StateProof, error)
+		// TrieProofAtHeight returns the proof of a key in a trie of the root, whose nodes are stored as the states of
+		// the namespace at height, such as the storage trie of a contract
+		TrieProofAtHeight(height uint64, ns string, root []byte, key []byte, opts ...mptrie.Option) ([][]byte, error)
+	}
+
+	// StateProof is the merkle proof of a state
+	StateProof struct {
+		// StateRoot is the root of the state trie
+		StateRoot []byte
+		// NamespaceKey and NamespaceProof prove the root of the namespace trie in the state trie
+		NamespaceKey   []byte
+		NamespaceProof [][]byte
+		// Key and KeyProof prove the state, or its absence, in the namespace trie
+		Key      []byte
+		KeyProof [][]byte
+	}
+
+	// archiveKVStore reads the states of a namespace in the archived state trie as a kv store
+	archiveKVStore struct {
+		tlt trie.TwoLayerTrie
+		ns  []byte
+	}
+)
+
+// ProofAtHeight returns the proof of the state of the namespace and key at height, the states below the tip height
+// are only available in archive mode
+func (sf *factory) ProofAtHeight(height uint64, opts ...protocol.StateOption) (*StateProof, error) {
+	cfg, err := processOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	tlt, err := sf.trieAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	defer tlt.Stop(context.Background())
+
+	ret := &StateProof{
+		NamespaceKey: namespaceKey(cfg.Namespace),
+		Key:          toLegacyKey(cfg.Key),
+	}
+	if ret.StateRoot, err = tlt.RootHash(); err != nil {
+		return nil, err
+	}
+	if ret.NamespaceProof, ret.KeyProof, err = tlt.Proof(ret.NamespaceKey, ret.Key); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// TrieProofAtHeight returns the proof of a key in a trie whose nodes are stored as the states of the namespace
+func (sf *factory) TrieProofAtHeight(height uint64, ns string, root []byte, key []byte, opts ...mptrie.Option) ([][]byte, error) {
+	tlt, err := sf.trieAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	defer tlt.Stop(context.Background())
+
+	opts = append(opts,
+		mptrie.KVStoreOption(&archiveKVStore{tlt: tlt, ns: namespaceKey(ns)}),
+		mptrie.KeyLengthOption(len(key)),
+		mptrie.RootHashOption(root),
+	)
+	tr, err := mptrie.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	defer tr.Stop(context.Background())
+
+	return tr.Proof(key)
+}
+
+// trieAtHeight returns the state trie at height, which is read only
+func (sf *factory) trieAtHeight(height uint64) (trie.TwoLayerTrie, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	if err := sf.checkArchivedHeight(height); err != nil {
+		return nil, err
+	}
+	rootKey := ArchiveTrieRootKey
+	if height < sf.currentChainHeight {
+		rootKey = fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height)
+	}
+	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, sf.dao, rootKey, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate trie for %d", height)
+	}
+	if err := tlt.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	return tlt, nil
+}
+
+func (kv *archiveKVStore) Start(context.Context) error {
+	return nil
+}
+
+func (kv *archiveKVStore) Stop(context.Context) error {
+	return nil
+}
+
+func (kv *archiveKVStore) Put([]byte, []byte) error {
+	return errors.Wrap(ErrNotSupported, "archived state is read only")
+}
+
+func (kv *archiveKVStore) Delete([]byte) error {
+	return errors.Wrap(ErrNotSupported, "archived state is read only")
+}
+
+func (kv *archiveKVStore) Get(key []byte) ([]byte, error) {
+	value, err := kv.tlt.Get(kv.ns, toLegacyKey(key))
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil, errors.Wrapf(db.ErrNotExist, "failed to find key %x", key)
+	}
+	return value, err
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	// StaleTrieNodeNamespace is the bucket of the height at which a trie node became stale
+	StaleTrieNodeNamespace = "StaleTrieNode"
+	// StaleTrieNodesNamespace is the bucket of the trie nodes which became stale at a height
+	StaleTrieNodesNamespace = "StaleTrieNodes"
+)
+
+// pruneStore keeps the trie nodes replaced by the batch of a block instead of deleting them, and marks them with the
+// height as their generation. The nodes of the generation out of the retention window are deleted, unless they have
+// been put back into the trie since then, so the state tries of the latest retention heights are kept readable while
+// the trie db no longer grows with the history.
+type pruneStore struct {
+	db.KVStore
+	height    uint64
+	retention uint64
+}
+
+// newPruneStore returns the store to flush the working set at height into, which keeps the stale trie nodes of the
+// latest retention blocks
+func newPruneStore(kv db.KVStore, height, retention uint64) db.KVStore {
+	if retention == 0 {
+		return kv
+	}
+	return &pruneStore{KVStore: kv, height: height, retention: retention}
+}
+
+func (s *pruneStore) WriteBatch(b batch.KVStoreBatch) error {
+	var (
+		keys  []string
+		stale = make(map[string]bool)
+	)
+	pb := b.Translate(func(wi *batch.WriteInfo) *batch.WriteInfo {
+		if wi.Namespace() != ArchiveTrieNamespace {
+			return wi
+		}
+		key := string(wi.Key())
+		if _, ok := stale[key]; !ok {
+			keys = append(keys, key)
+		}
+		stale[key] = wi.WriteType() == batch.Delete
+		if stale[key] {
+			return nil
+		}
+		return wi
+	})
+	heightKey := byteutil.Uint64ToBytesBigEndian(s.height)
+	var nodes bytes.Buffer
+	for _, key := range keys {
+		if stale[key] {
+			pb.Put(StaleTrieNodeNamespace, []byte(key), heightKey, "failed to mark stale trie node %x", key)
+			writeBytes(&nodes, []byte(key))
+			continue
+		}
+		// the node is put back into the trie
+		_, err := s.KVStore.Get(StaleTrieNodeNamespace, []byte(key))
+		switch errors.Cause(err) {
+		case nil:
+			pb.Delete(StaleTrieNodeNamespace, []byte(key), "failed to unmark stale trie node %x", key)
+		case db.ErrNotExist, db.ErrBucketNotExist:
+		default:
+			return errors.Wrapf(err, "failed to read stale trie node %x", key)
+		}
+	}
+	if nodes.Len() > 0 {
+		pb.Put(StaleTrieNodesNamespace, heightKey, nodes.Bytes(), "failed to put stale trie nodes of height %d", s.height)
+	}
+	if s.height > s.retention {
+		if err := s.prune(pb, s.height-s.retention, stale); err != nil {
+			return err
+		}
+	}
+	return s.KVStore.WriteBatch(pb)
+}
+
+// prune deletes the trie nodes became stale at height and are still stale, along with the root of the state trie at
+// the height before, which relies on them
+func (s *pruneStore) prune(b batch.KVStoreBatch, height uint64, written map[string]bool) error {
+	heightKey := byteutil.Uint64ToBytesBigEndian(height)
+	rootKey := []byte(fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height-1))
+	b.Delete(ArchiveTrieNamespace, rootKey, "failed to delete trie root of height %d", height-1)
+	value, err := s.KVStore.Get(StaleTrieNodesNamespace, heightKey)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return nil
+	default:
+		return errors.Wrapf(err, "failed to get stale trie nodes of height %d", height)
+	}
+	r := bytes.NewReader(value)
+	for r.Len() > 0 {
+		key, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		if _, ok := written[string(key)]; ok {
+			// the node is marked again or put back by the batch
+			continue
+		}
+		generation, err := s.KVStore.Get(StaleTrieNodeNamespace, key)
+		switch errors.Cause(err) {
+		case nil:
+		case db.ErrNotExist:
+			continue
+		default:
+			return errors.Wrapf(err, "failed to read stale trie node %x", key)
+		}
+		if !bytes.Equal(generation, heightKey) {
+			continue
+		}
+		b.Delete(ArchiveTrieNamespace, key, "failed to prune trie node %x", key)
+		b.Delete(StaleTrieNodeNamespace, key, "failed to unmark stale trie node %x", key)
+	}
+	b.Delete(StaleTrieNodesNamespace, heightKey, "failed to delete stale trie nodes of height %d", height)
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestPruneStore(t *testing.T) {
+	r := require.New(t)
+	kv := db.NewMemKVStore()
+	r.NoError(kv.Start(context.Background()))
+
+	writeBlock := func(height uint64, writes func(b batch.KVStoreBatch)) {
+		b := batch.NewBatch()
+		writes(b)
+		r.NoError(newPruneStore(kv, height, 2).WriteBatch(b))
+	}
+	exist := func(key string) bool {
+		_, err := kv.Get(ArchiveTrieNamespace, []byte(key))
+		if errors.Cause(err) == db.ErrNotExist {
+			return false
+		}
+		r.NoError(err)
+		return true
+	}
+	writeBlock(1, func(b batch.KVStoreBatch) {
+		b.Put(ArchiveTrieNamespace, []byte("a"), []byte("a"), "")
+		b.Put(ArchiveTrieNamespace, []byte("b"), []byte("b"), "")
+		b.Put(ArchiveTrieNamespace, []byte("archiveTrieRoot-0"), []byte("r0"), "")
+		b.Put(ArchiveTrieNamespace, []byte("archiveTrieRoot-1"), []byte("r1"), "")
+	})
+	writeBlock(2, func(b batch.KVStoreBatch) {
+		b.Delete(ArchiveTrieNamespace, []byte("a"), "")
+		b.Put(ArchiveTrieNamespace, []byte("c"), []byte("c"), "")
+		// replaced and put back in the same block
+		b.Delete(ArchiveTrieNamespace, []byte("b"), "")
+		b.Put(ArchiveTrieNamespace, []byte("b"), []byte("b"), "")
+	})
+	// the stale nodes are kept within the retention
+	r.True(exist("a"))
+	r.True(exist("b"))
+	writeBlock(3, func(b batch.KVStoreBatch) {
+		b.Delete(ArchiveTrieNamespace, []byte("b"), "")
+		b.Put(ArchiveTrieNamespace, []byte("a"), []byte("a"), "")
+	})
+	writeBlock(4, func(b batch.KVStoreBatch) {
+		b.Delete(ArchiveTrieNamespace, []byte("c"), "")
+	})
+	// a became stale at 2, but is put back at 3
+	r.True(exist("a"))
+	// the states of the heights before 2 are pruned
+	r.False(exist("archiveTrieRoot-0"))
+	r.False(exist("archiveTrieRoot-1"))
+	writeBlock(5, func(b batch.KVStoreBatch) {})
+	r.False(exist("b"))
+	r.True(exist("c"))
+	writeBlock(6, func(b batch.KVStoreBatch) {})
+	r.False(exist("c"))
+	r.True(exist("a"))
+	for h := uint64(2); h <= 4; h++ {
+		_, err := kv.Get(StaleTrieNodesNamespace, []byte{0, 0, 0, 0, 0, 0, 0, byte(h)})
+		r.Equal(db.ErrNotExist, errors.Cause(err))
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		_, err := kv.Get(StaleTrieNodeNamespace, []byte(key))
+		r.Equal(db.ErrNotExist, errors.Cause(err))
+	}
+}
+
+func TestFactoryStateRetention(t *testing.T) {
+	r := require.New(t)
+	cfg := config.Default
+	cfg.Chain.StateRetention = 2
+	sf, ctx := startTestFactory(t, cfg, 5)
+	defer func() {
+		r.NoError(sf.Stop(ctx))
+	}()
+
+	b := identityset.Address(31).String()
+	for h := uint64(3); h <= 5; h++ {
+		acct, err := accountutil.AccountState(NewHistoryStateReader(sf, h), b)
+		r.NoError(err)
+		r.Equal(big.NewInt(int64(10*h)), acct.Balance)
+	}
+	for h := uint64(0); h < 3; h++ {
+		_, err := accountutil.AccountState(NewHistoryStateReader(sf, h), b)
+		r.Equal(ErrNoArchiveData, errors.Cause(err))
+	}
+}
+
+// startTestFactory starts a trie state factory in memory, and puts the blocks each transferring 10 from identity 28 to
+// identity 31
+func startTestFactory(t *testing.T, cfg config.Config, blocks uint64) (Factory, context.Context) {
+	r := require.New(t)
+	sf, err := NewFactory(cfg, InMemTrieOption(), SkipBlockValidationOption())
+	r.NoError(err)
+	r.NoError(sf.Register(account.NewProtocol(rewarding.DepositGas)))
+
+	a := identityset.Address(28).String()
+	b := identityset.Address(31).String()
+	ge := config.Default.Genesis
+	ge.InitBalanceMap = map[string]string{a: "100"}
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: ge})
+	r.NoError(sf.Start(protocol.WithBlockCtx(ctx, protocol.BlockCtx{})))
+
+	for h := uint64(1); h <= blocks; h++ {
+		selp, err := testutil.SignedTransfer(b, identityset.PrivateKey(28), h, big.NewInt(10), nil, 20000, big.NewInt(0))
+		r.NoError(err)
+		blk, err := block.NewTestingBuilder().
+			SetHeight(h).
+			SetPrevBlockHash(hash.ZeroHash256).
+			SetTimeStamp(testutil.TimestampNow()).
+			AddActions([]action.SealedEnvelope{selp}...).
+			SignAndBuild(identityset.PrivateKey(27))
+		r.NoError(err)
+		r.NoError(sf.PutBlock(protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+			BlockHeight: h,
+			Producer:    identityset.Address(27),
+			GasLimit:    1000000,
+		}), &blk))
+	}
+	return sf, ctx
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+type (
+	// Simulation is an execution to simulate
+	Simulation struct {
+		Caller    address.Address
+		Execution *action.Execution
+	}
+
+	// SimulationResult is the result of a simulated execution, Err is set if the execution failed to run
+	SimulationResult struct {
+		Output  []byte
+		Receipt *action.Receipt
+		Err     error
+	}
+
+	// PendingState is the state after the pending actions on top of the latest state, which is never committed. It
+	// reads as a protocol.StateReader, so that the actions waiting in the actpool are visible to the queries.
+	PendingState struct {
+		ws *workingSet
+		// ctx carries the context of the block the pending actions are run in
+		ctx context.Context
+	}
+)
+
+func simulateExecutions(
+	ctx context.Context,
+	ws *workingSet,
+	simulations []*Simulation,
+	getBlockHash evm.GetBlockHash,
+) ([]*SimulationResult, error) {
+	results := make([]*SimulationResult, 0, len(simulations))
+	for _, sim := range simulations {
+		snapshot := ws.Snapshot()
+		res := &SimulationResult{}
+		res.Output, res.Receipt, res.Err = evm.SimulateExecution(ctx, ws, sim.Caller, sim.Execution, getBlockHash)
+		if err := ws.Revert(snapshot); err != nil {
+			return nil, errors.Wrap(err, "failed to revert the simulated execution")
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// newPendingState runs the pending actions in the block following the tip of the blockchain context, the actions
+// failing to run are skipped
+func newPendingState(ctx context.Context, ws *workingSet, pending []action.SealedEnvelope) (*PendingState, error) {
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	zeroAddr, err := address.FromString(address.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+		BlockHeight:    bcCtx.Tip.Height + 1,
+		BlockTimeStamp: bcCtx.Tip.Timestamp.Add(bcCtx.Genesis.BlockIntervalByHeight(bcCtx.Tip.Height + 1)),
+		GasLimit:       bcCtx.Genesis.BlockGasLimitByHeight(bcCtx.Tip.Height + 1),
+		Producer:       zeroAddr,
+	})
+	for _, p := range pending {
+		snapshot := ws.Snapshot()
+		if _, err := ws.simulate(ctx, p); err != nil {
+			if err := ws.Revert(snapshot); err != nil {
+				return nil, errors.Wrap(err, "failed to revert the pending action")
+			}
+		}
+	}
+	return &PendingState{ws: ws, ctx: ctx}, nil
+}
+
+// Height returns the height of the block the pending actions are run in
+func (ps *PendingState) Height() (uint64, error) {
+	return ps.ws.Height()
+}
+
+// State reads the state after the pending actions
+func (ps *PendingState) State(s interface{}, opts ...protocol.StateOption) (uint64, error) {
+	return ps.ws.State(s, opts...)
+}
+
+// States reads the states after the pending actions
+func (ps *PendingState) States(opts ...protocol.StateOption) (uint64, state.Iterator, error) {
+	return ps.ws.States(opts...)
+}
+
+// ReadView reads the view of the protocol
+func (ps *PendingState) ReadView(name string) (interface{}, error) {
+	return ps.ws.ReadView(name)
+}
+
+// SimulateExecution simulates the execution on top of the pending state, the changes of which are reverted
+func (ps *PendingState) SimulateExecution(
+	ctx context.Context,
+	caller address.Address,
+	ex *action.Execution,
+	getBlockHash evm.GetBlockHash,
+) ([]byte, *action.Receipt, error) {
+	snapshot := ps.ws.Snapshot()
+	retval, receipt, err := evm.SimulateExecution(ctx, ps.ws, caller, ex, getBlockHash)
+	if revertErr := ps.ws.Revert(snapshot); revertErr != nil {
+		return nil, nil, errors.Wrap(revertErr, "failed to revert the simulated execution")
+	}
+	return retval, receipt, err
+}
+
+// simulateAction runs the action on top of the pending state, the changes of which are reverted
+func (ps *PendingState) simulateAction(selp action.SealedEnvelope) (*action.Receipt, error) {
+	snapshot := ps.ws.Snapshot()
+	receipt, err := ps.ws.simulate(ps.ctx, selp)
+	if revertErr := ps.ws.Revert(snapshot); revertErr != nil {
+		return nil, errors.Wrap(revertErr, "failed to revert the simulated action")
+	}
+	return receipt, err
+}
@@ -69,7 +69,7 @@ func DefaultStateDBOption() StateDBOption {
 			return errors.New("Invalid empty trie db path")
 		}
 		cfg.DB.DbPath = dbPath // TODO: remove this after moving TrieDBPath from cfg.Chain to cfg.DB
-		sdb.dao = db.NewBoltDB(cfg.DB)
+		sdb.dao = db.NewKVStoreWithCoalescing(db.NewBoltDB(cfg.DB), cfg.DB.Flush)
 
 		return nil
 	}
@@ -83,7 +83,7 @@ func CachedStateDBOption() StateDBOption {
 			return errors.New("Invalid empty trie db path")
 		}
 		cfg.DB.DbPath = dbPath // TODO: remove this after moving TrieDBPath from cfg.Chain to cfg.DB
-		sdb.dao = db.NewKvStoreWithCache(db.NewBoltDB(cfg.DB), cfg.Chain.StateDBCacheSize)
+		sdb.dao = db.NewKvStoreWithCache(db.NewKVStoreWithCoalescing(db.NewBoltDB(cfg.DB), cfg.DB.Flush), cfg.Chain.StateDBCacheSize)
 
 		return nil
 	}
@@ -200,16 +200,43 @@ func (sdb *stateDB) Height() (uint64, error) {
 	return byteutil.BytesToUint64(height), nil
 }
 
+// Checkpoint returns a consistent view of the underlying DB, which is used to export the state
+func (sdb *stateDB) Checkpoint() (*db.Checkpoint, error) {
+	sdb.mutex.RLock()
+	defer sdb.mutex.RUnlock()
+	return checkpoint(sdb.dao)
+}
+
+// Backup writes a consistent copy of the underlying DB into the directory, which doesn't block the commits
+func (sdb *stateDB) Backup(ctx context.Context, dir string) error {
+	return backup(ctx, sdb.dao, dir)
+}
+
+// StateDiff returns the changes of the accounts and the contract storage made by the block at height
+func (sdb *stateDB) StateDiff(height uint64) (*StateDiff, error) {
+	sdb.mutex.RLock()
+	defer sdb.mutex.RUnlock()
+	return readStateDiff(sdb.dao, height, sdb.cfg.Chain.StateDiffRetention)
+}
+
 func (sdb *stateDB) newWorkingSet(ctx context.Context, height uint64) (*workingSet, error) {
-	flusher, err := db.NewKVStoreFlusher(sdb.dao, batch.NewCachedBatch(), sdb.flusherOptions(ctx, height)...)
+	buffers := getWorkingSetBuffers()
+	flusher, err := db.NewKVStoreFlusher(
+		newDiffStore(newUndoStore(sdb.dao, height, sdb.cfg.Chain.MaxReorgDepth), height, sdb.cfg.Chain.StateDiffRetention, buffers.storageDiffs),
+		buffers.batch,
+		sdb.flusherOptions(ctx, height)...,
+	)
 	if err != nil {
+		putWorkingSetBuffers(buffers)
 		return nil, err
 	}
 
 	return &workingSet{
-		height:    height,
-		finalized: false,
-		dock:      protocol.NewDock(),
+		height:       height,
+		finalized:    false,
+		dock:         buffers.dock,
+		storageDiffs: buffers.storageDiffs,
+		buffers:      buffers,
 		getStateFunc: func(ns string, key []byte, s interface{}) error {
 			data, err := flusher.KVStoreWithBuffer().Get(ns, key)
 			if err != nil {
@@ -322,7 +349,7 @@ func (sdb *stateDB) NewBlockBuilder(
 			}
 		}
 	}
-	blkBuilder, err := ws.CreateBuilder(ctx, ap, postSystemActions, sdb.cfg.Chain.AllowedBlockGasResidue)
+	blkBuilder, err := ws.CreateBuilder(ctx, ap, postSystemActions, sdb.cfg.Chain.AllowedBlockGasResidue, sdb.cfg.Chain.BlockComposition)
 	if err != nil {
 		return nil, err
 	}
@@ -347,10 +374,68 @@ func (sdb *stateDB) SimulateExecution(
 	if err != nil {
 		return nil, nil, err
 	}
+	defer ws.release()
 
 	return evm.SimulateExecution(ctx, ws, caller, ex, getBlockHash)
 }
 
+// SimulateExecutions simulates the executions one by one on top of the same state, each of them reverted before the
+// next one
+func (sdb *stateDB) SimulateExecutions(
+	ctx context.Context,
+	simulations []*Simulation,
+	getBlockHash evm.GetBlockHash,
+) ([]*SimulationResult, uint64, error) {
+	sdb.mutex.Lock()
+	height := sdb.currentChainHeight
+	ws, err := sdb.newWorkingSet(ctx, height+1)
+	sdb.mutex.Unlock()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer ws.release()
+	results, err := simulateExecutions(ctx, ws, simulations, getBlockHash)
+	return results, height, err
+}
+
+// SimulateAction runs the pending actions, and then the action, on top of the latest state. The pending actions failing
+// to run are skipped, and none of the changes is committed.
+func (sdb *stateDB) SimulateAction(
+	ctx context.Context,
+	pending []action.SealedEnvelope,
+	selp action.SealedEnvelope,
+) (*action.Receipt, error) {
+	ps, err := sdb.PendingState(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	return ps.simulateAction(selp)
+}
+
+// PendingState returns the state after running the pending actions on top of the latest state
+func (sdb *stateDB) PendingState(ctx context.Context, pending []action.SealedEnvelope) (*PendingState, error) {
+	sdb.mutex.Lock()
+	ctx = protocol.WithRegistry(ctx, sdb.registry)
+	ws, err := sdb.newWorkingSet(ctx, sdb.currentChainHeight+1)
+	sdb.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return newPendingState(ctx, ws, pending)
+}
+
+// SimulateExecutionAtHeight simulates a running of smart contract operation on top of the state at height -- archive
+// mode
+func (sdb *stateDB) SimulateExecutionAtHeight(
+	ctx context.Context,
+	height uint64,
+	caller address.Address,
+	ex *action.Execution,
+	getBlockHash evm.GetBlockHash,
+) ([]byte, *action.Receipt, error) {
+	return nil, nil, errors.Wrap(ErrNotSupported, "state db does not support archive mode")
+}
+
 // PutBlock persists all changes in RunActions() into the DB
 func (sdb *stateDB) PutBlock(ctx context.Context, blk *block.Block) error {
 	sdb.mutex.Lock()
@@ -367,7 +452,7 @@ func (sdb *stateDB) PutBlock(ctx context.Context, blk *block.Block) error {
 		protocol.BlockCtx{
 			BlockHeight:    blk.Height(),
 			BlockTimeStamp: blk.Timestamp(),
-			GasLimit:       bcCtx.Genesis.BlockGasLimit,
+			GasLimit:       bcCtx.Genesis.BlockGasLimitByHeight(blk.Height()),
 			Producer:       producer,
 		},
 	)
@@ -402,12 +487,45 @@ func (sdb *stateDB) PutBlock(ctx context.Context, blk *block.Block) error {
 			sdb.currentChainHeight, h,
 		)
 	}
+	if err := ws.Commit(ctx); err != nil {
+		return err
+	}
+	// the working set of the committed block is never used again, its buffers are reused by the next blocks
+	sdb.workingsets.Remove(key)
+	ws.release()
+	return nil
+}
 
-	return ws.Commit(ctx)
+// DeleteTipBlock reverts the state to the height before the tip block with its undo log
+func (sdb *stateDB) DeleteTipBlock(blk *block.Block) error {
+	if sdb.cfg.Chain.MaxReorgDepth == 0 {
+		return errors.Wrap(ErrNotSupported, "cannot delete tip block from state db")
+	}
+	if err := sdb.revertTipBlock(blk.Height()); err != nil {
+		return err
+	}
+	view, err := restartProtocols(sdb.cfg, sdb.registry, sdb)
+	if err != nil {
+		return err
+	}
+	sdb.mutex.Lock()
+	sdb.protocolView = view
+	sdb.mutex.Unlock()
+	return nil
 }
 
-func (sdb *stateDB) DeleteTipBlock(_ *block.Block) error {
-	return errors.Wrap(ErrNotSupported, "cannot delete tip block from state db")
+func (sdb *stateDB) revertTipBlock(height uint64) error {
+	sdb.mutex.Lock()
+	defer sdb.mutex.Unlock()
+	if height != sdb.currentChainHeight {
+		return errors.Errorf("block %d is not the tip %d of state db", height, sdb.currentChainHeight)
+	}
+	if err := revertTipBlock(sdb.dao, height); err != nil {
+		return err
+	}
+	sdb.currentChainHeight = height - 1
+	sdb.workingsets.Clear()
+	return nil
 }
 
 // State returns a confirmed state in the state factory
@@ -0,0 +1,390 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// StateDiffNamespace is the bucket of the state diffs of the recent blocks
+const StateDiffNamespace = "StateDiff"
+
+type (
+	// StateDiffReader reads the changes of the states made by a block
+	StateDiffReader interface {
+		// StateDiff returns the state diff of the block at height
+		StateDiff(uint64) (*StateDiff, error)
+	}
+
+	// StateDiff is the changes of the accounts and the contract storage made by a block
+	StateDiff struct {
+		Accounts []*AccountDiff
+		Storage  []*StorageDiff
+	}
+
+	// AccountDiff is the change of an account, the old values are nil if the account is created by the block, and the
+	// new values are nil if it is deleted
+	AccountDiff struct {
+		Address    hash.Hash160
+		OldBalance *big.Int
+		NewBalance *big.Int
+		OldNonce   *uint64
+		NewNonce   *uint64
+	}
+
+	// StorageDiff is the change of a slot of the contract storage, the value is nil if the slot doesn't exist
+	StorageDiff struct {
+		Contract hash.Hash160
+		Key      hash.Hash256
+		OldValue []byte
+		NewValue []byte
+	}
+
+	// storageDiffs collects the storage changes of the contracts committed by the executions of a block, keeping the
+	// value before the first change and after the last change of each slot
+	storageDiffs struct {
+		diffs []*StorageDiff
+		index map[string]int
+	}
+
+	// diffStore writes the batch of a block along with its state diff
+	diffStore struct {
+		db.KVStore
+		height    uint64
+		retention uint64
+		storage   *storageDiffs
+	}
+)
+
+func newStorageDiffs() *storageDiffs {
+	return &storageDiffs{index: make(map[string]int)}
+}
+
+func (sd *storageDiffs) record(contract hash.Hash160, changes []evm.StorageChange) {
+	for _, change := range changes {
+		id := string(contract[:]) + string(change.Key[:])
+		if i, ok := sd.index[id]; ok {
+			sd.diffs[i].NewValue = change.NewValue
+			continue
+		}
+		sd.index[id] = len(sd.diffs)
+		sd.diffs = append(sd.diffs, &StorageDiff{
+			Contract: contract,
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+}
+
+// reset clears the recorded changes, and keeps the capacity for reuse
+func (sd *storageDiffs) reset() {
+	for i := range sd.diffs {
+		sd.diffs[i] = nil
+	}
+	sd.diffs = sd.diffs[:0]
+	for id := range sd.index {
+		delete(sd.index, id)
+	}
+}
+
+// changed returns the slots whose value at the end differs from the one at the beginning
+func (sd *storageDiffs) changed() []*StorageDiff {
+	ret := make([]*StorageDiff, 0, len(sd.diffs))
+	for _, diff := range sd.diffs {
+		if !bytes.Equal(diff.OldValue, diff.NewValue) {
+			ret = append(ret, diff)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if c := bytes.Compare(ret[i].Contract[:], ret[j].Contract[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(ret[i].Key[:], ret[j].Key[:]) < 0
+	})
+	return ret
+}
+
+// newDiffStore returns the store to flush the working set at height into, which keeps the state diffs of the latest
+// retention blocks
+func newDiffStore(kv db.KVStore, height, retention uint64, storage *storageDiffs) db.KVStore {
+	if retention == 0 {
+		return kv
+	}
+	return &diffStore{KVStore: kv, height: height, retention: retention, storage: storage}
+}
+
+func (s *diffStore) WriteBatch(b batch.KVStoreBatch) error {
+	accounts, err := accountDiffs(s.KVStore, b)
+	if err != nil {
+		return err
+	}
+	diff := &StateDiff{Accounts: accounts}
+	if s.storage != nil {
+		diff.Storage = s.storage.changed()
+	}
+	b.Put(StateDiffNamespace, byteutil.Uint64ToBytesBigEndian(s.height), diff.Serialize(), "failed to put state diff of height %d", s.height)
+	if s.height > s.retention {
+		expired := s.height - s.retention
+		b.Delete(StateDiffNamespace, byteutil.Uint64ToBytesBigEndian(expired), "failed to delete state diff of height %d", expired)
+	}
+	return s.KVStore.WriteBatch(b)
+}
+
+// accountDiffs compares the accounts written by the batch with their previous values, the accounts whose balance and
+// nonce are unchanged are skipped
+func accountDiffs(kv db.KVStore, b batch.KVStoreBatch) ([]*AccountDiff, error) {
+	var (
+		keys   []hash.Hash160
+		latest = make(map[hash.Hash160][]byte)
+	)
+	for i := 0; i < b.Size(); i++ {
+		write, err := b.Entry(i)
+		if err != nil {
+			return nil, err
+		}
+		if write.Namespace() != AccountKVNamespace || len(write.Key()) != len(hash.ZeroHash160) {
+			continue
+		}
+		addr := hash.BytesToHash160(write.Key())
+		if _, ok := latest[addr]; !ok {
+			keys = append(keys, addr)
+		}
+		if write.WriteType() == batch.Delete {
+			latest[addr] = nil
+			continue
+		}
+		latest[addr] = write.Value()
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	diffs := make([]*AccountDiff, 0, len(keys))
+	for _, addr := range keys {
+		value, err := kv.Get(AccountKVNamespace, addr[:])
+		switch errors.Cause(err) {
+		case nil:
+		case db.ErrNotExist, db.ErrBucketNotExist:
+			value = nil
+		default:
+			return nil, errors.Wrapf(err, "failed to read previous account %x", addr)
+		}
+		diff := &AccountDiff{Address: addr}
+		var ok bool
+		if diff.OldBalance, diff.OldNonce, ok = decodeAccount(value); !ok {
+			continue
+		}
+		if diff.NewBalance, diff.NewNonce, ok = decodeAccount(latest[addr]); !ok {
+			continue
+		}
+		if equalBalance(diff.OldBalance, diff.NewBalance) && equalNonce(diff.OldNonce, diff.NewNonce) {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// decodeAccount returns the balance and nonce of a serialized account, which are nil if the account doesn't exist.
+// It returns false if the value is not an account.
+func decodeAccount(value []byte) (*big.Int, *uint64, bool) {
+	if value == nil {
+		return nil, nil, true
+	}
+	var acct state.Account
+	if err := state.Deserialize(&acct, value); err != nil {
+		return nil, nil, false
+	}
+	nonce := acct.Nonce
+	return acct.Balance, &nonce, true
+}
+
+func equalBalance(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+func equalNonce(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Serialize encodes the state diff
+func (sd *StateDiff) Serialize() []byte {
+	var buf bytes.Buffer
+	writeCount(&buf, uint64(len(sd.Accounts)))
+	for _, acct := range sd.Accounts {
+		buf.Write(acct.Address[:])
+		writeOptionalBytes(&buf, bigIntBytes(acct.OldBalance))
+		writeOptionalBytes(&buf, bigIntBytes(acct.NewBalance))
+		writeOptionalBytes(&buf, nonceBytes(acct.OldNonce))
+		writeOptionalBytes(&buf, nonceBytes(acct.NewNonce))
+	}
+	writeCount(&buf, uint64(len(sd.Storage)))
+	for _, slot := range sd.Storage {
+		buf.Write(slot.Contract[:])
+		buf.Write(slot.Key[:])
+		writeOptionalBytes(&buf, slot.OldValue)
+		writeOptionalBytes(&buf, slot.NewValue)
+	}
+	return buf.Bytes()
+}
+
+// Deserialize decodes the state diff
+func (sd *StateDiff) Deserialize(data []byte) error {
+	r := bytes.NewReader(data)
+	n, err := readCount(r)
+	if err != nil {
+		return err
+	}
+	sd.Accounts = make([]*AccountDiff, 0, n)
+	for i := uint64(0); i < n; i++ {
+		acct := &AccountDiff{}
+		if _, err := io.ReadFull(r, acct.Address[:]); err != nil {
+			return errors.Wrap(err, "invalid state diff")
+		}
+		var fields [4][]byte
+		for j := range fields {
+			if fields[j], err = readOptionalBytes(r); err != nil {
+				return err
+			}
+		}
+		acct.OldBalance, acct.NewBalance = bytesBigInt(fields[0]), bytesBigInt(fields[1])
+		acct.OldNonce, acct.NewNonce = bytesNonce(fields[2]), bytesNonce(fields[3])
+		sd.Accounts = append(sd.Accounts, acct)
+	}
+	if n, err = readCount(r); err != nil {
+		return err
+	}
+	sd.Storage = make([]*StorageDiff, 0, n)
+	for i := uint64(0); i < n; i++ {
+		slot := &StorageDiff{}
+		if _, err := io.ReadFull(r, slot.Contract[:]); err != nil {
+			return errors.Wrap(err, "invalid state diff")
+		}
+		if _, err := io.ReadFull(r, slot.Key[:]); err != nil {
+			return errors.Wrap(err, "invalid state diff")
+		}
+		if slot.OldValue, err = readOptionalBytes(r); err != nil {
+			return err
+		}
+		if slot.NewValue, err = readOptionalBytes(r); err != nil {
+			return err
+		}
+		sd.Storage = append(sd.Storage, slot)
+	}
+	return nil
+}
+
+// readStateDiff reads the state diff of the block at height
+func readStateDiff(kv db.KVStore, height, retention uint64) (*StateDiff, error) {
+	if retention == 0 {
+		return nil, errors.Wrap(ErrNotSupported, "state diff is disabled")
+	}
+	value, err := kv.Get(StateDiffNamespace, byteutil.Uint64ToBytesBigEndian(height))
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return nil, errors.Wrapf(ErrNoArchiveData, "state diff of height %d is not retained", height)
+	default:
+		return nil, errors.Wrapf(err, "failed to get state diff of height %d", height)
+	}
+	diff := &StateDiff{}
+	if err := diff.Deserialize(value); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func writeCount(buf *bytes.Buffer, n uint64) {
+	writeBytes(buf, byteutil.Uint64ToBytesBigEndian(n))
+}
+
+func readCount(r *bytes.Reader) (uint64, error) {
+	b, err := readBytes(r)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, errors.New("invalid state diff")
+	}
+	return byteutil.BytesToUint64BigEndian(b), nil
+}
+
+// writeOptionalBytes writes whether the value exists before the value, to tell nil from empty
+func writeOptionalBytes(buf *bytes.Buffer, value []byte) {
+	if value == nil {
+		buf.WriteByte(0)
+		return
+	}
+	buf.WriteByte(1)
+	writeBytes(buf, value)
+}
+
+func readOptionalBytes(r *bytes.Reader) ([]byte, error) {
+	exist, err := r.ReadByte()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid state diff")
+	}
+	if exist == 0 {
+		return nil, nil
+	}
+	value, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return value, nil
+}
+
+func bigIntBytes(v *big.Int) []byte {
+	if v == nil {
+		return nil
+	}
+	return []byte(v.String())
+}
+
+func bytesBigInt(b []byte) *big.Int {
+	if b == nil {
+		return nil
+	}
+	v, _ := new(big.Int).SetString(string(b), 10)
+	return v
+}
+
+func nonceBytes(v *uint64) []byte {
+	if v == nil {
+		return nil
+	}
+	return byteutil.Uint64ToBytesBigEndian(*v)
+}
+
+func bytesNonce(b []byte) *uint64 {
+	if len(b) != 8 {
+		return nil
+	}
+	v := byteutil.BytesToUint64BigEndian(b)
+	return &v
+}
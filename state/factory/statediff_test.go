@@ -0,0 +1,118 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestDiffStore(t *testing.T) {
+	r := require.New(t)
+	kv := db.NewMemKVStore()
+	r.NoError(kv.Start(context.Background()))
+
+	a := hash.BytesToHash160(identityset.Address(28).Bytes())
+	b := hash.BytesToHash160(identityset.Address(29).Bytes())
+	account := func(balance int64, nonce uint64) []byte {
+		ss, err := state.Serialize(&state.Account{Balance: big.NewInt(balance), Nonce: nonce})
+		r.NoError(err)
+		return ss
+	}
+	r.NoError(kv.Put(AccountKVNamespace, a[:], account(100, 1)))
+	r.NoError(kv.Put(AccountKVNamespace, b[:], account(5, 0)))
+
+	contract := hash.Hash160b([]byte("contract"))
+	k1, k2, k3 := hash.Hash256b([]byte("k1")), hash.Hash256b([]byte("k2")), hash.Hash256b([]byte("k3"))
+	storage := newStorageDiffs()
+	storage.record(contract, []evm.StorageChange{{Key: k2, OldValue: nil, NewValue: []byte{1}}, {Key: k1, OldValue: []byte{1}, NewValue: []byte{2}}})
+	storage.record(contract, []evm.StorageChange{{Key: k1, OldValue: []byte{2}, NewValue: []byte{3}}, {Key: k3, OldValue: []byte{1}, NewValue: []byte{2}}})
+	// k3 is set back to its value by the second execution
+	storage.record(contract, []evm.StorageChange{{Key: k3, OldValue: []byte{2}, NewValue: []byte{1}}})
+
+	c := hash.BytesToHash160(identityset.Address(30).Bytes())
+	wb := batch.NewBatch()
+	wb.Put(AccountKVNamespace, a[:], account(90, 2), "")
+	wb.Put(AccountKVNamespace, b[:], account(5, 0), "")
+	wb.Put(AccountKVNamespace, c[:], account(10, 0), "")
+	wb.Put(AccountKVNamespace, []byte(CurrentHeightKey), []byte{1}, "")
+	r.NoError(newDiffStore(kv, 1, 2, storage).WriteBatch(wb))
+
+	sf := &stateDB{dao: kv, cfg: config.Default}
+	_, err := sf.StateDiff(1)
+	r.Equal(ErrNotSupported, errors.Cause(err))
+	sf.cfg.Chain.StateDiffRetention = 2
+	diff, err := sf.StateDiff(1)
+	r.NoError(err)
+	r.Len(diff.Accounts, 2)
+	r.Equal(a, diff.Accounts[0].Address)
+	r.Equal(big.NewInt(100), diff.Accounts[0].OldBalance)
+	r.Equal(big.NewInt(90), diff.Accounts[0].NewBalance)
+	r.Equal(uint64(1), *diff.Accounts[0].OldNonce)
+	r.Equal(uint64(2), *diff.Accounts[0].NewNonce)
+	// the account created by the block
+	r.Equal(c, diff.Accounts[1].Address)
+	r.Nil(diff.Accounts[1].OldBalance)
+	r.Nil(diff.Accounts[1].OldNonce)
+	r.Equal(big.NewInt(10), diff.Accounts[1].NewBalance)
+
+	expected := []*StorageDiff{
+		{Contract: contract, Key: k1, OldValue: []byte{1}, NewValue: []byte{3}},
+		{Contract: contract, Key: k2, OldValue: nil, NewValue: []byte{1}},
+	}
+	if string(k2[:]) < string(k1[:]) {
+		expected[0], expected[1] = expected[1], expected[0]
+	}
+	r.Equal(expected, diff.Storage)
+
+	// the diff out of the retention is deleted
+	for h := uint64(2); h <= 3; h++ {
+		r.NoError(newDiffStore(kv, h, 2, nil).WriteBatch(batch.NewBatch()))
+	}
+	_, err = sf.StateDiff(1)
+	r.Equal(ErrNoArchiveData, errors.Cause(err))
+	diff, err = sf.StateDiff(3)
+	r.NoError(err)
+	r.Empty(diff.Accounts)
+	r.Empty(diff.Storage)
+}
+
+func TestFactoryStateDiff(t *testing.T) {
+	r := require.New(t)
+	cfg := config.Default
+	cfg.Chain.StateDiffRetention = 2
+	sf, ctx := startTestFactory(t, cfg, 3)
+	defer func() {
+		r.NoError(sf.Stop(ctx))
+	}()
+
+	diff, err := sf.(StateDiffReader).StateDiff(3)
+	r.NoError(err)
+	balances := make(map[hash.Hash160][2]*big.Int)
+	for _, acct := range diff.Accounts {
+		balances[acct.Address] = [2]*big.Int{acct.OldBalance, acct.NewBalance}
+	}
+	a := hash.BytesToHash160(identityset.Address(28).Bytes())
+	b := hash.BytesToHash160(identityset.Address(31).Bytes())
+	r.Equal([2]*big.Int{big.NewInt(80), big.NewInt(70)}, balances[a])
+	r.Equal([2]*big.Int{big.NewInt(20), big.NewInt(30)}, balances[b])
+
+	_, err = sf.(StateDiffReader).StateDiff(1)
+	r.Equal(ErrNoArchiveData, errors.Cause(err))
+}
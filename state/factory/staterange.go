@@ -0,0 +1,323 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory/staterangepb"
+)
+
+// StatePreimageNamespace is the bucket of the keys of the states and the names of the namespaces, keyed by their keys
+// in the state trie, which are their hashes. A new node rebuilds the flat copies of the states from them.
+const StatePreimageNamespace = "StatePreimage"
+
+var (
+	// ErrInvalidStateRange indicates a state range does not match the state root
+	ErrInvalidStateRange = errors.New("invalid state range")
+
+	// statePreimageBackfilledKey marks the preimages of the states created before the bucket was introduced are added
+	statePreimageBackfilledKey = []byte("backfilled")
+)
+
+type (
+	// StateRangeReader serves the consecutive leaves of the state trie at a retained height, from which a new node
+	// rebuilds the state trie instead of executing all the blocks
+	StateRangeReader interface {
+		// StateRange returns at most limit leaves from the first key not less than start. The leaves are in the first
+		// layer of the state trie if the namespace key is nil, otherwise in the trie of the namespace.
+		StateRange(height uint64, nsKey []byte, start []byte, limit uint64) (*StateRange, error)
+	}
+
+	// StateRange is a range of consecutive leaves of a trie in the state trie, with the proofs of its first and last
+	// leaves. A missing or forged leaf in the middle is only caught once the whole trie is rebuilt and its root checked.
+	StateRange struct {
+		// StateRoot is the root of the state trie
+		StateRoot []byte
+		// Namespace is the name of the namespace, NamespaceKey and NamespaceProof prove the root of the namespace trie
+		// in the first layer, they are empty for the range of the first layer itself
+		Namespace      string
+		NamespaceKey   []byte
+		NamespaceProof [][]byte
+		Keys           [][]byte
+		Values         [][]byte
+		// RawKeys are the preimages of the keys, which are the names of the namespaces in the first layer
+		RawKeys    [][]byte
+		FirstProof [][]byte
+		LastProof  [][]byte
+		// More tells there are more leaves after the range
+		More bool
+	}
+)
+
+// Serialize serializes the state range into bytes
+func (r *StateRange) Serialize() []byte {
+	return byteutil.Must(proto.Marshal(&staterangepb.StateRange{
+		StateRoot:      r.StateRoot,
+		Namespace:      r.Namespace,
+		NamespaceKey:   r.NamespaceKey,
+		NamespaceProof: r.NamespaceProof,
+		Keys:           r.Keys,
+		Values:         r.Values,
+		RawKeys:        r.RawKeys,
+		FirstProof:     r.FirstProof,
+		LastProof:      r.LastProof,
+		More:           r.More,
+	}))
+}
+
+// Deserialize deserializes the bytes into the state range
+func (r *StateRange) Deserialize(buf []byte) error {
+	pb := &staterangepb.StateRange{}
+	if err := proto.Unmarshal(buf, pb); err != nil {
+		return errors.Wrap(err, "failed to unmarshal state range")
+	}
+	*r = StateRange{
+		StateRoot:      pb.StateRoot,
+		Namespace:      pb.Namespace,
+		NamespaceKey:   pb.NamespaceKey,
+		NamespaceProof: pb.NamespaceProof,
+		Keys:           pb.Keys,
+		Values:         pb.Values,
+		RawKeys:        pb.RawKeys,
+		FirstProof:     pb.FirstProof,
+		LastProof:      pb.LastProof,
+		More:           pb.More,
+	}
+	return nil
+}
+
+// StateRange returns a range of the leaves of the state trie at height, the states below the tip height are only
+// available when they are retained
+func (sf *factory) StateRange(height uint64, nsKey []byte, start []byte, limit uint64) (*StateRange, error) {
+	if limit == 0 {
+		return nil, errors.New("limit of state range should be positive")
+	}
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	if err := sf.checkArchivedHeight(height); err != nil {
+		return nil, err
+	}
+	layerOne, dbForTrie, root, err := sf.layerOneTrieAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	defer layerOne.Stop(context.Background())
+
+	ret := &StateRange{StateRoot: root, NamespaceKey: nsKey}
+	tr := layerOne
+	if nsKey != nil {
+		if ret.NamespaceProof, err = layerOne.Proof(nsKey); err != nil {
+			return nil, err
+		}
+		nsRoot, err := layerOne.Get(nsKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the root of namespace %x", nsKey)
+		}
+		if tr, err = namespaceTrie(dbForTrie, nsRoot); err != nil {
+			return nil, err
+		}
+		defer tr.Stop(context.Background())
+		name, err := sf.statePreimage(nsKey)
+		if err != nil {
+			return nil, err
+		}
+		ret.Namespace = string(name)
+	}
+	iter, err := mptrie.NewOrderedLeafIterator(tr, start)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		key, value, err := iter.Next()
+		if err == trie.ErrEndOfIterator {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(ret.Keys)) == limit {
+			ret.More = true
+			break
+		}
+		preimageKey := key
+		if nsKey != nil {
+			preimageKey = statePreimageKey(nsKey, key)
+		}
+		rawKey, err := sf.statePreimage(preimageKey)
+		if err != nil {
+			return nil, err
+		}
+		ret.Keys = append(ret.Keys, key)
+		ret.Values = append(ret.Values, value)
+		ret.RawKeys = append(ret.RawKeys, rawKey)
+	}
+	if n := len(ret.Keys); n > 0 {
+		if ret.FirstProof, err = tr.Proof(ret.Keys[0]); err != nil {
+			return nil, err
+		}
+		if ret.LastProof, err = tr.Proof(ret.Keys[n-1]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// VerifyStateRange checks the range requested from start is sorted, and its namespace root and its edges are proven
+// against the state root. It returns the root of the trie of the range.
+func VerifyStateRange(stateRoot []byte, nsKey []byte, start []byte, r *StateRange) ([]byte, error) {
+	if !bytes.Equal(r.StateRoot, stateRoot) {
+		return nil, errors.Wrapf(ErrInvalidStateRange, "state root %x does not match %x", r.StateRoot, stateRoot)
+	}
+	if !bytes.Equal(r.NamespaceKey, nsKey) {
+		return nil, errors.Wrapf(ErrInvalidStateRange, "namespace %x does not match %x", r.NamespaceKey, nsKey)
+	}
+	if nsKey != nil && !bytes.Equal(namespaceKey(r.Namespace), nsKey) {
+		return nil, errors.Wrapf(ErrInvalidStateRange, "name %s does not match namespace %x", r.Namespace, nsKey)
+	}
+	if len(r.Keys) != len(r.Values) || len(r.Keys) != len(r.RawKeys) {
+		return nil, errors.Wrap(ErrInvalidStateRange, "keys and values do not match")
+	}
+	for i, key := range r.Keys {
+		if !bytes.Equal(toLegacyKey(r.RawKeys[i]), key) {
+			return nil, errors.Wrapf(ErrInvalidStateRange, "preimage of key %x does not match", key)
+		}
+	}
+	root := stateRoot
+	if nsKey != nil {
+		nsRoot, err := mptrie.VerifyProof(stateRoot, nsKey, r.NamespaceProof, nil)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidStateRange, "invalid proof of namespace %x: %v", nsKey, err)
+		}
+		root = nsRoot
+	}
+	prev := start
+	for i, key := range r.Keys {
+		if c := bytes.Compare(key, prev); c < 0 || (c == 0 && i > 0) {
+			return nil, errors.Wrapf(ErrInvalidStateRange, "key %x is out of order", key)
+		}
+		prev = key
+	}
+	if n := len(r.Keys); n > 0 {
+		for _, edge := range []struct {
+			i     int
+			proof [][]byte
+		}{{0, r.FirstProof}, {n - 1, r.LastProof}} {
+			value, err := mptrie.VerifyProof(root, r.Keys[edge.i], edge.proof, nil)
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidStateRange, "invalid proof of key %x: %v", r.Keys[edge.i], err)
+			}
+			if !bytes.Equal(value, r.Values[edge.i]) {
+				return nil, errors.Wrapf(ErrInvalidStateRange, "value of key %x does not match its proof", r.Keys[edge.i])
+			}
+		}
+	}
+	return root, nil
+}
+
+// WriteStateRange writes the flat copies of the states in a verified range of a namespace, and their preimages. The
+// range of the first layer only has the names of the namespaces.
+func WriteStateRange(kv db.KVStoreWithBuffer, r *StateRange) {
+	if r.NamespaceKey == nil {
+		for i, key := range r.Keys {
+			kv.MustPut(StatePreimageNamespace, key, r.RawKeys[i])
+		}
+		return
+	}
+	for i, key := range r.Keys {
+		kv.MustPut(r.Namespace, r.RawKeys[i], r.Values[i])
+		kv.MustPut(StatePreimageNamespace, statePreimageKey(r.NamespaceKey, key), r.RawKeys[i])
+		if r.Namespace == AccountKVNamespace {
+			kv.MustPut(AccountPreimageNamespace, key, r.RawKeys[i])
+		}
+	}
+}
+
+// statePreimage returns the preimage of the key in the state preimage bucket
+func (sf *factory) statePreimage(key []byte) ([]byte, error) {
+	value, err := sf.dao.Get(StatePreimageNamespace, key)
+	switch errors.Cause(err) {
+	case nil:
+		return value, nil
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return nil, errors.Wrapf(ErrNotSupported, "preimage of key %x is not recorded", key)
+	default:
+		return nil, err
+	}
+}
+
+// statePreimageKey returns the key of the preimage of a key of the namespace
+func statePreimageKey(nsKey []byte, key []byte) []byte {
+	return append(nsKey[:len(nsKey):len(nsKey)], key...)
+}
+
+// putStatePreimage records the key of the state and the name of its namespace if they are not recorded yet
+func putStatePreimage(kv db.KVStoreWithBuffer, ns string, key []byte) {
+	nsKey := namespaceKey(ns)
+	preimageKey := statePreimageKey(nsKey, toLegacyKey(key))
+	if _, err := kv.Get(StatePreimageNamespace, preimageKey); err == nil {
+		return
+	}
+	kv.MustPut(StatePreimageNamespace, preimageKey, key)
+	kv.MustPut(StatePreimageNamespace, nsKey, []byte(ns))
+}
+
+// backfillStatePreimages records the preimages of the states created before the preimages are recorded, from the
+// buckets of the namespaces in the state trie
+func (sf *factory) backfillStatePreimages() error {
+	if _, err := sf.dao.Get(StatePreimageNamespace, statePreimageBackfilledKey); err == nil {
+		return nil
+	}
+	lister, ok := sf.dao.(interface {
+		GetBucketByPrefix([]byte) ([][]byte, error)
+	})
+	if !ok {
+		log.L().Warn("Failed to backfill the state preimages, the buckets cannot be listed.")
+		return nil
+	}
+	buckets, err := lister.GetBucketByPrefix(nil)
+	if err != nil {
+		return err
+	}
+	layerOne, _, _, err := sf.layerOneTrieAtHeight(sf.currentChainHeight)
+	if err != nil {
+		log.L().Warn("Failed to backfill the state preimages.", zap.Error(err))
+		return nil
+	}
+	defer layerOne.Stop(context.Background())
+
+	b := batch.NewBatch()
+	for _, bucket := range buckets {
+		ns := string(bucket)
+		nsKey := namespaceKey(ns)
+		if _, err := layerOne.Get(nsKey); err != nil {
+			// not a namespace in the state trie
+			continue
+		}
+		keys, _, err := sf.dao.Filter(ns, func(k, v []byte) bool { return true }, nil, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the keys of namespace %s", ns)
+		}
+		b.Put(StatePreimageNamespace, nsKey, bucket, "failed to put the name of namespace")
+		for _, key := range keys {
+			b.Put(StatePreimageNamespace, statePreimageKey(nsKey, toLegacyKey(key)), key, "failed to put state preimage")
+		}
+	}
+	b.Put(StatePreimageNamespace, statePreimageBackfilledKey, []byte{1}, "failed to mark state preimages backfilled")
+	return sf.dao.WriteBatch(b)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestStateRange(t *testing.T) {
+	r := require.New(t)
+	cfg := config.Default
+	cfg.Chain.StateRetention = 2
+	sf, ctx := startTestFactory(t, cfg, 3)
+	defer func() {
+		r.NoError(sf.Stop(ctx))
+	}()
+	reader := sf.(StateRangeReader)
+	root, err := sf.(*factory).StateRootAt(2)
+	r.NoError(err)
+
+	// the first layer holds the roots of the namespaces
+	layerOne, err := reader.StateRange(2, nil, nil, 100)
+	r.NoError(err)
+	r.False(layerOne.More)
+	r.Contains(layerOne.Keys, namespaceKey(AccountKVNamespace))
+	_, err = VerifyStateRange(root, nil, nil, layerOne)
+	r.NoError(err)
+
+	nsKey := namespaceKey(AccountKVNamespace)
+	first, err := reader.StateRange(2, nsKey, nil, 1)
+	r.NoError(err)
+	r.True(first.More)
+	r.Len(first.Keys, 1)
+	nsRoot, err := VerifyStateRange(root, nsKey, nil, first)
+	r.NoError(err)
+	for i, key := range layerOne.Keys {
+		if string(key) == string(nsKey) {
+			r.Equal(layerOne.Values[i], nsRoot)
+		}
+	}
+	start := append(first.Keys[0][:0:0], first.Keys[0]...)
+	start[len(start)-1]++
+	rest, err := reader.StateRange(2, nsKey, start, 100)
+	r.NoError(err)
+	r.False(rest.More)
+	r.NotEmpty(rest.Keys)
+	_, err = VerifyStateRange(root, nsKey, start, rest)
+	r.NoError(err)
+
+	// the range does not match another state root
+	_, err = VerifyStateRange(layerOne.Values[0], nsKey, start, rest)
+	r.Equal(ErrInvalidStateRange, errors.Cause(err))
+	// the range before the start
+	_, err = VerifyStateRange(root, nsKey, rest.Keys[0], first)
+	r.Equal(ErrInvalidStateRange, errors.Cause(err))
+	// a forged preimage
+	forged := *rest
+	forged.RawKeys = append([][]byte{}, rest.RawKeys...)
+	forged.RawKeys[0] = []byte{1}
+	_, err = VerifyStateRange(root, nsKey, start, &forged)
+	r.Equal(ErrInvalidStateRange, errors.Cause(err))
+	// a forged value at the edge
+	forged = *rest
+	forged.Values = append([][]byte{}, rest.Values...)
+	forged.Values[len(forged.Values)-1] = []byte{1}
+	_, err = VerifyStateRange(root, nsKey, start, &forged)
+	r.Equal(ErrInvalidStateRange, errors.Cause(err))
+
+	// the state out of the retention is not served
+	_, err = reader.StateRange(0, nil, nil, 100)
+	r.Equal(ErrNoArchiveData, errors.Cause(err))
+}
+
+func TestBackfillStatePreimages(t *testing.T) {
+	r := require.New(t)
+	path, err := testutil.PathOfTempFile("preimage")
+	r.NoError(err)
+	defer testutil.CleanupPath(t, path)
+	cfg := config.Default
+	cfg.Chain.TrieDBPath = path
+	cfg.Genesis.InitBalanceMap = map[string]string{identityset.Address(28).String(): "100"}
+	ctx := protocol.WithBlockCtx(
+		protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: cfg.Genesis}),
+		protocol.BlockCtx{},
+	)
+	newFactory := func() Factory {
+		sf, err := NewFactory(cfg, DefaultTrieOption())
+		r.NoError(err)
+		r.NoError(sf.Register(account.NewProtocol(rewarding.DepositGas)))
+		return sf
+	}
+	sf := newFactory()
+	r.NoError(sf.Start(ctx))
+	r.NoError(sf.Stop(ctx))
+
+	// the preimages of the states written before the bucket is introduced
+	dbCfg := cfg.DB
+	dbCfg.DbPath = path
+	kv := db.NewBoltDB(dbCfg)
+	r.NoError(kv.Start(context.Background()))
+	r.NoError(kv.Delete(StatePreimageNamespace, nil))
+	r.NoError(kv.Stop(context.Background()))
+
+	restarted := newFactory()
+	r.NoError(restarted.Start(ctx))
+	defer func() {
+		r.NoError(restarted.Stop(ctx))
+	}()
+	layerOne, err := restarted.(StateRangeReader).StateRange(0, nil, nil, 100)
+	r.NoError(err)
+	r.Contains(layerOne.RawKeys, []byte(AccountKVNamespace))
+	accounts, err := restarted.(StateRangeReader).StateRange(0, namespaceKey(AccountKVNamespace), nil, 100)
+	r.NoError(err)
+	r.Equal(AccountKVNamespace, accounts.Namespace)
+	r.Len(accounts.RawKeys, 1)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+// UndoNamespace is the bucket of the undo logs of the recent blocks
+const UndoNamespace = "Undo"
+
+// undoStore writes the batch of a block along with its undo log, which records the previous values of the keys written
+// by the batch. Applying the undo log reverts the state to the height before the block.
+type undoStore struct {
+	db.KVStore
+	height uint64
+	depth  uint64
+}
+
+// newUndoStore returns the store to flush the working set at height into, which keeps the undo logs of the latest
+// depth blocks
+func newUndoStore(kv db.KVStore, height, depth uint64) db.KVStore {
+	if depth == 0 {
+		return kv
+	}
+	return &undoStore{KVStore: kv, height: height, depth: depth}
+}
+
+func (s *undoStore) WriteBatch(b batch.KVStoreBatch) error {
+	undo, err := undoLog(s.KVStore, b)
+	if err != nil {
+		return err
+	}
+	b.Put(UndoNamespace, byteutil.Uint64ToBytesBigEndian(s.height), undo, "failed to put undo log of height %d", s.height)
+	if s.height > s.depth {
+		expired := s.height - s.depth
+		b.Delete(UndoNamespace, byteutil.Uint64ToBytesBigEndian(expired), "failed to delete undo log of height %d", expired)
+	}
+	return s.KVStore.WriteBatch(b)
+}
+
+// undoLog encodes the previous value of every key written by the batch, as the namespace, the key, and the value if it
+// exists
+func undoLog(kv db.KVStore, b batch.KVStoreBatch) ([]byte, error) {
+	var (
+		buf     bytes.Buffer
+		visited = make(map[string]bool)
+	)
+	for i := 0; i < b.Size(); i++ {
+		write, err := b.Entry(i)
+		if err != nil {
+			return nil, err
+		}
+		ns, key := write.Namespace(), write.Key()
+		id := ns + "/" + string(key)
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		value, err := kv.Get(ns, key)
+		switch errors.Cause(err) {
+		case nil:
+		case db.ErrNotExist:
+			value = nil
+		default:
+			return nil, errors.Wrapf(err, "failed to read previous value of key %x in %s", key, ns)
+		}
+		writeBytes(&buf, []byte(ns))
+		writeBytes(&buf, key)
+		if value == nil {
+			buf.WriteByte(0)
+			continue
+		}
+		buf.WriteByte(1)
+		writeBytes(&buf, value)
+	}
+	return buf.Bytes(), nil
+}
+
+// revertTipBlock applies the undo log of the block at height, and deletes it
+func revertTipBlock(kv db.KVStore, height uint64) error {
+	heightKey := byteutil.Uint64ToBytesBigEndian(height)
+	undo, err := kv.Get(UndoNamespace, heightKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get undo log of height %d", height)
+	}
+	b := batch.NewBatch()
+	r := bytes.NewReader(undo)
+	for r.Len() > 0 {
+		ns, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		key, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		exist, err := r.ReadByte()
+		if err != nil {
+			return errors.Wrap(err, "invalid undo log")
+		}
+		if exist == 0 {
+			b.Delete(string(ns), key, "failed to delete key %x in %s", key, ns)
+			continue
+		}
+		value, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		b.Put(string(ns), key, value, "failed to restore key %x in %s", key, ns)
+	}
+	b.Delete(UndoNamespace, heightKey, "failed to delete undo log of height %d", height)
+	return kv.WriteBatch(b)
+}
+
+// restartProtocols rebuilds the views of the protocols from the reverted state
+func restartProtocols(cfg config.Config, registry *protocol.Registry, sr protocol.StateReader) (protocol.View, error) {
+	ctx := protocol.WithBlockchainCtx(
+		protocol.WithRegistry(context.Background(), registry),
+		protocol.BlockchainCtx{Genesis: cfg.Genesis},
+	)
+	return registry.StartAll(ctx, sr)
+}
+
+func writeBytes(buf *bytes.Buffer, b []byte) {
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(b)))
+	buf.Write(size[:n])
+	buf.Write(b)
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil || size > uint64(r.Len()) {
+		return nil, errors.New("invalid undo log")
+	}
+	b := make([]byte, size)
+	_, err = io.ReadFull(r, b)
+	return b, err
+}
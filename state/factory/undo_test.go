@@ -0,0 +1,57 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+func TestUndoStore(t *testing.T) {
+	r := require.New(t)
+	kv := db.NewMemKVStore()
+	r.NoError(kv.Start(context.Background()))
+	r.NoError(kv.Put("ns", []byte("a"), []byte("a0")))
+	r.NoError(kv.Put("ns", []byte("b"), []byte("b0")))
+
+	writeBlock := func(height uint64, writes func(b batch.KVStoreBatch)) {
+		b := batch.NewBatch()
+		writes(b)
+		r.NoError(newUndoStore(kv, height, 2).WriteBatch(b))
+	}
+	writeBlock(1, func(b batch.KVStoreBatch) {
+		b.Put("ns", []byte("a"), []byte("a1"), "")
+		b.Put("ns", []byte("a"), []byte("a2"), "")
+		b.Delete("ns", []byte("b"), "")
+		b.Put("ns", []byte("c"), []byte{}, "")
+	})
+	writeBlock(2, func(b batch.KVStoreBatch) {
+		b.Put("ns", []byte("b"), []byte("b2"), "")
+	})
+	writeBlock(3, func(b batch.KVStoreBatch) {
+		b.Delete("ns", []byte("a"), "")
+	})
+	// the undo log of height 1 is out of depth
+	_, err := kv.Get(UndoNamespace, []byte{0, 0, 0, 0, 0, 0, 0, 1})
+	r.Equal(db.ErrNotExist, errors.Cause(err))
+
+	r.NoError(revertTipBlock(kv, 3))
+	v, err := kv.Get("ns", []byte("a"))
+	r.NoError(err)
+	r.Equal([]byte("a2"), v)
+	r.NoError(revertTipBlock(kv, 2))
+	_, err = kv.Get("ns", []byte("b"))
+	r.Equal(db.ErrNotExist, errors.Cause(err))
+	r.Error(revertTipBlock(kv, 1))
+	r.Error(revertTipBlock(kv, 2))
+}
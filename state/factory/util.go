@@ -12,10 +12,12 @@ import (
 	"github.com/iotexproject/go-pkgs/bloom"
 	"github.com/iotexproject/go-pkgs/crypto"
 	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
 )
 
 func processOptions(opts ...protocol.StateOption) (*protocol.StateConfig, error) {
@@ -88,3 +90,22 @@ func protocolCommit(ctx context.Context, sr protocol.StateManager) error {
 	}
 	return nil
 }
+
+func backup(ctx context.Context, kv db.KVStore, dir string) error {
+	b, ok := kv.(db.Backuper)
+	if !ok {
+		return errors.Wrap(ErrNotSupported, "backup needs a persistent DB")
+	}
+	return b.Backup(ctx, dir)
+}
+
+func checkpoint(kv db.KVStore) (*db.Checkpoint, error) {
+	// the bolt DB, or the store coalescing the writes to it
+	c, ok := kv.(interface {
+		Checkpoint() (*db.Checkpoint, error)
+	})
+	if !ok {
+		return nil, errors.Wrap(ErrNotSupported, "checkpoint needs a bolt DB")
+	}
+	return c.Checkpoint()
+}
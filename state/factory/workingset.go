@@ -19,9 +19,11 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/actpool/actioniterator"
 	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/state"
@@ -59,6 +61,9 @@ type (
 		putStateFunc  func(string, []byte, interface{}) error
 		revertFunc    func(int) error
 		snapshotFunc  func() int
+		storageDiffs  *storageDiffs
+		// buffers are returned to the pool on release, nil if the working set does not own pooled buffers
+		buffers *workingSetBuffers
 	}
 
 	workingSetCreator interface {
@@ -141,10 +146,39 @@ func withActionCtx(ctx context.Context, selp action.SealedEnvelope) (context.Con
 	}
 	actionCtx.IntrinsicGas = intrinsicGas
 	actionCtx.Nonce = selp.Nonce()
+	if selp.IsSponsored() {
+		if actionCtx.GasPayer, err = address.FromBytes(selp.PayerPubkey().Hash()); err != nil {
+			return nil, err
+		}
+	}
 
 	return protocol.WithActionCtx(ctx, actionCtx), nil
 }
 
+// checkGasPayer checks the payer of a sponsored action can pay the max gas fee of the action, which is validated by the
+// actpool against the payer's balance when the action is added, but the balance may drop since then
+func (ws *workingSet) checkGasPayer(selp action.SealedEnvelope) error {
+	if !selp.IsSponsored() {
+		return nil
+	}
+	payer, err := address.FromBytes(selp.PayerPubkey().Hash())
+	if err != nil {
+		return err
+	}
+	payerState, err := accountutil.AccountState(ws, payer.String())
+	if err != nil {
+		return err
+	}
+	fee, err := selp.GasFee()
+	if err != nil {
+		return err
+	}
+	if payerState.Balance.Cmp(fee) < 0 {
+		return errors.Wrapf(action.ErrInsufficientBalanceForGas, "payer %s cannot pay gas fee %s", payer, fee)
+	}
+	return nil
+}
+
 func (ws *workingSet) runAction(
 	ctx context.Context,
 	elp action.SealedEnvelope,
@@ -174,6 +208,29 @@ func (ws *workingSet) runAction(
 	return nil, nil
 }
 
+// simulate validates and runs an action, as in a block
+func (ws *workingSet) simulate(ctx context.Context, selp action.SealedEnvelope) (*action.Receipt, error) {
+	ctx, err := withActionCtx(ctx, selp)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range protocol.MustGetRegistry(ctx).All() {
+		if validator, ok := p.(protocol.ActionValidator); ok {
+			if err := validator.Validate(ctx, selp.Action(), ws); err != nil {
+				return nil, err
+			}
+		}
+	}
+	receipt, err := ws.runAction(ctx, selp)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, errors.Errorf("action %x is not handled by any protocol", selp.Hash())
+	}
+	return receipt, nil
+}
+
 func (ws *workingSet) finalize() error {
 	if ws.finalized {
 		return errors.New("Cannot finalize a working set twice")
@@ -194,6 +251,13 @@ func (ws *workingSet) Revert(snapshot int) error {
 	return ws.revertFunc(snapshot)
 }
 
+// RecordStorageChanges records the storage changes of a contract into the state diff of the block
+func (ws *workingSet) RecordStorageChanges(contract hash.Hash160, changes []evm.StorageChange) {
+	if ws.storageDiffs != nil {
+		ws.storageDiffs.record(contract, changes)
+	}
+}
+
 // Commit persists all changes in RunActions() into the DB
 func (ws *workingSet) Commit(ctx context.Context) error {
 	if err := ws.commitFunc(ws.height); err != nil {
@@ -206,6 +270,15 @@ func (ws *workingSet) Commit(ctx context.Context) error {
 	return nil
 }
 
+// release returns the buffers of the working set to the pool, the working set must not be used afterwards
+func (ws *workingSet) release() {
+	if ws.buffers == nil {
+		return
+	}
+	putWorkingSetBuffers(ws.buffers)
+	ws.buffers = nil
+}
+
 // GetDB returns the underlying DB for account/contract storage
 func (ws *workingSet) GetDB() db.KVStore {
 	return ws.dbFunc()
@@ -365,6 +438,7 @@ func (ws *workingSet) pickAndRunActions(
 	ap actpool.ActPool,
 	postSystemActions []action.SealedEnvelope,
 	allowedBlockGasResidue uint64,
+	composition config.BlockComposition,
 ) ([]action.SealedEnvelope, error) {
 	err := ws.validate(ctx)
 	if err != nil {
@@ -385,13 +459,14 @@ func (ws *workingSet) pickAndRunActions(
 	// initial action iterator
 	blkCtx := protocol.MustGetBlockCtx(ctx)
 	if ap != nil {
-		actionIterator := actioniterator.NewActionIterator(ap.PendingActionMap())
+		composer := newBlockComposer(composition)
+		actionIterator, _ := ap.PendingActionIterator(actioniterator.WithPriority(ap.IsLocal))
 		for {
 			nextAction, ok := actionIterator.Next()
 			if !ok {
 				break
 			}
-			if nextAction.GasLimit() > blkCtx.GasLimit {
+			if !composer.Fits(nextAction, blkCtx.GasLimit) {
 				actionIterator.PopAccount()
 				continue
 			}
@@ -404,6 +479,9 @@ func (ws *workingSet) pickAndRunActions(
 					}
 				}
 			}
+			if err == nil {
+				err = ws.checkGasPayer(nextAction)
+			}
 			if err != nil {
 				caller, err := address.FromBytes(nextAction.SrcPubkey().Hash())
 				if err != nil {
@@ -429,6 +507,7 @@ func (ws *workingSet) pickAndRunActions(
 				receipts = append(receipts, receipt)
 			}
 			executedActions = append(executedActions, nextAction)
+			composer.Add(nextAction)
 
 			// To prevent loop all actions in act_pool, we stop processing action when remaining gas is below
 			// than certain threshold
@@ -484,8 +563,9 @@ func (ws *workingSet) CreateBuilder(
 	ap actpool.ActPool,
 	postSystemActions []action.SealedEnvelope,
 	allowedBlockGasResidue uint64,
+	composition config.BlockComposition,
 ) (*block.Builder, error) {
-	actions, err := ws.pickAndRunActions(ctx, ap, postSystemActions, allowedBlockGasResidue)
+	actions, err := ws.pickAndRunActions(ctx, ap, postSystemActions, allowedBlockGasResidue, composition)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/db/batch"
+)
+
+var (
+	workingSetPoolMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_workingset_pool",
+			Help: "IoTeX working set pool, the buffers allocated, taken from and returned to the pool",
+		},
+		[]string{"type"},
+	)
+
+	workingSetPool = sync.Pool{
+		New: func() interface{} {
+			workingSetPoolMtc.WithLabelValues("alloc").Inc()
+			return &workingSetBuffers{
+				batch:        batch.NewCachedBatch(),
+				dock:         protocol.NewDock(),
+				storageDiffs: newStorageDiffs(),
+			}
+		},
+	}
+)
+
+func init() {
+	prometheus.MustRegister(workingSetPoolMtc)
+}
+
+// workingSetBuffers are the buffers a working set fills while running a block. They are reset and reused by the
+// working sets of the following blocks once the working set is committed or discarded.
+type workingSetBuffers struct {
+	batch        batch.CachedBatch
+	dock         protocol.Dock
+	storageDiffs *storageDiffs
+}
+
+// getWorkingSetBuffers takes the buffers from the pool, which are allocated if the pool is empty
+func getWorkingSetBuffers() *workingSetBuffers {
+	workingSetPoolMtc.WithLabelValues("get").Inc()
+	return workingSetPool.Get().(*workingSetBuffers)
+}
+
+// putWorkingSetBuffers resets the buffers and returns them to the pool
+func putWorkingSetBuffers(b *workingSetBuffers) {
+	b.batch.Clear()
+	b.dock.Reset()
+	b.storageDiffs.reset()
+	workingSetPoolMtc.WithLabelValues("put").Inc()
+	workingSetPool.Put(b)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestWorkingSetBuffers(t *testing.T) {
+	r := require.New(t)
+	b := getWorkingSetBuffers()
+	b.batch.Put("ns", []byte("key"), []byte("value"), "")
+	b.batch.Snapshot()
+	r.NoError(b.dock.Load("ns", "key", &testString{"value"}))
+	b.storageDiffs.record(hash.ZeroHash160, []evm.StorageChange{{Key: hash.ZeroHash256, NewValue: []byte{1}}})
+
+	// the buffers are reset on return
+	putWorkingSetBuffers(b)
+	r.Zero(b.batch.Size())
+	_, err := b.batch.Get("ns", []byte("key"))
+	r.Error(err)
+	r.Error(b.batch.Revert(0))
+	r.False(b.dock.ProtocolDirty("ns"))
+	r.Empty(b.storageDiffs.changed())
+	r.Empty(b.storageDiffs.index)
+}
+
+func TestPutBlockReleasesWorkingSet(t *testing.T) {
+	r := require.New(t)
+	sf, ctx := startTestFactory(t, config.Default, 0)
+	defer func() {
+		r.NoError(sf.Stop(ctx))
+	}()
+
+	selp, err := testutil.SignedTransfer(identityset.Address(31).String(), identityset.PrivateKey(28), 1, big.NewInt(10), nil, 20000, big.NewInt(0))
+	r.NoError(err)
+	blk, err := block.NewTestingBuilder().
+		SetHeight(1).
+		SetPrevBlockHash(hash.ZeroHash256).
+		SetTimeStamp(testutil.TimestampNow()).
+		AddActions([]action.SealedEnvelope{selp}...).
+		SignAndBuild(identityset.PrivateKey(27))
+	r.NoError(err)
+	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+		BlockHeight: 1,
+		Producer:    identityset.Address(27),
+		GasLimit:    1000000,
+	})
+	f := sf.(*factory)
+	key := generateWorkingSetCacheKey(blk.Header, blk.Header.ProducerAddress())
+	ws, _, err := f.getFromWorkingSets(ctx, key)
+	r.NoError(err)
+	r.NotNil(ws.buffers)
+	r.NoError(ws.Process(protocol.WithRegistry(ctx, f.registry), blk.RunnableActions().Actions()))
+	f.putIntoWorkingSets(key, ws)
+
+	// the committed working set leaves the cache and returns its buffers
+	r.NoError(sf.PutBlock(ctx, &blk))
+	_, ok := f.workingsets.Get(key)
+	r.False(ok)
+	r.Nil(ws.buffers)
+	h, err := sf.Height()
+	r.NoError(err)
+	r.EqualValues(1, h)
+}
@@ -10,7 +10,10 @@ import (
 	hash "github.com/iotexproject/go-pkgs/hash"
 	address "github.com/iotexproject/iotex-address/address"
 	action "github.com/iotexproject/iotex-core/action"
+	actpool "github.com/iotexproject/iotex-core/actpool"
+	actioniterator "github.com/iotexproject/iotex-core/actpool/actioniterator"
 	block "github.com/iotexproject/iotex-core/blockchain/block"
+	big "math/big"
 	reflect "reflect"
 )
 
@@ -51,6 +54,34 @@ func (mr *MockActPoolMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockActPool)(nil).Validate), arg0, arg1)
 }
 
+// Start mocks base method
+func (m *MockActPool) Start(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start
+func (mr *MockActPoolMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockActPool)(nil).Start), arg0)
+}
+
+// Stop mocks base method
+func (m *MockActPool) Stop(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockActPoolMockRecorder) Stop(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockActPool)(nil).Stop), arg0)
+}
+
 // Reset mocks base method
 func (m *MockActPool) Reset() {
 	m.ctrl.T.Helper()
@@ -77,6 +108,54 @@ func (mr *MockActPoolMockRecorder) PendingActionMap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingActionMap", reflect.TypeOf((*MockActPool)(nil).PendingActionMap))
 }
 
+// PendingActionIterator mocks base method
+func (m *MockActPool) PendingActionIterator(arg0 ...actioniterator.Option) (actioniterator.ActionIterator, uint64) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PendingActionIterator", varargs...)
+	ret0, _ := ret[0].(actioniterator.ActionIterator)
+	ret1, _ := ret[1].(uint64)
+	return ret0, ret1
+}
+
+// PendingActionIterator indicates an expected call of PendingActionIterator
+func (mr *MockActPoolMockRecorder) PendingActionIterator(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingActionIterator", reflect.TypeOf((*MockActPool)(nil).PendingActionIterator), arg0...)
+}
+
+// PendingActionDiff mocks base method
+func (m *MockActPool) PendingActionDiff(snapshot uint64) (*actpool.ActionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingActionDiff", snapshot)
+	ret0, _ := ret[0].(*actpool.ActionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingActionDiff indicates an expected call of PendingActionDiff
+func (mr *MockActPoolMockRecorder) PendingActionDiff(snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingActionDiff", reflect.TypeOf((*MockActPool)(nil).PendingActionDiff), snapshot)
+}
+
+// Content mocks base method
+func (m *MockActPool) Content() map[string]actpool.AccountContent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Content")
+	ret0, _ := ret[0].(map[string]actpool.AccountContent)
+	return ret0
+}
+
+// Content indicates an expected call of Content
+func (mr *MockActPoolMockRecorder) Content() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Content", reflect.TypeOf((*MockActPool)(nil).Content))
+}
+
 // Add mocks base method
 func (m *MockActPool) Add(ctx context.Context, act action.SealedEnvelope) error {
 	m.ctrl.T.Helper()
@@ -135,6 +214,49 @@ func (mr *MockActPoolMockRecorder) GetActionByHash(hash interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionByHash", reflect.TypeOf((*MockActPool)(nil).GetActionByHash), hash)
 }
 
+// GetDropReason mocks base method
+func (m *MockActPool) GetDropReason(hash hash.Hash256) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDropReason", hash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDropReason indicates an expected call of GetDropReason
+func (mr *MockActPoolMockRecorder) GetDropReason(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDropReason", reflect.TypeOf((*MockActPool)(nil).GetDropReason), hash)
+}
+
+// IsLocal mocks base method
+func (m *MockActPool) IsLocal(addr string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLocal", addr)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLocal indicates an expected call of IsLocal
+func (mr *MockActPoolMockRecorder) IsLocal(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLocal", reflect.TypeOf((*MockActPool)(nil).IsLocal), addr)
+}
+
+// MinGasPrice mocks base method
+func (m *MockActPool) MinGasPrice() *big.Int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MinGasPrice")
+	ret0, _ := ret[0].(*big.Int)
+	return ret0
+}
+
+// MinGasPrice indicates an expected call of MinGasPrice
+func (mr *MockActPoolMockRecorder) MinGasPrice() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MinGasPrice", reflect.TypeOf((*MockActPool)(nil).MinGasPrice))
+}
+
 // GetSize mocks base method
 func (m *MockActPool) GetSize() uint64 {
 	m.ctrl.T.Helper()
@@ -232,3 +354,62 @@ func (mr *MockActPoolMockRecorder) AddActionEnvelopeValidators(arg0 ...interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActionEnvelopeValidators", reflect.TypeOf((*MockActPool)(nil).AddActionEnvelopeValidators), arg0...)
 }
+
+// AddSubscriber mocks base method
+func (m *MockActPool) AddSubscriber(arg0 actpool.ActionSubscriber) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddSubscriber", arg0)
+}
+
+// AddSubscriber indicates an expected call of AddSubscriber
+func (mr *MockActPoolMockRecorder) AddSubscriber(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSubscriber", reflect.TypeOf((*MockActPool)(nil).AddSubscriber), arg0)
+}
+
+// RemoveSubscriber mocks base method
+func (m *MockActPool) RemoveSubscriber(arg0 actpool.ActionSubscriber) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveSubscriber", arg0)
+}
+
+// RemoveSubscriber indicates an expected call of RemoveSubscriber
+func (mr *MockActPoolMockRecorder) RemoveSubscriber(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSubscriber", reflect.TypeOf((*MockActPool)(nil).RemoveSubscriber), arg0)
+}
+
+// MockActionSubscriber is a mock of ActionSubscriber interface
+type MockActionSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockActionSubscriberMockRecorder
+}
+
+// MockActionSubscriberMockRecorder is the mock recorder for MockActionSubscriber
+type MockActionSubscriberMockRecorder struct {
+	mock *MockActionSubscriber
+}
+
+// NewMockActionSubscriber creates a new mock instance
+func NewMockActionSubscriber(ctrl *gomock.Controller) *MockActionSubscriber {
+	mock := &MockActionSubscriber{ctrl: ctrl}
+	mock.recorder = &MockActionSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockActionSubscriber) EXPECT() *MockActionSubscriberMockRecorder {
+	return m.recorder
+}
+
+// ReceiveAction mocks base method
+func (m *MockActionSubscriber) ReceiveAction(arg0 action.SealedEnvelope) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReceiveAction", arg0)
+}
+
+// ReceiveAction indicates an expected call of ReceiveAction
+func (mr *MockActionSubscriberMockRecorder) ReceiveAction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveAction", reflect.TypeOf((*MockActionSubscriber)(nil).ReceiveAction), arg0)
+}
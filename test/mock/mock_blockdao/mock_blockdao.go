@@ -10,6 +10,8 @@ import (
 	hash "github.com/iotexproject/go-pkgs/hash"
 	action "github.com/iotexproject/iotex-core/action"
 	block "github.com/iotexproject/iotex-core/blockchain/block"
+	blockdao "github.com/iotexproject/iotex-core/blockchain/blockdao"
+	filedao "github.com/iotexproject/iotex-core/blockchain/filedao"
 	iotextypes "github.com/iotexproject/iotex-proto/golang/iotextypes"
 	reflect "reflect"
 )
@@ -257,6 +259,21 @@ func (mr *MockBlockDAOMockRecorder) FooterByHeight(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FooterByHeight", reflect.TypeOf((*MockBlockDAO)(nil).FooterByHeight), arg0)
 }
 
+// BlockStoresInRange mocks base method
+func (m *MockBlockDAO) BlockStoresInRange(arg0, arg1 uint64) (filedao.BlockStoreIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockStoresInRange", arg0, arg1)
+	ret0, _ := ret[0].(filedao.BlockStoreIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockStoresInRange indicates an expected call of BlockStoresInRange
+func (mr *MockBlockDAOMockRecorder) BlockStoresInRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockStoresInRange", reflect.TypeOf((*MockBlockDAO)(nil).BlockStoresInRange), arg0, arg1)
+}
+
 // GetActionByActionHash mocks base method
 func (m *MockBlockDAO) GetActionByActionHash(arg0 hash.Hash256, arg1 uint64) (action.SealedEnvelope, error) {
 	m.ctrl.T.Helper()
@@ -301,6 +318,112 @@ func (mr *MockBlockDAOMockRecorder) DeleteBlockToTarget(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlockToTarget", reflect.TypeOf((*MockBlockDAO)(nil).DeleteBlockToTarget), arg0)
 }
 
+// BlocksInRange mocks base method
+func (m *MockBlockDAO) BlocksInRange(arg0, arg1 uint64) (blockdao.BlockIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlocksInRange", arg0, arg1)
+	ret0, _ := ret[0].(blockdao.BlockIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlocksInRange indicates an expected call of BlocksInRange
+func (mr *MockBlockDAOMockRecorder) BlocksInRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlocksInRange", reflect.TypeOf((*MockBlockDAO)(nil).BlocksInRange), arg0, arg1)
+}
+
+// ReceiptsInRange mocks base method
+func (m *MockBlockDAO) ReceiptsInRange(arg0, arg1 uint64) (blockdao.ReceiptIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiptsInRange", arg0, arg1)
+	ret0, _ := ret[0].(blockdao.ReceiptIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiptsInRange indicates an expected call of ReceiptsInRange
+func (mr *MockBlockDAOMockRecorder) ReceiptsInRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiptsInRange", reflect.TypeOf((*MockBlockDAO)(nil).ReceiptsInRange), arg0, arg1)
+}
+
+// MockBlockIterator is a mock of BlockIterator interface
+type MockBlockIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockIteratorMockRecorder
+}
+
+// MockBlockIteratorMockRecorder is the mock recorder for MockBlockIterator
+type MockBlockIteratorMockRecorder struct {
+	mock *MockBlockIterator
+}
+
+// NewMockBlockIterator creates a new mock instance
+func NewMockBlockIterator(ctrl *gomock.Controller) *MockBlockIterator {
+	mock := &MockBlockIterator{ctrl: ctrl}
+	mock.recorder = &MockBlockIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBlockIterator) EXPECT() *MockBlockIteratorMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method
+func (m *MockBlockIterator) Next() (*block.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(*block.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next
+func (mr *MockBlockIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockBlockIterator)(nil).Next))
+}
+
+// MockReceiptIterator is a mock of ReceiptIterator interface
+type MockReceiptIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiptIteratorMockRecorder
+}
+
+// MockReceiptIteratorMockRecorder is the mock recorder for MockReceiptIterator
+type MockReceiptIteratorMockRecorder struct {
+	mock *MockReceiptIterator
+}
+
+// NewMockReceiptIterator creates a new mock instance
+func NewMockReceiptIterator(ctrl *gomock.Controller) *MockReceiptIterator {
+	mock := &MockReceiptIterator{ctrl: ctrl}
+	mock.recorder = &MockReceiptIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReceiptIterator) EXPECT() *MockReceiptIteratorMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method
+func (m *MockReceiptIterator) Next() ([]*action.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].([]*action.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next
+func (mr *MockReceiptIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockReceiptIterator)(nil).Next))
+}
+
 // MockBlockIndexer is a mock of BlockIndexer interface
 type MockBlockIndexer struct {
 	ctrl     *gomock.Controller
@@ -7,10 +7,13 @@ package mock_blocksync
 import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
+	hash "github.com/iotexproject/go-pkgs/hash"
 	block "github.com/iotexproject/iotex-core/blockchain/block"
+	factory "github.com/iotexproject/iotex-core/state/factory"
 	iotexrpc "github.com/iotexproject/iotex-proto/golang/iotexrpc"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	reflect "reflect"
+	time "time"
 )
 
 // MockBlockDAO is a mock of BlockDAO interface
@@ -157,3 +160,130 @@ func (mr *MockBlockSyncMockRecorder) ProcessBlockSync(ctx, blk interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBlockSync", reflect.TypeOf((*MockBlockSync)(nil).ProcessBlockSync), ctx, blk)
 }
+
+// ProcessHeaderRequest mocks base method
+func (m *MockBlockSync) ProcessHeaderRequest(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessHeaderRequest", ctx, peer, start, end)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessHeaderRequest indicates an expected call of ProcessHeaderRequest
+func (mr *MockBlockSyncMockRecorder) ProcessHeaderRequest(ctx, peer, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessHeaderRequest", reflect.TypeOf((*MockBlockSync)(nil).ProcessHeaderRequest), ctx, peer, start, end)
+}
+
+// ProcessHeaders mocks base method
+func (m *MockBlockSync) ProcessHeaders(ctx context.Context, peer peerstore.PeerInfo, headers []*block.Header) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessHeaders", ctx, peer, headers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessHeaders indicates an expected call of ProcessHeaders
+func (mr *MockBlockSyncMockRecorder) ProcessHeaders(ctx, peer, headers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessHeaders", reflect.TypeOf((*MockBlockSync)(nil).ProcessHeaders), ctx, peer, headers)
+}
+
+// ProcessBlockAnnouncement mocks base method
+func (m *MockBlockSync) ProcessBlockAnnouncement(ctx context.Context, peer peerstore.PeerInfo, height uint64, h hash.Hash256) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessBlockAnnouncement", ctx, peer, height, h)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessBlockAnnouncement indicates an expected call of ProcessBlockAnnouncement
+func (mr *MockBlockSyncMockRecorder) ProcessBlockAnnouncement(ctx, peer, height, h interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBlockAnnouncement", reflect.TypeOf((*MockBlockSync)(nil).ProcessBlockAnnouncement), ctx, peer, height, h)
+}
+
+// ProcessStateRangeRequest mocks base method
+func (m *MockBlockSync) ProcessStateRangeRequest(ctx context.Context, peer peerstore.PeerInfo, id, height uint64, nsKey, start []byte, limit uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessStateRangeRequest", ctx, peer, id, height, nsKey, start, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessStateRangeRequest indicates an expected call of ProcessStateRangeRequest
+func (mr *MockBlockSyncMockRecorder) ProcessStateRangeRequest(ctx, peer, id, height, nsKey, start, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessStateRangeRequest", reflect.TypeOf((*MockBlockSync)(nil).ProcessStateRangeRequest), ctx, peer, id, height, nsKey, start, limit)
+}
+
+// ProcessStateRange mocks base method
+func (m *MockBlockSync) ProcessStateRange(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessStateRange", ctx, peer, id, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessStateRange indicates an expected call of ProcessStateRange
+func (mr *MockBlockSyncMockRecorder) ProcessStateRange(ctx, peer, id, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessStateRange", reflect.TypeOf((*MockBlockSync)(nil).ProcessStateRange), ctx, peer, id, data)
+}
+
+// FetchStateRange mocks base method
+func (m *MockBlockSync) FetchStateRange(ctx context.Context, height uint64, nsKey, start []byte, limit uint64) (*factory.StateRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchStateRange", ctx, height, nsKey, start, limit)
+	ret0, _ := ret[0].(*factory.StateRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchStateRange indicates an expected call of FetchStateRange
+func (mr *MockBlockSyncMockRecorder) FetchStateRange(ctx, height, nsKey, start, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchStateRange", reflect.TypeOf((*MockBlockSync)(nil).FetchStateRange), ctx, height, nsKey, start, limit)
+}
+
+// SyncStatus mocks base method
+func (m *MockBlockSync) SyncStatus() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncStatus")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SyncStatus indicates an expected call of SyncStatus
+func (mr *MockBlockSyncMockRecorder) SyncStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MockBlockSync)(nil).SyncStatus))
+}
+
+// SyncSpeed mocks base method
+func (m *MockBlockSync) SyncSpeed() map[string]float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncSpeed")
+	ret0, _ := ret[0].(map[string]float64)
+	return ret0
+}
+
+// SyncSpeed indicates an expected call of SyncSpeed
+func (mr *MockBlockSyncMockRecorder) SyncSpeed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSpeed", reflect.TypeOf((*MockBlockSync)(nil).SyncSpeed))
+}
+
+// SyncETA mocks base method
+func (m *MockBlockSync) SyncETA() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncETA")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// SyncETA indicates an expected call of SyncETA
+func (mr *MockBlockSyncMockRecorder) SyncETA() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncETA", reflect.TypeOf((*MockBlockSync)(nil).SyncETA))
+}
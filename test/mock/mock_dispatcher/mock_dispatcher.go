@@ -8,6 +8,7 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	proto "github.com/golang/protobuf/proto"
+	hash "github.com/iotexproject/go-pkgs/hash"
 	dispatcher "github.com/iotexproject/iotex-core/dispatcher"
 	iotexrpc "github.com/iotexproject/iotex-proto/golang/iotexrpc"
 	iotextypes "github.com/iotexproject/iotex-proto/golang/iotextypes"
@@ -108,6 +109,104 @@ func (mr *MockSubscriberMockRecorder) HandleConsensusMsg(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleConsensusMsg", reflect.TypeOf((*MockSubscriber)(nil).HandleConsensusMsg), arg0)
 }
 
+// HandleActionAnnouncement mocks base method
+func (m *MockSubscriber) HandleActionAnnouncement(arg0 context.Context, arg1 peerstore.PeerInfo, arg2 []hash.Hash256) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleActionAnnouncement", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleActionAnnouncement indicates an expected call of HandleActionAnnouncement
+func (mr *MockSubscriberMockRecorder) HandleActionAnnouncement(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleActionAnnouncement", reflect.TypeOf((*MockSubscriber)(nil).HandleActionAnnouncement), arg0, arg1, arg2)
+}
+
+// HandleActionRequest mocks base method
+func (m *MockSubscriber) HandleActionRequest(arg0 context.Context, arg1 peerstore.PeerInfo, arg2 []hash.Hash256) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleActionRequest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleActionRequest indicates an expected call of HandleActionRequest
+func (mr *MockSubscriberMockRecorder) HandleActionRequest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleActionRequest", reflect.TypeOf((*MockSubscriber)(nil).HandleActionRequest), arg0, arg1, arg2)
+}
+
+// HandleHeaderRequest mocks base method
+func (m *MockSubscriber) HandleHeaderRequest(arg0 context.Context, arg1 peerstore.PeerInfo, arg2, arg3 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleHeaderRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleHeaderRequest indicates an expected call of HandleHeaderRequest
+func (mr *MockSubscriberMockRecorder) HandleHeaderRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleHeaderRequest", reflect.TypeOf((*MockSubscriber)(nil).HandleHeaderRequest), arg0, arg1, arg2, arg3)
+}
+
+// HandleHeaders mocks base method
+func (m *MockSubscriber) HandleHeaders(arg0 context.Context, arg1 peerstore.PeerInfo, arg2 []*iotextypes.BlockHeader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleHeaders", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleHeaders indicates an expected call of HandleHeaders
+func (mr *MockSubscriberMockRecorder) HandleHeaders(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleHeaders", reflect.TypeOf((*MockSubscriber)(nil).HandleHeaders), arg0, arg1, arg2)
+}
+
+// HandleBlockAnnouncement mocks base method
+func (m *MockSubscriber) HandleBlockAnnouncement(arg0 context.Context, arg1 peerstore.PeerInfo, arg2 uint64, arg3 hash.Hash256) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleBlockAnnouncement", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleBlockAnnouncement indicates an expected call of HandleBlockAnnouncement
+func (mr *MockSubscriberMockRecorder) HandleBlockAnnouncement(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBlockAnnouncement", reflect.TypeOf((*MockSubscriber)(nil).HandleBlockAnnouncement), arg0, arg1, arg2, arg3)
+}
+
+// HandleStateRangeRequest mocks base method
+func (m *MockSubscriber) HandleStateRangeRequest(arg0 context.Context, arg1 peerstore.PeerInfo, arg2, arg3 uint64, arg4, arg5 []byte, arg6 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleStateRangeRequest", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleStateRangeRequest indicates an expected call of HandleStateRangeRequest
+func (mr *MockSubscriberMockRecorder) HandleStateRangeRequest(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleStateRangeRequest", reflect.TypeOf((*MockSubscriber)(nil).HandleStateRangeRequest), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// HandleStateRange mocks base method
+func (m *MockSubscriber) HandleStateRange(arg0 context.Context, arg1 peerstore.PeerInfo, arg2 uint64, arg3 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleStateRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleStateRange indicates an expected call of HandleStateRange
+func (mr *MockSubscriberMockRecorder) HandleStateRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleStateRange", reflect.TypeOf((*MockSubscriber)(nil).HandleStateRange), arg0, arg1, arg2, arg3)
+}
+
 // MockDispatcher is a mock of Dispatcher interface
 type MockDispatcher struct {
 	ctrl     *gomock.Controller
@@ -194,3 +293,87 @@ func (mr *MockDispatcherMockRecorder) HandleTell(arg0, arg1, arg2, arg3 interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTell", reflect.TypeOf((*MockDispatcher)(nil).HandleTell), arg0, arg1, arg2, arg3)
 }
+
+// HandleActionAnnouncement mocks base method
+func (m *MockDispatcher) HandleActionAnnouncement(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3 []hash.Hash256) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleActionAnnouncement", arg0, arg1, arg2, arg3)
+}
+
+// HandleActionAnnouncement indicates an expected call of HandleActionAnnouncement
+func (mr *MockDispatcherMockRecorder) HandleActionAnnouncement(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleActionAnnouncement", reflect.TypeOf((*MockDispatcher)(nil).HandleActionAnnouncement), arg0, arg1, arg2, arg3)
+}
+
+// HandleActionRequest mocks base method
+func (m *MockDispatcher) HandleActionRequest(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3 []hash.Hash256) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleActionRequest", arg0, arg1, arg2, arg3)
+}
+
+// HandleActionRequest indicates an expected call of HandleActionRequest
+func (mr *MockDispatcherMockRecorder) HandleActionRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleActionRequest", reflect.TypeOf((*MockDispatcher)(nil).HandleActionRequest), arg0, arg1, arg2, arg3)
+}
+
+// HandleHeaderRequest mocks base method
+func (m *MockDispatcher) HandleHeaderRequest(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3, arg4 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleHeaderRequest", arg0, arg1, arg2, arg3, arg4)
+}
+
+// HandleHeaderRequest indicates an expected call of HandleHeaderRequest
+func (mr *MockDispatcherMockRecorder) HandleHeaderRequest(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleHeaderRequest", reflect.TypeOf((*MockDispatcher)(nil).HandleHeaderRequest), arg0, arg1, arg2, arg3, arg4)
+}
+
+// HandleHeaders mocks base method
+func (m *MockDispatcher) HandleHeaders(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3 []*iotextypes.BlockHeader) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleHeaders", arg0, arg1, arg2, arg3)
+}
+
+// HandleHeaders indicates an expected call of HandleHeaders
+func (mr *MockDispatcherMockRecorder) HandleHeaders(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleHeaders", reflect.TypeOf((*MockDispatcher)(nil).HandleHeaders), arg0, arg1, arg2, arg3)
+}
+
+// HandleBlockAnnouncement mocks base method
+func (m *MockDispatcher) HandleBlockAnnouncement(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3 uint64, arg4 hash.Hash256) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleBlockAnnouncement", arg0, arg1, arg2, arg3, arg4)
+}
+
+// HandleBlockAnnouncement indicates an expected call of HandleBlockAnnouncement
+func (mr *MockDispatcherMockRecorder) HandleBlockAnnouncement(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBlockAnnouncement", reflect.TypeOf((*MockDispatcher)(nil).HandleBlockAnnouncement), arg0, arg1, arg2, arg3, arg4)
+}
+
+// HandleStateRangeRequest mocks base method
+func (m *MockDispatcher) HandleStateRangeRequest(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3, arg4 uint64, arg5, arg6 []byte, arg7 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleStateRangeRequest", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+}
+
+// HandleStateRangeRequest indicates an expected call of HandleStateRangeRequest
+func (mr *MockDispatcherMockRecorder) HandleStateRangeRequest(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleStateRangeRequest", reflect.TypeOf((*MockDispatcher)(nil).HandleStateRangeRequest), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+}
+
+// HandleStateRange mocks base method
+func (m *MockDispatcher) HandleStateRange(arg0 context.Context, arg1 uint32, arg2 peerstore.PeerInfo, arg3 uint64, arg4 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleStateRange", arg0, arg1, arg2, arg3, arg4)
+}
+
+// HandleStateRange indicates an expected call of HandleStateRange
+func (mr *MockDispatcherMockRecorder) HandleStateRange(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleStateRange", reflect.TypeOf((*MockDispatcher)(nil).HandleStateRange), arg0, arg1, arg2, arg3, arg4)
+}
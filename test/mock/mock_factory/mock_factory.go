@@ -14,6 +14,7 @@ import (
 	actpool "github.com/iotexproject/iotex-core/actpool"
 	block "github.com/iotexproject/iotex-core/blockchain/block"
 	state "github.com/iotexproject/iotex-core/state"
+	factory "github.com/iotexproject/iotex-core/state/factory"
 	reflect "reflect"
 )
 
@@ -197,6 +198,68 @@ func (mr *MockFactoryMockRecorder) SimulateExecution(arg0, arg1, arg2, arg3 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateExecution", reflect.TypeOf((*MockFactory)(nil).SimulateExecution), arg0, arg1, arg2, arg3)
 }
 
+// SimulateExecutionAtHeight mocks base method
+func (m *MockFactory) SimulateExecutionAtHeight(arg0 context.Context, arg1 uint64, arg2 address.Address, arg3 *action.Execution, arg4 evm.GetBlockHash) ([]byte, *action.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateExecutionAtHeight", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*action.Receipt)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SimulateExecutionAtHeight indicates an expected call of SimulateExecutionAtHeight
+func (mr *MockFactoryMockRecorder) SimulateExecutionAtHeight(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateExecutionAtHeight", reflect.TypeOf((*MockFactory)(nil).SimulateExecutionAtHeight), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SimulateExecutions mocks base method
+func (m *MockFactory) SimulateExecutions(arg0 context.Context, arg1 []*factory.Simulation, arg2 evm.GetBlockHash) ([]*factory.SimulationResult, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateExecutions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*factory.SimulationResult)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SimulateExecutions indicates an expected call of SimulateExecutions
+func (mr *MockFactoryMockRecorder) SimulateExecutions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateExecutions", reflect.TypeOf((*MockFactory)(nil).SimulateExecutions), arg0, arg1, arg2)
+}
+
+// SimulateAction mocks base method
+func (m *MockFactory) SimulateAction(arg0 context.Context, arg1 []action.SealedEnvelope, arg2 action.SealedEnvelope) (*action.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateAction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*action.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateAction indicates an expected call of SimulateAction
+func (mr *MockFactoryMockRecorder) SimulateAction(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateAction", reflect.TypeOf((*MockFactory)(nil).SimulateAction), arg0, arg1, arg2)
+}
+
+// PendingState mocks base method
+func (m *MockFactory) PendingState(arg0 context.Context, arg1 []action.SealedEnvelope) (*factory.PendingState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingState", arg0, arg1)
+	ret0, _ := ret[0].(*factory.PendingState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingState indicates an expected call of PendingState
+func (mr *MockFactoryMockRecorder) PendingState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingState", reflect.TypeOf((*MockFactory)(nil).PendingState), arg0, arg1)
+}
+
 // PutBlock mocks base method
 func (m *MockFactory) PutBlock(arg0 context.Context, arg1 *block.Block) error {
 	m.ctrl.T.Helper()
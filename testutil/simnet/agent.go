@@ -0,0 +1,296 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package simnet
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	goproto "github.com/iotexproject/iotex-proto/golang"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	peer "github.com/libp2p/go-libp2p-peer"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/p2p"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+type (
+	// Handler handles the messages received by an agent, which is usually the dispatcher of the node
+	Handler interface {
+		HandleBroadcast(context.Context, uint32, proto.Message)
+		HandleTell(context.Context, uint32, peerstore.PeerInfo, proto.Message)
+		HandleActionAnnouncement(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256)
+		HandleActionRequest(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256)
+		HandleHeaderRequest(context.Context, uint32, peerstore.PeerInfo, uint64, uint64)
+		HandleHeaders(context.Context, uint32, peerstore.PeerInfo, []*iotextypes.BlockHeader)
+		HandleBlockAnnouncement(context.Context, uint32, peerstore.PeerInfo, uint64, hash.Hash256)
+		HandleStateRangeRequest(context.Context, uint32, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64)
+		HandleStateRange(context.Context, uint32, peerstore.PeerInfo, uint64, []byte)
+	}
+
+	// Agent is the agent of a node on the simulated network, which serves the chain service in place of the p2p agent
+	Agent struct {
+		mutex      sync.RWMutex
+		info       peerstore.PeerInfo
+		network    *Network
+		handler    Handler
+		started    bool
+		validators map[validatorKey]p2p.Validator
+		violations map[string][]p2p.Violation
+	}
+
+	validatorKey struct {
+		chainID uint32
+		msgType iotexrpc.MessageType
+	}
+)
+
+// NewAgent creates an agent of the ID on the network, which hands the received messages to the handler once started
+func (n *Network) NewAgent(id string, handler Handler) *Agent {
+	agent := &Agent{
+		info:       peerstore.PeerInfo{ID: peer.ID(id)},
+		network:    n,
+		handler:    handler,
+		validators: make(map[validatorKey]p2p.Validator),
+		violations: make(map[string][]p2p.Violation),
+	}
+	n.join(agent)
+	return agent
+}
+
+// Start connects the agent to the network
+func (a *Agent) Start(_ context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.started = true
+	return nil
+}
+
+// Stop disconnects the agent from the network, the messages to it are dropped
+func (a *Agent) Stop(_ context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.started = false
+	return nil
+}
+
+// ID returns the ID of the agent
+func (a *Agent) ID() string { return string(a.info.ID) }
+
+// Info returns the peer info of the agent
+func (a *Agent) Info() peerstore.PeerInfo { return a.info }
+
+func (a *Agent) running() bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.started
+}
+
+// BroadcastOutbound sends the message to all the reachable agents
+func (a *Agent) BroadcastOutbound(ctx context.Context, msg proto.Message) error {
+	p2pCtx, ok := p2p.GetContext(ctx)
+	if !ok {
+		return errors.New("P2P context doesn't exist")
+	}
+	msgType, body, err := encode(msg)
+	if err != nil {
+		return err
+	}
+	for _, to := range a.network.peers(a.ID()) {
+		to := to
+		a.network.send(a.ID(), to, func() {
+			msg, err := goproto.TypifyRPCMsg(msgType, body)
+			if err != nil {
+				log.L().Error("Failed to decode the broadcast message.", zap.Error(err))
+				return
+			}
+			ctx := p2p.WithBroadcastPeer(context.Background(), a.info.ID.Pretty())
+			if err := to.validate(ctx, p2pCtx.ChainID, msgType, msg); err != nil {
+				to.ReportViolation(a.info.ID.Pretty(), p2p.ViolationInvalidMessage)
+				return
+			}
+			to.handler.HandleBroadcast(ctx, p2pCtx.ChainID, msg)
+		})
+	}
+	return nil
+}
+
+// UnicastOutbound sends the message to the peer
+func (a *Agent) UnicastOutbound(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error {
+	msgType, body, err := encode(msg)
+	if err != nil {
+		return err
+	}
+	return a.unicast(ctx, peer, func(ctx context.Context, to *Agent, chainID uint32) {
+		msg, err := goproto.TypifyRPCMsg(msgType, body)
+		if err != nil {
+			log.L().Error("Failed to decode the unicast message.", zap.Error(err))
+			return
+		}
+		to.handler.HandleTell(ctx, chainID, a.info, msg)
+	})
+}
+
+// Neighbors returns the reachable agents
+func (a *Agent) Neighbors(_ context.Context) ([]peerstore.PeerInfo, error) {
+	var infos []peerstore.PeerInfo
+	for _, agent := range a.network.peers(a.ID()) {
+		infos = append(infos, agent.info)
+	}
+	return infos, nil
+}
+
+// RequestHeaders requests the headers from start to end from the peer
+func (a *Agent) RequestHeaders(ctx context.Context, peer peerstore.PeerInfo, start, end uint64) error {
+	return a.unicast(ctx, peer, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleHeaderRequest(ctx, chainID, a.info, start, end)
+	})
+}
+
+// SendHeaders sends the headers to the peer
+func (a *Agent) SendHeaders(ctx context.Context, peer peerstore.PeerInfo, headers []*iotextypes.BlockHeader) error {
+	copied := make([]*iotextypes.BlockHeader, len(headers))
+	for i, header := range headers {
+		copied[i] = proto.Clone(header).(*iotextypes.BlockHeader)
+	}
+	return a.unicast(ctx, peer, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleHeaders(ctx, chainID, a.info, copied)
+	})
+}
+
+// RequestStateRange requests a range of the state trie from the peer
+func (a *Agent) RequestStateRange(
+	ctx context.Context,
+	peer peerstore.PeerInfo,
+	id uint64,
+	height uint64,
+	nsKey []byte,
+	start []byte,
+	limit uint64,
+) error {
+	nsKey, start = append([]byte{}, nsKey...), append([]byte{}, start...)
+	return a.unicast(ctx, peer, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleStateRangeRequest(ctx, chainID, a.info, id, height, nsKey, start, limit)
+	})
+}
+
+// SendStateRange sends the serialized range of the state trie to the peer
+func (a *Agent) SendStateRange(ctx context.Context, peer peerstore.PeerInfo, id uint64, data []byte) error {
+	copied := append([]byte{}, data...)
+	return a.unicast(ctx, peer, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleStateRange(ctx, chainID, a.info, id, copied)
+	})
+}
+
+// AnnounceBlock announces the height and the hash of a block to the reachable agents
+func (a *Agent) AnnounceBlock(ctx context.Context, height uint64, h hash.Hash256) error {
+	return a.announce(ctx, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleBlockAnnouncement(ctx, chainID, a.info, height, h)
+	})
+}
+
+// AnnounceActions announces the hashes of the actions to the reachable agents
+func (a *Agent) AnnounceActions(ctx context.Context, hashes []hash.Hash256) error {
+	copied := append([]hash.Hash256{}, hashes...)
+	return a.announce(ctx, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleActionAnnouncement(ctx, chainID, a.info, copied)
+	})
+}
+
+// RequestActions requests the actions of the hashes from the peer
+func (a *Agent) RequestActions(ctx context.Context, peer peerstore.PeerInfo, hashes []hash.Hash256) error {
+	copied := append([]hash.Hash256{}, hashes...)
+	return a.unicast(ctx, peer, func(ctx context.Context, to *Agent, chainID uint32) {
+		to.handler.HandleActionRequest(ctx, chainID, a.info, copied)
+	})
+}
+
+// ReportViolation records the violation of the peer
+func (a *Agent) ReportViolation(peerID string, v p2p.Violation) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.violations[peerID] = append(a.violations[peerID], v)
+}
+
+// Violations returns the violations of the peer reported to the agent
+func (a *Agent) Violations(peerID string) []p2p.Violation {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return append([]p2p.Violation{}, a.violations[peerID]...)
+}
+
+// RegisterValidator registers the validator of the broadcast messages of the type on the chain
+func (a *Agent) RegisterValidator(chainID uint32, msgType iotexrpc.MessageType, v p2p.Validator) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.validators[validatorKey{chainID, msgType}] = v
+}
+
+func (a *Agent) validate(ctx context.Context, chainID uint32, msgType iotexrpc.MessageType, msg proto.Message) error {
+	a.mutex.RLock()
+	v, ok := a.validators[validatorKey{chainID, msgType}]
+	a.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	return v(ctx, msg)
+}
+
+// unicast delivers the message to the peer, which fails if the peer is not reachable
+func (a *Agent) unicast(ctx context.Context, peer peerstore.PeerInfo, deliver func(context.Context, *Agent, uint32)) error {
+	p2pCtx, ok := p2p.GetContext(ctx)
+	if !ok {
+		return errors.New("P2P context doesn't exist")
+	}
+	to, ok := a.network.peer(a.ID(), string(peer.ID))
+	if !ok {
+		return errors.Errorf("peer %s is not reachable", peer.ID.Pretty())
+	}
+	a.network.send(a.ID(), to, func() {
+		deliver(p2p.WithUnicastPeer(context.Background(), a.info.ID.Pretty()), to, p2pCtx.ChainID)
+	})
+	return nil
+}
+
+// announce delivers the message to the reachable agents
+func (a *Agent) announce(ctx context.Context, deliver func(context.Context, *Agent, uint32)) error {
+	p2pCtx, ok := p2p.GetContext(ctx)
+	if !ok {
+		return errors.New("P2P context doesn't exist")
+	}
+	for _, to := range a.network.peers(a.ID()) {
+		to := to
+		a.network.send(a.ID(), to, func() {
+			deliver(p2p.WithUnicastPeer(context.Background(), a.info.ID.Pretty()), to, p2pCtx.ChainID)
+		})
+	}
+	return nil
+}
+
+// encode marshals the message as it's sent on the p2p network, so that the receivers never share it with the sender
+func encode(msg proto.Message) (iotexrpc.MessageType, []byte, error) {
+	msgType, err := goproto.GetTypeFromRPCMsg(msg)
+	if err != nil {
+		return 0, nil, err
+	}
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "error when marshaling message")
+	}
+	return msgType, body, nil
+}
+
+func sortAgents(agents []*Agent) {
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID() < agents[j].ID() })
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package simnet simulates the p2p network of the in-process nodes, delivering the messages between them with the
+// configurable latency, drop rate and partitions, so that the consensus and sync can be tested without a testnet.
+package simnet
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Config is the config of the simulated network
+	Config struct {
+		// Latency is the delay of delivering a message
+		Latency time.Duration
+		// Jitter is the max random delay added to the latency, which reorders the messages
+		Jitter time.Duration
+		// DropRate is the probability of a message being dropped
+		DropRate float64
+		// Seed seeds the drops and the jitters, so that a run is reproducible given the same order of the messages
+		Seed int64
+	}
+
+	// Network is a simulated network delivering the messages between the agents
+	Network struct {
+		mutex     sync.RWMutex
+		cfg       Config
+		rand      *rand.Rand
+		agents    map[string]*Agent
+		partition map[string]int
+		pending   sync.WaitGroup
+		delivered uint64
+		dropped   uint64
+	}
+)
+
+// NewNetwork creates a simulated network
+func NewNetwork(cfg Config) *Network {
+	return &Network{
+		cfg:       cfg,
+		rand:      rand.New(rand.NewSource(cfg.Seed)),
+		agents:    make(map[string]*Agent),
+		partition: make(map[string]int),
+	}
+}
+
+// SetLatency sets the latency and the jitter of the messages sent afterwards
+func (n *Network) SetLatency(latency, jitter time.Duration) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.cfg.Latency, n.cfg.Jitter = latency, jitter
+}
+
+// SetDropRate sets the probability of the messages sent afterwards being dropped
+func (n *Network) SetDropRate(rate float64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.cfg.DropRate = rate
+}
+
+// Partition splits the network into the groups of the agents, where the agents in different groups can't reach each
+// other. The agents not in any group form a group of their own. The messages in flight across the groups are dropped.
+func (n *Network) Partition(groups ...[]string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.partition = make(map[string]int)
+	for i, group := range groups {
+		for _, id := range group {
+			n.partition[id] = i + 1
+		}
+	}
+}
+
+// Heal removes the partitions of the network
+func (n *Network) Heal() {
+	n.Partition()
+}
+
+// Stats returns the number of the messages delivered and dropped
+func (n *Network) Stats() (uint64, uint64) {
+	return atomic.LoadUint64(&n.delivered), atomic.LoadUint64(&n.dropped)
+}
+
+// Wait waits until the messages in flight are delivered or dropped
+func (n *Network) Wait() {
+	n.pending.Wait()
+}
+
+func (n *Network) join(agent *Agent) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.agents[agent.ID()] = agent
+}
+
+// peers returns the running agents reachable from the agent, in the order of the IDs
+func (n *Network) peers(from string) []*Agent {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	var peers []*Agent
+	for id, agent := range n.agents {
+		if id != from && agent.running() && n.reachable(from, id) {
+			peers = append(peers, agent)
+		}
+	}
+	sortAgents(peers)
+	return peers
+}
+
+// peer returns the agent of the ID if it's running and reachable from the agent
+func (n *Network) peer(from, to string) (*Agent, bool) {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	agent, ok := n.agents[to]
+	if !ok || !agent.running() || !n.reachable(from, to) {
+		return nil, false
+	}
+	return agent, true
+}
+
+func (n *Network) reachable(from, to string) bool {
+	return n.partition[from] == n.partition[to]
+}
+
+// send delivers the message from the agent to the other after the latency, unless it's dropped on the way
+func (n *Network) send(from string, to *Agent, deliver func()) {
+	n.mutex.Lock()
+	if n.cfg.DropRate > 0 && n.rand.Float64() < n.cfg.DropRate {
+		n.mutex.Unlock()
+		atomic.AddUint64(&n.dropped, 1)
+		return
+	}
+	delay := n.cfg.Latency
+	if n.cfg.Jitter > 0 {
+		delay += time.Duration(n.rand.Int63n(int64(n.cfg.Jitter)))
+	}
+	n.mutex.Unlock()
+	n.pending.Add(1)
+	time.AfterFunc(delay, func() {
+		defer n.pending.Done()
+		if _, ok := n.peer(from, to.ID()); !ok {
+			atomic.AddUint64(&n.dropped, 1)
+			return
+		}
+		atomic.AddUint64(&n.delivered, 1)
+		deliver()
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package simnet
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/iotexproject/iotex-proto/golang/testingpb"
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/p2p"
+)
+
+// recorder records the payloads received from the peers
+type recorder struct {
+	mutex    sync.Mutex
+	received []string
+}
+
+func (r *recorder) record(from string, msg proto.Message) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.received = append(r.received, from+":"+string(msg.(*testingpb.TestPayload).MsgBody))
+}
+
+func (r *recorder) payloads() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	payloads := r.received
+	r.received = nil
+	return payloads
+}
+
+func (r *recorder) HandleBroadcast(ctx context.Context, _ uint32, msg proto.Message) {
+	peer, _ := p2p.GetBroadcastPeer(ctx)
+	r.record(peer, msg)
+}
+
+func (r *recorder) HandleTell(_ context.Context, _ uint32, peer peerstore.PeerInfo, msg proto.Message) {
+	r.record(peer.ID.Pretty(), msg)
+}
+
+func (r *recorder) HandleActionAnnouncement(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256) {
+}
+
+func (r *recorder) HandleActionRequest(context.Context, uint32, peerstore.PeerInfo, []hash.Hash256) {}
+
+func (r *recorder) HandleHeaderRequest(context.Context, uint32, peerstore.PeerInfo, uint64, uint64) {}
+
+func (r *recorder) HandleHeaders(context.Context, uint32, peerstore.PeerInfo, []*iotextypes.BlockHeader) {
+}
+
+func (r *recorder) HandleBlockAnnouncement(context.Context, uint32, peerstore.PeerInfo, uint64, hash.Hash256) {
+}
+
+func (r *recorder) HandleStateRangeRequest(context.Context, uint32, peerstore.PeerInfo, uint64, uint64, []byte, []byte, uint64) {
+}
+
+func (r *recorder) HandleStateRange(context.Context, uint32, peerstore.PeerInfo, uint64, []byte) {}
+
+func TestNetwork(t *testing.T) {
+	require := require.New(t)
+	ctx := p2p.WitContext(context.Background(), p2p.Context{ChainID: 1})
+	network := NewNetwork(Config{Latency: time.Millisecond, Jitter: time.Millisecond})
+	var (
+		agents    = make(map[string]*Agent)
+		recorders = make(map[string]*recorder)
+	)
+	for _, id := range []string{"a", "b", "c"} {
+		recorders[id] = &recorder{}
+		agents[id] = network.NewAgent(id, recorders[id])
+		require.NoError(agents[id].Start(ctx))
+	}
+	a := agents["a"].Info().ID.Pretty()
+	payload := func(body string) proto.Message {
+		return &testingpb.TestPayload{MsgBody: []byte(body)}
+	}
+
+	require.Error(agents["a"].BroadcastOutbound(context.Background(), payload("0")))
+	require.NoError(agents["a"].BroadcastOutbound(ctx, payload("1")))
+	require.NoError(agents["a"].UnicastOutbound(ctx, agents["c"].Info(), payload("2")))
+	network.Wait()
+	require.Empty(recorders["a"].payloads())
+	require.Equal([]string{a + ":1"}, recorders["b"].payloads())
+	require.ElementsMatch([]string{a + ":1", a + ":2"}, recorders["c"].payloads())
+
+	// c is isolated
+	network.Partition([]string{"c"})
+	nbs, err := agents["a"].Neighbors(ctx)
+	require.NoError(err)
+	require.Equal([]peerstore.PeerInfo{agents["b"].Info()}, nbs)
+	require.NoError(agents["a"].BroadcastOutbound(ctx, payload("3")))
+	require.Error(agents["a"].UnicastOutbound(ctx, agents["c"].Info(), payload("4")))
+	network.Wait()
+	require.Equal([]string{a + ":3"}, recorders["b"].payloads())
+	require.Empty(recorders["c"].payloads())
+	network.Heal()
+
+	// the stopped agent receives nothing
+	require.NoError(agents["b"].Stop(ctx))
+	require.NoError(agents["a"].BroadcastOutbound(ctx, payload("5")))
+	network.Wait()
+	require.Empty(recorders["b"].payloads())
+	require.Equal([]string{a + ":5"}, recorders["c"].payloads())
+	require.NoError(agents["b"].Start(ctx))
+
+	// the invalid messages are dropped and reported
+	agents["c"].RegisterValidator(1, iotexrpc.MessageType_TEST, func(_ context.Context, msg proto.Message) error {
+		if string(msg.(*testingpb.TestPayload).MsgBody) == "6" {
+			return errors.New("invalid payload")
+		}
+		return nil
+	})
+	require.NoError(agents["a"].BroadcastOutbound(ctx, payload("6")))
+	network.Wait()
+	require.Equal([]string{a + ":6"}, recorders["b"].payloads())
+	require.Empty(recorders["c"].payloads())
+	require.Equal([]p2p.Violation{p2p.ViolationInvalidMessage}, agents["c"].Violations(a))
+
+	network.SetDropRate(1)
+	require.NoError(agents["a"].BroadcastOutbound(ctx, payload("7")))
+	network.Wait()
+	require.Empty(recorders["b"].payloads())
+	require.Empty(recorders["c"].payloads())
+	delivered, dropped := network.Stats()
+	require.EqualValues(7, delivered)
+	require.EqualValues(2, dropped)
+}
+
+func TestNetworkSeed(t *testing.T) {
+	require := require.New(t)
+	ctx := p2p.WitContext(context.Background(), p2p.Context{ChainID: 1})
+	run := func(seed int64) []string {
+		network := NewNetwork(Config{DropRate: 0.5, Seed: seed})
+		sender := network.NewAgent("sender", &recorder{})
+		r := &recorder{}
+		require.NoError(sender.Start(ctx))
+		require.NoError(network.NewAgent("receiver", r).Start(ctx))
+		for i := 0; i < 100; i++ {
+			require.NoError(sender.BroadcastOutbound(ctx, &testingpb.TestPayload{MsgBody: []byte{byte(i)}}))
+			// wait for each message, so that the order of the deliveries is deterministic
+			network.Wait()
+		}
+		return r.payloads()
+	}
+	// the same seed drops the same messages
+	first := run(1)
+	require.True(len(first) > 0 && len(first) < 100)
+	require.Equal(first, run(1))
+	require.NotEqual(first, run(2))
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package simnet
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/chainservice"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/dispatcher"
+)
+
+// Node is a node running a chain service on the simulated network
+type Node struct {
+	agent        *Agent
+	dispatcher   dispatcher.Dispatcher
+	chainService *chainservice.ChainService
+}
+
+// NewNode creates a node of the ID on the network, whose chain service keeps the chain in memory. The nodes on the same
+// network should share the genesis of the config, and use different ports of the api
+func (n *Network) NewNode(id string, cfg config.Config) (*Node, error) {
+	d, err := dispatcher.NewDispatcher(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to create dispatcher")
+	}
+	agent := n.NewAgent(id, d)
+	cs, err := chainservice.New(cfg, agent, d, chainservice.WithTesting())
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to create chain service")
+	}
+	d.AddSubscriber(cs.ChainID(), cs)
+	return &Node{
+		agent:        agent,
+		dispatcher:   d,
+		chainService: cs,
+	}, nil
+}
+
+// Start starts the node, in the same order as the server
+func (node *Node) Start(ctx context.Context) error {
+	if err := node.agent.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting agent")
+	}
+	if err := node.chainService.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting blockchain")
+	}
+	if err := node.dispatcher.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting dispatcher")
+	}
+	return nil
+}
+
+// Stop stops the node
+func (node *Node) Stop(ctx context.Context) error {
+	if err := node.agent.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping agent")
+	}
+	if err := node.dispatcher.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping dispatcher")
+	}
+	if err := node.chainService.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping blockchain")
+	}
+	return nil
+}
+
+// Agent returns the agent of the node
+func (node *Node) Agent() *Agent { return node.agent }
+
+// ChainService returns the chain service of the node
+func (node *Node) ChainService() *chainservice.ChainService { return node.chainService }
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package simnet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func newTestConfig(t *testing.T, scheme string) config.Config {
+	cfg := config.Default
+	cfg.Consensus.Scheme = scheme
+	cfg.Genesis.BlockInterval = 100 * time.Millisecond
+	cfg.Genesis.EnableGravityChainVoting = false
+	// the in-memory state factory doesn't support the staking protocol
+	cfg.Chain.EnableStakingProtocol = false
+	cfg.ActPool.MinGasPriceStr = "0"
+	cfg.API.Port = testutil.RandomPort()
+	cfg.BlockSync.Interval = 100 * time.Millisecond
+	sk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cfg.Chain.ProducerPrivKey = sk.HexString()
+	return cfg
+}
+
+func TestNodeSync(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	network := NewNetwork(Config{Latency: 10 * time.Millisecond, Jitter: 10 * time.Millisecond, DropRate: 0.1})
+	producer, err := network.NewNode("producer", newTestConfig(t, config.StandaloneScheme))
+	require.NoError(err)
+	follower, err := network.NewNode("follower", newTestConfig(t, config.NOOPScheme))
+	require.NoError(err)
+	for _, node := range []*Node{producer, follower} {
+		require.NoError(node.Start(ctx))
+	}
+	defer func() {
+		for _, node := range []*Node{producer, follower} {
+			require.NoError(node.Stop(ctx))
+		}
+		network.Wait()
+	}()
+	producerChain := producer.ChainService().Blockchain()
+	followerChain := follower.ChainService().Blockchain()
+
+	// the follower keeps up with the producer on the lossy network
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 20*time.Second, func() (bool, error) {
+		return followerChain.TipHeight() >= 5, nil
+	}))
+
+	// the isolated follower falls behind, and catches up once the partition heals
+	network.Partition([]string{"follower"})
+	height := producerChain.TipHeight()
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 20*time.Second, func() (bool, error) {
+		return producerChain.TipHeight() >= height+5, nil
+	}))
+	require.True(followerChain.TipHeight() < producerChain.TipHeight())
+	network.Heal()
+	height = producerChain.TipHeight()
+	require.NoError(testutil.WaitUntil(100*time.Millisecond, 20*time.Second, func() (bool, error) {
+		return followerChain.TipHeight() >= height, nil
+	}))
+	blk, err := follower.ChainService().BlockDAO().GetBlockByHeight(height)
+	require.NoError(err)
+	expected, err := producer.ChainService().BlockDAO().GetBlockByHeight(height)
+	require.NoError(err)
+	require.Equal(expected.HashBlock(), blk.HashBlock())
+}
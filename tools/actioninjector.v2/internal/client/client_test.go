@@ -59,7 +59,9 @@ func TestClient(t *testing.T) {
 	})
 	sf.EXPECT().Height().Return(uint64(10), nil).AnyTimes()
 	bc.EXPECT().ChainID().Return(chainID).AnyTimes()
+	bc.EXPECT().TipHeight().Return(uint64(10)).AnyTimes()
 	bc.EXPECT().AddSubscriber(gomock.Any()).Return(nil).AnyTimes()
+	bc.EXPECT().Context().Return(protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{}), nil).AnyTimes()
 	bh := &iotextypes.BlockHeader{Core: &iotextypes.BlockHeaderCore{
 		Version:          chainID,
 		Height:           10,
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/tools/iomigrater/common"
+)
+
+// Multi-language support
+var (
+	compressDbCmdShorts = map[string]string{
+		"english": "Sub-Command for compressing IoTeX blockchain db files.",
+		"chinese": "压缩IoTeX区块链 db 文件的子命令",
+	}
+	compressDbCmdLongs = map[string]string{
+		"english": "Sub-Command for converting the V2 chain db files to V3 format, whose blocks, receipts and transaction logs are compressed by the given compressor. The node must be stopped during the conversion.",
+		"chinese": "将 V2 格式的链 db 文件转换为 V3 格式的子命令，区块、收据和交易日志使用指定的压缩算法压缩。转换期间节点必须停止运行。",
+	}
+	compressDbCmdUse = map[string]string{
+		"english": "compress CHAIN_DB_FILE",
+		"chinese": "compress 链db文件",
+	}
+	compressDbFlagCompressorUse = map[string]string{
+		"english": "The compressor of the records, one of Gzip, Snappy and Zstd, or empty for no compression.",
+		"chinese": "记录的压缩算法，Gzip、Snappy 或 Zstd，为空则不压缩。",
+	}
+)
+
+var (
+	// CompressDb Used to Sub command.
+	CompressDb = &cobra.Command{
+		Use:   common.TranslateInLang(compressDbCmdUse),
+		Short: common.TranslateInLang(compressDbCmdShorts),
+		Long:  common.TranslateInLang(compressDbCmdLongs),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return compressDbFile(args[0])
+		},
+	}
+)
+
+var (
+	compressor = ""
+)
+
+func init() {
+	CompressDb.PersistentFlags().StringVarP(&compressor, "compressor", "c", config.Default.DB.Compressor, common.TranslateInLang(compressDbFlagCompressorUse))
+}
+
+func compressDbFile(file string) error {
+	cfg := config.Default.DB
+	cfg.DbPath = file
+	cfg.Compressor = compressor
+	if err := filedao.MigrateToV3(cfg); err != nil {
+		fmt.Printf("Compress db %s err: %v\n", file, err)
+		return err
+	}
+	fmt.Printf("Compressed db %s with %s.\n", file, compressor)
+	return nil
+}
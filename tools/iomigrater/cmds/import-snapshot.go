@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/snapshot"
+	"github.com/iotexproject/iotex-core/tools/iomigrater/common"
+)
+
+// Multi-language support
+var (
+	importSnapshotCmdShorts = map[string]string{
+		"english": "Sub-Command for bootstrapping IoTeX blockchain db files from a state snapshot.",
+		"chinese": "从状态快照初始化IoTeX区块链 db 文件的子命令",
+	}
+	importSnapshotCmdLongs = map[string]string{
+		"english": "Sub-Command for bootstrapping the chain db file and the trie db file of a new node from a state snapshot, the node syncs the blocks after the snapshot from its peers.",
+		"chinese": "从状态快照初始化新节点的链 db 文件和状态 db 文件的子命令，节点从其他节点同步快照之后的区块",
+	}
+	importSnapshotCmdUse = map[string]string{
+		"english": "import-snapshot SNAPSHOT_FILE",
+		"chinese": "import-snapshot 快照文件",
+	}
+	importSnapshotFlagChainDBUse = map[string]string{
+		"english": "The chain db file to create.",
+		"chinese": "要创建的链 db 文件。",
+	}
+	importSnapshotFlagTrieDBUse = map[string]string{
+		"english": "The trie db file to create.",
+		"chinese": "要创建的状态 db 文件。",
+	}
+	importSnapshotFlagChainIDUse = map[string]string{
+		"english": "The chain ID of the node.",
+		"chinese": "节点的链 ID。",
+	}
+)
+
+var (
+	// ImportSnapshot Used to Sub command.
+	ImportSnapshot = &cobra.Command{
+		Use:   common.TranslateInLang(importSnapshotCmdUse),
+		Short: common.TranslateInLang(importSnapshotCmdShorts),
+		Long:  common.TranslateInLang(importSnapshotCmdLongs),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importSnapshot(args[0])
+		},
+	}
+)
+
+var (
+	chainDBFile = ""
+	trieDBFile  = ""
+	chainID     = uint32(0)
+)
+
+func init() {
+	ImportSnapshot.PersistentFlags().StringVarP(&chainDBFile, "chain-db", "c", config.Default.Chain.ChainDBPath, common.TranslateInLang(importSnapshotFlagChainDBUse))
+	ImportSnapshot.PersistentFlags().StringVarP(&trieDBFile, "trie-db", "t", config.Default.Chain.TrieDBPath, common.TranslateInLang(importSnapshotFlagTrieDBUse))
+	ImportSnapshot.PersistentFlags().Uint32VarP(&chainID, "chain-id", "i", config.Default.Chain.ID, common.TranslateInLang(importSnapshotFlagChainIDUse))
+}
+
+func importSnapshot(file string) error {
+	cfg := config.Default.DB
+	cfg.DbPath = chainDBFile
+	m, err := snapshot.Import(file, chainID, trieDBFile, cfg)
+	if err != nil {
+		fmt.Printf("Import snapshot %s err: %v\n", file, err)
+		return err
+	}
+	fmt.Printf("Imported snapshot at height %d, block %s.\n", m.Height, m.Hash)
+	return nil
+}